@@ -0,0 +1,61 @@
+// Command normalize-emails recomputes normalized account emails after
+// ACCOUNTS_LOWERCASE_EMAIL_LOCAL_PART changes. It changes nothing and exits
+// with status 1 when accounts would collide, listing them so they can be
+// resolved by hand first.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/logger"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Warning: .env file not found, using environment variables")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	log, _, err := logger.NewLogger(&cfg.Logging)
+	if err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	db, err := database.NewDatabase(cfg, log)
+	if err != nil {
+		fmt.Printf("Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	normalizer := emailnorm.Normalizer{LowercaseLocalPart: cfg.Accounts.LowercaseEmailLocalPart}
+	collisions, err := database.NormalizeEmails(context.Background(), db.DB, normalizer)
+	if err != nil {
+		fmt.Printf("Failed to normalize emails: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(collisions) > 0 {
+		fmt.Printf("%d normalized emails are shared by several accounts; nothing was changed:\n", len(collisions))
+		for _, collision := range collisions {
+			fmt.Printf("  %s: %s\n", collision.Normalized, strings.Join(collision.Emails, ", "))
+		}
+		os.Exit(1)
+	}
+
+	fmt.Println("Emails normalized")
+}
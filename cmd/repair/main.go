@@ -0,0 +1,68 @@
+// Command repair checks stored rosters for data earlier bugs left
+// inconsistent: co-captains who aren't players, players on TTRs that are
+// gone, overfull rosters, pending invitations to TTRs that can't be joined
+// and notifications about missing targets. It prints a JSON report of what it
+// found and only fixes it when run with --apply. Overfull rosters are only
+// reported.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/logger"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "fix what the checks find instead of only reporting it")
+	flag.Parse()
+
+	// The report goes to stdout, so everything else goes to stderr.
+	if err := godotenv.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: .env file not found, using environment variables")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	log, _, err := logger.NewLogger(&cfg.Logging)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	db, err := database.NewDatabase(cfg, log)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	report, err := database.RepairRosters(context.Background(), db.DB, database.RepairOptions{
+		Apply:         *apply,
+		Now:           time.Now(),
+		RestoreWindow: cfg.TTRs.RestoreWindow,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to check rosters: %v\n", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write report: %v\n", err)
+		os.Exit(1)
+	}
+}
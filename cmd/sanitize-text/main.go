@@ -0,0 +1,49 @@
+// Command sanitize-text cleans the notes and messages stored before they were
+// sanitized on write, so every stored value is safe to render as HTML. It is
+// safe to run more than once.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/logger"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("Warning: .env file not found, using environment variables")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	log, _, err := logger.NewLogger(&cfg.Logging)
+	if err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer log.Sync()
+
+	db, err := database.NewDatabase(cfg, log)
+	if err != nil {
+		fmt.Printf("Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	changed, err := database.SanitizeText(context.Background(), db.DB)
+	if err != nil {
+		fmt.Printf("Failed to sanitize text: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Sanitized %d values\n", changed)
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"net/http"
 	"os"
@@ -13,10 +14,22 @@ import (
 	"github.com/yourusername/golf_messenger/internal/config"
 	"github.com/yourusername/golf_messenger/internal/database"
 	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/lifecycle"
 	"github.com/yourusername/golf_messenger/internal/logger"
 	"github.com/yourusername/golf_messenger/internal/repository"
 	"github.com/yourusername/golf_messenger/internal/router"
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/internal/tracing"
+	"github.com/yourusername/golf_messenger/pkg/analytics"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/redis"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"github.com/yourusername/golf_messenger/pkg/signing"
 	"github.com/yourusername/golf_messenger/pkg/storage"
 	"go.uber.org/zap"
 )
@@ -57,7 +70,9 @@ func main() {
 		os.Exit(1)
 	}
 
-	log, err := logger.NewLogger(&cfg.Logging)
+	emailnorm.Default = emailnorm.Normalizer{LowercaseLocalPart: cfg.Accounts.LowercaseEmailLocalPart}
+
+	log, logLevel, err := logger.NewLogger(&cfg.Logging)
 	if err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -69,7 +84,15 @@ func main() {
 		zap.String("port", cfg.Server.Port),
 	)
 
-	db, err := database.NewDatabase(cfg)
+	lc := lifecycle.NewManager(log)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+	lc.OnShutdown("tracing", shutdownTracing)
+
+	db, err := database.NewDatabase(cfg, log)
 	if err != nil {
 		log.Fatal("Failed to connect to database", zap.Error(err))
 	}
@@ -84,38 +107,219 @@ func main() {
 
 	log.Info("S3 client initialized successfully")
 
+	var mail mailer.Mailer
+	if cfg.Mail.Enabled() {
+		if mail, err = mailer.NewSMTPMailer(cfg.Mail); err != nil {
+			log.Fatal("Failed to initialize mailer", zap.Error(err))
+		}
+	}
+
+	// Validate has checked the proxy list parses.
+	clientIPs, _ := clientip.New(cfg.Server.TrustedProxies)
+	var authRateLimiter ratelimit.RateLimiter = ratelimit.NewMemoryLimiter(cfg.RateLimit.AuthRequests, cfg.RateLimit.AuthWindow)
+	var appCache cache.Cache = cache.NewMemoryCache()
+	var nonceStore signing.NonceStore = signing.NewMemoryNonceStore()
+	var redisClient *redis.Client
+	if cfg.Redis.Enabled {
+		redisClient, err = redis.NewClient(&cfg.Redis)
+		if err != nil {
+			log.Fatal("Failed to connect to Redis", zap.Error(err))
+		}
+		authRateLimiter = ratelimit.NewRedisLimiter(redisClient, "ratelimit:auth:", cfg.RateLimit.AuthRequests, cfg.RateLimit.AuthWindow)
+		appCache = cache.NewRedisCache(redisClient, "cache:")
+		nonceStore = signing.NewRedisNonceStore(redisClient, "signing:nonce:")
+
+		log.Info("Redis connected successfully")
+	}
+
 	userRepo := repository.NewUserRepository(db.DB)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db.DB)
 	ttrRepo := repository.NewTTRRepository(db.DB)
 	invitationRepo := repository.NewInvitationRepository(db.DB)
+	invitationTemplateRepo := repository.NewInvitationTemplateRepository(db.DB)
+	messageRepo := repository.NewMessageRepository(db.DB)
+	orgRepo := repository.NewOrganizationRepository(db.DB)
+	leagueRepo := repository.NewLeagueRepository(db.DB)
+	tournamentRepo := repository.NewTournamentRepository(db.DB)
+	suggestionRepo := repository.NewSuggestionRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	inviteLinkRepo := repository.NewInviteLinkRepository(db.DB)
+	actionItemRepo := repository.NewActionItemRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	slackRepo := repository.NewSlackRepository(db.DB)
+	apiTokenRepo := repository.NewAPITokenRepository(db.DB)
+	impersonationRepo := repository.NewImpersonationRepository(db.DB)
+	auditLogRepo := repository.NewAuditLogRepository(db.DB)
+	featureFlagRepo := repository.NewFeatureFlagRepository(db.DB)
+	ttrEventRepo := repository.NewTTREventRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	statsRepo := repository.NewStatsRepository(db.DB)
+	emailSuppressionRepo := repository.NewEmailSuppressionRepository(db.DB)
+	securityEventRepo := repository.NewSecurityEventRepository(db.DB)
+	historyRepo := repository.NewHistoryRepository(db.DB)
+	scheduleRepo := repository.NewScheduleRepository(db.DB)
+	reportRepo := repository.NewReportRepository(db.DB)
+	transactor := repository.NewTransactor(db.DB)
 
-	notificationService := service.NewNotificationService(log)
+	outboxService := service.NewOutboxService(outboxRepo, cfg.Outbox, log)
+	emailSuppressionService := service.NewEmailSuppressionService(emailSuppressionRepo, userRepo, transactor, log)
+	// Nothing is sent to addresses that bounced or complained.
+	mail = emailSuppressionService.Mailer(mail)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, outboxService, cfg.Notifications.DigestWindow, log)
+	authorizer := service.NewAuthorizer(ttrRepo, orgRepo, invitationRepo, userRepo)
+	webhookService := service.NewWebhookService(webhookRepo, authorizer, outboxService, cfg.Webhooks, log)
+	changeFeedService := service.NewChangeFeedService(ttrEventRepo, authorizer, cfg.TTRs.ChangeRetention, log)
+	analyticsPublisher, err := analytics.NewPublisher(context.Background(), &cfg.Analytics, &cfg.AWS)
+	if err != nil {
+		log.Fatal("Failed to initialize analytics sink", zap.Error(err))
+	}
+	analyticsService := service.NewAnalyticsService(analyticsPublisher, cfg.Analytics.UserSalt, cfg.Analytics.BufferSize, log)
 
+	securityEventService := service.NewSecurityEventService(securityEventRepo, notificationService)
 	authService := service.NewAuthService(
 		userRepo,
 		refreshTokenRepo,
+		securityEventService,
+		cfg.Passwords.Policy(),
 		cfg.JWT.Secret,
 		cfg.JWT.AccessTokenDuration,
 		cfg.JWT.RefreshTokenDuration,
 	)
-	userService := service.NewUserService(userRepo, s3Client)
-	ttrService := service.NewTTRService(ttrRepo, userRepo, log)
-	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, notificationService, log)
+	userService := service.NewUserService(userRepo, s3Client, securityEventService, cfg.Passwords.Policy(), cfg.Avatars)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo, log)
+	maintenanceService := service.NewMaintenanceService(appCache, log)
+	if cfg.Maintenance.Enabled {
+		state := service.MaintenanceState{Enabled: true}
+		if !cfg.Maintenance.EndsAt.IsZero() {
+			state.EndsAt = &cfg.Maintenance.EndsAt
+		}
+		if _, err := maintenanceService.SetState(context.Background(), state); err != nil {
+			log.Fatal("Failed to start in maintenance mode", zap.Error(err))
+		}
+	}
+	flagService := service.NewFlagService(featureFlagRepo, orgRepo, cfg.FeatureFlags.Rollouts, log)
+	historyService := service.NewHistoryService(historyRepo, userRepo, appCache, log)
+	scheduleService := service.NewScheduleService(scheduleRepo)
+	reportService := service.NewReportService(reportRepo, userRepo, messageRepo, ttrRepo, notificationService, cfg.RateLimit.ReportsPerDay, log)
+	impersonationService := service.NewImpersonationService(userRepo, impersonationRepo, auditLogRepo, cfg.JWT.Secret, cfg.JWT.ImpersonationTokenDuration, log)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, transactor, authorizer, notificationService, webhookService, changeFeedService, analyticsService, historyService, cfg.TTRs.RestoreWindow, cfg.TTRs.EditLockWindow, log)
+	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, invitationTemplateRepo, ttrService, transactor, authorizer, notificationService, webhookService, analyticsService, log)
+	invitationTemplateService := service.NewInvitationTemplateService(invitationTemplateRepo)
+	orgService := service.NewOrganizationService(orgRepo, userRepo, authorizer, transactor, notificationService, log)
+	leagueService := service.NewLeagueService(leagueRepo, ttrRepo, authorizer, appCache, cfg.Leagues.StandingsCacheTTL, log)
+	tournamentService := service.NewTournamentService(tournamentRepo, ttrRepo, userRepo, authorizer, transactor, notificationService, log)
+	messageService := service.NewMessageService(messageRepo, authorizer, s3Client, cfg.Messaging, changeFeedService, log)
+	checkInService := service.NewCheckInService(ttrRepo, authorizer, notificationService, cfg.TTRs.CheckInOpensBefore, cfg.TTRs.CheckInClosesAfter, log)
+	suggestionService := service.NewSuggestionService(suggestionRepo, userRepo, authorizer)
+	pairingService := service.NewPairingService(authorizer, cfg.TTRs.DefaultHandicap)
+	inviteLinkService := service.NewInviteLinkService(inviteLinkRepo, ttrRepo, ttrService, authorizer, notificationService, log)
+	actionItemService := service.NewActionItemService(actionItemRepo)
+	dashboardService := service.NewDashboardService(ttrRepo, invitationRepo, notificationRepo, actionItemService, log)
+	statsService := service.NewStatsService(statsRepo, appCache, log)
+	importService := service.NewUserImportService(userRepo, auditLogRepo, transactor, outboxService, mail, cfg.Accounts.ImportMaxRows, log)
+	slackService := service.NewSlackService(slackRepo, ttrService, inviteLinkService, cfg.Slack, log)
+	retentionService := service.NewRetentionService(ttrRepo, userRepo, securityEventRepo, cfg.Retention, log)
+	inactivityService := service.NewInactivityService(userRepo, refreshTokenRepo, auditLogRepo, transactor, notificationService, cfg.Retention, log)
+	digestService := service.NewDigestService(userRepo, ttrRepo, actionItemService, mail, cfg.Digest, log)
 
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	securityEventHandler := handler.NewSecurityEventHandler(securityEventService)
+	historyHandler := handler.NewHistoryHandler(historyService)
+	reportHandler := handler.NewReportHandler(reportService)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
 	ttrHandler := handler.NewTTRHandler(ttrService)
 	invitationHandler := handler.NewInvitationHandler(invitationService)
+	invitationTemplateHandler := handler.NewInvitationTemplateHandler(invitationTemplateService)
+	messageHandler := handler.NewMessageHandler(messageService)
+	suggestionHandler := handler.NewSuggestionHandler(suggestionService)
+	pairingHandler := handler.NewPairingHandler(pairingService)
+	inviteLinkHandler := handler.NewInviteLinkHandler(inviteLinkService)
+	actionItemHandler := handler.NewActionItemHandler(actionItemService)
+	dashboardHandler := handler.NewDashboardHandler(dashboardService)
+	graphqlHandler := handler.NewGraphQLHandler(userService, ttrService, invitationService, cfg.GraphQL.MaxDepth, cfg.GraphQL.MaxComplexity, log)
+	changeFeedHandler := handler.NewChangeFeedHandler(changeFeedService)
+	checkInHandler := handler.NewCheckInHandler(checkInService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	orgHandler := handler.NewOrganizationHandler(orgService)
+	leagueHandler := handler.NewLeagueHandler(leagueService)
+	tournamentHandler := handler.NewTournamentHandler(tournamentService)
+	mergeService := service.NewUserMergeService(userRepo, transactor, log)
+	adminHandler := handler.NewAdminHandler(logLevel, statsService, importService, mergeService, log)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+	flagHandler := handler.NewFlagHandler(flagService)
+	healthHandler := handler.NewHealthHandler(db, maintenanceService)
+	maintenanceHandler := handler.NewMaintenanceHandler(maintenanceService)
+	metaHandler := handler.NewMetaHandler()
 
-	rt := router.NewRouter(
-		authHandler,
-		userHandler,
-		ttrHandler,
-		invitationHandler,
-		log,
-		cfg.JWT.Secret,
-		cfg.CORS.AllowedOrigins,
-	)
+	routerOpts := []router.Option{
+		router.WithAuth(authHandler),
+		router.WithUsers(userHandler),
+		router.WithAPITokens(apiTokenHandler, apiTokenService),
+		router.WithSecurityEvents(securityEventHandler),
+		router.WithHistory(historyHandler),
+		router.WithReports(reportHandler),
+		router.WithSchedule(scheduleHandler),
+		router.WithTTR(ttrHandler),
+		router.WithInvitations(invitationHandler),
+		router.WithInvitationTemplates(invitationTemplateHandler),
+		router.WithMessages(messageHandler),
+		router.WithSuggestions(suggestionHandler),
+		router.WithPairings(pairingHandler),
+		router.WithInviteLinks(inviteLinkHandler),
+		router.WithActionItems(actionItemHandler),
+		router.WithDashboard(dashboardHandler),
+		router.WithGraphQL(graphqlHandler),
+		router.WithChangeFeed(changeFeedHandler),
+		router.WithCheckIns(checkInHandler),
+		router.WithWebhooks(webhookHandler),
+		router.WithOrganizations(orgHandler),
+		router.WithLeagues(leagueHandler),
+		router.WithTournaments(tournamentHandler),
+		router.WithAdmin(adminHandler),
+		router.WithImpersonation(impersonationHandler, impersonationService),
+		router.WithMeta(metaHandler),
+		router.WithFlags(flagHandler),
+		router.WithHealth(healthHandler),
+		router.WithMaintenance(maintenanceHandler, maintenanceService),
+		router.WithAuthRateLimiter(authRateLimiter),
+		router.WithTrustedProxies(clientIPs),
+	}
+	if cfg.Slack.SigningSecret != "" {
+		routerOpts = append(routerOpts, router.WithSlack(handler.NewSlackHandler(slackService, cfg.Slack.SigningSecret)))
+	}
+	if cfg.Mail.EventsSendGridPublicKey != "" || cfg.Mail.EventsSESTopicARN != "" {
+		var sendGridKey *ecdsa.PublicKey
+		if cfg.Mail.EventsSendGridPublicKey != "" {
+			// Validate has checked the key parses.
+			sendGridKey, _ = mailevents.ParseSendGridPublicKey(cfg.Mail.EventsSendGridPublicKey)
+		}
+		var sns *mailevents.SNSVerifier
+		if cfg.Mail.EventsSESTopicARN != "" {
+			sns = mailevents.NewSNSVerifier(cfg.Mail.EventsSESTopicARN)
+		}
+		routerOpts = append(routerOpts, router.WithEmailEvents(handler.NewEmailEventHandler(emailSuppressionService, sendGridKey, sns)))
+	}
+	if len(cfg.Signing.Clients) > 0 {
+		clients := make([]signing.Client, 0, len(cfg.Signing.Clients))
+		for keyID, secret := range cfg.Signing.Clients {
+			// Validate has checked the scope names.
+			scopes, _ := scope.ParseList(cfg.Signing.Scopes[keyID])
+			clients = append(clients, signing.Client{KeyID: keyID, Secret: secret, Scopes: scopes})
+		}
+		routerOpts = append(routerOpts, router.WithSignedRequests(signing.NewVerifier(clients, nonceStore)))
+	}
+	if cfg.Compression.Enabled {
+		routerOpts = append(routerOpts, router.WithCompression(cfg.Compression.MinSize))
+	}
+	if !cfg.API.V1Sunset.IsZero() {
+		routerOpts = append(routerOpts, router.WithV1Sunset(cfg.API.V1Sunset))
+	}
+	if cfg.API.ListRoutes {
+		routerOpts = append(routerOpts, router.WithRouteListing())
+	}
+	rt := router.New(log, cfg.JWT.Secret, cfg.CORS.AllowedOrigins, routerOpts...)
 
 	httpHandler := rt.SetupRoutes()
 
@@ -127,6 +331,30 @@ func main() {
 		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
+	lc.Every("refresh-token-cleanup", time.Hour, func(ctx context.Context) error {
+		return refreshTokenRepo.DeleteExpired(ctx)
+	})
+	lc.Every("rsvp-deadlines", time.Minute, ttrService.ProcessRSVPDeadlines)
+	lc.Every("attachment-cleanup", 5*time.Minute, messageService.PurgeDeletedAttachments)
+	lc.Every("retention-purge", time.Hour, retentionService.PurgeDeleted)
+	lc.Every("inactive-accounts", time.Hour, inactivityService.ProcessInactiveUsers)
+	if mail != nil {
+		lc.Every("weekly-digests", 15*time.Minute, digestService.SendWeeklyDigests)
+	}
+	lc.Every("ttr-event-purge", time.Hour, changeFeedService.PurgeExpired)
+	lc.Every("check-in-summaries", time.Minute, checkInService.SendCheckInSummaries)
+	lc.Go("outbox-dispatcher", outboxService.Run)
+	lc.Every("outbox-purge", time.Hour, outboxService.PurgeDelivered)
+	lc.Go("webhook-dispatcher", webhookService.Run)
+	lc.Go("analytics-exporter", analyticsService.Run)
+
+	if redisClient != nil {
+		lc.OnShutdown("redis", func(ctx context.Context) error {
+			return redisClient.Close()
+		})
+	}
+	lc.OnShutdown("http-server", server.Shutdown)
+
 	go func() {
 		log.Info("Server starting", zap.String("address", server.Addr))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -134,16 +362,16 @@ func main() {
 		}
 	}()
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-signalCtx.Done()
 
 	log.Info("Server shutting down...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	if err := lc.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown", zap.Error(err))
 	}
 
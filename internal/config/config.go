@@ -1,19 +1,51 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/featureflag"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"github.com/yourusername/golf_messenger/pkg/scope"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	AWS      AWSConfig
-	CORS     CORSConfig
-	Logging  LoggingConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	JWT           JWTConfig
+	Accounts      AccountsConfig
+	Passwords     PasswordConfig
+	AWS           AWSConfig
+	CORS          CORSConfig
+	Redis         RedisConfig
+	RateLimit     RateLimitConfig
+	Tracing       TracingConfig
+	Messaging     MessagingConfig
+	TTRs          TTRConfig
+	Notifications NotificationsConfig
+	Webhooks      WebhooksConfig
+	Outbox        OutboxConfig
+	Analytics     AnalyticsConfig
+	Slack         SlackConfig
+	Mail          MailConfig
+	Digest        DigestConfig
+	Retention     RetentionConfig
+	Leagues       LeaguesConfig
+	Compression   CompressionConfig
+	Avatars       AvatarConfig
+	Logging       LoggingConfig
+	API           APIConfig
+	GraphQL       GraphQLConfig
+	FeatureFlags  FeatureFlagsConfig
+	Maintenance   MaintenanceConfig
+	Signing       SigningConfig
 }
 
 type ServerConfig struct {
@@ -21,24 +53,81 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// TrustedProxies are the load balancers, as CIDR ranges or addresses,
+	// whose X-Forwarded-For and X-Real-IP headers name the client. Those
+	// headers are ignored on requests from anywhere else.
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
-	Host            string
-	Port            string
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host               string
+	Port               string
+	User               string
+	Password           string
+	DBName             string
+	SSLMode            string
+	MaxOpenConns       int
+	MaxIdleConns       int
+	ConnMaxLifetime    time.Duration
+	ConnectTimeout     time.Duration
+	SlowQueryThreshold time.Duration
 }
 
 type JWTConfig struct {
 	Secret               string
 	AccessTokenDuration  time.Duration
 	RefreshTokenDuration time.Duration
+	AllowWeakSecret      bool
+	// ImpersonationTokenDuration is how long a support session started with
+	// POST /admin/impersonate lasts.
+	ImpersonationTokenDuration time.Duration
+}
+
+// MinJWTSecretLength is the minimum number of bytes accepted for JWT_SECRET.
+const MinJWTSecretLength = 32
+
+// MinSigningSecretLength is the minimum number of bytes accepted for a
+// signing client's secret.
+const MinSigningSecretLength = 32
+
+var weakJWTSecrets = []string{
+	"secret",
+	"changeme",
+	"change-me",
+	"password",
+	"jwt-secret",
+	"your-secret-key",
+	"your-super-secret-key-change-this-in-production",
+}
+
+// AccountsConfig controls how account emails are normalized. The domain is
+// always lowercased; LowercaseEmailLocalPart also lowercases the part before
+// the @, so John@example.com and john@example.com are the same account.
+// ImportMaxRows caps the rows of a member CSV imported in one upload.
+type AccountsConfig struct {
+	LowercaseEmailLocalPart bool
+	ImportMaxRows           int
+}
+
+// PasswordConfig is the policy new passwords must satisfy on registration
+// and password changes: at least MinLength characters, one of each of
+// RequireClasses (lowercase, uppercase, digit, symbol), not on the list of
+// common passwords when BanCommon is set, and not containing the email
+// before the @ when RejectEmail is set.
+type PasswordConfig struct {
+	MinLength      int
+	RequireClasses []string
+	BanCommon      bool
+	RejectEmail    bool
+}
+
+// Policy returns the password.Policy the config describes.
+func (c PasswordConfig) Policy() password.Policy {
+	policy := password.Policy{MinLength: c.MinLength, BanCommon: c.BanCommon, RejectEmail: c.RejectEmail}
+	for _, class := range c.RequireClasses {
+		policy.RequireClasses = append(policy.RequireClasses, password.Rule(class))
+	}
+	return policy
 }
 
 type AWSConfig struct {
@@ -49,10 +138,236 @@ type AWSConfig struct {
 	S3Endpoint      string
 }
 
+// RedisConfig enables the Redis-backed cache and rate limiter. When disabled
+// the in-memory implementations are used, which are per-instance only.
+type RedisConfig struct {
+	Enabled  bool
+	Addr     string
+	Password string
+	DB       int
+}
+
+// RateLimitConfig limits requests to the unauthenticated /auth endpoints per
+// client IP within a fixed window. ReportsPerDay caps the abuse reports a user
+// can file in any 24 hours.
+type RateLimitConfig struct {
+	AuthRequests  int
+	AuthWindow    time.Duration
+	ReportsPerDay int
+}
+
+// TracingConfig configures OpenTelemetry export. Tracing is disabled when
+// Endpoint is empty.
+type TracingConfig struct {
+	Endpoint    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// MessagingConfig controls TTR chat. Authors can edit a message for EditWindow
+// after posting it. Attachment sizes and quotas are in bytes; quotas count the
+// attachments on messages that haven't been deleted.
+type MessagingConfig struct {
+	EditWindow          time.Duration
+	AttachmentMaxSize   int64
+	TTRAttachmentQuota  int64
+	UserAttachmentQuota int64
+	AttachmentURLTTL    time.Duration
+}
+
+// TTRConfig controls deleted TTRs, pairing suggestions, change feeds and
+// late edits. Captains can restore a deleted TTR for RestoreWindow; after that
+// it waits for the retention job to purge it. Pairing suggestions count
+// players without a handicap as DefaultHandicap. Change feed events are kept
+// for ChangeRetention. Within EditLockWindow of the tee time a TTR's course,
+// tee date and tee time only change, and the TTR is only deleted, when the
+// caller overrides the lock; zero turns the lock off. Players check in from
+// CheckInOpensBefore the tee time until CheckInClosesAfter it.
+type TTRConfig struct {
+	RestoreWindow      time.Duration
+	DefaultHandicap    float64
+	ChangeRetention    time.Duration
+	EditLockWindow     time.Duration
+	CheckInOpensBefore time.Duration
+	CheckInClosesAfter time.Duration
+}
+
+// NotificationsConfig controls notification digests. Events of the same type
+// about the same target within DigestWindow of the first one collapse into a
+// single notification; zero turns digests off.
+type NotificationsConfig struct {
+	DigestWindow time.Duration
+}
+
+// WebhooksConfig controls outgoing webhook deliveries. A delivery is
+// attempted up to MaxAttempts times, waiting InitialBackoff before the first
+// retry and twice as long before each next one. Each attempt gives up after
+// Timeout. A webhook whose deliveries fail FailingAfter times in a row is
+// marked FAILING and gets no more deliveries until its owner updates it.
+type WebhooksConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Timeout        time.Duration
+	FailingAfter   int
+}
+
+// OutboxConfig controls the dispatcher delivering outbox messages, such as
+// notifications, after the change that caused them commits. It polls every
+// PollInterval for up to BatchSize due messages and holds each for Lease
+// while delivering it. A message is tried MaxAttempts times, waiting
+// InitialBackoff before the first retry and twice as long before each next
+// one, and is then kept as DEAD. Delivered messages are deleted after
+// Retention.
+type OutboxConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	Lease          time.Duration
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	Retention      time.Duration
+}
+
+// Analytics sinks.
+const (
+	AnalyticsSinkNone    = "none"
+	AnalyticsSinkFile    = "file"
+	AnalyticsSinkKafka   = "kafka"
+	AnalyticsSinkKinesis = "kinesis"
+)
+
+// AnalyticsConfig controls the export of domain events for product analytics.
+// Sink picks where they go: nowhere, FilePath as JSON lines, KafkaTopic on
+// KafkaBrokers or KinesisStream in the AWS region. Up to BufferSize events
+// wait to be exported; more are dropped. Users are referred to by an HMAC of
+// their ID keyed with UserSalt, never by the ID itself.
+type AnalyticsConfig struct {
+	Sink          string
+	FilePath      string
+	KafkaBrokers  []string
+	KafkaTopic    string
+	KinesisStream string
+	BufferSize    int
+	UserSalt      string
+}
+
+// SlackConfig enables the /golf slash command, which is only served when
+// SigningSecret is set. Codes from "/golf link" expire after LinkCodeTTL.
+// PublicURL is the API's public base URL, used for the share links in
+// replies; replies carry no link without it.
+type SlackConfig struct {
+	SigningSecret string
+	LinkCodeTTL   time.Duration
+	PublicURL     string
+}
+
+// MailConfig configures the SMTP server email is sent through. No email is
+// sent when SMTPAddr is empty. Bounces and complaints are accepted from
+// SendGrid when EventsSendGridPublicKey, its base64 webhook verification
+// key, is set, and from SES when EventsSESTopicARN, the SNS topic SES
+// notifies, is set.
+type MailConfig struct {
+	SMTPAddr                string
+	Username                string
+	Password                string
+	From                    string
+	EventsSendGridPublicKey string
+	EventsSESTopicARN       string
+}
+
+// Enabled reports whether an SMTP server is configured.
+func (c MailConfig) Enabled() bool {
+	return c.SMTPAddr != ""
+}
+
+// DigestConfig schedules the weekly digest email. It goes out from Hour
+// (UTC) on Weekday to BatchSize users at a time, at most one email every
+// SendInterval so the mail server isn't flooded. Nothing is sent without a
+// mailer.
+type DigestConfig struct {
+	Weekday      time.Weekday
+	Hour         int
+	BatchSize    int
+	SendInterval time.Duration
+}
+
+// RetentionConfig controls the job that permanently deletes soft-deleted TTRs
+// and users once they have been deleted for PurgeAfter. Rows are purged
+// BatchSize at a time, one transaction per batch. Users who haven't logged in
+// for InactiveAfter are warned, and deactivated if they still haven't
+// DeactivateAfter the warning; an InactiveAfter of 0 turns that off. Security
+// events are deleted once they are older than SecurityEvents.
+type RetentionConfig struct {
+	PurgeAfter      time.Duration
+	BatchSize       int
+	InactiveAfter   time.Duration
+	DeactivateAfter time.Duration
+	SecurityEvents  time.Duration
+}
+
+// LeaguesConfig controls league standings. Standings are cached for
+// StandingsCacheTTL and dropped early whenever a league's scores change.
+type LeaguesConfig struct {
+	StandingsCacheTTL time.Duration
+}
+
+// AvatarConfig limits avatar uploads to MaxSize bytes. Presigned upload URLs
+// for direct-to-storage uploads stay valid for UploadURLTTL.
+type AvatarConfig struct {
+	MaxSize      int64
+	UploadURLTTL time.Duration
+}
+
+// CompressionConfig enables gzip for responses of at least MinSize bytes.
+type CompressionConfig struct {
+	Enabled bool
+	MinSize int
+}
+
 type CORSConfig struct {
 	AllowedOrigins []string
 }
 
+// APIConfig controls the API versions. Once V1Sunset is set, /api/v1
+// responses are marked deprecated and announce that date as v1's retirement.
+// ListRoutes mounts GET /meta/routes, which lists every route; it is meant
+// for debugging.
+type APIConfig struct {
+	V1Sunset   time.Time
+	ListRoutes bool
+}
+
+// GraphQLConfig limits the queries /graphql runs: MaxDepth is how deeply
+// selections may nest and MaxComplexity how many fields a query may select in
+// all. Larger queries are refused before anything is read.
+type GraphQLConfig struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// FeatureFlagsConfig rolls flags out from config: Rollouts maps a flag to
+// the percentage of users it is on for. Flags admins manage at runtime
+// replace these.
+type FeatureFlagsConfig struct {
+	Rollouts map[string]int
+}
+
+// MaintenanceConfig starts the API in maintenance mode, refusing traffic
+// with a 503 until an admin turns it off. EndsAt, if set, is the estimated
+// end shown to clients.
+type MaintenanceConfig struct {
+	Enabled bool
+	EndsAt  time.Time
+}
+
+// SigningConfig registers the internal clients that may call the API with
+// signed requests instead of a token. Clients maps each client's key ID to
+// its shared secret; Scopes maps a key ID to the scopes its requests carry,
+// and a client without an entry gets every scope.
+type SigningConfig struct {
+	Clients map[string]string
+	Scopes  map[string][]string
+}
+
 type LoggingConfig struct {
 	Level            string
 	Encoding         string
@@ -60,78 +375,475 @@ type LoggingConfig struct {
 	ErrorOutputPaths []string
 }
 
+// legacyEnvKeys maps config keys to the environment variable names used before
+// every key gained an automatic override (e.g. DATABASE_HOST). Both names work.
+var legacyEnvKeys = map[string]string{
+	"database.host":              "DB_HOST",
+	"database.port":              "DB_PORT",
+	"database.user":              "DB_USER",
+	"database.password":          "DB_PASSWORD",
+	"database.name":              "DB_NAME",
+	"database.ssl_mode":          "DB_SSL_MODE",
+	"jwt.access_token_duration":  "ACCESS_TOKEN_DURATION",
+	"jwt.refresh_token_duration": "REFRESH_TOKEN_DURATION",
+	"aws.s3_bucket_name":         "S3_BUCKET_NAME",
+	"aws.s3_endpoint":            "S3_ENDPOINT",
+	"cors.allowed_origins":       "ALLOWED_ORIGINS",
+	"logging.level":              "LOG_LEVEL",
+	"tracing.endpoint":           "OTEL_EXPORTER_OTLP_ENDPOINT",
+}
+
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("server.port", "8080")
+	v.SetDefault("server.read_timeout", "15s")
+	v.SetDefault("server.write_timeout", "15s")
+	v.SetDefault("server.idle_timeout", "60s")
+	v.SetDefault("server.trusted_proxies", "")
+
+	v.SetDefault("database.ssl_mode", "disable")
+	v.SetDefault("database.max_open_conns", 25)
+	v.SetDefault("database.max_idle_conns", 10)
+	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.connect_timeout", "30s")
+	v.SetDefault("database.slow_query_threshold", "200ms")
+
+	v.SetDefault("jwt.access_token_duration", "15m")
+	v.SetDefault("jwt.refresh_token_duration", "168h")
+	v.SetDefault("jwt.impersonation_token_duration", "15m")
+
+	v.SetDefault("accounts.lowercase_email_local_part", true)
+	v.SetDefault("accounts.import_max_rows", 5000)
+
+	v.SetDefault("password.min_length", 8)
+	v.SetDefault("password.require_classes", "")
+	v.SetDefault("password.ban_common", true)
+	v.SetDefault("password.reject_email", true)
+
+	v.SetDefault("redis.addr", "localhost:6379")
+
+	v.SetDefault("rate_limit.auth_requests", 20)
+	v.SetDefault("rate_limit.auth_window", "1m")
+	v.SetDefault("rate_limit.reports_per_day", 10)
+
+	v.SetDefault("tracing.service_name", "golf-messenger")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	v.SetDefault("messaging.edit_window", "15m")
+	v.SetDefault("messaging.attachment_max_size", 10<<20)
+	v.SetDefault("messaging.ttr_attachment_quota", 200<<20)
+	v.SetDefault("messaging.user_attachment_quota", 100<<20)
+	v.SetDefault("messaging.attachment_url_ttl", "15m")
+
+	v.SetDefault("ttrs.restore_window", "168h")
+	v.SetDefault("ttrs.default_handicap", 18.0)
+	v.SetDefault("ttrs.change_retention", "720h")
+	v.SetDefault("ttrs.edit_lock_window", "2h")
+	v.SetDefault("ttrs.check_in_opens_before", "2h")
+	v.SetDefault("ttrs.check_in_closes_after", "1h")
+
+	v.SetDefault("notifications.digest_window", "1h")
+
+	v.SetDefault("webhooks.max_attempts", 5)
+	v.SetDefault("webhooks.initial_backoff", "30s")
+	v.SetDefault("webhooks.timeout", "10s")
+	v.SetDefault("webhooks.failing_after", 5)
+
+	v.SetDefault("outbox.poll_interval", "1s")
+	v.SetDefault("outbox.batch_size", 100)
+	v.SetDefault("outbox.lease", "5m")
+	v.SetDefault("outbox.max_attempts", 10)
+	v.SetDefault("outbox.initial_backoff", "10s")
+	v.SetDefault("outbox.retention", "168h")
+
+	v.SetDefault("analytics.sink", AnalyticsSinkNone)
+	v.SetDefault("analytics.file_path", "analytics.jsonl")
+	v.SetDefault("analytics.buffer_size", 10000)
+
+	v.SetDefault("slack.link_code_ttl", "15m")
+
+	v.SetDefault("digest.weekday", "monday")
+	v.SetDefault("digest.hour", 7)
+	v.SetDefault("digest.batch_size", 100)
+	v.SetDefault("digest.send_interval", "200ms")
+
+	v.SetDefault("retention.purge_after", "720h")
+	v.SetDefault("retention.batch_size", 500)
+	v.SetDefault("retention.inactive_after", "4320h")
+	v.SetDefault("retention.deactivate_after", "720h")
+	v.SetDefault("retention.security_events", "2160h")
+
+	v.SetDefault("leagues.standings_cache_ttl", "10m")
+
+	v.SetDefault("compression.min_size", 1024)
+
+	v.SetDefault("graphql.max_depth", 8)
+	v.SetDefault("graphql.max_complexity", 200)
+
+	v.SetDefault("avatars.max_size", 10<<20)
+	v.SetDefault("avatars.upload_url_ttl", "15m")
+
+	v.SetDefault("logging.level", "info")
+	v.SetDefault("logging.encoding", "json")
+	v.SetDefault("logging.output_paths", []string{"stdout"})
+	v.SetDefault("logging.error_output_paths", []string{"stderr"})
+}
+
+// Load reads configuration with precedence env > config file > defaults. The
+// config file is optional; CONFIG_PATH points at a specific file, otherwise
+// config.yaml is looked up in ./config and the working directory.
 func Load() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config")
-	viper.AddConfigPath(".")
+	v := viper.New()
+	setDefaults(v)
 
-	viper.AutomaticEnv()
+	envReplacer := strings.NewReplacer(".", "_")
+	v.SetEnvKeyReplacer(envReplacer)
+	v.AutomaticEnv()
+	for key, legacy := range legacyEnvKeys {
+		if err := v.BindEnv(key, strings.ToUpper(envReplacer.Replace(key)), legacy); err != nil {
+			return nil, fmt.Errorf("failed to bind env for %s: %w", key, err)
+		}
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	if path := os.Getenv("CONFIG_PATH"); path != "" {
+		v.SetConfigFile(path)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath("./config")
+		v.AddConfigPath(".")
+	}
+
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
 	}
 
 	config := &Config{}
+	var err error
 
-	config.Server.Port = viper.GetString("SERVER_PORT")
-	if config.Server.Port == "" {
-		config.Server.Port = fmt.Sprintf("%d", viper.GetInt("server.port"))
+	config.Server.Port = v.GetString("server.port")
+	if config.Server.ReadTimeout, err = getDuration(v, "server.read_timeout"); err != nil {
+		return nil, err
+	}
+	if config.Server.WriteTimeout, err = getDuration(v, "server.write_timeout"); err != nil {
+		return nil, err
+	}
+	if config.Server.IdleTimeout, err = getDuration(v, "server.idle_timeout"); err != nil {
+		return nil, err
 	}
-	config.Server.ReadTimeout = viper.GetDuration("server.read_timeout")
-	config.Server.WriteTimeout = viper.GetDuration("server.write_timeout")
-	config.Server.IdleTimeout = viper.GetDuration("server.idle_timeout")
+	config.Server.TrustedProxies = getStringSlice(v, "server.trusted_proxies")
 
-	config.Database.Host = viper.GetString("DB_HOST")
-	config.Database.Port = viper.GetString("DB_PORT")
-	config.Database.User = viper.GetString("DB_USER")
-	config.Database.Password = viper.GetString("DB_PASSWORD")
-	config.Database.DBName = viper.GetString("DB_NAME")
-	config.Database.SSLMode = viper.GetString("DB_SSL_MODE")
-	config.Database.MaxOpenConns = viper.GetInt("database.max_open_conns")
-	config.Database.MaxIdleConns = viper.GetInt("database.max_idle_conns")
-	config.Database.ConnMaxLifetime = viper.GetDuration("database.conn_max_lifetime")
+	config.Database.Host = v.GetString("database.host")
+	config.Database.Port = v.GetString("database.port")
+	config.Database.User = v.GetString("database.user")
+	config.Database.Password = v.GetString("database.password")
+	config.Database.DBName = v.GetString("database.name")
+	config.Database.SSLMode = v.GetString("database.ssl_mode")
+	config.Database.MaxOpenConns = v.GetInt("database.max_open_conns")
+	config.Database.MaxIdleConns = v.GetInt("database.max_idle_conns")
+	if config.Database.ConnMaxLifetime, err = getDuration(v, "database.conn_max_lifetime"); err != nil {
+		return nil, err
+	}
+	if config.Database.ConnectTimeout, err = getDuration(v, "database.connect_timeout"); err != nil {
+		return nil, err
+	}
+	if config.Database.SlowQueryThreshold, err = getDuration(v, "database.slow_query_threshold"); err != nil {
+		return nil, err
+	}
 
-	config.JWT.Secret = viper.GetString("JWT_SECRET")
-	accessTokenDuration := viper.GetString("ACCESS_TOKEN_DURATION")
-	if accessTokenDuration != "" {
-		duration, err := time.ParseDuration(accessTokenDuration)
-		if err != nil {
-			return nil, fmt.Errorf("invalid ACCESS_TOKEN_DURATION: %w", err)
-		}
-		config.JWT.AccessTokenDuration = duration
+	config.JWT.Secret = v.GetString("jwt.secret")
+	if config.JWT.AccessTokenDuration, err = getDuration(v, "jwt.access_token_duration"); err != nil {
+		return nil, err
 	}
-	refreshTokenDuration := viper.GetString("REFRESH_TOKEN_DURATION")
-	if refreshTokenDuration != "" {
-		duration, err := time.ParseDuration(refreshTokenDuration)
-		if err != nil {
-			return nil, fmt.Errorf("invalid REFRESH_TOKEN_DURATION: %w", err)
-		}
-		config.JWT.RefreshTokenDuration = duration
+	if config.JWT.RefreshTokenDuration, err = getDuration(v, "jwt.refresh_token_duration"); err != nil {
+		return nil, err
+	}
+	config.JWT.AllowWeakSecret = v.GetBool("jwt.allow_weak_secret")
+	if config.JWT.ImpersonationTokenDuration, err = getDuration(v, "jwt.impersonation_token_duration"); err != nil {
+		return nil, err
 	}
 
-	config.AWS.Region = viper.GetString("AWS_REGION")
-	config.AWS.AccessKeyID = viper.GetString("AWS_ACCESS_KEY_ID")
-	config.AWS.SecretAccessKey = viper.GetString("AWS_SECRET_ACCESS_KEY")
-	config.AWS.S3BucketName = viper.GetString("S3_BUCKET_NAME")
-	config.AWS.S3Endpoint = viper.GetString("S3_ENDPOINT")
+	config.Accounts.LowercaseEmailLocalPart = v.GetBool("accounts.lowercase_email_local_part")
+	config.Accounts.ImportMaxRows = v.GetInt("accounts.import_max_rows")
+
+	config.Passwords.MinLength = v.GetInt("password.min_length")
+	config.Passwords.RequireClasses = getStringSlice(v, "password.require_classes")
+	config.Passwords.BanCommon = v.GetBool("password.ban_common")
+	config.Passwords.RejectEmail = v.GetBool("password.reject_email")
+
+	config.AWS.Region = v.GetString("aws.region")
+	config.AWS.AccessKeyID = v.GetString("aws.access_key_id")
+	config.AWS.SecretAccessKey = v.GetString("aws.secret_access_key")
+	config.AWS.S3BucketName = v.GetString("aws.s3_bucket_name")
+	config.AWS.S3Endpoint = v.GetString("aws.s3_endpoint")
 
-	allowedOrigins := viper.GetString("ALLOWED_ORIGINS")
-	if allowedOrigins != "" {
-		config.CORS.AllowedOrigins = viper.GetStringSlice("ALLOWED_ORIGINS")
+	config.CORS.AllowedOrigins = getStringSlice(v, "cors.allowed_origins")
+
+	config.Redis.Enabled = v.GetBool("redis.enabled")
+	config.Redis.Addr = v.GetString("redis.addr")
+	config.Redis.Password = v.GetString("redis.password")
+	config.Redis.DB = v.GetInt("redis.db")
+
+	config.RateLimit.AuthRequests = v.GetInt("rate_limit.auth_requests")
+	if config.RateLimit.AuthWindow, err = getDuration(v, "rate_limit.auth_window"); err != nil {
+		return nil, err
 	}
+	config.RateLimit.ReportsPerDay = v.GetInt("rate_limit.reports_per_day")
 
-	config.Logging.Level = viper.GetString("LOG_LEVEL")
-	if config.Logging.Level == "" {
-		config.Logging.Level = viper.GetString("logging.level")
+	config.Tracing.Endpoint = v.GetString("tracing.endpoint")
+	config.Tracing.ServiceName = v.GetString("tracing.service_name")
+	config.Tracing.SampleRatio = v.GetFloat64("tracing.sample_ratio")
+
+	if config.Messaging.EditWindow, err = getDuration(v, "messaging.edit_window"); err != nil {
+		return nil, err
+	}
+	config.Messaging.AttachmentMaxSize = v.GetInt64("messaging.attachment_max_size")
+	config.Messaging.TTRAttachmentQuota = v.GetInt64("messaging.ttr_attachment_quota")
+	config.Messaging.UserAttachmentQuota = v.GetInt64("messaging.user_attachment_quota")
+	if config.Messaging.AttachmentURLTTL, err = getDuration(v, "messaging.attachment_url_ttl"); err != nil {
+		return nil, err
+	}
+
+	if config.TTRs.RestoreWindow, err = getDuration(v, "ttrs.restore_window"); err != nil {
+		return nil, err
+	}
+	config.TTRs.DefaultHandicap = v.GetFloat64("ttrs.default_handicap")
+	if config.TTRs.ChangeRetention, err = getDuration(v, "ttrs.change_retention"); err != nil {
+		return nil, err
+	}
+	if config.TTRs.EditLockWindow, err = getDuration(v, "ttrs.edit_lock_window"); err != nil {
+		return nil, err
+	}
+	if config.TTRs.CheckInOpensBefore, err = getDuration(v, "ttrs.check_in_opens_before"); err != nil {
+		return nil, err
+	}
+	if config.TTRs.CheckInClosesAfter, err = getDuration(v, "ttrs.check_in_closes_after"); err != nil {
+		return nil, err
+	}
+
+	if config.Notifications.DigestWindow, err = getDuration(v, "notifications.digest_window"); err != nil {
+		return nil, err
+	}
+
+	config.Webhooks.MaxAttempts = v.GetInt("webhooks.max_attempts")
+	if config.Webhooks.InitialBackoff, err = getDuration(v, "webhooks.initial_backoff"); err != nil {
+		return nil, err
+	}
+	if config.Webhooks.Timeout, err = getDuration(v, "webhooks.timeout"); err != nil {
+		return nil, err
+	}
+	config.Webhooks.FailingAfter = v.GetInt("webhooks.failing_after")
+
+	if config.Outbox.PollInterval, err = getDuration(v, "outbox.poll_interval"); err != nil {
+		return nil, err
+	}
+	config.Outbox.BatchSize = v.GetInt("outbox.batch_size")
+	if config.Outbox.Lease, err = getDuration(v, "outbox.lease"); err != nil {
+		return nil, err
+	}
+	config.Outbox.MaxAttempts = v.GetInt("outbox.max_attempts")
+	if config.Outbox.InitialBackoff, err = getDuration(v, "outbox.initial_backoff"); err != nil {
+		return nil, err
+	}
+	if config.Outbox.Retention, err = getDuration(v, "outbox.retention"); err != nil {
+		return nil, err
+	}
+
+	config.Analytics.Sink = v.GetString("analytics.sink")
+	config.Analytics.FilePath = v.GetString("analytics.file_path")
+	config.Analytics.KafkaBrokers = getStringSlice(v, "analytics.kafka_brokers")
+	config.Analytics.KafkaTopic = v.GetString("analytics.kafka_topic")
+	config.Analytics.KinesisStream = v.GetString("analytics.kinesis_stream")
+	config.Analytics.BufferSize = v.GetInt("analytics.buffer_size")
+	config.Analytics.UserSalt = v.GetString("analytics.user_salt")
+
+	config.Slack.SigningSecret = v.GetString("slack.signing_secret")
+	if config.Slack.LinkCodeTTL, err = getDuration(v, "slack.link_code_ttl"); err != nil {
+		return nil, err
+	}
+	config.Slack.PublicURL = strings.TrimRight(v.GetString("slack.public_url"), "/")
+
+	config.Mail.SMTPAddr = v.GetString("mail.smtp_addr")
+	config.Mail.Username = v.GetString("mail.username")
+	config.Mail.Password = v.GetString("mail.password")
+	config.Mail.From = v.GetString("mail.from")
+	config.Mail.EventsSendGridPublicKey = v.GetString("mail.events_sendgrid_public_key")
+	config.Mail.EventsSESTopicARN = v.GetString("mail.events_ses_topic_arn")
+
+	if config.Digest.Weekday, err = getWeekday(v, "digest.weekday"); err != nil {
+		return nil, err
+	}
+	config.Digest.Hour = v.GetInt("digest.hour")
+	config.Digest.BatchSize = v.GetInt("digest.batch_size")
+	if config.Digest.SendInterval, err = getDuration(v, "digest.send_interval"); err != nil {
+		return nil, err
+	}
+
+	if config.Retention.PurgeAfter, err = getDuration(v, "retention.purge_after"); err != nil {
+		return nil, err
+	}
+	config.Retention.BatchSize = v.GetInt("retention.batch_size")
+	if config.Retention.InactiveAfter, err = getDuration(v, "retention.inactive_after"); err != nil {
+		return nil, err
+	}
+	if config.Retention.DeactivateAfter, err = getDuration(v, "retention.deactivate_after"); err != nil {
+		return nil, err
+	}
+	if config.Retention.SecurityEvents, err = getDuration(v, "retention.security_events"); err != nil {
+		return nil, err
+	}
+
+	if config.Leagues.StandingsCacheTTL, err = getDuration(v, "leagues.standings_cache_ttl"); err != nil {
+		return nil, err
+	}
+
+	config.Compression.Enabled = v.GetBool("compression.enabled")
+	config.Compression.MinSize = v.GetInt("compression.min_size")
+
+	config.Avatars.MaxSize = v.GetInt64("avatars.max_size")
+	if config.Avatars.UploadURLTTL, err = getDuration(v, "avatars.upload_url_ttl"); err != nil {
+		return nil, err
+	}
+
+	config.Logging.Level = v.GetString("logging.level")
+	config.Logging.Encoding = v.GetString("logging.encoding")
+	config.Logging.OutputPaths = getStringSlice(v, "logging.output_paths")
+	config.Logging.ErrorOutputPaths = getStringSlice(v, "logging.error_output_paths")
+
+	if config.API.V1Sunset, err = getDate(v, "api.v1_sunset"); err != nil {
+		return nil, err
+	}
+	config.API.ListRoutes = v.GetBool("api.list_routes")
+
+	config.GraphQL.MaxDepth = v.GetInt("graphql.max_depth")
+	config.GraphQL.MaxComplexity = v.GetInt("graphql.max_complexity")
+
+	if config.FeatureFlags.Rollouts, err = getRollouts(v, "feature_flags.rollouts"); err != nil {
+		return nil, err
+	}
+
+	config.Maintenance.Enabled = v.GetBool("maintenance.enabled")
+	if config.Maintenance.EndsAt, err = getTimestamp(v, "maintenance.ends_at"); err != nil {
+		return nil, err
+	}
+
+	if config.Signing.Clients, err = getPairs(v, "signing.clients"); err != nil {
+		return nil, err
+	}
+	scopes, err := getPairs(v, "signing.client_scopes")
+	if err != nil {
+		return nil, err
+	}
+	config.Signing.Scopes = make(map[string][]string, len(scopes))
+	for keyID, names := range scopes {
+		config.Signing.Scopes[keyID] = strings.Fields(names)
 	}
-	config.Logging.Encoding = viper.GetString("logging.encoding")
-	config.Logging.OutputPaths = viper.GetStringSlice("logging.output_paths")
-	config.Logging.ErrorOutputPaths = viper.GetStringSlice("logging.error_output_paths")
 
 	return config, nil
 }
 
+func getDuration(v *viper.Viper, key string) (time.Duration, error) {
+	raw := v.GetString(key)
+	if raw == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return duration, nil
+}
+
+// getDate parses a 2006-01-02 date as midnight UTC. An empty value is the
+// zero time.
+func getDate(v *viper.Viper, key string) (time.Time, error) {
+	raw := v.GetString(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return date, nil
+}
+
+// getTimestamp parses an RFC3339 timestamp. An empty value is the zero time.
+func getTimestamp(v *viper.Viper, key string) (time.Time, error) {
+	raw := v.GetString(key)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return timestamp, nil
+}
+
+// getWeekday parses an English day name such as "monday", in any case.
+func getWeekday(v *viper.Viper, key string) (time.Weekday, error) {
+	raw := strings.ToLower(strings.TrimSpace(v.GetString(key)))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == raw {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s: %q is not a day of the week", key, raw)
+}
+
+// getStringSlice accepts either a yaml list or a comma-separated string, which
+// is how list values arrive from the environment.
+func getStringSlice(v *viper.Viper, key string) []string {
+	raw, ok := v.Get(key).(string)
+	if !ok {
+		return v.GetStringSlice(key)
+	}
+	var values []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+// getRollouts parses a list of flag=percentage pairs such as
+// "waitlist=25,messaging=100".
+func getRollouts(v *viper.Viper, key string) (map[string]int, error) {
+	rollouts := make(map[string]int)
+	for _, pair := range getStringSlice(v, key) {
+		flag, raw, ok := strings.Cut(pair, "=")
+		flag = strings.TrimSpace(flag)
+		if !ok || !featureflag.ValidKey(flag) {
+			return nil, fmt.Errorf("invalid %s: %q is not flag=percentage", key, pair)
+		}
+		percentage, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || percentage < 0 || percentage > 100 {
+			return nil, fmt.Errorf("invalid %s: percentage for %s must be 0 to 100", key, flag)
+		}
+		rollouts[flag] = percentage
+	}
+	return rollouts, nil
+}
+
+// getPairs parses a list of name=value pairs such as
+// "scheduler=s3cr3t,reports=0th3r". Values may contain "=".
+func getPairs(v *viper.Viper, key string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, pair := range getStringSlice(v, key) {
+		name, value, ok := strings.Cut(pair, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid %s: %q is not name=value", key, pair)
+		}
+		pairs[name] = value
+	}
+	return pairs, nil
+}
+
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		c.Database.Host,
@@ -162,5 +874,164 @@ func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("SERVER_PORT is required")
 	}
+	if _, err := clientip.New(c.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("SERVER_TRUSTED_PROXIES: %w", err)
+	}
+	if c.Redis.Enabled && c.Redis.Addr == "" {
+		return fmt.Errorf("REDIS_ADDR is required when REDIS_ENABLED is set")
+	}
+	if c.Notifications.DigestWindow < 0 {
+		return fmt.Errorf("NOTIFICATIONS_DIGEST_WINDOW cannot be negative")
+	}
+	if c.Webhooks.MaxAttempts < 0 || c.Webhooks.FailingAfter < 0 {
+		return fmt.Errorf("WEBHOOKS_MAX_ATTEMPTS and WEBHOOKS_FAILING_AFTER cannot be negative")
+	}
+	if c.Outbox.PollInterval <= 0 || c.Outbox.Lease <= 0 || c.Outbox.Retention <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL, OUTBOX_LEASE and OUTBOX_RETENTION must be positive")
+	}
+	if c.Outbox.BatchSize < 1 || c.Outbox.MaxAttempts < 1 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE and OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+	if err := c.validateAnalytics(); err != nil {
+		return err
+	}
+	if c.Slack.SigningSecret != "" && c.Slack.LinkCodeTTL <= 0 {
+		return fmt.Errorf("SLACK_LINK_CODE_TTL must be positive")
+	}
+	if c.Mail.Enabled() && c.Mail.From == "" {
+		return fmt.Errorf("MAIL_FROM is required when MAIL_SMTP_ADDR is set")
+	}
+	if c.Mail.EventsSendGridPublicKey != "" {
+		if _, err := mailevents.ParseSendGridPublicKey(c.Mail.EventsSendGridPublicKey); err != nil {
+			return fmt.Errorf("MAIL_EVENTS_SENDGRID_PUBLIC_KEY must be a base64 ECDSA public key")
+		}
+	}
+	if c.Digest.Hour < 0 || c.Digest.Hour > 23 {
+		return fmt.Errorf("DIGEST_HOUR must be between 0 and 23")
+	}
+	if c.Digest.SendInterval < 0 {
+		return fmt.Errorf("DIGEST_SEND_INTERVAL cannot be negative")
+	}
+	if c.Accounts.ImportMaxRows < 1 {
+		return fmt.Errorf("ACCOUNTS_IMPORT_MAX_ROWS must be at least 1")
+	}
+	if c.Passwords.MinLength < 8 {
+		return fmt.Errorf("PASSWORD_MIN_LENGTH must be at least 8")
+	}
+	for _, class := range c.Passwords.RequireClasses {
+		if !password.IsClass(password.Rule(class)) {
+			return fmt.Errorf("PASSWORD_REQUIRE_CLASSES must list lowercase, uppercase, digit or symbol, not %q", class)
+		}
+	}
+	if c.GraphQL.MaxDepth < 1 || c.GraphQL.MaxComplexity < 1 {
+		return fmt.Errorf("GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY must be at least 1")
+	}
+	if c.RateLimit.ReportsPerDay < 1 {
+		return fmt.Errorf("RATE_LIMIT_REPORTS_PER_DAY must be at least 1")
+	}
+	if c.TTRs.DefaultHandicap < 0 || c.TTRs.DefaultHandicap > 54 {
+		return fmt.Errorf("TTRS_DEFAULT_HANDICAP must be between 0 and 54")
+	}
+	if c.TTRs.ChangeRetention <= 0 {
+		return fmt.Errorf("TTRS_CHANGE_RETENTION must be positive")
+	}
+	if c.TTRs.EditLockWindow < 0 {
+		return fmt.Errorf("TTRS_EDIT_LOCK_WINDOW cannot be negative")
+	}
+	if c.TTRs.CheckInOpensBefore < 0 || c.TTRs.CheckInClosesAfter < 0 {
+		return fmt.Errorf("TTRS_CHECK_IN_OPENS_BEFORE and TTRS_CHECK_IN_CLOSES_AFTER cannot be negative")
+	}
+	if c.Retention.PurgeAfter < c.TTRs.RestoreWindow {
+		return fmt.Errorf("RETENTION_PURGE_AFTER must be at least TTRS_RESTORE_WINDOW")
+	}
+	if c.Retention.InactiveAfter < 0 || c.Retention.DeactivateAfter < 0 {
+		return fmt.Errorf("RETENTION_INACTIVE_AFTER and RETENTION_DEACTIVATE_AFTER cannot be negative")
+	}
+	if c.Retention.SecurityEvents <= 0 {
+		return fmt.Errorf("RETENTION_SECURITY_EVENTS must be positive")
+	}
+	if err := c.validateSigning(); err != nil {
+		return err
+	}
+	return c.validateJWT()
+}
+
+func (c *Config) validateSigning() error {
+	for keyID, secret := range c.Signing.Clients {
+		if len(secret) < MinSigningSecretLength {
+			return fmt.Errorf("SIGNING_CLIENTS secret for %s must be at least %d bytes", keyID, MinSigningSecretLength)
+		}
+	}
+	for keyID, names := range c.Signing.Scopes {
+		if _, ok := c.Signing.Clients[keyID]; !ok {
+			return fmt.Errorf("SIGNING_CLIENT_SCOPES names unknown client %s", keyID)
+		}
+		if _, err := scope.ParseList(names); err != nil || len(names) == 0 {
+			return fmt.Errorf("SIGNING_CLIENT_SCOPES for %s must list known scopes", keyID)
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateAnalytics() error {
+	switch c.Analytics.Sink {
+	case AnalyticsSinkNone:
+		return nil
+	case AnalyticsSinkFile:
+		if c.Analytics.FilePath == "" {
+			return fmt.Errorf("ANALYTICS_FILE_PATH is required when ANALYTICS_SINK is file")
+		}
+	case AnalyticsSinkKafka:
+		if len(c.Analytics.KafkaBrokers) == 0 || c.Analytics.KafkaTopic == "" {
+			return fmt.Errorf("ANALYTICS_KAFKA_BROKERS and ANALYTICS_KAFKA_TOPIC are required when ANALYTICS_SINK is kafka")
+		}
+	case AnalyticsSinkKinesis:
+		if c.Analytics.KinesisStream == "" {
+			return fmt.Errorf("ANALYTICS_KINESIS_STREAM is required when ANALYTICS_SINK is kinesis")
+		}
+	default:
+		return fmt.Errorf("ANALYTICS_SINK must be none, file, kafka or kinesis")
+	}
+	if c.Analytics.BufferSize <= 0 {
+		return fmt.Errorf("ANALYTICS_BUFFER_SIZE must be positive")
+	}
+	if c.Analytics.UserSalt == "" {
+		return fmt.Errorf("ANALYTICS_USER_SALT is required when ANALYTICS_SINK is set")
+	}
+	return nil
+}
+
+func (c *Config) validateJWT() error {
+	if err := checkJWTSecret(c.JWT.Secret); err != nil {
+		if !c.JWT.AllowWeakSecret {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: %v; continuing because JWT_ALLOW_WEAK_SECRET is set. Never use this outside local development.\n", err)
+	}
+
+	if c.JWT.AccessTokenDuration <= 0 {
+		return fmt.Errorf("ACCESS_TOKEN_DURATION must be positive")
+	}
+	if c.JWT.RefreshTokenDuration <= 0 {
+		return fmt.Errorf("REFRESH_TOKEN_DURATION must be positive")
+	}
+	if c.JWT.AccessTokenDuration >= c.JWT.RefreshTokenDuration {
+		return fmt.Errorf("ACCESS_TOKEN_DURATION must be shorter than REFRESH_TOKEN_DURATION")
+	}
+	if c.JWT.ImpersonationTokenDuration <= 0 {
+		return fmt.Errorf("JWT_IMPERSONATION_TOKEN_DURATION must be positive")
+	}
+	return nil
+}
+
+func checkJWTSecret(secret string) error {
+	for _, weak := range weakJWTSecrets {
+		if strings.EqualFold(secret, weak) {
+			return fmt.Errorf("JWT_SECRET is a well-known default value")
+		}
+	}
+	if len(secret) < MinJWTSecretLength {
+		return fmt.Errorf("JWT_SECRET must be at least %d bytes", MinJWTSecretLength)
+	}
 	return nil
 }
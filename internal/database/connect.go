@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Dialer opens a database connection and checks that it is usable.
+type Dialer func(ctx context.Context) (*gorm.DB, error)
+
+// Backoff is the retry schedule Connect follows: the delay starts at Initial
+// and doubles after every failed attempt up to Max, until Timeout has passed.
+// A zero Timeout disables retries.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Timeout time.Duration
+}
+
+// Delay returns how long to wait after the given failed attempt, counting
+// from 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if delay > b.Max {
+		delay = b.Max
+	}
+	return delay
+}
+
+// Connect dials until it succeeds or the backoff's timeout runs out, logging
+// every failed attempt. The error from the last attempt is returned.
+func Connect(ctx context.Context, dial Dialer, backoff Backoff, log *zap.Logger) (*gorm.DB, error) {
+	if backoff.Timeout <= 0 {
+		db, err := dial(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		return db, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backoff.Timeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		db, err := dial(ctx)
+		if err == nil {
+			return db, nil
+		}
+
+		delay := backoff.Delay(attempt)
+		log.Warn("database connection failed",
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+	}
+}
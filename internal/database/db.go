@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"github.com/yourusername/golf_messenger/internal/config"
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -15,20 +17,43 @@ type Database struct {
 	DB *gorm.DB
 }
 
-func NewDatabase(cfg *config.Config) (*Database, error) {
+func NewDatabase(cfg *config.Config, log *zap.Logger) (*Database, error) {
 	dsn := cfg.GetDSN()
 
+	queryLogger := NewQueryLogger(log, cfg.Database.SlowQueryThreshold)
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger: queryLogger,
 	}
 
 	if cfg.Logging.Level == "debug" {
-		gormConfig.Logger = logger.Default.LogMode(logger.Info)
+		gormConfig.Logger = queryLogger.LogMode(logger.Info)
 	}
 
-	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	db, err := Connect(context.Background(), func(ctx context.Context) (*gorm.DB, error) {
+		db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			sqlDB.Close()
+			return nil, err
+		}
+		return db, nil
+	}, Backoff{
+		Initial: 500 * time.Millisecond,
+		Max:     5 * time.Second,
+		Timeout: cfg.Database.ConnectTimeout,
+	}, log)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
+	}
+
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithDBName(cfg.Database.DBName))); err != nil {
+		return nil, fmt.Errorf("failed to install tracing plugin: %w", err)
 	}
 
 	sqlDB, err := db.DB()
@@ -40,10 +65,6 @@ func NewDatabase(cfg *config.Config) (*Database, error) {
 	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
 	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
 
-	if err := sqlDB.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
 	return &Database{DB: db}, nil
 }
 
@@ -55,16 +76,20 @@ func (d *Database) Close() error {
 	return sqlDB.Close()
 }
 
-func (d *Database) HealthCheck(ctx context.Context) error {
+// Ping checks that the database is reachable, for use by readiness checks.
+func (d *Database) Ping(ctx context.Context) error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
+	return sqlDB.PingContext(ctx)
+}
 
+func (d *Database) HealthCheck(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err := sqlDB.PingContext(ctx); err != nil {
+	if err := d.Ping(ctx); err != nil {
 		return fmt.Errorf("database health check failed: %w", err)
 	}
 
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+	"gorm.io/gorm"
+)
+
+// NormalizeEmails recomputes every user's normalized email, including
+// soft-deleted users, and the address on every organization invitation with
+// n. It is needed after the normalization rules change. When two accounts
+// would end up with the same normalized email nothing is written and the
+// collisions are returned instead, to be resolved by hand.
+func NormalizeEmails(ctx context.Context, db *gorm.DB, n emailnorm.Normalizer) ([]emailnorm.Collision, error) {
+	var users []models.User
+	if err := db.WithContext(ctx).Unscoped().Select("id", "email", "normalized_email").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	emails := make([]string, 0, len(users))
+	for _, user := range users {
+		emails = append(emails, user.Email)
+	}
+	if collisions := n.Collisions(emails); len(collisions) > 0 {
+		return collisions, nil
+	}
+
+	var invitations []models.OrganizationInvitation
+	if err := db.WithContext(ctx).Select("id", "email").Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to load organization invitations: %w", err)
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, user := range users {
+			normalized := n.Normalize(user.Email)
+			if normalized == user.NormalizedEmail {
+				continue
+			}
+			if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("normalized_email", normalized).Error; err != nil {
+				return fmt.Errorf("failed to update user %s: %w", user.ID, err)
+			}
+		}
+		for _, invitation := range invitations {
+			normalized := n.Normalize(invitation.Email)
+			if normalized == invitation.Email {
+				continue
+			}
+			if err := tx.Model(&models.OrganizationInvitation{}).Where("id = ?", invitation.ID).UpdateColumn("email", normalized).Error; err != nil {
+				return fmt.Errorf("failed to update organization invitation %s: %w", invitation.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/tracing"
+	"go.uber.org/zap"
+	"gorm.io/gorm/logger"
+)
+
+// QueryLogger is a gorm logger backed by zap. Queries slower than the
+// threshold are logged at warn level; at the Info log mode every query is
+// logged at debug level. Query parameters are never logged, and every query
+// is counted against the request it ran for.
+type QueryLogger struct {
+	logger        *zap.Logger
+	slowThreshold time.Duration
+	level         logger.LogLevel
+}
+
+// NewQueryLogger returns a QueryLogger in Warn mode. A zero slowThreshold
+// disables slow-query logging.
+func NewQueryLogger(log *zap.Logger, slowThreshold time.Duration) *QueryLogger {
+	return &QueryLogger{
+		logger:        log,
+		slowThreshold: slowThreshold,
+		level:         logger.Warn,
+	}
+}
+
+func (l *QueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *QueryLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.Sugar().Infof(msg, data...)
+	}
+}
+
+func (l *QueryLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.Sugar().Warnf(msg, data...)
+	}
+}
+
+func (l *QueryLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.Sugar().Errorf(msg, data...)
+	}
+}
+
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	middleware.CountQuery(ctx)
+
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+
+	switch {
+	case slow && l.level >= logger.Warn:
+		l.logger.Warn("slow query", l.queryFields(ctx, elapsed, fc, err)...)
+	case l.level >= logger.Info:
+		l.logger.Debug("query", l.queryFields(ctx, elapsed, fc, err)...)
+	}
+}
+
+// ParamsFilter drops the query's bind parameters so the SQL handed to Trace
+// keeps its placeholders instead of user data.
+func (l *QueryLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+func (l *QueryLogger) queryFields(ctx context.Context, elapsed time.Duration, fc func() (string, int64), err error) []zap.Field {
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", sql),
+		zap.Int64("rows_affected", rows),
+		zap.Duration("duration", elapsed),
+	}
+	if requestID := middleware.RequestID(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return append(fields, tracing.LogFields(ctx)...)
+}
@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// RepairOptions controls the roster checks. Fixes are only written when
+// Apply is set. Rosters of TTRs deleted within RestoreWindow of Now are left
+// alone, since restoring the TTR brings them back.
+type RepairOptions struct {
+	Apply         bool
+	Now           time.Time
+	RestoreWindow time.Duration
+}
+
+// RepairIssue is one row breaking a roster invariant. Only the IDs that name
+// the row are set.
+type RepairIssue struct {
+	TTRID          *uuid.UUID `json:"ttr_id,omitempty"`
+	UserID         *uuid.UUID `json:"user_id,omitempty"`
+	InvitationID   *uuid.UUID `json:"invitation_id,omitempty"`
+	NotificationID *uuid.UUID `json:"notification_id,omitempty"`
+	Problem        string     `json:"problem"`
+}
+
+// RepairCheck is what one check found and, with Apply, how many of the
+// issues it fixed. A ReportOnly check never fixes anything; its issues need
+// someone to decide what to do.
+type RepairCheck struct {
+	Name       string        `json:"name"`
+	ReportOnly bool          `json:"report_only,omitempty"`
+	Issues     []RepairIssue `json:"issues"`
+	Fixed      int           `json:"fixed"`
+}
+
+// RepairReport is the outcome of RepairRosters, one entry per check.
+type RepairReport struct {
+	Applied bool          `json:"applied"`
+	Checks  []RepairCheck `json:"checks"`
+}
+
+// repairChecks are the checks RepairRosters runs, in order.
+var repairChecks = []func(context.Context, *gorm.DB, RepairOptions) (RepairCheck, error){
+	CheckCoCaptainsNotPlayers,
+	CheckPlayersOnDeletedTTRs,
+	CheckOverfullRosters,
+	CheckStalePendingInvitations,
+	CheckOrphanNotifications,
+}
+
+// RepairRosters runs every roster check in one transaction, so with Apply
+// either all the fixes are written or none are. Soft-deleted rows are looked
+// at too.
+func RepairRosters(ctx context.Context, db *gorm.DB, opts RepairOptions) (*RepairReport, error) {
+	report := &RepairReport{Applied: opts.Apply, Checks: make([]RepairCheck, 0, len(repairChecks))}
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, check := range repairChecks {
+			result, err := check(ctx, tx, opts)
+			if err != nil {
+				return err
+			}
+			report.Checks = append(report.Checks, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// CheckCoCaptainsNotPlayers finds co-captains of live TTRs who aren't on the
+// roster, left behind when players were removed without their role. The fix
+// takes the co-captain role away.
+func CheckCoCaptainsNotPlayers(ctx context.Context, db *gorm.DB, opts RepairOptions) (RepairCheck, error) {
+	result := RepairCheck{Name: "co_captains_not_players", Issues: []RepairIssue{}}
+
+	var rows []struct {
+		TTRID  uuid.UUID
+		UserID uuid.UUID
+	}
+	if err := db.WithContext(ctx).Table("ttr_co_captains AS c").
+		Select("c.ttr_id, c.user_id").
+		Joins("JOIN ttrs t ON t.id = c.ttr_id AND t.deleted_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM ttr_players p WHERE p.ttr_id = c.ttr_id AND p.user_id = c.user_id)").
+		Order("c.ttr_id, c.user_id").
+		Scan(&rows).Error; err != nil {
+		return result, fmt.Errorf("failed to find co-captains who aren't players: %w", err)
+	}
+
+	for _, row := range rows {
+		row := row
+		result.Issues = append(result.Issues, RepairIssue{TTRID: &row.TTRID, UserID: &row.UserID, Problem: "co-captain isn't on the roster"})
+		if !opts.Apply {
+			continue
+		}
+		if err := db.WithContext(ctx).Where("ttr_id = ? AND user_id = ?", row.TTRID, row.UserID).Delete(&models.TTRCoCaptain{}).Error; err != nil {
+			return result, fmt.Errorf("failed to remove co-captain %s from TTR %s: %w", row.UserID, row.TTRID, err)
+		}
+		result.Fixed++
+	}
+	return result, nil
+}
+
+// CheckPlayersOnDeletedTTRs finds roster rows of TTRs that are gone: missing
+// altogether, or deleted longer ago than the restore window and so never
+// coming back. The fix removes the players.
+func CheckPlayersOnDeletedTTRs(ctx context.Context, db *gorm.DB, opts RepairOptions) (RepairCheck, error) {
+	result := RepairCheck{Name: "players_on_deleted_ttrs", Issues: []RepairIssue{}}
+
+	var rows []struct {
+		TTRID   uuid.UUID
+		UserID  uuid.UUID
+		Missing bool
+	}
+	if err := db.WithContext(ctx).Table("ttr_players AS p").
+		Select("p.ttr_id, p.user_id, t.id IS NULL AS missing").
+		Joins("LEFT JOIN ttrs t ON t.id = p.ttr_id").
+		Where("t.id IS NULL OR t.deleted_at < ?", opts.Now.Add(-opts.RestoreWindow)).
+		Order("p.ttr_id, p.user_id").
+		Scan(&rows).Error; err != nil {
+		return result, fmt.Errorf("failed to find players on deleted TTRs: %w", err)
+	}
+
+	for _, row := range rows {
+		row := row
+		problem := "TTR was deleted and can no longer be restored"
+		if row.Missing {
+			problem = "TTR doesn't exist"
+		}
+		result.Issues = append(result.Issues, RepairIssue{TTRID: &row.TTRID, UserID: &row.UserID, Problem: problem})
+		if !opts.Apply {
+			continue
+		}
+		if err := db.WithContext(ctx).Where("ttr_id = ? AND user_id = ?", row.TTRID, row.UserID).Delete(&models.TTRPlayer{}).Error; err != nil {
+			return result, fmt.Errorf("failed to remove player %s from TTR %s: %w", row.UserID, row.TTRID, err)
+		}
+		result.Fixed++
+	}
+	return result, nil
+}
+
+// CheckOverfullRosters finds live TTRs whose active players and guests take
+// more slots than MaxPlayers. Which players should go is the captain's call,
+// so it only reports them.
+func CheckOverfullRosters(ctx context.Context, db *gorm.DB, opts RepairOptions) (RepairCheck, error) {
+	result := RepairCheck{Name: "overfull_rosters", ReportOnly: true, Issues: []RepairIssue{}}
+
+	const query = `
+SELECT id, max_players, headcount FROM (
+	SELECT t.id, t.max_players,
+		(SELECT COUNT(*) FROM ttr_players p WHERE p.ttr_id = t.id AND p.status IN ?) +
+		(SELECT COUNT(*) FROM ttr_guests g WHERE g.ttr_id = t.id) AS headcount
+	FROM ttrs t
+	WHERE t.deleted_at IS NULL
+) rosters
+WHERE headcount > max_players
+ORDER BY id`
+
+	var rows []struct {
+		ID         uuid.UUID
+		MaxPlayers int
+		Headcount  int
+	}
+	if err := db.WithContext(ctx).Raw(query, models.ActivePlayerStatuses()).Scan(&rows).Error; err != nil {
+		return result, fmt.Errorf("failed to find overfull rosters: %w", err)
+	}
+
+	for _, row := range rows {
+		row := row
+		problem := fmt.Sprintf("roster takes %d slots of %d", row.Headcount, row.MaxPlayers)
+		result.Issues = append(result.Issues, RepairIssue{TTRID: &row.ID, Problem: problem})
+	}
+	return result, nil
+}
+
+// CheckStalePendingInvitations finds PENDING invitations to TTRs that can't
+// be joined any more: missing, deleted, cancelled, or teeing off before
+// yesterday. The fix closes them the way the TTR's services would have:
+// cancelled with the TTR, or expired once it has passed.
+func CheckStalePendingInvitations(ctx context.Context, db *gorm.DB, opts RepairOptions) (RepairCheck, error) {
+	result := RepairCheck{Name: "stale_pending_invitations", Issues: []RepairIssue{}}
+
+	var rows []struct {
+		ID     uuid.UUID
+		TTRID  uuid.UUID
+		Reason string
+	}
+	if err := db.WithContext(ctx).Table("invitations AS i").
+		Select(`i.id, i.ttr_id, CASE
+			WHEN t.id IS NULL THEN 'missing'
+			WHEN t.deleted_at IS NOT NULL THEN 'deleted'
+			WHEN t.status = ? THEN 'cancelled'
+			ELSE 'past' END AS reason`, models.TTRStatusCancelled).
+		Joins("LEFT JOIN ttrs t ON t.id = i.ttr_id").
+		Where("i.status = ? AND (t.id IS NULL OR t.deleted_at IS NOT NULL OR t.status = ? OR t.tee_date < ?)",
+			models.InvitationStatusPending, models.TTRStatusCancelled, opts.Now.AddDate(0, 0, -1).Truncate(24*time.Hour)).
+		Order("i.ttr_id, i.id").
+		Scan(&rows).Error; err != nil {
+		return result, fmt.Errorf("failed to find stale pending invitations: %w", err)
+	}
+
+	problems := map[string]string{
+		"missing":   "TTR doesn't exist",
+		"deleted":   "TTR was deleted",
+		"cancelled": "TTR was cancelled",
+		"past":      "TTR has already been played",
+	}
+	for _, row := range rows {
+		row := row
+		result.Issues = append(result.Issues, RepairIssue{TTRID: &row.TTRID, InvitationID: &row.ID, Problem: problems[row.Reason]})
+		if !opts.Apply {
+			continue
+		}
+		status := models.InvitationStatusCanceled
+		if row.Reason == "past" {
+			status = models.InvitationStatusExpired
+		}
+		if err := db.WithContext(ctx).Model(&models.Invitation{}).Where("id = ?", row.ID).UpdateColumn("status", status).Error; err != nil {
+			return result, fmt.Errorf("failed to close invitation %s: %w", row.ID, err)
+		}
+		result.Fixed++
+	}
+	return result, nil
+}
+
+// notificationTargets maps each notification target type to the table its
+// targets live in.
+var notificationTargets = map[string]string{
+	"invitation":              "invitations",
+	"organization_invitation": "organization_invitations",
+	"report":                  "reports",
+	"tournament":              "tournaments",
+	"ttr":                     "ttrs",
+	"user":                    "users",
+}
+
+// CheckOrphanNotifications finds notifications whose target row no longer
+// exists, soft-deleted rows counting as existing. Opening one leads nowhere,
+// so the fix deletes it. Target types it doesn't know are left alone.
+func CheckOrphanNotifications(ctx context.Context, db *gorm.DB, opts RepairOptions) (RepairCheck, error) {
+	result := RepairCheck{Name: "orphan_notifications", Issues: []RepairIssue{}}
+
+	targetTypes := make([]string, 0, len(notificationTargets))
+	for targetType := range notificationTargets {
+		targetTypes = append(targetTypes, targetType)
+	}
+	sort.Strings(targetTypes)
+
+	for _, targetType := range targetTypes {
+		var ids []uuid.UUID
+		if err := db.WithContext(ctx).Table("notifications AS n").
+			Where("n.target_type = ? AND n.target_id IS NOT NULL", targetType).
+			Where("NOT EXISTS (SELECT 1 FROM "+notificationTargets[targetType]+" x WHERE x.id = n.target_id)").
+			Order("n.id").
+			Pluck("n.id", &ids).Error; err != nil {
+			return result, fmt.Errorf("failed to find notifications about missing %s targets: %w", targetType, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		for i := range ids {
+			result.Issues = append(result.Issues, RepairIssue{NotificationID: &ids[i], Problem: targetType + " doesn't exist"})
+		}
+		if !opts.Apply {
+			continue
+		}
+		deleted := db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.Notification{})
+		if deleted.Error != nil {
+			return result, fmt.Errorf("failed to delete notifications about missing %s targets: %w", targetType, deleted.Error)
+		}
+		result.Fixed += int(deleted.RowsAffected)
+	}
+	return result, nil
+}
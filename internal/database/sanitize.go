@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
+	"gorm.io/gorm"
+)
+
+// sanitizedColumns are the free-text columns services pass through
+// sanitize.Text on write, with the columns that key their rows.
+var sanitizedColumns = []struct {
+	table  string
+	column string
+	keys   []string
+}{
+	{"ttrs", "notes", []string{"id"}},
+	{"ttr_players", "notes", []string{"ttr_id", "user_id"}},
+	{"invitations", "message", []string{"id"}},
+	{"ttr_messages", "body", []string{"id"}},
+}
+
+// SanitizeText runs sanitize.Text over the free text stored before it was
+// sanitized on write, soft-deleted rows included, and returns how many values
+// it changed. Sanitized text is left alone, so it is safe to run again.
+func SanitizeText(ctx context.Context, db *gorm.DB) (int, error) {
+	changed := 0
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, col := range sanitizedColumns {
+			var rows []map[string]interface{}
+			if err := tx.Table(col.table).
+				Select(append(append([]string(nil), col.keys...), col.column)).
+				Where(col.column + " IS NOT NULL").
+				Find(&rows).Error; err != nil {
+				return fmt.Errorf("failed to load %s.%s: %w", col.table, col.column, err)
+			}
+
+			for _, row := range rows {
+				var text string
+				switch value := row[col.column].(type) {
+				case string:
+					text = value
+				case []byte:
+					text = string(value)
+				}
+				clean := sanitize.Text(text)
+				if clean == text {
+					continue
+				}
+
+				update := tx.Table(col.table)
+				for _, key := range col.keys {
+					update = update.Where(key+" = ?", row[key])
+				}
+				if err := update.UpdateColumn(col.column, clean).Error; err != nil {
+					return fmt.Errorf("failed to update %s.%s: %w", col.table, col.column, err)
+				}
+				changed++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+// maxActionItemHorizonHours caps the horizon_hours query parameter at two
+// weeks.
+const maxActionItemHorizonHours = 336
+
+type ActionItemHandler struct {
+	actionItemService *service.ActionItemService
+}
+
+func NewActionItemHandler(actionItemService *service.ActionItemService) *ActionItemHandler {
+	return &ActionItemHandler{actionItemService: actionItemService}
+}
+
+type ActionItemResponse struct {
+	Kind         string  `json:"kind"`
+	TTRID        string  `json:"ttr_id"`
+	InvitationID *string `json:"invitation_id,omitempty"`
+	CourseName   string  `json:"course_name"`
+	DueAt        string  `json:"due_at"`
+}
+
+// ListActionItems godoc
+// @Summary List action items
+// @Description List what the caller has to act on, most urgent first. kind is pending_invitation for invitations waiting for the caller's answer (invitation_id is set), unanswered_invitations for TTRs the caller captains that tee off within horizon_hours and still have pending invitations, and maybe_status for TTRs the caller is a MAYBE on. due_at is the RSVP deadline when there is one ahead, otherwise the tee time.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param horizon_hours query int false "How far ahead to look for captained TTRs, 1 to 336 (default 72)"
+// @Success 200 {object} response.Response{data=[]ActionItemResponse} "Action items retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid horizon_hours"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/me/action-items [get]
+func (h *ActionItemHandler) ListActionItems(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	horizon := service.DefaultActionItemHorizon
+	if raw := r.URL.Query().Get("horizon_hours"); raw != "" {
+		hours, err := strconv.Atoi(raw)
+		if err != nil || hours < 1 || hours > maxActionItemHorizonHours {
+			response.BadRequest(w, "Invalid horizon_hours")
+			return
+		}
+		horizon = time.Duration(hours) * time.Hour
+	}
+
+	items, err := h.actionItemService.ListActionItems(r.Context(), userID, horizon)
+	if err != nil {
+		response.FromError(w, err, "Failed to list action items")
+		return
+	}
+
+	itemResponses := make([]ActionItemResponse, 0, len(items))
+	for _, item := range items {
+		itemResponses = append(itemResponses, FromActionItem(item))
+	}
+
+	response.Success(w, http.StatusOK, itemResponses)
+}
@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/logger"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	logLevel      zap.AtomicLevel
+	statsService  *service.StatsService
+	importService *service.UserImportService
+	mergeService  *service.UserMergeService
+	logger        *zap.Logger
+}
+
+func NewAdminHandler(logLevel zap.AtomicLevel, statsService *service.StatsService, importService *service.UserImportService, mergeService *service.UserMergeService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		logLevel:      logLevel,
+		statsService:  statsService,
+		importService: importService,
+		mergeService:  mergeService,
+		logger:        logger,
+	}
+}
+
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// GetLogLevel godoc
+// @Summary Get log level
+// @Description Get the current log level of the running server. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=LogLevelResponse} "Log level retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Router /api/v1/admin/log-level [get]
+func (h *AdminHandler) GetLogLevel(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, http.StatusOK, LogLevelResponse{Level: h.logLevel.Level().String()})
+}
+
+// SetLogLevel godoc
+// @Summary Set log level
+// @Description Change the log level of the running server without a restart. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LogLevelRequest true "New log level"
+// @Success 200 {object} response.Response{data=LogLevelResponse} "Log level updated successfully"
+// @Failure 400 {object} response.Response "Invalid log level"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 422 {object} response.Response "Validation error"
+// @Router /api/v1/admin/log-level [put]
+func (h *AdminHandler) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		response.BadRequest(w, "Invalid log level")
+		return
+	}
+
+	previous := h.logLevel.Level()
+	h.logLevel.SetLevel(level)
+
+	h.logger.Warn("Log level changed",
+		zap.String("from", previous.String()),
+		zap.String("to", level.String()),
+		zap.Any("changed_by", r.Context().Value(middleware.UserIDKey)),
+	)
+
+	response.Success(w, http.StatusOK, LogLevelResponse{Level: level.String()})
+}
+
+type NewUsersResponse struct {
+	Last7Days  int64 `json:"last_7_days"`
+	Last30Days int64 `json:"last_30_days"`
+}
+
+type StatsResponse struct {
+	TotalUsers int64            `json:"total_users"`
+	NewUsers   NewUsersResponse `json:"new_users"`
+	// ActiveUsers counts users who logged in within the last 30 days.
+	ActiveUsers         int64            `json:"active_users"`
+	TTRsByStatus        map[string]int64 `json:"ttrs_by_status"`
+	InvitationsByStatus map[string]int64 `json:"invitations_by_status"`
+	AverageFillRate     float64          `json:"average_fill_rate"`
+	GeneratedAt         string           `json:"generated_at"`
+}
+
+// GetStats godoc
+// @Summary Get platform stats
+// @Description Get a snapshot of platform usage: users, new and active users, TTRs and invitations by status, and the average share of slots filled in completed TTRs. The snapshot is cached for a minute. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=StatsResponse} "Stats retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Router /api/v1/admin/stats [get]
+func (h *AdminHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.statsService.GetStats(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to get stats", zap.Error(err))
+		response.InternalServerError(w, "Failed to get stats")
+		return
+	}
+
+	ttrs := map[string]int64{}
+	for _, status := range []models.TTRStatus{models.TTRStatusOpen, models.TTRStatusConfirmed, models.TTRStatusCancelled, models.TTRStatusCompleted} {
+		ttrs[string(status)] = stats.TTRsByStatus[status]
+	}
+	invitations := map[string]int64{}
+	for _, status := range []models.InvitationStatus{models.InvitationStatusPending, models.InvitationStatusYes, models.InvitationStatusNo, models.InvitationStatusMaybe, models.InvitationStatusCanceled, models.InvitationStatusExpired, models.InvitationStatusForwardRequested} {
+		invitations[string(status)] = stats.InvitationsByStatus[status]
+	}
+
+	response.Success(w, http.StatusOK, StatsResponse{
+		TotalUsers:          stats.TotalUsers,
+		NewUsers:            NewUsersResponse{Last7Days: stats.NewUsers7Days, Last30Days: stats.NewUsers30Days},
+		ActiveUsers:         stats.ActiveUsers30Days,
+		TTRsByStatus:        ttrs,
+		InvitationsByStatus: invitations,
+		AverageFillRate:     stats.AverageFillRate,
+		GeneratedAt:         formatTime(stats.GeneratedAt),
+	})
+}
+
+// maxImportFileSize caps the body of a user import. The row limit is checked
+// as the file is read; this only stops uploads that are plainly too big.
+const maxImportFileSize = 20 << 20
+
+type UserImportRowResponse struct {
+	Line    int     `json:"line"`
+	Email   string  `json:"email,omitempty"`
+	Status  string  `json:"status"`
+	Reason  string  `json:"reason,omitempty"`
+	UserID  *string `json:"user_id,omitempty"`
+	Invited bool    `json:"invited,omitempty"`
+}
+
+type UserImportResponse struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+	// Truncated is set when the file had more rows than are imported at
+	// once; the rows past the limit weren't read.
+	Truncated bool                    `json:"truncated"`
+	Rows      []UserImportRowResponse `json:"rows"`
+}
+
+// ImportUsers godoc
+// @Summary Import users from CSV
+// @Description Create accounts for a club's members from a CSV upload with email, first_name and last_name columns and optional handicap and phone columns. Rows are imported one at a time: a row that is invalid or whose email already has an account is reported and skipped. Imported members must reset their password and, when email is configured, are sent an invitation with a temporary one. With format=csv the rows that weren't imported are returned as a CSV download instead. Admin only.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Param file formData file true "Member CSV"
+// @Param format query string false "csv for a CSV of the rows that weren't imported"
+// @Success 200 {object} response.Response{data=UserImportResponse} "Import report"
+// @Failure 400 {object} response.Response "Missing file or not a member CSV"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 413 {object} response.Response "File too large"
+// @Router /api/v1/admin/users/import [post]
+func (h *AdminHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	// Read the upload as a stream rather than buffering the form.
+	form, err := r.MultipartReader()
+	if err != nil {
+		response.BadRequest(w, "Import file is required")
+		return
+	}
+	var file io.Reader
+	for file == nil {
+		part, err := form.NextPart()
+		if err != nil {
+			response.BadRequest(w, "Import file is required")
+			return
+		}
+		if part.FormName() == "file" {
+			file = part
+		}
+	}
+
+	report, err := h.importService.Import(r.Context(), adminID, file)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Coded(w, errcode.PayloadTooLarge, "import file is too large")
+			return
+		}
+		response.FromError(w, err, "Failed to import users")
+		return
+	}
+
+	if r.URL.Query().Get("format") == "csv" {
+		writeImportErrors(w, report)
+		return
+	}
+
+	resp := UserImportResponse{
+		Created:   report.Created,
+		Skipped:   report.Skipped,
+		Failed:    report.Failed,
+		Truncated: report.Truncated,
+		Rows:      make([]UserImportRowResponse, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		rowResp := UserImportRowResponse{Line: row.Line, Email: row.Email, Status: row.Status, Reason: row.Reason, Invited: row.Invited}
+		if row.UserID != nil {
+			id := row.UserID.String()
+			rowResp.UserID = &id
+		}
+		resp.Rows = append(resp.Rows, rowResp)
+	}
+	response.Success(w, http.StatusOK, resp)
+}
+
+type MergeUsersRequest struct {
+	SourceUserID string `json:"source_user_id" validate:"required,uuid"`
+	TargetUserID string `json:"target_user_id" validate:"required,uuid"`
+}
+
+// UserMergeResponse counts what a merge moved to the target account. The
+// dropped counts are the source's rows left out because the target already
+// had their like.
+type UserMergeResponse struct {
+	SourceUserID         string `json:"source_user_id"`
+	TargetUserID         string `json:"target_user_id"`
+	CaptainedTTRs        int64  `json:"captained_ttrs"`
+	CoCaptaincies        int64  `json:"co_captaincies"`
+	DroppedCoCaptaincies int64  `json:"dropped_co_captaincies"`
+	RosterSpots          int64  `json:"roster_spots"`
+	DroppedRosterSpots   int64  `json:"dropped_roster_spots"`
+	Invitations          int64  `json:"invitations"`
+	DroppedInvitations   int64  `json:"dropped_invitations"`
+	Notifications        int64  `json:"notifications"`
+	Messages             int64  `json:"messages"`
+	RevokedRefreshTokens int64  `json:"revoked_refresh_tokens"`
+}
+
+// MergeUsers godoc
+// @Summary Merge duplicate accounts
+// @Description Merge the source account into the target, e.g. when someone signed up once with Google and once with their email. The source's TTR captaincies, co-captaincies, roster spots, invitations, notifications and chat messages move to the target in one transaction; its refresh tokens are revoked and it is deleted. Where both accounts hold the same spot the target's is kept: a roster spot or co-captaincy on a TTR the target is already on is dropped, as is a pending invitation to a TTR the target also has one for, and invitations between the two accounts. Merging the same accounts again changes nothing. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body MergeUsersRequest true "Accounts to merge"
+// @Success 200 {object} response.Response{data=UserMergeResponse} "Accounts merged"
+// @Failure 400 {object} response.Response "Bad request or merging an account into itself"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 404 {object} response.Response "Source or target user not found"
+// @Failure 409 {object} response.Response "Source already merged into another account"
+// @Failure 422 {object} response.Response "Validation error"
+// @Router /api/v1/admin/users/merge [post]
+func (h *AdminHandler) MergeUsers(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req MergeUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	sourceID, err := uuid.Parse(req.SourceUserID)
+	if err != nil {
+		response.BadRequest(w, "Invalid source user ID")
+		return
+	}
+	targetID, err := uuid.Parse(req.TargetUserID)
+	if err != nil {
+		response.BadRequest(w, "Invalid target user ID")
+		return
+	}
+
+	merge, err := h.mergeService.Merge(r.Context(), adminID, sourceID, targetID)
+	if err != nil {
+		response.FromError(w, err, "Failed to merge users")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromUserMerge(merge))
+}
+
+// writeImportErrors sends the rows of report that weren't imported as a CSV
+// download, for fixing and uploading again.
+func writeImportErrors(w http.ResponseWriter, report *service.UserImportReport) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="import-errors.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	out := csv.NewWriter(w)
+	out.Write([]string{"line", "email", "status", "reason"})
+	for _, row := range report.Rows {
+		if row.Status != service.ImportStatusCreated {
+			out.Write([]string{strconv.Itoa(row.Line), row.Email, row.Status, row.Reason})
+		}
+	}
+	out.Flush()
+}
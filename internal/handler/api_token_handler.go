@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type APITokenHandler struct {
+	apiTokenService *service.APITokenService
+}
+
+func NewAPITokenHandler(apiTokenService *service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{apiTokenService: apiTokenService}
+}
+
+type CreateAPITokenRequest struct {
+	Name      string   `json:"name" validate:"required,max=100"`
+	Scopes    []string `json:"scopes" validate:"required,min=1"`
+	ExpiresAt string   `json:"expires_at" validate:"omitempty"`
+}
+
+type APITokenResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	Token      string   `json:"token,omitempty"`
+}
+
+// CreateAPIToken godoc
+// @Summary Create API token
+// @Description Create a long-lived personal API token for scripts. Send it as "Authorization: Bearer <token>" wherever an access token is accepted, except for changing the password and managing API tokens. The token can only use routes covered by its scopes, such as read:ttrs or write:invitations; GET /meta/scopes lists them all. token is returned only here; lists show its prefix. An optional expires_at (RFC3339, in the future) stops it working once it passes. API tokens cannot create API tokens.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPITokenRequest true "Token details"
+// @Success 201 {object} response.Response{data=APITokenResponse} "API token created successfully"
+// @Failure 400 {object} response.Response "Invalid expires_at or scopes"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - authenticated with an API token"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/api-tokens [post]
+func (h *APITokenHandler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			response.BadRequest(w, "Invalid expires_at format, expected RFC3339")
+			return
+		}
+		expiresAt = &parsed
+	}
+
+	apiToken, token, err := h.apiTokenService.CreateAPIToken(r.Context(), userID, req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		response.FromError(w, err, "Failed to create API token")
+		return
+	}
+
+	resp := FromAPIToken(apiToken)
+	resp.Token = token
+	response.Success(w, http.StatusCreated, resp)
+}
+
+// ListAPITokens godoc
+// @Summary List API tokens
+// @Description List the caller's API tokens, newest first. Only each token's prefix is shown. last_used_at is updated at most every few minutes.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]APITokenResponse} "API tokens retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - authenticated with an API token"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/api-tokens [get]
+func (h *APITokenHandler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	tokens, err := h.apiTokenService.ListAPITokens(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to list API tokens")
+		return
+	}
+
+	tokenResponses := make([]APITokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		tokenResponses = append(tokenResponses, FromAPIToken(token))
+	}
+
+	response.Success(w, http.StatusOK, tokenResponses)
+}
+
+// RevokeAPIToken godoc
+// @Summary Revoke API token
+// @Description Revoke one of the caller's API tokens. Requests using it are refused from then on.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API token ID (UUID)"
+// @Success 200 {object} response.Response "API token revoked successfully"
+// @Failure 400 {object} response.Response "Invalid API token ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - authenticated with an API token"
+// @Failure 404 {object} response.Response "API token not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/api-tokens/{id} [delete]
+func (h *APITokenHandler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	tokenID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid API token ID")
+		return
+	}
+
+	if err := h.apiTokenService.RevokeAPIToken(r.Context(), userID, tokenID); err != nil {
+		response.FromError(w, err, "Failed to revoke API token")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "API token revoked successfully"})
+}
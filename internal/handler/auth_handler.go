@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 	"github.com/yourusername/golf_messenger/pkg/response"
 	"github.com/yourusername/golf_messenger/pkg/validator"
 )
@@ -41,15 +44,41 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID        string   `json:"id"`
-	Email     string   `json:"email"`
-	FirstName string   `json:"first_name"`
-	LastName  string   `json:"last_name"`
-	Handicap  *float64 `json:"handicap,omitempty"`
-	Phone     *string  `json:"phone,omitempty"`
-	AvatarURL *string  `json:"avatar_url,omitempty"`
-	CreatedAt string   `json:"created_at"`
-	UpdatedAt string   `json:"updated_at"`
+	ID                      string                           `json:"id"`
+	Email                   string                           `json:"email,omitempty"`
+	FirstName               string                           `json:"first_name"`
+	LastName                string                           `json:"last_name"`
+	Handicap                *float64                         `json:"handicap,omitempty"`
+	Phone                   *string                          `json:"phone,omitempty"`
+	AvatarURL               *string                          `json:"avatar_url,omitempty"`
+	PreferredLanguage       *string                          `json:"preferred_language,omitempty"`
+	HomeCourse              *string                          `json:"home_course,omitempty"`
+	Bio                     *string                          `json:"bio,omitempty"`
+	PlayingDays             []string                         `json:"playing_days,omitempty"`
+	PreferredTeeTimeRange   *TeeTimeRange                    `json:"preferred_tee_time_range,omitempty"`
+	CreatedAt               string                           `json:"created_at,omitempty"`
+	UpdatedAt               string                           `json:"updated_at,omitempty"`
+	Deleted                 bool                             `json:"deleted,omitempty"`
+	MustResetPassword       bool                             `json:"must_reset_password,omitempty"`
+	EmailUndeliverable      bool                             `json:"email_undeliverable,omitempty"`
+	Privacy                 *PrivacySettingsResponse         `json:"privacy,omitempty"`
+	NotificationPreferences *NotificationPreferencesResponse `json:"notification_preferences,omitempty"`
+}
+
+// PublicUserResponse is the v2 view of another user: their name and golf
+// profile, without contact details or account timestamps. The profile fields
+// are only filled in when a single user is fetched.
+type PublicUserResponse struct {
+	ID                    string        `json:"id"`
+	FirstName             string        `json:"first_name"`
+	LastName              string        `json:"last_name"`
+	Handicap              *float64      `json:"handicap,omitempty"`
+	AvatarURL             *string       `json:"avatar_url,omitempty"`
+	HomeCourse            *string       `json:"home_course,omitempty"`
+	Bio                   *string       `json:"bio,omitempty"`
+	PlayingDays           []string      `json:"playing_days,omitempty"`
+	PreferredTeeTimeRange *TeeTimeRange `json:"preferred_tee_time_range,omitempty"`
+	Deleted               bool          `json:"deleted,omitempty"`
 }
 
 type TokenResponse struct {
@@ -60,14 +89,15 @@ type TokenResponse struct {
 
 // Register godoc
 // @Summary Register a new user
-// @Description Create a new user account with email and password
+// @Description Create a new user account with email and password. A password breaking the password policy gets a 422 WEAK_PASSWORD whose details list the rules it breaks.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body RegisterRequest true "Registration details"
 // @Success 201 {object} response.Response{data=AuthResponse} "User registered successfully"
 // @Failure 400 {object} response.Response "Bad request"
-// @Failure 422 {object} response.Response "Validation error"
+// @Failure 409 {object} response.Response "Email already registered"
+// @Failure 422 {object} response.Response "Validation error or weak password"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
@@ -78,33 +108,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	user, tokenPair, err := h.authService.Register(req.Email, req.Password, req.FirstName, req.LastName)
+	user, tokenPair, err := h.authService.Register(r.Context(), req.Email, req.Password, req.FirstName, req.LastName)
 	if err != nil {
-		if err.Error() == "user with this email already exists" {
-			response.Conflict(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to register user")
+		handlePasswordPolicyError(w, err, "Failed to register user")
 		return
 	}
 
 	authResp := AuthResponse{
-		User: UserResponse{
-			ID:        user.ID.String(),
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Handicap:  user.Handicap,
-			Phone:     user.Phone,
-			AvatarURL: user.AvatarURL,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		User:         FromProfile(service.NewUserProfile(user)),
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
 		ExpiresAt:    tokenPair.ExpiresAt,
@@ -134,33 +150,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	user, tokenPair, err := h.authService.Login(req.Email, req.Password)
+	user, tokenPair, err := h.authService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
-		if err.Error() == "invalid email or password" {
-			response.Unauthorized(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to login")
+		response.FromError(w, err, "Failed to login")
 		return
 	}
 
 	authResp := AuthResponse{
-		User: UserResponse{
-			ID:        user.ID.String(),
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Handicap:  user.Handicap,
-			Phone:     user.Phone,
-			AvatarURL: user.AvatarURL,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		},
+		User:         FromProfile(service.NewUserProfile(user)),
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
 		ExpiresAt:    tokenPair.ExpiresAt,
@@ -190,18 +192,14 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	tokenPair, err := h.authService.RefreshToken(req.RefreshToken)
+	tokenPair, err := h.authService.RefreshToken(r.Context(), req.RefreshToken)
 	if err != nil {
-		if err.Error() == "invalid refresh token" || err.Error() == "refresh token is invalid or expired" {
-			response.Unauthorized(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to refresh token")
+		response.FromError(w, err, "Failed to refresh token")
 		return
 	}
 
@@ -235,19 +233,27 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	if err := h.authService.Logout(req.RefreshToken); err != nil {
-		if err.Error() == "invalid refresh token" {
-			response.Unauthorized(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to logout")
+	if err := h.authService.Logout(r.Context(), req.RefreshToken); err != nil {
+		response.FromError(w, err, "Failed to logout")
 		return
 	}
 
 	response.Success(w, http.StatusOK, map[string]string{"message": "Logged out successfully"})
 }
+
+// handlePasswordPolicyError writes a password breaking the password policy
+// with the rules it breaks, and any other error as FromError does.
+func handlePasswordPolicyError(w http.ResponseWriter, err error, fallback string) {
+	var weak *service.PasswordPolicyError
+	if !errors.As(err, &weak) {
+		response.FromError(w, err, fallback)
+		return
+	}
+	details := map[string]interface{}{"rules": weak.Rules, "min_length": weak.MinLength}
+	response.CodedWithDetails(w, errcode.WeakPassword, service.ErrWeakPassword.Message, details)
+}
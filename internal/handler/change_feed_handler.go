@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type ChangeFeedHandler struct {
+	changeFeedService *service.ChangeFeedService
+}
+
+func NewChangeFeedHandler(changeFeedService *service.ChangeFeedService) *ChangeFeedHandler {
+	return &ChangeFeedHandler{changeFeedService: changeFeedService}
+}
+
+type TTREventResponse struct {
+	Sequence    int64             `json:"sequence"`
+	Type        string            `json:"type"`
+	ActorUserID *string           `json:"actor_user_id"`
+	Data        map[string]string `json:"data"`
+	CreatedAt   string            `json:"created_at"`
+}
+
+type ChangeFeedResponse struct {
+	Events []TTREventResponse `json:"events"`
+	// Cursor is the since value for the next call: the sequence number of
+	// the last event returned, or the given since when there were none.
+	Cursor  int64 `json:"cursor"`
+	HasMore bool  `json:"has_more"`
+}
+
+// ListChanges godoc
+// @Summary List a TTR's changes
+// @Description Get the TTR's change events after a cursor, oldest first: player_joined, player_left, player_updated, status_changed, details_updated and message_posted. Pass the cursor from the previous response as since to get only what changed after it; has_more says whether another page is waiting. Events are kept for a limited time. Only participants can read the feed.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param since query int false "Sequence number of the last event seen" default(0)
+// @Param limit query int false "Results limit, at most 200" default(200)
+// @Success 200 {object} response.Response{data=ChangeFeedResponse} "Changes retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID or cursor"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/changes [get]
+func (h *ChangeFeedHandler) ListChanges(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var since int64
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+		if since, err = strconv.ParseInt(sinceStr, 10, 64); err != nil {
+			response.FromError(w, service.ErrInvalidChangeCursor, "Failed to list changes")
+			return
+		}
+	}
+
+	limit := service.MaxChangeFeedLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= service.MaxChangeFeedLimit {
+			limit = l
+		}
+	}
+
+	events, hasMore, err := h.changeFeedService.ListChanges(r.Context(), ttrID, userID, since, limit)
+	if err != nil {
+		response.FromError(w, err, "Failed to list changes")
+		return
+	}
+
+	resp := ChangeFeedResponse{
+		Events:  make([]TTREventResponse, 0, len(events)),
+		Cursor:  since,
+		HasMore: hasMore,
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, FromTTREvent(event))
+		resp.Cursor = event.Sequence
+	}
+
+	response.Success(w, http.StatusOK, resp)
+}
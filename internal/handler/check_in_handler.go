@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type CheckInHandler struct {
+	checkInService *service.CheckInService
+}
+
+func NewCheckInHandler(checkInService *service.CheckInService) *CheckInHandler {
+	return &CheckInHandler{checkInService: checkInService}
+}
+
+type CheckInsResponse struct {
+	OpensAt   string              `json:"opens_at"`
+	ClosesAt  string              `json:"closes_at"`
+	CheckedIn int                 `json:"checked_in"`
+	Players   []TTRPlayerResponse `json:"players"`
+}
+
+// CheckIn godoc
+// @Summary Check in to a TTR
+// @Description Check the caller in on the day of the round. Check-in opens TTRS_CHECK_IN_OPENS_BEFORE (default 2h) before the tee time and closes TTRS_CHECK_IN_CLOSES_AFTER (default 1h) after it; outside that window the request fails with 409 CHECK_IN_CLOSED and details.opens_at and details.closes_at. Checking in again keeps the first check-in time. Only players can check in.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=TTRPlayerResponse} "Checked in"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Not a player"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 409 {object} response.Response "Check-in is closed"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/check-in [post]
+func (h *CheckInHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	player, err := h.checkInService.CheckIn(r.Context(), ttrID, userID)
+	if err != nil {
+		handleTTRError(w, err, "Failed to check in")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTTRPlayer(viewerFrom(r), *player))
+}
+
+// ListCheckIns godoc
+// @Summary List a TTR's check-ins
+// @Description Get the TTR's roster with each player's checked_in_at, the number of players checked in and when check-in opens and closes. Only participants can see it.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=CheckInsResponse} "Check-ins retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/check-ins [get]
+func (h *CheckInHandler) ListCheckIns(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	ttr, players, err := h.checkInService.ListCheckIns(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to list check-ins")
+		return
+	}
+
+	v := viewerFrom(r).captaining(ttr.CaptainIDs(), players)
+	opensAt, closesAt := h.checkInService.Window(ttr)
+	resp := CheckInsResponse{
+		OpensAt:  formatTime(opensAt),
+		ClosesAt: formatTime(closesAt),
+		Players:  make([]TTRPlayerResponse, 0, len(players)),
+	}
+	for _, player := range players {
+		if player.CheckedInAt != nil {
+			resp.CheckedIn++
+		}
+		resp.Players = append(resp.Players, FromTTRPlayer(v, player))
+	}
+
+	response.Success(w, http.StatusOK, resp)
+}
@@ -0,0 +1,806 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+)
+
+// Every timestamp in a response is formatted by formatTime, so clients always
+// get UTC RFC3339 whatever zone the database or server runs in. Tee dates and
+// tee times are calendar values and keep their own formats.
+
+// deletedUserName is shown in place of a soft-deleted user's name.
+const deletedUserName = "Deleted user"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := formatTime(*t)
+	return &s
+}
+
+// Viewer is the user a response is for. The conversions that embed other
+// users show each of them only what their privacy settings let the viewer
+// see; this is the one place those settings are enforced.
+type Viewer struct {
+	UserID uuid.UUID
+	// Captained holds users who play on a TTR the viewer captains or
+	// co-captains. The viewer always sees their handicaps.
+	Captained map[uuid.UUID]bool
+}
+
+// viewerFrom is the caller of r; on public routes it is nobody.
+func viewerFrom(r *http.Request) Viewer {
+	userID, _ := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	return Viewer{UserID: userID}
+}
+
+// captaining adds players to the users v captains when v is one of
+// captainIDs.
+func (v Viewer) captaining(captainIDs []uuid.UUID, players []service.TTRPlayerDetail) Viewer {
+	if v.UserID == uuid.Nil {
+		return v
+	}
+	for _, captainID := range captainIDs {
+		if captainID != v.UserID {
+			continue
+		}
+		captained := make(map[uuid.UUID]bool, len(v.Captained)+len(players))
+		for userID := range v.Captained {
+			captained[userID] = true
+		}
+		for _, player := range players {
+			captained[player.UserID] = true
+		}
+		return Viewer{UserID: v.UserID, Captained: captained}
+	}
+	return v
+}
+
+// sees reports which of user's private fields v may see. Users always see
+// their own.
+func (v Viewer) sees(user service.UserSummary) (handicap, phone, email bool) {
+	if user.ID == v.UserID {
+		return true, true, true
+	}
+	return user.Privacy.ShowsHandicap() || v.Captained[user.ID], user.Privacy.ShowPhone, user.Privacy.ShowEmail
+}
+
+// FromUser is user as v sees them. A deleted user keeps only its ID and a
+// placeholder name.
+func FromUser(v Viewer, user service.UserSummary) UserResponse {
+	if user.Deleted {
+		return UserResponse{
+			ID:        user.ID.String(),
+			FirstName: deletedUserName,
+			Deleted:   true,
+		}
+	}
+
+	resp := UserResponse{
+		ID:        user.ID.String(),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		AvatarURL: user.AvatarURL,
+		CreatedAt: formatTime(user.CreatedAt),
+		UpdatedAt: formatTime(user.UpdatedAt),
+	}
+	handicap, phone, email := v.sees(user)
+	if handicap {
+		resp.Handicap = user.Handicap
+	}
+	if phone {
+		resp.Phone = user.Phone
+	}
+	if email {
+		resp.Email = user.Email
+	}
+	return resp
+}
+
+// FromProfile is the full profile shown to the user it belongs to.
+func FromProfile(profile *service.UserProfile) UserResponse {
+	resp := UserResponse{
+		ID:                 profile.ID.String(),
+		Email:              profile.Email,
+		FirstName:          profile.FirstName,
+		LastName:           profile.LastName,
+		Handicap:           profile.Handicap,
+		Phone:              profile.Phone,
+		AvatarURL:          profile.AvatarURL,
+		PreferredLanguage:  profile.PreferredLanguage,
+		CreatedAt:          formatTime(profile.CreatedAt),
+		UpdatedAt:          formatTime(profile.UpdatedAt),
+		MustResetPassword:  profile.MustResetPassword,
+		EmailUndeliverable: profile.EmailUndeliverable,
+	}
+	privacy := FromPrivacySettings(profile.Privacy)
+	resp.Privacy = &privacy
+	notifications := FromNotificationSettings(profile.Notifications)
+	resp.NotificationPreferences = &notifications
+	addProfileDetails(&resp, profile)
+	return resp
+}
+
+func FromPrivacySettings(privacy models.PrivacySettings) PrivacySettingsResponse {
+	return PrivacySettingsResponse{
+		ShowHandicap:         privacy.ShowsHandicap(),
+		ShowPhone:            privacy.ShowPhone,
+		ShowEmail:            privacy.ShowEmail,
+		DiscoverableInSearch: privacy.Discoverable(),
+	}
+}
+
+func FromNotificationSettings(notifications models.NotificationSettings) NotificationPreferencesResponse {
+	return NotificationPreferencesResponse{WeeklyDigest: notifications.WeeklyDigest()}
+}
+
+// addProfileDetails adds the golf profile fields, which are public.
+func addProfileDetails(resp *UserResponse, profile *service.UserProfile) {
+	resp.HomeCourse = profile.HomeCourse
+	resp.Bio = profile.Bio
+	resp.PlayingDays = profile.PlayingDays
+	resp.PreferredTeeTimeRange = fromTeeTimeRange(profile.PreferredTeeTimes)
+}
+
+func FromPublicUser(v Viewer, user service.UserSummary) PublicUserResponse {
+	if user.Deleted {
+		return PublicUserResponse{
+			ID:        user.ID.String(),
+			FirstName: deletedUserName,
+			Deleted:   true,
+		}
+	}
+
+	resp := PublicUserResponse{
+		ID:        user.ID.String(),
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		AvatarURL: user.AvatarURL,
+	}
+	if handicap, _, _ := v.sees(user); handicap {
+		resp.Handicap = user.Handicap
+	}
+	return resp
+}
+
+// FromPublicProfile is another user's profile as v2 shows it.
+func FromPublicProfile(v Viewer, profile *service.UserProfile) PublicUserResponse {
+	resp := FromPublicUser(v, profile.UserSummary)
+	resp.HomeCourse = profile.HomeCourse
+	resp.Bio = profile.Bio
+	resp.PlayingDays = profile.PlayingDays
+	resp.PreferredTeeTimeRange = fromTeeTimeRange(profile.PreferredTeeTimes)
+	return resp
+}
+
+func fromTeeTimeRange(teeTimes *service.TeeTimeRange) *TeeTimeRange {
+	if teeTimes == nil {
+		return nil
+	}
+	return &TeeTimeRange{
+		Start: teeTimes.Start.Format("15:04"),
+		End:   teeTimes.End.Format("15:04"),
+	}
+}
+
+// FromTTR is ttr as v sees it. Its captains see the handicaps of all its
+// players.
+func FromTTR(v Viewer, ttr *service.TTRDetail) TTRResponse {
+	captainIDs := []uuid.UUID{ttr.CaptainUserID}
+	for _, cc := range ttr.CoCaptains {
+		captainIDs = append(captainIDs, cc.UserID)
+	}
+	v = v.captaining(captainIDs, ttr.Players)
+
+	resp := TTRResponse{
+		ID:              ttr.ID.String(),
+		CourseName:      ttr.CourseName,
+		CourseLocation:  ttr.CourseLocation,
+		TeeDate:         ttr.TeeDate.Format("2006-01-02"),
+		TeeTime:         ttr.TeeTime.Format("15:04"),
+		MaxPlayers:      ttr.MaxPlayers,
+		MinPlayers:      ttr.MinPlayers,
+		CreatedByUserID: ttr.CreatedByUserID.String(),
+		CaptainUserID:   ttr.CaptainUserID.String(),
+		Status:          ttr.Status,
+		StatusLocked:    ttr.StatusLocked,
+		Visibility:      ttr.Visibility,
+		Notes:           ttr.Notes,
+		CreatedAt:       formatTime(ttr.CreatedAt),
+		UpdatedAt:       formatTime(ttr.UpdatedAt),
+		OpenSlots:       ttr.OpenSlots,
+		ConfirmedCount:  ttr.ConfirmedCount,
+		WalkingAllowed:  ttr.WalkingAllowed,
+		CartsAvailable:  ttr.CartsAvailable,
+		Holes:           ttr.Holes,
+		DressCode:       ttr.DressCode,
+		GreenFeeCents:   ttr.GreenFeeCents,
+	}
+
+	resp.RSVPDeadline = formatTimePtr(ttr.RSVPDeadline)
+	resp.OrganizationID = uuidToString(ttr.OrganizationID)
+	resp.LeagueID = uuidToString(ttr.LeagueID)
+	resp.DeletedAt = formatTimePtr(ttr.DeletedAt)
+
+	if ttr.CreatedByUser != nil {
+		userResp := FromUser(v, *ttr.CreatedByUser)
+		resp.CreatedByUser = &userResp
+	}
+
+	if ttr.CaptainUser != nil {
+		userResp := FromUser(v, *ttr.CaptainUser)
+		resp.CaptainUser = &userResp
+	}
+
+	if len(ttr.CoCaptains) > 0 {
+		resp.CoCaptains = make([]TTRCoCaptainResponse, 0, len(ttr.CoCaptains))
+		for _, cc := range ttr.CoCaptains {
+			resp.CoCaptains = append(resp.CoCaptains, FromTTRCoCaptain(v, cc))
+		}
+	}
+
+	// Players in a tee slot are listed under it rather than the roster.
+	slots := make(map[int]int, len(ttr.Slots))
+	for i, slot := range ttr.Slots {
+		slots[slot.Number] = i
+		resp.Slots = append(resp.Slots, TeeSlotResponse{
+			ID:            slot.ID.String(),
+			Number:        slot.Number,
+			TeeTime:       slot.TeeTime.Format("15:04"),
+			OffsetMinutes: slot.OffsetMinutes,
+			Capacity:      slot.Capacity,
+			Players:       []TTRPlayerResponse{},
+		})
+	}
+
+	if len(ttr.Players)+len(ttr.Guests) > 0 {
+		resp.Players = make([]TTRPlayerResponse, 0, len(ttr.Players)+len(ttr.Guests))
+		for _, p := range ttr.Players {
+			if i, ok := slots[p.GroupNumber]; ok {
+				resp.Slots[i].Players = append(resp.Slots[i].Players, FromTTRPlayer(v, p))
+				continue
+			}
+			resp.Players = append(resp.Players, FromTTRPlayer(v, p))
+		}
+		for _, g := range ttr.Guests {
+			resp.Players = append(resp.Players, FromTTRGuest(g))
+		}
+	}
+
+	return resp
+}
+
+func FromTTRCoCaptain(v Viewer, cc service.TTRCoCaptainDetail) TTRCoCaptainResponse {
+	userResp := FromUser(v, cc.User)
+	return TTRCoCaptainResponse{
+		TTRID:       cc.TTRID.String(),
+		UserID:      cc.UserID.String(),
+		AssignedAt:  formatTime(cc.AssignedAt),
+		Permissions: cc.Permissions,
+		User:        &userResp,
+	}
+}
+
+// FromPublicTTR is the limited view of a TTR shown to users who can find it
+// but are not on it.
+func FromPublicTTR(ttr *service.TTRDetail) TTRPublicResponse {
+	openSlots := ttr.MaxPlayers - len(ttr.Guests)
+	for _, player := range ttr.Players {
+		if player.Status.IsActive() {
+			openSlots--
+		}
+	}
+	if openSlots < 0 {
+		openSlots = 0
+	}
+
+	return TTRPublicResponse{
+		ID:             ttr.ID.String(),
+		CourseName:     ttr.CourseName,
+		CourseLocation: ttr.CourseLocation,
+		TeeDate:        ttr.TeeDate.Format("2006-01-02"),
+		TeeTime:        ttr.TeeTime.Format("15:04"),
+		MaxPlayers:     ttr.MaxPlayers,
+		OpenSlots:      openSlots,
+		Status:         ttr.Status,
+	}
+}
+
+func FromTTRPlayer(v Viewer, player service.TTRPlayerDetail) TTRPlayerResponse {
+	userResp := FromUser(v, player.User)
+	return TTRPlayerResponse{
+		TTRID:       player.TTRID.String(),
+		UserID:      player.UserID.String(),
+		JoinedAt:    formatTime(player.JoinedAt),
+		Status:      player.Status,
+		Notes:       player.Notes,
+		GroupNumber: player.GroupNumber,
+		Score:       player.Score,
+		CheckedInAt: formatTimePtr(player.CheckedInAt),
+		User:        &userResp,
+	}
+}
+
+// FromTTRGuest is a guest's place on the roster. Guests are always
+// confirmed and have no user to show.
+func FromTTRGuest(guest service.TTRGuestDetail) TTRPlayerResponse {
+	return TTRPlayerResponse{
+		TTRID:       guest.TTRID.String(),
+		IsGuest:     true,
+		GuestID:     guest.ID.String(),
+		DisplayName: guest.DisplayName,
+		Handicap:    guest.Handicap,
+		JoinedAt:    formatTime(guest.CreatedAt),
+		Status:      models.TTRPlayerStatusConfirmed,
+	}
+}
+
+func FromInvitation(v Viewer, invitation *service.InvitationDetail) InvitationResponse {
+	inviterResp := FromUser(v, invitation.InviterUser)
+	inviteeResp := FromUser(v, invitation.InviteeUser)
+	resp := InvitationResponse{
+		ID:            invitation.ID.String(),
+		TTRID:         invitation.TTRID.String(),
+		InviterUserID: invitation.InviterUserID.String(),
+		InviteeUserID: invitation.InviteeUserID.String(),
+		Status:        invitation.Status,
+		Message:       invitation.Message,
+		DeclineReason: invitation.DeclineReason,
+		CreatedAt:     formatTime(invitation.CreatedAt),
+		Acceptable:    invitation.Acceptable,
+		InviterUser:   &inviterResp,
+		InviteeUser:   &inviteeResp,
+	}
+
+	resp.RespondedAt = formatTimePtr(invitation.RespondedAt)
+	resp.ForwardedByUserID = uuidToString(invitation.ForwardedByUserID)
+
+	if invitation.TTR != nil {
+		ttrResp := FromTTR(v, invitation.TTR)
+		resp.TTR = &ttrResp
+	}
+
+	return resp
+}
+
+func FromOrganization(org *service.OrganizationDetail) OrganizationResponse {
+	return OrganizationResponse{
+		ID:              org.ID.String(),
+		Name:            org.Name,
+		Description:     org.Description,
+		CreatedByUserID: org.CreatedByUserID.String(),
+		CreatedAt:       formatTime(org.CreatedAt),
+		UpdatedAt:       formatTime(org.UpdatedAt),
+	}
+}
+
+func FromOrganizationMember(v Viewer, member service.OrganizationMemberDetail) OrganizationMemberResponse {
+	userResp := FromUser(v, member.User)
+	return OrganizationMemberResponse{
+		OrganizationID: member.OrganizationID.String(),
+		UserID:         member.UserID.String(),
+		Role:           member.Role,
+		JoinedAt:       formatTime(member.JoinedAt),
+		User:           &userResp,
+	}
+}
+
+func FromOrganizationInvitation(invitation *service.OrganizationInvitationDetail) OrganizationInvitationResponse {
+	resp := OrganizationInvitationResponse{
+		ID:              invitation.ID.String(),
+		OrganizationID:  invitation.OrganizationID.String(),
+		Email:           invitation.Email,
+		Role:            invitation.Role,
+		InvitedByUserID: invitation.InvitedByUserID.String(),
+		Status:          invitation.Status,
+		CreatedAt:       formatTime(invitation.CreatedAt),
+	}
+
+	resp.RespondedAt = formatTimePtr(invitation.RespondedAt)
+
+	if invitation.Organization != nil {
+		orgResp := FromOrganization(invitation.Organization)
+		resp.Organization = &orgResp
+	}
+
+	return resp
+}
+
+func FromMessage(v Viewer, message *models.Message) MessageResponse {
+	resp := MessageResponse{
+		ID:        message.ID.String(),
+		TTRID:     message.TTRID.String(),
+		UserID:    message.UserID.String(),
+		Body:      message.Body,
+		CreatedAt: formatTime(message.CreatedAt),
+		UpdatedAt: formatTime(message.UpdatedAt),
+		Deleted:   message.Deleted,
+		Reactions: make([]MessageReactionResponse, 0, len(message.Reactions)),
+	}
+
+	for _, reaction := range message.Reactions {
+		resp.Reactions = append(resp.Reactions, MessageReactionResponse{
+			Emoji:       reaction.Emoji,
+			Count:       reaction.Count,
+			ReactedByMe: reaction.ReactedByMe,
+		})
+	}
+
+	resp.EditedAt = formatTimePtr(message.EditedAt)
+
+	if message.User != nil {
+		userResp := FromUser(v, service.NewUserSummary(message.UserID, message.User))
+		resp.User = &userResp
+	}
+
+	if message.AttachmentKey != nil && !message.Deleted {
+		resp.Attachment = &MessageAttachmentResponse{
+			Size: message.AttachmentSize,
+			URL:  message.AttachmentURL,
+		}
+		if message.AttachmentName != nil {
+			resp.Attachment.Name = *message.AttachmentName
+		}
+		if message.AttachmentContentType != nil {
+			resp.Attachment.ContentType = *message.AttachmentContentType
+		}
+	}
+
+	return resp
+}
+
+func FromLeague(league *models.League) LeagueResponse {
+	resp := LeagueResponse{
+		ID:            league.ID.String(),
+		Name:          league.Name,
+		OwnerUserID:   league.OwnerUserID.String(),
+		StartDate:     league.StartDate.Format("2006-01-02"),
+		EndDate:       league.EndDate.Format("2006-01-02"),
+		ScoringScheme: league.ScoringScheme,
+		CreatedAt:     formatTime(league.CreatedAt),
+		UpdatedAt:     formatTime(league.UpdatedAt),
+	}
+	if league.OrganizationID != nil {
+		organizationID := league.OrganizationID.String()
+		resp.OrganizationID = &organizationID
+	}
+	return resp
+}
+
+func FromTournament(tournament *models.Tournament) TournamentResponse {
+	resp := TournamentResponse{
+		ID:           tournament.ID.String(),
+		Name:         tournament.Name,
+		OwnerUserID:  tournament.OwnerUserID.String(),
+		TTRID:        uuidToString(tournament.TTRID),
+		Status:       tournament.Status,
+		WinnerUserID: uuidToString(tournament.WinnerUserID),
+		CreatedAt:    formatTime(tournament.CreatedAt),
+		UpdatedAt:    formatTime(tournament.UpdatedAt),
+		Matches:      make([]MatchResponse, 0, len(tournament.Matches)),
+	}
+
+	for _, match := range tournament.Matches {
+		matchResp := MatchResponse{
+			ID:            match.ID.String(),
+			Round:         match.Round,
+			Position:      match.Position,
+			Player1UserID: uuidToString(match.Player1UserID),
+			Player2UserID: uuidToString(match.Player2UserID),
+			WinnerUserID:  uuidToString(match.WinnerUserID),
+			Status:        match.Status,
+			Walkover:      match.Walkover,
+			TTRID:         uuidToString(match.TTRID),
+			CompletedAt:   formatTimePtr(match.CompletedAt),
+		}
+		resp.Matches = append(resp.Matches, matchResp)
+	}
+
+	return resp
+}
+
+func uuidToString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+func FromActionItem(item service.ActionItem) ActionItemResponse {
+	return ActionItemResponse{
+		Kind:         item.Kind,
+		TTRID:        item.TTRID.String(),
+		InvitationID: uuidToString(item.InvitationID),
+		CourseName:   item.CourseName,
+		DueAt:        formatTime(item.DueAt),
+	}
+}
+
+func FromInviteLink(link *service.InviteLinkDetail) InviteLinkResponse {
+	return InviteLinkResponse{
+		ID:              link.ID.String(),
+		TTRID:           link.TTRID.String(),
+		Token:           link.Token,
+		CreatedByUserID: link.CreatedByUserID.String(),
+		MaxUses:         link.MaxUses,
+		Uses:            link.Uses,
+		ExpiresAt:       formatTimePtr(link.ExpiresAt),
+		RevokedAt:       formatTimePtr(link.RevokedAt),
+		CreatedAt:       formatTime(link.CreatedAt),
+	}
+}
+
+// FromInviteLinkPreview names the captain but leaves out their contact
+// details: the preview is public to anyone holding the link.
+func FromInviteLinkPreview(preview *service.InviteLinkPreview) InviteLinkPreviewResponse {
+	return InviteLinkPreviewResponse{
+		CourseName:     preview.CourseName,
+		CourseLocation: preview.CourseLocation,
+		TeeDate:        preview.TeeDate.Format("2006-01-02"),
+		TeeTime:        preview.TeeTime.Format("15:04"),
+		CaptainName:    displayName(preview.CaptainUser),
+		OpenSlots:      preview.OpenSlots,
+		RemainingUses:  preview.RemainingUses,
+		ExpiresAt:      formatTimePtr(preview.ExpiresAt),
+		Usable:         preview.Usable,
+	}
+}
+
+// displayName is the user's full name, for places that show a name without
+// the rest of the user.
+func displayName(user service.UserSummary) string {
+	if user.Deleted {
+		return deletedUserName
+	}
+	if user.LastName == "" {
+		return user.FirstName
+	}
+	return user.FirstName + " " + user.LastName
+}
+
+func FromTeeSheet(sheet *service.TeeSheet) TeeSheetResponse {
+	resp := TeeSheetResponse{
+		CourseName: sheet.CourseName,
+		Days:       make([]TeeSheetDayResponse, 0, len(sheet.Days)),
+	}
+	for _, day := range sheet.Days {
+		dayResp := TeeSheetDayResponse{
+			Date: day.Date.Format("2006-01-02"),
+			TTRs: make([]TeeSheetEntryResponse, 0, len(day.Entries)),
+		}
+		for _, entry := range day.Entries {
+			dayResp.TTRs = append(dayResp.TTRs, TeeSheetEntryResponse{
+				TTRID:          entry.TTRID.String(),
+				TeeTime:        entry.TeeTime.Format("15:04"),
+				Status:         string(entry.Status),
+				CaptainName:    displayName(entry.CaptainUser),
+				ConfirmedCount: entry.ConfirmedCount,
+				OpenSlots:      entry.OpenSlots,
+			})
+		}
+		resp.Days = append(resp.Days, dayResp)
+	}
+	return resp
+}
+
+func FromAPIToken(token *models.APIToken) APITokenResponse {
+	scopes := make([]string, 0)
+	for _, s := range token.ScopeList() {
+		scopes = append(scopes, string(s))
+	}
+	return APITokenResponse{
+		ID:         token.ID.String(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  formatTimePtr(token.ExpiresAt),
+		LastUsedAt: formatTimePtr(token.LastUsedAt),
+		CreatedAt:  formatTime(token.CreatedAt),
+	}
+}
+
+func FromAuditLogEntry(entry *models.AuditLogEntry) AuditLogEntryResponse {
+	resp := AuditLogEntryResponse{
+		ID:          entry.ID.String(),
+		Action:      entry.Action,
+		ActorUserID: entry.ActorUserID.String(),
+		Details:     entry.Details,
+		RequestID:   entry.RequestID,
+		CreatedAt:   formatTime(entry.CreatedAt),
+	}
+	if entry.SubjectUserID != nil {
+		subjectUserID := entry.SubjectUserID.String()
+		resp.SubjectUserID = &subjectUserID
+	}
+	if entry.SessionID != nil {
+		sessionID := entry.SessionID.String()
+		resp.SessionID = &sessionID
+	}
+	return resp
+}
+
+func FromTTREvent(event *models.TTREvent) TTREventResponse {
+	resp := TTREventResponse{
+		Sequence:  event.Sequence,
+		Type:      event.Type,
+		Data:      map[string]string{},
+		CreatedAt: formatTime(event.CreatedAt),
+	}
+	// Events are only written by ChangeFeedService.Record, so Data is
+	// always a JSON object of strings.
+	_ = json.Unmarshal([]byte(event.Data), &resp.Data)
+	if event.ActorUserID != nil {
+		actorUserID := event.ActorUserID.String()
+		resp.ActorUserID = &actorUserID
+	}
+	return resp
+}
+
+func FromFeatureFlag(flag *models.FeatureFlag) FeatureFlagResponse {
+	rule := flag.Rule()
+	userIDs := make([]string, 0, len(rule.Users))
+	for _, id := range rule.Users {
+		userIDs = append(userIDs, id.String())
+	}
+	orgIDs := make([]string, 0, len(rule.Organizations))
+	for _, id := range rule.Organizations {
+		orgIDs = append(orgIDs, id.String())
+	}
+	return FeatureFlagResponse{
+		Key:             flag.Key,
+		Description:     flag.Description,
+		Enabled:         flag.Enabled,
+		Percentage:      flag.Percentage,
+		UserIDs:         userIDs,
+		OrganizationIDs: orgIDs,
+		UpdatedAt:       formatTime(flag.UpdatedAt),
+	}
+}
+
+func FromMaintenanceState(state service.MaintenanceState) MaintenanceResponse {
+	return MaintenanceResponse{
+		Enabled: state.Enabled,
+		EndsAt:  formatTimePtr(state.EndsAt),
+	}
+}
+
+func FromWebhook(webhook *models.Webhook) WebhookResponse {
+	resp := WebhookResponse{
+		ID:                  webhook.ID.String(),
+		OwnerUserID:         webhook.OwnerUserID.String(),
+		URL:                 webhook.URL,
+		Events:              webhook.EventList(),
+		Status:              webhook.Status,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		CreatedAt:           formatTime(webhook.CreatedAt),
+		UpdatedAt:           formatTime(webhook.UpdatedAt),
+	}
+	if webhook.OrganizationID != nil {
+		organizationID := webhook.OrganizationID.String()
+		resp.OrganizationID = &organizationID
+	}
+	return resp
+}
+
+func FromInvitationTemplate(template *models.InvitationTemplate) InvitationTemplateResponse {
+	return InvitationTemplateResponse{
+		ID:        template.ID.String(),
+		Name:      template.Name,
+		Body:      template.Body,
+		CreatedAt: formatTime(template.CreatedAt),
+		UpdatedAt: formatTime(template.UpdatedAt),
+	}
+}
+
+func FromWebhookDelivery(delivery *models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:         delivery.ID.String(),
+		EventID:    delivery.EventID.String(),
+		Event:      delivery.Event,
+		Attempt:    delivery.Attempt,
+		StatusCode: delivery.StatusCode,
+		Error:      delivery.Error,
+		Succeeded:  delivery.Succeeded,
+		DurationMs: delivery.DurationMs,
+		CreatedAt:  formatTime(delivery.CreatedAt),
+	}
+}
+
+func FromSecurityEvent(event *models.SecurityEvent) SecurityEventResponse {
+	return SecurityEventResponse{
+		ID:        event.ID.String(),
+		Type:      event.Type,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		CreatedAt: formatTime(event.CreatedAt),
+	}
+}
+
+func FromReport(report *models.Report) ReportResponse {
+	resp := ReportResponse{
+		ID:               report.ID.String(),
+		ReporterUserID:   report.ReporterUserID.String(),
+		TargetType:       report.TargetType,
+		TargetID:         report.TargetID.String(),
+		Category:         report.Category,
+		Details:          report.Details,
+		Status:           report.Status,
+		Resolution:       report.Resolution,
+		ResolvedByUserID: uuidToString(report.ResolvedByUserID),
+		ResolvedAt:       formatTimePtr(report.ResolvedAt),
+		CreatedAt:        formatTime(report.CreatedAt),
+	}
+	if report.Reporter != nil {
+		resp.ReporterName = report.Reporter.FirstName + " " + report.Reporter.LastName
+	}
+	return resp
+}
+
+// FromRoundHistory is history with its partners as v sees them.
+func FromRoundHistory(v Viewer, history *service.RoundHistory) HistoryResponse {
+	resp := HistoryResponse{
+		Year:   history.Year,
+		Months: make([]HistoryMonthResponse, 0, len(history.Months)),
+		Summary: HistorySummaryResponse{
+			RoundsPlayed:    history.Summary.RoundsPlayed,
+			DistinctCourses: history.Summary.DistinctCourses,
+			TopPartners:     make([]HistoryPartnerResponse, 0, len(history.Summary.TopPartners)),
+		},
+	}
+	for _, month := range history.Months {
+		monthResp := HistoryMonthResponse{
+			Month:      int(month.Month),
+			RoundCount: len(month.Rounds),
+			Rounds:     make([]HistoryRoundResponse, 0, len(month.Rounds)),
+		}
+		for _, round := range month.Rounds {
+			monthResp.Rounds = append(monthResp.Rounds, HistoryRoundResponse{
+				TTRID:          round.TTRID.String(),
+				CourseName:     round.CourseName,
+				CourseLocation: round.CourseLocation,
+				TeeDate:        round.TeeDate.Format("2006-01-02"),
+				TeeTime:        round.TeeTime.Format("15:04"),
+				CaptainUserID:  round.CaptainUserID.String(),
+				Players:        round.Players,
+			})
+		}
+		resp.Months = append(resp.Months, monthResp)
+	}
+	for _, partner := range history.Summary.TopPartners {
+		resp.Summary.TopPartners = append(resp.Summary.TopPartners, HistoryPartnerResponse{
+			User:   FromPublicUser(v, partner.User),
+			Rounds: partner.Rounds,
+		})
+	}
+	return resp
+}
+
+func FromUserMerge(merge *service.UserMergeResult) UserMergeResponse {
+	return UserMergeResponse{
+		SourceUserID:         merge.SourceUserID.String(),
+		TargetUserID:         merge.TargetUserID.String(),
+		CaptainedTTRs:        merge.CaptainedTTRs,
+		CoCaptaincies:        merge.CoCaptaincies,
+		DroppedCoCaptaincies: merge.DroppedCoCaptaincies,
+		RosterSpots:          merge.RosterSpots,
+		DroppedRosterSpots:   merge.DroppedRosterSpots,
+		Invitations:          merge.Invitations,
+		DroppedInvitations:   merge.DroppedInvitations,
+		Notifications:        merge.Notifications,
+		Messages:             merge.Messages,
+		RevokedRefreshTokens: merge.RevokedRefreshTokens,
+	}
+}
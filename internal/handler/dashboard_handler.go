@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type DashboardHandler struct {
+	dashboardService *service.DashboardService
+}
+
+func NewDashboardHandler(dashboardService *service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{dashboardService: dashboardService}
+}
+
+type DashboardResponse struct {
+	NextTTR             *DashboardTTRResponse       `json:"next_ttr"`
+	PendingInvitations  *PendingInvitationsResponse `json:"pending_invitations"`
+	UnreadNotifications *int64                      `json:"unread_notifications"`
+	ActionItems         []ActionItemResponse        `json:"action_items"`
+	Warnings            []string                    `json:"warnings"`
+}
+
+type DashboardTTRResponse struct {
+	TTR    TTRResponse          `json:"ttr"`
+	Roster RosterCountsResponse `json:"roster"`
+}
+
+type RosterCountsResponse struct {
+	Confirmed int `json:"confirmed"`
+	Maybe     int `json:"maybe"`
+	Declined  int `json:"declined"`
+	OpenSlots int `json:"open_slots"`
+}
+
+type PendingInvitationsResponse struct {
+	Total int                              `json:"total"`
+	ByTTR []PendingInvitationCountResponse `json:"by_ttr"`
+}
+
+type PendingInvitationCountResponse struct {
+	TTRID string `json:"ttr_id"`
+	Count int    `json:"count"`
+}
+
+// GetDashboard godoc
+// @Summary Get the caller's dashboard
+// @Description Everything the app shows on opening, in one call: the caller's next round with its roster counts, the invitations still waiting for an answer on upcoming TTRs they captain, their unread notification count and their action items. The sections are loaded side by side; one that fails is returned as null and named in warnings, and the rest are still returned. next_ttr is also null when the caller has no upcoming round. A round stays upcoming until four hours after its tee time, while it is being played.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=DashboardResponse} "Dashboard retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Router /api/v1/dashboard [get]
+func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	dashboard := h.dashboardService.GetDashboard(r.Context(), userID)
+
+	resp := DashboardResponse{
+		UnreadNotifications: dashboard.UnreadNotifications,
+		Warnings:            make([]string, 0, len(dashboard.Warnings)),
+	}
+	resp.Warnings = append(resp.Warnings, dashboard.Warnings...)
+
+	if next := dashboard.NextTTR; next != nil {
+		resp.NextTTR = &DashboardTTRResponse{
+			TTR: FromTTR(viewerFrom(r), next.TTR),
+			Roster: RosterCountsResponse{
+				Confirmed: next.Roster.Confirmed,
+				Maybe:     next.Roster.Maybe,
+				Declined:  next.Roster.Declined,
+				OpenSlots: next.Roster.OpenSlots,
+			},
+		}
+	}
+
+	if dashboard.PendingInvitations != nil {
+		pending := &PendingInvitationsResponse{
+			ByTTR: make([]PendingInvitationCountResponse, 0, len(dashboard.PendingInvitations)),
+		}
+		for ttrID, count := range dashboard.PendingInvitations {
+			pending.Total += count
+			pending.ByTTR = append(pending.ByTTR, PendingInvitationCountResponse{
+				TTRID: ttrID.String(),
+				Count: count,
+			})
+		}
+		sort.Slice(pending.ByTTR, func(i, j int) bool {
+			return pending.ByTTR[i].TTRID < pending.ByTTR[j].TTRID
+		})
+		resp.PendingInvitations = pending
+	}
+
+	if dashboard.ActionItems != nil {
+		resp.ActionItems = make([]ActionItemResponse, 0, len(dashboard.ActionItems))
+		for _, item := range dashboard.ActionItems {
+			resp.ActionItems = append(resp.ActionItems, FromActionItem(item))
+		}
+	}
+
+	response.Success(w, http.StatusOK, resp)
+}
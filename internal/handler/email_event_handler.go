@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+// maxEmailEventsSize bounds a batch of events posted by a provider.
+const maxEmailEventsSize = 1 << 20
+
+// EmailEventHandler takes the bounces and complaints email providers post.
+// SendGrid requests are verified with sendGridKey and SES notifications,
+// which SNS posts, with sns; a provider without one is refused.
+type EmailEventHandler struct {
+	suppressionService *service.EmailSuppressionService
+	sendGridKey        *ecdsa.PublicKey
+	sns                *mailevents.SNSVerifier
+}
+
+func NewEmailEventHandler(suppressionService *service.EmailSuppressionService, sendGridKey *ecdsa.PublicKey, sns *mailevents.SNSVerifier) *EmailEventHandler {
+	return &EmailEventHandler{suppressionService: suppressionService, sendGridKey: sendGridKey, sns: sns}
+}
+
+// HandleEvents godoc
+// @Summary Receive email delivery events
+// @Description Endpoint for email provider webhooks: SendGrid's signed event webhook, or SES notifications delivered by SNS, told apart by the x-amz-sns-message-type header. A hard bounce or spam complaint stops mail to the address; soft bounces do after 3 in a row. The account with the address is flagged as undeliverable. SNS subscription confirmations are confirmed.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Success 204 "Events recorded"
+// @Failure 400 {object} response.Response "Invalid request body"
+// @Failure 401 {object} response.Response "Invalid signature"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/integrations/email/events [post]
+func (h *EmailEventHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxEmailEventsSize))
+	if err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	var events []mailevents.Event
+	if r.Header.Get(mailevents.SNSMessageTypeHeader) != "" {
+		if h.sns == nil {
+			response.Unauthorized(w, "Invalid signature")
+			return
+		}
+		msg, err := mailevents.ParseSNS(body)
+		if err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+		if err := h.sns.Verify(r.Context(), msg); err != nil {
+			if errors.Is(err, mailevents.ErrMissingSignature) || errors.Is(err, mailevents.ErrInvalidSignature) || errors.Is(err, mailevents.ErrUnknownTopic) {
+				response.Unauthorized(w, "Invalid signature")
+				return
+			}
+			response.InternalServerError(w, "Failed to verify signature")
+			return
+		}
+		switch msg.Type {
+		case mailevents.SNSTypeSubscriptionConfirmation:
+			if err := mailevents.ConfirmSubscription(r.Context(), msg); err != nil {
+				response.InternalServerError(w, "Failed to confirm subscription")
+				return
+			}
+			response.NoContent(w)
+			return
+		case mailevents.SNSTypeNotification:
+			if events, err = mailevents.ParseSES(msg); err != nil {
+				response.BadRequest(w, "Invalid request body")
+				return
+			}
+		}
+	} else {
+		if h.sendGridKey == nil {
+			response.Unauthorized(w, "Invalid signature")
+			return
+		}
+		if err := mailevents.VerifySendGrid(h.sendGridKey, r.Header, body, time.Now()); err != nil {
+			response.Unauthorized(w, "Invalid signature")
+			return
+		}
+		if events, err = mailevents.ParseSendGrid(body); err != nil {
+			response.BadRequest(w, "Invalid request body")
+			return
+		}
+	}
+
+	if err := h.suppressionService.RecordEvents(r.Context(), events); err != nil {
+		response.FromError(w, err, "Failed to record email events")
+		return
+	}
+	response.NoContent(w)
+}
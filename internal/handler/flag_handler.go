@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type FlagHandler struct {
+	flagService *service.FlagService
+}
+
+func NewFlagHandler(flagService *service.FlagService) *FlagHandler {
+	return &FlagHandler{flagService: flagService}
+}
+
+type SetFeatureFlagRequest struct {
+	Description     string   `json:"description" validate:"max=500"`
+	Enabled         bool     `json:"enabled"`
+	Percentage      int      `json:"percentage" validate:"min=0,max=100"`
+	UserIDs         []string `json:"user_ids" validate:"omitempty,dive,uuid"`
+	OrganizationIDs []string `json:"organization_ids" validate:"omitempty,dive,uuid"`
+}
+
+type FeatureFlagResponse struct {
+	Key             string   `json:"key"`
+	Description     string   `json:"description"`
+	Enabled         bool     `json:"enabled"`
+	Percentage      int      `json:"percentage"`
+	UserIDs         []string `json:"user_ids"`
+	OrganizationIDs []string `json:"organization_ids"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+// GetMyFlags godoc
+// @Summary Get my feature flags
+// @Description Get every feature flag and whether it is on for the caller, so clients can show or hide features. A user's percentage rollouts are stable: they stay on as a rollout grows.
+// @Tags meta
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=map[string]bool} "Feature flags retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/meta/flags [get]
+func (h *FlagHandler) GetMyFlags(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	flags, err := h.flagService.EvaluateFlags(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to evaluate feature flags")
+		return
+	}
+
+	response.Success(w, http.StatusOK, flags)
+}
+
+// ListFlags godoc
+// @Summary List feature flags
+// @Description List the feature flags managed at runtime, ordered by key. Flags rolled out only from config are not listed. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]FeatureFlagResponse} "Feature flags retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/flags [get]
+func (h *FlagHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	flags, err := h.flagService.ListFlags(r.Context())
+	if err != nil {
+		response.FromError(w, err, "Failed to list feature flags")
+		return
+	}
+
+	flagResponses := make([]FeatureFlagResponse, 0, len(flags))
+	for _, flag := range flags {
+		flagResponses = append(flagResponses, FromFeatureFlag(flag))
+	}
+
+	response.Success(w, http.StatusOK, flagResponses)
+}
+
+// SetFlag godoc
+// @Summary Set feature flag
+// @Description Create or replace a feature flag, taking effect on the next request. While enabled, the flag is on for user_ids, for members of organization_ids and for percentage percent of everyone else; while disabled it is off for everyone. It replaces any config rollout of the same flag. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key, e.g. waitlist"
+// @Param request body SetFeatureFlagRequest true "Flag rule"
+// @Success 200 {object} response.Response{data=FeatureFlagResponse} "Feature flag saved successfully"
+// @Failure 400 {object} response.Response "Invalid flag key or ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/flags/{key} [put]
+func (h *FlagHandler) SetFlag(w http.ResponseWriter, r *http.Request) {
+	var req SetFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	userIDs, ok := parseIDs(req.UserIDs)
+	if !ok {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+	orgIDs, ok := parseIDs(req.OrganizationIDs)
+	if !ok {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	flag, err := h.flagService.SetFlag(r.Context(), mux.Vars(r)["key"], req.Description, req.Enabled, req.Percentage, userIDs, orgIDs)
+	if err != nil {
+		response.FromError(w, err, "Failed to save feature flag")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromFeatureFlag(flag))
+}
+
+// DeleteFlag godoc
+// @Summary Delete feature flag
+// @Description Delete a runtime feature flag. The flag falls back to its config rollout, or is off for everyone without one. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key"
+// @Success 200 {object} response.Response "Feature flag deleted successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 404 {object} response.Response "Feature flag not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/flags/{key} [delete]
+func (h *FlagHandler) DeleteFlag(w http.ResponseWriter, r *http.Request) {
+	if err := h.flagService.DeleteFlag(r.Context(), mux.Vars(r)["key"]); err != nil {
+		response.FromError(w, err, "Failed to delete feature flag")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Feature flag deleted successfully"})
+}
+
+func parseIDs(raw []string) ([]uuid.UUID, bool) {
+	ids := make([]uuid.UUID, 0, len(raw))
+	for _, s := range raw {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+	return ids, true
+}
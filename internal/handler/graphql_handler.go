@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/dataloader"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/graphql"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+	"go.uber.org/zap"
+)
+
+// GraphQLHandler serves read-only GraphQL queries over the same services as
+// the REST routes, so the same permission and privacy rules apply.
+type GraphQLHandler struct {
+	userService       *service.UserService
+	ttrService        *service.TTRService
+	invitationService *service.InvitationService
+	schema            *graphql.Schema
+	maxDepth          int
+	maxComplexity     int
+	logger            *zap.Logger
+}
+
+// NewGraphQLHandler creates the handler. Queries nesting deeper than
+// maxDepth or selecting more than maxComplexity fields are refused.
+func NewGraphQLHandler(userService *service.UserService, ttrService *service.TTRService, invitationService *service.InvitationService, maxDepth int, maxComplexity int, logger *zap.Logger) *GraphQLHandler {
+	h := &GraphQLHandler{
+		userService:       userService,
+		ttrService:        ttrService,
+		invitationService: invitationService,
+		maxDepth:          maxDepth,
+		maxComplexity:     maxComplexity,
+		logger:            logger,
+	}
+	h.schema = h.newSchema()
+	return h
+}
+
+type GraphQLRequest struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQLResponse is the standard GraphQL response. data is missing when
+// the query was refused before it ran.
+type GraphQLResponse struct {
+	Data   interface{}      `json:"data,omitempty"`
+	Errors []*graphql.Error `json:"errors,omitempty"`
+}
+
+// Query godoc
+// @Summary Run a GraphQL query
+// @Description Runs a read-only GraphQL query, for clients that want a TTR with chosen fields and its invitations in one round trip. The root fields are me, ttr(id), myTTRs(as, status, limit, offset) and myInvitations(sent); mutations are not supported. Responses use the GraphQL shape rather than the usual envelope: a query that doesn't parse, doesn't fit the schema, nests deeper than GRAPHQL_MAX_DEPTH or selects more than GRAPHQL_MAX_COMPLEXITY fields gets a 400 with only errors. Otherwise the response is a 200 with data, and errors for any fields that failed, which are null in data. Each error carries extensions.code from the error catalogue. me needs the read:profile scope and invitations read:invitations; fields the token lacks a scope for fail with INSUFFICIENT_SCOPE.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body GraphQLRequest true "GraphQL query"
+// @Success 200 {object} GraphQLResponse "Query ran"
+// @Failure 400 {object} GraphQLResponse "Query refused"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 422 {object} response.Response "Validation error"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	var req GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphqlRequestKey{}, h.newRequest(r))
+	result := h.schema.Execute(ctx, graphql.Params{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+		MaxDepth:      h.maxDepth,
+		MaxComplexity: h.maxComplexity,
+		PresentError:  h.presentError,
+	})
+
+	status := http.StatusOK
+	if result.Rejected() {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(GraphQLResponse{Data: result.Data, Errors: result.Errors})
+}
+
+type graphqlRequestKey struct{}
+
+// graphqlRequest is what resolvers need about the request: who is asking,
+// with which scopes, and the loaders that batch what nested fields read.
+// Loaders live for one request, so nothing is cached across callers.
+type graphqlRequest struct {
+	viewer      Viewer
+	scopes      []scope.Scope
+	ttrs        *dataloader.Loader[uuid.UUID, service.TTRView]
+	invitations *dataloader.Loader[uuid.UUID, []*service.InvitationDetail]
+}
+
+func (h *GraphQLHandler) newRequest(r *http.Request) *graphqlRequest {
+	viewer := viewerFrom(r)
+	scopes, _ := r.Context().Value(middleware.ScopesKey).([]scope.Scope)
+	return &graphqlRequest{
+		viewer: viewer,
+		scopes: scopes,
+		ttrs: dataloader.New(func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]service.TTRView, error) {
+			return h.ttrService.GetTTRs(ctx, ids, viewer.UserID)
+		}),
+		invitations: dataloader.New(func(ctx context.Context, ttrIDs []uuid.UUID) (map[uuid.UUID][]*service.InvitationDetail, error) {
+			return h.invitationService.GetTTRsInvitations(ctx, ttrIDs, viewer.UserID)
+		}),
+	}
+}
+
+func requestFrom(ctx context.Context) *graphqlRequest {
+	return ctx.Value(graphqlRequestKey{}).(*graphqlRequest)
+}
+
+var (
+	errMissingScope = errcode.New(errcode.InsufficientScope, "Token is missing a required scope")
+	errInvalidTTRID = errcode.New(errcode.BadRequest, "Invalid TTR ID")
+)
+
+// requireScope fails a field the caller's token lacks s for.
+func (req *graphqlRequest) requireScope(s scope.Scope) error {
+	if !scope.Has(req.scopes, s) {
+		return errMissingScope
+	}
+	return nil
+}
+
+// presentError gives err its code in extensions.code and translates its
+// message. Errors that aren't the client's are logged and sent as internal
+// errors, so their details never reach the client.
+func (h *GraphQLHandler) presentError(ctx context.Context, err *graphql.Error) {
+	var (
+		coded      *errcode.Error
+		syntax     *graphql.SyntaxError
+		validation *graphql.ValidationError
+		limit      *graphql.LimitError
+		code       errcode.Code
+	)
+	switch {
+	case errors.As(err, &coded):
+		code, err.Message = coded.Code, coded.Message
+	case errors.As(err, &syntax):
+		code = errcode.GraphQLParseFailed
+	case errors.As(err, &validation):
+		code = errcode.GraphQLValidationFailed
+	case errors.As(err, &limit) && limit.Limit == "depth":
+		code = errcode.QueryTooDeep
+	case errors.As(err, &limit):
+		code = errcode.QueryTooComplex
+	default:
+		h.logger.Error("Failed to resolve GraphQL field", zap.Any("path", err.Path), zap.Error(err.Unwrap()))
+		code, err.Message = errcode.InternalServerError, "Internal server error"
+	}
+
+	key, message := i18n.Localize(i18n.FromContext(ctx), err.Message)
+	err.Message = message
+	err.Extensions = map[string]interface{}{"code": code}
+	if key != "" {
+		err.Extensions["message_key"] = key
+	}
+}
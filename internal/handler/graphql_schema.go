@@ -0,0 +1,280 @@
+package handler
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/graphql"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+// The GraphQL types are built from the REST responses, so a field shows
+// exactly what the matching REST field would to the same caller.
+
+// ttrSource is a TTR as the caller sees it. Everyone gets the public view;
+// participants also get the full one, whose fields are null for others.
+type ttrSource struct {
+	id     uuid.UUID
+	public TTRPublicResponse
+	full   *TTRResponse
+}
+
+// newTTRSource builds the caller's view of the TTR and primes the TTR loader
+// with it, so invitations nested under the TTR don't read it again.
+func newTTRSource(req *graphqlRequest, view service.TTRView) *ttrSource {
+	req.ttrs.Prime(view.TTR.ID, view)
+	source := &ttrSource{id: view.TTR.ID, public: FromPublicTTR(view.TTR)}
+	if view.Participant {
+		full := FromTTR(req.viewer, view.TTR)
+		source.full = &full
+	}
+	return source
+}
+
+// invitationSource is an invitation with the TTR it is for, which is loaded
+// separately with its roster.
+type invitationSource struct {
+	resp  InvitationResponse
+	ttrID uuid.UUID
+}
+
+func newInvitationSources(v Viewer, invitations []*service.InvitationDetail) []*invitationSource {
+	sources := make([]*invitationSource, 0, len(invitations))
+	for _, invitation := range invitations {
+		withoutTTR := *invitation
+		withoutTTR.TTR = nil
+		sources = append(sources, &invitationSource{resp: FromInvitation(v, &withoutTTR), ttrID: invitation.TTRID})
+	}
+	return sources
+}
+
+// resolve reads a field from a source of type S.
+func resolve[S any](get func(S) interface{}) graphql.ResolveFunc {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return get(p.Source.(S)), nil
+	}
+}
+
+// publicTTR reads a TTR field everyone who can see the TTR sees.
+func publicTTR(get func(TTRPublicResponse) interface{}) graphql.ResolveFunc {
+	return resolve(func(s *ttrSource) interface{} { return get(s.public) })
+}
+
+// fullTTR reads a TTR field only participants see.
+func fullTTR(get func(*TTRResponse) interface{}) graphql.ResolveFunc {
+	return resolve(func(s *ttrSource) interface{} {
+		if s.full == nil {
+			return nil
+		}
+		return get(s.full)
+	})
+}
+
+// optional is s, or null when it is empty because it is hidden.
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+//go:embed schema.graphqls
+var graphqlSchema string
+
+// newSchema loads schema.graphqls with the resolvers of its fields.
+func (h *GraphQLHandler) newSchema() *graphql.Schema {
+	return graphql.MustNewSchema(graphqlSchema, graphql.Resolvers{
+		"Query": {
+			"me":            h.resolveMe,
+			"ttr":           h.resolveTTR,
+			"myTTRs":        h.resolveMyTTRs,
+			"myInvitations": h.resolveMyInvitations,
+		},
+		"User": {
+			"id":        resolve(func(u *UserResponse) interface{} { return u.ID }),
+			"firstName": resolve(func(u *UserResponse) interface{} { return u.FirstName }),
+			"lastName":  resolve(func(u *UserResponse) interface{} { return optional(u.LastName) }),
+			"email":     resolve(func(u *UserResponse) interface{} { return optional(u.Email) }),
+			"phone":     resolve(func(u *UserResponse) interface{} { return u.Phone }),
+			"handicap":  resolve(func(u *UserResponse) interface{} { return u.Handicap }),
+			"avatarUrl": resolve(func(u *UserResponse) interface{} { return u.AvatarURL }),
+			"deleted":   resolve(func(u *UserResponse) interface{} { return u.Deleted }),
+		},
+		"TTR": {
+			"id":             resolve(func(s *ttrSource) interface{} { return s.id.String() }),
+			"participant":    resolve(func(s *ttrSource) interface{} { return s.full != nil }),
+			"courseName":     publicTTR(func(t TTRPublicResponse) interface{} { return t.CourseName }),
+			"courseLocation": publicTTR(func(t TTRPublicResponse) interface{} { return t.CourseLocation }),
+			"teeDate":        publicTTR(func(t TTRPublicResponse) interface{} { return t.TeeDate }),
+			"teeTime":        publicTTR(func(t TTRPublicResponse) interface{} { return t.TeeTime }),
+			"maxPlayers":     publicTTR(func(t TTRPublicResponse) interface{} { return t.MaxPlayers }),
+			"openSlots":      publicTTR(func(t TTRPublicResponse) interface{} { return t.OpenSlots }),
+			"status":         publicTTR(func(t TTRPublicResponse) interface{} { return t.Status }),
+			"minPlayers":     fullTTR(func(t *TTRResponse) interface{} { return t.MinPlayers }),
+			"visibility":     fullTTR(func(t *TTRResponse) interface{} { return t.Visibility }),
+			"notes":          fullTTR(func(t *TTRResponse) interface{} { return t.Notes }),
+			"rsvpDeadline":   fullTTR(func(t *TTRResponse) interface{} { return t.RSVPDeadline }),
+			"createdAt":      fullTTR(func(t *TTRResponse) interface{} { return t.CreatedAt }),
+			"updatedAt":      fullTTR(func(t *TTRResponse) interface{} { return t.UpdatedAt }),
+			"captain":        fullTTR(func(t *TTRResponse) interface{} { return t.CaptainUser }),
+			"createdBy":      fullTTR(func(t *TTRResponse) interface{} { return t.CreatedByUser }),
+			"coCaptains": fullTTR(func(t *TTRResponse) interface{} {
+				coCaptains := make([]*TTRCoCaptainResponse, len(t.CoCaptains))
+				for i := range t.CoCaptains {
+					coCaptains[i] = &t.CoCaptains[i]
+				}
+				return coCaptains
+			}),
+			// FromTTR lists players in a tee slot under the slot; here they
+			// are all on the roster.
+			"players": fullTTR(func(t *TTRResponse) interface{} {
+				players := make([]*TTRPlayerResponse, 0, len(t.Players))
+				for i := range t.Players {
+					players = append(players, &t.Players[i])
+				}
+				for _, slot := range t.Slots {
+					for i := range slot.Players {
+						players = append(players, &slot.Players[i])
+					}
+				}
+				return players
+			}),
+			"invitations": resolveTTRInvitations,
+		},
+		"Player": {
+			"userId":      resolve(func(p *TTRPlayerResponse) interface{} { return optional(p.UserID) }),
+			"user":        resolve(func(p *TTRPlayerResponse) interface{} { return p.User }),
+			"guest":       resolve(func(p *TTRPlayerResponse) interface{} { return p.IsGuest }),
+			"guestId":     resolve(func(p *TTRPlayerResponse) interface{} { return optional(p.GuestID) }),
+			"displayName": resolve(func(p *TTRPlayerResponse) interface{} { return optional(p.DisplayName) }),
+			"handicap":    resolve(func(p *TTRPlayerResponse) interface{} { return p.Handicap }),
+			"status":      resolve(func(p *TTRPlayerResponse) interface{} { return p.Status }),
+			"groupNumber": resolve(func(p *TTRPlayerResponse) interface{} { return p.GroupNumber }),
+			"joinedAt":    resolve(func(p *TTRPlayerResponse) interface{} { return p.JoinedAt }),
+			"checkedInAt": resolve(func(p *TTRPlayerResponse) interface{} { return p.CheckedInAt }),
+		},
+		"CoCaptain": {
+			"userId":      resolve(func(c *TTRCoCaptainResponse) interface{} { return c.UserID }),
+			"user":        resolve(func(c *TTRCoCaptainResponse) interface{} { return c.User }),
+			"permissions": resolve(func(c *TTRCoCaptainResponse) interface{} { return c.Permissions }),
+			"assignedAt":  resolve(func(c *TTRCoCaptainResponse) interface{} { return c.AssignedAt }),
+		},
+		"Invitation": {
+			"id":            resolve(func(i *invitationSource) interface{} { return i.resp.ID }),
+			"status":        resolve(func(i *invitationSource) interface{} { return i.resp.Status }),
+			"message":       resolve(func(i *invitationSource) interface{} { return i.resp.Message }),
+			"declineReason": resolve(func(i *invitationSource) interface{} { return i.resp.DeclineReason }),
+			"acceptable":    resolve(func(i *invitationSource) interface{} { return i.resp.Acceptable }),
+			"createdAt":     resolve(func(i *invitationSource) interface{} { return i.resp.CreatedAt }),
+			"respondedAt":   resolve(func(i *invitationSource) interface{} { return i.resp.RespondedAt }),
+			"inviter":       resolve(func(i *invitationSource) interface{} { return i.resp.InviterUser }),
+			"invitee":       resolve(func(i *invitationSource) interface{} { return i.resp.InviteeUser }),
+			"ttr":           resolveInvitationTTR,
+		},
+	})
+}
+
+// resolveTTRInvitations queues the TTR with the invitation loader, so the
+// invitations of every TTR in the response are read in one batch.
+func resolveTTRInvitations(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	if err := req.requireScope(scope.ReadInvitations); err != nil {
+		return nil, err
+	}
+	load := req.invitations.Load(p.Context, p.Source.(*ttrSource).id)
+	return graphql.Thunk(func() (interface{}, error) {
+		invitations, found, err := load()
+		if err != nil || !found {
+			return nil, err
+		}
+		return newInvitationSources(req.viewer, invitations), nil
+	}), nil
+}
+
+func resolveInvitationTTR(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	load := req.ttrs.Load(p.Context, p.Source.(*invitationSource).ttrID)
+	return graphql.Thunk(func() (interface{}, error) {
+		view, found, err := load()
+		if err != nil || !found {
+			return nil, err
+		}
+		return newTTRSource(req, view), nil
+	}), nil
+}
+
+func (h *GraphQLHandler) resolveMe(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	if err := req.requireScope(scope.ReadProfile); err != nil {
+		return nil, err
+	}
+	profile, err := h.userService.GetProfile(p.Context, req.viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	resp := FromProfile(profile)
+	return &resp, nil
+}
+
+func (h *GraphQLHandler) resolveTTR(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	id, err := uuid.Parse(fmt.Sprint(p.Args["id"]))
+	if err != nil {
+		return nil, errInvalidTTRID
+	}
+	detail, isParticipant, err := h.ttrService.GetTTR(p.Context, id, req.viewer.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return newTTRSource(req, service.TTRView{TTR: detail, Participant: isParticipant}), nil
+}
+
+// resolveMyTTRs lists the caller's TTRs, all of which they participate in.
+// Like the REST listing, a limit or offset out of range falls back to its
+// default.
+func (h *GraphQLHandler) resolveMyTTRs(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	// Arguments passed as null variables are missing from Args, like
+	// those not given.
+	as, _ := p.Args["as"].(string)
+	if as == "" {
+		as = "PLAYER"
+	}
+	status, _ := p.Args["status"].(string)
+	search := service.TTRSearch{Mine: models.TTRParticipation(strings.ToLower(as)), Status: models.TTRStatus(status)}
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if limit <= 0 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	ttrs, err := h.ttrService.SearchTTRs(p.Context, req.viewer.UserID, search, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]*ttrSource, 0, len(ttrs))
+	for _, ttr := range ttrs {
+		sources = append(sources, newTTRSource(req, service.TTRView{TTR: ttr, Participant: true}))
+	}
+	return sources, nil
+}
+
+func (h *GraphQLHandler) resolveMyInvitations(p graphql.ResolveParams) (interface{}, error) {
+	req := requestFrom(p.Context)
+	if err := req.requireScope(scope.ReadInvitations); err != nil {
+		return nil, err
+	}
+	sent, _ := p.Args["sent"].(bool)
+	invitations, err := h.invitationService.GetUserInvitations(p.Context, req.viewer.UserID, !sent)
+	if err != nil {
+		return nil, err
+	}
+	return newInvitationSources(req.viewer, invitations), nil
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+// HealthChecker reports whether a dependency, such as the database, is
+// reachable.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	db          HealthChecker
+	maintenance *service.MaintenanceService
+}
+
+// NewHealthHandler checks db, when set, for readiness. While maintenance
+// mode is on the instance reports itself not ready.
+func NewHealthHandler(db HealthChecker, maintenance *service.MaintenanceService) *HealthHandler {
+	return &HealthHandler{
+		db:          db,
+		maintenance: maintenance,
+	}
+}
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// Healthz godoc
+// @Summary Liveness check
+// @Description Report that the process is up. It answers even during maintenance.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} response.Response{data=HealthResponse} "Alive"
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness check
+// @Description Report whether the instance should get traffic. It is not ready while the database is unreachable or maintenance mode is on, so load balancers drain it.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} response.Response{data=HealthResponse} "Ready"
+// @Failure 503 {object} response.Response "Not ready, or down for maintenance"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	if h.maintenance != nil && h.maintenance.State(r.Context()).Enabled {
+		response.Coded(w, errcode.Maintenance, "We're down for maintenance and will be back shortly")
+		return
+	}
+	if h.db != nil {
+		if err := h.db.HealthCheck(r.Context()); err != nil {
+			response.Coded(w, errcode.NotReady, "Database is unreachable")
+			return
+		}
+	}
+
+	response.Success(w, http.StatusOK, HealthResponse{Status: "ready"})
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+// minHistoryYear is the earliest year a history can be asked for.
+const minHistoryYear = 1900
+
+type HistoryHandler struct {
+	historyService *service.HistoryService
+}
+
+func NewHistoryHandler(historyService *service.HistoryService) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService}
+}
+
+type HistoryResponse struct {
+	Year    int                    `json:"year"`
+	Months  []HistoryMonthResponse `json:"months"`
+	Summary HistorySummaryResponse `json:"summary"`
+}
+
+type HistoryMonthResponse struct {
+	Month      int                    `json:"month"`
+	RoundCount int                    `json:"round_count"`
+	Rounds     []HistoryRoundResponse `json:"rounds"`
+}
+
+type HistoryRoundResponse struct {
+	TTRID          string  `json:"ttr_id"`
+	CourseName     string  `json:"course_name"`
+	CourseLocation *string `json:"course_location,omitempty"`
+	TeeDate        string  `json:"tee_date"`
+	TeeTime        string  `json:"tee_time"`
+	CaptainUserID  string  `json:"captain_user_id"`
+	Players        int     `json:"players"`
+}
+
+type HistorySummaryResponse struct {
+	RoundsPlayed    int                      `json:"rounds_played"`
+	DistinctCourses int                      `json:"distinct_courses"`
+	TopPartners     []HistoryPartnerResponse `json:"top_partners"`
+}
+
+type HistoryPartnerResponse struct {
+	User   PublicUserResponse `json:"user"`
+	Rounds int                `json:"rounds"`
+}
+
+// GetHistory godoc
+// @Summary Get round history
+// @Description Get the caller's rounds in a calendar year: the COMPLETED TTRs they were a CONFIRMED player in, grouped by the month they teed off, earliest first. Months without rounds are left out. players counts the confirmed players and guests of a round. The summary counts the rounds played and the distinct courses, and ranks the five users the caller played the most rounds with.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param year query int false "Calendar year (default the current year)"
+// @Success 200 {object} response.Response{data=HistoryResponse} "History retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid year"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/history [get]
+func (h *HistoryHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	year := time.Now().UTC().Year()
+	if raw := r.URL.Query().Get("year"); raw != "" {
+		y, err := strconv.Atoi(raw)
+		if err != nil || y < minHistoryYear || y > year+1 {
+			response.BadRequest(w, "Invalid year")
+			return
+		}
+		year = y
+	}
+
+	history, err := h.historyService.History(r.Context(), userID, year)
+	if err != nil {
+		response.FromError(w, err, "Failed to get history")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromRoundHistory(viewerFrom(r), history))
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationService: impersonationService}
+}
+
+type StartImpersonationRequest struct {
+	Reason      string `json:"reason" validate:"required,max=500"`
+	AllowWrites bool   `json:"allow_writes"`
+}
+
+type ImpersonationResponse struct {
+	SessionID    string `json:"session_id"`
+	TargetUserID string `json:"target_user_id"`
+	AccessToken  string `json:"access_token"`
+	AllowWrites  bool   `json:"allow_writes"`
+	ExpiresAt    string `json:"expires_at"`
+}
+
+type AuditLogEntryResponse struct {
+	ID            string  `json:"id"`
+	Action        string  `json:"action"`
+	ActorUserID   string  `json:"actor_user_id"`
+	SubjectUserID *string `json:"subject_user_id,omitempty"`
+	SessionID     *string `json:"session_id,omitempty"`
+	Details       string  `json:"details"`
+	RequestID     string  `json:"request_id"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+// StartImpersonation godoc
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token for acting as the user, to see what they see when helping them. The token's act claim names the admin, and every request made with it is written to the audit log under the admin. Unless allow_writes is set, the session is read-only: requests other than GET, HEAD and OPTIONS are refused. Admins can't impersonate themselves or other admins. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param userId path string true "User ID (UUID)"
+// @Param request body StartImpersonationRequest true "Why the user is being impersonated"
+// @Success 201 {object} response.Response{data=ImpersonationResponse} "Impersonation started successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin, or the user can't be impersonated"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/impersonate/{userId} [post]
+func (h *ImpersonationHandler) StartImpersonation(w http.ResponseWriter, r *http.Request) {
+	actorID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	targetID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req StartImpersonationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	session, token, err := h.impersonationService.StartImpersonation(r.Context(), actorID, targetID, req.Reason, req.AllowWrites, middleware.RequestID(r.Context()))
+	if err != nil {
+		response.FromError(w, err, "Failed to start impersonation")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, ImpersonationResponse{
+		SessionID:    session.ID.String(),
+		TargetUserID: session.TargetUserID.String(),
+		AccessToken:  token,
+		AllowWrites:  session.AllowWrites,
+		ExpiresAt:    formatTime(session.ExpiresAt),
+	})
+}
+
+// StopImpersonation godoc
+// @Summary Stop impersonating
+// @Description End the impersonation session the caller's token belongs to. The token is refused from then on. Call it with the impersonation token; read-only sessions may call it too.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response "Impersonation stopped successfully"
+// @Failure 400 {object} response.Response "Not an impersonation token"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/impersonation/stop [post]
+func (h *ImpersonationHandler) StopImpersonation(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := r.Context().Value(middleware.ImpersonationSessionKey).(uuid.UUID)
+	if !ok {
+		response.FromError(w, service.ErrNotImpersonating, "Not impersonating")
+		return
+	}
+
+	if err := h.impersonationService.StopImpersonation(r.Context(), sessionID, middleware.RequestID(r.Context())); err != nil {
+		response.FromError(w, err, "Failed to stop impersonation")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Impersonation stopped successfully"})
+}
+
+// ListAuditLog godoc
+// @Summary List audit log
+// @Description List audit log entries, newest first: impersonation sessions starting and stopping, and each request made while impersonating. user_id narrows it to entries where the user is the actor or the subject. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "User ID (UUID)"
+// @Param limit query int false "Maximum number of entries" default(20)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {object} response.Response{data=[]AuditLogEntryResponse} "Audit log retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/audit-log [get]
+func (h *ImpersonationHandler) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	var userID *uuid.UUID
+	if raw := r.URL.Query().Get("user_id"); raw != "" {
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(w, "Invalid user ID")
+			return
+		}
+		userID = &parsed
+	}
+	limit, offset := pageParams(r)
+
+	entries, err := h.impersonationService.ListAuditLog(r.Context(), userID, limit, offset)
+	if err != nil {
+		response.FromError(w, err, "Failed to list audit log")
+		return
+	}
+
+	entryResponses := make([]AuditLogEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entryResponses = append(entryResponses, FromAuditLogEntry(entry))
+	}
+
+	response.Success(w, http.StatusOK, entryResponses)
+}
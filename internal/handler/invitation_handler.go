@@ -2,14 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/yourusername/golf_messenger/internal/middleware"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 	"github.com/yourusername/golf_messenger/pkg/response"
 	"github.com/yourusername/golf_messenger/pkg/validator"
 )
@@ -25,30 +27,40 @@ func NewInvitationHandler(invitationService *service.InvitationService) *Invitat
 type CreateInvitationRequest struct {
 	TTRID         string `json:"ttr_id" validate:"required,uuid"`
 	InviteeUserID string `json:"invitee_user_id" validate:"required,uuid"`
-	Message       string `json:"message" validate:"omitempty"`
+	Message       string `json:"message" validate:"omitempty,max=1000"`
+	TemplateID    string `json:"template_id" validate:"omitempty,uuid"`
 }
 
 type RespondToInvitationRequest struct {
-	Status string `json:"status" validate:"required"`
+	Status        models.InvitationStatus `json:"status" validate:"required,invitation_response"`
+	DeclineReason *string                 `json:"decline_reason,omitempty" validate:"omitempty,max=280"`
 }
 
 type InvitationResponse struct {
-	ID            string        `json:"id"`
-	TTRID         string        `json:"ttr_id"`
-	InviterUserID string        `json:"inviter_user_id"`
-	InviteeUserID string        `json:"invitee_user_id"`
-	Status        string        `json:"status"`
-	Message       *string       `json:"message,omitempty"`
-	CreatedAt     string        `json:"created_at"`
-	RespondedAt   *string       `json:"responded_at,omitempty"`
-	TTR           *TTRResponse  `json:"ttr,omitempty"`
-	InviterUser   *UserResponse `json:"inviter_user,omitempty"`
-	InviteeUser   *UserResponse `json:"invitee_user,omitempty"`
+	ID                string                  `json:"id"`
+	TTRID             string                  `json:"ttr_id"`
+	InviterUserID     string                  `json:"inviter_user_id"`
+	InviteeUserID     string                  `json:"invitee_user_id"`
+	ForwardedByUserID *string                 `json:"forwarded_by_user_id,omitempty"`
+	Status            models.InvitationStatus `json:"status"`
+	Message           *string                 `json:"message,omitempty"`
+	DeclineReason     *string                 `json:"decline_reason,omitempty"`
+	CreatedAt         string                  `json:"created_at"`
+	RespondedAt       *string                 `json:"responded_at,omitempty"`
+	TTR               *TTRResponse            `json:"ttr,omitempty"`
+	InviterUser       *UserResponse           `json:"inviter_user,omitempty"`
+	InviteeUser       *UserResponse           `json:"invitee_user,omitempty"`
+	Acceptable        *bool                   `json:"acceptable,omitempty"`
+}
+
+type ForwardInvitationRequest struct {
+	InviteeUserID string `json:"invitee_user_id" validate:"required,uuid"`
+	Message       string `json:"message" validate:"omitempty,max=1000"`
 }
 
 // CreateInvitation godoc
 // @Summary Create invitation
-// @Description Send an invitation to a user to join a TTR. Only captain or co-captains can send invitations.
+// @Description Send an invitation to a user to join a TTR. Only captain or co-captains can send invitations. The message, given directly or as the template_id of a saved invitation template, can use {{course}}, {{date}}, {{time}} and {{captain}}, which are filled in from the TTR. A malformed message gets a 422 with the line and column of the problem in its details.
 // @Tags invitations
 // @Accept json
 // @Produce json
@@ -58,8 +70,8 @@ type InvitationResponse struct {
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
-// @Failure 404 {object} response.Response "TTR or user not found"
-// @Failure 422 {object} response.Response "Validation error"
+// @Failure 404 {object} response.Response "TTR, user or invitation template not found"
+// @Failure 422 {object} response.Response "Validation error or malformed message"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/invitations [post]
 func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
@@ -72,7 +84,7 @@ func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Requ
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
@@ -94,31 +106,33 @@ func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Requ
 		message = &req.Message
 	}
 
-	invitation, err := h.invitationService.CreateInvitation(ttrID, userID, inviteeUserID, message)
-	if err != nil {
-		if err.Error() == "TTR not found" || err.Error() == "invitee user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "unauthorized: only captain or co-captain can send invitations" {
-			response.Forbidden(w, err.Error())
+	var templateID *uuid.UUID
+	if req.TemplateID != "" {
+		if message != nil {
+			response.BadRequest(w, "Send either message or template_id, not both")
 			return
 		}
-		if err.Error() == "TTR is full" || err.Error() == "invitee is already a player in this TTR" || err.Error() == "pending invitation already exists for this user" {
-			response.BadRequest(w, err.Error())
+		id, err := uuid.Parse(req.TemplateID)
+		if err != nil {
+			response.BadRequest(w, "Invalid invitation template ID")
 			return
 		}
-		response.InternalServerError(w, "Failed to create invitation")
+		templateID = &id
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(r.Context(), ttrID, userID, inviteeUserID, message, templateID)
+	if err != nil {
+		handleMessageTemplateError(w, err, "Failed to create invitation")
 		return
 	}
 
-	invitationResp := convertInvitationToResponse(invitation)
+	invitationResp := FromInvitation(viewerFrom(r), invitation)
 	response.Success(w, http.StatusCreated, invitationResp)
 }
 
 // RespondToInvitation godoc
 // @Summary Respond to invitation
-// @Description Respond to a received invitation with YES, NO, or MAYBE
+// @Description Respond to a received invitation with YES, NO, or MAYBE. A MAYBE can later be changed to YES or NO; YES and NO are final. An optional decline_reason (up to 280 characters) can go with NO or MAYBE; it is sent to the inviter and shown to the TTR's captains, never to other players.
 // @Tags invitations
 // @Accept json
 // @Produce json
@@ -129,6 +143,7 @@ func (h *InvitationHandler) CreateInvitation(w http.ResponseWriter, r *http.Requ
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "Invitation not found"
+// @Failure 409 {object} response.Response "Already a player in the TTR"
 // @Failure 422 {object} response.Response "Validation error"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/invitations/{id}/respond [put]
@@ -150,36 +165,156 @@ func (h *InvitationHandler) RespondToInvitation(w http.ResponseWriter, r *http.R
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	invitation, err := h.invitationService.RespondToInvitation(invitationID, userID, req.Status)
+	invitation, err := h.invitationService.RespondToInvitation(r.Context(), invitationID, userID, req.Status, req.DeclineReason)
 	if err != nil {
-		if err.Error() == "invitation not found" || err.Error() == "TTR not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "unauthorized: you can only respond to your own invitations" {
-			response.Forbidden(w, err.Error())
-			return
-		}
-		if err.Error() == "invalid invitation status" || err.Error() == "invitation has already been responded to" || err.Error() == "TTR is full, cannot accept invitation" {
-			response.BadRequest(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to respond to invitation")
+		response.FromError(w, err, "Failed to respond to invitation")
 		return
 	}
 
-	invitationResp := convertInvitationToResponse(invitation)
+	invitationResp := FromInvitation(viewerFrom(r), invitation)
 	response.Success(w, http.StatusOK, invitationResp)
 }
 
+// ForwardInvitation godoc
+// @Summary Forward invitation
+// @Description Pass a pending invitation on to someone else when the invitee can't make it. The invitation is answered NO and a FORWARD_REQUESTED invitation to invitee_user_id is created; the captain is notified and must approve it before the new invitee hears about it. Forwarding to the captain or anyone else on the roster, or to a user the captain has blocked, is refused.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID (UUID)"
+// @Param request body ForwardInvitationRequest true "Who to forward the invitation to"
+// @Success 201 {object} response.Response{data=InvitationResponse} "Forward requested"
+// @Failure 400 {object} response.Response "Bad request, invitation already answered or target not allowed"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not the invitee"
+// @Failure 404 {object} response.Response "Invitation or user not found"
+// @Failure 409 {object} response.Response "Target already has an open invitation"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitations/{id}/forward [post]
+func (h *InvitationHandler) ForwardInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	invitationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation ID")
+		return
+	}
+
+	var req ForwardInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	targetUserID, err := uuid.Parse(req.InviteeUserID)
+	if err != nil {
+		response.BadRequest(w, "Invalid invitee user ID")
+		return
+	}
+
+	var message *string
+	if req.Message != "" {
+		message = &req.Message
+	}
+
+	invitation, err := h.invitationService.ForwardInvitation(r.Context(), invitationID, userID, targetUserID, message)
+	if err != nil {
+		response.FromError(w, err, "Failed to forward invitation")
+		return
+	}
+
+	invitationResp := FromInvitation(viewerFrom(r), invitation)
+	response.Success(w, http.StatusCreated, invitationResp)
+}
+
+// ApproveInvitation godoc
+// @Summary Approve forwarded invitation
+// @Description Approve a FORWARD_REQUESTED invitation, turning it into a pending invitation from the approver and notifying the new invitee. Only the captain and co-captains can approve; to turn a forward down, cancel the invitation.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation ID (UUID)"
+// @Success 200 {object} response.Response{data=InvitationResponse} "Invitation approved"
+// @Failure 400 {object} response.Response "Bad request, not a forwarded invitation or target no longer allowed"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "Invitation not found"
+// @Failure 409 {object} response.Response "Target already has an open invitation"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitations/{id}/approve [put]
+func (h *InvitationHandler) ApproveInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	invitationID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation ID")
+		return
+	}
+
+	invitation, err := h.invitationService.ApproveForwardedInvitation(r.Context(), invitationID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to approve invitation")
+		return
+	}
+
+	invitationResp := FromInvitation(viewerFrom(r), invitation)
+	response.Success(w, http.StatusOK, invitationResp)
+}
+
+// GetTTRInvitations godoc
+// @Summary List a TTR's invitations
+// @Description List every invitation sent for a TTR, newest first, with each invitee's answer and decline_reason. Only the captain and co-captains can see it.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=[]InvitationResponse} "Invitations retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/invitations [get]
+func (h *InvitationHandler) GetTTRInvitations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	invitations, err := h.invitationService.GetTTRInvitations(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get invitations")
+		return
+	}
+
+	v := viewerFrom(r)
+	invitationResponses := make([]InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		invitationResponses = append(invitationResponses, FromInvitation(v, invitation))
+	}
+
+	response.Success(w, http.StatusOK, invitationResponses)
+}
+
 // GetInvitation godoc
 // @Summary Get invitation by ID
-// @Description Get detailed information about a specific invitation
+// @Description Get detailed information about a specific invitation. Only the inviter, the invitee, and the TTR's captain and co-captains can read it.
 // @Tags invitations
 // @Produce json
 // @Security BearerAuth
@@ -191,6 +326,7 @@ func (h *InvitationHandler) RespondToInvitation(w http.ResponseWriter, r *http.R
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/invitations/{id} [get]
 func (h *InvitationHandler) GetInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
 	vars := mux.Vars(r)
 	idStr := vars["id"]
 
@@ -200,17 +336,13 @@ func (h *InvitationHandler) GetInvitation(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	invitation, err := h.invitationService.GetInvitation(invitationID)
+	invitation, err := h.invitationService.GetInvitation(r.Context(), invitationID, userID)
 	if err != nil {
-		if err.Error() == "invitation not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to get invitation")
+		response.FromError(w, err, "Failed to get invitation")
 		return
 	}
 
-	invitationResp := convertInvitationToResponse(invitation)
+	invitationResp := FromInvitation(viewerFrom(r), invitation)
 	response.Success(w, http.StatusOK, invitationResp)
 }
 
@@ -234,15 +366,16 @@ func (h *InvitationHandler) GetMyInvitations(w http.ResponseWriter, r *http.Requ
 		received = false
 	}
 
-	invitations, err := h.invitationService.GetUserInvitations(userID, received)
+	invitations, err := h.invitationService.GetUserInvitations(r.Context(), userID, received)
 	if err != nil {
-		response.InternalServerError(w, "Failed to get invitations")
+		response.FromError(w, err, "Failed to get invitations")
 		return
 	}
 
+	v := viewerFrom(r)
 	invitationResponses := make([]InvitationResponse, 0, len(invitations))
 	for _, invitation := range invitations {
-		invitationResponses = append(invitationResponses, convertInvitationToResponse(invitation))
+		invitationResponses = append(invitationResponses, FromInvitation(v, invitation))
 	}
 
 	response.Success(w, http.StatusOK, invitationResponses)
@@ -250,7 +383,7 @@ func (h *InvitationHandler) GetMyInvitations(w http.ResponseWriter, r *http.Requ
 
 // CancelInvitation godoc
 // @Summary Cancel invitation
-// @Description Cancel a pending invitation. Only the inviter can cancel.
+// @Description Cancel a pending invitation. Only the inviter can cancel. A forwarded invitation waiting for approval can also be cancelled by the captain and co-captains, which turns the forward down.
 // @Tags invitations
 // @Produce json
 // @Security BearerAuth
@@ -273,56 +406,25 @@ func (h *InvitationHandler) CancelInvitation(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := h.invitationService.CancelInvitation(invitationID, userID); err != nil {
-		if err.Error() == "invitation not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "unauthorized: only the inviter can cancel the invitation" {
-			response.Forbidden(w, err.Error())
-			return
-		}
-		if err.Error() == "only pending invitations can be canceled" {
-			response.BadRequest(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to cancel invitation")
+	if err := h.invitationService.CancelInvitation(r.Context(), invitationID, userID); err != nil {
+		response.FromError(w, err, "Failed to cancel invitation")
 		return
 	}
 
 	response.Success(w, http.StatusOK, map[string]string{"message": "Invitation canceled successfully"})
 }
 
-func convertInvitationToResponse(invitation *models.Invitation) InvitationResponse {
-	resp := InvitationResponse{
-		ID:            invitation.ID.String(),
-		TTRID:         invitation.TTRID.String(),
-		InviterUserID: invitation.InviterUserID.String(),
-		InviteeUserID: invitation.InviteeUserID.String(),
-		Status:        invitation.Status,
-		Message:       invitation.Message,
-		CreatedAt:     invitation.CreatedAt.Format(time.RFC3339),
-	}
-
-	if invitation.RespondedAt != nil {
-		respondedAt := invitation.RespondedAt.Format(time.RFC3339)
-		resp.RespondedAt = &respondedAt
-	}
-
-	if invitation.TTR != nil {
-		ttrResp := convertTTRToResponse(invitation.TTR)
-		resp.TTR = &ttrResp
-	}
-
-	if invitation.InviterUser != nil {
-		userResp := convertUserToResponse(invitation.InviterUser)
-		resp.InviterUser = &userResp
+// handleMessageTemplateError writes a malformed invitation message with where
+// the problem is, and any other error as FromError does.
+func handleMessageTemplateError(w http.ResponseWriter, err error, fallback string) {
+	var malformed *service.MessageTemplateError
+	if !errors.As(err, &malformed) {
+		response.FromError(w, err, fallback)
+		return
 	}
-
-	if invitation.InviteeUser != nil {
-		userResp := convertUserToResponse(invitation.InviteeUser)
-		resp.InviteeUser = &userResp
+	details := map[string]interface{}{"line": malformed.Line, "reason": malformed.Reason}
+	if malformed.Column > 0 {
+		details["column"] = malformed.Column
 	}
-
-	return resp
+	response.CodedWithDetails(w, errcode.InvalidMessageTemplate, service.ErrInvalidMessageTemplate.Message, details)
 }
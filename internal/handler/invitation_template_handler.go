@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type InvitationTemplateHandler struct {
+	templateService *service.InvitationTemplateService
+}
+
+func NewInvitationTemplateHandler(templateService *service.InvitationTemplateService) *InvitationTemplateHandler {
+	return &InvitationTemplateHandler{templateService: templateService}
+}
+
+type CreateInvitationTemplateRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Body string `json:"body" validate:"required,max=1000"`
+}
+
+type UpdateInvitationTemplateRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=100"`
+	Body *string `json:"body" validate:"omitempty,min=1,max=1000"`
+}
+
+type InvitationTemplateResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// CreateInvitationTemplate godoc
+// @Summary Create invitation template
+// @Description Save an invitation message to send again by its template_id. The body can use {{course}}, {{date}}, {{time}} and {{captain}}, filled in from the TTR when an invitation is sent. A malformed body gets a 422 with the line and column of the problem in its details.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateInvitationTemplateRequest true "Template details"
+// @Success 201 {object} response.Response{data=InvitationTemplateResponse} "Invitation template created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 422 {object} response.Response "Validation error or malformed body"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitation-templates [post]
+func (h *InvitationTemplateHandler) CreateInvitationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateInvitationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(r.Context(), userID, req.Name, req.Body)
+	if err != nil {
+		handleMessageTemplateError(w, err, "Failed to create invitation template")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromInvitationTemplate(template))
+}
+
+// ListInvitationTemplates godoc
+// @Summary List invitation templates
+// @Description List the caller's invitation templates by name.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]InvitationTemplateResponse} "Invitation templates retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitation-templates [get]
+func (h *InvitationTemplateHandler) ListInvitationTemplates(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	templates, err := h.templateService.ListTemplates(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to list invitation templates")
+		return
+	}
+
+	templateResponses := make([]InvitationTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		templateResponses = append(templateResponses, FromInvitationTemplate(template))
+	}
+
+	response.Success(w, http.StatusOK, templateResponses)
+}
+
+// GetInvitationTemplate godoc
+// @Summary Get invitation template by ID
+// @Description Get one of the caller's invitation templates.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation template ID (UUID)"
+// @Success 200 {object} response.Response{data=InvitationTemplateResponse} "Invitation template retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid invitation template ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Invitation template not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitation-templates/{id} [get]
+func (h *InvitationTemplateHandler) GetInvitationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	templateID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation template ID")
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(r.Context(), userID, templateID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get invitation template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromInvitationTemplate(template))
+}
+
+// UpdateInvitationTemplate godoc
+// @Summary Update invitation template
+// @Description Change the name or body of one of the caller's invitation templates. Invitations already sent with it keep their message.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation template ID (UUID)"
+// @Param request body UpdateInvitationTemplateRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=InvitationTemplateResponse} "Invitation template updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Invitation template not found"
+// @Failure 422 {object} response.Response "Validation error or malformed body"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitation-templates/{id} [put]
+func (h *InvitationTemplateHandler) UpdateInvitationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	templateID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation template ID")
+		return
+	}
+
+	var req UpdateInvitationTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(r.Context(), userID, templateID, req.Name, req.Body)
+	if err != nil {
+		handleMessageTemplateError(w, err, "Failed to update invitation template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromInvitationTemplate(template))
+}
+
+// DeleteInvitationTemplate godoc
+// @Summary Delete invitation template
+// @Description Delete one of the caller's invitation templates. Invitations already sent with it keep their message.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Invitation template ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Invitation template deleted successfully"
+// @Failure 400 {object} response.Response "Invalid invitation template ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Invitation template not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invitation-templates/{id} [delete]
+func (h *InvitationTemplateHandler) DeleteInvitationTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	templateID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation template ID")
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(r.Context(), userID, templateID); err != nil {
+		response.FromError(w, err, "Failed to delete invitation template")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Invitation template deleted successfully"})
+}
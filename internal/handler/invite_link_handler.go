@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type InviteLinkHandler struct {
+	inviteLinkService *service.InviteLinkService
+}
+
+func NewInviteLinkHandler(inviteLinkService *service.InviteLinkService) *InviteLinkHandler {
+	return &InviteLinkHandler{inviteLinkService: inviteLinkService}
+}
+
+type CreateInviteLinkRequest struct {
+	MaxUses   int    `json:"max_uses" validate:"required,min=1,max=8"`
+	ExpiresAt string `json:"expires_at" validate:"omitempty"`
+}
+
+type InviteLinkResponse struct {
+	ID              string  `json:"id"`
+	TTRID           string  `json:"ttr_id"`
+	Token           string  `json:"token"`
+	CreatedByUserID string  `json:"created_by_user_id"`
+	MaxUses         int     `json:"max_uses"`
+	Uses            int     `json:"uses"`
+	ExpiresAt       *string `json:"expires_at,omitempty"`
+	RevokedAt       *string `json:"revoked_at,omitempty"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+type InviteLinkPreviewResponse struct {
+	CourseName     string  `json:"course_name"`
+	CourseLocation *string `json:"course_location,omitempty"`
+	TeeDate        string  `json:"tee_date"`
+	TeeTime        string  `json:"tee_time"`
+	CaptainName    string  `json:"captain_name"`
+	OpenSlots      int     `json:"open_slots"`
+	RemainingUses  int     `json:"remaining_uses"`
+	ExpiresAt      *string `json:"expires_at,omitempty"`
+	Usable         bool    `json:"usable"`
+}
+
+// CreateInviteLink godoc
+// @Summary Create invite link
+// @Description Create a shareable link the first max_uses (1 to 8) users to accept can join the TTR through, first come first served. An optional expires_at (RFC3339, in the future) stops the link working once it passes. Only the captain and co-captains can create links, and only for an open TTR whose tee time hasn't passed.
+// @Tags ttrs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body CreateInviteLinkRequest true "Invite link details"
+// @Success 201 {object} response.Response{data=InviteLinkResponse} "Invite link created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/invite-links [post]
+func (h *InviteLinkHandler) CreateInviteLink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req CreateInviteLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			response.BadRequest(w, "Invalid expires_at format, expected RFC3339")
+			return
+		}
+		expiresAt = &parsed
+	}
+
+	link, err := h.inviteLinkService.CreateInviteLink(r.Context(), ttrID, userID, req.MaxUses, expiresAt)
+	if err != nil {
+		response.FromError(w, err, "Failed to create invite link")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromInviteLink(link))
+}
+
+// ListInviteLinks godoc
+// @Summary List invite links
+// @Description List the TTR's invite links, revoked ones included, newest first. Only the captain and co-captains can list them.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=[]InviteLinkResponse} "Invite links retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/invite-links [get]
+func (h *InviteLinkHandler) ListInviteLinks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	links, err := h.inviteLinkService.ListInviteLinks(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get invite links")
+		return
+	}
+
+	linkResponses := make([]InviteLinkResponse, 0, len(links))
+	for _, link := range links {
+		linkResponses = append(linkResponses, FromInviteLink(link))
+	}
+
+	response.Success(w, http.StatusOK, linkResponses)
+}
+
+// RevokeInviteLink godoc
+// @Summary Revoke invite link
+// @Description Stop an invite link from being accepted. Players who already joined through it stay on the roster. Revoking a revoked link succeeds and changes nothing. Only the captain and co-captains can revoke links.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param linkId path string true "Invite link ID (UUID)"
+// @Success 200 {object} response.Response{data=InviteLinkResponse} "Invite link revoked successfully"
+// @Failure 400 {object} response.Response "Invalid TTR or invite link ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR or invite link not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/invite-links/{linkId} [delete]
+func (h *InviteLinkHandler) RevokeInviteLink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	linkID, err := uuid.Parse(vars["linkId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invite link ID")
+		return
+	}
+
+	link, err := h.inviteLinkService.RevokeInviteLink(r.Context(), ttrID, linkID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to revoke invite link")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromInviteLink(link))
+}
+
+// PreviewInviteLink godoc
+// @Summary Preview invite link
+// @Description Show the tee time behind an invite link without signing in: course, tee date and time, the captain's name, open slots and the link's remaining uses. usable is false when accepting would be refused.
+// @Tags invite-links
+// @Produce json
+// @Param token path string true "Invite link token"
+// @Success 200 {object} response.Response{data=InviteLinkPreviewResponse} "Invite link retrieved successfully"
+// @Failure 404 {object} response.Response "Invite link not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/public/invite-links/{token} [get]
+func (h *InviteLinkHandler) PreviewInviteLink(w http.ResponseWriter, r *http.Request) {
+	preview, err := h.inviteLinkService.PreviewInviteLink(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		response.FromError(w, err, "Failed to get invite link")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromInviteLinkPreview(preview))
+}
+
+// AcceptInviteLink godoc
+// @Summary Accept invite link
+// @Description Join the TTR behind an invite link as a confirmed player. Taking one of the link's uses and a slot on the TTR happen together, so a link is never used more than max_uses times and the TTR never goes over max_players, however many users accept at once.
+// @Tags invite-links
+// @Produce json
+// @Security BearerAuth
+// @Param token path string true "Invite link token"
+// @Success 200 {object} response.Response{data=TTRResponse} "Joined TTR successfully"
+// @Failure 400 {object} response.Response "Link revoked or expired, TTR full, closed or already teed off"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Invite link not found"
+// @Failure 409 {object} response.Response "Already a player, or no uses left"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/invite-links/{token}/accept [post]
+func (h *InviteLinkHandler) AcceptInviteLink(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttr, err := h.inviteLinkService.AcceptInviteLink(r.Context(), mux.Vars(r)["token"], userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to accept invite link")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTTR(viewerFrom(r), ttr))
+}
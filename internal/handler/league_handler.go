@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type LeagueHandler struct {
+	leagueService *service.LeagueService
+}
+
+func NewLeagueHandler(leagueService *service.LeagueService) *LeagueHandler {
+	return &LeagueHandler{leagueService: leagueService}
+}
+
+type CreateLeagueRequest struct {
+	Name           string `json:"name" validate:"required,min=2,max=255"`
+	OrganizationID string `json:"organization_id" validate:"omitempty,uuid"`
+	StartDate      string `json:"start_date" validate:"required"`
+	EndDate        string `json:"end_date" validate:"required"`
+	ScoringScheme  string `json:"scoring_scheme" validate:"required,oneof=stroke_play points_per_finish"`
+}
+
+type AttachLeagueTTRRequest struct {
+	TTRID string `json:"ttr_id" validate:"required,uuid"`
+}
+
+type RecordScoreRequest struct {
+	Score int `json:"score" validate:"required,min=1"`
+}
+
+type LeagueResponse struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	OwnerUserID    string  `json:"owner_user_id"`
+	OrganizationID *string `json:"organization_id,omitempty"`
+	StartDate      string  `json:"start_date"`
+	EndDate        string  `json:"end_date"`
+	ScoringScheme  string  `json:"scoring_scheme"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+type LeagueStandingResponse struct {
+	Position     int    `json:"position"`
+	UserID       string `json:"user_id"`
+	Rounds       int    `json:"rounds"`
+	TotalStrokes int    `json:"total_strokes"`
+	Points       int    `json:"points"`
+}
+
+// CreateLeague godoc
+// @Summary Create league
+// @Description Create a league season. The creator becomes its owner. An optional organization_id limits the league to that organization's members; only its owners and admins can create one. scoring_scheme is stroke_play (fewest total strokes) or points_per_finish (points per round by finishing position).
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateLeagueRequest true "League details"
+// @Success 201 {object} response.Response{data=LeagueResponse} "League created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an organization owner or admin"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/leagues [post]
+func (h *LeagueHandler) CreateLeague(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateLeagueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		response.BadRequest(w, "Invalid start_date format, expected YYYY-MM-DD")
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		response.BadRequest(w, "Invalid end_date format, expected YYYY-MM-DD")
+		return
+	}
+
+	var organizationID *uuid.UUID
+	if req.OrganizationID != "" {
+		parsed, err := uuid.Parse(req.OrganizationID)
+		if err != nil {
+			response.BadRequest(w, "Invalid organization_id")
+			return
+		}
+		organizationID = &parsed
+	}
+
+	league, err := h.leagueService.CreateLeague(r.Context(), userID, req.Name, organizationID, startDate, endDate, req.ScoringScheme)
+	if err != nil {
+		response.FromError(w, err, "Failed to create league")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromLeague(league))
+}
+
+// GetLeague godoc
+// @Summary Get league by ID
+// @Description Get a league. Organization leagues are only visible to the organization's members.
+// @Tags leagues
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "League ID (UUID)"
+// @Success 200 {object} response.Response{data=LeagueResponse} "League retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid league ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "League not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/leagues/{id} [get]
+func (h *LeagueHandler) GetLeague(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	leagueID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid league ID")
+		return
+	}
+
+	league, err := h.leagueService.GetLeague(r.Context(), leagueID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get league")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromLeague(league))
+}
+
+// AttachTTR godoc
+// @Summary Attach TTR to league
+// @Description Count a completed TTR towards the league. Only the league owner can attach TTRs, the TTR must have been played during the league's season, and a TTR can belong to one league only.
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "League ID (UUID)"
+// @Param request body AttachLeagueTTRRequest true "TTR to attach"
+// @Success 200 {object} response.Response "TTR attached successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not the league owner"
+// @Failure 404 {object} response.Response "League or TTR not found"
+// @Failure 409 {object} response.Response "TTR already belongs to a league"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/leagues/{id}/ttrs [post]
+func (h *LeagueHandler) AttachTTR(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	leagueID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid league ID")
+		return
+	}
+
+	var req AttachLeagueTTRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	ttrID, err := uuid.Parse(req.TTRID)
+	if err != nil {
+		response.BadRequest(w, "Invalid ttr_id")
+		return
+	}
+
+	if err := h.leagueService.AttachTTR(r.Context(), leagueID, ttrID, userID); err != nil {
+		response.FromError(w, err, "Failed to attach TTR")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "TTR attached successfully"})
+}
+
+// GetStandings godoc
+// @Summary Get league standings
+// @Description Get the league's standings from the recorded scores of its TTRs. Tied players share a position.
+// @Tags leagues
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "League ID (UUID)"
+// @Success 200 {object} response.Response{data=[]LeagueStandingResponse} "Standings retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid league ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "League not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/leagues/{id}/standings [get]
+func (h *LeagueHandler) GetStandings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	leagueID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid league ID")
+		return
+	}
+
+	standings, err := h.leagueService.GetStandings(r.Context(), leagueID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get standings")
+		return
+	}
+
+	standingResponses := make([]LeagueStandingResponse, 0, len(standings))
+	for _, standing := range standings {
+		standingResponses = append(standingResponses, LeagueStandingResponse{
+			Position:     standing.Position,
+			UserID:       standing.UserID.String(),
+			Rounds:       standing.Rounds,
+			TotalStrokes: standing.TotalStrokes,
+			Points:       standing.Points,
+		})
+	}
+
+	response.Success(w, http.StatusOK, standingResponses)
+}
+
+// RecordScore godoc
+// @Summary Record player score
+// @Description Record a player's gross score for a completed TTR. Only the captain, co-captains and organization admins can record scores.
+// @Tags leagues
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param userId path string true "Player user ID (UUID)"
+// @Param request body RecordScoreRequest true "Gross score"
+// @Success 200 {object} response.Response "Score recorded successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR or player not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/scores/{userId} [put]
+func (h *LeagueHandler) RecordScore(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	vars := mux.Vars(r)
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	playerUserID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req RecordScoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	if err := h.leagueService.RecordScore(r.Context(), ttrID, userID, playerUserID, req.Score); err != nil {
+		response.FromError(w, err, "Failed to record score")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Score recorded successfully"})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+}
+
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{maintenanceService: maintenanceService}
+}
+
+type SetMaintenanceRequest struct {
+	Enabled bool   `json:"enabled"`
+	EndsAt  string `json:"ends_at" validate:"omitempty"`
+}
+
+type MaintenanceResponse struct {
+	Enabled bool    `json:"enabled"`
+	EndsAt  *string `json:"ends_at,omitempty"`
+}
+
+// GetMaintenance godoc
+// @Summary Get maintenance mode
+// @Description Get whether the API is down for maintenance. Admin only; reachable during maintenance.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=MaintenanceResponse} "Maintenance mode retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Router /api/v1/admin/maintenance [get]
+func (h *MaintenanceHandler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, http.StatusOK, FromMaintenanceState(h.maintenanceService.State(r.Context())))
+}
+
+// SetMaintenance godoc
+// @Summary Set maintenance mode
+// @Description Turn maintenance mode on or off for every instance. While it is on, every route but /healthz, /readyz and this one answers 503 MAINTENANCE, and /readyz reports not ready so load balancers drain. ends_at (RFC3339) is the estimated end shown to clients. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SetMaintenanceRequest true "Maintenance mode"
+// @Success 200 {object} response.Response{data=MaintenanceResponse} "Maintenance mode updated successfully"
+// @Failure 400 {object} response.Response "Invalid ends_at"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/maintenance [put]
+func (h *MaintenanceHandler) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var req SetMaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	state := service.MaintenanceState{Enabled: req.Enabled}
+	if req.EndsAt != "" {
+		endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+		if err != nil {
+			response.BadRequest(w, "Invalid ends_at format, expected RFC3339")
+			return
+		}
+		state.EndsAt = &endsAt
+	}
+
+	state, err := h.maintenanceService.SetState(r.Context(), state)
+	if err != nil {
+		response.FromError(w, err, "Failed to update maintenance mode")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromMaintenanceState(state))
+}
@@ -0,0 +1,502 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type MessageHandler struct {
+	messageService *service.MessageService
+}
+
+func NewMessageHandler(messageService *service.MessageService) *MessageHandler {
+	return &MessageHandler{messageService: messageService}
+}
+
+type PostMessageRequest struct {
+	Body string `json:"body" validate:"required,max=4000"`
+}
+
+type UpdateMessageRequest struct {
+	Body string `json:"body" validate:"required,max=4000"`
+}
+
+type ReactionRequest struct {
+	Emoji string `json:"emoji" validate:"required"`
+}
+
+type MarkMessagesReadRequest struct {
+	MessageID string `json:"message_id" validate:"required,uuid"`
+}
+
+type MessageResponse struct {
+	ID         string                     `json:"id"`
+	TTRID      string                     `json:"ttr_id"`
+	UserID     string                     `json:"user_id"`
+	Body       string                     `json:"body"`
+	EditedAt   *string                    `json:"edited_at,omitempty"`
+	Deleted    bool                       `json:"deleted"`
+	CreatedAt  string                     `json:"created_at"`
+	UpdatedAt  string                     `json:"updated_at"`
+	User       *UserResponse              `json:"user,omitempty"`
+	Attachment *MessageAttachmentResponse `json:"attachment,omitempty"`
+	Reactions  []MessageReactionResponse  `json:"reactions"`
+}
+
+type MessageAttachmentResponse struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+}
+
+type MessageReactionResponse struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+// PostMessage godoc
+// @Summary Post a message to a TTR
+// @Description Post a chat message to a TTR. Only the captain, co-captains and players can post.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body PostMessageRequest true "Message details"
+// @Success 201 {object} response.Response{data=MessageResponse} "Message posted successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages [post]
+func (h *MessageHandler) PostMessage(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req PostMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	message, err := h.messageService.PostMessage(r.Context(), ttrID, userID, req.Body)
+	if err != nil {
+		response.FromError(w, err, "Failed to post message")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromMessage(viewerFrom(r), message))
+}
+
+// PostAttachment godoc
+// @Summary Post an attachment to a TTR
+// @Description Upload an image or PDF to a TTR's chat as a message with an optional caption. Uploads count against the TTR's and the uploader's attachment quotas.
+// @Tags messages
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param file formData file true "Image (JPEG, PNG, GIF, WebP) or PDF"
+// @Param body formData string false "Caption"
+// @Success 201 {object} response.Response{data=MessageResponse} "Attachment posted successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 413 {object} response.Response "Attachment too large or quota exceeded"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/attachments [post]
+func (h *MessageHandler) PostAttachment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	// Leave headroom over the file limit for the multipart framing and caption.
+	r.Body = http.MaxBytesReader(w, r.Body, h.messageService.AttachmentMaxSize()+1<<20)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.Coded(w, errcode.AttachmentTooLarge, "attachment is too large")
+			return
+		}
+		response.BadRequest(w, "Failed to parse form data")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.BadRequest(w, "Attachment file is required")
+		return
+	}
+	defer file.Close()
+
+	caption := r.FormValue("body")
+	if len(caption) > 4000 {
+		response.BadRequest(w, "Caption must be at most 4000 characters")
+		return
+	}
+
+	contentType := header.Header.Get("Content-Type")
+	message, err := h.messageService.PostAttachment(r.Context(), ttrID, userID, caption, file, header.Filename, contentType, header.Size)
+	if err != nil {
+		response.FromError(w, err, "Failed to post attachment")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromMessage(viewerFrom(r), message))
+}
+
+// GetMessages godoc
+// @Summary List TTR messages
+// @Description Get a page of a TTR's chat messages, newest first. Only the captain, co-captains and players can read them.
+// @Tags messages
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param limit query int false "Results limit" default(50)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} response.Response{data=[]MessageResponse} "Messages retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages [get]
+func (h *MessageHandler) GetMessages(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 50
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	offsetStr := r.URL.Query().Get("offset")
+	offset := 0
+	if offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	messages, err := h.messageService.GetMessages(r.Context(), ttrID, userID, limit, offset)
+	if err != nil {
+		response.FromError(w, err, "Failed to get messages")
+		return
+	}
+
+	v := viewerFrom(r)
+	messageResponses := make([]MessageResponse, 0, len(messages))
+	for _, message := range messages {
+		messageResponses = append(messageResponses, FromMessage(v, message))
+	}
+
+	response.Success(w, http.StatusOK, messageResponses)
+}
+
+// UpdateMessage godoc
+// @Summary Edit a TTR message
+// @Description Replace the body of a message. Only the author can edit, within the configured edit window after posting.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param messageId path string true "Message ID (UUID)"
+// @Param request body UpdateMessageRequest true "New message body"
+// @Success 200 {object} response.Response{data=MessageResponse} "Message updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not the author or edit window expired"
+// @Failure 404 {object} response.Response "Message not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/{messageId} [put]
+func (h *MessageHandler) UpdateMessage(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(vars["messageId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid message ID")
+		return
+	}
+
+	var req UpdateMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	message, err := h.messageService.EditMessage(r.Context(), ttrID, messageID, userID, req.Body)
+	if err != nil {
+		response.FromError(w, err, "Failed to update message")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromMessage(viewerFrom(r), message))
+}
+
+// DeleteMessage godoc
+// @Summary Delete a TTR message
+// @Description Delete a message. The author can delete their own messages and the captain or co-captains can delete any message. The message stays in the thread with its body cleared and deleted set.
+// @Tags messages
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param messageId path string true "Message ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Message deleted successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not the author, captain or co-captain"
+// @Failure 404 {object} response.Response "Message not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/{messageId} [delete]
+func (h *MessageHandler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(vars["messageId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid message ID")
+		return
+	}
+
+	if err := h.messageService.DeleteMessage(r.Context(), ttrID, messageID, userID); err != nil {
+		response.FromError(w, err, "Failed to delete message")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Message deleted successfully"})
+}
+
+// AddReaction godoc
+// @Summary React to a TTR message
+// @Description Add an emoji reaction to a message. Emoji must be one of the supported shortcodes (thumbsup, thumbsdown, heart, laughing, tada, golf, clap, eyes, question, pray). Adding the same reaction again has no effect.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param messageId path string true "Message ID (UUID)"
+// @Param request body ReactionRequest true "Reaction"
+// @Success 200 {object} response.Response{data=map[string]string} "Reaction added"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR or message not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/{messageId}/reactions [post]
+func (h *MessageHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(vars["messageId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid message ID")
+		return
+	}
+
+	var req ReactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	if err := h.messageService.AddReaction(r.Context(), ttrID, messageID, userID, req.Emoji); err != nil {
+		response.FromError(w, err, "Failed to add reaction")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Reaction added"})
+}
+
+// RemoveReaction godoc
+// @Summary Remove a reaction from a TTR message
+// @Description Remove the current user's emoji reaction from a message. Removing a reaction that doesn't exist has no effect.
+// @Tags messages
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param messageId path string true "Message ID (UUID)"
+// @Param emoji query string true "Emoji shortcode"
+// @Success 200 {object} response.Response{data=map[string]string} "Reaction removed"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR or message not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/{messageId}/reactions [delete]
+func (h *MessageHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	messageID, err := uuid.Parse(vars["messageId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid message ID")
+		return
+	}
+
+	emoji := r.URL.Query().Get("emoji")
+
+	if err := h.messageService.RemoveReaction(r.Context(), ttrID, messageID, userID, emoji); err != nil {
+		response.FromError(w, err, "Failed to remove reaction")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Reaction removed"})
+}
+
+// MarkMessagesRead godoc
+// @Summary Mark TTR messages as read
+// @Description Mark every message in the TTR up to and including the given message as read for the current user. Marking an older message than the current read position has no effect.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body MarkMessagesReadRequest true "Last read message"
+// @Success 200 {object} response.Response{data=map[string]string} "Messages marked as read"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a TTR player"
+// @Failure 404 {object} response.Response "TTR or message not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/messages/read [post]
+func (h *MessageHandler) MarkMessagesRead(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req MarkMessagesReadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	messageID, _ := uuid.Parse(req.MessageID)
+
+	if err := h.messageService.MarkRead(r.Context(), ttrID, userID, messageID); err != nil {
+		response.FromError(w, err, "Failed to mark messages as read")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Messages marked as read"})
+}
+
+// GetUnreadCounts godoc
+// @Summary Get unread message counts
+// @Description Get the number of unread messages for each TTR the current user plays in, keyed by TTR ID. TTRs with no unread messages are omitted.
+// @Tags messages
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=map[string]int64} "Unread counts retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/me/unread-counts [get]
+func (h *MessageHandler) GetUnreadCounts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	counts, err := h.messageService.GetUnreadCounts(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get unread counts")
+		return
+	}
+
+	countsResp := make(map[string]int64, len(counts))
+	for ttrID, count := range counts {
+		countsResp[ttrID.String()] = count
+	}
+
+	response.Success(w, http.StatusOK, countsResp)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+type MetaHandler struct{}
+
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+type ErrorCodeResponse struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+}
+
+// ListErrors godoc
+// @Summary List error codes
+// @Description List every error code the API can return in error.code, with the HTTP status it is sent with. Meant for client generators.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} response.Response{data=[]ErrorCodeResponse} "Error codes retrieved successfully"
+// @Router /api/v1/meta/errors [get]
+func (h *MetaHandler) ListErrors(w http.ResponseWriter, r *http.Request) {
+	definitions := errcode.All()
+	codes := make([]ErrorCodeResponse, 0, len(definitions))
+	for _, definition := range definitions {
+		codes = append(codes, ErrorCodeResponse{
+			Code:        string(definition.Code),
+			Status:      definition.Status,
+			Description: definition.Description,
+		})
+	}
+
+	response.Success(w, http.StatusOK, codes)
+}
+
+// ListScopes godoc
+// @Summary List token scopes
+// @Description List every scope an API token can be given. Read scopes cover a group's GET routes and write scopes the routes that change something; admin lets a token reach the admin routes, which also need the ADMIN role.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} response.Response{data=[]string} "Scopes retrieved successfully"
+// @Router /api/v1/meta/scopes [get]
+func (h *MetaHandler) ListScopes(w http.ResponseWriter, r *http.Request) {
+	scopes := scope.All()
+	names := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		names = append(names, string(s))
+	}
+
+	response.Success(w, http.StatusOK, names)
+}
+
+// RouteResponse is a route as GET /meta/routes lists it. Middleware lists
+// its group's chain, outermost first; auth is whether that chain
+// authenticates the caller.
+type RouteResponse struct {
+	Methods    []string `json:"methods"`
+	Path       string   `json:"path"`
+	Group      string   `json:"group,omitempty"`
+	Middleware []string `json:"middleware"`
+	Auth       bool     `json:"auth"`
+	Scope      string   `json:"scope,omitempty"`
+	Public     bool     `json:"public"`
+}
+
+// ListRoutes godoc
+// @Summary List routes
+// @Description List every route with its path template, the middleware of its group and the scope it requires. Only mounted when API_LIST_ROUTES is set, for debugging.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} response.Response{data=[]RouteResponse} "Routes retrieved successfully"
+// @Failure 404 {object} response.Response "Route listing is off"
+// @Router /api/v1/meta/routes [get]
+func (h *MetaHandler) ListRoutes(routes func() []RouteResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response.Success(w, http.StatusOK, routes())
+	}
+}
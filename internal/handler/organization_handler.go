@@ -0,0 +1,504 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+type CreateOrganizationRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=255"`
+	Description string `json:"description" validate:"omitempty,max=2000"`
+}
+
+type UpdateOrganizationRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=2,max=255"`
+	Description *string `json:"description" validate:"omitempty,max=2000"`
+}
+
+type InviteOrganizationMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=admin member"`
+}
+
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin member"`
+}
+
+type RespondToOrganizationInvitationRequest struct {
+	Accept *bool `json:"accept" validate:"required"`
+}
+
+type OrganizationResponse struct {
+	ID              string  `json:"id"`
+	Name            string  `json:"name"`
+	Description     *string `json:"description,omitempty"`
+	CreatedByUserID string  `json:"created_by_user_id"`
+	CreatedAt       string  `json:"created_at"`
+	UpdatedAt       string  `json:"updated_at"`
+}
+
+type OrganizationMemberResponse struct {
+	OrganizationID string        `json:"organization_id"`
+	UserID         string        `json:"user_id"`
+	Role           string        `json:"role"`
+	JoinedAt       string        `json:"joined_at"`
+	User           *UserResponse `json:"user,omitempty"`
+}
+
+type OrganizationInvitationResponse struct {
+	ID              string                `json:"id"`
+	OrganizationID  string                `json:"organization_id"`
+	Email           string                `json:"email"`
+	Role            string                `json:"role"`
+	InvitedByUserID string                `json:"invited_by_user_id"`
+	Status          string                `json:"status"`
+	CreatedAt       string                `json:"created_at"`
+	RespondedAt     *string               `json:"responded_at,omitempty"`
+	Organization    *OrganizationResponse `json:"organization,omitempty"`
+}
+
+// CreateOrganization godoc
+// @Summary Create organization
+// @Description Create a club organization. The creator becomes its owner.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOrganizationRequest true "Organization details"
+// @Success 201 {object} response.Response{data=OrganizationResponse} "Organization created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs [post]
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	var description *string
+	if req.Description != "" {
+		description = &req.Description
+	}
+
+	org, err := h.orgService.CreateOrganization(r.Context(), userID, req.Name, description)
+	if err != nil {
+		response.FromError(w, err, "Failed to create organization")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromOrganization(org))
+}
+
+// GetMyOrganizations godoc
+// @Summary List my organizations
+// @Description Get the organizations the current user is a member of
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]OrganizationResponse} "Organizations retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs [get]
+func (h *OrganizationHandler) GetMyOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgs, err := h.orgService.GetUserOrganizations(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get organizations")
+		return
+	}
+
+	orgResponses := make([]OrganizationResponse, 0, len(orgs))
+	for _, org := range orgs {
+		orgResponses = append(orgResponses, FromOrganization(org))
+	}
+
+	response.Success(w, http.StatusOK, orgResponses)
+}
+
+// GetOrganization godoc
+// @Summary Get organization by ID
+// @Description Get an organization. Only its members can see it.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Success 200 {object} response.Response{data=OrganizationResponse} "Organization retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid organization ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id} [get]
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	org, err := h.orgService.GetOrganization(r.Context(), orgID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get organization")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromOrganization(org))
+}
+
+// UpdateOrganization godoc
+// @Summary Update organization
+// @Description Update an organization's name or description. Only owners and admins can update it.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Param request body UpdateOrganizationRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=OrganizationResponse} "Organization updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not owner or admin"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	var req UpdateOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	org, err := h.orgService.UpdateOrganization(r.Context(), orgID, userID, req.Name, req.Description)
+	if err != nil {
+		response.FromError(w, err, "Failed to update organization")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromOrganization(org))
+}
+
+// DeleteOrganization godoc
+// @Summary Delete organization
+// @Description Delete an organization. Only the owner can delete it; its TTRs stay private to the former members.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Organization deleted successfully"
+// @Failure 400 {object} response.Response "Invalid organization ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not owner"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id} [delete]
+func (h *OrganizationHandler) DeleteOrganization(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	if err := h.orgService.DeleteOrganization(r.Context(), orgID, userID); err != nil {
+		response.FromError(w, err, "Failed to delete organization")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Organization deleted successfully"})
+}
+
+// GetMembers godoc
+// @Summary List organization members
+// @Description Get an organization's members and their roles. Only members can see them.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Success 200 {object} response.Response{data=[]OrganizationMemberResponse} "Members retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid organization ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id}/members [get]
+func (h *OrganizationHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	members, err := h.orgService.GetMembers(r.Context(), orgID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get members")
+		return
+	}
+
+	v := viewerFrom(r)
+	memberResponses := make([]OrganizationMemberResponse, 0, len(members))
+	for _, member := range members {
+		memberResponses = append(memberResponses, FromOrganizationMember(v, member))
+	}
+
+	response.Success(w, http.StatusOK, memberResponses)
+}
+
+// UpdateMember godoc
+// @Summary Change a member's role
+// @Description Change a member's role to admin or member. Only the owner can change roles.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Param userId path string true "Member user ID (UUID)"
+// @Param request body UpdateOrganizationMemberRequest true "New role"
+// @Success 200 {object} response.Response{data=map[string]string} "Role updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not owner"
+// @Failure 404 {object} response.Response "Organization or member not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id}/members/{userId} [put]
+func (h *OrganizationHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	orgID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	memberUserID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	if err := h.orgService.UpdateMemberRole(r.Context(), orgID, userID, memberUserID, req.Role); err != nil {
+		response.FromError(w, err, "Failed to update member role")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Member role updated successfully"})
+}
+
+// RemoveMember godoc
+// @Summary Remove organization member
+// @Description Remove a member from the organization. Members can remove themselves (except the owner); owners and admins can remove members, and only the owner can remove admins.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Param userId path string true "Member user ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Member removed successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Organization or member not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id}/members/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	orgID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	memberUserID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.orgService.RemoveMember(r.Context(), orgID, userID, memberUserID); err != nil {
+		response.FromError(w, err, "Failed to remove member")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Member removed successfully"})
+}
+
+// InviteMember godoc
+// @Summary Invite a member by email
+// @Description Invite an email address to join the organization as a member or admin. Owners and admins can invite members; only the owner can invite admins. The address doesn't need an account yet.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Organization ID (UUID)"
+// @Param request body InviteOrganizationMemberRequest true "Invitee email and role"
+// @Success 201 {object} response.Response{data=OrganizationInvitationResponse} "Invitation created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 409 {object} response.Response "Already a member or already invited"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	orgID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid organization ID")
+		return
+	}
+
+	var req InviteOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	invitation, err := h.orgService.InviteMember(r.Context(), orgID, userID, req.Email, req.Role)
+	if err != nil {
+		response.FromError(w, err, "Failed to invite member")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromOrganizationInvitation(invitation))
+}
+
+// GetMyInvitations godoc
+// @Summary List my organization invitations
+// @Description Get the pending organization invitations addressed to the current user's email
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]OrganizationInvitationResponse} "Invitations retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/invitations/me [get]
+func (h *OrganizationHandler) GetMyInvitations(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	invitations, err := h.orgService.GetUserInvitations(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get invitations")
+		return
+	}
+
+	invitationResponses := make([]OrganizationInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		invitationResponses = append(invitationResponses, FromOrganizationInvitation(invitation))
+	}
+
+	response.Success(w, http.StatusOK, invitationResponses)
+}
+
+// RespondToInvitation godoc
+// @Summary Respond to organization invitation
+// @Description Accept or decline an organization invitation addressed to the current user's email. Accepting joins the organization with the invited role.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param invitationId path string true "Invitation ID (UUID)"
+// @Param request body RespondToOrganizationInvitationRequest true "Accept or decline"
+// @Success 200 {object} response.Response{data=OrganizationInvitationResponse} "Response recorded successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Invitation not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/orgs/invitations/{invitationId}/respond [put]
+func (h *OrganizationHandler) RespondToInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	invitationID, err := uuid.Parse(mux.Vars(r)["invitationId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid invitation ID")
+		return
+	}
+
+	var req RespondToOrganizationInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	invitation, err := h.orgService.RespondToInvitation(r.Context(), invitationID, userID, *req.Accept)
+	if err != nil {
+		response.FromError(w, err, "Failed to respond to invitation")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromOrganizationInvitation(invitation))
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// PageResponse is the v2 envelope for list endpoints. NextOffset is set when
+// more items follow; pass it back as offset to fetch them.
+type PageResponse[T any] struct {
+	Items      []T  `json:"items"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// pageParams reads the limit and offset query parameters, falling back to 20
+// and 0 for missing or invalid values.
+func pageParams(r *http.Request) (int, int) {
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	offset := 0
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset
+}
+
+// newPage builds a page from items fetched with limit+1, so an extra item
+// tells that another page follows.
+func newPage[T any](items []T, limit int, offset int) PageResponse[T] {
+	page := PageResponse[T]{Items: items, Limit: limit, Offset: offset}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		next := offset + limit
+		page.NextOffset = &next
+	}
+	return page
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type PairingHandler struct {
+	pairingService *service.PairingService
+}
+
+func NewPairingHandler(pairingService *service.PairingService) *PairingHandler {
+	return &PairingHandler{pairingService: pairingService}
+}
+
+// SuggestedPairingsResponse can be sent back as is to PUT /ttrs/{id}/pairings:
+// pairings maps user IDs to group numbers, and groups spells the same groups
+// out with their handicap totals.
+type SuggestedPairingsResponse struct {
+	Pairings map[string]int         `json:"pairings"`
+	Groups   []PairingGroupResponse `json:"groups"`
+}
+
+type PairingGroupResponse struct {
+	Group         int      `json:"group"`
+	UserIDs       []string `json:"user_ids"`
+	TotalHandicap float64  `json:"total_handicap"`
+}
+
+// SuggestPairings godoc
+// @Summary Suggest balanced pairings
+// @Description Split the TTR's confirmed players into groups of at most 4, with sizes differing by at most one and handicap totals as even as possible. Players without a handicap count as TTRS_DEFAULT_HANDICAP. The response can be sent as is to PUT /ttrs/{id}/pairings. Only captain or co-captains can ask.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=SuggestedPairingsResponse} "Pairings suggested successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/pairings/suggest [get]
+func (h *PairingHandler) SuggestPairings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	groups, err := h.pairingService.SuggestPairings(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to suggest pairings")
+		return
+	}
+
+	suggested := SuggestedPairingsResponse{
+		Pairings: make(map[string]int),
+		Groups:   make([]PairingGroupResponse, 0, len(groups)),
+	}
+	for i, group := range groups {
+		userIDs := make([]string, 0, len(group.Players))
+		for _, playerID := range group.Players {
+			userIDs = append(userIDs, playerID.String())
+			suggested.Pairings[playerID.String()] = i + 1
+		}
+		suggested.Groups = append(suggested.Groups, PairingGroupResponse{
+			Group:         i + 1,
+			UserIDs:       userIDs,
+			TotalHandicap: group.TotalHandicap,
+		})
+	}
+
+	response.Success(w, http.StatusOK, suggested)
+}
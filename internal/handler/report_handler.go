@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type" validate:"required,oneof=USER MESSAGE TTR"`
+	TargetID   string `json:"target_id" validate:"required,uuid"`
+	Category   string `json:"category" validate:"required,oneof=HARASSMENT SPAM INAPPROPRIATE IMPERSONATION OTHER"`
+	Details    string `json:"details" validate:"max=2000"`
+}
+
+type CloseReportRequest struct {
+	Status string `json:"status" validate:"required,oneof=RESOLVED DISMISSED"`
+	Notes  string `json:"notes" validate:"max=2000"`
+}
+
+type ReportResponse struct {
+	ID               string  `json:"id"`
+	ReporterUserID   string  `json:"reporter_user_id"`
+	ReporterName     string  `json:"reporter_name,omitempty"`
+	TargetType       string  `json:"target_type"`
+	TargetID         string  `json:"target_id"`
+	Category         string  `json:"category"`
+	Details          string  `json:"details"`
+	Status           string  `json:"status"`
+	Resolution       string  `json:"resolution,omitempty"`
+	ResolvedByUserID *string `json:"resolved_by_user_id,omitempty"`
+	ResolvedAt       *string `json:"resolved_at,omitempty"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+// CreateReport godoc
+// @Summary Report abuse
+// @Description Report a user, a chat message or a TTR for admins to review, with a category and optional details. The report starts OPEN, and the caller is notified once an admin resolves or dismisses it. A caller can have one open report per target, can't report themselves or their own messages, and can file a limited number of reports in any 24 hours.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateReportRequest true "What is reported and why"
+// @Success 201 {object} response.Response{data=ReportResponse} "Report filed successfully"
+// @Failure 400 {object} response.Response "Invalid request body, or reporting yourself"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Reported user, message or TTR not found"
+// @Failure 409 {object} response.Response "An open report about the target already exists"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 429 {object} response.Response "Too many reports in the last 24 hours"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/reports [post]
+func (h *ReportHandler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	report, err := h.reportService.CreateReport(r.Context(), userID, req.TargetType, uuid.MustParse(req.TargetID), req.Category, req.Details)
+	if err != nil {
+		response.FromError(w, err, "Failed to create report")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromReport(report))
+}
+
+// ListReports godoc
+// @Summary List abuse reports
+// @Description List abuse reports, oldest first, optionally only those with a status: OPEN, RESOLVED or DISMISSED. next_offset is set when more reports follow. Admin only.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "OPEN, RESOLVED or DISMISSED"
+// @Param limit query int false "Results limit" default(20)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} response.Response{data=PageResponse[ReportResponse]} "Reports retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid report status"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/reports [get]
+func (h *ReportHandler) ListReports(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", models.ReportStatusOpen, models.ReportStatusResolved, models.ReportStatusDismissed:
+	default:
+		response.BadRequest(w, "Invalid report status")
+		return
+	}
+	limit, offset := pageParams(r)
+
+	reports, err := h.reportService.ListReports(r.Context(), status, limit+1, offset)
+	if err != nil {
+		response.FromError(w, err, "Failed to list reports")
+		return
+	}
+
+	reportResponses := make([]ReportResponse, 0, len(reports))
+	for _, report := range reports {
+		reportResponses = append(reportResponses, FromReport(report))
+	}
+
+	response.Success(w, http.StatusOK, newPage(reportResponses, limit, offset))
+}
+
+// CloseReport godoc
+// @Summary Resolve or dismiss an abuse report
+// @Description Close an open report as RESOLVED, when action was taken, or DISMISSED, with notes on what was decided. The reporter is notified either way; the notes are only shown to admins. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Report ID"
+// @Param request body CloseReportRequest true "Outcome and notes"
+// @Success 200 {object} response.Response{data=ReportResponse} "Report closed successfully"
+// @Failure 400 {object} response.Response "Invalid report ID or request body"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an admin"
+// @Failure 404 {object} response.Response "Report not found"
+// @Failure 409 {object} response.Response "Report already closed"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/admin/reports/{id} [put]
+func (h *ReportHandler) CloseReport(w http.ResponseWriter, r *http.Request) {
+	adminID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	reportID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid report ID")
+		return
+	}
+
+	var req CloseReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	report, err := h.reportService.CloseReport(r.Context(), adminID, reportID, req.Status, req.Notes)
+	if err != nil {
+		response.FromError(w, err, "Failed to update report")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromReport(report))
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type ScheduleHandler struct {
+	scheduleService *service.ScheduleService
+}
+
+func NewScheduleHandler(scheduleService *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{scheduleService: scheduleService}
+}
+
+type TeeSheetResponse struct {
+	CourseName string                `json:"course_name"`
+	Days       []TeeSheetDayResponse `json:"days"`
+}
+
+type TeeSheetDayResponse struct {
+	Date string                  `json:"date"`
+	TTRs []TeeSheetEntryResponse `json:"ttrs"`
+}
+
+// TeeSheetEntryResponse is a TTR's line on a tee sheet. It names the captain
+// but leaves out their contact details.
+type TeeSheetEntryResponse struct {
+	TTRID          string `json:"ttr_id"`
+	TeeTime        string `json:"tee_time"`
+	Status         string `json:"status"`
+	CaptainName    string `json:"captain_name"`
+	ConfirmedCount int    `json:"confirmed_count"`
+	OpenSlots      int    `json:"open_slots"`
+}
+
+// GetSchedule godoc
+// @Summary Get a course's tee sheet
+// @Description Get the TTRs at a course on a date, by tee time, as compact tee sheet entries. Only TTRs the caller can find are listed, and cancelled ones are left out. The course name is matched regardless of case. With week=true the sheet covers the seven days from the date; every day is listed, those without TTRs too. confirmed_count counts the confirmed players and guests.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param date query string true "First day of the sheet (YYYY-MM-DD)"
+// @Param course query string true "Course name"
+// @Param week query bool false "Cover seven days instead of one"
+// @Success 200 {object} response.Response{data=TeeSheetResponse} "Tee sheet retrieved successfully"
+// @Failure 400 {object} response.Response "Missing course, or invalid date or week"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/schedule [get]
+func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	query := r.URL.Query()
+
+	date, err := time.Parse("2006-01-02", query.Get("date"))
+	if err != nil {
+		response.BadRequest(w, "Invalid date format, expected YYYY-MM-DD")
+		return
+	}
+	course := strings.TrimSpace(query.Get("course"))
+	if course == "" {
+		response.BadRequest(w, "course is required")
+		return
+	}
+	week := false
+	if raw := query.Get("week"); raw != "" {
+		week, err = strconv.ParseBool(raw)
+		if err != nil {
+			response.BadRequest(w, "Invalid week, expected true or false")
+			return
+		}
+	}
+
+	sheet, err := h.scheduleService.TeeSheet(r.Context(), userID, course, date, week)
+	if err != nil {
+		response.FromError(w, err, "Failed to get schedule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTeeSheet(sheet))
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type SecurityEventHandler struct {
+	securityEventService *service.SecurityEventService
+}
+
+func NewSecurityEventHandler(securityEventService *service.SecurityEventService) *SecurityEventHandler {
+	return &SecurityEventHandler{securityEventService: securityEventService}
+}
+
+type SecurityEventResponse struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	IPAddress string `json:"ip_address,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ListSecurityEvents godoc
+// @Summary List security events
+// @Description List the caller's recent account activity, newest first: logins (LOGIN), failed logins (LOGIN_FAILED), password changes (PASSWORD_CHANGED) and sign-outs that revoked refresh tokens (TOKENS_REVOKED), each with the IP address and user agent it came from. Events are kept for 90 days. next_offset is set when more events follow.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Results limit" default(20)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} response.Response{data=PageResponse[SecurityEventResponse]} "Security events retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/security-events [get]
+func (h *SecurityEventHandler) ListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	limit, offset := pageParams(r)
+
+	events, err := h.securityEventService.List(r.Context(), userID, limit+1, offset)
+	if err != nil {
+		response.FromError(w, err, "Failed to list security events")
+		return
+	}
+
+	eventResponses := make([]SecurityEventResponse, 0, len(events))
+	for _, event := range events {
+		eventResponses = append(eventResponses, FromSecurityEvent(event))
+	}
+
+	response.Success(w, http.StatusOK, newPage(eventResponses, limit, offset))
+}
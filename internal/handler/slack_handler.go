@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/slack"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+// maxSlackCommandSize bounds the form Slack posts for a command.
+const maxSlackCommandSize = 64 << 10
+
+type SlackHandler struct {
+	slackService  *service.SlackService
+	signingSecret string
+}
+
+func NewSlackHandler(slackService *service.SlackService, signingSecret string) *SlackHandler {
+	return &SlackHandler{slackService: slackService, signingSecret: signingSecret}
+}
+
+type LinkSlackAccountRequest struct {
+	Code string `json:"code" validate:"required,max=16"`
+}
+
+// SlackCommandResponse is a reply in Slack's message format, not the API
+// envelope, as Slack shows it to the user as is.
+type SlackCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// HandleCommand godoc
+// @Summary Run a Slack slash command
+// @Description Endpoint for the /golf Slack slash command, called by Slack with its signed form post. "/golf <course> <date> <time> [players]" creates a TTR captained by the linked account and replies in the channel with a share link; "/golf link" replies with a code to link the Slack user to an account; "/golf help" explains the command. Requests must carry a valid X-Slack-Signature.
+// @Tags integrations
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param X-Slack-Request-Timestamp header string true "Slack request timestamp"
+// @Param X-Slack-Signature header string true "Slack request signature"
+// @Success 200 {object} SlackCommandResponse "Reply shown in Slack"
+// @Failure 400 {object} response.Response "Invalid request body"
+// @Failure 401 {object} response.Response "Invalid Slack signature"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/integrations/slack/commands [post]
+func (h *SlackHandler) HandleCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackCommandSize))
+	if err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := slack.Verify(h.signingSecret, r.Header, body, time.Now()); err != nil {
+		response.Unauthorized(w, "Invalid Slack signature")
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	reply, err := h.slackService.HandleCommand(r.Context(), form.Get("team_id"), form.Get("user_id"), form.Get("text"))
+	if err != nil {
+		response.FromError(w, err, "Failed to run Slack command")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(SlackCommandResponse{ResponseType: reply.ResponseType, Text: reply.Text})
+}
+
+// LinkSlackAccount godoc
+// @Summary Link a Slack account
+// @Description Link the Slack user who typed "/golf link" to the caller's account, using the code Slack replied with. The code works once and expires. A Slack user linked before is moved to the caller's account.
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LinkSlackAccountRequest true "Linking code"
+// @Success 200 {object} response.Response{data=map[string]string} "Slack account linked successfully"
+// @Failure 400 {object} response.Response "Invalid or expired linking code"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/integrations/slack/link [post]
+func (h *SlackHandler) LinkSlackAccount(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req LinkSlackAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	if err := h.slackService.LinkAccount(r.Context(), userID, req.Code); err != nil {
+		response.FromError(w, err, "Failed to link Slack account")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Slack account linked successfully"})
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+type SuggestionHandler struct {
+	suggestionService *service.SuggestionService
+}
+
+func NewSuggestionHandler(suggestionService *service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{suggestionService: suggestionService}
+}
+
+type SuggestedPlayerResponse struct {
+	User         UserResponse `json:"user"`
+	Reason       string       `json:"reason"`
+	SharedRounds int          `json:"shared_rounds,omitempty"`
+}
+
+// GetSuggestedPlayers godoc
+// @Summary Get suggested players
+// @Description Rank up to 20 users worth inviting to a TTR. reason is played_together for users who played past rounds with the caller (most shared_rounds first), organization for members of the caller's organizations, and playing_day for users who usually play on the TTR's weekday. Players and pending invitees are left out. Only the captain and co-captains can ask.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=[]SuggestedPlayerResponse} "Suggested players retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/suggested-players [get]
+func (h *SuggestionHandler) GetSuggestedPlayers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	suggestions, err := h.suggestionService.SuggestPlayers(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get suggested players")
+		return
+	}
+
+	v := viewerFrom(r)
+	suggestionResponses := make([]SuggestedPlayerResponse, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		suggestionResponses = append(suggestionResponses, SuggestedPlayerResponse{
+			User:         FromUser(v, service.NewUserSummary(suggestion.User.ID, suggestion.User)),
+			Reason:       suggestion.Reason,
+			SharedRounds: suggestion.SharedRounds,
+		})
+	}
+
+	response.Success(w, http.StatusOK, suggestionResponses)
+}
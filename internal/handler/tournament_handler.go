@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type TournamentHandler struct {
+	tournamentService *service.TournamentService
+}
+
+func NewTournamentHandler(tournamentService *service.TournamentService) *TournamentHandler {
+	return &TournamentHandler{tournamentService: tournamentService}
+}
+
+type CreateTournamentRequest struct {
+	Name      string   `json:"name" validate:"required,min=2,max=255"`
+	PlayerIDs []string `json:"player_ids" validate:"required,min=2,max=16,dive,uuid"`
+	TTRID     string   `json:"ttr_id" validate:"omitempty,uuid"`
+}
+
+type ReportMatchResultRequest struct {
+	WinnerUserID string `json:"winner_user_id" validate:"required,uuid"`
+}
+
+type CreateRoundTTRRequest struct {
+	TeeDate string `json:"tee_date" validate:"required"`
+	TeeTime string `json:"tee_time" validate:"omitempty"`
+}
+
+type TournamentResponse struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	OwnerUserID  string          `json:"owner_user_id"`
+	TTRID        *string         `json:"ttr_id,omitempty"`
+	Status       string          `json:"status"`
+	WinnerUserID *string         `json:"winner_user_id,omitempty"`
+	CreatedAt    string          `json:"created_at"`
+	UpdatedAt    string          `json:"updated_at"`
+	Matches      []MatchResponse `json:"matches"`
+}
+
+type MatchResponse struct {
+	ID            string  `json:"id"`
+	Round         int     `json:"round"`
+	Position      int     `json:"position"`
+	Player1UserID *string `json:"player1_user_id,omitempty"`
+	Player2UserID *string `json:"player2_user_id,omitempty"`
+	WinnerUserID  *string `json:"winner_user_id,omitempty"`
+	Status        string  `json:"status"`
+	Walkover      bool    `json:"walkover"`
+	TTRID         *string `json:"ttr_id,omitempty"`
+	CompletedAt   *string `json:"completed_at,omitempty"`
+}
+
+// CreateTournament godoc
+// @Summary Create tournament
+// @Description Create a single-elimination match-play tournament for 2 to 16 players, listed in seed order (best first). Fields that aren't a power of two give byes to the top seeds, which count as walkovers. An optional ttr_id names the TTR the tournament is drawn from; its course and settings are copied for round TTRs, and only its captain or co-captains can use it.
+// @Tags tournaments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateTournamentRequest true "Tournament details"
+// @Success 201 {object} response.Response{data=TournamentResponse} "Tournament created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain of the TTR"
+// @Failure 404 {object} response.Response "Player or TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/tournaments [post]
+func (h *TournamentHandler) CreateTournament(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateTournamentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	players := make([]uuid.UUID, 0, len(req.PlayerIDs))
+	for _, id := range req.PlayerIDs {
+		playerID, err := uuid.Parse(id)
+		if err != nil {
+			response.BadRequest(w, "Invalid player ID")
+			return
+		}
+		players = append(players, playerID)
+	}
+
+	var ttrID *uuid.UUID
+	if req.TTRID != "" {
+		parsed, err := uuid.Parse(req.TTRID)
+		if err != nil {
+			response.BadRequest(w, "Invalid ttr_id")
+			return
+		}
+		ttrID = &parsed
+	}
+
+	tournament, err := h.tournamentService.CreateTournament(r.Context(), userID, req.Name, players, ttrID)
+	if err != nil {
+		response.FromError(w, err, "Failed to create tournament")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromTournament(tournament))
+}
+
+// GetTournament godoc
+// @Summary Get tournament by ID
+// @Description Get a tournament and its bracket, ordered by round and position
+// @Tags tournaments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID (UUID)"
+// @Success 200 {object} response.Response{data=TournamentResponse} "Tournament retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid tournament ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Tournament not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/tournaments/{id} [get]
+func (h *TournamentHandler) GetTournament(w http.ResponseWriter, r *http.Request) {
+	tournamentID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid tournament ID")
+		return
+	}
+
+	tournament, err := h.tournamentService.GetTournament(r.Context(), tournamentID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get tournament")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTournament(tournament))
+}
+
+// ReportMatchResult godoc
+// @Summary Report match result
+// @Description Report the winner of a scheduled match, who advances to the next round. Either match player or the tournament owner can report it. Winning the final completes the tournament.
+// @Tags tournaments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID (UUID)"
+// @Param matchId path string true "Match ID (UUID)"
+// @Param request body ReportMatchResultRequest true "Match winner"
+// @Success 200 {object} response.Response{data=TournamentResponse} "Result reported successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not a match player or the tournament owner"
+// @Failure 404 {object} response.Response "Tournament or match not found"
+// @Failure 409 {object} response.Response "Match already completed"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/tournaments/{id}/matches/{matchId}/result [post]
+func (h *TournamentHandler) ReportMatchResult(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	vars := mux.Vars(r)
+	tournamentID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid tournament ID")
+		return
+	}
+
+	matchID, err := uuid.Parse(vars["matchId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid match ID")
+		return
+	}
+
+	var req ReportMatchResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	winnerID, err := uuid.Parse(req.WinnerUserID)
+	if err != nil {
+		response.BadRequest(w, "Invalid winner_user_id")
+		return
+	}
+
+	tournament, err := h.tournamentService.ReportResult(r.Context(), tournamentID, matchID, userID, winnerID)
+	if err != nil {
+		response.FromError(w, err, "Failed to report result")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTournament(tournament))
+}
+
+// CreateRoundTTR godoc
+// @Summary Create round TTR
+// @Description Create a TTR for the players still to play in a round, copying the course and settings of the TTR the tournament was drawn from. Only the tournament owner can create it, once every match in the round knows both players. tee_time (HH:MM) defaults to the original TTR's.
+// @Tags tournaments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tournament ID (UUID)"
+// @Param round path int true "Round number"
+// @Param request body CreateRoundTTRRequest true "Tee date and time"
+// @Success 201 {object} response.Response{data=TTRResponse} "Round TTR created successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not the tournament owner"
+// @Failure 404 {object} response.Response "Tournament not found"
+// @Failure 409 {object} response.Response "Round already has a TTR"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/tournaments/{id}/rounds/{round}/ttr [post]
+func (h *TournamentHandler) CreateRoundTTR(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	vars := mux.Vars(r)
+	tournamentID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid tournament ID")
+		return
+	}
+
+	round, err := strconv.Atoi(vars["round"])
+	if err != nil || round < 1 {
+		response.BadRequest(w, "Invalid round")
+		return
+	}
+
+	var req CreateRoundTTRRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	teeDate, err := time.Parse("2006-01-02", req.TeeDate)
+	if err != nil {
+		response.BadRequest(w, "Invalid tee_date format, expected YYYY-MM-DD")
+		return
+	}
+
+	var teeTime *time.Time
+	if req.TeeTime != "" {
+		parsed, err := time.Parse("15:04", req.TeeTime)
+		if err != nil {
+			response.BadRequest(w, "Invalid tee_time format, expected HH:MM")
+			return
+		}
+		teeTime = &parsed
+	}
+
+	ttr, err := h.tournamentService.CreateRoundTTR(r.Context(), tournamentID, round, userID, teeDate, teeTime)
+	if err != nil {
+		response.FromError(w, err, "Failed to create round TTR")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromTTR(viewerFrom(r), service.NewTTRDetail(ttr)))
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,6 +12,8 @@ import (
 	"github.com/yourusername/golf_messenger/internal/middleware"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 	"github.com/yourusername/golf_messenger/pkg/response"
 	"github.com/yourusername/golf_messenger/pkg/validator"
 )
@@ -29,25 +32,93 @@ type CreateTTRRequest struct {
 	TeeDate        string `json:"tee_date" validate:"required"`
 	TeeTime        string `json:"tee_time" validate:"required"`
 	MaxPlayers     int    `json:"max_players" validate:"required,min=1,max=8"`
-	Notes          string `json:"notes" validate:"omitempty"`
+	MinPlayers     int    `json:"min_players" validate:"omitempty,min=1,max=8"`
+	Notes          string `json:"notes" validate:"omitempty,max=2000"`
+	RSVPDeadline   string `json:"rsvp_deadline" validate:"omitempty"`
+	OrganizationID string `json:"organization_id" validate:"omitempty,uuid"`
+	Visibility     string `json:"visibility" validate:"omitempty,ttr_visibility"`
+	Force          bool   `json:"force"`
+	CourseConditionsRequest
 }
 
 type UpdateTTRRequest struct {
-	CourseName     *string `json:"course_name" validate:"omitempty,min=2,max=255"`
-	CourseLocation *string `json:"course_location" validate:"omitempty,max=255"`
-	TeeDate        *string `json:"tee_date" validate:"omitempty"`
-	TeeTime        *string `json:"tee_time" validate:"omitempty"`
-	MaxPlayers     *int    `json:"max_players" validate:"omitempty,min=1,max=8"`
-	Status         *string `json:"status" validate:"omitempty"`
-	Notes          *string `json:"notes" validate:"omitempty"`
+	CourseName     *string           `json:"course_name" validate:"omitempty,min=2,max=255"`
+	CourseLocation *string           `json:"course_location" validate:"omitempty,max=255"`
+	TeeDate        *string           `json:"tee_date" validate:"omitempty"`
+	TeeTime        *string           `json:"tee_time" validate:"omitempty"`
+	MaxPlayers     *int              `json:"max_players" validate:"omitempty,min=1,max=8"`
+	MinPlayers     *int              `json:"min_players" validate:"omitempty,min=1,max=8"`
+	Status         *models.TTRStatus `json:"status" validate:"omitempty,ttr_status"`
+	StatusLocked   *bool             `json:"status_locked"`
+	Notes          *string           `json:"notes" validate:"omitempty,max=2000"`
+	RSVPDeadline   *string           `json:"rsvp_deadline" validate:"omitempty"`
+	Visibility     *string           `json:"visibility" validate:"omitempty,ttr_visibility"`
+	Override       bool              `json:"override"`
+	CourseConditionsRequest
 }
 
+// CourseConditionsRequest holds the optional course conditions of a TTR.
+// Green fees are in cents.
+type CourseConditionsRequest struct {
+	WalkingAllowed *bool   `json:"walking_allowed"`
+	CartsAvailable *bool   `json:"carts_available"`
+	Holes          *int    `json:"holes" validate:"omitempty,oneof=9 18"`
+	DressCode      *string `json:"dress_code" validate:"omitempty,max=100"`
+	GreenFeeCents  *int    `json:"green_fee_cents" validate:"omitempty,min=0"`
+}
+
+func (c CourseConditionsRequest) conditions() models.CourseConditions {
+	return models.CourseConditions{
+		WalkingAllowed: c.WalkingAllowed,
+		CartsAvailable: c.CartsAvailable,
+		Holes:          c.Holes,
+		DressCode:      c.DressCode,
+		GreenFeeCents:  c.GreenFeeCents,
+	}
+}
+
+// AddCoCaptainRequest names the new co-captain and, optionally, what they may
+// do. Without permissions they get every one.
 type AddCoCaptainRequest struct {
-	UserID string `json:"user_id" validate:"required,uuid"`
+	UserID      string   `json:"user_id" validate:"required,uuid"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateCoCaptainRequest struct {
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+// MembershipResponse answers joining and leaving a TTR and adding and
+// removing co-captains. AlreadyDone is set when the roster was already as
+// asked, as on a double-submitted join, and nothing changed.
+type MembershipResponse struct {
+	Message     string `json:"message"`
+	AlreadyDone bool   `json:"already_done"`
 }
 
 type UpdatePlayerStatusRequest struct {
-	Status string `json:"status" validate:"required"`
+	Status      *models.TTRPlayerStatus `json:"status" validate:"omitempty,player_status"`
+	Notes       *string                 `json:"notes" validate:"omitempty,max=2000"`
+	GroupNumber *int                    `json:"group_number" validate:"omitempty,min=0"`
+}
+
+type SetPairingsRequest struct {
+	Pairings map[string]int `json:"pairings" validate:"required"`
+}
+
+type AddGuestRequest struct {
+	DisplayName string   `json:"display_name" validate:"required,max=100"`
+	Handicap    *float64 `json:"handicap" validate:"omitempty,gte=0,lte=54"`
+}
+
+type SetTeeSlotsRequest struct {
+	Slots    []TeeSlotRequest `json:"slots" validate:"max=8,dive"`
+	AutoFill bool             `json:"auto_fill"`
+}
+
+type TeeSlotRequest struct {
+	OffsetMinutes int `json:"offset_minutes" validate:"min=0,max=240"`
+	Capacity      int `json:"capacity" validate:"omitempty,min=1,max=4"`
 }
 
 type TTRResponse struct {
@@ -57,36 +128,86 @@ type TTRResponse struct {
 	TeeDate         string              `json:"tee_date"`
 	TeeTime         string              `json:"tee_time"`
 	MaxPlayers      int                 `json:"max_players"`
+	MinPlayers      int                 `json:"min_players"`
 	CreatedByUserID string              `json:"created_by_user_id"`
 	CaptainUserID   string              `json:"captain_user_id"`
-	Status          string              `json:"status"`
+	Status          models.TTRStatus    `json:"status"`
+	StatusLocked    bool                `json:"status_locked"`
+	Visibility      string              `json:"visibility"`
 	Notes           *string             `json:"notes,omitempty"`
+	RSVPDeadline    *string             `json:"rsvp_deadline,omitempty"`
+	OrganizationID  *string             `json:"organization_id,omitempty"`
+	LeagueID        *string             `json:"league_id,omitempty"`
+	WalkingAllowed  *bool               `json:"walking_allowed,omitempty"`
+	CartsAvailable  *bool               `json:"carts_available,omitempty"`
+	Holes           *int                `json:"holes,omitempty"`
+	DressCode       *string             `json:"dress_code,omitempty"`
+	GreenFeeCents   *int                `json:"green_fee_cents,omitempty"`
 	CreatedAt       string              `json:"created_at"`
 	UpdatedAt       string              `json:"updated_at"`
+	DeletedAt       *string             `json:"deleted_at,omitempty"`
+	OpenSlots       *int                `json:"open_slots,omitempty"`
+	ConfirmedCount  *int                `json:"confirmed_player_count,omitempty"`
 	CreatedByUser   *UserResponse       `json:"created_by_user,omitempty"`
 	CaptainUser     *UserResponse       `json:"captain_user,omitempty"`
 	CoCaptains      []TTRCoCaptainResponse `json:"co_captains,omitempty"`
 	Players         []TTRPlayerResponse `json:"players,omitempty"`
+	Slots           []TeeSlotResponse   `json:"slots,omitempty"`
+}
+
+// TeeSlotResponse is one of a TTR's tee slots with the players assigned to
+// it. A TTR with slots lists only the players in no slot under players.
+type TeeSlotResponse struct {
+	ID            string              `json:"id"`
+	Number        int                 `json:"number"`
+	TeeTime       string              `json:"tee_time"`
+	OffsetMinutes int                 `json:"offset_minutes"`
+	Capacity      int                 `json:"capacity"`
+	Players       []TTRPlayerResponse `json:"players"`
+}
+
+// TTRPublicResponse is the view of an OPEN TTR shown to users who aren't
+// participating in it.
+type TTRPublicResponse struct {
+	ID             string           `json:"id"`
+	CourseName     string           `json:"course_name"`
+	CourseLocation *string          `json:"course_location,omitempty"`
+	TeeDate        string           `json:"tee_date"`
+	TeeTime        string           `json:"tee_time"`
+	MaxPlayers     int              `json:"max_players"`
+	OpenSlots      int              `json:"open_slots"`
+	Status         models.TTRStatus `json:"status"`
 }
 
 type TTRCoCaptainResponse struct {
-	TTRID      string        `json:"ttr_id"`
-	UserID     string        `json:"user_id"`
-	AssignedAt string        `json:"assigned_at"`
-	User       *UserResponse `json:"user,omitempty"`
+	TTRID       string        `json:"ttr_id"`
+	UserID      string        `json:"user_id"`
+	AssignedAt  string        `json:"assigned_at"`
+	Permissions []string      `json:"permissions"`
+	User        *UserResponse `json:"user,omitempty"`
 }
 
+// TTRPlayerResponse is a place on a TTR's roster. Guests have is_guest set,
+// a guest_id, display_name and handicap in place of user_id and user.
 type TTRPlayerResponse struct {
-	TTRID    string        `json:"ttr_id"`
-	UserID   string        `json:"user_id"`
-	JoinedAt string        `json:"joined_at"`
-	Status   string        `json:"status"`
-	User     *UserResponse `json:"user,omitempty"`
+	TTRID       string                 `json:"ttr_id"`
+	UserID      string                 `json:"user_id,omitempty"`
+	IsGuest     bool                   `json:"is_guest"`
+	GuestID     string                 `json:"guest_id,omitempty"`
+	DisplayName string                 `json:"display_name,omitempty"`
+	Handicap    *float64               `json:"handicap,omitempty"`
+	JoinedAt    string                 `json:"joined_at"`
+	Status      models.TTRPlayerStatus `json:"status"`
+	Notes       *string                `json:"notes,omitempty"`
+	GroupNumber int                    `json:"group_number"`
+	Score       *int                   `json:"score,omitempty"`
+	CheckedInAt *string                `json:"checked_in_at,omitempty"`
+	User        *UserResponse          `json:"user,omitempty"`
 }
 
 // CreateTTR godoc
 // @Summary Create new TTR
-// @Description Create a new tee time reservation. The creator becomes the captain and is automatically added as the first player.
+// @Description Create a new tee time reservation. The creator becomes the captain and is automatically added as the first player. An optional rsvp_deadline (RFC3339, before the tee time) closes invitation responses once it passes. An optional organization_id makes it a club round visible only to that organization's members; the creator must be a member. visibility controls who can find and join it: PRIVATE (default, participants only), FRIENDS (the captain's friends) or PUBLIC (everyone). min_players (default 2, at most max_players) is how many confirmed players keep a full TTR CONFIRMED. If the captain already has a TTR at the same course within 30 minutes of the tee time, the request fails with 409 DUPLICATE_TTR and details.existing_ttr_id; send force: true to create it anyway.
 // @Tags ttrs
 // @Accept json
 // @Produce json
@@ -95,6 +216,8 @@ type TTRPlayerResponse struct {
 // @Success 201 {object} response.Response{data=TTRResponse} "TTR created successfully"
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an organization member"
+// @Failure 409 {object} response.Response "Duplicate of an existing TTR"
 // @Failure 422 {object} response.Response "Validation error"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs [post]
@@ -108,7 +231,7 @@ func (h *TTRHandler) CreateTTR(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
@@ -135,30 +258,51 @@ func (h *TTRHandler) CreateTTR(w http.ResponseWriter, r *http.Request) {
 		notes = &req.Notes
 	}
 
-	ttr, err := h.ttrService.CreateTTR(userID, req.CourseName, courseLocation, teeDate, teeTime, req.MaxPlayers, notes)
+	var rsvpDeadline *time.Time
+	if req.RSVPDeadline != "" {
+		parsed, err := time.Parse(time.RFC3339, req.RSVPDeadline)
+		if err != nil {
+			response.BadRequest(w, "Invalid rsvp_deadline format, expected RFC3339")
+			return
+		}
+		rsvpDeadline = &parsed
+	}
+
+	var organizationID *uuid.UUID
+	if req.OrganizationID != "" {
+		parsed, err := uuid.Parse(req.OrganizationID)
+		if err != nil {
+			response.BadRequest(w, "Invalid organization_id")
+			return
+		}
+		organizationID = &parsed
+	}
+
+	ttr, err := h.ttrService.CreateTTR(r.Context(), userID, req.CourseName, courseLocation, teeDate, teeTime, req.MaxPlayers, req.MinPlayers, notes, req.conditions(), rsvpDeadline, organizationID, req.Visibility, req.Force)
 	if err != nil {
-		response.InternalServerError(w, "Failed to create TTR")
+		handleTTRError(w, err, "Failed to create TTR")
 		return
 	}
 
-	ttrResp := convertTTRToResponse(ttr)
+	ttrResp := FromTTR(viewerFrom(r), ttr)
 	response.Success(w, http.StatusCreated, ttrResp)
 }
 
 // GetTTR godoc
 // @Summary Get TTR by ID
-// @Description Get detailed information about a specific TTR
+// @Description Get detailed information about a specific TTR. Participants (captain, co-captains, players and pending invitees) get the full TTR; other users get a public view of OPEN TTRs whose visibility lets them find it.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
-// @Success 200 {object} response.Response{data=TTRResponse} "TTR retrieved successfully"
+// @Success 200 {object} response.Response{data=TTRResponse} "TTR retrieved successfully (TTRPublicResponse for non-participants)"
 // @Failure 400 {object} response.Response "Invalid TTR ID"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "TTR not found"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id} [get]
 func (h *TTRHandler) GetTTR(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
 	vars := mux.Vars(r)
 	idStr := vars["id"]
 
@@ -168,23 +312,24 @@ func (h *TTRHandler) GetTTR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ttr, err := h.ttrService.GetTTR(ttrID)
+	ttr, isParticipant, err := h.ttrService.GetTTR(r.Context(), ttrID, userID)
 	if err != nil {
-		if err.Error() == "TTR not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to get TTR")
+		response.FromError(w, err, "Failed to get TTR")
+		return
+	}
+
+	if !isParticipant {
+		response.Success(w, http.StatusOK, FromPublicTTR(ttr))
 		return
 	}
 
-	ttrResp := convertTTRToResponse(ttr)
+	ttrResp := FromTTR(viewerFrom(r), ttr)
 	response.Success(w, http.StatusOK, ttrResp)
 }
 
 // UpdateTTR godoc
 // @Summary Update TTR
-// @Description Update TTR details. Only captain or co-captains can update.
+// @Description Update TTR details. Only captain or co-captains can update. The status follows the roster on its own: an OPEN TTR whose slots are all taken by confirmed players becomes CONFIRMED, and a CONFIRMED one with fewer than min_players confirmed players opens again. Setting status by hand locks it against those changes; send status_locked false to hand it back to the roster. Within the edit lock window before the tee time (2 hours by default) the course, tee date and tee time can't change: the request fails with 409 TTR_LOCKED and details.locked_at unless override is true, and an overridden change sends confirmed players an urgent notification.
 // @Tags ttrs
 // @Accept json
 // @Produce json
@@ -196,6 +341,7 @@ func (h *TTRHandler) GetTTR(w http.ResponseWriter, r *http.Request) {
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
 // @Failure 404 {object} response.Response "TTR not found"
+// @Failure 409 {object} response.Response "TTR locked close to its tee time"
 // @Failure 422 {object} response.Response "Validation error"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id} [put]
@@ -217,7 +363,7 @@ func (h *TTRHandler) UpdateTTR(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
@@ -242,35 +388,40 @@ func (h *TTRHandler) UpdateTTR(w http.ResponseWriter, r *http.Request) {
 		teeTime = &parsed
 	}
 
-	ttr, err := h.ttrService.UpdateTTR(ttrID, userID, req.CourseName, req.CourseLocation, teeDate, teeTime, req.MaxPlayers, req.Status, req.Notes)
-	if err != nil {
-		if err.Error() == "TTR not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "unauthorized: only captain or co-captain can update TTR" {
-			response.Forbidden(w, err.Error())
+	var rsvpDeadline *time.Time
+	if req.RSVPDeadline != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.RSVPDeadline)
+		if err != nil {
+			response.BadRequest(w, "Invalid rsvp_deadline format, expected RFC3339")
 			return
 		}
-		response.InternalServerError(w, "Failed to update TTR")
+		rsvpDeadline = &parsed
+	}
+
+	ttr, err := h.ttrService.UpdateTTR(r.Context(), ttrID, userID, req.CourseName, req.CourseLocation, teeDate, teeTime, req.MaxPlayers, req.MinPlayers, req.Status, req.StatusLocked, req.Notes, req.conditions(), rsvpDeadline, req.Visibility, req.Override)
+	if err != nil {
+		handleTTRError(w, err, "Failed to update TTR")
 		return
 	}
 
-	ttrResp := convertTTRToResponse(ttr)
+	ttrResp := FromTTR(viewerFrom(r), ttr)
 	response.Success(w, http.StatusOK, ttrResp)
 }
 
 // DeleteTTR godoc
 // @Summary Delete TTR
-// @Description Delete (cancel) a TTR. Only the captain can delete.
+// @Description Cancel and delete a TTR. Only the captain can delete. Pending invitations are cancelled and players and invitees are notified. Within the edit lock window before the tee time the request fails with 409 TTR_LOCKED and details.locked_at unless override is true.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
+// @Param override query bool false "Delete even though the TTR is locked close to its tee time"
 // @Success 200 {object} response.Response{data=map[string]string} "TTR deleted successfully"
 // @Failure 400 {object} response.Response "Invalid TTR ID"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 409 {object} response.Response "TTR locked close to its tee time"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id} [delete]
 func (h *TTRHandler) DeleteTTR(w http.ResponseWriter, r *http.Request) {
@@ -284,77 +435,216 @@ func (h *TTRHandler) DeleteTTR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ttrService.DeleteTTR(ttrID, userID); err != nil {
-		if err.Error() == "unauthorized: only captain can delete TTR" {
-			response.Forbidden(w, err.Error())
+	override := false
+	if overrideStr := r.URL.Query().Get("override"); overrideStr != "" {
+		if override, err = strconv.ParseBool(overrideStr); err != nil {
+			response.BadRequest(w, "Invalid override value")
 			return
 		}
-		response.InternalServerError(w, "Failed to delete TTR")
+	}
+
+	if err := h.ttrService.DeleteTTR(r.Context(), ttrID, userID, override); err != nil {
+		handleTTRError(w, err, "Failed to delete TTR")
 		return
 	}
 
 	response.Success(w, http.StatusOK, map[string]string{"message": "TTR deleted successfully"})
 }
 
+// ListDeletedTTRs godoc
+// @Summary List deleted TTRs
+// @Description Get the TTRs the caller captains that were deleted recently enough to be restored, most recently deleted first. Deleted TTRs are purged for good once the restore window (7 days by default) has passed.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]TTRResponse} "Deleted TTRs retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/trash [get]
+func (h *TTRHandler) ListDeletedTTRs(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrs, err := h.ttrService.ListDeletedTTRs(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to list deleted TTRs")
+		return
+	}
+
+	v := viewerFrom(r)
+	ttrResponses := make([]TTRResponse, 0, len(ttrs))
+	for _, ttr := range ttrs {
+		ttrResponses = append(ttrResponses, FromTTR(v, ttr))
+	}
+
+	response.Success(w, http.StatusOK, ttrResponses)
+}
+
+// RestoreTTR godoc
+// @Summary Restore deleted TTR
+// @Description Undo a delete within the restore window. The TTR gets back the status it had before it was deleted and its players are notified. Invitations cancelled by the delete stay cancelled. Only the captain can restore.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Success 200 {object} response.Response{data=TTRResponse} "TTR restored successfully"
+// @Failure 400 {object} response.Response "Invalid TTR ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Deleted TTR not found"
+// @Failure 409 {object} response.Response "Restore window has passed"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/restore [post]
+func (h *TTRHandler) RestoreTTR(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	ttrID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	ttr, err := h.ttrService.RestoreTTR(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to restore TTR")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTTR(viewerFrom(r), ttr))
+}
+
 // SearchTTRs godoc
 // @Summary Search TTRs
-// @Description Get a list of TTRs with optional filters
+// @Description Get a list of TTRs with optional filters. Lists the TTRs the caller takes part in, PUBLIC TTRs, and FRIENDS TTRs whose captain is the caller's friend. Organization TTRs are only listed for members of the organization. The filters combine: mine=invited with from_date and to_date lists the open invitations for those days. mine=invited only counts PENDING invitations to TTRs that aren't cancelled or completed, so with status=CANCELLED or COMPLETED it lists nothing.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param limit query int false "Results limit" default(20)
 // @Param offset query int false "Results offset" default(0)
 // @Param status query string false "Filter by status (OPEN, CONFIRMED, CANCELLED, COMPLETED)"
+// @Param mine query string false "Only TTRs the caller takes part in this way (captain, co_captain, player, invited)"
+// @Param from_date query string false "Earliest tee date, inclusive (YYYY-MM-DD)"
+// @Param to_date query string false "Latest tee date, inclusive (YYYY-MM-DD)"
+// @Param holes query int false "Only TTRs of this many holes (9 or 18)"
+// @Param max_green_fee query int false "Only TTRs with a known green fee of at most this many cents"
 // @Success 200 {object} response.Response{data=[]TTRResponse} "TTRs retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid mine, from_date, to_date, holes or max_green_fee"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs [get]
 func (h *TTRHandler) SearchTTRs(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
+	limit, offset := pageParams(r)
+
+	ttrResponses, ok := h.searchTTRs(w, r, limit, offset)
+	if !ok {
+		return
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	response.Success(w, http.StatusOK, ttrResponses)
+}
+
+// SearchTTRsV2 godoc
+// @Summary Search TTRs
+// @Description Same listing as v1, wrapped in a page. next_offset is set when more TTRs follow.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Results limit" default(20)
+// @Param offset query int false "Results offset" default(0)
+// @Param status query string false "Filter by status (OPEN, CONFIRMED, CANCELLED, COMPLETED)"
+// @Param mine query string false "Only TTRs the caller takes part in this way (captain, co_captain, player, invited)"
+// @Param from_date query string false "Earliest tee date, inclusive (YYYY-MM-DD)"
+// @Param to_date query string false "Latest tee date, inclusive (YYYY-MM-DD)"
+// @Param holes query int false "Only TTRs of this many holes (9 or 18)"
+// @Param max_green_fee query int false "Only TTRs with a known green fee of at most this many cents"
+// @Success 200 {object} response.Response{data=PageResponse[TTRResponse]} "TTRs retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid mine, from_date, to_date, holes or max_green_fee"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v2/ttrs [get]
+func (h *TTRHandler) SearchTTRsV2(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r)
+
+	ttrResponses, ok := h.searchTTRs(w, r, limit+1, offset)
+	if !ok {
+		return
 	}
 
-	status := r.URL.Query().Get("status")
+	response.Success(w, http.StatusOK, newPage(ttrResponses, limit, offset))
+}
+
+// searchTTRs lists the TTRs visible to the caller, writing the error
+// response itself when the filters are invalid or the search fails.
+func (h *TTRHandler) searchTTRs(w http.ResponseWriter, r *http.Request, limit int, offset int) ([]TTRResponse, bool) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	query := r.URL.Query()
+	search := service.TTRSearch{
+		Status: models.TTRStatus(query.Get("status")),
+		Mine:   models.TTRParticipation(query.Get("mine")),
+	}
+	if search.Mine != "" && !search.Mine.IsValid() {
+		response.BadRequest(w, "Invalid mine, expected captain, co_captain, player or invited")
+		return nil, false
+	}
+	if raw := query.Get("from_date"); raw != "" {
+		from, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(w, "Invalid from_date format, expected YYYY-MM-DD")
+			return nil, false
+		}
+		search.TeeFrom = &from
+	}
+	if raw := query.Get("to_date"); raw != "" {
+		to, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			response.BadRequest(w, "Invalid to_date format, expected YYYY-MM-DD")
+			return nil, false
+		}
+		search.TeeTo = &to
+	}
+	if raw := query.Get("holes"); raw != "" {
+		holes, err := strconv.Atoi(raw)
+		if err != nil || !models.ValidHoles(holes) {
+			response.BadRequest(w, "Invalid holes, expected 9 or 18")
+			return nil, false
+		}
+		search.Holes = &holes
+	}
+	if raw := query.Get("max_green_fee"); raw != "" {
+		fee, err := strconv.Atoi(raw)
+		if err != nil || fee < 0 {
+			response.BadRequest(w, "Invalid max_green_fee, expected a non-negative amount in cents")
+			return nil, false
+		}
+		search.MaxGreenFeeCents = &fee
+	}
 
-	ttrs, err := h.ttrService.SearchTTRs(limit, offset, status)
+	ttrs, err := h.ttrService.SearchTTRs(r.Context(), userID, search, limit, offset)
 	if err != nil {
-		response.InternalServerError(w, "Failed to search TTRs")
-		return
+		response.FromError(w, err, "Failed to search TTRs")
+		return nil, false
 	}
 
+	v := viewerFrom(r)
 	ttrResponses := make([]TTRResponse, 0, len(ttrs))
 	for _, ttr := range ttrs {
-		ttrResponses = append(ttrResponses, convertTTRToResponse(ttr))
+		ttrResponses = append(ttrResponses, FromTTR(v, ttr))
 	}
-
-	response.Success(w, http.StatusOK, ttrResponses)
+	return ttrResponses, true
 }
 
 // AddCoCaptain godoc
 // @Summary Add co-captain to TTR
-// @Description Add a user as co-captain. Only the captain can add co-captains.
+// @Description Add a user as co-captain. Only the captain can add co-captains. permissions limits what the co-captain may do: invite (send invitations and invite links), edit_details (change the TTR's details) and manage_players (change the roster, pairings and tee slots). Without it they get all three. Adding a user who already is one succeeds with already_done set and leaves their permissions as they were.
 // @Tags ttrs
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
 // @Param request body AddCoCaptainRequest true "Co-captain user ID"
-// @Success 200 {object} response.Response{data=map[string]string} "Co-captain added successfully"
-// @Failure 400 {object} response.Response "Bad request"
+// @Success 200 {object} response.Response{data=MembershipResponse} "Co-captain added successfully"
+// @Failure 400 {object} response.Response "Bad request or unknown permission"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain"
+// @Failure 404 {object} response.Response "TTR or co-captain user not found"
 // @Failure 422 {object} response.Response "Validation error"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id}/co-captains [post]
@@ -376,7 +666,7 @@ func (h *TTRHandler) AddCoCaptain(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
@@ -387,38 +677,83 @@ func (h *TTRHandler) AddCoCaptain(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ttrService.AddCoCaptain(ttrID, userID, coCaptainUserID); err != nil {
-		if err.Error() == "unauthorized: only captain can add co-captains" {
-			response.Forbidden(w, err.Error())
-			return
-		}
-		if err.Error() == "co-captain user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "user is already a co-captain" {
-			response.BadRequest(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to add co-captain")
+	already, err := h.ttrService.AddCoCaptain(r.Context(), ttrID, userID, coCaptainUserID, req.Permissions)
+	if err != nil {
+		response.FromError(w, err, "Failed to add co-captain")
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]string{"message": "Co-captain added successfully"})
+	response.Success(w, http.StatusOK, MembershipResponse{Message: "Co-captain added successfully", AlreadyDone: already})
+}
+
+// UpdateCoCaptain godoc
+// @Summary Change a co-captain's permissions
+// @Description Replace what a co-captain may do with permissions, any of invite, edit_details and manage_players. An empty list leaves them a co-captain who can only moderate the chat. Only the captain can change permissions.
+// @Tags ttrs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param userId path string true "User ID (UUID) of the co-captain"
+// @Param request body UpdateCoCaptainRequest true "Co-captain permissions"
+// @Success 200 {object} response.Response{data=TTRCoCaptainResponse} "Co-captain updated successfully"
+// @Failure 400 {object} response.Response "Invalid ID or unknown permission"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain"
+// @Failure 404 {object} response.Response "TTR not found or user not a co-captain"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/co-captains/{userId} [put]
+func (h *TTRHandler) UpdateCoCaptain(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	coCaptainUserID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	var req UpdateCoCaptainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	coCaptain, err := h.ttrService.UpdateCoCaptainPermissions(r.Context(), ttrID, userID, coCaptainUserID, req.Permissions)
+	if err != nil {
+		response.FromError(w, err, "Failed to update co-captain")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTTRCoCaptain(viewerFrom(r), *coCaptain))
 }
 
 // RemoveCoCaptain godoc
 // @Summary Remove co-captain from TTR
-// @Description Remove a co-captain from the TTR. Only the captain can remove co-captains.
+// @Description Remove a co-captain from the TTR. Only the captain can remove co-captains. Removing a user who isn't one succeeds with already_done set.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
 // @Param userId path string true "User ID (UUID) of co-captain to remove"
-// @Success 200 {object} response.Response{data=map[string]string} "Co-captain removed successfully"
+// @Success 200 {object} response.Response{data=MembershipResponse} "Co-captain removed successfully"
 // @Failure 400 {object} response.Response "Invalid ID"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain"
+// @Failure 404 {object} response.Response "TTR not found"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id}/co-captains/{userId} [delete]
 func (h *TTRHandler) RemoveCoCaptain(w http.ResponseWriter, r *http.Request) {
@@ -439,27 +774,24 @@ func (h *TTRHandler) RemoveCoCaptain(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ttrService.RemoveCoCaptain(ttrID, userID, coCaptainUserID); err != nil {
-		if err.Error() == "unauthorized: only captain can remove co-captains" {
-			response.Forbidden(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to remove co-captain")
+	already, err := h.ttrService.RemoveCoCaptain(r.Context(), ttrID, userID, coCaptainUserID)
+	if err != nil {
+		response.FromError(w, err, "Failed to remove co-captain")
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]string{"message": "Co-captain removed successfully"})
+	response.Success(w, http.StatusOK, MembershipResponse{Message: "Co-captain removed successfully", AlreadyDone: already})
 }
 
 // JoinTTR godoc
 // @Summary Join a TTR
-// @Description Join a TTR as a player
+// @Description Join a TTR as a player. TTRs whose visibility hides them from the caller are reported as not found. Joining a TTR the caller is already on succeeds with already_done set, so a double-submitted join is no error. Cancelled and completed TTRs can't be joined.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
-// @Success 200 {object} response.Response{data=map[string]string} "Joined TTR successfully"
-// @Failure 400 {object} response.Response "Bad request or TTR is full"
+// @Success 200 {object} response.Response{data=MembershipResponse} "Joined TTR successfully"
+// @Failure 400 {object} response.Response "Bad request, TTR is full, cancelled or completed"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "TTR not found"
 // @Failure 500 {object} response.Response "Internal server error"
@@ -475,30 +807,23 @@ func (h *TTRHandler) JoinTTR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ttrService.JoinTTR(ttrID, userID); err != nil {
-		if err.Error() == "TTR not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "TTR is full" || err.Error() == "user is already a player" {
-			response.BadRequest(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to join TTR")
+	already, err := h.ttrService.JoinTTR(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to join TTR")
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]string{"message": "Joined TTR successfully"})
+	response.Success(w, http.StatusOK, MembershipResponse{Message: "Joined TTR successfully", AlreadyDone: already})
 }
 
 // LeaveTTR godoc
 // @Summary Leave a TTR
-// @Description Leave a TTR. The captain cannot leave.
+// @Description Leave a TTR. The captain cannot leave. A co-captain leaving also loses their co-captain role, and their pending invitations are reassigned to the captain. Leaving a TTR the caller isn't on succeeds with already_done set.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
-// @Success 200 {object} response.Response{data=map[string]string} "Left TTR successfully"
+// @Success 200 {object} response.Response{data=MembershipResponse} "Left TTR successfully"
 // @Failure 400 {object} response.Response "Bad request or captain cannot leave"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "TTR not found"
@@ -515,36 +840,30 @@ func (h *TTRHandler) LeaveTTR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.ttrService.LeaveTTR(ttrID, userID); err != nil {
-		if err.Error() == "TTR not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		if err.Error() == "captain cannot leave TTR" {
-			response.BadRequest(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to leave TTR")
+	already, err := h.ttrService.LeaveTTR(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to leave TTR")
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]string{"message": "Left TTR successfully"})
+	response.Success(w, http.StatusOK, MembershipResponse{Message: "Left TTR successfully", AlreadyDone: already})
 }
 
 // UpdatePlayerStatus godoc
 // @Summary Update player status
-// @Description Update a player's status in the TTR. Only captain or co-captains can update.
+// @Description Update a player's status, notes or pairing group in the TTR. Omitted fields are left unchanged. Only captain or co-captains can update.
 // @Tags ttrs
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "TTR ID (UUID)"
 // @Param userId path string true "Player User ID (UUID)"
-// @Param request body UpdatePlayerStatusRequest true "New status"
+// @Param request body UpdatePlayerStatusRequest true "Fields to update"
 // @Success 200 {object} response.Response{data=map[string]string} "Player status updated successfully"
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
 // @Failure 422 {object} response.Response "Validation error"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id}/players/{userId} [put]
@@ -573,30 +892,111 @@ func (h *TTRHandler) UpdatePlayerStatus(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	if err := h.ttrService.UpdatePlayerStatus(ttrID, userID, playerUserID, req.Status); err != nil {
-		if err.Error() == "unauthorized: only captain or co-captain can update player status" {
-			response.Forbidden(w, err.Error())
-			return
-		}
-		if err.Error() == "invalid player status" || err.Error() == "player not found in TTR" {
-			response.BadRequest(w, err.Error())
+	if req.Status == nil && req.Notes == nil && req.GroupNumber == nil {
+		response.BadRequest(w, "No fields to update")
+		return
+	}
+
+	if err := h.ttrService.UpdatePlayer(r.Context(), ttrID, userID, playerUserID, req.Status, req.Notes, req.GroupNumber); err != nil {
+		response.FromError(w, err, "Failed to update player status")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Player status updated successfully"})
+}
+
+// SetPairings godoc
+// @Summary Set TTR pairings
+// @Description Replace the TTR's pairing groups with a mapping of user ID to group number. Roster players left out become unassigned (group 0). Each group holds at most 4 players. When the TTR has tee slots, group numbers are slot numbers and each group holds at most its slot's capacity. Only captain or co-captains can update.
+// @Tags ttrs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body SetPairingsRequest true "User ID to group number mapping"
+// @Success 200 {object} response.Response{data=[]TTRPlayerResponse} "Pairings updated successfully"
+// @Failure 400 {object} response.Response "Bad request, unknown player or group too large"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/pairings [put]
+func (h *TTRHandler) SetPairings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	ttrID, err := uuid.Parse(idStr)
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req SetPairingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	pairings := make(map[uuid.UUID]int, len(req.Pairings))
+	for userIDStr, group := range req.Pairings {
+		playerUserID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid user ID in pairings")
 			return
 		}
-		response.InternalServerError(w, "Failed to update player status")
+		pairings[playerUserID] = group
+	}
+
+	if err := h.ttrService.SetPairings(r.Context(), ttrID, userID, pairings); err != nil {
+		response.FromError(w, err, "Failed to update pairings")
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]string{"message": "Player status updated successfully"})
+	players, err := h.ttrService.GetPlayers(r.Context(), ttrID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get players")
+		return
+	}
+	captains, err := h.ttrService.Captains(r.Context(), ttrID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get players")
+		return
+	}
+
+	guests, err := h.ttrService.GetGuests(r.Context(), ttrID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get players")
+		return
+	}
+
+	v := viewerFrom(r).captaining(captains, players)
+	playerResponses := make([]TTRPlayerResponse, 0, len(players)+len(guests))
+	for _, player := range players {
+		playerResponses = append(playerResponses, FromTTRPlayer(v, player))
+	}
+	for _, guest := range guests {
+		playerResponses = append(playerResponses, FromTTRGuest(guest))
+	}
+
+	response.Success(w, http.StatusOK, playerResponses)
 }
 
 // GetPlayers godoc
 // @Summary Get TTR players
-// @Description Get all players for a specific TTR
+// @Description Get all players for a specific TTR. Only users who can see the TTR get its roster; anyone else gets 404.
 // @Tags ttrs
 // @Produce json
 // @Security BearerAuth
@@ -604,9 +1004,11 @@ func (h *TTRHandler) UpdatePlayerStatus(w http.ResponseWriter, r *http.Request)
 // @Success 200 {object} response.Response{data=[]TTRPlayerResponse} "Players retrieved successfully"
 // @Failure 400 {object} response.Response "Invalid TTR ID"
 // @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "TTR not found"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/ttrs/{id}/players [get]
 func (h *TTRHandler) GetPlayers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
 	vars := mux.Vars(r)
 	idStr := vars["id"]
 
@@ -616,102 +1018,192 @@ func (h *TTRHandler) GetPlayers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	players, err := h.ttrService.GetPlayers(ttrID)
+	players, err := h.ttrService.GetPlayers(r.Context(), ttrID, userID)
 	if err != nil {
-		response.InternalServerError(w, "Failed to get players")
+		response.FromError(w, err, "Failed to get players")
+		return
+	}
+	captains, err := h.ttrService.Captains(r.Context(), ttrID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get players")
 		return
 	}
 
-	playerResponses := make([]TTRPlayerResponse, 0, len(players))
+	guests, err := h.ttrService.GetGuests(r.Context(), ttrID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get players")
+		return
+	}
+
+	v := viewerFrom(r).captaining(captains, players)
+	playerResponses := make([]TTRPlayerResponse, 0, len(players)+len(guests))
 	for _, player := range players {
-		playerResp := TTRPlayerResponse{
-			TTRID:    player.TTRID.String(),
-			UserID:   player.UserID.String(),
-			JoinedAt: player.JoinedAt.Format(time.RFC3339),
-			Status:   player.Status,
-		}
-		if player.User != nil {
-			userResp := convertUserToResponse(player.User)
-			playerResp.User = &userResp
-		}
-		playerResponses = append(playerResponses, playerResp)
+		playerResponses = append(playerResponses, FromTTRPlayer(v, player))
+	}
+	for _, guest := range guests {
+		playerResponses = append(playerResponses, FromTTRGuest(guest))
 	}
 
 	response.Success(w, http.StatusOK, playerResponses)
 }
 
-func convertTTRToResponse(ttr *models.TTR) TTRResponse {
-	resp := TTRResponse{
-		ID:              ttr.ID.String(),
-		CourseName:      ttr.CourseName,
-		CourseLocation:  ttr.CourseLocation,
-		TeeDate:         ttr.TeeDate.Format("2006-01-02"),
-		TeeTime:         ttr.TeeTime.Format("15:04"),
-		MaxPlayers:      ttr.MaxPlayers,
-		CreatedByUserID: ttr.CreatedByUserID.String(),
-		CaptainUserID:   ttr.CaptainUserID.String(),
-		Status:          ttr.Status,
-		Notes:           ttr.Notes,
-		CreatedAt:       ttr.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:       ttr.UpdatedAt.Format(time.RFC3339),
-	}
-
-	if ttr.CreatedByUser != nil {
-		userResp := convertUserToResponse(ttr.CreatedByUser)
-		resp.CreatedByUser = &userResp
-	}
-
-	if ttr.CaptainUser != nil {
-		userResp := convertUserToResponse(ttr.CaptainUser)
-		resp.CaptainUser = &userResp
-	}
-
-	if ttr.CoCaptains != nil {
-		resp.CoCaptains = make([]TTRCoCaptainResponse, 0, len(ttr.CoCaptains))
-		for _, cc := range ttr.CoCaptains {
-			ccResp := TTRCoCaptainResponse{
-				TTRID:      cc.TTRID.String(),
-				UserID:     cc.UserID.String(),
-				AssignedAt: cc.AssignedAt.Format(time.RFC3339),
-			}
-			if cc.User != nil {
-				userResp := convertUserToResponse(cc.User)
-				ccResp.User = &userResp
-			}
-			resp.CoCaptains = append(resp.CoCaptains, ccResp)
-		}
+// handleTTRError writes err like response.FromError, adding the details
+// clients need to act on duplicate and locked TTRs and closed check-in.
+func handleTTRError(w http.ResponseWriter, err error, fallback string) {
+	var duplicate *service.DuplicateTTRError
+	var locked *service.TTRLockedError
+	var checkIn *service.CheckInWindowError
+	switch {
+	case errors.As(err, &duplicate):
+		response.CodedWithDetails(w, errcode.DuplicateTTR, service.ErrDuplicateTTR.Message, map[string]string{"existing_ttr_id": duplicate.ExistingTTRID.String()})
+	case errors.As(err, &locked):
+		response.CodedWithDetails(w, errcode.TTRLocked, service.ErrTTRLocked.Message, map[string]string{"locked_at": locked.LockedAt.UTC().Format(time.RFC3339)})
+	case errors.As(err, &checkIn):
+		response.CodedWithDetails(w, errcode.CheckInClosed, service.ErrCheckInClosed.Message, map[string]string{
+			"opens_at":  checkIn.OpensAt.UTC().Format(time.RFC3339),
+			"closes_at": checkIn.ClosesAt.UTC().Format(time.RFC3339),
+		})
+	default:
+		response.FromError(w, err, fallback)
 	}
+}
 
-	if ttr.Players != nil {
-		resp.Players = make([]TTRPlayerResponse, 0, len(ttr.Players))
-		for _, p := range ttr.Players {
-			pResp := TTRPlayerResponse{
-				TTRID:    p.TTRID.String(),
-				UserID:   p.UserID.String(),
-				JoinedAt: p.JoinedAt.Format(time.RFC3339),
-				Status:   p.Status,
-			}
-			if p.User != nil {
-				userResp := convertUserToResponse(p.User)
-				pResp.User = &userResp
-			}
-			resp.Players = append(resp.Players, pResp)
-		}
+// AddGuest godoc
+// @Summary Add a guest to a TTR
+// @Description Add a player without an account to the roster by display name, with an optional handicap. Guests take a slot like a confirmed player but get no invitations or notifications and are left out of stats. Only the captain or a co-captain can add guests.
+// @Tags ttrs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body AddGuestRequest true "Guest details"
+// @Success 201 {object} response.Response{data=TTRPlayerResponse} "Guest added successfully"
+// @Failure 400 {object} response.Response "Bad request or TTR is full"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/guests [post]
+func (h *TTRHandler) AddGuest(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req AddGuestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
 	}
 
-	return resp
+	guest, err := h.ttrService.AddGuest(r.Context(), ttrID, userID, req.DisplayName, req.Handicap)
+	if err != nil {
+		response.FromError(w, err, "Failed to add guest")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, FromTTRGuest(*guest))
 }
 
-func convertUserToResponse(user *models.User) UserResponse {
-	return UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+// RemoveGuest godoc
+// @Summary Remove a guest from a TTR
+// @Description Remove a guest from the roster. Only the captain or a co-captain can remove guests.
+// @Tags ttrs
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param guestId path string true "Guest ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Guest removed successfully"
+// @Failure 400 {object} response.Response "Invalid ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR or guest not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/guests/{guestId} [delete]
+func (h *TTRHandler) RemoveGuest(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
 	}
+
+	guestID, err := uuid.Parse(vars["guestId"])
+	if err != nil {
+		response.BadRequest(w, "Invalid guest ID")
+		return
+	}
+
+	if err := h.ttrService.RemoveGuest(r.Context(), ttrID, userID, guestID); err != nil {
+		response.FromError(w, err, "Failed to remove guest")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Guest removed successfully"})
+}
+
+// SetTeeSlots godoc
+// @Summary Set a TTR's tee slots
+// @Description Replace the block of tee times the course gave the TTR, e.g. 8:00, 8:10 and 8:20 as offsets 0, 10 and 20 minutes from its tee time. Slots are numbered from 1 in the given order, need increasing offsets and hold 1 to 4 players (default 4), and together must hold every confirmed player. Players are assigned to a slot through their pairing group number, with PUT /ttrs/{id}/pairings or, with auto_fill, by dealing the confirmed players out round-robin in join order. Without auto_fill, assignments to slots that are gone are dropped. An empty list removes the slots. The TTR is returned with its players nested under their slots. Only the captain or a co-captain can set slots.
+// @Tags ttrs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "TTR ID (UUID)"
+// @Param request body SetTeeSlotsRequest true "Tee slots"
+// @Success 200 {object} response.Response{data=TTRResponse} "Tee slots set successfully"
+// @Failure 400 {object} response.Response "Bad request or slots too small for the confirmed players"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not captain or co-captain"
+// @Failure 404 {object} response.Response "TTR not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/ttrs/{id}/slots [put]
+func (h *TTRHandler) SetTeeSlots(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	vars := mux.Vars(r)
+
+	ttrID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid TTR ID")
+		return
+	}
+
+	var req SetTeeSlotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	slots := make([]service.TeeSlotInput, 0, len(req.Slots))
+	for _, slot := range req.Slots {
+		slots = append(slots, service.TeeSlotInput{OffsetMinutes: slot.OffsetMinutes, Capacity: slot.Capacity})
+	}
+
+	ttr, err := h.ttrService.SetTeeSlots(r.Context(), ttrID, userID, slots, req.AutoFill)
+	if err != nil {
+		response.FromError(w, err, "Failed to set tee slots")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromTTR(viewerFrom(r), ttr))
 }
@@ -2,13 +2,18 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/yourusername/golf_messenger/internal/middleware"
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 	"github.com/yourusername/golf_messenger/pkg/response"
 	"github.com/yourusername/golf_messenger/pkg/validator"
 )
@@ -22,10 +27,50 @@ func NewUserHandler(userService *service.UserService) *UserHandler {
 }
 
 type UpdateProfileRequest struct {
-	FirstName string   `json:"first_name" validate:"omitempty,min=2,max=100"`
-	LastName  string   `json:"last_name" validate:"omitempty,min=2,max=100"`
-	Handicap  *float64 `json:"handicap" validate:"omitempty,gte=0,lte=54"`
-	Phone     *string  `json:"phone" validate:"omitempty,max=20"`
+	FirstName             string        `json:"first_name" validate:"omitempty,min=2,max=100"`
+	LastName              string        `json:"last_name" validate:"omitempty,min=2,max=100"`
+	Handicap              *float64      `json:"handicap" validate:"omitempty,gte=0,lte=54"`
+	Phone                 *string       `json:"phone" validate:"omitempty,max=20"`
+	PreferredLanguage     *string       `json:"preferred_language" validate:"omitempty,max=10"`
+	HomeCourse            *string       `json:"home_course" validate:"omitempty,max=200"`
+	Bio                   *string       `json:"bio" validate:"omitempty,max=500"`
+	PlayingDays           []string      `json:"playing_days" validate:"omitempty,max=7,unique,dive,weekday"`
+	PreferredTeeTimeRange *TeeTimeRange `json:"preferred_tee_time_range"`
+}
+
+// TeeTimeRange is a span of tee times in HH:MM.
+type TeeTimeRange struct {
+	Start string `json:"start" validate:"omitempty,time_of_day"`
+	End   string `json:"end" validate:"omitempty,time_of_day"`
+}
+
+// UpdatePrivacyRequest changes the privacy settings sent; the rest are kept.
+type UpdatePrivacyRequest struct {
+	ShowHandicap         *bool `json:"show_handicap"`
+	ShowPhone            *bool `json:"show_phone"`
+	ShowEmail            *bool `json:"show_email"`
+	DiscoverableInSearch *bool `json:"discoverable_in_search"`
+}
+
+// PrivacySettingsResponse is what a user lets other users see of them. It is
+// only shown to the user themselves.
+type PrivacySettingsResponse struct {
+	ShowHandicap         bool `json:"show_handicap"`
+	ShowPhone            bool `json:"show_phone"`
+	ShowEmail            bool `json:"show_email"`
+	DiscoverableInSearch bool `json:"discoverable_in_search"`
+}
+
+// UpdateNotificationPreferencesRequest changes the preferences sent; the rest
+// are kept.
+type UpdateNotificationPreferencesRequest struct {
+	WeeklyDigest *bool `json:"weekly_digest"`
+}
+
+// NotificationPreferencesResponse is which notifications a user gets besides
+// those in the app.
+type NotificationPreferencesResponse struct {
+	WeeklyDigest bool `json:"weekly_digest"`
 }
 
 type ChangePasswordRequest struct {
@@ -47,34 +92,20 @@ type ChangePasswordRequest struct {
 func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
 
-	user, err := h.userService.GetProfile(userID)
+	user, err := h.userService.GetProfile(r.Context(), userID)
 	if err != nil {
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to get user profile")
+		response.FromError(w, err, "Failed to get user profile")
 		return
 	}
 
-	userResp := UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	userResp := FromProfile(user)
 
 	response.Success(w, http.StatusOK, userResp)
 }
 
 // UpdateMe godoc
 // @Summary Update current user profile
-// @Description Update the profile of the currently authenticated user
+// @Description Update the profile of the currently authenticated user. Only the fields sent are changed. An empty home_course or bio clears it, playing_days replaces the user's playing days ([] clears them), and a preferred_tee_time_range with an empty start and end clears the range.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -96,39 +127,108 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	user, err := h.userService.UpdateProfile(userID, req.FirstName, req.LastName, req.Handicap, req.Phone)
-	if err != nil {
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
+	var teeTimes *service.TeeTimeRange
+	if rng := req.PreferredTeeTimeRange; rng != nil {
+		if (rng.Start == "") != (rng.End == "") {
+			response.BadRequest(w, "Preferred tee time range needs both start and end")
 			return
 		}
-		response.InternalServerError(w, "Failed to update profile")
-		return
+		teeTimes = &service.TeeTimeRange{}
+		if rng.Start != "" {
+			// Both were checked by the time_of_day validation.
+			teeTimes.Start, _ = time.Parse("15:04", rng.Start)
+			teeTimes.End, _ = time.Parse("15:04", rng.End)
+		}
 	}
 
-	userResp := UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	user, err := h.userService.UpdateProfile(r.Context(), userID, req.FirstName, req.LastName, req.Handicap, req.Phone, req.PreferredLanguage, req.HomeCourse, req.Bio, req.PlayingDays, teeTimes)
+	if err != nil {
+		response.FromError(w, err, "Failed to update profile")
+		return
 	}
 
+	userResp := FromProfile(user)
+
 	response.Success(w, http.StatusOK, userResp)
 }
 
+// UpdatePrivacy godoc
+// @Summary Update privacy settings
+// @Description Change what other users see of the current user. Only the settings sent are changed. Email and phone are hidden and the handicap shown by default; captains of a TTR the user plays on always see their handicap. Users not discoverable in search can still be found by their exact email.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdatePrivacyRequest true "Privacy settings to change"
+// @Success 200 {object} response.Response{data=PrivacySettingsResponse} "Privacy settings updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/privacy [put]
+func (h *UserHandler) UpdatePrivacy(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req UpdatePrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdatePrivacy(r.Context(), userID, service.PrivacyUpdate{
+		ShowHandicap:         req.ShowHandicap,
+		ShowPhone:            req.ShowPhone,
+		ShowEmail:            req.ShowEmail,
+		DiscoverableInSearch: req.DiscoverableInSearch,
+	})
+	if err != nil {
+		response.FromError(w, err, "Failed to update privacy settings")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromPrivacySettings(user.Privacy))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Change which notifications the current user gets besides those in the app. Only the preferences sent are changed. weekly_digest is the email summing up the week's rounds, invitations and action items; it is on by default and only sent when there is something in it.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateNotificationPreferencesRequest true "Notification preferences to change"
+// @Success 200 {object} response.Response{data=NotificationPreferencesResponse} "Notification preferences updated successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/notification-preferences [put]
+func (h *UserHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req UpdateNotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdateNotificationSettings(r.Context(), userID, service.NotificationSettingsUpdate{
+		WeeklyDigest: req.WeeklyDigest,
+	})
+	if err != nil {
+		response.FromError(w, err, "Failed to update notification preferences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromNotificationSettings(user.Notifications))
+}
+
 // ChangePassword godoc
 // @Summary Change user password
-// @Description Change the password of the currently authenticated user
+// @Description Change the password of the currently authenticated user, which is also how members who must reset their password choose one. A new password breaking the password policy gets a 422 WEAK_PASSWORD whose details list the rules it breaks.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -137,7 +237,7 @@ func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} response.Response{data=map[string]string} "Password changed successfully"
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized or invalid old password"
-// @Failure 422 {object} response.Response "Validation error"
+// @Failure 422 {object} response.Response "Validation error or weak password"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/users/me/password [put]
 func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
@@ -150,21 +250,13 @@ func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := validator.Validate(&req); err != nil {
-		errors := validator.FormatValidationErrors(err)
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
 		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	if err := h.userService.ChangePassword(userID, req.OldPassword, req.NewPassword); err != nil {
-		if err.Error() == "invalid old password" {
-			response.Unauthorized(w, err.Error())
-			return
-		}
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to change password")
+	if err := h.userService.ChangePassword(r.Context(), userID, req.OldPassword, req.NewPassword); err != nil {
+		handlePasswordPolicyError(w, err, "Failed to change password")
 		return
 	}
 
@@ -173,63 +265,179 @@ func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 // UploadAvatar godoc
 // @Summary Upload user avatar
-// @Description Upload an avatar image for the currently authenticated user
+// @Description Upload an avatar image for the currently authenticated user. The file is streamed to storage as it arrives; files larger than the configured limit are rejected with 413. Large files can instead be uploaded directly to storage with /users/me/avatar/upload-url.
 // @Tags users
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
-// @Param avatar formData file true "Avatar image file"
+// @Param avatar formData file true "Avatar image file (JPEG or PNG)"
 // @Success 200 {object} response.Response{data=UserResponse} "Avatar uploaded successfully"
-// @Failure 400 {object} response.Response "Bad request"
+// @Failure 400 {object} response.Response "Bad request or empty file"
 // @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 413 {object} response.Response "Avatar too large; details carry max_size"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/users/me/avatar [post]
 func (h *UserHandler) UploadAvatar(w http.ResponseWriter, r *http.Request) {
 	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+	maxSize := h.userService.AvatarMaxSize()
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
+	// Leave headroom over the file limit for the multipart framing.
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
 		response.BadRequest(w, "Failed to parse form data")
 		return
 	}
 
-	file, header, err := r.FormFile("avatar")
+	part, err := nextFilePart(reader, "avatar")
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			avatarTooLarge(w, maxSize)
+			return
+		}
 		response.BadRequest(w, "Avatar file is required")
 		return
 	}
-	defer file.Close()
+	defer part.Close()
+
+	user, err := h.userService.UploadAvatar(r.Context(), userID, part, part.Header.Get("Content-Type"))
+	if err != nil {
+		h.handleAvatarError(w, err, "Failed to upload avatar")
+		return
+	}
+
+	userResp := FromProfile(user)
+
+	response.Success(w, http.StatusOK, userResp)
+}
+
+type AvatarUploadURLRequest struct {
+	ContentType string `json:"content_type" validate:"required,oneof=image/jpeg image/png"`
+	Size        int64  `json:"size" validate:"required,gte=1"`
+}
+
+type AvatarUploadURLResponse struct {
+	UploadURL string `json:"upload_url"`
+	Key       string `json:"key"`
+}
+
+type CompleteAvatarUploadRequest struct {
+	Key string `json:"key" validate:"required"`
+}
+
+// CreateAvatarUploadURL godoc
+// @Summary Get a direct avatar upload URL
+// @Description Step one of a direct upload: returns a presigned URL to PUT the avatar to, with exactly the given Content-Type and size, and the key to confirm with /users/me/avatar/complete.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AvatarUploadURLRequest true "Avatar content type and size in bytes"
+// @Success 200 {object} response.Response{data=AvatarUploadURLResponse} "Upload URL created"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 413 {object} response.Response "Avatar too large; details carry max_size"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/avatar/upload-url [post]
+func (h *UserHandler) CreateAvatarUploadURL(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req AvatarUploadURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
 
-	contentType := header.Header.Get("Content-Type")
-	if contentType != "image/jpeg" && contentType != "image/png" && contentType != "image/jpg" {
-		response.BadRequest(w, "Only JPEG and PNG images are allowed")
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
 		return
 	}
 
-	user, err := h.userService.UploadAvatar(r.Context(), userID, file, header.Filename, contentType)
+	uploadURL, key, err := h.userService.PresignAvatarUpload(r.Context(), userID, req.ContentType, req.Size)
 	if err != nil {
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to upload avatar")
+		h.handleAvatarError(w, err, "Failed to create avatar upload URL")
 		return
 	}
 
-	userResp := UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	response.Success(w, http.StatusOK, AvatarUploadURLResponse{UploadURL: uploadURL, Key: key})
+}
+
+// CompleteAvatarUpload godoc
+// @Summary Confirm a direct avatar upload
+// @Description Step two of a direct upload: makes the object uploaded to the presigned URL the user's avatar.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CompleteAvatarUploadRequest true "Key returned with the upload URL"
+// @Success 200 {object} response.Response{data=UserResponse} "Avatar updated successfully"
+// @Failure 400 {object} response.Response "Bad request, unknown key or empty file"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 413 {object} response.Response "Avatar too large; details carry max_size"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/avatar/complete [post]
+func (h *UserHandler) CompleteAvatarUpload(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CompleteAvatarUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
 	}
 
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	user, err := h.userService.CompleteAvatarUpload(r.Context(), userID, req.Key)
+	if err != nil {
+		h.handleAvatarError(w, err, "Failed to update avatar")
+		return
+	}
+
+	userResp := FromProfile(user)
+
 	response.Success(w, http.StatusOK, userResp)
 }
 
+func (h *UserHandler) handleAvatarError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrAvatarTooLarge):
+		avatarTooLarge(w, h.userService.AvatarMaxSize())
+	case errors.Is(err, service.ErrAvatarEmpty):
+		response.Coded(w, errcode.AvatarEmpty, "Avatar file is empty")
+	case errors.Is(err, service.ErrUnsupportedAvatarType):
+		response.Coded(w, errcode.UnsupportedImageType, "Only JPEG and PNG images are allowed")
+	default:
+		response.FromError(w, err, fallback)
+	}
+}
+
+func avatarTooLarge(w http.ResponseWriter, maxSize int64) {
+	response.CodedWithDetails(w, errcode.AvatarTooLarge, "Avatar file is too large", map[string]int64{"max_size": maxSize})
+}
+
+// nextFilePart skips to the file part sent as field name without reading
+// earlier parts into memory.
+func nextFilePart(reader *multipart.Reader, name string) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
 // DeleteAvatar godoc
 // @Summary Delete user avatar
 // @Description Delete the avatar of the currently authenticated user
@@ -246,32 +454,222 @@ func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
 
 	user, err := h.userService.DeleteAvatar(r.Context(), userID)
 	if err != nil {
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to delete avatar")
+		response.FromError(w, err, "Failed to delete avatar")
 		return
 	}
 
-	userResp := UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}
+	userResp := FromProfile(user)
 
 	response.Success(w, http.StatusOK, userResp)
 }
 
+// GetBlockedUsers godoc
+// @Summary List blocked users
+// @Description List the users the caller has blocked, most recently blocked first. Invitations to the caller's TTRs can't be forwarded to them.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]UserResponse} "Blocked users retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/blocks [get]
+func (h *UserHandler) GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	users, err := h.userService.GetBlockedUsers(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get blocked users")
+		return
+	}
+	h.respondUsers(w, r, users)
+}
+
+// BlockUser godoc
+// @Summary Block user
+// @Description Block a user, so invitations to the caller's TTRs can't be forwarded to them. Blocking a user again does nothing.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} response.Response "User blocked successfully"
+// @Failure 400 {object} response.Response "Invalid user ID or blocking yourself"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/blocks/{id} [put]
+func (h *UserHandler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	blockedID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.userService.BlockUser(r.Context(), userID, blockedID); err != nil {
+		response.FromError(w, err, "Failed to block user")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "User blocked successfully"})
+}
+
+// UnblockUser godoc
+// @Summary Unblock user
+// @Description Take a user off the caller's block list.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} response.Response "User unblocked successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "User not blocked"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/blocks/{id} [delete]
+func (h *UserHandler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	blockedID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.userService.UnblockUser(r.Context(), userID, blockedID); err != nil {
+		response.FromError(w, err, "Failed to unblock user")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "User unblocked successfully"})
+}
+
+// GetFriends godoc
+// @Summary List friends
+// @Description List the caller's friends, most recent friendship first. Friends can find the caller's FRIENDS TTRs.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]UserResponse} "Friends retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/friends [get]
+func (h *UserHandler) GetFriends(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	users, err := h.userService.GetFriends(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get friends")
+		return
+	}
+	h.respondUsers(w, r, users)
+}
+
+// GetFriendRequests godoc
+// @Summary List friend requests
+// @Description List the users whose friend requests wait for the caller's answer, most recent request first.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]UserResponse} "Friend requests retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/friends/requests [get]
+func (h *UserHandler) GetFriendRequests(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	users, err := h.userService.GetFriendRequests(r.Context(), userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get friend requests")
+		return
+	}
+	h.respondUsers(w, r, users)
+}
+
+// AddFriend godoc
+// @Summary Add friend
+// @Description Send a user a friend request, or accept the one they sent the caller. status is ACCEPTED once the two are friends and PENDING while the request waits for the user. Sending a request again does nothing.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} response.Response "Friend request sent or accepted"
+// @Failure 400 {object} response.Response "Invalid user ID or befriending yourself"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/friends/{id} [put]
+func (h *UserHandler) AddFriend(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	friendID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	friends, err := h.userService.RequestFriend(r.Context(), userID, friendID)
+	if err != nil {
+		response.FromError(w, err, "Failed to add friend")
+		return
+	}
+
+	status := "PENDING"
+	if friends {
+		status = "ACCEPTED"
+	}
+	response.Success(w, http.StatusOK, map[string]string{"status": status})
+}
+
+// RemoveFriend godoc
+// @Summary Remove friend
+// @Description End the caller's friendship with a user, or withdraw or decline the friend request between them.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} response.Response "Friend removed successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "No friendship or friend request with the user"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/users/me/friends/{id} [delete]
+func (h *UserHandler) RemoveFriend(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	friendID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid user ID")
+		return
+	}
+
+	if err := h.userService.RemoveFriend(r.Context(), userID, friendID); err != nil {
+		response.FromError(w, err, "Failed to remove friend")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Friend removed successfully"})
+}
+
+// respondUsers writes users as the list GetBlockedUsers and the friend
+// lists return.
+func (h *UserHandler) respondUsers(w http.ResponseWriter, r *http.Request, users []service.UserSummary) {
+	v, ok := h.viewer(w, r, users)
+	if !ok {
+		return
+	}
+
+	userResponses := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, FromUser(v, user))
+	}
+
+	response.Success(w, http.StatusOK, userResponses)
+}
+
 // GetUserByID godoc
 // @Summary Get user by ID
-// @Description Get user profile by user ID
+// @Description Get user profile by user ID. Email, phone and handicap are shown as the user's privacy settings allow.
 // @Tags users
 // @Produce json
 // @Security BearerAuth
@@ -283,47 +681,97 @@ func (h *UserHandler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
+	user, ok := h.getUserByID(w, r)
+	if !ok {
+		return
+	}
+	v, ok := h.viewer(w, r, []service.UserSummary{user.UserSummary})
+	if !ok {
+		return
+	}
+
+	userResp := FromUser(v, user.UserSummary)
+	addProfileDetails(&userResp, user)
+
+	response.Success(w, http.StatusOK, userResp)
+}
 
-	userID, err := uuid.Parse(idStr)
+// GetUserByIDV2 godoc
+// @Summary Get user by ID
+// @Description Get a user's public profile by user ID. Contact details are left out.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} response.Response{data=PublicUserResponse} "User profile retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid user ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v2/users/{id} [get]
+func (h *UserHandler) GetUserByIDV2(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.getUserByID(w, r)
+	if !ok {
+		return
+	}
+	v, ok := h.viewer(w, r, []service.UserSummary{user.UserSummary})
+	if !ok {
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromPublicProfile(v, user))
+}
+
+// getUserByID loads the user named in the path, writing the error response
+// itself when the ID is invalid or the user can't be loaded.
+func (h *UserHandler) getUserByID(w http.ResponseWriter, r *http.Request) (*service.UserProfile, bool) {
+	userID, err := uuid.Parse(mux.Vars(r)["id"])
 	if err != nil {
 		response.BadRequest(w, "Invalid user ID")
-		return
+		return nil, false
 	}
 
-	user, err := h.userService.GetUserByID(userID)
+	user, err := h.userService.GetUserByID(r.Context(), userID)
 	if err != nil {
-		if err.Error() == "user not found" {
-			response.NotFound(w, err.Error())
-			return
-		}
-		response.InternalServerError(w, "Failed to get user")
-		return
+		response.FromError(w, err, "Failed to get user")
+		return nil, false
 	}
+	return user, true
+}
 
-	userResp := UserResponse{
-		ID:        user.ID.String(),
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Handicap:  user.Handicap,
-		Phone:     user.Phone,
-		AvatarURL: user.AvatarURL,
-		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+// viewer is the caller of r, knowing which of users play on a TTR the
+// caller captains. Only users hiding their handicap are looked up.
+func (h *UserHandler) viewer(w http.ResponseWriter, r *http.Request, users []service.UserSummary) (Viewer, bool) {
+	v := viewerFrom(r)
+
+	hidden := make([]uuid.UUID, 0)
+	for _, user := range users {
+		if user.ID != v.UserID && !user.Privacy.ShowsHandicap() {
+			hidden = append(hidden, user.ID)
+		}
+	}
+	if len(hidden) == 0 {
+		return v, true
 	}
 
-	response.Success(w, http.StatusOK, userResp)
+	captained, err := h.userService.CaptainedBy(r.Context(), v.UserID, hidden)
+	if err != nil {
+		response.FromError(w, err, "Failed to get users")
+		return Viewer{}, false
+	}
+	v.Captained = captained
+	return v, true
 }
 
 // SearchUsers godoc
 // @Summary Search users
-// @Description Search users by name or email
+// @Description Search users by name, or by exact email address when the query is a full email. Name queries need at least 3 characters.
 // @Tags users
 // @Produce json
 // @Security BearerAuth
 // @Param q query string true "Search query"
+// @Param exclude_self query bool false "Leave the caller out of the results" default(true)
+// @Param exclude_ttr_id query string false "Leave out users already on this TTR (UUID)"
 // @Param limit query int false "Results limit" default(20)
 // @Param offset query int false "Results offset" default(0)
 // @Success 200 {object} response.Response{data=[]UserResponse} "Users retrieved successfully"
@@ -332,48 +780,98 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /api/v1/users [get]
 func (h *UserHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r)
+
+	users, ok := h.searchUsers(w, r, limit, offset)
+	if !ok {
+		return
+	}
+	v, ok := h.viewer(w, r, users)
+	if !ok {
+		return
+	}
+
+	userResponses := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, FromUser(v, user))
+	}
+
+	response.Success(w, http.StatusOK, userResponses)
+}
+
+// SearchUsersV2 godoc
+// @Summary Search users
+// @Description Same search as v1, returning public users wrapped in a page. next_offset is set when more users follow.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param exclude_self query bool false "Leave the caller out of the results" default(true)
+// @Param exclude_ttr_id query string false "Leave out users already on this TTR (UUID)"
+// @Param limit query int false "Results limit" default(20)
+// @Param offset query int false "Results offset" default(0)
+// @Success 200 {object} response.Response{data=PageResponse[PublicUserResponse]} "Users retrieved successfully"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v2/users [get]
+func (h *UserHandler) SearchUsersV2(w http.ResponseWriter, r *http.Request) {
+	limit, offset := pageParams(r)
+
+	users, ok := h.searchUsers(w, r, limit+1, offset)
+	if !ok {
+		return
+	}
+	v, ok := h.viewer(w, r, users)
+	if !ok {
+		return
+	}
+
+	userResponses := make([]PublicUserResponse, 0, len(users))
+	for _, user := range users {
+		userResponses = append(userResponses, FromPublicUser(v, user))
+	}
+
+	response.Success(w, http.StatusOK, newPage(userResponses, limit, offset))
+}
+
+// searchUsers runs the search described by the query parameters, writing the
+// error response itself when the parameters are invalid or the search fails.
+func (h *UserHandler) searchUsers(w http.ResponseWriter, r *http.Request, limit int, offset int) ([]service.UserSummary, bool) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
 	query := r.URL.Query().Get("q")
 	if query == "" {
 		response.BadRequest(w, "Search query is required")
-		return
+		return nil, false
 	}
 
-	limitStr := r.URL.Query().Get("limit")
-	limit := 20
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
+	excludeUserID := userID
+	if excludeSelfStr := r.URL.Query().Get("exclude_self"); excludeSelfStr != "" {
+		excludeSelf, err := strconv.ParseBool(excludeSelfStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid exclude_self value")
+			return nil, false
+		}
+		if !excludeSelf {
+			excludeUserID = uuid.Nil
 		}
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
+	var excludeTTRID uuid.UUID
+	if excludeTTRStr := r.URL.Query().Get("exclude_ttr_id"); excludeTTRStr != "" {
+		id, err := uuid.Parse(excludeTTRStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid exclude_ttr_id")
+			return nil, false
 		}
+		excludeTTRID = id
 	}
 
-	users, err := h.userService.SearchUsers(query, limit, offset)
+	users, err := h.userService.SearchUsers(r.Context(), query, excludeUserID, excludeTTRID, limit, offset)
 	if err != nil {
-		response.InternalServerError(w, "Failed to search users")
-		return
-	}
-
-	userResponses := make([]UserResponse, 0, len(users))
-	for _, user := range users {
-		userResponses = append(userResponses, UserResponse{
-			ID:        user.ID.String(),
-			Email:     user.Email,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Handicap:  user.Handicap,
-			Phone:     user.Phone,
-			AvatarURL: user.AvatarURL,
-			CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt: user.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		})
+		response.FromError(w, err, "Failed to search users")
+		return nil, false
 	}
-
-	response.Success(w, http.StatusOK, userResponses)
+	return users, true
 }
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+type CreateWebhookRequest struct {
+	URL            string   `json:"url" validate:"required,max=2048"`
+	Events         []string `json:"events" validate:"required,min=1"`
+	OrganizationID string   `json:"organization_id" validate:"omitempty,uuid"`
+}
+
+type UpdateWebhookRequest struct {
+	URL    *string  `json:"url" validate:"omitempty,max=2048"`
+	Events []string `json:"events" validate:"omitempty,min=1"`
+}
+
+type WebhookResponse struct {
+	ID                  string   `json:"id"`
+	OwnerUserID         string   `json:"owner_user_id"`
+	OrganizationID      *string  `json:"organization_id,omitempty"`
+	URL                 string   `json:"url"`
+	Events              []string `json:"events"`
+	Status              string   `json:"status"`
+	ConsecutiveFailures int      `json:"consecutive_failures"`
+	Secret              string   `json:"secret,omitempty"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID         string  `json:"id"`
+	EventID    string  `json:"event_id"`
+	Event      string  `json:"event"`
+	Attempt    int     `json:"attempt"`
+	StatusCode *int    `json:"status_code,omitempty"`
+	Error      *string `json:"error,omitempty"`
+	Succeeded  bool    `json:"succeeded"`
+	DurationMs int64   `json:"duration_ms"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+// CreateWebhook godoc
+// @Summary Create webhook
+// @Description Register a URL to receive TTR events: ttr.created, ttr.updated, ttr.cancelled and invitation.responded. Without organization_id the webhook gets events for TTRs the caller captains; with it, events for the organization's TTRs, and only the organization's owners and admins can create one. Events are POSTed as JSON with an X-Signature header holding the hex HMAC-SHA256 of the body, keyed with the secret returned here and never shown again. Failed deliveries are retried with exponential backoff.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateWebhookRequest true "Webhook details"
+// @Success 201 {object} response.Response{data=WebhookResponse} "Webhook created successfully"
+// @Failure 400 {object} response.Response "Invalid URL or events"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an organization owner or admin"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	var organizationID *uuid.UUID
+	if req.OrganizationID != "" {
+		parsed, err := uuid.Parse(req.OrganizationID)
+		if err != nil {
+			response.BadRequest(w, "Invalid organization_id")
+			return
+		}
+		organizationID = &parsed
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), userID, organizationID, req.URL, req.Events)
+	if err != nil {
+		response.FromError(w, err, "Failed to create webhook")
+		return
+	}
+
+	resp := FromWebhook(webhook)
+	resp.Secret = webhook.Secret
+	response.Success(w, http.StatusCreated, resp)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description List the caller's own webhooks or, with organization_id, the organization's. Only the organization's owners and admins can list its webhooks.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param organization_id query string false "Organization ID (UUID)"
+// @Success 200 {object} response.Response{data=[]WebhookResponse} "Webhooks retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid organization_id"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 403 {object} response.Response "Forbidden - not an organization owner or admin"
+// @Failure 404 {object} response.Response "Organization not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	var organizationID *uuid.UUID
+	if orgIDStr := r.URL.Query().Get("organization_id"); orgIDStr != "" {
+		parsed, err := uuid.Parse(orgIDStr)
+		if err != nil {
+			response.BadRequest(w, "Invalid organization_id")
+			return
+		}
+		organizationID = &parsed
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(r.Context(), userID, organizationID)
+	if err != nil {
+		response.FromError(w, err, "Failed to list webhooks")
+		return
+	}
+
+	webhookResponses := make([]WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, FromWebhook(webhook))
+	}
+
+	response.Success(w, http.StatusOK, webhookResponses)
+}
+
+// GetWebhook godoc
+// @Summary Get webhook by ID
+// @Description Get a webhook, including its status. A webhook is FAILING after too many failed deliveries in a row and gets no events until it is updated.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} response.Response{data=WebhookResponse} "Webhook retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid webhook ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Webhook not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	webhookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid webhook ID")
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(r.Context(), webhookID, userID)
+	if err != nil {
+		response.FromError(w, err, "Failed to get webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromWebhook(webhook))
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook
+// @Description Change a webhook's URL or events. Updating a FAILING webhook makes it ACTIVE again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID (UUID)"
+// @Param request body UpdateWebhookRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=WebhookResponse} "Webhook updated successfully"
+// @Failure 400 {object} response.Response "Invalid URL or events"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Webhook not found"
+// @Failure 422 {object} response.Response "Validation error"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	webhookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid webhook ID")
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.BadRequest(w, "Invalid request body")
+		return
+	}
+
+	if err := validator.Validate(&req); err != nil {
+		errors := validator.FormatValidationErrors(i18n.FromContext(r.Context()), err)
+		response.UnprocessableEntity(w, "Validation failed", errors)
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(r.Context(), webhookID, userID, req.URL, req.Events)
+	if err != nil {
+		response.FromError(w, err, "Failed to update webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, FromWebhook(webhook))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description Delete a webhook and its delivery log.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID (UUID)"
+// @Success 200 {object} response.Response{data=map[string]string} "Webhook deleted successfully"
+// @Failure 400 {object} response.Response "Invalid webhook ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Webhook not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	webhookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), webhookID, userID); err != nil {
+		response.FromError(w, err, "Failed to delete webhook")
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "Webhook deleted successfully"})
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description List a webhook's delivery attempts, newest first. Retries of one event share its event_id.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Webhook ID (UUID)"
+// @Param limit query int false "Number of deliveries to return (default 20)"
+// @Param offset query int false "Number of deliveries to skip (default 0)"
+// @Success 200 {object} response.Response{data=[]WebhookDeliveryResponse} "Deliveries retrieved successfully"
+// @Failure 400 {object} response.Response "Invalid webhook ID"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "Webhook not found"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+
+	webhookID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		response.BadRequest(w, "Invalid webhook ID")
+		return
+	}
+
+	limit, offset := pageParams(r)
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), webhookID, userID, limit, offset)
+	if err != nil {
+		response.FromError(w, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	deliveryResponses := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, FromWebhookDelivery(delivery))
+	}
+
+	response.Success(w, http.StatusOK, deliveryResponses)
+}
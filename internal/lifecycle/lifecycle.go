@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Manager owns the long-lived components of the process. Background workers
+// are started with Go or Every and receive a context that is canceled during
+// Shutdown; components that need an explicit stop (HTTP server, services with
+// queued work) register a hook with OnShutdown.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	hooks []hook
+	wg    sync.WaitGroup
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func NewManager(logger *zap.Logger) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		ctx:    ctx,
+		cancel: cancel,
+		logger: logger,
+	}
+}
+
+// Context returns the root context shared by all workers. It is canceled once
+// Shutdown has stopped every registered hook.
+func (m *Manager) Context() context.Context {
+	return m.ctx
+}
+
+// Go runs fn in its own goroutine. fn should return once ctx is canceled,
+// after finishing the item it is currently working on.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if err := fn(m.ctx); err != nil && !errors.Is(err, context.Canceled) {
+			m.logger.Error("Background worker failed", zap.String("worker", name), zap.Error(err))
+		}
+	}()
+}
+
+// Every runs fn every interval until shutdown. Errors are logged and do not
+// stop the worker.
+func (m *Manager) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	m.Go(name, func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				if err := fn(ctx); err != nil {
+					m.logger.Error("Periodic task failed", zap.String("task", name), zap.Error(err))
+				}
+			}
+		}
+	})
+}
+
+// OnShutdown registers fn to be called during Shutdown. Hooks run in reverse
+// registration order, like deferred calls.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown runs the shutdown hooks, then cancels the worker context and waits
+// for all workers to return. Everything shares the deadline of ctx; if it
+// expires first the workers still running are abandoned and an error is
+// returned.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := make([]hook, len(m.hooks))
+	copy(hooks, m.hooks)
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if err := h.fn(ctx); err != nil {
+			m.logger.Error("Shutdown hook failed", zap.String("component", h.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("background workers did not stop: %w", ctx.Err()))
+	}
+
+	return errors.Join(errs...)
+}
@@ -11,25 +11,34 @@ import (
 var Log *zap.Logger
 
 func Initialize(cfg *config.Config) error {
+	logger, _, err := NewLogger(&cfg.Logging)
+	if err != nil {
+		return err
+	}
+
+	Log = logger
+	return nil
+}
+
+// NewLogger builds a zap logger from cfg. The returned AtomicLevel controls the
+// logger's level and can be changed at runtime.
+func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, zap.AtomicLevel, error) {
 	var zapConfig zap.Config
 
-	if cfg.Logging.Encoding == "json" {
+	if cfg.Encoding == "json" {
 		zapConfig = zap.NewProductionConfig()
 	} else {
 		zapConfig = zap.NewDevelopmentConfig()
 	}
 
-	level, err := parseLogLevel(cfg.Logging.Level)
-	if err != nil {
-		return err
-	}
-	zapConfig.Level = zap.NewAtomicLevelAt(level)
+	level := zap.NewAtomicLevelAt(parseLogLevel(cfg.Level))
+	zapConfig.Level = level
 
-	if len(cfg.Logging.OutputPaths) > 0 {
-		zapConfig.OutputPaths = cfg.Logging.OutputPaths
+	if len(cfg.OutputPaths) > 0 {
+		zapConfig.OutputPaths = cfg.OutputPaths
 	}
-	if len(cfg.Logging.ErrorOutputPaths) > 0 {
-		zapConfig.ErrorOutputPaths = cfg.Logging.ErrorOutputPaths
+	if len(cfg.ErrorOutputPaths) > 0 {
+		zapConfig.ErrorOutputPaths = cfg.ErrorOutputPaths
 	}
 
 	zapConfig.EncoderConfig.TimeKey = "timestamp"
@@ -37,14 +46,14 @@ func Initialize(cfg *config.Config) error {
 
 	logger, err := zapConfig.Build()
 	if err != nil {
-		return fmt.Errorf("failed to initialize logger: %w", err)
+		return nil, level, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
-	Log = logger
-	return nil
+	return logger, level, nil
 }
 
-func parseLogLevel(level string) (zapcore.Level, error) {
+// ParseLevel converts a level name to a zapcore.Level, rejecting unknown names.
+func ParseLevel(level string) (zapcore.Level, error) {
 	switch level {
 	case "debug":
 		return zapcore.DebugLevel, nil
@@ -59,8 +68,18 @@ func parseLogLevel(level string) (zapcore.Level, error) {
 	case "panic":
 		return zapcore.PanicLevel, nil
 	default:
-		return zapcore.InfoLevel, nil
+		return zapcore.InfoLevel, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// parseLogLevel falls back to info for unknown names so a typo in config
+// does not prevent startup.
+func parseLogLevel(level string) zapcore.Level {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return zapcore.InfoLevel
 	}
+	return parsed
 }
 
 func Sync() error {
@@ -5,18 +5,62 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
 	"github.com/yourusername/golf_messenger/pkg/jwt"
 	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/scope"
 )
 
 type contextKey string
 
 const (
-	UserIDKey  contextKey = "user_id"
-	EmailKey   contextKey = "email"
+	UserIDKey    contextKey = "user_id"
+	EmailKey     contextKey = "email"
+	RoleKey      contextKey = "role"
+	RequestIDKey contextKey = "request_id"
+	// AuthTypeKey holds AuthTypeJWT, AuthTypeAPIToken or AuthTypeSignature.
+	AuthTypeKey contextKey = "auth_type"
+	// ScopesKey holds the []scope.Scope the request's token carries.
+	ScopesKey contextKey = "scopes"
+	// ImpersonatorIDKey holds the uuid.UUID of the admin behind an
+	// impersonation token; UserIDKey is the user they act as.
+	ImpersonatorIDKey contextKey = "impersonator_id"
+	// ImpersonationSessionKey holds the impersonation session's uuid.UUID.
+	ImpersonationSessionKey contextKey = "impersonation_session"
+	// ImpersonationAllowWritesKey holds whether the impersonation session
+	// may change things.
+	ImpersonationAllowWritesKey contextKey = "impersonation_allow_writes"
+	// ServiceClientKey holds the key ID of the internal client that signed
+	// the request.
+	ServiceClientKey contextKey = "service_client"
 )
 
-func Auth(jwtSecret string) func(http.Handler) http.Handler {
+// How a request authenticated.
+const (
+	AuthTypeJWT       = "jwt"
+	AuthTypeAPIToken  = "api_token"
+	AuthTypeSignature = "signature"
+)
+
+// APITokenAuthenticator resolves a personal API token to the stored token,
+// with its User loaded, or nil when the token is not valid.
+type APITokenAuthenticator interface {
+	AuthenticateAPIToken(ctx context.Context, token string) (*models.APIToken, error)
+}
+
+// ImpersonationAuditor checks that impersonation sessions are still open and
+// records the requests made in them.
+type ImpersonationAuditor interface {
+	ImpersonationActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	RecordImpersonatedRequest(ctx context.Context, sessionID, actorID, userID uuid.UUID, method, path string, status int, requestID string) error
+}
+
+// Auth accepts a JWT access token or, when apiTokens is set, a personal API
+// token, told apart by models.APITokenPrefix. Impersonation tokens are only
+// accepted when impersonations is set, and only while their session is open.
+func Auth(jwtSecret string, apiTokens APITokenAuthenticator, impersonations ImpersonationAuditor) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -33,20 +77,127 @@ func Auth(jwtSecret string) func(http.Handler) http.Handler {
 
 			tokenString := parts[1]
 
+			if apiTokens != nil && strings.HasPrefix(tokenString, models.APITokenPrefix) {
+				apiToken, err := apiTokens.AuthenticateAPIToken(r.Context(), tokenString)
+				if err != nil {
+					response.InternalServerError(w, "Failed to authenticate")
+					return
+				}
+				if apiToken == nil {
+					response.Unauthorized(w, "Invalid token")
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), UserIDKey, apiToken.User.ID)
+				ctx = context.WithValue(ctx, EmailKey, apiToken.User.Email)
+				ctx = context.WithValue(ctx, RoleKey, apiToken.User.Role)
+				ctx = context.WithValue(ctx, AuthTypeKey, AuthTypeAPIToken)
+				ctx = context.WithValue(ctx, ScopesKey, apiToken.ScopeList())
+				setIdentity(ctx, apiToken.User.ID, uuid.Nil)
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			claims, err := jwt.ValidateAccessToken(tokenString, jwtSecret)
 			if err != nil {
 				if err == jwt.ErrExpiredToken {
 					response.Unauthorized(w, "Token has expired")
 					return
 				}
+				if err == jwt.ErrWrongTokenType {
+					response.Coded(w, errcode.WrongTokenType, "Not an access token")
+					return
+				}
 				response.Unauthorized(w, "Invalid token")
 				return
 			}
 
+			scopes := scope.All()
+			if claims.Scope != "" {
+				scopes, err = scope.ParseList(strings.Fields(claims.Scope))
+				if err != nil {
+					response.Unauthorized(w, "Invalid token")
+					return
+				}
+			}
+
 			ctx := context.WithValue(r.Context(), UserIDKey, claims.UserID)
 			ctx = context.WithValue(ctx, EmailKey, claims.Email)
+			ctx = context.WithValue(ctx, RoleKey, claims.Role)
+			ctx = context.WithValue(ctx, AuthTypeKey, AuthTypeJWT)
+			ctx = context.WithValue(ctx, ScopesKey, scopes)
+
+			impersonatorID := uuid.Nil
+			if claims.Act != nil {
+				sessionID, err := uuid.Parse(claims.ID)
+				if impersonations == nil || err != nil {
+					response.Unauthorized(w, "Invalid token")
+					return
+				}
+				active, err := impersonations.ImpersonationActive(ctx, sessionID)
+				if err != nil {
+					response.InternalServerError(w, "Failed to authenticate")
+					return
+				}
+				if !active {
+					response.Unauthorized(w, "Impersonation session has ended")
+					return
+				}
+
+				impersonatorID = claims.Act.Subject
+				ctx = context.WithValue(ctx, ImpersonatorIDKey, impersonatorID)
+				ctx = context.WithValue(ctx, ImpersonationSessionKey, sessionID)
+				ctx = context.WithValue(ctx, ImpersonationAllowWritesKey, claims.Act.AllowWrites)
+			}
+			setIdentity(ctx, claims.UserID, impersonatorID)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// RequireRole rejects requests whose access token does not carry the given
+// role. It must run after Auth.
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if userRole, _ := r.Context().Value(RoleKey).(string); userRole != role {
+				response.Forbidden(w, "Insufficient permissions")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope rejects requests whose token does not carry s. It must run
+// after Auth.
+func RequireScope(s scope.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if granted, _ := r.Context().Value(ScopesKey).([]scope.Scope); !scope.Has(granted, s) {
+				response.CodedWithDetails(w, errcode.InsufficientScope, "Token is missing a required scope", map[string]string{
+					"required_scope": string(s),
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RejectAPITokens keeps personal API tokens away from account security:
+// requests authenticated with one are refused. It must run after Auth.
+func RejectAPITokens(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authType, _ := r.Context().Value(AuthTypeKey).(string); authType == AuthTypeAPIToken {
+			response.Coded(w, errcode.APITokenNotAllowed, "API tokens cannot be used for this request")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
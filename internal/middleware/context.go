@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+)
+
+// RequestContext derives each request's context with fn, e.g. to attach
+// caches that should live for a single request.
+func RequestContext(fn func(context.Context) context.Context) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(fn(r.Context())))
+		})
+	}
+}
+
+// ClientInfo stores each request's client IP address, as clients finds it,
+// and user agent in its context with fn.
+func ClientInfo(clients *clientip.Resolver, fn func(ctx context.Context, ipAddress, userAgent string) context.Context) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(fn(r.Context(), clients.ClientIP(r), r.UserAgent())))
+		})
+	}
+}
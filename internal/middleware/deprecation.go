@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// Deprecation marks every response as coming from a deprecated API version
+// (RFC 8594): it sets Deprecation, a Sunset header announcing the retirement
+// date, and a Link to the successor version's base path.
+func Deprecation(sunset time.Time, successor string) func(http.Handler) http.Handler {
+	sunsetHeader := sunset.UTC().Format(http.TimeFormat)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", sunsetHeader)
+			w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
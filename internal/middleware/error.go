@@ -1,19 +1,31 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/pkg/response"
 	"go.uber.org/zap"
 )
 
+// ErrorRecovery answers a request that panicked with a 500. It should run
+// outermost, so it assigns each request the ID that Logging logs and
+// RequestID returns, and logs a panic with it.
 func ErrorRecovery(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := RequestID(r.Context())
+			if requestID == "" {
+				requestID = uuid.New().String()
+				r = r.WithContext(context.WithValue(r.Context(), RequestIDKey, requestID))
+			}
+
 			defer func() {
 				if err := recover(); err != nil {
 					logger.Error("panic recovered",
 						zap.Any("error", err),
+						zap.String("request_id", requestID),
 						zap.String("method", r.Method),
 						zap.String("path", r.URL.Path),
 					)
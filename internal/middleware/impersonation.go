@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"go.uber.org/zap"
+)
+
+// Impersonator returns the admin acting as the request's user, if the request
+// uses an impersonation token.
+func Impersonator(r *http.Request) (uuid.UUID, bool) {
+	actorID, ok := r.Context().Value(ImpersonatorIDKey).(uuid.UUID)
+	return actorID, ok
+}
+
+// ImpersonationGuard writes every request made with an impersonation token
+// to the audit log, and refuses requests that could change something unless
+// the session allows writes. Other requests pass straight through. It must
+// run after Auth.
+func ImpersonationGuard(auditor ImpersonationAuditor, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actorID, ok := Impersonator(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			sessionID, _ := ctx.Value(ImpersonationSessionKey).(uuid.UUID)
+			userID, _ := ctx.Value(UserIDKey).(uuid.UUID)
+			allowWrites, _ := ctx.Value(ImpersonationAllowWritesKey).(bool)
+
+			rw := &responseWriter{
+				ResponseWriter: w,
+				statusCode:     http.StatusOK,
+			}
+			if !allowWrites && !isReadOnlyMethod(r.Method) {
+				response.Coded(rw, errcode.ImpersonationReadOnly, "Impersonation session is read-only")
+			} else {
+				next.ServeHTTP(rw, r)
+			}
+
+			if err := auditor.RecordImpersonatedRequest(ctx, sessionID, actorID, userID, r.Method, r.URL.Path, rw.statusCode, RequestID(ctx)); err != nil {
+				logger.Error("Failed to audit impersonated request",
+					zap.String("request_id", RequestID(ctx)),
+					zap.String("session_id", sessionID.String()),
+					zap.Error(err),
+				)
+			}
+		})
+	}
+}
+
+// RejectImpersonation keeps impersonation sessions away from account
+// security: requests made with an impersonation token are refused, even when
+// the session allows writes. It must run after Auth.
+func RejectImpersonation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := Impersonator(r); ok {
+			response.Coded(w, errcode.ImpersonationDenied, "Impersonation sessions cannot be used for this request")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -1,10 +1,15 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -25,10 +30,59 @@ func (rw *responseWriter) Write(b []byte) (int, error) {
 	return n, err
 }
 
+const (
+	queryCountKey contextKey = "query_count"
+	identityKey   contextKey = "identity"
+)
+
+// requestIdentity is who a request authenticated as. Auth fills it in so the
+// request-completed log line can name the user and, when an admin is
+// impersonating them, the admin.
+type requestIdentity struct {
+	userID         uuid.UUID
+	impersonatorID uuid.UUID
+}
+
+func setIdentity(ctx context.Context, userID, impersonatorID uuid.UUID) {
+	if identity, ok := ctx.Value(identityKey).(*requestIdentity); ok {
+		identity.userID = userID
+		identity.impersonatorID = impersonatorID
+	}
+}
+
+// RequestID returns the ID ErrorRecovery or Logging assigned to the request in
+// ctx, or an empty string outside a request.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(RequestIDKey).(string)
+	return requestID
+}
+
+// CountQuery records a database query against the request in ctx so it shows
+// up in the request-completed log line. It does nothing outside a request.
+func CountQuery(ctx context.Context) {
+	if queries, ok := ctx.Value(queryCountKey).(*atomic.Int64); ok {
+		queries.Add(1)
+	}
+}
+
+// Logging logs each request as it comes in and once it completes. Requests
+// keep the ID an outer ErrorRecovery gave them. A request that panics is
+// logged as a 500, the answer ErrorRecovery gives it, and the panic carries on.
 func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			requestID := uuid.New().String()
+			ctx := r.Context()
+			requestID := RequestID(ctx)
+			if requestID == "" {
+				requestID = uuid.New().String()
+				ctx = context.WithValue(ctx, RequestIDKey, requestID)
+			}
+			queries := new(atomic.Int64)
+			identity := new(requestIdentity)
+			ctx = context.WithValue(ctx, queryCountKey, queries)
+			ctx = context.WithValue(ctx, identityKey, identity)
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("request_id", requestID))
+			traceFields := tracing.LogFields(ctx)
 
 			start := time.Now()
 
@@ -40,25 +94,44 @@ func Logging(logger *zap.Logger) func(http.Handler) http.Handler {
 			rw.Header().Set("X-Request-ID", requestID)
 
 			logger.Info("incoming request",
-				zap.String("request_id", requestID),
-				zap.String("method", r.Method),
-				zap.String("path", r.URL.Path),
-				zap.String("remote_addr", r.RemoteAddr),
-				zap.String("user_agent", r.UserAgent()),
+				append([]zap.Field{
+					zap.String("request_id", requestID),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("remote_addr", r.RemoteAddr),
+					zap.String("user_agent", r.UserAgent()),
+				}, traceFields...)...,
 			)
 
-			next.ServeHTTP(rw, r)
+			defer func() {
+				panicked := recover()
+				if panicked != nil {
+					rw.statusCode = http.StatusInternalServerError
+				}
 
-			duration := time.Since(start)
+				fields := []zap.Field{
+					zap.String("request_id", requestID),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.Int("status_code", rw.statusCode),
+					zap.Int64("response_size", rw.written),
+					zap.Duration("duration", time.Since(start)),
+					zap.Int64("db_queries", queries.Load()),
+				}
+				if identity.userID != uuid.Nil {
+					fields = append(fields, zap.String("user_id", identity.userID.String()))
+				}
+				if identity.impersonatorID != uuid.Nil {
+					fields = append(fields, zap.String("impersonator_id", identity.impersonatorID.String()))
+				}
+				logger.Info("request completed", append(fields, traceFields...)...)
 
-			logger.Info("request completed",
-				zap.String("request_id", requestID),
-				zap.String("method", r.Method),
-				zap.String("path", r.URL.Path),
-				zap.Int("status_code", rw.statusCode),
-				zap.Int64("response_size", rw.written),
-				zap.Duration("duration", duration),
-			)
+				if panicked != nil {
+					panic(panicked)
+				}
+			}()
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
 		})
 	}
 }
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+// MaintenanceChecker reports whether the API is down for maintenance and,
+// if known, when that is expected to end.
+type MaintenanceChecker interface {
+	Maintenance(ctx context.Context) (bool, *time.Time)
+}
+
+// Maintenance answers every request with a 503 MAINTENANCE while checker
+// reports maintenance, except those exempt allows through, such as health
+// checks and the switch itself. It must run before Auth so that nothing
+// touches the database.
+func Maintenance(checker MaintenanceChecker, exempt func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			active, endsAt := checker.Maintenance(r.Context())
+			if !active || exempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			details := map[string]interface{}{}
+			if endsAt != nil {
+				details["ends_at"] = endsAt.UTC().Format(time.RFC3339)
+				if endsAt.After(time.Now()) {
+					retryAfter := int(time.Until(*endsAt).Seconds()) + 1
+					w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				}
+			}
+			response.CodedWithDetails(w, errcode.Maintenance, "We're down for maintenance and will be back shortly", details)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"go.uber.org/zap"
+)
+
+// RateLimit rejects requests once the client IP, as clients finds it, exceeds
+// the limiter's window. If the limiter itself fails the request is let
+// through so a Redis outage does not take the API down with it.
+func RateLimit(limiter ratelimit.RateLimiter, clients *clientip.Resolver, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := limiter.Allow(r.Context(), clients.ClientIP(r))
+			if err != nil {
+				logger.Error("Rate limiter unavailable", zap.Error(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+			if !result.Allowed {
+				retryAfter := int(time.Until(result.ResetAt).Seconds()) + 1
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				response.TooManyRequests(w, "Too many requests, please try again later")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
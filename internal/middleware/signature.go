@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/signing"
+	"go.uber.org/zap"
+)
+
+// maxSignedBodySize caps the body read to verify a signed request.
+const maxSignedBodySize = 32 << 20
+
+// ServiceClient returns the key ID of the internal client that signed the
+// request, if it is a signed request.
+func ServiceClient(r *http.Request) (string, bool) {
+	keyID, ok := r.Context().Value(ServiceClientKey).(string)
+	return keyID, ok
+}
+
+// SignedAuth authenticates requests signed by an internal client, as a
+// service principal carrying the client's scopes. The body is read to check
+// the signature and handed on unchanged.
+func SignedAuth(verifier *signing.Verifier, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBodySize))
+				if err != nil {
+					response.BadRequest(w, "Invalid request body")
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			client, err := verifier.Verify(r.Context(), r.Method, r.URL.RequestURI(), r.Header, body, time.Now())
+			switch {
+			case errors.Is(err, signing.ErrStaleRequest):
+				response.Unauthorized(w, "Request signature has expired")
+				return
+			case errors.Is(err, signing.ErrReplayedRequest):
+				response.Unauthorized(w, "Request has already been used")
+				return
+			case errors.Is(err, signing.ErrMissingSignature), errors.Is(err, signing.ErrUnknownKey), errors.Is(err, signing.ErrInvalidSignature):
+				response.Unauthorized(w, "Invalid request signature")
+				return
+			case err != nil:
+				logger.Error("Failed to verify request signature", zap.String("request_id", RequestID(r.Context())), zap.Error(err))
+				response.InternalServerError(w, "Failed to authenticate")
+				return
+			}
+
+			principalID := client.PrincipalID()
+			ctx := context.WithValue(r.Context(), UserIDKey, principalID)
+			ctx = context.WithValue(ctx, EmailKey, "")
+			ctx = context.WithValue(ctx, RoleKey, models.UserRoleService)
+			ctx = context.WithValue(ctx, AuthTypeKey, AuthTypeSignature)
+			ctx = context.WithValue(ctx, ScopesKey, client.GrantedScopes())
+			ctx = context.WithValue(ctx, ServiceClientKey, client.KeyID)
+			setIdentity(ctx, principalID, uuid.Nil)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
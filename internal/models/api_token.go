@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"gorm.io/gorm"
+)
+
+// APITokenPrefix starts every personal API token, which is how Auth tells
+// them apart from JWTs.
+const APITokenPrefix = "gm_pat_"
+
+// APIToken is a long-lived personal token for scripts. Only the token's hash
+// is stored; Prefix is its first few characters, kept so owners can tell
+// their tokens apart. Scopes is a comma-separated list of the scopes the
+// token grants. A nil ExpiresAt never expires.
+type APIToken struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"`
+	TokenHash  string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"type:varchar(512);not null;default:''" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+}
+
+func (t *APIToken) TableName() string {
+	return "api_tokens"
+}
+
+func (t *APIToken) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// ScopeList splits Scopes.
+func (t *APIToken) ScopeList() []scope.Scope {
+	if t.Scopes == "" {
+		return nil
+	}
+	names := strings.Split(t.Scopes, ",")
+	scopes := make([]scope.Scope, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, scope.Scope(name))
+	}
+	return scopes
+}
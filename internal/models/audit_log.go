@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Audit log actions.
+const (
+	AuditActionImpersonationStart  = "impersonation.start"
+	AuditActionImpersonationStop   = "impersonation.stop"
+	AuditActionImpersonatedRequest = "impersonation.request"
+	// Inactive account actions are taken by the system, so their actor is
+	// the zero UUID and the user is the subject.
+	AuditActionInactivityWarned = "account.inactivity_warned"
+	AuditActionDeactivated      = "account.deactivated"
+	AuditActionImported         = "account.imported"
+)
+
+// AuditLogEntry records something ActorUserID did, for support and security
+// reviews. SubjectUserID is the user it was done to or as, and SessionID the
+// impersonation session it happened in, if any. Details is a short
+// description such as the method and path of a request.
+type AuditLogEntry struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Action        string     `gorm:"type:varchar(64);not null;index" json:"action"`
+	ActorUserID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"actor_user_id"`
+	SubjectUserID *uuid.UUID `gorm:"type:uuid;index" json:"subject_user_id,omitempty"`
+	SessionID     *uuid.UUID `gorm:"type:uuid;index" json:"session_id,omitempty"`
+	Details       string     `gorm:"type:text" json:"details"`
+	RequestID     string     `gorm:"type:varchar(64)" json:"request_id"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+func (e *AuditLogEntry) TableName() string {
+	return "audit_log_entries"
+}
+
+func (e *AuditLogEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Reasons an address is suppressed.
+const (
+	SuppressionReasonHardBounce = "HARD_BOUNCE"
+	SuppressionReasonSoftBounce = "SOFT_BOUNCE"
+	SuppressionReasonComplaint  = "COMPLAINT"
+)
+
+// EmailSuppression is what the email providers have told us about a
+// normalized address. SoftBounces counts soft bounces since the last
+// delivery; once the address is suppressed, SuppressedAt is set and no more
+// mail is sent to it. LastEventID is the provider's ID for the last event
+// recorded, so a redelivered event isn't counted twice.
+type EmailSuppression struct {
+	Email        string     `gorm:"type:varchar(255);primary_key" json:"email"`
+	Reason       string     `gorm:"type:varchar(20);not null;default:''" json:"reason"`
+	SoftBounces  int        `gorm:"not null;default:0" json:"soft_bounces"`
+	SuppressedAt *time.Time `json:"suppressed_at,omitempty"`
+	LastEventID  string     `gorm:"type:varchar(255);not null;default:''" json:"-"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (s *EmailSuppression) TableName() string {
+	return "email_suppressions"
+}
+
+// Suppressed reports whether mail to the address is no longer sent.
+func (s *EmailSuppression) Suppressed() bool {
+	return s.SuppressedAt != nil
+}
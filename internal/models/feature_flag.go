@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/pkg/featureflag"
+)
+
+// FeatureFlag is a flag admins manage at runtime. It replaces any rollout of
+// the same flag in the config. UserIDs and OrganizationIDs are
+// comma-separated lists of the users and organizations the flag is always on
+// for, while Enabled.
+type FeatureFlag struct {
+	Key             string    `gorm:"type:varchar(64);primary_key" json:"key"`
+	Description     string    `gorm:"type:varchar(500);not null;default:''" json:"description"`
+	Enabled         bool      `gorm:"not null;default:false" json:"enabled"`
+	Percentage      int       `gorm:"not null;default:0" json:"percentage"`
+	UserIDs         string    `gorm:"type:text;not null;default:''" json:"user_ids"`
+	OrganizationIDs string    `gorm:"type:text;not null;default:''" json:"organization_ids"`
+	CreatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (f *FeatureFlag) TableName() string {
+	return "feature_flags"
+}
+
+// Rule is the flag's rollout. IDs in the lists that don't parse are skipped.
+func (f *FeatureFlag) Rule() featureflag.Rule {
+	return featureflag.Rule{
+		Enabled:       f.Enabled,
+		Percentage:    f.Percentage,
+		Users:         splitIDs(f.UserIDs),
+		Organizations: splitIDs(f.OrganizationIDs),
+	}
+}
+
+// JoinIDs is the inverse of the lists Rule reads.
+func JoinIDs(ids []uuid.UUID) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names = append(names, id.String())
+	}
+	return strings.Join(names, ",")
+}
+
+func splitIDs(list string) []uuid.UUID {
+	if list == "" {
+		return nil
+	}
+	var ids []uuid.UUID
+	for _, name := range strings.Split(list, ",") {
+		if id, err := uuid.Parse(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// stampCreated sets the zero timestamps of a row being created to now, in Go
+// rather than through column defaults, which sqlite fills in differently.
+func stampCreated(timestamps ...*time.Time) {
+	now := time.Now()
+	for _, ts := range timestamps {
+		if ts.IsZero() {
+			*ts = now
+		}
+	}
+}
+
+// savesRow reports whether tx writes the whole of row, as Save does, rather
+// than a few columns through a bare model.
+func savesRow(tx *gorm.DB, row interface{}) bool {
+	return tx.Statement.Dest == row
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpersonationSession is an admin, ActorUserID, acting as TargetUserID. Its
+// ID is the ID of the impersonation token, which stops working once the
+// session ends or expires. Without AllowWrites the session is read-only.
+type ImpersonationSession struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	ActorUserID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"actor_user_id"`
+	TargetUserID uuid.UUID  `gorm:"type:uuid;not null;index" json:"target_user_id"`
+	Reason       string     `gorm:"type:varchar(500);not null;default:''" json:"reason"`
+	AllowWrites  bool       `gorm:"not null;default:false" json:"allow_writes"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (s *ImpersonationSession) TableName() string {
+	return "impersonation_sessions"
+}
+
+func (s *ImpersonationSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the session has neither ended nor expired.
+func (s *ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -4,30 +4,48 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
-	InvitationStatusPending  = "PENDING"
-	InvitationStatusYes      = "YES"
-	InvitationStatusNo       = "NO"
-	InvitationStatusMaybe    = "MAYBE"
-	InvitationStatusCanceled = "CANCELED"
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusYes      InvitationStatus = "YES"
+	InvitationStatusNo       InvitationStatus = "NO"
+	InvitationStatusMaybe    InvitationStatus = "MAYBE"
+	InvitationStatusCanceled InvitationStatus = "CANCELED"
+	InvitationStatusExpired  InvitationStatus = "EXPIRED"
+	// InvitationStatusForwardRequested is an invitation an invitee passed
+	// on to someone else. It waits for a captain to approve it, when it
+	// becomes PENDING.
+	InvitationStatusForwardRequested InvitationStatus = "FORWARD_REQUESTED"
 )
 
 type Invitation struct {
-	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	TTRID         uuid.UUID  `gorm:"type:uuid;not null" json:"ttr_id"`
-	InviterUserID uuid.UUID  `gorm:"type:uuid;not null" json:"inviter_user_id"`
-	InviteeUserID uuid.UUID  `gorm:"type:uuid;not null" json:"invitee_user_id"`
-	Status        string     `gorm:"type:varchar(50);default:'PENDING'" json:"status"`
-	Message       *string    `gorm:"type:text" json:"message,omitempty"`
-	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	RespondedAt   *time.Time `json:"responded_at,omitempty"`
-	TTR           *TTR       `gorm:"foreignKey:TTRID" json:"ttr,omitempty"`
-	InviterUser   *User      `gorm:"foreignKey:InviterUserID" json:"inviter_user,omitempty"`
-	InviteeUser   *User      `gorm:"foreignKey:InviteeUserID" json:"invitee_user,omitempty"`
+	ID            uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID         uuid.UUID        `gorm:"type:uuid;not null;index:idx_invitations_ttr_invitee_status,priority:1;uniqueIndex:idx_invitations_pending,where:status = 'PENDING'" json:"ttr_id"`
+	InviterUserID uuid.UUID        `gorm:"type:uuid;not null" json:"inviter_user_id"`
+	InviteeUserID uuid.UUID        `gorm:"type:uuid;not null;index:idx_invitations_ttr_invitee_status,priority:2;uniqueIndex:idx_invitations_pending,where:status = 'PENDING'" json:"invitee_user_id"`
+	Status        InvitationStatus `gorm:"type:varchar(50);not null;default:'PENDING';check:chk_invitations_status,status IN ('PENDING','YES','NO','MAYBE','CANCELED','EXPIRED','FORWARD_REQUESTED');index:idx_invitations_ttr_invitee_status,priority:3" json:"status"`
+	Message       *string          `gorm:"type:text" json:"message,omitempty"`
+	DeclineReason *string          `gorm:"type:varchar(280)" json:"decline_reason,omitempty"`
+	CreatedAt     time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	RespondedAt   *time.Time       `json:"responded_at,omitempty"`
+	// ForwardedByUserID is the invitee who passed their invitation on to
+	// InviteeUserID, for invitations created by forwarding.
+	ForwardedByUserID *uuid.UUID `gorm:"type:uuid" json:"forwarded_by_user_id,omitempty"`
+	TTR               *TTR       `gorm:"foreignKey:TTRID" json:"ttr,omitempty"`
+	InviterUser       *User      `gorm:"foreignKey:InviterUserID" json:"inviter_user,omitempty"`
+	InviteeUser       *User      `gorm:"foreignKey:InviteeUserID" json:"invitee_user,omitempty"`
 }
 
 func (i *Invitation) TableName() string {
 	return "invitations"
 }
+
+func (i *Invitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	stampCreated(&i.CreatedAt)
+	return nil
+}
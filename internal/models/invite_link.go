@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InviteLink lets the first MaxUses users who open it join a TTR without a
+// targeted invitation. Uses counts the users who joined through it.
+type InviteLink struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID           uuid.UUID  `gorm:"type:uuid;not null;index" json:"ttr_id"`
+	Token           string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"token"`
+	CreatedByUserID uuid.UUID  `gorm:"type:uuid;not null" json:"created_by_user_id"`
+	MaxUses         int        `gorm:"not null" json:"max_uses"`
+	Uses            int        `gorm:"not null;default:0" json:"uses"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (l *InviteLink) TableName() string {
+	return "invite_links"
+}
+
+func (l *InviteLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	// Links are listed newest first; the column default only has whole
+	// seconds, which can't order links created together.
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	return nil
+}
+
+// RemainingUses is how many more users can join through the link.
+func (l *InviteLink) RemainingUses() int {
+	if l.Uses >= l.MaxUses {
+		return 0
+	}
+	return l.MaxUses - l.Uses
+}
+
+// Expired reports whether the link's expiry has passed at now.
+func (l *InviteLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
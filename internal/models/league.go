@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// LeagueScoringStrokePlay ranks players by their total strokes across the
+	// season's rounds, fewest first.
+	LeagueScoringStrokePlay = "stroke_play"
+	// LeagueScoringPointsPerFinish awards points for each round's finishing
+	// position: the winner of an N-player round gets N points and last place
+	// gets 1. Tied players share the better position.
+	LeagueScoringPointsPerFinish = "points_per_finish"
+)
+
+// League is a season of completed TTRs whose recorded scores add up to
+// standings. A league belongs to its owner and optionally to an organization,
+// in which case only the organization's members can see it.
+type League struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Name           string         `gorm:"type:varchar(255);not null" json:"name"`
+	OwnerUserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"owner_user_id"`
+	OrganizationID *uuid.UUID     `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	StartDate      time.Time      `gorm:"type:date;not null" json:"start_date"`
+	EndDate        time.Time      `gorm:"type:date;not null" json:"end_date"`
+	ScoringScheme  string         `gorm:"type:varchar(50);not null" json:"scoring_scheme"`
+	CreatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (l *League) TableName() string {
+	return "leagues"
+}
+
+func (l *League) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// LeagueStanding is one player's line in a league's standings. Position uses
+// standard competition ranking, so tied players share a position and the next
+// one is skipped.
+type LeagueStanding struct {
+	Position     int       `json:"position"`
+	UserID       uuid.UUID `json:"user_id"`
+	Rounds       int       `json:"rounds"`
+	TotalStrokes int       `json:"total_strokes"`
+	Points       int       `json:"points"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Message struct {
+	ID                    uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID                 uuid.UUID  `gorm:"type:uuid;not null;index:idx_ttr_messages_ttr_created,priority:1" json:"ttr_id"`
+	UserID                uuid.UUID  `gorm:"type:uuid;not null;index:idx_ttr_messages_user" json:"user_id"`
+	Body                  string     `gorm:"type:text;not null" json:"body"`
+	EditedAt              *time.Time `json:"edited_at,omitempty"`
+	Deleted               bool       `gorm:"default:false" json:"deleted"`
+	AttachmentKey         *string    `gorm:"type:varchar(512)" json:"-"`
+	AttachmentName        *string    `gorm:"type:varchar(255)" json:"attachment_name,omitempty"`
+	AttachmentContentType *string    `gorm:"type:varchar(100)" json:"attachment_content_type,omitempty"`
+	AttachmentSize        int64      `gorm:"default:0" json:"attachment_size,omitempty"`
+	CreatedAt             time.Time  `gorm:"index:idx_ttr_messages_ttr_created,priority:2" json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	User                  *User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+
+	// Reactions and AttachmentURL are filled in by the service for list
+	// responses.
+	Reactions     []ReactionSummary `gorm:"-" json:"reactions,omitempty"`
+	AttachmentURL string            `gorm:"-" json:"attachment_url,omitempty"`
+}
+
+func (m *Message) TableName() string {
+	return "ttr_messages"
+}
+
+func (m *Message) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// MessageRead records how far a user has read a TTR's message thread. Messages
+// created after LastReadAt by other users are unread.
+type MessageRead struct {
+	TTRID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"ttr_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	LastReadAt time.Time `gorm:"not null" json:"last_read_at"`
+}
+
+func (r *MessageRead) TableName() string {
+	return "ttr_message_reads"
+}
+
+// AttachmentContentTypes lists the file types accepted as chat attachments.
+var AttachmentContentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// ReactionEmojis is the whitelist of shortcodes accepted as message reactions.
+var ReactionEmojis = map[string]bool{
+	"thumbsup":   true,
+	"thumbsdown": true,
+	"heart":      true,
+	"laughing":   true,
+	"tada":       true,
+	"golf":       true,
+	"clap":       true,
+	"eyes":       true,
+	"question":   true,
+	"pray":       true,
+}
+
+type MessageReaction struct {
+	MessageID uuid.UUID `gorm:"type:uuid;primaryKey" json:"message_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Emoji     string    `gorm:"type:varchar(32);primaryKey" json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *MessageReaction) TableName() string {
+	return "message_reactions"
+}
+
+// ReactionSummary is the aggregated count for one emoji on a message, and
+// whether the requesting user is among those who reacted with it.
+type ReactionSummary struct {
+	Emoji       string `json:"emoji"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
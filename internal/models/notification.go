@@ -4,27 +4,40 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 const (
-	NotificationTypeInvitation      = "INVITATION"
-	NotificationTypeTTRUpdate       = "TTR_UPDATE"
-	NotificationTypeNewMessage      = "NEW_MESSAGE"
-	NotificationTypeTTRCancelled    = "TTR_CANCELLED"
-	NotificationTypePlayerJoined    = "PLAYER_JOINED"
-	NotificationTypeCoCaptainAdded  = "CO_CAPTAIN_ADDED"
+	NotificationTypeInvitation          = "INVITATION"
+	NotificationTypeInvitationResponse  = "INVITATION_RESPONSE"
+	NotificationTypeInvitationWithdrawn = "INVITATION_WITHDRAWN"
+	NotificationTypeInvitationForwarded = "INVITATION_FORWARDED"
+	NotificationTypeTTRUpdate           = "TTR_UPDATE"
+	NotificationTypeTTRUrgentChange     = "TTR_URGENT_CHANGE"
+	NotificationTypeNewMessage          = "NEW_MESSAGE"
+	NotificationTypeTTRCancelled        = "TTR_CANCELLED"
+	NotificationTypeTTRRestored         = "TTR_RESTORED"
+	NotificationTypePlayerJoined        = "PLAYER_JOINED"
+	NotificationTypeCoCaptainAdded      = "CO_CAPTAIN_ADDED"
+	NotificationTypeCheckIn             = "CHECK_IN"
+	NotificationTypeAccountInactive     = "ACCOUNT_INACTIVE"
+	NotificationTypeNewLogin            = "NEW_LOGIN"
+	NotificationTypeReportResolved      = "REPORT_RESOLVED"
 )
 
+// Notification is one row in a user's inbox. A digest row stands for
+// EventCount events of the same type about the same target.
 type Notification struct {
-	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	UserID     uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
-	Type       string     `gorm:"type:varchar(100);not null" json:"type"`
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_notifications_user_read_created,priority:1;index:idx_notifications_digest,priority:1" json:"user_id"`
+	Type       string     `gorm:"type:varchar(100);not null;index:idx_notifications_digest,priority:2" json:"type"`
 	Title      string     `gorm:"type:varchar(255);not null" json:"title"`
 	Message    string     `gorm:"type:text;not null" json:"message"`
-	TargetType *string    `gorm:"type:varchar(50)" json:"target_type,omitempty"`
-	TargetID   *uuid.UUID `gorm:"type:uuid" json:"target_id,omitempty"`
-	IsRead     bool       `gorm:"default:false" json:"is_read"`
-	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	TargetType *string    `gorm:"type:varchar(50);index:idx_notifications_digest,priority:3" json:"target_type,omitempty"`
+	TargetID   *uuid.UUID `gorm:"type:uuid;index:idx_notifications_digest,priority:4" json:"target_id,omitempty"`
+	EventCount int        `gorm:"not null;default:1" json:"event_count"`
+	IsRead     bool       `gorm:"default:false;index:idx_notifications_user_read_created,priority:2" json:"is_read"`
+	CreatedAt  time.Time  `gorm:"default:CURRENT_TIMESTAMP;index:idx_notifications_user_read_created,priority:3;index:idx_notifications_digest,priority:5" json:"created_at"`
 	ReadAt     *time.Time `json:"read_at,omitempty"`
 	User       *User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
@@ -32,3 +45,11 @@ type Notification struct {
 func (n *Notification) TableName() string {
 	return "notifications"
 }
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	stampCreated(&n.CreatedAt)
+	return nil
+}
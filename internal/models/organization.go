@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+const (
+	OrganizationInvitationStatusPending  = "PENDING"
+	OrganizationInvitationStatusAccepted = "ACCEPTED"
+	OrganizationInvitationStatusDeclined = "DECLINED"
+)
+
+// Organization is a club whose members share private TTRs. TTRs belonging to
+// an organization are only visible to its members.
+type Organization struct {
+	ID              uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Name            string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description     *string        `gorm:"type:text" json:"description,omitempty"`
+	CreatedByUserID uuid.UUID      `gorm:"type:uuid;not null" json:"created_by_user_id"`
+	CreatedAt       time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (o *Organization) TableName() string {
+	return "organizations"
+}
+
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	return nil
+}
+
+type OrganizationMember struct {
+	OrganizationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"organization_id"`
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"user_id"`
+	Role           string    `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	JoinedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
+	User           *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (m *OrganizationMember) TableName() string {
+	return "organization_members"
+}
+
+// CanAdminister reports whether the member's role lets them manage the
+// organization and its TTRs.
+func (m *OrganizationMember) CanAdminister() bool {
+	return m.Role == OrganizationRoleOwner || m.Role == OrganizationRoleAdmin
+}
+
+// OrganizationInvitation invites an email address to join an organization. The
+// address doesn't need an account yet; whoever registers or signs in with it
+// can accept.
+type OrganizationInvitation struct {
+	ID              uuid.UUID     `gorm:"type:uuid;primary_key" json:"id"`
+	OrganizationID  uuid.UUID     `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Email           string        `gorm:"type:varchar(255);not null;index" json:"email"`
+	Role            string        `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	InvitedByUserID uuid.UUID     `gorm:"type:uuid;not null" json:"invited_by_user_id"`
+	Status          string        `gorm:"type:varchar(50);default:'PENDING'" json:"status"`
+	CreatedAt       time.Time     `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	RespondedAt     *time.Time    `json:"responded_at,omitempty"`
+	Organization    *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+}
+
+func (i *OrganizationInvitation) TableName() string {
+	return "organization_invitations"
+}
+
+func (i *OrganizationInvitation) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of outbox messages, each delivered by its own sender.
+const (
+	OutboxKindNotification = "notification"
+	OutboxKindWebhook      = "webhook"
+	OutboxKindMemberInvite = "member_invite"
+)
+
+// A DONE message was delivered; a DEAD one failed every attempt and is kept
+// for someone to look at.
+const (
+	OutboxStatusPending = "PENDING"
+	OutboxStatusDone    = "DONE"
+	OutboxStatusDead    = "DEAD"
+)
+
+// OutboxMessage is a side effect of a change, such as a notification, written
+// in the change's transaction and delivered afterwards by the outbox
+// dispatcher. Payload is the JSON the kind's sender reads. A PENDING message
+// is due once NextAttemptAt has passed; Attempts counts the failed ones and
+// LastError says why the last one failed.
+type OutboxMessage struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Kind          string     `gorm:"type:varchar(32);not null" json:"kind"`
+	Payload       string     `gorm:"type:text;not null" json:"payload"`
+	Status        string     `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_outbox_messages_status_next_attempt,priority:1" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     *string    `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_messages_status_next_attempt,priority:2" json:"next_attempt_at"`
+	ProcessedAt   *time.Time `json:"processed_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (m *OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
+
+func (m *OutboxMessage) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
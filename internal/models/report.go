@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// What a report can be about.
+const (
+	ReportTargetUser    = "USER"
+	ReportTargetMessage = "MESSAGE"
+	ReportTargetTTR     = "TTR"
+)
+
+// Why something was reported.
+const (
+	ReportCategoryHarassment    = "HARASSMENT"
+	ReportCategorySpam          = "SPAM"
+	ReportCategoryInappropriate = "INAPPROPRIATE"
+	ReportCategoryImpersonation = "IMPERSONATION"
+	ReportCategoryOther         = "OTHER"
+)
+
+// An OPEN report waits for an admin, who either RESOLVES it, acting on it, or
+// DISMISSES it.
+const (
+	ReportStatusOpen      = "OPEN"
+	ReportStatusResolved  = "RESOLVED"
+	ReportStatusDismissed = "DISMISSED"
+)
+
+// Report is a user, message or TTR that ReporterUserID flagged as abusive.
+// A reporter has at most one OPEN report per target. Once closed, Resolution
+// holds the admin's notes and ResolvedByUserID who closed it.
+type Report struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	ReporterUserID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_reports_reporter_created,priority:1;uniqueIndex:idx_reports_open_target,priority:1,where:status = 'OPEN'" json:"reporter_user_id"`
+	TargetType       string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_reports_open_target,priority:2" json:"target_type"`
+	TargetID         uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_reports_open_target,priority:3" json:"target_id"`
+	Category         string     `gorm:"type:varchar(20);not null" json:"category"`
+	Details          string     `gorm:"type:text;not null;default:''" json:"details"`
+	Status           string     `gorm:"type:varchar(20);not null;default:'OPEN';index" json:"status"`
+	Resolution       string     `gorm:"type:text;not null;default:''" json:"resolution"`
+	ResolvedByUserID *uuid.UUID `gorm:"type:uuid" json:"resolved_by_user_id,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP;index:idx_reports_reporter_created,priority:2" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Reporter         *User      `gorm:"foreignKey:ReporterUserID" json:"reporter,omitempty"`
+}
+
+func (r *Report) TableName() string {
+	return "reports"
+}
+
+func (r *Report) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsOpen reports whether the report still waits for an admin.
+func (r *Report) IsOpen() bool {
+	return r.Status == ReportStatusOpen
+}
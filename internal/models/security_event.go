@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Security event types.
+const (
+	SecurityEventLogin           = "LOGIN"
+	SecurityEventLoginFailed     = "LOGIN_FAILED"
+	SecurityEventPasswordChanged = "PASSWORD_CHANGED"
+	SecurityEventTokensRevoked   = "TOKENS_REVOKED"
+)
+
+// SecurityEvent is something that happened to a user's account that they may
+// want to check was them, with the IP address and user agent of the request
+// it happened in.
+type SecurityEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_security_events_user_created,priority:1" json:"user_id"`
+	Type      string    `gorm:"type:varchar(32);not null" json:"type"`
+	IPAddress string    `gorm:"type:varchar(45);not null;default:''" json:"ip_address"`
+	UserAgent string    `gorm:"type:varchar(500);not null;default:''" json:"user_agent"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP;index;index:idx_security_events_user_created,priority:2" json:"created_at"`
+}
+
+func (e *SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+func (e *SecurityEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// KnownDevice is an IP address and user agent pair a user has logged in
+// from, by Fingerprint, a hash of the two.
+type KnownDevice struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primary_key" json:"user_id"`
+	Fingerprint string    `gorm:"type:varchar(64);primary_key" json:"fingerprint"`
+	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"not null" json:"last_seen_at"`
+}
+
+func (d *KnownDevice) TableName() string {
+	return "known_devices"
+}
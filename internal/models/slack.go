@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SlackAccount links a Slack user, identified by workspace and user ID, to
+// the account their /golf commands act as.
+type SlackAccount struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TeamID      string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_slack_accounts_team_user,priority:1" json:"team_id"`
+	SlackUserID string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_slack_accounts_team_user,priority:2" json:"slack_user_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (a *SlackAccount) TableName() string {
+	return "slack_accounts"
+}
+
+func (a *SlackAccount) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// SlackLinkCode is a one-time code issued by "/golf link". A signed-in user
+// who sends it before ExpiresAt becomes the account behind that Slack user.
+type SlackLinkCode struct {
+	Code        string    `gorm:"type:varchar(16);primary_key" json:"code"`
+	TeamID      string    `gorm:"type:varchar(32);not null" json:"team_id"`
+	SlackUserID string    `gorm:"type:varchar(32);not null" json:"slack_user_id"`
+	ExpiresAt   time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (c *SlackLinkCode) TableName() string {
+	return "slack_link_codes"
+}
@@ -0,0 +1,167 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// TTRStatus is where a TTR is in its life. The column is checked by the
+// database too, so a value outside the constants can't be stored or read.
+type TTRStatus string
+
+func (s TTRStatus) IsValid() bool {
+	switch s {
+	case TTRStatusOpen, TTRStatusConfirmed, TTRStatusCancelled, TTRStatusCompleted:
+		return true
+	}
+	return false
+}
+
+func (s *TTRStatus) Scan(value interface{}) error {
+	return scanStatus(value, s, "TTR status")
+}
+
+func (s TTRStatus) Value() (driver.Value, error) {
+	return valueStatus(s, "TTR status")
+}
+
+// TTRPlayerStatus is a player's answer on a TTR's roster.
+type TTRPlayerStatus string
+
+// playerStatuses defines the roster statuses. Active ones take one of the
+// TTR's slots; from, when set, lists the only statuses a player can move in
+// from. A new status is added here and to the column's database check.
+var playerStatuses = []struct {
+	status TTRPlayerStatus
+	active bool
+	from   []TTRPlayerStatus
+}{
+	{status: TTRPlayerStatusConfirmed, active: true},
+	{status: TTRPlayerStatusMaybe, active: true},
+	{status: TTRPlayerStatusDeclined, active: true},
+	{status: TTRPlayerStatusWaitlisted},
+	// Only a player who said they'd come can fail to show up.
+	{status: TTRPlayerStatusNoShow, from: []TTRPlayerStatus{TTRPlayerStatusConfirmed}},
+}
+
+// AllPlayerStatuses returns every roster status, in a stable order.
+func AllPlayerStatuses() []TTRPlayerStatus {
+	statuses := make([]TTRPlayerStatus, len(playerStatuses))
+	for i, def := range playerStatuses {
+		statuses[i] = def.status
+	}
+	return statuses
+}
+
+// ActivePlayerStatuses returns the roster statuses that take a slot.
+func ActivePlayerStatuses() []TTRPlayerStatus {
+	var statuses []TTRPlayerStatus
+	for _, def := range playerStatuses {
+		if def.active {
+			statuses = append(statuses, def.status)
+		}
+	}
+	return statuses
+}
+
+func (s TTRPlayerStatus) IsValid() bool {
+	for _, def := range playerStatuses {
+		if def.status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether a player with the status takes one of the TTR's
+// slots, i.e. counts towards its capacity.
+func (s TTRPlayerStatus) IsActive() bool {
+	for _, def := range playerStatuses {
+		if def.status == s {
+			return def.active
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether a player with the status can be moved to
+// next. Keeping the status is always allowed.
+func (s TTRPlayerStatus) CanTransitionTo(next TTRPlayerStatus) bool {
+	if next == s {
+		return next.IsValid()
+	}
+	for _, def := range playerStatuses {
+		if def.status != next {
+			continue
+		}
+		if def.from == nil {
+			return true
+		}
+		for _, from := range def.from {
+			if from == s {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func (s *TTRPlayerStatus) Scan(value interface{}) error {
+	return scanStatus(value, s, "TTR player status")
+}
+
+func (s TTRPlayerStatus) Value() (driver.Value, error) {
+	return valueStatus(s, "TTR player status")
+}
+
+// InvitationStatus is where an invitation is: waiting for an answer,
+// answered, or withdrawn.
+type InvitationStatus string
+
+func (s InvitationStatus) IsValid() bool {
+	switch s {
+	case InvitationStatusPending, InvitationStatusYes, InvitationStatusNo, InvitationStatusMaybe, InvitationStatusCanceled, InvitationStatusExpired, InvitationStatusForwardRequested:
+		return true
+	}
+	return false
+}
+
+func (s *InvitationStatus) Scan(value interface{}) error {
+	return scanStatus(value, s, "invitation status")
+}
+
+func (s InvitationStatus) Value() (driver.Value, error) {
+	return valueStatus(s, "invitation status")
+}
+
+type status interface {
+	~string
+	IsValid() bool
+}
+
+// scanStatus reads a status column into dst, refusing values the type
+// doesn't know rather than letting them reach a switch that mishandles them.
+func scanStatus[S status](value interface{}, dst *S, name string) error {
+	var s S
+	switch v := value.(type) {
+	case string:
+		s = S(v)
+	case []byte:
+		s = S(v)
+	default:
+		return fmt.Errorf("unsupported %s value %T", name, value)
+	}
+	if !s.IsValid() {
+		return fmt.Errorf("invalid %s %q", name, string(s))
+	}
+	*dst = s
+	return nil
+}
+
+func valueStatus[S status](s S, name string) (driver.Value, error) {
+	if !s.IsValid() {
+		return nil, fmt.Errorf("invalid %s %q", name, string(s))
+	}
+	return string(s), nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("timeofday", TimeOfDaySerializer{})
+}
+
+// TimeOfDaySerializer stores a time.Time, or a *time.Time for a nullable
+// column, in a TIME column. Drivers disagree on how TIME values come back
+// (postgres returns time.Time, sqlite a string), so both are normalised to a
+// time on 0000-01-01 UTC.
+type TimeOfDaySerializer struct{}
+
+func (TimeOfDaySerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var t time.Time
+	switch v := dbValue.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		t = time.Date(0, 1, 1, v.Hour(), v.Minute(), v.Second(), 0, time.UTC)
+	case []byte:
+		parsed, err := parseTimeOfDay(string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+	case string:
+		parsed, err := parseTimeOfDay(v)
+		if err != nil {
+			return err
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("unsupported time of day value %T", dbValue)
+	}
+	return field.Set(ctx, dst, t)
+}
+
+func (TimeOfDaySerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	switch t := fieldValue.(type) {
+	case time.Time:
+		return t.Format("15:04:05"), nil
+	case *time.Time:
+		if t == nil {
+			return nil, nil
+		}
+		return t.Format("15:04:05"), nil
+	default:
+		return nil, fmt.Errorf("unsupported time of day value %T", fieldValue)
+	}
+}
+
+func parseTimeOfDay(s string) (time.Time, error) {
+	for _, layout := range []string{"15:04:05.999999999", "15:04", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Date(0, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time of day %q", s)
+}
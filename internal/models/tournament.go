@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	TournamentStatusInProgress = "IN_PROGRESS"
+	TournamentStatusCompleted  = "COMPLETED"
+)
+
+const (
+	// MatchStatusPending means at least one player is still to be decided by
+	// an earlier match.
+	MatchStatusPending = "PENDING"
+	// MatchStatusScheduled means both players are known and the match can be
+	// played.
+	MatchStatusScheduled = "SCHEDULED"
+	// MatchStatusCompleted means the match has a winner, either played or by
+	// walkover.
+	MatchStatusCompleted = "COMPLETED"
+)
+
+// Tournament is a single-elimination match-play bracket. When it is drawn
+// from a TTR, that TTR's course and settings are copied for each round's TTR.
+type Tournament struct {
+	ID           uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	Name         string         `gorm:"type:varchar(255);not null" json:"name"`
+	OwnerUserID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"owner_user_id"`
+	TTRID        *uuid.UUID     `gorm:"type:uuid" json:"ttr_id,omitempty"`
+	Status       string         `gorm:"type:varchar(50);default:'IN_PROGRESS'" json:"status"`
+	WinnerUserID *uuid.UUID     `gorm:"type:uuid" json:"winner_user_id,omitempty"`
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Matches      []*Match       `gorm:"foreignKey:TournamentID" json:"matches,omitempty"`
+}
+
+func (t *Tournament) TableName() string {
+	return "tournaments"
+}
+
+func (t *Tournament) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// Match is one pairing in a tournament bracket. Rounds are numbered from 1
+// and positions from 0 within a round; the winner of a match moves on to
+// position Position/2 of the next round.
+type Match struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TournamentID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"tournament_id"`
+	Round         int        `gorm:"not null" json:"round"`
+	Position      int        `gorm:"not null" json:"position"`
+	Player1UserID *uuid.UUID `gorm:"type:uuid" json:"player1_user_id,omitempty"`
+	Player2UserID *uuid.UUID `gorm:"type:uuid" json:"player2_user_id,omitempty"`
+	WinnerUserID  *uuid.UUID `gorm:"type:uuid" json:"winner_user_id,omitempty"`
+	Status        string     `gorm:"type:varchar(50);not null" json:"status"`
+	Walkover      bool       `gorm:"not null;default:false" json:"walkover"`
+	TTRID         *uuid.UUID `gorm:"type:uuid" json:"ttr_id,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (m *Match) TableName() string {
+	return "tournament_matches"
+}
+
+func (m *Match) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// HasPlayer reports whether userID is one of the match's two players.
+func (m *Match) HasPlayer(userID uuid.UUID) bool {
+	return (m.Player1UserID != nil && *m.Player1UserID == userID) ||
+		(m.Player2UserID != nil && *m.Player2UserID == userID)
+}
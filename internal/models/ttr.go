@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,61 +10,409 @@ import (
 )
 
 const (
-	TTRStatusOpen      = "OPEN"
-	TTRStatusConfirmed = "CONFIRMED"
-	TTRStatusCancelled = "CANCELLED"
-	TTRStatusCompleted = "COMPLETED"
+	TTRStatusOpen      TTRStatus = "OPEN"
+	TTRStatusConfirmed TTRStatus = "CONFIRMED"
+	TTRStatusCancelled TTRStatus = "CANCELLED"
+	TTRStatusCompleted TTRStatus = "COMPLETED"
 )
 
+// Who can find a TTR in listings and join it. Participants always can.
+// FRIENDS TTRs are open to users who accepted a friend request from the
+// captain, or whose request the captain accepted.
 const (
-	TTRPlayerStatusConfirmed = "CONFIRMED"
-	TTRPlayerStatusMaybe     = "MAYBE"
-	TTRPlayerStatusDeclined  = "DECLINED"
+	TTRVisibilityPrivate = "PRIVATE"
+	TTRVisibilityFriends = "FRIENDS"
+	TTRVisibilityPublic  = "PUBLIC"
 )
 
+// Roster statuses; see playerStatuses for which take a slot. WAITLISTED
+// players wait for one to free up and NO_SHOW marks a confirmed player who
+// didn't turn up.
+const (
+	TTRPlayerStatusConfirmed  TTRPlayerStatus = "CONFIRMED"
+	TTRPlayerStatusMaybe      TTRPlayerStatus = "MAYBE"
+	TTRPlayerStatusDeclined   TTRPlayerStatus = "DECLINED"
+	TTRPlayerStatusWaitlisted TTRPlayerStatus = "WAITLISTED"
+	TTRPlayerStatusNoShow     TTRPlayerStatus = "NO_SHOW"
+)
+
+// DefaultPlayerStatus is the status of a player added to a roster: joining,
+// accepting an invitation or redeeming an invite link confirms them.
+const DefaultPlayerStatus = TTRPlayerStatusConfirmed
+
+// Ways a user takes part in a TTR, which TTR searches can filter by. Invited
+// means a pending invitation.
+type TTRParticipation string
+
+const (
+	TTRParticipationCaptain   TTRParticipation = "captain"
+	TTRParticipationCoCaptain TTRParticipation = "co_captain"
+	TTRParticipationPlayer    TTRParticipation = "player"
+	TTRParticipationInvited   TTRParticipation = "invited"
+)
+
+func (p TTRParticipation) IsValid() bool {
+	switch p {
+	case TTRParticipationCaptain, TTRParticipationCoCaptain, TTRParticipationPlayer, TTRParticipationInvited:
+		return true
+	}
+	return false
+}
+
+// DefaultMaxPlayers is the group size of a TTR created without one.
+const DefaultMaxPlayers = 4
+
+// Invariants every stored TTR keeps, checked by its hooks before a write.
+var (
+	ErrTTRMaxPlayers = errors.New("max players must be at least 1")
+	ErrTTRCourseName = errors.New("course name is required")
+	ErrTTRHoles      = errors.New("holes must be 9 or 18")
+	ErrTTRGreenFee   = errors.New("green fee can't be negative")
+)
+
+// CourseConditions are what the course offers on the day, as the captain
+// gives them. Nil fields weren't given.
+type CourseConditions struct {
+	WalkingAllowed *bool   `json:"walking_allowed,omitempty"`
+	CartsAvailable *bool   `json:"carts_available,omitempty"`
+	Holes          *int    `gorm:"check:chk_ttrs_holes,holes IN (9,18)" json:"holes,omitempty"`
+	DressCode      *string `gorm:"type:varchar(100)" json:"dress_code,omitempty"`
+	GreenFeeCents  *int    `gorm:"check:chk_ttrs_green_fee_cents,green_fee_cents >= 0" json:"green_fee_cents,omitempty"`
+}
+
+// ValidHoles reports whether a round can be holes long: 9 or 18.
+func ValidHoles(holes int) bool {
+	return holes == 9 || holes == 18
+}
+
+// Validate returns ErrTTRHoles or ErrTTRGreenFee when a given field is out of
+// range.
+func (c CourseConditions) Validate() error {
+	if c.Holes != nil && !ValidHoles(*c.Holes) {
+		return ErrTTRHoles
+	}
+	if c.GreenFeeCents != nil && *c.GreenFeeCents < 0 {
+		return ErrTTRGreenFee
+	}
+	return nil
+}
+
 type TTR struct {
-	ID              uuid.UUID       `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	CourseName      string          `gorm:"type:varchar(255);not null" json:"course_name"`
-	CourseLocation  *string         `gorm:"type:varchar(255)" json:"course_location,omitempty"`
-	TeeDate         time.Time       `gorm:"type:date;not null" json:"tee_date"`
-	TeeTime         time.Time       `gorm:"type:time;not null" json:"tee_time"`
-	MaxPlayers      int             `gorm:"default:4" json:"max_players"`
-	CreatedByUserID uuid.UUID       `gorm:"type:uuid;not null" json:"created_by_user_id"`
-	CaptainUserID   uuid.UUID       `gorm:"type:uuid;not null" json:"captain_user_id"`
-	Status          string          `gorm:"type:varchar(50);default:'OPEN'" json:"status"`
-	Notes           *string         `gorm:"type:text" json:"notes,omitempty"`
-	CreatedAt       time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt       time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt       gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
-	CreatedByUser   *User           `gorm:"foreignKey:CreatedByUserID" json:"created_by_user,omitempty"`
-	CaptainUser     *User           `gorm:"foreignKey:CaptainUserID" json:"captain_user,omitempty"`
-	CoCaptains      []TTRCoCaptain  `gorm:"foreignKey:TTRID" json:"co_captains,omitempty"`
-	Players         []TTRPlayer     `gorm:"foreignKey:TTRID" json:"players,omitempty"`
+	ID               uuid.UUID      `gorm:"type:uuid;primary_key" json:"id"`
+	CourseName       string         `gorm:"type:varchar(255);not null" json:"course_name"`
+	CourseLocation   *string        `gorm:"type:varchar(255)" json:"course_location,omitempty"`
+	TeeDate          time.Time      `gorm:"type:date;not null;index:idx_ttrs_status_tee_date,priority:2;index:idx_ttrs_captain_tee_date,priority:2" json:"tee_date"`
+	TeeTime          time.Time      `gorm:"type:time;not null;serializer:timeofday" json:"tee_time"`
+	MaxPlayers       int            `gorm:"default:4" json:"max_players"`
+	MinPlayers       int            `gorm:"not null;default:2" json:"min_players"`
+	CreatedByUserID  uuid.UUID      `gorm:"type:uuid;not null" json:"created_by_user_id"`
+	CaptainUserID    uuid.UUID      `gorm:"type:uuid;not null;index:idx_ttrs_captain_tee_date,priority:1" json:"captain_user_id"`
+	Status           TTRStatus      `gorm:"type:varchar(50);not null;default:'OPEN';check:chk_ttrs_status,status IN ('OPEN','CONFIRMED','CANCELLED','COMPLETED');index:idx_ttrs_status_tee_date,priority:1" json:"status"`
+	StatusLocked     bool           `gorm:"not null;default:false" json:"status_locked"`
+	Visibility       string         `gorm:"type:varchar(20);not null;default:'PRIVATE'" json:"visibility"`
+	Notes            *string        `gorm:"type:text" json:"notes,omitempty"`
+	RSVPDeadline     *time.Time     `gorm:"index" json:"rsvp_deadline,omitempty"`
+	RSVPClosedAt     *time.Time     `json:"-"`
+	CheckInSummaryAt *time.Time     `json:"-"`
+	PreDeleteStatus  *TTRStatus     `gorm:"type:varchar(50);check:chk_ttrs_pre_delete_status,pre_delete_status IN ('OPEN','CONFIRMED','CANCELLED','COMPLETED')" json:"-"`
+	OrganizationID   *uuid.UUID     `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	LeagueID         *uuid.UUID     `gorm:"type:uuid;index" json:"league_id,omitempty"`
+	CreatedAt        time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	CreatedByUser    *User          `gorm:"foreignKey:CreatedByUserID" json:"created_by_user,omitempty"`
+	CaptainUser      *User          `gorm:"foreignKey:CaptainUserID" json:"captain_user,omitempty"`
+	CoCaptains       []TTRCoCaptain `gorm:"foreignKey:TTRID" json:"co_captains,omitempty"`
+	Players          []TTRPlayer    `gorm:"foreignKey:TTRID" json:"players,omitempty"`
+	Guests           []TTRGuest     `gorm:"foreignKey:TTRID" json:"guests,omitempty"`
+	Slots            []TeeSlot      `gorm:"foreignKey:TTRID" json:"slots,omitempty"`
+
+	CourseConditions
+}
+
+// PlayerCounts is how many players take a slot on a TTR, i.e. have an active
+// status, and how many of them are confirmed. Total is what the capacity check
+// counts. Guests count as confirmed players.
+type PlayerCounts struct {
+	Total     int
+	Confirmed int
+}
+
+// OpenSlots is how many more players the TTR can take.
+func (t *TTR) OpenSlots(counts PlayerCounts) int {
+	if open := t.MaxPlayers - counts.Total; open > 0 {
+		return open
+	}
+	return 0
+}
+
+// DefaultMinPlayers is how many confirmed players a TTR needs to stay
+// CONFIRMED when its captain doesn't say otherwise.
+const DefaultMinPlayers = 2
+
+// SyncedStatus is the status the roster calls for: an OPEN TTR whose slots are
+// all taken by confirmed players becomes CONFIRMED, and a CONFIRMED one that
+// drops below MinPlayers confirmed players opens again. It returns the
+// current status when the captain locked it or no transition applies.
+func (t *TTR) SyncedStatus(counts PlayerCounts) TTRStatus {
+	if t.StatusLocked {
+		return t.Status
+	}
+	switch {
+	case t.Status == TTRStatusOpen && counts.Confirmed >= t.MaxPlayers:
+		return TTRStatusConfirmed
+	case t.Status == TTRStatusConfirmed && counts.Confirmed < t.MinPlayers:
+		return TTRStatusOpen
+	}
+	return t.Status
 }
 
 func (t *TTR) TableName() string {
 	return "ttrs"
 }
 
+// RSVPDeadlinePassed reports whether the TTR has an RSVP deadline before now.
+func (t *TTR) RSVPDeadlinePassed(now time.Time) bool {
+	return t.RSVPDeadline != nil && now.After(*t.RSVPDeadline)
+}
+
+// TeeDateTime combines TeeDate and TeeTime into the instant the round starts.
+func (t *TTR) TeeDateTime() time.Time {
+	return time.Date(t.TeeDate.Year(), t.TeeDate.Month(), t.TeeDate.Day(),
+		t.TeeTime.Hour(), t.TeeTime.Minute(), t.TeeTime.Second(), 0, t.TeeDate.Location())
+}
+
+// HasCoCaptain reports whether userID is one of the TTR's co-captains. It
+// relies on CoCaptains being preloaded.
+func (t *TTR) HasCoCaptain(userID uuid.UUID) bool {
+	for _, coCaptain := range t.CoCaptains {
+		if coCaptain.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// CoCaptainCan reports whether userID is a co-captain of the TTR with
+// permission. It relies on CoCaptains being preloaded.
+func (t *TTR) CoCaptainCan(userID uuid.UUID, permission string) bool {
+	for _, coCaptain := range t.CoCaptains {
+		if coCaptain.UserID == userID {
+			return coCaptain.HasPermission(permission)
+		}
+	}
+	return false
+}
+
+// CaptainIDs returns the captain and co-captains. It relies on CoCaptains
+// being preloaded.
+func (t *TTR) CaptainIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(t.CoCaptains)+1)
+	ids = append(ids, t.CaptainUserID)
+	for _, coCaptain := range t.CoCaptains {
+		ids = append(ids, coCaptain.UserID)
+	}
+	return ids
+}
+
+// Headcount is how many slots the roster takes: its active players and its
+// guests. It relies on Players and Guests being preloaded.
+func (t *TTR) Headcount() int {
+	count := len(t.Guests)
+	for _, player := range t.Players {
+		if player.Status.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
+// HasPlayer reports whether userID is on the TTR's roster. It relies on
+// Players being preloaded.
+func (t *TTR) HasPlayer(userID uuid.UUID) bool {
+	for _, player := range t.Players {
+		if player.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns ErrTTRMaxPlayers, ErrTTRCourseName or one of the
+// CourseConditions errors when the TTR breaks one of its invariants.
+func (t *TTR) Validate() error {
+	if t.MaxPlayers < 1 {
+		return ErrTTRMaxPlayers
+	}
+	if strings.TrimSpace(t.CourseName) == "" {
+		return ErrTTRCourseName
+	}
+	return t.CourseConditions.Validate()
+}
+
+// BeforeCreate fills in the ID, timestamps and group size the database used
+// to default, so rows get them on any driver, and checks the invariants.
+func (t *TTR) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.MaxPlayers == 0 {
+		t.MaxPlayers = DefaultMaxPlayers
+	}
+	stampCreated(&t.CreatedAt, &t.UpdatedAt)
+	return t.Validate()
+}
+
+// BeforeUpdate bumps UpdatedAt. The invariants are checked when the whole
+// row is saved; updates of a few columns leave the rest as stored.
+func (t *TTR) BeforeUpdate(tx *gorm.DB) error {
+	t.UpdatedAt = time.Now()
+	if savesRow(tx, t) {
+		return t.Validate()
+	}
+	return nil
+}
+
+// What a co-captain may do. The captain can do all of it; a co-captain only
+// what they were given.
+const (
+	CoCaptainPermissionInvite        = "invite"
+	CoCaptainPermissionEditDetails   = "edit_details"
+	CoCaptainPermissionManagePlayers = "manage_players"
+)
+
+// CoCaptainPermissions lists every co-captain permission. Co-captains get
+// them all unless the captain gives fewer.
+var CoCaptainPermissions = []string{
+	CoCaptainPermissionInvite,
+	CoCaptainPermissionEditDetails,
+	CoCaptainPermissionManagePlayers,
+}
+
+// ValidCoCaptainPermission reports whether permission is one of
+// CoCaptainPermissions.
+func ValidCoCaptainPermission(permission string) bool {
+	for _, known := range CoCaptainPermissions {
+		if permission == known {
+			return true
+		}
+	}
+	return false
+}
+
+// TTRCoCaptain is a co-captain of a TTR. Permissions is a comma-separated
+// list of their co-captain permissions.
 type TTRCoCaptain struct {
-	TTRID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"ttr_id"`
-	UserID     uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
-	AssignedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"assigned_at"`
-	User       *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	TTRID       uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_ttr_co_captains_user_ttr,priority:2" json:"ttr_id"`
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey;index:idx_ttr_co_captains_user_ttr,priority:1" json:"user_id"`
+	AssignedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"assigned_at"`
+	Permissions string    `gorm:"type:varchar(100);not null" json:"permissions"`
+	User        *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
 func (t *TTRCoCaptain) TableName() string {
 	return "ttr_co_captains"
 }
 
+// PermissionList splits Permissions.
+func (t *TTRCoCaptain) PermissionList() []string {
+	if t.Permissions == "" {
+		return []string{}
+	}
+	return strings.Split(t.Permissions, ",")
+}
+
+// HasPermission reports whether the co-captain was given permission.
+func (t *TTRCoCaptain) HasPermission(permission string) bool {
+	for _, granted := range t.PermissionList() {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxPairingGroupSize is the most players a single pairing group can hold.
+const MaxPairingGroupSize = 4
+
 type TTRPlayer struct {
-	TTRID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"ttr_id"`
-	UserID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
-	JoinedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
-	Status   string    `gorm:"type:varchar(50);default:'CONFIRMED'" json:"status"`
-	User     *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	TTRID       uuid.UUID       `gorm:"type:uuid;primaryKey;index:idx_ttr_players_user_ttr,priority:2" json:"ttr_id"`
+	UserID      uuid.UUID       `gorm:"type:uuid;primaryKey;index:idx_ttr_players_user_ttr,priority:1" json:"user_id"`
+	JoinedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"joined_at"`
+	Status      TTRPlayerStatus `gorm:"type:varchar(50);not null;default:'CONFIRMED';check:chk_ttr_players_status,status IN ('CONFIRMED','MAYBE','DECLINED','WAITLISTED','NO_SHOW')" json:"status"`
+	Notes       *string         `gorm:"type:text" json:"notes,omitempty"`
+	GroupNumber int             `gorm:"not null;default:0" json:"group_number"`
+	Score       *int            `json:"score,omitempty"`
+	CheckedInAt *time.Time      `json:"checked_in_at,omitempty"`
+	User        *User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
 func (t *TTRPlayer) TableName() string {
 	return "ttr_players"
 }
+
+// TTRGuest is someone without an account playing on a TTR, added by one of
+// its captains. A guest takes a slot like a confirmed player but can't be
+// invited or notified and is left out of stats.
+type TTRGuest struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID         uuid.UUID `gorm:"type:uuid;not null;index" json:"ttr_id"`
+	DisplayName   string    `gorm:"type:varchar(100);not null" json:"display_name"`
+	Handicap      *float64  `gorm:"type:decimal(3,1)" json:"handicap,omitempty"`
+	AddedByUserID uuid.UUID `gorm:"type:uuid;not null" json:"added_by_user_id"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (g *TTRGuest) TableName() string {
+	return "ttr_guests"
+}
+
+func (g *TTRGuest) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == uuid.Nil {
+		g.ID = uuid.New()
+	}
+	return nil
+}
+
+// TeeSlot is one tee time in the block a course gave a TTR, OffsetMinutes
+// after the TTR's tee time. Players are in slot Number when their
+// GroupNumber is Number.
+type TeeSlot struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tee_slots_ttr_number,priority:1" json:"ttr_id"`
+	Number        int       `gorm:"not null;uniqueIndex:idx_tee_slots_ttr_number,priority:2" json:"number"`
+	OffsetMinutes int       `gorm:"not null" json:"offset_minutes"`
+	Capacity      int       `gorm:"not null;default:4" json:"capacity"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (s *TeeSlot) TableName() string {
+	return "tee_slots"
+}
+
+func (s *TeeSlot) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// SlotTeeTime is the time of day the slot tees off.
+func (t *TTR) SlotTeeTime(slot TeeSlot) time.Time {
+	return t.TeeTime.Add(time.Duration(slot.OffsetMinutes) * time.Minute)
+}
+
+// GroupCapacity is how many players pairing group number can hold: its
+// slot's capacity when the TTR has slots, MaxPairingGroupSize otherwise. It
+// reports false for a group with no slot.
+func (t *TTR) GroupCapacity(number int) (int, bool) {
+	if len(t.Slots) == 0 {
+		return MaxPairingGroupSize, true
+	}
+	for _, slot := range t.Slots {
+		if slot.Number == number {
+			return slot.Capacity, true
+		}
+	}
+	return 0, false
+}
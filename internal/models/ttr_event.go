@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TTR change event types.
+const (
+	TTREventPlayerJoined   = "player_joined"
+	TTREventPlayerLeft     = "player_left"
+	TTREventPlayerUpdated  = "player_updated"
+	TTREventStatusChanged  = "status_changed"
+	TTREventDetailsUpdated = "details_updated"
+	TTREventMessagePosted  = "message_posted"
+	TTREventGuestAdded     = "guest_added"
+	TTREventGuestRemoved   = "guest_removed"
+)
+
+// TTREvent is one entry in a TTR's change feed. Sequence numbers start at 1
+// and go up by one per TTR, so clients can ask for everything after the last
+// one they saw. ActorUserID is who made the change, or nil for changes made
+// by the server itself such as status syncs. Data holds a small JSON object
+// describing the change, e.g. the player or the new status.
+type TTREvent struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	TTRID       uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_ttr_events_ttr_sequence" json:"ttr_id"`
+	Sequence    int64      `gorm:"not null;uniqueIndex:idx_ttr_events_ttr_sequence" json:"sequence"`
+	Type        string     `gorm:"type:varchar(32);not null" json:"type"`
+	ActorUserID *uuid.UUID `gorm:"type:uuid" json:"actor_user_id,omitempty"`
+	Data        string     `gorm:"type:text;not null;default:'{}'" json:"data"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP;index" json:"created_at"`
+}
+
+func (e *TTREvent) TableName() string {
+	return "ttr_events"
+}
+
+func (e *TTREvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
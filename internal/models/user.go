@@ -4,28 +4,186 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+const (
+	UserRoleUser  = "USER"
+	UserRoleAdmin = "ADMIN"
+	// UserRoleService is never stored; it is the role of requests signed by
+	// an internal client.
+	UserRoleService = "SERVICE"
+)
+
 type User struct {
-	ID           uuid.UUID      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
-	Email        string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
-	PasswordHash string         `gorm:"type:varchar(255);not null" json:"-"`
-	FirstName    string         `gorm:"type:varchar(100);not null" json:"first_name"`
-	LastName     string         `gorm:"type:varchar(100);not null" json:"last_name"`
-	Handicap     *float64       `gorm:"type:decimal(3,1)" json:"handicap,omitempty"`
-	Phone        *string        `gorm:"type:varchar(20)" json:"phone,omitempty"`
-	AvatarURL    *string        `gorm:"type:text" json:"avatar_url,omitempty"`
-	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID                    uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	Email                 string           `gorm:"type:varchar(255);uniqueIndex;not null" json:"email"`
+	NormalizedEmail       string           `gorm:"type:varchar(255);uniqueIndex;not null" json:"-"`
+	PasswordHash          string           `gorm:"type:varchar(255);not null" json:"-"`
+	FirstName             string           `gorm:"type:varchar(100);not null" json:"first_name"`
+	LastName              string           `gorm:"type:varchar(100);not null" json:"last_name"`
+	Handicap              *float64         `gorm:"type:decimal(3,1)" json:"handicap,omitempty"`
+	Phone                 *string          `gorm:"type:varchar(20)" json:"phone,omitempty"`
+	AvatarURL             *string          `gorm:"type:text" json:"avatar_url,omitempty"`
+	PreferredLanguage     *string          `gorm:"type:varchar(10)" json:"preferred_language,omitempty"`
+	HomeCourse            *string          `gorm:"type:varchar(200)" json:"home_course,omitempty"`
+	Bio                   *string          `gorm:"type:varchar(500)" json:"bio,omitempty"`
+	PreferredTeeTimeStart *time.Time       `gorm:"type:time;serializer:timeofday" json:"preferred_tee_time_start,omitempty"`
+	PreferredTeeTimeEnd   *time.Time       `gorm:"type:time;serializer:timeofday" json:"preferred_tee_time_end,omitempty"`
+	PlayingDays           []UserPlayingDay `gorm:"foreignKey:UserID" json:"-"`
+	Role                  string           `gorm:"type:varchar(20);not null;default:'USER'" json:"role"`
+	LastLoginAt           *time.Time       `gorm:"index" json:"last_login_at,omitempty"`
+	InactivityWarnedAt    *time.Time       `json:"-"`
+	// MustResetPassword is set on accounts created for someone else, such as
+	// imported members, until they choose their own password.
+	MustResetPassword bool `gorm:"not null;default:false" json:"must_reset_password"`
+	// EmailUndeliverable is set once the user's address is suppressed for
+	// bouncing or reporting mail as spam; nothing more is sent to it.
+	EmailUndeliverable bool                 `gorm:"not null;default:false" json:"email_undeliverable"`
+	Privacy            PrivacySettings      `gorm:"embedded;embeddedPrefix:privacy_" json:"-"`
+	Notifications      NotificationSettings `gorm:"embedded;embeddedPrefix:notify_" json:"-"`
+	// DigestSentAt is when the user's last weekly digest run handled them,
+	// whether or not there was anything to send.
+	DigestSentAt *time.Time `json:"-"`
+	// MergedIntoUserID is set on an account that was merged into another
+	// one. It is deleted at the same time.
+	MergedIntoUserID *uuid.UUID     `gorm:"type:uuid;index" json:"-"`
+	CreatedAt        time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// PrivacySettings are what a user lets other users see of them. They are
+// stored so that the zero value is the default: handicap shown, contact
+// details hidden and the user findable by name.
+type PrivacySettings struct {
+	HideHandicap   bool `gorm:"not null;default:false"`
+	ShowPhone      bool `gorm:"not null;default:false"`
+	ShowEmail      bool `gorm:"not null;default:false"`
+	HideFromSearch bool `gorm:"not null;default:false"`
+}
+
+// ShowsHandicap reports whether other users may see the user's handicap.
+func (p PrivacySettings) ShowsHandicap() bool {
+	return !p.HideHandicap
+}
+
+// Discoverable reports whether the user can be found by name search.
+func (p PrivacySettings) Discoverable() bool {
+	return !p.HideFromSearch
+}
+
+// NotificationSettings are which notifications a user gets besides those in
+// the app. Like PrivacySettings, the zero value is the default.
+type NotificationSettings struct {
+	NoWeeklyDigest bool `gorm:"not null;default:false"`
+}
+
+// WeeklyDigest reports whether the user gets the weekly digest email.
+func (n NotificationSettings) WeeklyDigest() bool {
+	return !n.NoWeeklyDigest
+}
+
+// Weekdays are the accepted playing days, in week order.
+var Weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// UserPlayingDay is a day of the week the user usually plays. Days are kept
+// one per row rather than in a list column so users can be matched by day.
+type UserPlayingDay struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	Day    string    `gorm:"type:varchar(9);primaryKey;index" json:"day"`
+}
+
+func (d *UserPlayingDay) TableName() string {
+	return "user_playing_days"
+}
+
+// UserBlock is a user BlockerUserID doesn't want to play with. Invitations
+// to their TTRs can't be forwarded to BlockedUserID.
+type UserBlock struct {
+	BlockerUserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	BlockedUserID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"-"`
+	CreatedAt     time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (b *UserBlock) TableName() string {
+	return "user_blocks"
+}
+
+// Friendship is a friend request RequesterUserID sent AddresseeUserID, and
+// once AcceptedAt is set, a friendship between the two. The captain's friends
+// can find FRIENDS TTRs.
+type Friendship struct {
+	RequesterUserID uuid.UUID  `gorm:"type:uuid;primaryKey" json:"-"`
+	AddresseeUserID uuid.UUID  `gorm:"type:uuid;primaryKey;index" json:"-"`
+	CreatedAt       time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	AcceptedAt      *time.Time `json:"accepted_at,omitempty"`
+}
+
+func (f *Friendship) TableName() string {
+	return "friendships"
+}
+
+// Accepted reports whether the request was accepted.
+func (f *Friendship) Accepted() bool {
+	return f.AcceptedAt != nil
+}
+
+// Between reports whether the request is from either user to the other.
+func (f *Friendship) Between(userID uuid.UUID, otherUserID uuid.UUID) bool {
+	return (f.RequesterUserID == userID && f.AddresseeUserID == otherUserID) ||
+		(f.RequesterUserID == otherUserID && f.AddresseeUserID == userID)
 }
 
 func (u *User) TableName() string {
 	return "users"
 }
 
+// IsDeleted reports whether the account has been soft-deleted.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt.Valid
+}
+
+// Days returns the user's playing days in week order.
+func (u *User) Days() []string {
+	days := make([]string, 0, len(u.PlayingDays))
+	for _, weekday := range Weekdays {
+		for _, d := range u.PlayingDays {
+			if d.Day == weekday {
+				days = append(days, weekday)
+				break
+			}
+		}
+	}
+	return days
+}
+
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	stampCreated(&u.CreatedAt, &u.UpdatedAt)
+	return nil
+}
+
+func (u *User) BeforeUpdate(tx *gorm.DB) error {
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+// BeforeSave keeps NormalizedEmail, the unique key accounts are looked up by,
+// in step with Email.
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	u.NormalizedEmail = emailnorm.Normalize(u.Email)
+	return nil
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
 func (u *User) SetPassword(password string) error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 12)
 	if err != nil {
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Events a webhook can subscribe to.
+const (
+	WebhookEventTTRCreated          = "ttr.created"
+	WebhookEventTTRUpdated          = "ttr.updated"
+	WebhookEventTTRCancelled        = "ttr.cancelled"
+	WebhookEventInvitationResponded = "invitation.responded"
+)
+
+// WebhookEvents lists every event a webhook can subscribe to.
+var WebhookEvents = []string{
+	WebhookEventTTRCreated,
+	WebhookEventTTRUpdated,
+	WebhookEventTTRCancelled,
+	WebhookEventInvitationResponded,
+}
+
+// A FAILING webhook gets no deliveries until its owner updates it.
+const (
+	WebhookStatusActive  = "ACTIVE"
+	WebhookStatusFailing = "FAILING"
+)
+
+// Webhook receives signed POSTs about the TTRs of its owner: the TTRs
+// OwnerUserID captains or, when OrganizationID is set, the organization's
+// TTRs. Events is a comma-separated list of subscribed events.
+// ConsecutiveFailures counts deliveries that failed every attempt since the
+// last successful one.
+type Webhook struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	OwnerUserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"owner_user_id"`
+	OrganizationID      *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"`
+	URL                 string     `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret              string     `gorm:"type:varchar(128);not null" json:"-"`
+	Events              string     `gorm:"type:varchar(255);not null" json:"events"`
+	Status              string     `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"`
+	ConsecutiveFailures int        `gorm:"not null;default:0" json:"consecutive_failures"`
+	CreatedAt           time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt           time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+func (w *Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// EventList splits Events.
+func (w *Webhook) EventList() []string {
+	if w.Events == "" {
+		return nil
+	}
+	return strings.Split(w.Events, ",")
+}
+
+// Subscribes reports whether the webhook wants event.
+func (w *Webhook) Subscribes(event string) bool {
+	for _, e := range w.EventList() {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records one attempt to deliver an event to a webhook.
+// StatusCode is set when the endpoint answered, Error when the attempt
+// failed.
+type WebhookDelivery struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	WebhookID  uuid.UUID `gorm:"type:uuid;not null;index:idx_webhook_deliveries_webhook_created,priority:1" json:"webhook_id"`
+	EventID    uuid.UUID `gorm:"type:uuid;not null" json:"event_id"`
+	Event      string    `gorm:"type:varchar(50);not null" json:"event"`
+	Attempt    int       `gorm:"not null" json:"attempt"`
+	StatusCode *int      `json:"status_code,omitempty"`
+	Error      *string   `gorm:"type:text" json:"error,omitempty"`
+	Succeeded  bool      `gorm:"not null;default:false" json:"succeeded"`
+	DurationMs int64     `gorm:"not null;default:0" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP;index:idx_webhook_deliveries_webhook_created,priority:2" json:"created_at"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// ActionItemRepository finds what a user has to act on, one query per kind
+// of item. Only live TTRs count: deleted, cancelled and completed ones are
+// left out, and so are TTRs whose tee date is before from. Callers filter on
+// the exact tee time.
+type ActionItemRepository interface {
+	PendingInvitations(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Invitation, error)
+	AwaitingAnswers(ctx context.Context, captainID uuid.UUID, from time.Time, until time.Time) ([]*models.TTR, error)
+	MaybeRounds(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.TTR, error)
+}
+
+// liveTTRStatuses are the statuses of TTRs that are still going to be played.
+var liveTTRStatuses = []models.TTRStatus{models.TTRStatusOpen, models.TTRStatusConfirmed}
+
+type actionItemRepository struct {
+	db *gorm.DB
+}
+
+func NewActionItemRepository(db *gorm.DB) ActionItemRepository {
+	return &actionItemRepository{db: db}
+}
+
+// PendingInvitations returns userID's PENDING invitations with their TTR,
+// soonest tee date first.
+func (r *actionItemRepository) PendingInvitations(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Invitation, error) {
+	var invitations []*models.Invitation
+	if err := txOrDB(ctx, r.db).
+		Preload("TTR").
+		Joins("JOIN ttrs ON ttrs.id = invitations.ttr_id AND ttrs.deleted_at IS NULL").
+		Where("invitations.invitee_user_id = ? AND invitations.status = ?", userID, models.InvitationStatusPending).
+		Where("ttrs.status IN ? AND ttrs.tee_date >= ?", liveTTRStatuses, from).
+		Order("ttrs.tee_date ASC, ttrs.tee_time ASC").
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find pending invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// AwaitingAnswers returns the TTRs captainID captains, with a tee date from
+// from to until, that still have PENDING invitations, soonest first.
+func (r *actionItemRepository) AwaitingAnswers(ctx context.Context, captainID uuid.UUID, from time.Time, until time.Time) ([]*models.TTR, error) {
+	var ttrs []*models.TTR
+	if err := txOrDB(ctx, r.db).
+		Where("ttrs.captain_user_id = ? AND ttrs.status IN ? AND ttrs.tee_date >= ? AND ttrs.tee_date <= ?", captainID, liveTTRStatuses, from, until).
+		Where("EXISTS (SELECT 1 FROM invitations WHERE invitations.ttr_id = ttrs.id AND invitations.status = ?)", models.InvitationStatusPending).
+		Order("ttrs.tee_date ASC, ttrs.tee_time ASC").
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttrs awaiting answers: %w", err)
+	}
+	return ttrs, nil
+}
+
+// MaybeRounds returns the TTRs userID is on as a MAYBE player, soonest
+// first.
+func (r *actionItemRepository) MaybeRounds(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.TTR, error) {
+	var ttrs []*models.TTR
+	if err := txOrDB(ctx, r.db).
+		Joins("JOIN ttr_players ON ttr_players.ttr_id = ttrs.id").
+		Where("ttr_players.user_id = ? AND ttr_players.status = ?", userID, models.TTRPlayerStatusMaybe).
+		Where("ttrs.status IN ? AND ttrs.tee_date >= ?", liveTTRStatuses, from).
+		Order("ttrs.tee_date ASC, ttrs.tee_time ASC").
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find maybe rounds: %w", err)
+	}
+	return ttrs, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *models.APIToken) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.APIToken, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.APIToken, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.APIToken, error)
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type apiTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	if err := txOrDB(ctx, r.db).Create(token).Error; err != nil {
+		return createError("create API token", err)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.APIToken, error) {
+	var token models.APIToken
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find API token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindByTokenHash returns the token with its user preloaded.
+func (r *apiTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	if err := txOrDB(ctx, r.db).Where("token_hash = ?", tokenHash).Preload("User").First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find API token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindByUserID returns the user's tokens, newest first.
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	if err := txOrDB(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to find API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := txOrDB(ctx, r.db).Model(&models.APIToken{}).
+		Where("id = ?", id).
+		Update("last_used_at", at).Error; err != nil {
+		return fmt.Errorf("failed to update API token: %w", err)
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).Delete(&models.APIToken{}).Error; err != nil {
+		return fmt.Errorf("failed to delete API token: %w", err)
+	}
+	return nil
+}
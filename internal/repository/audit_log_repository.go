@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLogEntry) error
+	Find(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]*models.AuditLogEntry, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLogEntry) error {
+	if err := txOrDB(ctx, r.db).Create(entry).Error; err != nil {
+		return createError("create audit log entry", err)
+	}
+	return nil
+}
+
+// Find returns entries newest first. When userID is set, only entries where
+// the user is the actor or the subject are returned.
+func (r *auditLogRepository) Find(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]*models.AuditLogEntry, error) {
+	query := txOrDB(ctx, r.db)
+	if userID != nil {
+		query = query.Where("actor_user_id = ? OR subject_user_id = ?", *userID, *userID)
+	}
+
+	var entries []*models.AuditLogEntry
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find audit log entries: %w", err)
+	}
+	return entries, nil
+}
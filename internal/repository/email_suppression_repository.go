@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmailSuppressionRepository interface {
+	FindByEmailForUpdate(ctx context.Context, email string) (*models.EmailSuppression, error)
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Upsert(ctx context.Context, suppression *models.EmailSuppression) error
+}
+
+type emailSuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailSuppressionRepository(db *gorm.DB) EmailSuppressionRepository {
+	return &emailSuppressionRepository{db: db}
+}
+
+// FindByEmailForUpdate returns the address's row, locked until the
+// transaction in ctx ends, or nil when there is none. email must be
+// normalized.
+func (r *emailSuppressionRepository) FindByEmailForUpdate(ctx context.Context, email string) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	if err := txOrDB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("email = ?", email).
+		First(&suppression).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find email suppression: %w", err)
+	}
+	return &suppression, nil
+}
+
+// IsSuppressed reports whether mail to the normalized email is suppressed.
+func (r *emailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.EmailSuppression{}).
+		Where("email = ? AND suppressed_at IS NOT NULL", email).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check email suppression: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Upsert creates the address's row or replaces it, keeping CreatedAt.
+func (r *emailSuppressionRepository) Upsert(ctx context.Context, suppression *models.EmailSuppression) error {
+	now := time.Now()
+	if suppression.CreatedAt.IsZero() {
+		suppression.CreatedAt = now
+	}
+	suppression.UpdatedAt = now
+	if err := txOrDB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "soft_bounces", "suppressed_at", "last_event_id", "updated_at"}),
+	}).Create(suppression).Error; err != nil {
+		return fmt.Errorf("failed to save email suppression: %w", err)
+	}
+	return nil
+}
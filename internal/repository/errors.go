@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicate is wrapped by errors from writes that violate a unique
+// constraint, so callers can report a conflict instead of a failure.
+var ErrDuplicate = gorm.ErrDuplicatedKey
+
+// ErrNoUsesLeft is returned when redeeming an invite link whose uses are all
+// taken, and ErrTTRFull when the TTR has no open slot left for a player.
+var (
+	ErrNoUsesLeft = errors.New("invite link has no uses left")
+	ErrTTRFull    = errors.New("TTR is full")
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE for unique_violation.
+const pgUniqueViolation = "23505"
+
+// createError wraps an error from inserting what, marking unique-constraint
+// violations with ErrDuplicate.
+func createError(what string, err error) error {
+	if isUniqueViolation(err) {
+		return fmt.Errorf("failed to %s: %w: %v", what, ErrDuplicate, err)
+	}
+	return fmt.Errorf("failed to %s: %w", what, err)
+}
+
+func isUniqueViolation(err error) bool {
+	if errors.Is(err, ErrDuplicate) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgUniqueViolation
+	}
+	// SQLite, used by the tests, reports both unique indexes and composite
+	// primary keys this way.
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FeatureFlagRepository interface {
+	FindAll(ctx context.Context) ([]*models.FeatureFlag, error)
+	FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	Upsert(ctx context.Context, flag *models.FeatureFlag) error
+	Delete(ctx context.Context, key string) (bool, error)
+}
+
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+func NewFeatureFlagRepository(db *gorm.DB) FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+// FindAll returns every flag ordered by key.
+func (r *featureFlagRepository) FindAll(ctx context.Context) ([]*models.FeatureFlag, error) {
+	var flags []*models.FeatureFlag
+	if err := txOrDB(ctx, r.db).Order("key ASC").Find(&flags).Error; err != nil {
+		return nil, fmt.Errorf("failed to find feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+func (r *featureFlagRepository) FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	if err := txOrDB(ctx, r.db).Where("key = ?", key).First(&flag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+// Upsert creates the flag or replaces its rule, keeping CreatedAt.
+func (r *featureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	now := time.Now()
+	if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = now
+	}
+	flag.UpdatedAt = now
+	if err := txOrDB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "enabled", "percentage", "user_ids", "organization_ids", "updated_at"}),
+	}).Create(flag).Error; err != nil {
+		return fmt.Errorf("failed to save feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the flag and reports whether it existed.
+func (r *featureFlagRepository) Delete(ctx context.Context, key string) (bool, error) {
+	result := txOrDB(ctx, r.db).Where("key = ?", key).Delete(&models.FeatureFlag{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete feature flag: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// HistorySummary aggregates the rounds a user played in a period.
+type HistorySummary struct {
+	Rounds  int
+	Courses int
+}
+
+// PartnerCount is how many of a user's rounds another user played in.
+type PartnerCount struct {
+	UserID uuid.UUID
+	Rounds int
+}
+
+// HistoryRepository reads a user's round history. A round is a completed TTR
+// the user was a confirmed player in, and a period runs from its from date up
+// to but not including its to date. Deleted TTRs are left out.
+type HistoryRepository interface {
+	Rounds(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) ([]*models.TTR, error)
+	Summary(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) (HistorySummary, error)
+	TopPartners(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, limit int) ([]PartnerCount, error)
+}
+
+type historyRepository struct {
+	db *gorm.DB
+}
+
+func NewHistoryRepository(db *gorm.DB) HistoryRepository {
+	return &historyRepository{db: db}
+}
+
+// playedRoundsQuery selects the user's rounds in a period. It takes the user
+// ID, the confirmed player status, the completed TTR status and the period.
+const playedRoundsQuery = `
+SELECT t.id AS ttr_id, t.course_name
+FROM ttrs t
+JOIN ttr_players me ON me.ttr_id = t.id
+WHERE me.user_id = ? AND me.status = ? AND t.status = ? AND t.deleted_at IS NULL
+	AND t.tee_date >= ? AND t.tee_date < ?`
+
+func playedRoundsArgs(userID uuid.UUID, from time.Time, to time.Time) []interface{} {
+	return []interface{}{userID, models.TTRPlayerStatusConfirmed, models.TTRStatusCompleted, from, to}
+}
+
+// Rounds returns the user's rounds in the period with their rosters, by tee
+// time.
+func (r *historyRepository) Rounds(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) ([]*models.TTR, error) {
+	var ttrs []*models.TTR
+	if err := txOrDB(ctx, r.db).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Joins("JOIN ttr_players me ON me.ttr_id = ttrs.id").
+		Where("me.user_id = ? AND me.status = ? AND ttrs.status = ? AND ttrs.tee_date >= ? AND ttrs.tee_date < ?",
+			playedRoundsArgs(userID, from, to)...).
+		Order("ttrs.tee_date, ttrs.tee_time").
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find rounds: %w", err)
+	}
+	return ttrs, nil
+}
+
+// Summary counts the user's rounds in the period and the courses they were
+// played on, telling course names apart regardless of case.
+func (r *historyRepository) Summary(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) (HistorySummary, error) {
+	query := `
+WITH rounds AS (` + playedRoundsQuery + `
+)
+SELECT COUNT(*) AS rounds, COUNT(DISTINCT LOWER(course_name)) AS courses
+FROM rounds`
+
+	var summary HistorySummary
+	if err := txOrDB(ctx, r.db).Raw(query, playedRoundsArgs(userID, from, to)...).Scan(&summary).Error; err != nil {
+		return HistorySummary{}, fmt.Errorf("failed to summarize rounds: %w", err)
+	}
+	return summary, nil
+}
+
+// TopPartners returns the users who were confirmed players in the most of the
+// user's rounds in the period, at most limit of them. Ties go by name, and
+// deleted users are left out.
+func (r *historyRepository) TopPartners(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, limit int) ([]PartnerCount, error) {
+	query := `
+WITH rounds AS (` + playedRoundsQuery + `
+)
+SELECT p.user_id, COUNT(*) AS rounds
+FROM ttr_players p
+JOIN rounds r ON r.ttr_id = p.ttr_id
+JOIN users u ON u.id = p.user_id
+WHERE p.user_id <> ? AND p.status = ? AND u.deleted_at IS NULL
+GROUP BY p.user_id, u.first_name, u.last_name
+ORDER BY rounds DESC, u.first_name, u.last_name, p.user_id
+LIMIT ?`
+
+	args := append(playedRoundsArgs(userID, from, to), userID, models.TTRPlayerStatusConfirmed, limit)
+	var partners []PartnerCount
+	if err := txOrDB(ctx, r.db).Raw(query, args...).Scan(&partners).Error; err != nil {
+		return nil, fmt.Errorf("failed to rank partners: %w", err)
+	}
+	return partners, nil
+}
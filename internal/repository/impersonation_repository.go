@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ImpersonationRepository interface {
+	CreateSession(ctx context.Context, session *models.ImpersonationSession) error
+	FindSession(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error)
+	// EndSession sets the session's EndedAt, reporting false when it had
+	// already ended or does not exist.
+	EndSession(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+func (r *impersonationRepository) CreateSession(ctx context.Context, session *models.ImpersonationSession) error {
+	if err := txOrDB(ctx, r.db).Create(session).Error; err != nil {
+		return createError("create impersonation session", err)
+	}
+	return nil
+}
+
+func (r *impersonationRepository) FindSession(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find impersonation session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *impersonationRepository) EndSession(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.ImpersonationSession{}).
+		Where("id = ? AND ended_at IS NULL", id).
+		Update("ended_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to end impersonation session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
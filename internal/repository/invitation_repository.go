@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
@@ -10,13 +12,19 @@ import (
 )
 
 type InvitationRepository interface {
-	Create(invitation *models.Invitation) error
-	FindByID(id uuid.UUID) (*models.Invitation, error)
-	FindReceivedByUserID(userID uuid.UUID) ([]*models.Invitation, error)
-	FindSentByUserID(userID uuid.UUID) ([]*models.Invitation, error)
-	Update(invitation *models.Invitation) error
-	Delete(id uuid.UUID) error
-	FindByTTRAndInvitee(ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error)
+	Create(ctx context.Context, invitation *models.Invitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error)
+	FindReceivedByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error)
+	FindSentByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error)
+	FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error)
+	FindByTTRIDs(ctx context.Context, ttrIDs []uuid.UUID) ([]*models.Invitation, error)
+	Update(ctx context.Context, invitation *models.Invitation) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindByTTRAndInvitee(ctx context.Context, ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error)
+	FindByTTRsAndInvitee(ctx context.Context, ttrIDs []uuid.UUID, inviteeUserID uuid.UUID) ([]*models.Invitation, error)
+	CancelPendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error)
+	ExpirePendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error)
+	CountPendingByCaptainID(ctx context.Context, captainID uuid.UUID, from time.Time) (map[uuid.UUID]int, error)
 }
 
 type invitationRepository struct {
@@ -27,20 +35,22 @@ func NewInvitationRepository(db *gorm.DB) InvitationRepository {
 	return &invitationRepository{db: db}
 }
 
-func (r *invitationRepository) Create(invitation *models.Invitation) error {
-	if err := r.db.Create(invitation).Error; err != nil {
-		return fmt.Errorf("failed to create invitation: %w", err)
+// Create inserts the invitation. A user can only have one pending
+// invitation per TTR; a second one fails with ErrDuplicate.
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	if err := txOrDB(ctx, r.db).Create(invitation).Error; err != nil {
+		return createError("create invitation", err)
 	}
 	return nil
 }
 
-func (r *invitationRepository) FindByID(id uuid.UUID) (*models.Invitation, error) {
+func (r *invitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error) {
 	var invitation models.Invitation
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Preload("TTR").
-		Preload("TTR.CaptainUser").
-		Preload("InviterUser").
-		Preload("InviteeUser").
+		Preload("TTR.CaptainUser", withDeletedUsers).
+		Preload("InviterUser", withDeletedUsers).
+		Preload("InviteeUser", withDeletedUsers).
 		Where("id = ?", id).
 		First(&invitation).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -51,14 +61,14 @@ func (r *invitationRepository) FindByID(id uuid.UUID) (*models.Invitation, error
 	return &invitation, nil
 }
 
-func (r *invitationRepository) FindReceivedByUserID(userID uuid.UUID) ([]*models.Invitation, error) {
+func (r *invitationRepository) FindReceivedByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
 	var invitations []*models.Invitation
 
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Preload("TTR").
-		Preload("TTR.CaptainUser").
-		Preload("InviterUser").
-		Preload("InviteeUser").
+		Preload("TTR.CaptainUser", withDeletedUsers).
+		Preload("InviterUser", withDeletedUsers).
+		Preload("InviteeUser", withDeletedUsers).
 		Where("invitee_user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&invitations).Error; err != nil {
@@ -68,14 +78,14 @@ func (r *invitationRepository) FindReceivedByUserID(userID uuid.UUID) ([]*models
 	return invitations, nil
 }
 
-func (r *invitationRepository) FindSentByUserID(userID uuid.UUID) ([]*models.Invitation, error) {
+func (r *invitationRepository) FindSentByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
 	var invitations []*models.Invitation
 
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Preload("TTR").
-		Preload("TTR.CaptainUser").
-		Preload("InviterUser").
-		Preload("InviteeUser").
+		Preload("TTR.CaptainUser", withDeletedUsers).
+		Preload("InviterUser", withDeletedUsers).
+		Preload("InviteeUser", withDeletedUsers).
 		Where("inviter_user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&invitations).Error; err != nil {
@@ -85,23 +95,59 @@ func (r *invitationRepository) FindSentByUserID(userID uuid.UUID) ([]*models.Inv
 	return invitations, nil
 }
 
-func (r *invitationRepository) Update(invitation *models.Invitation) error {
-	if err := r.db.Save(invitation).Error; err != nil {
+// FindByTTRID returns every invitation sent for the TTR, newest first, with
+// both users.
+func (r *invitationRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	var invitations []*models.Invitation
+
+	if err := txOrDB(ctx, r.db).
+		Preload("InviterUser", withDeletedUsers).
+		Preload("InviteeUser", withDeletedUsers).
+		Where("ttr_id = ?", ttrID).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find TTR invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// FindByTTRIDs is FindByTTRID for several TTRs at once, newest first.
+func (r *invitationRepository) FindByTTRIDs(ctx context.Context, ttrIDs []uuid.UUID) ([]*models.Invitation, error) {
+	if len(ttrIDs) == 0 {
+		return nil, nil
+	}
+	var invitations []*models.Invitation
+
+	if err := txOrDB(ctx, r.db).
+		Preload("InviterUser", withDeletedUsers).
+		Preload("InviteeUser", withDeletedUsers).
+		Where("ttr_id IN ?", ttrIDs).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find TTR invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *invitationRepository) Update(ctx context.Context, invitation *models.Invitation) error {
+	if err := txOrDB(ctx, r.db).Save(invitation).Error; err != nil {
 		return fmt.Errorf("failed to update invitation: %w", err)
 	}
 	return nil
 }
 
-func (r *invitationRepository) Delete(id uuid.UUID) error {
-	if err := r.db.Delete(&models.Invitation{}, id).Error; err != nil {
+func (r *invitationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Delete(&models.Invitation{}, id).Error; err != nil {
 		return fmt.Errorf("failed to delete invitation: %w", err)
 	}
 	return nil
 }
 
-func (r *invitationRepository) FindByTTRAndInvitee(ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
+func (r *invitationRepository) FindByTTRAndInvitee(ctx context.Context, ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
 	var invitation models.Invitation
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Where("ttr_id = ? AND invitee_user_id = ?", ttrID, inviteeUserID).
 		First(&invitation).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -111,3 +157,82 @@ func (r *invitationRepository) FindByTTRAndInvitee(ttrID uuid.UUID, inviteeUserI
 	}
 	return &invitation, nil
 }
+
+// FindByTTRsAndInvitee returns the invitee's invitations to any of the TTRs.
+func (r *invitationRepository) FindByTTRsAndInvitee(ctx context.Context, ttrIDs []uuid.UUID, inviteeUserID uuid.UUID) ([]*models.Invitation, error) {
+	if len(ttrIDs) == 0 {
+		return nil, nil
+	}
+	var invitations []*models.Invitation
+	if err := txOrDB(ctx, r.db).
+		Where("ttr_id IN ? AND invitee_user_id = ?", ttrIDs, inviteeUserID).
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find invitations by TTRs and invitee: %w", err)
+	}
+	return invitations, nil
+}
+
+// CancelPendingByTTRID marks every PENDING invitation for the TTR as CANCELED
+// and returns the invitations it changed.
+func (r *invitationRepository) CancelPendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	return r.closePendingByTTRID(ctx, ttrID, models.InvitationStatusCanceled)
+}
+
+// ExpirePendingByTTRID marks every PENDING invitation for the TTR as EXPIRED
+// and returns the invitations it changed.
+func (r *invitationRepository) ExpirePendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	return r.closePendingByTTRID(ctx, ttrID, models.InvitationStatusExpired)
+}
+
+func (r *invitationRepository) closePendingByTTRID(ctx context.Context, ttrID uuid.UUID, status models.InvitationStatus) ([]*models.Invitation, error) {
+	db := txOrDB(ctx, r.db)
+
+	var invitations []*models.Invitation
+	if err := db.
+		Where("ttr_id = ? AND status = ?", ttrID, models.InvitationStatusPending).
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find pending invitations: %w", err)
+	}
+	if len(invitations) == 0 {
+		return invitations, nil
+	}
+
+	ids := make([]uuid.UUID, len(invitations))
+	for i, invitation := range invitations {
+		ids[i] = invitation.ID
+		invitation.Status = status
+	}
+
+	if err := db.Model(&models.Invitation{}).
+		Where("id IN ?", ids).
+		Update("status", status).Error; err != nil {
+		return nil, fmt.Errorf("failed to close pending invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+// CountPendingByCaptainID counts the PENDING invitations of each TTR
+// captainID captains that is still going to be played, with a tee date from
+// from on. TTRs without pending invitations are left out of the map.
+func (r *invitationRepository) CountPendingByCaptainID(ctx context.Context, captainID uuid.UUID, from time.Time) (map[uuid.UUID]int, error) {
+	var rows []struct {
+		TTRID uuid.UUID
+		Count int
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.Invitation{}).
+		Select("invitations.ttr_id, COUNT(*) AS count").
+		Joins("JOIN ttrs ON ttrs.id = invitations.ttr_id AND ttrs.deleted_at IS NULL").
+		Where("ttrs.captain_user_id = ? AND invitations.status = ?", captainID, models.InvitationStatusPending).
+		Where("ttrs.status IN ? AND ttrs.tee_date >= ?", liveTTRStatuses, from).
+		Group("invitations.ttr_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count pending invitations: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.TTRID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type InvitationTemplateRepository interface {
+	Create(ctx context.Context, template *models.InvitationTemplate) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.InvitationTemplate, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.InvitationTemplate, error)
+	Update(ctx context.Context, template *models.InvitationTemplate) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type invitationTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationTemplateRepository(db *gorm.DB) InvitationTemplateRepository {
+	return &invitationTemplateRepository{db: db}
+}
+
+func (r *invitationTemplateRepository) Create(ctx context.Context, template *models.InvitationTemplate) error {
+	if err := txOrDB(ctx, r.db).Create(template).Error; err != nil {
+		return createError("create invitation template", err)
+	}
+	return nil
+}
+
+func (r *invitationTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.InvitationTemplate, error) {
+	var template models.InvitationTemplate
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&template).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find invitation template: %w", err)
+	}
+	return &template, nil
+}
+
+// FindByUserID returns the user's templates by name.
+func (r *invitationTemplateRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.InvitationTemplate, error) {
+	var templates []*models.InvitationTemplate
+	if err := txOrDB(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("name ASC, id ASC").
+		Find(&templates).Error; err != nil {
+		return nil, fmt.Errorf("failed to find invitation templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (r *invitationTemplateRepository) Update(ctx context.Context, template *models.InvitationTemplate) error {
+	if err := txOrDB(ctx, r.db).Save(template).Error; err != nil {
+		return fmt.Errorf("failed to update invitation template: %w", err)
+	}
+	return nil
+}
+
+func (r *invitationTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).Delete(&models.InvitationTemplate{}).Error; err != nil {
+		return fmt.Errorf("failed to delete invitation template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type InviteLinkRepository interface {
+	Create(ctx context.Context, link *models.InviteLink) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.InviteLink, error)
+	FindByToken(ctx context.Context, token string) (*models.InviteLink, error)
+	FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.InviteLink, error)
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	Redeem(ctx context.Context, link *models.InviteLink, userID uuid.UUID) error
+}
+
+type inviteLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewInviteLinkRepository(db *gorm.DB) InviteLinkRepository {
+	return &inviteLinkRepository{db: db}
+}
+
+func (r *inviteLinkRepository) Create(ctx context.Context, link *models.InviteLink) error {
+	if err := txOrDB(ctx, r.db).Create(link).Error; err != nil {
+		return createError("create invite link", err)
+	}
+	return nil
+}
+
+func (r *inviteLinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.InviteLink, error) {
+	var link models.InviteLink
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&link).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find invite link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *inviteLinkRepository) FindByToken(ctx context.Context, token string) (*models.InviteLink, error) {
+	var link models.InviteLink
+	if err := txOrDB(ctx, r.db).Where("token = ?", token).First(&link).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find invite link: %w", err)
+	}
+	return &link, nil
+}
+
+// FindByTTRID returns the TTR's invite links, newest first.
+func (r *inviteLinkRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.InviteLink, error) {
+	var links []*models.InviteLink
+	if err := txOrDB(ctx, r.db).
+		Where("ttr_id = ?", ttrID).
+		Order("created_at DESC").
+		Find(&links).Error; err != nil {
+		return nil, fmt.Errorf("failed to find invite links: %w", err)
+	}
+	return links, nil
+}
+
+func (r *inviteLinkRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	if err := txOrDB(ctx, r.db).Model(&models.InviteLink{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt).Error; err != nil {
+		return fmt.Errorf("failed to revoke invite link: %w", err)
+	}
+	return nil
+}
+
+// Redeem takes one of the link's uses and adds userID to its TTR as a
+// confirmed player, in one transaction. It returns ErrTTRFull when the TTR
+// has no open slot and ErrNoUsesLeft when the link is used up. The TTR row
+// stays locked until the player is added, so concurrent redeems can't
+// overfill the TTR or the link.
+func (r *inviteLinkRepository) Redeem(ctx context.Context, link *models.InviteLink, userID uuid.UUID) error {
+	return txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenSlot(tx, link.TTRID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.InviteLink{}).
+			Where("id = ? AND uses < max_uses", link.ID).
+			Update("uses", gorm.Expr("uses + 1"))
+		if result.Error != nil {
+			return fmt.Errorf("failed to use invite link: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrNoUsesLeft
+		}
+
+		player := &models.TTRPlayer{
+			TTRID:  link.TTRID,
+			UserID: userID,
+			Status: models.DefaultPlayerStatus,
+		}
+		if err := tx.Create(player).Error; err != nil {
+			return createError("add player", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type LeagueRepository interface {
+	Create(ctx context.Context, league *models.League) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.League, error)
+	AttachTTR(ctx context.Context, leagueID uuid.UUID, ttrID uuid.UUID) (bool, error)
+	ComputeStandings(ctx context.Context, league *models.League) ([]*models.LeagueStanding, error)
+}
+
+type leagueRepository struct {
+	db *gorm.DB
+}
+
+func NewLeagueRepository(db *gorm.DB) LeagueRepository {
+	return &leagueRepository{db: db}
+}
+
+func (r *leagueRepository) Create(ctx context.Context, league *models.League) error {
+	if err := txOrDB(ctx, r.db).Create(league).Error; err != nil {
+		return fmt.Errorf("failed to create league: %w", err)
+	}
+	return nil
+}
+
+func (r *leagueRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.League, error) {
+	var league models.League
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&league).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find league by ID: %w", err)
+	}
+	return &league, nil
+}
+
+// AttachTTR sets the TTR's league unless it already has one. It reports false
+// when the TTR belongs to a league already, which keeps two concurrent attaches
+// from both succeeding.
+func (r *leagueRepository) AttachTTR(ctx context.Context, leagueID uuid.UUID, ttrID uuid.UUID) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.TTR{}).
+		Where("id = ? AND league_id IS NULL", ttrID).
+		Update("league_id", leagueID)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to attach TTR to league: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// leagueRoundsQuery scores every recorded round in the league's completed
+// TTRs. A round's points follow its finishing position: with N scores in the
+// round the winner gets N points and last place 1, and RANK gives tied players
+// the better position.
+const leagueRoundsQuery = `
+SELECT p.user_id, p.score,
+	COUNT(*) OVER (PARTITION BY p.ttr_id) - RANK() OVER (PARTITION BY p.ttr_id ORDER BY p.score) + 1 AS points
+FROM ttr_players p
+JOIN ttrs t ON t.id = p.ttr_id
+WHERE t.league_id = ? AND t.status = ? AND t.deleted_at IS NULL AND p.score IS NOT NULL`
+
+// ComputeStandings aggregates the league's recorded scores per player and
+// ranks them under the league's scoring scheme: fewest total strokes for
+// stroke play, most points for points per finish.
+func (r *leagueRepository) ComputeStandings(ctx context.Context, league *models.League) ([]*models.LeagueStanding, error) {
+	order := "total_strokes ASC"
+	if league.ScoringScheme == models.LeagueScoringPointsPerFinish {
+		order = "points DESC"
+	}
+
+	query := `
+WITH rounds AS (` + leagueRoundsQuery + `
+), totals AS (
+	SELECT user_id, COUNT(*) AS rounds, SUM(score) AS total_strokes, SUM(points) AS points
+	FROM rounds
+	GROUP BY user_id
+)
+SELECT RANK() OVER (ORDER BY ` + order + `) AS position, user_id, rounds, total_strokes, points
+FROM totals
+ORDER BY position, user_id`
+
+	var standings []*models.LeagueStanding
+	if err := txOrDB(ctx, r.db).Raw(query, league.ID, models.TTRStatusCompleted).Scan(&standings).Error; err != nil {
+		return nil, fmt.Errorf("failed to compute league standings: %w", err)
+	}
+	return standings, nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type actionItemRepository struct {
+	store *Store
+}
+
+func NewActionItemRepository(store *Store) repository.ActionItemRepository {
+	return &actionItemRepository{store: store}
+}
+
+// liveTTR returns the TTR when it isn't deleted, cancelled or completed and
+// its tee date isn't before from. The caller must hold s.mu.
+func (s *Store) liveTTR(id uuid.UUID, from time.Time) (models.TTR, bool) {
+	ttr, ok := s.ttrs[id]
+	if !ok || ttr.DeletedAt.Valid || ttr.TeeDate.Before(from) {
+		return models.TTR{}, false
+	}
+	return ttr, ttr.Status == models.TTRStatusOpen || ttr.Status == models.TTRStatusConfirmed
+}
+
+func (r *actionItemRepository) PendingInvitations(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.Invitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invitations := make([]*models.Invitation, 0)
+	for _, row := range r.store.invitations {
+		if row.InviteeUserID != userID || row.Status != models.InvitationStatusPending {
+			continue
+		}
+		ttr, ok := r.store.liveTTR(row.TTRID, from)
+		if !ok {
+			continue
+		}
+		invitation := row
+		invitation.TTR = &ttr
+		invitations = append(invitations, &invitation)
+	}
+	sortByTime(invitations, func(i *models.Invitation) time.Time { return i.TTR.TeeDateTime() }, func(i *models.Invitation) uuid.UUID { return i.ID }, false)
+	return invitations, nil
+}
+
+func (r *actionItemRepository) AwaitingAnswers(ctx context.Context, captainID uuid.UUID, from time.Time, until time.Time) ([]*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	awaiting := make(map[uuid.UUID]bool)
+	for _, invitation := range r.store.invitations {
+		if invitation.Status == models.InvitationStatusPending {
+			awaiting[invitation.TTRID] = true
+		}
+	}
+
+	ttrs := make([]*models.TTR, 0)
+	for id := range awaiting {
+		ttr, ok := r.store.liveTTR(id, from)
+		if !ok || ttr.CaptainUserID != captainID || ttr.TeeDate.After(until) {
+			continue
+		}
+		ttrs = append(ttrs, &ttr)
+	}
+	sortByTime(ttrs, (*models.TTR).TeeDateTime, func(t *models.TTR) uuid.UUID { return t.ID }, false)
+	return ttrs, nil
+}
+
+func (r *actionItemRepository) MaybeRounds(ctx context.Context, userID uuid.UUID, from time.Time) ([]*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ttrs := make([]*models.TTR, 0)
+	for _, player := range r.store.players {
+		if player.UserID != userID || player.Status != models.TTRPlayerStatusMaybe {
+			continue
+		}
+		ttr, ok := r.store.liveTTR(player.TTRID, from)
+		if !ok {
+			continue
+		}
+		ttrs = append(ttrs, &ttr)
+	}
+	sortByTime(ttrs, (*models.TTR).TeeDateTime, func(t *models.TTR) uuid.UUID { return t.ID }, false)
+	return ttrs, nil
+}
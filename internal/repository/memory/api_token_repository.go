@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type apiTokenRepository struct {
+	store *Store
+}
+
+func NewAPITokenRepository(store *Store) repository.APITokenRepository {
+	return &apiTokenRepository{store: store}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *models.APIToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	for _, existing := range r.store.apiTokens {
+		if existing.ID == token.ID || existing.TokenHash == token.TokenHash {
+			return duplicateKey("create API token")
+		}
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	stored := *token
+	stored.User = nil
+	r.store.apiTokens[token.ID] = stored
+	return nil
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.APIToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	token, ok := r.store.apiTokens[id]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.APIToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.apiTokens {
+		if token.TokenHash == tokenHash {
+			if user := r.store.user(token.UserID); user != nil && !user.DeletedAt.Valid {
+				token.User = user
+			}
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.APIToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tokens []*models.APIToken
+	for _, token := range r.store.apiTokens {
+		if token.UserID == userID {
+			token := token
+			tokens = append(tokens, &token)
+		}
+	}
+	sortByTime(tokens, func(t *models.APIToken) time.Time { return t.CreatedAt }, func(t *models.APIToken) uuid.UUID { return t.ID }, true)
+	return tokens, nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if token, ok := r.store.apiTokens[id]; ok {
+		token.LastUsedAt = &at
+		r.store.apiTokens[id] = token
+	}
+	return nil
+}
+
+func (r *apiTokenRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.apiTokens, id)
+	return nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type auditLogRepository struct {
+	store *Store
+}
+
+func NewAuditLogRepository(store *Store) repository.AuditLogRepository {
+	return &auditLogRepository{store: store}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLogEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if _, exists := r.store.auditLogEntries[entry.ID]; exists {
+		return duplicateKey("create audit log entry")
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	r.store.auditLogEntries[entry.ID] = *entry
+	return nil
+}
+
+func (r *auditLogRepository) Find(ctx context.Context, userID *uuid.UUID, limit int, offset int) ([]*models.AuditLogEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []*models.AuditLogEntry
+	for _, entry := range r.store.auditLogEntries {
+		if userID != nil && entry.ActorUserID != *userID && (entry.SubjectUserID == nil || *entry.SubjectUserID != *userID) {
+			continue
+		}
+		entry := entry
+		entries = append(entries, &entry)
+	}
+	sortByTime(entries, func(e *models.AuditLogEntry) time.Time { return e.CreatedAt }, func(e *models.AuditLogEntry) uuid.UUID { return e.ID }, true)
+	return page(entries, limit, offset), nil
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type emailSuppressionRepository struct {
+	store *Store
+}
+
+func NewEmailSuppressionRepository(store *Store) repository.EmailSuppressionRepository {
+	return &emailSuppressionRepository{store: store}
+}
+
+func (r *emailSuppressionRepository) FindByEmailForUpdate(ctx context.Context, email string) (*models.EmailSuppression, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	suppression, ok := r.store.emailSuppressions[email]
+	if !ok {
+		return nil, nil
+	}
+	return &suppression, nil
+}
+
+func (r *emailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	suppression, ok := r.store.emailSuppressions[email]
+	return ok && suppression.Suppressed(), nil
+}
+
+func (r *emailSuppressionRepository) Upsert(ctx context.Context, suppression *models.EmailSuppression) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := r.store.emailSuppressions[suppression.Email]; ok {
+		suppression.CreatedAt = existing.CreatedAt
+	} else if suppression.CreatedAt.IsZero() {
+		suppression.CreatedAt = now
+	}
+	suppression.UpdatedAt = now
+	r.store.emailSuppressions[suppression.Email] = *suppression
+	return nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type featureFlagRepository struct {
+	store *Store
+}
+
+func NewFeatureFlagRepository(store *Store) repository.FeatureFlagRepository {
+	return &featureFlagRepository{store: store}
+}
+
+func (r *featureFlagRepository) FindAll(ctx context.Context) ([]*models.FeatureFlag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	flags := make([]*models.FeatureFlag, 0, len(r.store.featureFlags))
+	for _, flag := range r.store.featureFlags {
+		flag := flag
+		flags = append(flags, &flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags, nil
+}
+
+func (r *featureFlagRepository) FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	flag, ok := r.store.featureFlags[key]
+	if !ok {
+		return nil, nil
+	}
+	return &flag, nil
+}
+
+func (r *featureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := r.store.featureFlags[flag.Key]; ok {
+		flag.CreatedAt = existing.CreatedAt
+	} else if flag.CreatedAt.IsZero() {
+		flag.CreatedAt = now
+	}
+	flag.UpdatedAt = now
+	r.store.featureFlags[flag.Key] = *flag
+	return nil
+}
+
+func (r *featureFlagRepository) Delete(ctx context.Context, key string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.featureFlags[key]; !ok {
+		return false, nil
+	}
+	delete(r.store.featureFlags, key)
+	return true, nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type historyRepository struct {
+	store *Store
+}
+
+func NewHistoryRepository(store *Store) repository.HistoryRepository {
+	return &historyRepository{store: store}
+}
+
+// playedRounds returns the TTRs the user played in the period, without
+// associations. The caller must hold s.mu.
+func (s *Store) playedRounds(userID uuid.UUID, from time.Time, to time.Time) []models.TTR {
+	var rounds []models.TTR
+	for _, player := range s.players {
+		if player.UserID != userID || player.Status != models.TTRPlayerStatusConfirmed {
+			continue
+		}
+		ttr, ok := s.ttrs[player.TTRID]
+		if !ok || ttr.DeletedAt.Valid || ttr.Status != models.TTRStatusCompleted {
+			continue
+		}
+		if !ttr.TeeDate.Before(from) && ttr.TeeDate.Before(to) {
+			rounds = append(rounds, ttr)
+		}
+	}
+	return rounds
+}
+
+func (r *historyRepository) Rounds(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) ([]*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ttrs := make([]*models.TTR, 0)
+	for _, row := range r.store.playedRounds(userID, from, to) {
+		ttrs = append(ttrs, r.store.loadTTR(row))
+	}
+	sortByTeeTime(ttrs, false)
+	return ttrs, nil
+}
+
+func (r *historyRepository) Summary(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time) (repository.HistorySummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rounds := r.store.playedRounds(userID, from, to)
+	courses := make(map[string]bool)
+	for _, ttr := range rounds {
+		courses[strings.ToLower(ttr.CourseName)] = true
+	}
+	return repository.HistorySummary{Rounds: len(rounds), Courses: len(courses)}, nil
+}
+
+func (r *historyRepository) TopPartners(ctx context.Context, userID uuid.UUID, from time.Time, to time.Time, limit int) ([]repository.PartnerCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	played := make(map[uuid.UUID]bool)
+	for _, ttr := range r.store.playedRounds(userID, from, to) {
+		played[ttr.ID] = true
+	}
+	counts := make(map[uuid.UUID]int)
+	for _, player := range r.store.players {
+		if !played[player.TTRID] || player.UserID == userID || player.Status != models.TTRPlayerStatusConfirmed {
+			continue
+		}
+		if user, ok := r.store.users[player.UserID]; ok && !user.DeletedAt.Valid {
+			counts[player.UserID]++
+		}
+	}
+
+	partners := make([]repository.PartnerCount, 0, len(counts))
+	for id, rounds := range counts {
+		partners = append(partners, repository.PartnerCount{UserID: id, Rounds: rounds})
+	}
+	sort.Slice(partners, func(i, j int) bool {
+		a, b := partners[i], partners[j]
+		if a.Rounds != b.Rounds {
+			return a.Rounds > b.Rounds
+		}
+		userA, userB := r.store.users[a.UserID], r.store.users[b.UserID]
+		if userA.FirstName != userB.FirstName {
+			return userA.FirstName < userB.FirstName
+		}
+		if userA.LastName != userB.LastName {
+			return userA.LastName < userB.LastName
+		}
+		return a.UserID.String() < b.UserID.String()
+	})
+	return page(partners, limit, 0), nil
+}
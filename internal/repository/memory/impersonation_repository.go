@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type impersonationRepository struct {
+	store *Store
+}
+
+func NewImpersonationRepository(store *Store) repository.ImpersonationRepository {
+	return &impersonationRepository{store: store}
+}
+
+func (r *impersonationRepository) CreateSession(ctx context.Context, session *models.ImpersonationSession) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if _, exists := r.store.impersonationSessions[session.ID]; exists {
+		return duplicateKey("create impersonation session")
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	r.store.impersonationSessions[session.ID] = *session
+	return nil
+}
+
+func (r *impersonationRepository) FindSession(ctx context.Context, id uuid.UUID) (*models.ImpersonationSession, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	session, ok := r.store.impersonationSessions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &session, nil
+}
+
+func (r *impersonationRepository) EndSession(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	session, ok := r.store.impersonationSessions[id]
+	if !ok || session.EndedAt != nil {
+		return false, nil
+	}
+	session.EndedAt = &at
+	r.store.impersonationSessions[id] = session
+	return true, nil
+}
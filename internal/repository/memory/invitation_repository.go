@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type invitationRepository struct {
+	store *Store
+}
+
+func NewInvitationRepository(store *Store) repository.InvitationRepository {
+	return &invitationRepository{store: store}
+}
+
+func invitationRow(invitation *models.Invitation) models.Invitation {
+	row := *invitation
+	row.TTR = nil
+	row.InviterUser = nil
+	row.InviteeUser = nil
+	return row
+}
+
+// loadInvitation returns a copy of the invitation with its TTR, unless that
+// was deleted, and both users. The caller must hold s.mu.
+func (s *Store) loadInvitation(row models.Invitation) *models.Invitation {
+	invitation := row
+	if ttr, ok := s.ttrs[invitation.TTRID]; ok && !ttr.DeletedAt.Valid {
+		ttr.CaptainUser = s.user(ttr.CaptainUserID)
+		invitation.TTR = &ttr
+	}
+	invitation.InviterUser = s.user(invitation.InviterUserID)
+	invitation.InviteeUser = s.user(invitation.InviteeUserID)
+	return &invitation
+}
+
+// isPendingInvitee reports whether userID has a PENDING invitation to the TTR.
+// The caller must hold s.mu.
+func (s *Store) isPendingInvitee(ttrID uuid.UUID, userID uuid.UUID) bool {
+	for _, invitation := range s.invitations {
+		if invitation.TTRID == ttrID && invitation.InviteeUserID == userID && invitation.Status == models.InvitationStatusPending {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+	if _, exists := r.store.invitations[invitation.ID]; exists {
+		return duplicateKey("create invitation")
+	}
+	if invitation.Status == "" {
+		invitation.Status = models.InvitationStatusPending
+	}
+	if invitation.Status == models.InvitationStatusPending && r.store.isPendingInvitee(invitation.TTRID, invitation.InviteeUserID) {
+		return duplicateKey("create invitation")
+	}
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	r.store.invitations[invitation.ID] = invitationRow(invitation)
+	return nil
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.invitations[id]
+	if !ok {
+		return nil, nil
+	}
+	return r.store.loadInvitation(row), nil
+}
+
+func (r *invitationRepository) FindReceivedByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
+	return r.find(func(invitation models.Invitation) bool {
+		return invitation.InviteeUserID == userID
+	}), nil
+}
+
+func (r *invitationRepository) FindSentByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
+	return r.find(func(invitation models.Invitation) bool {
+		return invitation.InviterUserID == userID
+	}), nil
+}
+
+func (r *invitationRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	return r.find(func(invitation models.Invitation) bool {
+		return invitation.TTRID == ttrID
+	}), nil
+}
+
+func (r *invitationRepository) FindByTTRIDs(ctx context.Context, ttrIDs []uuid.UUID) ([]*models.Invitation, error) {
+	wanted := make(map[uuid.UUID]bool, len(ttrIDs))
+	for _, id := range ttrIDs {
+		wanted[id] = true
+	}
+	return r.find(func(invitation models.Invitation) bool {
+		return wanted[invitation.TTRID]
+	}), nil
+}
+
+// find returns the invitations matching keep, newest first.
+func (r *invitationRepository) find(keep func(models.Invitation) bool) []*models.Invitation {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invitations := make([]*models.Invitation, 0)
+	for _, row := range r.store.invitations {
+		if keep(row) {
+			invitations = append(invitations, r.store.loadInvitation(row))
+		}
+	}
+	sortByTime(invitations, func(i *models.Invitation) time.Time { return i.CreatedAt }, func(i *models.Invitation) uuid.UUID { return i.ID }, true)
+	return invitations
+}
+
+func (r *invitationRepository) Update(ctx context.Context, invitation *models.Invitation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.invitations[invitation.ID] = invitationRow(invitation)
+	return nil
+}
+
+func (r *invitationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.invitations, id)
+	return nil
+}
+
+func (r *invitationRepository) FindByTTRAndInvitee(ctx context.Context, ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, row := range r.store.invitations {
+		if row.TTRID == ttrID && row.InviteeUserID == inviteeUserID {
+			return &row, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *invitationRepository) FindByTTRsAndInvitee(ctx context.Context, ttrIDs []uuid.UUID, inviteeUserID uuid.UUID) ([]*models.Invitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(ttrIDs))
+	for _, id := range ttrIDs {
+		wanted[id] = true
+	}
+	invitations := make([]*models.Invitation, 0)
+	for _, row := range r.store.invitations {
+		if wanted[row.TTRID] && row.InviteeUserID == inviteeUserID {
+			row := row
+			invitations = append(invitations, &row)
+		}
+	}
+	return invitations, nil
+}
+
+// CancelPendingByTTRID marks every PENDING invitation for the TTR as CANCELED
+// and returns the invitations it changed.
+func (r *invitationRepository) CancelPendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	return r.closePendingByTTRID(ttrID, models.InvitationStatusCanceled), nil
+}
+
+// ExpirePendingByTTRID marks every PENDING invitation for the TTR as EXPIRED
+// and returns the invitations it changed.
+func (r *invitationRepository) ExpirePendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	return r.closePendingByTTRID(ttrID, models.InvitationStatusExpired), nil
+}
+
+func (r *invitationRepository) closePendingByTTRID(ttrID uuid.UUID, status models.InvitationStatus) []*models.Invitation {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	invitations := make([]*models.Invitation, 0)
+	for id, row := range r.store.invitations {
+		if row.TTRID == ttrID && row.Status == models.InvitationStatusPending {
+			row.Status = status
+			r.store.invitations[id] = row
+			row := row
+			invitations = append(invitations, &row)
+		}
+	}
+	sortByTime(invitations, func(i *models.Invitation) time.Time { return i.CreatedAt }, func(i *models.Invitation) uuid.UUID { return i.ID }, false)
+	return invitations
+}
+
+func (r *invitationRepository) CountPendingByCaptainID(ctx context.Context, captainID uuid.UUID, from time.Time) (map[uuid.UUID]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[uuid.UUID]int)
+	for _, row := range r.store.invitations {
+		if row.Status != models.InvitationStatusPending {
+			continue
+		}
+		if ttr, ok := r.store.liveTTR(row.TTRID, from); ok && ttr.CaptainUserID == captainID {
+			counts[row.TTRID]++
+		}
+	}
+	return counts, nil
+}
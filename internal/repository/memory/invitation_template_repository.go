@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type invitationTemplateRepository struct {
+	store *Store
+}
+
+func NewInvitationTemplateRepository(store *Store) repository.InvitationTemplateRepository {
+	return &invitationTemplateRepository{store: store}
+}
+
+func (r *invitationTemplateRepository) Create(ctx context.Context, template *models.InvitationTemplate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if template.ID == uuid.Nil {
+		template.ID = uuid.New()
+	}
+	if _, ok := r.store.invitationTemplates[template.ID]; ok {
+		return duplicateKey("create invitation template")
+	}
+	now := time.Now()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	if template.UpdatedAt.IsZero() {
+		template.UpdatedAt = now
+	}
+
+	r.store.invitationTemplates[template.ID] = *template
+	return nil
+}
+
+func (r *invitationTemplateRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.InvitationTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	template, ok := r.store.invitationTemplates[id]
+	if !ok {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+func (r *invitationTemplateRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.InvitationTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var templates []*models.InvitationTemplate
+	for _, template := range r.store.invitationTemplates {
+		if template.UserID == userID {
+			template := template
+			templates = append(templates, &template)
+		}
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].Name != templates[j].Name {
+			return templates[i].Name < templates[j].Name
+		}
+		return templates[i].ID.String() < templates[j].ID.String()
+	})
+	return templates, nil
+}
+
+func (r *invitationTemplateRepository) Update(ctx context.Context, template *models.InvitationTemplate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	template.UpdatedAt = time.Now()
+	r.store.invitationTemplates[template.ID] = *template
+	return nil
+}
+
+func (r *invitationTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.invitationTemplates, id)
+	return nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type inviteLinkRepository struct {
+	store *Store
+}
+
+func NewInviteLinkRepository(store *Store) repository.InviteLinkRepository {
+	return &inviteLinkRepository{store: store}
+}
+
+func (r *inviteLinkRepository) Create(ctx context.Context, link *models.InviteLink) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if link.ID == uuid.Nil {
+		link.ID = uuid.New()
+	}
+	if _, exists := r.store.inviteLinks[link.ID]; exists {
+		return duplicateKey("create invite link")
+	}
+	for _, existing := range r.store.inviteLinks {
+		if existing.Token == link.Token {
+			return duplicateKey("create invite link")
+		}
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	r.store.inviteLinks[link.ID] = *link
+	return nil
+}
+
+func (r *inviteLinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.InviteLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	link, ok := r.store.inviteLinks[id]
+	if !ok {
+		return nil, nil
+	}
+	return &link, nil
+}
+
+func (r *inviteLinkRepository) FindByToken(ctx context.Context, token string) (*models.InviteLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, link := range r.store.inviteLinks {
+		if link.Token == token {
+			return &link, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *inviteLinkRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.InviteLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	links := make([]*models.InviteLink, 0)
+	for _, link := range r.store.inviteLinks {
+		if link.TTRID == ttrID {
+			link := link
+			links = append(links, &link)
+		}
+	}
+	sortByTime(links, func(l *models.InviteLink) time.Time { return l.CreatedAt }, func(l *models.InviteLink) uuid.UUID { return l.ID }, true)
+	return links, nil
+}
+
+func (r *inviteLinkRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if link, ok := r.store.inviteLinks[id]; ok && link.RevokedAt == nil {
+		link.RevokedAt = &revokedAt
+		r.store.inviteLinks[id] = link
+	}
+	return nil
+}
+
+func (r *inviteLinkRepository) Redeem(ctx context.Context, link *models.InviteLink, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.hasOpenSlot(link.TTRID) {
+		return repository.ErrTTRFull
+	}
+
+	stored, ok := r.store.inviteLinks[link.ID]
+	if !ok || stored.Uses >= stored.MaxUses {
+		return repository.ErrNoUsesLeft
+	}
+	if r.store.isPlayer(link.TTRID, userID) {
+		return duplicateKey("add player")
+	}
+
+	stored.Uses++
+	r.store.inviteLinks[link.ID] = stored
+	r.store.players = append(r.store.players, models.TTRPlayer{
+		TTRID:    link.TTRID,
+		UserID:   userID,
+		JoinedAt: time.Now(),
+		Status:   models.DefaultPlayerStatus,
+	})
+	return nil
+}
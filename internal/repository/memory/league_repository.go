@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type leagueRepository struct {
+	store *Store
+}
+
+func NewLeagueRepository(store *Store) repository.LeagueRepository {
+	return &leagueRepository{store: store}
+}
+
+func (r *leagueRepository) Create(ctx context.Context, league *models.League) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if league.ID == uuid.Nil {
+		league.ID = uuid.New()
+	}
+	if _, exists := r.store.leagues[league.ID]; exists {
+		return duplicateKey("create league")
+	}
+	now := time.Now()
+	if league.CreatedAt.IsZero() {
+		league.CreatedAt = now
+	}
+	league.UpdatedAt = now
+
+	r.store.leagues[league.ID] = *league
+	return nil
+}
+
+func (r *leagueRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.League, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	league, ok := r.store.leagues[id]
+	if !ok || league.DeletedAt.Valid {
+		return nil, nil
+	}
+	return &league, nil
+}
+
+// AttachTTR sets the TTR's league unless it already has one. It reports false
+// when the TTR belongs to a league already.
+func (r *leagueRepository) AttachTTR(ctx context.Context, leagueID uuid.UUID, ttrID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	ttr, ok := r.store.ttrs[ttrID]
+	if !ok || ttr.DeletedAt.Valid || ttr.LeagueID != nil {
+		return false, nil
+	}
+	ttr.LeagueID = &leagueID
+	r.store.ttrs[ttrID] = ttr
+	return true, nil
+}
+
+// ComputeStandings ranks the league's players the way the SQL in the GORM
+// repository does: points per round follow the finishing position, and tied
+// players share a position.
+func (r *leagueRepository) ComputeStandings(ctx context.Context, league *models.League) ([]*models.LeagueStanding, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rounds := make(map[uuid.UUID][]models.TTRPlayer)
+	for _, player := range r.store.players {
+		ttr, ok := r.store.ttrs[player.TTRID]
+		if !ok || ttr.DeletedAt.Valid || ttr.Status != models.TTRStatusCompleted || player.Score == nil {
+			continue
+		}
+		if ttr.LeagueID == nil || *ttr.LeagueID != league.ID {
+			continue
+		}
+		rounds[player.TTRID] = append(rounds[player.TTRID], player)
+	}
+
+	totals := make(map[uuid.UUID]*models.LeagueStanding)
+	for _, players := range rounds {
+		for _, player := range players {
+			beaten := 0
+			for _, other := range players {
+				if *other.Score < *player.Score {
+					beaten++
+				}
+			}
+
+			standing := totals[player.UserID]
+			if standing == nil {
+				standing = &models.LeagueStanding{UserID: player.UserID}
+				totals[player.UserID] = standing
+			}
+			standing.Rounds++
+			standing.TotalStrokes += *player.Score
+			standing.Points += len(players) - beaten
+		}
+	}
+
+	better := func(a, b *models.LeagueStanding) bool {
+		if league.ScoringScheme == models.LeagueScoringPointsPerFinish {
+			return a.Points > b.Points
+		}
+		return a.TotalStrokes < b.TotalStrokes
+	}
+
+	standings := make([]*models.LeagueStanding, 0, len(totals))
+	for _, standing := range totals {
+		standings = append(standings, standing)
+	}
+	for _, standing := range standings {
+		standing.Position = 1
+		for _, other := range standings {
+			if better(other, standing) {
+				standing.Position++
+			}
+		}
+	}
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Position != standings[j].Position {
+			return standings[i].Position < standings[j].Position
+		}
+		return standings[i].UserID.String() < standings[j].UserID.String()
+	})
+	return standings, nil
+}
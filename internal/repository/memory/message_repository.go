@@ -0,0 +1,280 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type messageRepository struct {
+	store *Store
+}
+
+func NewMessageRepository(store *Store) repository.MessageRepository {
+	return &messageRepository{store: store}
+}
+
+func messageRow(message *models.Message) models.Message {
+	row := *message
+	row.User = nil
+	row.Reactions = nil
+	row.AttachmentURL = ""
+	return row
+}
+
+func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	if _, exists := r.store.messages[message.ID]; exists {
+		return duplicateKey("create message")
+	}
+	now := time.Now()
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+	message.UpdatedAt = now
+
+	r.store.messages[message.ID] = messageRow(message)
+	return nil
+}
+
+func (r *messageRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	message, ok := r.store.messages[id]
+	if !ok {
+		return nil, nil
+	}
+	message.User = r.store.user(message.UserID)
+	return &message, nil
+}
+
+// FindByTTRID returns a page of the TTR's messages, newest first.
+func (r *messageRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID, limit int, offset int) ([]*models.Message, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	messages := make([]*models.Message, 0)
+	for _, message := range r.store.messages {
+		if message.TTRID == ttrID {
+			message := message
+			message.User = r.store.user(message.UserID)
+			messages = append(messages, &message)
+		}
+	}
+	sortByTime(messages, func(m *models.Message) time.Time { return m.CreatedAt }, func(m *models.Message) uuid.UUID { return m.ID }, true)
+	return page(messages, limit, offset), nil
+}
+
+func (r *messageRepository) Update(ctx context.Context, message *models.Message) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	message.UpdatedAt = time.Now()
+	r.store.messages[message.ID] = messageRow(message)
+	return nil
+}
+
+// MarkRead moves the user's read marker for the TTR forward to readAt. An
+// older readAt leaves the marker where it is.
+func (r *messageRepository) MarkRead(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, readAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.messageReads {
+		read := &r.store.messageReads[i]
+		if read.TTRID == ttrID && read.UserID == userID {
+			if readAt.After(read.LastReadAt) {
+				read.LastReadAt = readAt
+			}
+			return nil
+		}
+	}
+	r.store.messageReads = append(r.store.messageReads, models.MessageRead{TTRID: ttrID, UserID: userID, LastReadAt: readAt})
+	return nil
+}
+
+// CountUnreadByUserID returns the number of unread messages per TTR for every
+// TTR the user plays in. Their own and deleted messages never count as
+// unread, and TTRs with nothing unread are left out of the map.
+func (r *messageRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	lastRead := make(map[uuid.UUID]time.Time)
+	for _, read := range r.store.messageReads {
+		if read.UserID == userID {
+			lastRead[read.TTRID] = read.LastReadAt
+		}
+	}
+
+	counts := make(map[uuid.UUID]int64)
+	for _, message := range r.store.messages {
+		if message.UserID == userID || message.Deleted || !r.store.isPlayer(message.TTRID, userID) {
+			continue
+		}
+		if ttr, ok := r.store.ttrs[message.TTRID]; !ok || ttr.DeletedAt.Valid {
+			continue
+		}
+		if readAt, ok := lastRead[message.TTRID]; ok && !message.CreatedAt.After(readAt) {
+			continue
+		}
+		counts[message.TTRID]++
+	}
+	return counts, nil
+}
+
+// AddReaction records the reaction. Adding a reaction the user already has is
+// a no-op.
+func (r *messageRepository) AddReaction(ctx context.Context, reaction *models.MessageReaction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.reactions {
+		if existing.MessageID == reaction.MessageID && existing.UserID == reaction.UserID && existing.Emoji == reaction.Emoji {
+			return nil
+		}
+	}
+	if reaction.CreatedAt.IsZero() {
+		reaction.CreatedAt = time.Now()
+	}
+	r.store.reactions = append(r.store.reactions, *reaction)
+	return nil
+}
+
+func (r *messageRepository) RemoveReaction(ctx context.Context, messageID uuid.UUID, userID uuid.UUID, emoji string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.reactions[:0]
+	for _, reaction := range r.store.reactions {
+		if reaction.MessageID != messageID || reaction.UserID != userID || reaction.Emoji != emoji {
+			kept = append(kept, reaction)
+		}
+	}
+	r.store.reactions = kept
+	return nil
+}
+
+// SummarizeReactions returns per-emoji reaction counts for each message,
+// ordered by first use, flagging the emoji userID reacted with. Messages
+// without reactions are left out of the map.
+func (r *messageRepository) SummarizeReactions(ctx context.Context, messageIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]models.ReactionSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+
+	type group struct {
+		messageID uuid.UUID
+		firstAt   time.Time
+		summary   models.ReactionSummary
+	}
+	groups := make(map[uuid.UUID]map[string]*group)
+	for _, reaction := range r.store.reactions {
+		if !wanted[reaction.MessageID] {
+			continue
+		}
+		if groups[reaction.MessageID] == nil {
+			groups[reaction.MessageID] = make(map[string]*group)
+		}
+		g := groups[reaction.MessageID][reaction.Emoji]
+		if g == nil {
+			g = &group{messageID: reaction.MessageID, firstAt: reaction.CreatedAt, summary: models.ReactionSummary{Emoji: reaction.Emoji}}
+			groups[reaction.MessageID][reaction.Emoji] = g
+		}
+		if reaction.CreatedAt.Before(g.firstAt) {
+			g.firstAt = reaction.CreatedAt
+		}
+		g.summary.Count++
+		if reaction.UserID == userID {
+			g.summary.ReactedByMe = true
+		}
+	}
+
+	summaries := make(map[uuid.UUID][]models.ReactionSummary)
+	for messageID, byEmoji := range groups {
+		ordered := make([]*group, 0, len(byEmoji))
+		for _, g := range byEmoji {
+			ordered = append(ordered, g)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			if !ordered[i].firstAt.Equal(ordered[j].firstAt) {
+				return ordered[i].firstAt.Before(ordered[j].firstAt)
+			}
+			return ordered[i].summary.Emoji < ordered[j].summary.Emoji
+		})
+		for _, g := range ordered {
+			summaries[messageID] = append(summaries[messageID], g.summary)
+		}
+	}
+	return summaries, nil
+}
+
+// SumAttachmentSizeByTTRID returns the total attachment bytes on the TTR's
+// messages that haven't been deleted.
+func (r *messageRepository) SumAttachmentSizeByTTRID(ctx context.Context, ttrID uuid.UUID) (int64, error) {
+	return r.sumAttachmentSize(func(message models.Message) bool { return message.TTRID == ttrID }), nil
+}
+
+// SumAttachmentSizeByUserID returns the total attachment bytes on the user's
+// messages that haven't been deleted, across all TTRs.
+func (r *messageRepository) SumAttachmentSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.sumAttachmentSize(func(message models.Message) bool { return message.UserID == userID }), nil
+}
+
+func (r *messageRepository) sumAttachmentSize(keep func(models.Message) bool) int64 {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total int64
+	for _, message := range r.store.messages {
+		if !message.Deleted && keep(message) {
+			total += message.AttachmentSize
+		}
+	}
+	return total
+}
+
+// FindDeletedWithAttachments returns deleted messages whose attachment object
+// hasn't been removed from storage yet.
+func (r *messageRepository) FindDeletedWithAttachments(ctx context.Context, limit int) ([]*models.Message, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	messages := make([]*models.Message, 0)
+	for _, message := range r.store.messages {
+		if message.Deleted && message.AttachmentKey != nil {
+			message := message
+			messages = append(messages, &message)
+		}
+	}
+	sortByTime(messages, func(m *models.Message) time.Time { return m.UpdatedAt }, func(m *models.Message) uuid.UUID { return m.ID }, false)
+	return page(messages, limit, 0), nil
+}
+
+func (r *messageRepository) ClearAttachment(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if message, ok := r.store.messages[id]; ok {
+		message.AttachmentKey = nil
+		message.AttachmentName = nil
+		message.AttachmentContentType = nil
+		message.AttachmentSize = 0
+		r.store.messages[id] = message
+	}
+	return nil
+}
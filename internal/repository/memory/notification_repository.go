@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type notificationRepository struct {
+	store *Store
+}
+
+func NewNotificationRepository(store *Store) repository.NotificationRepository {
+	return &notificationRepository{store: store}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if notification.ID == uuid.Nil {
+		notification.ID = uuid.New()
+	}
+	if _, exists := r.store.notifications[notification.ID]; exists {
+		return duplicateKey("create notification")
+	}
+	if notification.CreatedAt.IsZero() {
+		notification.CreatedAt = time.Now()
+	}
+	if notification.EventCount == 0 {
+		notification.EventCount = 1
+	}
+
+	row := *notification
+	row.User = nil
+	r.store.notifications[notification.ID] = row
+	return nil
+}
+
+func (r *notificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notification, ok := r.store.notifications[id]
+	if !ok {
+		return nil, nil
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Notification, error) {
+	return page(r.find(func(notification models.Notification) bool {
+		return notification.UserID == userID
+	}), limit, offset), nil
+}
+
+func (r *notificationRepository) FindUnreadByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Notification, error) {
+	return r.find(func(notification models.Notification) bool {
+		return notification.UserID == userID && !notification.IsRead
+	}), nil
+}
+
+func (r *notificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, notification := range r.store.notifications {
+		if notification.UserID == userID && !notification.IsRead {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *notificationRepository) FindByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]*models.Notification, error) {
+	return r.find(func(notification models.Notification) bool {
+		return notification.TargetType != nil && *notification.TargetType == targetType &&
+			notification.TargetID != nil && *notification.TargetID == targetID
+	}), nil
+}
+
+func (r *notificationRepository) FindRecentByTypeAndTarget(ctx context.Context, userID uuid.UUID, notificationType string, targetType string, targetID uuid.UUID, since time.Time) (*models.Notification, error) {
+	notifications := r.find(func(notification models.Notification) bool {
+		return notification.UserID == userID && notification.Type == notificationType &&
+			notification.TargetType != nil && *notification.TargetType == targetType &&
+			notification.TargetID != nil && *notification.TargetID == targetID &&
+			!notification.CreatedAt.Before(since)
+	})
+	if len(notifications) == 0 {
+		return nil, nil
+	}
+	return notifications[0], nil
+}
+
+func (r *notificationRepository) UpdateDigest(ctx context.Context, id uuid.UUID, title string, message string, eventCount int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if notification, ok := r.store.notifications[id]; ok {
+		notification.Title = title
+		notification.Message = message
+		notification.EventCount = eventCount
+		notification.IsRead = false
+		notification.ReadAt = nil
+		r.store.notifications[id] = notification
+	}
+	return nil
+}
+
+// find returns the notifications matching keep, newest first.
+func (r *notificationRepository) find(keep func(models.Notification) bool) []*models.Notification {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notifications := make([]*models.Notification, 0)
+	for _, notification := range r.store.notifications {
+		if keep(notification) {
+			notification := notification
+			notifications = append(notifications, &notification)
+		}
+	}
+	sortByTime(notifications, func(n *models.Notification) time.Time { return n.CreatedAt }, func(n *models.Notification) uuid.UUID { return n.ID }, true)
+	return notifications
+}
+
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if notification, ok := r.store.notifications[id]; ok {
+		r.store.notifications[id] = markRead(notification, time.Now())
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for id, notification := range r.store.notifications {
+		if notification.UserID == userID && !notification.IsRead {
+			r.store.notifications[id] = markRead(notification, now)
+		}
+	}
+	return nil
+}
+
+func markRead(notification models.Notification, now time.Time) models.Notification {
+	notification.IsRead = true
+	notification.ReadAt = &now
+	return notification
+}
+
+func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.notifications, id)
+	return nil
+}
@@ -0,0 +1,255 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type organizationRepository struct {
+	store *Store
+}
+
+func NewOrganizationRepository(store *Store) repository.OrganizationRepository {
+	return &organizationRepository{store: store}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if org.ID == uuid.Nil {
+		org.ID = uuid.New()
+	}
+	if _, exists := r.store.organizations[org.ID]; exists {
+		return duplicateKey("create organization")
+	}
+	now := time.Now()
+	if org.CreatedAt.IsZero() {
+		org.CreatedAt = now
+	}
+	org.UpdatedAt = now
+
+	r.store.organizations[org.ID] = *org
+	return nil
+}
+
+func (r *organizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.organization(id), nil
+}
+
+// organization returns a copy of the organization unless it doesn't exist or
+// was deleted. The caller must hold s.mu.
+func (s *Store) organization(id uuid.UUID) *models.Organization {
+	org, ok := s.organizations[id]
+	if !ok || org.DeletedAt.Valid {
+		return nil
+	}
+	return &org
+}
+
+// FindByUserID returns the organizations the user is a member of, by name.
+func (r *organizationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	orgs := make([]*models.Organization, 0)
+	for _, member := range r.store.organizationMembers {
+		if member.UserID != userID {
+			continue
+		}
+		if org := r.store.organization(member.OrganizationID); org != nil {
+			orgs = append(orgs, org)
+		}
+	}
+	sort.SliceStable(orgs, func(i, j int) bool {
+		return orgs[i].Name < orgs[j].Name
+	})
+	return orgs, nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	org.UpdatedAt = time.Now()
+	r.store.organizations[org.ID] = *org
+	return nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if org, ok := r.store.organizations[id]; ok && !org.DeletedAt.Valid {
+		org.DeletedAt.Time = time.Now()
+		org.DeletedAt.Valid = true
+		r.store.organizations[id] = org
+	}
+	return nil
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.isOrganizationMember(member.OrganizationID, member.UserID) {
+		return duplicateKey("add organization member")
+	}
+	if member.Role == "" {
+		member.Role = models.OrganizationRoleMember
+	}
+	if member.JoinedAt.IsZero() {
+		member.JoinedAt = time.Now()
+	}
+
+	row := *member
+	row.User = nil
+	r.store.organizationMembers = append(r.store.organizationMembers, row)
+	return nil
+}
+
+func (r *organizationRepository) FindMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, member := range r.store.organizationMembers {
+		if member.OrganizationID == orgID && member.UserID == userID {
+			return &member, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *organizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.organizationMembers {
+		existing := &r.store.organizationMembers[i]
+		if existing.OrganizationID == member.OrganizationID && existing.UserID == member.UserID {
+			existing.Role = member.Role
+		}
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.organizationMembers[:0]
+	for _, member := range r.store.organizationMembers {
+		if member.OrganizationID != orgID || member.UserID != userID {
+			kept = append(kept, member)
+		}
+	}
+	r.store.organizationMembers = kept
+	return nil
+}
+
+func (r *organizationRepository) GetMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrganizationMember, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	members := make([]*models.OrganizationMember, 0)
+	for _, member := range r.store.organizationMembers {
+		if member.OrganizationID == orgID {
+			member := member
+			member.User = r.store.user(member.UserID)
+			members = append(members, &member)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].JoinedAt.Before(members[j].JoinedAt)
+	})
+	return members, nil
+}
+
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if invitation.ID == uuid.Nil {
+		invitation.ID = uuid.New()
+	}
+	if _, exists := r.store.organizationInvitations[invitation.ID]; exists {
+		return duplicateKey("create organization invitation")
+	}
+	if invitation.Role == "" {
+		invitation.Role = models.OrganizationRoleMember
+	}
+	if invitation.Status == "" {
+		invitation.Status = models.OrganizationInvitationStatusPending
+	}
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	row := *invitation
+	row.Organization = nil
+	r.store.organizationInvitations[invitation.ID] = row
+	return nil
+}
+
+func (r *organizationRepository) FindInvitationByID(ctx context.Context, id uuid.UUID) (*models.OrganizationInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invitation, ok := r.store.organizationInvitations[id]
+	if !ok {
+		return nil, nil
+	}
+	invitation.Organization = r.store.organization(invitation.OrganizationID)
+	return &invitation, nil
+}
+
+func (r *organizationRepository) FindPendingInvitation(ctx context.Context, orgID uuid.UUID, email string) (*models.OrganizationInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, invitation := range r.store.organizationInvitations {
+		if invitation.OrganizationID == orgID && invitation.Email == email && invitation.Status == models.OrganizationInvitationStatusPending {
+			return &invitation, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindPendingInvitationsByEmail returns pending invitations addressed to the
+// email, skipping organizations that have since been deleted.
+func (r *organizationRepository) FindPendingInvitationsByEmail(ctx context.Context, email string) ([]*models.OrganizationInvitation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	invitations := make([]*models.OrganizationInvitation, 0)
+	for _, invitation := range r.store.organizationInvitations {
+		if invitation.Email != email || invitation.Status != models.OrganizationInvitationStatusPending {
+			continue
+		}
+		invitation := invitation
+		invitation.Organization = r.store.organization(invitation.OrganizationID)
+		if invitation.Organization != nil {
+			invitations = append(invitations, &invitation)
+		}
+	}
+	sortByTime(invitations, func(i *models.OrganizationInvitation) time.Time { return i.CreatedAt }, func(i *models.OrganizationInvitation) uuid.UUID { return i.ID }, true)
+	return invitations, nil
+}
+
+func (r *organizationRepository) UpdateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row := *invitation
+	row.Organization = nil
+	r.store.organizationInvitations[invitation.ID] = row
+	return nil
+}
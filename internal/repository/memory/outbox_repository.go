@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type outboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) repository.OutboxRepository {
+	return &outboxRepository{store: store}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, message *models.OutboxMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	if _, exists := r.store.outboxMessages[message.ID]; exists {
+		return duplicateKey("create outbox message")
+	}
+	now := time.Now()
+	if message.Status == "" {
+		message.Status = models.OutboxStatusPending
+	}
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = now
+	}
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = now
+	}
+
+	r.store.outboxMessages[message.ID] = *message
+	return nil
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxMessage, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*models.OutboxMessage
+	for _, message := range r.store.outboxMessages {
+		if message.Status == models.OutboxStatusPending && !message.NextAttemptAt.After(now) {
+			message := message
+			due = append(due, &message)
+		}
+	}
+	sortByTime(due, func(m *models.OutboxMessage) time.Time { return m.NextAttemptAt }, func(m *models.OutboxMessage) uuid.UUID { return m.ID }, false)
+	due = page(due, limit, 0)
+
+	for _, message := range due {
+		message.NextAttemptAt = now.Add(lease)
+		r.store.outboxMessages[message.ID] = *message
+	}
+	return due, nil
+}
+
+func (r *outboxRepository) MarkDone(ctx context.Context, id uuid.UUID) error {
+	return r.update(id, func(message *models.OutboxMessage) {
+		now := time.Now()
+		message.Status = models.OutboxStatusDone
+		message.ProcessedAt = &now
+	})
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	return r.update(id, func(message *models.OutboxMessage) {
+		message.Attempts = attempts
+		message.LastError = &lastError
+		message.NextAttemptAt = nextAttemptAt
+		if dead {
+			now := time.Now()
+			message.Status = models.OutboxStatusDead
+			message.ProcessedAt = &now
+		}
+	})
+}
+
+func (r *outboxRepository) update(id uuid.UUID, change func(message *models.OutboxMessage)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	message, ok := r.store.outboxMessages[id]
+	if !ok {
+		return nil
+	}
+	change(&message)
+	r.store.outboxMessages[id] = message
+	return nil
+}
+
+func (r *outboxRepository) FindByStatus(ctx context.Context, status string, limit int) ([]*models.OutboxMessage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	messages := make([]*models.OutboxMessage, 0)
+	for _, message := range r.store.outboxMessages {
+		if message.Status == status {
+			message := message
+			messages = append(messages, &message)
+		}
+	}
+	sortByTime(messages, func(m *models.OutboxMessage) time.Time { return m.CreatedAt }, func(m *models.OutboxMessage) uuid.UUID { return m.ID }, false)
+	return page(messages, limit, 0), nil
+}
+
+func (r *outboxRepository) DeleteDoneBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var done []*models.OutboxMessage
+	for _, message := range r.store.outboxMessages {
+		if message.Status == models.OutboxStatusDone && message.ProcessedAt != nil && message.ProcessedAt.Before(cutoff) {
+			message := message
+			done = append(done, &message)
+		}
+	}
+	sortByTime(done, func(m *models.OutboxMessage) time.Time { return *m.ProcessedAt }, func(m *models.OutboxMessage) uuid.UUID { return m.ID }, false)
+	done = page(done, limit, 0)
+
+	for _, message := range done {
+		delete(r.store.outboxMessages, message.ID)
+	}
+	return int64(len(done)), nil
+}
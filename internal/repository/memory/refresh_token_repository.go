@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type refreshTokenRepository struct {
+	store *Store
+}
+
+func NewRefreshTokenRepository(store *Store) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{store: store}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if _, exists := r.store.refreshTokens[token.ID]; exists {
+		return duplicateKey("create refresh token")
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	row := *token
+	row.User = nil
+	r.store.refreshTokens[token.ID] = row
+	return nil
+}
+
+// FindByTokenHash returns the token with its user. Like the GORM preload, the
+// user is left nil once it has been soft-deleted.
+func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.refreshTokens {
+		if token.TokenHash == tokenHash {
+			if user := r.store.user(token.UserID); user != nil && !user.DeletedAt.Valid {
+				token.User = user
+			}
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, token := range r.store.refreshTokens {
+		if token.UserID == userID && !token.Revoked {
+			token.Revoked = true
+			r.store.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.store.refreshTokens {
+		if token.ExpiresAt.Before(now) {
+			delete(r.store.refreshTokens, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type reportRepository struct {
+	store *Store
+}
+
+func NewReportRepository(store *Store) repository.ReportRepository {
+	return &reportRepository{store: store}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	if _, exists := r.store.reports[report.ID]; exists {
+		return duplicateKey("create report")
+	}
+	if report.Status == "" {
+		report.Status = models.ReportStatusOpen
+	}
+	if report.IsOpen() {
+		for _, existing := range r.store.reports {
+			if existing.IsOpen() && existing.ReporterUserID == report.ReporterUserID && existing.TargetType == report.TargetType && existing.TargetID == report.TargetID {
+				return duplicateKey("create report")
+			}
+		}
+	}
+	now := time.Now()
+	if report.CreatedAt.IsZero() {
+		report.CreatedAt = now
+	}
+	if report.UpdatedAt.IsZero() {
+		report.UpdatedAt = now
+	}
+
+	row := *report
+	row.Reporter = nil
+	r.store.reports[report.ID] = row
+	return nil
+}
+
+func (r *reportRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	report, ok := r.store.reports[id]
+	if !ok {
+		return nil, nil
+	}
+	return r.store.loadReport(report), nil
+}
+
+func (r *reportRepository) FindByStatus(ctx context.Context, status string, limit int, offset int) ([]*models.Report, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var reports []*models.Report
+	for _, report := range r.store.reports {
+		if status == "" || report.Status == status {
+			reports = append(reports, r.store.loadReport(report))
+		}
+	}
+	sortByTime(reports, func(r *models.Report) time.Time { return r.CreatedAt }, func(r *models.Report) uuid.UUID { return r.ID }, false)
+	return page(reports, limit, offset), nil
+}
+
+func (r *reportRepository) CountByReporterSince(ctx context.Context, reporterID uuid.UUID, since time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, report := range r.store.reports {
+		if report.ReporterUserID == reporterID && !report.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *reportRepository) Close(ctx context.Context, report *models.Report) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.reports[report.ID]
+	if !ok || !row.IsOpen() {
+		return false, nil
+	}
+	row.Status = report.Status
+	row.Resolution = report.Resolution
+	row.ResolvedByUserID = report.ResolvedByUserID
+	row.ResolvedAt = report.ResolvedAt
+	row.UpdatedAt = time.Now()
+	r.store.reports[report.ID] = row
+	return true, nil
+}
+
+// loadReport returns a copy of report with its reporter preloaded. The caller
+// must hold s.mu.
+func (s *Store) loadReport(report models.Report) *models.Report {
+	report.Reporter = s.user(report.ReporterUserID)
+	return &report
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type scheduleRepository struct {
+	store *Store
+}
+
+func NewScheduleRepository(store *Store) repository.ScheduleRepository {
+	return &scheduleRepository{store: store}
+}
+
+func (r *scheduleRepository) FindSchedule(ctx context.Context, viewerID uuid.UUID, courseName string, from time.Time, to time.Time) ([]repository.ScheduleEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entries := make([]repository.ScheduleEntry, 0)
+	for _, ttr := range r.store.ttrs {
+		if ttr.DeletedAt.Valid || ttr.Status == models.TTRStatusCancelled || !strings.EqualFold(ttr.CourseName, courseName) {
+			continue
+		}
+		if ttr.TeeDate.Before(from) || !ttr.TeeDate.Before(to) || !r.store.canFind(ttr, viewerID) {
+			continue
+		}
+
+		entry := repository.ScheduleEntry{
+			TTRID:         ttr.ID,
+			TeeDate:       ttr.TeeDate,
+			TeeTime:       ttr.TeeTime,
+			Status:        ttr.Status,
+			MaxPlayers:    ttr.MaxPlayers,
+			CaptainUserID: ttr.CaptainUserID,
+		}
+		if captain := r.store.user(ttr.CaptainUserID); captain != nil {
+			entry.CaptainFirstName = captain.FirstName
+			entry.CaptainLastName = captain.LastName
+			entry.CaptainDeleted = captain.IsDeleted()
+		} else {
+			entry.CaptainDeleted = true
+		}
+		for _, player := range r.store.players {
+			if player.TTRID != ttr.ID {
+				continue
+			}
+			if player.Status == models.TTRPlayerStatusConfirmed {
+				entry.Confirmed++
+			}
+			if player.Status.IsActive() {
+				entry.Taken++
+			}
+		}
+		for _, guest := range r.store.guests {
+			if guest.TTRID == ttr.ID {
+				entry.Confirmed++
+				entry.Taken++
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.TeeDate.Equal(b.TeeDate) {
+			return a.TeeDate.Before(b.TeeDate)
+		}
+		if !a.TeeTime.Equal(b.TeeTime) {
+			return a.TeeTime.Before(b.TeeTime)
+		}
+		return a.TTRID.String() < b.TTRID.String()
+	})
+	return entries, nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type securityEventRepository struct {
+	store *Store
+}
+
+func NewSecurityEventRepository(store *Store) repository.SecurityEventRepository {
+	return &securityEventRepository{store: store}
+}
+
+func (r *securityEventRepository) Create(ctx context.Context, event *models.SecurityEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if _, exists := r.store.securityEvents[event.ID]; exists {
+		return duplicateKey("create security event")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.store.securityEvents[event.ID] = *event
+	return nil
+}
+
+func (r *securityEventRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.SecurityEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*models.SecurityEvent
+	for _, event := range r.store.securityEvents {
+		if event.UserID == userID {
+			event := event
+			events = append(events, &event)
+		}
+	}
+	sortByTime(events, func(e *models.SecurityEvent) time.Time { return e.CreatedAt }, func(e *models.SecurityEvent) uuid.UUID { return e.ID }, true)
+	return page(events, limit, offset), nil
+}
+
+func (r *securityEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []*models.SecurityEvent
+	for _, event := range r.store.securityEvents {
+		if event.CreatedAt.Before(cutoff) {
+			event := event
+			expired = append(expired, &event)
+		}
+	}
+	sortByTime(expired, func(e *models.SecurityEvent) time.Time { return e.CreatedAt }, func(e *models.SecurityEvent) uuid.UUID { return e.ID }, false)
+	expired = page(expired, limit, 0)
+
+	for _, event := range expired {
+		delete(r.store.securityEvents, event.ID)
+	}
+	return int64(len(expired)), nil
+}
+
+func (r *securityEventRepository) SeeDevice(ctx context.Context, userID uuid.UUID, fingerprint string, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, device := range r.store.knownDevices {
+		if device.UserID == userID && device.Fingerprint == fingerprint {
+			r.store.knownDevices[i].LastSeenAt = at
+			return false, nil
+		}
+	}
+	r.store.knownDevices = append(r.store.knownDevices, models.KnownDevice{UserID: userID, Fingerprint: fingerprint, FirstSeenAt: at, LastSeenAt: at})
+	return true, nil
+}
+
+func (r *securityEventRepository) HasDevices(ctx context.Context, userID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, device := range r.store.knownDevices {
+		if device.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
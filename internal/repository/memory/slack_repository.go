@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type slackRepository struct {
+	store *Store
+}
+
+func NewSlackRepository(store *Store) repository.SlackRepository {
+	return &slackRepository{store: store}
+}
+
+func (r *slackRepository) FindAccount(ctx context.Context, teamID string, slackUserID string) (*models.SlackAccount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, account := range r.store.slackAccounts {
+		if account.TeamID == teamID && account.SlackUserID == slackUserID {
+			return &account, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *slackRepository) LinkAccount(ctx context.Context, account *models.SlackAccount) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, existing := range r.store.slackAccounts {
+		if existing.TeamID == account.TeamID && existing.SlackUserID == account.SlackUserID {
+			delete(r.store.slackAccounts, id)
+		}
+	}
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+	if account.CreatedAt.IsZero() {
+		account.CreatedAt = time.Now()
+	}
+	r.store.slackAccounts[account.ID] = *account
+	return nil
+}
+
+func (r *slackRepository) CreateLinkCode(ctx context.Context, code *models.SlackLinkCode) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.slackLinkCodes[code.Code]; ok {
+		return duplicateKey("create slack link code")
+	}
+	if code.CreatedAt.IsZero() {
+		code.CreatedAt = time.Now()
+	}
+	r.store.slackLinkCodes[code.Code] = *code
+	return nil
+}
+
+func (r *slackRepository) ConsumeLinkCode(ctx context.Context, code string, now time.Time) (*models.SlackLinkCode, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	linkCode, ok := r.store.slackLinkCodes[code]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.store.slackLinkCodes, code)
+	if !now.Before(linkCode.ExpiresAt) {
+		return nil, nil
+	}
+	return &linkCode, nil
+}
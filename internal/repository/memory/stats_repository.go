@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type statsRepository struct {
+	store *Store
+}
+
+func NewStatsRepository(store *Store) repository.StatsRepository {
+	return &statsRepository{store: store}
+}
+
+func (r *statsRepository) countUsers(match func(models.User) bool) int64 {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, row := range r.store.users {
+		if !row.DeletedAt.Valid && match(row) {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *statsRepository) CountUsers(ctx context.Context) (int64, error) {
+	return r.countUsers(func(models.User) bool { return true }), nil
+}
+
+func (r *statsRepository) CountUsersCreatedSince(ctx context.Context, since time.Time) (int64, error) {
+	return r.countUsers(func(user models.User) bool { return !user.CreatedAt.Before(since) }), nil
+}
+
+func (r *statsRepository) CountUsersLoggedInSince(ctx context.Context, since time.Time) (int64, error) {
+	return r.countUsers(func(user models.User) bool {
+		return user.LastLoginAt != nil && !user.LastLoginAt.Before(since)
+	}), nil
+}
+
+func (r *statsRepository) CountTTRsByStatus(ctx context.Context) (map[models.TTRStatus]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[models.TTRStatus]int64)
+	for _, row := range r.store.ttrs {
+		if !row.DeletedAt.Valid {
+			counts[row.Status]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *statsRepository) CountInvitationsByStatus(ctx context.Context) (map[models.InvitationStatus]int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[models.InvitationStatus]int64)
+	for _, row := range r.store.invitations {
+		counts[row.Status]++
+	}
+	return counts, nil
+}
+
+func (r *statsRepository) AverageFillRate(ctx context.Context, status models.TTRStatus) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var total float64
+	var ttrs int
+	for _, row := range r.store.ttrs {
+		if row.DeletedAt.Valid || row.Status != status || row.MaxPlayers <= 0 {
+			continue
+		}
+		players := 0
+		for _, player := range r.store.players {
+			if player.TTRID == row.ID && player.Status == models.TTRPlayerStatusConfirmed {
+				players++
+			}
+		}
+		total += float64(players) / float64(row.MaxPlayers)
+		ttrs++
+	}
+	if ttrs == 0 {
+		return 0, nil
+	}
+	return total / float64(ttrs), nil
+}
@@ -0,0 +1,259 @@
+// Package memory implements the repository interfaces on top of plain maps, so
+// tests can run services without a database. The implementations follow the
+// GORM repositories closely enough for service tests: soft-deleted rows are
+// hidden, associations the GORM repositories preload are filled in, and
+// callers get copies they can change without touching the stored rows.
+// Anything beyond that, such as foreign key checks, is left out.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+// Store holds the rows of every in-memory repository. Repositories created on
+// the same Store see each other's writes, the way repositories sharing a
+// database do, which the TTR and organization repositories rely on.
+type Store struct {
+	mu sync.RWMutex
+
+	users                   map[uuid.UUID]models.User
+	ttrs                    map[uuid.UUID]models.TTR
+	coCaptains              []models.TTRCoCaptain
+	players                 []models.TTRPlayer
+	guests                  []models.TTRGuest
+	slots                   []models.TeeSlot
+	invitations             map[uuid.UUID]models.Invitation
+	invitationTemplates     map[uuid.UUID]models.InvitationTemplate
+	organizations           map[uuid.UUID]models.Organization
+	organizationMembers     []models.OrganizationMember
+	organizationInvitations map[uuid.UUID]models.OrganizationInvitation
+	messages                map[uuid.UUID]models.Message
+	messageReads            []models.MessageRead
+	reactions               []models.MessageReaction
+	notifications           map[uuid.UUID]models.Notification
+	refreshTokens           map[uuid.UUID]models.RefreshToken
+	leagues                 map[uuid.UUID]models.League
+	tournaments             map[uuid.UUID]models.Tournament
+	matches                 map[uuid.UUID]models.Match
+	inviteLinks             map[uuid.UUID]models.InviteLink
+	webhooks                map[uuid.UUID]models.Webhook
+	webhookDeliveries       map[uuid.UUID]models.WebhookDelivery
+	slackAccounts           map[uuid.UUID]models.SlackAccount
+	slackLinkCodes          map[string]models.SlackLinkCode
+	apiTokens               map[uuid.UUID]models.APIToken
+	auditLogEntries         map[uuid.UUID]models.AuditLogEntry
+	impersonationSessions   map[uuid.UUID]models.ImpersonationSession
+	featureFlags            map[string]models.FeatureFlag
+	ttrEvents               map[uuid.UUID]models.TTREvent
+	outboxMessages          map[uuid.UUID]models.OutboxMessage
+	emailSuppressions       map[string]models.EmailSuppression
+	securityEvents          map[uuid.UUID]models.SecurityEvent
+	knownDevices            []models.KnownDevice
+	reports                 map[uuid.UUID]models.Report
+	userBlocks              []models.UserBlock
+	friendships             []models.Friendship
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:                   make(map[uuid.UUID]models.User),
+		ttrs:                    make(map[uuid.UUID]models.TTR),
+		invitations:             make(map[uuid.UUID]models.Invitation),
+		invitationTemplates:     make(map[uuid.UUID]models.InvitationTemplate),
+		organizations:           make(map[uuid.UUID]models.Organization),
+		organizationInvitations: make(map[uuid.UUID]models.OrganizationInvitation),
+		messages:                make(map[uuid.UUID]models.Message),
+		notifications:           make(map[uuid.UUID]models.Notification),
+		refreshTokens:           make(map[uuid.UUID]models.RefreshToken),
+		leagues:                 make(map[uuid.UUID]models.League),
+		tournaments:             make(map[uuid.UUID]models.Tournament),
+		matches:                 make(map[uuid.UUID]models.Match),
+		inviteLinks:             make(map[uuid.UUID]models.InviteLink),
+		webhooks:                make(map[uuid.UUID]models.Webhook),
+		webhookDeliveries:       make(map[uuid.UUID]models.WebhookDelivery),
+		slackAccounts:           make(map[uuid.UUID]models.SlackAccount),
+		slackLinkCodes:          make(map[string]models.SlackLinkCode),
+		apiTokens:               make(map[uuid.UUID]models.APIToken),
+		auditLogEntries:         make(map[uuid.UUID]models.AuditLogEntry),
+		impersonationSessions:   make(map[uuid.UUID]models.ImpersonationSession),
+		featureFlags:            make(map[string]models.FeatureFlag),
+		ttrEvents:               make(map[uuid.UUID]models.TTREvent),
+		outboxMessages:          make(map[uuid.UUID]models.OutboxMessage),
+		emailSuppressions:       make(map[string]models.EmailSuppression),
+		securityEvents:          make(map[uuid.UUID]models.SecurityEvent),
+		reports:                 make(map[uuid.UUID]models.Report),
+	}
+}
+
+// snapshot copies the store's rows. The caller must hold s.mu.
+func (s *Store) snapshot() *Store {
+	return &Store{
+		users:                   cloneMap(s.users),
+		ttrs:                    cloneMap(s.ttrs),
+		coCaptains:              append([]models.TTRCoCaptain(nil), s.coCaptains...),
+		players:                 append([]models.TTRPlayer(nil), s.players...),
+		guests:                  append([]models.TTRGuest(nil), s.guests...),
+		slots:                   append([]models.TeeSlot(nil), s.slots...),
+		invitations:             cloneMap(s.invitations),
+		invitationTemplates:     cloneMap(s.invitationTemplates),
+		organizations:           cloneMap(s.organizations),
+		organizationMembers:     append([]models.OrganizationMember(nil), s.organizationMembers...),
+		organizationInvitations: cloneMap(s.organizationInvitations),
+		messages:                cloneMap(s.messages),
+		messageReads:            append([]models.MessageRead(nil), s.messageReads...),
+		reactions:               append([]models.MessageReaction(nil), s.reactions...),
+		notifications:           cloneMap(s.notifications),
+		refreshTokens:           cloneMap(s.refreshTokens),
+		leagues:                 cloneMap(s.leagues),
+		tournaments:             cloneMap(s.tournaments),
+		matches:                 cloneMap(s.matches),
+		inviteLinks:             cloneMap(s.inviteLinks),
+		webhooks:                cloneMap(s.webhooks),
+		webhookDeliveries:       cloneMap(s.webhookDeliveries),
+		slackAccounts:           cloneMap(s.slackAccounts),
+		slackLinkCodes:          cloneMap(s.slackLinkCodes),
+		apiTokens:               cloneMap(s.apiTokens),
+		auditLogEntries:         cloneMap(s.auditLogEntries),
+		impersonationSessions:   cloneMap(s.impersonationSessions),
+		featureFlags:            cloneMap(s.featureFlags),
+		ttrEvents:               cloneMap(s.ttrEvents),
+		outboxMessages:          cloneMap(s.outboxMessages),
+		emailSuppressions:       cloneMap(s.emailSuppressions),
+		securityEvents:          cloneMap(s.securityEvents),
+		knownDevices:            append([]models.KnownDevice(nil), s.knownDevices...),
+		reports:                 cloneMap(s.reports),
+		userBlocks:              append([]models.UserBlock(nil), s.userBlocks...),
+		friendships:             append([]models.Friendship(nil), s.friendships...),
+	}
+}
+
+// restore puts back the rows of a snapshot. The caller must hold s.mu.
+func (s *Store) restore(snapshot *Store) {
+	s.users = snapshot.users
+	s.ttrs = snapshot.ttrs
+	s.coCaptains = snapshot.coCaptains
+	s.players = snapshot.players
+	s.guests = snapshot.guests
+	s.slots = snapshot.slots
+	s.invitations = snapshot.invitations
+	s.invitationTemplates = snapshot.invitationTemplates
+	s.organizations = snapshot.organizations
+	s.organizationMembers = snapshot.organizationMembers
+	s.organizationInvitations = snapshot.organizationInvitations
+	s.messages = snapshot.messages
+	s.messageReads = snapshot.messageReads
+	s.reactions = snapshot.reactions
+	s.notifications = snapshot.notifications
+	s.refreshTokens = snapshot.refreshTokens
+	s.leagues = snapshot.leagues
+	s.tournaments = snapshot.tournaments
+	s.matches = snapshot.matches
+	s.inviteLinks = snapshot.inviteLinks
+	s.webhooks = snapshot.webhooks
+	s.webhookDeliveries = snapshot.webhookDeliveries
+	s.slackAccounts = snapshot.slackAccounts
+	s.slackLinkCodes = snapshot.slackLinkCodes
+	s.apiTokens = snapshot.apiTokens
+	s.auditLogEntries = snapshot.auditLogEntries
+	s.impersonationSessions = snapshot.impersonationSessions
+	s.featureFlags = snapshot.featureFlags
+	s.ttrEvents = snapshot.ttrEvents
+	s.outboxMessages = snapshot.outboxMessages
+	s.emailSuppressions = snapshot.emailSuppressions
+	s.securityEvents = snapshot.securityEvents
+	s.knownDevices = snapshot.knownDevices
+	s.reports = snapshot.reports
+	s.userBlocks = snapshot.userBlocks
+	s.friendships = snapshot.friendships
+}
+
+// user returns a copy of the user, deleted or not, for preloading. The caller
+// must hold s.mu.
+func (s *Store) user(id uuid.UUID) *models.User {
+	user, ok := s.users[id]
+	if !ok {
+		return nil
+	}
+	return &user
+}
+
+func cloneMap[K comparable, T any](m map[K]T) map[K]T {
+	clone := make(map[K]T, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+// sortByTime orders rows by at, oldest first or newest first when desc is
+// set, breaking ties on ID so results are stable.
+func sortByTime[T any](rows []T, at func(T) time.Time, id func(T) uuid.UUID, desc bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := at(rows[i]), at(rows[j])
+		if !a.Equal(b) {
+			if desc {
+				return a.After(b)
+			}
+			return a.Before(b)
+		}
+		return id(rows[i]).String() < id(rows[j]).String()
+	})
+}
+
+// page applies limit and offset the way SQL does; a negative or zero limit
+// means no limit.
+func page[T any](rows []T, limit int, offset int) []T {
+	if offset > 0 {
+		if offset >= len(rows) {
+			return rows[:0]
+		}
+		rows = rows[offset:]
+	}
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+func duplicateKey(what string) error {
+	return fmt.Errorf("failed to %s: %w", what, repository.ErrDuplicate)
+}
+
+type txKey struct{}
+
+type transactor struct {
+	store *Store
+}
+
+// NewTransactor returns a Transactor that rolls the store back to where it
+// was when fn fails. Writes made concurrently by other goroutines while fn
+// runs are rolled back with it, which tests don't rely on.
+func NewTransactor(store *Store) repository.Transactor {
+	return &transactor{store: store}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t.store.mu.Lock()
+	snapshot := t.store.snapshot()
+	t.store.mu.Unlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		t.store.mu.Lock()
+		t.store.restore(snapshot)
+		t.store.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type suggestionRepository struct {
+	store *Store
+}
+
+func NewSuggestionRepository(store *Store) repository.SuggestionRepository {
+	return &suggestionRepository{store: store}
+}
+
+func (r *suggestionRepository) SharedRounds(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, before time.Time, limit int) ([]repository.SharedRoundCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rounds := make(map[uuid.UUID]int)
+	for _, player := range r.store.players {
+		ttr, ok := r.store.ttrs[player.TTRID]
+		if !ok || ttr.DeletedAt.Valid || ttr.Status == models.TTRStatusCancelled || !ttr.TeeDate.Before(before) {
+			continue
+		}
+		if r.store.isPlayer(ttr.ID, userID) && r.store.isCandidate(ttrID, userID, player.UserID) {
+			rounds[player.UserID]++
+		}
+	}
+
+	users := r.store.usersByName(rounds)
+	sort.SliceStable(users, func(i, j int) bool {
+		return rounds[users[i].ID] > rounds[users[j].ID]
+	})
+	counts := make([]repository.SharedRoundCount, 0, len(users))
+	for _, user := range page(users, limit, 0) {
+		counts = append(counts, repository.SharedRoundCount{UserID: user.ID, Rounds: rounds[user.ID]})
+	}
+	return counts, nil
+}
+
+func (r *suggestionRepository) OrganizationMates(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	mates := make(map[uuid.UUID]int)
+	for _, member := range r.store.organizationMembers {
+		if org, ok := r.store.organizations[member.OrganizationID]; !ok || org.DeletedAt.Valid {
+			continue
+		}
+		if r.store.isOrganizationMember(member.OrganizationID, userID) && r.store.isCandidate(ttrID, userID, member.UserID) {
+			mates[member.UserID]++
+		}
+	}
+	return userIDs(page(r.store.usersByName(mates), limit, 0)), nil
+}
+
+func (r *suggestionRepository) PlayingOn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, day string, limit int) ([]uuid.UUID, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	players := make(map[uuid.UUID]int)
+	for _, user := range r.store.users {
+		if !r.store.isCandidate(ttrID, userID, user.ID) {
+			continue
+		}
+		for _, playingDay := range user.PlayingDays {
+			if playingDay.Day == day {
+				players[user.ID]++
+			}
+		}
+	}
+	return userIDs(page(r.store.usersByName(players), limit, 0)), nil
+}
+
+// isCandidate reports whether candidateID may be suggested to userID for the
+// TTR: an existing user who is not userID and not already on the TTR or
+// invited to it. The caller must hold s.mu.
+func (s *Store) isCandidate(ttrID uuid.UUID, userID uuid.UUID, candidateID uuid.UUID) bool {
+	user, ok := s.users[candidateID]
+	if !ok || user.DeletedAt.Valid || candidateID == userID {
+		return false
+	}
+	if ttr, ok := s.ttrs[ttrID]; ok && ttr.CaptainUserID == candidateID {
+		return false
+	}
+	return !s.isPlayer(ttrID, candidateID) && !s.isCoCaptain(ttrID, candidateID) && !s.isPendingInvitee(ttrID, candidateID)
+}
+
+// usersByName returns the users keyed in ids ordered by name. The caller
+// must hold s.mu.
+func (s *Store) usersByName(ids map[uuid.UUID]int) []models.User {
+	users := make([]models.User, 0, len(ids))
+	for id := range ids {
+		users = append(users, s.users[id])
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].FirstName != users[j].FirstName {
+			return users[i].FirstName < users[j].FirstName
+		}
+		if users[i].LastName != users[j].LastName {
+			return users[i].LastName < users[j].LastName
+		}
+		return users[i].ID.String() < users[j].ID.String()
+	})
+	return users
+}
+
+func userIDs(users []models.User) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type tournamentRepository struct {
+	store *Store
+}
+
+func NewTournamentRepository(store *Store) repository.TournamentRepository {
+	return &tournamentRepository{store: store}
+}
+
+// Create inserts the tournament together with its bracket matches.
+func (r *tournamentRepository) Create(ctx context.Context, tournament *models.Tournament) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if tournament.ID == uuid.Nil {
+		tournament.ID = uuid.New()
+	}
+	if _, exists := r.store.tournaments[tournament.ID]; exists {
+		return duplicateKey("create tournament")
+	}
+	if tournament.Status == "" {
+		tournament.Status = models.TournamentStatusInProgress
+	}
+	now := time.Now()
+	if tournament.CreatedAt.IsZero() {
+		tournament.CreatedAt = now
+	}
+	tournament.UpdatedAt = now
+
+	for _, match := range tournament.Matches {
+		if match.ID == uuid.Nil {
+			match.ID = uuid.New()
+		}
+		if _, exists := r.store.matches[match.ID]; exists {
+			return duplicateKey("create tournament")
+		}
+		match.TournamentID = tournament.ID
+		if match.CreatedAt.IsZero() {
+			match.CreatedAt = now
+		}
+		match.UpdatedAt = now
+	}
+	for _, match := range tournament.Matches {
+		r.store.matches[match.ID] = *match
+	}
+
+	row := *tournament
+	row.Matches = nil
+	r.store.tournaments[tournament.ID] = row
+	return nil
+}
+
+// FindByID returns the tournament with its matches in bracket order.
+func (r *tournamentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Tournament, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tournament, ok := r.store.tournaments[id]
+	if !ok || tournament.DeletedAt.Valid {
+		return nil, nil
+	}
+
+	tournament.Matches = make([]*models.Match, 0)
+	for _, match := range r.store.matches {
+		if match.TournamentID == id {
+			match := match
+			tournament.Matches = append(tournament.Matches, &match)
+		}
+	}
+	sort.Slice(tournament.Matches, func(i, j int) bool {
+		a, b := tournament.Matches[i], tournament.Matches[j]
+		if a.Round != b.Round {
+			return a.Round < b.Round
+		}
+		return a.Position < b.Position
+	})
+	return &tournament, nil
+}
+
+func (r *tournamentRepository) Update(ctx context.Context, tournament *models.Tournament) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tournament.UpdatedAt = time.Now()
+	row := *tournament
+	row.Matches = nil
+	r.store.tournaments[tournament.ID] = row
+	return nil
+}
+
+// CompleteMatch saves the match's result if it was still scheduled. It
+// reports false when someone else completed the match first.
+func (r *tournamentRepository) CompleteMatch(ctx context.Context, match *models.Match) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.matches[match.ID]
+	if !ok || existing.Status != models.MatchStatusScheduled {
+		return false, nil
+	}
+	existing.WinnerUserID = match.WinnerUserID
+	existing.Status = match.Status
+	existing.Walkover = match.Walkover
+	existing.CompletedAt = match.CompletedAt
+	existing.UpdatedAt = time.Now()
+	r.store.matches[match.ID] = existing
+	return true, nil
+}
+
+func (r *tournamentRepository) UpdateMatch(ctx context.Context, match *models.Match) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	match.UpdatedAt = time.Now()
+	r.store.matches[match.ID] = *match
+	return nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type ttrEventRepository struct {
+	store *Store
+}
+
+func NewTTREventRepository(store *Store) repository.TTREventRepository {
+	return &ttrEventRepository{store: store}
+}
+
+func (r *ttrEventRepository) Append(ctx context.Context, event *models.TTREvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if _, exists := r.store.ttrEvents[event.ID]; exists {
+		return duplicateKey("append ttr event")
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if event.Data == "" {
+		event.Data = "{}"
+	}
+
+	event.Sequence = 1
+	for _, existing := range r.store.ttrEvents {
+		if existing.TTRID == event.TTRID && existing.Sequence >= event.Sequence {
+			event.Sequence = existing.Sequence + 1
+		}
+	}
+	r.store.ttrEvents[event.ID] = *event
+	return nil
+}
+
+func (r *ttrEventRepository) FindSince(ctx context.Context, ttrID uuid.UUID, after int64, limit int) ([]*models.TTREvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*models.TTREvent
+	for _, event := range r.store.ttrEvents {
+		if event.TTRID != ttrID || event.Sequence <= after {
+			continue
+		}
+		event := event
+		events = append(events, &event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Sequence < events[j].Sequence
+	})
+	return page(events, limit, 0), nil
+}
+
+func (r *ttrEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	latest := make(map[uuid.UUID]int64)
+	for _, event := range r.store.ttrEvents {
+		if event.Sequence > latest[event.TTRID] {
+			latest[event.TTRID] = event.Sequence
+		}
+	}
+
+	var expired []*models.TTREvent
+	for _, event := range r.store.ttrEvents {
+		if event.CreatedAt.Before(cutoff) && event.Sequence < latest[event.TTRID] {
+			event := event
+			expired = append(expired, &event)
+		}
+	}
+	sortByTime(expired, func(e *models.TTREvent) time.Time { return e.CreatedAt }, func(e *models.TTREvent) uuid.UUID { return e.ID }, false)
+	expired = page(expired, limit, 0)
+
+	for _, event := range expired {
+		delete(r.store.ttrEvents, event.ID)
+	}
+	return int64(len(expired)), nil
+}
@@ -0,0 +1,788 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ttrRepository struct {
+	store *Store
+}
+
+func NewTTRRepository(store *Store) repository.TTRRepository {
+	return &ttrRepository{store: store}
+}
+
+// ttrRow strips the associations, which live in their own tables.
+func ttrRow(ttr *models.TTR) models.TTR {
+	row := *ttr
+	row.CreatedByUser = nil
+	row.CaptainUser = nil
+	row.CoCaptains = nil
+	row.Players = nil
+	row.Guests = nil
+	row.Slots = nil
+	return row
+}
+
+// loadTTR returns a copy of the TTR with the associations the GORM repository
+// preloads. The caller must hold s.mu.
+func (s *Store) loadTTR(row models.TTR) *models.TTR {
+	ttr := row
+	ttr.CreatedByUser = s.user(ttr.CreatedByUserID)
+	ttr.CaptainUser = s.user(ttr.CaptainUserID)
+	ttr.CoCaptains = []models.TTRCoCaptain{}
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.TTRID == ttr.ID {
+			coCaptain.User = s.user(coCaptain.UserID)
+			ttr.CoCaptains = append(ttr.CoCaptains, coCaptain)
+		}
+	}
+	ttr.Players = []models.TTRPlayer{}
+	for _, player := range s.players {
+		if player.TTRID == ttr.ID {
+			player.User = s.user(player.UserID)
+			ttr.Players = append(ttr.Players, player)
+		}
+	}
+	ttr.Guests = []models.TTRGuest{}
+	for _, guest := range s.guests {
+		if guest.TTRID == ttr.ID {
+			ttr.Guests = append(ttr.Guests, guest)
+		}
+	}
+	ttr.Slots = []models.TeeSlot{}
+	for _, slot := range s.slots {
+		if slot.TTRID == ttr.ID {
+			ttr.Slots = append(ttr.Slots, slot)
+		}
+	}
+	sort.Slice(ttr.Slots, func(i, j int) bool { return ttr.Slots[i].Number < ttr.Slots[j].Number })
+	return &ttr
+}
+
+// isPlayer reports whether userID is on the TTR's roster. The caller must
+// hold s.mu.
+func (s *Store) isPlayer(ttrID uuid.UUID, userID uuid.UUID) bool {
+	for _, player := range s.players {
+		if player.TTRID == ttrID && player.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) isCoCaptain(ttrID uuid.UUID, userID uuid.UUID) bool {
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.TTRID == ttrID && coCaptain.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// isOrganizationMember reports whether userID belongs to the organization.
+// The caller must hold s.mu.
+func (s *Store) isOrganizationMember(orgID uuid.UUID, userID uuid.UUID) bool {
+	for _, member := range s.organizationMembers {
+		if member.OrganizationID == orgID && member.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByTeeTime(ttrs []*models.TTR, desc bool) {
+	sort.SliceStable(ttrs, func(i, j int) bool {
+		a, b := ttrs[i].TeeDateTime(), ttrs[j].TeeDateTime()
+		if desc {
+			return a.After(b)
+		}
+		return a.Before(b)
+	})
+}
+
+func (r *ttrRepository) Create(ctx context.Context, ttr *models.TTR) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if ttr.ID == uuid.Nil {
+		ttr.ID = uuid.New()
+	}
+	if _, exists := r.store.ttrs[ttr.ID]; exists {
+		return duplicateKey("create ttr")
+	}
+	if ttr.MaxPlayers == 0 {
+		ttr.MaxPlayers = models.DefaultMaxPlayers
+	}
+	if err := ttr.Validate(); err != nil {
+		return fmt.Errorf("failed to create ttr: %w", err)
+	}
+	if ttr.Status == "" {
+		ttr.Status = models.TTRStatusOpen
+	}
+	if ttr.Visibility == "" {
+		ttr.Visibility = models.TTRVisibilityPrivate
+	}
+	now := time.Now()
+	if ttr.CreatedAt.IsZero() {
+		ttr.CreatedAt = now
+	}
+	ttr.UpdatedAt = now
+
+	r.store.ttrs[ttr.ID] = ttrRow(ttr)
+	return nil
+}
+
+func (r *ttrRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.ttrs[id]
+	if !ok || row.DeletedAt.Valid {
+		return nil, nil
+	}
+	return r.store.loadTTR(row), nil
+}
+
+func (r *ttrRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ttrs := make([]*models.TTR, 0, len(ids))
+	for _, id := range ids {
+		if row, ok := r.store.ttrs[id]; ok && !row.DeletedAt.Valid {
+			ttrs = append(ttrs, r.store.loadTTR(row))
+		}
+	}
+	return ttrs, nil
+}
+
+// FindAll returns a page of TTRs viewerID takes part in or may find through
+// the TTR's visibility.
+func (r *ttrRepository) FindAll(ctx context.Context, viewerID uuid.UUID, filter repository.TTRSearchFilter) ([]*models.TTR, error) {
+	return r.find(func(ttr models.TTR) bool {
+		if filter.Status != "" && ttr.Status != filter.Status {
+			return false
+		}
+		if filter.TeeFrom != nil && ttr.TeeDate.Before(*filter.TeeFrom) {
+			return false
+		}
+		if filter.TeeTo != nil && ttr.TeeDate.After(*filter.TeeTo) {
+			return false
+		}
+		if filter.Holes != nil && (ttr.Holes == nil || *ttr.Holes != *filter.Holes) {
+			return false
+		}
+		if filter.MaxGreenFeeCents != nil && (ttr.GreenFeeCents == nil || *ttr.GreenFeeCents > *filter.MaxGreenFeeCents) {
+			return false
+		}
+		if filter.Mine != "" && !r.store.takesPart(ttr, viewerID, filter.Mine) {
+			return false
+		}
+		return r.store.canFind(ttr, viewerID)
+	}, false, filter.Limit, filter.Offset), nil
+}
+
+// takesPart reports whether userID takes part in the TTR the way given. The
+// caller must hold s.mu.
+func (s *Store) takesPart(ttr models.TTR, userID uuid.UUID, participation models.TTRParticipation) bool {
+	switch participation {
+	case models.TTRParticipationCaptain:
+		return ttr.CaptainUserID == userID
+	case models.TTRParticipationCoCaptain:
+		return s.isCoCaptain(ttr.ID, userID)
+	case models.TTRParticipationPlayer:
+		return s.isPlayer(ttr.ID, userID)
+	case models.TTRParticipationInvited:
+		closed := ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted
+		return !closed && s.isPendingInvitee(ttr.ID, userID)
+	}
+	return false
+}
+
+// canFind reports whether viewerID can find the TTR in listings, as the GORM
+// repository's ttrVisibleTo does. The caller must hold s.mu.
+func (s *Store) canFind(ttr models.TTR, viewerID uuid.UUID) bool {
+	if s.isMember(ttr, viewerID) || s.isPendingInvitee(ttr.ID, viewerID) {
+		return true
+	}
+	if ttr.OrganizationID != nil && !s.isOrganizationMember(*ttr.OrganizationID, viewerID) {
+		return false
+	}
+	switch ttr.Visibility {
+	case models.TTRVisibilityPublic:
+		return true
+	case models.TTRVisibilityFriends:
+		return s.areFriends(ttr.CaptainUserID, viewerID)
+	default:
+		return false
+	}
+}
+
+func (r *ttrRepository) Update(ctx context.Context, ttr *models.TTR) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := ttr.Validate(); err != nil {
+		return fmt.Errorf("failed to update ttr: %w", err)
+	}
+	ttr.UpdatedAt = time.Now()
+	r.store.ttrs[ttr.ID] = ttrRow(ttr)
+	return nil
+}
+
+func (r *ttrRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if row, ok := r.store.ttrs[id]; ok {
+		row.Status = status
+		row.UpdatedAt = time.Now()
+		r.store.ttrs[id] = row
+	}
+	return nil
+}
+
+func (r *ttrRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if row, ok := r.store.ttrs[id]; ok && !row.DeletedAt.Valid {
+		row.DeletedAt.Time = time.Now()
+		row.DeletedAt.Valid = true
+		r.store.ttrs[id] = row
+	}
+	return nil
+}
+
+func (r *ttrRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.ttrs[id]
+	if !ok || !row.DeletedAt.Valid {
+		return nil, nil
+	}
+	return r.store.loadTTR(row), nil
+}
+
+// FindDeletedByCaptain returns the TTRs captained by captainID that were
+// soft-deleted at or after since, most recently deleted first.
+func (r *ttrRepository) FindDeletedByCaptain(ctx context.Context, captainID uuid.UUID, since time.Time) ([]*models.TTR, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ttrs := make([]*models.TTR, 0)
+	for _, row := range r.store.ttrs {
+		if row.CaptainUserID == captainID && row.DeletedAt.Valid && !row.DeletedAt.Time.Before(since) {
+			ttrs = append(ttrs, r.store.loadTTR(row))
+		}
+	}
+	sort.SliceStable(ttrs, func(i, j int) bool {
+		return ttrs[i].DeletedAt.Time.After(ttrs[j].DeletedAt.Time)
+	})
+	return ttrs, nil
+}
+
+func (r *ttrRepository) Restore(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if row, ok := r.store.ttrs[id]; ok && row.DeletedAt.Valid {
+		row.DeletedAt = gorm.DeletedAt{}
+		row.Status = status
+		row.PreDeleteStatus = nil
+		row.UpdatedAt = time.Now()
+		r.store.ttrs[id] = row
+	}
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes up to limit TTRs soft-deleted before
+// cutoff, oldest first, together with everything that belongs to them.
+func (r *ttrRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	expired := make([]models.TTR, 0)
+	for _, row := range r.store.ttrs {
+		if row.DeletedAt.Valid && row.DeletedAt.Time.Before(cutoff) {
+			expired = append(expired, row)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Time.Before(expired[j].DeletedAt.Time)
+	})
+	expired = page(expired, limit, 0)
+
+	for _, row := range expired {
+		r.store.purgeTTR(row.ID)
+	}
+	return int64(len(expired)), nil
+}
+
+// purgeTTR removes the TTR and every row that belongs to it. The caller must
+// hold s.mu.
+func (s *Store) purgeTTR(id uuid.UUID) {
+	delete(s.ttrs, id)
+
+	players := s.players[:0]
+	for _, player := range s.players {
+		if player.TTRID != id {
+			players = append(players, player)
+		}
+	}
+	s.players = players
+
+	guests := s.guests[:0]
+	for _, guest := range s.guests {
+		if guest.TTRID != id {
+			guests = append(guests, guest)
+		}
+	}
+	s.guests = guests
+
+	slots := s.slots[:0]
+	for _, slot := range s.slots {
+		if slot.TTRID != id {
+			slots = append(slots, slot)
+		}
+	}
+	s.slots = slots
+
+	coCaptains := s.coCaptains[:0]
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.TTRID != id {
+			coCaptains = append(coCaptains, coCaptain)
+		}
+	}
+	s.coCaptains = coCaptains
+
+	for invitationID, invitation := range s.invitations {
+		if invitation.TTRID == id {
+			delete(s.invitations, invitationID)
+		}
+	}
+
+	for linkID, link := range s.inviteLinks {
+		if link.TTRID == id {
+			delete(s.inviteLinks, linkID)
+		}
+	}
+
+	purgedMessages := make(map[uuid.UUID]bool)
+	for messageID, message := range s.messages {
+		if message.TTRID == id {
+			purgedMessages[messageID] = true
+			delete(s.messages, messageID)
+		}
+	}
+	reactions := s.reactions[:0]
+	for _, reaction := range s.reactions {
+		if !purgedMessages[reaction.MessageID] {
+			reactions = append(reactions, reaction)
+		}
+	}
+	s.reactions = reactions
+
+	reads := s.messageReads[:0]
+	for _, read := range s.messageReads {
+		if read.TTRID != id {
+			reads = append(reads, read)
+		}
+	}
+	s.messageReads = reads
+
+	for eventID, event := range s.ttrEvents {
+		if event.TTRID == id {
+			delete(s.ttrEvents, eventID)
+		}
+	}
+
+	for notificationID, notification := range s.notifications {
+		if notification.TargetType != nil && *notification.TargetType == "ttr" && notification.TargetID != nil && *notification.TargetID == id {
+			delete(s.notifications, notificationID)
+		}
+	}
+
+	for tournamentID, tournament := range s.tournaments {
+		if tournament.TTRID != nil && *tournament.TTRID == id {
+			tournament.TTRID = nil
+			s.tournaments[tournamentID] = tournament
+		}
+	}
+	for matchID, match := range s.matches {
+		if match.TTRID != nil && *match.TTRID == id {
+			match.TTRID = nil
+			s.matches[matchID] = match
+		}
+	}
+}
+
+func (r *ttrRepository) FindUpcomingByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
+	cutoff := now.Add(-grace)
+	return r.find(func(ttr models.TTR) bool {
+		return !ttr.TeeDateTime().Before(cutoff) && r.store.isMember(ttr, userID)
+	}, false, 0, 0), nil
+}
+
+func (r *ttrRepository) FindPastByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
+	cutoff := now.Add(-grace)
+	return r.find(func(ttr models.TTR) bool {
+		return ttr.TeeDateTime().Before(cutoff) && r.store.isMember(ttr, userID)
+	}, true, 0, 0), nil
+}
+
+// FindRSVPDeadlinePassed returns TTRs whose RSVP deadline is before now and
+// that haven't been closed for RSVPs yet.
+func (r *ttrRepository) FindRSVPDeadlinePassed(ctx context.Context, now time.Time) ([]*models.TTR, error) {
+	return r.find(func(ttr models.TTR) bool {
+		return ttr.RSVPDeadline != nil && ttr.RSVPDeadline.Before(now) && ttr.RSVPClosedAt == nil
+	}, false, 0, 0), nil
+}
+
+func (r *ttrRepository) FindByCaptainCourseNear(ctx context.Context, captainID uuid.UUID, courseName string, from time.Time, to time.Time) (*models.TTR, error) {
+	ttrs := r.find(func(ttr models.TTR) bool {
+		teeAt := ttr.TeeDateTime()
+		return ttr.CaptainUserID == captainID &&
+			strings.EqualFold(ttr.CourseName, courseName) &&
+			ttr.Status != models.TTRStatusCancelled &&
+			!teeAt.Before(from) && !teeAt.After(to)
+	}, false, 1, 0)
+	if len(ttrs) == 0 {
+		return nil, nil
+	}
+	return ttrs[0], nil
+}
+
+func (r *ttrRepository) FindCheckInSummaryDue(ctx context.Context, from time.Time, to time.Time) ([]*models.TTR, error) {
+	return r.find(func(ttr models.TTR) bool {
+		teeAt := ttr.TeeDateTime()
+		return (ttr.Status == models.TTRStatusOpen || ttr.Status == models.TTRStatusConfirmed) &&
+			ttr.CheckInSummaryAt == nil &&
+			!teeAt.Before(from) && !teeAt.After(to)
+	}, false, 0, 0), nil
+}
+
+func (r *ttrRepository) MarkCheckInSummarySent(ctx context.Context, id uuid.UUID, sentAt time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.ttrs[id]
+	if !ok || row.CheckInSummaryAt != nil {
+		return false, nil
+	}
+	row.CheckInSummaryAt = &sentAt
+	r.store.ttrs[id] = row
+	return true, nil
+}
+
+// find returns the TTRs that aren't deleted and match keep, by tee time.
+func (r *ttrRepository) find(keep func(models.TTR) bool, desc bool, limit int, offset int) []*models.TTR {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ttrs := make([]*models.TTR, 0)
+	for _, row := range r.store.ttrs {
+		if !row.DeletedAt.Valid && keep(row) {
+			ttrs = append(ttrs, r.store.loadTTR(row))
+		}
+	}
+	sortByTeeTime(ttrs, desc)
+	return page(ttrs, limit, offset)
+}
+
+// isMember reports whether userID captains, co-captains or plays in the TTR.
+// The caller must hold s.mu.
+func (s *Store) isMember(ttr models.TTR, userID uuid.UUID) bool {
+	return ttr.CaptainUserID == userID || s.isPlayer(ttr.ID, userID) || s.isCoCaptain(ttr.ID, userID)
+}
+
+func (r *ttrRepository) AddCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.isCoCaptain(ttrID, userID) {
+		return duplicateKey("add co-captain")
+	}
+	r.store.coCaptains = append(r.store.coCaptains, models.TTRCoCaptain{
+		TTRID:       ttrID,
+		UserID:      userID,
+		AssignedAt:  time.Now(),
+		Permissions: strings.Join(permissions, ","),
+	})
+	return nil
+}
+
+func (r *ttrRepository) UpdateCoCaptainPermissions(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, coCaptain := range r.store.coCaptains {
+		if coCaptain.TTRID == ttrID && coCaptain.UserID == userID {
+			r.store.coCaptains[i].Permissions = strings.Join(permissions, ",")
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *ttrRepository) RemoveCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	removed := r.store.isCoCaptain(ttrID, userID)
+	r.store.removeCoCaptain(ttrID, userID)
+	return removed, nil
+}
+
+func (s *Store) removeCoCaptain(ttrID uuid.UUID, userID uuid.UUID) {
+	kept := s.coCaptains[:0]
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.TTRID != ttrID || coCaptain.UserID != userID {
+			kept = append(kept, coCaptain)
+		}
+	}
+	s.coCaptains = kept
+}
+
+func (r *ttrRepository) IsCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isCoCaptain(ttrID, userID), nil
+}
+
+func (r *ttrRepository) AddPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.addPlayer(ttrID, userID, status)
+}
+
+func (r *ttrRepository) AddPlayerIfOpen(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.hasOpenSlot(ttrID) {
+		return repository.ErrTTRFull
+	}
+	return r.store.addPlayer(ttrID, userID, status)
+}
+
+func (s *Store) addPlayer(ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
+	if s.isPlayer(ttrID, userID) {
+		return duplicateKey("add player")
+	}
+	if status == "" {
+		status = models.DefaultPlayerStatus
+	}
+	s.players = append(s.players, models.TTRPlayer{
+		TTRID:    ttrID,
+		UserID:   userID,
+		JoinedAt: time.Now(),
+		Status:   status,
+	})
+	return nil
+}
+
+// hasOpenSlot reports whether the TTR's active players and guests leave a
+// slot free.
+func (s *Store) hasOpenSlot(ttrID uuid.UUID) bool {
+	taken := 0
+	for _, player := range s.players {
+		if player.TTRID == ttrID && player.Status.IsActive() {
+			taken++
+		}
+	}
+	for _, guest := range s.guests {
+		if guest.TTRID == ttrID {
+			taken++
+		}
+	}
+	return taken < s.ttrs[ttrID].MaxPlayers
+}
+
+func (r *ttrRepository) RemovePlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.removePlayer(ttrID, userID)
+	return nil
+}
+
+func (s *Store) removePlayer(ttrID uuid.UUID, userID uuid.UUID) {
+	kept := s.players[:0]
+	for _, player := range s.players {
+		if player.TTRID != ttrID || player.UserID != userID {
+			kept = append(kept, player)
+		}
+	}
+	s.players = kept
+}
+
+// UpdatePlayer saves the player's status, notes, group number and score.
+func (r *ttrRepository) UpdatePlayer(ctx context.Context, player *models.TTRPlayer) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.players {
+		existing := &r.store.players[i]
+		if existing.TTRID == player.TTRID && existing.UserID == player.UserID {
+			existing.Status = player.Status
+			existing.Notes = player.Notes
+			existing.GroupNumber = player.GroupNumber
+			existing.Score = player.Score
+		}
+	}
+	return nil
+}
+
+func (r *ttrRepository) UpdatePlayerCheckIn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, checkedInAt time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i := range r.store.players {
+		player := &r.store.players[i]
+		if player.TTRID == ttrID && player.UserID == userID && player.CheckedInAt == nil {
+			player.CheckedInAt = &checkedInAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoveMember drops userID from the TTR's players and co-captains and hands
+// their pending invitations over to newInviterID.
+func (r *ttrRepository) RemoveMember(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, newInviterID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	removed := r.store.isPlayer(ttrID, userID) || r.store.isCoCaptain(ttrID, userID)
+	r.store.removePlayer(ttrID, userID)
+	r.store.removeCoCaptain(ttrID, userID)
+	for id, invitation := range r.store.invitations {
+		if invitation.TTRID == ttrID && invitation.InviterUserID == userID && invitation.Status == models.InvitationStatusPending {
+			invitation.InviterUserID = newInviterID
+			r.store.invitations[id] = invitation
+		}
+	}
+	return removed, nil
+}
+
+func (r *ttrRepository) GetPlayers(ctx context.Context, ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	players := make([]*models.TTRPlayer, 0)
+	for _, player := range r.store.players {
+		if player.TTRID == ttrID {
+			player := player
+			player.User = r.store.user(player.UserID)
+			players = append(players, &player)
+		}
+	}
+	return players, nil
+}
+
+func (r *ttrRepository) CountPlayers(ctx context.Context, ttrIDs []uuid.UUID) (map[uuid.UUID]models.PlayerCounts, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(ttrIDs))
+	for _, id := range ttrIDs {
+		wanted[id] = true
+	}
+	counts := make(map[uuid.UUID]models.PlayerCounts, len(ttrIDs))
+	for _, player := range r.store.players {
+		if !wanted[player.TTRID] {
+			continue
+		}
+		if !player.Status.IsActive() {
+			continue
+		}
+		count := counts[player.TTRID]
+		count.Total++
+		if player.Status == models.TTRPlayerStatusConfirmed {
+			count.Confirmed++
+		}
+		counts[player.TTRID] = count
+	}
+	for _, guest := range r.store.guests {
+		if !wanted[guest.TTRID] {
+			continue
+		}
+		count := counts[guest.TTRID]
+		count.Total++
+		count.Confirmed++
+		counts[guest.TTRID] = count
+	}
+	return counts, nil
+}
+
+func (r *ttrRepository) AddGuest(ctx context.Context, guest *models.TTRGuest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if guest.ID == uuid.Nil {
+		guest.ID = uuid.New()
+	}
+	if guest.CreatedAt.IsZero() {
+		guest.CreatedAt = time.Now()
+	}
+	r.store.guests = append(r.store.guests, *guest)
+	return nil
+}
+
+func (r *ttrRepository) RemoveGuest(ctx context.Context, ttrID uuid.UUID, guestID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, guest := range r.store.guests {
+		if guest.ID == guestID && guest.TTRID == ttrID {
+			r.store.guests = append(r.store.guests[:i], r.store.guests[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *ttrRepository) IsPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isPlayer(ttrID, userID), nil
+}
+
+func (r *ttrRepository) ReplaceSlots(ctx context.Context, ttrID uuid.UUID, slots []models.TeeSlot) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	kept := r.store.slots[:0]
+	for _, slot := range r.store.slots {
+		if slot.TTRID != ttrID {
+			kept = append(kept, slot)
+		}
+	}
+	now := time.Now()
+	for i := range slots {
+		if slots[i].ID == uuid.Nil {
+			slots[i].ID = uuid.New()
+		}
+		if slots[i].CreatedAt.IsZero() {
+			slots[i].CreatedAt = now
+		}
+		kept = append(kept, slots[i])
+	}
+	r.store.slots = kept
+	return nil
+}
@@ -0,0 +1,850 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+	"gorm.io/gorm"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{store: store}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if _, exists := r.store.users[user.ID]; exists {
+		return duplicateKey("create user")
+	}
+	user.NormalizedEmail = emailnorm.Normalize(user.Email)
+	for _, existing := range r.store.users {
+		if existing.NormalizedEmail == user.NormalizedEmail {
+			return duplicateKey("create user")
+		}
+	}
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.user(id), nil
+}
+
+func (r *userRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := []*models.User{}
+	for _, id := range ids {
+		if user, ok := r.store.users[id]; ok && !user.DeletedAt.Valid {
+			users = append(users, &user)
+		}
+	}
+	return users, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	normalized := emailnorm.Normalize(email)
+	for _, user := range r.store.users {
+		if user.NormalizedEmail == normalized && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.NormalizedEmail = emailnorm.Normalize(user.Email)
+	user.UpdatedAt = time.Now()
+	stored := *user
+	stored.PlayingDays = r.store.users[user.ID].PlayingDays
+	r.store.users[user.ID] = stored
+	return nil
+}
+
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if user, ok := r.store.users[id]; ok && !user.DeletedAt.Valid {
+		user.LastLoginAt = &at
+		user.InactivityWarnedAt = nil
+		r.store.users[id] = user
+	}
+	return nil
+}
+
+func (r *userRepository) MarkEmailUndeliverable(ctx context.Context, email string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	normalized := emailnorm.Normalize(email)
+	for id, user := range r.store.users {
+		if user.NormalizedEmail == normalized {
+			user.EmailUndeliverable = true
+			r.store.users[id] = user
+		}
+	}
+	return nil
+}
+
+// hasUpcomingTTR reports whether the user captains or is on the roster of a
+// TTR still to be played on or after from. The caller must hold s.mu.
+func (s *Store) hasUpcomingTTR(userID uuid.UUID, from time.Time) bool {
+	for id, ttr := range s.ttrs {
+		if _, live := s.liveTTR(id, from); !live {
+			continue
+		}
+		if ttr.CaptainUserID == userID {
+			return true
+		}
+		for _, player := range s.players {
+			if player.TTRID == id && player.UserID == userID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lastSeen is when the user last logged in, or signed up if they never have.
+func lastSeen(user *models.User) time.Time {
+	if user.LastLoginAt != nil {
+		return *user.LastLoginAt
+	}
+	return user.CreatedAt
+}
+
+func (r *userRepository) FindInactiveSince(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]*models.User, 0)
+	for _, user := range r.store.users {
+		user := user
+		if user.DeletedAt.Valid || user.InactivityWarnedAt != nil || !lastSeen(&user).Before(cutoff) || r.store.hasUpcomingTTR(user.ID, from) {
+			continue
+		}
+		users = append(users, &user)
+	}
+	sortByTime(users, lastSeen, func(u *models.User) uuid.UUID { return u.ID }, false)
+	return page(users, limit, 0), nil
+}
+
+func (r *userRepository) FindWarnedBefore(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]*models.User, 0)
+	for _, user := range r.store.users {
+		user := user
+		if user.DeletedAt.Valid || user.InactivityWarnedAt == nil || !user.InactivityWarnedAt.Before(cutoff) || r.store.hasUpcomingTTR(user.ID, from) {
+			continue
+		}
+		users = append(users, &user)
+	}
+	sortByTime(users, func(u *models.User) time.Time { return *u.InactivityWarnedAt }, func(u *models.User) uuid.UUID { return u.ID }, false)
+	return page(users, limit, 0), nil
+}
+
+func (r *userRepository) MarkInactivityWarned(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid || user.InactivityWarnedAt != nil {
+		return false, nil
+	}
+	user.InactivityWarnedAt = &at
+	r.store.users[id] = user
+	return true, nil
+}
+
+func (r *userRepository) FindDigestDue(ctx context.Context, before time.Time, limit int) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]*models.User, 0)
+	for _, user := range r.store.users {
+		user := user
+		if user.DeletedAt.Valid || !user.Notifications.WeeklyDigest() || user.EmailUndeliverable || !digestDue(&user, before) {
+			continue
+		}
+		users = append(users, &user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID.String() < users[j].ID.String() })
+	return page(users, limit, 0), nil
+}
+
+func (r *userRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, before time.Time, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid || !digestDue(&user, before) {
+		return false, nil
+	}
+	user.DigestSentAt = &at
+	r.store.users[id] = user
+	return true, nil
+}
+
+func digestDue(user *models.User, before time.Time) bool {
+	return user.DigestSentAt == nil || user.DigestSentAt.Before(before)
+}
+
+func (r *userRepository) Deactivate(ctx context.Context, id uuid.UUID, warnedBefore time.Time, at time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok || user.DeletedAt.Valid || user.InactivityWarnedAt == nil || !user.InactivityWarnedAt.Before(warnedBefore) {
+		return false, nil
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+	r.store.users[id] = user
+	return true, nil
+}
+
+func (r *userRepository) SetPlayingDays(ctx context.Context, userID uuid.UUID, days []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return nil
+	}
+	user.PlayingDays = make([]models.UserPlayingDay, 0, len(days))
+	for _, day := range days {
+		user.PlayingDays = append(user.PlayingDays, models.UserPlayingDay{UserID: userID, Day: day})
+	}
+	r.store.users[userID] = user
+	return nil
+}
+
+func (r *userRepository) Block(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, block := range r.store.userBlocks {
+		if block.BlockerUserID == blockerID && block.BlockedUserID == blockedID {
+			return nil
+		}
+	}
+	r.store.userBlocks = append(r.store.userBlocks, models.UserBlock{BlockerUserID: blockerID, BlockedUserID: blockedID, CreatedAt: time.Now()})
+	return nil
+}
+
+func (r *userRepository) Unblock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, block := range r.store.userBlocks {
+		if block.BlockerUserID == blockerID && block.BlockedUserID == blockedID {
+			r.store.userBlocks = append(r.store.userBlocks[:i], r.store.userBlocks[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *userRepository) IsBlocked(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, block := range r.store.userBlocks {
+		if block.BlockerUserID == blockerID && block.BlockedUserID == blockedID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *userRepository) FindBlocked(ctx context.Context, blockerID uuid.UUID) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blocks := make([]*models.UserBlock, 0)
+	for _, block := range r.store.userBlocks {
+		if block.BlockerUserID != blockerID {
+			continue
+		}
+		if user, ok := r.store.users[block.BlockedUserID]; ok && !user.DeletedAt.Valid {
+			block := block
+			blocks = append(blocks, &block)
+		}
+	}
+	sortByTime(blocks, func(b *models.UserBlock) time.Time { return b.CreatedAt }, func(b *models.UserBlock) uuid.UUID { return b.BlockedUserID }, true)
+
+	users := make([]*models.User, len(blocks))
+	for i, block := range blocks {
+		users[i] = r.store.user(block.BlockedUserID)
+	}
+	return users, nil
+}
+
+func (r *userRepository) RequestFriendship(ctx context.Context, requesterID uuid.UUID, addresseeID uuid.UUID, at time.Time) (*models.Friendship, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, friendship := range r.store.friendships {
+		if friendship.RequesterUserID == addresseeID && friendship.AddresseeUserID == requesterID {
+			if !friendship.Accepted() {
+				friendship.AcceptedAt = &at
+				r.store.friendships[i] = friendship
+			}
+			return &friendship, nil
+		}
+		if friendship.RequesterUserID == requesterID && friendship.AddresseeUserID == addresseeID {
+			return &friendship, nil
+		}
+	}
+	friendship := models.Friendship{RequesterUserID: requesterID, AddresseeUserID: addresseeID, CreatedAt: at}
+	r.store.friendships = append(r.store.friendships, friendship)
+	return &friendship, nil
+}
+
+func (r *userRepository) RemoveFriendship(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	friendships := r.store.friendships[:0]
+	removed := false
+	for _, friendship := range r.store.friendships {
+		if friendship.Between(userID, otherUserID) {
+			removed = true
+			continue
+		}
+		friendships = append(friendships, friendship)
+	}
+	r.store.friendships = friendships
+	return removed, nil
+}
+
+func (r *userRepository) AreFriends(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.areFriends(userID, otherUserID), nil
+}
+
+// areFriends reports whether the two users are friends. The caller must hold
+// s.mu.
+func (s *Store) areFriends(userID uuid.UUID, otherUserID uuid.UUID) bool {
+	for _, friendship := range s.friendships {
+		if friendship.Accepted() && friendship.Between(userID, otherUserID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *userRepository) FindFriends(ctx context.Context, userID uuid.UUID) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type friend struct {
+		userID     uuid.UUID
+		acceptedAt time.Time
+	}
+	friends := make([]*friend, 0)
+	for _, friendship := range r.store.friendships {
+		if !friendship.Accepted() {
+			continue
+		}
+		otherID := friendship.RequesterUserID
+		if otherID == userID {
+			otherID = friendship.AddresseeUserID
+		} else if friendship.AddresseeUserID != userID {
+			continue
+		}
+		if user, ok := r.store.users[otherID]; ok && !user.DeletedAt.Valid {
+			friends = append(friends, &friend{userID: otherID, acceptedAt: *friendship.AcceptedAt})
+		}
+	}
+	sortByTime(friends, func(f *friend) time.Time { return f.acceptedAt }, func(f *friend) uuid.UUID { return f.userID }, true)
+
+	users := make([]*models.User, len(friends))
+	for i, friend := range friends {
+		users[i] = r.store.user(friend.userID)
+	}
+	return users, nil
+}
+
+func (r *userRepository) FindFriendRequests(ctx context.Context, addresseeID uuid.UUID) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	requests := make([]*models.Friendship, 0)
+	for _, friendship := range r.store.friendships {
+		if friendship.AddresseeUserID != addresseeID || friendship.Accepted() {
+			continue
+		}
+		if user, ok := r.store.users[friendship.RequesterUserID]; ok && !user.DeletedAt.Valid {
+			friendship := friendship
+			requests = append(requests, &friendship)
+		}
+	}
+	sortByTime(requests, func(f *models.Friendship) time.Time { return f.CreatedAt }, func(f *models.Friendship) uuid.UUID { return f.RequesterUserID }, true)
+
+	users := make([]*models.User, len(requests))
+	for i, request := range requests {
+		users[i] = r.store.user(request.RequesterUserID)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Search(ctx context.Context, filter repository.UserSearchFilter) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	name := strings.ToLower(filter.Name)
+	email := emailnorm.Normalize(filter.Email)
+	users := make([]*models.User, 0)
+	for _, user := range r.store.users {
+		if user.DeletedAt.Valid {
+			continue
+		}
+		if filter.Email != "" {
+			if user.NormalizedEmail != email {
+				continue
+			}
+		} else if !user.Privacy.Discoverable() || !strings.Contains(strings.ToLower(user.FirstName), name) && !strings.Contains(strings.ToLower(user.LastName), name) {
+			continue
+		}
+		if filter.ExcludeUserID != uuid.Nil && user.ID == filter.ExcludeUserID {
+			continue
+		}
+		if filter.ExcludeTTRID != uuid.Nil && r.store.isPlayer(filter.ExcludeTTRID, user.ID) {
+			continue
+		}
+
+		user := user
+		users = append(users, &user)
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		if users[i].FirstName != users[j].FirstName {
+			return users[i].FirstName < users[j].FirstName
+		}
+		return users[i].LastName < users[j].LastName
+	})
+	return page(users, filter.Limit, filter.Offset), nil
+}
+
+// FindCaptainedBy returns those of userIDs who play on a TTR captainID
+// captains or co-captains.
+func (r *userRepository) FindCaptainedBy(ctx context.Context, captainID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	captained := make([]uuid.UUID, 0)
+	for _, player := range r.store.players {
+		if !wanted[player.UserID] {
+			continue
+		}
+		ttr, ok := r.store.ttrs[player.TTRID]
+		if !ok || ttr.DeletedAt.Valid {
+			continue
+		}
+		if ttr.CaptainUserID == captainID || r.store.isCoCaptain(ttr.ID, captainID) {
+			captained = append(captained, player.UserID)
+			delete(wanted, player.UserID)
+		}
+	}
+	return captained, nil
+}
+
+// PurgeDeletedBefore permanently deletes up to limit users soft-deleted
+// before cutoff that nothing outside their own data still points at, oldest
+// deletion first, together with their own rows.
+func (r *userRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	expired := make([]models.User, 0)
+	for _, user := range r.store.users {
+		if user.DeletedAt.Valid && user.DeletedAt.Time.Before(cutoff) && !r.store.isReferenced(user.ID) {
+			expired = append(expired, user)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Time.Before(expired[j].DeletedAt.Time)
+	})
+	expired = page(expired, limit, 0)
+
+	for _, user := range expired {
+		r.store.purgeUser(user.ID)
+	}
+	return int64(len(expired)), nil
+}
+
+// Merge moves the source user's rows to the target, keeping the target's
+// where both hold the same spot, and soft-deletes the source.
+func (r *userRepository) Merge(ctx context.Context, sourceID uuid.UUID, targetID uuid.UUID, at time.Time) (*repository.UserMerge, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := r.store
+	merge := &repository.UserMerge{}
+	for id, ttr := range s.ttrs {
+		if ttr.CaptainUserID == sourceID {
+			ttr.CaptainUserID = targetID
+			merge.CaptainedTTRs++
+		}
+		if ttr.CreatedByUserID == sourceID {
+			ttr.CreatedByUserID = targetID
+		}
+		s.ttrs[id] = ttr
+	}
+
+	coCaptained := make(map[uuid.UUID]bool)
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.UserID == targetID {
+			coCaptained[coCaptain.TTRID] = true
+		}
+	}
+	coCaptains := s.coCaptains[:0]
+	for _, coCaptain := range s.coCaptains {
+		switch {
+		case coCaptain.UserID != sourceID && coCaptain.UserID != targetID:
+		case s.ttrs[coCaptain.TTRID].CaptainUserID == targetID,
+			coCaptain.UserID == sourceID && coCaptained[coCaptain.TTRID]:
+			merge.DroppedCoCaptaincies++
+			continue
+		case coCaptain.UserID == sourceID:
+			coCaptain.UserID = targetID
+			merge.CoCaptaincies++
+		}
+		coCaptains = append(coCaptains, coCaptain)
+	}
+	s.coCaptains = coCaptains
+
+	plays := make(map[uuid.UUID]bool)
+	for _, player := range s.players {
+		if player.UserID == targetID {
+			plays[player.TTRID] = true
+		}
+	}
+	players := s.players[:0]
+	for _, player := range s.players {
+		if player.UserID == sourceID {
+			if plays[player.TTRID] {
+				merge.DroppedRosterSpots++
+				continue
+			}
+			player.UserID = targetID
+			merge.RosterSpots++
+		}
+		players = append(players, player)
+	}
+	s.players = players
+
+	pending := make(map[uuid.UUID]bool)
+	for _, invitation := range s.invitations {
+		if invitation.InviteeUserID == targetID && invitation.Status == models.InvitationStatusPending {
+			pending[invitation.TTRID] = true
+		}
+	}
+	for id, invitation := range s.invitations {
+		between := invitation.InviterUserID == sourceID && invitation.InviteeUserID == targetID ||
+			invitation.InviterUserID == targetID && invitation.InviteeUserID == sourceID
+		duplicate := invitation.InviteeUserID == sourceID && invitation.Status == models.InvitationStatusPending && pending[invitation.TTRID]
+		if between || duplicate {
+			delete(s.invitations, id)
+			merge.DroppedInvitations++
+			continue
+		}
+		if invitation.InviteeUserID == sourceID {
+			invitation.InviteeUserID = targetID
+			merge.Invitations++
+		}
+		if invitation.InviterUserID == sourceID {
+			invitation.InviterUserID = targetID
+			merge.Invitations++
+		}
+		if invitation.ForwardedByUserID != nil && *invitation.ForwardedByUserID == sourceID {
+			invitation.ForwardedByUserID = &targetID
+		}
+		s.invitations[id] = invitation
+	}
+
+	blocked := make(map[[2]uuid.UUID]bool)
+	for _, block := range s.userBlocks {
+		if block.BlockerUserID != sourceID && block.BlockedUserID != sourceID {
+			blocked[[2]uuid.UUID{block.BlockerUserID, block.BlockedUserID}] = true
+		}
+	}
+	blocks := make([]models.UserBlock, 0, len(s.userBlocks))
+	for _, block := range s.userBlocks {
+		if block.BlockerUserID == sourceID || block.BlockedUserID == sourceID {
+			if block.BlockerUserID == sourceID {
+				block.BlockerUserID = targetID
+			}
+			if block.BlockedUserID == sourceID {
+				block.BlockedUserID = targetID
+			}
+			key := [2]uuid.UUID{block.BlockerUserID, block.BlockedUserID}
+			if block.BlockerUserID == block.BlockedUserID || blocked[key] {
+				continue
+			}
+			blocked[key] = true
+		}
+		blocks = append(blocks, block)
+	}
+	s.userBlocks = blocks
+
+	befriended := make(map[uuid.UUID]bool)
+	for _, friendship := range s.friendships {
+		if friendship.RequesterUserID == targetID {
+			befriended[friendship.AddresseeUserID] = true
+		} else if friendship.AddresseeUserID == targetID {
+			befriended[friendship.RequesterUserID] = true
+		}
+	}
+	friendships := make([]models.Friendship, 0, len(s.friendships))
+	for _, friendship := range s.friendships {
+		if friendship.RequesterUserID == sourceID || friendship.AddresseeUserID == sourceID {
+			otherID := friendship.RequesterUserID
+			if otherID == sourceID {
+				otherID = friendship.AddresseeUserID
+				friendship.RequesterUserID = targetID
+			} else {
+				friendship.AddresseeUserID = targetID
+			}
+			if otherID == targetID || befriended[otherID] {
+				continue
+			}
+			befriended[otherID] = true
+		}
+		friendships = append(friendships, friendship)
+	}
+	s.friendships = friendships
+
+	for id, notification := range s.notifications {
+		if notification.UserID == sourceID {
+			notification.UserID = targetID
+			s.notifications[id] = notification
+			merge.Notifications++
+		}
+	}
+	for id, message := range s.messages {
+		if message.UserID == sourceID {
+			message.UserID = targetID
+			s.messages[id] = message
+			merge.Messages++
+		}
+	}
+	for id, token := range s.refreshTokens {
+		if token.UserID == sourceID && !token.Revoked {
+			token.Revoked = true
+			s.refreshTokens[id] = token
+			merge.RevokedRefreshTokens++
+		}
+	}
+
+	if user, ok := s.users[sourceID]; ok {
+		user.MergedIntoUserID = &targetID
+		if !user.DeletedAt.Valid {
+			user.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+		}
+		s.users[sourceID] = user
+	}
+	return merge, nil
+}
+
+// isReferenced reports whether a TTR, chat message, organization, league or
+// tournament still points at the user. The caller must hold s.mu.
+func (s *Store) isReferenced(userID uuid.UUID) bool {
+	is := func(id *uuid.UUID) bool {
+		return id != nil && *id == userID
+	}
+	for _, ttr := range s.ttrs {
+		if ttr.CaptainUserID == userID || ttr.CreatedByUserID == userID {
+			return true
+		}
+	}
+	for _, message := range s.messages {
+		if message.UserID == userID {
+			return true
+		}
+	}
+	for _, org := range s.organizations {
+		if org.CreatedByUserID == userID {
+			return true
+		}
+	}
+	for _, invitation := range s.organizationInvitations {
+		if invitation.InvitedByUserID == userID {
+			return true
+		}
+	}
+	for _, league := range s.leagues {
+		if league.OwnerUserID == userID {
+			return true
+		}
+	}
+	for _, tournament := range s.tournaments {
+		if tournament.OwnerUserID == userID || is(tournament.WinnerUserID) {
+			return true
+		}
+	}
+	for _, match := range s.matches {
+		if is(match.Player1UserID) || is(match.Player2UserID) || is(match.WinnerUserID) {
+			return true
+		}
+	}
+	return false
+}
+
+// purgeUser removes the user and every row that belongs to them. The caller
+// must hold s.mu.
+func (s *Store) purgeUser(userID uuid.UUID) {
+	delete(s.users, userID)
+
+	for id, token := range s.refreshTokens {
+		if token.UserID == userID {
+			delete(s.refreshTokens, id)
+		}
+	}
+	for id, notification := range s.notifications {
+		if notification.UserID == userID {
+			delete(s.notifications, id)
+		}
+	}
+	for id, invitation := range s.invitations {
+		if invitation.InviteeUserID == userID || invitation.InviterUserID == userID {
+			delete(s.invitations, id)
+		}
+	}
+	for id, event := range s.securityEvents {
+		if event.UserID == userID {
+			delete(s.securityEvents, id)
+		}
+	}
+	for id, report := range s.reports {
+		if report.ReporterUserID == userID {
+			delete(s.reports, id)
+		} else if report.ResolvedByUserID != nil && *report.ResolvedByUserID == userID {
+			report.ResolvedByUserID = nil
+			s.reports[id] = report
+		}
+	}
+
+	for id, invitation := range s.invitations {
+		if invitation.ForwardedByUserID != nil && *invitation.ForwardedByUserID == userID {
+			invitation.ForwardedByUserID = nil
+			s.invitations[id] = invitation
+		}
+	}
+	blocks := s.userBlocks[:0]
+	for _, block := range s.userBlocks {
+		if block.BlockerUserID != userID && block.BlockedUserID != userID {
+			blocks = append(blocks, block)
+		}
+	}
+	s.userBlocks = blocks
+	friendships := s.friendships[:0]
+	for _, friendship := range s.friendships {
+		if friendship.RequesterUserID != userID && friendship.AddresseeUserID != userID {
+			friendships = append(friendships, friendship)
+		}
+	}
+	s.friendships = friendships
+
+	devices := s.knownDevices[:0]
+	for _, device := range s.knownDevices {
+		if device.UserID != userID {
+			devices = append(devices, device)
+		}
+	}
+	s.knownDevices = devices
+
+	members := s.organizationMembers[:0]
+	for _, member := range s.organizationMembers {
+		if member.UserID != userID {
+			members = append(members, member)
+		}
+	}
+	s.organizationMembers = members
+
+	players := s.players[:0]
+	for _, player := range s.players {
+		if player.UserID != userID {
+			players = append(players, player)
+		}
+	}
+	s.players = players
+
+	coCaptains := s.coCaptains[:0]
+	for _, coCaptain := range s.coCaptains {
+		if coCaptain.UserID != userID {
+			coCaptains = append(coCaptains, coCaptain)
+		}
+	}
+	s.coCaptains = coCaptains
+
+	reads := s.messageReads[:0]
+	for _, read := range s.messageReads {
+		if read.UserID != userID {
+			reads = append(reads, read)
+		}
+	}
+	s.messageReads = reads
+
+	reactions := s.reactions[:0]
+	for _, reaction := range s.reactions {
+		if reaction.UserID != userID {
+			reactions = append(reactions, reaction)
+		}
+	}
+	s.reactions = reactions
+}
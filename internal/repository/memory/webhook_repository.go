@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type webhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) repository.WebhookRepository {
+	return &webhookRepository{store: store}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if webhook.ID == uuid.Nil {
+		webhook.ID = uuid.New()
+	}
+	if _, exists := r.store.webhooks[webhook.ID]; exists {
+		return duplicateKey("create webhook")
+	}
+	if webhook.Status == "" {
+		webhook.Status = models.WebhookStatusActive
+	}
+	now := time.Now()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = now
+	}
+	webhook.UpdatedAt = now
+
+	r.store.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhook, ok := r.store.webhooks[id]
+	if !ok {
+		return nil, nil
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) FindByOwner(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	return r.find(func(w models.Webhook) bool {
+		return w.OwnerUserID == userID && w.OrganizationID == nil
+	}), nil
+}
+
+func (r *webhookRepository) FindByOrganization(ctx context.Context, orgID uuid.UUID) ([]*models.Webhook, error) {
+	return r.find(func(w models.Webhook) bool {
+		return w.OrganizationID != nil && *w.OrganizationID == orgID
+	}), nil
+}
+
+func (r *webhookRepository) FindActiveForTTR(ctx context.Context, captainID uuid.UUID, orgID *uuid.UUID) ([]*models.Webhook, error) {
+	return r.find(func(w models.Webhook) bool {
+		if w.Status != models.WebhookStatusActive {
+			return false
+		}
+		if w.OrganizationID == nil {
+			return w.OwnerUserID == captainID
+		}
+		return orgID != nil && *w.OrganizationID == *orgID
+	}), nil
+}
+
+func (r *webhookRepository) find(match func(models.Webhook) bool) []*models.Webhook {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhooks := make([]*models.Webhook, 0)
+	for _, webhook := range r.store.webhooks {
+		if match(webhook) {
+			webhook := webhook
+			webhooks = append(webhooks, &webhook)
+		}
+	}
+	sortByTime(webhooks, func(w *models.Webhook) time.Time { return w.CreatedAt }, func(w *models.Webhook) uuid.UUID { return w.ID }, false)
+	return webhooks
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	webhook.UpdatedAt = time.Now()
+	r.store.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *webhookRepository) RecordResult(ctx context.Context, id uuid.UUID, succeeded bool, failingAfter int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	webhook, ok := r.store.webhooks[id]
+	if !ok {
+		return nil
+	}
+	if succeeded {
+		webhook.ConsecutiveFailures = 0
+	} else {
+		webhook.ConsecutiveFailures++
+		if failingAfter > 0 && webhook.ConsecutiveFailures >= failingAfter {
+			webhook.Status = models.WebhookStatusFailing
+		}
+	}
+	r.store.webhooks[id] = webhook
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.webhooks, id)
+	for deliveryID, delivery := range r.store.webhookDeliveries {
+		if delivery.WebhookID == id {
+			delete(r.store.webhookDeliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	r.store.webhookDeliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, webhookID uuid.UUID, limit int, offset int) ([]*models.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for _, delivery := range r.store.webhookDeliveries {
+		if delivery.WebhookID == webhookID {
+			delivery := delivery
+			deliveries = append(deliveries, &delivery)
+		}
+	}
+	sortByTime(deliveries, func(d *models.WebhookDelivery) time.Time { return d.CreatedAt }, func(d *models.WebhookDelivery) uuid.UUID { return d.ID }, true)
+	return page(deliveries, limit, offset), nil
+}
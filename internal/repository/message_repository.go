@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MessageRepository interface {
+	Create(ctx context.Context, message *models.Message) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Message, error)
+	FindByTTRID(ctx context.Context, ttrID uuid.UUID, limit int, offset int) ([]*models.Message, error)
+	Update(ctx context.Context, message *models.Message) error
+	MarkRead(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, readAt time.Time) error
+	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error)
+	AddReaction(ctx context.Context, reaction *models.MessageReaction) error
+	RemoveReaction(ctx context.Context, messageID uuid.UUID, userID uuid.UUID, emoji string) error
+	SummarizeReactions(ctx context.Context, messageIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]models.ReactionSummary, error)
+	SumAttachmentSizeByTTRID(ctx context.Context, ttrID uuid.UUID) (int64, error)
+	SumAttachmentSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	FindDeletedWithAttachments(ctx context.Context, limit int) ([]*models.Message, error)
+	ClearAttachment(ctx context.Context, id uuid.UUID) error
+}
+
+type messageRepository struct {
+	db *gorm.DB
+}
+
+func NewMessageRepository(db *gorm.DB) MessageRepository {
+	return &messageRepository{db: db}
+}
+
+func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
+	if err := txOrDB(ctx, r.db).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+	return nil
+}
+
+func (r *messageRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	var message models.Message
+	if err := txOrDB(ctx, r.db).
+		Preload("User", withDeletedUsers).
+		Where("id = ?", id).
+		First(&message).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find message by ID: %w", err)
+	}
+	return &message, nil
+}
+
+// FindByTTRID returns a page of the TTR's messages, newest first.
+func (r *messageRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID, limit int, offset int) ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := txOrDB(ctx, r.db).
+		Preload("User", withDeletedUsers).
+		Where("ttr_id = ?", ttrID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to find messages: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *messageRepository) Update(ctx context.Context, message *models.Message) error {
+	if err := txOrDB(ctx, r.db).Save(message).Error; err != nil {
+		return fmt.Errorf("failed to update message: %w", err)
+	}
+	return nil
+}
+
+// MarkRead moves the user's read marker for the TTR forward to readAt. An
+// older readAt leaves the marker where it is, so out-of-order requests never
+// make messages unread again.
+func (r *messageRepository) MarkRead(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, readAt time.Time) error {
+	read := &models.MessageRead{TTRID: ttrID, UserID: userID, LastReadAt: readAt}
+	if err := txOrDB(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "ttr_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"last_read_at": gorm.Expr("CASE WHEN excluded.last_read_at > ttr_message_reads.last_read_at THEN excluded.last_read_at ELSE ttr_message_reads.last_read_at END"),
+		}),
+	}).Create(read).Error; err != nil {
+		return fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+	return nil
+}
+
+// CountUnreadByUserID returns the number of unread messages per TTR for every
+// TTR the user plays in. Their own and deleted messages never count as
+// unread, and TTRs with nothing unread are left out of the map.
+func (r *messageRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		TTRID  uuid.UUID
+		Unread int64
+	}
+
+	if err := txOrDB(ctx, r.db).
+		Table("ttr_messages AS m").
+		Select("m.ttr_id AS ttr_id, COUNT(*) AS unread").
+		Joins("JOIN ttr_players AS p ON p.ttr_id = m.ttr_id AND p.user_id = ?", userID).
+		Joins("JOIN ttrs AS t ON t.id = m.ttr_id AND t.deleted_at IS NULL").
+		Joins("LEFT JOIN ttr_message_reads AS r ON r.ttr_id = m.ttr_id AND r.user_id = ?", userID).
+		Where("m.user_id <> ? AND m.deleted = ?", userID, false).
+		Where("r.last_read_at IS NULL OR m.created_at > r.last_read_at").
+		Group("m.ttr_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TTRID] = row.Unread
+	}
+	return counts, nil
+}
+
+// AddReaction records the reaction. Adding a reaction the user already has is
+// a no-op.
+func (r *messageRepository) AddReaction(ctx context.Context, reaction *models.MessageReaction) error {
+	if err := txOrDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(reaction).Error; err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return nil
+}
+
+func (r *messageRepository) RemoveReaction(ctx context.Context, messageID uuid.UUID, userID uuid.UUID, emoji string) error {
+	if err := txOrDB(ctx, r.db).
+		Where("message_id = ? AND user_id = ? AND emoji = ?", messageID, userID, emoji).
+		Delete(&models.MessageReaction{}).Error; err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return nil
+}
+
+// SummarizeReactions returns per-emoji reaction counts for each message,
+// flagging the emoji userID reacted with. Messages without reactions are left
+// out of the map.
+func (r *messageRepository) SummarizeReactions(ctx context.Context, messageIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]models.ReactionSummary, error) {
+	summaries := make(map[uuid.UUID][]models.ReactionSummary)
+	if len(messageIDs) == 0 {
+		return summaries, nil
+	}
+
+	var rows []struct {
+		MessageID uuid.UUID
+		Emoji     string
+		Count     int64
+		Mine      int
+	}
+
+	if err := txOrDB(ctx, r.db).
+		Model(&models.MessageReaction{}).
+		Select("message_id, emoji, COUNT(*) AS count, MAX(CASE WHEN user_id = ? THEN 1 ELSE 0 END) AS mine", userID).
+		Where("message_id IN ?", messageIDs).
+		Group("message_id, emoji").
+		Order("MIN(created_at), emoji").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize reactions: %w", err)
+	}
+
+	for _, row := range rows {
+		summaries[row.MessageID] = append(summaries[row.MessageID], models.ReactionSummary{
+			Emoji:       row.Emoji,
+			Count:       row.Count,
+			ReactedByMe: row.Mine == 1,
+		})
+	}
+	return summaries, nil
+}
+
+// SumAttachmentSizeByTTRID returns the total attachment bytes on the TTR's
+// messages that haven't been deleted.
+func (r *messageRepository) SumAttachmentSizeByTTRID(ctx context.Context, ttrID uuid.UUID) (int64, error) {
+	return r.sumAttachmentSize(ctx, "ttr_id = ?", ttrID)
+}
+
+// SumAttachmentSizeByUserID returns the total attachment bytes on the user's
+// messages that haven't been deleted, across all TTRs.
+func (r *messageRepository) SumAttachmentSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return r.sumAttachmentSize(ctx, "user_id = ?", userID)
+}
+
+func (r *messageRepository) sumAttachmentSize(ctx context.Context, query string, id uuid.UUID) (int64, error) {
+	var total int64
+	if err := txOrDB(ctx, r.db).
+		Model(&models.Message{}).
+		Select("COALESCE(SUM(attachment_size), 0)").
+		Where(query, id).
+		Where("deleted = ?", false).
+		Scan(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to sum attachment sizes: %w", err)
+	}
+	return total, nil
+}
+
+// FindDeletedWithAttachments returns deleted messages whose attachment object
+// hasn't been removed from storage yet.
+func (r *messageRepository) FindDeletedWithAttachments(ctx context.Context, limit int) ([]*models.Message, error) {
+	var messages []*models.Message
+	if err := txOrDB(ctx, r.db).
+		Where("deleted = ? AND attachment_key IS NOT NULL", true).
+		Order("updated_at").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to find deleted attachments: %w", err)
+	}
+	return messages, nil
+}
+
+func (r *messageRepository) ClearAttachment(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).
+		Model(&models.Message{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attachment_key":          nil,
+			"attachment_name":         nil,
+			"attachment_content_type": nil,
+			"attachment_size":         0,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to clear attachment: %w", err)
+	}
+	return nil
+}
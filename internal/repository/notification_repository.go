@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
@@ -9,13 +11,17 @@ import (
 )
 
 type NotificationRepository interface {
-	Create(notification *models.Notification) error
-	FindByID(id uuid.UUID) (*models.Notification, error)
-	FindByUserID(userID uuid.UUID, limit int, offset int) ([]*models.Notification, error)
-	FindUnreadByUserID(userID uuid.UUID) ([]*models.Notification, error)
-	MarkAsRead(id uuid.UUID) error
-	MarkAllAsRead(userID uuid.UUID) error
-	Delete(id uuid.UUID) error
+	Create(ctx context.Context, notification *models.Notification) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Notification, error)
+	FindUnreadByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Notification, error)
+	CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	FindByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]*models.Notification, error)
+	FindRecentByTypeAndTarget(ctx context.Context, userID uuid.UUID, notificationType string, targetType string, targetID uuid.UUID, since time.Time) (*models.Notification, error)
+	UpdateDigest(ctx context.Context, id uuid.UUID, title string, message string, eventCount int) error
+	MarkAsRead(ctx context.Context, id uuid.UUID) error
+	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type notificationRepository struct {
@@ -26,16 +32,16 @@ func NewNotificationRepository(db *gorm.DB) NotificationRepository {
 	return &notificationRepository{db: db}
 }
 
-func (r *notificationRepository) Create(notification *models.Notification) error {
-	if err := r.db.Create(notification).Error; err != nil {
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	if err := txOrDB(ctx, r.db).Create(notification).Error; err != nil {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
 	return nil
 }
 
-func (r *notificationRepository) FindByID(id uuid.UUID) (*models.Notification, error) {
+func (r *notificationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Notification, error) {
 	var notification models.Notification
-	if err := r.db.Where("id = ?", id).First(&notification).Error; err != nil {
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&notification).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -44,9 +50,9 @@ func (r *notificationRepository) FindByID(id uuid.UUID) (*models.Notification, e
 	return &notification, nil
 }
 
-func (r *notificationRepository) FindByUserID(userID uuid.UUID, limit int, offset int) ([]*models.Notification, error) {
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.Notification, error) {
 	var notifications []*models.Notification
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Limit(limit).
@@ -57,9 +63,9 @@ func (r *notificationRepository) FindByUserID(userID uuid.UUID, limit int, offse
 	return notifications, nil
 }
 
-func (r *notificationRepository) FindUnreadByUserID(userID uuid.UUID) ([]*models.Notification, error) {
+func (r *notificationRepository) FindUnreadByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Notification, error) {
 	var notifications []*models.Notification
-	if err := r.db.
+	if err := txOrDB(ctx, r.db).
 		Where("user_id = ? AND is_read = ?", userID, false).
 		Order("created_at DESC").
 		Find(&notifications).Error; err != nil {
@@ -68,8 +74,64 @@ func (r *notificationRepository) FindUnreadByUserID(userID uuid.UUID) ([]*models
 	return notifications, nil
 }
 
-func (r *notificationRepository) MarkAsRead(id uuid.UUID) error {
-	if err := r.db.Model(&models.Notification{}).
+func (r *notificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// FindByTarget returns every notification about the given target, newest
+// first.
+func (r *notificationRepository) FindByTarget(ctx context.Context, targetType string, targetID uuid.UUID) ([]*models.Notification, error) {
+	var notifications []*models.Notification
+	if err := txOrDB(ctx, r.db).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("failed to find notifications by target: %w", err)
+	}
+	return notifications, nil
+}
+
+// FindRecentByTypeAndTarget returns userID's newest notification of the given
+// type about the target created at or after since, or nil if there is none.
+func (r *notificationRepository) FindRecentByTypeAndTarget(ctx context.Context, userID uuid.UUID, notificationType string, targetType string, targetID uuid.UUID, since time.Time) (*models.Notification, error) {
+	var notification models.Notification
+	if err := txOrDB(ctx, r.db).
+		Where("user_id = ? AND type = ? AND target_type = ? AND target_id = ? AND created_at >= ?", userID, notificationType, targetType, targetID, since).
+		Order("created_at DESC").
+		First(&notification).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find recent notification: %w", err)
+	}
+	return &notification, nil
+}
+
+// UpdateDigest rewrites a digest notification for eventCount events and marks
+// it unread again.
+func (r *notificationRepository) UpdateDigest(ctx context.Context, id uuid.UUID, title string, message string, eventCount int) error {
+	if err := txOrDB(ctx, r.db).Model(&models.Notification{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"title":       title,
+			"message":     message,
+			"event_count": eventCount,
+			"is_read":     false,
+			"read_at":     nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update digest notification: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Model(&models.Notification{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
 			"is_read": true,
@@ -80,8 +142,8 @@ func (r *notificationRepository) MarkAsRead(id uuid.UUID) error {
 	return nil
 }
 
-func (r *notificationRepository) MarkAllAsRead(userID uuid.UUID) error {
-	if err := r.db.Model(&models.Notification{}).
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Model(&models.Notification{}).
 		Where("user_id = ? AND is_read = ?", userID, false).
 		Updates(map[string]interface{}{
 			"is_read": true,
@@ -92,8 +154,8 @@ func (r *notificationRepository) MarkAllAsRead(userID uuid.UUID) error {
 	return nil
 }
 
-func (r *notificationRepository) Delete(id uuid.UUID) error {
-	if err := r.db.Delete(&models.Notification{}, id).Error; err != nil {
+func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Delete(&models.Notification{}, id).Error; err != nil {
 		return fmt.Errorf("failed to delete notification: %w", err)
 	}
 	return nil
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error)
+	Update(ctx context.Context, org *models.Organization) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	FindMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.OrganizationMember, error)
+	UpdateMember(ctx context.Context, member *models.OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error
+	GetMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrganizationMember, error)
+	CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error
+	FindInvitationByID(ctx context.Context, id uuid.UUID) (*models.OrganizationInvitation, error)
+	FindPendingInvitation(ctx context.Context, orgID uuid.UUID, email string) (*models.OrganizationInvitation, error)
+	FindPendingInvitationsByEmail(ctx context.Context, email string) ([]*models.OrganizationInvitation, error)
+	UpdateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	if err := txOrDB(ctx, r.db).Create(org).Error; err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&org).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find organization by ID: %w", err)
+	}
+	return &org, nil
+}
+
+// FindByUserID returns the organizations the user is a member of, by name.
+func (r *organizationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	if err := txOrDB(ctx, r.db).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC").
+		Find(&orgs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find organizations by user: %w", err)
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	if err := txOrDB(ctx, r.db).Save(org).Error; err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Delete(&models.Organization{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	if err := txOrDB(ctx, r.db).Create(member).Error; err != nil {
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) FindMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	if err := txOrDB(ctx, r.db).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find organization member: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *organizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	if err := txOrDB(ctx, r.db).
+		Model(&models.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", member.OrganizationID, member.UserID).
+		Update("role", member.Role).Error; err != nil {
+		return fmt.Errorf("failed to update organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).
+		Where("organization_id = ? AND user_id = ?", orgID, userID).
+		Delete(&models.OrganizationMember{}).Error; err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) GetMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrganizationMember, error) {
+	var members []*models.OrganizationMember
+	if err := txOrDB(ctx, r.db).
+		Preload("User", withDeletedUsers).
+		Where("organization_id = ?", orgID).
+		Order("joined_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("failed to get organization members: %w", err)
+	}
+	return members, nil
+}
+
+func (r *organizationRepository) CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	if err := txOrDB(ctx, r.db).Create(invitation).Error; err != nil {
+		return fmt.Errorf("failed to create organization invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *organizationRepository) FindInvitationByID(ctx context.Context, id uuid.UUID) (*models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	if err := txOrDB(ctx, r.db).
+		Preload("Organization").
+		Where("id = ?", id).
+		First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find organization invitation by ID: %w", err)
+	}
+	return &invitation, nil
+}
+
+func (r *organizationRepository) FindPendingInvitation(ctx context.Context, orgID uuid.UUID, email string) (*models.OrganizationInvitation, error) {
+	var invitation models.OrganizationInvitation
+	if err := txOrDB(ctx, r.db).
+		Where("organization_id = ? AND email = ? AND status = ?", orgID, email, models.OrganizationInvitationStatusPending).
+		First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find pending organization invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+// FindPendingInvitationsByEmail returns pending invitations addressed to the
+// email, skipping organizations that have since been deleted.
+func (r *organizationRepository) FindPendingInvitationsByEmail(ctx context.Context, email string) ([]*models.OrganizationInvitation, error) {
+	var invitations []*models.OrganizationInvitation
+	if err := txOrDB(ctx, r.db).
+		Preload("Organization").
+		Joins("JOIN organizations ON organizations.id = organization_invitations.organization_id AND organizations.deleted_at IS NULL").
+		Where("organization_invitations.email = ? AND organization_invitations.status = ?", email, models.OrganizationInvitationStatusPending).
+		Order("organization_invitations.created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, fmt.Errorf("failed to find organization invitations by email: %w", err)
+	}
+	return invitations, nil
+}
+
+func (r *organizationRepository) UpdateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	if err := txOrDB(ctx, r.db).Save(invitation).Error; err != nil {
+		return fmt.Errorf("failed to update organization invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	Create(ctx context.Context, message *models.OutboxMessage) error
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxMessage, error)
+	MarkDone(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error
+	FindByStatus(ctx context.Context, status string, limit int) ([]*models.OutboxMessage, error)
+	DeleteDoneBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Create joins the transaction carried by ctx, so the message is only
+// delivered if the change it belongs to commits.
+func (r *outboxRepository) Create(ctx context.Context, message *models.OutboxMessage) error {
+	if message.Status == "" {
+		message.Status = models.OutboxStatusPending
+	}
+	if message.NextAttemptAt.IsZero() {
+		message.NextAttemptAt = time.Now()
+	}
+	if err := txOrDB(ctx, r.db).Create(message).Error; err != nil {
+		return createError("create outbox message", err)
+	}
+	return nil
+}
+
+// Claim returns up to limit PENDING messages due at now, oldest first, and
+// pushes each one's next attempt lease into the future so no other
+// dispatcher takes it meanwhile. A message whose dispatcher dies before
+// marking it is due again once the lease is up.
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.OutboxMessage, error) {
+	db := txOrDB(ctx, r.db)
+
+	var due []*models.OutboxMessage
+	if err := db.
+		Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+		Order("next_attempt_at ASC, created_at ASC").
+		Limit(limit).
+		Find(&due).Error; err != nil {
+		return nil, fmt.Errorf("failed to find due outbox messages: %w", err)
+	}
+
+	leasedUntil := now.Add(lease)
+	claimed := make([]*models.OutboxMessage, 0, len(due))
+	for _, message := range due {
+		// Another dispatcher that claimed the message first has moved its
+		// next attempt past now.
+		result := db.Model(&models.OutboxMessage{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", message.ID, models.OutboxStatusPending, now).
+			Update("next_attempt_at", leasedUntil)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim outbox message: %w", result.Error)
+		}
+		if result.RowsAffected == 1 {
+			message.NextAttemptAt = leasedUntil
+			claimed = append(claimed, message)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *outboxRepository) MarkDone(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       models.OutboxStatusDone,
+		"processed_at": time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message done: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt. The message is tried again at
+// nextAttemptAt, or not at all when dead is set.
+func (r *outboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	updates := map[string]interface{}{
+		"attempts":        attempts,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+	}
+	if dead {
+		updates["status"] = models.OutboxStatusDead
+		updates["processed_at"] = time.Now()
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.OutboxMessage{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox message failed: %w", err)
+	}
+	return nil
+}
+
+// FindByStatus returns up to limit messages with the status, oldest first.
+func (r *outboxRepository) FindByStatus(ctx context.Context, status string, limit int) ([]*models.OutboxMessage, error) {
+	var messages []*models.OutboxMessage
+	if err := txOrDB(ctx, r.db).
+		Where("status = ?", status).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to find outbox messages: %w", err)
+	}
+	return messages, nil
+}
+
+// DeleteDoneBefore deletes up to limit DONE messages delivered before
+// cutoff and returns how many it deleted. DEAD messages are kept.
+func (r *outboxRepository) DeleteDoneBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	db := txOrDB(ctx, r.db)
+	done := db.Model(&models.OutboxMessage{}).
+		Select("id").
+		Where("status = ? AND processed_at < ?", models.OutboxStatusDone, cutoff).
+		Order("processed_at ASC").
+		Limit(limit)
+
+	result := db.Where("id IN (?)", done).Delete(&models.OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete outbox messages: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
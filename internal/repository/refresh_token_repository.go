@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -11,10 +12,10 @@ import (
 )
 
 type RefreshTokenRepository interface {
-	Create(token *models.RefreshToken) error
-	FindByTokenHash(tokenHash string) (*models.RefreshToken, error)
-	RevokeByUserID(userID uuid.UUID) error
-	DeleteExpired() error
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteExpired(ctx context.Context) error
 }
 
 type refreshTokenRepository struct {
@@ -25,16 +26,16 @@ func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
 	return &refreshTokenRepository{db: db}
 }
 
-func (r *refreshTokenRepository) Create(token *models.RefreshToken) error {
-	if err := r.db.Create(token).Error; err != nil {
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	if err := txOrDB(ctx, r.db).Create(token).Error; err != nil {
 		return fmt.Errorf("failed to create refresh token: %w", err)
 	}
 	return nil
 }
 
-func (r *refreshTokenRepository) FindByTokenHash(tokenHash string) (*models.RefreshToken, error) {
+func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	var token models.RefreshToken
-	if err := r.db.Where("token_hash = ?", tokenHash).Preload("User").First(&token).Error; err != nil {
+	if err := txOrDB(ctx, r.db).Where("token_hash = ?", tokenHash).Preload("User").First(&token).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -43,8 +44,8 @@ func (r *refreshTokenRepository) FindByTokenHash(tokenHash string) (*models.Refr
 	return &token, nil
 }
 
-func (r *refreshTokenRepository) RevokeByUserID(userID uuid.UUID) error {
-	if err := r.db.Model(&models.RefreshToken{}).
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Model(&models.RefreshToken{}).
 		Where("user_id = ? AND revoked = false", userID).
 		Update("revoked", true).Error; err != nil {
 		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
@@ -52,8 +53,8 @@ func (r *refreshTokenRepository) RevokeByUserID(userID uuid.UUID) error {
 	return nil
 }
 
-func (r *refreshTokenRepository) DeleteExpired() error {
-	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{}).Error; err != nil {
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
+	if err := txOrDB(ctx, r.db).Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{}).Error; err != nil {
 		return fmt.Errorf("failed to delete expired tokens: %w", err)
 	}
 	return nil
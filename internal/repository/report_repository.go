@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	// Create wraps ErrDuplicate when the reporter already has an open
+	// report against the target.
+	Create(ctx context.Context, report *models.Report) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Report, error)
+	FindByStatus(ctx context.Context, status string, limit int, offset int) ([]*models.Report, error)
+	CountByReporterSince(ctx context.Context, reporterID uuid.UUID, since time.Time) (int64, error)
+	// Close records the report's Status, Resolution, ResolvedByUserID and
+	// ResolvedAt, reporting false when it was no longer open.
+	Close(ctx context.Context, report *models.Report) (bool, error)
+}
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	if err := txOrDB(ctx, r.db).Create(report).Error; err != nil {
+		return createError("create report", err)
+	}
+	return nil
+}
+
+func (r *reportRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Report, error) {
+	var report models.Report
+	if err := txOrDB(ctx, r.db).
+		Preload("Reporter", withDeletedUsers).
+		Where("id = ?", id).
+		First(&report).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find report: %w", err)
+	}
+	return &report, nil
+}
+
+// FindByStatus returns reports with the status, or every report for an empty
+// status, oldest first so the queue is worked in order.
+func (r *reportRepository) FindByStatus(ctx context.Context, status string, limit int, offset int) ([]*models.Report, error) {
+	query := txOrDB(ctx, r.db).Preload("Reporter", withDeletedUsers)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var reports []*models.Report
+	if err := query.
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find reports: %w", err)
+	}
+	return reports, nil
+}
+
+// CountByReporterSince counts the reports the user filed at or after since,
+// whatever their status.
+func (r *reportRepository) CountByReporterSince(ctx context.Context, reporterID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.Report{}).
+		Where("reporter_user_id = ? AND created_at >= ?", reporterID, since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count reports: %w", err)
+	}
+	return count, nil
+}
+
+func (r *reportRepository) Close(ctx context.Context, report *models.Report) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.Report{}).
+		Where("id = ? AND status = ?", report.ID, models.ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":              report.Status,
+			"resolution":          report.Resolution,
+			"resolved_by_user_id": report.ResolvedByUserID,
+			"resolved_at":         report.ResolvedAt,
+			"updated_at":          time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to close report: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
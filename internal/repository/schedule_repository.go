@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// ScheduleEntry is a TTR's line on a tee sheet. Confirmed counts its confirmed
+// players and guests, Taken the slots its active players and guests take.
+type ScheduleEntry struct {
+	TTRID            uuid.UUID
+	TeeDate          time.Time
+	TeeTime          time.Time `gorm:"serializer:timeofday"`
+	Status           models.TTRStatus
+	MaxPlayers       int
+	CaptainUserID    uuid.UUID
+	CaptainFirstName string
+	CaptainLastName  string
+	CaptainDeleted   bool
+	Confirmed        int
+	Taken            int
+}
+
+// ScheduleRepository reads tee sheets: the TTRs at a course in a period
+// running from its from date up to but not including its to date. It loads
+// only what a sheet shows rather than whole rosters.
+type ScheduleRepository interface {
+	FindSchedule(ctx context.Context, viewerID uuid.UUID, courseName string, from time.Time, to time.Time) ([]ScheduleEntry, error)
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+// FindSchedule returns the TTRs viewerID can find at the course, matched
+// regardless of case, by tee time. Cancelled and deleted TTRs are left out.
+func (r *scheduleRepository) FindSchedule(ctx context.Context, viewerID uuid.UUID, courseName string, from time.Time, to time.Time) ([]ScheduleEntry, error) {
+	var entries []ScheduleEntry
+	if err := txOrDB(ctx, r.db).Model(&models.TTR{}).
+		Select(`ttrs.id AS ttr_id, ttrs.tee_date, ttrs.tee_time, ttrs.status, ttrs.max_players, ttrs.captain_user_id,
+	captain.first_name AS captain_first_name, captain.last_name AS captain_last_name,
+	(captain.id IS NULL OR captain.deleted_at IS NOT NULL) AS captain_deleted,
+	(SELECT COUNT(*) FROM ttr_players p WHERE p.ttr_id = ttrs.id AND p.status = @confirmed) + (SELECT COUNT(*) FROM ttr_guests g WHERE g.ttr_id = ttrs.id) AS confirmed,
+	(SELECT COUNT(*) FROM ttr_players p WHERE p.ttr_id = ttrs.id AND p.status IN @active) + (SELECT COUNT(*) FROM ttr_guests g WHERE g.ttr_id = ttrs.id) AS taken`,
+			map[string]interface{}{
+				"confirmed": models.TTRPlayerStatusConfirmed,
+				"active":    models.ActivePlayerStatuses(),
+			}).
+		Joins("LEFT JOIN users captain ON captain.id = ttrs.captain_user_id").
+		Where("LOWER(ttrs.course_name) = LOWER(?) AND ttrs.tee_date >= ? AND ttrs.tee_date < ? AND ttrs.status <> ?",
+			courseName, from, to, models.TTRStatusCancelled).
+		Where(ttrVisibleTo, map[string]interface{}{
+			"viewer":  viewerID,
+			"pending": models.InvitationStatusPending,
+			"public":  models.TTRVisibilityPublic,
+			"friends": models.TTRVisibilityFriends,
+		}).
+		Order("ttrs.tee_date, ttrs.tee_time, ttrs.id").
+		Scan(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find schedule: %w", err)
+	}
+	return entries, nil
+}
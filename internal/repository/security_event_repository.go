@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SecurityEventRepository interface {
+	Create(ctx context.Context, event *models.SecurityEvent) error
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.SecurityEvent, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	SeeDevice(ctx context.Context, userID uuid.UUID, fingerprint string, at time.Time) (bool, error)
+	HasDevices(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+func (r *securityEventRepository) Create(ctx context.Context, event *models.SecurityEvent) error {
+	if err := txOrDB(ctx, r.db).Create(event).Error; err != nil {
+		return createError("create security event", err)
+	}
+	return nil
+}
+
+// FindByUserID returns the user's events newest first.
+func (r *securityEventRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.SecurityEvent, error) {
+	var events []*models.SecurityEvent
+	if err := txOrDB(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to find security events: %w", err)
+	}
+	return events, nil
+}
+
+// DeleteBefore deletes up to limit events from before cutoff, oldest first,
+// and returns how many it deleted.
+func (r *securityEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	db := txOrDB(ctx, r.db)
+	expired := db.Model(&models.SecurityEvent{}).
+		Select("id").
+		Where("created_at < ?", cutoff).
+		Order("created_at ASC").
+		Limit(limit)
+
+	result := db.Where("id IN (?)", expired).Delete(&models.SecurityEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete security events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SeeDevice records that the user was seen on the device with fingerprint
+// at at. It returns true when they hadn't been seen on it before.
+func (r *securityEventRepository) SeeDevice(ctx context.Context, userID uuid.UUID, fingerprint string, at time.Time) (bool, error) {
+	db := txOrDB(ctx, r.db)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.KnownDevice{
+		UserID:      userID,
+		Fingerprint: fingerprint,
+		FirstSeenAt: at,
+		LastSeenAt:  at,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record device: %w", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+	if err := db.Model(&models.KnownDevice{}).
+		Where("user_id = ? AND fingerprint = ?", userID, fingerprint).
+		Update("last_seen_at", at).Error; err != nil {
+		return false, fmt.Errorf("failed to record device: %w", err)
+	}
+	return false, nil
+}
+
+// HasDevices reports whether the user has been seen on any device.
+func (r *securityEventRepository) HasDevices(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.KnownDevice{}).
+		Where("user_id = ?", userID).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to find devices: %w", err)
+	}
+	return count > 0, nil
+}
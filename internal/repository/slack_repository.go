@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type SlackRepository interface {
+	FindAccount(ctx context.Context, teamID string, slackUserID string) (*models.SlackAccount, error)
+	LinkAccount(ctx context.Context, account *models.SlackAccount) error
+	CreateLinkCode(ctx context.Context, code *models.SlackLinkCode) error
+	ConsumeLinkCode(ctx context.Context, code string, now time.Time) (*models.SlackLinkCode, error)
+}
+
+type slackRepository struct {
+	db *gorm.DB
+}
+
+func NewSlackRepository(db *gorm.DB) SlackRepository {
+	return &slackRepository{db: db}
+}
+
+func (r *slackRepository) FindAccount(ctx context.Context, teamID string, slackUserID string) (*models.SlackAccount, error) {
+	var account models.SlackAccount
+	if err := txOrDB(ctx, r.db).Where("team_id = ? AND slack_user_id = ?", teamID, slackUserID).First(&account).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find slack account: %w", err)
+	}
+	return &account, nil
+}
+
+// LinkAccount links the Slack user to account.UserID, replacing any account
+// they were linked to before.
+func (r *slackRepository) LinkAccount(ctx context.Context, account *models.SlackAccount) error {
+	return txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("team_id = ? AND slack_user_id = ?", account.TeamID, account.SlackUserID).Delete(&models.SlackAccount{}).Error; err != nil {
+			return fmt.Errorf("failed to unlink slack account: %w", err)
+		}
+		if err := tx.Create(account).Error; err != nil {
+			return createError("link slack account", err)
+		}
+		return nil
+	})
+}
+
+func (r *slackRepository) CreateLinkCode(ctx context.Context, code *models.SlackLinkCode) error {
+	if err := txOrDB(ctx, r.db).Create(code).Error; err != nil {
+		return createError("create slack link code", err)
+	}
+	return nil
+}
+
+// ConsumeLinkCode deletes the code and returns it, or returns nil when it
+// doesn't exist or expired before now. Deleting it first means a code
+// sent twice at once is only consumed once.
+func (r *slackRepository) ConsumeLinkCode(ctx context.Context, code string, now time.Time) (*models.SlackLinkCode, error) {
+	var linkCode models.SlackLinkCode
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("code = ?", code).First(&linkCode).Error; err != nil {
+			return err
+		}
+		result := tx.Where("code = ?", code).Delete(&models.SlackLinkCode{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to consume slack link code: %w", err)
+	}
+	if !now.Before(linkCode.ExpiresAt) {
+		return nil, nil
+	}
+	return &linkCode, nil
+}
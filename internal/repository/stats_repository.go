@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// StatsRepository runs the aggregate queries behind the admin stats. Deleted
+// users and TTRs are left out of every count.
+type StatsRepository interface {
+	CountUsers(ctx context.Context) (int64, error)
+	CountUsersCreatedSince(ctx context.Context, since time.Time) (int64, error)
+	CountUsersLoggedInSince(ctx context.Context, since time.Time) (int64, error)
+	CountTTRsByStatus(ctx context.Context) (map[models.TTRStatus]int64, error)
+	CountInvitationsByStatus(ctx context.Context) (map[models.InvitationStatus]int64, error)
+	AverageFillRate(ctx context.Context, status models.TTRStatus) (float64, error)
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+func (r *statsRepository) CountUsers(ctx context.Context) (int64, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *statsRepository) CountUsersCreatedSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("created_at >= ?", since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count new users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *statsRepository) CountUsersLoggedInSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("last_login_at >= ?", since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count active users: %w", err)
+	}
+	return count, nil
+}
+
+func (r *statsRepository) CountTTRsByStatus(ctx context.Context) (map[models.TTRStatus]int64, error) {
+	var rows []struct {
+		Status models.TTRStatus
+		Count  int64
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.TTR{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count ttrs by status: %w", err)
+	}
+
+	counts := make(map[models.TTRStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *statsRepository) CountInvitationsByStatus(ctx context.Context) (map[models.InvitationStatus]int64, error) {
+	var rows []struct {
+		Status models.InvitationStatus
+		Count  int64
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.Invitation{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count invitations by status: %w", err)
+	}
+
+	counts := make(map[models.InvitationStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// AverageFillRate returns the mean share of slots taken by confirmed players
+// across the TTRs in status, from 0 to 1. It is 0 when there are none.
+func (r *statsRepository) AverageFillRate(ctx context.Context, status models.TTRStatus) (float64, error) {
+	var rate sql.NullFloat64
+	if err := txOrDB(ctx, r.db).Model(&models.TTR{}).
+		Select("AVG(CAST((SELECT COUNT(*) FROM ttr_players WHERE ttr_players.ttr_id = ttrs.id AND ttr_players.status = ?) AS FLOAT) / ttrs.max_players)", models.TTRPlayerStatusConfirmed).
+		Where("ttrs.status = ? AND ttrs.max_players > 0", status).
+		Row().Scan(&rate); err != nil {
+		return 0, fmt.Errorf("failed to average fill rate: %w", err)
+	}
+	return rate.Float64, nil
+}
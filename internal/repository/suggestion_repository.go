@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// SuggestionRepository finds users a TTR's captain might want to invite.
+// Every query leaves out the asking user, deleted users and anyone already
+// on the TTR: its captain, co-captains, players and pending invitees.
+type SuggestionRepository interface {
+	SharedRounds(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, before time.Time, limit int) ([]SharedRoundCount, error)
+	OrganizationMates(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, limit int) ([]uuid.UUID, error)
+	PlayingOn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, day string, limit int) ([]uuid.UUID, error)
+}
+
+// SharedRoundCount is how many past rounds a user played with the asking
+// user.
+type SharedRoundCount struct {
+	UserID uuid.UUID
+	Rounds int
+}
+
+type suggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuggestionRepository(db *gorm.DB) SuggestionRepository {
+	return &suggestionRepository{db: db}
+}
+
+// suggestionCandidates restricts users u to those who may be suggested for a
+// TTR. Its parameters are the asking user, then the TTR four times and the
+// pending invitation status.
+const suggestionCandidates = `
+u.deleted_at IS NULL AND u.id <> ?
+AND u.id NOT IN (SELECT captain_user_id FROM ttrs WHERE id = ?)
+AND u.id NOT IN (SELECT user_id FROM ttr_co_captains WHERE ttr_id = ?)
+AND u.id NOT IN (SELECT user_id FROM ttr_players WHERE ttr_id = ?)
+AND u.id NOT IN (SELECT invitee_user_id FROM invitations WHERE ttr_id = ? AND status = ?)`
+
+func candidateArgs(ttrID uuid.UUID, userID uuid.UUID) []interface{} {
+	return []interface{}{userID, ttrID, ttrID, ttrID, ttrID, models.InvitationStatusPending}
+}
+
+// SharedRounds counts, per user, the rounds before the given date that they
+// and userID both played in, leaving out cancelled and deleted TTRs. Users
+// who played most often with userID come first.
+func (r *suggestionRepository) SharedRounds(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, before time.Time, limit int) ([]SharedRoundCount, error) {
+	query := `
+SELECT u.id AS user_id, COUNT(*) AS rounds
+FROM users u
+JOIN ttr_players theirs ON theirs.user_id = u.id
+JOIN ttr_players mine ON mine.ttr_id = theirs.ttr_id AND mine.user_id = ?
+JOIN ttrs t ON t.id = theirs.ttr_id
+WHERE t.tee_date < ? AND t.status <> ? AND t.deleted_at IS NULL AND ` + suggestionCandidates + `
+GROUP BY u.id, u.first_name, u.last_name
+ORDER BY rounds DESC, u.first_name, u.last_name
+LIMIT ?`
+
+	args := append([]interface{}{userID, before, models.TTRStatusCancelled}, candidateArgs(ttrID, userID)...)
+	args = append(args, limit)
+
+	var counts []SharedRoundCount
+	if err := txOrDB(ctx, r.db).Raw(query, args...).Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to count shared rounds: %w", err)
+	}
+	return counts, nil
+}
+
+// OrganizationMates returns users who share an organization with userID,
+// ordered by name. Deleted organizations don't count.
+func (r *suggestionRepository) OrganizationMates(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	query := `
+SELECT u.id
+FROM users u
+WHERE u.id IN (
+	SELECT theirs.user_id FROM organization_members theirs
+	JOIN organization_members mine ON mine.organization_id = theirs.organization_id
+	JOIN organizations o ON o.id = theirs.organization_id
+	WHERE mine.user_id = ? AND o.deleted_at IS NULL
+) AND ` + suggestionCandidates + `
+ORDER BY u.first_name, u.last_name
+LIMIT ?`
+
+	args := append([]interface{}{userID}, candidateArgs(ttrID, userID)...)
+	args = append(args, limit)
+
+	var ids []uuid.UUID
+	if err := txOrDB(ctx, r.db).Raw(query, args...).Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find organization mates: %w", err)
+	}
+	return ids, nil
+}
+
+// PlayingOn returns users who usually play on the given weekday, ordered by
+// name.
+func (r *suggestionRepository) PlayingOn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, day string, limit int) ([]uuid.UUID, error) {
+	query := `
+SELECT u.id
+FROM users u
+JOIN user_playing_days d ON d.user_id = u.id AND d.day = ?
+WHERE ` + suggestionCandidates + `
+ORDER BY u.first_name, u.last_name
+LIMIT ?`
+
+	args := append([]interface{}{day}, candidateArgs(ttrID, userID)...)
+	args = append(args, limit)
+
+	var ids []uuid.UUID
+	if err := txOrDB(ctx, r.db).Raw(query, args...).Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users playing on %s: %w", day, err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type TournamentRepository interface {
+	Create(ctx context.Context, tournament *models.Tournament) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Tournament, error)
+	Update(ctx context.Context, tournament *models.Tournament) error
+	CompleteMatch(ctx context.Context, match *models.Match) (bool, error)
+	UpdateMatch(ctx context.Context, match *models.Match) error
+}
+
+type tournamentRepository struct {
+	db *gorm.DB
+}
+
+func NewTournamentRepository(db *gorm.DB) TournamentRepository {
+	return &tournamentRepository{db: db}
+}
+
+// Create inserts the tournament together with its bracket matches.
+func (r *tournamentRepository) Create(ctx context.Context, tournament *models.Tournament) error {
+	if err := txOrDB(ctx, r.db).Create(tournament).Error; err != nil {
+		return fmt.Errorf("failed to create tournament: %w", err)
+	}
+	return nil
+}
+
+// FindByID returns the tournament with its matches in bracket order.
+func (r *tournamentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Tournament, error) {
+	var tournament models.Tournament
+	if err := txOrDB(ctx, r.db).
+		Preload("Matches", func(db *gorm.DB) *gorm.DB {
+			return db.Order("round ASC, position ASC")
+		}).
+		Where("id = ?", id).
+		First(&tournament).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find tournament by ID: %w", err)
+	}
+	return &tournament, nil
+}
+
+func (r *tournamentRepository) Update(ctx context.Context, tournament *models.Tournament) error {
+	if err := txOrDB(ctx, r.db).Omit("Matches").Save(tournament).Error; err != nil {
+		return fmt.Errorf("failed to update tournament: %w", err)
+	}
+	return nil
+}
+
+// CompleteMatch saves the match's result if it was still scheduled. It
+// reports false when someone else completed the match first.
+func (r *tournamentRepository) CompleteMatch(ctx context.Context, match *models.Match) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.Match{}).
+		Where("id = ? AND status = ?", match.ID, models.MatchStatusScheduled).
+		Updates(map[string]interface{}{
+			"winner_user_id": match.WinnerUserID,
+			"status":         match.Status,
+			"walkover":       match.Walkover,
+			"completed_at":   match.CompletedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to complete match: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *tournamentRepository) UpdateMatch(ctx context.Context, match *models.Match) error {
+	if err := txOrDB(ctx, r.db).Save(match).Error; err != nil {
+		return fmt.Errorf("failed to update match: %w", err)
+	}
+	return nil
+}
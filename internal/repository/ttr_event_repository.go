@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxAppendAttempts bounds how often Append retries when a concurrent writer
+// took the sequence number it picked.
+const maxAppendAttempts = 5
+
+type TTREventRepository interface {
+	Append(ctx context.Context, event *models.TTREvent) error
+	FindSince(ctx context.Context, ttrID uuid.UUID, after int64, limit int) ([]*models.TTREvent, error)
+	DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+}
+
+type ttrEventRepository struct {
+	db *gorm.DB
+}
+
+func NewTTREventRepository(db *gorm.DB) TTREventRepository {
+	return &ttrEventRepository{db: db}
+}
+
+// Append stores the event with the next sequence number of its TTR, which it
+// sets on event. Two writers racing for the same number are told apart by the
+// unique index, and the loser tries again with the next one. Retrying needs
+// the failed insert to leave the connection usable, so Append shouldn't run
+// inside a transaction.
+func (r *ttrEventRepository) Append(ctx context.Context, event *models.TTREvent) error {
+	var err error
+	for attempt := 0; attempt < maxAppendAttempts; attempt++ {
+		var last int64
+		if err := txOrDB(ctx, r.db).Model(&models.TTREvent{}).
+			Where("ttr_id = ?", event.TTRID).
+			Select("COALESCE(MAX(sequence), 0)").
+			Scan(&last).Error; err != nil {
+			return fmt.Errorf("failed to find last ttr event: %w", err)
+		}
+
+		event.Sequence = last + 1
+		err = txOrDB(ctx, r.db).Create(event).Error
+		if err == nil {
+			return nil
+		}
+		err = createError("append ttr event", err)
+		if !errors.Is(err, ErrDuplicate) {
+			return err
+		}
+	}
+	return err
+}
+
+// FindSince returns up to limit of the TTR's events with a sequence number
+// above after, in order.
+func (r *ttrEventRepository) FindSince(ctx context.Context, ttrID uuid.UUID, after int64, limit int) ([]*models.TTREvent, error) {
+	var events []*models.TTREvent
+	if err := txOrDB(ctx, r.db).
+		Where("ttr_id = ? AND sequence > ?", ttrID, after).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttr events: %w", err)
+	}
+	return events, nil
+}
+
+// DeleteBefore deletes up to limit events recorded before cutoff, oldest
+// first, and returns how many it deleted. Each TTR's latest event is kept
+// whatever its age, so Append never hands out a sequence number again.
+func (r *ttrEventRepository) DeleteBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	db := txOrDB(ctx, r.db)
+	expired := db.Model(&models.TTREvent{}).
+		Select("id").
+		Where("created_at < ?", cutoff).
+		Where("sequence < (SELECT MAX(latest.sequence) FROM ttr_events AS latest WHERE latest.ttr_id = ttr_events.ttr_id)").
+		Order("created_at ASC").
+		Limit(limit)
+
+	result := db.Where("id IN (?)", expired).Delete(&models.TTREvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete ttr events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
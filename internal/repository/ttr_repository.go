@@ -1,30 +1,63 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TTRRepository interface {
-	Create(ttr *models.TTR) error
-	FindByID(id uuid.UUID) (*models.TTR, error)
-	FindAll(limit int, offset int, status string) ([]*models.TTR, error)
-	Update(ttr *models.TTR) error
-	Delete(id uuid.UUID) error
-	FindUpcomingByUserID(userID uuid.UUID) ([]*models.TTR, error)
-	FindPastByUserID(userID uuid.UUID) ([]*models.TTR, error)
-	AddCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error
-	RemoveCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error
-	IsCoCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, error)
-	AddPlayer(ttrID uuid.UUID, userID uuid.UUID, status string) error
-	RemovePlayer(ttrID uuid.UUID, userID uuid.UUID) error
-	GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, error)
-	IsPlayer(ttrID uuid.UUID, userID uuid.UUID) (bool, error)
+	Create(ctx context.Context, ttr *models.TTR) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.TTR, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.TTR, error)
+	FindAll(ctx context.Context, viewerID uuid.UUID, filter TTRSearchFilter) ([]*models.TTR, error)
+	Update(ctx context.Context, ttr *models.TTR) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status models.TTRStatus) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*models.TTR, error)
+	FindDeletedByCaptain(ctx context.Context, captainID uuid.UUID, since time.Time) ([]*models.TTR, error)
+	Restore(ctx context.Context, id uuid.UUID, status models.TTRStatus) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	FindUpcomingByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error)
+	FindPastByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error)
+	FindRSVPDeadlinePassed(ctx context.Context, now time.Time) ([]*models.TTR, error)
+	FindByCaptainCourseNear(ctx context.Context, captainID uuid.UUID, courseName string, from time.Time, to time.Time) (*models.TTR, error)
+	FindCheckInSummaryDue(ctx context.Context, from time.Time, to time.Time) ([]*models.TTR, error)
+	MarkCheckInSummarySent(ctx context.Context, id uuid.UUID, sentAt time.Time) (bool, error)
+	AddCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) error
+	UpdateCoCaptainPermissions(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) (bool, error)
+	RemoveCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error)
+	IsCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error)
+	AddPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error
+	AddPlayerIfOpen(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error
+	RemovePlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) error
+	UpdatePlayer(ctx context.Context, player *models.TTRPlayer) error
+	UpdatePlayerCheckIn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, checkedInAt time.Time) (bool, error)
+	RemoveMember(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, newInviterID uuid.UUID) (bool, error)
+	GetPlayers(ctx context.Context, ttrID uuid.UUID) ([]*models.TTRPlayer, error)
+	IsPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error)
+	CountPlayers(ctx context.Context, ttrIDs []uuid.UUID) (map[uuid.UUID]models.PlayerCounts, error)
+	AddGuest(ctx context.Context, guest *models.TTRGuest) error
+	RemoveGuest(ctx context.Context, ttrID uuid.UUID, guestID uuid.UUID) (bool, error)
+	ReplaceSlots(ctx context.Context, ttrID uuid.UUID, slots []models.TeeSlot) error
+}
+
+// withDeletedUsers preloads soft-deleted users too. A deleted player still
+// holds their slot on the roster, so responses need the row to render it.
+func withDeletedUsers(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// orderedSlots preloads a TTR's tee slots in tee order.
+func orderedSlots(db *gorm.DB) *gorm.DB {
+	return db.Order("number")
 }
 
 type ttrRepository struct {
@@ -35,20 +68,22 @@ func NewTTRRepository(db *gorm.DB) TTRRepository {
 	return &ttrRepository{db: db}
 }
 
-func (r *ttrRepository) Create(ttr *models.TTR) error {
-	if err := r.db.Create(ttr).Error; err != nil {
+func (r *ttrRepository) Create(ctx context.Context, ttr *models.TTR) error {
+	if err := txOrDB(ctx, r.db).Create(ttr).Error; err != nil {
 		return fmt.Errorf("failed to create ttr: %w", err)
 	}
 	return nil
 }
 
-func (r *ttrRepository) FindByID(id uuid.UUID) (*models.TTR, error) {
+func (r *ttrRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
 	var ttr models.TTR
-	if err := r.db.
-		Preload("CreatedByUser").
-		Preload("CaptainUser").
-		Preload("CoCaptains.User").
-		Preload("Players.User").
+	if err := txOrDB(ctx, r.db).
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
 		Where("id = ?", id).
 		First(&ttr).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -59,22 +94,113 @@ func (r *ttrRepository) FindByID(id uuid.UUID) (*models.TTR, error) {
 	return &ttr, nil
 }
 
-func (r *ttrRepository) FindAll(limit int, offset int, status string) ([]*models.TTR, error) {
+// FindByIDs returns the TTRs among ids, preloaded like FindByID, in no
+// particular order. IDs of missing or deleted TTRs are skipped.
+func (r *ttrRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.TTR, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 	var ttrs []*models.TTR
-	query := r.db.
-		Preload("CreatedByUser").
-		Preload("CaptainUser").
-		Preload("CoCaptains.User").
-		Preload("Players.User")
+	if err := txOrDB(ctx, r.db).
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where("id IN ?", ids).
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttrs by ID: %w", err)
+	}
+	return ttrs, nil
+}
+
+// ttrVisibleTo restricts ttrs to those @viewer can find: TTRs they take part
+// in as captain, co-captain, player or pending invitee, and otherwise PUBLIC
+// TTRs and FRIENDS TTRs whose captain is their friend.
+// Organization TTRs stay limited to the organization's members.
+const ttrVisibleTo = `(
+ttrs.captain_user_id = @viewer
+OR ttrs.id IN (SELECT ttr_id FROM ttr_co_captains WHERE user_id = @viewer)
+OR ttrs.id IN (SELECT ttr_id FROM ttr_players WHERE user_id = @viewer)
+OR ttrs.id IN (SELECT ttr_id FROM invitations WHERE invitee_user_id = @viewer AND status = @pending)
+OR ((ttrs.organization_id IS NULL OR ttrs.organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = @viewer))
+	AND (ttrs.visibility = @public OR (ttrs.visibility = @friends AND ttrs.captain_user_id IN (
+		SELECT addressee_user_id FROM friendships WHERE requester_user_id = @viewer AND accepted_at IS NOT NULL
+		UNION SELECT requester_user_id FROM friendships WHERE addressee_user_id = @viewer AND accepted_at IS NOT NULL
+	)))))`
+
+// closedTTRStatuses are the statuses of TTRs whose invitations can no longer
+// be answered.
+var closedTTRStatuses = []models.TTRStatus{models.TTRStatusCancelled, models.TTRStatusCompleted}
+
+// TTRSearchFilter narrows FindAll. Empty fields don't filter. Mine keeps the
+// TTRs the viewer takes part in that way; invited TTRs are open ones with a
+// pending invitation. TeeFrom and TeeTo bound the tee date, both inclusive.
+// Holes and MaxGreenFeeCents leave out TTRs whose captain didn't give them.
+type TTRSearchFilter struct {
+	Status           models.TTRStatus
+	Mine             models.TTRParticipation
+	TeeFrom          *time.Time
+	TeeTo            *time.Time
+	Holes            *int
+	MaxGreenFeeCents *int
+	Limit            int
+	Offset           int
+}
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+// FindAll returns a page of TTRs visible to viewerID, checking visibility and
+// the viewer's relationships in the same query. The Mine filter joins the
+// table that records the participation, as FindUpcomingByUserID does; each
+// holds a user once per TTR, so the join adds no duplicates.
+func (r *ttrRepository) FindAll(ctx context.Context, viewerID uuid.UUID, filter TTRSearchFilter) ([]*models.TTR, error) {
+	var ttrs []*models.TTR
+	query := txOrDB(ctx, r.db).
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where(ttrVisibleTo, map[string]interface{}{
+			"viewer":  viewerID,
+			"pending": models.InvitationStatusPending,
+			"public":  models.TTRVisibilityPublic,
+			"friends": models.TTRVisibilityFriends,
+		})
+
+	switch filter.Mine {
+	case models.TTRParticipationCaptain:
+		query = query.Where("ttrs.captain_user_id = ?", viewerID)
+	case models.TTRParticipationCoCaptain:
+		query = query.Joins("JOIN ttr_co_captains mine ON mine.ttr_id = ttrs.id AND mine.user_id = ?", viewerID)
+	case models.TTRParticipationPlayer:
+		query = query.Joins("JOIN ttr_players mine ON mine.ttr_id = ttrs.id AND mine.user_id = ?", viewerID)
+	case models.TTRParticipationInvited:
+		query = query.Joins("JOIN invitations mine ON mine.ttr_id = ttrs.id AND mine.invitee_user_id = ? AND mine.status = ?",
+			viewerID, models.InvitationStatusPending).
+			Where("ttrs.status NOT IN ?", closedTTRStatuses)
+	}
+	if filter.Status != "" {
+		query = query.Where("ttrs.status = ?", filter.Status)
+	}
+	if filter.TeeFrom != nil {
+		query = query.Where("ttrs.tee_date >= ?", *filter.TeeFrom)
+	}
+	if filter.TeeTo != nil {
+		query = query.Where("ttrs.tee_date <= ?", *filter.TeeTo)
+	}
+	if filter.Holes != nil {
+		query = query.Where("ttrs.holes = ?", *filter.Holes)
+	}
+	if filter.MaxGreenFeeCents != nil {
+		query = query.Where("ttrs.green_fee_cents <= ?", *filter.MaxGreenFeeCents)
 	}
 
 	if err := query.
-		Limit(limit).
-		Offset(offset).
-		Order("tee_date ASC, tee_time ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Order("ttrs.tee_date ASC, ttrs.tee_time ASC").
 		Find(&ttrs).Error; err != nil {
 		return nil, fmt.Errorf("failed to find all ttrs: %w", err)
 	}
@@ -82,92 +208,340 @@ func (r *ttrRepository) FindAll(limit int, offset int, status string) ([]*models
 	return ttrs, nil
 }
 
-func (r *ttrRepository) Update(ttr *models.TTR) error {
-	if err := r.db.Save(ttr).Error; err != nil {
+func (r *ttrRepository) Update(ctx context.Context, ttr *models.TTR) error {
+	if err := txOrDB(ctx, r.db).Save(ttr).Error; err != nil {
 		return fmt.Errorf("failed to update ttr: %w", err)
 	}
 	return nil
 }
 
-func (r *ttrRepository) Delete(id uuid.UUID) error {
-	if err := r.db.Delete(&models.TTR{}, id).Error; err != nil {
+// UpdateStatus sets only the TTR's status, leaving the rest of the row as
+// whoever else is writing it left it.
+func (r *ttrRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	if err := txOrDB(ctx, r.db).
+		Model(&models.TTR{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update ttr status: %w", err)
+	}
+	return nil
+}
+
+func (r *ttrRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).Delete(&models.TTR{}, id).Error; err != nil {
 		return fmt.Errorf("failed to delete ttr: %w", err)
 	}
 	return nil
 }
 
-func (r *ttrRepository) FindUpcomingByUserID(userID uuid.UUID) ([]*models.TTR, error) {
+// FindDeletedByID returns the TTR only if it has been soft-deleted.
+func (r *ttrRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
+	var ttr models.TTR
+	if err := txOrDB(ctx, r.db).
+		Unscoped().
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&ttr).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find deleted ttr by ID: %w", err)
+	}
+	return &ttr, nil
+}
+
+// FindDeletedByCaptain returns the TTRs captained by captainID that were
+// soft-deleted at or after since, most recently deleted first.
+func (r *ttrRepository) FindDeletedByCaptain(ctx context.Context, captainID uuid.UUID, since time.Time) ([]*models.TTR, error) {
 	var ttrs []*models.TTR
+	if err := txOrDB(ctx, r.db).
+		Unscoped().
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where("captain_user_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", captainID, since).
+		Order("deleted_at DESC").
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find deleted ttrs: %w", err)
+	}
+	return ttrs, nil
+}
 
-	now := time.Now()
+// Restore undoes a soft delete, putting the TTR back in the given status.
+func (r *ttrRepository) Restore(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	if err := txOrDB(ctx, r.db).
+		Unscoped().
+		Model(&models.TTR{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at":        nil,
+			"status":            status,
+			"pre_delete_status": nil,
+			"updated_at":        time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to restore ttr: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeletedBefore permanently deletes up to limit TTRs soft-deleted before
+// cutoff, oldest first, together with their roster, invitations, invite
+// links, chat and the notifications about them. Tournaments and matches that
+// pointed at a purged TTR keep their rows and lose the link. Everything
+// happens in one transaction; it returns the number of TTRs purged.
+func (r *ttrRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	var purged int64
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Model(&models.TTR{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Order("deleted_at ASC").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find ttrs to purge: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		messages := tx.Model(&models.Message{}).Select("id").Where("ttr_id IN ?", ids)
+		if err := tx.Where("message_id IN (?)", messages).Delete(&models.MessageReaction{}).Error; err != nil {
+			return fmt.Errorf("failed to purge message reactions: %w", err)
+		}
+		for _, model := range []interface{}{&models.Message{}, &models.MessageRead{}, &models.Invitation{}, &models.InviteLink{}, &models.TTRPlayer{}, &models.TTRGuest{}, &models.TeeSlot{}, &models.TTRCoCaptain{}, &models.TTREvent{}} {
+			if err := tx.Where("ttr_id IN ?", ids).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to purge ttr dependents: %w", err)
+			}
+		}
+		if err := tx.Where("target_type = ? AND target_id IN ?", "ttr", ids).Delete(&models.Notification{}).Error; err != nil {
+			return fmt.Errorf("failed to purge ttr notifications: %w", err)
+		}
+		for _, model := range []interface{}{&models.Tournament{}, &models.Match{}} {
+			if err := tx.Unscoped().Model(model).Where("ttr_id IN ?", ids).Update("ttr_id", nil).Error; err != nil {
+				return fmt.Errorf("failed to unlink purged ttrs: %w", err)
+			}
+		}
 
-	if err := r.db.
-		Preload("CreatedByUser").
-		Preload("CaptainUser").
-		Preload("CoCaptains.User").
-		Preload("Players.User").
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.TTR{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge ttrs: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// FindUpcomingByUserID returns the TTRs userID captains, co-captains or plays
+// in that tee off no earlier than grace before now, soonest first. A round
+// stays upcoming while it is being played; FindPastByUserID returns the rest.
+func (r *ttrRepository) FindUpcomingByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
+	var candidates []*models.TTR
+	cutoff := now.Add(-grace)
+
+	// Tee times are stored apart from tee dates, so the query narrows down
+	// to the dates and the exact tee time is checked here.
+	if err := txOrDB(ctx, r.db).
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
 		Joins("LEFT JOIN ttr_players ON ttrs.id = ttr_players.ttr_id").
 		Joins("LEFT JOIN ttr_co_captains ON ttrs.id = ttr_co_captains.ttr_id").
 		Where("ttrs.tee_date >= ? AND (ttrs.captain_user_id = ? OR ttr_players.user_id = ? OR ttr_co_captains.user_id = ?)",
-			now, userID, userID, userID).
+			truncateToDate(cutoff), userID, userID, userID).
 		Group("ttrs.id").
 		Order("ttrs.tee_date ASC, ttrs.tee_time ASC").
-		Find(&ttrs).Error; err != nil {
+		Find(&candidates).Error; err != nil {
 		return nil, fmt.Errorf("failed to find upcoming ttrs: %w", err)
 	}
 
+	ttrs := make([]*models.TTR, 0, len(candidates))
+	for _, ttr := range candidates {
+		if !ttr.TeeDateTime().Before(cutoff) {
+			ttrs = append(ttrs, ttr)
+		}
+	}
 	return ttrs, nil
 }
 
-func (r *ttrRepository) FindPastByUserID(userID uuid.UUID) ([]*models.TTR, error) {
-	var ttrs []*models.TTR
-
-	now := time.Now()
-
-	if err := r.db.
-		Preload("CreatedByUser").
-		Preload("CaptainUser").
-		Preload("CoCaptains.User").
-		Preload("Players.User").
+// FindPastByUserID returns the TTRs userID captains, co-captains or plays in
+// that teed off more than grace before now, latest first.
+func (r *ttrRepository) FindPastByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
+	var candidates []*models.TTR
+	cutoff := now.Add(-grace)
+
+	if err := txOrDB(ctx, r.db).
+		Preload("CreatedByUser", withDeletedUsers).
+		Preload("CaptainUser", withDeletedUsers).
+		Preload("CoCaptains.User", withDeletedUsers).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
 		Joins("LEFT JOIN ttr_players ON ttrs.id = ttr_players.ttr_id").
 		Joins("LEFT JOIN ttr_co_captains ON ttrs.id = ttr_co_captains.ttr_id").
-		Where("ttrs.tee_date < ? AND (ttrs.captain_user_id = ? OR ttr_players.user_id = ? OR ttr_co_captains.user_id = ?)",
-			now, userID, userID, userID).
+		Where("ttrs.tee_date <= ? AND (ttrs.captain_user_id = ? OR ttr_players.user_id = ? OR ttr_co_captains.user_id = ?)",
+			truncateToDate(cutoff), userID, userID, userID).
 		Group("ttrs.id").
 		Order("ttrs.tee_date DESC, ttrs.tee_time DESC").
-		Find(&ttrs).Error; err != nil {
+		Find(&candidates).Error; err != nil {
 		return nil, fmt.Errorf("failed to find past ttrs: %w", err)
 	}
 
+	ttrs := make([]*models.TTR, 0, len(candidates))
+	for _, ttr := range candidates {
+		if ttr.TeeDateTime().Before(cutoff) {
+			ttrs = append(ttrs, ttr)
+		}
+	}
+	return ttrs, nil
+}
+
+// FindRSVPDeadlinePassed returns TTRs whose RSVP deadline is before now and
+// that haven't been closed for RSVPs yet.
+func (r *ttrRepository) FindRSVPDeadlinePassed(ctx context.Context, now time.Time) ([]*models.TTR, error) {
+	var ttrs []*models.TTR
+
+	if err := txOrDB(ctx, r.db).
+		Preload("Players").
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where("rsvp_deadline IS NOT NULL AND rsvp_deadline < ? AND rsvp_closed_at IS NULL", now).
+		Find(&ttrs).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttrs past their RSVP deadline: %w", err)
+	}
+
+	return ttrs, nil
+}
+
+// FindByCaptainCourseNear returns a TTR captained by captainID at courseName
+// that isn't cancelled and tees off between from and to, inclusive, or nil
+// when there is none. Course names match regardless of case.
+func (r *ttrRepository) FindByCaptainCourseNear(ctx context.Context, captainID uuid.UUID, courseName string, from time.Time, to time.Time) (*models.TTR, error) {
+	var candidates []*models.TTR
+
+	// Tee times are stored apart from tee dates, so the query narrows down
+	// to the dates and the exact window is checked here.
+	if err := txOrDB(ctx, r.db).
+		Where("captain_user_id = ? AND LOWER(course_name) = LOWER(?) AND status <> ?", captainID, courseName, models.TTRStatusCancelled).
+		Where("tee_date BETWEEN ? AND ?", truncateToDate(from), truncateToDate(to)).
+		Order("created_at").
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttrs near tee time: %w", err)
+	}
+
+	for _, ttr := range candidates {
+		if teeAt := ttr.TeeDateTime(); !teeAt.Before(from) && !teeAt.After(to) {
+			return ttr, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindCheckInSummaryDue returns the open or confirmed TTRs teeing off between
+// from and to, inclusive, whose captain hasn't been sent the check-in summary
+// yet, with their players.
+func (r *ttrRepository) FindCheckInSummaryDue(ctx context.Context, from time.Time, to time.Time) ([]*models.TTR, error) {
+	var candidates []*models.TTR
+
+	if err := txOrDB(ctx, r.db).
+		Preload("Players.User", withDeletedUsers).
+		Preload("Guests").
+		Preload("Slots", orderedSlots).
+		Where("status IN ? AND check_in_summary_at IS NULL", []models.TTRStatus{models.TTRStatusOpen, models.TTRStatusConfirmed}).
+		Where("tee_date BETWEEN ? AND ?", truncateToDate(from), truncateToDate(to)).
+		Order("tee_date ASC, tee_time ASC").
+		Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to find ttrs due a check-in summary: %w", err)
+	}
+
+	ttrs := make([]*models.TTR, 0, len(candidates))
+	for _, ttr := range candidates {
+		if teeAt := ttr.TeeDateTime(); !teeAt.Before(from) && !teeAt.After(to) {
+			ttrs = append(ttrs, ttr)
+		}
+	}
 	return ttrs, nil
 }
 
-func (r *ttrRepository) AddCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error {
+// MarkCheckInSummarySent records that the TTR's check-in summary went out at
+// sentAt and reports whether this call recorded it, so the summary is sent
+// once however many callers race to send it.
+func (r *ttrRepository) MarkCheckInSummarySent(ctx context.Context, id uuid.UUID, sentAt time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Model(&models.TTR{}).
+		Where("id = ? AND check_in_summary_at IS NULL", id).
+		Update("check_in_summary_at", sentAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark check-in summary sent: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// truncateToDate returns t's calendar date at midnight UTC, as tee dates are
+// stored.
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (r *ttrRepository) AddCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) error {
 	coCaptain := &models.TTRCoCaptain{
-		TTRID:  ttrID,
-		UserID: userID,
+		TTRID:       ttrID,
+		UserID:      userID,
+		Permissions: strings.Join(permissions, ","),
 	}
 
-	if err := r.db.Create(coCaptain).Error; err != nil {
-		return fmt.Errorf("failed to add co-captain: %w", err)
+	if err := txOrDB(ctx, r.db).Create(coCaptain).Error; err != nil {
+		return createError("add co-captain", err)
 	}
 
 	return nil
 }
 
-func (r *ttrRepository) RemoveCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error {
-	if err := r.db.
+// UpdateCoCaptainPermissions replaces the co-captain's permissions and
+// reports whether userID is a co-captain of the TTR.
+func (r *ttrRepository) UpdateCoCaptainPermissions(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Model(&models.TTRCoCaptain{}).
 		Where("ttr_id = ? AND user_id = ?", ttrID, userID).
-		Delete(&models.TTRCoCaptain{}).Error; err != nil {
-		return fmt.Errorf("failed to remove co-captain: %w", err)
+		Update("permissions", strings.Join(permissions, ","))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update co-captain permissions: %w", result.Error)
 	}
 
-	return nil
+	return result.RowsAffected > 0, nil
+}
+
+// RemoveCoCaptain reports whether userID was a co-captain of the TTR, so
+// removing one twice is no error.
+func (r *ttrRepository) RemoveCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Where("ttr_id = ? AND user_id = ?", ttrID, userID).
+		Delete(&models.TTRCoCaptain{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove co-captain: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
 }
 
-func (r *ttrRepository) IsCoCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+func (r *ttrRepository) IsCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.TTRCoCaptain{}).
+	if err := txOrDB(ctx, r.db).Model(&models.TTRCoCaptain{}).
 		Where("ttr_id = ? AND user_id = ?", ttrID, userID).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check co-captain status: %w", err)
@@ -176,22 +550,66 @@ func (r *ttrRepository) IsCoCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, er
 	return count > 0, nil
 }
 
-func (r *ttrRepository) AddPlayer(ttrID uuid.UUID, userID uuid.UUID, status string) error {
+func (r *ttrRepository) AddPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
 	player := &models.TTRPlayer{
 		TTRID:  ttrID,
 		UserID: userID,
 		Status: status,
 	}
 
-	if err := r.db.Create(player).Error; err != nil {
-		return fmt.Errorf("failed to add player: %w", err)
+	if err := txOrDB(ctx, r.db).Create(player).Error; err != nil {
+		return createError("add player", err)
 	}
 
 	return nil
 }
 
-func (r *ttrRepository) RemovePlayer(ttrID uuid.UUID, userID uuid.UUID) error {
-	if err := r.db.
+// AddPlayerIfOpen adds the player like AddPlayer, but only while the TTR has
+// an open slot, and returns ErrTTRFull when it hasn't. The TTR row stays
+// locked until the player is added, so concurrent adds can't overfill it.
+func (r *ttrRepository) AddPlayerIfOpen(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
+	return txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenSlot(tx, ttrID); err != nil {
+			return err
+		}
+		player := &models.TTRPlayer{
+			TTRID:  ttrID,
+			UserID: userID,
+			Status: status,
+		}
+		if err := tx.Create(player).Error; err != nil {
+			return createError("add player", err)
+		}
+		return nil
+	})
+}
+
+// lockOpenSlot locks the TTR row for the rest of tx and returns ErrTTRFull
+// when its active players and guests already take every slot.
+func lockOpenSlot(tx *gorm.DB, ttrID uuid.UUID) error {
+	var ttr models.TTR
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "max_players").
+		Where("id = ?", ttrID).
+		First(&ttr).Error; err != nil {
+		return fmt.Errorf("failed to lock ttr: %w", err)
+	}
+
+	var players, guests int64
+	if err := tx.Model(&models.TTRPlayer{}).Where("ttr_id = ? AND status IN ?", ttrID, models.ActivePlayerStatuses()).Count(&players).Error; err != nil {
+		return fmt.Errorf("failed to count players: %w", err)
+	}
+	if err := tx.Model(&models.TTRGuest{}).Where("ttr_id = ?", ttrID).Count(&guests).Error; err != nil {
+		return fmt.Errorf("failed to count guests: %w", err)
+	}
+	if players+guests >= int64(ttr.MaxPlayers) {
+		return ErrTTRFull
+	}
+	return nil
+}
+
+func (r *ttrRepository) RemovePlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) error {
+	if err := txOrDB(ctx, r.db).
 		Where("ttr_id = ? AND user_id = ?", ttrID, userID).
 		Delete(&models.TTRPlayer{}).Error; err != nil {
 		return fmt.Errorf("failed to remove player: %w", err)
@@ -200,11 +618,70 @@ func (r *ttrRepository) RemovePlayer(ttrID uuid.UUID, userID uuid.UUID) error {
 	return nil
 }
 
-func (r *ttrRepository) GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
+// UpdatePlayer saves the player's status, notes, group number and score.
+func (r *ttrRepository) UpdatePlayer(ctx context.Context, player *models.TTRPlayer) error {
+	if err := txOrDB(ctx, r.db).
+		Model(&models.TTRPlayer{}).
+		Where("ttr_id = ? AND user_id = ?", player.TTRID, player.UserID).
+		Select("status", "notes", "group_number", "score").
+		Updates(player).Error; err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
+	}
+
+	return nil
+}
+
+// UpdatePlayerCheckIn checks the player in at checkedInAt unless they already
+// are, and reports whether this call checked them in.
+func (r *ttrRepository) UpdatePlayerCheckIn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, checkedInAt time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Model(&models.TTRPlayer{}).
+		Where("ttr_id = ? AND user_id = ? AND checked_in_at IS NULL", ttrID, userID).
+		Update("checked_in_at", checkedInAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check in player: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// RemoveMember drops userID from the TTR's players and co-captains and hands
+// their pending invitations over to newInviterID, all in one transaction. It
+// reports whether userID was a player or co-captain, so leaving twice is no
+// error.
+func (r *ttrRepository) RemoveMember(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, newInviterID uuid.UUID) (bool, error) {
+	removed := false
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		players := tx.Where("ttr_id = ? AND user_id = ?", ttrID, userID).Delete(&models.TTRPlayer{})
+		if players.Error != nil {
+			return fmt.Errorf("failed to remove player: %w", players.Error)
+		}
+
+		coCaptains := tx.Where("ttr_id = ? AND user_id = ?", ttrID, userID).Delete(&models.TTRCoCaptain{})
+		if coCaptains.Error != nil {
+			return fmt.Errorf("failed to remove co-captain: %w", coCaptains.Error)
+		}
+		removed = players.RowsAffected+coCaptains.RowsAffected > 0
+
+		if err := tx.Model(&models.Invitation{}).
+			Where("ttr_id = ? AND inviter_user_id = ? AND status = ?", ttrID, userID, models.InvitationStatusPending).
+			Update("inviter_user_id", newInviterID).Error; err != nil {
+			return fmt.Errorf("failed to reassign invitations: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return removed, nil
+}
+
+func (r *ttrRepository) GetPlayers(ctx context.Context, ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
 	var players []*models.TTRPlayer
 
-	if err := r.db.
-		Preload("User").
+	if err := txOrDB(ctx, r.db).
+		Preload("User", withDeletedUsers).
 		Where("ttr_id = ?", ttrID).
 		Find(&players).Error; err != nil {
 		return nil, fmt.Errorf("failed to get players: %w", err)
@@ -213,9 +690,9 @@ func (r *ttrRepository) GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, error)
 	return players, nil
 }
 
-func (r *ttrRepository) IsPlayer(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+func (r *ttrRepository) IsPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.TTRPlayer{}).
+	if err := txOrDB(ctx, r.db).Model(&models.TTRPlayer{}).
 		Where("ttr_id = ? AND user_id = ?", ttrID, userID).
 		Count(&count).Error; err != nil {
 		return false, fmt.Errorf("failed to check player status: %w", err)
@@ -223,3 +700,82 @@ func (r *ttrRepository) IsPlayer(ttrID uuid.UUID, userID uuid.UUID) (bool, error
 
 	return count > 0, nil
 }
+
+// CountPlayers counts each TTR's players that take a slot, all of them and the
+// confirmed ones, with its guests counted as confirmed players. TTRs without
+// such players or guests are left out of the map.
+func (r *ttrRepository) CountPlayers(ctx context.Context, ttrIDs []uuid.UUID) (map[uuid.UUID]models.PlayerCounts, error) {
+	counts := make(map[uuid.UUID]models.PlayerCounts, len(ttrIDs))
+	if len(ttrIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TTRID     uuid.UUID
+		Total     int
+		Confirmed int
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.TTRPlayer{}).
+		Select("ttr_id, COUNT(*) AS total, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS confirmed", models.TTRPlayerStatusConfirmed).
+		Where("ttr_id IN ? AND status IN ?", ttrIDs, models.ActivePlayerStatuses()).
+		Group("ttr_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count players: %w", err)
+	}
+
+	for _, row := range rows {
+		counts[row.TTRID] = models.PlayerCounts{Total: row.Total, Confirmed: row.Confirmed}
+	}
+
+	var guests []struct {
+		TTRID uuid.UUID
+		Total int
+	}
+	if err := txOrDB(ctx, r.db).Model(&models.TTRGuest{}).
+		Select("ttr_id, COUNT(*) AS total").
+		Where("ttr_id IN ?", ttrIDs).
+		Group("ttr_id").
+		Scan(&guests).Error; err != nil {
+		return nil, fmt.Errorf("failed to count guests: %w", err)
+	}
+	for _, row := range guests {
+		count := counts[row.TTRID]
+		count.Total += row.Total
+		count.Confirmed += row.Total
+		counts[row.TTRID] = count
+	}
+	return counts, nil
+}
+
+func (r *ttrRepository) AddGuest(ctx context.Context, guest *models.TTRGuest) error {
+	if err := txOrDB(ctx, r.db).Create(guest).Error; err != nil {
+		return createError("add guest", err)
+	}
+	return nil
+}
+
+// RemoveGuest removes the guest from the TTR, reporting whether it was on it.
+func (r *ttrRepository) RemoveGuest(ctx context.Context, ttrID uuid.UUID, guestID uuid.UUID) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Where("id = ? AND ttr_id = ?", guestID, ttrID).
+		Delete(&models.TTRGuest{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove guest: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceSlots swaps the TTR's tee slots for slots, which may be empty.
+func (r *ttrRepository) ReplaceSlots(ctx context.Context, ttrID uuid.UUID, slots []models.TeeSlot) error {
+	db := txOrDB(ctx, r.db)
+	if err := db.Where("ttr_id = ?", ttrID).Delete(&models.TeeSlot{}).Error; err != nil {
+		return fmt.Errorf("failed to remove tee slots: %w", err)
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+	if err := db.Create(&slots).Error; err != nil {
+		return createError("create tee slots", err)
+	}
+	return nil
+}
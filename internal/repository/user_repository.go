@@ -1,20 +1,84 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
-	FindByID(id uuid.UUID) (*models.User, error)
-	FindByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
-	Search(query string, limit int, offset int) ([]*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	MarkEmailUndeliverable(ctx context.Context, email string) error
+	FindInactiveSince(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error)
+	FindWarnedBefore(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error)
+	MarkInactivityWarned(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	FindDigestDue(ctx context.Context, before time.Time, limit int) ([]*models.User, error)
+	MarkDigestSent(ctx context.Context, id uuid.UUID, before time.Time, at time.Time) (bool, error)
+	Deactivate(ctx context.Context, id uuid.UUID, warnedBefore time.Time, at time.Time) (bool, error)
+	SetPlayingDays(ctx context.Context, userID uuid.UUID, days []string) error
+	Search(ctx context.Context, filter UserSearchFilter) ([]*models.User, error)
+	FindCaptainedBy(ctx context.Context, captainID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error)
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	Merge(ctx context.Context, sourceID uuid.UUID, targetID uuid.UUID, at time.Time) (*UserMerge, error)
+	// Block is a no-op when blockerID already blocks blockedID.
+	Block(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error
+	// Unblock reports false when blockerID didn't block blockedID.
+	Unblock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error)
+	IsBlocked(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error)
+	FindBlocked(ctx context.Context, blockerID uuid.UUID) ([]*models.User, error)
+	// RequestFriendship accepts addresseeID's pending request to requesterID
+	// if there is one, and otherwise records a request from requesterID to
+	// addresseeID. Requesting again changes nothing.
+	RequestFriendship(ctx context.Context, requesterID uuid.UUID, addresseeID uuid.UUID, at time.Time) (*models.Friendship, error)
+	// RemoveFriendship reports false when there was neither a friendship nor
+	// a request between the two users.
+	RemoveFriendship(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error)
+	AreFriends(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error)
+	FindFriends(ctx context.Context, userID uuid.UUID) ([]*models.User, error)
+	FindFriendRequests(ctx context.Context, addresseeID uuid.UUID) ([]*models.User, error)
+}
+
+// UserMerge counts what Merge moved from the source account to the target.
+// The Dropped counts are the source's rows left out because the target
+// already had their like, and Invitations between the two accounts.
+type UserMerge struct {
+	CaptainedTTRs        int64
+	CoCaptaincies        int64
+	DroppedCoCaptaincies int64
+	RosterSpots          int64
+	DroppedRosterSpots   int64
+	Invitations          int64
+	DroppedInvitations   int64
+	Notifications        int64
+	Messages             int64
+	RevokedRefreshTokens int64
+}
+
+// UserSearchFilter selects users for Search. Email, when set, is matched
+// exactly and Name is ignored; otherwise Name is matched against first and
+// last names of users who can be found by name. Zero UUIDs disable the
+// exclusions.
+type UserSearchFilter struct {
+	Email         string
+	Name          string
+	ExcludeUserID uuid.UUID
+	ExcludeTTRID  uuid.UUID
+	Limit         int
+	Offset        int
 }
 
 type userRepository struct {
@@ -25,16 +89,28 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	if err := r.db.Create(user).Error; err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	if err := txOrDB(ctx, r.db).Create(user).Error; err != nil {
+		return createError("create user", err)
 	}
 	return nil
 }
 
-func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := txOrDB(ctx, r.db).Preload("PlayingDays").Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user by ID: %w", err)
+	}
+	return &user, nil
+}
+
+// FindByIDUnscoped is FindByID including soft-deleted users.
+func (r *userRepository) FindByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("id = ?", id).First(&user).Error; err != nil {
+	if err := txOrDB(ctx, r.db).Unscoped().Preload("PlayingDays").Where("id = ?", id).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -43,9 +119,24 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+// FindByIDs returns the users with the given IDs that exist, in no
+// particular order.
+func (r *userRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+	users := []*models.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := txOrDB(ctx, r.db).Preload("PlayingDays").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users by ID: %w", err)
+	}
+	return users, nil
+}
+
+// FindByEmail looks the user up by normalized email, so any spelling of their
+// address finds them.
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := txOrDB(ctx, r.db).Where("normalized_email = ?", emailnorm.Normalize(email)).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -54,24 +145,537 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *models.User) error {
-	if err := r.db.Save(user).Error; err != nil {
+// Update saves the user's own columns; playing days are changed with
+// SetPlayingDays.
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	if err := txOrDB(ctx, r.db).Omit("PlayingDays").Save(user).Error; err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	return nil
 }
 
-func (r *userRepository) Search(query string, limit int, offset int) ([]*models.User, error) {
+// UpdateLastLogin records when the user last logged in, without touching
+// updated_at. Logging in withdraws any inactivity warning.
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if err := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_login_at": at, "inactivity_warned_at": nil}).Error; err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailUndeliverable flags the account with email, if there is one, as
+// no longer receiving mail.
+func (r *userRepository) MarkEmailUndeliverable(ctx context.Context, email string) error {
+	if err := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("normalized_email = ?", emailnorm.Normalize(email)).
+		UpdateColumn("email_undeliverable", true).Error; err != nil {
+		return fmt.Errorf("failed to mark email undeliverable: %w", err)
+	}
+	return nil
+}
+
+// withoutUpcomingTTRs leaves out users who captain or are on the roster of a
+// TTR still to be played on or after the date it is given.
+const withoutUpcomingTTRs = `NOT EXISTS (SELECT 1 FROM ttrs WHERE ttrs.deleted_at IS NULL AND ttrs.status IN ? AND ttrs.tee_date >= ?
+AND (ttrs.captain_user_id = users.id OR EXISTS (SELECT 1 FROM ttr_players WHERE ttr_players.ttr_id = ttrs.id AND ttr_players.user_id = users.id)))`
+
+// FindInactiveSince returns up to limit users who haven't logged in, or
+// signed up if they never have, since cutoff and haven't been warned yet,
+// longest inactive first. Users with a TTR from from on are left out.
+func (r *userRepository) FindInactiveSince(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
+	var users []*models.User
+	if err := txOrDB(ctx, r.db).
+		Where("COALESCE(last_login_at, created_at) < ? AND inactivity_warned_at IS NULL", cutoff).
+		Where(withoutUpcomingTTRs, liveTTRStatuses, from).
+		Order("COALESCE(last_login_at, created_at) ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find inactive users: %w", err)
+	}
+	return users, nil
+}
+
+// FindWarnedBefore returns up to limit users warned of inactivity before
+// cutoff who haven't logged in since, earliest warned first. Users with a
+// TTR from from on are left out.
+func (r *userRepository) FindWarnedBefore(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
 	var users []*models.User
-	searchPattern := "%" + query + "%"
+	if err := txOrDB(ctx, r.db).
+		Where("inactivity_warned_at < ?", cutoff).
+		Where(withoutUpcomingTTRs, liveTTRStatuses, from).
+		Order("inactivity_warned_at ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find warned users: %w", err)
+	}
+	return users, nil
+}
+
+// MarkInactivityWarned records that the user was warned at at. It returns
+// false, changing nothing, when they already were.
+func (r *userRepository) MarkInactivityWarned(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND inactivity_warned_at IS NULL", id).
+		UpdateColumn("inactivity_warned_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark inactivity warning: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
 
-	if err := r.db.
-		Where("first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ?", searchPattern, searchPattern, searchPattern).
+// FindDigestDue returns up to limit users who get the weekly digest and
+// haven't been handled by a digest run since before, by ID. Users whose
+// address bounced are left out.
+func (r *userRepository) FindDigestDue(ctx context.Context, before time.Time, limit int) ([]*models.User, error) {
+	var users []*models.User
+	if err := txOrDB(ctx, r.db).
+		Where("notify_no_weekly_digest = ? AND email_undeliverable = ?", false, false).
+		Where("digest_sent_at IS NULL OR digest_sent_at < ?", before).
+		Order("id").
 		Limit(limit).
-		Offset(offset).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users due a digest: %w", err)
+	}
+	return users, nil
+}
+
+// MarkDigestSent records that a digest run handled the user at at. It
+// returns false, changing nothing, when a run already did since before.
+func (r *userRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, before time.Time, at time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND (digest_sent_at IS NULL OR digest_sent_at < ?)", id, before).
+		UpdateColumn("digest_sent_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark digest sent: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Deactivate soft-deletes the user if they were warned before warnedBefore
+// and haven't logged in since. It returns false, changing nothing, when they
+// weren't or are already deleted.
+func (r *userRepository) Deactivate(ctx context.Context, id uuid.UUID, warnedBefore time.Time, at time.Time) (bool, error) {
+	result := txOrDB(ctx, r.db).Model(&models.User{}).
+		Where("id = ? AND inactivity_warned_at < ?", id, warnedBefore).
+		UpdateColumn("deleted_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to deactivate user: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// SetPlayingDays replaces the user's playing days with days.
+func (r *userRepository) SetPlayingDays(ctx context.Context, userID uuid.UUID, days []string) error {
+	return txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserPlayingDay{}).Error; err != nil {
+			return fmt.Errorf("failed to clear playing days: %w", err)
+		}
+		if len(days) == 0 {
+			return nil
+		}
+
+		rows := make([]models.UserPlayingDay, 0, len(days))
+		for _, day := range days {
+			rows = append(rows, models.UserPlayingDay{UserID: userID, Day: day})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			return fmt.Errorf("failed to set playing days: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) Block(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	block := &models.UserBlock{BlockerUserID: blockerID, BlockedUserID: blockedID}
+	if err := txOrDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error; err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) Unblock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Where("blocker_user_id = ? AND blocked_user_id = ?", blockerID, blockedID).
+		Delete(&models.UserBlock{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to unblock user: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) IsBlocked(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.UserBlock{}).
+		Where("blocker_user_id = ? AND blocked_user_id = ?", blockerID, blockedID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FindBlocked returns the users blockerID blocked, most recently blocked
+// first. Deleted users are left out.
+func (r *userRepository) FindBlocked(ctx context.Context, blockerID uuid.UUID) ([]*models.User, error) {
+	var users []*models.User
+	if err := txOrDB(ctx, r.db).
+		Joins("JOIN user_blocks ON user_blocks.blocked_user_id = users.id").
+		Where("user_blocks.blocker_user_id = ?", blockerID).
+		Order("user_blocks.created_at DESC, users.id").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find blocked users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *userRepository) RequestFriendship(ctx context.Context, requesterID uuid.UUID, addresseeID uuid.UUID, at time.Time) (*models.Friendship, error) {
+	var friendship models.Friendship
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("requester_user_id = ? AND addressee_user_id = ?", addresseeID, requesterID).
+			First(&friendship).Error
+		if err == nil {
+			if friendship.Accepted() {
+				return nil
+			}
+			friendship.AcceptedAt = &at
+			if err := tx.Model(&friendship).UpdateColumn("accepted_at", at).Error; err != nil {
+				return fmt.Errorf("failed to accept friend request: %w", err)
+			}
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to find friend request: %w", err)
+		}
+
+		request := &models.Friendship{RequesterUserID: requesterID, AddresseeUserID: addresseeID, CreatedAt: at}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(request).Error; err != nil {
+			return fmt.Errorf("failed to request friendship: %w", err)
+		}
+		if err := tx.Where("requester_user_id = ? AND addressee_user_id = ?", requesterID, addresseeID).First(&friendship).Error; err != nil {
+			return fmt.Errorf("failed to find friend request: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &friendship, nil
+}
+
+func (r *userRepository) RemoveFriendship(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	result := txOrDB(ctx, r.db).
+		Where("(requester_user_id = ? AND addressee_user_id = ?) OR (requester_user_id = ? AND addressee_user_id = ?)", userID, otherUserID, otherUserID, userID).
+		Delete(&models.Friendship{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove friendship: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *userRepository) AreFriends(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	var count int64
+	if err := txOrDB(ctx, r.db).Model(&models.Friendship{}).
+		Where("((requester_user_id = ? AND addressee_user_id = ?) OR (requester_user_id = ? AND addressee_user_id = ?)) AND accepted_at IS NOT NULL", userID, otherUserID, otherUserID, userID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check friendship: %w", err)
+	}
+	return count > 0, nil
+}
+
+// FindFriends returns the user's friends, most recent friendship first.
+// Deleted users are left out.
+func (r *userRepository) FindFriends(ctx context.Context, userID uuid.UUID) ([]*models.User, error) {
+	var users []*models.User
+	if err := txOrDB(ctx, r.db).
+		Joins("JOIN friendships ON (friendships.requester_user_id = ? AND friendships.addressee_user_id = users.id) OR (friendships.addressee_user_id = ? AND friendships.requester_user_id = users.id)", userID, userID).
+		Where("friendships.accepted_at IS NOT NULL").
+		Order("friendships.accepted_at DESC, users.id").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find friends: %w", err)
+	}
+	return users, nil
+}
+
+// FindFriendRequests returns the users whose friend requests to addresseeID
+// are waiting for an answer, most recent request first. Deleted users are
+// left out.
+func (r *userRepository) FindFriendRequests(ctx context.Context, addresseeID uuid.UUID) ([]*models.User, error) {
+	var users []*models.User
+	if err := txOrDB(ctx, r.db).
+		Joins("JOIN friendships ON friendships.requester_user_id = users.id").
+		Where("friendships.addressee_user_id = ? AND friendships.accepted_at IS NULL", addresseeID).
+		Order("friendships.created_at DESC, users.id").
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find friend requests: %w", err)
+	}
+	return users, nil
+}
+
+func (r *userRepository) Search(ctx context.Context, filter UserSearchFilter) ([]*models.User, error) {
+	var users []*models.User
+
+	db := txOrDB(ctx, r.db)
+	if filter.Email != "" {
+		db = db.Where("normalized_email = ?", emailnorm.Normalize(filter.Email))
+	} else {
+		searchPattern := "%" + strings.ToLower(filter.Name) + "%"
+		db = db.Where("LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?", searchPattern, searchPattern).
+			Where("privacy_hide_from_search = ?", false)
+	}
+
+	if filter.ExcludeUserID != uuid.Nil {
+		db = db.Where("id <> ?", filter.ExcludeUserID)
+	}
+	if filter.ExcludeTTRID != uuid.Nil {
+		db = db.Where("NOT EXISTS (?)", txOrDB(ctx, r.db).
+			Model(&models.TTRPlayer{}).
+			Select("1").
+			Where("ttr_players.user_id = users.id AND ttr_players.ttr_id = ?", filter.ExcludeTTRID))
+	}
+
+	if err := db.
+		Order("first_name, last_name").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
 		Find(&users).Error; err != nil {
 		return nil, fmt.Errorf("failed to search users: %w", err)
 	}
 
 	return users, nil
 }
+
+// FindCaptainedBy returns those of userIDs who play on a TTR captainID
+// captains or co-captains.
+func (r *userRepository) FindCaptainedBy(ctx context.Context, captainID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var captained []uuid.UUID
+	if err := txOrDB(ctx, r.db).
+		Model(&models.TTRPlayer{}).
+		Distinct("ttr_players.user_id").
+		Joins("JOIN ttrs ON ttrs.id = ttr_players.ttr_id AND ttrs.deleted_at IS NULL").
+		Where("ttr_players.user_id IN ?", userIDs).
+		Where("ttrs.captain_user_id = ? OR EXISTS (SELECT 1 FROM ttr_co_captains WHERE ttr_co_captains.ttr_id = ttrs.id AND ttr_co_captains.user_id = ?)", captainID, captainID).
+		Pluck("ttr_players.user_id", &captained).Error; err != nil {
+		return nil, fmt.Errorf("failed to find captained users: %w", err)
+	}
+	return captained, nil
+}
+
+// purgeableUsers matches users soft-deleted before the cutoff that nothing
+// outside their own data still points at: users who created or captain a
+// TTR, wrote chat messages, or own organizations, leagues or tournaments are
+// kept until those rows are gone.
+const purgeableUsers = `deleted_at IS NOT NULL AND deleted_at < ?
+AND id NOT IN (SELECT captain_user_id FROM ttrs)
+AND id NOT IN (SELECT created_by_user_id FROM ttrs)
+AND id NOT IN (SELECT user_id FROM ttr_messages)
+AND id NOT IN (SELECT created_by_user_id FROM organizations)
+AND id NOT IN (SELECT invited_by_user_id FROM organization_invitations)
+AND id NOT IN (SELECT owner_user_id FROM leagues)
+AND id NOT IN (SELECT owner_user_id FROM tournaments)
+AND id NOT IN (SELECT winner_user_id FROM tournaments WHERE winner_user_id IS NOT NULL)
+AND id NOT IN (SELECT player1_user_id FROM tournament_matches WHERE player1_user_id IS NOT NULL)
+AND id NOT IN (SELECT player2_user_id FROM tournament_matches WHERE player2_user_id IS NOT NULL)
+AND id NOT IN (SELECT winner_user_id FROM tournament_matches WHERE winner_user_id IS NOT NULL)`
+
+// PurgeDeletedBefore permanently deletes up to limit purgeable users,
+// oldest deletion first, together with their refresh tokens, notifications,
+// playing days, memberships, roster spots, invitations, read markers,
+// reactions and the reports they filed. Everything happens in one
+// transaction; it returns the number of users purged.
+func (r *userRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	var purged int64
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var ids []uuid.UUID
+		if err := tx.Unscoped().Model(&models.User{}).
+			Where(purgeableUsers, cutoff).
+			Order("deleted_at ASC").
+			Limit(limit).
+			Pluck("id", &ids).Error; err != nil {
+			return fmt.Errorf("failed to find users to purge: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, model := range []interface{}{
+			&models.RefreshToken{},
+			&models.Notification{},
+			&models.UserPlayingDay{},
+			&models.OrganizationMember{},
+			&models.TTRPlayer{},
+			&models.TTRCoCaptain{},
+			&models.MessageRead{},
+			&models.MessageReaction{},
+			&models.SecurityEvent{},
+			&models.KnownDevice{},
+		} {
+			if err := tx.Where("user_id IN ?", ids).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to purge user dependents: %w", err)
+			}
+		}
+		if err := tx.Where("invitee_user_id IN ? OR inviter_user_id IN ?", ids, ids).Delete(&models.Invitation{}).Error; err != nil {
+			return fmt.Errorf("failed to purge user invitations: %w", err)
+		}
+		if err := tx.Model(&models.Invitation{}).Where("forwarded_by_user_id IN ?", ids).Update("forwarded_by_user_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to purge user invitations: %w", err)
+		}
+		if err := tx.Where("blocker_user_id IN ? OR blocked_user_id IN ?", ids, ids).Delete(&models.UserBlock{}).Error; err != nil {
+			return fmt.Errorf("failed to purge user blocks: %w", err)
+		}
+		if err := tx.Where("requester_user_id IN ? OR addressee_user_id IN ?", ids, ids).Delete(&models.Friendship{}).Error; err != nil {
+			return fmt.Errorf("failed to purge user friendships: %w", err)
+		}
+		if err := tx.Where("reporter_user_id IN ?", ids).Delete(&models.Report{}).Error; err != nil {
+			return fmt.Errorf("failed to purge user reports: %w", err)
+		}
+		if err := tx.Model(&models.Report{}).Where("resolved_by_user_id IN ?", ids).Update("resolved_by_user_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to purge user reports: %w", err)
+		}
+
+		result := tx.Unscoped().Where("id IN ?", ids).Delete(&models.User{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to purge users: %w", result.Error)
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// Merge moves the source user's TTRs, co-captaincies, roster spots,
+// invitations, notifications and chat messages to the target, revokes the
+// source's refresh tokens and soft-deletes the source, pointing it at the
+// target. Where both users hold the same spot, the target's row is kept:
+//   - a roster spot or co-captaincy on a TTR the target also plays in or
+//     co-captains is dropped, and so is a co-captaincy on a TTR the target
+//     captains, the moved TTRs included;
+//   - a pending invitation to a TTR the target also has a pending invitation
+//     to is dropped;
+//   - invitations between the two users are dropped;
+//   - blocks between the two users, and blocks the target already has
+//     its like of, are dropped;
+//   - friendships and friend requests between the two users, and those
+//     with someone the target already has one with, are dropped.
+//
+// Everything happens in one transaction, and merging again changes nothing.
+func (r *userRepository) Merge(ctx context.Context, sourceID uuid.UUID, targetID uuid.UUID, at time.Time) (*UserMerge, error) {
+	merge := &UserMerge{}
+	err := txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.TTR{}).Where("captain_user_id = ?", sourceID).UpdateColumn("captain_user_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge TTR captaincy: %w", result.Error)
+		}
+		merge.CaptainedTTRs = result.RowsAffected
+		if err := tx.Unscoped().Model(&models.TTR{}).Where("created_by_user_id = ?", sourceID).UpdateColumn("created_by_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge TTR creators: %w", err)
+		}
+
+		result = tx.Where(`(user_id = ? AND ttr_id IN (SELECT ttr_id FROM ttr_co_captains WHERE user_id = ?))
+OR (user_id IN ? AND ttr_id IN (SELECT id FROM ttrs WHERE captain_user_id = ?))`, sourceID, targetID, []uuid.UUID{sourceID, targetID}, targetID).
+			Delete(&models.TTRCoCaptain{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to drop duplicate co-captaincies: %w", result.Error)
+		}
+		merge.DroppedCoCaptaincies = result.RowsAffected
+		result = tx.Model(&models.TTRCoCaptain{}).Where("user_id = ?", sourceID).UpdateColumn("user_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge co-captaincies: %w", result.Error)
+		}
+		merge.CoCaptaincies = result.RowsAffected
+
+		result = tx.Where("user_id = ? AND ttr_id IN (SELECT ttr_id FROM ttr_players WHERE user_id = ?)", sourceID, targetID).Delete(&models.TTRPlayer{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to drop duplicate roster spots: %w", result.Error)
+		}
+		merge.DroppedRosterSpots = result.RowsAffected
+		result = tx.Model(&models.TTRPlayer{}).Where("user_id = ?", sourceID).UpdateColumn("user_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge roster spots: %w", result.Error)
+		}
+		merge.RosterSpots = result.RowsAffected
+
+		result = tx.Where(`(inviter_user_id = ? AND invitee_user_id = ?) OR (inviter_user_id = ? AND invitee_user_id = ?)
+OR (invitee_user_id = ? AND status = ? AND ttr_id IN (SELECT ttr_id FROM invitations WHERE invitee_user_id = ? AND status = ?))`,
+			sourceID, targetID, targetID, sourceID,
+			sourceID, models.InvitationStatusPending, targetID, models.InvitationStatusPending).
+			Delete(&models.Invitation{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to drop duplicate invitations: %w", result.Error)
+		}
+		merge.DroppedInvitations = result.RowsAffected
+		for _, column := range []string{"invitee_user_id", "inviter_user_id"} {
+			result = tx.Model(&models.Invitation{}).Where(column+" = ?", sourceID).UpdateColumn(column, targetID)
+			if result.Error != nil {
+				return fmt.Errorf("failed to merge invitations: %w", result.Error)
+			}
+			merge.Invitations += result.RowsAffected
+		}
+		if err := tx.Model(&models.Invitation{}).Where("forwarded_by_user_id = ?", sourceID).UpdateColumn("forwarded_by_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge forwarded invitations: %w", err)
+		}
+
+		if err := tx.Model(&models.UserBlock{}).
+			Where("blocker_user_id = ? AND blocked_user_id <> ? AND blocked_user_id NOT IN (SELECT blocked_user_id FROM user_blocks WHERE blocker_user_id = ?)", sourceID, targetID, targetID).
+			UpdateColumn("blocker_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge blocks: %w", err)
+		}
+		if err := tx.Model(&models.UserBlock{}).
+			Where("blocked_user_id = ? AND blocker_user_id <> ? AND blocker_user_id NOT IN (SELECT blocker_user_id FROM user_blocks WHERE blocked_user_id = ?)", sourceID, targetID, targetID).
+			UpdateColumn("blocked_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge blocks: %w", err)
+		}
+		if err := tx.Where("blocker_user_id = ? OR blocked_user_id = ?", sourceID, sourceID).Delete(&models.UserBlock{}).Error; err != nil {
+			return fmt.Errorf("failed to drop duplicate blocks: %w", err)
+		}
+
+		const targetsFriends = `SELECT addressee_user_id FROM friendships WHERE requester_user_id = ?
+UNION SELECT requester_user_id FROM friendships WHERE addressee_user_id = ?`
+		if err := tx.Model(&models.Friendship{}).
+			Where("requester_user_id = ? AND addressee_user_id <> ? AND addressee_user_id NOT IN ("+targetsFriends+")", sourceID, targetID, targetID, targetID).
+			UpdateColumn("requester_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge friendships: %w", err)
+		}
+		if err := tx.Model(&models.Friendship{}).
+			Where("addressee_user_id = ? AND requester_user_id <> ? AND requester_user_id NOT IN ("+targetsFriends+")", sourceID, targetID, targetID, targetID).
+			UpdateColumn("addressee_user_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to merge friendships: %w", err)
+		}
+		if err := tx.Where("requester_user_id = ? OR addressee_user_id = ?", sourceID, sourceID).Delete(&models.Friendship{}).Error; err != nil {
+			return fmt.Errorf("failed to drop duplicate friendships: %w", err)
+		}
+
+		result = tx.Model(&models.Notification{}).Where("user_id = ?", sourceID).UpdateColumn("user_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge notifications: %w", result.Error)
+		}
+		merge.Notifications = result.RowsAffected
+		result = tx.Model(&models.Message{}).Where("user_id = ?", sourceID).UpdateColumn("user_id", targetID)
+		if result.Error != nil {
+			return fmt.Errorf("failed to merge messages: %w", result.Error)
+		}
+		merge.Messages = result.RowsAffected
+		result = tx.Model(&models.RefreshToken{}).Where("user_id = ? AND revoked = ?", sourceID, false).UpdateColumn("revoked", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", result.Error)
+		}
+		merge.RevokedRefreshTokens = result.RowsAffected
+
+		if err := tx.Unscoped().Model(&models.User{}).Where("id = ?", sourceID).UpdateColumns(map[string]interface{}{
+			"merged_into_user_id": targetID,
+			"deleted_at":          gorm.Expr("COALESCE(deleted_at, ?)", at),
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark user merged: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
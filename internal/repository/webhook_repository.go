@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	FindByOwner(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error)
+	FindByOrganization(ctx context.Context, orgID uuid.UUID) ([]*models.Webhook, error)
+	FindActiveForTTR(ctx context.Context, captainID uuid.UUID, orgID *uuid.UUID) ([]*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	RecordResult(ctx context.Context, id uuid.UUID, succeeded bool, failingAfter int) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, webhookID uuid.UUID, limit int, offset int) ([]*models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	if err := txOrDB(ctx, r.db).Create(webhook).Error; err != nil {
+		return createError("create webhook", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := txOrDB(ctx, r.db).Where("id = ?", id).First(&webhook).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+// FindByOwner returns the user's own webhooks, not their organizations',
+// oldest first.
+func (r *webhookRepository) FindByOwner(ctx context.Context, userID uuid.UUID) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	if err := txOrDB(ctx, r.db).
+		Where("owner_user_id = ? AND organization_id IS NULL", userID).
+		Order("created_at ASC").
+		Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// FindByOrganization returns the organization's webhooks, oldest first.
+func (r *webhookRepository) FindByOrganization(ctx context.Context, orgID uuid.UUID) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	if err := txOrDB(ctx, r.db).
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// FindActiveForTTR returns the ACTIVE webhooks that hear about a TTR: those
+// the captain owns personally and, when orgID is set, the organization's.
+func (r *webhookRepository) FindActiveForTTR(ctx context.Context, captainID uuid.UUID, orgID *uuid.UUID) ([]*models.Webhook, error) {
+	query := txOrDB(ctx, r.db).Where("status = ?", models.WebhookStatusActive)
+	if orgID != nil {
+		query = query.Where("(owner_user_id = ? AND organization_id IS NULL) OR organization_id = ?", captainID, *orgID)
+	} else {
+		query = query.Where("owner_user_id = ? AND organization_id IS NULL", captainID)
+	}
+
+	var webhooks []*models.Webhook
+	if err := query.Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to find webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	if err := txOrDB(ctx, r.db).Save(webhook).Error; err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return nil
+}
+
+// RecordResult records the outcome of a delivery. A success resets the
+// failure count; a failure increments it and marks the webhook FAILING once
+// it reaches failingAfter. A failingAfter of 0 never marks it FAILING.
+func (r *webhookRepository) RecordResult(ctx context.Context, id uuid.UUID, succeeded bool, failingAfter int) error {
+	db := txOrDB(ctx, r.db).Model(&models.Webhook{}).Where("id = ?", id)
+	var err error
+	if succeeded {
+		err = db.Update("consecutive_failures", 0).Error
+	} else {
+		updates := map[string]interface{}{
+			"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+		}
+		if failingAfter > 0 {
+			updates["status"] = gorm.Expr("CASE WHEN consecutive_failures + 1 >= ? THEN ? ELSE status END", failingAfter, models.WebhookStatusFailing)
+		}
+		err = db.Updates(updates).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record webhook result: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return txOrDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Where("id = ?", id).Delete(&models.Webhook{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if err := txOrDB(ctx, r.db).Create(delivery).Error; err != nil {
+		return createError("create webhook delivery", err)
+	}
+	return nil
+}
+
+// FindDeliveries returns a page of the webhook's delivery attempts, newest
+// first.
+func (r *webhookRepository) FindDeliveries(ctx context.Context, webhookID uuid.UUID, limit int, offset int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	if err := txOrDB(ctx, r.db).
+		Where("webhook_id = ?", webhookID).
+		Order("created_at DESC, attempt DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to find webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
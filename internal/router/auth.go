@@ -0,0 +1,52 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/signing"
+)
+
+// auth authenticates a route group with a JWT or, once WithAPITokens is set,
+// a personal API token. Once WithImpersonation is set it also accepts
+// impersonation tokens, behind middleware.ImpersonationGuard, and once
+// WithSignedRequests is set, requests carrying a signature are authenticated
+// by it instead.
+func (rt *Router) auth() Middleware {
+	return Middleware{Name: "auth", Wrap: rt.authenticate(), Auth: true}
+}
+
+// Route group middleware that needs no configuration.
+var (
+	rejectAPITokens     = Middleware{Name: "reject_api_tokens", Wrap: middleware.RejectAPITokens}
+	rejectImpersonation = Middleware{Name: "reject_impersonation", Wrap: middleware.RejectImpersonation}
+	requireAdmin        = Middleware{Name: "require_admin", Wrap: middleware.RequireRole(models.UserRoleAdmin)}
+)
+
+// authenticate builds auth's middleware.
+func (rt *Router) authenticate() mux.MiddlewareFunc {
+	authenticate := middleware.Auth(rt.jwtSecret, rt.apiTokens, rt.impersonations)
+	if rt.impersonations != nil {
+		guard := middleware.ImpersonationGuard(rt.impersonations, rt.logger)
+		withToken := authenticate
+		authenticate = func(next http.Handler) http.Handler {
+			return withToken(guard(next))
+		}
+	}
+	if rt.signatures == nil {
+		return authenticate
+	}
+	signed := middleware.SignedAuth(rt.signatures, rt.logger)
+	return func(next http.Handler) http.Handler {
+		withToken, withSignature := authenticate(next), signed(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if signing.IsSigned(r.Header) {
+				withSignature.ServeHTTP(w, r)
+				return
+			}
+			withToken.ServeHTTP(w, r)
+		})
+	}
+}
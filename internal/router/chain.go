@@ -0,0 +1,81 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// Middleware is one named layer of a Chain. Auth marks middleware that
+// authenticates the caller.
+type Middleware struct {
+	Name string
+	Wrap func(http.Handler) http.Handler
+	Auth bool
+}
+
+// Chain is an ordered list of middleware, outermost first.
+type Chain []Middleware
+
+// Then wraps h in the chain, so the first middleware sees each request first
+// and its response last.
+func (c Chain) Then(h http.Handler) http.Handler {
+	for i := len(c) - 1; i >= 0; i-- {
+		h = c[i].Wrap(h)
+	}
+	return h
+}
+
+// Authenticates reports whether a middleware in the chain authenticates the
+// caller.
+func (c Chain) Authenticates() bool {
+	for _, m := range c {
+		if m.Auth {
+			return true
+		}
+	}
+	return false
+}
+
+// Names lists the chain's middleware, outermost first.
+func (c Chain) Names() []string {
+	names := make([]string, 0, len(c))
+	for _, m := range c {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+// Chains for the groups that are not route groups.
+const (
+	// ChainGlobal wraps every request, matched or not.
+	ChainGlobal = "global"
+	// ChainAPI wraps every route under an API version, after ChainGlobal;
+	// its name is suffixed with the version, e.g. "api/v1".
+	ChainAPI = "api/"
+)
+
+// group mounts a route group at prefix on api behind chain, recording the
+// chain under name. Groups mounted once per API version get the same chain
+// each time.
+func (rt *Router) group(api *mux.Router, name string, prefix string, chain ...Middleware) *mux.Router {
+	routes := api.PathPrefix(prefix).Subrouter()
+	for _, m := range chain {
+		routes.Use(m.Wrap)
+	}
+	rt.chains[name] = chain
+	rt.groups[routes] = name
+	return routes
+}
+
+// Chains lists each group's middleware by name, outermost first: ChainGlobal,
+// ChainAPI plus each version, and every route group. A request passes through
+// ChainGlobal, then its version's chain, then its route group's chain. Call it
+// after SetupRoutes.
+func (rt *Router) Chains() map[string][]string {
+	chains := make(map[string][]string, len(rt.chains))
+	for name, chain := range rt.chains {
+		chains[name] = chain.Names()
+	}
+	return chains
+}
@@ -0,0 +1,291 @@
+package router
+
+import (
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/signing"
+)
+
+// Option configures which route groups a Router mounts.
+type Option func(*Router)
+
+// WithAuth mounts the /auth routes.
+func WithAuth(h *handler.AuthHandler) Option {
+	return func(rt *Router) {
+		rt.authHandler = h
+	}
+}
+
+// WithUsers mounts the /users routes.
+func WithUsers(h *handler.UserHandler) Option {
+	return func(rt *Router) {
+		rt.userHandler = h
+	}
+}
+
+// WithTTR mounts the /ttrs routes.
+func WithTTR(h *handler.TTRHandler) Option {
+	return func(rt *Router) {
+		rt.ttrHandler = h
+	}
+}
+
+// WithInvitations mounts the /invitations routes.
+func WithInvitations(h *handler.InvitationHandler) Option {
+	return func(rt *Router) {
+		rt.invitationHandler = h
+	}
+}
+
+// WithInvitationTemplates mounts the /invitation-templates routes.
+func WithInvitationTemplates(h *handler.InvitationTemplateHandler) Option {
+	return func(rt *Router) {
+		rt.templateHandler = h
+	}
+}
+
+// WithMessages mounts the TTR chat routes under /ttrs.
+func WithMessages(h *handler.MessageHandler) Option {
+	return func(rt *Router) {
+		rt.messageHandler = h
+	}
+}
+
+// WithSuggestions mounts the suggested players route under /ttrs.
+func WithSuggestions(h *handler.SuggestionHandler) Option {
+	return func(rt *Router) {
+		rt.suggestionHandler = h
+	}
+}
+
+// WithPairings mounts the pairing suggestion route under /ttrs.
+func WithPairings(h *handler.PairingHandler) Option {
+	return func(rt *Router) {
+		rt.pairingHandler = h
+	}
+}
+
+// WithInviteLinks mounts the invite link routes: link management under
+// /ttrs, the public preview under /public and accepting under /invite-links.
+func WithInviteLinks(h *handler.InviteLinkHandler) Option {
+	return func(rt *Router) {
+		rt.inviteLinkHandler = h
+	}
+}
+
+// WithActionItems mounts the /me/action-items route.
+func WithActionItems(h *handler.ActionItemHandler) Option {
+	return func(rt *Router) {
+		rt.actionItemHandler = h
+	}
+}
+
+// WithDashboard mounts the /dashboard route.
+func WithDashboard(h *handler.DashboardHandler) Option {
+	return func(rt *Router) {
+		rt.dashboardHandler = h
+	}
+}
+
+// WithGraphQL mounts the /graphql route.
+func WithGraphQL(h *handler.GraphQLHandler) Option {
+	return func(rt *Router) {
+		rt.graphqlHandler = h
+	}
+}
+
+// WithChangeFeed mounts the TTR change feed route under /ttrs.
+func WithChangeFeed(h *handler.ChangeFeedHandler) Option {
+	return func(rt *Router) {
+		rt.changeFeedHandler = h
+	}
+}
+
+// WithCheckIns mounts the TTR check-in routes under /ttrs.
+func WithCheckIns(h *handler.CheckInHandler) Option {
+	return func(rt *Router) {
+		rt.checkInHandler = h
+	}
+}
+
+// WithWebhooks mounts the /webhooks routes.
+func WithWebhooks(h *handler.WebhookHandler) Option {
+	return func(rt *Router) {
+		rt.webhookHandler = h
+	}
+}
+
+// WithAPITokens mounts the /users/me/api-tokens routes and lets every
+// authenticated route accept the tokens authenticator resolves.
+func WithAPITokens(h *handler.APITokenHandler, authenticator middleware.APITokenAuthenticator) Option {
+	return func(rt *Router) {
+		rt.apiTokenHandler = h
+		rt.apiTokens = authenticator
+	}
+}
+
+// WithSecurityEvents mounts the /users/me/security-events route.
+func WithSecurityEvents(h *handler.SecurityEventHandler) Option {
+	return func(rt *Router) {
+		rt.securityEventHandler = h
+	}
+}
+
+// WithHistory mounts the /users/me/history route.
+func WithHistory(h *handler.HistoryHandler) Option {
+	return func(rt *Router) {
+		rt.historyHandler = h
+	}
+}
+
+// WithSchedule mounts the /ttrs/schedule tee sheet route.
+func WithSchedule(h *handler.ScheduleHandler) Option {
+	return func(rt *Router) {
+		rt.scheduleHandler = h
+	}
+}
+
+// WithReports mounts POST /reports and the /admin/reports routes.
+func WithReports(h *handler.ReportHandler) Option {
+	return func(rt *Router) {
+		rt.reportHandler = h
+	}
+}
+
+// WithImpersonation mounts the admin impersonation and audit log routes and
+// lets every authenticated route accept impersonation tokens, auditing the
+// requests made with them.
+func WithImpersonation(h *handler.ImpersonationHandler, auditor middleware.ImpersonationAuditor) Option {
+	return func(rt *Router) {
+		rt.impersonationHandler = h
+		rt.impersonations = auditor
+	}
+}
+
+// WithSignedRequests lets every authenticated route accept requests signed
+// by the internal clients verifier knows, instead of a token.
+func WithSignedRequests(verifier *signing.Verifier) Option {
+	return func(rt *Router) {
+		rt.signatures = verifier
+	}
+}
+
+// WithFlags mounts GET /meta/flags and the /admin/flags routes.
+func WithFlags(h *handler.FlagHandler) Option {
+	return func(rt *Router) {
+		rt.flagHandler = h
+	}
+}
+
+// WithHealth mounts /healthz and /readyz at the root.
+func WithHealth(h *handler.HealthHandler) Option {
+	return func(rt *Router) {
+		rt.healthHandler = h
+	}
+}
+
+// WithMaintenance mounts the /admin/maintenance routes and answers every
+// other route but the health checks with a 503 while checker reports
+// maintenance.
+func WithMaintenance(h *handler.MaintenanceHandler, checker middleware.MaintenanceChecker) Option {
+	return func(rt *Router) {
+		rt.maintenanceHandler = h
+		rt.maintenance = checker
+	}
+}
+
+// WithSlack mounts the Slack integration routes under /integrations/slack.
+func WithSlack(h *handler.SlackHandler) Option {
+	return func(rt *Router) {
+		rt.slackHandler = h
+	}
+}
+
+// WithEmailEvents mounts the email provider webhook under
+// /integrations/email.
+func WithEmailEvents(h *handler.EmailEventHandler) Option {
+	return func(rt *Router) {
+		rt.emailEventHandler = h
+	}
+}
+
+// WithOrganizations mounts the /orgs routes.
+func WithOrganizations(h *handler.OrganizationHandler) Option {
+	return func(rt *Router) {
+		rt.orgHandler = h
+	}
+}
+
+// WithLeagues mounts the /leagues routes and score recording under /ttrs.
+func WithLeagues(h *handler.LeagueHandler) Option {
+	return func(rt *Router) {
+		rt.leagueHandler = h
+	}
+}
+
+// WithTournaments mounts the /tournaments routes.
+func WithTournaments(h *handler.TournamentHandler) Option {
+	return func(rt *Router) {
+		rt.tournamentHandler = h
+	}
+}
+
+// WithAdmin mounts the admin-only /admin routes.
+func WithAdmin(h *handler.AdminHandler) Option {
+	return func(rt *Router) {
+		rt.adminHandler = h
+	}
+}
+
+// WithMeta mounts the public /meta routes.
+func WithMeta(h *handler.MetaHandler) Option {
+	return func(rt *Router) {
+		rt.metaHandler = h
+	}
+}
+
+// WithRouteListing adds GET /meta/routes to the /meta routes, listing every
+// route and what it takes to call it. It is meant for debugging and needs
+// WithMeta.
+func WithRouteListing() Option {
+	return func(rt *Router) {
+		rt.listRoutes = true
+	}
+}
+
+// WithAuthRateLimiter limits requests to the /auth routes per client IP.
+func WithAuthRateLimiter(l ratelimit.RateLimiter) Option {
+	return func(rt *Router) {
+		rt.authRateLimiter = l
+	}
+}
+
+// WithTrustedProxies makes rate limiting and security events see the client
+// behind a trusted proxy, from the forwarding headers it sets, rather than
+// the proxy itself.
+func WithTrustedProxies(clients *clientip.Resolver) Option {
+	return func(rt *Router) {
+		rt.clientIPs = clients
+	}
+}
+
+// WithCompression gzips responses of at least minSize bytes for clients that
+// accept it.
+func WithCompression(minSize int) Option {
+	return func(rt *Router) {
+		rt.compressMinSize = minSize
+		rt.compress = true
+	}
+}
+
+// WithV1Sunset marks /api/v1 deprecated, announcing sunset as the date it
+// will be retired.
+func WithV1Sunset(sunset time.Time) Option {
+	return func(rt *Router) {
+		rt.v1Sunset = sunset
+	}
+}
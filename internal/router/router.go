@@ -2,89 +2,305 @@ package router
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/yourusername/golf_messenger/internal/handler"
 	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/response"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"github.com/yourusername/golf_messenger/pkg/signing"
 	"go.uber.org/zap"
 )
 
+// API versions, each mounted under /api/<version>. Route groups are shared
+// between versions except where a v2 handler changes the response.
+const (
+	APIV1 = "v1"
+	APIV2 = "v2"
+)
+
+// apiVersions lists the supported API versions, oldest first.
+var apiVersions = []string{APIV1, APIV2}
+
 type Router struct {
-	mux               *mux.Router
-	authHandler       *handler.AuthHandler
-	userHandler       *handler.UserHandler
-	ttrHandler        *handler.TTRHandler
-	invitationHandler *handler.InvitationHandler
-	logger            *zap.Logger
-	jwtSecret         string
-	corsOrigins       []string
+	mux                  *mux.Router
+	authHandler          *handler.AuthHandler
+	userHandler          *handler.UserHandler
+	ttrHandler           *handler.TTRHandler
+	invitationHandler    *handler.InvitationHandler
+	templateHandler      *handler.InvitationTemplateHandler
+	messageHandler       *handler.MessageHandler
+	suggestionHandler    *handler.SuggestionHandler
+	pairingHandler       *handler.PairingHandler
+	inviteLinkHandler    *handler.InviteLinkHandler
+	actionItemHandler    *handler.ActionItemHandler
+	dashboardHandler     *handler.DashboardHandler
+	graphqlHandler       *handler.GraphQLHandler
+	changeFeedHandler    *handler.ChangeFeedHandler
+	checkInHandler       *handler.CheckInHandler
+	webhookHandler       *handler.WebhookHandler
+	apiTokenHandler      *handler.APITokenHandler
+	apiTokens            middleware.APITokenAuthenticator
+	securityEventHandler *handler.SecurityEventHandler
+	historyHandler       *handler.HistoryHandler
+	reportHandler        *handler.ReportHandler
+	scheduleHandler      *handler.ScheduleHandler
+	impersonationHandler *handler.ImpersonationHandler
+	impersonations       middleware.ImpersonationAuditor
+	signatures           *signing.Verifier
+	flagHandler          *handler.FlagHandler
+	healthHandler        *handler.HealthHandler
+	maintenanceHandler   *handler.MaintenanceHandler
+	maintenance          middleware.MaintenanceChecker
+	slackHandler         *handler.SlackHandler
+	emailEventHandler    *handler.EmailEventHandler
+	orgHandler           *handler.OrganizationHandler
+	leagueHandler        *handler.LeagueHandler
+	tournamentHandler    *handler.TournamentHandler
+	adminHandler         *handler.AdminHandler
+	metaHandler          *handler.MetaHandler
+	authRateLimiter      ratelimit.RateLimiter
+	clientIPs            *clientip.Resolver
+	compress             bool
+	compressMinSize      int
+	v1Sunset             time.Time
+	logger               *zap.Logger
+	jwtSecret            string
+	corsOrigins          []string
+	listRoutes           bool
+	// routes records how each route was registered, and groups the name of
+	// each route group's subrouter.
+	routes map[*mux.Route]routeSpec
+	groups map[*mux.Router]string
+	// chains records the middleware chain of each group, by group name.
+	chains map[string]Chain
 }
 
-func NewRouter(
-	authHandler *handler.AuthHandler,
-	userHandler *handler.UserHandler,
-	ttrHandler *handler.TTRHandler,
-	invitationHandler *handler.InvitationHandler,
-	logger *zap.Logger,
-	jwtSecret string,
-	corsOrigins []string,
-) *Router {
-	return &Router{
-		mux:               mux.NewRouter(),
-		authHandler:       authHandler,
-		userHandler:       userHandler,
-		ttrHandler:        ttrHandler,
-		invitationHandler: invitationHandler,
-		logger:            logger,
-		jwtSecret:         jwtSecret,
-		corsOrigins:       corsOrigins,
+// New creates a Router. Only the route groups whose handlers are supplied via
+// options are registered; requests to any other group get a 404.
+func New(logger *zap.Logger, jwtSecret string, corsOrigins []string, opts ...Option) *Router {
+	rt := &Router{
+		mux:         mux.NewRouter(),
+		logger:      logger,
+		jwtSecret:   jwtSecret,
+		corsOrigins: corsOrigins,
+		routes:      make(map[*mux.Route]routeSpec),
+		groups:      make(map[*mux.Router]string),
+		chains:      make(map[string]Chain),
+	}
+	for _, opt := range opts {
+		opt(rt)
 	}
+	return rt
 }
 
 func (rt *Router) SetupRoutes() http.Handler {
-	api := rt.mux.PathPrefix("/api/v1").Subrouter()
-
-	authRoutes := api.PathPrefix("/auth").Subrouter()
-	authRoutes.HandleFunc("/register", rt.authHandler.Register).Methods("POST")
-	authRoutes.HandleFunc("/login", rt.authHandler.Login).Methods("POST")
-	authRoutes.HandleFunc("/refresh", rt.authHandler.Refresh).Methods("POST")
-	authRoutes.HandleFunc("/logout", rt.authHandler.Logout).Methods("POST")
-
-	userRoutes := api.PathPrefix("/users").Subrouter()
-	userRoutes.Use(middleware.Auth(rt.jwtSecret))
-	userRoutes.HandleFunc("/me", rt.userHandler.GetMe).Methods("GET")
-	userRoutes.HandleFunc("/me", rt.userHandler.UpdateMe).Methods("PUT")
-	userRoutes.HandleFunc("/me/password", rt.userHandler.ChangePassword).Methods("PUT")
-	userRoutes.HandleFunc("/me/avatar", rt.userHandler.UploadAvatar).Methods("POST")
-	userRoutes.HandleFunc("/me/avatar", rt.userHandler.DeleteAvatar).Methods("DELETE")
-	userRoutes.HandleFunc("/{id}", rt.userHandler.GetUserByID).Methods("GET")
-	userRoutes.HandleFunc("", rt.userHandler.SearchUsers).Methods("GET")
-
-	ttrRoutes := api.PathPrefix("/ttrs").Subrouter()
-	ttrRoutes.Use(middleware.Auth(rt.jwtSecret))
-	ttrRoutes.HandleFunc("", rt.ttrHandler.CreateTTR).Methods("POST")
-	ttrRoutes.HandleFunc("", rt.ttrHandler.SearchTTRs).Methods("GET")
-	ttrRoutes.HandleFunc("/{id}", rt.ttrHandler.GetTTR).Methods("GET")
-	ttrRoutes.HandleFunc("/{id}", rt.ttrHandler.UpdateTTR).Methods("PUT")
-	ttrRoutes.HandleFunc("/{id}", rt.ttrHandler.DeleteTTR).Methods("DELETE")
-	ttrRoutes.HandleFunc("/{id}/co-captains", rt.ttrHandler.AddCoCaptain).Methods("POST")
-	ttrRoutes.HandleFunc("/{id}/co-captains/{userId}", rt.ttrHandler.RemoveCoCaptain).Methods("DELETE")
-	ttrRoutes.HandleFunc("/{id}/join", rt.ttrHandler.JoinTTR).Methods("POST")
-	ttrRoutes.HandleFunc("/{id}/leave", rt.ttrHandler.LeaveTTR).Methods("POST")
-	ttrRoutes.HandleFunc("/{id}/players", rt.ttrHandler.GetPlayers).Methods("GET")
-	ttrRoutes.HandleFunc("/{id}/players/{userId}", rt.ttrHandler.UpdatePlayerStatus).Methods("PUT")
-
-	invitationRoutes := api.PathPrefix("/invitations").Subrouter()
-	invitationRoutes.Use(middleware.Auth(rt.jwtSecret))
-	invitationRoutes.HandleFunc("", rt.invitationHandler.CreateInvitation).Methods("POST")
-	invitationRoutes.HandleFunc("/me", rt.invitationHandler.GetMyInvitations).Methods("GET")
-	invitationRoutes.HandleFunc("/{id}", rt.invitationHandler.GetInvitation).Methods("GET")
-	invitationRoutes.HandleFunc("/{id}/respond", rt.invitationHandler.RespondToInvitation).Methods("PUT")
-	invitationRoutes.HandleFunc("/{id}", rt.invitationHandler.CancelInvitation).Methods("DELETE")
-
-	handler := middleware.ErrorRecovery(rt.logger)(rt.mux)
-	handler = middleware.Logging(rt.logger)(handler)
-	handler = middleware.CORS(rt.corsOrigins)(handler)
-
-	return handler
+	for _, version := range apiVersions {
+		chain := Chain{
+			// Services load each TTR once per request through the authorizer.
+			{Name: "ttr_memo", Wrap: middleware.RequestContext(service.WithTTRMemo)},
+			// Security events record where each request came from.
+			{Name: "client_info", Wrap: middleware.ClientInfo(rt.clientIPs, service.WithClient)},
+		}
+		if version == APIV1 && !rt.v1Sunset.IsZero() {
+			chain = append(chain, Middleware{Name: "deprecation", Wrap: middleware.Deprecation(rt.v1Sunset, "/api/"+APIV2)})
+		}
+		api := rt.group(rt.mux, ChainAPI+version, "/api/"+version, chain...)
+		rt.setupVersion(api, version)
+	}
+	if rt.healthHandler != nil {
+		rt.handlePublic(rt.mux, "/healthz", rt.healthHandler.Healthz).Methods("GET")
+		rt.handlePublic(rt.mux, "/readyz", rt.healthHandler.Readyz).Methods("GET")
+	}
+	unsupported := rt.mux.MatcherFunc(isUnsupportedVersion).HandlerFunc(unsupportedVersion)
+	rt.routes[unsupported] = routeSpec{public: true}
+
+	// ErrorRecovery is outermost so a panic anywhere is answered, and Logging
+	// wraps everything that can answer a request itself, so it logs the
+	// status the client got.
+	chain := Chain{
+		{Name: "error_recovery", Wrap: middleware.ErrorRecovery(rt.logger)},
+		{Name: "tracing", Wrap: middleware.Tracing(rt.mux)},
+		{Name: "logging", Wrap: middleware.Logging(rt.logger)},
+		{Name: "cors", Wrap: middleware.CORS(rt.corsOrigins)},
+	}
+	if rt.compress {
+		// Inside Logging, so logged response sizes are the compressed ones.
+		chain = append(chain, Middleware{Name: "compress", Wrap: middleware.Compress(rt.compressMinSize)})
+	}
+	chain = append(chain, Middleware{Name: "language", Wrap: middleware.Language})
+	if rt.maintenance != nil {
+		chain = append(chain, Middleware{Name: "maintenance", Wrap: middleware.Maintenance(rt.maintenance, maintenanceExempt)})
+	}
+	rt.chains[ChainGlobal] = chain
+
+	return chain.Then(rt.mux)
+}
+
+// setupVersion registers the route groups mounted by options on api, one
+// domain at a time.
+func (rt *Router) setupVersion(api *mux.Router, version string) {
+	if rt.authHandler != nil {
+		rt.registerAuthRoutes(api)
+	}
+	if rt.userHandler != nil {
+		rt.registerUserRoutes(api, version)
+	}
+	// Ahead of the TTR routes, so /ttrs/{id} doesn't take /ttrs/schedule.
+	if rt.scheduleHandler != nil {
+		rt.registerScheduleRoutes(api)
+	}
+	if rt.ttrHandler != nil {
+		rt.registerTTRRoutes(api, version)
+	}
+	if rt.invitationHandler != nil {
+		rt.registerInvitationRoutes(api)
+	}
+	if rt.templateHandler != nil {
+		rt.registerInvitationTemplateRoutes(api)
+	}
+	if rt.messageHandler != nil {
+		rt.registerMessageRoutes(api)
+	}
+	if rt.suggestionHandler != nil {
+		rt.registerSuggestionRoutes(api)
+	}
+	if rt.pairingHandler != nil {
+		rt.registerPairingRoutes(api)
+	}
+	if rt.inviteLinkHandler != nil {
+		rt.registerInviteLinkRoutes(api)
+	}
+	if rt.actionItemHandler != nil {
+		rt.registerActionItemRoutes(api)
+	}
+	if rt.dashboardHandler != nil {
+		rt.registerDashboardRoutes(api)
+	}
+	if rt.graphqlHandler != nil {
+		rt.registerGraphQLRoutes(api)
+	}
+	if rt.changeFeedHandler != nil {
+		rt.registerChangeFeedRoutes(api)
+	}
+	if rt.checkInHandler != nil {
+		rt.registerCheckInRoutes(api)
+	}
+	if rt.webhookHandler != nil {
+		rt.registerWebhookRoutes(api)
+	}
+	if rt.apiTokenHandler != nil {
+		rt.registerAPITokenRoutes(api)
+	}
+	if rt.securityEventHandler != nil {
+		rt.registerSecurityEventRoutes(api)
+	}
+	if rt.historyHandler != nil {
+		rt.registerHistoryRoutes(api)
+	}
+	if rt.reportHandler != nil {
+		rt.registerReportRoutes(api)
+	}
+	if rt.impersonationHandler != nil {
+		rt.registerImpersonationRoutes(api)
+	}
+	if rt.slackHandler != nil {
+		rt.registerSlackRoutes(api)
+	}
+	if rt.emailEventHandler != nil {
+		rt.registerEmailEventRoutes(api)
+	}
+	if rt.orgHandler != nil {
+		rt.registerOrganizationRoutes(api)
+	}
+	if rt.leagueHandler != nil {
+		rt.registerLeagueRoutes(api)
+	}
+	if rt.tournamentHandler != nil {
+		rt.registerTournamentRoutes(api)
+	}
+	if rt.adminHandler != nil {
+		rt.registerAdminRoutes(api)
+	}
+	if rt.metaHandler != nil {
+		rt.registerMetaRoutes(api)
+	}
+	if rt.flagHandler != nil {
+		rt.registerFlagRoutes(api)
+	}
+	if rt.maintenanceHandler != nil {
+		rt.registerMaintenanceRoutes(api)
+	}
+}
+
+// handle registers h at path on r, behind mw and a check that the caller's
+// token carries s. r must authenticate its routes.
+func (rt *Router) handle(r *mux.Router, s scope.Scope, path string, h http.HandlerFunc, mw ...func(http.Handler) http.Handler) *mux.Route {
+	// The route's own middleware runs first, so a request it refuses
+	// outright gets that answer rather than a scope error.
+	next := middleware.RequireScope(s)(h)
+	for i := len(mw) - 1; i >= 0; i-- {
+		next = mw[i](next)
+	}
+	route := r.Handle(path, next)
+	rt.routes[route] = routeSpec{group: rt.groups[r], scope: s}
+	return route
+}
+
+// handlePublic registers h at path on r for callers without a token.
+func (rt *Router) handlePublic(r *mux.Router, path string, h http.HandlerFunc) *mux.Route {
+	route := r.HandleFunc(path, h)
+	rt.routes[route] = routeSpec{group: rt.groups[r], public: true}
+	return route
+}
+
+// byVersion picks the v1 handler on /api/v1 and the v2 one on later versions.
+func byVersion(version string, v1 http.HandlerFunc, v2 http.HandlerFunc) http.HandlerFunc {
+	if version == APIV1 {
+		return v1
+	}
+	return v2
+}
+
+// isUnsupportedVersion matches /api requests whose first path segment is not
+// a supported version.
+func isUnsupportedVersion(r *http.Request, _ *mux.RouteMatch) bool {
+	path := r.URL.Path
+	if path != "/api" && !strings.HasPrefix(path, "/api/") {
+		return false
+	}
+	version, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(path, "/api"), "/"), "/")
+	for _, supported := range apiVersions {
+		if version == supported {
+			return false
+		}
+	}
+	return true
+}
+
+func unsupportedVersion(w http.ResponseWriter, r *http.Request) {
+	response.CodedWithDetails(w, errcode.NotFound, "Unsupported API version", map[string][]string{
+		"supported_versions": apiVersions,
+	})
+}
+
+// maintenanceExempt lets health checks and the maintenance switch through
+// maintenance mode, so load balancers can watch it and admins can end it.
+func maintenanceExempt(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz":
+		return true
+	}
+	for _, version := range apiVersions {
+		if r.URL.Path == "/api/"+version+"/admin/maintenance" {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,74 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+// routeSpec is what handle and handlePublic record about a route.
+type routeSpec struct {
+	group  string
+	scope  scope.Scope
+	public bool
+}
+
+// Route describes a registered route. Group names the route group that
+// mounts it, empty for routes at the root, and Middleware lists that group's
+// chain. Auth reports whether the chain authenticates the caller. Public
+// routes take callers without a token and have an empty Scope; Declared is
+// false for routes registered without going through handle or handlePublic.
+type Route struct {
+	Methods    []string
+	Path       string
+	Group      string
+	Middleware []string
+	Auth       bool
+	Scope      scope.Scope
+	Public     bool
+	Declared   bool
+}
+
+// Routes lists every route in registration order. Call it after SetupRoutes.
+func (rt *Router) Routes() []Route {
+	var routes []Route
+	rt.mux.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, _ := route.GetPathTemplate()
+		methods, _ := route.GetMethods()
+		spec, declared := rt.routes[route]
+		chain := rt.chains[spec.group]
+		routes = append(routes, Route{
+			Methods:    methods,
+			Path:       path,
+			Group:      spec.group,
+			Middleware: chain.Names(),
+			Auth:       chain.Authenticates(),
+			Scope:      spec.scope,
+			Public:     spec.public,
+			Declared:   declared,
+		})
+		return nil
+	})
+	return routes
+}
+
+// routeList is Routes as GET /meta/routes lists them.
+func (rt *Router) routeList() []handler.RouteResponse {
+	routes := rt.Routes()
+	list := make([]handler.RouteResponse, 0, len(routes))
+	for _, route := range routes {
+		list = append(list, handler.RouteResponse{
+			Methods:    route.Methods,
+			Path:       route.Path,
+			Group:      route.Group,
+			Middleware: route.Middleware,
+			Auth:       route.Auth,
+			Scope:      string(route.Scope),
+			Public:     route.Public,
+		})
+	}
+	return list
+}
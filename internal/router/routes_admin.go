@@ -0,0 +1,49 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func (rt *Router) registerAdminRoutes(api *mux.Router) {
+	adminRoutes := rt.group(api, "admin", "/admin", rt.auth(), requireAdmin)
+	rt.handle(adminRoutes, scope.Admin, "/log-level", rt.adminHandler.GetLogLevel).Methods("GET")
+	rt.handle(adminRoutes, scope.Admin, "/log-level", rt.adminHandler.SetLogLevel).Methods("PUT")
+	rt.handle(adminRoutes, scope.Admin, "/stats", rt.adminHandler.GetStats).Methods("GET")
+	rt.handle(adminRoutes, scope.Admin, "/users/import", rt.adminHandler.ImportUsers).Methods("POST")
+	rt.handle(adminRoutes, scope.Admin, "/users/merge", rt.adminHandler.MergeUsers).Methods("POST")
+}
+
+func (rt *Router) registerReportRoutes(api *mux.Router) {
+	reportRoutes := rt.group(api, "reports", "/reports", rt.auth())
+	rt.handle(reportRoutes, scope.WriteProfile, "", rt.reportHandler.CreateReport).Methods("POST")
+
+	adminRoutes := rt.group(api, "admin-reports", "/admin/reports", rt.auth(), requireAdmin)
+	rt.handle(adminRoutes, scope.Admin, "", rt.reportHandler.ListReports).Methods("GET")
+	rt.handle(adminRoutes, scope.Admin, "/{id}", rt.reportHandler.CloseReport).Methods("PUT")
+}
+
+func (rt *Router) registerFlagRoutes(api *mux.Router) {
+	myFlagRoutes := rt.group(api, "flags", "/meta", rt.auth())
+	rt.handle(myFlagRoutes, scope.ReadProfile, "/flags", rt.flagHandler.GetMyFlags).Methods("GET")
+
+	adminRoutes := rt.group(api, "admin-flags", "/admin/flags", rt.auth(), requireAdmin)
+	rt.handle(adminRoutes, scope.Admin, "", rt.flagHandler.ListFlags).Methods("GET")
+	rt.handle(adminRoutes, scope.Admin, "/{key}", rt.flagHandler.SetFlag).Methods("PUT")
+	rt.handle(adminRoutes, scope.Admin, "/{key}", rt.flagHandler.DeleteFlag).Methods("DELETE")
+}
+
+func (rt *Router) registerMaintenanceRoutes(api *mux.Router) {
+	maintenanceRoutes := rt.group(api, "maintenance", "/admin/maintenance", rt.auth(), requireAdmin)
+	rt.handle(maintenanceRoutes, scope.Admin, "", rt.maintenanceHandler.GetMaintenance).Methods("GET")
+	rt.handle(maintenanceRoutes, scope.Admin, "", rt.maintenanceHandler.SetMaintenance).Methods("PUT")
+}
+
+func (rt *Router) registerMetaRoutes(api *mux.Router) {
+	metaRoutes := rt.group(api, "meta", "/meta")
+	rt.handlePublic(metaRoutes, "/errors", rt.metaHandler.ListErrors).Methods("GET")
+	rt.handlePublic(metaRoutes, "/scopes", rt.metaHandler.ListScopes).Methods("GET")
+	if rt.listRoutes {
+		rt.handlePublic(metaRoutes, "/routes", rt.metaHandler.ListRoutes(rt.routeList)).Methods("GET")
+	}
+}
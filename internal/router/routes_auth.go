@@ -0,0 +1,70 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func (rt *Router) registerAuthRoutes(api *mux.Router) {
+	var chain Chain
+	if rt.authRateLimiter != nil {
+		chain = append(chain, Middleware{Name: "rate_limit", Wrap: middleware.RateLimit(rt.authRateLimiter, rt.clientIPs, rt.logger)})
+	}
+	authRoutes := rt.group(api, "auth", "/auth", chain...)
+	rt.handlePublic(authRoutes, "/register", rt.authHandler.Register).Methods("POST")
+	rt.handlePublic(authRoutes, "/login", rt.authHandler.Login).Methods("POST")
+	rt.handlePublic(authRoutes, "/refresh", rt.authHandler.Refresh).Methods("POST")
+	rt.handlePublic(authRoutes, "/logout", rt.authHandler.Logout).Methods("POST")
+}
+
+func (rt *Router) registerUserRoutes(api *mux.Router, version string) {
+	userRoutes := rt.group(api, "users", "/users", rt.auth())
+	rt.handle(userRoutes, scope.ReadProfile, "/me", rt.userHandler.GetMe).Methods("GET")
+	rt.handle(userRoutes, scope.WriteProfile, "/me", rt.userHandler.UpdateMe).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/password", rt.userHandler.ChangePassword, middleware.RejectAPITokens, middleware.RejectImpersonation).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/privacy", rt.userHandler.UpdatePrivacy).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/notification-preferences", rt.userHandler.UpdateNotificationPreferences).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/avatar", rt.userHandler.UploadAvatar).Methods("POST")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/avatar", rt.userHandler.DeleteAvatar).Methods("DELETE")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/avatar/upload-url", rt.userHandler.CreateAvatarUploadURL).Methods("POST")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/avatar/complete", rt.userHandler.CompleteAvatarUpload).Methods("POST")
+	rt.handle(userRoutes, scope.ReadProfile, "/me/blocks", rt.userHandler.GetBlockedUsers).Methods("GET")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/blocks/{id}", rt.userHandler.BlockUser).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/blocks/{id}", rt.userHandler.UnblockUser).Methods("DELETE")
+	rt.handle(userRoutes, scope.ReadProfile, "/me/friends", rt.userHandler.GetFriends).Methods("GET")
+	rt.handle(userRoutes, scope.ReadProfile, "/me/friends/requests", rt.userHandler.GetFriendRequests).Methods("GET")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/friends/{id}", rt.userHandler.AddFriend).Methods("PUT")
+	rt.handle(userRoutes, scope.WriteProfile, "/me/friends/{id}", rt.userHandler.RemoveFriend).Methods("DELETE")
+	rt.handle(userRoutes, scope.ReadProfile, "/{id}", byVersion(version, rt.userHandler.GetUserByID, rt.userHandler.GetUserByIDV2)).Methods("GET")
+	rt.handle(userRoutes, scope.ReadProfile, "", byVersion(version, rt.userHandler.SearchUsers, rt.userHandler.SearchUsersV2)).Methods("GET")
+}
+
+func (rt *Router) registerAPITokenRoutes(api *mux.Router) {
+	// A leaked API token must not be able to mint or keep alive others, and
+	// an admin acting as the user must not leave one behind.
+	tokenRoutes := rt.group(api, "api-tokens", "/users/me/api-tokens", rt.auth(), rejectAPITokens, rejectImpersonation)
+	rt.handle(tokenRoutes, scope.WriteProfile, "", rt.apiTokenHandler.CreateAPIToken).Methods("POST")
+	rt.handle(tokenRoutes, scope.ReadProfile, "", rt.apiTokenHandler.ListAPITokens).Methods("GET")
+	rt.handle(tokenRoutes, scope.WriteProfile, "/{id}", rt.apiTokenHandler.RevokeAPIToken).Methods("DELETE")
+}
+
+func (rt *Router) registerSecurityEventRoutes(api *mux.Router) {
+	eventRoutes := rt.group(api, "security-events", "/users/me/security-events", rt.auth())
+	rt.handle(eventRoutes, scope.ReadProfile, "", rt.securityEventHandler.ListSecurityEvents).Methods("GET")
+}
+
+func (rt *Router) registerHistoryRoutes(api *mux.Router) {
+	historyRoutes := rt.group(api, "history", "/users/me/history", rt.auth())
+	rt.handle(historyRoutes, scope.ReadProfile, "", rt.historyHandler.GetHistory).Methods("GET")
+}
+
+func (rt *Router) registerImpersonationRoutes(api *mux.Router) {
+	adminRoutes := rt.group(api, "impersonation", "/admin", rt.auth(), requireAdmin)
+	rt.handle(adminRoutes, scope.Admin, "/impersonate/{userId}", rt.impersonationHandler.StartImpersonation).Methods("POST")
+	rt.handle(adminRoutes, scope.Admin, "/audit-log", rt.impersonationHandler.ListAuditLog).Methods("GET")
+
+	// Stopping must work from a read-only session, so it skips the guard.
+	stopRoutes := rt.group(api, "impersonation-stop", "/impersonation", Middleware{Name: "auth_unguarded", Wrap: middleware.Auth(rt.jwtSecret, rt.apiTokens, rt.impersonations), Auth: true})
+	rt.handle(stopRoutes, scope.WriteProfile, "/stop", rt.impersonationHandler.StopImpersonation).Methods("POST")
+}
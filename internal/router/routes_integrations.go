@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func (rt *Router) registerWebhookRoutes(api *mux.Router) {
+	webhookRoutes := rt.group(api, "webhooks", "/webhooks", rt.auth())
+	rt.handle(webhookRoutes, scope.WriteWebhooks, "", rt.webhookHandler.CreateWebhook).Methods("POST")
+	rt.handle(webhookRoutes, scope.ReadWebhooks, "", rt.webhookHandler.ListWebhooks).Methods("GET")
+	rt.handle(webhookRoutes, scope.ReadWebhooks, "/{id}", rt.webhookHandler.GetWebhook).Methods("GET")
+	rt.handle(webhookRoutes, scope.WriteWebhooks, "/{id}", rt.webhookHandler.UpdateWebhook).Methods("PUT")
+	rt.handle(webhookRoutes, scope.WriteWebhooks, "/{id}", rt.webhookHandler.DeleteWebhook).Methods("DELETE")
+	rt.handle(webhookRoutes, scope.ReadWebhooks, "/{id}/deliveries", rt.webhookHandler.ListDeliveries).Methods("GET")
+}
+
+func (rt *Router) registerSlackRoutes(api *mux.Router) {
+	// Slack signs its command requests instead of sending a token.
+	commandRoutes := rt.group(api, "slack-commands", "/integrations/slack")
+	rt.handlePublic(commandRoutes, "/commands", rt.slackHandler.HandleCommand).Methods("POST")
+
+	linkRoutes := rt.group(api, "slack-link", "/integrations/slack", rt.auth())
+	rt.handle(linkRoutes, scope.WriteProfile, "/link", rt.slackHandler.LinkSlackAccount).Methods("POST")
+}
+
+func (rt *Router) registerEmailEventRoutes(api *mux.Router) {
+	// Providers sign their events instead of sending a token.
+	eventRoutes := rt.group(api, "email-events", "/integrations/email")
+	rt.handlePublic(eventRoutes, "/events", rt.emailEventHandler.HandleEvents).Methods("POST")
+}
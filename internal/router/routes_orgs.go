@@ -0,0 +1,40 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func (rt *Router) registerOrganizationRoutes(api *mux.Router) {
+	orgRoutes := rt.group(api, "organizations", "/orgs", rt.auth())
+	rt.handle(orgRoutes, scope.WriteOrganizations, "", rt.orgHandler.CreateOrganization).Methods("POST")
+	rt.handle(orgRoutes, scope.ReadOrganizations, "", rt.orgHandler.GetMyOrganizations).Methods("GET")
+	rt.handle(orgRoutes, scope.ReadOrganizations, "/invitations/me", rt.orgHandler.GetMyInvitations).Methods("GET")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/invitations/{invitationId}/respond", rt.orgHandler.RespondToInvitation).Methods("PUT")
+	rt.handle(orgRoutes, scope.ReadOrganizations, "/{id}", rt.orgHandler.GetOrganization).Methods("GET")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/{id}", rt.orgHandler.UpdateOrganization).Methods("PUT")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/{id}", rt.orgHandler.DeleteOrganization).Methods("DELETE")
+	rt.handle(orgRoutes, scope.ReadOrganizations, "/{id}/members", rt.orgHandler.GetMembers).Methods("GET")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/{id}/members/{userId}", rt.orgHandler.UpdateMember).Methods("PUT")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/{id}/members/{userId}", rt.orgHandler.RemoveMember).Methods("DELETE")
+	rt.handle(orgRoutes, scope.WriteOrganizations, "/{id}/invitations", rt.orgHandler.InviteMember).Methods("POST")
+}
+
+func (rt *Router) registerLeagueRoutes(api *mux.Router) {
+	leagueRoutes := rt.group(api, "leagues", "/leagues", rt.auth())
+	rt.handle(leagueRoutes, scope.WriteLeagues, "", rt.leagueHandler.CreateLeague).Methods("POST")
+	rt.handle(leagueRoutes, scope.ReadLeagues, "/{id}", rt.leagueHandler.GetLeague).Methods("GET")
+	rt.handle(leagueRoutes, scope.WriteLeagues, "/{id}/ttrs", rt.leagueHandler.AttachTTR).Methods("POST")
+	rt.handle(leagueRoutes, scope.ReadLeagues, "/{id}/standings", rt.leagueHandler.GetStandings).Methods("GET")
+
+	scoreRoutes := rt.group(api, "league-scores", "/ttrs", rt.auth())
+	rt.handle(scoreRoutes, scope.WriteLeagues, "/{id}/scores/{userId}", rt.leagueHandler.RecordScore).Methods("PUT")
+}
+
+func (rt *Router) registerTournamentRoutes(api *mux.Router) {
+	tournamentRoutes := rt.group(api, "tournaments", "/tournaments", rt.auth())
+	rt.handle(tournamentRoutes, scope.WriteTournaments, "", rt.tournamentHandler.CreateTournament).Methods("POST")
+	rt.handle(tournamentRoutes, scope.ReadTournaments, "/{id}", rt.tournamentHandler.GetTournament).Methods("GET")
+	rt.handle(tournamentRoutes, scope.WriteTournaments, "/{id}/matches/{matchId}/result", rt.tournamentHandler.ReportMatchResult).Methods("POST")
+	rt.handle(tournamentRoutes, scope.WriteTournaments, "/{id}/rounds/{round}/ttr", rt.tournamentHandler.CreateRoundTTR).Methods("POST")
+}
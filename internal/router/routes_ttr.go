@@ -0,0 +1,120 @@
+package router
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func (rt *Router) registerTTRRoutes(api *mux.Router, version string) {
+	ttrRoutes := rt.group(api, "ttrs", "/ttrs", rt.auth())
+	rt.handle(ttrRoutes, scope.WriteTTRs, "", rt.ttrHandler.CreateTTR).Methods("POST")
+	rt.handle(ttrRoutes, scope.ReadTTRs, "", byVersion(version, rt.ttrHandler.SearchTTRs, rt.ttrHandler.SearchTTRsV2)).Methods("GET")
+	rt.handle(ttrRoutes, scope.ReadTTRs, "/trash", rt.ttrHandler.ListDeletedTTRs).Methods("GET")
+	rt.handle(ttrRoutes, scope.ReadTTRs, "/{id}", rt.ttrHandler.GetTTR).Methods("GET")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}", rt.ttrHandler.UpdateTTR).Methods("PUT")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}", rt.ttrHandler.DeleteTTR).Methods("DELETE")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/restore", rt.ttrHandler.RestoreTTR).Methods("POST")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/co-captains", rt.ttrHandler.AddCoCaptain).Methods("POST")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/co-captains/{userId}", rt.ttrHandler.UpdateCoCaptain).Methods("PUT")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/co-captains/{userId}", rt.ttrHandler.RemoveCoCaptain).Methods("DELETE")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/join", rt.ttrHandler.JoinTTR).Methods("POST")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/leave", rt.ttrHandler.LeaveTTR).Methods("POST")
+	rt.handle(ttrRoutes, scope.ReadTTRs, "/{id}/players", rt.ttrHandler.GetPlayers).Methods("GET")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/guests", rt.ttrHandler.AddGuest).Methods("POST")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/guests/{guestId}", rt.ttrHandler.RemoveGuest).Methods("DELETE")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/players/{userId}", rt.ttrHandler.UpdatePlayerStatus).Methods("PUT")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/pairings", rt.ttrHandler.SetPairings).Methods("PUT")
+	rt.handle(ttrRoutes, scope.WriteTTRs, "/{id}/slots", rt.ttrHandler.SetTeeSlots).Methods("PUT")
+}
+
+func (rt *Router) registerScheduleRoutes(api *mux.Router) {
+	scheduleRoutes := rt.group(api, "schedule", "/ttrs/schedule", rt.auth())
+	rt.handle(scheduleRoutes, scope.ReadTTRs, "", rt.scheduleHandler.GetSchedule).Methods("GET")
+}
+
+func (rt *Router) registerInvitationRoutes(api *mux.Router) {
+	invitationRoutes := rt.group(api, "invitations", "/invitations", rt.auth())
+	rt.handle(invitationRoutes, scope.WriteInvitations, "", rt.invitationHandler.CreateInvitation).Methods("POST")
+	rt.handle(invitationRoutes, scope.ReadInvitations, "/me", rt.invitationHandler.GetMyInvitations).Methods("GET")
+	rt.handle(invitationRoutes, scope.ReadInvitations, "/{id}", rt.invitationHandler.GetInvitation).Methods("GET")
+	rt.handle(invitationRoutes, scope.WriteInvitations, "/{id}/respond", rt.invitationHandler.RespondToInvitation).Methods("PUT")
+	rt.handle(invitationRoutes, scope.WriteInvitations, "/{id}/forward", rt.invitationHandler.ForwardInvitation).Methods("POST")
+	rt.handle(invitationRoutes, scope.WriteInvitations, "/{id}/approve", rt.invitationHandler.ApproveInvitation).Methods("PUT")
+	rt.handle(invitationRoutes, scope.WriteInvitations, "/{id}", rt.invitationHandler.CancelInvitation).Methods("DELETE")
+
+	ttrInvitationRoutes := rt.group(api, "ttr-invitations", "/ttrs", rt.auth())
+	rt.handle(ttrInvitationRoutes, scope.ReadInvitations, "/{id}/invitations", rt.invitationHandler.GetTTRInvitations).Methods("GET")
+}
+
+func (rt *Router) registerInvitationTemplateRoutes(api *mux.Router) {
+	templateRoutes := rt.group(api, "invitation-templates", "/invitation-templates", rt.auth())
+	rt.handle(templateRoutes, scope.WriteInvitations, "", rt.templateHandler.CreateInvitationTemplate).Methods("POST")
+	rt.handle(templateRoutes, scope.ReadInvitations, "", rt.templateHandler.ListInvitationTemplates).Methods("GET")
+	rt.handle(templateRoutes, scope.ReadInvitations, "/{id}", rt.templateHandler.GetInvitationTemplate).Methods("GET")
+	rt.handle(templateRoutes, scope.WriteInvitations, "/{id}", rt.templateHandler.UpdateInvitationTemplate).Methods("PUT")
+	rt.handle(templateRoutes, scope.WriteInvitations, "/{id}", rt.templateHandler.DeleteInvitationTemplate).Methods("DELETE")
+}
+
+func (rt *Router) registerMessageRoutes(api *mux.Router) {
+	messageRoutes := rt.group(api, "messages", "/ttrs", rt.auth())
+	rt.handle(messageRoutes, scope.ReadMessages, "/me/unread-counts", rt.messageHandler.GetUnreadCounts).Methods("GET")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages", rt.messageHandler.PostMessage).Methods("POST")
+	rt.handle(messageRoutes, scope.ReadMessages, "/{id}/messages", rt.messageHandler.GetMessages).Methods("GET")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/read", rt.messageHandler.MarkMessagesRead).Methods("POST")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/attachments", rt.messageHandler.PostAttachment).Methods("POST")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/{messageId}", rt.messageHandler.UpdateMessage).Methods("PUT")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/{messageId}", rt.messageHandler.DeleteMessage).Methods("DELETE")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/{messageId}/reactions", rt.messageHandler.AddReaction).Methods("POST")
+	rt.handle(messageRoutes, scope.WriteMessages, "/{id}/messages/{messageId}/reactions", rt.messageHandler.RemoveReaction).Methods("DELETE")
+}
+
+func (rt *Router) registerSuggestionRoutes(api *mux.Router) {
+	suggestionRoutes := rt.group(api, "suggestions", "/ttrs", rt.auth())
+	rt.handle(suggestionRoutes, scope.ReadTTRs, "/{id}/suggested-players", rt.suggestionHandler.GetSuggestedPlayers).Methods("GET")
+}
+
+func (rt *Router) registerPairingRoutes(api *mux.Router) {
+	pairingRoutes := rt.group(api, "pairings", "/ttrs", rt.auth())
+	rt.handle(pairingRoutes, scope.ReadTTRs, "/{id}/pairings/suggest", rt.pairingHandler.SuggestPairings).Methods("GET")
+}
+
+func (rt *Router) registerInviteLinkRoutes(api *mux.Router) {
+	linkRoutes := rt.group(api, "invite-links", "/ttrs", rt.auth())
+	rt.handle(linkRoutes, scope.WriteTTRs, "/{id}/invite-links", rt.inviteLinkHandler.CreateInviteLink).Methods("POST")
+	rt.handle(linkRoutes, scope.ReadTTRs, "/{id}/invite-links", rt.inviteLinkHandler.ListInviteLinks).Methods("GET")
+	rt.handle(linkRoutes, scope.WriteTTRs, "/{id}/invite-links/{linkId}", rt.inviteLinkHandler.RevokeInviteLink).Methods("DELETE")
+
+	publicRoutes := rt.group(api, "invite-link-previews", "/public")
+	rt.handlePublic(publicRoutes, "/invite-links/{token}", rt.inviteLinkHandler.PreviewInviteLink).Methods("GET")
+
+	acceptRoutes := rt.group(api, "invite-link-accepts", "/invite-links", rt.auth())
+	rt.handle(acceptRoutes, scope.WriteTTRs, "/{token}/accept", rt.inviteLinkHandler.AcceptInviteLink).Methods("POST")
+}
+
+func (rt *Router) registerActionItemRoutes(api *mux.Router) {
+	meRoutes := rt.group(api, "action-items", "/me", rt.auth())
+	rt.handle(meRoutes, scope.ReadProfile, "/action-items", rt.actionItemHandler.ListActionItems).Methods("GET")
+}
+
+func (rt *Router) registerDashboardRoutes(api *mux.Router) {
+	dashboardRoutes := rt.group(api, "dashboard", "/dashboard", rt.auth())
+	rt.handle(dashboardRoutes, scope.ReadProfile, "", rt.dashboardHandler.GetDashboard).Methods("GET")
+}
+
+// registerGraphQLRoutes mounts the GraphQL endpoint. It reads TTRs, so it
+// needs read:ttrs; fields reading anything else check their own scope.
+func (rt *Router) registerGraphQLRoutes(api *mux.Router) {
+	graphqlRoutes := rt.group(api, "graphql", "/graphql", rt.auth())
+	rt.handle(graphqlRoutes, scope.ReadTTRs, "", rt.graphqlHandler.Query).Methods("POST")
+}
+
+func (rt *Router) registerChangeFeedRoutes(api *mux.Router) {
+	changeRoutes := rt.group(api, "change-feed", "/ttrs", rt.auth())
+	rt.handle(changeRoutes, scope.ReadTTRs, "/{id}/changes", rt.changeFeedHandler.ListChanges).Methods("GET")
+}
+
+func (rt *Router) registerCheckInRoutes(api *mux.Router) {
+	checkInRoutes := rt.group(api, "check-ins", "/ttrs", rt.auth())
+	rt.handle(checkInRoutes, scope.WriteTTRs, "/{id}/check-in", rt.checkInHandler.CheckIn).Methods("POST")
+	rt.handle(checkInRoutes, scope.ReadTTRs, "/{id}/check-ins", rt.checkInHandler.ListCheckIns).Methods("GET")
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+// DefaultActionItemHorizon is how far ahead ListActionItems looks for
+// captained TTRs with unanswered invitations.
+const DefaultActionItemHorizon = 72 * time.Hour
+
+// Kinds of action items, in the order they are listed when due at the same
+// time.
+const (
+	ActionItemPendingInvitation     = "pending_invitation"
+	ActionItemUnansweredInvitations = "unanswered_invitations"
+	ActionItemMaybeStatus           = "maybe_status"
+)
+
+var actionItemKindOrder = map[string]int{
+	ActionItemPendingInvitation:     0,
+	ActionItemUnansweredInvitations: 1,
+	ActionItemMaybeStatus:           2,
+}
+
+// ActionItem is something the user has to do about a TTR. InvitationID is set
+// for pending_invitation items. DueAt is the TTR's RSVP deadline when it is
+// still ahead, otherwise its tee time.
+type ActionItem struct {
+	Kind         string
+	TTRID        uuid.UUID
+	InvitationID *uuid.UUID
+	CourseName   string
+	DueAt        time.Time
+}
+
+type ActionItemService struct {
+	actionItemRepo repository.ActionItemRepository
+}
+
+func NewActionItemService(actionItemRepo repository.ActionItemRepository) *ActionItemService {
+	return &ActionItemService{actionItemRepo: actionItemRepo}
+}
+
+// ListActionItems lists what userID has to act on, most urgent first:
+// invitations waiting for their answer, TTRs they captain that tee off within
+// horizon and still have unanswered invitations, and TTRs they are a MAYBE
+// on. Rounds that already teed off are left out, and so are invitations past
+// their RSVP deadline.
+func (s *ActionItemService) ListActionItems(ctx context.Context, userID uuid.UUID, horizon time.Duration) ([]ActionItem, error) {
+	if horizon <= 0 {
+		horizon = DefaultActionItemHorizon
+	}
+	now := time.Now()
+	// Tee dates are stored without a time zone, so the queries start a day
+	// early and the tee times are checked here.
+	from := now.AddDate(0, 0, -1).Truncate(24 * time.Hour)
+	until := now.Add(horizon)
+
+	invitations, err := s.actionItemRepo.PendingInvitations(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+	awaiting, err := s.actionItemRepo.AwaitingAnswers(ctx, userID, from, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+	maybes, err := s.actionItemRepo.MaybeRounds(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list action items: %w", err)
+	}
+
+	items := make([]ActionItem, 0, len(invitations)+len(awaiting)+len(maybes))
+	for _, invitation := range invitations {
+		ttr := invitation.TTR
+		if ttr == nil || !ttr.TeeDateTime().After(now) || ttr.RSVPDeadlinePassed(now) {
+			continue
+		}
+		invitationID := invitation.ID
+		items = append(items, newActionItem(ActionItemPendingInvitation, ttr, &invitationID, now))
+	}
+	for _, ttr := range awaiting {
+		if teeOff := ttr.TeeDateTime(); !teeOff.After(now) || teeOff.After(until) {
+			continue
+		}
+		items = append(items, newActionItem(ActionItemUnansweredInvitations, ttr, nil, now))
+	}
+	for _, ttr := range maybes {
+		if !ttr.TeeDateTime().After(now) {
+			continue
+		}
+		items = append(items, newActionItem(ActionItemMaybeStatus, ttr, nil, now))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].DueAt.Equal(items[j].DueAt) {
+			return items[i].DueAt.Before(items[j].DueAt)
+		}
+		if items[i].Kind != items[j].Kind {
+			return actionItemKindOrder[items[i].Kind] < actionItemKindOrder[items[j].Kind]
+		}
+		return items[i].TTRID.String() < items[j].TTRID.String()
+	})
+	return items, nil
+}
+
+func newActionItem(kind string, ttr *models.TTR, invitationID *uuid.UUID, now time.Time) ActionItem {
+	dueAt := ttr.TeeDateTime()
+	if ttr.RSVPDeadline != nil && ttr.RSVPDeadline.After(now) && ttr.RSVPDeadline.Before(dueAt) {
+		dueAt = *ttr.RSVPDeadline
+	}
+	return ActionItem{
+		Kind:         kind,
+		TTRID:        ttr.ID,
+		InvitationID: invitationID,
+		CourseName:   ttr.CourseName,
+		DueAt:        dueAt,
+	}
+}
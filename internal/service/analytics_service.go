@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/analytics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+// analyticsBatchSize caps how many events are handed to the publisher at once.
+const analyticsBatchSize = 100
+
+// AnalyticsService exports domain events for product analytics. Events are
+// queued without blocking the request that caused them and published in the
+// background by Run; when the queue is full they are dropped and counted.
+type AnalyticsService struct {
+	publisher  analytics.EventPublisher
+	anonymizer analytics.Anonymizer
+	queue      chan analytics.Event
+	dropped    atomic.Int64
+	dropCount  metric.Int64Counter
+	logger     *zap.Logger
+}
+
+// NewAnalyticsService creates the analytics service. Up to bufferSize events
+// wait for publisher; user IDs are anonymized with salt.
+func NewAnalyticsService(publisher analytics.EventPublisher, salt string, bufferSize int, logger *zap.Logger) *AnalyticsService {
+	dropCount, err := otel.Meter("github.com/yourusername/golf_messenger").Int64Counter(
+		"analytics.events.dropped",
+		metric.WithDescription("Analytics events dropped because the export queue was full"),
+	)
+	if err != nil {
+		logger.Error("Failed to create analytics drop counter", zap.Error(err))
+	}
+	return &AnalyticsService{
+		publisher:  publisher,
+		anonymizer: analytics.NewAnonymizer(salt),
+		queue:      make(chan analytics.Event, bufferSize),
+		dropCount:  dropCount,
+		logger:     logger,
+	}
+}
+
+// Dropped returns how many events were dropped because the queue was full.
+func (s *AnalyticsService) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// TTRCreated records that the TTR was created by its captain.
+func (s *AnalyticsService) TTRCreated(ttr *models.TTR) {
+	if s == nil {
+		return
+	}
+	s.publish(analytics.Event{
+		Type:  analytics.EventTTRCreated,
+		TTRID: ttr.ID,
+		Actor: s.anonymizer.User(ttr.CaptainUserID),
+		Properties: map[string]string{
+			"tee_at":       ttr.TeeDateTime().Format(time.RFC3339),
+			"max_players":  strconv.Itoa(ttr.MaxPlayers),
+			"visibility":   ttr.Visibility,
+			"organization": strconv.FormatBool(ttr.OrganizationID != nil),
+		},
+	})
+}
+
+// TTRCompleted records that userID marked the TTR completed, with the
+// confirmed players who played it.
+func (s *AnalyticsService) TTRCompleted(ttr *models.TTR, userID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	players := make([]string, 0, len(ttr.Players))
+	for _, player := range ttr.Players {
+		if player.Status == models.TTRPlayerStatusConfirmed {
+			players = append(players, s.anonymizer.User(player.UserID))
+		}
+	}
+	s.publish(analytics.Event{
+		Type:    analytics.EventTTRCompleted,
+		TTRID:   ttr.ID,
+		Actor:   s.anonymizer.User(userID),
+		Players: players,
+	})
+}
+
+// InvitationSent records that the inviter invited the invitee.
+func (s *AnalyticsService) InvitationSent(invitation *models.Invitation) {
+	if s == nil {
+		return
+	}
+	s.publish(analytics.Event{
+		Type:         analytics.EventInvitationSent,
+		TTRID:        invitation.TTRID,
+		InvitationID: &invitation.ID,
+		Actor:        s.anonymizer.User(invitation.InviterUserID),
+		Subject:      s.anonymizer.User(invitation.InviteeUserID),
+	})
+}
+
+// InvitationResponded records the invitee's answer to the invitation.
+func (s *AnalyticsService) InvitationResponded(invitation *models.Invitation) {
+	if s == nil {
+		return
+	}
+	s.publish(analytics.Event{
+		Type:         analytics.EventInvitationResponded,
+		TTRID:        invitation.TTRID,
+		InvitationID: &invitation.ID,
+		Actor:        s.anonymizer.User(invitation.InviteeUserID),
+		Properties:   map[string]string{"status": string(invitation.Status)},
+	})
+}
+
+// PlayerJoined records that userID joined the TTR's roster.
+func (s *AnalyticsService) PlayerJoined(ttrID uuid.UUID, userID uuid.UUID) {
+	if s == nil {
+		return
+	}
+	s.publish(analytics.Event{
+		Type:  analytics.EventPlayerJoined,
+		TTRID: ttrID,
+		Actor: s.anonymizer.User(userID),
+	})
+}
+
+func (s *AnalyticsService) publish(event analytics.Event) {
+	event.ID = uuid.New()
+	event.OccurredAt = time.Now().UTC()
+	select {
+	case s.queue <- event:
+	default:
+		s.dropped.Add(1)
+		if s.dropCount != nil {
+			s.dropCount.Add(context.Background(), 1)
+		}
+	}
+}
+
+// Run publishes queued events in batches until ctx is canceled, then
+// publishes what is still queued and closes the publisher. A batch that fails
+// to publish is logged and lost.
+func (s *AnalyticsService) Run(ctx context.Context) error {
+	defer func() {
+		if err := s.publisher.Close(); err != nil {
+			s.logger.Error("Failed to close analytics publisher", zap.Error(err))
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Whatever was queued before shutdown still goes out.
+			s.flush(context.WithoutCancel(ctx))
+			return nil
+		case event := <-s.queue:
+			s.publishBatch(ctx, s.batch(event))
+		}
+	}
+}
+
+// batch returns first followed by the events already waiting, up to
+// analyticsBatchSize.
+func (s *AnalyticsService) batch(first analytics.Event) []analytics.Event {
+	events := []analytics.Event{first}
+	for len(events) < analyticsBatchSize {
+		select {
+		case event := <-s.queue:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+func (s *AnalyticsService) flush(ctx context.Context) {
+	for {
+		select {
+		case event := <-s.queue:
+			s.publishBatch(ctx, s.batch(event))
+		default:
+			return
+		}
+	}
+}
+
+func (s *AnalyticsService) publishBatch(ctx context.Context, events []analytics.Event) {
+	if err := s.publisher.Publish(ctx, events); err != nil {
+		s.logger.Error("Failed to publish analytics events", zap.Int("count", len(events)), zap.Error(err))
+	}
+}
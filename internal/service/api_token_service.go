@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"go.uber.org/zap"
+)
+
+// apiTokenLastUsedInterval is how stale an API token's LastUsedAt can get
+// before a request records it again, so busy scripts don't write on every
+// call.
+const apiTokenLastUsedInterval = 5 * time.Minute
+
+// apiTokenDisplayLength is how much of a token, prefix included, is kept to
+// tell tokens apart in lists.
+const apiTokenDisplayLength = 12
+
+type APITokenService struct {
+	apiTokenRepo repository.APITokenRepository
+	logger       *zap.Logger
+}
+
+func NewAPITokenService(apiTokenRepo repository.APITokenRepository, logger *zap.Logger) *APITokenService {
+	return &APITokenService{
+		apiTokenRepo: apiTokenRepo,
+		logger:       logger,
+	}
+}
+
+// CreateAPIToken issues a token for the user granting scopes. The token
+// itself is returned only here; afterwards only its prefix is known.
+func (s *APITokenService) CreateAPIToken(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresAt *time.Time) (*models.APIToken, string, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", ErrInvalidAPITokenExpiry
+	}
+	granted, err := scope.ParseList(scopes)
+	if err != nil || len(granted) == 0 {
+		return nil, "", ErrInvalidAPITokenScope
+	}
+	scopeNames := make([]string, 0, len(granted))
+	for _, g := range granted {
+		scopeNames = append(scopeNames, string(g))
+	}
+
+	token, err := newAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiToken := &models.APIToken{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    token[:apiTokenDisplayLength],
+		TokenHash: hashAPIToken(token),
+		Scopes:    strings.Join(scopeNames, ","),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.apiTokenRepo.Create(ctx, apiToken); err != nil {
+		return nil, "", fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return apiToken, token, nil
+}
+
+func (s *APITokenService) ListAPITokens(ctx context.Context, userID uuid.UUID) ([]*models.APIToken, error) {
+	tokens, err := s.apiTokenRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken deletes one of the user's tokens. Requests using it fail
+// from then on.
+func (s *APITokenService) RevokeAPIToken(ctx context.Context, userID uuid.UUID, tokenID uuid.UUID) error {
+	token, err := s.apiTokenRepo.FindByID(ctx, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to find API token: %w", err)
+	}
+	if token == nil || token.UserID != userID {
+		return ErrAPITokenNotFound
+	}
+
+	if err := s.apiTokenRepo.Delete(ctx, tokenID); err != nil {
+		return fmt.Errorf("failed to revoke API token: %w", err)
+	}
+	return nil
+}
+
+// AuthenticateAPIToken returns the stored token, with its user, or nil when
+// the token is unknown, revoked, expired or its user is gone.
+func (s *APITokenService) AuthenticateAPIToken(ctx context.Context, token string) (*models.APIToken, error) {
+	if !strings.HasPrefix(token, models.APITokenPrefix) {
+		return nil, nil
+	}
+
+	apiToken, err := s.apiTokenRepo.FindByTokenHash(ctx, hashAPIToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find API token: %w", err)
+	}
+	now := time.Now()
+	if apiToken == nil || apiToken.User == nil || apiToken.IsExpired(now) {
+		return nil, nil
+	}
+
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) >= apiTokenLastUsedInterval {
+		// Losing a last-used time is not worth failing the request over.
+		if err := s.apiTokenRepo.UpdateLastUsed(ctx, apiToken.ID, now); err != nil {
+			s.logger.Warn("Failed to record API token use", zap.String("token_id", apiToken.ID.String()), zap.Error(err))
+		}
+	}
+
+	return apiToken, nil
+}
+
+func newAPIToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return models.APITokenPrefix + base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+func hashAPIToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -1,18 +1,23 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/repository"
 	"github.com/yourusername/golf_messenger/pkg/jwt"
+	"github.com/yourusername/golf_messenger/pkg/password"
 )
 
 type AuthService struct {
 	userRepo         repository.UserRepository
 	refreshTokenRepo repository.RefreshTokenRepository
+	securityEvents   *SecurityEventService
+	passwords        password.Policy
 	jwtSecret        string
 	accessDuration   time.Duration
 	refreshDuration  time.Duration
@@ -21,6 +26,8 @@ type AuthService struct {
 func NewAuthService(
 	userRepo repository.UserRepository,
 	refreshTokenRepo repository.RefreshTokenRepository,
+	securityEvents *SecurityEventService,
+	passwords password.Policy,
 	jwtSecret string,
 	accessDuration time.Duration,
 	refreshDuration time.Duration,
@@ -28,23 +35,31 @@ func NewAuthService(
 	return &AuthService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		securityEvents:   securityEvents,
+		passwords:        passwords,
 		jwtSecret:        jwtSecret,
 		accessDuration:   accessDuration,
 		refreshDuration:  refreshDuration,
 	}
 }
 
-func (s *AuthService) Register(email, password, firstName, lastName string) (*models.User, *jwt.TokenPair, error) {
-	existingUser, err := s.userRepo.FindByEmail(email)
+// Register creates an account. A password breaking the password policy is
+// a *PasswordPolicyError.
+func (s *AuthService) Register(ctx context.Context, email, password, firstName, lastName string) (*models.User, *jwt.TokenPair, error) {
+	if err := checkPassword(s.passwords, password, email); err != nil {
+		return nil, nil, err
+	}
+
+	existingUser, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
 	if existingUser != nil {
-		return nil, nil, errors.New("user with this email already exists")
+		return nil, nil, ErrEmailTaken
 	}
 
 	user := &models.User{
-		Email:     email,
+		Email:     strings.TrimSpace(email),
 		FirstName: firstName,
 		LastName:  lastName,
 	}
@@ -53,11 +68,19 @@ func (s *AuthService) Register(email, password, firstName, lastName string) (*mo
 		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		// A concurrent registration can get past the check above.
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, nil, ErrEmailTaken
+		}
 		return nil, nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	tokenPair, err := s.createTokenPair(user)
+	if err := s.securityEvents.RecordLogin(ctx, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	tokenPair, err := s.createTokenPair(ctx, user)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create tokens: %w", err)
 	}
@@ -65,20 +88,32 @@ func (s *AuthService) Register(email, password, firstName, lastName string) (*mo
 	return user, tokenPair, nil
 }
 
-func (s *AuthService) Login(email, password string) (*models.User, *jwt.TokenPair, error) {
-	user, err := s.userRepo.FindByEmail(email)
+func (s *AuthService) Login(ctx context.Context, email, password string) (*models.User, *jwt.TokenPair, error) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, nil, errors.New("invalid email or password")
+		return nil, nil, ErrInvalidCredentials
 	}
 
 	if !user.CheckPassword(password) {
-		return nil, nil, errors.New("invalid email or password")
+		if err := s.securityEvents.Record(ctx, user.ID, models.SecurityEventLoginFailed); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID, now); err != nil {
+		return nil, nil, err
+	}
+	user.LastLoginAt = &now
+	if err := s.securityEvents.RecordLogin(ctx, user.ID); err != nil {
+		return nil, nil, err
 	}
 
-	tokenPair, err := s.createTokenPair(user)
+	tokenPair, err := s.createTokenPair(ctx, user)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create tokens: %w", err)
 	}
@@ -86,26 +121,31 @@ func (s *AuthService) Login(email, password string) (*models.User, *jwt.TokenPai
 	return user, tokenPair, nil
 }
 
-func (s *AuthService) RefreshToken(refreshToken string) (*jwt.TokenPair, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
 	tokenHash := jwt.HashRefreshToken(refreshToken)
 
-	storedToken, err := s.refreshTokenRepo.FindByTokenHash(tokenHash)
+	storedToken, err := s.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find refresh token: %w", err)
 	}
 	if storedToken == nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, ErrInvalidRefreshToken
 	}
 
 	if !storedToken.IsValid() {
-		return nil, errors.New("refresh token is invalid or expired")
+		return nil, ErrRefreshTokenExpired
 	}
 
-	if err := s.refreshTokenRepo.RevokeByUserID(storedToken.UserID); err != nil {
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, storedToken.UserID); err != nil {
 		return nil, fmt.Errorf("failed to revoke old tokens: %w", err)
 	}
 
-	tokenPair, err := s.createTokenPair(storedToken.User)
+	// A refresh is the app opening again, so it counts as logging in.
+	if err := s.userRepo.UpdateLastLogin(ctx, storedToken.UserID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	tokenPair, err := s.createTokenPair(ctx, storedToken.User)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new tokens: %w", err)
 	}
@@ -113,26 +153,26 @@ func (s *AuthService) RefreshToken(refreshToken string) (*jwt.TokenPair, error)
 	return tokenPair, nil
 }
 
-func (s *AuthService) Logout(refreshToken string) error {
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
 	tokenHash := jwt.HashRefreshToken(refreshToken)
 
-	storedToken, err := s.refreshTokenRepo.FindByTokenHash(tokenHash)
+	storedToken, err := s.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
 	if err != nil {
 		return fmt.Errorf("failed to find refresh token: %w", err)
 	}
 	if storedToken == nil {
-		return errors.New("invalid refresh token")
+		return ErrInvalidRefreshToken
 	}
 
-	if err := s.refreshTokenRepo.RevokeByUserID(storedToken.UserID); err != nil {
+	if err := s.refreshTokenRepo.RevokeByUserID(ctx, storedToken.UserID); err != nil {
 		return fmt.Errorf("failed to revoke tokens: %w", err)
 	}
 
-	return nil
+	return s.securityEvents.Record(ctx, storedToken.UserID, models.SecurityEventTokensRevoked)
 }
 
-func (s *AuthService) createTokenPair(user *models.User) (*jwt.TokenPair, error) {
-	accessToken, err := jwt.GenerateAccessToken(user.ID, user.Email, s.jwtSecret, s.accessDuration)
+func (s *AuthService) createTokenPair(ctx context.Context, user *models.User) (*jwt.TokenPair, error) {
+	accessToken, err := jwt.GenerateAccessToken(user.ID, user.Email, user.Role, s.jwtSecret, s.accessDuration)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -150,7 +190,7 @@ func (s *AuthService) createTokenPair(user *models.User) (*jwt.TokenPair, error)
 		Revoked:   false,
 	}
 
-	if err := s.refreshTokenRepo.Create(refreshTokenModel); err != nil {
+	if err := s.refreshTokenRepo.Create(ctx, refreshTokenModel); err != nil {
 		return nil, fmt.Errorf("failed to store refresh token: %w", err)
 	}
 
@@ -160,3 +200,12 @@ func (s *AuthService) createTokenPair(user *models.User) (*jwt.TokenPair, error)
 		ExpiresAt:    expiresAt.Unix(),
 	}, nil
 }
+
+// checkPassword returns a *PasswordPolicyError when newPassword, chosen by
+// the user with email, breaks policy.
+func checkPassword(policy password.Policy, newPassword, email string) error {
+	if rules := policy.Check(newPassword, email); len(rules) > 0 {
+		return &PasswordPolicyError{Rules: rules, MinLength: policy.MinLength}
+	}
+	return nil
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+// Action is something a user may try to do to a TTR or an invitation.
+type Action string
+
+const (
+	// ActionTTRList covers seeing a TTR in listings: participants always see
+	// it, others depending on its visibility. Organization TTRs are only
+	// listed for the organization's members.
+	ActionTTRList Action = "ttr.list"
+	// ActionTTRView covers reading a TTR: participants always, anyone else
+	// only while it is OPEN and listed for them.
+	ActionTTRView             Action = "ttr.view"
+	ActionTTRJoin             Action = "ttr.join"
+	ActionTTRUpdate           Action = "ttr.update"
+	ActionTTRDelete           Action = "ttr.delete"
+	ActionTTRManageCoCaptains Action = "ttr.manage_co_captains"
+	ActionTTRManagePlayers    Action = "ttr.manage_players"
+	ActionTTRInvite           Action = "ttr.invite"
+	ActionTTRChat             Action = "ttr.chat"
+	ActionTTRModerateChat     Action = "ttr.moderate_chat"
+
+	ActionInvitationView    Action = "invitation.view"
+	ActionInvitationRespond Action = "invitation.respond"
+	ActionInvitationCancel  Action = "invitation.cancel"
+)
+
+// coCaptainPermissions is the permission a co-captain needs for each action
+// the captain and the organization's admins can always take.
+var coCaptainPermissions = map[Action]string{
+	ActionTTRUpdate:        models.CoCaptainPermissionEditDetails,
+	ActionTTRManagePlayers: models.CoCaptainPermissionManagePlayers,
+	ActionTTRInvite:        models.CoCaptainPermissionInvite,
+}
+
+// Authorizer answers permission questions about TTRs and invitations, so
+// every service applies the same rules. TTR checks read the roster from the
+// TTR itself, so TTRs should come from TTR, which preloads it.
+type Authorizer struct {
+	ttrRepo        repository.TTRRepository
+	orgRepo        repository.OrganizationRepository
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+}
+
+func NewAuthorizer(ttrRepo repository.TTRRepository, orgRepo repository.OrganizationRepository, invitationRepo repository.InvitationRepository, userRepo repository.UserRepository) *Authorizer {
+	return &Authorizer{
+		ttrRepo:        ttrRepo,
+		orgRepo:        orgRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+	}
+}
+
+type ttrMemoKey struct{}
+
+type ttrMemo struct {
+	mu   sync.Mutex
+	ttrs map[uuid.UUID]*models.TTR
+}
+
+// WithTTRMemo returns a context in which Authorizer.TTR loads each TTR once,
+// so a request that checks permissions and then works on the TTR only
+// fetches it a single time. It is meant to wrap one request.
+func WithTTRMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ttrMemoKey{}, &ttrMemo{ttrs: make(map[uuid.UUID]*models.TTR)})
+}
+
+// TTR loads the TTR with its roster, or returns ErrTTRNotFound. Within a
+// WithTTRMemo context repeated calls return the same TTR.
+func (a *Authorizer) TTR(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
+	memo, _ := ctx.Value(ttrMemoKey{}).(*ttrMemo)
+	if memo != nil {
+		memo.mu.Lock()
+		defer memo.mu.Unlock()
+		if ttr, ok := memo.ttrs[id]; ok {
+			return ttr, nil
+		}
+	}
+
+	ttr, err := a.ttrRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TTR: %w", err)
+	}
+	if ttr == nil {
+		return nil, ErrTTRNotFound
+	}
+
+	if memo != nil {
+		memo.ttrs[id] = ttr
+	}
+	return ttr, nil
+}
+
+// TTRs is TTR for several TTRs, fetching those not yet loaded in one query.
+// TTRs that don't exist are left out.
+func (a *Authorizer) TTRs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*models.TTR, error) {
+	ttrs := make(map[uuid.UUID]*models.TTR, len(ids))
+	memo, _ := ctx.Value(ttrMemoKey{}).(*ttrMemo)
+	if memo != nil {
+		memo.mu.Lock()
+		defer memo.mu.Unlock()
+	}
+
+	missing := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if memo != nil {
+			if ttr, ok := memo.ttrs[id]; ok {
+				ttrs[id] = ttr
+				continue
+			}
+		}
+		missing = append(missing, id)
+	}
+	if len(missing) == 0 {
+		return ttrs, nil
+	}
+
+	found, err := a.ttrRepo.FindByIDs(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TTRs: %w", err)
+	}
+	for _, ttr := range found {
+		ttrs[ttr.ID] = ttr
+		if memo != nil {
+			memo.ttrs[ttr.ID] = ttr
+		}
+	}
+	return ttrs, nil
+}
+
+// Can reports whether userID may perform action on resource, which is a
+// *models.TTR for ttr.* actions and a *models.Invitation for invitation.*
+// actions.
+func (a *Authorizer) Can(ctx context.Context, userID uuid.UUID, action Action, resource interface{}) (bool, error) {
+	switch r := resource.(type) {
+	case *models.TTR:
+		return a.canTTR(ctx, userID, action, r)
+	case *models.Invitation:
+		return a.canInvitation(ctx, userID, action, r)
+	default:
+		return false, fmt.Errorf("unsupported resource %T for action %s", resource, action)
+	}
+}
+
+func (a *Authorizer) canTTR(ctx context.Context, userID uuid.UUID, action Action, ttr *models.TTR) (bool, error) {
+	switch action {
+	case ActionTTRList, ActionTTRJoin:
+		return a.isListed(ctx, ttr, userID)
+	case ActionTTRView:
+		isParticipant, err := a.IsParticipant(ctx, ttr, userID)
+		if err != nil || isParticipant {
+			return isParticipant, err
+		}
+		if ttr.Status != models.TTRStatusOpen {
+			return false, nil
+		}
+		return a.isListed(ctx, ttr, userID)
+	case ActionTTRUpdate, ActionTTRManagePlayers, ActionTTRInvite:
+		if ttr.CaptainUserID == userID || ttr.CoCaptainCan(userID, coCaptainPermissions[action]) {
+			return true, nil
+		}
+		return a.isOrganizationAdmin(ctx, ttr, userID)
+	case ActionTTRModerateChat:
+		if ttr.CaptainUserID == userID || ttr.HasCoCaptain(userID) {
+			return true, nil
+		}
+		return a.isOrganizationAdmin(ctx, ttr, userID)
+	case ActionTTRDelete:
+		if ttr.CaptainUserID == userID {
+			return true, nil
+		}
+		return a.isOrganizationAdmin(ctx, ttr, userID)
+	case ActionTTRManageCoCaptains:
+		return ttr.CaptainUserID == userID, nil
+	case ActionTTRChat:
+		return ttr.CaptainUserID == userID || ttr.HasCoCaptain(userID) || ttr.HasPlayer(userID), nil
+	default:
+		return false, fmt.Errorf("unsupported TTR action %s", action)
+	}
+}
+
+func (a *Authorizer) canInvitation(ctx context.Context, userID uuid.UUID, action Action, invitation *models.Invitation) (bool, error) {
+	switch action {
+	case ActionInvitationView:
+		if invitation.InviterUserID == userID || invitation.InviteeUserID == userID {
+			return true, nil
+		}
+		// Only the inviter and invitee can still read invitations to a
+		// deleted TTR.
+		ttr, err := a.TTR(ctx, invitation.TTRID)
+		if err != nil {
+			if errors.Is(err, ErrTTRNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		canInvite, err := a.canTTR(ctx, userID, ActionTTRInvite, ttr)
+		if err != nil || canInvite {
+			return canInvite, err
+		}
+		return a.canTTR(ctx, userID, ActionTTRManagePlayers, ttr)
+	case ActionInvitationRespond:
+		return invitation.InviteeUserID == userID, nil
+	case ActionInvitationCancel:
+		return invitation.InviterUserID == userID, nil
+	default:
+		return false, fmt.Errorf("unsupported invitation action %s", action)
+	}
+}
+
+// IsParticipant reports whether userID is the TTR's captain, a co-captain, a
+// player or has a pending invitation to it.
+func (a *Authorizer) IsParticipant(ctx context.Context, ttr *models.TTR, userID uuid.UUID) (bool, error) {
+	if ttr.CaptainUserID == userID || ttr.HasCoCaptain(userID) || ttr.HasPlayer(userID) {
+		return true, nil
+	}
+
+	invitation, err := a.invitationRepo.FindByTTRAndInvitee(ctx, ttr.ID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check invitation: %w", err)
+	}
+	return invitation != nil && invitation.Status == models.InvitationStatusPending, nil
+}
+
+// ViewTTRs checks ActionTTRView for several TTRs at once. It returns the TTRs
+// userID may view, each with whether they participate in it, and looks up
+// pending invitations to all of them in one query.
+func (a *Authorizer) ViewTTRs(ctx context.Context, ttrs []*models.TTR, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	viewable := make(map[uuid.UUID]bool, len(ttrs))
+	var others []*models.TTR
+	for _, ttr := range ttrs {
+		if ttr.CaptainUserID == userID || ttr.HasCoCaptain(userID) || ttr.HasPlayer(userID) {
+			viewable[ttr.ID] = true
+			continue
+		}
+		others = append(others, ttr)
+	}
+	if len(others) == 0 {
+		return viewable, nil
+	}
+
+	ids := make([]uuid.UUID, len(others))
+	for i, ttr := range others {
+		ids[i] = ttr.ID
+	}
+	invitations, err := a.invitationRepo.FindByTTRsAndInvitee(ctx, ids, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check invitations: %w", err)
+	}
+	invited := make(map[uuid.UUID]bool, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.Status == models.InvitationStatusPending {
+			invited[invitation.TTRID] = true
+		}
+	}
+
+	for _, ttr := range others {
+		if invited[ttr.ID] {
+			viewable[ttr.ID] = true
+			continue
+		}
+		if ttr.Status != models.TTRStatusOpen {
+			continue
+		}
+		listed, err := a.isListedToOutsider(ctx, ttr, userID)
+		if err != nil {
+			return nil, err
+		}
+		if listed {
+			viewable[ttr.ID] = false
+		}
+	}
+	return viewable, nil
+}
+
+// OrganizationMember returns userID's membership of the organization, or nil
+// if they aren't a member.
+func (a *Authorizer) OrganizationMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	member, err := a.orgRepo.FindMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check organization membership: %w", err)
+	}
+	return member, nil
+}
+
+func (a *Authorizer) isListed(ctx context.Context, ttr *models.TTR, userID uuid.UUID) (bool, error) {
+	isParticipant, err := a.IsParticipant(ctx, ttr, userID)
+	if err != nil || isParticipant {
+		return isParticipant, err
+	}
+	return a.isListedToOutsider(ctx, ttr, userID)
+}
+
+// isListedToOutsider is isListed for a user known not to participate.
+func (a *Authorizer) isListedToOutsider(ctx context.Context, ttr *models.TTR, userID uuid.UUID) (bool, error) {
+	if ttr.OrganizationID != nil {
+		member, err := a.OrganizationMember(ctx, *ttr.OrganizationID, userID)
+		if err != nil || member == nil {
+			return false, err
+		}
+	}
+
+	switch ttr.Visibility {
+	case models.TTRVisibilityPublic:
+		return true, nil
+	case models.TTRVisibilityFriends:
+		friends, err := a.userRepo.AreFriends(ctx, ttr.CaptainUserID, userID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check friendship: %w", err)
+		}
+		return friends, nil
+	default:
+		return false, nil
+	}
+}
+
+func (a *Authorizer) isOrganizationAdmin(ctx context.Context, ttr *models.TTR, userID uuid.UUID) (bool, error) {
+	if ttr.OrganizationID == nil {
+		return false, nil
+	}
+	member, err := a.OrganizationMember(ctx, *ttr.OrganizationID, userID)
+	if err != nil {
+		return false, err
+	}
+	return member != nil && member.CanAdminister(), nil
+}
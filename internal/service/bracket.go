@@ -0,0 +1,176 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+const (
+	minBracketPlayers = 2
+	maxBracketPlayers = 16
+)
+
+// NewBracket draws a single-elimination bracket for players, who are given in
+// seed order (best first). The bracket is sized to the next power of two and
+// the spare slots become byes for the top seeds: their first-round matches are
+// completed as walkovers straight away and they wait in round two.
+func NewBracket(tournamentID uuid.UUID, players []uuid.UUID) ([]*models.Match, error) {
+	if len(players) < minBracketPlayers || len(players) > maxBracketPlayers {
+		return nil, ErrInvalidFieldSize
+	}
+	seen := make(map[uuid.UUID]bool, len(players))
+	for _, player := range players {
+		if seen[player] {
+			return nil, ErrDuplicateEntrant
+		}
+		seen[player] = true
+	}
+
+	size := 2
+	for size < len(players) {
+		size *= 2
+	}
+
+	var matches []*models.Match
+	for round, count := 1, size/2; count >= 1; round, count = round+1, count/2 {
+		for position := 0; position < count; position++ {
+			matches = append(matches, &models.Match{
+				ID:           uuid.New(),
+				TournamentID: tournamentID,
+				Round:        round,
+				Position:     position,
+				Status:       models.MatchStatusPending,
+			})
+		}
+	}
+
+	// A seed's opponent is the seed that makes the pair add up to size+1, and
+	// the order keeps the top two seeds in opposite halves.
+	order := bracketSeedOrder(size)
+	for position := 0; position < size/2; position++ {
+		match := matches[position]
+		match.Player1UserID = seededPlayer(players, order[2*position])
+		match.Player2UserID = seededPlayer(players, order[2*position+1])
+	}
+
+	// More than half the slots are always filled, so a bye is never paired
+	// with another bye and the better seed always holds the first slot.
+	for _, match := range matches[:size/2] {
+		if match.Player2UserID == nil {
+			completeMatch(match, *match.Player1UserID, true)
+			advanceWinner(matches, match)
+			continue
+		}
+		match.Status = models.MatchStatusScheduled
+	}
+
+	return matches, nil
+}
+
+// ReportMatchResult records winnerID as the winner of a scheduled match and
+// moves them into the next round. It returns the matches it changed, starting
+// with the reported one; the last match of the bracket is the final, and its
+// winner wins the tournament.
+func ReportMatchResult(matches []*models.Match, matchID uuid.UUID, winnerID uuid.UUID) ([]*models.Match, error) {
+	var match *models.Match
+	for _, m := range matches {
+		if m.ID == matchID {
+			match = m
+			break
+		}
+	}
+	if match == nil {
+		return nil, ErrMatchNotFound
+	}
+
+	switch match.Status {
+	case models.MatchStatusPending:
+		return nil, ErrMatchNotReady
+	case models.MatchStatusCompleted:
+		return nil, ErrMatchCompleted
+	}
+	if !match.HasPlayer(winnerID) {
+		return nil, ErrInvalidWinner
+	}
+
+	completeMatch(match, winnerID, false)
+	changed := []*models.Match{match}
+	if next := advanceWinner(matches, match); next != nil {
+		changed = append(changed, next)
+	}
+
+	return changed, nil
+}
+
+// FinalMatch returns the last match of the bracket.
+func FinalMatch(matches []*models.Match) *models.Match {
+	var final *models.Match
+	for _, m := range matches {
+		if final == nil || m.Round > final.Round {
+			final = m
+		}
+	}
+	return final
+}
+
+func completeMatch(match *models.Match, winnerID uuid.UUID, walkover bool) {
+	now := time.Now()
+	match.WinnerUserID = &winnerID
+	match.Status = models.MatchStatusCompleted
+	match.Walkover = walkover
+	match.CompletedAt = &now
+}
+
+// advanceWinner puts the match winner into their slot in the next round and
+// schedules that match once both players are known. It returns the next-round
+// match, or nil after the final.
+func advanceWinner(matches []*models.Match, match *models.Match) *models.Match {
+	var next *models.Match
+	for _, m := range matches {
+		if m.Round == match.Round+1 && m.Position == match.Position/2 {
+			next = m
+			break
+		}
+	}
+	if next == nil {
+		return nil
+	}
+
+	winner := *match.WinnerUserID
+	if match.Position%2 == 0 {
+		next.Player1UserID = &winner
+	} else {
+		next.Player2UserID = &winner
+	}
+	if next.Player1UserID != nil && next.Player2UserID != nil {
+		next.Status = models.MatchStatusScheduled
+	}
+
+	return next
+}
+
+// bracketSeedOrder lists the 1-based seeds of a size-slot bracket in slot
+// order, e.g. 1 8 4 5 2 7 3 6 for eight slots.
+func bracketSeedOrder(size int) []int {
+	order := []int{1}
+	for len(order) < size {
+		slots := len(order) * 2
+		expanded := make([]int, 0, slots)
+		for _, seed := range order {
+			expanded = append(expanded, seed, slots+1-seed)
+		}
+		order = expanded
+	}
+	return order
+}
+
+// seededPlayer returns the player holding the 1-based seed, or nil for a bye.
+func seededPlayer(players []uuid.UUID, seed int) *uuid.UUID {
+	if seed > len(players) {
+		return nil
+	}
+	player := players[seed-1]
+	return &player
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// MaxChangeFeedLimit caps how many events one ListChanges call returns.
+const MaxChangeFeedLimit = 200
+
+// ChangeFeedService keeps each TTR's change feed: an ordered list of what
+// happened to it, which clients poll to reconcile optimistic updates. Events
+// are recorded after the change they describe is committed.
+type ChangeFeedService struct {
+	eventRepo  repository.TTREventRepository
+	authorizer *Authorizer
+	retention  time.Duration
+	logger     *zap.Logger
+}
+
+// NewChangeFeedService creates the change feed service. Events are purged
+// once they are older than retention.
+func NewChangeFeedService(eventRepo repository.TTREventRepository, authorizer *Authorizer, retention time.Duration, logger *zap.Logger) *ChangeFeedService {
+	return &ChangeFeedService{
+		eventRepo:  eventRepo,
+		authorizer: authorizer,
+		retention:  retention,
+		logger:     logger,
+	}
+}
+
+// Record appends an event to the TTR's change feed. actorUserID is nil for
+// changes the server made on its own. The change has already happened, so a
+// failure is logged rather than returned. A nil service records nothing.
+func (s *ChangeFeedService) Record(ctx context.Context, ttrID uuid.UUID, eventType string, actorUserID *uuid.UUID, data map[string]string) {
+	if s == nil {
+		return
+	}
+
+	encoded := []byte("{}")
+	if len(data) > 0 {
+		var err error
+		if encoded, err = json.Marshal(data); err != nil {
+			s.logger.Error("Failed to encode TTR event", zap.String("type", eventType), zap.Error(err))
+			return
+		}
+	}
+
+	event := &models.TTREvent{
+		TTRID:       ttrID,
+		Type:        eventType,
+		ActorUserID: actorUserID,
+		Data:        string(encoded),
+	}
+	if err := s.eventRepo.Append(ctx, event); err != nil {
+		s.logger.Error("Failed to record TTR event",
+			zap.String("ttr_id", ttrID.String()),
+			zap.String("type", eventType),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListChanges returns up to limit of the TTR's events after the since cursor,
+// oldest first, and whether more events follow them. The cursor is the
+// sequence number of the last event the client saw, or 0 for the whole feed.
+// A limit outside 1 to MaxChangeFeedLimit means MaxChangeFeedLimit. Only
+// participants can read the feed; to anyone else the TTR doesn't exist.
+func (s *ChangeFeedService) ListChanges(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, since int64, limit int) ([]*models.TTREvent, bool, error) {
+	if since < 0 {
+		return nil, false, ErrInvalidChangeCursor
+	}
+	if limit <= 0 || limit > MaxChangeFeedLimit {
+		limit = MaxChangeFeedLimit
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, false, err
+	}
+	isParticipant, err := s.authorizer.IsParticipant(ctx, ttr, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if !isParticipant {
+		return nil, false, ErrTTRNotFound
+	}
+
+	// One extra event tells whether there is another page.
+	events, err := s.eventRepo.FindSince(ctx, ttrID, since, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list changes: %w", err)
+	}
+	if len(events) > limit {
+		return events[:limit], true, nil
+	}
+	return events, false, nil
+}
+
+// PurgeExpired deletes events older than the retention window, in batches.
+// It runs as a periodic job.
+func (s *ChangeFeedService) PurgeExpired(ctx context.Context) error {
+	cutoff := time.Now().Add(-s.retention)
+
+	var total int64
+	for {
+		purged, err := s.eventRepo.DeleteBefore(ctx, cutoff, defaultPurgeBatchSize)
+		total += purged
+		if err != nil {
+			return fmt.Errorf("failed to purge TTR events: %w", err)
+		}
+		if purged < defaultPurgeBatchSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("Purged expired TTR events", zap.Int64("count", total))
+	}
+	return nil
+}
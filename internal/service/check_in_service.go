@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// CheckInSummaryLead is how long before the tee time the captain is told who
+// hasn't checked in yet.
+const CheckInSummaryLead = 15 * time.Minute
+
+// CheckInService lets players check in on the day of the round and keeps the
+// captain posted on who has arrived.
+type CheckInService struct {
+	ttrRepo             repository.TTRRepository
+	authorizer          *Authorizer
+	notificationService *NotificationService
+	opensBefore         time.Duration
+	closesAfter         time.Duration
+	logger              *zap.Logger
+}
+
+// NewCheckInService creates the check-in service. Check-in opens opensBefore
+// the tee time and closes closesAfter it.
+func NewCheckInService(ttrRepo repository.TTRRepository, authorizer *Authorizer, notificationService *NotificationService, opensBefore time.Duration, closesAfter time.Duration, logger *zap.Logger) *CheckInService {
+	return &CheckInService{
+		ttrRepo:             ttrRepo,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		opensBefore:         opensBefore,
+		closesAfter:         closesAfter,
+		logger:              logger,
+	}
+}
+
+// Window returns when check-in for the TTR opens and closes.
+func (s *CheckInService) Window(ttr *models.TTR) (time.Time, time.Time) {
+	teeAt := ttr.TeeDateTime()
+	return teeAt.Add(-s.opensBefore), teeAt.Add(s.closesAfter)
+}
+
+// CheckIn checks userID in to the TTR and returns their roster entry. Only
+// players can check in, and only within the check-in window; checking in
+// again keeps the first check-in time. When the last confirmed player checks
+// in, the captain is told everyone is there.
+func (s *CheckInService) CheckIn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (*TTRPlayerDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	if !ttr.HasPlayer(userID) {
+		return nil, ErrNotPlayerCheckIn
+	}
+	if ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted {
+		return nil, ErrCheckInClosed
+	}
+
+	now := time.Now()
+	opensAt, closesAt := s.Window(ttr)
+	if now.Before(opensAt) || now.After(closesAt) {
+		return nil, &CheckInWindowError{OpensAt: opensAt, ClosesAt: closesAt}
+	}
+
+	checkedIn, err := s.ttrRepo.UpdatePlayerCheckIn(ctx, ttrID, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check in: %w", err)
+	}
+
+	players, err := s.ttrRepo.GetPlayers(ctx, ttrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get players: %w", err)
+	}
+
+	var detail *TTRPlayerDetail
+	for _, player := range players {
+		if player.UserID == userID {
+			d := NewTTRPlayerDetail(player)
+			detail = &d
+		}
+	}
+	if detail == nil {
+		return nil, ErrNotPlayerCheckIn
+	}
+
+	if checkedIn {
+		s.logger.Info("Player checked in",
+			zap.String("ttr_id", ttrID.String()),
+			zap.String("user_id", userID.String()),
+		)
+		if len(notCheckedIn(players)) == 0 {
+			s.sendSummary(ctx, ttr, nil, now)
+		}
+	}
+	return detail, nil
+}
+
+// ListCheckIns returns the TTR's roster with each player's check-in. Only
+// participants can see it; to anyone else the TTR doesn't exist.
+func (s *CheckInService) ListCheckIns(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (*models.TTR, []TTRPlayerDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	isParticipant, err := s.authorizer.IsParticipant(ctx, ttr, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isParticipant {
+		return nil, nil, ErrTTRNotFound
+	}
+
+	players := make([]TTRPlayerDetail, 0, len(ttr.Players))
+	for i := range ttr.Players {
+		players = append(players, NewTTRPlayerDetail(&ttr.Players[i]))
+	}
+	return ttr, players, nil
+}
+
+// SendCheckInSummaries tells the captain of each TTR teeing off within
+// CheckInSummaryLead which confirmed players haven't checked in. A TTR whose
+// players have all checked in already had its summary. It runs as a periodic
+// job.
+func (s *CheckInService) SendCheckInSummaries(ctx context.Context) error {
+	now := time.Now()
+
+	ttrs, err := s.ttrRepo.FindCheckInSummaryDue(ctx, now, now.Add(CheckInSummaryLead))
+	if err != nil {
+		return fmt.Errorf("failed to find TTRs due a check-in summary: %w", err)
+	}
+
+	for _, ttr := range ttrs {
+		players := make([]*models.TTRPlayer, 0, len(ttr.Players))
+		for i := range ttr.Players {
+			players = append(players, &ttr.Players[i])
+		}
+		s.sendSummary(ctx, ttr, notCheckedIn(players), now)
+	}
+	return nil
+}
+
+// sendSummary notifies the captain that the missing players haven't checked
+// in, or that everyone has when there are none, unless the TTR's summary was
+// already sent.
+func (s *CheckInService) sendSummary(ctx context.Context, ttr *models.TTR, missing []*models.TTRPlayer, now time.Time) {
+	marked, err := s.ttrRepo.MarkCheckInSummarySent(ctx, ttr.ID, now)
+	if err != nil {
+		s.logger.Error("Failed to mark check-in summary sent", zap.String("ttr_id", ttr.ID.String()), zap.Error(err))
+		return
+	}
+	if !marked {
+		return
+	}
+
+	targetType := "ttr"
+	template := "everyone_checked_in"
+	params := map[string]string{"course": ttr.CourseName}
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for _, player := range missing {
+			if player.User != nil {
+				names = append(names, player.User.FirstName)
+			}
+		}
+		template = "check_in_summary"
+		params["count"] = strconv.Itoa(len(missing))
+		params["missing"] = strings.Join(names, ", ")
+	}
+	if err := s.notificationService.Notify(ctx, ttr.CaptainUserID, models.NotificationTypeCheckIn, template, params, &targetType, &ttr.ID); err != nil {
+		s.logger.Error("Failed to create notification", zap.Error(err))
+	}
+}
+
+// notCheckedIn returns the confirmed players who haven't checked in.
+func notCheckedIn(players []*models.TTRPlayer) []*models.TTRPlayer {
+	var missing []*models.TTRPlayer
+	for _, player := range players {
+		if player.Status == models.TTRPlayerStatusConfirmed && player.CheckedInAt == nil {
+			missing = append(missing, player)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Sections of the dashboard, as named in its warnings.
+const (
+	DashboardSectionNextTTR             = "next_ttr"
+	DashboardSectionPendingInvitations  = "pending_invitations"
+	DashboardSectionUnreadNotifications = "unread_notifications"
+	DashboardSectionActionItems         = "action_items"
+)
+
+// UpcomingGrace is how long after its tee time a round still counts as
+// upcoming, so one being played doesn't drop off before it is over.
+const UpcomingGrace = 4 * time.Hour
+
+// RosterCounts is how a TTR's players stand.
+type RosterCounts struct {
+	Confirmed int
+	Maybe     int
+	Declined  int
+	OpenSlots int
+}
+
+// DashboardTTR is the user's next round with its roster counts.
+type DashboardTTR struct {
+	TTR    *TTRDetail
+	Roster RosterCounts
+}
+
+// Dashboard is what a user sees on opening the app. A section that could not
+// be loaded is left nil and named in Warnings; NextTTR is also nil when the
+// user has no upcoming round.
+type Dashboard struct {
+	NextTTR *DashboardTTR
+	// PendingInvitations counts the invitations still waiting for an answer
+	// on each upcoming TTR the user captains.
+	PendingInvitations  map[uuid.UUID]int
+	UnreadNotifications *int64
+	ActionItems         []ActionItem
+	Warnings            []string
+}
+
+// DashboardService assembles the dashboard from a fixed set of queries run
+// side by side.
+type DashboardService struct {
+	ttrRepo           repository.TTRRepository
+	invitationRepo    repository.InvitationRepository
+	notificationRepo  repository.NotificationRepository
+	actionItemService *ActionItemService
+	logger            *zap.Logger
+}
+
+func NewDashboardService(
+	ttrRepo repository.TTRRepository,
+	invitationRepo repository.InvitationRepository,
+	notificationRepo repository.NotificationRepository,
+	actionItemService *ActionItemService,
+	logger *zap.Logger,
+) *DashboardService {
+	return &DashboardService{
+		ttrRepo:           ttrRepo,
+		invitationRepo:    invitationRepo,
+		notificationRepo:  notificationRepo,
+		actionItemService: actionItemService,
+		logger:            logger,
+	}
+}
+
+// GetDashboard loads every section of userID's dashboard at once. It never
+// fails as a whole: a section whose query fails is logged and reported in
+// Warnings instead.
+func (s *DashboardService) GetDashboard(ctx context.Context, userID uuid.UUID) *Dashboard {
+	var (
+		dashboard                                         Dashboard
+		nextTTRErr, pendingErr, unreadErr, actionItemsErr error
+		nextTTR                                           *DashboardTTR
+		pending                                           map[uuid.UUID]int
+		unread                                            int64
+		actionItems                                       []ActionItem
+	)
+	now := time.Now()
+
+	// Each query records its own error rather than returning it, so one
+	// failing section doesn't cancel the others.
+	var g errgroup.Group
+	g.Go(func() error {
+		nextTTR, nextTTRErr = s.nextTTR(ctx, userID, now)
+		return nil
+	})
+	g.Go(func() error {
+		// Tee dates are stored without a time zone, so count from a day early.
+		pending, pendingErr = s.invitationRepo.CountPendingByCaptainID(ctx, userID, now.AddDate(0, 0, -1).Truncate(24*time.Hour))
+		return nil
+	})
+	g.Go(func() error {
+		unread, unreadErr = s.notificationRepo.CountUnreadByUserID(ctx, userID)
+		return nil
+	})
+	g.Go(func() error {
+		actionItems, actionItemsErr = s.actionItemService.ListActionItems(ctx, userID, DefaultActionItemHorizon)
+		return nil
+	})
+	_ = g.Wait()
+
+	if s.loaded(userID, DashboardSectionNextTTR, nextTTRErr, &dashboard) {
+		dashboard.NextTTR = nextTTR
+	}
+	if s.loaded(userID, DashboardSectionPendingInvitations, pendingErr, &dashboard) {
+		dashboard.PendingInvitations = pending
+	}
+	if s.loaded(userID, DashboardSectionUnreadNotifications, unreadErr, &dashboard) {
+		dashboard.UnreadNotifications = &unread
+	}
+	if s.loaded(userID, DashboardSectionActionItems, actionItemsErr, &dashboard) {
+		dashboard.ActionItems = actionItems
+	}
+	return &dashboard
+}
+
+// nextTTR returns the user's soonest round that is still going to be played,
+// or is being played, or nil when there is none.
+func (s *DashboardService) nextTTR(ctx context.Context, userID uuid.UUID, now time.Time) (*DashboardTTR, error) {
+	ttrs, err := s.ttrRepo.FindUpcomingByUserID(ctx, userID, now, UpcomingGrace)
+	if err != nil {
+		return nil, err
+	}
+	for _, ttr := range ttrs {
+		if ttr.Status != models.TTRStatusOpen && ttr.Status != models.TTRStatusConfirmed {
+			continue
+		}
+
+		var roster RosterCounts
+		for _, player := range ttr.Players {
+			switch player.Status {
+			case models.TTRPlayerStatusConfirmed:
+				roster.Confirmed++
+			case models.TTRPlayerStatusMaybe:
+				roster.Maybe++
+			case models.TTRPlayerStatusDeclined:
+				roster.Declined++
+			}
+		}
+		roster.Confirmed += len(ttr.Guests)
+		roster.OpenSlots = ttr.OpenSlots(models.PlayerCounts{Total: ttr.Headcount(), Confirmed: roster.Confirmed})
+
+		detail := NewTTRDetail(ttr)
+		detail.OpenSlots = &roster.OpenSlots
+		detail.ConfirmedCount = &roster.Confirmed
+		return &DashboardTTR{TTR: detail, Roster: roster}, nil
+	}
+	return nil, nil
+}
+
+// loaded reports whether a section loaded, otherwise logging err and adding
+// the section to the dashboard's warnings.
+func (s *DashboardService) loaded(userID uuid.UUID, section string, err error, dashboard *Dashboard) bool {
+	if err == nil {
+		return true
+	}
+	s.logger.Warn("Failed to load dashboard section",
+		zap.String("section", section),
+		zap.String("user_id", userID.String()),
+		zap.Error(err),
+	)
+	dashboard.Warnings = append(dashboard.Warnings, section)
+	return false
+}
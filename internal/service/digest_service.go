@@ -0,0 +1,303 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+const (
+	// digestHorizon is how far ahead a digest looks for rounds and action
+	// items.
+	digestHorizon = 7 * 24 * time.Hour
+	// digestRunWindow is how long after the configured time a week's digests
+	// are sent. A run that crashed picks up where it stopped within it;
+	// after it, whoever wasn't reached waits for next week.
+	digestRunWindow = 24 * time.Hour
+	// defaultDigestBatchSize is used when the configured batch size isn't
+	// positive.
+	defaultDigestBatchSize = 100
+)
+
+//go:embed templates/digest.txt.tmpl templates/digest.html.tmpl
+var digestTemplateFS embed.FS
+
+// The digest templates are parsed once; each render clones them with the
+// functions for its language.
+var (
+	digestText = texttemplate.Must(texttemplate.New("digest.txt.tmpl").Funcs(digestFuncs(i18n.DefaultLanguage)).ParseFS(digestTemplateFS, "templates/digest.txt.tmpl"))
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest.html.tmpl").Funcs(digestFuncs(i18n.DefaultLanguage)).ParseFS(digestTemplateFS, "templates/digest.html.tmpl"))
+)
+
+// Digest is what a user's weekly email sums up: the rounds they play in the
+// coming week, the invitations waiting for their answer and what else they
+// have to act on.
+type Digest struct {
+	FirstName          string
+	Rounds             []DigestRound
+	PendingInvitations []ActionItem
+	ActionItems        []ActionItem
+}
+
+// DigestRound is an upcoming round in a digest. Captain is set when the user
+// captains or co-captains it.
+type DigestRound struct {
+	CourseName string
+	TeeTime    time.Time
+	Captain    bool
+}
+
+// Empty reports whether the digest has nothing to tell.
+func (d *Digest) Empty() bool {
+	return len(d.Rounds) == 0 && len(d.PendingInvitations) == 0 && len(d.ActionItems) == 0
+}
+
+// digestView is what the digest templates render.
+type digestView struct {
+	*Digest
+	Lang string
+}
+
+// RenderDigest renders digest as an email to to in lang, with a plain-text
+// body and an HTML one.
+func RenderDigest(lang string, to string, digest *Digest) (mailer.Message, error) {
+	funcs := digestFuncs(lang)
+	view := digestView{Digest: digest, Lang: lang}
+
+	text, err := digestText.Clone()
+	if err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to clone digest template: %w", err)
+	}
+	var body strings.Builder
+	if err := text.Funcs(funcs).Execute(&body, view); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	html, err := digestHTML.Clone()
+	if err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to clone digest template: %w", err)
+	}
+	var htmlBody bytes.Buffer
+	if err := html.Funcs(funcs).Execute(&htmlBody, view); err != nil {
+		return mailer.Message{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	return mailer.Message{
+		To:      to,
+		Subject: i18n.Translate(lang, "email.digest.subject", nil),
+		Body:    body.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
+
+// digestFuncs are the digest templates' functions in lang: t translates a
+// key with name and value pairs as parameters, datetime formats a time and
+// actionItem describes an action item.
+func digestFuncs(lang string) map[string]interface{} {
+	return map[string]interface{}{
+		"t": func(key string, pairs ...string) string {
+			params := make(map[string]string, len(pairs)/2)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				params[pairs[i]] = pairs[i+1]
+			}
+			return i18n.Translate(lang, key, params)
+		},
+		"datetime": func(t time.Time) string {
+			return t.UTC().Format("Mon 2 Jan 15:04")
+		},
+		"actionItem": func(item ActionItem) string {
+			return i18n.Translate(lang, "email.digest."+item.Kind, map[string]string{"course": item.CourseName})
+		},
+	}
+}
+
+// DigestService sends the weekly digest email. Each week's run starts at the
+// configured time and goes through the users who get the digest in batches.
+// A user is marked handled before their email is sent, so a run picked up
+// after a crash never emails anyone twice; at worst the one email being sent
+// at the time of the crash is lost.
+type DigestService struct {
+	userRepo          repository.UserRepository
+	ttrRepo           repository.TTRRepository
+	actionItemService *ActionItemService
+	mailer            mailer.Mailer
+	cfg               config.DigestConfig
+	logger            *zap.Logger
+}
+
+func NewDigestService(userRepo repository.UserRepository, ttrRepo repository.TTRRepository, actionItemService *ActionItemService, mailer mailer.Mailer, cfg config.DigestConfig, logger *zap.Logger) *DigestService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultDigestBatchSize
+	}
+	return &DigestService{
+		userRepo:          userRepo,
+		ttrRepo:           ttrRepo,
+		actionItemService: actionItemService,
+		mailer:            mailer,
+		cfg:               cfg,
+		logger:            logger,
+	}
+}
+
+// SendWeeklyDigests sends this week's digests to the users who haven't been
+// handled yet. Users with nothing to report are marked handled without an
+// email. It runs as a periodic job and does nothing outside the day after the
+// configured time.
+func (s *DigestService) SendWeeklyDigests(ctx context.Context) error {
+	now := time.Now()
+	runStart := DigestRunStart(now, s.cfg.Weekday, s.cfg.Hour)
+	if now.Sub(runStart) >= digestRunWindow {
+		return nil
+	}
+
+	throttle := &throttle{interval: s.cfg.SendInterval}
+	var sent, skipped int
+	defer func() {
+		if sent > 0 || skipped > 0 {
+			s.logger.Info("Sent weekly digests", zap.Int("sent", sent), zap.Int("skipped", skipped))
+		}
+	}()
+	for {
+		users, err := s.userRepo.FindDigestDue(ctx, runStart, s.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			delivered, err := s.handle(ctx, user, runStart, now, throttle)
+			if err != nil {
+				return fmt.Errorf("failed to send weekly digest: %w", err)
+			}
+			if delivered {
+				sent++
+			} else {
+				skipped++
+			}
+		}
+		if len(users) < s.cfg.BatchSize {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// handle builds the user's digest, marks them handled and sends it. It
+// returns false when there was nothing to send or another run got to them
+// first. A failure to deliver is only logged: the user is already marked, and
+// retrying could send twice.
+func (s *DigestService) handle(ctx context.Context, user *models.User, runStart time.Time, now time.Time, throttle *throttle) (bool, error) {
+	digest, err := s.BuildDigest(ctx, user, now)
+	if err != nil {
+		return false, err
+	}
+	marked, err := s.userRepo.MarkDigestSent(ctx, user.ID, runStart, now)
+	if err != nil || !marked || digest.Empty() {
+		return false, err
+	}
+
+	msg, err := RenderDigest(userLanguage(user), user.Email, digest)
+	if err != nil {
+		return false, err
+	}
+	if err := throttle.wait(ctx); err != nil {
+		return false, err
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.Error("Failed to send weekly digest", zap.String("user_id", user.ID.String()), zap.Error(err))
+		return false, nil
+	}
+	return true, nil
+}
+
+// BuildDigest gathers the user's digest as of now: their open or confirmed
+// rounds in the coming week and their action items over the same span.
+func (s *DigestService) BuildDigest(ctx context.Context, user *models.User, now time.Time) (*Digest, error) {
+	ttrs, err := s.ttrRepo.FindUpcomingByUserID(ctx, user.ID, now, UpcomingGrace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find upcoming TTRs: %w", err)
+	}
+	items, err := s.actionItemService.ListActionItems(ctx, user.ID, digestHorizon)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{FirstName: user.FirstName}
+	until := now.Add(digestHorizon)
+	for _, ttr := range ttrs {
+		if ttr.Status != models.TTRStatusOpen && ttr.Status != models.TTRStatusConfirmed {
+			continue
+		}
+		teeTime := ttr.TeeDateTime()
+		if teeTime.After(until) {
+			continue
+		}
+		digest.Rounds = append(digest.Rounds, DigestRound{
+			CourseName: ttr.CourseName,
+			TeeTime:    teeTime,
+			Captain:    ttr.CaptainUserID == user.ID || ttr.HasCoCaptain(user.ID),
+		})
+	}
+	for _, item := range items {
+		if item.Kind == ActionItemPendingInvitation {
+			digest.PendingInvitations = append(digest.PendingInvitations, item)
+		} else {
+			digest.ActionItems = append(digest.ActionItems, item)
+		}
+	}
+	return digest, nil
+}
+
+// DigestRunStart returns when the week's digest run that now falls in
+// started: the last time it was hour (UTC) on weekday, now included.
+func DigestRunStart(now time.Time, weekday time.Weekday, hour int) time.Time {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	start = start.AddDate(0, 0, -int((now.Weekday()-weekday+7)%7))
+	if start.After(now) {
+		start = start.AddDate(0, 0, -7)
+	}
+	return start
+}
+
+// userLanguage is the user's preferred language if it is supported.
+func userLanguage(user *models.User) string {
+	if user.PreferredLanguage == nil || !i18n.Supported(*user.PreferredLanguage) {
+		return i18n.DefaultLanguage
+	}
+	return *user.PreferredLanguage
+}
+
+// throttle spaces out calls to wait by at least interval.
+type throttle struct {
+	interval time.Duration
+	last     time.Time
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	if !t.last.IsZero() {
+		if d := t.interval - time.Since(t.last); d > 0 {
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+	t.last = time.Now()
+	return nil
+}
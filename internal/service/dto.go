@@ -0,0 +1,429 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+// The TTR, user, invitation and organization services return these views
+// instead of gorm models. Every field is filled in by the service: a nested
+// User is never missing, and a user that couldn't be loaded shows up like a
+// deleted one. Pointer fields that hold a nested view are documented where
+// they are only set for some reads.
+
+// UserSummary is the public view of a user. A deleted user keeps only its ID
+// and Deleted. Privacy says which fields other users may see; the handler's
+// response conversions apply it.
+type UserSummary struct {
+	ID        uuid.UUID
+	Email     string
+	FirstName string
+	LastName  string
+	Handicap  *float64
+	Phone     *string
+	AvatarURL *string
+	Privacy   models.PrivacySettings
+	Deleted   bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewUserSummary builds the summary of user. A nil user is treated as
+// deleted, so callers always get a summary for userID.
+func NewUserSummary(userID uuid.UUID, user *models.User) UserSummary {
+	if user == nil || user.IsDeleted() {
+		return UserSummary{ID: userID, Deleted: true}
+	}
+	return UserSummary{
+		ID:        user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Handicap:  user.Handicap,
+		Phone:     user.Phone,
+		AvatarURL: user.AvatarURL,
+		Privacy:   user.Privacy,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+// UserProfile is a user's golf profile on top of their summary.
+// PreferredLanguage is private to the user.
+type UserProfile struct {
+	UserSummary
+	PreferredLanguage  *string
+	HomeCourse         *string
+	Bio                *string
+	PlayingDays        []string
+	PreferredTeeTimes  *TeeTimeRange
+	MustResetPassword  bool
+	EmailUndeliverable bool
+	Notifications      models.NotificationSettings
+}
+
+func NewUserProfile(user *models.User) *UserProfile {
+	profile := &UserProfile{
+		UserSummary:        NewUserSummary(user.ID, user),
+		PreferredLanguage:  user.PreferredLanguage,
+		HomeCourse:         user.HomeCourse,
+		Bio:                user.Bio,
+		PlayingDays:        user.Days(),
+		MustResetPassword:  user.MustResetPassword,
+		EmailUndeliverable: user.EmailUndeliverable,
+		Notifications:      user.Notifications,
+	}
+	if user.PreferredTeeTimeStart != nil && user.PreferredTeeTimeEnd != nil {
+		profile.PreferredTeeTimes = &TeeTimeRange{Start: *user.PreferredTeeTimeStart, End: *user.PreferredTeeTimeEnd}
+	}
+	return profile
+}
+
+func newUserSummaries(users []*models.User) []UserSummary {
+	summaries := make([]UserSummary, 0, len(users))
+	for _, user := range users {
+		summaries = append(summaries, NewUserSummary(user.ID, user))
+	}
+	return summaries
+}
+
+// TTRDetail is a TTR with its roster. CreatedByUser is only set on TTR
+// reads; the TTR of an invitation has just its CaptainUser and an empty
+// roster, and is the only place OpenSlots and ConfirmedCount are counted.
+type TTRDetail struct {
+	ID              uuid.UUID
+	CourseName      string
+	CourseLocation  *string
+	TeeDate         time.Time
+	TeeTime         time.Time
+	MaxPlayers      int
+	MinPlayers      int
+	CreatedByUserID uuid.UUID
+	CaptainUserID   uuid.UUID
+	Status          models.TTRStatus
+	StatusLocked    bool
+	Visibility      string
+	Notes           *string
+	RSVPDeadline    *time.Time
+	OrganizationID  *uuid.UUID
+	LeagueID        *uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
+	OpenSlots       *int
+	ConfirmedCount  *int
+	CreatedByUser   *UserSummary
+	CaptainUser     *UserSummary
+	CoCaptains      []TTRCoCaptainDetail
+	Players         []TTRPlayerDetail
+	Guests          []TTRGuestDetail
+	Slots           []TeeSlotDetail
+
+	models.CourseConditions
+}
+
+type TTRCoCaptainDetail struct {
+	TTRID       uuid.UUID
+	UserID      uuid.UUID
+	User        UserSummary
+	AssignedAt  time.Time
+	Permissions []string
+}
+
+func NewTTRCoCaptainDetail(coCaptain *models.TTRCoCaptain) TTRCoCaptainDetail {
+	return TTRCoCaptainDetail{
+		TTRID:       coCaptain.TTRID,
+		UserID:      coCaptain.UserID,
+		User:        NewUserSummary(coCaptain.UserID, coCaptain.User),
+		AssignedAt:  coCaptain.AssignedAt,
+		Permissions: coCaptain.PermissionList(),
+	}
+}
+
+type TTRPlayerDetail struct {
+	TTRID       uuid.UUID
+	UserID      uuid.UUID
+	User        UserSummary
+	JoinedAt    time.Time
+	Status      models.TTRPlayerStatus
+	Notes       *string
+	GroupNumber int
+	Score       *int
+	CheckedInAt *time.Time
+}
+
+// TTRGuestDetail is a guest on a TTR's roster, a player without an account.
+type TTRGuestDetail struct {
+	ID            uuid.UUID
+	TTRID         uuid.UUID
+	DisplayName   string
+	Handicap      *float64
+	AddedByUserID uuid.UUID
+	CreatedAt     time.Time
+}
+
+// TeeSlotDetail is one of a TTR's tee slots; TeeTime is the time of day it
+// tees off.
+type TeeSlotDetail struct {
+	ID            uuid.UUID
+	Number        int
+	OffsetMinutes int
+	Capacity      int
+	TeeTime       time.Time
+}
+
+func NewTTRDetail(ttr *models.TTR) *TTRDetail {
+	detail := &TTRDetail{
+		ID:              ttr.ID,
+		CourseName:      ttr.CourseName,
+		CourseLocation:  ttr.CourseLocation,
+		TeeDate:         ttr.TeeDate,
+		TeeTime:         ttr.TeeTime,
+		MaxPlayers:      ttr.MaxPlayers,
+		MinPlayers:      ttr.MinPlayers,
+		CreatedByUserID: ttr.CreatedByUserID,
+		CaptainUserID:   ttr.CaptainUserID,
+		Status:          ttr.Status,
+		StatusLocked:    ttr.StatusLocked,
+		Visibility:      ttr.Visibility,
+		Notes:           ttr.Notes,
+		RSVPDeadline:    ttr.RSVPDeadline,
+		OrganizationID:  ttr.OrganizationID,
+		LeagueID:        ttr.LeagueID,
+		CreatedAt:       ttr.CreatedAt,
+		UpdatedAt:       ttr.UpdatedAt,
+		CoCaptains:      make([]TTRCoCaptainDetail, 0, len(ttr.CoCaptains)),
+		Players:         make([]TTRPlayerDetail, 0, len(ttr.Players)),
+		Guests:          make([]TTRGuestDetail, 0, len(ttr.Guests)),
+		Slots:           make([]TeeSlotDetail, 0, len(ttr.Slots)),
+	}
+	detail.CourseConditions = ttr.CourseConditions
+	if ttr.DeletedAt.Valid {
+		deletedAt := ttr.DeletedAt.Time
+		detail.DeletedAt = &deletedAt
+	}
+	if ttr.CreatedByUser != nil {
+		createdBy := NewUserSummary(ttr.CreatedByUserID, ttr.CreatedByUser)
+		detail.CreatedByUser = &createdBy
+	}
+	if ttr.CaptainUser != nil {
+		captain := NewUserSummary(ttr.CaptainUserID, ttr.CaptainUser)
+		detail.CaptainUser = &captain
+	}
+	for i := range ttr.CoCaptains {
+		detail.CoCaptains = append(detail.CoCaptains, NewTTRCoCaptainDetail(&ttr.CoCaptains[i]))
+	}
+	for i := range ttr.Players {
+		detail.Players = append(detail.Players, NewTTRPlayerDetail(&ttr.Players[i]))
+	}
+	for i := range ttr.Guests {
+		detail.Guests = append(detail.Guests, NewTTRGuestDetail(&ttr.Guests[i]))
+	}
+	for _, slot := range ttr.Slots {
+		detail.Slots = append(detail.Slots, TeeSlotDetail{
+			ID:            slot.ID,
+			Number:        slot.Number,
+			OffsetMinutes: slot.OffsetMinutes,
+			Capacity:      slot.Capacity,
+			TeeTime:       ttr.SlotTeeTime(slot),
+		})
+	}
+	return detail
+}
+
+func NewTTRGuestDetail(guest *models.TTRGuest) TTRGuestDetail {
+	return TTRGuestDetail{
+		ID:            guest.ID,
+		TTRID:         guest.TTRID,
+		DisplayName:   guest.DisplayName,
+		Handicap:      guest.Handicap,
+		AddedByUserID: guest.AddedByUserID,
+		CreatedAt:     guest.CreatedAt,
+	}
+}
+
+func NewTTRPlayerDetail(player *models.TTRPlayer) TTRPlayerDetail {
+	return TTRPlayerDetail{
+		TTRID:       player.TTRID,
+		UserID:      player.UserID,
+		User:        NewUserSummary(player.UserID, player.User),
+		JoinedAt:    player.JoinedAt,
+		Status:      player.Status,
+		Notes:       player.Notes,
+		GroupNumber: player.GroupNumber,
+		Score:       player.Score,
+		CheckedInAt: player.CheckedInAt,
+	}
+}
+
+func newTTRDetails(ttrs []*models.TTR) []*TTRDetail {
+	details := make([]*TTRDetail, 0, len(ttrs))
+	for _, ttr := range ttrs {
+		details = append(details, NewTTRDetail(ttr))
+	}
+	return details
+}
+
+// InvitationDetail is a TTR invitation with both users. TTR is set unless
+// the invitation was listed by TTR. Acceptable is only set on received
+// invitations: false when answering YES would be refused.
+// ForwardedByUserID is the invitee who passed their invitation on, for
+// forwarded invitations.
+type InvitationDetail struct {
+	ID                uuid.UUID
+	TTRID             uuid.UUID
+	TTR               *TTRDetail
+	InviterUserID     uuid.UUID
+	InviterUser       UserSummary
+	InviteeUserID     uuid.UUID
+	InviteeUser       UserSummary
+	ForwardedByUserID *uuid.UUID
+	Status            models.InvitationStatus
+	Message           *string
+	DeclineReason     *string
+	Acceptable        *bool
+	CreatedAt         time.Time
+	RespondedAt       *time.Time
+}
+
+func NewInvitationDetail(invitation *models.Invitation) *InvitationDetail {
+	detail := &InvitationDetail{
+		ID:                invitation.ID,
+		TTRID:             invitation.TTRID,
+		InviterUserID:     invitation.InviterUserID,
+		InviterUser:       NewUserSummary(invitation.InviterUserID, invitation.InviterUser),
+		InviteeUserID:     invitation.InviteeUserID,
+		InviteeUser:       NewUserSummary(invitation.InviteeUserID, invitation.InviteeUser),
+		Status:            invitation.Status,
+		Message:           invitation.Message,
+		DeclineReason:     invitation.DeclineReason,
+		CreatedAt:         invitation.CreatedAt,
+		RespondedAt:       invitation.RespondedAt,
+		ForwardedByUserID: invitation.ForwardedByUserID,
+	}
+	if invitation.TTR != nil {
+		detail.TTR = NewTTRDetail(invitation.TTR)
+	}
+	return detail
+}
+
+func newInvitationDetails(invitations []*models.Invitation) []*InvitationDetail {
+	details := make([]*InvitationDetail, 0, len(invitations))
+	for _, invitation := range invitations {
+		details = append(details, NewInvitationDetail(invitation))
+	}
+	return details
+}
+
+type OrganizationDetail struct {
+	ID              uuid.UUID
+	Name            string
+	Description     *string
+	CreatedByUserID uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func NewOrganizationDetail(org *models.Organization) *OrganizationDetail {
+	return &OrganizationDetail{
+		ID:              org.ID,
+		Name:            org.Name,
+		Description:     org.Description,
+		CreatedByUserID: org.CreatedByUserID,
+		CreatedAt:       org.CreatedAt,
+		UpdatedAt:       org.UpdatedAt,
+	}
+}
+
+type OrganizationMemberDetail struct {
+	OrganizationID uuid.UUID
+	UserID         uuid.UUID
+	User           UserSummary
+	Role           string
+	JoinedAt       time.Time
+}
+
+func NewOrganizationMemberDetail(member *models.OrganizationMember) OrganizationMemberDetail {
+	return OrganizationMemberDetail{
+		OrganizationID: member.OrganizationID,
+		UserID:         member.UserID,
+		User:           NewUserSummary(member.UserID, member.User),
+		Role:           member.Role,
+		JoinedAt:       member.JoinedAt,
+	}
+}
+
+// OrganizationInvitationDetail is an invitation to join an organization.
+// Organization is nil once the organization has been deleted.
+type OrganizationInvitationDetail struct {
+	ID              uuid.UUID
+	OrganizationID  uuid.UUID
+	Organization    *OrganizationDetail
+	Email           string
+	Role            string
+	InvitedByUserID uuid.UUID
+	Status          string
+	CreatedAt       time.Time
+	RespondedAt     *time.Time
+}
+
+func NewOrganizationInvitationDetail(invitation *models.OrganizationInvitation) *OrganizationInvitationDetail {
+	detail := &OrganizationInvitationDetail{
+		ID:              invitation.ID,
+		OrganizationID:  invitation.OrganizationID,
+		Email:           invitation.Email,
+		Role:            invitation.Role,
+		InvitedByUserID: invitation.InvitedByUserID,
+		Status:          invitation.Status,
+		CreatedAt:       invitation.CreatedAt,
+		RespondedAt:     invitation.RespondedAt,
+	}
+	if invitation.Organization != nil {
+		detail.Organization = NewOrganizationDetail(invitation.Organization)
+	}
+	return detail
+}
+
+type InviteLinkDetail struct {
+	ID              uuid.UUID
+	TTRID           uuid.UUID
+	Token           string
+	CreatedByUserID uuid.UUID
+	MaxUses         int
+	Uses            int
+	ExpiresAt       *time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+}
+
+func NewInviteLinkDetail(link *models.InviteLink) *InviteLinkDetail {
+	return &InviteLinkDetail{
+		ID:              link.ID,
+		TTRID:           link.TTRID,
+		Token:           link.Token,
+		CreatedByUserID: link.CreatedByUserID,
+		MaxUses:         link.MaxUses,
+		Uses:            link.Uses,
+		ExpiresAt:       link.ExpiresAt,
+		RevokedAt:       link.RevokedAt,
+		CreatedAt:       link.CreatedAt,
+	}
+}
+
+// InviteLinkPreview is what anyone holding an invite link may see of the TTR
+// before accepting it. Usable is false when accepting would be refused
+// because the link was revoked, expired or used up, or the TTR is full or no
+// longer open.
+type InviteLinkPreview struct {
+	CourseName     string
+	CourseLocation *string
+	TeeDate        time.Time
+	TeeTime        time.Time
+	CaptainUser    UserSummary
+	OpenSlots      int
+	RemainingUses  int
+	ExpiresAt      *time.Time
+	Usable         bool
+}
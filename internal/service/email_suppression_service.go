@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"go.uber.org/zap"
+)
+
+// SoftBounceLimit is how many soft bounces in a row suppress an address.
+const SoftBounceLimit = 3
+
+// EmailSuppressionService keeps the list of addresses mail is no longer sent
+// to, from the bounces and complaints email providers report. A hard bounce
+// or a complaint suppresses an address at once; soft bounces only after
+// SoftBounceLimit of them with no delivery in between. Suppression is
+// permanent.
+type EmailSuppressionService struct {
+	suppressionRepo repository.EmailSuppressionRepository
+	userRepo        repository.UserRepository
+	transactor      repository.Transactor
+	logger          *zap.Logger
+}
+
+func NewEmailSuppressionService(suppressionRepo repository.EmailSuppressionRepository, userRepo repository.UserRepository, transactor repository.Transactor, logger *zap.Logger) *EmailSuppressionService {
+	return &EmailSuppressionService{
+		suppressionRepo: suppressionRepo,
+		userRepo:        userRepo,
+		transactor:      transactor,
+		logger:          logger,
+	}
+}
+
+// RecordEvents records each event in its own transaction, stopping at the
+// first that fails. An event seen last for its address is skipped, so a
+// provider redelivering a batch doesn't count its bounces twice.
+func (s *EmailSuppressionService) RecordEvents(ctx context.Context, events []mailevents.Event) error {
+	for _, event := range events {
+		if err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return s.record(ctx, event)
+		}); err != nil {
+			return fmt.Errorf("failed to record email event: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *EmailSuppressionService) record(ctx context.Context, event mailevents.Event) error {
+	email := emailnorm.Normalize(event.Email)
+	suppression, err := s.suppressionRepo.FindByEmailForUpdate(ctx, email)
+	if err != nil {
+		return err
+	}
+	if suppression == nil {
+		if event.Kind == mailevents.KindDelivered {
+			// Nothing to reset.
+			return nil
+		}
+		suppression = &models.EmailSuppression{Email: email}
+	} else if event.ID != "" && suppression.LastEventID == event.ID {
+		return nil
+	}
+
+	wasSuppressed := suppression.Suppressed()
+	switch event.Kind {
+	case mailevents.KindHardBounce:
+		suppress(suppression, models.SuppressionReasonHardBounce)
+	case mailevents.KindComplaint:
+		suppress(suppression, models.SuppressionReasonComplaint)
+	case mailevents.KindSoftBounce:
+		suppression.SoftBounces++
+		if suppression.SoftBounces >= SoftBounceLimit {
+			suppress(suppression, models.SuppressionReasonSoftBounce)
+		}
+	case mailevents.KindDelivered:
+		suppression.SoftBounces = 0
+	default:
+		return nil
+	}
+	suppression.LastEventID = event.ID
+	if err := s.suppressionRepo.Upsert(ctx, suppression); err != nil {
+		return err
+	}
+
+	if suppression.Suppressed() && !wasSuppressed {
+		s.logger.Info("Suppressing email address",
+			zap.String("reason", suppression.Reason),
+			zap.String("detail", event.Reason))
+		return s.userRepo.MarkEmailUndeliverable(ctx, email)
+	}
+	return nil
+}
+
+// suppress suppresses the address for reason, unless it already is.
+func suppress(suppression *models.EmailSuppression, reason string) {
+	if suppression.Suppressed() {
+		return
+	}
+	now := time.Now()
+	suppression.SuppressedAt = &now
+	suppression.Reason = reason
+}
+
+// Mailer wraps next so that mail to suppressed addresses is dropped instead
+// of sent, and the account with the address is flagged. It returns nil when
+// next is nil, so a missing mailer stays missing.
+func (s *EmailSuppressionService) Mailer(next mailer.Mailer) mailer.Mailer {
+	if next == nil {
+		return nil
+	}
+	return &suppressingMailer{service: s, next: next}
+}
+
+type suppressingMailer struct {
+	service *EmailSuppressionService
+	next    mailer.Mailer
+}
+
+func (m *suppressingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	email := emailnorm.Normalize(msg.To)
+	suppressed, err := m.service.suppressionRepo.IsSuppressed(ctx, email)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		m.service.logger.Info("Skipping email to suppressed address", zap.String("subject", msg.Subject))
+		return m.service.userRepo.MarkEmailUndeliverable(ctx, email)
+	}
+	return m.next.Send(ctx, msg)
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/password"
+)
+
+// Errors returned by the services. Each carries the errcode sent to clients,
+// so handlers check them with errors.Is or pass them to response.FromError
+// rather than matching on the message, which is only for people.
+
+// Accounts and users.
+var (
+	ErrInvalidCredentials     = errcode.New(errcode.InvalidCredentials, "invalid email or password")
+	ErrInvalidOldPassword     = errcode.New(errcode.InvalidCredentials, "invalid old password")
+	ErrInvalidRefreshToken    = errcode.New(errcode.InvalidRefreshToken, "invalid refresh token")
+	ErrRefreshTokenExpired    = errcode.New(errcode.InvalidRefreshToken, "refresh token is invalid or expired")
+	ErrEmailTaken             = errcode.New(errcode.EmailTaken, "user with this email already exists")
+	ErrUserNotFound           = errcode.New(errcode.UserNotFound, "user not found")
+	ErrInviteeNotFound        = errcode.New(errcode.UserNotFound, "invitee user not found")
+	ErrCoCaptainNotFound      = errcode.New(errcode.UserNotFound, "co-captain user not found")
+	ErrEntrantNotFound        = errcode.New(errcode.UserNotFound, "player not found")
+	ErrUnsupportedLanguage    = errcode.New(errcode.UnsupportedLanguage, "unsupported language")
+	ErrInvalidPlayingDay      = errcode.New(errcode.InvalidPlayingDay, "invalid playing day")
+	ErrInvalidTeeTimeRange    = errcode.New(errcode.InvalidTeeTimeRange, "preferred tee time range must end after it starts")
+	ErrSearchQueryTooShort    = errcode.New(errcode.SearchQueryTooShort, "search query must be at least 3 characters")
+	ErrAvatarTooLarge         = errcode.New(errcode.AvatarTooLarge, "avatar file is too large")
+	ErrAvatarEmpty            = errcode.New(errcode.AvatarEmpty, "avatar file is empty")
+	ErrUnsupportedAvatarType  = errcode.New(errcode.UnsupportedImageType, "only JPEG and PNG images are allowed")
+	ErrInvalidAvatarUploadKey = errcode.New(errcode.InvalidAvatarUpload, "invalid avatar upload key")
+	ErrAvatarUploadNotFound   = errcode.New(errcode.InvalidAvatarUpload, "avatar upload not found")
+	ErrAPITokenNotFound       = errcode.New(errcode.APITokenNotFound, "API token not found")
+	ErrInvalidAPITokenExpiry  = errcode.New(errcode.InvalidAPITokenExpiry, "API token expiry must be in the future")
+	ErrInvalidAPITokenScope   = errcode.New(errcode.InvalidAPITokenScope, "API token scopes must be one or more known scopes")
+	ErrCannotImpersonateSelf  = errcode.New(errcode.CannotImpersonate, "cannot impersonate yourself")
+	ErrCannotImpersonateAdmin = errcode.New(errcode.CannotImpersonate, "cannot impersonate another admin")
+	ErrNotImpersonating       = errcode.New(errcode.NotImpersonating, "not an impersonation session")
+	ErrInvalidImportFile      = errcode.New(errcode.InvalidImportFile, "import file must be a CSV with email, first_name and last_name columns")
+	ErrCannotMergeSelf        = errcode.New(errcode.CannotMergeUsers, "cannot merge a user into themselves")
+	ErrMergeTargetNotFound    = errcode.New(errcode.UserNotFound, "merge target user not found")
+	ErrUserAlreadyMerged      = errcode.New(errcode.UserAlreadyMerged, "user was already merged into another account")
+	ErrCannotBlockSelf        = errcode.New(errcode.CannotBlockSelf, "cannot block yourself")
+	ErrCannotBefriendSelf     = errcode.New(errcode.CannotBefriendSelf, "cannot send yourself a friend request")
+	ErrFriendshipNotFound     = errcode.New(errcode.FriendshipNotFound, "no friendship or friend request with this user")
+	ErrWeakPassword           = errcode.New(errcode.WeakPassword, "password does not meet the password policy")
+)
+
+// TTRs and their rosters.
+var (
+	ErrTTRNotFound               = errcode.New(errcode.TTRNotFound, "TTR not found")
+	ErrTTRFull                   = errcode.New(errcode.TTRFull, "TTR is full")
+	ErrTTRFullForInvitation      = errcode.New(errcode.TTRFull, "TTR is full, cannot accept invitation")
+	ErrTTRNotRestorable          = errcode.New(errcode.TTRNotRestorable, "TTR can no longer be restored")
+	ErrDuplicateTTR              = errcode.New(errcode.DuplicateTTR, "you already have a TTR at this course around this tee time")
+	ErrTTRLocked                 = errcode.New(errcode.TTRLocked, "TTR is too close to its tee time to change the course, date or time, or to delete it")
+	ErrInvalidTTRStatus          = errcode.New(errcode.InvalidTTRStatus, "invalid TTR status")
+	ErrInvalidTTRVisibility      = errcode.New(errcode.InvalidTTRVisibility, "invalid TTR visibility")
+	ErrInvalidMaxPlayers         = errcode.New(errcode.InvalidMaxPlayers, "max_players must be greater than 0")
+	ErrInvalidMinPlayers         = errcode.New(errcode.InvalidMinPlayers, "min_players must be between 1 and max_players")
+	ErrInvalidHoles              = errcode.New(errcode.InvalidHoles, "holes must be 9 or 18")
+	ErrInvalidGreenFee           = errcode.New(errcode.InvalidGreenFee, "green_fee_cents cannot be negative")
+	ErrInvalidRSVPDeadline       = errcode.New(errcode.InvalidRSVPDeadline, "rsvp_deadline must be before the tee time")
+	ErrInvalidPermission         = errcode.New(errcode.InvalidPermission, "invalid co-captain permission")
+	ErrNotCoCaptain              = errcode.New(errcode.CoCaptainNotFound, "user is not a co-captain of this TTR")
+	ErrAlreadyPlayer             = errcode.New(errcode.AlreadyPlayer, "user is already a player")
+	ErrInviteeAlreadyPlayer      = errcode.New(errcode.AlreadyPlayer, "invitee is already a player in this TTR")
+	ErrCaptainCannotLeave        = errcode.New(errcode.CaptainCannotLeave, "captain cannot leave TTR")
+	ErrPlayerNotFound            = errcode.New(errcode.PlayerNotFound, "player not found in TTR")
+	ErrGuestNotFound             = errcode.New(errcode.GuestNotFound, "guest not found in TTR")
+	ErrInvalidPlayerStatus       = errcode.New(errcode.InvalidPlayerStatus, "invalid player status")
+	ErrInvalidStatusChange       = errcode.New(errcode.InvalidStatusChange, "player cannot move to that status")
+	ErrInvalidGroupNumber        = errcode.New(errcode.InvalidGroupNumber, "invalid group number")
+	ErrPairingGroupFull          = errcode.New(errcode.PairingGroupFull, "pairing group cannot have more than 4 players")
+	ErrTeeSlotFull               = errcode.New(errcode.PairingGroupFull, "tee slot is full")
+	ErrPairingOffRoster          = errcode.New(errcode.PairingOffRoster, "pairings can only include players on the roster")
+	ErrTeeSlotNotFound           = errcode.New(errcode.TeeSlotNotFound, "no tee slot for pairing group")
+	ErrInvalidTeeSlots           = errcode.New(errcode.InvalidTeeSlots, "invalid tee slots")
+	ErrSlotCapacityTooSmall      = errcode.New(errcode.SlotCapacityTooSmall, "tee slots hold fewer players than are confirmed")
+	ErrInvalidChangeCursor       = errcode.New(errcode.InvalidChangeCursor, "invalid change cursor")
+	ErrCheckInClosed             = errcode.New(errcode.CheckInClosed, "check-in is not open for this TTR")
+	ErrNotCaptainAddCoCaptain    = errcode.New(errcode.NotTTRCaptain, "unauthorized: only captain can add co-captains")
+	ErrNotCaptainRemoveCoCaptain = errcode.New(errcode.NotTTRCaptain, "unauthorized: only captain can remove co-captains")
+	ErrNotCaptainUpdateCoCaptain = errcode.New(errcode.NotTTRCaptain, "unauthorized: only captain can change co-captain permissions")
+	ErrNotCaptainDelete          = errcode.New(errcode.NotTTRCaptain, "unauthorized: only captain can delete TTR")
+	ErrNotManagerUpdateTTR       = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can update TTR")
+	ErrNotManagerUpdatePlayer    = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can update player status")
+	ErrNotManagerUpdatePairings  = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can update pairings")
+	ErrNotManagerGuests          = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can add or remove guests")
+	ErrNotManagerTeeSlots        = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can set tee slots")
+	ErrNotManagerSuggestPairings = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can see suggested pairings")
+	ErrNotManagerInvite          = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can send invitations")
+	ErrNotManagerListInvitations = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can see the TTR's invitations")
+	ErrNotManagerApproveForward  = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can approve forwarded invitations")
+	ErrNotManagerSuggestions     = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can see suggested players")
+	ErrNotManagerRecordScore     = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can record scores")
+	ErrNotManagerStartTournament = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can start a tournament from this TTR")
+	ErrNotTTRPlayer              = errcode.New(errcode.NotTTRPlayer, "unauthorized: only TTR players can access messages")
+	ErrNotPlayerCheckIn          = errcode.New(errcode.NotTTRPlayer, "unauthorized: only TTR players can check in")
+	ErrNotMemberCreateTTR        = errcode.New(errcode.NotOrganizationMember, "unauthorized: only organization members can create TTRs in it")
+)
+
+// DuplicateTTRError is returned when a new TTR looks like a double submit of
+// an existing one. It wraps ErrDuplicateTTR.
+type DuplicateTTRError struct {
+	ExistingTTRID uuid.UUID
+}
+
+func (e *DuplicateTTRError) Error() string {
+	return ErrDuplicateTTR.Error()
+}
+
+func (e *DuplicateTTRError) Unwrap() error {
+	return ErrDuplicateTTR
+}
+
+// TTRLockedError is returned when a TTR is changed or deleted after its edit
+// lock started at LockedAt. It wraps ErrTTRLocked.
+type TTRLockedError struct {
+	LockedAt time.Time
+}
+
+func (e *TTRLockedError) Error() string {
+	return ErrTTRLocked.Error()
+}
+
+func (e *TTRLockedError) Unwrap() error {
+	return ErrTTRLocked
+}
+
+// CheckInWindowError is returned when a player checks in outside the window
+// from OpensAt to ClosesAt. It wraps ErrCheckInClosed.
+type CheckInWindowError struct {
+	OpensAt  time.Time
+	ClosesAt time.Time
+}
+
+func (e *CheckInWindowError) Error() string {
+	return ErrCheckInClosed.Error()
+}
+
+func (e *CheckInWindowError) Unwrap() error {
+	return ErrCheckInClosed
+}
+
+// PasswordPolicyError is returned for a new password that breaks the
+// password policy, which requires MinLength characters; Rules lists the
+// rules it breaks. It wraps ErrWeakPassword.
+type PasswordPolicyError struct {
+	Rules     []password.Rule
+	MinLength int
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrWeakPassword
+}
+
+// MessageTemplateError is returned for an invitation message or template
+// that doesn't parse or uses something other than its variables. Column is 0
+// when only the line is known. It wraps ErrInvalidMessageTemplate.
+type MessageTemplateError struct {
+	Line   int
+	Column int
+	Reason string
+}
+
+func (e *MessageTemplateError) Error() string {
+	return ErrInvalidMessageTemplate.Error()
+}
+
+func (e *MessageTemplateError) Unwrap() error {
+	return ErrInvalidMessageTemplate
+}
+
+// TTR invitations.
+var (
+	ErrInvitationNotFound            = errcode.New(errcode.InvitationNotFound, "invitation not found")
+	ErrInvitationPending             = errcode.New(errcode.InvitationAlreadyPending, "pending invitation already exists for this user")
+	ErrOrganizationInvitationPending = errcode.New(errcode.InvitationAlreadyPending, "pending invitation already exists for this email")
+	ErrInvitationAnswered            = errcode.New(errcode.InvitationNotPending, "invitation has already been responded to")
+	ErrInvitationNotCancelable       = errcode.New(errcode.InvitationNotPending, "only pending invitations can be canceled")
+	ErrInvitationNotPending          = errcode.New(errcode.InvitationNotPending, "invitation is no longer pending")
+	ErrRSVPDeadlinePassed            = errcode.New(errcode.InvitationExpired, "RSVP deadline has passed")
+	ErrInvalidInvitationStatus       = errcode.New(errcode.InvalidInvitationStatus, "invalid invitation status")
+	ErrDeclineReasonNotAllowed       = errcode.New(errcode.DeclineReasonNotAllowed, "decline reason is only allowed with NO or MAYBE")
+	ErrCannotInviteSelf              = errcode.New(errcode.CannotInviteSelf, "cannot invite yourself")
+	ErrCannotInviteCaptain           = errcode.New(errcode.CannotInviteCaptain, "cannot invite the TTR captain")
+	ErrCannotInviteDeletedUser       = errcode.New(errcode.CannotInviteDeletedUser, "cannot invite a deleted user")
+	ErrTTRClosed                     = errcode.New(errcode.TTRClosed, "cannot invite to a cancelled or completed TTR")
+	ErrTTRClosedJoin                 = errcode.New(errcode.TTRClosed, "cannot join a cancelled or completed TTR")
+	ErrTeeTimePassed                 = errcode.New(errcode.TeeTimePassed, "cannot invite to a TTR whose tee time has passed")
+	ErrNotInviter                    = errcode.New(errcode.NotInviter, "unauthorized: only the inviter can cancel the invitation")
+	ErrNotInvitee                    = errcode.New(errcode.NotInvitee, "unauthorized: you can only respond to your own invitations")
+	ErrNotInviteeForward             = errcode.New(errcode.NotInvitee, "unauthorized: you can only forward your own invitations")
+	ErrForwardToRoster               = errcode.New(errcode.CannotForwardToUser, "cannot forward the invitation to someone on the TTR's roster")
+	ErrForwardToBlocked              = errcode.New(errcode.CannotForwardToUser, "cannot forward the invitation to this user")
+	ErrInvitationNotForwarded        = errcode.New(errcode.InvitationNotForwarded, "only forwarded invitations waiting for approval can be approved")
+	ErrInvitationTemplateNotFound    = errcode.New(errcode.InvitationTemplateNotFound, "invitation template not found")
+	ErrInvalidMessageTemplate        = errcode.New(errcode.InvalidMessageTemplate, "invalid message template")
+	ErrMessageNotFound               = errcode.New(errcode.MessageNotFound, "message not found")
+	ErrMessageDeleted                = errcode.New(errcode.MessageDeleted, "message has been deleted")
+	ErrMessageEmpty                  = errcode.New(errcode.MessageEmpty, "message body cannot be empty")
+	ErrInvalidEmoji                  = errcode.New(errcode.InvalidEmoji, "invalid emoji")
+	ErrEditWindowExpired             = errcode.New(errcode.EditWindowExpired, "unauthorized: edit window has expired")
+	ErrNotAuthorEdit                 = errcode.New(errcode.NotMessageAuthor, "unauthorized: only the author can edit a message")
+	ErrNotAuthorDelete               = errcode.New(errcode.NotMessageAuthor, "unauthorized: only the author, captain or co-captain can delete a message")
+	ErrAttachmentTooLarge            = errcode.New(errcode.AttachmentTooLarge, "attachment is too large")
+	ErrTTRAttachmentQuota            = errcode.New(errcode.AttachmentQuotaExceeded, "TTR attachment storage quota exceeded")
+	ErrUserAttachmentQuota           = errcode.New(errcode.AttachmentQuotaExceeded, "user attachment storage quota exceeded")
+	ErrUnsupportedAttachmentType     = errcode.New(errcode.UnsupportedAttachmentType, "unsupported attachment type")
+)
+
+// TTR invite links.
+var (
+	ErrInviteLinkNotFound      = errcode.New(errcode.InviteLinkNotFound, "invite link not found")
+	ErrInviteLinkRevoked       = errcode.New(errcode.InviteLinkRevoked, "invite link has been revoked")
+	ErrInviteLinkExpired       = errcode.New(errcode.InviteLinkExpired, "invite link has expired")
+	ErrInviteLinkUsedUp        = errcode.New(errcode.InviteLinkUsedUp, "invite link has no uses left")
+	ErrInvalidInviteLinkExpiry = errcode.New(errcode.InvalidInviteLinkExpiry, "expires_at must be in the future")
+	ErrNotManagerInviteLinks   = errcode.New(errcode.NotTTRManager, "unauthorized: only captain or co-captain can manage invite links")
+)
+
+// Organizations.
+var (
+	ErrOrganizationNotFound       = errcode.New(errcode.OrganizationNotFound, "organization not found")
+	ErrOrganizationNameRequired   = errcode.New(errcode.OrganizationNameRequired, "organization name cannot be empty")
+	ErrMemberNotFound             = errcode.New(errcode.MemberNotFound, "member not found")
+	ErrAlreadyMember              = errcode.New(errcode.AlreadyMember, "user is already a member of this organization")
+	ErrInvalidRole                = errcode.New(errcode.InvalidRole, "invalid role")
+	ErrOwnerRoleLocked            = errcode.New(errcode.OwnerProtected, "cannot change the owner's role")
+	ErrOwnerNotRemovable          = errcode.New(errcode.OwnerProtected, "the organization owner cannot be removed")
+	ErrNotAdminUpdateOrganization = errcode.New(errcode.NotOrganizationAdmin, "unauthorized: only organization owners and admins can update the organization")
+	ErrNotAdminRemoveMember       = errcode.New(errcode.NotOrganizationAdmin, "unauthorized: only organization owners and admins can remove members")
+	ErrNotAdminInvite             = errcode.New(errcode.NotOrganizationAdmin, "unauthorized: only organization owners and admins can invite members")
+	ErrNotAdminCreateLeague       = errcode.New(errcode.NotOrganizationAdmin, "unauthorized: only organization owners and admins can create leagues in it")
+	ErrNotOwnerChangeRole         = errcode.New(errcode.NotOrganizationOwner, "unauthorized: only the organization owner can change roles")
+	ErrNotOwnerDelete             = errcode.New(errcode.NotOrganizationOwner, "unauthorized: only the organization owner can delete it")
+	ErrNotOwnerGrantAdmin         = errcode.New(errcode.NotOrganizationOwner, "unauthorized: only the organization owner can grant the admin role")
+	ErrNotOwnerRemoveAdmin        = errcode.New(errcode.NotOrganizationOwner, "unauthorized: only the organization owner can remove admins")
+)
+
+// Leagues.
+var (
+	ErrLeagueNotFound       = errcode.New(errcode.LeagueNotFound, "league not found")
+	ErrLeagueNameRequired   = errcode.New(errcode.LeagueNameRequired, "league name cannot be empty")
+	ErrInvalidSeason        = errcode.New(errcode.InvalidSeason, "league end date cannot be before its start date")
+	ErrInvalidScoringScheme = errcode.New(errcode.InvalidScoringScheme, "invalid scoring scheme")
+	ErrTTRInLeague          = errcode.New(errcode.TTRInLeague, "TTR already belongs to a league")
+	ErrTTROutsideSeason     = errcode.New(errcode.TTROutsideSeason, "TTR is outside the league's season")
+	ErrAttachIncompleteTTR  = errcode.New(errcode.TTRNotCompleted, "only completed TTRs can be attached to a league")
+	ErrScoreIncompleteTTR   = errcode.New(errcode.TTRNotCompleted, "scores can only be recorded for completed TTRs")
+	ErrInvalidScore         = errcode.New(errcode.InvalidScore, "score must be positive")
+	ErrNotLeagueOwner       = errcode.New(errcode.NotLeagueOwner, "unauthorized: only the league owner can attach TTRs")
+)
+
+// Tournaments.
+var (
+	ErrTournamentNotFound     = errcode.New(errcode.TournamentNotFound, "tournament not found")
+	ErrTournamentNameRequired = errcode.New(errcode.TournamentNameRequired, "tournament name cannot be empty")
+	ErrInvalidFieldSize       = errcode.New(errcode.InvalidFieldSize, "a tournament needs between 2 and 16 players")
+	ErrDuplicateEntrant       = errcode.New(errcode.DuplicateEntrant, "a player can only be entered once")
+	ErrTournamentHasNoTTR     = errcode.New(errcode.TournamentHasNoTTR, "tournament has no TTR to copy")
+	ErrMatchNotFound          = errcode.New(errcode.MatchNotFound, "match not found")
+	ErrMatchCompleted         = errcode.New(errcode.MatchCompleted, "match already completed")
+	ErrMatchNotReady          = errcode.New(errcode.MatchNotReady, "match is not ready to be played")
+	ErrInvalidWinner          = errcode.New(errcode.InvalidWinner, "winner must be one of the match players")
+	ErrRoundNotReady          = errcode.New(errcode.RoundNotReady, "round is not ready yet")
+	ErrRoundFinished          = errcode.New(errcode.RoundFinished, "round has no matches left to play")
+	ErrRoundHasTTR            = errcode.New(errcode.RoundHasTTR, "round already has a TTR")
+	ErrNotTournamentOwner     = errcode.New(errcode.NotTournamentOwner, "unauthorized: only the tournament owner can create round TTRs")
+	ErrNotMatchPlayer         = errcode.New(errcode.NotMatchPlayer, "unauthorized: only the match players or the tournament owner can report results")
+)
+
+// Webhooks.
+var (
+	ErrWebhookNotFound     = errcode.New(errcode.WebhookNotFound, "webhook not found")
+	ErrInvalidWebhookURL   = errcode.New(errcode.InvalidWebhookURL, "webhook URL must be an absolute http or https URL")
+	ErrInvalidWebhookEvent = errcode.New(errcode.InvalidWebhookEvent, "webhook events must be one or more of ttr.created, ttr.updated, ttr.cancelled and invitation.responded")
+	ErrNotAdminWebhooks    = errcode.New(errcode.NotWebhookOwner, "unauthorized: only organization owners and admins can manage its webhooks")
+)
+
+// Integrations.
+var (
+	ErrInvalidSlackLinkCode = errcode.New(errcode.InvalidSlackLinkCode, "invalid or expired Slack linking code")
+)
+
+// Feature flags.
+var (
+	ErrFeatureFlagNotFound   = errcode.New(errcode.FeatureFlagNotFound, "feature flag not found")
+	ErrInvalidFeatureFlagKey = errcode.New(errcode.InvalidFeatureFlag, "feature flag keys must be lowercase letters, digits and underscores")
+	ErrInvalidPercentage     = errcode.New(errcode.InvalidFeatureFlag, "rollout percentage must be between 0 and 100")
+)
+
+// Abuse reports.
+var (
+	ErrReportTargetNotFound = errcode.New(errcode.ReportTargetNotFound, "reported user, message or TTR not found")
+	ErrCannotReportSelf     = errcode.New(errcode.CannotReportSelf, "cannot report yourself")
+	ErrReportAlreadyOpen    = errcode.New(errcode.ReportAlreadyOpen, "you already have an open report about this")
+	ErrReportLimitReached   = errcode.New(errcode.ReportLimitReached, "too many reports in the last 24 hours, please try again later")
+	ErrReportNotFound       = errcode.New(errcode.ReportNotFound, "report not found")
+	ErrReportClosed         = errcode.New(errcode.ReportClosed, "report is already closed")
+)
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/featureflag"
+	"go.uber.org/zap"
+)
+
+// FlagService decides which features are on for a user. Flags come from the
+// config's rollouts and from the feature_flags table, which admins change at
+// runtime; a flag in the table replaces its config rollout. Unknown flags are
+// off.
+type FlagService struct {
+	flagRepo repository.FeatureFlagRepository
+	orgRepo  repository.OrganizationRepository
+	rollouts map[string]int
+	logger   *zap.Logger
+}
+
+func NewFlagService(flagRepo repository.FeatureFlagRepository, orgRepo repository.OrganizationRepository, rollouts map[string]int, logger *zap.Logger) *FlagService {
+	return &FlagService{
+		flagRepo: flagRepo,
+		orgRepo:  orgRepo,
+		rollouts: rollouts,
+		logger:   logger,
+	}
+}
+
+// IsEnabled reports whether flag is on for the user.
+func (s *FlagService) IsEnabled(ctx context.Context, userID uuid.UUID, flag string) (bool, error) {
+	rule, ok := s.rollouts[flag]
+	rules := map[string]featureflag.Rule{}
+	if ok {
+		rules[flag] = featureflag.Rule{Enabled: true, Percentage: rule}
+	}
+
+	stored, err := s.flagRepo.FindByKey(ctx, flag)
+	if err != nil {
+		return false, fmt.Errorf("failed to find feature flag: %w", err)
+	}
+	if stored != nil {
+		rules[flag] = stored.Rule()
+	}
+
+	flags, err := s.evaluate(ctx, userID, rules)
+	if err != nil {
+		return false, err
+	}
+	return flags[flag], nil
+}
+
+// EvaluateFlags returns every known flag and whether it is on for the user.
+func (s *FlagService) EvaluateFlags(ctx context.Context, userID uuid.UUID) (map[string]bool, error) {
+	rules := make(map[string]featureflag.Rule, len(s.rollouts))
+	for flag, percentage := range s.rollouts {
+		rules[flag] = featureflag.Rule{Enabled: true, Percentage: percentage}
+	}
+
+	stored, err := s.flagRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	for _, flag := range stored {
+		rules[flag.Key] = flag.Rule()
+	}
+
+	return s.evaluate(ctx, userID, rules)
+}
+
+func (s *FlagService) evaluate(ctx context.Context, userID uuid.UUID, rules map[string]featureflag.Rule) (map[string]bool, error) {
+	var orgIDs []uuid.UUID
+	for _, rule := range rules {
+		if !rule.NeedsOrganizations() {
+			continue
+		}
+		orgs, err := s.orgRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find organizations: %w", err)
+		}
+		for _, org := range orgs {
+			orgIDs = append(orgIDs, org.ID)
+		}
+		break
+	}
+
+	flags := make(map[string]bool, len(rules))
+	for flag, rule := range rules {
+		flags[flag] = rule.Evaluate(flag, userID, orgIDs)
+	}
+	return flags, nil
+}
+
+// ListFlags returns the flags managed at runtime, ordered by key.
+func (s *FlagService) ListFlags(ctx context.Context) ([]*models.FeatureFlag, error) {
+	flags, err := s.flagRepo.FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// SetFlag creates or replaces a runtime flag. It takes effect on the next
+// request; nothing is cached.
+func (s *FlagService) SetFlag(ctx context.Context, key, description string, enabled bool, percentage int, userIDs, orgIDs []uuid.UUID) (*models.FeatureFlag, error) {
+	if !featureflag.ValidKey(key) {
+		return nil, ErrInvalidFeatureFlagKey
+	}
+	if percentage < 0 || percentage > 100 {
+		return nil, ErrInvalidPercentage
+	}
+
+	flag := &models.FeatureFlag{
+		Key:             key,
+		Description:     strings.TrimSpace(description),
+		Enabled:         enabled,
+		Percentage:      percentage,
+		UserIDs:         models.JoinIDs(userIDs),
+		OrganizationIDs: models.JoinIDs(orgIDs),
+	}
+	if err := s.flagRepo.Upsert(ctx, flag); err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	s.logger.Info("Feature flag set",
+		zap.String("flag", key),
+		zap.Bool("enabled", enabled),
+		zap.Int("percentage", percentage),
+	)
+
+	saved, err := s.flagRepo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find feature flag: %w", err)
+	}
+	return saved, nil
+}
+
+// DeleteFlag removes a runtime flag, handing the flag back to its config
+// rollout, if any.
+func (s *FlagService) DeleteFlag(ctx context.Context, key string) error {
+	deleted, err := s.flagRepo.Delete(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+	if !deleted {
+		return ErrFeatureFlagNotFound
+	}
+
+	s.logger.Info("Feature flag deleted", zap.String("flag", key))
+	return nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	// historyCacheTTL bounds how stale a cached year of history gets when
+	// a change other than completing a TTR touches it.
+	historyCacheTTL = time.Hour
+	// historyTopPartners is how many partners a history summary ranks.
+	historyTopPartners = 5
+)
+
+// RoundHistory is a user's rounds in a calendar year: the completed TTRs they
+// were a confirmed player in, grouped by the month they teed off.
+type RoundHistory struct {
+	Year    int
+	Months  []HistoryMonth
+	Summary RoundHistorySummary
+}
+
+// HistoryMonth holds a month's rounds by tee time. Months without rounds are
+// left out of a RoundHistory.
+type HistoryMonth struct {
+	Month  time.Month
+	Rounds []HistoryRound
+}
+
+// HistoryRound is one round in a user's history. Players counts the
+// confirmed players and guests.
+type HistoryRound struct {
+	TTRID          uuid.UUID
+	CourseName     string
+	CourseLocation *string
+	TeeDate        time.Time
+	TeeTime        time.Time
+	CaptainUserID  uuid.UUID
+	Players        int
+}
+
+type RoundHistorySummary struct {
+	RoundsPlayed    int
+	DistinctCourses int
+	TopPartners     []HistoryPartner
+}
+
+// HistoryPartner is a user who played in Rounds of the year's rounds.
+type HistoryPartner struct {
+	User   UserSummary
+	Rounds int
+}
+
+// HistoryService serves users' round history. A year of history is cached
+// per user, and dropped when one of its TTRs is completed or reopened.
+type HistoryService struct {
+	historyRepo repository.HistoryRepository
+	userRepo    repository.UserRepository
+	cache       cache.Cache
+	logger      *zap.Logger
+}
+
+func NewHistoryService(historyRepo repository.HistoryRepository, userRepo repository.UserRepository, cache cache.Cache, logger *zap.Logger) *HistoryService {
+	return &HistoryService{
+		historyRepo: historyRepo,
+		userRepo:    userRepo,
+		cache:       cache,
+		logger:      logger,
+	}
+}
+
+// History returns the user's rounds in year, with the number of rounds, the
+// distinct courses and the partners they played with most.
+func (s *HistoryService) History(ctx context.Context, userID uuid.UUID, year int) (*RoundHistory, error) {
+	key := historyCacheKey(userID, year)
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var history RoundHistory
+		if err := json.Unmarshal(data, &history); err == nil {
+			return &history, nil
+		}
+		s.logger.Warn("Discarding unreadable cached history", zap.String("user_id", userID.String()))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		s.logger.Warn("Failed to read cached history", zap.Error(err))
+	}
+
+	history, err := s.computeHistory(ctx, userID, year)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(history); err == nil {
+		if err := s.cache.Set(ctx, key, data, historyCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache history", zap.Error(err))
+		}
+	}
+	return history, nil
+}
+
+func (s *HistoryService) computeHistory(ctx context.Context, userID uuid.UUID, year int) (*RoundHistory, error) {
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	ttrs, err := s.historyRepo.Rounds(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.historyRepo.Summary(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	partners, err := s.historyRepo.TopPartners(ctx, userID, from, to, historyTopPartners)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &RoundHistory{
+		Year:   year,
+		Months: []HistoryMonth{},
+		Summary: RoundHistorySummary{
+			RoundsPlayed:    summary.Rounds,
+			DistinctCourses: summary.Courses,
+			TopPartners:     make([]HistoryPartner, 0, len(partners)),
+		},
+	}
+	for _, ttr := range ttrs {
+		month := ttr.TeeDate.UTC().Month()
+		if n := len(history.Months); n == 0 || history.Months[n-1].Month != month {
+			history.Months = append(history.Months, HistoryMonth{Month: month})
+		}
+		last := &history.Months[len(history.Months)-1]
+		last.Rounds = append(last.Rounds, newHistoryRound(ttr))
+	}
+
+	ids := make([]uuid.UUID, 0, len(partners))
+	for _, partner := range partners {
+		ids = append(ids, partner.UserID)
+	}
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]*models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	for _, partner := range partners {
+		history.Summary.TopPartners = append(history.Summary.TopPartners, HistoryPartner{
+			User:   NewUserSummary(partner.UserID, byID[partner.UserID]),
+			Rounds: partner.Rounds,
+		})
+	}
+	return history, nil
+}
+
+func newHistoryRound(ttr *models.TTR) HistoryRound {
+	players := len(ttr.Guests)
+	for _, player := range ttr.Players {
+		if player.Status == models.TTRPlayerStatusConfirmed {
+			players++
+		}
+	}
+	return HistoryRound{
+		TTRID:          ttr.ID,
+		CourseName:     ttr.CourseName,
+		CourseLocation: ttr.CourseLocation,
+		TeeDate:        ttr.TeeDate,
+		TeeTime:        ttr.TeeTime,
+		CaptainUserID:  ttr.CaptainUserID,
+		Players:        players,
+	}
+}
+
+// Invalidate drops the cached history of the TTR's tee year for each of its
+// confirmed players. A failure only delays fresh history until the cache
+// entry expires, so it is logged. It does nothing on a nil service.
+func (s *HistoryService) Invalidate(ctx context.Context, ttr *models.TTR) {
+	if s == nil {
+		return
+	}
+	year := ttr.TeeDate.UTC().Year()
+	for _, player := range ttr.Players {
+		if player.Status != models.TTRPlayerStatusConfirmed {
+			continue
+		}
+		if err := s.cache.Delete(ctx, historyCacheKey(player.UserID, year)); err != nil {
+			s.logger.Warn("Failed to invalidate cached history",
+				zap.String("user_id", player.UserID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+func historyCacheKey(userID uuid.UUID, year int) string {
+	return "history:" + userID.String() + ":" + strconv.Itoa(year)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+	"go.uber.org/zap"
+)
+
+// ImpersonationService lets admins act as a user to see what they see, and
+// keeps the audit log of who did so and what they did.
+type ImpersonationService struct {
+	userRepo          repository.UserRepository
+	impersonationRepo repository.ImpersonationRepository
+	auditLogRepo      repository.AuditLogRepository
+	jwtSecret         string
+	tokenDuration     time.Duration
+	logger            *zap.Logger
+}
+
+func NewImpersonationService(
+	userRepo repository.UserRepository,
+	impersonationRepo repository.ImpersonationRepository,
+	auditLogRepo repository.AuditLogRepository,
+	jwtSecret string,
+	tokenDuration time.Duration,
+	logger *zap.Logger,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:          userRepo,
+		impersonationRepo: impersonationRepo,
+		auditLogRepo:      auditLogRepo,
+		jwtSecret:         jwtSecret,
+		tokenDuration:     tokenDuration,
+		logger:            logger,
+	}
+}
+
+// StartImpersonation opens a session for actorID acting as targetID and
+// returns it with its access token. Admins can't be impersonated, so a
+// session never grants more than the target user has.
+func (s *ImpersonationService) StartImpersonation(ctx context.Context, actorID, targetID uuid.UUID, reason string, allowWrites bool, requestID string) (*models.ImpersonationSession, string, error) {
+	if actorID == targetID {
+		return nil, "", ErrCannotImpersonateSelf
+	}
+
+	target, err := s.userRepo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if target == nil {
+		return nil, "", ErrUserNotFound
+	}
+	if target.IsAdmin() {
+		return nil, "", ErrCannotImpersonateAdmin
+	}
+
+	session := &models.ImpersonationSession{
+		ID:           uuid.New(),
+		ActorUserID:  actorID,
+		TargetUserID: targetID,
+		Reason:       strings.TrimSpace(reason),
+		AllowWrites:  allowWrites,
+		ExpiresAt:    time.Now().Add(s.tokenDuration),
+	}
+	if err := s.impersonationRepo.CreateSession(ctx, session); err != nil {
+		return nil, "", fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+
+	token, err := jwt.GenerateImpersonationToken(target.ID, target.Email, target.Role, jwt.Actor{
+		Subject:     actorID,
+		AllowWrites: allowWrites,
+	}, session.ID.String(), s.jwtSecret, session.ExpiresAt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	details := session.Reason
+	if allowWrites {
+		details = strings.TrimSpace(details + " (writes allowed)")
+	}
+	if err := s.record(ctx, models.AuditActionImpersonationStart, session, details, requestID); err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+// StopImpersonation ends the session, after which its token is refused.
+// Stopping a session that already ended does nothing.
+func (s *ImpersonationService) StopImpersonation(ctx context.Context, sessionID uuid.UUID, requestID string) error {
+	session, err := s.impersonationRepo.FindSession(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to find impersonation session: %w", err)
+	}
+	if session == nil {
+		return ErrNotImpersonating
+	}
+
+	ended, err := s.impersonationRepo.EndSession(ctx, sessionID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to end impersonation session: %w", err)
+	}
+	if !ended {
+		return nil
+	}
+
+	return s.record(ctx, models.AuditActionImpersonationStop, session, "", requestID)
+}
+
+// ImpersonationActive reports whether an impersonation token for the session
+// may still be used.
+func (s *ImpersonationService) ImpersonationActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	session, err := s.impersonationRepo.FindSession(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find impersonation session: %w", err)
+	}
+	return session != nil && session.IsActive(time.Now()), nil
+}
+
+// RecordImpersonatedRequest writes a request made during an impersonation
+// session to the audit log under the admin who made it.
+func (s *ImpersonationService) RecordImpersonatedRequest(ctx context.Context, sessionID, actorID, userID uuid.UUID, method, path string, status int, requestID string) error {
+	entry := &models.AuditLogEntry{
+		Action:        models.AuditActionImpersonatedRequest,
+		ActorUserID:   actorID,
+		SubjectUserID: &userID,
+		SessionID:     &sessionID,
+		Details:       fmt.Sprintf("%s %s %d", method, path, status),
+		RequestID:     requestID,
+	}
+	if err := s.auditLogRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLog returns audit log entries, newest first, optionally only
+// those where userID is the actor or the subject.
+func (s *ImpersonationService) ListAuditLog(ctx context.Context, userID *uuid.UUID, limit, offset int) ([]*models.AuditLogEntry, error) {
+	entries, err := s.auditLogRepo.Find(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *ImpersonationService) record(ctx context.Context, action string, session *models.ImpersonationSession, details, requestID string) error {
+	entry := &models.AuditLogEntry{
+		Action:        action,
+		ActorUserID:   session.ActorUserID,
+		SubjectUserID: &session.TargetUserID,
+		SessionID:     &session.ID,
+		Details:       details,
+		RequestID:     requestID,
+	}
+	if err := s.auditLogRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log entry: %w", err)
+	}
+
+	s.logger.Info("Impersonation audit entry",
+		zap.String("action", action),
+		zap.String("actor_user_id", session.ActorUserID.String()),
+		zap.String("target_user_id", session.TargetUserID.String()),
+		zap.String("session_id", session.ID.String()),
+	)
+	return nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// InactivityService cleans up accounts nobody uses. Users who haven't logged
+// in for InactiveAfter are notified that their account will be deactivated,
+// and soft-deleted if they still haven't logged in DeactivateAfter later.
+// Users with an upcoming TTR are skipped at both steps. Each step happens
+// once per user, in one transaction with its audit log entry, so the job can
+// run again at any point.
+type InactivityService struct {
+	userRepo            repository.UserRepository
+	refreshTokenRepo    repository.RefreshTokenRepository
+	auditLogRepo        repository.AuditLogRepository
+	transactor          repository.Transactor
+	notificationService *NotificationService
+	cfg                 config.RetentionConfig
+	logger              *zap.Logger
+}
+
+func NewInactivityService(
+	userRepo repository.UserRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	auditLogRepo repository.AuditLogRepository,
+	transactor repository.Transactor,
+	notificationService *NotificationService,
+	cfg config.RetentionConfig,
+	logger *zap.Logger,
+) *InactivityService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultPurgeBatchSize
+	}
+	return &InactivityService{
+		userRepo:            userRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		auditLogRepo:        auditLogRepo,
+		transactor:          transactor,
+		notificationService: notificationService,
+		cfg:                 cfg,
+		logger:              logger,
+	}
+}
+
+// ProcessInactiveUsers deactivates warned users whose time is up and then
+// warns newly inactive ones. It runs as a periodic job.
+func (s *InactivityService) ProcessInactiveUsers(ctx context.Context) error {
+	if s.cfg.InactiveAfter <= 0 {
+		return nil
+	}
+	now := time.Now()
+	// Tee dates are stored without a time zone, so a TTR from yesterday on
+	// still counts as upcoming.
+	from := now.AddDate(0, 0, -1).Truncate(24 * time.Hour)
+
+	warnedBefore := now.Add(-s.cfg.DeactivateAfter)
+	deactivated, err := s.each(ctx, func(ctx context.Context) ([]*models.User, error) {
+		return s.userRepo.FindWarnedBefore(ctx, warnedBefore, from, s.cfg.BatchSize)
+	}, func(ctx context.Context, user *models.User) (bool, error) {
+		return s.deactivate(ctx, user, warnedBefore, now)
+	})
+	if deactivated > 0 {
+		s.logger.Info("Deactivated inactive users", zap.Int("count", deactivated))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deactivate inactive users: %w", err)
+	}
+
+	inactiveSince := now.Add(-s.cfg.InactiveAfter)
+	warned, err := s.each(ctx, func(ctx context.Context) ([]*models.User, error) {
+		return s.userRepo.FindInactiveSince(ctx, inactiveSince, from, s.cfg.BatchSize)
+	}, func(ctx context.Context, user *models.User) (bool, error) {
+		return s.warn(ctx, user, now)
+	})
+	if warned > 0 {
+		s.logger.Info("Warned inactive users", zap.Int("count", warned))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to warn inactive users: %w", err)
+	}
+	return nil
+}
+
+// each runs step on every user find returns, batch after batch, until a batch
+// comes back short. Users a step handled drop out of find, so it returns how
+// many it handled.
+func (s *InactivityService) each(ctx context.Context, find func(context.Context) ([]*models.User, error), step func(context.Context, *models.User) (bool, error)) (int, error) {
+	var total int
+	for {
+		users, err := find(ctx)
+		if err != nil {
+			return total, err
+		}
+		for _, user := range users {
+			done, err := step(ctx, user)
+			if err != nil {
+				return total, err
+			}
+			if done {
+				total++
+			}
+		}
+		if len(users) < s.cfg.BatchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// warn tells the user when their account will be deactivated unless they log
+// in. It returns false when they were already warned.
+func (s *InactivityService) warn(ctx context.Context, user *models.User, now time.Time) (bool, error) {
+	var warned bool
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		warned, err = s.userRepo.MarkInactivityWarned(ctx, user.ID, now)
+		if err != nil || !warned {
+			return err
+		}
+
+		deadline := now.Add(s.cfg.DeactivateAfter).UTC().Format("2006-01-02")
+		targetType := "user"
+		if err := s.notificationService.Notify(ctx, user.ID, models.NotificationTypeAccountInactive, "account_inactive", map[string]string{"date": deadline}, &targetType, &user.ID); err != nil {
+			return err
+		}
+		return s.audit(ctx, models.AuditActionInactivityWarned, user, "deactivation on "+deadline)
+	})
+	return warned, err
+}
+
+// deactivate soft-deletes the user and signs them out everywhere. It returns
+// false when they logged in since the warning or are already deactivated.
+func (s *InactivityService) deactivate(ctx context.Context, user *models.User, warnedBefore time.Time, now time.Time) (bool, error) {
+	var deactivated bool
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		deactivated, err = s.userRepo.Deactivate(ctx, user.ID, warnedBefore, now)
+		if err != nil || !deactivated {
+			return err
+		}
+
+		if err := s.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+			return fmt.Errorf("failed to revoke tokens: %w", err)
+		}
+		return s.audit(ctx, models.AuditActionDeactivated, user, "warned "+user.InactivityWarnedAt.UTC().Format(time.RFC3339))
+	})
+	return deactivated, err
+}
+
+func (s *InactivityService) audit(ctx context.Context, action string, user *models.User, details string) error {
+	entry := &models.AuditLogEntry{
+		Action:        action,
+		ActorUserID:   uuid.Nil,
+		SubjectUserID: &user.ID,
+		Details:       details,
+	}
+	if err := s.auditLogRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to write audit log entry: %w", err)
+	}
+	return nil
+}
@@ -1,13 +1,16 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
 	"go.uber.org/zap"
 )
 
@@ -15,7 +18,13 @@ type InvitationService struct {
 	invitationRepo      repository.InvitationRepository
 	ttrRepo             repository.TTRRepository
 	userRepo            repository.UserRepository
+	templateRepo        repository.InvitationTemplateRepository
+	ttrService          *TTRService
+	transactor          repository.Transactor
+	authorizer          *Authorizer
 	notificationService *NotificationService
+	webhookService      *WebhookService
+	analytics           *AnalyticsService
 	logger              *zap.Logger
 }
 
@@ -23,67 +32,110 @@ func NewInvitationService(
 	invitationRepo repository.InvitationRepository,
 	ttrRepo repository.TTRRepository,
 	userRepo repository.UserRepository,
+	templateRepo repository.InvitationTemplateRepository,
+	ttrService *TTRService,
+	transactor repository.Transactor,
+	authorizer *Authorizer,
 	notificationService *NotificationService,
+	webhookService *WebhookService,
+	analytics *AnalyticsService,
 	logger *zap.Logger,
 ) *InvitationService {
 	return &InvitationService{
 		invitationRepo:      invitationRepo,
 		ttrRepo:             ttrRepo,
 		userRepo:            userRepo,
+		templateRepo:        templateRepo,
+		ttrService:          ttrService,
+		transactor:          transactor,
+		authorizer:          authorizer,
 		notificationService: notificationService,
+		webhookService:      webhookService,
+		analytics:           analytics,
 		logger:              logger,
 	}
 }
 
-func (s *InvitationService) CreateInvitation(ttrID uuid.UUID, inviterUserID uuid.UUID, inviteeUserID uuid.UUID, message *string) (*models.Invitation, error) {
-	ttr, err := s.ttrRepo.FindByID(ttrID)
+// CreateInvitation invites inviteeUserID to the TTR. The message, or the body
+// of the inviter's saved template templateID when that is set instead, may
+// use the TTR's {{course}}, {{date}}, {{time}} and {{captain}}, which are
+// filled in now; other variables are a *MessageTemplateError.
+func (s *InvitationService) CreateInvitation(ctx context.Context, ttrID uuid.UUID, inviterUserID uuid.UUID, inviteeUserID uuid.UUID, message *string, templateID *uuid.UUID) (*InvitationDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find TTR: %w", err)
-	}
-	if ttr == nil {
-		return nil, errors.New("TTR not found")
+		return nil, err
 	}
 
-	isCaptain := ttr.CaptainUserID == inviterUserID
-	isCoCaptain, err := s.ttrRepo.IsCoCaptain(ttrID, inviterUserID)
+	canInvite, err := s.authorizer.Can(ctx, inviterUserID, ActionTTRInvite, ttr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check co-captain status: %w", err)
+		return nil, err
+	}
+	if !canInvite {
+		return nil, ErrNotManagerInvite
 	}
 
-	if !isCaptain && !isCoCaptain {
-		return nil, errors.New("unauthorized: only captain or co-captain can send invitations")
+	if inviteeUserID == inviterUserID {
+		return nil, ErrCannotInviteSelf
+	}
+	if inviteeUserID == ttr.CaptainUserID {
+		return nil, ErrCannotInviteCaptain
 	}
 
-	inviteeUser, err := s.userRepo.FindByID(inviteeUserID)
+	if ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted {
+		return nil, ErrTTRClosed
+	}
+	if ttr.TeeDateTime().Before(time.Now()) {
+		return nil, ErrTeeTimePassed
+	}
+
+	inviteeUser, err := s.userRepo.FindByIDUnscoped(ctx, inviteeUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find invitee user: %w", err)
 	}
 	if inviteeUser == nil {
-		return nil, errors.New("invitee user not found")
+		return nil, ErrInviteeNotFound
+	}
+	if inviteeUser.IsDeleted() {
+		return nil, ErrCannotInviteDeletedUser
 	}
 
-	players, err := s.ttrRepo.GetPlayers(ttrID)
+	players, err := s.ttrRepo.GetPlayers(ctx, ttrID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get players: %w", err)
 	}
-	if len(players) >= ttr.MaxPlayers {
-		return nil, errors.New("TTR is full")
+	if activePlayers(players)+len(ttr.Guests) >= ttr.MaxPlayers {
+		return nil, ErrTTRFull
 	}
 
-	isAlreadyPlayer, err := s.ttrRepo.IsPlayer(ttrID, inviteeUserID)
+	isAlreadyPlayer, err := s.ttrRepo.IsPlayer(ctx, ttrID, inviteeUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check player status: %w", err)
 	}
 	if isAlreadyPlayer {
-		return nil, errors.New("invitee is already a player in this TTR")
+		return nil, ErrInviteeAlreadyPlayer
 	}
 
-	existingInvitation, err := s.invitationRepo.FindByTTRAndInvitee(ttrID, inviteeUserID)
+	existingInvitation, err := s.invitationRepo.FindByTTRAndInvitee(ctx, ttrID, inviteeUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing invitation: %w", err)
 	}
 	if existingInvitation != nil && existingInvitation.Status == models.InvitationStatusPending {
-		return nil, errors.New("pending invitation already exists for this user")
+		return nil, ErrInvitationPending
+	}
+
+	if templateID != nil {
+		template, err := findOwnedTemplate(ctx, s.templateRepo, inviterUserID, *templateID)
+		if err != nil {
+			return nil, err
+		}
+		message = &template.Body
+	}
+	if message != nil {
+		rendered, err := renderInvitationMessage(*message, ttr)
+		if err != nil {
+			return nil, err
+		}
+		message = &rendered
 	}
 
 	invitation := &models.Invitation{
@@ -91,142 +143,634 @@ func (s *InvitationService) CreateInvitation(ttrID uuid.UUID, inviterUserID uuid
 		InviterUserID: inviterUserID,
 		InviteeUserID: inviteeUserID,
 		Status:        models.InvitationStatusPending,
-		Message:       message,
+		Message:       sanitize.TextPtr(message),
 	}
 
-	if err := s.invitationRepo.Create(invitation); err != nil {
-		return nil, fmt.Errorf("failed to create invitation: %w", err)
-	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+			// Another invite for the same user can slip in after the check above
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrInvitationPending
+			}
+			return fmt.Errorf("failed to create invitation: %w", err)
+		}
 
-	targetType := "invitation"
-	notifTitle := "New TTR Invitation"
-	notifMessage := fmt.Sprintf("You have been invited to join a tee time at %s", ttr.CourseName)
-	if err := s.notificationService.CreateNotification(inviteeUserID, "invitation_received", notifTitle, notifMessage, &targetType, &invitation.ID); err != nil {
-		s.logger.Error("Failed to create notification", zap.Error(err))
+		targetType := "invitation"
+		params := map[string]string{"course": ttr.CourseName}
+		return s.notificationService.Notify(ctx, inviteeUserID, models.NotificationTypeInvitation, "invitation_received", params, &targetType, &invitation.ID)
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.analytics.InvitationSent(invitation)
 
-	createdInvitation, err := s.invitationRepo.FindByID(invitation.ID)
+	createdInvitation, err := s.invitationRepo.FindByID(ctx, invitation.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve created invitation: %w", err)
 	}
 
-	return createdInvitation, nil
+	return NewInvitationDetail(createdInvitation), nil
 }
 
-func (s *InvitationService) RespondToInvitation(invitationID uuid.UUID, inviteeUserID uuid.UUID, status string) (*models.Invitation, error) {
-	validStatuses := map[string]bool{
+// invitationTransitions lists the answers an invitee can give from each
+// status. MAYBE can still be changed; YES and NO are final.
+var invitationTransitions = map[models.InvitationStatus]map[models.InvitationStatus]bool{
+	models.InvitationStatusPending: {
 		models.InvitationStatusYes:   true,
 		models.InvitationStatusNo:    true,
 		models.InvitationStatusMaybe: true,
+	},
+	models.InvitationStatusMaybe: {
+		models.InvitationStatusYes: true,
+		models.InvitationStatusNo:  true,
+	},
+}
+
+// RespondToInvitation records the invitee's answer and tells the inviter.
+// declineReason is optional, only allowed with NO or MAYBE, and only shown to
+// the invitee and the TTR's captains.
+func (s *InvitationService) RespondToInvitation(ctx context.Context, invitationID uuid.UUID, inviteeUserID uuid.UUID, status models.InvitationStatus, declineReason *string) (*InvitationDetail, error) {
+	if !invitationTransitions[models.InvitationStatusPending][status] {
+		return nil, ErrInvalidInvitationStatus
+	}
+	if declineReason != nil {
+		if trimmed := strings.TrimSpace(*declineReason); trimmed != "" {
+			declineReason = &trimmed
+		} else {
+			declineReason = nil
+		}
 	}
-	if !validStatuses[status] {
-		return nil, errors.New("invalid invitation status")
+	if declineReason != nil && status == models.InvitationStatusYes {
+		return nil, ErrDeclineReasonNotAllowed
 	}
 
-	invitation, err := s.invitationRepo.FindByID(invitationID)
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find invitation: %w", err)
 	}
 	if invitation == nil {
-		return nil, errors.New("invitation not found")
+		return nil, ErrInvitationNotFound
 	}
 
-	if invitation.InviteeUserID != inviteeUserID {
-		return nil, errors.New("unauthorized: you can only respond to your own invitations")
+	canRespond, err := s.authorizer.Can(ctx, inviteeUserID, ActionInvitationRespond, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if !canRespond {
+		return nil, ErrNotInvitee
 	}
 
-	if invitation.Status != models.InvitationStatusPending {
-		return nil, errors.New("invitation has already been responded to")
+	retried := invitation.Status == models.InvitationStatusYes && status == models.InvitationStatusYes
+	if !retried && !invitationTransitions[invitation.Status][status] {
+		return nil, ErrInvitationAnswered
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, invitation.TTRID)
+	if err != nil {
+		return nil, err
+	}
+
+	if retried {
+		// A YES that already went through succeeds again, so clients can
+		// safely retry one whose response they never saw.
+		if !ttr.HasPlayer(inviteeUserID) {
+			return nil, ErrInvitationAnswered
+		}
+		return NewInvitationDetail(invitation), nil
 	}
 
 	now := time.Now()
+	if ttr.RSVPDeadlinePassed(now) {
+		return nil, ErrRSVPDeadlinePassed
+	}
+
 	invitation.Status = status
+	invitation.DeclineReason = declineReason
 	invitation.RespondedAt = &now
 
-	if status == models.InvitationStatusYes {
-		ttr, err := s.ttrRepo.FindByID(invitation.TTRID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to find TTR: %w", err)
+	// The player is added, the invitation answered and the inviter told in
+	// one transaction, so a failure can't leave the invitee on the roster
+	// with the invitation still open.
+	previous := ttr.Status
+	var joined bool
+	var synced models.TTRStatus
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if status == models.InvitationStatusYes {
+			players, err := s.ttrRepo.GetPlayers(ctx, invitation.TTRID)
+			if err != nil {
+				return fmt.Errorf("failed to get players: %w", err)
+			}
+			// An invitee who is already a player, e.g. through an invite
+			// link, just has the invitation answered.
+			joined = !hasPlayer(players, inviteeUserID)
+			if joined {
+				// The slot is checked where the player is added, with the
+				// TTR locked, so concurrent accepts can't overfill it.
+				synced, err = s.ttrService.applyRosterChange(ctx, ttr, func(ctx context.Context) error {
+					return s.ttrRepo.AddPlayerIfOpen(ctx, invitation.TTRID, inviteeUserID, models.DefaultPlayerStatus)
+				})
+				if errors.Is(err, repository.ErrTTRFull) {
+					return ErrTTRFullForInvitation
+				}
+				if errors.Is(err, repository.ErrDuplicate) {
+					return ErrAlreadyPlayer
+				}
+				if err != nil {
+					return fmt.Errorf("failed to add player to TTR: %w", err)
+				}
+			}
+		}
+
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return fmt.Errorf("failed to update invitation: %w", err)
 		}
-		if ttr == nil {
-			return nil, errors.New("TTR not found")
+		if err := s.notifyInviter(ctx, invitation, ttr); err != nil {
+			return err
 		}
+		return s.webhookService.PublishInvitationResponse(ctx, invitation, ttr)
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		players, err := s.ttrRepo.GetPlayers(invitation.TTRID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get players: %w", err)
+	if joined {
+		s.ttrService.recordRosterChange(ctx, ttr, previous, synced, playerJoined(inviteeUserID))
+	}
+	s.analytics.InvitationResponded(invitation)
+
+	updatedInvitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve updated invitation: %w", err)
+	}
+
+	return NewInvitationDetail(updatedInvitation), nil
+}
+
+func hasPlayer(players []*models.TTRPlayer, userID uuid.UUID) bool {
+	for _, player := range players {
+		if player.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// activePlayers is how many of the players take a slot.
+func activePlayers(players []*models.TTRPlayer) int {
+	count := 0
+	for _, player := range players {
+		if player.Status.IsActive() {
+			count++
 		}
-		if len(players) >= ttr.MaxPlayers {
-			return nil, errors.New("TTR is full, cannot accept invitation")
+	}
+	return count
+}
+
+// notifyInviter tells the inviter how the invitee answered, including the
+// decline reason if one was given.
+func (s *InvitationService) notifyInviter(ctx context.Context, invitation *models.Invitation, ttr *models.TTR) error {
+	template := map[models.InvitationStatus]string{
+		models.InvitationStatusYes:   "invitation_accepted",
+		models.InvitationStatusNo:    "invitation_declined",
+		models.InvitationStatusMaybe: "invitation_maybe",
+	}[invitation.Status]
+
+	params := map[string]string{"course": ttr.CourseName}
+	if invitation.InviteeUser != nil {
+		params["name"] = strings.TrimSpace(invitation.InviteeUser.FirstName + " " + invitation.InviteeUser.LastName)
+	}
+	if invitation.DeclineReason != nil {
+		template += "_reason"
+		params["reason"] = *invitation.DeclineReason
+	}
+
+	targetType := "invitation"
+	return s.notificationService.Notify(ctx, invitation.InviterUserID, models.NotificationTypeInvitationResponse, template, params, &targetType, &invitation.ID)
+}
+
+// ForwardInvitation passes the invitee's PENDING invitation on to
+// targetUserID, e.g. when they can't make it but a friend can. The invitation
+// is answered NO and a FORWARD_REQUESTED invitation to the target is created
+// for the captain to approve; the target hears nothing until then.
+func (s *InvitationService) ForwardInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID, targetUserID uuid.UUID, message *string) (*InvitationDetail, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if invitation == nil {
+		return nil, ErrInvitationNotFound
+	}
+
+	canForward, err := s.authorizer.Can(ctx, userID, ActionInvitationRespond, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if !canForward {
+		return nil, ErrNotInviteeForward
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return nil, ErrInvitationAnswered
+	}
+	if targetUserID == userID {
+		return nil, ErrCannotInviteSelf
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, invitation.TTRID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if ttr.RSVPDeadlinePassed(now) {
+		return nil, ErrRSVPDeadlinePassed
+	}
+	target, err := s.checkForwardTarget(ctx, ttr, targetUserID, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.Status = models.InvitationStatusNo
+	invitation.RespondedAt = &now
+
+	forwarded := &models.Invitation{
+		TTRID:             ttr.ID,
+		InviterUserID:     userID,
+		InviteeUserID:     targetUserID,
+		ForwardedByUserID: &userID,
+		Status:            models.InvitationStatusForwardRequested,
+		Message:           sanitize.TextPtr(message),
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return fmt.Errorf("failed to update invitation: %w", err)
+		}
+		if err := s.invitationRepo.Create(ctx, forwarded); err != nil {
+			return fmt.Errorf("failed to create forwarded invitation: %w", err)
 		}
 
-		if err := s.ttrRepo.AddPlayer(invitation.TTRID, inviteeUserID, models.TTRPlayerStatusConfirmed); err != nil {
-			return nil, fmt.Errorf("failed to add player to TTR: %w", err)
+		targetType := "invitation"
+		params := map[string]string{
+			"course": ttr.CourseName,
+			"target": strings.TrimSpace(target.FirstName + " " + target.LastName),
 		}
+		if invitation.InviteeUser != nil {
+			params["name"] = strings.TrimSpace(invitation.InviteeUser.FirstName + " " + invitation.InviteeUser.LastName)
+		}
+		if err := s.notificationService.Notify(ctx, ttr.CaptainUserID, models.NotificationTypeInvitationForwarded, "invitation_forward_requested", params, &targetType, &forwarded.ID); err != nil {
+			return err
+		}
+		return s.webhookService.PublishInvitationResponse(ctx, invitation, ttr)
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.notificationService.ResolveInvitation(ctx, invitation)
+	s.analytics.InvitationResponded(invitation)
 
-	if err := s.invitationRepo.Update(invitation); err != nil {
-		return nil, fmt.Errorf("failed to update invitation: %w", err)
+	created, err := s.invitationRepo.FindByID(ctx, forwarded.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve forwarded invitation: %w", err)
 	}
 
-	updatedInvitation, err := s.invitationRepo.FindByID(invitationID)
+	return NewInvitationDetail(created), nil
+}
+
+// ApproveForwardedInvitation turns a FORWARD_REQUESTED invitation into a
+// PENDING one from the approving captain or co-captain, and invites the
+// target. The checks made when it was forwarded are made again, since the
+// roster may have changed since.
+func (s *InvitationService) ApproveForwardedInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*InvitationDetail, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve updated invitation: %w", err)
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if invitation == nil {
+		return nil, ErrInvitationNotFound
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, invitation.TTRID)
+	if err != nil {
+		return nil, err
+	}
+	canApprove, err := s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+	if err != nil {
+		return nil, err
 	}
+	if !canApprove {
+		return nil, ErrNotManagerApproveForward
+	}
+	if invitation.Status != models.InvitationStatusForwardRequested {
+		return nil, ErrInvitationNotForwarded
+	}
+
+	if ttr.RSVPDeadlinePassed(time.Now()) {
+		return nil, ErrRSVPDeadlinePassed
+	}
+	if _, err := s.checkForwardTarget(ctx, ttr, invitation.InviteeUserID, invitation.ID); err != nil {
+		return nil, err
+	}
+	players, err := s.ttrRepo.GetPlayers(ctx, ttr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get players: %w", err)
+	}
+	if activePlayers(players)+len(ttr.Guests) >= ttr.MaxPlayers {
+		return nil, ErrTTRFull
+	}
+
+	invitation.Status = models.InvitationStatusPending
+	invitation.InviterUserID = userID
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrInvitationPending
+			}
+			return fmt.Errorf("failed to approve invitation: %w", err)
+		}
+
+		targetType := "invitation"
+		params := map[string]string{"course": ttr.CourseName}
+		return s.notificationService.Notify(ctx, invitation.InviteeUserID, models.NotificationTypeInvitation, "invitation_received", params, &targetType, &invitation.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.analytics.InvitationSent(invitation)
+
+	approved, err := s.invitationRepo.FindByID(ctx, invitation.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve approved invitation: %w", err)
+	}
+
+	return NewInvitationDetail(approved), nil
+}
+
+// checkForwardTarget returns the user an invitation to the TTR may be
+// forwarded to: not the captain or anyone else on the roster, not blocked
+// by the captain, and without an open invitation of their own other than
+// forwardedID.
+func (s *InvitationService) checkForwardTarget(ctx context.Context, ttr *models.TTR, targetUserID uuid.UUID, forwardedID uuid.UUID) (*models.User, error) {
+	if targetUserID == ttr.CaptainUserID {
+		return nil, ErrCannotInviteCaptain
+	}
+	if ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted {
+		return nil, ErrTTRClosed
+	}
+	if ttr.TeeDateTime().Before(time.Now()) {
+		return nil, ErrTeeTimePassed
+	}
+
+	target, err := s.userRepo.FindByIDUnscoped(ctx, targetUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitee user: %w", err)
+	}
+	if target == nil {
+		return nil, ErrInviteeNotFound
+	}
+	if target.IsDeleted() {
+		return nil, ErrCannotInviteDeletedUser
+	}
+
+	if ttr.HasPlayer(targetUserID) || ttr.HasCoCaptain(targetUserID) {
+		return nil, ErrForwardToRoster
+	}
+	blocked, err := s.userRepo.IsBlocked(ctx, ttr.CaptainUserID, targetUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blocked users: %w", err)
+	}
+	if blocked {
+		return nil, ErrForwardToBlocked
+	}
+
+	existing, err := s.invitationRepo.FindByTTRsAndInvitee(ctx, []uuid.UUID{ttr.ID}, targetUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing invitations: %w", err)
+	}
+	for _, invitation := range existing {
+		if invitation.ID == forwardedID {
+			continue
+		}
+		if invitation.Status == models.InvitationStatusPending || invitation.Status == models.InvitationStatusForwardRequested {
+			return nil, ErrInvitationPending
+		}
+	}
+	return target, nil
+}
+
+// GetTTRInvitations lists every invitation sent for the TTR, newest first,
+// with the invitees' answers and decline reasons. Only the captain and
+// co-captains can see it.
+func (s *InvitationService) GetTTRInvitations(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) ([]*InvitationDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrNotManagerListInvitations
+	}
+
+	invitations, err := s.invitationRepo.FindByTTRID(ctx, ttrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TTR invitations: %w", err)
+	}
+	return newInvitationDetails(invitations), nil
+}
 
-	return updatedInvitation, nil
+// GetTTRsInvitations is GetTTRInvitations for several TTRs, reading their
+// invitations in one query. TTRs userID can't list the invitations of are
+// left out rather than failing the rest.
+func (s *InvitationService) GetTTRsInvitations(ctx context.Context, ttrIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]*InvitationDetail, error) {
+	ttrs, err := s.authorizer.TTRs(ctx, ttrIDs)
+	if err != nil {
+		return nil, err
+	}
+	allowed := make([]uuid.UUID, 0, len(ttrs))
+	for id, ttr := range ttrs {
+		canView, err := s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+		if err != nil {
+			return nil, err
+		}
+		if canView {
+			allowed = append(allowed, id)
+		}
+	}
+
+	invitations, err := s.invitationRepo.FindByTTRIDs(ctx, allowed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get TTR invitations: %w", err)
+	}
+	details := make(map[uuid.UUID][]*InvitationDetail, len(allowed))
+	for _, id := range allowed {
+		details[id] = []*InvitationDetail{}
+	}
+	for _, invitation := range invitations {
+		details[invitation.TTRID] = append(details[invitation.TTRID], NewInvitationDetail(invitation))
+	}
+	return details, nil
 }
 
-func (s *InvitationService) GetInvitation(id uuid.UUID) (*models.Invitation, error) {
-	invitation, err := s.invitationRepo.FindByID(id)
+// GetInvitation returns the invitation if userID is its inviter or invitee, or
+// manages its TTR. Anyone else gets "invitation not found" so the ID's
+// existence isn't confirmed.
+func (s *InvitationService) GetInvitation(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*InvitationDetail, error) {
+	invitation, err := s.invitationRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get invitation: %w", err)
 	}
 	if invitation == nil {
-		return nil, errors.New("invitation not found")
+		return nil, ErrInvitationNotFound
 	}
-	return invitation, nil
+
+	canView, err := s.authorizer.Can(ctx, userID, ActionInvitationView, invitation)
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, ErrInvitationNotFound
+	}
+
+	details, err := s.invitationDetails(ctx, []*models.Invitation{invitation}, false)
+	if err != nil {
+		return nil, err
+	}
+	return details[0], nil
 }
 
-func (s *InvitationService) GetUserInvitations(userID uuid.UUID, received bool) ([]*models.Invitation, error) {
+func (s *InvitationService) GetUserInvitations(ctx context.Context, userID uuid.UUID, received bool) ([]*InvitationDetail, error) {
 	var invitations []*models.Invitation
 	var err error
 
 	if received {
-		invitations, err = s.invitationRepo.FindReceivedByUserID(userID)
+		invitations, err = s.invitationRepo.FindReceivedByUserID(ctx, userID)
 	} else {
-		invitations, err = s.invitationRepo.FindSentByUserID(userID)
+		invitations, err = s.invitationRepo.FindSentByUserID(ctx, userID)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user invitations: %w", err)
 	}
+	if received {
+		// Forwarded invitations only reach the invitee once approved
+		visible := invitations[:0]
+		for _, invitation := range invitations {
+			if invitation.Status != models.InvitationStatusForwardRequested {
+				visible = append(visible, invitation)
+			}
+		}
+		invitations = visible
+	}
+
+	return s.invitationDetails(ctx, invitations, received)
+}
+
+// invitationDetails builds the invitations' views with how full each TTR is,
+// so the invitee can see it. withAcceptable also sets Acceptable, for
+// invitations the user received.
+func (s *InvitationService) invitationDetails(ctx context.Context, invitations []*models.Invitation, withAcceptable bool) ([]*InvitationDetail, error) {
+	ttrIDs := make([]uuid.UUID, 0, len(invitations))
+	for _, invitation := range invitations {
+		if invitation.TTR != nil {
+			ttrIDs = append(ttrIDs, invitation.TTR.ID)
+		}
+	}
+	counts, err := s.ttrRepo.CountPlayers(ctx, ttrIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count players: %w", err)
+	}
 
-	return invitations, nil
+	now := time.Now()
+	details := make([]*InvitationDetail, 0, len(invitations))
+	for _, invitation := range invitations {
+		detail := NewInvitationDetail(invitation)
+		if invitation.TTR != nil {
+			count := counts[invitation.TTR.ID]
+			openSlots := invitation.TTR.OpenSlots(count)
+			detail.TTR.OpenSlots = &openSlots
+			detail.TTR.ConfirmedCount = &count.Confirmed
+		}
+		if withAcceptable {
+			acceptable := isAcceptable(invitation, counts, now)
+			detail.Acceptable = &acceptable
+		}
+		details = append(details, detail)
+	}
+	return details, nil
+}
+
+// isAcceptable reports whether answering YES to the invitation would go
+// through right now. It is a hint for clients; RespondToInvitation still
+// checks capacity itself.
+func isAcceptable(invitation *models.Invitation, counts map[uuid.UUID]models.PlayerCounts, now time.Time) bool {
+	ttr := invitation.TTR
+	if ttr == nil || !invitationTransitions[invitation.Status][models.InvitationStatusYes] {
+		return false
+	}
+	return !ttr.RSVPDeadlinePassed(now) && ttr.OpenSlots(counts[ttr.ID]) > 0
 }
 
-func (s *InvitationService) CancelInvitation(invitationID uuid.UUID, userID uuid.UUID) error {
-	invitation, err := s.invitationRepo.FindByID(invitationID)
+// CancelInvitation withdraws the inviter's PENDING invitation. A forwarded
+// invitation waiting for approval can also be turned down by the TTR's
+// captain and co-captains.
+func (s *InvitationService) CancelInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) error {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
 	if err != nil {
 		return fmt.Errorf("failed to find invitation: %w", err)
 	}
 	if invitation == nil {
-		return errors.New("invitation not found")
+		return ErrInvitationNotFound
 	}
 
-	if invitation.InviterUserID != userID {
-		return errors.New("unauthorized: only the inviter can cancel the invitation")
+	canCancel, err := s.authorizer.Can(ctx, userID, ActionInvitationCancel, invitation)
+	if err != nil {
+		return err
+	}
+	forwardRequest := invitation.Status == models.InvitationStatusForwardRequested
+	if !canCancel && forwardRequest {
+		// Captains and co-captains turn down forwarded invitations by
+		// cancelling them.
+		ttr, err := s.authorizer.TTR(ctx, invitation.TTRID)
+		if err != nil {
+			return err
+		}
+		canCancel, err = s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+		if err != nil {
+			return err
+		}
+	}
+	if !canCancel {
+		return ErrNotInviter
 	}
 
-	if invitation.Status != models.InvitationStatusPending {
-		return errors.New("only pending invitations can be canceled")
+	if invitation.Status != models.InvitationStatusPending && !forwardRequest {
+		return ErrInvitationNotCancelable
 	}
 
 	invitation.Status = models.InvitationStatusCanceled
 
-	if err := s.invitationRepo.Update(invitation); err != nil {
-		return fmt.Errorf("failed to cancel invitation: %w", err)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+			return fmt.Errorf("failed to cancel invitation: %w", err)
+		}
+		if forwardRequest {
+			// The invitee was never told about it
+			return nil
+		}
+
+		targetType := "invitation"
+		params := map[string]string{}
+		if invitation.TTR != nil {
+			params["course"] = invitation.TTR.CourseName
+		}
+		return s.notificationService.Notify(ctx, invitation.InviteeUserID, models.NotificationTypeInvitationWithdrawn, "invitation_withdrawn", params, &targetType, &invitation.ID)
+	})
+	if err != nil {
+		return err
 	}
+	s.notificationService.ResolveInvitation(ctx, invitation)
 
 	return nil
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type InvitationTemplateService struct {
+	templateRepo repository.InvitationTemplateRepository
+}
+
+func NewInvitationTemplateService(templateRepo repository.InvitationTemplateRepository) *InvitationTemplateService {
+	return &InvitationTemplateService{templateRepo: templateRepo}
+}
+
+// CreateTemplate saves an invitation message for userID to reuse. The body
+// is checked the way invitation messages are, so a saved template always
+// renders.
+func (s *InvitationTemplateService) CreateTemplate(ctx context.Context, userID uuid.UUID, name string, body string) (*models.InvitationTemplate, error) {
+	if _, err := parseMessageTemplate(body); err != nil {
+		return nil, err
+	}
+
+	template := &models.InvitationTemplate{
+		UserID: userID,
+		Name:   strings.TrimSpace(name),
+		Body:   body,
+	}
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create invitation template: %w", err)
+	}
+	return template, nil
+}
+
+// ListTemplates returns userID's templates by name.
+func (s *InvitationTemplateService) ListTemplates(ctx context.Context, userID uuid.UUID) ([]*models.InvitationTemplate, error) {
+	templates, err := s.templateRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitation templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns one of userID's templates. Other users' templates are
+// ErrInvitationTemplateNotFound, as missing ones are.
+func (s *InvitationTemplateService) GetTemplate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID) (*models.InvitationTemplate, error) {
+	return findOwnedTemplate(ctx, s.templateRepo, userID, templateID)
+}
+
+// UpdateTemplate changes the given fields of one of userID's templates and
+// leaves nil ones as they are.
+func (s *InvitationTemplateService) UpdateTemplate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID, name *string, body *string) (*models.InvitationTemplate, error) {
+	template, err := findOwnedTemplate(ctx, s.templateRepo, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		if _, err := parseMessageTemplate(*body); err != nil {
+			return nil, err
+		}
+		template.Body = *body
+	}
+	if name != nil {
+		template.Name = strings.TrimSpace(*name)
+	}
+
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to update invitation template: %w", err)
+	}
+	return template, nil
+}
+
+// DeleteTemplate deletes one of userID's templates. Invitations sent with it
+// keep their message.
+func (s *InvitationTemplateService) DeleteTemplate(ctx context.Context, userID uuid.UUID, templateID uuid.UUID) error {
+	if _, err := findOwnedTemplate(ctx, s.templateRepo, userID, templateID); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(ctx, templateID); err != nil {
+		return fmt.Errorf("failed to delete invitation template: %w", err)
+	}
+	return nil
+}
+
+func findOwnedTemplate(ctx context.Context, templateRepo repository.InvitationTemplateRepository, userID uuid.UUID, templateID uuid.UUID) (*models.InvitationTemplate, error) {
+	template, err := templateRepo.FindByID(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation template: %w", err)
+	}
+	if template == nil || template.UserID != userID {
+		return nil, ErrInvitationTemplateNotFound
+	}
+	return template, nil
+}
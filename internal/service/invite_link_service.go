@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// InviteLinkService manages shareable links that let the first few users who
+// open them join a TTR, first come first served.
+type InviteLinkService struct {
+	inviteLinkRepo      repository.InviteLinkRepository
+	ttrRepo             repository.TTRRepository
+	ttrService          *TTRService
+	authorizer          *Authorizer
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+func NewInviteLinkService(inviteLinkRepo repository.InviteLinkRepository, ttrRepo repository.TTRRepository, ttrService *TTRService, authorizer *Authorizer, notificationService *NotificationService, logger *zap.Logger) *InviteLinkService {
+	return &InviteLinkService{
+		inviteLinkRepo:      inviteLinkRepo,
+		ttrRepo:             ttrRepo,
+		ttrService:          ttrService,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// CreateInviteLink creates a link the first maxUses users can join the TTR
+// through, until expiresAt when it is set. Only the captain and co-captains
+// can create links, and only for an open TTR that hasn't teed off.
+func (s *InviteLinkService) CreateInviteLink(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, maxUses int, expiresAt *time.Time) (*InviteLinkDetail, error) {
+	ttr, err := s.managedTTR(ctx, ttrID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted {
+		return nil, ErrTTRClosed
+	}
+	if ttr.TeeDateTime().Before(now) {
+		return nil, ErrTeeTimePassed
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, ErrInvalidInviteLinkExpiry
+	}
+
+	token, err := newInviteLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	link := &models.InviteLink{
+		TTRID:           ttrID,
+		Token:           token,
+		CreatedByUserID: userID,
+		MaxUses:         maxUses,
+		ExpiresAt:       expiresAt,
+	}
+	if err := s.inviteLinkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to create invite link: %w", err)
+	}
+
+	return NewInviteLinkDetail(link), nil
+}
+
+// ListInviteLinks returns the TTR's invite links, revoked ones included,
+// newest first.
+func (s *InviteLinkService) ListInviteLinks(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) ([]*InviteLinkDetail, error) {
+	if _, err := s.managedTTR(ctx, ttrID, userID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.inviteLinkRepo.FindByTTRID(ctx, ttrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invite links: %w", err)
+	}
+
+	details := make([]*InviteLinkDetail, 0, len(links))
+	for _, link := range links {
+		details = append(details, NewInviteLinkDetail(link))
+	}
+	return details, nil
+}
+
+// RevokeInviteLink stops the link from being accepted. Players who already
+// joined through it stay on the roster. Revoking a revoked link is a no-op.
+func (s *InviteLinkService) RevokeInviteLink(ctx context.Context, ttrID uuid.UUID, linkID uuid.UUID, userID uuid.UUID) (*InviteLinkDetail, error) {
+	if _, err := s.managedTTR(ctx, ttrID, userID); err != nil {
+		return nil, err
+	}
+
+	link, err := s.inviteLinkRepo.FindByID(ctx, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invite link: %w", err)
+	}
+	if link == nil || link.TTRID != ttrID {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	if link.RevokedAt == nil {
+		now := time.Now()
+		if err := s.inviteLinkRepo.Revoke(ctx, link.ID, now); err != nil {
+			return nil, fmt.Errorf("failed to revoke invite link: %w", err)
+		}
+		link.RevokedAt = &now
+	}
+
+	return NewInviteLinkDetail(link), nil
+}
+
+// PreviewInviteLink shows the TTR behind a link to anyone holding its token,
+// signed in or not.
+func (s *InviteLinkService) PreviewInviteLink(ctx context.Context, token string) (*InviteLinkPreview, error) {
+	link, err := s.inviteLinkRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invite link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	ttr, err := s.ttrRepo.FindByID(ctx, link.TTRID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TTR: %w", err)
+	}
+	if ttr == nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	openSlots := ttr.MaxPlayers - ttr.Headcount()
+	if openSlots < 0 {
+		openSlots = 0
+	}
+	return &InviteLinkPreview{
+		CourseName:     ttr.CourseName,
+		CourseLocation: ttr.CourseLocation,
+		TeeDate:        ttr.TeeDate,
+		TeeTime:        ttr.TeeTime,
+		CaptainUser:    NewUserSummary(ttr.CaptainUserID, ttr.CaptainUser),
+		OpenSlots:      openSlots,
+		RemainingUses:  link.RemainingUses(),
+		ExpiresAt:      link.ExpiresAt,
+		Usable:         checkInviteLink(link, ttr, time.Now()) == nil && openSlots > 0 && link.RemainingUses() > 0,
+	}, nil
+}
+
+// AcceptInviteLink adds userID to the link's TTR as a confirmed player and
+// notifies the captain. Taking one of the link's uses and the TTR slot
+// happen atomically, so concurrent accepts never exceed either.
+func (s *InviteLinkService) AcceptInviteLink(ctx context.Context, token string, userID uuid.UUID) (*TTRDetail, error) {
+	link, err := s.inviteLinkRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invite link: %w", err)
+	}
+	if link == nil {
+		return nil, ErrInviteLinkNotFound
+	}
+
+	ttr, err := s.ttrRepo.FindByID(ctx, link.TTRID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TTR: %w", err)
+	}
+	if ttr == nil {
+		return nil, ErrInviteLinkNotFound
+	}
+	if err := checkInviteLink(link, ttr, time.Now()); err != nil {
+		return nil, err
+	}
+	if ttr.HasPlayer(userID) {
+		return nil, ErrAlreadyPlayer
+	}
+
+	if err := s.ttrService.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		return s.inviteLinkRepo.Redeem(ctx, link, userID)
+	}, playerJoined(userID)); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrTTRFull):
+			return nil, ErrTTRFull
+		case errors.Is(err, repository.ErrNoUsesLeft):
+			return nil, ErrInviteLinkUsedUp
+		case errors.Is(err, repository.ErrDuplicate):
+			return nil, ErrAlreadyPlayer
+		}
+		return nil, fmt.Errorf("failed to accept invite link: %w", err)
+	}
+
+	targetType := "ttr"
+	params := map[string]string{"course": ttr.CourseName}
+	if err := s.notificationService.Notify(ctx, ttr.CaptainUserID, models.NotificationTypePlayerJoined, "player_joined", params, &targetType, &ttr.ID); err != nil {
+		s.logger.Error("Failed to create notification", zap.Error(err))
+	}
+
+	joined, err := s.ttrRepo.FindByID(ctx, ttr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve TTR: %w", err)
+	}
+	return NewTTRDetail(joined), nil
+}
+
+// managedTTR loads the TTR and checks that userID may manage its invite
+// links.
+func (s *InviteLinkService) managedTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (*models.TTR, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+
+	canInvite, err := s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+	if err != nil {
+		return nil, err
+	}
+	if !canInvite {
+		return nil, ErrNotManagerInviteLinks
+	}
+	return ttr, nil
+}
+
+// checkInviteLink returns why the link can't be accepted at now, leaving the
+// uses and open slots to Redeem.
+func checkInviteLink(link *models.InviteLink, ttr *models.TTR, now time.Time) error {
+	switch {
+	case link.RevokedAt != nil:
+		return ErrInviteLinkRevoked
+	case link.Expired(now):
+		return ErrInviteLinkExpired
+	case ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted:
+		return ErrTTRClosed
+	case ttr.TeeDateTime().Before(now):
+		return ErrTeeTimePassed
+	}
+	return nil
+}
+
+func newInviteLinkToken() (string, error) {
+	tokenBytes := make([]byte, 18)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("failed to generate invite link token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
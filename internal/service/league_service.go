@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"go.uber.org/zap"
+)
+
+type LeagueService struct {
+	leagueRepo repository.LeagueRepository
+	ttrRepo    repository.TTRRepository
+	authorizer *Authorizer
+	cache      cache.Cache
+	cacheTTL   time.Duration
+	logger     *zap.Logger
+}
+
+func NewLeagueService(
+	leagueRepo repository.LeagueRepository,
+	ttrRepo repository.TTRRepository,
+	authorizer *Authorizer,
+	cache cache.Cache,
+	cacheTTL time.Duration,
+	logger *zap.Logger,
+) *LeagueService {
+	return &LeagueService{
+		leagueRepo: leagueRepo,
+		ttrRepo:    ttrRepo,
+		authorizer: authorizer,
+		cache:      cache,
+		cacheTTL:   cacheTTL,
+		logger:     logger,
+	}
+}
+
+// CreateLeague creates a league owned by userID. A league inside an
+// organization can only be created by one of its owners or admins.
+func (s *LeagueService) CreateLeague(ctx context.Context, userID uuid.UUID, name string, organizationID *uuid.UUID, startDate, endDate time.Time, scoringScheme string) (*models.League, error) {
+	league := &models.League{
+		Name:           strings.TrimSpace(name),
+		OwnerUserID:    userID,
+		OrganizationID: organizationID,
+		StartDate:      startDate,
+		EndDate:        endDate,
+		ScoringScheme:  scoringScheme,
+	}
+	if league.Name == "" {
+		return nil, ErrLeagueNameRequired
+	}
+	if endDate.Before(startDate) {
+		return nil, ErrInvalidSeason
+	}
+	if scoringScheme != models.LeagueScoringStrokePlay && scoringScheme != models.LeagueScoringPointsPerFinish {
+		return nil, ErrInvalidScoringScheme
+	}
+
+	if organizationID != nil {
+		member, err := s.authorizer.OrganizationMember(ctx, *organizationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil || !member.CanAdminister() {
+			return nil, ErrNotAdminCreateLeague
+		}
+	}
+
+	if err := s.leagueRepo.Create(ctx, league); err != nil {
+		return nil, fmt.Errorf("failed to create league: %w", err)
+	}
+
+	return league, nil
+}
+
+// GetLeague returns the league if userID may see it. Organization leagues are
+// reported as not found to non-members, like organization TTRs.
+func (s *LeagueService) GetLeague(ctx context.Context, leagueID uuid.UUID, userID uuid.UUID) (*models.League, error) {
+	league, err := s.leagueRepo.FindByID(ctx, leagueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find league: %w", err)
+	}
+	if league == nil {
+		return nil, ErrLeagueNotFound
+	}
+
+	if league.OrganizationID != nil {
+		member, err := s.authorizer.OrganizationMember(ctx, *league.OrganizationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, ErrLeagueNotFound
+		}
+	}
+
+	return league, nil
+}
+
+// AttachTTR adds a completed TTR played during the league's season to the
+// league. Only the league owner can attach TTRs, and a TTR can count towards
+// one league at most.
+func (s *LeagueService) AttachTTR(ctx context.Context, leagueID uuid.UUID, ttrID uuid.UUID, userID uuid.UUID) error {
+	league, err := s.GetLeague(ctx, leagueID, userID)
+	if err != nil {
+		return err
+	}
+	if league.OwnerUserID != userID {
+		return ErrNotLeagueOwner
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return err
+	}
+	canList, err := s.authorizer.Can(ctx, userID, ActionTTRList, ttr)
+	if err != nil {
+		return err
+	}
+	if !canList {
+		return ErrTTRNotFound
+	}
+
+	if ttr.Status != models.TTRStatusCompleted {
+		return ErrAttachIncompleteTTR
+	}
+	if ttr.TeeDate.Before(league.StartDate) || ttr.TeeDate.After(league.EndDate) {
+		return ErrTTROutsideSeason
+	}
+
+	attached, err := s.leagueRepo.AttachTTR(ctx, league.ID, ttr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to attach TTR: %w", err)
+	}
+	if !attached {
+		return ErrTTRInLeague
+	}
+
+	s.invalidateStandings(ctx, league.ID)
+
+	return nil
+}
+
+// RecordScore sets a player's gross score for a completed TTR. The TTR's
+// captain, co-captains and organization admins can record scores, and the
+// standings of the TTR's league are recomputed on the next read.
+func (s *LeagueService) RecordScore(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, playerUserID uuid.UUID, score int) error {
+	if score <= 0 {
+		return ErrInvalidScore
+	}
+
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return err
+	}
+
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
+	if err != nil {
+		return err
+	}
+	if !canManage {
+		return ErrNotManagerRecordScore
+	}
+
+	if ttr.Status != models.TTRStatusCompleted {
+		return ErrScoreIncompleteTTR
+	}
+
+	var player *models.TTRPlayer
+	for i := range ttr.Players {
+		if ttr.Players[i].UserID == playerUserID {
+			player = &ttr.Players[i]
+			break
+		}
+	}
+	if player == nil {
+		return ErrPlayerNotFound
+	}
+
+	player.Score = &score
+	if err := s.ttrRepo.UpdatePlayer(ctx, player); err != nil {
+		return fmt.Errorf("failed to record score: %w", err)
+	}
+
+	if ttr.LeagueID != nil {
+		s.invalidateStandings(ctx, *ttr.LeagueID)
+	}
+
+	return nil
+}
+
+// GetStandings returns the league's standings, served from the cache when a
+// fresh copy is available.
+func (s *LeagueService) GetStandings(ctx context.Context, leagueID uuid.UUID, userID uuid.UUID) ([]*models.LeagueStanding, error) {
+	league, err := s.GetLeague(ctx, leagueID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := standingsCacheKey(league.ID)
+	if data, err := s.cache.Get(ctx, key); err == nil {
+		var standings []*models.LeagueStanding
+		if err := json.Unmarshal(data, &standings); err == nil {
+			return standings, nil
+		}
+		s.logger.Warn("Discarding unreadable cached standings", zap.String("league_id", league.ID.String()))
+	} else if !errors.Is(err, cache.ErrMiss) {
+		s.logger.Warn("Failed to read cached standings", zap.Error(err))
+	}
+
+	standings, err := s.leagueRepo.ComputeStandings(ctx, league)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute standings: %w", err)
+	}
+	if standings == nil {
+		standings = []*models.LeagueStanding{}
+	}
+
+	if data, err := json.Marshal(standings); err == nil {
+		if err := s.cache.Set(ctx, key, data, s.cacheTTL); err != nil {
+			s.logger.Warn("Failed to cache standings", zap.Error(err))
+		}
+	}
+
+	return standings, nil
+}
+
+// invalidateStandings drops the league's cached standings. A failure only
+// delays fresh standings until the cache entry expires, so it is logged.
+func (s *LeagueService) invalidateStandings(ctx context.Context, leagueID uuid.UUID) {
+	if err := s.cache.Delete(ctx, standingsCacheKey(leagueID)); err != nil {
+		s.logger.Warn("Failed to invalidate cached standings",
+			zap.String("league_id", leagueID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func standingsCacheKey(leagueID uuid.UUID) string {
+	return "league:standings:" + leagueID.String()
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"go.uber.org/zap"
+)
+
+const maintenanceCacheKey = "maintenance"
+
+// maintenanceStateTTL bounds how long maintenance mode stays on in the cache,
+// which needs an expiry; nobody migrates for a month.
+const maintenanceStateTTL = 30 * 24 * time.Hour
+
+// maintenanceRefreshInterval is how stale an instance's view of maintenance
+// mode may get, so requests don't each read it from Redis.
+const maintenanceRefreshInterval = time.Second
+
+// MaintenanceState is whether the API is refusing traffic for maintenance.
+// EndsAt is shown to clients and is only an estimate.
+type MaintenanceState struct {
+	Enabled bool       `json:"enabled"`
+	EndsAt  *time.Time `json:"ends_at,omitempty"`
+}
+
+// MaintenanceService holds the maintenance mode switch. The state lives in
+// the cache, so with Redis every instance sees a toggle within
+// maintenanceRefreshInterval.
+type MaintenanceService struct {
+	cache  cache.Cache
+	logger *zap.Logger
+
+	mu        sync.Mutex
+	state     MaintenanceState
+	checkedAt time.Time
+}
+
+func NewMaintenanceService(cache cache.Cache, logger *zap.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// State returns the current maintenance state. When the cache can't be read
+// the last known state is kept rather than taking the API down or up.
+func (s *MaintenanceService) State(ctx context.Context) MaintenanceState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkedAt.IsZero() && time.Since(s.checkedAt) < maintenanceRefreshInterval {
+		return s.state
+	}
+
+	data, err := s.cache.Get(ctx, maintenanceCacheKey)
+	switch {
+	case errors.Is(err, cache.ErrMiss):
+		s.state = MaintenanceState{}
+	case err != nil:
+		s.logger.Warn("Failed to read maintenance state", zap.Error(err))
+	default:
+		var state MaintenanceState
+		if err := json.Unmarshal(data, &state); err != nil {
+			s.logger.Warn("Discarding unreadable maintenance state", zap.Error(err))
+			break
+		}
+		s.state = state
+	}
+	s.checkedAt = time.Now()
+	return s.state
+}
+
+// Maintenance reports whether maintenance mode is on and when it is expected
+// to end, for middleware.Maintenance.
+func (s *MaintenanceService) Maintenance(ctx context.Context) (bool, *time.Time) {
+	state := s.State(ctx)
+	return state.Enabled, state.EndsAt
+}
+
+// SetState turns maintenance mode on or off for every instance.
+func (s *MaintenanceService) SetState(ctx context.Context, state MaintenanceState) (MaintenanceState, error) {
+	if !state.Enabled {
+		state = MaintenanceState{}
+		if err := s.cache.Delete(ctx, maintenanceCacheKey); err != nil {
+			return MaintenanceState{}, fmt.Errorf("failed to end maintenance: %w", err)
+		}
+	} else {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return MaintenanceState{}, fmt.Errorf("failed to encode maintenance state: %w", err)
+		}
+		if err := s.cache.Set(ctx, maintenanceCacheKey, data, maintenanceStateTTL); err != nil {
+			return MaintenanceState{}, fmt.Errorf("failed to start maintenance: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.state = state
+	s.checkedAt = time.Now()
+	s.mu.Unlock()
+
+	s.logger.Warn("Maintenance mode changed", zap.Bool("enabled", state.Enabled))
+	return state, nil
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type MessageService struct {
+	messageRepo repository.MessageRepository
+	authorizer  *Authorizer
+	storage     storage.Storage
+	cfg         config.MessagingConfig
+	changeFeed  *ChangeFeedService
+	logger      *zap.Logger
+}
+
+// NewMessageService creates the chat service. New messages are recorded in
+// changeFeed, which may be nil.
+func NewMessageService(
+	messageRepo repository.MessageRepository,
+	authorizer *Authorizer,
+	storage storage.Storage,
+	cfg config.MessagingConfig,
+	changeFeed *ChangeFeedService,
+	logger *zap.Logger,
+) *MessageService {
+	return &MessageService{
+		messageRepo: messageRepo,
+		authorizer:  authorizer,
+		storage:     storage,
+		cfg:         cfg,
+		changeFeed:  changeFeed,
+		logger:      logger,
+	}
+}
+
+func (s *MessageService) PostMessage(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, body string) (*models.Message, error) {
+	body = sanitize.Text(body)
+	if body == "" {
+		return nil, ErrMessageEmpty
+	}
+
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return nil, err
+	}
+
+	message := &models.Message{
+		TTRID:  ttrID,
+		UserID: userID,
+		Body:   body,
+	}
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	s.recordPosted(ctx, message)
+
+	created, err := s.messageRepo.FindByID(ctx, message.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve created message: %w", err)
+	}
+
+	return created, nil
+}
+
+func (s *MessageService) GetMessages(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, limit int, offset int) ([]*models.Message, error) {
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.messageRepo.FindByTTRID(ctx, ttrID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	messageIDs := make([]uuid.UUID, len(messages))
+	for i, message := range messages {
+		messageIDs[i] = message.ID
+	}
+	reactions, err := s.messageRepo.SummarizeReactions(ctx, messageIDs, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reactions: %w", err)
+	}
+	for _, message := range messages {
+		message.Reactions = reactions[message.ID]
+
+		if message.AttachmentKey == nil || message.Deleted {
+			continue
+		}
+		url, err := s.storage.PresignGetURL(ctx, *message.AttachmentKey, s.cfg.AttachmentURLTTL)
+		if err != nil {
+			s.logger.Error("Failed to presign attachment URL",
+				zap.String("message_id", message.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		message.AttachmentURL = url
+	}
+
+	return messages, nil
+}
+
+// AttachmentMaxSize returns the largest attachment upload allowed, in bytes.
+func (s *MessageService) AttachmentMaxSize() int64 {
+	return s.cfg.AttachmentMaxSize
+}
+
+// PostAttachment uploads a file and posts it to the TTR as a message with an
+// optional caption. Uploads are limited by type, size and the per-TTR and
+// per-user attachment quotas.
+func (s *MessageService) PostAttachment(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, caption string, file io.Reader, filename string, contentType string, size int64) (*models.Message, error) {
+	if !models.AttachmentContentTypes[contentType] {
+		return nil, ErrUnsupportedAttachmentType
+	}
+	if size > s.cfg.AttachmentMaxSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return nil, err
+	}
+
+	ttrUsage, err := s.messageRepo.SumAttachmentSizeByTTRID(ctx, ttrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check TTR attachment usage: %w", err)
+	}
+	if ttrUsage+size > s.cfg.TTRAttachmentQuota {
+		return nil, ErrTTRAttachmentQuota
+	}
+
+	userUsage, err := s.messageRepo.SumAttachmentSizeByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user attachment usage: %w", err)
+	}
+	if userUsage+size > s.cfg.UserAttachmentQuota {
+		return nil, ErrUserAttachmentQuota
+	}
+
+	key := fmt.Sprintf("ttr-attachments/%s/%s%s", ttrID, uuid.New(), strings.ToLower(filepath.Ext(filename)))
+	if err := s.storage.PutObject(ctx, key, file, contentType); err != nil {
+		return nil, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	name := filepath.Base(filename)
+	message := &models.Message{
+		TTRID:                 ttrID,
+		UserID:                userID,
+		Body:                  sanitize.Text(caption),
+		AttachmentKey:         &key,
+		AttachmentName:        &name,
+		AttachmentContentType: &contentType,
+		AttachmentSize:        size,
+	}
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		if delErr := s.storage.DeleteObject(ctx, key); delErr != nil {
+			s.logger.Error("Failed to remove orphaned attachment", zap.String("key", key), zap.Error(delErr))
+		}
+		return nil, fmt.Errorf("failed to create message: %w", err)
+	}
+
+	s.recordPosted(ctx, message)
+
+	created, err := s.messageRepo.FindByID(ctx, message.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve created message: %w", err)
+	}
+
+	url, err := s.storage.PresignGetURL(ctx, key, s.cfg.AttachmentURLTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign attachment URL: %w", err)
+	}
+	created.AttachmentURL = url
+
+	return created, nil
+}
+
+// PurgeDeletedAttachments removes the stored objects of deleted messages'
+// attachments. Message deletion leaves the object in place for this job so
+// that a storage outage can't fail the delete.
+func (s *MessageService) PurgeDeletedAttachments(ctx context.Context) error {
+	messages, err := s.messageRepo.FindDeletedWithAttachments(ctx, 100)
+	if err != nil {
+		return fmt.Errorf("failed to find deleted attachments: %w", err)
+	}
+
+	for _, message := range messages {
+		if err := s.storage.DeleteObject(ctx, *message.AttachmentKey); err != nil {
+			return fmt.Errorf("failed to delete attachment for message %s: %w", message.ID, err)
+		}
+		if err := s.messageRepo.ClearAttachment(ctx, message.ID); err != nil {
+			return fmt.Errorf("failed to clear attachment for message %s: %w", message.ID, err)
+		}
+	}
+
+	if len(messages) > 0 {
+		s.logger.Info("Purged deleted message attachments", zap.Int("count", len(messages)))
+	}
+
+	return nil
+}
+
+// EditMessage replaces the body of a message. Only the author can edit, and
+// only within the configured edit window after posting.
+func (s *MessageService) EditMessage(ctx context.Context, ttrID uuid.UUID, messageID uuid.UUID, userID uuid.UUID, body string) (*models.Message, error) {
+	body = sanitize.Text(body)
+	if body == "" {
+		return nil, ErrMessageEmpty
+	}
+
+	message, err := s.findMessage(ctx, ttrID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.Deleted {
+		return nil, ErrMessageDeleted
+	}
+	if message.UserID != userID {
+		return nil, ErrNotAuthorEdit
+	}
+
+	now := time.Now()
+	if now.Sub(message.CreatedAt) > s.cfg.EditWindow {
+		return nil, ErrEditWindowExpired
+	}
+
+	message.Body = body
+	message.EditedAt = &now
+	if err := s.messageRepo.Update(ctx, message); err != nil {
+		return nil, fmt.Errorf("failed to update message: %w", err)
+	}
+
+	return message, nil
+}
+
+// DeleteMessage soft-deletes a message by clearing its body and flagging it,
+// so it keeps its place in the thread. The author can delete their own
+// messages; the captain, co-captains and organization admins can delete
+// anyone's. Any attachment
+// is removed from storage later by PurgeDeletedAttachments.
+func (s *MessageService) DeleteMessage(ctx context.Context, ttrID uuid.UUID, messageID uuid.UUID, userID uuid.UUID) error {
+	message, err := s.findMessage(ctx, ttrID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if message.UserID != userID {
+		ttr, err := s.authorizer.TTR(ctx, ttrID)
+		if err != nil {
+			return err
+		}
+		canModerate, err := s.authorizer.Can(ctx, userID, ActionTTRModerateChat, ttr)
+		if err != nil {
+			return err
+		}
+		if !canModerate {
+			return ErrNotAuthorDelete
+		}
+	}
+
+	if message.Deleted {
+		return nil
+	}
+
+	message.Body = ""
+	message.Deleted = true
+	if err := s.messageRepo.Update(ctx, message); err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+
+	s.logger.Info("Message deleted",
+		zap.String("message_id", messageID.String()),
+		zap.String("ttr_id", ttrID.String()),
+		zap.String("deleted_by", userID.String()),
+	)
+
+	return nil
+}
+
+// AddReaction adds an emoji reaction from the user to a message. Reacting twice
+// with the same emoji is a no-op. Reactions don't send notifications.
+func (s *MessageService) AddReaction(ctx context.Context, ttrID uuid.UUID, messageID uuid.UUID, userID uuid.UUID, emoji string) error {
+	if !models.ReactionEmojis[emoji] {
+		return ErrInvalidEmoji
+	}
+
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return err
+	}
+
+	message, err := s.findMessage(ctx, ttrID, messageID)
+	if err != nil {
+		return err
+	}
+	if message.Deleted {
+		return ErrMessageDeleted
+	}
+
+	reaction := &models.MessageReaction{
+		MessageID: messageID,
+		UserID:    userID,
+		Emoji:     emoji,
+	}
+	if err := s.messageRepo.AddReaction(ctx, reaction); err != nil {
+		return fmt.Errorf("failed to add reaction: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveReaction removes the user's emoji reaction from a message, if any.
+func (s *MessageService) RemoveReaction(ctx context.Context, ttrID uuid.UUID, messageID uuid.UUID, userID uuid.UUID, emoji string) error {
+	if !models.ReactionEmojis[emoji] {
+		return ErrInvalidEmoji
+	}
+
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.findMessage(ctx, ttrID, messageID); err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
+		return fmt.Errorf("failed to remove reaction: %w", err)
+	}
+
+	return nil
+}
+
+// MarkRead marks every message in the TTR up to and including messageID as
+// read for the user.
+func (s *MessageService) MarkRead(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, messageID uuid.UUID) error {
+	if err := s.requireMember(ctx, ttrID, userID); err != nil {
+		return err
+	}
+
+	message, err := s.findMessage(ctx, ttrID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.MarkRead(ctx, ttrID, userID, message.CreatedAt); err != nil {
+		return fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+
+	return nil
+}
+
+// GetUnreadCounts returns the number of unread messages per TTR the user plays
+// in. TTRs with no unread messages are omitted.
+func (s *MessageService) GetUnreadCounts(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts, err := s.messageRepo.CountUnreadByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+	return counts, nil
+}
+
+// recordPosted adds a new message to its TTR's change feed.
+func (s *MessageService) recordPosted(ctx context.Context, message *models.Message) {
+	s.changeFeed.Record(ctx, message.TTRID, models.TTREventMessagePosted, &message.UserID, map[string]string{"message_id": message.ID.String()})
+}
+
+func (s *MessageService) findMessage(ctx context.Context, ttrID uuid.UUID, messageID uuid.UUID) (*models.Message, error) {
+	message, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message: %w", err)
+	}
+	if message == nil || message.TTRID != ttrID {
+		return nil, ErrMessageNotFound
+	}
+	return message, nil
+}
+
+// requireMember checks that the TTR exists and that the user is on its
+// roster; only the captain, co-captains and players take part in the chat.
+func (s *MessageService) requireMember(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) error {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return err
+	}
+	canChat, err := s.authorizer.Can(ctx, userID, ActionTTRChat, ttr)
+	if err != nil {
+		return err
+	}
+	if !canChat {
+		return ErrNotTTRPlayer
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+// messageVariables are what invitation messages can use, each on its own as
+// {{course}}. They are the only functions the templates get, and anything
+// else, such as a pipeline, a field or an if, is rejected, so a template can
+// only fill in the TTR's details.
+var messageVariables = []string{"course", "date", "time", "captain"}
+
+// templatePosition finds the line, and the column if there is one, in the
+// errors text/template reports, such as "template: message:2:7: ...".
+var templatePosition = regexp.MustCompile(`^(?:template: )?message:(\d+)(?::(\d+))?:\s*(.*)$`)
+
+// parseMessageTemplate parses an invitation message, returning a
+// *MessageTemplateError when it is malformed or uses anything but
+// messageVariables.
+func parseMessageTemplate(body string) (*template.Template, error) {
+	funcs := template.FuncMap{}
+	for _, name := range messageVariables {
+		funcs[name] = func() string { return "" }
+	}
+	tmpl, err := template.New("message").Funcs(funcs).Parse(body)
+	if err != nil {
+		return nil, newMessageTemplateError(err.Error())
+	}
+	for _, node := range tmpl.Tree.Root.Nodes {
+		if _, ok := node.(*parse.TextNode); ok || isMessageVariable(node) {
+			continue
+		}
+		location, _ := tmpl.Tree.ErrorContext(node)
+		return nil, newMessageTemplateError(location + ": only " + variableList() + " are allowed")
+	}
+	return tmpl, nil
+}
+
+// isMessageVariable reports whether node is a bare {{name}} of one of the
+// messageVariables.
+func isMessageVariable(node parse.Node) bool {
+	action, ok := node.(*parse.ActionNode)
+	if !ok || len(action.Pipe.Decl) > 0 || len(action.Pipe.Cmds) != 1 || len(action.Pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	ident, ok := action.Pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return false
+	}
+	for _, name := range messageVariables {
+		if ident.Ident == name {
+			return true
+		}
+	}
+	return false
+}
+
+func variableList() string {
+	names := make([]string, 0, len(messageVariables))
+	for _, name := range messageVariables {
+		names = append(names, "{{"+name+"}}")
+	}
+	return strings.Join(names, ", ")
+}
+
+func newMessageTemplateError(message string) *MessageTemplateError {
+	match := templatePosition.FindStringSubmatch(message)
+	if match == nil {
+		return &MessageTemplateError{Line: 1, Reason: message}
+	}
+	line, _ := strconv.Atoi(match[1])
+	column, _ := strconv.Atoi(match[2])
+	return &MessageTemplateError{Line: line, Column: column, Reason: match[3]}
+}
+
+// renderInvitationMessage fills the TTR's course, tee date and time and
+// captain's name into an invitation message.
+func renderInvitationMessage(body string, ttr *models.TTR) (string, error) {
+	tmpl, err := parseMessageTemplate(body)
+	if err != nil {
+		return "", err
+	}
+	captain := ""
+	if ttr.CaptainUser != nil {
+		captain = strings.TrimSpace(ttr.CaptainUser.FirstName + " " + ttr.CaptainUser.LastName)
+	}
+	values := map[string]string{
+		"course":  ttr.CourseName,
+		"date":    ttr.TeeDate.Format("2006-01-02"),
+		"time":    ttr.TeeTime.Format("15:04"),
+		"captain": captain,
+	}
+	funcs := template.FuncMap{}
+	for name, value := range values {
+		value := value
+		funcs[name] = func() string { return value }
+	}
+
+	var message strings.Builder
+	if err := tmpl.Funcs(funcs).Execute(&message, nil); err != nil {
+		return "", newMessageTemplateError(err.Error())
+	}
+	return message.String(), nil
+}
@@ -1,26 +1,213 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/tracing"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
+// digestTemplates maps the templates whose events are digested to the
+// template of the digest, which gets an extra {count} param.
+var digestTemplates = map[string]string{
+	"player_joined": "player_joined_digest",
+	"player_left":   "player_left_digest",
+}
+
 type NotificationService struct {
-	logger *zap.Logger
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	outbox           *OutboxService
+	digestWindow     time.Duration
+	logger           *zap.Logger
+}
+
+// NewNotificationService creates a NotificationService. Notifications are
+// stored with notificationRepo; when it is nil they are only logged. userRepo
+// is used to find each recipient's preferred language; when it is nil every
+// notification is rendered in i18n.DefaultLanguage. With an outbox,
+// notifications are written to it and created when it delivers them; without
+// one they are created right away. Digestible events within digestWindow of
+// each other collapse into one notification; zero turns digests off.
+func NewNotificationService(notificationRepo repository.NotificationRepository, userRepo repository.UserRepository, outbox *OutboxService, digestWindow time.Duration, logger *zap.Logger) *NotificationService {
+	s := &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		outbox:           outbox,
+		digestWindow:     digestWindow,
+		logger:           logger,
+	}
+	if outbox != nil {
+		outbox.Handle(models.OutboxKindNotification, s.sendOutboxMessage)
+	}
+	return s
+}
+
+// notificationMessage is the outbox payload of a notification.
+type notificationMessage struct {
+	UserID     uuid.UUID         `json:"user_id"`
+	Type       string            `json:"type"`
+	Template   string            `json:"template"`
+	Params     map[string]string `json:"params,omitempty"`
+	TargetType *string           `json:"target_type,omitempty"`
+	TargetID   *uuid.UUID        `json:"target_id,omitempty"`
+}
+
+// Notify renders the "notification.<template>.title" and ".message" catalog
+// entries in the recipient's preferred language and creates the notification.
+// Events of a digested template are folded into a recent notification of the
+// same type about the same target instead, see digest. With an outbox, Notify
+// only writes the notification to it, in the transaction ctx carries if any,
+// and the rest happens on delivery.
+func (s *NotificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType, template string, params map[string]string, targetType *string, targetID *uuid.UUID) error {
+	if s.outbox != nil {
+		return s.outbox.Enqueue(ctx, models.OutboxKindNotification, notificationMessage{
+			UserID:     userID,
+			Type:       notificationType,
+			Template:   template,
+			Params:     params,
+			TargetType: targetType,
+			TargetID:   targetID,
+		})
+	}
+	return s.notify(ctx, userID, notificationType, template, params, targetType, targetID)
+}
+
+func (s *NotificationService) sendOutboxMessage(ctx context.Context, payload []byte) error {
+	var message notificationMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return fmt.Errorf("failed to decode notification message: %w", err)
+	}
+	return s.notify(ctx, message.UserID, message.Type, message.Template, message.Params, message.TargetType, message.TargetID)
 }
 
-func NewNotificationService(logger *zap.Logger) *NotificationService {
-	return &NotificationService{
-		logger: logger,
+func (s *NotificationService) notify(ctx context.Context, userID uuid.UUID, notificationType, template string, params map[string]string, targetType *string, targetID *uuid.UUID) error {
+	lang := s.recipientLanguage(ctx, userID)
+	if digestTemplate, ok := digestTemplates[template]; ok {
+		digested, err := s.digest(ctx, lang, userID, notificationType, digestTemplate, params, targetType, targetID)
+		if err != nil || digested {
+			return err
+		}
 	}
+	title := i18n.Translate(lang, "notification."+template+".title", params)
+	message := i18n.Translate(lang, "notification."+template+".message", params)
+	return s.CreateNotification(ctx, userID, notificationType, title, message, targetType, targetID)
 }
 
-func (s *NotificationService) CreateNotification(userID uuid.UUID, notificationType string, title string, message string, targetType *string, targetID *uuid.UUID) error {
-	s.logger.Info("Notification stub called",
+// digest folds an event into userID's notification of the same type about the
+// same target when that one was created within the digest window, rewriting
+// it from template and marking it unread again. It reports whether it did.
+// Only the first event of a window creates a notification, so whatever is
+// sent out for new notifications goes out at most once per window.
+func (s *NotificationService) digest(ctx context.Context, lang string, userID uuid.UUID, notificationType, template string, params map[string]string, targetType *string, targetID *uuid.UUID) (bool, error) {
+	if s.notificationRepo == nil || s.digestWindow <= 0 || targetType == nil || targetID == nil {
+		return false, nil
+	}
+	recent, err := s.notificationRepo.FindRecentByTypeAndTarget(ctx, userID, notificationType, *targetType, *targetID, time.Now().Add(-s.digestWindow))
+	if err != nil {
+		return false, err
+	}
+	if recent == nil {
+		return false, nil
+	}
+
+	count := recent.EventCount + 1
+	digestParams := map[string]string{"count": strconv.Itoa(count)}
+	for name, value := range params {
+		digestParams[name] = value
+	}
+	title := i18n.Translate(lang, "notification."+template+".title", digestParams)
+	message := i18n.Translate(lang, "notification."+template+".message", digestParams)
+	if err := s.notificationRepo.UpdateDigest(ctx, recent.ID, title, message, count); err != nil {
+		return false, err
+	}
+
+	s.logger.Debug("Notification digested",
 		zap.String("user_id", userID.String()),
 		zap.String("type", notificationType),
-		zap.String("title", title),
-		zap.String("message", message),
+		zap.String("notification_id", recent.ID.String()),
+		zap.Int("event_count", count),
+	)
+	return true, nil
+}
+
+// ResolveInvitation marks the invitee's "new invitation" notification as read
+// once the invitation can no longer be answered. Failures are only logged.
+func (s *NotificationService) ResolveInvitation(ctx context.Context, invitation *models.Invitation) {
+	if err := s.MarkTargetRead(ctx, invitation.InviteeUserID, models.NotificationTypeInvitation, "invitation", invitation.ID); err != nil {
+		s.logger.Error("Failed to resolve invitation notification", zap.String("invitation_id", invitation.ID.String()), zap.Error(err))
+	}
+}
+
+func (s *NotificationService) recipientLanguage(ctx context.Context, userID uuid.UUID) string {
+	if s.userRepo == nil {
+		return i18n.DefaultLanguage
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load notification recipient", zap.String("user_id", userID.String()), zap.Error(err))
+		return i18n.DefaultLanguage
+	}
+	if user == nil || user.PreferredLanguage == nil || !i18n.Supported(*user.PreferredLanguage) {
+		return i18n.DefaultLanguage
+	}
+	return *user.PreferredLanguage
+}
+
+func (s *NotificationService) CreateNotification(ctx context.Context, userID uuid.UUID, notificationType string, title string, message string, targetType *string, targetID *uuid.UUID) error {
+	ctx, span := tracing.Tracer().Start(ctx, "NotificationService.CreateNotification", trace.WithAttributes(
+		attribute.String("notification.type", notificationType),
+	))
+	defer span.End()
+
+	s.logger.Info("Notification created",
+		append([]zap.Field{
+			zap.String("user_id", userID.String()),
+			zap.String("type", notificationType),
+			zap.String("title", title),
+			zap.String("message", message),
+		}, tracing.LogFields(ctx)...)...,
 	)
+	if s.notificationRepo == nil {
+		return nil
+	}
+	return s.notificationRepo.Create(ctx, &models.Notification{
+		UserID:     userID,
+		Type:       notificationType,
+		Title:      title,
+		Message:    message,
+		TargetType: targetType,
+		TargetID:   targetID,
+	})
+}
+
+// MarkTargetRead marks userID's unread notifications of the given type about
+// a target as read, for when what they announced no longer needs attention.
+func (s *NotificationService) MarkTargetRead(ctx context.Context, userID uuid.UUID, notificationType string, targetType string, targetID uuid.UUID) error {
+	if s.notificationRepo == nil {
+		return nil
+	}
+	notifications, err := s.notificationRepo.FindByTarget(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	for _, notification := range notifications {
+		if notification.UserID != userID || notification.Type != notificationType || notification.IsRead {
+			continue
+		}
+		if err := s.notificationRepo.MarkAsRead(ctx, notification.ID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
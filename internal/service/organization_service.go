@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+	"go.uber.org/zap"
+)
+
+type OrganizationService struct {
+	orgRepo             repository.OrganizationRepository
+	userRepo            repository.UserRepository
+	authorizer          *Authorizer
+	transactor          repository.Transactor
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+func NewOrganizationService(
+	orgRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	authorizer *Authorizer,
+	transactor repository.Transactor,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:             orgRepo,
+		userRepo:            userRepo,
+		authorizer:          authorizer,
+		transactor:          transactor,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// CreateOrganization creates an organization with userID as its owner.
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID uuid.UUID, name string, description *string) (*OrganizationDetail, error) {
+	org := &models.Organization{
+		Name:            strings.TrimSpace(name),
+		Description:     description,
+		CreatedByUserID: userID,
+	}
+	if org.Name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.orgRepo.Create(ctx, org); err != nil {
+			return err
+		}
+		return s.orgRepo.AddMember(ctx, &models.OrganizationMember{
+			OrganizationID: org.ID,
+			UserID:         userID,
+			Role:           models.OrganizationRoleOwner,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return NewOrganizationDetail(org), nil
+}
+
+// GetOrganization returns the organization if userID is a member. Anyone else
+// gets "organization not found" so private clubs aren't discoverable by ID.
+func (s *OrganizationService) GetOrganization(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*OrganizationDetail, error) {
+	org, _, err := s.findForMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return NewOrganizationDetail(org), nil
+}
+
+func (s *OrganizationService) GetUserOrganizations(ctx context.Context, userID uuid.UUID) ([]*OrganizationDetail, error) {
+	orgs, err := s.orgRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user organizations: %w", err)
+	}
+
+	details := make([]*OrganizationDetail, 0, len(orgs))
+	for _, org := range orgs {
+		details = append(details, NewOrganizationDetail(org))
+	}
+	return details, nil
+}
+
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, name *string, description *string) (*OrganizationDetail, error) {
+	org, member, err := s.findForMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.CanAdminister() {
+		return nil, ErrNotAdminUpdateOrganization
+	}
+
+	if name != nil {
+		trimmed := strings.TrimSpace(*name)
+		if trimmed == "" {
+			return nil, ErrOrganizationNameRequired
+		}
+		org.Name = trimmed
+	}
+	if description != nil {
+		org.Description = description
+	}
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return NewOrganizationDetail(org), nil
+}
+
+// DeleteOrganization soft-deletes the organization. Its TTRs stay private to
+// the former members.
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	_, member, err := s.findForMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != models.OrganizationRoleOwner {
+		return ErrNotOwnerDelete
+	}
+
+	if err := s.orgRepo.Delete(ctx, orgID); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+	return nil
+}
+
+func (s *OrganizationService) GetMembers(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) ([]OrganizationMemberDetail, error) {
+	if _, _, err := s.findForMember(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.orgRepo.GetMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members: %w", err)
+	}
+
+	details := make([]OrganizationMemberDetail, 0, len(members))
+	for _, member := range members {
+		details = append(details, NewOrganizationMemberDetail(member))
+	}
+	return details, nil
+}
+
+// InviteMember invites an email address to the organization with the given
+// role. Owners and admins can invite members; only the owner can invite
+// admins. If the address belongs to an existing user they are notified.
+func (s *OrganizationService) InviteMember(ctx context.Context, orgID uuid.UUID, inviterUserID uuid.UUID, email string, role string) (*OrganizationInvitationDetail, error) {
+	if role == "" {
+		role = models.OrganizationRoleMember
+	}
+	if role != models.OrganizationRoleMember && role != models.OrganizationRoleAdmin {
+		return nil, ErrInvalidRole
+	}
+
+	org, inviter, err := s.findForMember(ctx, orgID, inviterUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !inviter.CanAdminister() {
+		return nil, ErrNotAdminInvite
+	}
+	if role == models.OrganizationRoleAdmin && inviter.Role != models.OrganizationRoleOwner {
+		return nil, ErrNotOwnerGrantAdmin
+	}
+
+	email = emailnorm.Normalize(email)
+
+	invitee, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitee: %w", err)
+	}
+	if invitee != nil {
+		existing, err := s.orgRepo.FindMember(ctx, orgID, invitee.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check membership: %w", err)
+		}
+		if existing != nil {
+			return nil, ErrAlreadyMember
+		}
+	}
+
+	pending, err := s.orgRepo.FindPendingInvitation(ctx, orgID, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing invitation: %w", err)
+	}
+	if pending != nil {
+		return nil, ErrOrganizationInvitationPending
+	}
+
+	invitation := &models.OrganizationInvitation{
+		OrganizationID:  orgID,
+		Email:           email,
+		Role:            role,
+		InvitedByUserID: inviterUserID,
+		Status:          models.OrganizationInvitationStatusPending,
+	}
+	if err := s.orgRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	invitation.Organization = org
+
+	if invitee != nil {
+		targetType := "organization_invitation"
+		params := map[string]string{"organization": org.Name}
+		if err := s.notificationService.Notify(ctx, invitee.ID, "organization_invitation", "organization_invitation", params, &targetType, &invitation.ID); err != nil {
+			s.logger.Error("Failed to create notification", zap.Error(err))
+		}
+	}
+
+	return NewOrganizationInvitationDetail(invitation), nil
+}
+
+// GetUserInvitations returns the pending organization invitations addressed
+// to the user's email.
+func (s *OrganizationService) GetUserInvitations(ctx context.Context, userID uuid.UUID) ([]*OrganizationInvitationDetail, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	invitations, err := s.orgRepo.FindPendingInvitationsByEmail(ctx, emailnorm.Normalize(user.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization invitations: %w", err)
+	}
+
+	details := make([]*OrganizationInvitationDetail, 0, len(invitations))
+	for _, invitation := range invitations {
+		details = append(details, NewOrganizationInvitationDetail(invitation))
+	}
+	return details, nil
+}
+
+// RespondToInvitation accepts or declines an invitation addressed to the
+// user's email. Accepting adds them to the organization with the invited role.
+func (s *OrganizationService) RespondToInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID, accept bool) (*OrganizationInvitationDetail, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	invitation, err := s.orgRepo.FindInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+	if invitation == nil || invitation.Organization == nil || invitation.Email != emailnorm.Normalize(user.Email) {
+		return nil, ErrInvitationNotFound
+	}
+	if invitation.Status != models.OrganizationInvitationStatusPending {
+		return nil, ErrInvitationNotPending
+	}
+
+	now := time.Now()
+	invitation.RespondedAt = &now
+	if !accept {
+		invitation.Status = models.OrganizationInvitationStatusDeclined
+		if err := s.orgRepo.UpdateInvitation(ctx, invitation); err != nil {
+			return nil, fmt.Errorf("failed to decline invitation: %w", err)
+		}
+		return NewOrganizationInvitationDetail(invitation), nil
+	}
+
+	invitation.Status = models.OrganizationInvitationStatusAccepted
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.orgRepo.FindMember(ctx, invitation.OrganizationID, userID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			if err := s.orgRepo.AddMember(ctx, &models.OrganizationMember{
+				OrganizationID: invitation.OrganizationID,
+				UserID:         userID,
+				Role:           invitation.Role,
+			}); err != nil {
+				return err
+			}
+		}
+		return s.orgRepo.UpdateInvitation(ctx, invitation)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return NewOrganizationInvitationDetail(invitation), nil
+}
+
+// UpdateMemberRole changes a member's role between admin and member. Only the
+// owner can change roles, and the owner's own role is fixed.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, orgID uuid.UUID, ownerUserID uuid.UUID, memberUserID uuid.UUID, role string) error {
+	if role != models.OrganizationRoleMember && role != models.OrganizationRoleAdmin {
+		return ErrInvalidRole
+	}
+
+	_, owner, err := s.findForMember(ctx, orgID, ownerUserID)
+	if err != nil {
+		return err
+	}
+	if owner.Role != models.OrganizationRoleOwner {
+		return ErrNotOwnerChangeRole
+	}
+
+	member, err := s.orgRepo.FindMember(ctx, orgID, memberUserID)
+	if err != nil {
+		return fmt.Errorf("failed to find member: %w", err)
+	}
+	if member == nil {
+		return ErrMemberNotFound
+	}
+	if member.Role == models.OrganizationRoleOwner {
+		return ErrOwnerRoleLocked
+	}
+
+	member.Role = role
+	if err := s.orgRepo.UpdateMember(ctx, member); err != nil {
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember removes memberUserID from the organization. Members can remove
+// themselves, except the owner; owners and admins can remove members, and only
+// the owner can remove admins.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID, memberUserID uuid.UUID) error {
+	_, actor, err := s.findForMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+
+	member := actor
+	if memberUserID != userID {
+		member, err = s.orgRepo.FindMember(ctx, orgID, memberUserID)
+		if err != nil {
+			return fmt.Errorf("failed to find member: %w", err)
+		}
+		if member == nil {
+			return ErrMemberNotFound
+		}
+	}
+
+	if member.Role == models.OrganizationRoleOwner {
+		return ErrOwnerNotRemovable
+	}
+	if memberUserID != userID {
+		if !actor.CanAdminister() {
+			return ErrNotAdminRemoveMember
+		}
+		if member.Role == models.OrganizationRoleAdmin && actor.Role != models.OrganizationRoleOwner {
+			return ErrNotOwnerRemoveAdmin
+		}
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, orgID, memberUserID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	return nil
+}
+
+// findForMember loads the organization and userID's membership of it.
+// Non-members get "organization not found".
+func (s *OrganizationService) findForMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.Organization, *models.OrganizationMember, error) {
+	org, err := s.orgRepo.FindByID(ctx, orgID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+	if org == nil {
+		return nil, nil, ErrOrganizationNotFound
+	}
+
+	member, err := s.authorizer.OrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if member == nil {
+		return nil, nil, ErrOrganizationNotFound
+	}
+
+	return org, member, nil
+}
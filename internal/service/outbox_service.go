@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// OutboxSender delivers the payload of one outbox message. An error leaves
+// the message to be tried again, so senders must cope with delivering the
+// same message twice.
+type OutboxSender func(ctx context.Context, payload []byte) error
+
+// OutboxService writes side effects of a change, such as notifications, to
+// the outbox in the change's transaction, and Run delivers them once it has
+// committed, retrying until they go through. A message is delivered at least
+// once: one whose dispatcher dies mid-delivery is tried again after the
+// lease.
+type OutboxService struct {
+	outboxRepo repository.OutboxRepository
+	cfg        config.OutboxConfig
+	logger     *zap.Logger
+
+	mu      sync.RWMutex
+	senders map[string]OutboxSender
+}
+
+func NewOutboxService(outboxRepo repository.OutboxRepository, cfg config.OutboxConfig, logger *zap.Logger) *OutboxService {
+	return &OutboxService{
+		outboxRepo: outboxRepo,
+		cfg:        cfg,
+		logger:     logger,
+		senders:    make(map[string]OutboxSender),
+	}
+}
+
+// Handle makes sender deliver the messages of kind.
+func (s *OutboxService) Handle(kind string, sender OutboxSender) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.senders[kind] = sender
+}
+
+// Enqueue writes a message of kind with payload encoded as JSON. Called with
+// a ctx carrying a transaction, the message is only delivered if the
+// transaction commits.
+func (s *OutboxService) Enqueue(ctx context.Context, kind string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s outbox message: %w", kind, err)
+	}
+	return s.outboxRepo.Create(ctx, &models.OutboxMessage{Kind: kind, Payload: string(data)})
+}
+
+// Run dispatches due messages every poll interval until ctx is canceled.
+func (s *OutboxService) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// Keep going while full batches come back, so a backlog
+			// doesn't wait a poll interval per batch.
+			for {
+				dispatched, err := s.Dispatch(ctx)
+				if err != nil {
+					s.logger.Error("Failed to dispatch outbox messages", zap.Error(err))
+				}
+				if err != nil || dispatched < s.cfg.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Dispatch claims a batch of due messages and delivers them one by one. It
+// returns how many it claimed.
+func (s *OutboxService) Dispatch(ctx context.Context) (int, error) {
+	messages, err := s.outboxRepo.Claim(ctx, time.Now(), s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Outcomes are recorded even when shutting down, so nothing delivered
+	// is sent again.
+	recordCtx := context.WithoutCancel(ctx)
+	for _, message := range messages {
+		err := s.send(ctx, message)
+		if err == nil {
+			if err := s.outboxRepo.MarkDone(recordCtx, message.ID); err != nil {
+				s.logger.Error("Failed to mark outbox message done", zap.String("message_id", message.ID.String()), zap.Error(err))
+			}
+			continue
+		}
+		s.fail(recordCtx, message, err)
+	}
+	return len(messages), nil
+}
+
+func (s *OutboxService) send(ctx context.Context, message *models.OutboxMessage) error {
+	s.mu.RLock()
+	sender, ok := s.senders[message.Kind]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no sender for %s messages", message.Kind)
+	}
+	return sender(ctx, []byte(message.Payload))
+}
+
+// fail records a failed attempt and schedules the next one, doubling the
+// wait each time, or gives up on the message after the last attempt.
+func (s *OutboxService) fail(ctx context.Context, message *models.OutboxMessage, cause error) {
+	attempts := message.Attempts + 1
+	dead := attempts >= s.cfg.MaxAttempts
+	// The shift is capped so a large OUTBOX_MAX_ATTEMPTS can't overflow it.
+	nextAttemptAt := time.Now().Add(s.cfg.InitialBackoff << min(attempts-1, 16))
+
+	fields := []zap.Field{
+		zap.String("message_id", message.ID.String()),
+		zap.String("kind", message.Kind),
+		zap.Int("attempts", attempts),
+		zap.Error(cause),
+	}
+	if dead {
+		s.logger.Error("Outbox message failed every attempt", fields...)
+	} else {
+		s.logger.Warn("Outbox message failed", append(fields, zap.Time("next_attempt_at", nextAttemptAt))...)
+	}
+
+	if err := s.outboxRepo.MarkFailed(ctx, message.ID, attempts, cause.Error(), nextAttemptAt, dead); err != nil {
+		s.logger.Error("Failed to record outbox message failure", zap.String("message_id", message.ID.String()), zap.Error(err))
+	}
+}
+
+// PurgeDelivered deletes messages delivered more than the retention ago, in
+// batches. It runs as a periodic job.
+func (s *OutboxService) PurgeDelivered(ctx context.Context) error {
+	cutoff := time.Now().Add(-s.cfg.Retention)
+
+	var total int64
+	for {
+		purged, err := s.outboxRepo.DeleteDoneBefore(ctx, cutoff, defaultPurgeBatchSize)
+		total += purged
+		if err != nil {
+			return fmt.Errorf("failed to purge outbox messages: %w", err)
+		}
+		if purged < defaultPurgeBatchSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("Purged delivered outbox messages", zap.Int64("count", total))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/pairing"
+)
+
+type PairingService struct {
+	authorizer      *Authorizer
+	defaultHandicap float64
+}
+
+// NewPairingService creates a PairingService that counts players without a
+// handicap as defaultHandicap.
+func NewPairingService(authorizer *Authorizer, defaultHandicap float64) *PairingService {
+	return &PairingService{
+		authorizer:      authorizer,
+		defaultHandicap: defaultHandicap,
+	}
+}
+
+// SuggestPairings splits the TTR's confirmed players into balanced groups of
+// at most models.MaxPairingGroupSize. Group i of the result is meant to be
+// pairing group i+1. Only the captain and co-captains can ask.
+func (s *PairingService) SuggestPairings(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID) ([]pairing.Group, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
+	if err != nil {
+		return nil, err
+	}
+	if !canManage {
+		return nil, ErrNotManagerSuggestPairings
+	}
+
+	players := make([]pairing.Player, 0, len(ttr.Players))
+	for _, player := range ttr.Players {
+		if player.Status != models.TTRPlayerStatusConfirmed {
+			continue
+		}
+		var handicap *float64
+		if player.User != nil {
+			handicap = player.User.Handicap
+		}
+		players = append(players, pairing.Player{ID: player.UserID, Handicap: handicap})
+	}
+
+	return pairing.Balance(players, models.MaxPairingGroupSize, s.defaultHandicap), nil
+}
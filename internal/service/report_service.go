@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
+	"go.uber.org/zap"
+)
+
+// reportWindow is how far back reports count towards a user's limit.
+const reportWindow = 24 * time.Hour
+
+type ReportService struct {
+	reportRepo          repository.ReportRepository
+	userRepo            repository.UserRepository
+	messageRepo         repository.MessageRepository
+	ttrRepo             repository.TTRRepository
+	notificationService *NotificationService
+	perDay              int
+	logger              *zap.Logger
+}
+
+// NewReportService creates a ReportService. A user can file perDay reports in
+// any 24 hours. Reporters are notified through notificationService when an
+// admin closes their report; without one they aren't.
+func NewReportService(reportRepo repository.ReportRepository, userRepo repository.UserRepository, messageRepo repository.MessageRepository, ttrRepo repository.TTRRepository, notificationService *NotificationService, perDay int, logger *zap.Logger) *ReportService {
+	return &ReportService{
+		reportRepo:          reportRepo,
+		userRepo:            userRepo,
+		messageRepo:         messageRepo,
+		ttrRepo:             ttrRepo,
+		notificationService: notificationService,
+		perDay:              perDay,
+		logger:              logger,
+	}
+}
+
+// CreateReport files an open report by reporterID against the user, chat
+// message or TTR targetID. Users can't report themselves or their own
+// messages, and can have one open report per target.
+func (s *ReportService) CreateReport(ctx context.Context, reporterID uuid.UUID, targetType string, targetID uuid.UUID, category string, details string) (*models.Report, error) {
+	if err := s.checkTarget(ctx, reporterID, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	filed, err := s.reportRepo.CountByReporterSince(ctx, reporterID, now.Add(-reportWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reports: %w", err)
+	}
+	if filed >= int64(s.perDay) {
+		return nil, ErrReportLimitReached
+	}
+
+	report := &models.Report{
+		ReporterUserID: reporterID,
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Category:       category,
+		Details:        sanitize.Text(details),
+		Status:         models.ReportStatusOpen,
+		CreatedAt:      now,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrReportAlreadyOpen
+		}
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+
+	s.logger.Info("Report filed",
+		zap.String("report_id", report.ID.String()),
+		zap.String("target_type", targetType),
+		zap.String("category", category),
+	)
+	return report, nil
+}
+
+// checkTarget returns ErrReportTargetNotFound unless the target exists, and
+// ErrCannotReportSelf when it is the reporter or one of their messages.
+func (s *ReportService) checkTarget(ctx context.Context, reporterID uuid.UUID, targetType string, targetID uuid.UUID) error {
+	switch targetType {
+	case models.ReportTargetUser:
+		if targetID == reporterID {
+			return ErrCannotReportSelf
+		}
+		user, err := s.userRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to find user: %w", err)
+		}
+		if user == nil {
+			return ErrReportTargetNotFound
+		}
+	case models.ReportTargetMessage:
+		message, err := s.messageRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to find message: %w", err)
+		}
+		if message == nil {
+			return ErrReportTargetNotFound
+		}
+		if message.UserID == reporterID {
+			return ErrCannotReportSelf
+		}
+	case models.ReportTargetTTR:
+		ttr, err := s.ttrRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to find TTR: %w", err)
+		}
+		if ttr == nil {
+			return ErrReportTargetNotFound
+		}
+	default:
+		return ErrReportTargetNotFound
+	}
+	return nil
+}
+
+// ListReports returns reports with the status, or all reports for an empty
+// status, oldest first.
+func (s *ReportService) ListReports(ctx context.Context, status string, limit int, offset int) ([]*models.Report, error) {
+	reports, err := s.reportRepo.FindByStatus(ctx, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	return reports, nil
+}
+
+// CloseReport resolves or dismisses an open report, as status says, with the
+// admin's notes, and lets the reporter know.
+func (s *ReportService) CloseReport(ctx context.Context, adminID uuid.UUID, reportID uuid.UUID, status string, notes string) (*models.Report, error) {
+	report, err := s.reportRepo.FindByID(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find report: %w", err)
+	}
+	if report == nil {
+		return nil, ErrReportNotFound
+	}
+	if !report.IsOpen() {
+		return nil, ErrReportClosed
+	}
+
+	now := time.Now()
+	report.Status = status
+	report.Resolution = sanitize.Text(notes)
+	report.ResolvedByUserID = &adminID
+	report.ResolvedAt = &now
+	closed, err := s.reportRepo.Close(ctx, report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to close report: %w", err)
+	}
+	if !closed {
+		// Another admin closed it since it was read.
+		return nil, ErrReportClosed
+	}
+	report.UpdatedAt = now
+
+	s.logger.Info("Report closed",
+		zap.String("report_id", report.ID.String()),
+		zap.String("status", status),
+		zap.String("admin_id", adminID.String()),
+	)
+	s.notifyReporter(ctx, report)
+	return report, nil
+}
+
+// notifyReporter tells the reporter their report was closed. A failure is
+// only logged: the report is closed either way.
+func (s *ReportService) notifyReporter(ctx context.Context, report *models.Report) {
+	if s.notificationService == nil {
+		return
+	}
+	template := "report_resolved"
+	if report.Status == models.ReportStatusDismissed {
+		template = "report_dismissed"
+	}
+	targetType := "report"
+	if err := s.notificationService.Notify(ctx, report.ReporterUserID, models.NotificationTypeReportResolved, template, nil, &targetType, &report.ID); err != nil {
+		s.logger.Warn("Failed to notify reporter", zap.String("report_id", report.ID.String()), zap.Error(err))
+	}
+}
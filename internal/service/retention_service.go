@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// defaultPurgeBatchSize is used when the configured batch size isn't positive.
+const defaultPurgeBatchSize = 500
+
+// RetentionService permanently deletes rows that have been soft-deleted for
+// longer than the retention window, so personal data doesn't outlive it.
+type RetentionService struct {
+	ttrRepo           repository.TTRRepository
+	userRepo          repository.UserRepository
+	securityEventRepo repository.SecurityEventRepository
+	cfg               config.RetentionConfig
+	logger            *zap.Logger
+}
+
+func NewRetentionService(ttrRepo repository.TTRRepository, userRepo repository.UserRepository, securityEventRepo repository.SecurityEventRepository, cfg config.RetentionConfig, logger *zap.Logger) *RetentionService {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultPurgeBatchSize
+	}
+	return &RetentionService{
+		ttrRepo:           ttrRepo,
+		userRepo:          userRepo,
+		securityEventRepo: securityEventRepo,
+		cfg:               cfg,
+		logger:            logger,
+	}
+}
+
+// PurgeDeleted purges TTRs and then users deleted before the retention
+// window, in batches. TTRs go first because a deleted user who captained a
+// deleted TTR can only be purged once the TTR is gone. Security events older
+// than their own window go too. It runs as a periodic job.
+func (s *RetentionService) PurgeDeleted(ctx context.Context) error {
+	cutoff := time.Now().Add(-s.cfg.PurgeAfter)
+
+	ttrs, err := s.purge(ctx, cutoff, s.ttrRepo.PurgeDeletedBefore)
+	if ttrs > 0 {
+		s.logger.Info("Purged deleted TTRs", zap.Int64("count", ttrs))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted TTRs: %w", err)
+	}
+
+	users, err := s.purge(ctx, cutoff, s.userRepo.PurgeDeletedBefore)
+	if users > 0 {
+		s.logger.Info("Purged deleted users", zap.Int64("count", users))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge deleted users: %w", err)
+	}
+
+	events, err := s.purge(ctx, time.Now().Add(-s.cfg.SecurityEvents), s.securityEventRepo.DeleteBefore)
+	if events > 0 {
+		s.logger.Info("Purged old security events", zap.Int64("count", events))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to purge security events: %w", err)
+	}
+
+	return nil
+}
+
+// purge calls purgeBatch until a batch comes back short, returning the total
+// purged.
+func (s *RetentionService) purge(ctx context.Context, cutoff time.Time, purgeBatch func(context.Context, time.Time, int) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		purged, err := purgeBatch(ctx, cutoff, s.cfg.BatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < int64(s.cfg.BatchSize) {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+// teeSheetWeek is how many days a week's tee sheet covers.
+const teeSheetWeek = 7
+
+// TeeSheet is the TTRs at a course a viewer can find, by day. Every day of
+// the sheet is listed, those without TTRs too.
+type TeeSheet struct {
+	CourseName string
+	Days       []TeeSheetDay
+}
+
+// TeeSheetDay holds a day's TTRs by tee time.
+type TeeSheetDay struct {
+	Date    time.Time
+	Entries []TeeSheetEntry
+}
+
+// TeeSheetEntry is a TTR's line on a tee sheet. ConfirmedCount counts the
+// confirmed players and guests.
+type TeeSheetEntry struct {
+	TTRID          uuid.UUID
+	TeeTime        time.Time
+	Status         models.TTRStatus
+	CaptainUser    UserSummary
+	ConfirmedCount int
+	OpenSlots      int
+}
+
+type ScheduleService struct {
+	scheduleRepo repository.ScheduleRepository
+}
+
+func NewScheduleService(scheduleRepo repository.ScheduleRepository) *ScheduleService {
+	return &ScheduleService{scheduleRepo: scheduleRepo}
+}
+
+// TeeSheet returns the tee sheet of the course for date, or for the seven
+// days from date when week is set. Only TTRs viewerID can find are on it, and
+// cancelled ones are left off.
+func (s *ScheduleService) TeeSheet(ctx context.Context, viewerID uuid.UUID, courseName string, date time.Time, week bool) (*TeeSheet, error) {
+	from := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	days := 1
+	if week {
+		days = teeSheetWeek
+	}
+
+	entries, err := s.scheduleRepo.FindSchedule(ctx, viewerID, courseName, from, from.AddDate(0, 0, days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find schedule: %w", err)
+	}
+
+	sheet := &TeeSheet{CourseName: courseName, Days: make([]TeeSheetDay, days)}
+	for i := range sheet.Days {
+		sheet.Days[i] = TeeSheetDay{Date: from.AddDate(0, 0, i), Entries: []TeeSheetEntry{}}
+	}
+	for _, entry := range entries {
+		// Entries come by tee time, so each day's stay in order.
+		day := int(entry.TeeDate.UTC().Sub(from).Hours() / 24)
+		if day < 0 || day >= days {
+			continue
+		}
+		sheet.Days[day].Entries = append(sheet.Days[day].Entries, newTeeSheetEntry(entry))
+	}
+	return sheet, nil
+}
+
+func newTeeSheetEntry(entry repository.ScheduleEntry) TeeSheetEntry {
+	ttr := models.TTR{MaxPlayers: entry.MaxPlayers}
+	return TeeSheetEntry{
+		TTRID:   entry.TTRID,
+		TeeTime: entry.TeeTime,
+		Status:  entry.Status,
+		CaptainUser: UserSummary{
+			ID:        entry.CaptainUserID,
+			FirstName: entry.CaptainFirstName,
+			LastName:  entry.CaptainLastName,
+			Deleted:   entry.CaptainDeleted,
+		},
+		ConfirmedCount: entry.Confirmed,
+		OpenSlots:      ttr.OpenSlots(models.PlayerCounts{Total: entry.Taken, Confirmed: entry.Confirmed}),
+	}
+}
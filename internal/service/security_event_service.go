@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+type clientKey struct{}
+
+// Client is the IP address and user agent a request came from.
+type Client struct {
+	IPAddress string
+	UserAgent string
+}
+
+// WithClient returns a copy of ctx carrying the client of the request it
+// belongs to, which security events are recorded with.
+func WithClient(ctx context.Context, ipAddress, userAgent string) context.Context {
+	return context.WithValue(ctx, clientKey{}, Client{IPAddress: ipAddress, UserAgent: userAgent})
+}
+
+// ClientFromContext returns the client stored by WithClient, or the zero
+// Client.
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
+
+// fingerprint identifies the client's device for new login alerts.
+func (c Client) fingerprint() string {
+	sum := sha256.Sum256([]byte(c.IPAddress + "\n" + c.UserAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// maxUserAgentLength is the longest user agent stored with an event.
+const maxUserAgentLength = 500
+
+// SecurityEventService records logins, failed logins, password changes and
+// sign-outs so users can check their account's activity, and alerts users
+// who log in from a device they haven't used before. A nil
+// *SecurityEventService records nothing.
+type SecurityEventService struct {
+	securityEventRepo   repository.SecurityEventRepository
+	notificationService *NotificationService
+}
+
+func NewSecurityEventService(securityEventRepo repository.SecurityEventRepository, notificationService *NotificationService) *SecurityEventService {
+	return &SecurityEventService{
+		securityEventRepo:   securityEventRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Record records an event of eventType for the user, from the client in ctx.
+func (s *SecurityEventService) Record(ctx context.Context, userID uuid.UUID, eventType string) error {
+	if s == nil {
+		return nil
+	}
+	client := ClientFromContext(ctx)
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	if err := s.securityEventRepo.Create(ctx, &models.SecurityEvent{
+		UserID:    userID,
+		Type:      eventType,
+		IPAddress: client.IPAddress,
+		UserAgent: userAgent,
+		// Set here rather than left to the column default, which SQLite
+		// only keeps to the second, so events list in the order they happened.
+		CreatedAt: time.Now(),
+	}); err != nil {
+		return fmt.Errorf("failed to record security event: %w", err)
+	}
+	return nil
+}
+
+// RecordLogin records a login and remembers the client's device. A user who
+// has logged in before is notified when the device is new to them; the
+// first device, usually the one they signed up on, isn't news.
+func (s *SecurityEventService) RecordLogin(ctx context.Context, userID uuid.UUID) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.Record(ctx, userID, models.SecurityEventLogin); err != nil {
+		return err
+	}
+
+	client := ClientFromContext(ctx)
+	if client.IPAddress == "" {
+		return nil
+	}
+	known, err := s.securityEventRepo.HasDevices(ctx, userID)
+	if err != nil {
+		return err
+	}
+	isNew, err := s.securityEventRepo.SeeDevice(ctx, userID, client.fingerprint(), time.Now())
+	if err != nil {
+		return err
+	}
+	if !isNew || !known || s.notificationService == nil {
+		return nil
+	}
+
+	targetType := "user"
+	if err := s.notificationService.Notify(ctx, userID, models.NotificationTypeNewLogin, "new_login", map[string]string{"ip": client.IPAddress}, &targetType, &userID); err != nil {
+		return fmt.Errorf("failed to notify new login: %w", err)
+	}
+	return nil
+}
+
+// List returns the user's events newest first.
+func (s *SecurityEventService) List(ctx context.Context, userID uuid.UUID, limit int, offset int) ([]*models.SecurityEvent, error) {
+	events, err := s.securityEventRepo.FindByUserID(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+	return events, nil
+}
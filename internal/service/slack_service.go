@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/slack"
+	"go.uber.org/zap"
+)
+
+// Slack reply visibility: ephemeral replies are only shown to the user who
+// typed the command, in_channel ones to the whole channel.
+const (
+	SlackReplyEphemeral = "ephemeral"
+	SlackReplyInChannel = "in_channel"
+)
+
+// slackLinkCodeAlphabet leaves out characters that are easy to mistype.
+const slackLinkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const slackLinkCodeLength = 8
+
+// SlackReply is the message Slack shows in answer to a command.
+type SlackReply struct {
+	ResponseType string
+	Text         string
+}
+
+// SlackService runs /golf slash commands for Slack users linked to an
+// account.
+type SlackService struct {
+	slackRepo         repository.SlackRepository
+	ttrService        *TTRService
+	inviteLinkService *InviteLinkService
+	cfg               config.SlackConfig
+	logger            *zap.Logger
+}
+
+func NewSlackService(slackRepo repository.SlackRepository, ttrService *TTRService, inviteLinkService *InviteLinkService, cfg config.SlackConfig, logger *zap.Logger) *SlackService {
+	return &SlackService{
+		slackRepo:         slackRepo,
+		ttrService:        ttrService,
+		inviteLinkService: inviteLinkService,
+		cfg:               cfg,
+		logger:            logger,
+	}
+}
+
+// HandleCommand runs the text typed after /golf by a Slack user. Mistakes
+// and unlinked users get an ephemeral reply explaining what to do, not an
+// error; errors are left for failures the user can't fix.
+func (s *SlackService) HandleCommand(ctx context.Context, teamID string, slackUserID string, text string) (*SlackReply, error) {
+	now := time.Now()
+	cmd, err := slack.Parse(text, now)
+	if err != nil {
+		return ephemeralReply(fmt.Sprintf("Sorry, I couldn't read that: %s.\n%s", err, slack.Usage)), nil
+	}
+
+	switch cmd.Kind {
+	case slack.CommandLink:
+		return s.issueLinkCode(ctx, teamID, slackUserID, now)
+	case slack.CommandCreate:
+		return s.createTTR(ctx, teamID, slackUserID, cmd, now)
+	default:
+		return ephemeralReply(slack.Usage), nil
+	}
+}
+
+func (s *SlackService) issueLinkCode(ctx context.Context, teamID string, slackUserID string, now time.Time) (*SlackReply, error) {
+	code, err := newSlackLinkCode()
+	if err != nil {
+		return nil, err
+	}
+	linkCode := &models.SlackLinkCode{
+		Code:        code,
+		TeamID:      teamID,
+		SlackUserID: slackUserID,
+		ExpiresAt:   now.Add(s.cfg.LinkCodeTTL),
+	}
+	if err := s.slackRepo.CreateLinkCode(ctx, linkCode); err != nil {
+		return nil, fmt.Errorf("failed to create slack link code: %w", err)
+	}
+
+	return ephemeralReply(fmt.Sprintf(
+		"Your linking code is *%s*. Sign in to Golf Messenger and send it to `POST /api/v1/integrations/slack/link` within %s to link this Slack account.",
+		code, s.cfg.LinkCodeTTL,
+	)), nil
+}
+
+func (s *SlackService) createTTR(ctx context.Context, teamID string, slackUserID string, cmd slack.Command, now time.Time) (*SlackReply, error) {
+	account, err := s.slackRepo.FindAccount(ctx, teamID, slackUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find slack account: %w", err)
+	}
+	if account == nil {
+		return ephemeralReply("Your Slack account isn't linked to Golf Messenger yet. Type `/golf link` to get a linking code."), nil
+	}
+
+	if n := utf8.RuneCountInString(cmd.CourseName); n < 2 || n > 255 {
+		return ephemeralReply("The course name must be between 2 and 255 characters."), nil
+	}
+	teeDateTime := time.Date(cmd.TeeDate.Year(), cmd.TeeDate.Month(), cmd.TeeDate.Day(), cmd.TeeTime.Hour(), cmd.TeeTime.Minute(), 0, 0, cmd.TeeDate.Location())
+	if teeDateTime.Before(now) {
+		return ephemeralReply("That tee time has already passed."), nil
+	}
+
+	ttr, err := s.ttrService.CreateTTR(ctx, account.UserID, cmd.CourseName, nil, cmd.TeeDate, cmd.TeeTime, cmd.MaxPlayers, 0, nil, models.CourseConditions{}, nil, nil, "", false)
+	if err != nil {
+		var coded *errcode.Error
+		if errors.As(err, &coded) {
+			return ephemeralReply(fmt.Sprintf("Sorry, I couldn't create that tee time: %s.", err)), nil
+		}
+		return nil, err
+	}
+
+	text := fmt.Sprintf("<@%s> is getting a group together at *%s* on %s at %s for %d players.",
+		slackUserID, ttr.CourseName, ttr.TeeDate.Format("Mon, Jan 2"), ttr.TeeTime.Format("15:04"), ttr.MaxPlayers)
+	if shareURL := s.shareLink(ctx, ttr, account.UserID); shareURL != "" {
+		text += fmt.Sprintf("\n<%s|Join the tee time>", shareURL)
+	}
+	return &SlackReply{ResponseType: SlackReplyInChannel, Text: text}, nil
+}
+
+// shareLink creates an invite link for the TTR's open slots and returns its
+// public URL, or "" when there is no slot to share or no public URL is
+// configured.
+func (s *SlackService) shareLink(ctx context.Context, ttr *TTRDetail, userID uuid.UUID) string {
+	if s.cfg.PublicURL == "" || ttr.MaxPlayers < 2 {
+		return ""
+	}
+	link, err := s.inviteLinkService.CreateInviteLink(ctx, ttr.ID, userID, ttr.MaxPlayers-1, nil)
+	if err != nil {
+		s.logger.Error("Failed to create share link", zap.String("ttr_id", ttr.ID.String()), zap.Error(err))
+		return ""
+	}
+	return s.cfg.PublicURL + "/api/v1/public/invite-links/" + link.Token
+}
+
+// LinkAccount makes userID the account behind the Slack user that was given
+// code by "/golf link". Each code works once.
+func (s *SlackService) LinkAccount(ctx context.Context, userID uuid.UUID, code string) error {
+	linkCode, err := s.slackRepo.ConsumeLinkCode(ctx, strings.ToUpper(strings.TrimSpace(code)), time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to consume slack link code: %w", err)
+	}
+	if linkCode == nil {
+		return ErrInvalidSlackLinkCode
+	}
+
+	if err := s.slackRepo.LinkAccount(ctx, &models.SlackAccount{
+		TeamID:      linkCode.TeamID,
+		SlackUserID: linkCode.SlackUserID,
+		UserID:      userID,
+	}); err != nil {
+		return fmt.Errorf("failed to link slack account: %w", err)
+	}
+	return nil
+}
+
+func ephemeralReply(text string) *SlackReply {
+	return &SlackReply{ResponseType: SlackReplyEphemeral, Text: text}
+}
+
+func newSlackLinkCode() (string, error) {
+	randomBytes := make([]byte, slackLinkCodeLength)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", fmt.Errorf("failed to generate slack link code: %w", err)
+	}
+	code := make([]byte, slackLinkCodeLength)
+	for i, b := range randomBytes {
+		code[i] = slackLinkCodeAlphabet[int(b)%len(slackLinkCodeAlphabet)]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	statsCacheKey = "admin:stats"
+	// statsCacheTTL is how long a stats snapshot is served before the
+	// aggregates run again.
+	statsCacheTTL = time.Minute
+	// activeUserWindow is how recently a user must have logged in to count
+	// as active.
+	activeUserWindow = 30 * 24 * time.Hour
+)
+
+// PlatformStats is a snapshot of how the platform is used, for operators.
+type PlatformStats struct {
+	TotalUsers          int64
+	NewUsers7Days       int64
+	NewUsers30Days      int64
+	ActiveUsers30Days   int64
+	TTRsByStatus        map[models.TTRStatus]int64
+	InvitationsByStatus map[models.InvitationStatus]int64
+	// AverageFillRate is the mean share of slots confirmed players took in
+	// completed TTRs, from 0 to 1.
+	AverageFillRate float64
+	GeneratedAt     time.Time
+}
+
+// StatsService serves the admin stats. The aggregates scan whole tables, so a
+// snapshot is cached for a minute and shared by every admin and instance.
+type StatsService struct {
+	statsRepo repository.StatsRepository
+	cache     cache.Cache
+	logger    *zap.Logger
+}
+
+func NewStatsService(statsRepo repository.StatsRepository, cache cache.Cache, logger *zap.Logger) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		cache:     cache,
+		logger:    logger,
+	}
+}
+
+// GetStats returns the platform stats, at most a minute old.
+func (s *StatsService) GetStats(ctx context.Context) (*PlatformStats, error) {
+	if data, err := s.cache.Get(ctx, statsCacheKey); err == nil {
+		var stats PlatformStats
+		if err := json.Unmarshal(data, &stats); err == nil {
+			return &stats, nil
+		}
+		s.logger.Warn("Discarding unreadable cached stats")
+	} else if !errors.Is(err, cache.ErrMiss) {
+		s.logger.Warn("Failed to read cached stats", zap.Error(err))
+	}
+
+	stats, err := s.computeStats(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(stats); err == nil {
+		if err := s.cache.Set(ctx, statsCacheKey, data, statsCacheTTL); err != nil {
+			s.logger.Warn("Failed to cache stats", zap.Error(err))
+		}
+	}
+	return stats, nil
+}
+
+func (s *StatsService) computeStats(ctx context.Context, now time.Time) (*PlatformStats, error) {
+	stats := &PlatformStats{GeneratedAt: now.UTC()}
+	var err error
+
+	if stats.TotalUsers, err = s.statsRepo.CountUsers(ctx); err != nil {
+		return nil, err
+	}
+	if stats.NewUsers7Days, err = s.statsRepo.CountUsersCreatedSince(ctx, now.AddDate(0, 0, -7)); err != nil {
+		return nil, err
+	}
+	if stats.NewUsers30Days, err = s.statsRepo.CountUsersCreatedSince(ctx, now.AddDate(0, 0, -30)); err != nil {
+		return nil, err
+	}
+	if stats.ActiveUsers30Days, err = s.statsRepo.CountUsersLoggedInSince(ctx, now.Add(-activeUserWindow)); err != nil {
+		return nil, err
+	}
+	if stats.TTRsByStatus, err = s.statsRepo.CountTTRsByStatus(ctx); err != nil {
+		return nil, err
+	}
+	if stats.InvitationsByStatus, err = s.statsRepo.CountInvitationsByStatus(ctx); err != nil {
+		return nil, err
+	}
+	if stats.AverageFillRate, err = s.statsRepo.AverageFillRate(ctx, models.TTRStatusCompleted); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+// MaxSuggestions caps the number of players SuggestPlayers returns.
+const MaxSuggestions = 20
+
+// Reasons a player is suggested, from the strongest signal to the weakest.
+const (
+	SuggestionReasonPlayedTogether = "played_together"
+	SuggestionReasonOrganization   = "organization"
+	SuggestionReasonPlayingDay     = "playing_day"
+)
+
+// PlayerSuggestion is a user the captain might want to invite. SharedRounds
+// is set for played_together suggestions.
+type PlayerSuggestion struct {
+	User         *models.User
+	Reason       string
+	SharedRounds int
+}
+
+type SuggestionService struct {
+	suggestionRepo repository.SuggestionRepository
+	userRepo       repository.UserRepository
+	authorizer     *Authorizer
+}
+
+func NewSuggestionService(suggestionRepo repository.SuggestionRepository, userRepo repository.UserRepository, authorizer *Authorizer) *SuggestionService {
+	return &SuggestionService{
+		suggestionRepo: suggestionRepo,
+		userRepo:       userRepo,
+		authorizer:     authorizer,
+	}
+}
+
+// SuggestPlayers ranks up to MaxSuggestions users for the TTR's invite
+// picker: first the users who played the most past rounds with userID, then
+// members of userID's organizations, then users who usually play on the TTR's
+// weekday. A user is listed once, under the first reason that applies.
+// Players, pending invitees and the TTR's captains are never suggested.
+func (s *SuggestionService) SuggestPlayers(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) ([]PlayerSuggestion, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	canInvite, err := s.authorizer.Can(ctx, userID, ActionTTRInvite, ttr)
+	if err != nil {
+		return nil, err
+	}
+	if !canInvite {
+		return nil, ErrNotManagerSuggestions
+	}
+
+	var suggestions []PlayerSuggestion
+	seen := make(map[uuid.UUID]bool)
+	add := func(id uuid.UUID, reason string, rounds int) {
+		if seen[id] || len(suggestions) >= MaxSuggestions {
+			return
+		}
+		seen[id] = true
+		suggestions = append(suggestions, PlayerSuggestion{User: &models.User{ID: id}, Reason: reason, SharedRounds: rounds})
+	}
+
+	counts, err := s.suggestionRepo.SharedRounds(ctx, ttr.ID, userID, time.Now().Truncate(24*time.Hour), MaxSuggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest players: %w", err)
+	}
+	for _, count := range counts {
+		add(count.UserID, SuggestionReasonPlayedTogether, count.Rounds)
+	}
+
+	if len(suggestions) < MaxSuggestions {
+		mates, err := s.suggestionRepo.OrganizationMates(ctx, ttr.ID, userID, MaxSuggestions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest players: %w", err)
+		}
+		for _, id := range mates {
+			add(id, SuggestionReasonOrganization, 0)
+		}
+	}
+
+	if len(suggestions) < MaxSuggestions {
+		day := strings.ToLower(ttr.TeeDate.Weekday().String())
+		players, err := s.suggestionRepo.PlayingOn(ctx, ttr.ID, userID, day, MaxSuggestions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to suggest players: %w", err)
+		}
+		for _, id := range players {
+			add(id, SuggestionReasonPlayingDay, 0)
+		}
+	}
+
+	return s.loadUsers(ctx, suggestions)
+}
+
+// loadUsers fills in the suggested users in one query, dropping any that
+// disappeared since they were ranked.
+func (s *SuggestionService) loadUsers(ctx context.Context, suggestions []PlayerSuggestion) ([]PlayerSuggestion, error) {
+	ids := make([]uuid.UUID, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		ids = append(ids, suggestion.User.ID)
+	}
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suggested players: %w", err)
+	}
+	byID := make(map[uuid.UUID]*models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	loaded := make([]PlayerSuggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if user, ok := byID[suggestion.User.ID]; ok {
+			suggestion.User = user
+			loaded = append(loaded, suggestion)
+		}
+	}
+	return loaded, nil
+}
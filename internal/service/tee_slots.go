@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+// TeeSlotInput is one tee slot of a SetTeeSlots request. A zero Capacity
+// means models.MaxPairingGroupSize.
+type TeeSlotInput struct {
+	OffsetMinutes int
+	Capacity      int
+}
+
+// SetTeeSlots replaces the TTR's tee slots, numbered 1 up in the given order.
+// The slots must hold every confirmed player. With autoFill the confirmed
+// players are dealt out round-robin in join order and everyone else is
+// unassigned; otherwise assignments to slots that are gone are dropped and
+// the rest kept. An empty list removes the slots and keeps the pairings.
+// Players whose slot changed are notified.
+func (s *TTRService) SetTeeSlots(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, inputs []TeeSlotInput, autoFill bool) (*TTRDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canManage {
+		return nil, ErrNotManagerTeeSlots
+	}
+
+	slots := make([]models.TeeSlot, 0, len(inputs))
+	capacity := 0
+	for i, input := range inputs {
+		if input.Capacity == 0 {
+			input.Capacity = models.MaxPairingGroupSize
+		}
+		if input.OffsetMinutes < 0 || input.Capacity < 1 || input.Capacity > models.MaxPairingGroupSize {
+			return nil, ErrInvalidTeeSlots
+		}
+		if i > 0 && input.OffsetMinutes <= inputs[i-1].OffsetMinutes {
+			return nil, ErrInvalidTeeSlots
+		}
+		slots = append(slots, models.TeeSlot{
+			TTRID:         ttrID,
+			Number:        i + 1,
+			OffsetMinutes: input.OffsetMinutes,
+			Capacity:      input.Capacity,
+		})
+		capacity += input.Capacity
+	}
+
+	var confirmed []*models.TTRPlayer
+	for i := range ttr.Players {
+		if ttr.Players[i].Status == models.TTRPlayerStatusConfirmed {
+			confirmed = append(confirmed, &ttr.Players[i])
+		}
+	}
+	if len(slots) > 0 && capacity < len(confirmed) {
+		return nil, ErrSlotCapacityTooSmall
+	}
+
+	groups := make(map[uuid.UUID]int, len(ttr.Players))
+	switch {
+	case autoFill && len(slots) > 0:
+		groups = dealSlots(confirmed, slots)
+	case len(slots) > 0:
+		sizes := make(map[int]int)
+		for _, player := range ttr.Players {
+			if player.GroupNumber == 0 || player.GroupNumber > len(slots) {
+				continue
+			}
+			groups[player.UserID] = player.GroupNumber
+			sizes[player.GroupNumber]++
+			if sizes[player.GroupNumber] > slots[player.GroupNumber-1].Capacity {
+				return nil, ErrTeeSlotFull
+			}
+		}
+	default:
+		for _, player := range ttr.Players {
+			groups[player.UserID] = player.GroupNumber
+		}
+	}
+
+	var changed []*models.TTRPlayer
+	for i := range ttr.Players {
+		player := &ttr.Players[i]
+		if group := groups[player.UserID]; player.GroupNumber != group {
+			player.GroupNumber = group
+			changed = append(changed, player)
+		}
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ttrRepo.ReplaceSlots(ctx, ttrID, slots); err != nil {
+			return err
+		}
+		for _, player := range changed {
+			if err := s.ttrRepo.UpdatePlayer(ctx, player); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tee slots: %w", err)
+	}
+	ttr.Slots = slots
+	s.pairingsChanged(ctx, ttr, managerUserID, changed)
+
+	return NewTTRDetail(ttr), nil
+}
+
+// dealSlots deals players out over slots one at a time in join order, like
+// cards, passing over slots that are full. The slots must hold them all.
+func dealSlots(players []*models.TTRPlayer, slots []models.TeeSlot) map[uuid.UUID]int {
+	ordered := append([]*models.TTRPlayer(nil), players...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].JoinedAt.Before(ordered[j].JoinedAt)
+	})
+
+	groups := make(map[uuid.UUID]int, len(ordered))
+	sizes := make([]int, len(slots))
+	next := 0
+	for _, player := range ordered {
+		for sizes[next] >= slots[next].Capacity {
+			next = (next + 1) % len(slots)
+		}
+		groups[player.UserID] = slots[next].Number
+		sizes[next]++
+		next = (next + 1) % len(slots)
+	}
+	return groups
+}
+
+// checkGroupSize returns the error for putting size players in the TTR's
+// pairing group, if that is more than it holds.
+func checkGroupSize(ttr *models.TTR, group int, size int) error {
+	capacity, ok := ttr.GroupCapacity(group)
+	if !ok {
+		return ErrTeeSlotNotFound
+	}
+	if size <= capacity {
+		return nil
+	}
+	if len(ttr.Slots) > 0 {
+		return ErrTeeSlotFull
+	}
+	return ErrPairingGroupFull
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+type TournamentService struct {
+	tournamentRepo      repository.TournamentRepository
+	ttrRepo             repository.TTRRepository
+	userRepo            repository.UserRepository
+	authorizer          *Authorizer
+	transactor          repository.Transactor
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+func NewTournamentService(
+	tournamentRepo repository.TournamentRepository,
+	ttrRepo repository.TTRRepository,
+	userRepo repository.UserRepository,
+	authorizer *Authorizer,
+	transactor repository.Transactor,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *TournamentService {
+	return &TournamentService{
+		tournamentRepo:      tournamentRepo,
+		ttrRepo:             ttrRepo,
+		userRepo:            userRepo,
+		authorizer:          authorizer,
+		transactor:          transactor,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// CreateTournament draws a bracket for players, given in seed order, and
+// makes userID its owner. ttrID optionally names the TTR the tournament is
+// drawn from; its managers are the only ones who can use it, and its course
+// and settings are copied for round TTRs.
+func (s *TournamentService) CreateTournament(ctx context.Context, userID uuid.UUID, name string, players []uuid.UUID, ttrID *uuid.UUID) (*models.Tournament, error) {
+	tournament := &models.Tournament{
+		ID:          uuid.New(),
+		Name:        strings.TrimSpace(name),
+		OwnerUserID: userID,
+		TTRID:       ttrID,
+		Status:      models.TournamentStatusInProgress,
+	}
+	if tournament.Name == "" {
+		return nil, ErrTournamentNameRequired
+	}
+
+	matches, err := NewBracket(tournament.ID, players)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Matches = matches
+
+	for _, playerID := range players {
+		player, err := s.userRepo.FindByID(ctx, playerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find player: %w", err)
+		}
+		if player == nil {
+			return nil, ErrEntrantNotFound
+		}
+	}
+
+	if ttrID != nil {
+		ttr, err := s.authorizer.TTR(ctx, *ttrID)
+		if err != nil {
+			return nil, err
+		}
+		canManage, err := s.authorizer.Can(ctx, userID, ActionTTRUpdate, ttr)
+		if err != nil {
+			return nil, err
+		}
+		if !canManage {
+			return nil, ErrNotManagerStartTournament
+		}
+	}
+
+	if err := s.tournamentRepo.Create(ctx, tournament); err != nil {
+		return nil, fmt.Errorf("failed to create tournament: %w", err)
+	}
+
+	for _, playerID := range players {
+		if playerID == userID {
+			continue
+		}
+		s.notify(ctx, playerID, tournament, "tournament_entered", map[string]string{"tournament": tournament.Name})
+	}
+
+	return tournament, nil
+}
+
+func (s *TournamentService) GetTournament(ctx context.Context, tournamentID uuid.UUID) (*models.Tournament, error) {
+	tournament, err := s.tournamentRepo.FindByID(ctx, tournamentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tournament: %w", err)
+	}
+	if tournament == nil {
+		return nil, ErrTournamentNotFound
+	}
+	return tournament, nil
+}
+
+// ReportResult records the winner of a scheduled match and advances them
+// through the bracket. Either of the match's players or the tournament owner
+// can report it. Winning the final completes the tournament.
+func (s *TournamentService) ReportResult(ctx context.Context, tournamentID uuid.UUID, matchID uuid.UUID, userID uuid.UUID, winnerID uuid.UUID) (*models.Tournament, error) {
+	var tournament *models.Tournament
+	var reported *models.Match
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		tournament, err = s.GetTournament(ctx, tournamentID)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range tournament.Matches {
+			if m.ID == matchID {
+				reported = m
+				break
+			}
+		}
+		if reported == nil {
+			return ErrMatchNotFound
+		}
+		if userID != tournament.OwnerUserID && !reported.HasPlayer(userID) {
+			return ErrNotMatchPlayer
+		}
+
+		changed, err := ReportMatchResult(tournament.Matches, matchID, winnerID)
+		if err != nil {
+			return err
+		}
+
+		completed, err := s.tournamentRepo.CompleteMatch(ctx, changed[0])
+		if err != nil {
+			return err
+		}
+		if !completed {
+			return ErrMatchCompleted
+		}
+		for _, m := range changed[1:] {
+			if err := s.tournamentRepo.UpdateMatch(ctx, m); err != nil {
+				return err
+			}
+		}
+
+		if FinalMatch(tournament.Matches).ID == matchID {
+			tournament.Status = models.TournamentStatusCompleted
+			tournament.WinnerUserID = &winnerID
+			if err := s.tournamentRepo.Update(ctx, tournament); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, playerID := range []*uuid.UUID{reported.Player1UserID, reported.Player2UserID} {
+		if *playerID == userID {
+			continue
+		}
+		s.notify(ctx, *playerID, tournament, "tournament_match_result", map[string]string{
+			"round":      strconv.Itoa(reported.Round),
+			"tournament": tournament.Name,
+		})
+	}
+
+	return tournament, nil
+}
+
+// CreateRoundTTR creates a TTR for the players still to play in round,
+// copying the course and settings of the TTR the tournament was drawn from.
+// Only the tournament owner can create it, and only once every match in the
+// round knows both players.
+func (s *TournamentService) CreateRoundTTR(ctx context.Context, tournamentID uuid.UUID, round int, userID uuid.UUID, teeDate time.Time, teeTime *time.Time) (*models.TTR, error) {
+	tournament, err := s.GetTournament(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	if tournament.OwnerUserID != userID {
+		return nil, ErrNotTournamentOwner
+	}
+	if tournament.TTRID == nil {
+		return nil, ErrTournamentHasNoTTR
+	}
+
+	var roundMatches []*models.Match
+	var players []uuid.UUID
+	for _, m := range tournament.Matches {
+		if m.Round != round {
+			continue
+		}
+		if m.Status == models.MatchStatusPending {
+			return nil, ErrRoundNotReady
+		}
+		if m.TTRID != nil {
+			return nil, ErrRoundHasTTR
+		}
+		if m.Status == models.MatchStatusScheduled {
+			roundMatches = append(roundMatches, m)
+			players = append(players, *m.Player1UserID, *m.Player2UserID)
+		}
+	}
+	if len(roundMatches) == 0 {
+		return nil, ErrRoundFinished
+	}
+
+	source, err := s.authorizer.TTR(ctx, *tournament.TTRID)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := fmt.Sprintf("%s - round %d", tournament.Name, round)
+	ttr := &models.TTR{
+		CourseName:      source.CourseName,
+		CourseLocation:  source.CourseLocation,
+		TeeDate:         teeDate,
+		TeeTime:         source.TeeTime,
+		MaxPlayers:      len(players),
+		CreatedByUserID: userID,
+		CaptainUserID:   userID,
+		Status:          models.TTRStatusConfirmed,
+		Notes:           &notes,
+		OrganizationID:  source.OrganizationID,
+	}
+	ttr.CourseConditions = source.CourseConditions
+	if teeTime != nil {
+		ttr.TeeTime = *teeTime
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ttrRepo.Create(ctx, ttr); err != nil {
+			return fmt.Errorf("failed to create TTR: %w", err)
+		}
+		for _, playerID := range players {
+			if err := s.ttrRepo.AddPlayer(ctx, ttr.ID, playerID, models.DefaultPlayerStatus); err != nil {
+				return fmt.Errorf("failed to add player: %w", err)
+			}
+		}
+		for _, m := range roundMatches {
+			m.TTRID = &ttr.ID
+			if err := s.tournamentRepo.UpdateMatch(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, playerID := range players {
+		s.notify(ctx, playerID, tournament, "tournament_round_scheduled", map[string]string{
+			"round":      strconv.Itoa(round),
+			"tournament": tournament.Name,
+			"course":     source.CourseName,
+			"date":       teeDate.Format("2006-01-02"),
+		})
+	}
+
+	createdTTR, err := s.ttrRepo.FindByID(ctx, ttr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve created TTR: %w", err)
+	}
+
+	return createdTTR, nil
+}
+
+func (s *TournamentService) notify(ctx context.Context, userID uuid.UUID, tournament *models.Tournament, notifType string, params map[string]string) {
+	targetType := "tournament"
+	if err := s.notificationService.Notify(ctx, userID, notifType, notifType, params, &targetType, &tournament.ID); err != nil {
+		s.logger.Error("Failed to create notification", zap.Error(err))
+	}
+}
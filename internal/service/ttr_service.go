@@ -1,41 +1,123 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
 	"go.uber.org/zap"
 )
 
+// DuplicateTTRWindow is how close to an existing TTR's tee time a new TTR by
+// the same captain at the same course counts as a duplicate.
+const DuplicateTTRWindow = 30 * time.Minute
+
 type TTRService struct {
-	ttrRepo  repository.TTRRepository
-	userRepo repository.UserRepository
-	logger   *zap.Logger
+	ttrRepo             repository.TTRRepository
+	userRepo            repository.UserRepository
+	invitationRepo      repository.InvitationRepository
+	transactor          repository.Transactor
+	authorizer          *Authorizer
+	notificationService *NotificationService
+	webhookService      *WebhookService
+	changeFeed          *ChangeFeedService
+	analytics           *AnalyticsService
+	history             *HistoryService
+	restoreWindow       time.Duration
+	editLockWindow      time.Duration
+	logger              *zap.Logger
 }
 
-func NewTTRService(ttrRepo repository.TTRRepository, userRepo repository.UserRepository, logger *zap.Logger) *TTRService {
+// NewTTRService creates the TTR service. Deleted TTRs can be restored for
+// restoreWindow. From editLockWindow before a TTR's tee time on, its course,
+// tee date and tee time only change, and it is only deleted, with an
+// override; zero turns the lock off. TTR events go to webhookService and
+// analytics, and every change is recorded in changeFeed. Completing a TTR, or
+// reopening it, drops its players' cached history. Any of them may be nil.
+func NewTTRService(
+	ttrRepo repository.TTRRepository,
+	userRepo repository.UserRepository,
+	invitationRepo repository.InvitationRepository,
+	transactor repository.Transactor,
+	authorizer *Authorizer,
+	notificationService *NotificationService,
+	webhookService *WebhookService,
+	changeFeed *ChangeFeedService,
+	analytics *AnalyticsService,
+	history *HistoryService,
+	restoreWindow time.Duration,
+	editLockWindow time.Duration,
+	logger *zap.Logger,
+) *TTRService {
 	return &TTRService{
-		ttrRepo:  ttrRepo,
-		userRepo: userRepo,
-		logger:   logger,
+		ttrRepo:             ttrRepo,
+		userRepo:            userRepo,
+		invitationRepo:      invitationRepo,
+		transactor:          transactor,
+		authorizer:          authorizer,
+		notificationService: notificationService,
+		webhookService:      webhookService,
+		changeFeed:          changeFeed,
+		analytics:           analytics,
+		history:             history,
+		restoreWindow:       restoreWindow,
+		editLockWindow:      editLockWindow,
+		logger:              logger,
 	}
 }
 
-func (s *TTRService) CreateTTR(userID uuid.UUID, courseName string, courseLocation *string, teeDate time.Time, teeTime time.Time, maxPlayers int, notes *string) (*models.TTR, error) {
+// CreateTTR creates a TTR captained by userID. When organizationID is set the
+// TTR is private to that organization, and userID must be one of its members.
+// An empty visibility means PRIVATE, and a zero minPlayers means
+// models.DefaultMinPlayers, or maxPlayers when that is smaller. Unless force
+// is set, it returns a *DuplicateTTRError when userID already captains a TTR
+// at the course, not cancelled, within DuplicateTTRWindow of the tee time.
+// The course conditions are all optional.
+func (s *TTRService) CreateTTR(ctx context.Context, userID uuid.UUID, courseName string, courseLocation *string, teeDate time.Time, teeTime time.Time, maxPlayers int, minPlayers int, notes *string, conditions models.CourseConditions, rsvpDeadline *time.Time, organizationID *uuid.UUID, visibility string, force bool) (*TTRDetail, error) {
 	if maxPlayers <= 0 {
-		return nil, errors.New("max_players must be greater than 0")
+		return nil, ErrInvalidMaxPlayers
+	}
+	if minPlayers == 0 {
+		minPlayers = models.DefaultMinPlayers
+		if maxPlayers < minPlayers {
+			minPlayers = maxPlayers
+		}
+	}
+	if minPlayers < 0 || minPlayers > maxPlayers {
+		return nil, ErrInvalidMinPlayers
+	}
+	if visibility == "" {
+		visibility = models.TTRVisibilityPrivate
+	}
+	if !validVisibility(visibility) {
+		return nil, ErrInvalidTTRVisibility
+	}
+	if err := validateConditions(conditions); err != nil {
+		return nil, err
 	}
 
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
+	}
+
+	if organizationID != nil {
+		member, err := s.authorizer.OrganizationMember(ctx, *organizationID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, ErrNotMemberCreateTTR
+		}
 	}
 
 	ttr := &models.TTR{
@@ -44,54 +126,143 @@ func (s *TTRService) CreateTTR(userID uuid.UUID, courseName string, courseLocati
 		TeeDate:         teeDate,
 		TeeTime:         teeTime,
 		MaxPlayers:      maxPlayers,
+		MinPlayers:      minPlayers,
 		CreatedByUserID: userID,
 		CaptainUserID:   userID,
 		Status:          models.TTRStatusOpen,
-		Notes:           notes,
+		Visibility:      visibility,
+		Notes:           sanitize.TextPtr(notes),
+		RSVPDeadline:    rsvpDeadline,
+		OrganizationID:  organizationID,
 	}
-
-	if err := s.ttrRepo.Create(ttr); err != nil {
-		return nil, fmt.Errorf("failed to create TTR: %w", err)
+	applyConditions(ttr, conditions)
+	if err := validateRSVPDeadline(ttr); err != nil {
+		return nil, err
 	}
 
-	if err := s.ttrRepo.AddPlayer(ttr.ID, userID, models.TTRPlayerStatusConfirmed); err != nil {
-		return nil, fmt.Errorf("failed to add captain as player: %w", err)
+	if !force {
+		teeAt := ttr.TeeDateTime()
+		existing, err := s.ttrRepo.FindByCaptainCourseNear(ctx, userID, courseName, teeAt.Add(-DuplicateTTRWindow), teeAt.Add(DuplicateTTRWindow))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check for duplicate TTRs: %w", err)
+		}
+		if existing != nil {
+			return nil, &DuplicateTTRError{ExistingTTRID: existing.ID}
+		}
 	}
 
-	createdTTR, err := s.ttrRepo.FindByID(ttr.ID)
+	var createdTTR *models.TTR
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ttrRepo.Create(ctx, ttr); err != nil {
+			return fmt.Errorf("failed to create TTR: %w", err)
+		}
+
+		if err := s.ttrRepo.AddPlayer(ctx, ttr.ID, userID, models.DefaultPlayerStatus); err != nil {
+			return fmt.Errorf("failed to add captain as player: %w", err)
+		}
+
+		var err error
+		createdTTR, err = s.ttrRepo.FindByID(ctx, ttr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve created TTR: %w", err)
+		}
+
+		return s.webhookService.PublishTTR(ctx, models.WebhookEventTTRCreated, createdTTR)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve created TTR: %w", err)
+		return nil, err
 	}
+	s.analytics.TTRCreated(createdTTR)
 
-	return createdTTR, nil
+	return NewTTRDetail(createdTTR), nil
 }
 
-func (s *TTRService) GetTTR(id uuid.UUID) (*models.TTR, error) {
-	ttr, err := s.ttrRepo.FindByID(id)
+// GetTTR returns the TTR and whether userID participates in it as captain,
+// co-captain, player or pending invitee. Participants may see the full TTR;
+// others only see OPEN TTRs, which callers should trim to a public view. A
+// non-participant asking for any other TTR, or for an organization TTR when
+// they aren't a member, gets ErrTTRNotFound.
+func (s *TTRService) GetTTR(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*TTRDetail, bool, error) {
+	ttr, err := s.authorizer.TTR(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get TTR: %w", err)
+		return nil, false, err
 	}
-	if ttr == nil {
-		return nil, errors.New("TTR not found")
+
+	isParticipant, err := s.authorizer.IsParticipant(ctx, ttr, userID)
+	if err != nil {
+		return nil, false, err
 	}
-	return ttr, nil
+	if !isParticipant {
+		canView, err := s.authorizer.Can(ctx, userID, ActionTTRView, ttr)
+		if err != nil {
+			return nil, false, err
+		}
+		if !canView {
+			return nil, false, ErrTTRNotFound
+		}
+	}
+
+	return NewTTRDetail(ttr), isParticipant, nil
 }
 
-func (s *TTRService) UpdateTTR(ttrID uuid.UUID, userID uuid.UUID, courseName *string, courseLocation *string, teeDate *time.Time, teeTime *time.Time, maxPlayers *int, status *string, notes *string) (*models.TTR, error) {
-	canManage, err := s.canManageTTR(ttrID, userID)
+// TTRView is a TTR as GetTTRs returns it, with whether the user it was read
+// for participates in it.
+type TTRView struct {
+	TTR         *TTRDetail
+	Participant bool
+}
+
+// GetTTRs is GetTTR for several TTRs, loading them and their viewer's pending
+// invitations in a fixed number of queries. TTRs userID may not view, or that
+// don't exist, are left out of the map.
+func (s *TTRService) GetTTRs(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (map[uuid.UUID]TTRView, error) {
+	loaded, err := s.authorizer.TTRs(ctx, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check permissions: %w", err)
+		return nil, err
 	}
-	if !canManage {
-		return nil, errors.New("unauthorized: only captain or co-captain can update TTR")
+	ttrs := make([]*models.TTR, 0, len(loaded))
+	for _, ttr := range loaded {
+		ttrs = append(ttrs, ttr)
+	}
+	viewable, err := s.authorizer.ViewTTRs(ctx, ttrs, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	ttr, err := s.ttrRepo.FindByID(ttrID)
+	views := make(map[uuid.UUID]TTRView, len(viewable))
+	for id, isParticipant := range viewable {
+		views[id] = TTRView{TTR: NewTTRDetail(loaded[id]), Participant: isParticipant}
+	}
+	return views, nil
+}
+
+// UpdateTTR changes the given fields and leaves nil ones as they are. Setting
+// status locks it: roster changes no longer move the TTR between OPEN and
+// CONFIRMED until statusLocked is set back to false. Once the TTR's edit lock
+// has started, changing the course, tee date or tee time returns a
+// *TTRLockedError unless override is set, and an overridden change is sent to
+// the confirmed players as an urgent notification. Nil course conditions are
+// left as they are too.
+func (s *TTRService) UpdateTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, courseName *string, courseLocation *string, teeDate *time.Time, teeTime *time.Time, maxPlayers *int, minPlayers *int, status *models.TTRStatus, statusLocked *bool, notes *string, conditions models.CourseConditions, rsvpDeadline *time.Time, visibility *string, override bool) (*TTRDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to find TTR: %w", err)
+		return nil, err
 	}
-	if ttr == nil {
-		return nil, errors.New("TTR not found")
+	canUpdate, err := s.authorizer.Can(ctx, userID, ActionTTRUpdate, ttr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canUpdate {
+		return nil, ErrNotManagerUpdateTTR
+	}
+	previousStatus := ttr.Status
+
+	lateChange := false
+	if lockedAt, locked := s.editLock(ttr); locked && lockedFieldsChanged(ttr, courseName, courseLocation, teeDate, teeTime) {
+		if !override {
+			return nil, &TTRLockedError{LockedAt: lockedAt}
+		}
+		lateChange = true
 	}
 
 	if courseName != nil {
@@ -108,242 +279,1067 @@ func (s *TTRService) UpdateTTR(ttrID uuid.UUID, userID uuid.UUID, courseName *st
 	}
 	if maxPlayers != nil {
 		if *maxPlayers <= 0 {
-			return nil, errors.New("max_players must be greater than 0")
+			return nil, ErrInvalidMaxPlayers
 		}
 		ttr.MaxPlayers = *maxPlayers
 	}
+	if minPlayers != nil {
+		ttr.MinPlayers = *minPlayers
+	}
+	if (minPlayers != nil || maxPlayers != nil) && (ttr.MinPlayers < 1 || ttr.MinPlayers > ttr.MaxPlayers) {
+		return nil, ErrInvalidMinPlayers
+	}
 	if status != nil {
+		if !status.IsValid() {
+			return nil, ErrInvalidTTRStatus
+		}
 		ttr.Status = *status
+		ttr.StatusLocked = true
+	}
+	if statusLocked != nil {
+		ttr.StatusLocked = *statusLocked
 	}
 	if notes != nil {
-		ttr.Notes = notes
+		ttr.Notes = sanitize.TextPtr(notes)
 	}
-
-	if err := s.ttrRepo.Update(ttr); err != nil {
-		return nil, fmt.Errorf("failed to update TTR: %w", err)
+	if err := validateConditions(conditions); err != nil {
+		return nil, err
+	}
+	conditionsChanged := applyConditions(ttr, conditions)
+	if visibility != nil {
+		if !validVisibility(*visibility) {
+			return nil, ErrInvalidTTRVisibility
+		}
+		ttr.Visibility = *visibility
+	}
+	if rsvpDeadline != nil {
+		ttr.RSVPDeadline = rsvpDeadline
+		// A moved deadline gets processed again when it passes.
+		ttr.RSVPClosedAt = nil
+	}
+	if err := validateRSVPDeadline(ttr); err != nil {
+		return nil, err
 	}
 
-	updatedTTR, err := s.ttrRepo.FindByID(ttrID)
+	var changes []ttrChange
+	if status != nil && ttr.Status != previousStatus {
+		changes = append(changes, ttrChange{
+			eventType:   models.TTREventStatusChanged,
+			actorUserID: &userID,
+			data:        map[string]string{"from": string(previousStatus), "to": string(ttr.Status)},
+		})
+	}
+	if courseName != nil || courseLocation != nil || teeDate != nil || teeTime != nil || maxPlayers != nil || minPlayers != nil || statusLocked != nil || notes != nil || conditionsChanged || rsvpDeadline != nil || visibility != nil {
+		changes = append(changes, ttrChange{eventType: models.TTREventDetailsUpdated, actorUserID: &userID})
+	}
+	var updatedTTR *models.TTR
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+			return s.ttrRepo.Update(ctx, ttr)
+		}, changes...); err != nil {
+			return fmt.Errorf("failed to update TTR: %w", err)
+		}
+
+		var err error
+		updatedTTR, err = s.ttrRepo.FindByID(ctx, ttrID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve updated TTR: %w", err)
+		}
+
+		event := models.WebhookEventTTRUpdated
+		if updatedTTR.Status == models.TTRStatusCancelled && previousStatus != models.TTRStatusCancelled {
+			event = models.WebhookEventTTRCancelled
+		}
+		if err := s.webhookService.PublishTTR(ctx, event, updatedTTR); err != nil {
+			return err
+		}
+
+		if lateChange {
+			return s.notifyLateChange(ctx, updatedTTR, userID)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve updated TTR: %w", err)
+		return nil, err
+	}
+	if updatedTTR.Status == models.TTRStatusCompleted && previousStatus != models.TTRStatusCompleted {
+		s.analytics.TTRCompleted(updatedTTR, userID)
+	}
+	if (updatedTTR.Status == models.TTRStatusCompleted) != (previousStatus == models.TTRStatusCompleted) {
+		s.history.Invalidate(ctx, updatedTTR)
 	}
 
-	return updatedTTR, nil
+	return NewTTRDetail(updatedTTR), nil
 }
 
-func (s *TTRService) DeleteTTR(ttrID uuid.UUID, userID uuid.UUID) error {
-	isCaptain, err := s.isCaptain(ttrID, userID)
+// DeleteTTR cancels the TTR and then soft-deletes it. The row keeps the
+// CANCELLED status so anything reading it unscoped (invitation history,
+// reports) sees why it went away, and remembers the status it had before so
+// RestoreTTR can put it back. Pending invitations are cancelled in the same
+// transaction, and every other player and pending invitee is notified. Besides
+// the captain, owners and admins of the TTR's organization may delete it.
+// Once the TTR's edit lock has started it returns a *TTRLockedError unless
+// override is set.
+func (s *TTRService) DeleteTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, override bool) error {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to check captain status: %w", err)
+		return err
 	}
-	if !isCaptain {
-		return errors.New("unauthorized: only captain can delete TTR")
+	canDelete, err := s.authorizer.Can(ctx, userID, ActionTTRDelete, ttr)
+	if err != nil {
+		return err
 	}
+	if !canDelete {
+		return ErrNotCaptainDelete
+	}
+	if lockedAt, locked := s.editLock(ttr); locked && !override {
+		return &TTRLockedError{LockedAt: lockedAt}
+	}
+
+	var cancelled []*models.Invitation
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		cancelled, err = s.invitationRepo.CancelPendingByTTRID(ctx, ttrID)
+		if err != nil {
+			return err
+		}
+
+		previous := ttr.Status
+		ttr.PreDeleteStatus = &previous
+		ttr.Status = models.TTRStatusCancelled
+		if err := s.ttrRepo.Update(ctx, ttr); err != nil {
+			return err
+		}
+
+		if err := s.ttrRepo.Delete(ctx, ttrID); err != nil {
+			return err
+		}
+		if err := s.webhookService.PublishTTR(ctx, models.WebhookEventTTRCancelled, ttr); err != nil {
+			return err
+		}
+
+		recipients := make([]uuid.UUID, 0, len(ttr.Players)+len(cancelled))
+		for _, player := range ttr.Players {
+			if player.UserID != userID {
+				recipients = append(recipients, player.UserID)
+			}
+		}
+		for _, invitation := range cancelled {
+			recipients = append(recipients, invitation.InviteeUserID)
+		}
 
-	if err := s.ttrRepo.Delete(ttrID); err != nil {
+		targetType := "ttr"
+		params := map[string]string{"course": ttr.CourseName, "date": ttr.TeeDate.Format("2006-01-02")}
+		for _, recipient := range recipients {
+			if err := s.notificationService.Notify(ctx, recipient, "TTR_CANCELLED", "ttr_cancelled", params, &targetType, &ttr.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete TTR: %w", err)
 	}
+	s.changeFeed.Record(ctx, ttrID, models.TTREventStatusChanged, &userID, map[string]string{"from": string(*ttr.PreDeleteStatus), "to": string(ttr.Status), "deleted": "true"})
+	for _, invitation := range cancelled {
+		s.notificationService.ResolveInvitation(ctx, invitation)
+	}
 
 	return nil
 }
 
-func (s *TTRService) SearchTTRs(limit int, offset int, status string) ([]*models.TTR, error) {
-	ttrs, err := s.ttrRepo.FindAll(limit, offset, status)
+// ListDeletedTTRs returns the TTRs userID captains that were deleted within
+// the restore window, most recently deleted first.
+func (s *TTRService) ListDeletedTTRs(ctx context.Context, userID uuid.UUID) ([]*TTRDetail, error) {
+	ttrs, err := s.ttrRepo.FindDeletedByCaptain(ctx, userID, time.Now().Add(-s.restoreWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted TTRs: %w", err)
+	}
+	return newTTRDetails(ttrs), nil
+}
+
+// RestoreTTR undoes DeleteTTR within the restore window, putting the TTR back
+// in the status it had before and telling its players the round is back on.
+// Invitations cancelled by the delete stay cancelled. Only the captain may
+// restore a TTR; to anyone else a deleted TTR doesn't exist.
+func (s *TTRService) RestoreTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (*TTRDetail, error) {
+	ttr, err := s.ttrRepo.FindDeletedByID(ctx, ttrID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find TTR: %w", err)
+	}
+	if ttr == nil || ttr.CaptainUserID != userID {
+		return nil, ErrTTRNotFound
+	}
+	if ttr.DeletedAt.Time.Before(time.Now().Add(-s.restoreWindow)) {
+		return nil, ErrTTRNotRestorable
+	}
+
+	status := models.TTRStatusOpen
+	if ttr.PreDeleteStatus != nil {
+		status = *ttr.PreDeleteStatus
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ttrRepo.Restore(ctx, ttrID, status); err != nil {
+			return fmt.Errorf("failed to restore TTR: %w", err)
+		}
+
+		targetType := "ttr"
+		params := map[string]string{"course": ttr.CourseName, "date": ttr.TeeDate.Format("2006-01-02")}
+		for _, player := range ttr.Players {
+			if player.UserID == userID {
+				continue
+			}
+			if err := s.notificationService.Notify(ctx, player.UserID, "TTR_RESTORED", "ttr_restored", params, &targetType, &ttr.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.changeFeed.Record(ctx, ttrID, models.TTREventStatusChanged, &userID, map[string]string{"from": string(ttr.Status), "to": string(status)})
+
+	restored, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	return NewTTRDetail(restored), nil
+}
+
+// TTRSearch narrows SearchTTRs. Empty fields don't filter, and the rest
+// combine. Mine keeps the TTRs the caller takes part in that way. Invited
+// TTRs are those with a pending invitation the caller can still answer, so
+// invited with Status CANCELLED or COMPLETED finds nothing. TeeFrom and TeeTo
+// bound the tee date, both inclusive. Holes keeps TTRs of that many holes and
+// MaxGreenFeeCents those whose green fee is known and at most that; TTRs
+// without the condition don't match either.
+type TTRSearch struct {
+	Status           models.TTRStatus
+	Mine             models.TTRParticipation
+	TeeFrom          *time.Time
+	TeeTo            *time.Time
+	Holes            *int
+	MaxGreenFeeCents *int
+}
+
+// SearchTTRs lists the TTRs userID takes part in or may find through their
+// visibility, leaving out organization TTRs from organizations they don't
+// belong to.
+func (s *TTRService) SearchTTRs(ctx context.Context, userID uuid.UUID, search TTRSearch, limit int, offset int) ([]*TTRDetail, error) {
+	ttrs, err := s.ttrRepo.FindAll(ctx, userID, repository.TTRSearchFilter{
+		Status:           search.Status,
+		Mine:             search.Mine,
+		TeeFrom:          search.TeeFrom,
+		TeeTo:            search.TeeTo,
+		Holes:            search.Holes,
+		MaxGreenFeeCents: search.MaxGreenFeeCents,
+		Limit:            limit,
+		Offset:           offset,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to search TTRs: %w", err)
 	}
-	return ttrs, nil
+	return newTTRDetails(ttrs), nil
 }
 
-func (s *TTRService) AddCoCaptain(ttrID uuid.UUID, captainUserID uuid.UUID, coCaptainUserID uuid.UUID) error {
-	isCaptain, err := s.isCaptain(ttrID, captainUserID)
+// AddCoCaptain makes coCaptainUserID a co-captain of the TTR with the
+// permissions, or with every permission when permissions is nil. It reports
+// true when they already were one, which is no error, so a repeated request
+// succeeds; their permissions are left as they were.
+func (s *TTRService) AddCoCaptain(ctx context.Context, ttrID uuid.UUID, captainUserID uuid.UUID, coCaptainUserID uuid.UUID, permissions []string) (bool, error) {
+	permissions, err := coCaptainPermissionSet(permissions)
 	if err != nil {
-		return fmt.Errorf("failed to check captain status: %w", err)
+		return false, err
 	}
-	if !isCaptain {
-		return errors.New("unauthorized: only captain can add co-captains")
+
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return false, err
+	}
+	canManage, err := s.authorizer.Can(ctx, captainUserID, ActionTTRManageCoCaptains, ttr)
+	if err != nil {
+		return false, fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canManage {
+		return false, ErrNotCaptainAddCoCaptain
 	}
 
-	coCaptainUser, err := s.userRepo.FindByID(coCaptainUserID)
+	coCaptainUser, err := s.userRepo.FindByID(ctx, coCaptainUserID)
 	if err != nil {
-		return fmt.Errorf("failed to find co-captain user: %w", err)
+		return false, fmt.Errorf("failed to find co-captain user: %w", err)
 	}
 	if coCaptainUser == nil {
-		return errors.New("co-captain user not found")
+		return false, ErrCoCaptainNotFound
+	}
+
+	if ttr.HasCoCaptain(coCaptainUserID) {
+		return true, nil
+	}
+
+	if err := s.ttrRepo.AddCoCaptain(ctx, ttrID, coCaptainUserID, permissions); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			// A concurrent request added them first.
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to add co-captain: %w", err)
+	}
+
+	return false, nil
+}
+
+// UpdateCoCaptainPermissions replaces what coCaptainUserID may do as a
+// co-captain of the TTR with permissions.
+func (s *TTRService) UpdateCoCaptainPermissions(ctx context.Context, ttrID uuid.UUID, captainUserID uuid.UUID, coCaptainUserID uuid.UUID, permissions []string) (*TTRCoCaptainDetail, error) {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	permissions, err := coCaptainPermissionSet(permissions)
+	if err != nil {
+		return nil, err
 	}
 
-	isAlreadyCoCaptain, err := s.ttrRepo.IsCoCaptain(ttrID, coCaptainUserID)
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to check co-captain status: %w", err)
+		return nil, err
+	}
+	canManage, err := s.authorizer.Can(ctx, captainUserID, ActionTTRManageCoCaptains, ttr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check permissions: %w", err)
 	}
-	if isAlreadyCoCaptain {
-		return errors.New("user is already a co-captain")
+	if !canManage {
+		return nil, ErrNotCaptainUpdateCoCaptain
 	}
 
-	if err := s.ttrRepo.AddCoCaptain(ttrID, coCaptainUserID); err != nil {
-		return fmt.Errorf("failed to add co-captain: %w", err)
+	for _, coCaptain := range ttr.CoCaptains {
+		if coCaptain.UserID != coCaptainUserID {
+			continue
+		}
+		updated, err := s.ttrRepo.UpdateCoCaptainPermissions(ctx, ttrID, coCaptainUserID, permissions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update co-captain permissions: %w", err)
+		}
+		if !updated {
+			// They were removed since the TTR was read.
+			return nil, ErrNotCoCaptain
+		}
+		detail := NewTTRCoCaptainDetail(&coCaptain)
+		detail.Permissions = permissions
+		return &detail, nil
 	}
+	return nil, ErrNotCoCaptain
+}
 
-	return nil
+// coCaptainPermissionSet checks permissions and returns them without repeats,
+// in the order of models.CoCaptainPermissions. nil stands for all of them.
+func coCaptainPermissionSet(permissions []string) ([]string, error) {
+	if permissions == nil {
+		return models.CoCaptainPermissions, nil
+	}
+	granted := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		if !models.ValidCoCaptainPermission(permission) {
+			return nil, ErrInvalidPermission
+		}
+		granted[permission] = true
+	}
+	set := make([]string, 0, len(granted))
+	for _, permission := range models.CoCaptainPermissions {
+		if granted[permission] {
+			set = append(set, permission)
+		}
+	}
+	return set, nil
 }
 
-func (s *TTRService) RemoveCoCaptain(ttrID uuid.UUID, captainUserID uuid.UUID, coCaptainUserID uuid.UUID) error {
-	isCaptain, err := s.isCaptain(ttrID, captainUserID)
+// RemoveCoCaptain takes the co-captain role from coCaptainUserID. It reports
+// true when they had none, which is no error.
+func (s *TTRService) RemoveCoCaptain(ctx context.Context, ttrID uuid.UUID, captainUserID uuid.UUID, coCaptainUserID uuid.UUID) (bool, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to check captain status: %w", err)
+		return false, err
 	}
-	if !isCaptain {
-		return errors.New("unauthorized: only captain can remove co-captains")
+	canManage, err := s.authorizer.Can(ctx, captainUserID, ActionTTRManageCoCaptains, ttr)
+	if err != nil {
+		return false, fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canManage {
+		return false, ErrNotCaptainRemoveCoCaptain
 	}
 
-	if err := s.ttrRepo.RemoveCoCaptain(ttrID, coCaptainUserID); err != nil {
-		return fmt.Errorf("failed to remove co-captain: %w", err)
+	removed, err := s.ttrRepo.RemoveCoCaptain(ctx, ttrID, coCaptainUserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove co-captain: %w", err)
 	}
 
-	return nil
+	return !removed, nil
 }
 
-func (s *TTRService) JoinTTR(ttrID uuid.UUID, userID uuid.UUID) error {
-	ttr, err := s.ttrRepo.FindByID(ttrID)
+// JoinTTR adds the user to the TTR's roster and notifies the captain. It
+// reports true when the user was already on the roster, which is no error,
+// so a double-submitted join succeeds. Cancelled and completed TTRs can't be
+// joined.
+func (s *TTRService) JoinTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to find TTR: %w", err)
-	}
-	if ttr == nil {
-		return errors.New("TTR not found")
+		return false, err
 	}
 
-	playerCount, err := s.getPlayerCount(ttrID)
+	canJoin, err := s.authorizer.Can(ctx, userID, ActionTTRJoin, ttr)
 	if err != nil {
-		return fmt.Errorf("failed to get player count: %w", err)
+		return false, err
 	}
-	if playerCount >= ttr.MaxPlayers {
-		return errors.New("TTR is full")
+	if !canJoin {
+		return false, ErrTTRNotFound
+	}
+
+	if ttr.Status == models.TTRStatusCancelled || ttr.Status == models.TTRStatusCompleted {
+		return false, ErrTTRClosedJoin
 	}
 
-	isAlreadyPlayer, err := s.ttrRepo.IsPlayer(ttrID, userID)
+	if ttr.HasPlayer(userID) {
+		return true, nil
+	}
+
+	if ttr.Headcount() >= ttr.MaxPlayers {
+		return false, ErrTTRFull
+	}
+
+	if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		if err := s.ttrRepo.AddPlayer(ctx, ttrID, userID, models.DefaultPlayerStatus); err != nil {
+			return err
+		}
+		targetType := "ttr"
+		params := map[string]string{"course": ttr.CourseName}
+		return s.notificationService.Notify(ctx, ttr.CaptainUserID, models.NotificationTypePlayerJoined, "player_joined", params, &targetType, &ttr.ID)
+	}, playerJoined(userID)); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			// A concurrent join, such as a double tap, got in first.
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to join TTR: %w", err)
+	}
+
+	return false, nil
+}
+
+// errNotMember rolls back a leave when the user turns out to have left
+// already, so the captain isn't told twice.
+var errNotMember = errors.New("not a member of the TTR")
+
+// LeaveTTR removes the user from the TTR, including any co-captain role, and
+// notifies the captain. Pending invitations the user sent stay valid: they were
+// issued on behalf of the TTR, so they are reassigned to the captain, who can
+// then still cancel them. It reports true when the user wasn't on the TTR,
+// which is no error, so leaving twice succeeds.
+func (s *TTRService) LeaveTTR(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to check player status: %w", err)
+		return false, err
+	}
+
+	if ttr.CaptainUserID == userID {
+		return false, ErrCaptainCannotLeave
 	}
-	if isAlreadyPlayer {
-		return errors.New("user is already a player")
+	if !ttr.HasPlayer(userID) && !ttr.HasCoCaptain(userID) {
+		return true, nil
 	}
 
-	if err := s.ttrRepo.AddPlayer(ttrID, userID, models.TTRPlayerStatusConfirmed); err != nil {
-		return fmt.Errorf("failed to join TTR: %w", err)
+	if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		removed, err := s.ttrRepo.RemoveMember(ctx, ttrID, userID, ttr.CaptainUserID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errNotMember
+		}
+		targetType := "ttr"
+		params := map[string]string{"course": ttr.CourseName}
+		return s.notificationService.Notify(ctx, ttr.CaptainUserID, "player_left", "player_left", params, &targetType, &ttr.ID)
+	}, ttrChange{eventType: models.TTREventPlayerLeft, actorUserID: &userID, data: map[string]string{"user_id": userID.String()}}); err != nil {
+		if errors.Is(err, errNotMember) {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to leave TTR: %w", err)
 	}
 
-	return nil
+	return false, nil
 }
 
-func (s *TTRService) LeaveTTR(ttrID uuid.UUID, userID uuid.UUID) error {
-	ttr, err := s.ttrRepo.FindByID(ttrID)
+// AddGuest puts a player without an account on the TTR's roster. Guests take
+// a slot like any confirmed player but get no invitations or notifications.
+func (s *TTRService) AddGuest(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, displayName string, handicap *float64) (*TTRGuestDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
 	if err != nil {
-		return fmt.Errorf("failed to find TTR: %w", err)
+		return nil, fmt.Errorf("failed to check permissions: %w", err)
 	}
-	if ttr == nil {
-		return errors.New("TTR not found")
+	if !canManage {
+		return nil, ErrNotManagerGuests
 	}
 
-	if ttr.CaptainUserID == userID {
-		return errors.New("captain cannot leave TTR")
+	if ttr.Headcount() >= ttr.MaxPlayers {
+		return nil, ErrTTRFull
 	}
 
-	if err := s.ttrRepo.RemovePlayer(ttrID, userID); err != nil {
-		return fmt.Errorf("failed to leave TTR: %w", err)
+	guest := &models.TTRGuest{
+		TTRID:         ttrID,
+		DisplayName:   displayName,
+		Handicap:      handicap,
+		AddedByUserID: managerUserID,
+	}
+	if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		return s.ttrRepo.AddGuest(ctx, guest)
+	}, guestChange(models.TTREventGuestAdded, managerUserID, guest)); err != nil {
+		return nil, fmt.Errorf("failed to add guest: %w", err)
 	}
 
-	return nil
+	detail := NewTTRGuestDetail(guest)
+	return &detail, nil
 }
 
-func (s *TTRService) UpdatePlayerStatus(ttrID uuid.UUID, managerUserID uuid.UUID, playerUserID uuid.UUID, status string) error {
-	canManage, err := s.canManageTTR(ttrID, managerUserID)
+// RemoveGuest takes a guest off the TTR's roster.
+func (s *TTRService) RemoveGuest(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, guestID uuid.UUID) error {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
 	if err != nil {
 		return fmt.Errorf("failed to check permissions: %w", err)
 	}
 	if !canManage {
-		return errors.New("unauthorized: only captain or co-captain can update player status")
+		return ErrNotManagerGuests
 	}
 
-	validStatuses := map[string]bool{
-		models.TTRPlayerStatusConfirmed: true,
-		models.TTRPlayerStatusMaybe:     true,
-		models.TTRPlayerStatusDeclined:  true,
+	var guest *models.TTRGuest
+	for i := range ttr.Guests {
+		if ttr.Guests[i].ID == guestID {
+			guest = &ttr.Guests[i]
+		}
 	}
-	if !validStatuses[status] {
-		return errors.New("invalid player status")
+	if guest == nil {
+		return ErrGuestNotFound
 	}
 
-	players, err := s.ttrRepo.GetPlayers(ttrID)
+	if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		removed, err := s.ttrRepo.RemoveGuest(ctx, ttrID, guestID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrGuestNotFound
+		}
+		return nil
+	}, guestChange(models.TTREventGuestRemoved, managerUserID, guest)); err != nil {
+		if errors.Is(err, ErrGuestNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to remove guest: %w", err)
+	}
+	return nil
+}
+
+func (s *TTRService) UpdatePlayerStatus(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, playerUserID uuid.UUID, status models.TTRPlayerStatus) error {
+	return s.UpdatePlayer(ctx, ttrID, managerUserID, playerUserID, &status, nil, nil)
+}
+
+// UpdatePlayer changes a roster entry's status, notes or pairing group. Nil
+// arguments leave the field as it is. A status the player can't move to is
+// refused, as is one that takes a slot when the TTR is full.
+func (s *TTRService) UpdatePlayer(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, playerUserID uuid.UUID, status *models.TTRPlayerStatus, notes *string, groupNumber *int) error {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return fmt.Errorf("failed to get players: %w", err)
+		return err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canManage {
+		return ErrNotManagerUpdatePlayer
 	}
 
-	var found bool
-	for _, player := range players {
-		if player.UserID == playerUserID {
-			found = true
+	if status != nil {
+		if !status.IsValid() {
+			return ErrInvalidPlayerStatus
+		}
+	}
+	if groupNumber != nil && *groupNumber < 0 {
+		return ErrInvalidGroupNumber
+	}
+
+	var player *models.TTRPlayer
+	for i := range ttr.Players {
+		if ttr.Players[i].UserID == playerUserID {
+			player = &ttr.Players[i]
 			break
 		}
 	}
 
-	if !found {
-		return errors.New("player not found in TTR")
+	if player == nil {
+		return ErrPlayerNotFound
+	}
+
+	if status != nil {
+		if !player.Status.CanTransitionTo(*status) {
+			return ErrInvalidStatusChange
+		}
+		if status.IsActive() && !player.Status.IsActive() && ttr.Headcount() >= ttr.MaxPlayers {
+			return ErrTTRFull
+		}
+	}
+
+	if groupNumber != nil && *groupNumber > 0 && *groupNumber != player.GroupNumber {
+		groupSize := 0
+		for _, p := range ttr.Players {
+			if p.GroupNumber == *groupNumber {
+				groupSize++
+			}
+		}
+		if err := checkGroupSize(ttr, *groupNumber, groupSize+1); err != nil {
+			return err
+		}
+	}
+
+	if status != nil {
+		player.Status = *status
+	}
+	if notes != nil {
+		player.Notes = sanitize.TextPtr(notes)
+	}
+	if groupNumber != nil {
+		player.GroupNumber = *groupNumber
 	}
 
-	if err := s.ttrRepo.RemovePlayer(ttrID, playerUserID); err != nil {
-		return fmt.Errorf("failed to remove player: %w", err)
+	if err := s.changeRoster(ctx, ttr, func(ctx context.Context) error {
+		return s.ttrRepo.UpdatePlayer(ctx, player)
+	}, playerUpdated(managerUserID, player)); err != nil {
+		return fmt.Errorf("failed to update player: %w", err)
 	}
 
-	if err := s.ttrRepo.AddPlayer(ttrID, playerUserID, status); err != nil {
-		return fmt.Errorf("failed to add player with new status: %w", err)
+	return nil
+}
+
+// SetPairings replaces the TTR's pairing groups with the given user→group
+// mapping. Roster players missing from the mapping become unassigned (group
+// 0). Players whose group changed are notified.
+func (s *TTRService) SetPairings(ctx context.Context, ttrID uuid.UUID, managerUserID uuid.UUID, pairings map[uuid.UUID]int) error {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return err
+	}
+	canManage, err := s.authorizer.Can(ctx, managerUserID, ActionTTRManagePlayers, ttr)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+	if !canManage {
+		return ErrNotManagerUpdatePairings
+	}
+
+	roster := make(map[uuid.UUID]*models.TTRPlayer, len(ttr.Players))
+	for i := range ttr.Players {
+		roster[ttr.Players[i].UserID] = &ttr.Players[i]
 	}
 
+	groupSizes := make(map[int]int)
+	for userID, group := range pairings {
+		if _, ok := roster[userID]; !ok {
+			return ErrPairingOffRoster
+		}
+		if group < 0 {
+			return ErrInvalidGroupNumber
+		}
+		if group == 0 {
+			continue
+		}
+		groupSizes[group]++
+		if err := checkGroupSize(ttr, group, groupSizes[group]); err != nil {
+			return err
+		}
+	}
+
+	var changed []*models.TTRPlayer
+	for userID, player := range roster {
+		group := pairings[userID]
+		if player.GroupNumber != group {
+			player.GroupNumber = group
+			changed = append(changed, player)
+		}
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, player := range changed {
+			if err := s.ttrRepo.UpdatePlayer(ctx, player); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pairings: %w", err)
+	}
+	s.pairingsChanged(ctx, ttr, managerUserID, changed)
 	return nil
 }
 
-func (s *TTRService) GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
-	players, err := s.ttrRepo.GetPlayers(ttrID)
+// pairingsChanged records the players whose group changed in the change feed
+// and tells each of them their new group.
+func (s *TTRService) pairingsChanged(ctx context.Context, ttr *models.TTR, managerUserID uuid.UUID, changed []*models.TTRPlayer) {
+	for _, player := range changed {
+		change := playerUpdated(managerUserID, player)
+		s.changeFeed.Record(ctx, ttr.ID, change.eventType, change.actorUserID, change.data)
+	}
+
+	targetType := "ttr"
+	for _, player := range changed {
+		template := "pairings_unassigned"
+		if player.GroupNumber > 0 {
+			template = "pairings_updated"
+		}
+		params := map[string]string{"course": ttr.CourseName, "group": strconv.Itoa(player.GroupNumber)}
+		if err := s.notificationService.Notify(ctx, player.UserID, "pairings_updated", template, params, &targetType, &ttr.ID); err != nil {
+			s.logger.Error("Failed to create notification", zap.Error(err))
+		}
+	}
+}
+
+// GetPlayers lists the TTR's players to userID if they may see the TTR, by
+// the same rule as GetTTR. Anyone else gets ErrTTRNotFound.
+func (s *TTRService) GetPlayers(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) ([]TTRPlayerDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
+	}
+	isParticipant, err := s.authorizer.IsParticipant(ctx, ttr, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isParticipant {
+		canView, err := s.authorizer.Can(ctx, userID, ActionTTRView, ttr)
+		if err != nil {
+			return nil, err
+		}
+		if !canView {
+			return nil, ErrTTRNotFound
+		}
+	}
+
+	players, err := s.ttrRepo.GetPlayers(ctx, ttrID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get players: %w", err)
 	}
-	return players, nil
+
+	details := make([]TTRPlayerDetail, 0, len(players))
+	for _, player := range players {
+		details = append(details, NewTTRPlayerDetail(player))
+	}
+	return details, nil
 }
 
-func (s *TTRService) isCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
-	ttr, err := s.ttrRepo.FindByID(ttrID)
+// GetGuests lists the guests on the TTR's roster.
+func (s *TTRService) GetGuests(ctx context.Context, ttrID uuid.UUID) ([]TTRGuestDetail, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
 	if err != nil {
-		return false, fmt.Errorf("failed to find TTR: %w", err)
+		return nil, err
 	}
-	if ttr == nil {
-		return false, errors.New("TTR not found")
+	return NewTTRDetail(ttr).Guests, nil
+}
+
+// Captains returns the IDs of the TTR's captain and co-captains.
+func (s *TTRService) Captains(ctx context.Context, ttrID uuid.UUID) ([]uuid.UUID, error) {
+	ttr, err := s.authorizer.TTR(ctx, ttrID)
+	if err != nil {
+		return nil, err
 	}
-	return ttr.CaptainUserID == userID, nil
+	return ttr.CaptainIDs(), nil
 }
 
-func (s *TTRService) isCoCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
-	return s.ttrRepo.IsCoCaptain(ttrID, userID)
+// ProcessRSVPDeadlines closes RSVPs on every TTR whose deadline has passed:
+// pending invitations expire and MAYBE players are asked to confirm. Each TTR
+// is handled once per deadline.
+func (s *TTRService) ProcessRSVPDeadlines(ctx context.Context) error {
+	now := time.Now()
+
+	ttrs, err := s.ttrRepo.FindRSVPDeadlinePassed(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to find TTRs past their RSVP deadline: %w", err)
+	}
+
+	for _, ttr := range ttrs {
+		var expired []*models.Invitation
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			expired, err = s.invitationRepo.ExpirePendingByTTRID(ctx, ttr.ID)
+			if err != nil {
+				return err
+			}
+
+			ttr.RSVPClosedAt = &now
+			return s.ttrRepo.Update(ctx, ttr)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to close RSVPs for TTR %s: %w", ttr.ID, err)
+		}
+
+		s.logger.Info("Closed RSVPs for TTR",
+			zap.String("ttr_id", ttr.ID.String()),
+			zap.Int("expired_invitations", len(expired)),
+		)
+		for _, invitation := range expired {
+			s.notificationService.ResolveInvitation(ctx, invitation)
+		}
+
+		targetType := "ttr"
+		params := map[string]string{"course": ttr.CourseName}
+		for _, player := range ttr.Players {
+			if player.Status != models.TTRPlayerStatusMaybe {
+				continue
+			}
+			if err := s.notificationService.Notify(ctx, player.UserID, "rsvp_confirm", "rsvp_confirm", params, &targetType, &ttr.ID); err != nil {
+				s.logger.Error("Failed to create notification", zap.Error(err))
+			}
+		}
+	}
+
+	return nil
 }
 
-func (s *TTRService) canManageTTR(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
-	isCaptain, err := s.isCaptain(ttrID, userID)
+// ttrChange is an event for the TTR's change feed, recorded once the change
+// it describes is committed.
+type ttrChange struct {
+	eventType   string
+	actorUserID *uuid.UUID
+	data        map[string]string
+}
+
+func playerJoined(userID uuid.UUID) ttrChange {
+	return ttrChange{
+		eventType:   models.TTREventPlayerJoined,
+		actorUserID: &userID,
+		data:        map[string]string{"user_id": userID.String()},
+	}
+}
+
+func playerUpdated(actorUserID uuid.UUID, player *models.TTRPlayer) ttrChange {
+	return ttrChange{
+		eventType:   models.TTREventPlayerUpdated,
+		actorUserID: &actorUserID,
+		data: map[string]string{
+			"user_id":      player.UserID.String(),
+			"status":       string(player.Status),
+			"group_number": strconv.Itoa(player.GroupNumber),
+		},
+	}
+}
+
+func guestChange(eventType string, actorUserID uuid.UUID, guest *models.TTRGuest) ttrChange {
+	return ttrChange{
+		eventType:   eventType,
+		actorUserID: &actorUserID,
+		data: map[string]string{
+			"guest_id":     guest.ID.String(),
+			"display_name": guest.DisplayName,
+		},
+	}
+}
+
+// changeRoster runs change and then syncStatus in one transaction, so the
+// TTR's status never disagrees with its roster, and tells the players in it
+// when the status moved. Once committed it records changes, and then any
+// status move, in the change feed.
+func (s *TTRService) changeRoster(ctx context.Context, ttr *models.TTR, change func(ctx context.Context) error, changes ...ttrChange) error {
+	previous := ttr.Status
+	var synced models.TTRStatus
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		synced, err = s.applyRosterChange(ctx, ttr, change)
+		return err
+	})
 	if err != nil {
-		return false, err
+		return err
 	}
-	if isCaptain {
-		return true, nil
+
+	s.recordRosterChange(ctx, ttr, previous, synced, changes...)
+	return nil
+}
+
+// applyRosterChange is the transactional half of changeRoster, for callers
+// that change more than the roster in their own transaction. It returns the
+// status syncStatus moved the TTR to, if any.
+func (s *TTRService) applyRosterChange(ctx context.Context, ttr *models.TTR, change func(ctx context.Context) error) (models.TTRStatus, error) {
+	if err := change(ctx); err != nil {
+		return "", err
+	}
+	synced, err := s.syncStatus(ctx, ttr)
+	if err != nil || synced == "" {
+		return "", err
+	}
+	return synced, s.notifyStatusSynced(ctx, ttr, synced)
+}
+
+// recordRosterChange is the half of changeRoster that runs once the change
+// has committed: it records changes, and the move from previous to synced if
+// there was one, in the change feed.
+func (s *TTRService) recordRosterChange(ctx context.Context, ttr *models.TTR, previous models.TTRStatus, synced models.TTRStatus, changes ...ttrChange) {
+	for _, change := range changes {
+		s.changeFeed.Record(ctx, ttr.ID, change.eventType, change.actorUserID, change.data)
+		if change.eventType == models.TTREventPlayerJoined {
+			s.analytics.PlayerJoined(ttr.ID, *change.actorUserID)
+		}
 	}
+	if synced != "" {
+		s.changeFeed.Record(ctx, ttr.ID, models.TTREventStatusChanged, nil, map[string]string{"from": string(previous), "to": string(synced)})
+	}
+}
 
-	isCoCaptain, err := s.isCoCaptain(ttrID, userID)
+// syncStatus moves the TTR between OPEN and CONFIRMED as its confirmed
+// players call for, unless the captain locked the status. It returns the new
+// status, or "" when the status stayed as it was.
+func (s *TTRService) syncStatus(ctx context.Context, ttr *models.TTR) (models.TTRStatus, error) {
+	counts, err := s.ttrRepo.CountPlayers(ctx, []uuid.UUID{ttr.ID})
 	if err != nil {
-		return false, err
+		return "", err
+	}
+
+	status := ttr.SyncedStatus(counts[ttr.ID])
+	if status == ttr.Status {
+		return "", nil
 	}
-	return isCoCaptain, nil
+	if err := s.ttrRepo.UpdateStatus(ctx, ttr.ID, status); err != nil {
+		return "", err
+	}
+
+	s.logger.Info("Synced TTR status with its roster",
+		zap.String("ttr_id", ttr.ID.String()),
+		zap.String("from", string(ttr.Status)),
+		zap.String("to", string(status)),
+	)
+	ttr.Status = status
+	return status, nil
 }
 
-func (s *TTRService) getPlayerCount(ttrID uuid.UUID) (int, error) {
-	players, err := s.ttrRepo.GetPlayers(ttrID)
+// notifyStatusSynced tells everyone on the roster that the TTR filled up and
+// is confirmed, or went short of players and is open again.
+func (s *TTRService) notifyStatusSynced(ctx context.Context, ttr *models.TTR, status models.TTRStatus) error {
+	template := "ttr_reopened"
+	if status == models.TTRStatusConfirmed {
+		template = "ttr_confirmed"
+	}
+
+	players, err := s.ttrRepo.GetPlayers(ctx, ttr.ID)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get players: %w", err)
+		return fmt.Errorf("failed to get players to notify: %w", err)
+	}
+
+	targetType := "ttr"
+	params := map[string]string{"course": ttr.CourseName, "date": ttr.TeeDate.Format("2006-01-02")}
+	for _, player := range players {
+		if err := s.notificationService.Notify(ctx, player.UserID, models.NotificationTypeTTRUpdate, template, params, &targetType, &ttr.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// editLock returns when the TTR's edit lock starts, editLockWindow before its
+// tee time, and whether it has started. Without a window nothing locks.
+func (s *TTRService) editLock(ttr *models.TTR) (time.Time, bool) {
+	if s.editLockWindow <= 0 {
+		return time.Time{}, false
+	}
+	lockedAt := ttr.TeeDateTime().Add(-s.editLockWindow)
+	return lockedAt, !time.Now().Before(lockedAt)
+}
+
+// lockedFieldsChanged reports whether the update moves the TTR to another
+// course, date or time. Fields sent with their current value don't count.
+func lockedFieldsChanged(ttr *models.TTR, courseName *string, courseLocation *string, teeDate *time.Time, teeTime *time.Time) bool {
+	if courseName != nil && *courseName != ttr.CourseName {
+		return true
+	}
+	if courseLocation != nil && (ttr.CourseLocation == nil || *courseLocation != *ttr.CourseLocation) {
+		return true
+	}
+	if teeDate != nil && teeDate.Format("2006-01-02") != ttr.TeeDate.Format("2006-01-02") {
+		return true
+	}
+	return teeTime != nil && teeTime.Format("15:04:05") != ttr.TeeTime.Format("15:04:05")
+}
+
+// notifyLateChange urgently tells the confirmed players, other than the one
+// who made it, that the TTR moved after its edit lock started.
+func (s *TTRService) notifyLateChange(ctx context.Context, ttr *models.TTR, userID uuid.UUID) error {
+	targetType := "ttr"
+	params := map[string]string{
+		"course": ttr.CourseName,
+		"date":   ttr.TeeDate.Format("2006-01-02"),
+		"time":   ttr.TeeTime.Format("15:04"),
+	}
+	for _, player := range ttr.Players {
+		if player.UserID == userID || player.Status != models.TTRPlayerStatusConfirmed {
+			continue
+		}
+		if err := s.notificationService.Notify(ctx, player.UserID, models.NotificationTypeTTRUrgentChange, "ttr_changed_late", params, &targetType, &ttr.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateConditions returns the API error for course conditions that break
+// a TTR invariant.
+func validateConditions(conditions models.CourseConditions) error {
+	switch conditions.Validate() {
+	case models.ErrTTRHoles:
+		return ErrInvalidHoles
+	case models.ErrTTRGreenFee:
+		return ErrInvalidGreenFee
+	}
+	return nil
+}
+
+// applyConditions sets the TTR's course conditions that are given, leaving
+// nil ones as they are, and reports whether any were.
+func applyConditions(ttr *models.TTR, conditions models.CourseConditions) bool {
+	given := false
+	if conditions.WalkingAllowed != nil {
+		ttr.WalkingAllowed = conditions.WalkingAllowed
+		given = true
+	}
+	if conditions.CartsAvailable != nil {
+		ttr.CartsAvailable = conditions.CartsAvailable
+		given = true
+	}
+	if conditions.Holes != nil {
+		ttr.Holes = conditions.Holes
+		given = true
+	}
+	if conditions.DressCode != nil {
+		ttr.DressCode = sanitize.TextPtr(conditions.DressCode)
+		given = true
+	}
+	if conditions.GreenFeeCents != nil {
+		ttr.GreenFeeCents = conditions.GreenFeeCents
+		given = true
+	}
+	return given
+}
+
+func validateRSVPDeadline(ttr *models.TTR) error {
+	if ttr.RSVPDeadline != nil && !ttr.RSVPDeadline.Before(ttr.TeeDateTime()) {
+		return ErrInvalidRSVPDeadline
+	}
+	return nil
+}
+
+func validVisibility(visibility string) bool {
+	switch visibility {
+	case models.TTRVisibilityPrivate, models.TTRVisibilityFriends, models.TTRVisibilityPublic:
+		return true
 	}
-	return len(players), nil
+	return false
 }
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+// Outcomes of importing a row.
+const (
+	ImportStatusCreated   = "created"
+	ImportStatusDuplicate = "skipped_duplicate"
+	ImportStatusError     = "error"
+)
+
+// importColumns maps the header names a member CSV may use, lowercased with
+// spaces and dashes as underscores, to the column they hold.
+var importColumns = map[string]string{
+	"email":         "email",
+	"email_address": "email",
+	"e_mail":        "email",
+	"first_name":    "first_name",
+	"firstname":     "first_name",
+	"first":         "first_name",
+	"last_name":     "last_name",
+	"lastname":      "last_name",
+	"last":          "last_name",
+	"surname":       "last_name",
+	"handicap":      "handicap",
+	"hcp":           "handicap",
+	"phone":         "phone",
+	"phone_number":  "phone",
+	"mobile":        "phone",
+}
+
+// UserImportRow is a valid member row of an import CSV. Line is its line in
+// the file, counting the header as line 1.
+type UserImportRow struct {
+	Line      int
+	Email     string
+	FirstName string
+	LastName  string
+	Handicap  *float64
+	Phone     *string
+}
+
+// UserImportRowError is returned by UserImportReader.Next for a row that
+// can't be imported. Email is the row's email as written, if it has one.
+type UserImportRowError struct {
+	Line   int
+	Email  string
+	Reason string
+}
+
+func (e *UserImportRowError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// UserImportReader reads a member CSV one row at a time. The header names the
+// columns, in any order: email, first_name and last_name are required,
+// handicap and phone optional, and any others are ignored.
+type UserImportReader struct {
+	csv     *csv.Reader
+	columns map[string]int
+}
+
+// NewUserImportReader reads the header of r. It returns ErrInvalidImportFile
+// when r isn't a CSV with the required columns.
+func NewUserImportReader(r io.Reader) (*UserImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets often save CSVs with a byte order mark.
+			name = strings.TrimPrefix(name, "\uFEFF")
+		}
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(name)))
+		if column, ok := importColumns[name]; ok {
+			if _, seen := columns[column]; !seen {
+				columns[column] = i
+			}
+		}
+	}
+	for _, required := range []string{"email", "first_name", "last_name"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ErrInvalidImportFile
+		}
+	}
+	return &UserImportReader{csv: reader, columns: columns}, nil
+}
+
+// Next returns the next row. A row that can't be imported comes back as a
+// *UserImportRowError, after which Next can be called again; io.EOF means the
+// file has been read. Blank lines are skipped.
+func (r *UserImportReader) Next() (*UserImportRow, error) {
+	record, err := r.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &UserImportRowError{Line: parseErr.StartLine, Reason: "malformed CSV: " + parseErr.Err.Error()}
+		}
+		return nil, err
+	}
+	line, _ := r.csv.FieldPos(0)
+
+	field := func(column string) string {
+		i, ok := r.columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	row := &UserImportRow{
+		Line:      line,
+		Email:     field("email"),
+		FirstName: field("first_name"),
+		LastName:  field("last_name"),
+	}
+	rowErr := func(reason string) error {
+		return &UserImportRowError{Line: line, Email: row.Email, Reason: reason}
+	}
+
+	if row.Email == "" {
+		return nil, rowErr("email is required")
+	}
+	if address, err := mail.ParseAddress(row.Email); err != nil || address.Address != row.Email {
+		return nil, rowErr("email is not a valid address")
+	}
+	for _, name := range []struct{ column, value string }{{"first_name", row.FirstName}, {"last_name", row.LastName}} {
+		if n := utf8.RuneCountInString(name.value); n < 2 || n > 100 {
+			return nil, rowErr(name.column + " must be 2 to 100 characters")
+		}
+	}
+	if handicap := field("handicap"); handicap != "" {
+		value, err := strconv.ParseFloat(handicap, 64)
+		if err != nil || value < 0 || value > 54 {
+			return nil, rowErr("handicap must be a number from 0 to 54")
+		}
+		row.Handicap = &value
+	}
+	if phone := field("phone"); phone != "" {
+		if len(phone) > 20 {
+			return nil, rowErr("phone must be at most 20 characters")
+		}
+		row.Phone = &phone
+	}
+	return row, nil
+}
+
+// UserImportResult is what happened to one row of an import.
+type UserImportResult struct {
+	Line    int
+	Email   string
+	Status  string
+	Reason  string
+	UserID  *uuid.UUID
+	Invited bool
+}
+
+// UserImportReport lists the result of every row read. Truncated is set when
+// the file had more rows than an import accepts; those rows weren't read.
+type UserImportReport struct {
+	Created   int
+	Skipped   int
+	Failed    int
+	Truncated bool
+	Rows      []UserImportResult
+}
+
+func (r *UserImportReport) add(result UserImportResult) {
+	switch result.Status {
+	case ImportStatusCreated:
+		r.Created++
+	case ImportStatusDuplicate:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// UserImportService creates accounts in bulk from a member CSV. Imported
+// members get an unusable password and must reset it; with a mailer, each is
+// sent an invitation with a temporary password through the outbox.
+type UserImportService struct {
+	userRepo     repository.UserRepository
+	auditLogRepo repository.AuditLogRepository
+	transactor   repository.Transactor
+	outbox       *OutboxService
+	mailer       mailer.Mailer
+	maxRows      int
+	logger       *zap.Logger
+}
+
+// NewUserImportService makes a service that imports up to maxRows rows at a
+// time. mailer may be nil, in which case no invitations are sent.
+func NewUserImportService(userRepo repository.UserRepository, auditLogRepo repository.AuditLogRepository, transactor repository.Transactor, outbox *OutboxService, mailer mailer.Mailer, maxRows int, logger *zap.Logger) *UserImportService {
+	s := &UserImportService{
+		userRepo:     userRepo,
+		auditLogRepo: auditLogRepo,
+		transactor:   transactor,
+		outbox:       outbox,
+		mailer:       mailer,
+		maxRows:      maxRows,
+		logger:       logger,
+	}
+	if outbox != nil && mailer != nil {
+		outbox.Handle(models.OutboxKindMemberInvite, s.sendInvite)
+	}
+	return s
+}
+
+// unusablePasswordHash is the password hash of imported members until their
+// invitation is sent. It isn't a bcrypt hash, so no password matches it, and
+// it saves hashing a password per row.
+const unusablePasswordHash = "!"
+
+// memberInvite is the outbox payload of an invitation email.
+type memberInvite struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Import reads r row by row, creating an account for every new member. A row
+// that fails is reported and the import goes on; only a file without the
+// required header, or a failure reading it, stops it with an error.
+func (s *UserImportService) Import(ctx context.Context, adminID uuid.UUID, r io.Reader) (*UserImportReport, error) {
+	reader, err := NewUserImportReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UserImportReport{Rows: make([]UserImportResult, 0)}
+	for read := 0; ; read++ {
+		row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if read == s.maxRows {
+			report.Truncated = true
+			break
+		}
+		var rowErr *UserImportRowError
+		if errors.As(err, &rowErr) {
+			report.add(UserImportResult{Line: rowErr.Line, Email: rowErr.Email, Status: ImportStatusError, Reason: rowErr.Reason})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import file: %w", err)
+		}
+
+		result, err := s.importRow(ctx, adminID, row)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.logger.Error("Failed to import user", zap.Int("line", row.Line), zap.Error(err))
+			result = UserImportResult{Line: row.Line, Email: row.Email, Status: ImportStatusError, Reason: "account could not be created"}
+		}
+		report.add(result)
+	}
+
+	s.logger.Info("Imported users",
+		zap.String("admin_id", adminID.String()),
+		zap.Int("created", report.Created),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed),
+		zap.Bool("truncated", report.Truncated),
+	)
+	return report, nil
+}
+
+// importRow creates the row's account, its audit log entry and its
+// invitation in one transaction, so a row is either imported whole or not at
+// all.
+func (s *UserImportService) importRow(ctx context.Context, adminID uuid.UUID, row *UserImportRow) (UserImportResult, error) {
+	result := UserImportResult{Line: row.Line, Email: row.Email}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.userRepo.FindByEmail(ctx, row.Email)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			result.Status = ImportStatusDuplicate
+			result.Reason = "an account with this email already exists"
+			return nil
+		}
+
+		user := &models.User{
+			Email:             row.Email,
+			PasswordHash:      unusablePasswordHash,
+			FirstName:         row.FirstName,
+			LastName:          row.LastName,
+			Handicap:          row.Handicap,
+			Phone:             row.Phone,
+			MustResetPassword: true,
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+
+		if err := s.auditLogRepo.Create(ctx, &models.AuditLogEntry{
+			Action:        models.AuditActionImported,
+			ActorUserID:   adminID,
+			SubjectUserID: &user.ID,
+			Details:       fmt.Sprintf("import line %d", row.Line),
+		}); err != nil {
+			return fmt.Errorf("failed to write audit log entry: %w", err)
+		}
+		if s.mailer != nil && s.outbox != nil {
+			if err := s.outbox.Enqueue(ctx, models.OutboxKindMemberInvite, memberInvite{UserID: user.ID}); err != nil {
+				return err
+			}
+			result.Invited = true
+		}
+
+		result.Status = ImportStatusCreated
+		result.UserID = &user.ID
+		return nil
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		// Someone signed up with the email while the row was imported.
+		return UserImportResult{Line: row.Line, Email: row.Email, Status: ImportStatusDuplicate, Reason: "an account with this email already exists"}, nil
+	}
+	return result, err
+}
+
+// sendInvite gives an imported member a new temporary password and emails it
+// to them. The password only lives in the email: a delivery that is retried
+// sets another one, and members who have chosen their own password are
+// skipped.
+func (s *UserImportService) sendInvite(ctx context.Context, payload []byte) error {
+	var invite memberInvite
+	if err := json.Unmarshal(payload, &invite); err != nil {
+		return fmt.Errorf("failed to decode member invite: %w", err)
+	}
+	user, err := s.userRepo.FindByID(ctx, invite.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.MustResetPassword {
+		return nil
+	}
+
+	password, err := temporaryPassword()
+	if err != nil {
+		return err
+	}
+	if err := user.SetPassword(password); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	params := map[string]string{"first_name": user.FirstName, "email": user.Email, "password": password}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: i18n.Translate(i18n.DefaultLanguage, "email.member_invite.subject", params),
+		Body:    i18n.Translate(i18n.DefaultLanguage, "email.member_invite.body", params),
+	})
+}
+
+func temporaryPassword() (string, error) {
+	passwordBytes := make([]byte, 12)
+	if _, err := rand.Read(passwordBytes); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(passwordBytes), nil
+}
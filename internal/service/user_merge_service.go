@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// UserMergeResult is what a merge did: the accounts it merged and what it
+// moved from one to the other.
+type UserMergeResult struct {
+	SourceUserID uuid.UUID
+	TargetUserID uuid.UUID
+	repository.UserMerge
+}
+
+// UserMergeService merges duplicate accounts, such as one a user signed up
+// for with Google and another with their email, on behalf of support.
+type UserMergeService struct {
+	userRepo   repository.UserRepository
+	transactor repository.Transactor
+	logger     *zap.Logger
+}
+
+func NewUserMergeService(userRepo repository.UserRepository, transactor repository.Transactor, logger *zap.Logger) *UserMergeService {
+	return &UserMergeService{
+		userRepo:   userRepo,
+		transactor: transactor,
+		logger:     logger,
+	}
+}
+
+// Merge moves the source account's rounds, invitations, notifications and
+// messages to the target account and deletes the source, as
+// UserRepository.Merge describes. The source may already be deactivated; the
+// target must be active. Merging a source into the target it was already
+// merged into changes nothing, so a retried request is safe.
+func (s *UserMergeService) Merge(ctx context.Context, adminID uuid.UUID, sourceID uuid.UUID, targetID uuid.UUID) (*UserMergeResult, error) {
+	if sourceID == targetID {
+		return nil, ErrCannotMergeSelf
+	}
+
+	var merge *repository.UserMerge
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		source, err := s.userRepo.FindByIDUnscoped(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return ErrUserNotFound
+		}
+		if source.MergedIntoUserID != nil && *source.MergedIntoUserID != targetID {
+			return ErrUserAlreadyMerged
+		}
+		target, err := s.userRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrMergeTargetNotFound
+		}
+
+		merge, err = s.userRepo.Merge(ctx, sourceID, targetID, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Merged users",
+		zap.String("admin_id", adminID.String()),
+		zap.String("source_user_id", sourceID.String()),
+		zap.String("target_user_id", targetID.String()),
+		zap.Int64("ttrs", merge.CaptainedTTRs),
+		zap.Int64("roster_spots", merge.RosterSpots),
+		zap.Int64("invitations", merge.Invitations),
+	)
+	return &UserMergeResult{SourceUserID: sourceID, TargetUserID: targetID, UserMerge: *merge}, nil
+}
@@ -1,48 +1,76 @@
 package service
 
 import (
+	"bufio"
 	"context"
-	"errors"
 	"fmt"
 	"io"
+	"net/mail"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+	"github.com/yourusername/golf_messenger/pkg/password"
 	"github.com/yourusername/golf_messenger/pkg/storage"
 )
 
+// avatarExtensions maps the accepted avatar content types to the extension
+// their objects are stored with.
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/jpg":  ".jpg",
+	"image/png":  ".png",
+}
+
 type UserService struct {
-	userRepo repository.UserRepository
-	s3Client *storage.S3Client
+	userRepo       repository.UserRepository
+	storage        storage.PublicStorage
+	securityEvents *SecurityEventService
+	passwords      password.Policy
+	cfg            config.AvatarConfig
 }
 
-func NewUserService(userRepo repository.UserRepository, s3Client *storage.S3Client) *UserService {
+func NewUserService(userRepo repository.UserRepository, storage storage.PublicStorage, securityEvents *SecurityEventService, passwords password.Policy, cfg config.AvatarConfig) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		s3Client: s3Client,
+		userRepo:       userRepo,
+		storage:        storage,
+		securityEvents: securityEvents,
+		passwords:      passwords,
+		cfg:            cfg,
 	}
 }
 
-func (s *UserService) GetProfile(userID uuid.UUID) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
+func (s *UserService) GetProfile(ctx context.Context, userID uuid.UUID) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
-	return user, nil
+	return NewUserProfile(user), nil
 }
 
-func (s *UserService) UpdateProfile(userID uuid.UUID, firstName, lastName string, handicap *float64, phone *string) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
+// TeeTimeRange is the span of tee times a user prefers. The zero value clears
+// the preference.
+type TeeTimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// UpdateProfile changes the profile fields that are set. An empty homeCourse
+// or bio clears it; a non-nil playingDays replaces the user's playing days.
+func (s *UserService) UpdateProfile(ctx context.Context, userID uuid.UUID, firstName, lastName string, handicap *float64, phone *string, preferredLanguage *string, homeCourse *string, bio *string, playingDays []string, teeTimes *TeeTimeRange) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if firstName != "" {
@@ -57,112 +85,487 @@ func (s *UserService) UpdateProfile(userID uuid.UUID, firstName, lastName string
 	if phone != nil {
 		user.Phone = phone
 	}
+	if preferredLanguage != nil {
+		lang := i18n.Base(*preferredLanguage)
+		if !i18n.Supported(lang) {
+			return nil, ErrUnsupportedLanguage
+		}
+		user.PreferredLanguage = &lang
+	}
+	if homeCourse != nil {
+		user.HomeCourse = optionalText(*homeCourse)
+	}
+	if bio != nil {
+		user.Bio = optionalText(*bio)
+	}
+	if teeTimes != nil {
+		if *teeTimes == (TeeTimeRange{}) {
+			user.PreferredTeeTimeStart, user.PreferredTeeTimeEnd = nil, nil
+		} else {
+			if !teeTimes.End.After(teeTimes.Start) {
+				return nil, ErrInvalidTeeTimeRange
+			}
+			start, end := teeTimes.Start, teeTimes.End
+			user.PreferredTeeTimeStart, user.PreferredTeeTimeEnd = &start, &end
+		}
+	}
 
-	if err := s.userRepo.Update(user); err != nil {
+	var days []string
+	if playingDays != nil {
+		if days, err = normalizeWeekdays(playingDays); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return user, nil
+	if playingDays != nil {
+		if err := s.userRepo.SetPlayingDays(ctx, userID, days); err != nil {
+			return nil, fmt.Errorf("failed to update playing days: %w", err)
+		}
+		user.PlayingDays = make([]models.UserPlayingDay, 0, len(days))
+		for _, day := range days {
+			user.PlayingDays = append(user.PlayingDays, models.UserPlayingDay{UserID: userID, Day: day})
+		}
+	}
+
+	return NewUserProfile(user), nil
+}
+
+// PrivacyUpdate changes the privacy settings that are set; nil fields are
+// kept.
+type PrivacyUpdate struct {
+	ShowHandicap         *bool
+	ShowPhone            *bool
+	ShowEmail            *bool
+	DiscoverableInSearch *bool
+}
+
+// UpdatePrivacy changes what the user lets other users see of them.
+func (s *UserService) UpdatePrivacy(ctx context.Context, userID uuid.UUID, update PrivacyUpdate) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if update.ShowHandicap != nil {
+		user.Privacy.HideHandicap = !*update.ShowHandicap
+	}
+	if update.ShowPhone != nil {
+		user.Privacy.ShowPhone = *update.ShowPhone
+	}
+	if update.ShowEmail != nil {
+		user.Privacy.ShowEmail = *update.ShowEmail
+	}
+	if update.DiscoverableInSearch != nil {
+		user.Privacy.HideFromSearch = !*update.DiscoverableInSearch
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update privacy settings: %w", err)
+	}
+	return NewUserProfile(user), nil
 }
 
-func (s *UserService) ChangePassword(userID uuid.UUID, oldPassword, newPassword string) error {
-	user, err := s.userRepo.FindByID(userID)
+// NotificationSettingsUpdate changes the notification settings that are set;
+// nil fields are kept.
+type NotificationSettingsUpdate struct {
+	WeeklyDigest *bool
+}
+
+// UpdateNotificationSettings changes which notifications the user gets
+// besides those in the app.
+func (s *UserService) UpdateNotificationSettings(ctx context.Context, userID uuid.UUID, update NotificationSettingsUpdate) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if update.WeeklyDigest != nil {
+		user.Notifications.NoWeeklyDigest = !*update.WeeklyDigest
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("failed to update notification settings: %w", err)
+	}
+	return NewUserProfile(user), nil
+}
+
+// CaptainedBy reports which of userIDs play on a TTR captainID captains or
+// co-captains. Captains always see those players' handicaps.
+func (s *UserService) CaptainedBy(ctx context.Context, captainID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ids, err := s.userRepo.FindCaptainedBy(ctx, captainID, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find captained users: %w", err)
+	}
+	captained := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		captained[id] = true
+	}
+	return captained, nil
+}
+
+func optionalText(text string) *string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return &text
+}
+
+// normalizeWeekdays lowercases and dedupes days and puts them in week order.
+func normalizeWeekdays(days []string) ([]string, error) {
+	selected := make(map[string]bool, len(days))
+	for _, day := range days {
+		selected[strings.ToLower(strings.TrimSpace(day))] = true
+	}
+
+	normalized := make([]string, 0, len(selected))
+	for _, weekday := range models.Weekdays {
+		if selected[weekday] {
+			normalized = append(normalized, weekday)
+			delete(selected, weekday)
+		}
+	}
+	if len(selected) > 0 {
+		return nil, ErrInvalidPlayingDay
+	}
+	return normalized, nil
+}
+
+// ChangePassword replaces the user's password, which is also how members who
+// must reset theirs choose one. A new password breaking the password policy
+// is a *PasswordPolicyError.
+func (s *UserService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	if !user.CheckPassword(oldPassword) {
-		return errors.New("invalid old password")
+		return ErrInvalidOldPassword
+	}
+	if err := checkPassword(s.passwords, newPassword, user.Email); err != nil {
+		return err
 	}
 
 	if err := user.SetPassword(newPassword); err != nil {
 		return fmt.Errorf("failed to set new password: %w", err)
 	}
+	user.MustResetPassword = false
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return nil
+	return s.securityEvents.Record(ctx, userID, models.SecurityEventPasswordChanged)
+}
+
+// AvatarMaxSize returns the largest avatar upload allowed, in bytes.
+func (s *UserService) AvatarMaxSize() int64 {
+	return s.cfg.MaxSize
 }
 
-func (s *UserService) UploadAvatar(ctx context.Context, userID uuid.UUID, file io.Reader, filename string, contentType string) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
+// UploadAvatar streams file to storage and makes it the user's avatar. The
+// upload fails with ErrAvatarTooLarge as soon as more than AvatarMaxSize
+// bytes have been read, and with ErrAvatarEmpty if file has no content.
+func (s *UserService) UploadAvatar(ctx context.Context, userID uuid.UUID, file io.Reader, contentType string) (*UserProfile, error) {
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedAvatarType
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
-	if user.AvatarURL != nil && *user.AvatarURL != "" {
-		if err := s.s3Client.DeleteFile(ctx, *user.AvatarURL); err != nil {
-			return nil, fmt.Errorf("failed to delete old avatar: %w", err)
+	body := bufio.NewReader(file)
+	if _, err := body.Peek(1); err != nil {
+		if err == io.EOF {
+			return nil, ErrAvatarEmpty
 		}
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
 	}
 
-	avatarURL, err := s.s3Client.UploadFile(ctx, file, filename, contentType)
-	if err != nil {
+	limited := &sizeLimitReader{r: io.LimitReader(body, s.cfg.MaxSize+1), max: s.cfg.MaxSize}
+	key := avatarKey(userID, ext)
+	if err := s.storage.PutObject(ctx, key, limited, contentType); err != nil {
+		if limited.exceeded {
+			return nil, ErrAvatarTooLarge
+		}
 		return nil, fmt.Errorf("failed to upload avatar: %w", err)
 	}
 
+	return s.setAvatar(ctx, user, key)
+}
+
+// PresignAvatarUpload returns a URL the client can PUT an avatar of size
+// bytes to directly, and the key to pass to CompleteAvatarUpload afterwards.
+func (s *UserService) PresignAvatarUpload(ctx context.Context, userID uuid.UUID, contentType string, size int64) (uploadURL, key string, err error) {
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", "", ErrUnsupportedAvatarType
+	}
+	if size <= 0 {
+		return "", "", ErrAvatarEmpty
+	}
+	if size > s.cfg.MaxSize {
+		return "", "", ErrAvatarTooLarge
+	}
+
+	key = avatarKey(userID, ext)
+	uploadURL, err = s.storage.PresignPutURL(ctx, key, contentType, size, s.cfg.UploadURLTTL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to presign avatar upload: %w", err)
+	}
+	return uploadURL, key, nil
+}
+
+// CompleteAvatarUpload makes an object uploaded through PresignAvatarUpload
+// the user's avatar, once it has checked the object's size.
+func (s *UserService) CompleteAvatarUpload(ctx context.Context, userID uuid.UUID, key string) (*UserProfile, error) {
+	if !strings.HasPrefix(key, fmt.Sprintf("avatars/%s/", userID)) {
+		return nil, ErrInvalidAvatarUploadKey
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	size, err := s.storage.ObjectSize(ctx, key)
+	if err != nil {
+		return nil, ErrAvatarUploadNotFound
+	}
+	if size == 0 || size > s.cfg.MaxSize {
+		if delErr := s.storage.DeleteObject(ctx, key); delErr != nil {
+			return nil, fmt.Errorf("failed to delete rejected avatar: %w", delErr)
+		}
+		if size == 0 {
+			return nil, ErrAvatarEmpty
+		}
+		return nil, ErrAvatarTooLarge
+	}
+
+	return s.setAvatar(ctx, user, key)
+}
+
+// setAvatar points the user at the avatar stored under key and then removes
+// the previous one, so a failed upload never leaves the user without one.
+func (s *UserService) setAvatar(ctx context.Context, user *models.User, key string) (*UserProfile, error) {
+	oldAvatarURL := user.AvatarURL
+	avatarURL := s.storage.ObjectURL(key)
 	user.AvatarURL = &avatarURL
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user with avatar URL: %w", err)
 	}
 
-	return user, nil
+	if oldAvatarURL != nil && *oldAvatarURL != "" && *oldAvatarURL != avatarURL {
+		if err := s.storage.DeleteFile(ctx, *oldAvatarURL); err != nil {
+			return nil, fmt.Errorf("failed to delete old avatar: %w", err)
+		}
+	}
+
+	return NewUserProfile(user), nil
 }
 
-func (s *UserService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
+func avatarKey(userID uuid.UUID, ext string) string {
+	return fmt.Sprintf("avatars/%s/%s%s", userID, uuid.New(), ext)
+}
+
+// sizeLimitReader fails once more than max bytes have been read from r, which
+// should be limited to max+1 bytes so an oversized body is never read fully.
+type sizeLimitReader struct {
+	r        io.Reader
+	max      int64
+	read     int64
+	exceeded bool
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		l.exceeded = true
+		return n, ErrAvatarTooLarge
+	}
+	return n, err
+}
+
+func (s *UserService) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
 	if user.AvatarURL != nil && *user.AvatarURL != "" {
-		if err := s.s3Client.DeleteFile(ctx, *user.AvatarURL); err != nil {
+		if err := s.storage.DeleteFile(ctx, *user.AvatarURL); err != nil {
 			return nil, fmt.Errorf("failed to delete avatar from S3: %w", err)
 		}
 	}
 
 	user.AvatarURL = nil
 
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return user, nil
+	return NewUserProfile(user), nil
 }
 
-func (s *UserService) SearchUsers(query string, limit, offset int) ([]*models.User, error) {
+// SearchUsers finds users by name fragment, or by exact address when the
+// query is a full email, so the directory can't be enumerated by domain.
+// Users who aren't discoverable in search are only found by email.
+// excludeUserID and excludeTTRID drop the caller and users already on a TTR;
+// pass uuid.Nil to skip either.
+func (s *UserService) SearchUsers(ctx context.Context, query string, excludeUserID uuid.UUID, excludeTTRID uuid.UUID, limit, offset int) ([]UserSummary, error) {
 	query = strings.TrimSpace(query)
 	if query == "" {
-		return []*models.User{}, nil
+		return []UserSummary{}, nil
 	}
 
-	users, err := s.userRepo.Search(query, limit, offset)
+	filter := repository.UserSearchFilter{
+		ExcludeUserID: excludeUserID,
+		ExcludeTTRID:  excludeTTRID,
+		Limit:         limit,
+		Offset:        offset,
+	}
+	if isEmailAddress(query) {
+		filter.Email = query
+	} else {
+		if len([]rune(query)) < 3 {
+			return nil, ErrSearchQueryTooShort
+		}
+		filter.Name = query
+	}
+
+	users, err := s.userRepo.Search(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search users: %w", err)
 	}
 
-	return users, nil
+	return newUserSummaries(users), nil
 }
 
-func (s *UserService) GetUserByID(userID uuid.UUID) (*models.User, error) {
-	user, err := s.userRepo.FindByID(userID)
+func isEmailAddress(query string) bool {
+	addr, err := mail.ParseAddress(query)
+	return err == nil && addr.Address == query
+}
+
+func (s *UserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*UserProfile, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
+	}
+	return NewUserProfile(user), nil
+}
+
+// BlockUser adds blockedID to the user's block list, so invitations to the
+// user's TTRs can't be forwarded to them. Blocking someone twice is fine.
+func (s *UserService) BlockUser(ctx context.Context, userID uuid.UUID, blockedID uuid.UUID) error {
+	if blockedID == userID {
+		return ErrCannotBlockSelf
+	}
+	if _, err := s.GetUserByID(ctx, blockedID); err != nil {
+		return err
+	}
+	if err := s.userRepo.Block(ctx, userID, blockedID); err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	return nil
+}
+
+// UnblockUser takes blockedID off the user's block list.
+func (s *UserService) UnblockUser(ctx context.Context, userID uuid.UUID, blockedID uuid.UUID) error {
+	removed, err := s.userRepo.Unblock(ctx, userID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+	if !removed {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// GetBlockedUsers lists the user's block list, most recently blocked first.
+func (s *UserService) GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]UserSummary, error) {
+	users, err := s.userRepo.FindBlocked(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked users: %w", err)
+	}
+	return newUserSummaries(users), nil
+}
+
+// RequestFriend sends otherID a friend request, or accepts the one otherID
+// sent the user. It reports whether the two are now friends. Requesting
+// again, or befriending a friend, changes nothing.
+func (s *UserService) RequestFriend(ctx context.Context, userID uuid.UUID, otherID uuid.UUID) (bool, error) {
+	if otherID == userID {
+		return false, ErrCannotBefriendSelf
+	}
+	if _, err := s.GetUserByID(ctx, otherID); err != nil {
+		return false, err
+	}
+	friendship, err := s.userRepo.RequestFriendship(ctx, userID, otherID, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to request friendship: %w", err)
+	}
+	return friendship.Accepted(), nil
+}
+
+// RemoveFriend ends the user's friendship with otherID, or withdraws or
+// declines the friend request between them.
+func (s *UserService) RemoveFriend(ctx context.Context, userID uuid.UUID, otherID uuid.UUID) error {
+	removed, err := s.userRepo.RemoveFriendship(ctx, userID, otherID)
+	if err != nil {
+		return fmt.Errorf("failed to remove friend: %w", err)
+	}
+	if !removed {
+		return ErrFriendshipNotFound
+	}
+	return nil
+}
+
+// GetFriends lists the user's friends, most recent friendship first.
+func (s *UserService) GetFriends(ctx context.Context, userID uuid.UUID) ([]UserSummary, error) {
+	users, err := s.userRepo.FindFriends(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+	return newUserSummaries(users), nil
+}
+
+// GetFriendRequests lists the users waiting for the user to accept their
+// friend requests, most recent request first.
+func (s *UserService) GetFriendRequests(ctx context.Context, userID uuid.UUID) ([]UserSummary, error) {
+	users, err := s.userRepo.FindFriendRequests(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friend requests: %w", err)
 	}
-	return user, nil
+	return newUserSummaries(users), nil
 }
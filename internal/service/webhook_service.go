@@ -0,0 +1,499 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Headers sent with every webhook delivery. WebhookSignatureHeader carries
+// WebhookSignature of the body; WebhookDeliveryHeader is the event's ID,
+// which stays the same across retries.
+const (
+	WebhookSignatureHeader = "X-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// webhookQueueSize is how many events can wait for delivery before Publish
+// starts dropping them.
+const webhookQueueSize = 256
+
+// WebhookPayload is the JSON body POSTed to webhooks. Data is a
+// WebhookTTRData for ttr.* events and a WebhookInvitationData for
+// invitation.responded.
+type WebhookPayload struct {
+	ID        uuid.UUID   `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+type WebhookTTRData struct {
+	ID             uuid.UUID        `json:"id"`
+	CourseName     string           `json:"course_name"`
+	CourseLocation *string          `json:"course_location,omitempty"`
+	TeeDate        string           `json:"tee_date"`
+	TeeTime        string           `json:"tee_time"`
+	Status         models.TTRStatus `json:"status"`
+	MaxPlayers     int              `json:"max_players"`
+	CaptainUserID  uuid.UUID        `json:"captain_user_id"`
+	OrganizationID *uuid.UUID       `json:"organization_id,omitempty"`
+}
+
+type WebhookInvitationData struct {
+	ID            uuid.UUID               `json:"id"`
+	InviteeUserID uuid.UUID               `json:"invitee_user_id"`
+	Status        models.InvitationStatus `json:"status"`
+	TTR           WebhookTTRData          `json:"ttr"`
+}
+
+func newWebhookTTRData(ttr *models.TTR) WebhookTTRData {
+	return WebhookTTRData{
+		ID:             ttr.ID,
+		CourseName:     ttr.CourseName,
+		CourseLocation: ttr.CourseLocation,
+		TeeDate:        ttr.TeeDate.Format("2006-01-02"),
+		TeeTime:        ttr.TeeTime.Format("15:04"),
+		Status:         ttr.Status,
+		MaxPlayers:     ttr.MaxPlayers,
+		CaptainUserID:  ttr.CaptainUserID,
+		OrganizationID: ttr.OrganizationID,
+	}
+}
+
+// WebhookSignature is the hex HMAC-SHA256 of body keyed with the webhook's
+// secret, as sent in WebhookSignatureHeader.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEvent is an event on its way to the webhooks of a TTR. It is also
+// the outbox payload of webhook messages. Body is the encoded
+// WebhookPayload, which is what gets signed.
+type webhookEvent struct {
+	CaptainID uuid.UUID       `json:"captain_id"`
+	OrgID     *uuid.UUID      `json:"organization_id,omitempty"`
+	ID        uuid.UUID       `json:"id"`
+	Event     string          `json:"event"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// WebhookService manages webhooks and delivers events to them. Publishing
+// only queues an event; Run delivers the queue in the background. With an
+// outbox, events go through it first, so one published in a transaction is
+// only delivered if it commits and isn't lost if the process stops before
+// queuing it. A nil *WebhookService publishes nothing, so services can run
+// without one.
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	authorizer  *Authorizer
+	outbox      *OutboxService
+	cfg         config.WebhooksConfig
+	client      *http.Client
+	queue       chan webhookEvent
+	logger      *zap.Logger
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, authorizer *Authorizer, outbox *OutboxService, cfg config.WebhooksConfig, logger *zap.Logger) *WebhookService {
+	s := &WebhookService{
+		webhookRepo: webhookRepo,
+		authorizer:  authorizer,
+		outbox:      outbox,
+		cfg:         cfg,
+		client: &http.Client{
+			// A redirect is a failed delivery, not a new target.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queue:  make(chan webhookEvent, webhookQueueSize),
+		logger: logger,
+	}
+	if outbox != nil {
+		outbox.Handle(models.OutboxKindWebhook, s.sendOutboxMessage)
+	}
+	return s
+}
+
+// CreateWebhook creates a webhook for userID or, when orgID is set, for the
+// organization, which userID must administer. The returned webhook carries
+// its generated secret, which is not shown again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID, targetURL string, events []string) (*models.Webhook, error) {
+	if orgID != nil {
+		if err := s.checkOrganizationAdmin(ctx, *orgID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := validateWebhookURL(targetURL); err != nil {
+		return nil, err
+	}
+	eventList, err := normalizeWebhookEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &models.Webhook{
+		OwnerUserID:    userID,
+		OrganizationID: orgID,
+		URL:            targetURL,
+		Secret:         secret,
+		Events:         eventList,
+		Status:         models.WebhookStatusActive,
+	}
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks lists userID's own webhooks or, when orgID is set, the
+// organization's, which userID must administer.
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID) ([]*models.Webhook, error) {
+	if orgID == nil {
+		return s.webhookRepo.FindByOwner(ctx, userID)
+	}
+	if err := s.checkOrganizationAdmin(ctx, *orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindByOrganization(ctx, *orgID)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Webhook, error) {
+	return s.managedWebhook(ctx, id, userID)
+}
+
+// UpdateWebhook changes the webhook's URL and events. Any update puts a
+// FAILING webhook back to ACTIVE.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID, targetURL *string, events []string) (*models.Webhook, error) {
+	webhook, err := s.managedWebhook(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetURL != nil {
+		if err := validateWebhookURL(*targetURL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *targetURL
+	}
+	if events != nil {
+		eventList, err := normalizeWebhookEvents(events)
+		if err != nil {
+			return nil, err
+		}
+		webhook.Events = eventList
+	}
+	webhook.Status = models.WebhookStatusActive
+	webhook.ConsecutiveFailures = 0
+
+	if err := s.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook deletes the webhook with its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	if _, err := s.managedWebhook(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of the webhook's delivery attempts, newest
+// first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id uuid.UUID, userID uuid.UUID, limit int, offset int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.managedWebhook(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.FindDeliveries(ctx, id, limit, offset)
+}
+
+// managedWebhook loads a webhook userID may manage. Other users' webhooks,
+// and those of organizations userID isn't a member of, look missing.
+func (s *WebhookService) managedWebhook(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook: %w", err)
+	}
+	if webhook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	if webhook.OrganizationID == nil {
+		if webhook.OwnerUserID != userID {
+			return nil, ErrWebhookNotFound
+		}
+		return webhook, nil
+	}
+	if err := s.checkOrganizationAdmin(ctx, *webhook.OrganizationID, userID); err != nil {
+		if err == ErrOrganizationNotFound {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) checkOrganizationAdmin(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	member, err := s.authorizer.OrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if member == nil {
+		return ErrOrganizationNotFound
+	}
+	if !member.CanAdminister() {
+		return ErrNotAdminWebhooks
+	}
+	return nil
+}
+
+func validateWebhookURL(targetURL string) error {
+	parsed, err := url.Parse(targetURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+// normalizeWebhookEvents checks events and joins them, without duplicates,
+// into models.Webhook's Events format.
+func normalizeWebhookEvents(events []string) (string, error) {
+	known := make(map[string]bool, len(models.WebhookEvents))
+	for _, event := range models.WebhookEvents {
+		known[event] = true
+	}
+
+	seen := make(map[string]bool, len(events))
+	list := make([]string, 0, len(events))
+	for _, event := range events {
+		if !known[event] {
+			return "", ErrInvalidWebhookEvent
+		}
+		if !seen[event] {
+			seen[event] = true
+			list = append(list, event)
+		}
+	}
+	if len(list) == 0 {
+		return "", ErrInvalidWebhookEvent
+	}
+	return strings.Join(list, ","), nil
+}
+
+func newWebhookSecret() (string, error) {
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secretBytes), nil
+}
+
+// PublishTTR queues a ttr.* event about ttr for the webhooks of its captain
+// and organization. With an outbox, the event is written to it in the
+// transaction ctx carries, if any.
+func (s *WebhookService) PublishTTR(ctx context.Context, event string, ttr *models.TTR) error {
+	if s == nil {
+		return nil
+	}
+	return s.publish(ctx, event, ttr, newWebhookTTRData(ttr))
+}
+
+// PublishInvitationResponse queues an invitation.responded event for the
+// webhooks of the invitation's TTR, like PublishTTR.
+func (s *WebhookService) PublishInvitationResponse(ctx context.Context, invitation *models.Invitation, ttr *models.TTR) error {
+	if s == nil {
+		return nil
+	}
+	return s.publish(ctx, models.WebhookEventInvitationResponded, ttr, WebhookInvitationData{
+		ID:            invitation.ID,
+		InviteeUserID: invitation.InviteeUserID,
+		Status:        invitation.Status,
+		TTR:           newWebhookTTRData(ttr),
+	})
+}
+
+func (s *WebhookService) publish(ctx context.Context, event string, ttr *models.TTR, data interface{}) error {
+	payload := WebhookPayload{
+		ID:        uuid.New(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	queued := webhookEvent{
+		CaptainID: ttr.CaptainUserID,
+		OrgID:     ttr.OrganizationID,
+		ID:        payload.ID,
+		Event:     event,
+		Body:      body,
+	}
+	if s.outbox != nil {
+		return s.outbox.Enqueue(ctx, models.OutboxKindWebhook, queued)
+	}
+
+	select {
+	case s.queue <- queued:
+	default:
+		s.logger.Warn("Webhook queue is full, dropping event", zap.String("event", event), zap.String("ttr_id", ttr.ID.String()))
+	}
+	return nil
+}
+
+// sendOutboxMessage hands a webhook event from the outbox to Run. While the
+// queue is full it fails, and the outbox tries again later.
+func (s *WebhookService) sendOutboxMessage(ctx context.Context, payload []byte) error {
+	var event webhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to decode webhook message: %w", err)
+	}
+	select {
+	case s.queue <- event:
+		return nil
+	default:
+		return fmt.Errorf("webhook queue is full")
+	}
+}
+
+// Run delivers queued events until ctx is canceled, then waits for the
+// deliveries in flight. Those stop retrying once ctx is canceled.
+func (s *WebhookService) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-s.queue:
+			webhooks, err := s.webhookRepo.FindActiveForTTR(ctx, event.CaptainID, event.OrgID)
+			if err != nil {
+				s.logger.Error("Failed to find webhooks", zap.String("event", event.Event), zap.Error(err))
+				continue
+			}
+			for _, webhook := range webhooks {
+				if !webhook.Subscribes(event.Event) {
+					continue
+				}
+				wg.Add(1)
+				go func(webhook *models.Webhook) {
+					defer wg.Done()
+					s.deliver(ctx, webhook, event)
+				}(webhook)
+			}
+		}
+	}
+}
+
+// deliver POSTs the event's body to the webhook until an attempt succeeds or
+// cfg.MaxAttempts attempts failed, doubling the wait between attempts from
+// cfg.InitialBackoff. Every attempt is recorded, and the outcome counts
+// towards marking the webhook FAILING.
+func (s *WebhookService) deliver(ctx context.Context, webhook *models.Webhook, event webhookEvent) {
+	// Attempts made while shutting down are still recorded.
+	recordCtx := context.WithoutCancel(ctx)
+	maxAttempts := max(s.cfg.MaxAttempts, 1)
+	backoff := s.cfg.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		delivery := s.attempt(ctx, webhook, event, attempt)
+		if err := s.webhookRepo.CreateDelivery(recordCtx, delivery); err != nil {
+			s.logger.Error("Failed to record webhook delivery", zap.String("webhook_id", webhook.ID.String()), zap.Error(err))
+		}
+		if delivery.Succeeded || attempt >= maxAttempts {
+			if err := s.webhookRepo.RecordResult(recordCtx, webhook.ID, delivery.Succeeded, s.cfg.FailingAfter); err != nil {
+				s.logger.Error("Failed to record webhook result", zap.String("webhook_id", webhook.ID.String()), zap.Error(err))
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (s *WebhookService) attempt(ctx context.Context, webhook *models.Webhook, event webhookEvent, attempt int) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		Event:     event.Event,
+		Attempt:   attempt,
+	}
+
+	started := time.Now()
+	statusCode, err := s.post(ctx, webhook, event)
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.StatusCode = statusCode
+	if err != nil {
+		message := err.Error()
+		delivery.Error = &message
+	} else {
+		delivery.Succeeded = true
+	}
+	return delivery
+}
+
+// post sends one attempt. Any answer other than a 2xx is a failure.
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, event webhookEvent) (*int, error) {
+	if s.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(event.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event.Event)
+	req.Header.Set(WebhookDeliveryHeader, event.ID.String())
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(webhook.Secret, event.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		return &statusCode, fmt.Errorf("endpoint answered %d", statusCode)
+	}
+	return &statusCode, nil
+}
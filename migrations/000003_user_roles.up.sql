@@ -0,0 +1,2 @@
+-- Add role column to users for admin-only endpoints
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'USER';
@@ -0,0 +1,2 @@
+ALTER TABLE ttr_players DROP COLUMN IF EXISTS group_number;
+ALTER TABLE ttr_players DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,3 @@
+-- Per-player notes and pairing groups on the roster; group 0 means unassigned
+ALTER TABLE ttr_players ADD COLUMN notes TEXT;
+ALTER TABLE ttr_players ADD COLUMN group_number INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ttrs_rsvp_deadline;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS rsvp_closed_at;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS rsvp_deadline;
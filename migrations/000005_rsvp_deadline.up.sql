@@ -0,0 +1,4 @@
+-- Optional RSVP deadline; rsvp_closed_at records when the expiry job processed it
+ALTER TABLE ttrs ADD COLUMN rsvp_deadline TIMESTAMP NULL;
+ALTER TABLE ttrs ADD COLUMN rsvp_closed_at TIMESTAMP NULL;
+CREATE INDEX idx_ttrs_rsvp_deadline ON ttrs(rsvp_deadline);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ttr_message_reads;
+DROP TABLE IF EXISTS ttr_messages;
@@ -0,0 +1,18 @@
+CREATE TABLE ttr_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ttr_id UUID NOT NULL REFERENCES ttrs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ttr_messages_ttr_created ON ttr_messages(ttr_id, created_at);
+
+-- One row per user per TTR; messages after last_read_at are unread
+CREATE TABLE ttr_message_reads (
+    ttr_id UUID REFERENCES ttrs(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    last_read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (ttr_id, user_id)
+);
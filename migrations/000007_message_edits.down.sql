@@ -0,0 +1,2 @@
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS deleted;
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,3 @@
+-- Deleted messages keep their row (with the body cleared) so the thread doesn't renumber
+ALTER TABLE ttr_messages ADD COLUMN edited_at TIMESTAMP NULL;
+ALTER TABLE ttr_messages ADD COLUMN deleted BOOLEAN NOT NULL DEFAULT FALSE;
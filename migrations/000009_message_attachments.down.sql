@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_ttr_messages_user;
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS attachment_size;
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS attachment_content_type;
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS attachment_name;
+ALTER TABLE ttr_messages DROP COLUMN IF EXISTS attachment_key;
@@ -0,0 +1,7 @@
+-- attachment_key stays set on deleted messages until the cleanup job removes the object
+ALTER TABLE ttr_messages ADD COLUMN attachment_key VARCHAR(512);
+ALTER TABLE ttr_messages ADD COLUMN attachment_name VARCHAR(255);
+ALTER TABLE ttr_messages ADD COLUMN attachment_content_type VARCHAR(100);
+ALTER TABLE ttr_messages ADD COLUMN attachment_size BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_ttr_messages_user ON ttr_messages(user_id);
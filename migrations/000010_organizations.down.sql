@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_ttrs_organization;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_ttrs_league;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS league_id;
+
+DROP TABLE IF EXISTS leagues;
+
+ALTER TABLE ttr_players DROP COLUMN IF EXISTS score;
@@ -0,0 +1,23 @@
+-- Gross strokes for the round; NULL until the captain records it
+ALTER TABLE ttr_players ADD COLUMN score INTEGER;
+
+CREATE TABLE leagues (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    owner_user_id UUID NOT NULL REFERENCES users(id),
+    organization_id UUID REFERENCES organizations(id),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    scoring_scheme VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_leagues_owner ON leagues(owner_user_id);
+CREATE INDEX idx_leagues_organization ON leagues(organization_id);
+CREATE INDEX idx_leagues_deleted_at ON leagues(deleted_at);
+
+-- A TTR counts towards at most one league
+ALTER TABLE ttrs ADD COLUMN league_id UUID REFERENCES leagues(id);
+CREATE INDEX idx_ttrs_league ON ttrs(league_id);
@@ -0,0 +1,33 @@
+CREATE TABLE tournaments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    owner_user_id UUID NOT NULL REFERENCES users(id),
+    ttr_id UUID REFERENCES ttrs(id),
+    status VARCHAR(50) DEFAULT 'IN_PROGRESS',
+    winner_user_id UUID REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_tournaments_owner ON tournaments(owner_user_id);
+CREATE INDEX idx_tournaments_deleted_at ON tournaments(deleted_at);
+
+CREATE TABLE tournament_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tournament_id UUID NOT NULL REFERENCES tournaments(id) ON DELETE CASCADE,
+    round INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    player1_user_id UUID REFERENCES users(id),
+    player2_user_id UUID REFERENCES users(id),
+    winner_user_id UUID REFERENCES users(id),
+    status VARCHAR(50) NOT NULL,
+    walkover BOOLEAN NOT NULL DEFAULT FALSE,
+    ttr_id UUID REFERENCES ttrs(id),
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (tournament_id, round, position)
+);
+
+CREATE INDEX idx_tournament_matches_tournament ON tournament_matches(tournament_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_normalized_email;
+ALTER TABLE users DROP COLUMN IF EXISTS normalized_email;
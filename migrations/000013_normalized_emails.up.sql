@@ -0,0 +1,29 @@
+-- Accounts are looked up by a normalized copy of their email (trimmed and
+-- lowercased, matching the default ACCOUNTS_LOWERCASE_EMAIL_LOCAL_PART=true)
+ALTER TABLE users ADD COLUMN normalized_email VARCHAR(255);
+UPDATE users SET normalized_email = LOWER(TRIM(email));
+
+-- Accounts whose emails only differ in case would now share a key. Stop and
+-- list them rather than merging anything; they have to be resolved by hand.
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(normalized_email || ': ' || emails, '; ' ORDER BY normalized_email)
+    INTO collisions
+    FROM (
+        SELECT normalized_email, string_agg(email, ', ' ORDER BY email) AS emails
+        FROM users
+        GROUP BY normalized_email
+        HAVING COUNT(*) > 1
+    ) grouped;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'emails collide once normalized: %', collisions;
+    END IF;
+END $$;
+
+ALTER TABLE users ALTER COLUMN normalized_email SET NOT NULL;
+CREATE UNIQUE INDEX idx_users_normalized_email ON users(normalized_email);
+
+UPDATE organization_invitations SET email = LOWER(TRIM(email));
@@ -0,0 +1,2 @@
+-- Language for notifications sent to the user; NULL means the default (en)
+ALTER TABLE users ADD COLUMN preferred_language VARCHAR(10) NULL;
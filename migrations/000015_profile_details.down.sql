@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_playing_days;
+
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_tee_time_end;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_tee_time_start;
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
+ALTER TABLE users DROP COLUMN IF EXISTS home_course;
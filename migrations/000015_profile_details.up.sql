@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN home_course VARCHAR(200) NULL;
+ALTER TABLE users ADD COLUMN bio VARCHAR(500) NULL;
+ALTER TABLE users ADD COLUMN preferred_tee_time_start TIME NULL;
+ALTER TABLE users ADD COLUMN preferred_tee_time_end TIME NULL;
+
+-- One row per weekday a user usually plays, so players can be matched by day
+CREATE TABLE user_playing_days (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day VARCHAR(9) NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
+
+CREATE INDEX idx_user_playing_days_day ON user_playing_days(day);
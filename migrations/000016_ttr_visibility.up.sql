@@ -0,0 +1,5 @@
+ALTER TABLE ttrs ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'PRIVATE';
+
+-- TTRs created before visibility existed were listed for everyone, so keep
+-- them discoverable
+UPDATE ttrs SET visibility = 'PUBLIC';
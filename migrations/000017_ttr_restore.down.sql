@@ -0,0 +1 @@
+ALTER TABLE ttrs DROP COLUMN IF EXISTS pre_delete_status;
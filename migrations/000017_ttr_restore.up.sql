@@ -0,0 +1,2 @@
+-- Status a TTR had before it was deleted, so restoring it can put it back
+ALTER TABLE ttrs ADD COLUMN pre_delete_status VARCHAR(50);
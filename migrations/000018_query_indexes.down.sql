@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_user_read_created;
+DROP INDEX IF EXISTS idx_ttrs_status_tee_date;
+DROP INDEX IF EXISTS idx_invitations_ttr_invitee_status;
+DROP INDEX IF EXISTS idx_ttr_co_captains_user_ttr;
+DROP INDEX IF EXISTS idx_ttr_players_user_ttr;
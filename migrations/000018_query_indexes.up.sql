@@ -0,0 +1,22 @@
+-- No earlier migration created the notifications table
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id),
+    type VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    target_type VARCHAR(50),
+    target_id UUID,
+    is_read BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP NULL
+);
+
+-- The primary keys lead with ttr_id, so looking up a user's rounds needs
+-- user_id first
+CREATE INDEX idx_ttr_players_user_ttr ON ttr_players(user_id, ttr_id);
+CREATE INDEX idx_ttr_co_captains_user_ttr ON ttr_co_captains(user_id, ttr_id);
+
+CREATE INDEX idx_invitations_ttr_invitee_status ON invitations(ttr_id, invitee_user_id, status);
+CREATE INDEX idx_ttrs_status_tee_date ON ttrs(status, tee_date);
+CREATE INDEX idx_notifications_user_read_created ON notifications(user_id, is_read, created_at);
@@ -0,0 +1,15 @@
+-- Keep only the oldest pending invitation per user and TTR so the index can
+-- be built
+UPDATE invitations SET status = 'CANCELED'
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY ttr_id, invitee_user_id ORDER BY created_at, id) AS n
+        FROM invitations
+        WHERE status = 'PENDING'
+    ) duplicates
+    WHERE n > 1
+);
+
+-- A user can have one pending invitation per TTR; answered ones don't count,
+-- so declining and being invited again still works
+CREATE UNIQUE INDEX idx_invitations_pending ON invitations(ttr_id, invitee_user_id) WHERE status = 'PENDING';
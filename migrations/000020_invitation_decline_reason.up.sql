@@ -0,0 +1,2 @@
+-- Optional note from an invitee answering NO or MAYBE, shown to the captain
+ALTER TABLE invitations ADD COLUMN decline_reason VARCHAR(280);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_digest;
+ALTER TABLE notifications DROP COLUMN IF EXISTS event_count;
@@ -0,0 +1,3 @@
+-- Number of events a digest notification stands for; 1 for a plain one
+ALTER TABLE notifications ADD COLUMN event_count INTEGER NOT NULL DEFAULT 1;
+CREATE INDEX idx_notifications_digest ON notifications(user_id, type, target_type, target_id, created_at);
@@ -0,0 +1,14 @@
+-- Shareable links that let the first max_uses users join a TTR
+CREATE TABLE invite_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ttr_id UUID NOT NULL REFERENCES ttrs(id) ON DELETE CASCADE,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    created_by_user_id UUID NOT NULL REFERENCES users(id),
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0 CHECK (uses <= max_uses),
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_invite_links_ttr_id ON invite_links(ttr_id);
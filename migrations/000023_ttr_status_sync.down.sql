@@ -0,0 +1,2 @@
+ALTER TABLE ttrs DROP COLUMN IF EXISTS status_locked;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS min_players;
@@ -0,0 +1,5 @@
+-- Confirmed players a TTR needs to stay CONFIRMED, and whether the captain set
+-- the status by hand so roster changes leave it alone
+ALTER TABLE ttrs ADD COLUMN min_players INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE ttrs ADD COLUMN status_locked BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE ttrs SET min_players = max_players WHERE max_players < min_players;
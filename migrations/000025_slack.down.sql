@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS slack_link_codes;
+DROP TABLE IF EXISTS slack_accounts;
@@ -0,0 +1,20 @@
+-- Slack users linked to accounts for the /golf slash command, and the
+-- one-time codes that link them
+CREATE TABLE slack_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    team_id VARCHAR(32) NOT NULL,
+    slack_user_id VARCHAR(32) NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_slack_accounts_team_user ON slack_accounts(team_id, slack_user_id);
+CREATE INDEX idx_slack_accounts_user_id ON slack_accounts(user_id);
+
+CREATE TABLE slack_link_codes (
+    code VARCHAR(16) PRIMARY KEY,
+    team_id VARCHAR(32) NOT NULL,
+    slack_user_id VARCHAR(32) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,5 @@
+-- Scopes granted by each API token. Tokens created before scopes existed
+-- keep the access they had: every scope.
+ALTER TABLE api_tokens ADD COLUMN scopes VARCHAR(512) NOT NULL DEFAULT '';
+
+UPDATE api_tokens SET scopes = 'read:profile,write:profile,read:ttrs,write:ttrs,read:invitations,write:invitations,read:messages,write:messages,read:organizations,write:organizations,read:leagues,write:leagues,read:tournaments,write:tournaments,read:webhooks,write:webhooks,admin';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS impersonation_sessions;
+DROP TABLE IF EXISTS audit_log_entries;
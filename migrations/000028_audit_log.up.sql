@@ -0,0 +1,33 @@
+-- Audit log, starting with admins impersonating users, and the
+-- impersonation sessions themselves. Audit entries have no foreign keys so
+-- they outlive the users they mention.
+CREATE TABLE audit_log_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    action VARCHAR(64) NOT NULL,
+    actor_user_id UUID NOT NULL,
+    subject_user_id UUID,
+    session_id UUID,
+    details TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_log_entries_action ON audit_log_entries(action);
+CREATE INDEX idx_audit_log_entries_actor_user_id ON audit_log_entries(actor_user_id);
+CREATE INDEX idx_audit_log_entries_subject_user_id ON audit_log_entries(subject_user_id);
+CREATE INDEX idx_audit_log_entries_session_id ON audit_log_entries(session_id);
+CREATE INDEX idx_audit_log_entries_created_at ON audit_log_entries(created_at);
+
+CREATE TABLE impersonation_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    allow_writes BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_impersonation_sessions_actor_user_id ON impersonation_sessions(actor_user_id);
+CREATE INDEX idx_impersonation_sessions_target_user_id ON impersonation_sessions(target_user_id);
@@ -0,0 +1,12 @@
+-- Feature flags managed at runtime; a row replaces the config rollout of the
+-- same flag
+CREATE TABLE feature_flags (
+    key VARCHAR(64) PRIMARY KEY,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    percentage INTEGER NOT NULL DEFAULT 0 CHECK (percentage BETWEEN 0 AND 100),
+    user_ids TEXT NOT NULL DEFAULT '',
+    organization_ids TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,14 @@
+-- Per-TTR change feed. Sequence numbers are assigned per TTR and serve as
+-- the clients' cursor; old events are purged after the retention window.
+CREATE TABLE ttr_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ttr_id UUID NOT NULL REFERENCES ttrs(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    actor_user_id UUID,
+    data TEXT NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_ttr_events_ttr_sequence ON ttr_events(ttr_id, sequence);
+CREATE INDEX idx_ttr_events_created_at ON ttr_events(created_at);
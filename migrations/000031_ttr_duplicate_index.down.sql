@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_ttrs_captain_tee_date;
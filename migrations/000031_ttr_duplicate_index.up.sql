@@ -0,0 +1,2 @@
+-- Duplicate detection looks up a captain's TTRs around a tee date
+CREATE INDEX idx_ttrs_captain_tee_date ON ttrs(captain_user_id, tee_date);
@@ -0,0 +1,2 @@
+ALTER TABLE ttrs DROP COLUMN IF EXISTS check_in_summary_at;
+ALTER TABLE ttr_players DROP COLUMN IF EXISTS checked_in_at;
@@ -0,0 +1,4 @@
+-- When each player checked in on the day, and when the captain was sent the
+-- check-in summary for the TTR
+ALTER TABLE ttr_players ADD COLUMN checked_in_at TIMESTAMP;
+ALTER TABLE ttrs ADD COLUMN check_in_summary_at TIMESTAMP;
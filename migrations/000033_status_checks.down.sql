@@ -0,0 +1,7 @@
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS chk_invitations_status;
+ALTER TABLE ttr_players DROP CONSTRAINT IF EXISTS chk_ttr_players_status;
+ALTER TABLE ttrs DROP CONSTRAINT IF EXISTS chk_ttrs_pre_delete_status;
+ALTER TABLE ttrs DROP CONSTRAINT IF EXISTS chk_ttrs_status;
+ALTER TABLE invitations ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE ttr_players ALTER COLUMN status DROP NOT NULL;
+ALTER TABLE ttrs ALTER COLUMN status DROP NOT NULL;
@@ -0,0 +1,43 @@
+-- Status columns only take the values the API knows. Stop and list the rows
+-- that don't rather than guessing what they meant; fix them by hand first.
+DO $$
+DECLARE
+    offending TEXT;
+BEGIN
+    SELECT string_agg(row_ref, '; ' ORDER BY row_ref)
+    INTO offending
+    FROM (
+        SELECT 'ttrs ' || id || ' status ' || quote_nullable(status) AS row_ref
+        FROM ttrs
+        WHERE status IS NULL OR status NOT IN ('OPEN', 'CONFIRMED', 'CANCELLED', 'COMPLETED')
+        UNION ALL
+        SELECT 'ttrs ' || id || ' pre_delete_status ' || quote_nullable(pre_delete_status)
+        FROM ttrs
+        WHERE pre_delete_status NOT IN ('OPEN', 'CONFIRMED', 'CANCELLED', 'COMPLETED')
+        UNION ALL
+        SELECT 'ttr_players ' || ttr_id || '/' || user_id || ' status ' || quote_nullable(status)
+        FROM ttr_players
+        WHERE status IS NULL OR status NOT IN ('CONFIRMED', 'MAYBE', 'DECLINED')
+        UNION ALL
+        SELECT 'invitations ' || id || ' status ' || quote_nullable(status)
+        FROM invitations
+        WHERE status IS NULL OR status NOT IN ('PENDING', 'YES', 'NO', 'MAYBE', 'CANCELED', 'EXPIRED')
+    ) rows;
+
+    IF offending IS NOT NULL THEN
+        RAISE EXCEPTION 'rows with an unknown status: %', offending;
+    END IF;
+END $$;
+
+ALTER TABLE ttrs ALTER COLUMN status SET NOT NULL;
+ALTER TABLE ttr_players ALTER COLUMN status SET NOT NULL;
+ALTER TABLE invitations ALTER COLUMN status SET NOT NULL;
+
+ALTER TABLE ttrs ADD CONSTRAINT chk_ttrs_status
+    CHECK (status IN ('OPEN', 'CONFIRMED', 'CANCELLED', 'COMPLETED'));
+ALTER TABLE ttrs ADD CONSTRAINT chk_ttrs_pre_delete_status
+    CHECK (pre_delete_status IN ('OPEN', 'CONFIRMED', 'CANCELLED', 'COMPLETED'));
+ALTER TABLE ttr_players ADD CONSTRAINT chk_ttr_players_status
+    CHECK (status IN ('CONFIRMED', 'MAYBE', 'DECLINED'));
+ALTER TABLE invitations ADD CONSTRAINT chk_invitations_status
+    CHECK (status IN ('PENDING', 'YES', 'NO', 'MAYBE', 'CANCELED', 'EXPIRED'));
@@ -0,0 +1,3 @@
+-- When an inactive user was warned their account will be deactivated;
+-- cleared when they log in again
+ALTER TABLE users ADD COLUMN inactivity_warned_at TIMESTAMP;
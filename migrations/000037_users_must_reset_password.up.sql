@@ -0,0 +1,3 @@
+-- Set on accounts created for someone else, such as imported members, until
+-- they choose their own password
+ALTER TABLE users ADD COLUMN must_reset_password BOOLEAN NOT NULL DEFAULT FALSE;
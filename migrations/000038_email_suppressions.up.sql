@@ -0,0 +1,13 @@
+-- Addresses email providers reported bounces or complaints for, by
+-- normalized email, and the flag shown to users whose address is suppressed
+CREATE TABLE email_suppressions (
+    email VARCHAR(255) PRIMARY KEY,
+    reason VARCHAR(20) NOT NULL DEFAULT '',
+    soft_bounces INTEGER NOT NULL DEFAULT 0,
+    suppressed_at TIMESTAMP,
+    last_event_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE users ADD COLUMN email_undeliverable BOOLEAN NOT NULL DEFAULT FALSE;
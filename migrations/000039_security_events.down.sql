@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS known_devices;
+DROP TABLE IF EXISTS security_events;
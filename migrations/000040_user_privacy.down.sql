@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_hide_from_search;
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_show_email;
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_show_phone;
+ALTER TABLE users DROP COLUMN IF EXISTS privacy_hide_handicap;
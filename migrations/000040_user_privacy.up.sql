@@ -0,0 +1,6 @@
+-- What each user lets other users see of them; every flag defaults to the
+-- setting a new user gets
+ALTER TABLE users ADD COLUMN privacy_hide_handicap BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN privacy_show_phone BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN privacy_show_email BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN privacy_hide_from_search BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ttr_guests;
@@ -0,0 +1,11 @@
+-- Players without an account, added to a TTR by its captains
+CREATE TABLE ttr_guests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ttr_id UUID NOT NULL REFERENCES ttrs(id) ON DELETE CASCADE,
+    display_name VARCHAR(100) NOT NULL,
+    handicap DECIMAL(3,1),
+    added_by_user_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ttr_guests_ttr_id ON ttr_guests(ttr_id);
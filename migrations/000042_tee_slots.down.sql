@@ -0,0 +1 @@
+DROP TABLE IF EXISTS tee_slots;
@@ -0,0 +1,12 @@
+-- The block of tee times a course gave a TTR; players are in a slot through
+-- their group number
+CREATE TABLE tee_slots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ttr_id UUID NOT NULL REFERENCES ttrs(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    offset_minutes INTEGER NOT NULL,
+    capacity INTEGER NOT NULL DEFAULT 4,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tee_slots_ttr_number ON tee_slots(ttr_id, number);
@@ -0,0 +1,22 @@
+-- Abuse reports users file against other users, chat messages and TTRs,
+-- reviewed by admins
+CREATE TABLE reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reporter_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('USER', 'MESSAGE', 'TTR')),
+    target_id UUID NOT NULL,
+    category VARCHAR(20) NOT NULL CHECK (category IN ('HARASSMENT', 'SPAM', 'INAPPROPRIATE', 'IMPERSONATION', 'OTHER')),
+    details TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'RESOLVED', 'DISMISSED')),
+    resolution TEXT NOT NULL DEFAULT '',
+    resolved_by_user_id UUID NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reports_reporter_created ON reports(reporter_user_id, created_at);
+CREATE INDEX idx_reports_target_id ON reports(target_id);
+CREATE INDEX idx_reports_status ON reports(status);
+-- A reporter can't pile up open reports against the same target
+CREATE UNIQUE INDEX idx_reports_open_target ON reports(reporter_user_id, target_type, target_id) WHERE status = 'OPEN';
@@ -0,0 +1,5 @@
+-- Fails while players are WAITLISTED or NO_SHOW; move them to another status
+-- by hand first.
+ALTER TABLE ttr_players DROP CONSTRAINT IF EXISTS chk_ttr_players_status;
+ALTER TABLE ttr_players ADD CONSTRAINT chk_ttr_players_status
+    CHECK (status IN ('CONFIRMED', 'MAYBE', 'DECLINED'));
@@ -0,0 +1,4 @@
+-- Roster players can be WAITLISTED for a slot or marked NO_SHOW.
+ALTER TABLE ttr_players DROP CONSTRAINT IF EXISTS chk_ttr_players_status;
+ALTER TABLE ttr_players ADD CONSTRAINT chk_ttr_players_status
+    CHECK (status IN ('CONFIRMED', 'MAYBE', 'DECLINED', 'WAITLISTED', 'NO_SHOW'));
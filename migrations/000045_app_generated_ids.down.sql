@@ -0,0 +1,3 @@
+ALTER TABLE invitations ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE ttrs ALTER COLUMN id SET DEFAULT uuid_generate_v4();
+ALTER TABLE users ALTER COLUMN id SET DEFAULT uuid_generate_v4();
@@ -0,0 +1,5 @@
+-- Users, TTRs and invitations get their ids from the application, so these
+-- tables no longer need uuid-ossp to fill them in.
+ALTER TABLE users ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE ttrs ALTER COLUMN id DROP DEFAULT;
+ALTER TABLE invitations ALTER COLUMN id DROP DEFAULT;
@@ -0,0 +1,5 @@
+ALTER TABLE ttrs DROP COLUMN IF EXISTS green_fee_cents;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS dress_code;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS holes;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS carts_available;
+ALTER TABLE ttrs DROP COLUMN IF EXISTS walking_allowed;
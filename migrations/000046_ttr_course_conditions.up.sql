@@ -0,0 +1,8 @@
+-- What the course offers on the day, given by the captain. All optional.
+ALTER TABLE ttrs ADD COLUMN walking_allowed BOOLEAN;
+ALTER TABLE ttrs ADD COLUMN carts_available BOOLEAN;
+ALTER TABLE ttrs ADD COLUMN holes INTEGER
+    CONSTRAINT chk_ttrs_holes CHECK (holes IN (9, 18));
+ALTER TABLE ttrs ADD COLUMN dress_code VARCHAR(100);
+ALTER TABLE ttrs ADD COLUMN green_fee_cents INTEGER
+    CONSTRAINT chk_ttrs_green_fee_cents CHECK (green_fee_cents >= 0);
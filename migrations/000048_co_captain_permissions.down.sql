@@ -0,0 +1 @@
+ALTER TABLE ttr_co_captains DROP COLUMN IF EXISTS permissions;
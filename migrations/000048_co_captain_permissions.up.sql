@@ -0,0 +1,5 @@
+-- What each co-captain may do. Existing co-captains keep every permission;
+-- new ones are always given theirs, so the default goes again.
+ALTER TABLE ttr_co_captains ADD COLUMN permissions VARCHAR(100) NOT NULL
+    DEFAULT 'invite,edit_details,manage_players';
+ALTER TABLE ttr_co_captains ALTER COLUMN permissions DROP DEFAULT;
@@ -0,0 +1,5 @@
+-- Users can turn the weekly digest email off; it is on by default
+ALTER TABLE users ADD COLUMN notify_no_weekly_digest BOOLEAN NOT NULL DEFAULT FALSE;
+-- When the last weekly digest run handled the user, so a run picked up after
+-- a crash doesn't email them twice
+ALTER TABLE users ADD COLUMN digest_sent_at TIMESTAMP;
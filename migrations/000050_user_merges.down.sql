@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_merged_into_user_id;
+ALTER TABLE users DROP COLUMN IF EXISTS merged_into_user_id;
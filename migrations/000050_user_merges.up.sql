@@ -0,0 +1,3 @@
+-- The account a duplicate was merged into; merged accounts are soft-deleted
+ALTER TABLE users ADD COLUMN merged_into_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_users_merged_into_user_id ON users(merged_into_user_id);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_blocks;
+ALTER TABLE invitations DROP COLUMN IF EXISTS forwarded_by_user_id;
+-- Fails while invitations are FORWARD_REQUESTED; cancel them first.
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS chk_invitations_status;
+ALTER TABLE invitations ADD CONSTRAINT chk_invitations_status
+    CHECK (status IN ('PENDING', 'YES', 'NO', 'MAYBE', 'CANCELED', 'EXPIRED'));
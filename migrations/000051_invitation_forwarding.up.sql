@@ -0,0 +1,15 @@
+-- Invitees can forward an invitation to someone else, which a captain
+-- approves; forwards never go to users the captain blocked
+ALTER TABLE invitations DROP CONSTRAINT IF EXISTS chk_invitations_status;
+ALTER TABLE invitations ADD CONSTRAINT chk_invitations_status
+    CHECK (status IN ('PENDING', 'YES', 'NO', 'MAYBE', 'CANCELED', 'EXPIRED', 'FORWARD_REQUESTED'));
+ALTER TABLE invitations ADD COLUMN forwarded_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE TABLE user_blocks (
+    blocker_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_user_id, blocked_user_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_user_id ON user_blocks(blocked_user_id);
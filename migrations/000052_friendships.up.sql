@@ -0,0 +1,12 @@
+-- Users send each other friend requests; FRIENDS TTRs are listed to the
+-- captain's accepted friends instead of users sharing an organization
+CREATE TABLE friendships (
+    requester_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    addressee_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP,
+    PRIMARY KEY (requester_user_id, addressee_user_id),
+    CONSTRAINT chk_friendships_not_self CHECK (requester_user_id <> addressee_user_id)
+);
+
+CREATE INDEX idx_friendships_addressee_user_id ON friendships(addressee_user_id);
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/golf_messenger/internal/config"
+)
+
+// Event types.
+const (
+	EventTTRCreated          = "ttr.created"
+	EventTTRCompleted        = "ttr.completed"
+	EventInvitationSent      = "invitation.sent"
+	EventInvitationResponded = "invitation.responded"
+	EventPlayerJoined        = "player.joined"
+)
+
+// Event is a domain event as exported for analytics. Users appear only as
+// references made by an Anonymizer.
+type Event struct {
+	ID           uuid.UUID         `json:"id"`
+	Type         string            `json:"type"`
+	OccurredAt   time.Time         `json:"occurred_at"`
+	TTRID        uuid.UUID         `json:"ttr_id"`
+	InvitationID *uuid.UUID        `json:"invitation_id,omitempty"`
+	Actor        string            `json:"actor,omitempty"`
+	Subject      string            `json:"subject,omitempty"`
+	Players      []string          `json:"players,omitempty"`
+	Properties   map[string]string `json:"properties,omitempty"`
+}
+
+// EventPublisher exports events to an analytics sink. Publish is called from
+// a single goroutine; Close flushes and releases the sink.
+type EventPublisher interface {
+	Publish(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// NewPublisher returns the publisher for the configured sink.
+func NewPublisher(ctx context.Context, cfg *config.AnalyticsConfig, awsCfg *config.AWSConfig) (EventPublisher, error) {
+	switch cfg.Sink {
+	case config.AnalyticsSinkNone:
+		return NopPublisher{}, nil
+	case config.AnalyticsSinkFile:
+		return NewFilePublisher(cfg.FilePath)
+	case config.AnalyticsSinkKafka:
+		return NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic), nil
+	case config.AnalyticsSinkKinesis:
+		return NewKinesisPublisher(ctx, awsCfg, cfg.KinesisStream)
+	default:
+		return nil, fmt.Errorf("unknown analytics sink %q", cfg.Sink)
+	}
+}
+
+// Anonymizer turns user IDs into stable references that can be joined across
+// events but not traced back to the user without the salt.
+type Anonymizer struct {
+	salt []byte
+}
+
+func NewAnonymizer(salt string) Anonymizer {
+	return Anonymizer{salt: []byte(salt)}
+}
+
+// User returns the reference for userID: the hex HMAC-SHA256 of the ID keyed
+// with the salt.
+func (a Anonymizer) User(userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write(userID[:])
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NopPublisher drops every event.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, events []Event) error { return nil }
+
+func (NopPublisher) Close() error { return nil }
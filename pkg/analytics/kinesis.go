@@ -0,0 +1,65 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/yourusername/golf_messenger/internal/config"
+)
+
+// maxKinesisRecords is how many records one PutRecords call takes.
+const maxKinesisRecords = 500
+
+// KinesisPublisher puts events on a Kinesis data stream, partitioned by TTR.
+type KinesisPublisher struct {
+	client *kinesis.Client
+	stream string
+}
+
+// NewKinesisPublisher connects to stream in the region of cfg, using the
+// default AWS credential chain.
+func NewKinesisPublisher(ctx context.Context, cfg *config.AWSConfig, stream string) (*KinesisPublisher, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &KinesisPublisher{client: kinesis.NewFromConfig(awsCfg), stream: stream}, nil
+}
+
+func (p *KinesisPublisher) Publish(ctx context.Context, events []Event) error {
+	for start := 0; start < len(events); start += maxKinesisRecords {
+		end := min(start+maxKinesisRecords, len(events))
+		records := make([]types.PutRecordsRequestEntry, 0, end-start)
+		for _, event := range events[start:end] {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return fmt.Errorf("failed to encode analytics event: %w", err)
+			}
+			records = append(records, types.PutRecordsRequestEntry{
+				Data:         data,
+				PartitionKey: aws.String(event.TTRID.String()),
+			})
+		}
+
+		out, err := p.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(p.stream),
+			Records:    records,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to put analytics events: %w", err)
+		}
+		if failed := aws.ToInt32(out.FailedRecordCount); failed > 0 {
+			return fmt.Errorf("failed to put %d of %d analytics events", failed, len(records))
+		}
+	}
+	return nil
+}
+
+func (p *KinesisPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores opaque values with a TTL. Implementations must be safe for
+// concurrent use.
+type Cache interface {
+	// Get returns ErrMiss when the key is absent or expired.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,105 @@
+// Package clientip works out which address a request came from. Behind a
+// load balancer the peer is the balancer, so the client is read from the
+// X-Forwarded-For or X-Real-IP headers, but only when the peer is a proxy we
+// trust: anyone else could send those headers to pose as another address.
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Resolver finds the client address of requests. Its zero value, and a nil
+// Resolver, trusts no proxy and always answers with the peer address.
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// New returns a Resolver believing the forwarding headers of peers in
+// trusted, given as CIDR ranges such as 10.0.0.0/8 or as single addresses.
+func New(trusted []string) (*Resolver, error) {
+	r := &Resolver{}
+	for _, entry := range trusted {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			r.trusted = append(r.trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		r.trusted = append(r.trusted, network)
+	}
+	return r, nil
+}
+
+// ClientIP returns the address req came from. When the peer is a trusted
+// proxy, X-Forwarded-For is read from the right, skipping the trusted
+// proxies that appended to it, and the first other address is the client.
+// Without X-Forwarded-For, X-Real-IP is used. Anything that doesn't parse
+// ends the search at the last address known to be real.
+func (r *Resolver) ClientIP(req *http.Request) string {
+	peer := peerIP(req)
+	if !r.isTrusted(peer) {
+		return peer
+	}
+
+	if forwarded := req.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				break
+			}
+			client = hop
+			if !r.isTrusted(hop) {
+				break
+			}
+		}
+		return client
+	}
+
+	if realIP := strings.TrimSpace(req.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+	return peer
+}
+
+func (r *Resolver) isTrusted(address string) bool {
+	if r == nil {
+		return false
+	}
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return false
+	}
+	for _, network := range r.trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func peerIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,110 @@
+// Package dataloader batches and caches lookups by key, so resolvers that
+// each need one row can be served by a single query. A Loader lives for one
+// request: it caches what it fetched and never expires anything.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// FetchFunc loads the values for keys. Keys it leaves out of the map are
+// reported as not found.
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader collects the keys asked for with Load and fetches them together.
+type Loader[K comparable, V any] struct {
+	fetch FetchFunc[K, V]
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	results map[K]result[V]
+}
+
+type result[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+// New returns a loader that fetches with fetch.
+func New[K comparable, V any](fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{fetch: fetch, queued: map[K]bool{}, results: map[K]result[V]{}}
+}
+
+// Load queues key and returns a function that waits for its value. The
+// first such function called fetches every key queued so far in one call
+// to fetch; later calls are answered from the cache. found is false when
+// fetch left the key out.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) func() (V, bool, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.results[key]; !ok {
+			l.dispatch(ctx)
+		}
+		r := l.results[key]
+		return r.value, r.found, r.err
+	}
+}
+
+// LoadMany loads every key, in one fetch with whatever else is queued, and
+// returns the values that were found.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	thunks := make([]func() (V, bool, error), len(keys))
+	for i, key := range keys {
+		thunks[i] = l.Load(ctx, key)
+	}
+	values := make(map[K]V, len(keys))
+	for i, thunk := range thunks {
+		value, found, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			values[keys[i]] = value
+		}
+	}
+	return values, nil
+}
+
+// Prime caches value for key, for rows a caller already has. It doesn't
+// replace a value that was already fetched.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.results[key]; !ok {
+		l.results[key] = result[V]{value: value, found: true}
+	}
+}
+
+// dispatch fetches the pending keys. The caller holds l.mu. An error is
+// cached for every key in the batch, since a request sees it once either
+// way.
+func (l *Loader[K, V]) dispatch(ctx context.Context) {
+	keys := make([]K, 0, len(l.pending))
+	for _, key := range l.pending {
+		if _, ok := l.results[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	l.pending = nil
+	l.queued = map[K]bool{}
+	if len(keys) == 0 {
+		return
+	}
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		value, found := values[key]
+		l.results[key] = result[V]{value: value, found: found && err == nil, err: err}
+	}
+}
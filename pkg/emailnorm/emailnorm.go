@@ -0,0 +1,80 @@
+// Package emailnorm normalizes email addresses so that the spellings of one
+// mailbox map to one account.
+package emailnorm
+
+import (
+	"sort"
+	"strings"
+)
+
+// Normalizer trims addresses and lowercases their domain, which is always
+// case-insensitive. The local part is only lowercased when
+// LowercaseLocalPart is set: RFC 5321 lets mail servers treat it as
+// case-sensitive, although hardly any do.
+type Normalizer struct {
+	LowercaseLocalPart bool
+}
+
+// Default is the Normalizer used by Normalize. It is set once at startup from
+// the configuration.
+var Default = Normalizer{LowercaseLocalPart: true}
+
+// Normalize normalizes email with Default.
+func Normalize(email string) string {
+	return Default.Normalize(email)
+}
+
+func (n Normalizer) Normalize(email string) string {
+	email = strings.TrimSpace(email)
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		if n.LowercaseLocalPart {
+			return strings.ToLower(email)
+		}
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	if n.LowercaseLocalPart {
+		local = strings.ToLower(local)
+	}
+	return local + "@" + strings.ToLower(domain)
+}
+
+// Collision lists distinct stored addresses that normalize to the same value
+// and so can no longer belong to separate accounts.
+type Collision struct {
+	Normalized string
+	Emails     []string
+}
+
+// Collisions groups emails by their normalized form and returns the groups
+// holding more than one distinct address, sorted by normalized value.
+func (n Normalizer) Collisions(emails []string) []Collision {
+	groups := make(map[string]map[string]bool)
+	for _, email := range emails {
+		normalized := n.Normalize(email)
+		if groups[normalized] == nil {
+			groups[normalized] = make(map[string]bool)
+		}
+		groups[normalized][email] = true
+	}
+
+	var collisions []Collision
+	for normalized, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		collision := Collision{Normalized: normalized}
+		for email := range group {
+			collision.Emails = append(collision.Emails, email)
+		}
+		sort.Strings(collision.Emails)
+		collisions = append(collisions, collision)
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Normalized < collisions[j].Normalized
+	})
+	return collisions
+}
@@ -0,0 +1,437 @@
+// Package errcode is the registry of the machine-readable codes sent in
+// response.ErrorInfo.Code, each with the HTTP status it maps to. Clients
+// branch on these codes, so a released code is never renamed or reused.
+package errcode
+
+import "net/http"
+
+// Code is a stable, machine-readable error code.
+type Code string
+
+// Generic codes, for errors that don't come from a service.
+const (
+	BadRequest          Code = "BAD_REQUEST"
+	ValidationError     Code = "VALIDATION_ERROR"
+	Unauthorized        Code = "UNAUTHORIZED"
+	WrongTokenType      Code = "WRONG_TOKEN_TYPE"
+	Forbidden           Code = "FORBIDDEN"
+	NotFound            Code = "NOT_FOUND"
+	Conflict            Code = "CONFLICT"
+	PayloadTooLarge     Code = "PAYLOAD_TOO_LARGE"
+	RateLimited         Code = "RATE_LIMITED"
+	InternalServerError Code = "INTERNAL_SERVER_ERROR"
+	Maintenance         Code = "MAINTENANCE"
+	NotReady            Code = "NOT_READY"
+)
+
+// Accounts and users.
+const (
+	InvalidCredentials    Code = "INVALID_CREDENTIALS"
+	InvalidRefreshToken   Code = "INVALID_REFRESH_TOKEN"
+	EmailTaken            Code = "EMAIL_TAKEN"
+	UserNotFound          Code = "USER_NOT_FOUND"
+	UnsupportedLanguage   Code = "UNSUPPORTED_LANGUAGE"
+	InvalidPlayingDay     Code = "INVALID_PLAYING_DAY"
+	InvalidTeeTimeRange   Code = "INVALID_TEE_TIME_RANGE"
+	SearchQueryTooShort   Code = "SEARCH_QUERY_TOO_SHORT"
+	AvatarTooLarge        Code = "AVATAR_TOO_LARGE"
+	AvatarEmpty           Code = "AVATAR_EMPTY"
+	UnsupportedImageType  Code = "UNSUPPORTED_IMAGE_TYPE"
+	InvalidAvatarUpload   Code = "INVALID_AVATAR_UPLOAD"
+	APITokenNotFound      Code = "API_TOKEN_NOT_FOUND"
+	InvalidAPITokenExpiry Code = "INVALID_API_TOKEN_EXPIRY"
+	APITokenNotAllowed    Code = "API_TOKEN_NOT_ALLOWED"
+	InvalidAPITokenScope  Code = "INVALID_API_TOKEN_SCOPE"
+	InsufficientScope     Code = "INSUFFICIENT_SCOPE"
+	CannotImpersonate     Code = "CANNOT_IMPERSONATE"
+	NotImpersonating      Code = "NOT_IMPERSONATING"
+	ImpersonationReadOnly Code = "IMPERSONATION_READ_ONLY"
+	ImpersonationDenied   Code = "IMPERSONATION_DENIED"
+	InvalidImportFile     Code = "INVALID_IMPORT_FILE"
+	CannotMergeUsers      Code = "CANNOT_MERGE_USERS"
+	UserAlreadyMerged     Code = "USER_ALREADY_MERGED"
+	CannotBlockSelf       Code = "CANNOT_BLOCK_SELF"
+	CannotBefriendSelf    Code = "CANNOT_BEFRIEND_SELF"
+	FriendshipNotFound    Code = "FRIENDSHIP_NOT_FOUND"
+	WeakPassword          Code = "WEAK_PASSWORD"
+)
+
+// TTRs and their rosters.
+const (
+	TTRNotFound          Code = "TTR_NOT_FOUND"
+	TTRFull              Code = "TTR_FULL"
+	TTRNotRestorable     Code = "TTR_NOT_RESTORABLE"
+	DuplicateTTR         Code = "DUPLICATE_TTR"
+	TTRLocked            Code = "TTR_LOCKED"
+	InvalidTTRStatus     Code = "INVALID_TTR_STATUS"
+	InvalidTTRVisibility Code = "INVALID_TTR_VISIBILITY"
+	InvalidMaxPlayers    Code = "INVALID_MAX_PLAYERS"
+	InvalidMinPlayers    Code = "INVALID_MIN_PLAYERS"
+	InvalidHoles         Code = "INVALID_HOLES"
+	InvalidGreenFee      Code = "INVALID_GREEN_FEE"
+	InvalidRSVPDeadline  Code = "INVALID_RSVP_DEADLINE"
+	InvalidPermission    Code = "INVALID_CO_CAPTAIN_PERMISSION"
+	CoCaptainNotFound    Code = "CO_CAPTAIN_NOT_FOUND"
+	AlreadyPlayer        Code = "ALREADY_PLAYER"
+	CaptainCannotLeave   Code = "CAPTAIN_CANNOT_LEAVE"
+	PlayerNotFound       Code = "PLAYER_NOT_FOUND"
+	GuestNotFound        Code = "GUEST_NOT_FOUND"
+	InvalidPlayerStatus  Code = "INVALID_PLAYER_STATUS"
+	InvalidStatusChange  Code = "INVALID_STATUS_CHANGE"
+	InvalidGroupNumber   Code = "INVALID_GROUP_NUMBER"
+	PairingGroupFull     Code = "PAIRING_GROUP_FULL"
+	PairingOffRoster     Code = "PAIRING_OFF_ROSTER"
+	TeeSlotNotFound      Code = "TEE_SLOT_NOT_FOUND"
+	InvalidTeeSlots      Code = "INVALID_TEE_SLOTS"
+	SlotCapacityTooSmall Code = "SLOT_CAPACITY_TOO_SMALL"
+	InvalidChangeCursor  Code = "INVALID_CHANGE_CURSOR"
+	CheckInClosed        Code = "CHECK_IN_CLOSED"
+	NotTTRCaptain        Code = "NOT_TTR_CAPTAIN"
+	NotTTRManager        Code = "NOT_TTR_MANAGER"
+	NotTTRPlayer         Code = "NOT_TTR_PLAYER"
+)
+
+// TTR invitations.
+const (
+	InvitationNotFound         Code = "INVITATION_NOT_FOUND"
+	InvitationAlreadyPending   Code = "INVITATION_ALREADY_PENDING"
+	InvitationNotPending       Code = "INVITATION_NOT_PENDING"
+	InvitationExpired          Code = "INVITATION_EXPIRED"
+	InvalidInvitationStatus    Code = "INVALID_INVITATION_STATUS"
+	DeclineReasonNotAllowed    Code = "DECLINE_REASON_NOT_ALLOWED"
+	CannotInviteSelf           Code = "CANNOT_INVITE_SELF"
+	CannotInviteCaptain        Code = "CANNOT_INVITE_CAPTAIN"
+	CannotInviteDeletedUser    Code = "CANNOT_INVITE_DELETED_USER"
+	TTRClosed                  Code = "TTR_CLOSED"
+	TeeTimePassed              Code = "TEE_TIME_PASSED"
+	NotInviter                 Code = "NOT_INVITER"
+	NotInvitee                 Code = "NOT_INVITEE"
+	InvitationTemplateNotFound Code = "INVITATION_TEMPLATE_NOT_FOUND"
+	InvalidMessageTemplate     Code = "INVALID_MESSAGE_TEMPLATE"
+	CannotForwardToUser        Code = "CANNOT_FORWARD_TO_USER"
+	InvitationNotForwarded     Code = "INVITATION_NOT_FORWARDED"
+)
+
+// TTR invite links.
+const (
+	InviteLinkNotFound      Code = "INVITE_LINK_NOT_FOUND"
+	InviteLinkRevoked       Code = "INVITE_LINK_REVOKED"
+	InviteLinkExpired       Code = "INVITE_LINK_EXPIRED"
+	InviteLinkUsedUp        Code = "INVITE_LINK_USED_UP"
+	InvalidInviteLinkExpiry Code = "INVALID_INVITE_LINK_EXPIRY"
+)
+
+// TTR chat.
+const (
+	MessageNotFound           Code = "MESSAGE_NOT_FOUND"
+	MessageDeleted            Code = "MESSAGE_DELETED"
+	MessageEmpty              Code = "MESSAGE_EMPTY"
+	InvalidEmoji              Code = "INVALID_EMOJI"
+	EditWindowExpired         Code = "EDIT_WINDOW_EXPIRED"
+	NotMessageAuthor          Code = "NOT_MESSAGE_AUTHOR"
+	AttachmentTooLarge        Code = "ATTACHMENT_TOO_LARGE"
+	AttachmentQuotaExceeded   Code = "ATTACHMENT_QUOTA_EXCEEDED"
+	UnsupportedAttachmentType Code = "UNSUPPORTED_ATTACHMENT_TYPE"
+)
+
+// Organizations.
+const (
+	OrganizationNotFound     Code = "ORGANIZATION_NOT_FOUND"
+	OrganizationNameRequired Code = "ORGANIZATION_NAME_REQUIRED"
+	MemberNotFound           Code = "MEMBER_NOT_FOUND"
+	AlreadyMember            Code = "ALREADY_MEMBER"
+	InvalidRole              Code = "INVALID_ROLE"
+	OwnerProtected           Code = "OWNER_PROTECTED"
+	NotOrganizationMember    Code = "NOT_ORGANIZATION_MEMBER"
+	NotOrganizationAdmin     Code = "NOT_ORGANIZATION_ADMIN"
+	NotOrganizationOwner     Code = "NOT_ORGANIZATION_OWNER"
+)
+
+// Leagues.
+const (
+	LeagueNotFound       Code = "LEAGUE_NOT_FOUND"
+	LeagueNameRequired   Code = "LEAGUE_NAME_REQUIRED"
+	InvalidSeason        Code = "INVALID_SEASON"
+	InvalidScoringScheme Code = "INVALID_SCORING_SCHEME"
+	TTRInLeague          Code = "TTR_IN_LEAGUE"
+	TTROutsideSeason     Code = "TTR_OUTSIDE_SEASON"
+	TTRNotCompleted      Code = "TTR_NOT_COMPLETED"
+	InvalidScore         Code = "INVALID_SCORE"
+	NotLeagueOwner       Code = "NOT_LEAGUE_OWNER"
+)
+
+// Tournaments.
+const (
+	TournamentNotFound     Code = "TOURNAMENT_NOT_FOUND"
+	TournamentNameRequired Code = "TOURNAMENT_NAME_REQUIRED"
+	InvalidFieldSize       Code = "INVALID_FIELD_SIZE"
+	DuplicateEntrant       Code = "DUPLICATE_ENTRANT"
+	TournamentHasNoTTR     Code = "TOURNAMENT_HAS_NO_TTR"
+	MatchNotFound          Code = "MATCH_NOT_FOUND"
+	MatchCompleted         Code = "MATCH_COMPLETED"
+	MatchNotReady          Code = "MATCH_NOT_READY"
+	InvalidWinner          Code = "INVALID_WINNER"
+	RoundNotReady          Code = "ROUND_NOT_READY"
+	RoundFinished          Code = "ROUND_FINISHED"
+	RoundHasTTR            Code = "ROUND_HAS_TTR"
+	NotTournamentOwner     Code = "NOT_TOURNAMENT_OWNER"
+	NotMatchPlayer         Code = "NOT_MATCH_PLAYER"
+)
+
+// Webhooks.
+const (
+	WebhookNotFound     Code = "WEBHOOK_NOT_FOUND"
+	InvalidWebhookURL   Code = "INVALID_WEBHOOK_URL"
+	InvalidWebhookEvent Code = "INVALID_WEBHOOK_EVENT"
+	NotWebhookOwner     Code = "NOT_WEBHOOK_OWNER"
+)
+
+// Integrations.
+const (
+	InvalidSlackLinkCode Code = "INVALID_SLACK_LINK_CODE"
+)
+
+// Feature flags.
+const (
+	FeatureFlagNotFound Code = "FEATURE_FLAG_NOT_FOUND"
+	InvalidFeatureFlag  Code = "INVALID_FEATURE_FLAG"
+)
+
+// Abuse reports.
+const (
+	ReportTargetNotFound Code = "REPORT_TARGET_NOT_FOUND"
+	CannotReportSelf     Code = "CANNOT_REPORT_SELF"
+	ReportAlreadyOpen    Code = "REPORT_ALREADY_OPEN"
+	ReportLimitReached   Code = "REPORT_LIMIT_REACHED"
+	ReportNotFound       Code = "REPORT_NOT_FOUND"
+	ReportClosed         Code = "REPORT_CLOSED"
+)
+
+// GraphQL queries. These are sent in an error's extensions.code rather than
+// response.ErrorInfo.
+const (
+	GraphQLParseFailed      Code = "GRAPHQL_PARSE_FAILED"
+	GraphQLValidationFailed Code = "GRAPHQL_VALIDATION_FAILED"
+	QueryTooDeep            Code = "QUERY_TOO_DEEP"
+	QueryTooComplex         Code = "QUERY_TOO_COMPLEX"
+)
+
+// Definition is a registered code with the status it is sent with.
+type Definition struct {
+	Code        Code
+	Status      int
+	Description string
+}
+
+// registry lists every code, in the order the catalogue shows them.
+var registry = []Definition{
+	{BadRequest, http.StatusBadRequest, "The request could not be parsed: a malformed body, ID or query parameter."},
+	{ValidationError, http.StatusUnprocessableEntity, "The request body failed validation. details maps each invalid field to its problem."},
+	{Unauthorized, http.StatusUnauthorized, "The access token is missing, invalid or expired."},
+	{WrongTokenType, http.StatusUnauthorized, "The bearer token is valid but not an access token, e.g. a login challenge token."},
+	{Forbidden, http.StatusForbidden, "The caller's role doesn't allow this request."},
+	{NotFound, http.StatusNotFound, "No such route, or an unsupported API version. details lists the supported versions for the latter."},
+	{Conflict, http.StatusConflict, "The request conflicts with the current state of the resource."},
+	{PayloadTooLarge, http.StatusRequestEntityTooLarge, "The request body is larger than the server accepts."},
+	{RateLimited, http.StatusTooManyRequests, "Too many requests from this client; retry after the Retry-After header."},
+	{InternalServerError, http.StatusInternalServerError, "An unexpected server error."},
+	{Maintenance, http.StatusServiceUnavailable, "The API is down for maintenance. details.ends_at, when present, estimates when it is back."},
+	{NotReady, http.StatusServiceUnavailable, "The instance can't serve traffic, e.g. its database is unreachable. Only sent by /readyz."},
+
+	{InvalidCredentials, http.StatusUnauthorized, "The email and password, or the current password, are wrong."},
+	{InvalidRefreshToken, http.StatusUnauthorized, "The refresh token is unknown, revoked or expired."},
+	{EmailTaken, http.StatusConflict, "An account with this email already exists."},
+	{UserNotFound, http.StatusNotFound, "The user does not exist or was deleted."},
+	{UnsupportedLanguage, http.StatusBadRequest, "The preferred language is not one the API is translated into."},
+	{InvalidPlayingDay, http.StatusBadRequest, "A playing day is not a weekday name such as MONDAY."},
+	{InvalidTeeTimeRange, http.StatusBadRequest, "The preferred tee time range ends before it starts."},
+	{SearchQueryTooShort, http.StatusBadRequest, "User search by name needs at least 3 characters."},
+	{AvatarTooLarge, http.StatusRequestEntityTooLarge, "The avatar is larger than the allowed size, given in details.max_size."},
+	{AvatarEmpty, http.StatusBadRequest, "The avatar file is empty."},
+	{UnsupportedImageType, http.StatusBadRequest, "Avatars must be JPEG or PNG images."},
+	{InvalidAvatarUpload, http.StatusBadRequest, "The avatar upload key is not the caller's, or nothing was uploaded to it."},
+	{APITokenNotFound, http.StatusNotFound, "The API token does not exist or belongs to someone else."},
+	{InvalidAPITokenExpiry, http.StatusBadRequest, "An API token's expiry must be in the future."},
+	{APITokenNotAllowed, http.StatusForbidden, "API tokens can't change the password or manage API tokens. Sign in to do this."},
+	{InvalidAPITokenScope, http.StatusBadRequest, "API tokens need at least one scope, and only known scopes."},
+	{InsufficientScope, http.StatusForbidden, "The token doesn't carry the scope the route requires, named in details.required_scope."},
+	{CannotImpersonate, http.StatusForbidden, "Admins can't impersonate themselves or other admins."},
+	{NotImpersonating, http.StatusBadRequest, "The access token is not an impersonation token."},
+	{ImpersonationReadOnly, http.StatusForbidden, "The impersonation session is read-only; start one with allow_writes to make changes."},
+	{ImpersonationDenied, http.StatusForbidden, "Impersonation sessions can't change the password or manage API tokens, even with allow_writes."},
+	{InvalidImportFile, http.StatusBadRequest, "The import file isn't a CSV with email, first_name and last_name columns."},
+	{CannotMergeUsers, http.StatusBadRequest, "The source and target of an account merge are the same user."},
+	{UserAlreadyMerged, http.StatusConflict, "The source account was already merged into a different account."},
+	{CannotBlockSelf, http.StatusBadRequest, "Users cannot block themselves."},
+	{CannotBefriendSelf, http.StatusBadRequest, "Users cannot send themselves a friend request."},
+	{FriendshipNotFound, http.StatusNotFound, "The caller has neither a friendship nor a pending friend request with the user."},
+	{WeakPassword, http.StatusUnprocessableEntity, "The new password breaks the password policy. details.rules lists the rules it breaks (min_length, lowercase, uppercase, digit, symbol, common or email) and details.min_length gives the shortest length allowed."},
+
+	{TTRNotFound, http.StatusNotFound, "The TTR does not exist, was deleted, or is not visible to the caller."},
+	{TTRFull, http.StatusBadRequest, "The TTR has no open slot left."},
+	{TTRNotRestorable, http.StatusConflict, "The deleted TTR is past its restore window."},
+	{DuplicateTTR, http.StatusConflict, "The captain already has a TTR at the course within 30 minutes of the tee time, named in details.existing_ttr_id. Send force to create it anyway."},
+	{TTRLocked, http.StatusConflict, "The TTR's edit lock, which started at details.locked_at, keeps its course, tee date and tee time from changing and the TTR from being deleted. Send override to do it anyway."},
+	{InvalidTTRStatus, http.StatusBadRequest, "The TTR status is not a known status."},
+	{InvalidTTRVisibility, http.StatusBadRequest, "The TTR visibility is not a known visibility."},
+	{InvalidMaxPlayers, http.StatusBadRequest, "max_players must be greater than 0."},
+	{InvalidMinPlayers, http.StatusBadRequest, "min_players must be between 1 and max_players."},
+	{InvalidHoles, http.StatusBadRequest, "holes must be 9 or 18."},
+	{InvalidGreenFee, http.StatusBadRequest, "green_fee_cents can't be negative."},
+	{InvalidRSVPDeadline, http.StatusBadRequest, "The RSVP deadline is not before the tee time."},
+	{InvalidPermission, http.StatusBadRequest, "A co-captain permission is not invite, edit_details or manage_players."},
+	{CoCaptainNotFound, http.StatusNotFound, "The user is not a co-captain of the TTR."},
+	{AlreadyPlayer, http.StatusConflict, "The user is already on the TTR's roster."},
+	{CaptainCannotLeave, http.StatusBadRequest, "The captain cannot leave their own TTR."},
+	{PlayerNotFound, http.StatusNotFound, "The user is not on the TTR's roster."},
+	{GuestNotFound, http.StatusNotFound, "The guest is not on the TTR's roster."},
+	{InvalidPlayerStatus, http.StatusBadRequest, "The player status is not a known status."},
+	{InvalidStatusChange, http.StatusConflict, "The player can't move from their current status to the requested one, e.g. to NO_SHOW without being CONFIRMED."},
+	{InvalidGroupNumber, http.StatusBadRequest, "Pairing group numbers start at 1."},
+	{PairingGroupFull, http.StatusBadRequest, "A pairing group holds at most 4 players, or its tee slot's capacity."},
+	{PairingOffRoster, http.StatusBadRequest, "Pairings can only include players on the roster."},
+	{TeeSlotNotFound, http.StatusBadRequest, "The TTR has tee slots and none has the pairing group's number."},
+	{InvalidTeeSlots, http.StatusBadRequest, "Tee slots need increasing offsets of 0 minutes or more and a capacity of 1 to 4."},
+	{SlotCapacityTooSmall, http.StatusBadRequest, "The tee slots hold fewer players than are confirmed."},
+	{InvalidChangeCursor, http.StatusBadRequest, "The since cursor of a change feed must be a sequence number of 0 or more."},
+	{CheckInClosed, http.StatusConflict, "Check-in is only open from details.opens_at to details.closes_at around the tee time."},
+	{NotTTRCaptain, http.StatusForbidden, "Only the TTR's captain can do this."},
+	{NotTTRManager, http.StatusForbidden, "Only the TTR's captain, or a co-captain with the permission it takes, can do this."},
+	{NotTTRPlayer, http.StatusForbidden, "Only players on the TTR can use its chat or check in."},
+
+	{InvitationNotFound, http.StatusNotFound, "The invitation does not exist or is not addressed to the caller."},
+	{InvitationAlreadyPending, http.StatusConflict, "The user or email already has a pending invitation."},
+	{InvitationNotPending, http.StatusBadRequest, "The invitation was already answered or canceled."},
+	{InvitationExpired, http.StatusBadRequest, "The TTR's RSVP deadline has passed."},
+	{InvalidInvitationStatus, http.StatusBadRequest, "The answer is not YES, NO or MAYBE."},
+	{DeclineReasonNotAllowed, http.StatusBadRequest, "A decline reason only goes with a NO or MAYBE answer."},
+	{CannotInviteSelf, http.StatusBadRequest, "Users cannot invite themselves."},
+	{CannotInviteCaptain, http.StatusBadRequest, "The TTR's captain cannot be invited to it."},
+	{CannotInviteDeletedUser, http.StatusBadRequest, "Deleted users cannot be invited."},
+	{TTRClosed, http.StatusBadRequest, "The TTR is cancelled or completed."},
+	{TeeTimePassed, http.StatusBadRequest, "The TTR's tee time has passed."},
+	{NotInviter, http.StatusForbidden, "Only the user who sent the invitation can do this."},
+	{NotInvitee, http.StatusForbidden, "Only the invited user can answer the invitation."},
+	{InvitationTemplateNotFound, http.StatusNotFound, "The invitation template does not exist or belongs to another user."},
+	{InvalidMessageTemplate, http.StatusUnprocessableEntity, "The message template is malformed or uses a variable other than {{course}}, {{date}}, {{time}} and {{captain}}. details gives the line and, when known, the column of the problem."},
+	{CannotForwardToUser, http.StatusBadRequest, "An invitation can't be forwarded to someone already on the TTR's roster, or whom its captain blocked."},
+	{InvitationNotForwarded, http.StatusBadRequest, "Only a forwarded invitation still waiting for a captain's approval can be approved."},
+
+	{InviteLinkNotFound, http.StatusNotFound, "The invite link does not exist."},
+	{InviteLinkRevoked, http.StatusBadRequest, "The invite link was revoked by the TTR's captain or a co-captain."},
+	{InviteLinkExpired, http.StatusBadRequest, "The invite link's expiry has passed."},
+	{InviteLinkUsedUp, http.StatusConflict, "Every use of the invite link is taken."},
+	{InvalidInviteLinkExpiry, http.StatusBadRequest, "An invite link's expiry must be in the future."},
+
+	{MessageNotFound, http.StatusNotFound, "The chat message does not exist."},
+	{MessageDeleted, http.StatusBadRequest, "The chat message was deleted."},
+	{MessageEmpty, http.StatusBadRequest, "Chat messages need a body."},
+	{InvalidEmoji, http.StatusBadRequest, "The reaction is not a single emoji."},
+	{EditWindowExpired, http.StatusForbidden, "The message is too old to edit."},
+	{NotMessageAuthor, http.StatusForbidden, "Only the message's author, or for deletes the TTR's captain or co-captains, can do this."},
+	{AttachmentTooLarge, http.StatusRequestEntityTooLarge, "The attachment is larger than the allowed size."},
+	{AttachmentQuotaExceeded, http.StatusRequestEntityTooLarge, "The TTR's or the user's attachment storage quota is used up."},
+	{UnsupportedAttachmentType, http.StatusBadRequest, "The attachment's file type is not allowed."},
+
+	{OrganizationNotFound, http.StatusNotFound, "The organization does not exist or the caller is not a member."},
+	{OrganizationNameRequired, http.StatusBadRequest, "Organizations need a name."},
+	{MemberNotFound, http.StatusNotFound, "The user is not a member of the organization."},
+	{AlreadyMember, http.StatusConflict, "The user is already a member of the organization."},
+	{InvalidRole, http.StatusBadRequest, "The role is not a known organization role."},
+	{OwnerProtected, http.StatusBadRequest, "The organization owner cannot be removed or have their role changed."},
+	{NotOrganizationMember, http.StatusForbidden, "Only members of the organization can do this."},
+	{NotOrganizationAdmin, http.StatusForbidden, "Only the organization's owner or admins can do this."},
+	{NotOrganizationOwner, http.StatusForbidden, "Only the organization's owner can do this."},
+
+	{LeagueNotFound, http.StatusNotFound, "The league does not exist."},
+	{LeagueNameRequired, http.StatusBadRequest, "Leagues need a name."},
+	{InvalidSeason, http.StatusBadRequest, "The league's end date is before its start date."},
+	{InvalidScoringScheme, http.StatusBadRequest, "The scoring scheme is not a known scheme."},
+	{TTRInLeague, http.StatusConflict, "The TTR already belongs to a league."},
+	{TTROutsideSeason, http.StatusBadRequest, "The TTR's tee date is outside the league's season."},
+	{TTRNotCompleted, http.StatusBadRequest, "Only completed TTRs can be attached to a league or scored."},
+	{InvalidScore, http.StatusBadRequest, "Scores must be positive."},
+	{NotLeagueOwner, http.StatusForbidden, "Only the league's owner can do this."},
+
+	{TournamentNotFound, http.StatusNotFound, "The tournament does not exist."},
+	{TournamentNameRequired, http.StatusBadRequest, "Tournaments need a name."},
+	{InvalidFieldSize, http.StatusBadRequest, "A tournament needs between 2 and 16 players."},
+	{DuplicateEntrant, http.StatusBadRequest, "A player can only be entered once."},
+	{TournamentHasNoTTR, http.StatusBadRequest, "The tournament was not started from a TTR, so there is none to copy."},
+	{MatchNotFound, http.StatusNotFound, "The match does not exist in the tournament."},
+	{MatchCompleted, http.StatusConflict, "The match already has a result."},
+	{MatchNotReady, http.StatusBadRequest, "The match's players aren't known yet."},
+	{InvalidWinner, http.StatusBadRequest, "The winner must be one of the match's players."},
+	{RoundNotReady, http.StatusBadRequest, "The round's matches aren't all known yet."},
+	{RoundFinished, http.StatusBadRequest, "Every match in the round has been played."},
+	{RoundHasTTR, http.StatusConflict, "The round already has a TTR."},
+	{NotTournamentOwner, http.StatusForbidden, "Only the tournament's owner can do this."},
+	{NotMatchPlayer, http.StatusForbidden, "Only the match's players or the tournament's owner can report its result."},
+
+	{WebhookNotFound, http.StatusNotFound, "The webhook does not exist or belongs to someone else."},
+	{InvalidWebhookURL, http.StatusBadRequest, "Webhook URLs must be absolute http or https URLs."},
+	{InvalidWebhookEvent, http.StatusBadRequest, "Webhooks need at least one event, and only known events."},
+	{NotWebhookOwner, http.StatusForbidden, "Only the webhook's owner, or for organization webhooks the organization's owner or admins, can do this."},
+
+	{InvalidSlackLinkCode, http.StatusBadRequest, "The Slack linking code is unknown, used or expired. Type /golf link in Slack for a new one."},
+
+	{FeatureFlagNotFound, http.StatusNotFound, "No flag with this key is managed at runtime."},
+	{InvalidFeatureFlag, http.StatusBadRequest, "Flag keys are lowercase letters, digits and underscores, and percentages are 0 to 100."},
+
+	{ReportTargetNotFound, http.StatusNotFound, "The reported user, message or TTR doesn't exist."},
+	{CannotReportSelf, http.StatusBadRequest, "Users can't report themselves."},
+	{ReportAlreadyOpen, http.StatusConflict, "The caller already has an open report against this target; it is reviewed once."},
+	{ReportLimitReached, http.StatusTooManyRequests, "The caller has filed as many reports as allowed in the last 24 hours."},
+	{ReportNotFound, http.StatusNotFound, "The report doesn't exist."},
+	{ReportClosed, http.StatusConflict, "The report was already resolved or dismissed."},
+
+	{GraphQLParseFailed, http.StatusBadRequest, "The GraphQL query is not valid syntax. locations points at the problem."},
+	{GraphQLValidationFailed, http.StatusBadRequest, "The GraphQL query asks for fields, arguments or operations the schema doesn't have."},
+	{QueryTooDeep, http.StatusBadRequest, "The GraphQL query nests selections deeper than the server allows."},
+	{QueryTooComplex, http.StatusBadRequest, "The GraphQL query selects more fields in all than the server allows."},
+}
+
+var byCode = func() map[Code]Definition {
+	m := make(map[Code]Definition, len(registry))
+	for _, def := range registry {
+		m[def.Code] = def
+	}
+	return m
+}()
+
+// Lookup returns the definition of code.
+func Lookup(code Code) (Definition, bool) {
+	def, ok := byCode[code]
+	return def, ok
+}
+
+// Status returns the HTTP status code maps to, or 500 for an unregistered
+// code.
+func Status(code Code) int {
+	if def, ok := byCode[code]; ok {
+		return def.Status
+	}
+	return http.StatusInternalServerError
+}
+
+// All returns every registered code.
+func All() []Definition {
+	return append([]Definition(nil), registry...)
+}
+
+// Error is an error with a registered code. Services declare their errors
+// with New so handlers can send the code without matching on the message.
+type Error struct {
+	Code    Code
+	Message string
+}
+
+// New returns an error with code and message. The message is what clients
+// see, translated through the i18n catalog.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
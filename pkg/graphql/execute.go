@@ -0,0 +1,569 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Params is a query to execute. MaxDepth and MaxComplexity limit how deep
+// the selections go and how many fields they select in all; zero means no
+// limit. PresentError, if set, is called on every error before it is sent,
+// to rewrite its message or add extensions.
+type Params struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+	MaxDepth      int
+	MaxComplexity int
+	PresentError  func(ctx context.Context, err *Error)
+}
+
+// Result is the response to a query. A query that was rejected before it ran
+// has no Data, only Errors.
+type Result struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Rejected reports whether the query was refused before it ran: it didn't
+// parse, failed validation or went over a limit.
+func (r *Result) Rejected() bool {
+	return r.Data == nil
+}
+
+// Error is an error in a response. Path is set for errors resolving a field.
+type Error struct {
+	Message    string                 `json:"message"`
+	Locations  []Location             `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+
+	err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the error the resolver or the executor returned.
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// SyntaxError is a query that doesn't parse.
+type SyntaxError struct {
+	Message  string
+	Location Location
+}
+
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// ValidationError is a query that parses but doesn't fit the schema, or
+// whose variables don't fit the operation.
+type ValidationError struct {
+	Message  string
+	Location Location
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// LimitError is a query that goes deeper or selects more fields than
+// Params allows. Limit is "depth" or "complexity".
+type LimitError struct {
+	Limit string
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("query exceeds the maximum %s of %d", e.Limit, e.Max)
+}
+
+// Location is a 1-based line and column in the query.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Execute runs a query against the schema.
+func (s *Schema) Execute(ctx context.Context, params Params) *Result {
+	present := func(err *Error) *Error {
+		if params.PresentError != nil {
+			params.PresentError(ctx, err)
+		}
+		return err
+	}
+	reject := func(errs ...error) *Result {
+		result := &Result{}
+		for _, err := range errs {
+			e := &Error{Message: err.Error(), err: err}
+			switch err := err.(type) {
+			case *SyntaxError:
+				e.Locations = []Location{err.Location}
+			case *ValidationError:
+				if err.Location.Line > 0 {
+					e.Locations = []Location{err.Location}
+				}
+			}
+			result.Errors = append(result.Errors, present(e))
+		}
+		return result
+	}
+
+	doc, err := parser.ParseQuery(&ast.Source{Input: params.Query})
+	if err != nil {
+		syntax := &SyntaxError{Message: err.Error()}
+		if gqlErr, ok := err.(*gqlerror.Error); ok {
+			syntax.Message, syntax.Location = gqlErr.Message, firstLocation(gqlErr)
+		}
+		return reject(syntax)
+	}
+	if gqlErrs := validator.ValidateWithRules(s.schema, doc, nil); len(gqlErrs) > 0 {
+		errs := make([]error, len(gqlErrs))
+		for i, gqlErr := range gqlErrs {
+			errs[i] = &ValidationError{Message: gqlErr.Message, Location: firstLocation(gqlErr)}
+		}
+		return reject(errs...)
+	}
+	operation, err := selectOperation(doc, params.OperationName)
+	if err != nil {
+		return reject(err)
+	}
+	variables, err := validator.VariableValues(s.schema, operation, params.Variables)
+	if err != nil {
+		return reject(variableError(err))
+	}
+
+	p := &planner{schema: s, doc: doc, variables: variables, maxDepth: params.MaxDepth, maxComplexity: params.MaxComplexity}
+	query := s.schema.Query
+	fields, err := p.plan(query, []ast.SelectionSet{operation.SelectionSet}, 1)
+	if err != nil {
+		return reject(err)
+	}
+
+	e := &executor{ctx: ctx, schema: s.schema, present: present}
+	data := e.run(query, fields)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+func firstLocation(err *gqlerror.Error) Location {
+	if len(err.Locations) == 0 {
+		return Location{}
+	}
+	return Location{Line: err.Locations[0].Line, Column: err.Locations[0].Column}
+}
+
+// variableError names the variable a gqlparser variable error is about,
+// which it only gives in its path.
+func variableError(err error) error {
+	gqlErr, ok := err.(*gqlerror.Error)
+	if !ok {
+		return &ValidationError{Message: err.Error()}
+	}
+	message := gqlErr.Message
+	if len(gqlErr.Path) > 1 {
+		message = fmt.Sprintf("variable $%v %s", gqlErr.Path[1], message)
+	}
+	return &ValidationError{Message: message}
+}
+
+func selectOperation(doc *ast.QueryDocument, name string) (*ast.OperationDefinition, error) {
+	switch {
+	case name != "":
+		operation := doc.Operations.ForName(name)
+		if operation == nil {
+			return nil, &ValidationError{Message: fmt.Sprintf("unknown operation %q", name)}
+		}
+		return operation, nil
+	case len(doc.Operations) > 1:
+		return nil, &ValidationError{Message: "operationName is required when the document has several operations"}
+	case len(doc.Operations) == 0:
+		return nil, &ValidationError{Message: "the document has no operation"}
+	}
+	return doc.Operations[0], nil
+}
+
+// plannedField is a field the query selects, after fragments are expanded,
+// directives applied, fields with the same response key merged and
+// arguments coerced. typ is nil for __typename.
+type plannedField struct {
+	key      string
+	name     string
+	typ      *ast.Type
+	resolve  ResolveFunc
+	args     map[string]interface{}
+	children []*plannedField
+	location Location
+}
+
+// planner turns a validated operation into plannedFields, counting depth and
+// complexity as it goes so an oversized query is refused before it is
+// expanded in full.
+type planner struct {
+	schema        *Schema
+	doc           *ast.QueryDocument
+	variables     map[string]interface{}
+	maxDepth      int
+	maxComplexity int
+	complexity    int
+}
+
+// plan plans the selection sets, all selecting from object at depth.
+func (p *planner) plan(object *ast.Definition, selectionSets []ast.SelectionSet, depth int) ([]*plannedField, error) {
+	var keys []string
+	groups := map[string][]*ast.Field{}
+	for _, selections := range selectionSets {
+		if err := p.collect(selections, &keys, groups); err != nil {
+			return nil, err
+		}
+	}
+
+	planned := make([]*plannedField, 0, len(keys))
+	for _, key := range keys {
+		fields := groups[key]
+		first := fields[0]
+		location := positionLocation(first.Position)
+		if p.maxDepth > 0 && depth > p.maxDepth {
+			return nil, &LimitError{Limit: "depth", Max: p.maxDepth}
+		}
+		if first.Name == "__typename" {
+			planned = append(planned, &plannedField{key: key, name: first.Name, location: location})
+			continue
+		}
+		if isIntrospection(first.Name) {
+			return nil, &ValidationError{Message: "introspection is not supported", Location: location}
+		}
+		p.complexity++
+		if p.maxComplexity > 0 && p.complexity > p.maxComplexity {
+			return nil, &LimitError{Limit: "complexity", Max: p.maxComplexity}
+		}
+
+		definition := object.Fields.ForName(first.Name)
+		args, err := p.arguments(definition, first)
+		if err != nil {
+			return nil, err
+		}
+		field := &plannedField{key: key, name: first.Name, typ: definition.Type, resolve: p.schema.resolvers[object.Name][first.Name], args: args, location: location}
+		if child := p.schema.schema.Types[definition.Type.Name()]; child.Kind == ast.Object {
+			var children []ast.SelectionSet
+			for _, other := range fields {
+				children = append(children, other.SelectionSet)
+			}
+			if field.children, err = p.plan(child, children, depth+1); err != nil {
+				return nil, err
+			}
+		}
+		planned = append(planned, field)
+	}
+	return planned, nil
+}
+
+// collect gathers the fields selections select by response key, expanding
+// fragments and dropping what @skip and @include leave out. Validation has
+// made sure the fragments apply to the object and don't spread themselves.
+func (p *planner) collect(selections ast.SelectionSet, keys *[]string, groups map[string][]*ast.Field) error {
+	for _, selection := range selections {
+		var directives ast.DirectiveList
+		var children ast.SelectionSet
+		switch selection := selection.(type) {
+		case *ast.Field:
+			directives = selection.Directives
+		case *ast.InlineFragment:
+			directives, children = selection.Directives, selection.SelectionSet
+		case *ast.FragmentSpread:
+			directives, children = selection.Directives, p.doc.Fragments.ForName(selection.Name).SelectionSet
+		}
+		include, err := p.included(directives)
+		if err != nil {
+			return err
+		}
+		if !include {
+			continue
+		}
+
+		if field, ok := selection.(*ast.Field); ok {
+			if _, ok := groups[field.Alias]; !ok {
+				*keys = append(*keys, field.Alias)
+			}
+			groups[field.Alias] = append(groups[field.Alias], field)
+			continue
+		}
+		if err := p.collect(children, keys, groups); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// included applies @skip and @include. Other directives are refused rather
+// than ignored.
+func (p *planner) included(directives ast.DirectiveList) (bool, error) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			return false, &ValidationError{Message: fmt.Sprintf("directive @%s is not supported", directive.Name), Location: positionLocation(directive.Position)}
+		}
+		if condition, _ := directive.ArgumentMap(p.variables)["if"].(bool); condition == (directive.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// arguments returns the field's arguments with defaults filled in, coerced
+// to the Go types resolvers get. Null arguments are left out.
+func (p *planner) arguments(definition *ast.FieldDefinition, field *ast.Field) (map[string]interface{}, error) {
+	args := field.ArgumentMap(p.variables)
+	for _, arg := range definition.Arguments {
+		value, ok := args[arg.Name]
+		if !ok {
+			continue
+		}
+		if value == nil {
+			delete(args, arg.Name)
+			continue
+		}
+		coerced, ok := coerceInput(arg.Type, value)
+		if !ok {
+			return nil, &ValidationError{Message: fmt.Sprintf("argument %q must be a %s", arg.Name, arg.Type), Location: positionLocation(field.Position)}
+		}
+		args[arg.Name] = coerced
+	}
+	return args, nil
+}
+
+// coerceInput turns a validated argument value into the Go type resolvers
+// get for t. Literals arrive from gqlparser as int64 and variables from JSON
+// as float64, so numbers are where the work is.
+func coerceInput(t *ast.Type, v interface{}) (interface{}, bool) {
+	if v == nil {
+		return nil, true
+	}
+	if t.Elem != nil {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return coerceInput(t, []interface{}{v})
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			item, ok := coerceInput(t.Elem, rv.Index(i).Interface())
+			if !ok {
+				return nil, false
+			}
+			items[i] = item
+		}
+		return items, true
+	}
+
+	switch t.NamedType {
+	case "Int":
+		f, ok := toFloat(v)
+		if !ok || f != math.Trunc(f) || f < math.MinInt32 || f > math.MaxInt32 {
+			return nil, false
+		}
+		return int(f), true
+	case "Float":
+		return toFloat(v)
+	case "ID":
+		if s, ok := v.(string); ok {
+			return s, true
+		}
+		f, ok := toFloat(v)
+		if !ok || f != math.Trunc(f) {
+			return nil, false
+		}
+		return strconv.FormatFloat(f, 'f', 0, 64), true
+	}
+	return v, true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func positionLocation(position *ast.Position) Location {
+	if position == nil {
+		return Location{}
+	}
+	return Location{Line: position.Line, Column: position.Column}
+}
+
+// object is a response object, which keeps its keys in the order the query
+// selected them.
+type object struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newObject(size int) *object {
+	return &object{keys: make([]string, 0, size), values: make(map[string]interface{}, size)}
+}
+
+func (o *object) set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		b.Write(k)
+		b.WriteByte(':')
+		v, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// job resolves the fields of one object in the response.
+type job struct {
+	object *ast.Definition
+	source interface{}
+	fields []*plannedField
+	out    *object
+	path   []interface{}
+}
+
+// deferred is a thunk a resolver returned, with where its value goes.
+type deferred struct {
+	thunk Thunk
+	field *plannedField
+	set   func(interface{})
+	path  []interface{}
+}
+
+// executor resolves a planned query breadth first: every object at one depth
+// is resolved before any below it, so loaders see the keys of a whole level
+// at once.
+type executor struct {
+	ctx     context.Context
+	schema  *ast.Schema
+	present func(*Error) *Error
+	errors  []*Error
+}
+
+func (e *executor) run(query *ast.Definition, fields []*plannedField) *object {
+	data := newObject(len(fields))
+	level := []job{{object: query, fields: fields, out: data}}
+	for len(level) > 0 {
+		var next []job
+		var thunks []deferred
+		for _, j := range level {
+			for _, f := range j.fields {
+				out, key := j.out, f.key
+				set := func(v interface{}) { out.set(key, v) }
+				path := appendPath(j.path, key)
+				if f.typ == nil {
+					set(j.object.Name)
+					continue
+				}
+				set(nil)
+				value, err := f.resolve(ResolveParams{Context: e.ctx, Source: j.source, Args: f.args})
+				if err != nil {
+					e.fail(err, f, path)
+					continue
+				}
+				if thunk, ok := value.(Thunk); ok {
+					thunks = append(thunks, deferred{thunk: thunk, field: f, set: set, path: path})
+					continue
+				}
+				next = e.complete(next, f, f.typ, value, set, path)
+			}
+		}
+		for _, d := range thunks {
+			value, err := d.thunk()
+			if err != nil {
+				e.fail(err, d.field, d.path)
+				continue
+			}
+			next = e.complete(next, d.field, d.field.typ, value, d.set, d.path)
+		}
+		level = next
+	}
+	return data
+}
+
+// complete sets a resolved value of type t, queueing a job for each object
+// in it.
+func (e *executor) complete(next []job, f *plannedField, t *ast.Type, value interface{}, set func(interface{}), path []interface{}) []job {
+	if isNil(value) {
+		set(nil)
+		return next
+	}
+	if t.Elem != nil {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fail(fmt.Errorf("field %q resolved to %T, not a list", f.name, value), f, path)
+			set(nil)
+			return next
+		}
+		items := make([]interface{}, rv.Len())
+		set(items)
+		for i := range items {
+			i := i
+			next = e.complete(next, f, t.Elem, rv.Index(i).Interface(), func(v interface{}) { items[i] = v }, appendPath(path, i))
+		}
+		return next
+	}
+	if object := e.schema.Types[t.NamedType]; object.Kind == ast.Object {
+		out := newObject(len(f.children))
+		set(out)
+		return append(next, job{object: object, source: value, fields: f.children, out: out, path: path})
+	}
+	set(serializeLeaf(value))
+	return next
+}
+
+func (e *executor) fail(err error, f *plannedField, path []interface{}) {
+	e.errors = append(e.errors, e.present(&Error{
+		Message:   err.Error(),
+		Locations: []Location{f.location},
+		Path:      path,
+		err:       err,
+	}))
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path)+1)
+	copy(out, path)
+	out[len(path)] = elem
+	return out
+}
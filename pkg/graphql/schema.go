@@ -0,0 +1,164 @@
+// Package graphql runs read-only GraphQL queries. Schemas are written in
+// SDL and queries are parsed and validated by gqlparser, the parser gqlgen
+// is built on; this package only resolves the fields, through resolvers
+// registered by type and field name. Object, scalar and enum types are
+// supported. Mutations, subscriptions, interfaces, unions, input objects and
+// introspection are not.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Resolvers are a schema's resolvers by object type name, then field name.
+type Resolvers map[string]map[string]ResolveFunc
+
+// ResolveFunc resolves a field. It returns the field's value or a Thunk that
+// produces it; see Thunk. Objects are returned as whatever their own
+// resolvers take as Source, lists as slices, and scalars and enums as Go
+// values, which are sent with pointers followed and times as RFC3339 in UTC.
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// ResolveParams is what a field is resolved from. Args holds the arguments
+// with their defaults filled in: string, int, float64 or bool values, enum
+// values as strings, and lists of those as []interface{}. Arguments that
+// weren't given and have no default are absent.
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Thunk is a field value resolved later. The executor resolves every field
+// at one depth of the response before calling the thunks any of them
+// returned, so resolvers can queue keys with a loader and fetch them in one
+// batch when the first thunk runs.
+type Thunk func() (interface{}, error)
+
+// Schema is a read-only schema and the resolvers of its fields.
+type Schema struct {
+	schema    *ast.Schema
+	resolvers Resolvers
+}
+
+// NewSchema loads the schema sdl declares. Every field of every object type
+// needs a resolver, and every resolver a field. Output fields are nullable,
+// since a field whose resolver fails is null.
+func NewSchema(sdl string, resolvers Resolvers) (*Schema, error) {
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: sdl})
+	if err != nil {
+		return nil, err
+	}
+	if schema.Query == nil || schema.Mutation != nil || schema.Subscription != nil {
+		return nil, fmt.Errorf("the schema must declare a Query type and no mutations or subscriptions")
+	}
+
+	names := make([]string, 0, len(schema.Types))
+	for name := range schema.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		def := schema.Types[name]
+		if def.BuiltIn {
+			continue
+		}
+		switch def.Kind {
+		case ast.Scalar, ast.Enum:
+		case ast.Object:
+			for _, field := range def.Fields {
+				if isIntrospection(field.Name) {
+					continue
+				}
+				if field.Type.NonNull {
+					return nil, fmt.Errorf("field %s.%s must be nullable", name, field.Name)
+				}
+				if resolvers[name][field.Name] == nil {
+					return nil, fmt.Errorf("field %s.%s has no resolver", name, field.Name)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("type %s is of unsupported kind %s", name, def.Kind)
+		}
+	}
+	for typeName, fields := range resolvers {
+		for fieldName := range fields {
+			if def := schema.Types[typeName]; def == nil || def.Kind != ast.Object || def.Fields.ForName(fieldName) == nil {
+				return nil, fmt.Errorf("resolver for %s.%s has no field", typeName, fieldName)
+			}
+		}
+	}
+	return &Schema{schema: schema, resolvers: resolvers}, nil
+}
+
+// MustNewSchema is NewSchema for schemas fixed at compile time. It panics
+// when the schema doesn't load.
+func MustNewSchema(sdl string, resolvers Resolvers) *Schema {
+	s, err := NewSchema(sdl, resolvers)
+	if err != nil {
+		panic(fmt.Sprintf("graphql: %v", err))
+	}
+	return s
+}
+
+// isIntrospection reports whether a field is one of the introspection
+// fields gqlparser adds to the Query type.
+func isIntrospection(name string) bool {
+	return name == "__schema" || name == "__type"
+}
+
+// serializeLeaf turns a resolved scalar or enum value into what is sent,
+// following pointers. A nil pointer is null.
+func serializeLeaf(v interface{}) interface{} {
+	if t, ok := v.(time.Time); ok {
+		return t.UTC().Format(time.RFC3339)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+		if t, ok := rv.Interface().(time.Time); ok {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Invalid:
+		return nil
+	}
+	if stringer, ok := rv.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return rv.Interface()
+}
+
+// isNil reports whether v is nil or a nil pointer, slice or map.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Package i18n translates user-facing messages. Messages are identified by
+// keys such as "error.ttr_not_found" and looked up in the JSON catalogs under
+// locales/, one per language. Lookups fall back to DefaultLanguage and then
+// to the key itself, so a missing translation never produces an empty string.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLanguage is used when a request names no supported language and for
+// keys missing from another language's catalog.
+const DefaultLanguage = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var (
+	catalogs map[string]map[string]string
+	// keysByText maps each English message to its key, so code that still
+	// passes English text around (errors, response helpers) can be localized.
+	keysByText map[string]string
+)
+
+func init() {
+	if err := load(); err != nil {
+		panic(err)
+	}
+}
+
+func load() error {
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return fmt.Errorf("failed to read locales: %w", err)
+	}
+
+	catalogs = make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		data, err := localeFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read locale %s: %w", file.Name(), err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("failed to parse locale %s: %w", file.Name(), err)
+		}
+		catalogs[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+
+	if _, ok := catalogs[DefaultLanguage]; !ok {
+		return fmt.Errorf("missing %s locale", DefaultLanguage)
+	}
+	keysByText = make(map[string]string, len(catalogs[DefaultLanguage]))
+	for key, text := range catalogs[DefaultLanguage] {
+		keysByText[text] = key
+	}
+	return nil
+}
+
+// Languages returns the supported language codes in sorted order.
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Supported reports whether lang has a catalog.
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Translate renders key in lang, substituting {name} placeholders from
+// params.
+func Translate(lang, key string, params map[string]string) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		text, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	for name, value := range params {
+		text = strings.ReplaceAll(text, "{"+name+"}", value)
+	}
+	return text
+}
+
+// KeyFor returns the key of an English message, or "" if the catalog does not
+// know it.
+func KeyFor(text string) string {
+	return keysByText[text]
+}
+
+// Localize translates an English message into lang and returns it with its
+// key. Unknown messages are returned unchanged with an empty key.
+func Localize(lang, text string) (key, message string) {
+	key = KeyFor(text)
+	if key == "" {
+		return "", text
+	}
+	return key, Translate(lang, key, nil)
+}
+
+// Match picks the supported language that best satisfies an Accept-Language
+// header, comparing primary subtags only ("es-MX" selects "es"). It returns
+// DefaultLanguage when nothing matches.
+func Match(acceptLanguage string) string {
+	best, bestQ := DefaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseLanguageRange(part)
+		if tag == "" || q <= bestQ {
+			continue
+		}
+		if lang := Base(tag); Supported(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// Base lowercases a language tag and strips everything after its primary
+// subtag.
+func Base(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+func parseLanguageRange(part string) (string, float64) {
+	fields := strings.Split(part, ";")
+	tag := strings.TrimSpace(fields[0])
+	q := 1.0
+	for _, param := range fields[1:] {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || name != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", 0
+		}
+		q = parsed
+	}
+	return tag, q
+}
+
+type contextKey struct{}
+
+// WithLanguage returns a copy of ctx carrying lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language stored by WithLanguage, or DefaultLanguage.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return DefaultLanguage
+}
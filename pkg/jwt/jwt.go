@@ -12,12 +12,40 @@ import (
 	"github.com/google/uuid"
 )
 
+// What a token is for, carried in its typ claim. Only access tokens
+// authenticate API requests; the other types are checked by the flow that
+// issued them.
+const (
+	TokenTypeAccess    = "access"
+	TokenTypeChallenge = "challenge"
+	TokenTypeLink      = "link"
+)
+
+// Claims are a token's claims. TokenType tells access tokens from the other
+// types, which share the secret. Scope is an OAuth-style space-separated list
+// of scopes; when it is empty the token carries every scope, as tokens from
+// logging in do. Act is set on impersonation tokens: UserID is the user being
+// impersonated and Act names the admin acting as them, as in RFC 8693. Extra
+// holds whatever else a non-access token needs to carry.
 type Claims struct {
-	UserID uuid.UUID `json:"user_id"`
-	Email  string    `json:"email"`
+	TokenType string            `json:"typ"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Email     string            `json:"email"`
+	Role      string            `json:"role,omitempty"`
+	Scope     string            `json:"scope,omitempty"`
+	Act       *Actor            `json:"act,omitempty"`
+	Extra     map[string]string `json:"extra,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// Actor is the party acting on behalf of a token's user.
+type Actor struct {
+	Subject uuid.UUID `json:"sub"`
+	// AllowWrites lets the actor change things as the user. Without it an
+	// impersonation session is read-only.
+	AllowWrites bool `json:"allow_writes,omitempty"`
+}
+
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -32,19 +60,75 @@ type RefreshTokenData struct {
 var (
 	ErrInvalidToken = errors.New("invalid token")
 	ErrExpiredToken = errors.New("token has expired")
+	// ErrWrongTokenType is returned for a valid token of another type than
+	// the one asked for, e.g. a challenge token used as an access token.
+	ErrWrongTokenType = errors.New("wrong token type")
 )
 
-func GenerateAccessToken(userID uuid.UUID, email, secret string, duration time.Duration) (string, error) {
+func GenerateAccessToken(userID uuid.UUID, email, role, secret string, duration time.Duration) (string, error) {
+	claims := &Claims{
+		TokenType: TokenTypeAccess,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signAccessToken(claims, secret)
+}
+
+// GenerateImpersonationToken issues an access token for userID carrying
+// actor in its act claim. sessionID becomes the token's ID, so the session
+// can be ended before the token expires.
+func GenerateImpersonationToken(userID uuid.UUID, email, role string, actor Actor, sessionID string, secret string, expiresAt time.Time) (string, error) {
+	claims := &Claims{
+		TokenType: TokenTypeAccess,
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		Act:       &actor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signAccessToken(claims, secret)
+}
+
+// GenerateToken issues a token of tokenType for subject, such as a login
+// challenge or a link token, carrying extra. Access tokens come from
+// GenerateAccessToken, which sets what they need.
+func GenerateToken(tokenType string, subject uuid.UUID, extra map[string]string, secret string, duration time.Duration) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
+		TokenType: tokenType,
+		UserID:    subject,
+		Extra:     extra,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
 
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signedToken, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %w", tokenType, err)
+	}
+
+	return signedToken, nil
+}
+
+func signAccessToken(claims *Claims, secret string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString([]byte(secret))
 	if err != nil {
@@ -69,7 +153,16 @@ func GenerateRefreshToken() (*RefreshTokenData, error) {
 	}, nil
 }
 
+// ValidateAccessToken checks tokenString is an access token signed with
+// secret and returns its claims. Tokens of any other type, or without one,
+// get ErrWrongTokenType.
 func ValidateAccessToken(tokenString, secret string) (*Claims, error) {
+	return ValidateToken(tokenString, TokenTypeAccess, secret)
+}
+
+// ValidateToken checks tokenString is a tokenType token signed with secret
+// and returns its claims.
+func ValidateToken(tokenString, tokenType, secret string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -88,6 +181,9 @@ func ValidateAccessToken(tokenString, secret string) (*Claims, error) {
 	if !ok || !token.Valid {
 		return nil, ErrInvalidToken
 	}
+	if claims.TokenType != tokenType {
+		return nil, ErrWrongTokenType
+	}
 
 	return claims, nil
 }
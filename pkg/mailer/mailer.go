@@ -0,0 +1,17 @@
+package mailer
+
+import "context"
+
+// Message is an email to one recipient. Body is plain text; HTML, when set,
+// goes along with it for mail clients that show HTML.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	HTML    string
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,29 @@
+// Package mailevents verifies and parses the delivery events email providers
+// post back: bounces, complaints and deliveries, from SendGrid's event
+// webhook and from Amazon SES through SNS.
+package mailevents
+
+// Kind is what happened to a message sent to an address.
+type Kind string
+
+const (
+	// KindHardBounce means the address doesn't exist or refuses mail for
+	// good.
+	KindHardBounce Kind = "hard_bounce"
+	// KindSoftBounce means delivery failed for a reason that may pass, such
+	// as a full mailbox.
+	KindSoftBounce Kind = "soft_bounce"
+	// KindComplaint means the recipient marked the message as spam.
+	KindComplaint Kind = "complaint"
+	// KindDelivered means the message reached the mailbox.
+	KindDelivered Kind = "delivered"
+)
+
+// Event is one delivery event for one address. ID is the provider's ID for
+// it, so a redelivered event can be recognized.
+type Event struct {
+	ID     string
+	Email  string
+	Kind   Kind
+	Reason string
+}
@@ -0,0 +1,273 @@
+package mailevents
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNSMessageTypeHeader is set by SNS on every message it posts.
+const SNSMessageTypeHeader = "X-Amz-Sns-Message-Type"
+
+// Types of SNS messages.
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// ErrUnknownTopic is returned for SNS messages from a topic other than the
+// one configured.
+var ErrUnknownTopic = errors.New("mailevents: message from an unknown SNS topic")
+
+// snsCertHost matches the hosts SNS serves its signing certificates from.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSMessage is a message SNS posts to an HTTPS subscription.
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL"`
+}
+
+// ParseSNS reads an SNS message from body.
+func ParseSNS(body []byte) (*SNSMessage, error) {
+	var msg SNSMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("mailevents: invalid SNS message: %w", err)
+	}
+	return &msg, nil
+}
+
+// StringToSign is the text SNS signs: the message's fields, in order, each
+// name and value on their own line.
+func (m *SNSMessage) StringToSign() string {
+	var fields []string
+	if m.Type == SNSTypeNotification {
+		fields = []string{"Message", m.Message, "MessageId", m.MessageID}
+		if m.Subject != "" {
+			fields = append(fields, "Subject", m.Subject)
+		}
+		fields = append(fields, "Timestamp", m.Timestamp)
+	} else {
+		fields = []string{
+			"Message", m.Message,
+			"MessageId", m.MessageID,
+			"SubscribeURL", m.SubscribeURL,
+			"Timestamp", m.Timestamp,
+			"Token", m.Token,
+		}
+	}
+	fields = append(fields, "TopicArn", m.TopicArn, "Type", m.Type)
+	return strings.Join(fields, "\n") + "\n"
+}
+
+// SNSVerifier checks SNS messages come from TopicARN and carry a valid
+// signature. Signing certificates are fetched from SNS once and cached.
+type SNSVerifier struct {
+	TopicARN string
+	// Fetch downloads a signing certificate. It is replaced in tests.
+	Fetch func(ctx context.Context, certURL string) ([]byte, error)
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+func NewSNSVerifier(topicARN string) *SNSVerifier {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return &SNSVerifier{
+		TopicARN: topicARN,
+		Fetch: func(ctx context.Context, certURL string) ([]byte, error) {
+			return get(ctx, client, certURL)
+		},
+		certs: make(map[string]*x509.Certificate),
+	}
+}
+
+// Verify checks msg was signed by SNS for the verifier's topic.
+func (v *SNSVerifier) Verify(ctx context.Context, msg *SNSMessage) error {
+	if msg.TopicArn != v.TopicARN {
+		return ErrUnknownTopic
+	}
+	if msg.Signature == "" || msg.SigningCertURL == "" {
+		return ErrMissingSignature
+	}
+
+	var hash crypto.Hash
+	var digest []byte
+	switch msg.SignatureVersion {
+	case "1":
+		sum := sha1.Sum([]byte(msg.StringToSign()))
+		hash, digest = crypto.SHA1, sum[:]
+	case "2":
+		sum := sha256.Sum256([]byte(msg.StringToSign()))
+		hash, digest = crypto.SHA256, sum[:]
+	default:
+		return ErrInvalidSignature
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	cert, err := v.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (v *SNSVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	parsed, err := url.Parse(certURL)
+	if err != nil || parsed.Scheme != "https" || !snsCertHost.MatchString(parsed.Hostname()) || !strings.HasSuffix(parsed.Path, ".pem") {
+		return nil, ErrInvalidSignature
+	}
+
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	data, err := v.Fetch(ctx, certURL)
+	if err != nil {
+		return nil, fmt.Errorf("mailevents: failed to fetch signing certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidSignature
+	}
+	cert, err = x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	v.mu.Lock()
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// ConfirmSubscription visits the SubscribeURL of a verified subscription
+// confirmation, which starts SNS delivering the topic's messages.
+func ConfirmSubscription(ctx context.Context, msg *SNSMessage) error {
+	parsed, err := url.Parse(msg.SubscribeURL)
+	if err != nil || parsed.Scheme != "https" || !snsCertHost.MatchString(parsed.Hostname()) {
+		return fmt.Errorf("mailevents: invalid SubscribeURL %q", msg.SubscribeURL)
+	}
+	_, err = get(ctx, &http.Client{Timeout: 10 * time.Second}, msg.SubscribeURL)
+	return err
+}
+
+func get(ctx context.Context, client *http.Client, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: status %d", target, resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Bounce           *struct {
+		BounceType        string         `json:"bounceType"`
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplainedRecipients []sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+	Delivery *struct {
+		Recipients []string `json:"recipients"`
+	} `json:"delivery"`
+}
+
+// ParseSES reads the events of an SES notification, the Message of an SNS
+// notification. A Permanent bounce is hard and any other bounce soft. It
+// accepts both notifications and event publishing, which names the type
+// eventType.
+func ParseSES(msg *SNSMessage) ([]Event, error) {
+	var n sesNotification
+	if err := json.Unmarshal([]byte(msg.Message), &n); err != nil {
+		return nil, fmt.Errorf("mailevents: invalid SES notification: %w", err)
+	}
+	notificationType := n.NotificationType
+	if notificationType == "" {
+		notificationType = n.EventType
+	}
+
+	var events []Event
+	add := func(email string, kind Kind, reason string) {
+		if email != "" {
+			events = append(events, Event{ID: msg.MessageID + ":" + email, Email: email, Kind: kind, Reason: reason})
+		}
+	}
+	switch {
+	case notificationType == "Bounce" && n.Bounce != nil:
+		kind := KindSoftBounce
+		if n.Bounce.BounceType == "Permanent" {
+			kind = KindHardBounce
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			reason := r.DiagnosticCode
+			if reason == "" {
+				reason = n.Bounce.BounceSubType
+			}
+			add(r.EmailAddress, kind, reason)
+		}
+	case notificationType == "Complaint" && n.Complaint != nil:
+		for _, r := range n.Complaint.ComplainedRecipients {
+			add(r.EmailAddress, KindComplaint, "")
+		}
+	case notificationType == "Delivery" && n.Delivery != nil:
+		for _, email := range n.Delivery.Recipients {
+			add(email, KindDelivered, "")
+		}
+	}
+	return events, nil
+}
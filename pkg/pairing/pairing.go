@@ -0,0 +1,129 @@
+// Package pairing splits a roster into groups whose handicaps add up to
+// about the same total, so that no group is stacked with the best players.
+package pairing
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// epsilon absorbs float noise when comparing handicap totals.
+const epsilon = 1e-9
+
+// Player is a player to place. A nil Handicap counts as the default handicap
+// passed to Balance.
+type Player struct {
+	ID       uuid.UUID
+	Handicap *float64
+}
+
+// Group is a suggested group and the handicap total it was balanced on,
+// rounded to one decimal like handicaps themselves.
+type Group struct {
+	Players       []uuid.UUID
+	TotalHandicap float64
+}
+
+// Balance partitions players into the fewest groups of at most maxSize, with
+// sizes that differ by at most one, and keeps the spread between the highest
+// and lowest group total small. Players are dealt highest handicap first to
+// the group with the lowest total that has room, then pairs of players are
+// swapped between groups while that narrows the spread. The result only
+// depends on the set of players, not on their order.
+func Balance(players []Player, maxSize int, defaultHandicap float64) []Group {
+	if len(players) == 0 {
+		return nil
+	}
+	if maxSize < 1 {
+		maxSize = 1
+	}
+
+	sorted := make([]Player, len(players))
+	copy(sorted, players)
+	handicap := func(p Player) float64 {
+		if p.Handicap == nil {
+			return defaultHandicap
+		}
+		return *p.Handicap
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		hi, hj := handicap(sorted[i]), handicap(sorted[j])
+		if hi != hj {
+			return hi > hj
+		}
+		return sorted[i].ID.String() < sorted[j].ID.String()
+	})
+
+	count := (len(sorted) + maxSize - 1) / maxSize
+	capacity := make([]int, count)
+	for i := range capacity {
+		capacity[i] = len(sorted) / count
+		if i < len(sorted)%count {
+			capacity[i]++
+		}
+	}
+
+	groups := make([][]Player, count)
+	totals := make([]float64, count)
+	for _, p := range sorted {
+		best := -1
+		for i := range groups {
+			if len(groups[i]) < capacity[i] && (best < 0 || totals[i] < totals[best]-epsilon) {
+				best = i
+			}
+		}
+		groups[best] = append(groups[best], p)
+		totals[best] += handicap(p)
+	}
+
+	for improveBySwap(groups, totals, handicap) {
+	}
+
+	result := make([]Group, count)
+	for i, group := range groups {
+		ids := make([]uuid.UUID, len(group))
+		for j, p := range group {
+			ids[j] = p.ID
+		}
+		result[i] = Group{Players: ids, TotalHandicap: math.Round(totals[i]*10) / 10}
+	}
+	return result
+}
+
+// improveBySwap makes the first swap of two players in different groups
+// that narrows the spread of totals, and reports whether it found one.
+func improveBySwap(groups [][]Player, totals []float64, handicap func(Player) float64) bool {
+	current := spread(totals)
+	for a := range groups {
+		for b := a + 1; b < len(groups); b++ {
+			for i := range groups[a] {
+				for j := range groups[b] {
+					delta := handicap(groups[a][i]) - handicap(groups[b][j])
+					if delta == 0 {
+						continue
+					}
+					totals[a] -= delta
+					totals[b] += delta
+					if spread(totals) < current-epsilon {
+						groups[a][i], groups[b][j] = groups[b][j], groups[a][i]
+						return true
+					}
+					totals[a] += delta
+					totals[b] -= delta
+				}
+			}
+		}
+	}
+	return false
+}
+
+func spread(totals []float64) float64 {
+	low, high := totals[0], totals[0]
+	for _, total := range totals[1:] {
+		low = math.Min(low, total)
+		high = math.Max(high, total)
+	}
+	return high - low
+}
@@ -0,0 +1,111 @@
+// Package password checks new passwords against a configurable policy.
+package password
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule names a requirement of a Policy. The rules a password breaks are sent
+// to clients as is, so they can say what to fix.
+type Rule string
+
+const (
+	RuleMinLength Rule = "min_length"
+	RuleLowercase Rule = "lowercase"
+	RuleUppercase Rule = "uppercase"
+	RuleDigit     Rule = "digit"
+	RuleSymbol    Rule = "symbol"
+	RuleCommon    Rule = "common"
+	RuleEmail     Rule = "email"
+)
+
+// classes are the rules a Policy can require a character of, with how to
+// recognize one. A symbol is anything that isn't a letter or a digit.
+var classes = map[Rule]func(rune) bool{
+	RuleLowercase: unicode.IsLower,
+	RuleUppercase: unicode.IsUpper,
+	RuleDigit:     unicode.IsDigit,
+	RuleSymbol: func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	},
+}
+
+// IsClass reports whether rule is one a Policy can list in RequireClasses.
+func IsClass(rule Rule) bool {
+	_, ok := classes[rule]
+	return ok
+}
+
+// minEmailLength is the shortest email local part RejectEmail looks for;
+// shorter ones would turn down too many unrelated passwords.
+const minEmailLength = 3
+
+// Policy is what a new password must satisfy. MinLength counts characters,
+// not bytes. RequireClasses lists the kinds of character it must contain at
+// least one of. BanCommon refuses the passwords on the embedded list of
+// common ones, ignoring case, and RejectEmail passwords containing the part
+// of the user's email before the @. The zero Policy accepts anything.
+type Policy struct {
+	MinLength      int
+	RequireClasses []Rule
+	BanCommon      bool
+	RejectEmail    bool
+}
+
+// Check returns the rules password breaks for the user with email, in the
+// order they are listed above, or nil when it satisfies the policy.
+func (p Policy) Check(password, email string) []Rule {
+	var failed []Rule
+	if utf8.RuneCountInString(password) < p.MinLength {
+		failed = append(failed, RuleMinLength)
+	}
+	for _, class := range p.RequireClasses {
+		if !strings.ContainsFunc(password, classes[class]) {
+			failed = append(failed, class)
+		}
+	}
+	if p.BanCommon && IsCommon(password) {
+		failed = append(failed, RuleCommon)
+	}
+	if p.RejectEmail && containsEmail(password, email) {
+		failed = append(failed, RuleEmail)
+	}
+	return failed
+}
+
+//go:embed common.txt
+var commonList string
+
+// common holds the lowercased entries of common.txt. Blank lines and lines
+// starting with # are skipped.
+var common = func() map[string]bool {
+	set := make(map[string]bool)
+	for _, line := range strings.Split(commonList, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = true
+		}
+	}
+	return set
+}()
+
+// IsCommon reports whether password is on the list of common passwords,
+// ignoring case.
+func IsCommon(password string) bool {
+	return common[strings.ToLower(password)]
+}
+
+func containsEmail(password, email string) bool {
+	local := email
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		local = email[:at]
+	}
+	local = strings.ToLower(strings.TrimSpace(local))
+	if utf8.RuneCountInString(local) < minEmailLength {
+		return false
+	}
+	return strings.Contains(strings.ToLower(password), local)
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type window struct {
+	count   int
+	resetAt time.Time
+}
+
+// MemoryLimiter is a per-process RateLimiter. With several instances behind a
+// load balancer each one counts separately; use RedisLimiter there.
+type MemoryLimiter struct {
+	limit  int
+	period time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	nextSweep time.Time
+}
+
+func NewMemoryLimiter(limit int, period time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:   limit,
+		period:  period,
+		windows: make(map[string]*window),
+	}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.After(l.nextSweep) {
+		for k, w := range l.windows {
+			if !now.Before(w.resetAt) {
+				delete(l.windows, k)
+			}
+		}
+		l.nextSweep = now.Add(l.period)
+	}
+
+	w, ok := l.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &window{resetAt: now.Add(l.period)}
+		l.windows[key] = w
+	}
+	w.count++
+
+	return newResult(l.limit, w.count, w.resetAt), nil
+}
@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result describes the state of a key's window after a call to Allow.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RateLimiter counts requests per key in fixed windows. Implementations must
+// be safe for concurrent use.
+type RateLimiter interface {
+	Allow(ctx context.Context, key string) (Result, error)
+}
+
+func newResult(limit, count int, resetAt time.Time) Result {
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}
+}
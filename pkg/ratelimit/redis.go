@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/yourusername/golf_messenger/pkg/redis"
+)
+
+// incrementScript increments the window counter and starts its expiry on the
+// first hit, atomically so a crash cannot leave a counter without a TTL.
+var incrementScript = goredis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+// RedisLimiter is a RateLimiter whose counters are shared by every instance
+// connected to the same Redis.
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+	limit  int
+	period time.Duration
+}
+
+func NewRedisLimiter(client *redis.Client, prefix string, limit int, period time.Duration) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+		prefix: prefix,
+		limit:  limit,
+		period: period,
+	}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (Result, error) {
+	values, err := incrementScript.Run(ctx, l.client, []string{l.prefix + key}, l.period.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to increment rate limit counter: %w", err)
+	}
+
+	count, ttl := int(values[0]), time.Duration(values[1])*time.Millisecond
+	if ttl < 0 {
+		ttl = l.period
+	}
+
+	return newResult(l.limit, count, time.Now().Add(ttl)), nil
+}
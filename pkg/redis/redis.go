@@ -0,0 +1,38 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/yourusername/golf_messenger/internal/config"
+)
+
+// Client wraps the go-redis client shared by the Redis-backed cache and rate
+// limiter.
+type Client struct {
+	*goredis.Client
+}
+
+func NewClient(cfg *config.RedisConfig) (*Client, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &Client{Client: client}, nil
+}
+
+func (c *Client) HealthCheck(ctx context.Context) error {
+	return c.Ping(ctx).Err()
+}
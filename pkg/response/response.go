@@ -2,7 +2,11 @@ package response
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 )
 
 type Response struct {
@@ -12,10 +16,13 @@ type Response struct {
 	Message string      `json:"message,omitempty"`
 }
 
+// ErrorInfo carries the message translated into the response language along
+// with its catalog key, so clients can render their own text instead.
 type ErrorInfo struct {
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Details interface{} `json:"details,omitempty"`
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	MessageKey string      `json:"message_key,omitempty"`
+	Details    interface{} `json:"details,omitempty"`
 }
 
 func JSON(w http.ResponseWriter, statusCode int, data interface{}) {
@@ -56,62 +63,93 @@ func SuccessWithMessage(w http.ResponseWriter, statusCode int, message string, d
 }
 
 func Error(w http.ResponseWriter, statusCode int, code, message string) {
+	ErrorWithDetails(w, statusCode, code, message, nil)
+}
+
+func ErrorWithDetails(w http.ResponseWriter, statusCode int, code, message string, details interface{}) {
+	key, message := i18n.Localize(language(w), message)
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
 	response := Response{
 		Success: false,
 		Error: &ErrorInfo{
-			Code:    code,
-			Message: message,
+			Code:       code,
+			Message:    message,
+			MessageKey: key,
+			Details:    details,
 		},
 	}
 
 	json.NewEncoder(w).Encode(response)
 }
 
-func ErrorWithDetails(w http.ResponseWriter, statusCode int, code, message string, details interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+// Coded writes an error with a registered code, sent with the status the
+// registry maps it to.
+func Coded(w http.ResponseWriter, code errcode.Code, message string) {
+	CodedWithDetails(w, code, message, nil)
+}
 
-	response := Response{
-		Success: false,
-		Error: &ErrorInfo{
-			Code:    code,
-			Message: message,
-			Details: details,
-		},
+func CodedWithDetails(w http.ResponseWriter, code errcode.Code, message string, details interface{}) {
+	ErrorWithDetails(w, errcode.Status(code), string(code), message, details)
+}
+
+// FromError writes err with its code when it, or an error it wraps, is an
+// *errcode.Error. Any other error is a 500 with the fallback message, so
+// internal details never reach the client.
+func FromError(w http.ResponseWriter, err error, fallback string) {
+	var coded *errcode.Error
+	if errors.As(err, &coded) {
+		Coded(w, coded.Code, coded.Message)
+		return
 	}
+	InternalServerError(w, fallback)
+}
 
-	json.NewEncoder(w).Encode(response)
+// language returns the response language chosen by the Language middleware,
+// which it records in the Content-Language header.
+func language(w http.ResponseWriter) string {
+	if lang := w.Header().Get("Content-Language"); lang != "" {
+		return lang
+	}
+	return i18n.DefaultLanguage
 }
 
 func BadRequest(w http.ResponseWriter, message string) {
-	Error(w, http.StatusBadRequest, "BAD_REQUEST", message)
+	Coded(w, errcode.BadRequest, message)
 }
 
 func Unauthorized(w http.ResponseWriter, message string) {
-	Error(w, http.StatusUnauthorized, "UNAUTHORIZED", message)
+	Coded(w, errcode.Unauthorized, message)
 }
 
 func Forbidden(w http.ResponseWriter, message string) {
-	Error(w, http.StatusForbidden, "FORBIDDEN", message)
+	Coded(w, errcode.Forbidden, message)
 }
 
 func NotFound(w http.ResponseWriter, message string) {
-	Error(w, http.StatusNotFound, "NOT_FOUND", message)
+	Coded(w, errcode.NotFound, message)
 }
 
 func Conflict(w http.ResponseWriter, message string) {
-	Error(w, http.StatusConflict, "CONFLICT", message)
+	Coded(w, errcode.Conflict, message)
+}
+
+func PayloadTooLarge(w http.ResponseWriter, message string) {
+	Coded(w, errcode.PayloadTooLarge, message)
 }
 
 func UnprocessableEntity(w http.ResponseWriter, message string, details interface{}) {
-	ErrorWithDetails(w, http.StatusUnprocessableEntity, "VALIDATION_ERROR", message, details)
+	CodedWithDetails(w, errcode.ValidationError, message, details)
+}
+
+func TooManyRequests(w http.ResponseWriter, message string) {
+	Coded(w, errcode.RateLimited, message)
 }
 
 func InternalServerError(w http.ResponseWriter, message string) {
-	Error(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", message)
+	Coded(w, errcode.InternalServerError, message)
 }
 
 func Created(w http.ResponseWriter, data interface{}) {
@@ -0,0 +1,64 @@
+// Package sanitize cleans free text users write, such as notes and messages,
+// before it is stored, so clients can render it as HTML without escaping it
+// themselves.
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// strict strips every tag, drops the contents of script and style elements
+// and escapes what remains.
+var strict = bluemonday.StrictPolicy()
+
+var (
+	spaceRun   = regexp.MustCompile(` {2,}`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	// quotes only need escaping inside attributes, and text is never put in
+	// one, so they are kept readable.
+	quotes = strings.NewReplacer("&#39;", "'", "&#34;", `"`)
+)
+
+// Text returns s as plain text that is safe to render as HTML. Markup is
+// stripped and <, > and & are escaped. Invalid UTF-8, control characters
+// other than newlines and invisible bidi controls are dropped; tabs and other
+// spaces become plain spaces, runs of them collapse to one, more than one
+// blank line collapses to one, and both ends of every line are trimmed. Text
+// is idempotent, so sanitized text passes through unchanged.
+func Text(s string) string {
+	s = quotes.Replace(strict.Sanitize(strings.ToValidUTF8(s, "")))
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n':
+			return r
+		case r == '\r':
+			return '\n'
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r):
+			return -1
+		}
+		return r
+	}, s)
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(line, " "))
+	}
+	s = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
+
+// TextPtr is Text for optional fields; nil stays nil.
+func TextPtr(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	text := Text(*s)
+	return &text
+}
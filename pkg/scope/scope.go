@@ -0,0 +1,88 @@
+// Package scope is the taxonomy of permissions a token can carry. Every
+// authenticated route requires one scope: read scopes for GET routes and
+// write scopes for the routes that change something. Access tokens from
+// logging in carry every scope; personal API tokens carry the ones chosen
+// when they were created.
+package scope
+
+import "fmt"
+
+// Scope is a permission in "action:resource" form.
+type Scope string
+
+const (
+	ReadProfile        Scope = "read:profile"
+	WriteProfile       Scope = "write:profile"
+	ReadTTRs           Scope = "read:ttrs"
+	WriteTTRs          Scope = "write:ttrs"
+	ReadInvitations    Scope = "read:invitations"
+	WriteInvitations   Scope = "write:invitations"
+	ReadMessages       Scope = "read:messages"
+	WriteMessages      Scope = "write:messages"
+	ReadOrganizations  Scope = "read:organizations"
+	WriteOrganizations Scope = "write:organizations"
+	ReadLeagues        Scope = "read:leagues"
+	WriteLeagues       Scope = "write:leagues"
+	ReadTournaments    Scope = "read:tournaments"
+	WriteTournaments   Scope = "write:tournaments"
+	ReadWebhooks       Scope = "read:webhooks"
+	WriteWebhooks      Scope = "write:webhooks"
+	// Admin also needs the ADMIN role; the scope only lets a token use it.
+	Admin Scope = "admin"
+)
+
+var all = []Scope{
+	ReadProfile, WriteProfile,
+	ReadTTRs, WriteTTRs,
+	ReadInvitations, WriteInvitations,
+	ReadMessages, WriteMessages,
+	ReadOrganizations, WriteOrganizations,
+	ReadLeagues, WriteLeagues,
+	ReadTournaments, WriteTournaments,
+	ReadWebhooks, WriteWebhooks,
+	Admin,
+}
+
+var known = func() map[Scope]bool {
+	m := make(map[Scope]bool, len(all))
+	for _, s := range all {
+		m[s] = true
+	}
+	return m
+}()
+
+// All returns every scope.
+func All() []Scope {
+	return append([]Scope(nil), all...)
+}
+
+// ParseList parses scope names, dropping duplicates. The result is in the
+// order All lists scopes, whatever order names are in.
+func ParseList(names []string) ([]Scope, error) {
+	requested := make(map[Scope]bool, len(names))
+	for _, name := range names {
+		s := Scope(name)
+		if !known[s] {
+			return nil, fmt.Errorf("unknown scope %q", name)
+		}
+		requested[s] = true
+	}
+
+	scopes := make([]Scope, 0, len(requested))
+	for _, s := range all {
+		if requested[s] {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
+
+// Has reports whether granted includes s.
+func Has(granted []Scope, s Scope) bool {
+	for _, g := range granted {
+		if g == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package signing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/yourusername/golf_messenger/pkg/redis"
+)
+
+// NonceStore remembers the nonces of verified requests. Implementations must
+// be safe for concurrent use.
+type NonceStore interface {
+	// Claim records key for ttl and reports whether it was not already
+	// recorded.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore is a per-process NonceStore. With several instances
+// behind a load balancer a request could be replayed against another
+// instance; use RedisNonceStore there.
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	expiries  map[string]time.Time
+	nextSweep time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{expiries: make(map[string]time.Time)}
+}
+
+func (s *MemoryNonceStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.After(s.nextSweep) {
+		for k, expiresAt := range s.expiries {
+			if !now.Before(expiresAt) {
+				delete(s.expiries, k)
+			}
+		}
+		s.nextSweep = now.Add(ttl)
+	}
+
+	if expiresAt, ok := s.expiries[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	s.expiries[key] = now.Add(ttl)
+	return true, nil
+}
+
+// RedisNonceStore is a NonceStore shared by every instance connected to the
+// same Redis.
+type RedisNonceStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisNonceStore(client *redis.Client, prefix string) *RedisNonceStore {
+	return &RedisNonceStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisNonceStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	fresh, err := s.client.SetNX(ctx, s.prefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim nonce: %w", err)
+	}
+	return fresh, nil
+}
@@ -0,0 +1,225 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kinds of /golf commands.
+const (
+	CommandCreate = "create"
+	CommandLink   = "link"
+	CommandHelp   = "help"
+)
+
+// DefaultMaxPlayers is the group size when a create command doesn't give one.
+const DefaultMaxPlayers = 4
+
+// MaxPlayers is the largest group a create command accepts.
+const MaxPlayers = 8
+
+// Usage explains the create command, for help and error replies.
+const Usage = "Usage: `/golf <course> <date> <time> [players]`, e.g. `/golf Bethpage Black 2024-06-01 07:30 4`. " +
+	"Dates can be 2024-06-01, 6/1, 6/1/2024, today, tomorrow or a weekday; times 07:30, 7:30am or 7am. " +
+	"Players default to 4. Type `/golf link` to link your Slack account first."
+
+var (
+	ErrMissingCourse     = errors.New("give the course name before the date")
+	ErrMissingDate       = errors.New("give the date after the course name")
+	ErrMissingTime       = errors.New("give the tee time after the date")
+	ErrInvalidPlayers    = fmt.Errorf("players must be between 1 and %d", MaxPlayers)
+	ErrUnterminatedQuote = errors.New("the course name is missing its closing quote")
+)
+
+// Command is a parsed /golf command. The TTR fields are only set for
+// CommandCreate; TeeDate is a date at midnight UTC and TeeTime a time of day
+// on January 1st of year 0, the way the TTR API parses them.
+type Command struct {
+	Kind       string
+	CourseName string
+	TeeDate    time.Time
+	TeeTime    time.Time
+	MaxPlayers int
+}
+
+// Parse reads the text typed after /golf. An empty text or "help" asks for
+// help and "link" for a linking code; anything else creates a TTR from
+// "<course> <date> <time> [players]", where the course may be quoted.
+// Relative dates are resolved against now.
+func Parse(text string, now time.Time) (Command, error) {
+	text = strings.TrimSpace(text)
+	switch strings.ToLower(text) {
+	case "", CommandHelp:
+		return Command{Kind: CommandHelp}, nil
+	case CommandLink:
+		return Command{Kind: CommandLink}, nil
+	}
+
+	quoted, tokens, err := splitQuotedCourse(text)
+	if err != nil {
+		return Command{}, err
+	}
+	cmd := Command{Kind: CommandCreate, MaxPlayers: DefaultMaxPlayers}
+
+	// Read from the end: [players], time, date; what's left is the course.
+	if n := len(tokens); n > 0 {
+		if players, ok := parsePlayers(tokens[n-1]); ok {
+			if players < 1 || players > MaxPlayers {
+				return Command{}, ErrInvalidPlayers
+			}
+			cmd.MaxPlayers = players
+			tokens = tokens[:n-1]
+		}
+	}
+
+	teeTime, consumed, ok := parseTimeSuffix(tokens)
+	if !ok {
+		return Command{}, ErrMissingTime
+	}
+	cmd.TeeTime = teeTime
+	tokens = dropFiller(tokens[:len(tokens)-consumed], "at")
+
+	if len(tokens) == 0 {
+		return Command{}, ErrMissingDate
+	}
+	teeDate, ok := parseDate(tokens[len(tokens)-1], now)
+	if !ok {
+		return Command{}, ErrMissingDate
+	}
+	cmd.TeeDate = teeDate
+	tokens = dropFiller(tokens[:len(tokens)-1], "on")
+
+	switch {
+	case quoted == "":
+		cmd.CourseName = strings.Join(tokens, " ")
+	case len(tokens) == 0:
+		cmd.CourseName = quoted
+	default:
+		return Command{}, ErrMissingDate
+	}
+	if cmd.CourseName == "" {
+		return Command{}, ErrMissingCourse
+	}
+	return cmd, nil
+}
+
+// splitQuotedCourse splits off a course name in straight or curly double
+// quotes, as Slack clients send either. Without quotes course is empty and
+// every word is a token.
+func splitQuotedCourse(text string) (course string, tokens []string, err error) {
+	open, size := utf8.DecodeRuneInString(text)
+	if open != '"' && open != '“' {
+		return "", strings.Fields(text), nil
+	}
+	text = text[size:]
+	end := strings.IndexAny(text, `"”`)
+	if end < 0 {
+		return "", nil, ErrUnterminatedQuote
+	}
+	course = strings.TrimSpace(text[:end])
+	if course == "" {
+		return "", nil, ErrMissingCourse
+	}
+	_, size = utf8.DecodeRuneInString(text[end:])
+	return course, strings.Fields(text[end+size:]), nil
+}
+
+// dropFiller drops a trailing filler word, as in "tomorrow at 7am".
+func dropFiller(tokens []string, word string) []string {
+	if n := len(tokens); n > 0 && strings.EqualFold(tokens[n-1], word) {
+		return tokens[:n-1]
+	}
+	return tokens
+}
+
+// parsePlayers reads a group size written as 4, 4p, x4 or 4players.
+func parsePlayers(token string) (int, bool) {
+	token = strings.ToLower(token)
+	token = strings.TrimPrefix(token, "x")
+	for _, suffix := range []string{"players", "player", "p"} {
+		if trimmed := strings.TrimSuffix(token, suffix); trimmed != token {
+			token = trimmed
+			break
+		}
+	}
+	players, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, false
+	}
+	return players, true
+}
+
+// parseTimeSuffix reads the time at the end of tokens, either one token
+// (07:30, 7:30am, 7am) or a time and a separate am/pm, and returns how many
+// tokens it used.
+func parseTimeSuffix(tokens []string) (time.Time, int, bool) {
+	n := len(tokens)
+	if n >= 2 {
+		meridiem := strings.ToLower(tokens[n-1])
+		if meridiem == "am" || meridiem == "pm" {
+			if t, ok := parseTime(tokens[n-2] + meridiem); ok {
+				return t, 2, true
+			}
+		}
+	}
+	if n >= 1 {
+		if t, ok := parseTime(tokens[n-1]); ok {
+			return t, 1, true
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+func parseTime(token string) (time.Time, bool) {
+	token = strings.ToLower(token)
+	for _, layout := range []string{"15:04", "3:04pm", "3pm"} {
+		if t, err := time.Parse(layout, token); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDate reads an ISO date, a US month/day with an optional year, today,
+// tomorrow or a weekday. Dates without a year and weekdays are the next
+// such day from now, today included.
+func parseDate(token string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	lower := strings.ToLower(token)
+
+	switch lower {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if lower == name || lower == name[:3] {
+			return today.AddDate(0, 0, (int(day)-int(today.Weekday())+7)%7), true
+		}
+	}
+
+	for _, layout := range []string{"2006-01-02", "1/2/2006", "1/2/06"} {
+		if t, err := time.Parse(layout, token); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse("1/2", token); err == nil {
+		year := today.Year()
+		if time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Before(today) {
+			year++
+		}
+		date := time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Day() != t.Day() {
+			// February 29th in a year that has none.
+			return time.Time{}, false
+		}
+		return date, true
+	}
+	return time.Time{}, false
+}
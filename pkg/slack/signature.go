@@ -0,0 +1,63 @@
+// Package slack verifies Slack's signed requests and parses the /golf slash
+// command.
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers Slack signs its requests with.
+const (
+	TimestampHeader = "X-Slack-Request-Timestamp"
+	SignatureHeader = "X-Slack-Signature"
+)
+
+// MaxRequestAge is how old a signed request may be before it is refused as a
+// possible replay.
+const MaxRequestAge = 5 * time.Minute
+
+var (
+	ErrMissingSignature = errors.New("slack: missing request signature")
+	ErrInvalidSignature = errors.New("slack: invalid request signature")
+	ErrStaleRequest     = errors.New("slack: request timestamp too old")
+)
+
+// Sign returns the signature Slack sends for body at timestamp: "v0=" and
+// the hex HMAC-SHA256 of "v0:<timestamp>:<body>" keyed with the app's
+// signing secret.
+func Sign(signingSecret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that body was signed by Slack with signingSecret no more than
+// MaxRequestAge before now.
+func Verify(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(TimestampHeader)
+	signature := header.Get(SignatureHeader)
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > MaxRequestAge || age < -MaxRequestAge {
+		return ErrStaleRequest
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(signingSecret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+// MemoryStorage keeps objects in process memory. It is meant for tests and
+// local development; presigned URLs are not servable.
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{objects: make(map[string]memoryObject)}
+}
+
+func (s *MemoryStorage) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, body); err != nil {
+		return fmt.Errorf("failed to read object body: %w", err)
+	}
+
+	s.mu.Lock()
+	s.objects[key] = memoryObject{data: buf.Bytes(), contentType: contentType}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStorage) DeleteObject(ctx context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.objects, key)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStorage) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	s.mu.RLock()
+	_, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("object %q not found", key)
+	}
+
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("memory://%s?expires=%d", url.PathEscape(key), expires), nil
+}
+
+// ObjectURL returns a memory:// URL for key.
+func (s *MemoryStorage) ObjectURL(key string) string {
+	return "memory://" + key
+}
+
+func (s *MemoryStorage) DeleteFile(ctx context.Context, fileURL string) error {
+	if !strings.HasPrefix(fileURL, "memory://") {
+		return fmt.Errorf("invalid memory URL %q", fileURL)
+	}
+	return s.DeleteObject(ctx, strings.TrimPrefix(fileURL, "memory://"))
+}
+
+// PresignPutURL returns a placeholder URL; objects are uploaded to
+// MemoryStorage with PutObject.
+func (s *MemoryStorage) PresignPutURL(ctx context.Context, key, contentType string, size int64, ttl time.Duration) (string, error) {
+	expires := time.Now().Add(ttl).Unix()
+	return fmt.Sprintf("memory://%s?expires=%d&method=PUT", url.PathEscape(key), expires), nil
+}
+
+func (s *MemoryStorage) ObjectSize(ctx context.Context, key string) (int64, error) {
+	s.mu.RLock()
+	object, ok := s.objects[key]
+	s.mu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("object %q not found", key)
+	}
+	return int64(len(object.data)), nil
+}
+
+// Has reports whether an object is stored under key.
+func (s *MemoryStorage) Has(key string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.objects[key]
+	return ok
+}
@@ -4,19 +4,24 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"path/filepath"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/google/uuid"
 	"github.com/yourusername/golf_messenger/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/yourusername/golf_messenger/pkg/storage")
+
 type S3Client struct {
 	client     *s3.Client
+	presigner  *s3.PresignClient
 	bucketName string
 }
 
@@ -27,9 +32,9 @@ func NewS3Client(cfg *config.AWSConfig) (*S3Client, error) {
 	var err error
 
 	if cfg.S3Endpoint != "" {
-		awsCfg, err = config.LoadDefaultConfig(ctx,
-			config.WithRegion(cfg.Region),
-			config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+		awsCfg, err = awsconfig.LoadDefaultConfig(ctx,
+			awsconfig.WithRegion(cfg.Region),
+			awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 				cfg.AccessKeyID,
 				cfg.SecretAccessKey,
 				"",
@@ -46,13 +51,14 @@ func NewS3Client(cfg *config.AWSConfig) (*S3Client, error) {
 
 		return &S3Client{
 			client:     s3Client,
+			presigner:  s3.NewPresignClient(s3Client),
 			bucketName: cfg.S3BucketName,
 		}, nil
 	}
 
-	awsCfg, err = config.LoadDefaultConfig(ctx,
-		config.WithRegion(cfg.Region),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+	awsCfg, err = awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(cfg.Region),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
 			cfg.AccessKeyID,
 			cfg.SecretAccessKey,
 			"",
@@ -66,47 +72,114 @@ func NewS3Client(cfg *config.AWSConfig) (*S3Client, error) {
 
 	return &S3Client{
 		client:     s3Client,
+		presigner:  s3.NewPresignClient(s3Client),
 		bucketName: cfg.S3BucketName,
 	}, nil
 }
 
-func (s *S3Client) UploadFile(ctx context.Context, file io.Reader, filename string, contentType string) (string, error) {
-	ext := filepath.Ext(filename)
-	key := fmt.Sprintf("avatars/%s%s", uuid.New().String(), ext)
+// ObjectURL returns the public URL of key.
+func (s *S3Client) ObjectURL(key string) string {
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucketName, key)
+}
+
+func (s *S3Client) DeleteFile(ctx context.Context, fileURL string) error {
+	key, err := s.extractKeyFromURL(fileURL)
+	if err != nil {
+		return err
+	}
+
+	return s.DeleteObject(ctx, key)
+}
+
+func (s *S3Client) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	ctx, span := tracer.Start(ctx, "s3.PutObject", trace.WithAttributes(
+		attribute.String("aws.s3.bucket", s.bucketName),
+		attribute.String("aws.s3.key", key),
+	))
+	defer span.End()
 
 	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucketName),
 		Key:         aws.String(key),
-		Body:        file,
+		Body:        body,
 		ContentType: aws.String(contentType),
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %w", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upload failed")
+		return fmt.Errorf("failed to upload file to S3: %w", err)
 	}
 
-	url := fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucketName, key)
-	return url, nil
+	return nil
 }
 
-func (s *S3Client) DeleteFile(ctx context.Context, fileURL string) error {
-	key, err := s.extractKeyFromURL(fileURL)
-	if err != nil {
-		return err
-	}
+func (s *S3Client) DeleteObject(ctx context.Context, key string) error {
+	ctx, span := tracer.Start(ctx, "s3.DeleteObject", trace.WithAttributes(
+		attribute.String("aws.s3.bucket", s.bucketName),
+		attribute.String("aws.s3.key", key),
+	))
+	defer span.End()
 
-	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucketName),
 		Key:    aws.String(key),
 	})
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "delete failed")
 		return fmt.Errorf("failed to delete file from S3: %w", err)
 	}
 
 	return nil
 }
 
+func (s *S3Client) PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 URL: %w", err)
+	}
+	return req.URL, nil
+}
+
+// PresignPutURL returns a URL that uploads exactly size bytes of contentType
+// to key. S3 rejects uploads whose Content-Length or Content-Type differ.
+func (s *S3Client) PresignPutURL(ctx context.Context, key, contentType string, size int64, ttl time.Duration) (string, error) {
+	req, err := s.presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign S3 upload URL: %w", err)
+	}
+	return req.URL, nil
+}
+
+func (s *S3Client) ObjectSize(ctx context.Context, key string) (int64, error) {
+	ctx, span := tracer.Start(ctx, "s3.HeadObject", trace.WithAttributes(
+		attribute.String("aws.s3.bucket", s.bucketName),
+		attribute.String("aws.s3.key", key),
+	))
+	defer span.End()
+
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "head failed")
+		return 0, fmt.Errorf("failed to stat S3 object: %w", err)
+	}
+	return aws.ToInt64(out.ContentLength), nil
+}
+
 func (s *S3Client) extractKeyFromURL(fileURL string) (string, error) {
-	baseURL := fmt.Sprintf("https://%s.s3.amazonaws.com/", s.bucketName)
+	baseURL := s.ObjectURL("")
 	if len(fileURL) <= len(baseURL) {
 		return "", fmt.Errorf("invalid S3 URL format")
 	}
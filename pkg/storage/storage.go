@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Storage stores objects by key. Implementations must be safe for concurrent
+// use.
+type Storage interface {
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) error
+	DeleteObject(ctx context.Context, key string) error
+	// PresignGetURL returns a URL that can download the object until ttl
+	// elapses.
+	PresignGetURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// PublicStorage stores objects that are served from stable public URLs, such
+// as avatars, and lets clients upload them directly.
+type PublicStorage interface {
+	Storage
+	// ObjectURL returns the public URL of the object stored under key.
+	ObjectURL(key string) string
+	// DeleteFile deletes the object behind a URL returned by ObjectURL.
+	DeleteFile(ctx context.Context, fileURL string) error
+	// PresignPutURL returns a URL that uploads exactly size bytes of
+	// contentType to key until ttl elapses.
+	PresignPutURL(ctx context.Context, key, contentType string, size int64, ttl time.Duration) (string, error)
+	// ObjectSize returns the size in bytes of the object stored under key.
+	ObjectSize(ctx context.Context, key string) (int64, error)
+}
@@ -1,54 +1,145 @@
 package validator
 
 import (
-	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
 )
 
 var validate *validator.Validate
 
+// enums maps custom validation tags to the values they accept.
+var enums = map[string][]string{
+	"ttr_status": {
+		string(models.TTRStatusOpen),
+		string(models.TTRStatusConfirmed),
+		string(models.TTRStatusCancelled),
+		string(models.TTRStatusCompleted),
+	},
+	"player_status": playerStatuses(),
+	"invitation_response": {
+		string(models.InvitationStatusYes),
+		string(models.InvitationStatusNo),
+		string(models.InvitationStatusMaybe),
+	},
+	"ttr_visibility": {
+		models.TTRVisibilityPrivate,
+		models.TTRVisibilityFriends,
+		models.TTRVisibilityPublic,
+	},
+	"weekday": models.Weekdays,
+}
+
+func playerStatuses() []string {
+	var values []string
+	for _, status := range models.AllPlayerStatuses() {
+		values = append(values, string(status))
+	}
+	return values
+}
+
 func init() {
 	validate = validator.New()
+	// Report fields by the names clients send rather than Go field names.
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	for tag, allowed := range enums {
+		allowed := allowed
+		validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			value := fl.Field().String()
+			for _, v := range allowed {
+				if value == v {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	validate.RegisterValidation("time_of_day", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse("15:04", fl.Field().String())
+		return err == nil
+	})
 }
 
 func Validate(data interface{}) error {
 	return validate.Struct(data)
 }
 
-func FormatValidationErrors(err error) map[string]string {
-	errors := make(map[string]string)
+// FieldError describes one failed constraint on a request field. Field is the
+// JSON path of the value (e.g. "player_ids[2]"), Rule the validate tag that
+// failed and Param its argument, if any. Allowed lists the accepted values
+// when Rule is an enum tag.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Rule    string   `json:"rule"`
+	Param   string   `json:"param,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
+	Message string   `json:"message"`
+}
+
+// FormatValidationErrors lists the failed constraints in err with messages
+// in lang, in the order the fields were validated.
+func FormatValidationErrors(lang string, err error) []FieldError {
+	errors := []FieldError{}
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldError := range validationErrors {
-			field := strings.ToLower(fieldError.Field())
-			errors[field] = getErrorMessage(fieldError)
+			errors = append(errors, FieldError{
+				Field:   fieldPath(fieldError),
+				Rule:    fieldError.Tag(),
+				Param:   fieldError.Param(),
+				Allowed: enums[fieldError.Tag()],
+				Message: getErrorMessage(lang, fieldError),
+			})
 		}
 	}
 
 	return errors
 }
 
-func getErrorMessage(fe validator.FieldError) string {
+// fieldPath drops the struct name the namespace starts with.
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func getErrorMessage(lang string, fe validator.FieldError) string {
+	key := "validation.invalid"
+	param := fe.Param()
 	switch fe.Tag() {
-	case "required":
-		return fmt.Sprintf("%s is required", fe.Field())
-	case "email":
-		return "Invalid email format"
-	case "min":
-		return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
-	case "max":
-		return fmt.Sprintf("%s must not exceed %s characters", fe.Field(), fe.Param())
-	case "gte":
-		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
-	case "lte":
-		return fmt.Sprintf("%s must be less than or equal to %s", fe.Field(), fe.Param())
-	case "eqfield":
-		return fmt.Sprintf("%s must match %s", fe.Field(), fe.Param())
-	default:
-		return fmt.Sprintf("%s is invalid", fe.Field())
+	case "required", "email", "uuid", "gte", "lte", "eqfield", "unique", "time_of_day":
+		key = "validation." + fe.Tag()
+	case "oneof":
+		key = "validation.oneof"
+		param = strings.Join(strings.Fields(param), ", ")
+	case "ttr_status", "player_status", "invitation_response", "ttr_visibility", "weekday":
+		key = "validation.oneof"
+		param = strings.Join(enums[fe.Tag()], ", ")
+	case "min", "max":
+		key = "validation." + fe.Tag()
+		switch fe.Kind() {
+		case reflect.String:
+			key += "_length"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			key += "_items"
+		}
 	}
+	return i18n.Translate(lang, key, map[string]string{"param": param})
 }
 
 func GetValidator() *validator.Validate {
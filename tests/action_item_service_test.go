@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+)
+
+func TestActionItemService_ListActionItems(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	actionItemService := service.NewActionItemService(memory.NewActionItemRepository(store))
+
+	me := uuid.New()
+	other := uuid.New()
+	now := time.Now()
+
+	createTTR := func(captainID uuid.UUID, days int, course string) *models.TTR {
+		ttr := &models.TTR{
+			CourseName:      course,
+			TeeDate:         now.AddDate(0, 0, days).Truncate(24 * time.Hour),
+			TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: captainID,
+			CaptainUserID:   captainID,
+			Status:          models.TTRStatusOpen,
+			Visibility:      models.TTRVisibilityPublic,
+		}
+		require.NoError(t, ttrRepo.Create(ctx, ttr))
+		return ttr
+	}
+	invite := func(ttr *models.TTR, inviteeID uuid.UUID) *models.Invitation {
+		invitation := &models.Invitation{TTRID: ttr.ID, InviterUserID: ttr.CaptainUserID, InviteeUserID: inviteeID, Status: models.InvitationStatusPending}
+		require.NoError(t, invitationRepo.Create(ctx, invitation))
+		return invitation
+	}
+
+	// Invited by someone else, with an RSVP deadline before the round.
+	invited := createTTR(other, 5, "Pebble Beach")
+	deadline := now.Add(60 * time.Hour)
+	invited.RSVPDeadline = &deadline
+	require.NoError(t, ttrRepo.Update(ctx, invited))
+	invitation := invite(invited, me)
+
+	// Invited, but the RSVP deadline passed.
+	lapsed := createTTR(other, 4, "Torrey Pines")
+	passed := now.Add(-time.Hour)
+	lapsed.RSVPDeadline = &passed
+	require.NoError(t, ttrRepo.Update(ctx, lapsed))
+	invite(lapsed, me)
+
+	// Captained rounds with unanswered invitations, soon and later.
+	soon := createTTR(me, 2, "Bandon Dunes")
+	invite(soon, other)
+	later := createTTR(me, 10, "Whistling Straits")
+	invite(later, other)
+
+	// A MAYBE tomorrow, and one on a round that already teed off.
+	maybe := createTTR(other, 1, "Kiawah Island")
+	require.NoError(t, ttrRepo.AddPlayer(ctx, maybe.ID, me, models.TTRPlayerStatusMaybe))
+	teedOff := createTTR(other, -1, "Sawgrass")
+	require.NoError(t, ttrRepo.AddPlayer(ctx, teedOff.ID, me, models.TTRPlayerStatusMaybe))
+
+	t.Run("merged and ordered by urgency", func(t *testing.T) {
+		items, err := actionItemService.ListActionItems(ctx, me, service.DefaultActionItemHorizon)
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+
+		assert.Equal(t, service.ActionItemMaybeStatus, items[0].Kind)
+		assert.Equal(t, maybe.ID, items[0].TTRID)
+		assert.Nil(t, items[0].InvitationID)
+		assert.Equal(t, maybe.TeeDateTime(), items[0].DueAt)
+
+		assert.Equal(t, service.ActionItemUnansweredInvitations, items[1].Kind)
+		assert.Equal(t, soon.ID, items[1].TTRID)
+		assert.Equal(t, "Bandon Dunes", items[1].CourseName)
+
+		assert.Equal(t, service.ActionItemPendingInvitation, items[2].Kind)
+		assert.Equal(t, invited.ID, items[2].TTRID)
+		require.NotNil(t, items[2].InvitationID)
+		assert.Equal(t, invitation.ID, *items[2].InvitationID)
+		assert.True(t, deadline.Equal(items[2].DueAt), "due at the RSVP deadline")
+	})
+
+	t.Run("horizon widens the captained rounds", func(t *testing.T) {
+		items, err := actionItemService.ListActionItems(ctx, me, 14*24*time.Hour)
+		require.NoError(t, err)
+		require.Len(t, items, 4)
+		assert.Equal(t, service.ActionItemUnansweredInvitations, items[3].Kind)
+		assert.Equal(t, later.ID, items[3].TTRID)
+	})
+
+	t.Run("answered invitations drop out", func(t *testing.T) {
+		invitation.Status = models.InvitationStatusYes
+		require.NoError(t, invitationRepo.Update(ctx, invitation))
+
+		items, err := actionItemService.ListActionItems(ctx, me, service.DefaultActionItemHorizon)
+		require.NoError(t, err)
+		kinds := make([]string, 0, len(items))
+		for _, item := range items {
+			kinds = append(kinds, item.Kind)
+		}
+		assert.Equal(t, []string{service.ActionItemMaybeStatus, service.ActionItemUnansweredInvitations}, kinds)
+	})
+}
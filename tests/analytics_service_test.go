@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/analytics"
+	"go.uber.org/zap"
+)
+
+// runAnalytics runs the service until the returned function is called, which
+// waits for it to flush and returns the events written to path.
+func runAnalytics(t *testing.T, analyticsService *service.AnalyticsService, path string) func() []map[string]interface{} {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- analyticsService.Run(ctx) }()
+
+	return func() []map[string]interface{} {
+		cancel()
+		require.NoError(t, <-done)
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+
+		var events []map[string]interface{}
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var event map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+			events = append(events, event)
+		}
+		require.NoError(t, scanner.Err())
+		return events
+	}
+}
+
+func TestAnalyticsService_EventSchemas(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+	publisher, err := analytics.NewFilePublisher(path)
+	require.NoError(t, err)
+	analyticsService := service.NewAnalyticsService(publisher, "salt", 100, logger)
+	stop := runAnalytics(t, analyticsService, path)
+
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	transactor := memory.NewTransactor(store)
+	authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), invitationRepo, userRepo)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, transactor, authorizer, notificationService, nil, nil, analyticsService, nil, 7*24*time.Hour, 0, logger)
+	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, memory.NewInvitationTemplateRepository(store), ttrService, transactor, authorizer, notificationService, nil, analyticsService, logger)
+
+	newUser := func(name string) uuid.UUID {
+		user := &models.User{Email: name + "@example.com", FirstName: name, LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user.ID
+	}
+	captainID := newUser("captain")
+	inviteeID := newUser("invitee")
+	walkUpID := newUser("walkup")
+
+	ttr, err := ttrService.CreateTTR(ctx, captainID, "Pebble Beach", nil, time.Now().UTC().AddDate(0, 0, 7).Truncate(24*time.Hour), time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), 4, 2, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, false)
+	require.NoError(t, err)
+	invitation, err := invitationService.CreateInvitation(ctx, ttr.ID, captainID, inviteeID, nil, nil)
+	require.NoError(t, err)
+	_, err = invitationService.RespondToInvitation(ctx, invitation.ID, inviteeID, models.InvitationStatusYes, nil)
+	require.NoError(t, err)
+	_, err = ttrService.JoinTTR(ctx, ttr.ID, walkUpID)
+	require.NoError(t, err)
+	completed := models.TTRStatusCompleted
+	_, err = ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, nil, nil, nil, &completed, nil, nil, models.CourseConditions{}, nil, nil, false)
+	require.NoError(t, err)
+
+	events := stop()
+
+	anonymizer := analytics.NewAnonymizer("salt")
+	captain, invitee, walkUp := anonymizer.User(captainID), anonymizer.User(inviteeID), anonymizer.User(walkUpID)
+	want := []struct {
+		eventType  string
+		actor      string
+		subject    string
+		invitation bool
+	}{
+		{analytics.EventTTRCreated, captain, "", false},
+		{analytics.EventInvitationSent, captain, invitee, true},
+		{analytics.EventPlayerJoined, invitee, "", false},
+		{analytics.EventInvitationResponded, invitee, "", true},
+		{analytics.EventPlayerJoined, walkUp, "", false},
+		{analytics.EventTTRCompleted, captain, "", false},
+	}
+	require.Len(t, events, len(want))
+	for i, event := range events {
+		assert.Equal(t, want[i].eventType, event["type"], "event %d", i)
+		assert.Equal(t, ttr.ID.String(), event["ttr_id"], "event %d", i)
+		assert.Equal(t, want[i].actor, event["actor"], "event %d", i)
+		if want[i].subject != "" {
+			assert.Equal(t, want[i].subject, event["subject"], "event %d", i)
+		} else {
+			assert.NotContains(t, event, "subject", "event %d", i)
+		}
+		if want[i].invitation {
+			assert.Equal(t, invitation.ID.String(), event["invitation_id"], "event %d", i)
+		} else {
+			assert.NotContains(t, event, "invitation_id", "event %d", i)
+		}
+
+		_, err := uuid.Parse(event["id"].(string))
+		assert.NoError(t, err, "event %d", i)
+		_, err = time.Parse(time.RFC3339Nano, event["occurred_at"].(string))
+		assert.NoError(t, err, "event %d", i)
+
+		for _, value := range event {
+			raw, _ := json.Marshal(value)
+			for _, id := range []uuid.UUID{captainID, inviteeID, walkUpID} {
+				assert.NotContains(t, string(raw), id.String(), "event %d names a user", i)
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"tee_at":       ttr.TeeDate.Format("2006-01-02") + "T09:00:00Z",
+		"max_players":  "4",
+		"visibility":   models.TTRVisibilityPublic,
+		"organization": "false",
+	}, events[0]["properties"])
+	assert.Equal(t, map[string]interface{}{"status": string(models.InvitationStatusYes)}, events[3]["properties"])
+	assert.ElementsMatch(t, []interface{}{captain, invitee, walkUp}, events[5]["players"])
+}
+
+func TestAnalyticsService_DropsOnOverflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+	publisher, err := analytics.NewFilePublisher(path)
+	require.NoError(t, err)
+	analyticsService := service.NewAnalyticsService(publisher, "salt", 2, zap.NewNop())
+
+	ttrID := uuid.New()
+	for i := 0; i < 5; i++ {
+		analyticsService.PlayerJoined(ttrID, uuid.New())
+	}
+	assert.Equal(t, int64(3), analyticsService.Dropped(), "publishing never blocks; what doesn't fit is dropped")
+
+	events := runAnalytics(t, analyticsService, path)()
+	assert.Len(t, events, 2, "queued events are flushed on shutdown")
+}
+
+func TestAnalyticsService_NilIsNoop(t *testing.T) {
+	var analyticsService *service.AnalyticsService
+	assert.NotPanics(t, func() {
+		analyticsService.PlayerJoined(uuid.New(), uuid.New())
+	})
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+func TestAPITokenService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	repo := memory.NewAPITokenRepository(store)
+	apiTokenService := service.NewAPITokenService(repo, zap.NewNop())
+
+	user := &models.User{Email: "scripter@example.com", FirstName: "Scripter", LastName: "Tester"}
+	require.NoError(t, memory.NewUserRepository(store).Create(ctx, user))
+
+	apiToken, token, err := apiTokenService.CreateAPIToken(ctx, user.ID, " nightly export ", []string{"read:ttrs"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "nightly export", apiToken.Name)
+	assert.NotContains(t, apiToken.TokenHash, token, "only the hash is stored")
+
+	t.Run("resolves the owner and samples the last use", func(t *testing.T) {
+		authenticated, err := apiTokenService.AuthenticateAPIToken(ctx, token)
+		require.NoError(t, err)
+		require.NotNil(t, authenticated)
+		require.NotNil(t, authenticated.User)
+		assert.Equal(t, user.ID, authenticated.User.ID)
+
+		stored, err := repo.FindByID(ctx, apiToken.ID)
+		require.NoError(t, err)
+		require.NotNil(t, stored.LastUsedAt)
+		firstUse := *stored.LastUsedAt
+
+		_, err = apiTokenService.AuthenticateAPIToken(ctx, token)
+		require.NoError(t, err)
+		stored, err = repo.FindByID(ctx, apiToken.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.LastUsedAt.Equal(firstUse), "a use within the interval is not written")
+
+		require.NoError(t, repo.UpdateLastUsed(ctx, apiToken.ID, time.Now().Add(-time.Hour)))
+		_, err = apiTokenService.AuthenticateAPIToken(ctx, token)
+		require.NoError(t, err)
+		stored, err = repo.FindByID(ctx, apiToken.ID)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now(), *stored.LastUsedAt, time.Minute)
+	})
+
+	t.Run("expired and unknown tokens resolve to nobody", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		expiring, expiringToken, err := apiTokenService.CreateAPIToken(ctx, user.ID, "expiring", []string{"read:ttrs"}, &expiresAt)
+		require.NoError(t, err)
+		// Tokens can't be created already expired, so age this one in place.
+		past := time.Now().Add(-time.Second)
+		expiring.ExpiresAt = &past
+		require.NoError(t, repo.Delete(ctx, expiring.ID))
+		require.NoError(t, repo.Create(ctx, expiring))
+
+		authenticated, err := apiTokenService.AuthenticateAPIToken(ctx, expiringToken)
+		require.NoError(t, err)
+		assert.Nil(t, authenticated)
+
+		authenticated, err = apiTokenService.AuthenticateAPIToken(ctx, token+"x")
+		require.NoError(t, err)
+		assert.Nil(t, authenticated)
+	})
+
+	t.Run("only the owner can revoke", func(t *testing.T) {
+		err := apiTokenService.RevokeAPIToken(ctx, uuid.New(), apiToken.ID)
+		assert.ErrorIs(t, err, service.ErrAPITokenNotFound)
+
+		require.NoError(t, apiTokenService.RevokeAPIToken(ctx, user.ID, apiToken.ID))
+		authenticated, err := apiTokenService.AuthenticateAPIToken(ctx, token)
+		require.NoError(t, err)
+		assert.Nil(t, authenticated)
+	})
+}
@@ -1,7 +1,7 @@
 package tests
 
 import (
-	"errors"
+	"context"
 	"testing"
 	"time"
 
@@ -9,19 +9,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
 	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/password"
 )
 
 type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(user *models.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,7 +31,142 @@ func (m *MockUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
+func (m *MockUserRepository) FindByIDUnscoped(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.User, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailUndeliverable(ctx context.Context, email string) error {
+	args := m.Called(email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindInactiveSince(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
+	args := m.Called(cutoff, from, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindWarnedBefore(ctx context.Context, cutoff time.Time, from time.Time, limit int) ([]*models.User, error) {
+	args := m.Called(cutoff, from, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkInactivityWarned(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	args := m.Called(id, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) FindDigestDue(ctx context.Context, before time.Time, limit int) ([]*models.User, error) {
+	args := m.Called(before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkDigestSent(ctx context.Context, id uuid.UUID, before time.Time, at time.Time) (bool, error) {
+	args := m.Called(id, before, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Deactivate(ctx context.Context, id uuid.UUID, warnedBefore time.Time, at time.Time) (bool, error) {
+	args := m.Called(id, warnedBefore, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	args := m.Called(cutoff, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) Merge(ctx context.Context, sourceID uuid.UUID, targetID uuid.UUID, at time.Time) (*repository.UserMerge, error) {
+	args := m.Called(sourceID, targetID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.UserMerge), args.Error(1)
+}
+
+func (m *MockUserRepository) Block(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	args := m.Called(blockerID, blockedID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Unblock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	args := m.Called(blockerID, blockedID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) IsBlocked(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (bool, error) {
+	args := m.Called(blockerID, blockedID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) FindBlocked(ctx context.Context, blockerID uuid.UUID) ([]*models.User, error) {
+	args := m.Called(blockerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) RequestFriendship(ctx context.Context, requesterID uuid.UUID, addresseeID uuid.UUID, at time.Time) (*models.Friendship, error) {
+	args := m.Called(requesterID, addresseeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Friendship), args.Error(1)
+}
+
+func (m *MockUserRepository) RemoveFriendship(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	args := m.Called(userID, otherUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) AreFriends(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	args := m.Called(userID, otherUserID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) FindFriends(ctx context.Context, userID uuid.UUID) ([]*models.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindFriendRequests(ctx context.Context, addresseeID uuid.UUID) ([]*models.User, error) {
+	args := m.Called(addresseeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	args := m.Called(email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -37,29 +174,42 @@ func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Update(user *models.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, user *models.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Search(query string, limit int, offset int) ([]*models.User, error) {
-	args := m.Called(query, limit, offset)
+func (m *MockUserRepository) SetPlayingDays(ctx context.Context, userID uuid.UUID, days []string) error {
+	args := m.Called(userID, days)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Search(ctx context.Context, filter repository.UserSearchFilter) ([]*models.User, error) {
+	args := m.Called(filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindCaptainedBy(ctx context.Context, captainID uuid.UUID, userIDs []uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(captainID, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
 type MockRefreshTokenRepository struct {
 	mock.Mock
 }
 
-func (m *MockRefreshTokenRepository) Create(token *models.RefreshToken) error {
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
 	args := m.Called(token)
 	return args.Error(0)
 }
 
-func (m *MockRefreshTokenRepository) FindByTokenHash(tokenHash string) (*models.RefreshToken, error) {
+func (m *MockRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	args := m.Called(tokenHash)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -67,12 +217,12 @@ func (m *MockRefreshTokenRepository) FindByTokenHash(tokenHash string) (*models.
 	return args.Get(0).(*models.RefreshToken), args.Error(1)
 }
 
-func (m *MockRefreshTokenRepository) RevokeByUserID(userID uuid.UUID) error {
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockRefreshTokenRepository) DeleteExpired() error {
+func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) error {
 	args := m.Called()
 	return args.Error(0)
 }
@@ -88,12 +238,14 @@ func TestAuthService_Register_Success(t *testing.T) {
 	authService := service.NewAuthService(
 		mockUserRepo,
 		mockRefreshTokenRepo,
+		nil,
+		password.Policy{},
 		"test-secret",
 		15*time.Minute,
 		7*24*time.Hour,
 	)
 
-	user, tokenPair, err := authService.Register("test@example.com", "password123", "John", "Doe")
+	user, tokenPair, err := authService.Register(context.Background(), "test@example.com", "password123", "John", "Doe")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
@@ -122,12 +274,14 @@ func TestAuthService_Register_DuplicateEmail(t *testing.T) {
 	authService := service.NewAuthService(
 		mockUserRepo,
 		mockRefreshTokenRepo,
+		nil,
+		password.Policy{},
 		"test-secret",
 		15*time.Minute,
 		7*24*time.Hour,
 	)
 
-	user, tokenPair, err := authService.Register("test@example.com", "password123", "John", "Doe")
+	user, tokenPair, err := authService.Register(context.Background(), "test@example.com", "password123", "John", "Doe")
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -137,6 +291,33 @@ func TestAuthService_Register_DuplicateEmail(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestAuthService_Register_WeakPassword(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+
+	authService := service.NewAuthService(
+		mockUserRepo,
+		mockRefreshTokenRepo,
+		nil,
+		password.Policy{MinLength: 8, BanCommon: true, RejectEmail: true},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+
+	user, tokenPair, err := authService.Register(context.Background(), "test@example.com", "password123", "John", "Doe")
+
+	var weak *service.PasswordPolicyError
+	assert.ErrorAs(t, err, &weak)
+	assert.ErrorIs(t, err, service.ErrWeakPassword)
+	assert.Equal(t, []password.Rule{password.RuleCommon}, weak.Rules)
+	assert.Equal(t, 8, weak.MinLength)
+	assert.Nil(t, user)
+	assert.Nil(t, tokenPair)
+
+	mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
 func TestAuthService_Login_Success(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 	mockRefreshTokenRepo := new(MockRefreshTokenRepository)
@@ -150,22 +331,26 @@ func TestAuthService_Login_Success(t *testing.T) {
 	user.SetPassword("password123")
 
 	mockUserRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockUserRepo.On("UpdateLastLogin", user.ID, mock.AnythingOfType("time.Time")).Return(nil)
 	mockRefreshTokenRepo.On("Create", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
 
 	authService := service.NewAuthService(
 		mockUserRepo,
 		mockRefreshTokenRepo,
+		nil,
+		password.Policy{},
 		"test-secret",
 		15*time.Minute,
 		7*24*time.Hour,
 	)
 
-	loggedInUser, tokenPair, err := authService.Login("test@example.com", "password123")
+	loggedInUser, tokenPair, err := authService.Login(context.Background(), "test@example.com", "password123")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, loggedInUser)
 	assert.NotNil(t, tokenPair)
 	assert.Equal(t, user.ID, loggedInUser.ID)
+	assert.NotNil(t, loggedInUser.LastLoginAt)
 	assert.NotEmpty(t, tokenPair.AccessToken)
 	assert.NotEmpty(t, tokenPair.RefreshToken)
 
@@ -190,12 +375,14 @@ func TestAuthService_Login_InvalidCredentials(t *testing.T) {
 	authService := service.NewAuthService(
 		mockUserRepo,
 		mockRefreshTokenRepo,
+		nil,
+		password.Policy{},
 		"test-secret",
 		15*time.Minute,
 		7*24*time.Hour,
 	)
 
-	loggedInUser, tokenPair, err := authService.Login("test@example.com", "wrongpassword")
+	loggedInUser, tokenPair, err := authService.Login(context.Background(), "test@example.com", "wrongpassword")
 
 	assert.Error(t, err)
 	assert.Nil(t, loggedInUser)
@@ -214,12 +401,14 @@ func TestAuthService_Login_UserNotFound(t *testing.T) {
 	authService := service.NewAuthService(
 		mockUserRepo,
 		mockRefreshTokenRepo,
+		nil,
+		password.Policy{},
 		"test-secret",
 		15*time.Minute,
 		7*24*time.Hour,
 	)
 
-	loggedInUser, tokenPair, err := authService.Login("test@example.com", "password123")
+	loggedInUser, tokenPair, err := authService.Login(context.Background(), "test@example.com", "password123")
 
 	assert.Error(t, err)
 	assert.Nil(t, loggedInUser)
@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+)
+
+type authorizerFixture struct {
+	authorizer  *service.Authorizer
+	ttrRepo     repository.TTRRepository
+	invitations repository.InvitationRepository
+	users       repository.UserRepository
+	roles       map[string]uuid.UUID
+	orgID       uuid.UUID
+}
+
+// newAuthorizerFixture sets up one user per role. Everyone but the outsider
+// belongs to the organization; only orgAdmin administers it.
+func newAuthorizerFixture(t *testing.T) *authorizerFixture {
+	store := memory.NewStore()
+	orgRepo := memory.NewOrganizationRepository(store)
+	f := &authorizerFixture{
+		ttrRepo:     memory.NewTTRRepository(store),
+		invitations: memory.NewInvitationRepository(store),
+		users:       memory.NewUserRepository(store),
+		roles:       make(map[string]uuid.UUID),
+		orgID:       uuid.New(),
+	}
+	f.authorizer = service.NewAuthorizer(f.ttrRepo, orgRepo, f.invitations, f.users)
+
+	require.NoError(t, orgRepo.Create(context.Background(), &models.Organization{ID: f.orgID, Name: "Cypress Point Club"}))
+	for _, role := range []string{"captain", "coCaptain", "player", "invitee", "orgAdmin", "orgMember", "outsider"} {
+		f.roles[role] = uuid.New()
+
+		switch role {
+		case "outsider":
+		case "orgAdmin":
+			require.NoError(t, orgRepo.AddMember(context.Background(), &models.OrganizationMember{OrganizationID: f.orgID, UserID: f.roles[role], Role: models.OrganizationRoleAdmin}))
+		default:
+			require.NoError(t, orgRepo.AddMember(context.Background(), &models.OrganizationMember{OrganizationID: f.orgID, UserID: f.roles[role], Role: models.OrganizationRoleMember}))
+		}
+	}
+	return f
+}
+
+func (f *authorizerFixture) ttr(t *testing.T, status models.TTRStatus, inOrganization bool) *models.TTR {
+	return f.ttrWithVisibility(t, status, inOrganization, models.TTRVisibilityPublic)
+}
+
+func (f *authorizerFixture) ttrWithVisibility(t *testing.T, status models.TTRStatus, inOrganization bool, visibility string) *models.TTR {
+	ctx := context.Background()
+	ttr := &models.TTR{
+		CourseName:    "Pebble Beach",
+		CaptainUserID: f.roles["captain"],
+		Status:        status,
+		Visibility:    visibility,
+		MaxPlayers:    4,
+	}
+	if inOrganization {
+		ttr.OrganizationID = &f.orgID
+	}
+	require.NoError(t, f.ttrRepo.Create(ctx, ttr))
+	require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, ttr.ID, f.roles["coCaptain"], models.CoCaptainPermissions))
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, ttr.ID, f.roles["player"], models.TTRPlayerStatusConfirmed))
+	require.NoError(t, f.invitations.Create(ctx, &models.Invitation{TTRID: ttr.ID, InviterUserID: f.roles["captain"], InviteeUserID: f.roles["invitee"]}))
+
+	loaded, err := f.ttrRepo.FindByID(ctx, ttr.ID)
+	require.NoError(t, err)
+	return loaded
+}
+
+func assertPermissions(t *testing.T, f *authorizerFixture, resource interface{}, allowed map[service.Action][]string) {
+	t.Helper()
+	for action, roles := range allowed {
+		for role, userID := range f.roles {
+			want := false
+			for _, allowedRole := range roles {
+				if allowedRole == role {
+					want = true
+				}
+			}
+
+			got, err := f.authorizer.Can(context.Background(), userID, action, resource)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "%s: %s", action, role)
+		}
+	}
+}
+
+func TestAuthorizer_PublicTTR(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	everyone := []string{"captain", "coCaptain", "player", "invitee", "orgAdmin", "orgMember", "outsider"}
+
+	assertPermissions(t, f, f.ttr(t, models.TTRStatusOpen, false), map[service.Action][]string{
+		service.ActionTTRList:             everyone,
+		service.ActionTTRView:             everyone,
+		service.ActionTTRJoin:             everyone,
+		service.ActionTTRUpdate:           {"captain", "coCaptain"},
+		service.ActionTTRDelete:           {"captain"},
+		service.ActionTTRManageCoCaptains: {"captain"},
+		service.ActionTTRManagePlayers:    {"captain", "coCaptain"},
+		service.ActionTTRInvite:           {"captain", "coCaptain"},
+		service.ActionTTRChat:             {"captain", "coCaptain", "player"},
+		service.ActionTTRModerateChat:     {"captain", "coCaptain"},
+	})
+}
+
+func TestAuthorizer_OrganizationTTR(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	members := []string{"captain", "coCaptain", "player", "invitee", "orgAdmin", "orgMember"}
+
+	assertPermissions(t, f, f.ttr(t, models.TTRStatusOpen, true), map[service.Action][]string{
+		service.ActionTTRList:             members,
+		service.ActionTTRView:             members,
+		service.ActionTTRJoin:             members,
+		service.ActionTTRUpdate:           {"captain", "coCaptain", "orgAdmin"},
+		service.ActionTTRDelete:           {"captain", "orgAdmin"},
+		service.ActionTTRManageCoCaptains: {"captain"},
+		service.ActionTTRManagePlayers:    {"captain", "coCaptain", "orgAdmin"},
+		service.ActionTTRInvite:           {"captain", "coCaptain", "orgAdmin"},
+		service.ActionTTRChat:             {"captain", "coCaptain", "player"},
+		service.ActionTTRModerateChat:     {"captain", "coCaptain", "orgAdmin"},
+	})
+}
+
+func TestAuthorizer_CoCaptainPermissions(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	ctx := context.Background()
+
+	for _, permission := range models.CoCaptainPermissions {
+		ttr := f.ttr(t, models.TTRStatusOpen, false)
+		updated, err := f.ttrRepo.UpdateCoCaptainPermissions(ctx, ttr.ID, f.roles["coCaptain"], []string{permission})
+		require.NoError(t, err)
+		require.True(t, updated)
+		ttr, err = f.ttrRepo.FindByID(ctx, ttr.ID)
+		require.NoError(t, err)
+
+		allowed := map[service.Action][]string{
+			service.ActionTTRUpdate:           {"captain"},
+			service.ActionTTRManageCoCaptains: {"captain"},
+			service.ActionTTRManagePlayers:    {"captain"},
+			service.ActionTTRInvite:           {"captain"},
+			service.ActionTTRModerateChat:     {"captain", "coCaptain"},
+		}
+		switch permission {
+		case models.CoCaptainPermissionEditDetails:
+			allowed[service.ActionTTRUpdate] = []string{"captain", "coCaptain"}
+		case models.CoCaptainPermissionManagePlayers:
+			allowed[service.ActionTTRManagePlayers] = []string{"captain", "coCaptain"}
+		case models.CoCaptainPermissionInvite:
+			allowed[service.ActionTTRInvite] = []string{"captain", "coCaptain"}
+		}
+		assertPermissions(t, f, ttr, allowed)
+	}
+}
+
+func TestAuthorizer_TTRVisibility(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	ctx := context.Background()
+	participants := []string{"captain", "coCaptain", "player", "invitee"}
+	// The outsider is the captain's friend; sharing the organization or a
+	// pending request doesn't count.
+	friends := []string{"captain", "coCaptain", "player", "invitee", "outsider"}
+
+	_, err := f.users.RequestFriendship(ctx, f.roles["outsider"], f.roles["captain"], time.Now())
+	require.NoError(t, err)
+	_, err = f.users.RequestFriendship(ctx, f.roles["captain"], f.roles["outsider"], time.Now())
+	require.NoError(t, err)
+	_, err = f.users.RequestFriendship(ctx, f.roles["orgMember"], f.roles["captain"], time.Now())
+	require.NoError(t, err)
+
+	for visibility, allowed := range map[string][]string{
+		models.TTRVisibilityPrivate: participants,
+		models.TTRVisibilityFriends: friends,
+	} {
+		assertPermissions(t, f, f.ttrWithVisibility(t, models.TTRStatusOpen, false, visibility), map[service.Action][]string{
+			service.ActionTTRList: allowed,
+			service.ActionTTRView: allowed,
+			service.ActionTTRJoin: allowed,
+		})
+	}
+}
+
+func TestAuthorizer_ViewClosedTTR(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	participants := []string{"captain", "coCaptain", "player", "invitee"}
+
+	assertPermissions(t, f, f.ttr(t, models.TTRStatusCompleted, false), map[service.Action][]string{
+		service.ActionTTRView: participants,
+	})
+	assertPermissions(t, f, f.ttr(t, models.TTRStatusConfirmed, true), map[service.Action][]string{
+		service.ActionTTRView: participants,
+	})
+}
+
+func TestAuthorizer_Invitation(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	ttr := f.ttr(t, models.TTRStatusOpen, true)
+
+	invitation := &models.Invitation{
+		ID:            uuid.New(),
+		TTRID:         ttr.ID,
+		InviterUserID: f.roles["coCaptain"],
+		InviteeUserID: f.roles["invitee"],
+		Status:        models.InvitationStatusPending,
+	}
+
+	assertPermissions(t, f, invitation, map[service.Action][]string{
+		service.ActionInvitationView:    {"captain", "coCaptain", "invitee", "orgAdmin"},
+		service.ActionInvitationRespond: {"invitee"},
+		service.ActionInvitationCancel:  {"coCaptain"},
+	})
+}
+
+func TestAuthorizer_InvitationToDeletedTTR(t *testing.T) {
+	f := newAuthorizerFixture(t)
+	ttr := f.ttr(t, models.TTRStatusOpen, true)
+	require.NoError(t, f.ttrRepo.Delete(context.Background(), ttr.ID))
+
+	invitation := &models.Invitation{
+		ID:            uuid.New(),
+		TTRID:         ttr.ID,
+		InviterUserID: f.roles["coCaptain"],
+		InviteeUserID: f.roles["invitee"],
+		Status:        models.InvitationStatusPending,
+	}
+
+	assertPermissions(t, f, invitation, map[service.Action][]string{
+		service.ActionInvitationView: {"coCaptain", "invitee"},
+	})
+}
+
+func TestAuthorizer_UnsupportedAction(t *testing.T) {
+	f := newAuthorizerFixture(t)
+
+	_, err := f.authorizer.Can(context.Background(), f.roles["captain"], service.ActionInvitationCancel, f.ttr(t, models.TTRStatusOpen, false))
+	assert.Error(t, err)
+
+	_, err = f.authorizer.Can(context.Background(), f.roles["captain"], service.ActionTTRUpdate, &models.User{})
+	assert.Error(t, err)
+}
+
+func TestAuthorizer_TTRMemo(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	authorizer := service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository))
+	ttr := &models.TTR{ID: uuid.New(), CaptainUserID: uuid.New(), Status: models.TTRStatusOpen}
+	mockTTRRepo.On("FindByID", ttr.ID).Return(ttr, nil)
+
+	ctx := service.WithTTRMemo(context.Background())
+	first, err := authorizer.TTR(ctx, ttr.ID)
+	assert.NoError(t, err)
+	second, err := authorizer.TTR(ctx, ttr.ID)
+	assert.NoError(t, err)
+
+	assert.Same(t, first, second)
+	mockTTRRepo.AssertNumberOfCalls(t, "FindByID", 1)
+
+	_, err = authorizer.TTR(context.Background(), ttr.ID)
+	assert.NoError(t, err)
+	_, err = authorizer.TTR(context.Background(), ttr.ID)
+	assert.NoError(t, err)
+	mockTTRRepo.AssertNumberOfCalls(t, "FindByID", 3)
+}
+
+func TestAuthorizer_TTRNotFound(t *testing.T) {
+	f := newAuthorizerFixture(t)
+
+	_, err := f.authorizer.TTR(service.WithTTRMemo(context.Background()), uuid.New())
+	assert.Error(t, err)
+	assert.Equal(t, "TTR not found", err.Error())
+}
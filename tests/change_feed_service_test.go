@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+func TestChangeFeedService_RecordsEachMutation(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), invitationRepo, userRepo)
+	changeFeed := service.NewChangeFeedService(memory.NewTTREventRepository(store), authorizer, 30*24*time.Hour, logger)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, memory.NewTransactor(store), authorizer, service.NewNotificationService(nil, nil, nil, 0, logger), nil, changeFeed, nil, nil, 7*24*time.Hour, 0, logger)
+	messageService := service.NewMessageService(memory.NewMessageRepository(store), authorizer, storage.NewMemoryStorage(), config.MessagingConfig{}, changeFeed, logger)
+
+	newUser := func(name string) uuid.UUID {
+		user := &models.User{Email: name + "@example.com", FirstName: name, LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user.ID
+	}
+	captainID := newUser("captain")
+	playerID := newUser("player")
+
+	ttr, err := ttrService.CreateTTR(ctx, captainID, "Pinehurst No. 2", nil, time.Now().AddDate(0, 0, 7), time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), 2, 2, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, false)
+	require.NoError(t, err)
+
+	_, err = ttrService.JoinTTR(ctx, ttr.ID, playerID)
+	require.NoError(t, err)
+	require.NoError(t, ttrService.UpdatePlayerStatus(ctx, ttr.ID, captainID, playerID, models.TTRPlayerStatusMaybe))
+	message, err := messageService.PostMessage(ctx, ttr.ID, playerID, "On my way")
+	require.NoError(t, err)
+	notes := "Carts only"
+	_, err = ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, nil, nil, nil, nil, nil, &notes, models.CourseConditions{}, nil, nil, false)
+	require.NoError(t, err)
+	_, err = ttrService.LeaveTTR(ctx, ttr.ID, playerID)
+	require.NoError(t, err)
+	cancelled := models.TTRStatusCancelled
+	_, err = ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, nil, nil, nil, &cancelled, nil, nil, models.CourseConditions{}, nil, nil, false)
+	require.NoError(t, err)
+
+	events, hasMore, err := changeFeed.ListChanges(ctx, ttr.ID, captainID, 0, 0)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+
+	type change struct {
+		eventType string
+		actor     *uuid.UUID
+		data      string
+	}
+	want := []change{
+		{models.TTREventPlayerJoined, &playerID, `{"user_id":"` + playerID.String() + `"}`},
+		{models.TTREventStatusChanged, nil, `{"from":"OPEN","to":"CONFIRMED"}`},
+		{models.TTREventPlayerUpdated, &captainID, `{"group_number":"0","status":"MAYBE","user_id":"` + playerID.String() + `"}`},
+		{models.TTREventStatusChanged, nil, `{"from":"CONFIRMED","to":"OPEN"}`},
+		{models.TTREventMessagePosted, &playerID, `{"message_id":"` + message.ID.String() + `"}`},
+		{models.TTREventDetailsUpdated, &captainID, `{}`},
+		{models.TTREventPlayerLeft, &playerID, `{"user_id":"` + playerID.String() + `"}`},
+		{models.TTREventStatusChanged, &captainID, `{"from":"OPEN","to":"CANCELLED"}`},
+	}
+	require.Len(t, events, len(want))
+	for i, event := range events {
+		assert.Equal(t, int64(i+1), event.Sequence)
+		assert.Equal(t, want[i].eventType, event.Type, "event %d", i+1)
+		assert.Equal(t, want[i].actor, event.ActorUserID, "event %d", i+1)
+		assert.JSONEq(t, want[i].data, event.Data, "event %d", i+1)
+	}
+
+	events, hasMore, err = changeFeed.ListChanges(ctx, ttr.ID, captainID, 6, 1)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(7), events[0].Sequence, "the cursor skips what the client already has")
+	assert.True(t, hasMore)
+
+	_, _, err = changeFeed.ListChanges(ctx, ttr.ID, newUser("stranger"), 0, 0)
+	assert.ErrorIs(t, err, service.ErrTTRNotFound, "only participants see the feed")
+	_, _, err = changeFeed.ListChanges(ctx, ttr.ID, captainID, -1, 0)
+	assert.ErrorIs(t, err, service.ErrInvalidChangeCursor)
+}
+
+func TestChangeFeedService_PurgeExpired(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	eventRepo := memory.NewTTREventRepository(store)
+	changeFeed := service.NewChangeFeedService(eventRepo, nil, 30*24*time.Hour, zap.NewNop())
+	ttrID := uuid.New()
+
+	for _, age := range []int{45, 31, 29, 1} {
+		require.NoError(t, eventRepo.Append(ctx, &models.TTREvent{TTRID: ttrID, Type: models.TTREventMessagePosted, CreatedAt: time.Now().AddDate(0, 0, -age)}))
+	}
+
+	require.NoError(t, changeFeed.PurgeExpired(ctx))
+
+	events, err := eventRepo.FindSince(ctx, ttrID, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(3), events[0].Sequence)
+	assert.Equal(t, int64(4), events[1].Sequence)
+}
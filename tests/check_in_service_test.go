@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+func TestCheckInService(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	notificationRepo := memory.NewNotificationRepository(store)
+	notificationService := service.NewNotificationService(notificationRepo, nil, nil, 0, logger)
+	authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), invitationRepo, userRepo)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, memory.NewTransactor(store), authorizer, notificationService, nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+	checkInService := service.NewCheckInService(ttrRepo, authorizer, notificationService, 2*time.Hour, time.Hour, logger)
+
+	newUser := func(name string) uuid.UUID {
+		user := &models.User{Email: uuid.NewString() + "@example.com", FirstName: name, LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user.ID
+	}
+	// newTTR creates a TTR teeing off in, to the minute, the given time, with
+	// the players joined as confirmed.
+	newTTR := func(captainID uuid.UUID, in time.Duration, players ...uuid.UUID) (*service.TTRDetail, time.Time) {
+		teeAt := time.Now().UTC().Add(in).Truncate(time.Minute)
+		teeDate := time.Date(teeAt.Year(), teeAt.Month(), teeAt.Day(), 0, 0, 0, 0, time.UTC)
+		teeTime := time.Date(0, 1, 1, teeAt.Hour(), teeAt.Minute(), 0, 0, time.UTC)
+		ttr, err := ttrService.CreateTTR(ctx, captainID, "Pebble Beach", nil, teeDate, teeTime, 4, 0, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, true)
+		require.NoError(t, err)
+		for _, playerID := range players {
+			_, err = ttrService.JoinTTR(ctx, ttr.ID, playerID)
+			require.NoError(t, err)
+		}
+		return ttr, teeAt
+	}
+	checkInNotifications := func(userID uuid.UUID) []*models.Notification {
+		notifications, err := notificationRepo.FindByUserID(ctx, userID, 50, 0)
+		require.NoError(t, err)
+		var found []*models.Notification
+		for _, notification := range notifications {
+			if notification.Type == models.NotificationTypeCheckIn {
+				found = append(found, notification)
+			}
+		}
+		return found
+	}
+
+	t.Run("window", func(t *testing.T) {
+		captainID := newUser("Captain")
+		tests := []struct {
+			name string
+			in   time.Duration
+			open bool
+		}{
+			{"before it opens", 2*time.Hour + 2*time.Minute, false},
+			{"just after it opens", 2*time.Hour - time.Minute, true},
+			{"at the tee time", 0, true},
+			{"just before it closes", -59 * time.Minute, true},
+			{"after it closes", -61 * time.Minute, false},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				ttr, teeAt := newTTR(captainID, tt.in)
+
+				player, err := checkInService.CheckIn(ctx, ttr.ID, captainID)
+				if tt.open {
+					require.NoError(t, err)
+					assert.NotNil(t, player.CheckedInAt)
+					return
+				}
+				var closed *service.CheckInWindowError
+				require.ErrorAs(t, err, &closed)
+				assert.ErrorIs(t, err, service.ErrCheckInClosed)
+				assert.Equal(t, teeAt.Add(-2*time.Hour), closed.OpensAt)
+				assert.Equal(t, teeAt.Add(time.Hour), closed.ClosesAt)
+			})
+		}
+	})
+
+	t.Run("only players", func(t *testing.T) {
+		ttr, _ := newTTR(newUser("Captain"), 0)
+
+		_, err := checkInService.CheckIn(ctx, ttr.ID, newUser("Stranger"))
+		assert.ErrorIs(t, err, service.ErrNotPlayerCheckIn)
+		_, _, err = checkInService.ListCheckIns(ctx, ttr.ID, newUser("Stranger"))
+		assert.ErrorIs(t, err, service.ErrTTRNotFound)
+	})
+
+	t.Run("idempotent and notifies when everyone is in", func(t *testing.T) {
+		captainID := newUser("Captain")
+		playerID := newUser("Player")
+		ttr, _ := newTTR(captainID, 30*time.Minute, playerID)
+
+		first, err := checkInService.CheckIn(ctx, ttr.ID, playerID)
+		require.NoError(t, err)
+		require.NotNil(t, first.CheckedInAt)
+		again, err := checkInService.CheckIn(ctx, ttr.ID, playerID)
+		require.NoError(t, err)
+		assert.Equal(t, *first.CheckedInAt, *again.CheckedInAt, "checking in again keeps the first time")
+		assert.Empty(t, checkInNotifications(captainID), "the captain hasn't checked in yet")
+
+		_, err = checkInService.CheckIn(ctx, ttr.ID, captainID)
+		require.NoError(t, err)
+		_, err = checkInService.CheckIn(ctx, ttr.ID, captainID)
+		require.NoError(t, err)
+		notified := checkInNotifications(captainID)
+		require.Len(t, notified, 1, "the summary is sent once")
+		assert.Equal(t, "Everyone has checked in for the tee time at Pebble Beach", notified[0].Message)
+
+		_, players, err := checkInService.ListCheckIns(ctx, ttr.ID, playerID)
+		require.NoError(t, err)
+		require.Len(t, players, 2)
+		for _, player := range players {
+			assert.NotNil(t, player.CheckedInAt)
+		}
+
+		require.NoError(t, checkInService.SendCheckInSummaries(ctx))
+		assert.Len(t, checkInNotifications(captainID), 1, "the job skips TTRs that already had their summary")
+	})
+
+	t.Run("summary lists who hasn't checked in", func(t *testing.T) {
+		captainID := newUser("Captain")
+		arrivedID := newUser("Arrived")
+		lateID := newUser("Late")
+		ttr, _ := newTTR(captainID, 10*time.Minute, arrivedID, lateID)
+		laterTTR, _ := newTTR(captainID, 20*time.Minute, arrivedID)
+
+		_, err := checkInService.CheckIn(ctx, ttr.ID, arrivedID)
+		require.NoError(t, err)
+
+		require.NoError(t, checkInService.SendCheckInSummaries(ctx))
+		require.NoError(t, checkInService.SendCheckInSummaries(ctx))
+
+		notified := checkInNotifications(captainID)
+		require.Len(t, notified, 1, "only the TTR teeing off within 15 minutes, once")
+		assert.Equal(t, ttr.ID, *notified[0].TargetID)
+		assert.Equal(t, "2 still to check in for the tee time at Pebble Beach: Captain, Late", notified[0].Message)
+		assert.NotEqual(t, laterTTR.ID, *notified[0].TargetID)
+	})
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+)
+
+func TestClientIP(t *testing.T) {
+	resolver, err := clientip.New([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::/32"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		realIP     string
+		want       string
+	}{
+		{
+			name:       "direct client",
+			remoteAddr: "203.0.113.7:4321",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "forwarding headers from an untrusted peer are ignored",
+			remoteAddr: "203.0.113.7:4321",
+			forwarded:  []string{"198.51.100.1"},
+			realIP:     "198.51.100.2",
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "client behind a trusted proxy",
+			remoteAddr: "10.0.0.5:4321",
+			forwarded:  []string{"198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "addresses the client made up are skipped",
+			remoteAddr: "10.0.0.5:4321",
+			forwarded:  []string{"1.2.3.4, 198.51.100.1"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "chained trusted proxies",
+			remoteAddr: "10.0.0.5:4321",
+			forwarded:  []string{"198.51.100.1, 192.0.2.1", "10.1.2.3"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "every hop trusted",
+			remoteAddr: "10.0.0.5:4321",
+			forwarded:  []string{"10.1.2.3"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "garbage stops at the last real address",
+			remoteAddr: "10.0.0.5:4321",
+			forwarded:  []string{"not-an-ip, 10.1.2.3"},
+			want:       "10.1.2.3",
+		},
+		{
+			name:       "X-Real-IP without X-Forwarded-For",
+			remoteAddr: "10.0.0.5:4321",
+			realIP:     "198.51.100.2",
+			want:       "198.51.100.2",
+		},
+		{
+			name:       "invalid X-Real-IP",
+			remoteAddr: "10.0.0.5:4321",
+			realIP:     "unknown",
+			want:       "10.0.0.5",
+		},
+		{
+			name:       "IPv6 proxy",
+			remoteAddr: "[2001:db8::1]:4321",
+			forwarded:  []string{"2001:db9::7"},
+			want:       "2001:db9::7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			assert.Equal(t, tt.want, resolver.ClientIP(req))
+		})
+	}
+}
+
+func TestClientIP_NoTrustedProxies(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.5:4321"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	var resolver *clientip.Resolver
+	assert.Equal(t, "10.0.0.5", resolver.ClientIP(req))
+
+	resolver, err := clientip.New(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.5", resolver.ClientIP(req))
+}
+
+func TestClientIP_InvalidProxy(t *testing.T) {
+	for _, entry := range []string{"10.0.0.0/33", "lb.internal", "10.0.0"} {
+		_, err := clientip.New([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func serveCompressed(t *testing.T, handler http.Handler, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest("GET", "/api/v1/ttrs", nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	middleware.Compress(1024)(handler).ServeHTTP(w, req)
+	return w
+}
+
+func writeBody(contentType, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		// Write in pieces so the threshold is crossed mid-response.
+		for i := 0; i < len(body); i += 100 {
+			end := i + 100
+			if end > len(body) {
+				end = len(body)
+			}
+			io.WriteString(w, body[i:end])
+		}
+	})
+}
+
+func gunzip(t *testing.T, body []byte) string {
+	t.Helper()
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(decoded)
+}
+
+func TestCompress_Threshold(t *testing.T) {
+	gzipOnly := http.Header{"Accept-Encoding": {"gzip, deflate"}}
+	large := `{"data":"` + strings.Repeat("roster ", 500) + `"}`
+	small := `{"data":"roster"}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		header      http.Header
+		compressed  bool
+	}{
+		{"large json", "application/json", large, gzipOnly, true},
+		{"small json", "application/json", small, gzipOnly, false},
+		{"exactly at threshold", "application/json", strings.Repeat("a", 1024), gzipOnly, true},
+		{"client without gzip", "application/json", large, http.Header{"Accept-Encoding": {"br"}}, false},
+		{"gzip refused", "application/json", large, http.Header{"Accept-Encoding": {"gzip;q=0"}}, false},
+		{"no accept-encoding", "application/json", large, nil, false},
+		{"already compressed image", "image/png", large, gzipOnly, false},
+		{"event stream", "text/event-stream", large, gzipOnly, false},
+		{"websocket upgrade", "application/json", large, http.Header{"Accept-Encoding": {"gzip"}, "Upgrade": {"websocket"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveCompressed(t, writeBody(tt.contentType, tt.body), tt.header)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if tt.compressed {
+				assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+				assert.Empty(t, w.Header().Get("Content-Length"))
+				assert.Less(t, w.Body.Len(), len(tt.body))
+				assert.Equal(t, tt.body, gunzip(t, w.Body.Bytes()))
+			} else {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+				assert.Equal(t, tt.body, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCompress_PreservesStatusAndNoContent(t *testing.T) {
+	gzipOnly := http.Header{"Accept-Encoding": {"gzip"}}
+
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, strings.Repeat("x", 2048))
+	})
+	w := serveCompressed(t, notFound, gzipOnly)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, strings.Repeat("x", 2048), gunzip(t, w.Body.Bytes()))
+
+	noContent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w = serveCompressed(t, noContent, gzipOnly)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Zero(t, w.Body.Len())
+}
+
+func TestCompress_LoggingRecordsCompressedSize(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	body := strings.Repeat("tee time ", 1000)
+
+	handler := middleware.Logging(zap.New(core))(middleware.Compress(1024)(writeBody("application/json", body)))
+	req := httptest.NewRequest("GET", "/api/v1/ttrs", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	require.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	completed := logs.FilterMessage("request completed").All()
+	require.Len(t, completed, 1)
+	assert.Equal(t, int64(w.Body.Len()), completed[0].ContextMap()["response_size"])
+	assert.Less(t, w.Body.Len(), len(body))
+}
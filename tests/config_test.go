@@ -0,0 +1,467 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/pkg/password"
+)
+
+func validConfig() *config.Config {
+	return &config.Config{
+		Server: config.ServerConfig{Port: "8080"},
+		Database: config.DatabaseConfig{
+			Host:   "localhost",
+			Port:   "5432",
+			User:   "postgres",
+			DBName: "golf_messenger",
+		},
+		JWT: config.JWTConfig{
+			Secret:                     strings.Repeat("a", config.MinJWTSecretLength),
+			AccessTokenDuration:        15 * time.Minute,
+			RefreshTokenDuration:       7 * 24 * time.Hour,
+			ImpersonationTokenDuration: 15 * time.Minute,
+		},
+		Accounts:  config.AccountsConfig{ImportMaxRows: 5000},
+		Passwords: config.PasswordConfig{MinLength: 8},
+		RateLimit: config.RateLimitConfig{ReportsPerDay: 10},
+		TTRs:      config.TTRConfig{ChangeRetention: 30 * 24 * time.Hour},
+		Analytics: config.AnalyticsConfig{Sink: config.AnalyticsSinkNone},
+		Outbox:    config.OutboxConfig{PollInterval: time.Second, BatchSize: 100, Lease: 5 * time.Minute, MaxAttempts: 10, Retention: 7 * 24 * time.Hour},
+		Retention: config.RetentionConfig{SecurityEvents: 90 * 24 * time.Hour},
+		GraphQL:   config.GraphQLConfig{MaxDepth: 8, MaxComplexity: 200},
+	}
+}
+
+func TestConfigValidate_JWT(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *config.Config)
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			modify: func(c *config.Config) {},
+		},
+		{
+			name:    "missing secret",
+			modify:  func(c *config.Config) { c.JWT.Secret = "" },
+			wantErr: "JWT_SECRET is required",
+		},
+		{
+			name:    "short secret",
+			modify:  func(c *config.Config) { c.JWT.Secret = "short-secret" },
+			wantErr: "JWT_SECRET must be at least 32 bytes",
+		},
+		{
+			name:    "well-known default secret",
+			modify:  func(c *config.Config) { c.JWT.Secret = "your-super-secret-key-change-this-in-production" },
+			wantErr: "JWT_SECRET is a well-known default value",
+		},
+		{
+			name:    "default secret is matched case-insensitively",
+			modify:  func(c *config.Config) { c.JWT.Secret = "CHANGEME" },
+			wantErr: "JWT_SECRET is a well-known default value",
+		},
+		{
+			name: "weak secret allowed by escape hatch",
+			modify: func(c *config.Config) {
+				c.JWT.Secret = "secret"
+				c.JWT.AllowWeakSecret = true
+			},
+		},
+		{
+			name:    "zero access token duration",
+			modify:  func(c *config.Config) { c.JWT.AccessTokenDuration = 0 },
+			wantErr: "ACCESS_TOKEN_DURATION must be positive",
+		},
+		{
+			name:    "negative refresh token duration",
+			modify:  func(c *config.Config) { c.JWT.RefreshTokenDuration = -time.Hour },
+			wantErr: "REFRESH_TOKEN_DURATION must be positive",
+		},
+		{
+			name:    "access token outlives refresh token",
+			modify:  func(c *config.Config) { c.JWT.AccessTokenDuration = 8 * 24 * time.Hour },
+			wantErr: "ACCESS_TOKEN_DURATION must be shorter than REFRESH_TOKEN_DURATION",
+		},
+		{
+			name:    "zero impersonation token duration",
+			modify:  func(c *config.Config) { c.JWT.ImpersonationTokenDuration = 0 },
+			wantErr: "JWT_IMPERSONATION_TOKEN_DURATION must be positive",
+		},
+		{
+			name: "signing client with short secret",
+			modify: func(c *config.Config) {
+				c.Signing.Clients = map[string]string{"scheduler": "short"}
+			},
+			wantErr: "SIGNING_CLIENTS secret for scheduler must be at least 32 bytes",
+		},
+		{
+			name: "signing scopes for unknown client",
+			modify: func(c *config.Config) {
+				c.Signing.Scopes = map[string][]string{"scheduler": {"read:ttrs"}}
+			},
+			wantErr: "SIGNING_CLIENT_SCOPES names unknown client scheduler",
+		},
+		{
+			name: "signing client with unknown scope",
+			modify: func(c *config.Config) {
+				c.Signing.Clients = map[string]string{"scheduler": strings.Repeat("s", config.MinSigningSecretLength)}
+				c.Signing.Scopes = map[string][]string{"scheduler": {"read:everything"}}
+			},
+			wantErr: "SIGNING_CLIENT_SCOPES for scheduler must list known scopes",
+		},
+		{
+			name:    "zero change retention",
+			modify:  func(c *config.Config) { c.TTRs.ChangeRetention = 0 },
+			wantErr: "TTRS_CHANGE_RETENTION must be positive",
+		},
+		{
+			name:    "negative edit lock window",
+			modify:  func(c *config.Config) { c.TTRs.EditLockWindow = -time.Minute },
+			wantErr: "TTRS_EDIT_LOCK_WINDOW cannot be negative",
+		},
+		{
+			name:    "negative check-in window",
+			modify:  func(c *config.Config) { c.TTRs.CheckInClosesAfter = -time.Minute },
+			wantErr: "TTRS_CHECK_IN_OPENS_BEFORE and TTRS_CHECK_IN_CLOSES_AFTER cannot be negative",
+		},
+		{
+			name:    "unknown analytics sink",
+			modify:  func(c *config.Config) { c.Analytics.Sink = "bigquery" },
+			wantErr: "ANALYTICS_SINK must be none, file, kafka or kinesis",
+		},
+		{
+			name: "kafka sink without a topic",
+			modify: func(c *config.Config) {
+				c.Analytics = config.AnalyticsConfig{Sink: config.AnalyticsSinkKafka, KafkaBrokers: []string{"localhost:9092"}, BufferSize: 100, UserSalt: "salt"}
+			},
+			wantErr: "ANALYTICS_KAFKA_BROKERS and ANALYTICS_KAFKA_TOPIC are required when ANALYTICS_SINK is kafka",
+		},
+		{
+			name: "analytics sink without a salt",
+			modify: func(c *config.Config) {
+				c.Analytics = config.AnalyticsConfig{Sink: config.AnalyticsSinkFile, FilePath: "analytics.jsonl", BufferSize: 100}
+			},
+			wantErr: "ANALYTICS_USER_SALT is required when ANALYTICS_SINK is set",
+		},
+		{
+			name: "file sink",
+			modify: func(c *config.Config) {
+				c.Analytics = config.AnalyticsConfig{Sink: config.AnalyticsSinkFile, FilePath: "analytics.jsonl", BufferSize: 100, UserSalt: "salt"}
+			},
+		},
+		{
+			name:    "zero outbox lease",
+			modify:  func(c *config.Config) { c.Outbox.Lease = 0 },
+			wantErr: "OUTBOX_POLL_INTERVAL, OUTBOX_LEASE and OUTBOX_RETENTION must be positive",
+		},
+		{
+			name:    "mail without sender",
+			modify:  func(c *config.Config) { c.Mail.SMTPAddr = "smtp.example.com:587" },
+			wantErr: "MAIL_FROM is required when MAIL_SMTP_ADDR is set",
+		},
+		{
+			name:    "invalid sendgrid key",
+			modify:  func(c *config.Config) { c.Mail.EventsSendGridPublicKey = "not-a-key" },
+			wantErr: "MAIL_EVENTS_SENDGRID_PUBLIC_KEY must be a base64 ECDSA public key",
+		},
+		{
+			name:    "import without rows",
+			modify:  func(c *config.Config) { c.Accounts.ImportMaxRows = 0 },
+			wantErr: "ACCOUNTS_IMPORT_MAX_ROWS must be at least 1",
+		},
+		{
+			name:    "short passwords",
+			modify:  func(c *config.Config) { c.Passwords.MinLength = 6 },
+			wantErr: "PASSWORD_MIN_LENGTH must be at least 8",
+		},
+		{
+			name:    "unknown password character class",
+			modify:  func(c *config.Config) { c.Passwords.RequireClasses = []string{"digit", "emoji"} },
+			wantErr: `PASSWORD_REQUIRE_CLASSES must list lowercase, uppercase, digit or symbol, not "emoji"`,
+		},
+		{
+			name:    "no graphql depth",
+			modify:  func(c *config.Config) { c.GraphQL.MaxDepth = 0 },
+			wantErr: "GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY must be at least 1",
+		},
+		{
+			name:    "no reports per day",
+			modify:  func(c *config.Config) { c.RateLimit.ReportsPerDay = 0 },
+			wantErr: "RATE_LIMIT_REPORTS_PER_DAY must be at least 1",
+		},
+		{
+			name:    "negative inactive after",
+			modify:  func(c *config.Config) { c.Retention.InactiveAfter = -time.Hour },
+			wantErr: "RETENTION_INACTIVE_AFTER and RETENTION_DEACTIVATE_AFTER cannot be negative",
+		},
+		{
+			name:    "digest hour out of range",
+			modify:  func(c *config.Config) { c.Digest.Hour = 24 },
+			wantErr: "DIGEST_HOUR must be between 0 and 23",
+		},
+		{
+			name:    "negative digest send interval",
+			modify:  func(c *config.Config) { c.Digest.SendInterval = -time.Second },
+			wantErr: "DIGEST_SEND_INTERVAL cannot be negative",
+		},
+		{
+			name:    "zero security event retention",
+			modify:  func(c *config.Config) { c.Retention.SecurityEvents = 0 },
+			wantErr: "RETENTION_SECURITY_EVENTS must be positive",
+		},
+		{
+			name:    "outbox without attempts",
+			modify:  func(c *config.Config) { c.Outbox.MaxAttempts = 0 },
+			wantErr: "OUTBOX_BATCH_SIZE and OUTBOX_MAX_ATTEMPTS must be at least 1",
+		},
+		{
+			name:    "invalid trusted proxy",
+			modify:  func(c *config.Config) { c.Server.TrustedProxies = []string{"10.0.0.0/8", "load-balancer"} },
+			wantErr: `SERVER_TRUSTED_PROXIES: invalid trusted proxy "load-balancer"`,
+		},
+		{
+			name: "escape hatch does not skip duration checks",
+			modify: func(c *config.Config) {
+				c.JWT.AllowWeakSecret = true
+				c.JWT.AccessTokenDuration = 0
+			},
+			wantErr: "ACCESS_TOKEN_DURATION must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	const fileContents = `
+server:
+  port: 9090
+  read_timeout: 30s
+database:
+  host: file-host
+  max_open_conns: 50
+logging:
+  level: warn
+  output_paths:
+    - stdout
+    - /var/log/app.log
+`
+
+	tests := []struct {
+		name   string
+		file   bool
+		env    map[string]string
+		assert func(t *testing.T, cfg *config.Config)
+	}{
+		{
+			name: "defaults without file or env",
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "8080", cfg.Server.Port)
+				assert.Equal(t, 15*time.Second, cfg.Server.ReadTimeout)
+				assert.Equal(t, 60*time.Second, cfg.Server.IdleTimeout)
+				assert.Equal(t, 25, cfg.Database.MaxOpenConns)
+				assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime)
+				assert.Equal(t, 30*time.Second, cfg.Database.ConnectTimeout)
+				assert.Equal(t, 200*time.Millisecond, cfg.Database.SlowQueryThreshold)
+				assert.Equal(t, "info", cfg.Logging.Level)
+				assert.Equal(t, []string{"stdout"}, cfg.Logging.OutputPaths)
+				assert.Equal(t, 15*time.Minute, cfg.JWT.AccessTokenDuration)
+				assert.True(t, cfg.Accounts.LowercaseEmailLocalPart)
+				assert.Equal(t, 5000, cfg.Accounts.ImportMaxRows)
+				assert.Equal(t, password.Policy{MinLength: 8, BanCommon: true, RejectEmail: true}, cfg.Passwords.Policy())
+				assert.Equal(t, 10, cfg.RateLimit.ReportsPerDay)
+				assert.False(t, cfg.Mail.Enabled())
+				assert.False(t, cfg.Compression.Enabled)
+				assert.Equal(t, 1024, cfg.Compression.MinSize)
+				assert.False(t, cfg.API.ListRoutes)
+				assert.Equal(t, 8, cfg.GraphQL.MaxDepth)
+				assert.Equal(t, 200, cfg.GraphQL.MaxComplexity)
+				assert.Equal(t, int64(10<<20), cfg.Avatars.MaxSize)
+				assert.Equal(t, 15*time.Minute, cfg.Avatars.UploadURLTTL)
+				assert.Equal(t, 7*24*time.Hour, cfg.TTRs.RestoreWindow)
+				assert.Equal(t, 30*24*time.Hour, cfg.TTRs.ChangeRetention)
+				assert.Equal(t, 2*time.Hour, cfg.TTRs.EditLockWindow)
+				assert.Equal(t, 2*time.Hour, cfg.TTRs.CheckInOpensBefore)
+				assert.Equal(t, time.Hour, cfg.TTRs.CheckInClosesAfter)
+				assert.Equal(t, config.AnalyticsSinkNone, cfg.Analytics.Sink)
+				assert.Equal(t, 10000, cfg.Analytics.BufferSize)
+				assert.Equal(t, 10, cfg.Outbox.MaxAttempts)
+				assert.Equal(t, 5*time.Minute, cfg.Outbox.Lease)
+				assert.Equal(t, time.Monday, cfg.Digest.Weekday)
+				assert.Equal(t, 7, cfg.Digest.Hour)
+				assert.Equal(t, 200*time.Millisecond, cfg.Digest.SendInterval)
+				assert.Equal(t, 30*24*time.Hour, cfg.Retention.PurgeAfter)
+				assert.Equal(t, 500, cfg.Retention.BatchSize)
+				assert.Equal(t, 180*24*time.Hour, cfg.Retention.InactiveAfter)
+				assert.Equal(t, 30*24*time.Hour, cfg.Retention.DeactivateAfter)
+				assert.Equal(t, 90*24*time.Hour, cfg.Retention.SecurityEvents)
+			},
+		},
+		{
+			name: "file overrides defaults",
+			file: true,
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "9090", cfg.Server.Port)
+				assert.Equal(t, 30*time.Second, cfg.Server.ReadTimeout)
+				assert.Equal(t, 15*time.Second, cfg.Server.WriteTimeout)
+				assert.Equal(t, "file-host", cfg.Database.Host)
+				assert.Equal(t, 50, cfg.Database.MaxOpenConns)
+				assert.Equal(t, "warn", cfg.Logging.Level)
+				assert.Equal(t, []string{"stdout", "/var/log/app.log"}, cfg.Logging.OutputPaths)
+			},
+		},
+		{
+			name: "env overrides file",
+			file: true,
+			env: map[string]string{
+				"SERVER_PORT":              "7070",
+				"SERVER_READ_TIMEOUT":      "45s",
+				"DATABASE_MAX_OPEN_CONNS":  "5",
+				"LOGGING_OUTPUT_PATHS":     "stderr, /tmp/app.log",
+				"FEATURE_FLAGS_ROLLOUTS":   "waitlist=25, messaging=100",
+				"SIGNING_CLIENTS":          "scheduler=s3cr3t=with=equals",
+				"SIGNING_CLIENT_SCOPES":    "scheduler=read:ttrs write:ttrs",
+				"PASSWORD_REQUIRE_CLASSES": "uppercase, digit",
+			},
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "7070", cfg.Server.Port)
+				assert.Equal(t, 45*time.Second, cfg.Server.ReadTimeout)
+				assert.Equal(t, 5, cfg.Database.MaxOpenConns)
+				assert.Equal(t, "file-host", cfg.Database.Host)
+				assert.Equal(t, []string{"stderr", "/tmp/app.log"}, cfg.Logging.OutputPaths)
+				assert.Equal(t, map[string]int{"waitlist": 25, "messaging": 100}, cfg.FeatureFlags.Rollouts)
+				assert.Equal(t, map[string]string{"scheduler": "s3cr3t=with=equals"}, cfg.Signing.Clients)
+				assert.Equal(t, map[string][]string{"scheduler": {"read:ttrs", "write:ttrs"}}, cfg.Signing.Scopes)
+				assert.Equal(t, []password.Rule{password.RuleUppercase, password.RuleDigit}, cfg.Passwords.Policy().RequireClasses)
+			},
+		},
+		{
+			name: "legacy env names still apply",
+			file: true,
+			env: map[string]string{
+				"DB_HOST":         "legacy-host",
+				"LOG_LEVEL":       "error",
+				"ALLOWED_ORIGINS": "http://localhost:3000,http://localhost:8080",
+			},
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "legacy-host", cfg.Database.Host)
+				assert.Equal(t, "error", cfg.Logging.Level)
+				assert.Equal(t, []string{"http://localhost:3000", "http://localhost:8080"}, cfg.CORS.AllowedOrigins)
+			},
+		},
+		{
+			name: "env applies without a file",
+			env: map[string]string{
+				"JWT_SECRET":            "env-secret",
+				"ACCESS_TOKEN_DURATION": "5m",
+			},
+			assert: func(t *testing.T, cfg *config.Config) {
+				assert.Equal(t, "env-secret", cfg.JWT.Secret)
+				assert.Equal(t, 5*time.Minute, cfg.JWT.AccessTokenDuration)
+				assert.Equal(t, 168*time.Hour, cfg.JWT.RefreshTokenDuration)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file {
+				path = writeConfigFile(t, fileContents)
+			}
+			t.Setenv("CONFIG_PATH", path)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := config.Load()
+
+			require.NoError(t, err)
+			tt.assert(t, cfg)
+		})
+	}
+}
+
+func TestLoad_ConfigPathErrors(t *testing.T) {
+	t.Run("missing explicit file", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+
+		_, err := config.Load()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", writeConfigFile(t, "server:\n  read_timeout: soon\n"))
+
+		_, err := config.Load()
+
+		assert.ErrorContains(t, err, "invalid server.read_timeout")
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", writeConfigFile(t, "api:\n  v1_sunset: next spring\n"))
+
+		_, err := config.Load()
+
+		assert.ErrorContains(t, err, "invalid api.v1_sunset")
+	})
+
+	t.Run("invalid rollout", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", "")
+		t.Setenv("FEATURE_FLAGS_ROLLOUTS", "waitlist=150")
+
+		_, err := config.Load()
+
+		assert.ErrorContains(t, err, "invalid feature_flags.rollouts")
+	})
+
+	t.Run("invalid signing client", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", "")
+		t.Setenv("SIGNING_CLIENTS", "scheduler")
+
+		_, err := config.Load()
+
+		assert.ErrorContains(t, err, "invalid signing.clients")
+	})
+
+	t.Run("invalid maintenance end", func(t *testing.T) {
+		t.Setenv("CONFIG_PATH", writeConfigFile(t, "maintenance:\n  enabled: true\n  ends_at: tonight\n"))
+
+		_, err := config.Load()
+
+		assert.ErrorContains(t, err, "invalid maintenance.ends_at")
+	})
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"gorm.io/gorm"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// convertZone is deliberately not UTC, so the golden files show every
+// timestamp being normalized.
+var convertZone = time.FixedZone("PDT", -7*60*60)
+
+func assertGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	require.NoError(t, err)
+
+	path := filepath.Join("testdata", "convert", name+".json")
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, append(got, '\n'), 0o644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+}
+
+func convertUser() *models.User {
+	handicap := 12.4
+	language := "es"
+	createdAt := time.Date(2026, 4, 1, 8, 30, 0, 0, convertZone)
+	return &models.User{
+		ID:                uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+		Email:             "ada@example.com",
+		FirstName:         "Ada",
+		LastName:          "Lovelace",
+		Handicap:          &handicap,
+		PreferredLanguage: &language,
+		CreatedAt:         createdAt,
+		UpdatedAt:         createdAt.Add(time.Hour),
+	}
+}
+
+func TestConvert_FromUser(t *testing.T) {
+	user := convertUser()
+	assertGolden(t, "user", handler.FromUser(handler.Viewer{UserID: user.ID}, service.NewUserSummary(user.ID, user)))
+}
+
+func TestConvert_FromUser_Deleted(t *testing.T) {
+	user := convertUser()
+	user.DeletedAt = gorm.DeletedAt{Time: user.UpdatedAt, Valid: true}
+	assertGolden(t, "user_deleted", handler.FromUser(handler.Viewer{UserID: user.ID}, service.NewUserSummary(user.ID, user)))
+}
+
+func TestConvert_FromProfile(t *testing.T) {
+	homeCourse := "Torrey Pines"
+	start := time.Date(0, 1, 1, 7, 0, 0, 0, time.UTC)
+	end := time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC)
+	user := convertUser()
+	user.HomeCourse = &homeCourse
+	user.PreferredTeeTimeStart = &start
+	user.PreferredTeeTimeEnd = &end
+	user.PlayingDays = []models.UserPlayingDay{{Day: "sunday"}, {Day: "saturday"}}
+	assertGolden(t, "profile", handler.FromProfile(service.NewUserProfile(user)))
+}
+
+func TestConvert_FromTTR(t *testing.T) {
+	captain := convertUser()
+	rsvpDeadline := time.Date(2026, 4, 16, 18, 0, 0, 0, convertZone)
+	ttrID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	ttr := &models.TTR{
+		ID:              ttrID,
+		CourseName:      "Torrey Pines",
+		TeeDate:         time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC),
+		TeeTime:         time.Date(0, 1, 1, 8, 10, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		MinPlayers:      2,
+		CreatedByUserID: captain.ID,
+		CaptainUserID:   captain.ID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPrivate,
+		RSVPDeadline:    &rsvpDeadline,
+		CreatedAt:       captain.CreatedAt,
+		UpdatedAt:       captain.UpdatedAt,
+		Players: []models.TTRPlayer{{
+			TTRID:    ttrID,
+			UserID:   captain.ID,
+			JoinedAt: captain.CreatedAt,
+			Status:   models.TTRPlayerStatusConfirmed,
+			User:     captain,
+		}},
+	}
+	assertGolden(t, "ttr", handler.FromTTR(handler.Viewer{UserID: captain.ID}, service.NewTTRDetail(ttr)))
+}
+
+func TestConvert_FromInvitation(t *testing.T) {
+	inviter := convertUser()
+	respondedAt := time.Date(2026, 4, 2, 21, 15, 0, 0, convertZone)
+	invitation := &models.Invitation{
+		ID:            uuid.MustParse("33333333-3333-3333-3333-333333333333"),
+		TTRID:         uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+		InviterUserID: inviter.ID,
+		InviteeUserID: uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+		Status:        models.InvitationStatusYes,
+		CreatedAt:     inviter.CreatedAt,
+		RespondedAt:   &respondedAt,
+		InviterUser:   inviter,
+	}
+	// The invitee wasn't loaded, so it comes out like a deleted user.
+	assertGolden(t, "invitation", handler.FromInvitation(handler.Viewer{UserID: inviter.ID}, service.NewInvitationDetail(invitation)))
+}
@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+var errDashboardQuery = errors.New("query failed")
+
+type failingUpcomingTTRRepository struct {
+	repository.TTRRepository
+}
+
+func (failingUpcomingTTRRepository) FindUpcomingByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
+	return nil, errDashboardQuery
+}
+
+type failingUnreadNotificationRepository struct {
+	repository.NotificationRepository
+}
+
+func (failingUnreadNotificationRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return 0, errDashboardQuery
+}
+
+func TestDashboardService_GetDashboard(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	notificationRepo := memory.NewNotificationRepository(store)
+	actionItemService := service.NewActionItemService(memory.NewActionItemRepository(store))
+
+	me := uuid.New()
+	friend := uuid.New()
+	maybe := uuid.New()
+	invited := uuid.New()
+
+	createTTR := func(days int, course string) *models.TTR {
+		ttr := &models.TTR{
+			CourseName:      course,
+			TeeDate:         time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour),
+			TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: me,
+			CaptainUserID:   me,
+			Status:          models.TTRStatusOpen,
+			Visibility:      models.TTRVisibilityPublic,
+		}
+		require.NoError(t, ttrRepo.Create(ctx, ttr))
+		return ttr
+	}
+
+	next := createTTR(2, "Bandon Dunes")
+	require.NoError(t, ttrRepo.AddPlayer(ctx, next.ID, friend, models.TTRPlayerStatusConfirmed))
+	require.NoError(t, ttrRepo.AddPlayer(ctx, next.ID, maybe, models.TTRPlayerStatusMaybe))
+	require.NoError(t, invitationRepo.Create(ctx, &models.Invitation{TTRID: next.ID, InviterUserID: me, InviteeUserID: invited, Status: models.InvitationStatusPending}))
+	later := createTTR(9, "Whistling Straits")
+	require.NoError(t, invitationRepo.Create(ctx, &models.Invitation{TTRID: later.ID, InviterUserID: me, InviteeUserID: invited, Status: models.InvitationStatusPending}))
+	require.NoError(t, invitationRepo.Create(ctx, &models.Invitation{TTRID: later.ID, InviterUserID: me, InviteeUserID: friend, Status: models.InvitationStatusPending}))
+	require.NoError(t, notificationRepo.Create(ctx, &models.Notification{UserID: me, Type: models.NotificationTypePlayerJoined, Title: "Joined", Message: "A player joined"}))
+
+	t.Run("every section", func(t *testing.T) {
+		dashboardService := service.NewDashboardService(ttrRepo, invitationRepo, notificationRepo, actionItemService, zap.NewNop())
+
+		dashboard := dashboardService.GetDashboard(ctx, me)
+
+		assert.Empty(t, dashboard.Warnings)
+		require.NotNil(t, dashboard.NextTTR)
+		assert.Equal(t, next.ID, dashboard.NextTTR.TTR.ID)
+		assert.Equal(t, service.RosterCounts{Confirmed: 1, Maybe: 1, OpenSlots: 2}, dashboard.NextTTR.Roster)
+		assert.Equal(t, map[uuid.UUID]int{next.ID: 1, later.ID: 2}, dashboard.PendingInvitations)
+		require.NotNil(t, dashboard.UnreadNotifications)
+		assert.Equal(t, int64(1), *dashboard.UnreadNotifications)
+		require.Len(t, dashboard.ActionItems, 1)
+		assert.Equal(t, service.ActionItemUnansweredInvitations, dashboard.ActionItems[0].Kind)
+	})
+
+	t.Run("failed sections degrade to nil with warnings", func(t *testing.T) {
+		dashboardService := service.NewDashboardService(
+			failingUpcomingTTRRepository{ttrRepo},
+			invitationRepo,
+			failingUnreadNotificationRepository{notificationRepo},
+			actionItemService,
+			zap.NewNop(),
+		)
+
+		dashboard := dashboardService.GetDashboard(ctx, me)
+
+		assert.ElementsMatch(t, []string{service.DashboardSectionNextTTR, service.DashboardSectionUnreadNotifications}, dashboard.Warnings)
+		assert.Nil(t, dashboard.NextTTR)
+		assert.Nil(t, dashboard.UnreadNotifications)
+		assert.Equal(t, map[uuid.UUID]int{next.ID: 1, later.ID: 2}, dashboard.PendingInvitations, "other sections still load")
+		assert.Len(t, dashboard.ActionItems, 1)
+	})
+
+	t.Run("a round being played is still the next one", func(t *testing.T) {
+		dashboardService := service.NewDashboardService(ttrRepo, invitationRepo, notificationRepo, actionItemService, zap.NewNop())
+		player := uuid.New()
+		teeAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Minute)
+		playing := createTTR(0, "Torrey Pines")
+		playing.TeeDate = teeAt.Truncate(24 * time.Hour)
+		playing.TeeTime = time.Date(0, 1, 1, teeAt.Hour(), teeAt.Minute(), 0, 0, time.UTC)
+		require.NoError(t, ttrRepo.Update(ctx, playing))
+		require.NoError(t, ttrRepo.AddPlayer(ctx, playing.ID, player, models.TTRPlayerStatusConfirmed))
+		require.NoError(t, ttrRepo.AddPlayer(ctx, next.ID, player, models.TTRPlayerStatusConfirmed))
+
+		dashboard := dashboardService.GetDashboard(ctx, player)
+
+		require.NotNil(t, dashboard.NextTTR)
+		assert.Equal(t, playing.ID, dashboard.NextTTR.TTR.ID, "it teed off within the grace")
+	})
+
+	t.Run("no upcoming round is not a failure", func(t *testing.T) {
+		dashboardService := service.NewDashboardService(ttrRepo, invitationRepo, notificationRepo, actionItemService, zap.NewNop())
+
+		dashboard := dashboardService.GetDashboard(ctx, uuid.New())
+
+		assert.Empty(t, dashboard.Warnings)
+		assert.Nil(t, dashboard.NextTTR)
+		assert.Empty(t, dashboard.PendingInvitations)
+		require.NotNil(t, dashboard.UnreadNotifications)
+		assert.Zero(t, *dashboard.UnreadNotifications)
+	})
+}
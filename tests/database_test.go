@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+var errConnectionRefused = errors.New("connection refused")
+
+// failingDialer fails the first failures dials and then succeeds, counting
+// every attempt.
+func failingDialer(failures int, attempts *int) database.Dialer {
+	return func(ctx context.Context) (*gorm.DB, error) {
+		*attempts++
+		if *attempts <= failures {
+			return nil, errConnectionRefused
+		}
+		return &gorm.DB{}, nil
+	}
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := database.Backoff{Initial: 500 * time.Millisecond, Max: 5 * time.Second}
+
+	var schedule []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		schedule = append(schedule, backoff.Delay(attempt))
+	}
+
+	assert.Equal(t, []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		5 * time.Second,
+		5 * time.Second,
+	}, schedule)
+}
+
+func TestConnect_RetriesUntilDialSucceeds(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	backoff := database.Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond, Timeout: time.Minute}
+
+	attempts := 0
+	db, err := database.Connect(context.Background(), failingDialer(3, &attempts), backoff, zap.New(core))
+
+	require.NoError(t, err)
+	assert.NotNil(t, db)
+	assert.Equal(t, 4, attempts)
+
+	entries := logs.FilterMessage("database connection failed").All()
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		fields := entry.ContextMap()
+		assert.Equal(t, int64(i+1), fields["attempt"])
+		assert.Equal(t, backoff.Delay(i+1), fields["retry_in"])
+		assert.Equal(t, errConnectionRefused.Error(), fields["error"])
+	}
+}
+
+func TestConnect_GivesUpAfterTimeout(t *testing.T) {
+	backoff := database.Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Timeout: 50 * time.Millisecond}
+
+	attempts := 0
+	start := time.Now()
+	db, err := database.Connect(context.Background(), failingDialer(1000, &attempts), backoff, zap.NewNop())
+
+	assert.Nil(t, db)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errConnectionRefused)
+	assert.Greater(t, attempts, 1)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestConnect_ZeroTimeoutTriesOnce(t *testing.T) {
+	attempts := 0
+	_, err := database.Connect(context.Background(), failingDialer(1, &attempts), database.Backoff{Initial: time.Millisecond}, zap.NewNop())
+
+	assert.ErrorIs(t, err, errConnectionRefused)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/dataloader"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	var batches [][]int
+	loader := dataloader.New(func(ctx context.Context, keys []int) (map[int]string, error) {
+		batches = append(batches, keys)
+		values := map[int]string{}
+		for _, key := range keys {
+			if key > 0 {
+				values[key] = string(rune('a' + key - 1))
+			}
+		}
+		return values, nil
+	})
+
+	first := loader.Load(ctx, 1)
+	second := loader.Load(ctx, 2)
+	again := loader.Load(ctx, 1)
+	missing := loader.Load(ctx, -1)
+
+	value, found, err := second()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "b", value)
+
+	value, found, err = first()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "a", value)
+
+	value, _, _ = again()
+	assert.Equal(t, "a", value)
+	_, found, err = missing()
+	require.NoError(t, err)
+	assert.False(t, found, "keys fetch leaves out aren't found")
+
+	assert.Equal(t, [][]int{{1, 2, -1}}, batches, "queued keys are fetched together, once each")
+
+	t.Run("cached keys aren't fetched again", func(t *testing.T) {
+		loader.Prime(5, "primed")
+		values, err := loader.LoadMany(ctx, []int{1, 3, 5})
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "a", 3: "c", 5: "primed"}, values)
+		assert.Equal(t, []int{3}, batches[len(batches)-1])
+	})
+
+	t.Run("prime doesn't replace a fetched value", func(t *testing.T) {
+		loader.Prime(2, "primed")
+		value, _, _ := loader.Load(ctx, 2)()
+		assert.Equal(t, "b", value)
+	})
+}
+
+func TestLoader_Error(t *testing.T) {
+	ctx := context.Background()
+	calls := 0
+	loader := dataloader.New(func(ctx context.Context, keys []int) (map[int]string, error) {
+		calls++
+		return nil, errors.New("database is down")
+	})
+
+	first := loader.Load(ctx, 1)
+	second := loader.Load(ctx, 2)
+
+	_, found, err := first()
+	assert.EqualError(t, err, "database is down")
+	assert.False(t, found)
+	_, _, err = second()
+	assert.EqualError(t, err, "database is down")
+	assert.Equal(t, 1, calls)
+
+	_, err = loader.LoadMany(ctx, []int{1, 2})
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+// fakeMailer records when each message was sent and fails those to failFor.
+type fakeMailer struct {
+	mu      sync.Mutex
+	sent    []mailer.Message
+	sentAt  []time.Time
+	failFor string
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if msg.To == m.failFor {
+		return errors.New("mailbox unavailable")
+	}
+	m.sent = append(m.sent, msg)
+	m.sentAt = append(m.sentAt, time.Now())
+	return nil
+}
+
+func (m *fakeMailer) recipients() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	to := make([]string, 0, len(m.sent))
+	for _, msg := range m.sent {
+		to = append(to, msg.To)
+	}
+	return to
+}
+
+type digestFixture struct {
+	userRepo       repository.UserRepository
+	ttrRepo        repository.TTRRepository
+	invitationRepo repository.InvitationRepository
+	mailer         *fakeMailer
+	service        *service.DigestService
+}
+
+// newDigestFixture sets up a digest service whose run started at midnight
+// UTC today, so it is always within its run window.
+func newDigestFixture(cfg config.DigestConfig) *digestFixture {
+	store := memory.NewStore()
+	f := &digestFixture{
+		userRepo:       memory.NewUserRepository(store),
+		ttrRepo:        memory.NewTTRRepository(store),
+		invitationRepo: memory.NewInvitationRepository(store),
+		mailer:         &fakeMailer{},
+	}
+	actionItemService := service.NewActionItemService(memory.NewActionItemRepository(store))
+	f.service = service.NewDigestService(f.userRepo, f.ttrRepo, actionItemService, f.mailer, cfg, zap.NewNop())
+	return f
+}
+
+func todaysDigest() config.DigestConfig {
+	return config.DigestConfig{Weekday: time.Now().UTC().Weekday(), Hour: 0, BatchSize: 2}
+}
+
+func (f *digestFixture) createUser(t *testing.T, name string) *models.User {
+	user := &models.User{Email: name + "@example.com", PasswordHash: "hash", FirstName: name, LastName: "Golfer"}
+	require.NoError(t, f.userRepo.Create(context.Background(), user))
+	return user
+}
+
+func (f *digestFixture) createTTR(t *testing.T, captainID uuid.UUID, days int, course string) *models.TTR {
+	ttr := &models.TTR{
+		CourseName:      course,
+		TeeDate:         time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captainID,
+		CaptainUserID:   captainID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPublic,
+	}
+	require.NoError(t, f.ttrRepo.Create(context.Background(), ttr))
+	return ttr
+}
+
+func (f *digestFixture) digestSentAt(t *testing.T, userID uuid.UUID) *time.Time {
+	user, err := f.userRepo.FindByID(context.Background(), userID)
+	require.NoError(t, err)
+	return user.DigestSentAt
+}
+
+func TestRenderDigest(t *testing.T) {
+	teeTime := time.Date(2026, 6, 15, 8, 30, 0, 0, time.UTC)
+	invitationID := uuid.New()
+	digest := &service.Digest{
+		FirstName: "Ana",
+		Rounds: []service.DigestRound{
+			{CourseName: "Pebble Beach", TeeTime: teeTime, Captain: true},
+			{CourseName: "<Links & Co>", TeeTime: teeTime.AddDate(0, 0, 2)},
+		},
+		PendingInvitations: []service.ActionItem{
+			{Kind: service.ActionItemPendingInvitation, InvitationID: &invitationID, CourseName: "Torrey Pines", DueAt: teeTime.AddDate(0, 0, 1)},
+		},
+		ActionItems: []service.ActionItem{
+			{Kind: service.ActionItemMaybeStatus, CourseName: "Bandon Dunes", DueAt: teeTime},
+		},
+	}
+
+	msg, err := service.RenderDigest("en", "ana@example.com", digest)
+	require.NoError(t, err)
+	assert.Equal(t, "ana@example.com", msg.To)
+	assert.Equal(t, "Your week in golf", msg.Subject)
+	assert.Equal(t, `Hi Ana,
+
+Here's what's coming up this week.
+
+Your rounds
+- Pebble Beach, Mon 15 Jun 08:30 (you're captain)
+- <Links & Co>, Wed 17 Jun 08:30
+
+Invitations waiting for your answer
+- Torrey Pines, answer by Tue 16 Jun 08:30
+
+Also needing your attention
+- Bandon Dunes: you're still a maybe
+
+You can turn this email off in your notification preferences.
+`, msg.Body)
+
+	assert.Contains(t, msg.HTML, `<html lang="en">`)
+	assert.Contains(t, msg.HTML, "<li><strong>Pebble Beach</strong>, Mon 15 Jun 08:30 (you&#39;re captain)</li>")
+	assert.Contains(t, msg.HTML, "<strong>&lt;Links &amp; Co&gt;</strong>", "course names are escaped")
+	assert.NotContains(t, msg.HTML, "<Links")
+	assert.Contains(t, msg.HTML, "Bandon Dunes: you&#39;re still a maybe")
+
+	t.Run("sections without entries are left out", func(t *testing.T) {
+		msg, err := service.RenderDigest("en", "ana@example.com", &service.Digest{FirstName: "Ana", Rounds: digest.Rounds[:1]})
+		require.NoError(t, err)
+		assert.Contains(t, msg.Body, "Your rounds")
+		assert.NotContains(t, msg.Body, "Invitations waiting")
+		assert.NotContains(t, msg.HTML, "Also needing")
+	})
+
+	t.Run("in the user's language", func(t *testing.T) {
+		msg, err := service.RenderDigest("es", "ana@example.com", digest)
+		require.NoError(t, err)
+		assert.Equal(t, "Tu semana de golf", msg.Subject)
+		assert.Contains(t, msg.Body, "Hola Ana:")
+		assert.Contains(t, msg.Body, "- Pebble Beach, Mon 15 Jun 08:30 (eres capitán)")
+		assert.Contains(t, msg.HTML, `<html lang="es">`)
+	})
+}
+
+func TestDigestRunStart(t *testing.T) {
+	// 2026-06-17 is a Wednesday.
+	wednesday := time.Date(2026, 6, 17, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		weekday time.Weekday
+		hour    int
+		want    time.Time
+	}{
+		{"earlier this week", wednesday, time.Monday, 7, time.Date(2026, 6, 15, 7, 0, 0, 0, time.UTC)},
+		{"earlier today", wednesday, time.Wednesday, 7, time.Date(2026, 6, 17, 7, 0, 0, 0, time.UTC)},
+		{"at the hour", wednesday, time.Wednesday, 10, wednesday},
+		{"later today is last week's", wednesday, time.Wednesday, 11, time.Date(2026, 6, 10, 11, 0, 0, 0, time.UTC)},
+		{"later this week is last week's", wednesday, time.Friday, 7, time.Date(2026, 6, 12, 7, 0, 0, 0, time.UTC)},
+		{"in UTC", wednesday.In(time.FixedZone("UTC-12", -12*3600)), time.Wednesday, 7, time.Date(2026, 6, 17, 7, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, service.DigestRunStart(tt.now, tt.weekday, tt.hour))
+		})
+	}
+}
+
+func TestDigestService_SendWeeklyDigests(t *testing.T) {
+	ctx := context.Background()
+	cfg := todaysDigest()
+	cfg.SendInterval = 30 * time.Millisecond
+	f := newDigestFixture(cfg)
+
+	captain := f.createUser(t, "captain")
+	invitee := f.createUser(t, "invitee")
+	idle := f.createUser(t, "idle")
+	optedOut := f.createUser(t, "optedout")
+	optedOut.Notifications.NoWeeklyDigest = true
+	require.NoError(t, f.userRepo.Update(ctx, optedOut))
+
+	round := f.createTTR(t, captain.ID, 2, "Pebble Beach")
+	f.createTTR(t, captain.ID, 20, "Far Away Links")
+	require.NoError(t, f.invitationRepo.Create(ctx, &models.Invitation{TTRID: round.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}))
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, round.ID, optedOut.ID, models.TTRPlayerStatusConfirmed))
+
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+
+	assert.ElementsMatch(t, []string{captain.Email, invitee.Email}, f.mailer.recipients(), "users with nothing to report get no email")
+	for _, msg := range f.mailer.sent {
+		assert.Contains(t, msg.Body, "Pebble Beach")
+		assert.NotContains(t, msg.Body, "Far Away Links", "rounds after the coming week are left out")
+		assert.NotEmpty(t, msg.HTML)
+	}
+	require.Len(t, f.mailer.sentAt, 2)
+	assert.GreaterOrEqual(t, f.mailer.sentAt[1].Sub(f.mailer.sentAt[0]), cfg.SendInterval, "emails are throttled")
+
+	assert.NotNil(t, f.digestSentAt(t, idle.ID), "users with nothing to report are still handled")
+	assert.Nil(t, f.digestSentAt(t, optedOut.ID))
+
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+	assert.Len(t, f.mailer.recipients(), 2, "a second run in the same week sends nothing")
+}
+
+func TestDigestService_ResumesWithoutDoubleSending(t *testing.T) {
+	ctx := context.Background()
+	f := newDigestFixture(todaysDigest())
+
+	var users []*models.User
+	for _, name := range []string{"first", "second", "third"} {
+		user := f.createUser(t, name)
+		f.createTTR(t, user.ID, 1, "Torrey Pines")
+		users = append(users, user)
+	}
+	// A run that crashed after handling the first user.
+	runStart := service.DigestRunStart(time.Now(), time.Now().UTC().Weekday(), 0)
+	marked, err := f.userRepo.MarkDigestSent(ctx, users[0].ID, runStart, time.Now())
+	require.NoError(t, err)
+	require.True(t, marked)
+
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+	assert.ElementsMatch(t, []string{users[1].Email, users[2].Email}, f.mailer.recipients())
+}
+
+func TestDigestService_FailedDeliveryIsNotRetried(t *testing.T) {
+	ctx := context.Background()
+	f := newDigestFixture(todaysDigest())
+
+	bouncing := f.createUser(t, "bouncing")
+	f.createTTR(t, bouncing.ID, 1, "Torrey Pines")
+	other := f.createUser(t, "other")
+	f.createTTR(t, other.ID, 1, "Torrey Pines")
+	f.mailer.failFor = bouncing.Email
+
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+	assert.Equal(t, []string{other.Email}, f.mailer.recipients(), "one failed email doesn't stop the run")
+	assert.NotNil(t, f.digestSentAt(t, bouncing.ID))
+
+	f.mailer.failFor = ""
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+	assert.Equal(t, []string{other.Email}, f.mailer.recipients())
+}
+
+func TestDigestService_OutsideRunWindow(t *testing.T) {
+	ctx := context.Background()
+	cfg := todaysDigest()
+	// Yesterday's run is over.
+	cfg.Weekday = time.Now().UTC().AddDate(0, 0, -1).Weekday()
+	f := newDigestFixture(cfg)
+
+	user := f.createUser(t, "golfer")
+	f.createTTR(t, user.ID, 1, "Torrey Pines")
+
+	require.NoError(t, f.service.SendWeeklyDigests(ctx))
+	assert.Empty(t, f.mailer.recipients())
+	assert.Nil(t, f.digestSentAt(t, user.ID))
+}
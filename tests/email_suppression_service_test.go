@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"go.uber.org/zap"
+)
+
+type emailSuppressionFixture struct {
+	suppressionRepo repository.EmailSuppressionRepository
+	userRepo        repository.UserRepository
+	service         *service.EmailSuppressionService
+}
+
+func newEmailSuppressionFixture() *emailSuppressionFixture {
+	store := memory.NewStore()
+	f := &emailSuppressionFixture{
+		suppressionRepo: memory.NewEmailSuppressionRepository(store),
+		userRepo:        memory.NewUserRepository(store),
+	}
+	f.service = service.NewEmailSuppressionService(f.suppressionRepo, f.userRepo, memory.NewTransactor(store), zap.NewNop())
+	return f
+}
+
+func (f *emailSuppressionFixture) suppression(t *testing.T, email string) *models.EmailSuppression {
+	suppression, err := f.suppressionRepo.FindByEmailForUpdate(context.Background(), email)
+	require.NoError(t, err)
+	return suppression
+}
+
+func (f *emailSuppressionFixture) undeliverable(t *testing.T, email string) bool {
+	user, err := f.userRepo.FindByEmail(context.Background(), email)
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	return user.EmailUndeliverable
+}
+
+func TestEmailSuppressionService_RecordEvents(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		events     []mailevents.Event
+		suppressed bool
+		reason     string
+	}{
+		{"hard bounce", []mailevents.Event{{ID: "1", Kind: mailevents.KindHardBounce}}, true, models.SuppressionReasonHardBounce},
+		{"complaint", []mailevents.Event{{ID: "1", Kind: mailevents.KindComplaint}}, true, models.SuppressionReasonComplaint},
+		{"one soft bounce", []mailevents.Event{{ID: "1", Kind: mailevents.KindSoftBounce}}, false, ""},
+		{"soft bounces up to the limit", []mailevents.Event{
+			{ID: "1", Kind: mailevents.KindSoftBounce},
+			{ID: "2", Kind: mailevents.KindSoftBounce},
+			{ID: "3", Kind: mailevents.KindSoftBounce},
+		}, true, models.SuppressionReasonSoftBounce},
+		{"a delivery resets the soft bounces", []mailevents.Event{
+			{ID: "1", Kind: mailevents.KindSoftBounce},
+			{ID: "2", Kind: mailevents.KindSoftBounce},
+			{ID: "3", Kind: mailevents.KindDelivered},
+			{ID: "4", Kind: mailevents.KindSoftBounce},
+		}, false, ""},
+		{"a redelivered soft bounce counts once", []mailevents.Event{
+			{ID: "1", Kind: mailevents.KindSoftBounce},
+			{ID: "2", Kind: mailevents.KindSoftBounce},
+			{ID: "2", Kind: mailevents.KindSoftBounce},
+		}, false, ""},
+		{"suppression is permanent", []mailevents.Event{
+			{ID: "1", Kind: mailevents.KindHardBounce},
+			{ID: "2", Kind: mailevents.KindDelivered},
+			{ID: "3", Kind: mailevents.KindComplaint},
+		}, true, models.SuppressionReasonHardBounce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEmailSuppressionFixture()
+			require.NoError(t, f.userRepo.Create(ctx, &models.User{Email: "ann@example.com", PasswordHash: "hash", FirstName: "Ann", LastName: "Archer"}))
+			for i := range tt.events {
+				// Providers may send any spelling of the address.
+				tt.events[i].Email = "Ann@Example.com"
+			}
+
+			require.NoError(t, f.service.RecordEvents(ctx, tt.events))
+
+			suppression := f.suppression(t, "ann@example.com")
+			require.NotNil(t, suppression)
+			assert.Equal(t, tt.suppressed, suppression.Suppressed())
+			assert.Equal(t, tt.reason, suppression.Reason)
+			assert.Equal(t, tt.suppressed, f.undeliverable(t, "ann@example.com"))
+		})
+	}
+}
+
+func TestEmailSuppressionService_SoftBounceCounter(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSuppressionFixture()
+
+	require.NoError(t, f.service.RecordEvents(ctx, []mailevents.Event{{ID: "d", Email: "bob@example.com", Kind: mailevents.KindDelivered}}))
+	assert.Nil(t, f.suppression(t, "bob@example.com"), "deliveries alone aren't recorded")
+
+	for i := 1; i <= service.SoftBounceLimit; i++ {
+		require.NoError(t, f.service.RecordEvents(ctx, []mailevents.Event{{ID: string(rune('a' + i)), Email: "bob@example.com", Kind: mailevents.KindSoftBounce}}))
+		suppression := f.suppression(t, "bob@example.com")
+		require.NotNil(t, suppression)
+		assert.Equal(t, i, suppression.SoftBounces)
+		assert.Equal(t, i == service.SoftBounceLimit, suppression.Suppressed(), "after %d soft bounces", i)
+	}
+
+	require.NoError(t, f.service.RecordEvents(ctx, []mailevents.Event{{ID: "z", Email: "bob@example.com", Kind: mailevents.KindDelivered}}))
+	suppression := f.suppression(t, "bob@example.com")
+	assert.Zero(t, suppression.SoftBounces)
+	assert.True(t, suppression.Suppressed(), "a delivery doesn't lift a suppression")
+}
+
+func TestEmailSuppressionService_Mailer(t *testing.T) {
+	ctx := context.Background()
+	f := newEmailSuppressionFixture()
+	require.NoError(t, f.userRepo.Create(ctx, &models.User{Email: "ann@example.com", PasswordHash: "hash", FirstName: "Ann", LastName: "Archer"}))
+	suppressedAt := time.Now()
+	require.NoError(t, f.suppressionRepo.Upsert(ctx, &models.EmailSuppression{Email: "ann@example.com", Reason: models.SuppressionReasonHardBounce, SuppressedAt: &suppressedAt}))
+
+	sent := mailer.NewMemoryMailer()
+	mail := f.service.Mailer(sent)
+	require.NoError(t, mail.Send(ctx, mailer.Message{To: "ANN@example.com", Subject: "Hi"}))
+	require.NoError(t, mail.Send(ctx, mailer.Message{To: "bob@example.com", Subject: "Hi"}))
+
+	messages := sent.Sent()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "bob@example.com", messages[0].To)
+	assert.True(t, f.undeliverable(t, "ann@example.com"), "skipping mail flags the account")
+
+	assert.Nil(t, f.service.Mailer(nil))
+}
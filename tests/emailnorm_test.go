@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+)
+
+func TestEmailNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		lowercaseLocal string
+		preserveLocal  string
+	}{
+		{"already normalized", "john@example.com", "john@example.com", "john@example.com"},
+		{"mixed case", "John.Smith@Example.COM", "john.smith@example.com", "John.Smith@example.com"},
+		{"surrounding whitespace", "  john@example.com\t", "john@example.com", "john@example.com"},
+		{"quoted local part with @", `"a@b"@Example.com`, `"a@b"@example.com`, `"a@b"@example.com`},
+		{"no domain", "John", "john", "John"},
+	}
+
+	lowercase := emailnorm.Normalizer{LowercaseLocalPart: true}
+	preserve := emailnorm.Normalizer{LowercaseLocalPart: false}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.lowercaseLocal, lowercase.Normalize(tt.email))
+			assert.Equal(t, tt.preserveLocal, preserve.Normalize(tt.email))
+		})
+	}
+}
+
+func TestEmailNormalizer_Collisions(t *testing.T) {
+	emails := []string{
+		"john@example.com",
+		"John@Example.com",
+		"JOHN@example.com",
+		"jane@example.com",
+		"Jane@example.com",
+		"solo@example.com",
+		"solo@example.com",
+	}
+
+	t.Run("lowercased local parts", func(t *testing.T) {
+		n := emailnorm.Normalizer{LowercaseLocalPart: true}
+		assert.Equal(t, []emailnorm.Collision{
+			{Normalized: "jane@example.com", Emails: []string{"Jane@example.com", "jane@example.com"}},
+			{Normalized: "john@example.com", Emails: []string{"JOHN@example.com", "John@Example.com", "john@example.com"}},
+		}, n.Collisions(emails))
+	})
+
+	t.Run("case-sensitive local parts", func(t *testing.T) {
+		n := emailnorm.Normalizer{LowercaseLocalPart: false}
+		assert.Empty(t, n.Collisions(emails))
+
+		assert.Equal(t, []emailnorm.Collision{
+			{Normalized: "John@example.com", Emails: []string{"John@Example.com", "John@example.com"}},
+		}, n.Collisions(append(emails, "John@example.com")))
+	})
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+func TestErrcode_ServiceErrorsMapToDocumentedCodes(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   errcode.Code
+		status int
+	}{
+		{service.ErrInvalidCredentials, "INVALID_CREDENTIALS", http.StatusUnauthorized},
+		{service.ErrEmailTaken, "EMAIL_TAKEN", http.StatusConflict},
+		{service.ErrUserNotFound, "USER_NOT_FOUND", http.StatusNotFound},
+		{service.ErrAvatarTooLarge, "AVATAR_TOO_LARGE", http.StatusRequestEntityTooLarge},
+		{service.ErrAPITokenNotFound, "API_TOKEN_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvalidAPITokenExpiry, "INVALID_API_TOKEN_EXPIRY", http.StatusBadRequest},
+		{service.ErrInvalidAPITokenScope, "INVALID_API_TOKEN_SCOPE", http.StatusBadRequest},
+		{service.ErrCannotImpersonateAdmin, "CANNOT_IMPERSONATE", http.StatusForbidden},
+		{service.ErrCannotMergeSelf, "CANNOT_MERGE_USERS", http.StatusBadRequest},
+		{service.ErrMergeTargetNotFound, "USER_NOT_FOUND", http.StatusNotFound},
+		{service.ErrUserAlreadyMerged, "USER_ALREADY_MERGED", http.StatusConflict},
+		{service.ErrNotImpersonating, "NOT_IMPERSONATING", http.StatusBadRequest},
+		{service.ErrFeatureFlagNotFound, "FEATURE_FLAG_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvalidPercentage, "INVALID_FEATURE_FLAG", http.StatusBadRequest},
+		{service.ErrTTRNotFound, "TTR_NOT_FOUND", http.StatusNotFound},
+		{service.ErrTTRFull, "TTR_FULL", http.StatusBadRequest},
+		{service.ErrTTRFullForInvitation, "TTR_FULL", http.StatusBadRequest},
+		{service.ErrAlreadyPlayer, "ALREADY_PLAYER", http.StatusConflict},
+		{service.ErrInviteeAlreadyPlayer, "ALREADY_PLAYER", http.StatusConflict},
+		{service.ErrCaptainCannotLeave, "CAPTAIN_CANNOT_LEAVE", http.StatusBadRequest},
+		{service.ErrPlayerNotFound, "PLAYER_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvalidStatusChange, "INVALID_STATUS_CHANGE", http.StatusConflict},
+		{service.ErrInvalidHoles, "INVALID_HOLES", http.StatusBadRequest},
+		{service.ErrInvalidGreenFee, "INVALID_GREEN_FEE", http.StatusBadRequest},
+		{service.ErrNotManagerUpdateTTR, "NOT_TTR_MANAGER", http.StatusForbidden},
+		{service.ErrInvalidPermission, "INVALID_CO_CAPTAIN_PERMISSION", http.StatusBadRequest},
+		{service.ErrNotCoCaptain, "CO_CAPTAIN_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvitationPending, "INVITATION_ALREADY_PENDING", http.StatusConflict},
+		{service.ErrRSVPDeadlinePassed, "INVITATION_EXPIRED", http.StatusBadRequest},
+		{service.ErrInvitationTemplateNotFound, "INVITATION_TEMPLATE_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvalidMessageTemplate, "INVALID_MESSAGE_TEMPLATE", http.StatusUnprocessableEntity},
+		{service.ErrNotInvitee, "NOT_INVITEE", http.StatusForbidden},
+		{service.ErrInviteLinkUsedUp, "INVITE_LINK_USED_UP", http.StatusConflict},
+		{service.ErrNotManagerInviteLinks, "NOT_TTR_MANAGER", http.StatusForbidden},
+		{service.ErrNotManagerSuggestPairings, "NOT_TTR_MANAGER", http.StatusForbidden},
+		{service.ErrEditWindowExpired, "EDIT_WINDOW_EXPIRED", http.StatusForbidden},
+		{service.ErrOrganizationNotFound, "ORGANIZATION_NOT_FOUND", http.StatusNotFound},
+		{service.ErrNotOwnerDelete, "NOT_ORGANIZATION_OWNER", http.StatusForbidden},
+		{service.ErrTTRInLeague, "TTR_IN_LEAGUE", http.StatusConflict},
+		{service.ErrMatchCompleted, "MATCH_COMPLETED", http.StatusConflict},
+		{service.ErrWebhookNotFound, "WEBHOOK_NOT_FOUND", http.StatusNotFound},
+		{service.ErrInvalidWebhookURL, "INVALID_WEBHOOK_URL", http.StatusBadRequest},
+		{service.ErrInvalidWebhookEvent, "INVALID_WEBHOOK_EVENT", http.StatusBadRequest},
+		{service.ErrNotAdminWebhooks, "NOT_WEBHOOK_OWNER", http.StatusForbidden},
+		{service.ErrInvalidSlackLinkCode, "INVALID_SLACK_LINK_CODE", http.StatusBadRequest},
+		{service.ErrReportAlreadyOpen, "REPORT_ALREADY_OPEN", http.StatusConflict},
+		{service.ErrReportLimitReached, "REPORT_LIMIT_REACHED", http.StatusTooManyRequests},
+		{service.ErrReportClosed, "REPORT_CLOSED", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			var coded *errcode.Error
+			require.True(t, errors.As(tt.err, &coded))
+			assert.Equal(t, tt.code, coded.Code)
+
+			definition, ok := errcode.Lookup(coded.Code)
+			require.True(t, ok, "code is registered")
+			assert.Equal(t, tt.status, definition.Status)
+			assert.NotEmpty(t, definition.Description)
+		})
+	}
+}
+
+func TestErrcode_RegistryIsUnique(t *testing.T) {
+	seen := make(map[errcode.Code]bool)
+	for _, definition := range errcode.All() {
+		assert.False(t, seen[definition.Code], "%s is registered twice", definition.Code)
+		seen[definition.Code] = true
+		assert.NotZero(t, definition.Status)
+		assert.NotEmpty(t, definition.Description)
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, errcode.Status("NOT_A_CODE"))
+}
+
+func TestResponse_FromError(t *testing.T) {
+	t.Run("wrapped service error keeps its code", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		response.FromError(rec, fmt.Errorf("join: %w", service.ErrTTRFull), "Failed to join TTR")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var body response.Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.NotNil(t, body.Error)
+		assert.Equal(t, "TTR_FULL", body.Error.Code)
+		assert.Equal(t, "TTR is full", body.Error.Message)
+	})
+
+	t.Run("unknown error is an internal error", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		response.FromError(rec, errors.New("connection reset"), "Failed to join TTR")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		var body response.Response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		require.NotNil(t, body.Error)
+		assert.Equal(t, "INTERNAL_SERVER_ERROR", body.Error.Code)
+		assert.Equal(t, "Failed to join TTR", body.Error.Message)
+	})
+}
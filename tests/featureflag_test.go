@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/featureflag"
+	"go.uber.org/zap"
+)
+
+func TestFeatureFlag_BucketDistribution(t *testing.T) {
+	const users = 20000
+	counts := make([]int, featureflag.Buckets)
+	for i := 0; i < users; i++ {
+		counts[featureflag.Bucket("waitlist", uuid.New())]++
+	}
+
+	expected := users / featureflag.Buckets
+	for bucket, count := range counts {
+		assert.InDelta(t, expected, count, float64(expected)*0.5, "bucket %d", bucket)
+	}
+
+	rule := featureflag.Rule{Enabled: true, Percentage: 25}
+	enabled := 0
+	for i := 0; i < users; i++ {
+		if rule.Evaluate("waitlist", uuid.New(), nil) {
+			enabled++
+		}
+	}
+	assert.InDelta(t, 0.25, float64(enabled)/users, 0.02)
+}
+
+func TestFeatureFlag_BucketIsStable(t *testing.T) {
+	userID := uuid.New()
+	bucket := featureflag.Bucket("waitlist", userID)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, bucket, featureflag.Bucket("waitlist", userID))
+	}
+
+	// A user in a rollout stays in it as the rollout grows.
+	for percentage := bucket + 1; percentage <= 100; percentage++ {
+		assert.True(t, featureflag.Rule{Enabled: true, Percentage: percentage}.Evaluate("waitlist", userID, nil))
+	}
+	assert.False(t, featureflag.Rule{Enabled: true, Percentage: bucket}.Evaluate("waitlist", userID, nil))
+}
+
+func TestFeatureFlag_Precedence(t *testing.T) {
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name    string
+		rule    featureflag.Rule
+		orgIDs  []uuid.UUID
+		enabled bool
+	}{
+		{"allowlist beats a 0% rollout", featureflag.Rule{Enabled: true, Users: []uuid.UUID{userID}}, nil, true},
+		{"organization beats a 0% rollout", featureflag.Rule{Enabled: true, Organizations: []uuid.UUID{orgID}}, []uuid.UUID{orgID}, true},
+		{"other organizations don't count", featureflag.Rule{Enabled: true, Organizations: []uuid.UUID{uuid.New()}}, []uuid.UUID{orgID}, false},
+		{"100% is everyone", featureflag.Rule{Enabled: true, Percentage: 100}, nil, true},
+		{"disabled beats the allowlist", featureflag.Rule{Percentage: 100, Users: []uuid.UUID{userID}}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.enabled, tt.rule.Evaluate("waitlist", userID, tt.orgIDs))
+		})
+	}
+}
+
+func TestFlagService_StoredFlagsReplaceConfig(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	orgRepo := memory.NewOrganizationRepository(store)
+	flagService := service.NewFlagService(memory.NewFeatureFlagRepository(store), orgRepo, map[string]int{"messaging": 100, "waitlist": 0}, zap.NewNop())
+
+	userID := uuid.New()
+	org := &models.Organization{ID: uuid.New(), Name: "Pine Valley", CreatedByUserID: uuid.New()}
+	require.NoError(t, orgRepo.Create(ctx, org))
+	require.NoError(t, orgRepo.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: userID}))
+
+	flags, err := flagService.EvaluateFlags(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"messaging": true, "waitlist": false}, flags)
+
+	_, err = flagService.SetFlag(ctx, "waitlist", "Join full tee times", true, 0, nil, []uuid.UUID{org.ID})
+	require.NoError(t, err)
+	_, err = flagService.SetFlag(ctx, "messaging", "", false, 100, nil, nil)
+	require.NoError(t, err)
+
+	enabled, err := flagService.IsEnabled(ctx, userID, "waitlist")
+	require.NoError(t, err)
+	assert.True(t, enabled, "on for the user's organization")
+	enabled, err = flagService.IsEnabled(ctx, userID, "messaging")
+	require.NoError(t, err)
+	assert.False(t, enabled, "the stored flag replaces the config rollout")
+	enabled, err = flagService.IsEnabled(ctx, userID, "unknown")
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	require.NoError(t, flagService.DeleteFlag(ctx, "messaging"))
+	enabled, err = flagService.IsEnabled(ctx, userID, "messaging")
+	require.NoError(t, err)
+	assert.True(t, enabled, "back to the config rollout")
+	assert.ErrorIs(t, flagService.DeleteFlag(ctx, "messaging"), service.ErrFeatureFlagNotFound)
+
+	_, err = flagService.SetFlag(ctx, "Bad Key", "", true, 50, nil, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidFeatureFlagKey)
+	_, err = flagService.SetFlag(ctx, "waitlist", "", true, 101, nil, nil)
+	assert.ErrorIs(t, err, service.ErrInvalidPercentage)
+}
@@ -0,0 +1,343 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/graphql"
+)
+
+type testBook struct {
+	ID       string
+	Title    string
+	AuthorID string
+}
+
+type testAuthor struct {
+	ID   string
+	Name string
+}
+
+const testLibrarySchema = `
+type Query {
+  books(first: Int = 10): [Book]
+  book(id: ID!): Book
+}
+
+type Book {
+  id: ID
+  title: String
+  author: Author
+  broken: String
+}
+
+type Author {
+  id: ID
+  name: String
+}
+`
+
+// newTestLibrary returns a schema over a few books whose author field
+// fetches authors in batches, and a pointer to the batches it fetched.
+func newTestLibrary() (*graphql.Schema, *[][]string) {
+	books := []*testBook{{"1", "Golf My Way", "a"}, {"2", "Five Lessons", "b"}, {"3", "Power Golf", "b"}}
+	authors := map[string]*testAuthor{"a": {"a", "Jack Nicklaus"}, "b": {"b", "Ben Hogan"}}
+	var batches [][]string
+	var pending []string
+	var fetched map[string]*testAuthor
+
+	return graphql.MustNewSchema(testLibrarySchema, graphql.Resolvers{
+		"Query": {
+			"books": func(p graphql.ResolveParams) (interface{}, error) {
+				first := p.Args["first"].(int)
+				if first > len(books) {
+					first = len(books)
+				}
+				return books[:first], nil
+			},
+			"book": func(p graphql.ResolveParams) (interface{}, error) {
+				for _, b := range books {
+					if b.ID == p.Args["id"] {
+						return b, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+		"Book": {
+			"id":    func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*testBook).ID, nil },
+			"title": func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*testBook).Title, nil },
+			"author": func(p graphql.ResolveParams) (interface{}, error) {
+				id := p.Source.(*testBook).AuthorID
+				pending = append(pending, id)
+				return graphql.Thunk(func() (interface{}, error) {
+					if pending != nil {
+						batches = append(batches, pending)
+						fetched = map[string]*testAuthor{}
+						for _, id := range pending {
+							fetched[id] = authors[id]
+						}
+						pending = nil
+					}
+					return fetched[id], nil
+				}), nil
+			},
+			"broken": func(p graphql.ResolveParams) (interface{}, error) {
+				return nil, errors.New("broken")
+			},
+		},
+		"Author": {
+			"id":   func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*testAuthor).ID, nil },
+			"name": func(p graphql.ResolveParams) (interface{}, error) { return p.Source.(*testAuthor).Name, nil },
+		},
+	}), &batches
+}
+
+func executeJSON(t *testing.T, schema *graphql.Schema, params graphql.Params) (string, []*graphql.Error) {
+	t.Helper()
+	result := schema.Execute(context.Background(), params)
+	if result.Rejected() {
+		return "", result.Errors
+	}
+	data, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	return string(data), result.Errors
+}
+
+func TestGraphQL_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "fields in the order asked",
+			query: `{ book(id: "2") { title id } }`,
+			want:  `{"book":{"title":"Five Lessons","id":"2"}}`,
+		},
+		{
+			name:  "aliases",
+			query: `{ first: book(id: "1") { title } second: book(id: "3") { name: title } }`,
+			want:  `{"first":{"title":"Golf My Way"},"second":{"name":"Power Golf"}}`,
+		},
+		{
+			name:  "arguments and defaults",
+			query: `{ some: books(first: 1) { id } all: books { id } }`,
+			want:  `{"some":[{"id":"1"}],"all":[{"id":"1"},{"id":"2"},{"id":"3"}]}`,
+		},
+		{
+			name:      "variables",
+			query:     `query Book($id: ID!) { book(id: $id) { title } }`,
+			variables: map[string]interface{}{"id": "3"},
+			want:      `{"book":{"title":"Power Golf"}}`,
+		},
+		{
+			name:  "fragments",
+			query: `{ book(id: "1") { ...Details ... on Book { author { name } } } } fragment Details on Book { id title }`,
+			want:  `{"book":{"id":"1","title":"Golf My Way","author":{"name":"Jack Nicklaus"}}}`,
+		},
+		{
+			name:  "repeated fields are merged",
+			query: `{ book(id: "1") { author { id } author { name } } }`,
+			want:  `{"book":{"author":{"id":"a","name":"Jack Nicklaus"}}}`,
+		},
+		{
+			name:      "skip and include",
+			query:     `query($yes: Boolean!) { book(id: "1") { id @skip(if: $yes) title @include(if: $yes) } }`,
+			variables: map[string]interface{}{"yes": true},
+			want:      `{"book":{"title":"Golf My Way"}}`,
+		},
+		{
+			name:      "Int variables from JSON",
+			query:     `query($first: Int) { books(first: $first) { id } }`,
+			variables: map[string]interface{}{"first": float64(1)},
+			want:      `{"books":[{"id":"1"}]}`,
+		},
+		{
+			name:  "typename",
+			query: `{ book(id: "1") { __typename } }`,
+			want:  `{"book":{"__typename":"Book"}}`,
+		},
+		{
+			name:  "a missing object is null",
+			query: `{ book(id: "9") { id } }`,
+			want:  `{"book":null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, _ := newTestLibrary()
+			data, errs := executeJSON(t, schema, graphql.Params{Query: tt.query, Variables: tt.variables})
+			require.Empty(t, errs)
+			assert.JSONEq(t, tt.want, data)
+		})
+	}
+}
+
+func TestGraphQL_ThunksAreBatched(t *testing.T) {
+	schema, batches := newTestLibrary()
+
+	data, errs := executeJSON(t, schema, graphql.Params{Query: `{ books { author { name } } }`})
+	require.Empty(t, errs)
+	assert.JSONEq(t, `{"books":[{"author":{"name":"Jack Nicklaus"}},{"author":{"name":"Ben Hogan"}},{"author":{"name":"Ben Hogan"}}]}`, data)
+	assert.Equal(t, [][]string{{"a", "b", "b"}}, *batches, "every author is fetched in one batch")
+}
+
+func TestGraphQL_FieldErrors(t *testing.T) {
+	schema, _ := newTestLibrary()
+
+	data, errs := executeJSON(t, schema, graphql.Params{Query: `{ books(first: 2) { id broken } }`})
+	assert.JSONEq(t, `{"books":[{"id":"1","broken":null},{"id":"2","broken":null}]}`, data)
+	require.Len(t, errs, 2)
+	assert.Equal(t, []interface{}{"books", 0, "broken"}, errs[0].Path)
+	assert.Equal(t, []interface{}{"books", 1, "broken"}, errs[1].Path)
+	assert.EqualError(t, errs[0].Unwrap(), "broken")
+}
+
+func TestGraphQL_Rejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		maxDepth  int
+		maxFields int
+		check     func(t *testing.T, err error)
+	}{
+		{name: "syntax error", query: `{ books { id }`, check: isError[*graphql.SyntaxError]},
+		{name: "unknown field", query: `{ books { isbn } }`, check: isError[*graphql.ValidationError]},
+		{name: "unknown argument", query: `{ books(last: 1) { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "missing required argument", query: `{ book { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "wrong argument type", query: `{ books(first: "two") { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "missing selection", query: `{ books }`, check: isError[*graphql.ValidationError]},
+		{name: "selection on a leaf", query: `{ books { id { x } } }`, check: isError[*graphql.ValidationError]},
+		{name: "mutation", query: `mutation { books { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "unknown fragment", query: `{ books { ...Missing } }`, check: isError[*graphql.ValidationError]},
+		{name: "fragment cycle", query: `{ books { ...A } } fragment A on Book { ...B } fragment B on Book { ...A }`, check: isError[*graphql.ValidationError]},
+		{name: "undeclared variable", query: `{ book(id: $id) { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "missing variable", query: `query($id: ID!) { book(id: $id) { id } }`, check: isError[*graphql.ValidationError]},
+		{name: "wrong variable type", query: `query($id: ID!) { book(id: $id) { id } }`, variables: map[string]interface{}{"id": true}, check: isError[*graphql.ValidationError]},
+		{name: "fractional Int variable", query: `query($first: Int) { books(first: $first) { id } }`, variables: map[string]interface{}{"first": 1.5}, check: isError[*graphql.ValidationError]},
+		{name: "introspection", query: `{ __schema { queryType { name } } }`, check: isError[*graphql.ValidationError]},
+		{
+			name:     "too deep",
+			query:    `{ books { author { name } } }`,
+			maxDepth: 2,
+			check: func(t *testing.T, err error) {
+				var limit *graphql.LimitError
+				require.ErrorAs(t, err, &limit)
+				assert.Equal(t, "depth", limit.Limit)
+			},
+		},
+		{
+			name:      "too complex",
+			query:     `{ books { id title author { id name } } }`,
+			maxFields: 5,
+			check: func(t *testing.T, err error) {
+				var limit *graphql.LimitError
+				require.ErrorAs(t, err, &limit)
+				assert.Equal(t, "complexity", limit.Limit)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, _ := newTestLibrary()
+			result := schema.Execute(context.Background(), graphql.Params{
+				Query:         tt.query,
+				Variables:     tt.variables,
+				MaxDepth:      tt.maxDepth,
+				MaxComplexity: tt.maxFields,
+			})
+			require.True(t, result.Rejected())
+			require.NotEmpty(t, result.Errors)
+			tt.check(t, result.Errors[0])
+		})
+	}
+}
+
+func TestGraphQL_WithinLimits(t *testing.T) {
+	schema, _ := newTestLibrary()
+
+	_, errs := executeJSON(t, schema, graphql.Params{
+		Query:         `{ books { id title author { id name } } }`,
+		MaxDepth:      3,
+		MaxComplexity: 6,
+	})
+	assert.Empty(t, errs)
+}
+
+func TestGraphQL_NewSchema(t *testing.T) {
+	resolve := func(p graphql.ResolveParams) (interface{}, error) { return nil, nil }
+
+	tests := []struct {
+		name      string
+		sdl       string
+		resolvers graphql.Resolvers
+		wantErr   string
+	}{
+		{
+			name:      "every field resolved",
+			sdl:       `type Query { ping: String }`,
+			resolvers: graphql.Resolvers{"Query": {"ping": resolve}},
+		},
+		{
+			name:    "invalid SDL",
+			sdl:     `type Query { ping: Missing }`,
+			wantErr: "Missing",
+		},
+		{
+			name:    "missing resolver",
+			sdl:     `type Query { ping: String }`,
+			wantErr: "field Query.ping has no resolver",
+		},
+		{
+			name:      "resolver without a field",
+			sdl:       `type Query { ping: String }`,
+			resolvers: graphql.Resolvers{"Query": {"ping": resolve, "pong": resolve}},
+			wantErr:   "resolver for Query.pong has no field",
+		},
+		{
+			name:      "non-null output",
+			sdl:       `type Query { ping: String! }`,
+			resolvers: graphql.Resolvers{"Query": {"ping": resolve}},
+			wantErr:   "field Query.ping must be nullable",
+		},
+		{
+			name:      "mutations",
+			sdl:       `type Query { ping: String } type Mutation { ping: String }`,
+			resolvers: graphql.Resolvers{"Query": {"ping": resolve}, "Mutation": {"ping": resolve}},
+			wantErr:   "no mutations",
+		},
+		{
+			name:      "interfaces",
+			sdl:       `type Query { node: Node } interface Node { id: ID }`,
+			resolvers: graphql.Resolvers{"Query": {"node": resolve}},
+			wantErr:   "type Node is of unsupported kind INTERFACE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := graphql.NewSchema(tt.sdl, tt.resolvers)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func isError[T error](t *testing.T, err error) {
+	t.Helper()
+	var target T
+	assert.ErrorAs(t, err, &target, "got %v", err)
+}
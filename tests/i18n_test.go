@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/golf_messenger/pkg/i18n"
+)
+
+func TestI18n_Match(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"empty header", "", "en"},
+		{"exact language", "es", "es"},
+		{"region subtag", "es-MX", "es"},
+		{"highest quality wins", "en;q=0.5, es;q=0.9", "es"},
+		{"unsupported languages skipped", "fr-FR, de;q=0.9, es;q=0.1", "es"},
+		{"nothing supported", "fr, de", "en"},
+		{"malformed quality ignored", "es;q=abc", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, i18n.Match(tt.header))
+		})
+	}
+}
+
+func TestI18n_TranslateFallback(t *testing.T) {
+	assert.Equal(t, "TTR no encontrado", i18n.Translate("es", "error.ttr_not_found", nil))
+	assert.Equal(t, "TTR not found", i18n.Translate("fr", "error.ttr_not_found", nil))
+	assert.Equal(t, "error.no_such_message", i18n.Translate("es", "error.no_such_message", nil))
+	assert.Equal(t, "Te han invitado a unirte a Pine Valley",
+		i18n.Translate("es", "notification.organization_invitation.message", map[string]string{"organization": "Pine Valley"}))
+}
+
+func TestI18n_Localize(t *testing.T) {
+	key, message := i18n.Localize("es", "user not found")
+	assert.Equal(t, "error.user_not_found", key)
+	assert.Equal(t, "usuario no encontrado", message)
+
+	key, message = i18n.Localize("es", "something went sideways")
+	assert.Empty(t, key)
+	assert.Equal(t, "something went sideways", message)
+}
+
+func TestI18n_CatalogsCoverDefaultLanguage(t *testing.T) {
+	for _, lang := range i18n.Languages() {
+		for _, text := range []string{"Invalid request body", "Validation failed", "TTR not found"} {
+			key := i18n.KeyFor(text)
+			if assert.NotEmpty(t, key, text) {
+				assert.NotEqual(t, key, i18n.Translate(lang, key, nil), "%s: %s", lang, key)
+			}
+		}
+	}
+}
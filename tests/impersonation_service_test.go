@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+	"go.uber.org/zap"
+)
+
+func TestImpersonationService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepository(store)
+	auditLogRepo := memory.NewAuditLogRepository(store)
+	impersonationService := service.NewImpersonationService(userRepo, memory.NewImpersonationRepository(store), auditLogRepo, "test-secret", 15*time.Minute, zap.NewNop())
+
+	admin := &models.User{Email: "admin@example.com", FirstName: "Admin", LastName: "Tester", Role: models.UserRoleAdmin}
+	otherAdmin := &models.User{Email: "other-admin@example.com", FirstName: "Other", LastName: "Tester", Role: models.UserRoleAdmin}
+	golfer := &models.User{Email: "golfer@example.com", FirstName: "Golfer", LastName: "Tester", Role: models.UserRoleUser}
+	for _, user := range []*models.User{admin, otherAdmin, golfer} {
+		require.NoError(t, userRepo.Create(ctx, user))
+	}
+
+	t.Run("admins cannot be impersonated", func(t *testing.T) {
+		_, _, err := impersonationService.StartImpersonation(ctx, admin.ID, admin.ID, "testing", false, "req-1")
+		assert.True(t, errors.Is(err, service.ErrCannotImpersonateSelf))
+		_, _, err = impersonationService.StartImpersonation(ctx, admin.ID, otherAdmin.ID, "testing", false, "req-1")
+		assert.True(t, errors.Is(err, service.ErrCannotImpersonateAdmin))
+	})
+
+	session, token, err := impersonationService.StartImpersonation(ctx, admin.ID, golfer.ID, " ticket 42 ", false, "req-2")
+	require.NoError(t, err)
+	assert.Equal(t, "ticket 42", session.Reason)
+
+	t.Run("the token carries the user and the acting admin", func(t *testing.T) {
+		claims, err := jwt.ValidateAccessToken(token, "test-secret")
+		require.NoError(t, err)
+		assert.Equal(t, golfer.ID, claims.UserID)
+		assert.Equal(t, golfer.Role, claims.Role)
+		require.NotNil(t, claims.Act)
+		assert.Equal(t, admin.ID, claims.Act.Subject)
+		assert.False(t, claims.Act.AllowWrites)
+		assert.Equal(t, session.ID.String(), claims.ID)
+	})
+
+	t.Run("stopping ends the session once", func(t *testing.T) {
+		active, err := impersonationService.ImpersonationActive(ctx, session.ID)
+		require.NoError(t, err)
+		assert.True(t, active)
+
+		require.NoError(t, impersonationService.StopImpersonation(ctx, session.ID, "req-3"))
+		require.NoError(t, impersonationService.StopImpersonation(ctx, session.ID, "req-4"))
+		active, err = impersonationService.ImpersonationActive(ctx, session.ID)
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("start and stop are audited under the admin", func(t *testing.T) {
+		entries, err := impersonationService.ListAuditLog(ctx, &golfer.ID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		actions := []string{entries[0].Action, entries[1].Action}
+		assert.ElementsMatch(t, []string{models.AuditActionImpersonationStart, models.AuditActionImpersonationStop}, actions)
+		for _, entry := range entries {
+			assert.Equal(t, admin.ID, entry.ActorUserID)
+			require.NotNil(t, entry.SessionID)
+			assert.Equal(t, session.ID, *entry.SessionID)
+		}
+	})
+}
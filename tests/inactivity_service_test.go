@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+type inactivityFixture struct {
+	userRepo         repository.UserRepository
+	ttrRepo          repository.TTRRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	auditLogRepo     repository.AuditLogRepository
+	notificationRepo repository.NotificationRepository
+	service          *service.InactivityService
+}
+
+func newInactivityFixture() *inactivityFixture {
+	store := memory.NewStore()
+	f := &inactivityFixture{
+		userRepo:         memory.NewUserRepository(store),
+		ttrRepo:          memory.NewTTRRepository(store),
+		refreshTokenRepo: memory.NewRefreshTokenRepository(store),
+		auditLogRepo:     memory.NewAuditLogRepository(store),
+		notificationRepo: memory.NewNotificationRepository(store),
+	}
+	logger := zap.NewNop()
+	notificationService := service.NewNotificationService(f.notificationRepo, f.userRepo, nil, 0, logger)
+	cfg := config.RetentionConfig{InactiveAfter: 180 * 24 * time.Hour, DeactivateAfter: 30 * 24 * time.Hour, BatchSize: 2}
+	f.service = service.NewInactivityService(f.userRepo, f.refreshTokenRepo, f.auditLogRepo, memory.NewTransactor(store), notificationService, cfg, logger)
+	return f
+}
+
+// createUser adds a user who last logged in daysAgo days ago.
+func (f *inactivityFixture) createUser(t *testing.T, daysAgo int) *models.User {
+	ctx := context.Background()
+	lastLogin := time.Now().AddDate(0, 0, -daysAgo)
+	user := &models.User{Email: uuid.NewString() + "@example.com", PasswordHash: "hash", FirstName: "Test", LastName: "Golfer", CreatedAt: lastLogin.AddDate(-1, 0, 0)}
+	require.NoError(t, f.userRepo.Create(ctx, user))
+	require.NoError(t, f.userRepo.UpdateLastLogin(ctx, user.ID, lastLogin))
+	return user
+}
+
+func (f *inactivityFixture) notifications(t *testing.T, userID uuid.UUID) []*models.Notification {
+	notifications, err := f.notificationRepo.FindByUserID(context.Background(), userID, 100, 0)
+	require.NoError(t, err)
+	return notifications
+}
+
+func (f *inactivityFixture) auditActions(t *testing.T, userID uuid.UUID) []string {
+	entries, err := f.auditLogRepo.Find(context.Background(), &userID, 100, 0)
+	require.NoError(t, err)
+	actions := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+func (f *inactivityFixture) deactivated(t *testing.T, userID uuid.UUID) bool {
+	user, err := f.userRepo.FindByID(context.Background(), userID)
+	require.NoError(t, err)
+	return user == nil
+}
+
+func TestInactivityService_Warn(t *testing.T) {
+	ctx := context.Background()
+	f := newInactivityFixture()
+
+	active := f.createUser(t, 179)
+	inactive := make([]*models.User, 0, 3)
+	for i := 0; i < 3; i++ {
+		inactive = append(inactive, f.createUser(t, 181+i))
+	}
+
+	require.NoError(t, f.service.ProcessInactiveUsers(ctx))
+	require.NoError(t, f.service.ProcessInactiveUsers(ctx))
+
+	assert.Empty(t, f.notifications(t, active.ID))
+	assert.Empty(t, f.auditActions(t, active.ID))
+	for _, user := range inactive {
+		notifications := f.notifications(t, user.ID)
+		require.Len(t, notifications, 1, "a second run doesn't warn again")
+		assert.Equal(t, models.NotificationTypeAccountInactive, notifications[0].Type)
+		assert.Equal(t, []string{models.AuditActionInactivityWarned}, f.auditActions(t, user.ID))
+		assert.False(t, f.deactivated(t, user.ID), "nobody is deactivated without the grace period")
+	}
+}
+
+func TestInactivityService_Deactivate(t *testing.T) {
+	ctx := context.Background()
+	f := newInactivityFixture()
+	now := time.Now()
+
+	graceLeft := f.createUser(t, 250)
+	graceOver := f.createUser(t, 250)
+	loggedIn := f.createUser(t, 250)
+	for _, warning := range []struct {
+		user    *models.User
+		daysAgo int
+	}{{graceLeft, 29}, {graceOver, 31}, {loggedIn, 31}} {
+		marked, err := f.userRepo.MarkInactivityWarned(ctx, warning.user.ID, now.AddDate(0, 0, -warning.daysAgo))
+		require.NoError(t, err)
+		require.True(t, marked)
+	}
+	tokenHash := uuid.NewString()
+	require.NoError(t, f.refreshTokenRepo.Create(ctx, &models.RefreshToken{UserID: graceOver.ID, TokenHash: tokenHash, ExpiresAt: now.Add(time.Hour)}))
+	// Logging in after the warning clears it, so loggedIn keeps their account.
+	require.NoError(t, f.userRepo.UpdateLastLogin(ctx, loggedIn.ID, now))
+
+	require.NoError(t, f.service.ProcessInactiveUsers(ctx))
+	require.NoError(t, f.service.ProcessInactiveUsers(ctx))
+
+	assert.False(t, f.deactivated(t, graceLeft.ID))
+	assert.Empty(t, f.auditActions(t, graceLeft.ID), "already warned")
+
+	assert.True(t, f.deactivated(t, graceOver.ID))
+	assert.Equal(t, []string{models.AuditActionDeactivated}, f.auditActions(t, graceOver.ID), "deactivated once")
+	token, err := f.refreshTokenRepo.FindByTokenHash(ctx, tokenHash)
+	require.NoError(t, err)
+	assert.True(t, token.Revoked, "deactivated users are signed out")
+
+	assert.False(t, f.deactivated(t, loggedIn.ID))
+	assert.Empty(t, f.auditActions(t, loggedIn.ID))
+	assert.Empty(t, f.notifications(t, loggedIn.ID))
+}
+
+func TestInactivityService_SkipsUpcomingTTRs(t *testing.T) {
+	ctx := context.Background()
+	f := newInactivityFixture()
+	now := time.Now()
+
+	captain := f.createUser(t, 400)
+	player := f.createUser(t, 400)
+	warned := f.createUser(t, 400)
+	marked, err := f.userRepo.MarkInactivityWarned(ctx, warned.ID, now.AddDate(0, 0, -60))
+	require.NoError(t, err)
+	require.True(t, marked)
+	pastPlayer := f.createUser(t, 400)
+
+	createTTR := func(days int) *models.TTR {
+		ttr := &models.TTR{
+			CourseName:      "Pebble Beach",
+			TeeDate:         now.AddDate(0, 0, days).Truncate(24 * time.Hour),
+			TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: captain.ID,
+			CaptainUserID:   captain.ID,
+			Status:          models.TTRStatusOpen,
+			Visibility:      models.TTRVisibilityPublic,
+		}
+		require.NoError(t, f.ttrRepo.Create(ctx, ttr))
+		return ttr
+	}
+	upcoming := createTTR(7)
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, upcoming.ID, player.ID, models.TTRPlayerStatusConfirmed))
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, upcoming.ID, warned.ID, models.TTRPlayerStatusMaybe))
+	played := createTTR(-7)
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, played.ID, pastPlayer.ID, models.TTRPlayerStatusConfirmed))
+
+	require.NoError(t, f.service.ProcessInactiveUsers(ctx))
+
+	for _, user := range []*models.User{captain, player, warned} {
+		assert.Empty(t, f.auditActions(t, user.ID))
+		assert.False(t, f.deactivated(t, user.ID))
+	}
+	assert.Equal(t, []string{models.AuditActionInactivityWarned}, f.auditActions(t, pastPlayer.ID), "past TTRs don't count")
+}
@@ -0,0 +1,326 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAdminAPI_LogLevel(t *testing.T) {
+	db := setupTestDB(t)
+
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+
+	authService := service.NewAuthService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+
+	api := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAdmin(handler.NewAdminHandler(level, nil, nil, nil, logger)),
+	).SetupRoutes()
+
+	userToken, _ := registerTestUser(t, api, "user@example.com", "Regular")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	t.Run("requires authentication", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/admin/log-level", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/admin/log-level", userToken, map[string]string{"level": "debug"})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "FORBIDDEN", env.Error.Code)
+		assert.Equal(t, zapcore.InfoLevel, level.Level())
+	})
+
+	t.Run("get current level", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/admin/log-level", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		var resp handler.LogLevelResponse
+		require.NoError(t, json.Unmarshal(env.Data, &resp))
+		assert.Equal(t, "info", resp.Level)
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/admin/log-level", adminToken, map[string]string{"level": "verbose"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, zapcore.InfoLevel, level.Level())
+	})
+
+	t.Run("flip to debug", func(t *testing.T) {
+		logger.Debug("before change")
+		assert.Zero(t, logs.FilterMessage("before change").Len())
+
+		code, env := doJSON(t, api, "PUT", "/api/v1/admin/log-level", adminToken, map[string]string{"level": "debug"})
+		require.Equal(t, http.StatusOK, code)
+
+		var resp handler.LogLevelResponse
+		require.NoError(t, json.Unmarshal(env.Data, &resp))
+		assert.Equal(t, "debug", resp.Level)
+
+		logger.Debug("after change")
+		assert.Equal(t, 1, logs.FilterMessage("after change").Len())
+	})
+}
+
+func TestAdminAPI_Stats(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger := zap.NewNop()
+
+	authService := service.NewAuthService(repository.NewUserRepository(db), repository.NewRefreshTokenRepository(db), nil, password.Policy{}, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	statsService := service.NewStatsService(repository.NewStatsRepository(db), cache.NewMemoryCache(), logger)
+	api := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), statsService, nil, nil, logger)),
+	).SetupRoutes()
+
+	userToken, _ := registerTestUser(t, api, "user@example.com", "Regular")
+	code, _ := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{"email": "user@example.com", "password": "password123"})
+	require.Equal(t, http.StatusOK, code)
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/admin/stats", userToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("admin gets a cached snapshot", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/admin/stats", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		var stats handler.StatsResponse
+		require.NoError(t, json.Unmarshal(env.Data, &stats))
+		assert.Equal(t, int64(1), stats.TotalUsers)
+		assert.Equal(t, handler.NewUsersResponse{Last7Days: 1, Last30Days: 1}, stats.NewUsers)
+		assert.Equal(t, int64(1), stats.ActiveUsers, "logging in makes the user active")
+		assert.Len(t, stats.TTRsByStatus, 4)
+		assert.Zero(t, stats.TTRsByStatus["OPEN"])
+		assert.Len(t, stats.InvitationsByStatus, 7)
+		assert.Zero(t, stats.AverageFillRate)
+		assert.NotEmpty(t, stats.GeneratedAt)
+
+		registerTestUser(t, api, "another@example.com", "Another")
+		code, env = doJSON(t, api, "GET", "/api/v1/admin/stats", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var cached handler.StatsResponse
+		require.NoError(t, json.Unmarshal(env.Data, &cached))
+		assert.Equal(t, stats, cached, "served from the cache for a minute")
+	})
+}
+
+func postImport(t *testing.T, h http.Handler, token, query string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", "members.csv")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/api/v1/admin/users/import"+query, &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAdminAPI_ImportUsers(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepository(db)
+	outbox := service.NewOutboxService(repository.NewOutboxRepository(db), config.OutboxConfig{BatchSize: 100, Lease: time.Minute, MaxAttempts: 3}, logger)
+	mail := mailer.NewMemoryMailer()
+	importService := service.NewUserImportService(userRepo, repository.NewAuditLogRepository(db), repository.NewTransactor(db), outbox, mail, 100, logger)
+	authService := service.NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, password.Policy{}, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	api := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), nil, importService, nil, logger)),
+	).SetupRoutes()
+
+	userToken, _ := registerTestUser(t, api, "taken@example.com", "Taken")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+	members, err := os.ReadFile(filepath.Join("..", "testdata", "import", "members.csv"))
+	require.NoError(t, err)
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		rec := postImport(t, api, userToken, "", members)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("not a member csv", func(t *testing.T) {
+		rec := postImport(t, api, adminToken, "", []byte("id,name\n1,Ann\n"))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "INVALID_IMPORT_FILE")
+	})
+
+	t.Run("imports the fixture", func(t *testing.T) {
+		rec := postImport(t, api, adminToken, "", members)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var env apiEnvelope
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+		var report handler.UserImportResponse
+		require.NoError(t, json.Unmarshal(env.Data, &report))
+		assert.Equal(t, 4, report.Created)
+		assert.Equal(t, 2, report.Skipped)
+		assert.Equal(t, 4, report.Failed)
+		require.Len(t, report.Rows, 10)
+		assert.Equal(t, "created", report.Rows[0].Status)
+		assert.NotNil(t, report.Rows[0].UserID)
+		assert.True(t, report.Rows[0].Invited)
+		assert.Equal(t, handler.UserImportRowResponse{Line: 8, Email: "taken@example.com", Status: "skipped_duplicate", Reason: "an account with this email already exists"}, report.Rows[6])
+
+		// The invited member logs in with the emailed password and is told
+		// to choose their own.
+		_, err := outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		var password string
+		for _, msg := range mail.Sent() {
+			if msg.To == "hal@example.com" {
+				password = strings.Split(msg.Body, "\n")[4]
+			}
+		}
+		require.NotEmpty(t, password)
+		code, env := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{"email": "hal@example.com", "password": password})
+		require.Equal(t, http.StatusOK, code)
+		var auth handler.AuthResponse
+		require.NoError(t, json.Unmarshal(env.Data, &auth))
+		assert.True(t, auth.User.MustResetPassword)
+		require.NotNil(t, auth.User.Handicap)
+		assert.Equal(t, 5.0, *auth.User.Handicap)
+	})
+
+	t.Run("error csv", func(t *testing.T) {
+		rec := postImport(t, api, adminToken, "?format=csv", members)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Header().Get("Content-Disposition"), "import-errors.csv")
+
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 11, "everyone but the header is a duplicate or an error the second time")
+		assert.Equal(t, []string{"line", "email", "status", "reason"}, rows[0])
+		assert.Equal(t, []string{"2", "ann@example.com", "skipped_duplicate", "an account with this email already exists"}, rows[1])
+		assert.Equal(t, []string{"5", "not-an-email", "error", "email is not a valid address"}, rows[4])
+	})
+}
+
+func TestAdminAPI_MergeUsers(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger := zap.NewNop()
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepository(db)
+	mergeService := service.NewUserMergeService(userRepo, repository.NewTransactor(db), logger)
+	authService := service.NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, password.Policy{}, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	api := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), nil, nil, mergeService, logger)),
+	).SetupRoutes()
+
+	sourceToken, sourceID := registerTestUser(t, api, "ana.google@example.com", "Ana")
+	_, targetID := registerTestUser(t, api, "ana@example.com", "Ana")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+	body := map[string]string{"source_user_id": sourceID, "target_user_id": targetID}
+
+	t.Run("non-admin is forbidden", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/users/merge", sourceToken, body)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "FORBIDDEN", env.Error.Code)
+	})
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/admin/users/merge", adminToken, map[string]string{"source_user_id": "nope", "target_user_id": targetID})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/users/merge", adminToken, map[string]string{"source_user_id": sourceID, "target_user_id": sourceID})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "CANNOT_MERGE_USERS", env.Error.Code)
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/users/merge", adminToken, map[string]string{"source_user_id": sourceID, "target_user_id": uuid.NewString()})
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "USER_NOT_FOUND", env.Error.Code)
+	})
+
+	t.Run("merges and can be retried", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/users/merge", adminToken, body)
+		require.Equal(t, http.StatusOK, code)
+		var resp handler.UserMergeResponse
+		require.NoError(t, json.Unmarshal(env.Data, &resp))
+		assert.Equal(t, sourceID, resp.SourceUserID)
+		assert.Equal(t, targetID, resp.TargetUserID)
+		assert.Equal(t, int64(1), resp.RevokedRefreshTokens)
+
+		source, err := userRepo.FindByIDUnscoped(ctx, uuid.MustParse(sourceID))
+		require.NoError(t, err)
+		require.NotNil(t, source.MergedIntoUserID)
+		assert.Equal(t, targetID, source.MergedIntoUserID.String())
+
+		code, env = doJSON(t, api, "POST", "/api/v1/admin/users/merge", adminToken, body)
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(env.Data, &resp))
+		assert.Equal(t, handler.UserMergeResponse{SourceUserID: sourceID, TargetUserID: targetID}, resp)
+	})
+}
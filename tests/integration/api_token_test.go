@@ -0,0 +1,203 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+)
+
+func TestAPITokenAPI_Scopes(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	userToken, _ := registerTestUser(t, api, "reader@example.com", "Reader")
+	code, env := doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", userToken, map[string]interface{}{
+		"name":   "dashboard",
+		"scopes": []string{"read:ttrs"},
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created handler.APITokenResponse
+	require.NoError(t, json.Unmarshal(env.Data, &created))
+	readOnly := created.Token
+
+	newTTR := map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+	}
+
+	t.Run("a read-only token can read", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/ttrs", readOnly, nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("a read-only token cannot create a TTR", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", readOnly, newTTR)
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INSUFFICIENT_SCOPE", env.Error.Code)
+		assert.JSONEq(t, `{"required_scope":"write:ttrs"}`, string(env.Error.Details))
+	})
+
+	t.Run("scopes are per resource", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/invitations/me", readOnly, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INSUFFICIENT_SCOPE", env.Error.Code)
+	})
+
+	t.Run("access tokens from logging in carry every scope", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs", userToken, newTTR)
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	t.Run("an access token with a scope claim is limited to it", func(t *testing.T) {
+		var me struct {
+			ID string `json:"id"`
+		}
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", userToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(env.Data, &me))
+
+		claims := &jwt.Claims{
+			TokenType: jwt.TokenTypeAccess,
+			UserID:    uuid.MustParse(me.ID),
+			Email:     "reader@example.com",
+			Scope:     "read:ttrs read:profile",
+			RegisteredClaims: jwtlib.RegisteredClaims{
+				ExpiresAt: jwtlib.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}
+		scoped, err := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs", scoped, nil)
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs", scoped, newTTR)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("unknown scopes are refused", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", userToken, map[string]interface{}{
+			"name":   "too much",
+			"scopes": []string{"read:ttrs", "delete:everything"},
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_API_TOKEN_SCOPE", env.Error.Code)
+	})
+}
+
+func TestAPITokenAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	userToken, userID := registerTestUser(t, api, "scripter@example.com", "Scripter")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", userToken, map[string]interface{}{
+		"name":   "tee sheet sync",
+		"scopes": []string{"write:ttrs", "read:profile", "read:ttrs", "write:ttrs"},
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created handler.APITokenResponse
+	require.NoError(t, json.Unmarshal(env.Data, &created))
+	require.True(t, strings.HasPrefix(created.Token, "gm_pat_"))
+	assert.True(t, strings.HasPrefix(created.Token, created.Prefix))
+	assert.Equal(t, "tee sheet sync", created.Name)
+	assert.Nil(t, created.ExpiresAt)
+	assert.Equal(t, []string{"read:profile", "read:ttrs", "write:ttrs"}, created.Scopes)
+	apiToken := created.Token
+
+	t.Run("authenticates as its owner", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", apiToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var me handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &me))
+		assert.Equal(t, userID, me.ID)
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs", apiToken, map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    "08:30",
+			"max_players": 4,
+		})
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	t.Run("list shows the prefix only and the last use", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me/api-tokens", userToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var listed []handler.APITokenResponse
+		require.NoError(t, json.Unmarshal(env.Data, &listed))
+		require.Len(t, listed, 1)
+		assert.Equal(t, created.ID, listed[0].ID)
+		assert.Equal(t, created.Prefix, listed[0].Prefix)
+		assert.Empty(t, listed[0].Token)
+		assert.NotNil(t, listed[0].LastUsedAt)
+	})
+
+	t.Run("cannot change the password or manage tokens", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/users/me/password", apiToken, map[string]interface{}{
+			"old_password": "password123",
+			"new_password": "hijacked123",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "API_TOKEN_NOT_ALLOWED", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", apiToken, map[string]interface{}{
+			"name":   "another",
+			"scopes": []string{"read:ttrs"},
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "API_TOKEN_NOT_ALLOWED", env.Error.Code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/users/me/api-tokens", apiToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = doJSON(t, api, "DELETE", "/api/v1/users/me/api-tokens/"+created.ID, apiToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("expiry must be in the future", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", userToken, map[string]interface{}{
+			"name":       "stale",
+			"scopes":     []string{"read:ttrs"},
+			"expires_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_API_TOKEN_EXPIRY", env.Error.Code)
+	})
+
+	t.Run("unknown tokens are refused", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/users/me", "gm_pat_not-a-real-token", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("only the owner can revoke", func(t *testing.T) {
+		code, env := doJSON(t, api, "DELETE", "/api/v1/users/me/api-tokens/"+created.ID, otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "API_TOKEN_NOT_FOUND", env.Error.Code)
+	})
+
+	t.Run("revoking takes effect immediately", func(t *testing.T) {
+		code, _ := doJSON(t, api, "DELETE", "/api/v1/users/me/api-tokens/"+created.ID, userToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/users/me", apiToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -2,31 +2,42 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
 	"github.com/yourusername/golf_messenger/internal/handler"
 	"github.com/yourusername/golf_messenger/internal/models"
 	"github.com/yourusername/golf_messenger/internal/repository"
 	"github.com/yourusername/golf_messenger/internal/router"
 	"github.com/yourusername/golf_messenger/internal/service"
-	"github.com/yourusername/golf_messenger/pkg/storage"
+	"github.com/yourusername/golf_messenger/pkg/password"
 	"go.uber.org/zap"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func setupTestDB(t *testing.T) *gorm.DB {
+func setupTestDB(t testing.TB) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
 		t.Fatalf("Failed to connect to test database: %v", err)
 	}
+	// Every connection to :memory: opens a new, empty database, so requests
+	// that query side by side, like the dashboard's, must share one.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get test database handle: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
 
-	err = db.AutoMigrate(&models.User{}, &models.RefreshToken{})
+	err = db.AutoMigrate(&models.User{}, &models.UserPlayingDay{}, &models.RefreshToken{})
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
 	}
@@ -49,21 +60,23 @@ func TestAuthFlow_Integration(t *testing.T) {
 	authService := service.NewAuthService(
 		userRepo,
 		refreshTokenRepo,
+		nil,
+		password.Policy{},
 		jwtSecret,
 		accessDuration,
 		refreshDuration,
 	)
-	userService := service.NewUserService(userRepo, nil)
+	userService := service.NewUserService(userRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandler(userService)
 
-	rt := router.NewRouter(
-		authHandler,
-		userHandler,
+	rt := router.New(
 		logger,
 		jwtSecret,
 		[]string{"*"},
+		router.WithAuth(authHandler),
+		router.WithUsers(userHandler),
 	)
 
 	httpHandler := rt.SetupRoutes()
@@ -159,3 +172,183 @@ func TestAuthFlow_Integration(t *testing.T) {
 		assert.True(t, logoutResp["success"].(bool))
 	})
 }
+
+// racingUserRepository holds every FindByEmail until all registrations have
+// made theirs, so each one gets past AuthService's duplicate-email check.
+type racingUserRepository struct {
+	repository.UserRepository
+	checked sync.WaitGroup
+}
+
+func (r *racingUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := r.UserRepository.FindByEmail(ctx, email)
+	r.checked.Done()
+	r.checked.Wait()
+	return user, err
+}
+
+func TestAuthFlow_ConcurrentRegistration(t *testing.T) {
+	db := setupTestDB(t)
+	// Every connection to ":memory:" opens a separate, empty database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	const registrations = 5
+	userRepo := &racingUserRepository{UserRepository: repository.NewUserRepository(db)}
+	userRepo.checked.Add(registrations)
+
+	authService := service.NewAuthService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+	api := router.New(zap.NewNop(), "test-secret", []string{"*"}, router.WithAuth(handler.NewAuthHandler(authService))).SetupRoutes()
+
+	codes := make(chan int, registrations)
+	var wg sync.WaitGroup
+	for i := 0; i < registrations; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, _ := json.Marshal(map[string]string{
+				"email":      "race@example.com",
+				"password":   "password123",
+				"first_name": "Race",
+				"last_name":  "Condition",
+			})
+			req := httptest.NewRequest("POST", "/api/v1/auth/register", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			api.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	counts := make(map[int]int)
+	for code := range codes {
+		counts[code]++
+	}
+	assert.Equal(t, map[int]int{
+		http.StatusCreated:  1,
+		http.StatusConflict: registrations - 1,
+	}, counts)
+}
+
+func TestAuthFlow_PasswordPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	policy := password.Policy{MinLength: 10, RequireClasses: []password.Rule{password.RuleDigit}, BanCommon: true, RejectEmail: true}
+
+	authService := service.NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, policy, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	userService := service.NewUserService(userRepo, nil, nil, policy, config.AvatarConfig{})
+	api := router.New(
+		zap.NewNop(),
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithUsers(handler.NewUserHandler(userService)),
+	).SetupRoutes()
+
+	type policyDetails struct {
+		Rules     []string `json:"rules"`
+		MinLength int      `json:"min_length"`
+	}
+	register := func(pass string) (int, apiEnvelope) {
+		return doJSON(t, api, "POST", "/api/v1/auth/register", "", map[string]string{
+			"email":      "policy@example.com",
+			"password":   pass,
+			"first_name": "Policy",
+			"last_name":  "Check",
+		})
+	}
+
+	code, env := register("password123")
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "WEAK_PASSWORD", env.Error.Code)
+	var details policyDetails
+	require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+	assert.Equal(t, policyDetails{Rules: []string{"common"}, MinLength: 10}, details)
+
+	code, env = register("short")
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, "VALIDATION_ERROR", env.Error.Code, "the request's own rules are checked first")
+
+	code, env = register("fairway-drive-7")
+	require.Equal(t, http.StatusCreated, code)
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	require.NoError(t, json.Unmarshal(env.Data, &tokens))
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/users/me/password", tokens.AccessToken, map[string]string{
+		"old_password": "fairway-drive-7",
+		"new_password": "Policy-is-my-name",
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, "WEAK_PASSWORD", env.Error.Code)
+	require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+	assert.Equal(t, []string{"digit", "email"}, details.Rules)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me/password", tokens.AccessToken, map[string]string{
+		"old_password": "fairway-drive-7",
+		"new_password": "back-nine-2024",
+	})
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestAuthFlow_ForcedResetPasswordPolicy(t *testing.T) {
+	db := setupTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	policy := password.Policy{MinLength: 10, RequireClasses: []password.Rule{password.RuleDigit}, BanCommon: true}
+
+	authService := service.NewAuthService(userRepo, repository.NewRefreshTokenRepository(db), nil, policy, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	userService := service.NewUserService(userRepo, nil, nil, policy, config.AvatarConfig{})
+	api := router.New(
+		zap.NewNop(),
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithUsers(handler.NewUserHandler(userService)),
+	).SetupRoutes()
+
+	// Imported accounts get an emailed password that the policy never saw,
+	// and must choose their own on first login.
+	user := &models.User{Email: "imported@example.com", FirstName: "Imported", LastName: "Member", MustResetPassword: true}
+	require.NoError(t, user.SetPassword("emailed-by-import"))
+	require.NoError(t, userRepo.Create(context.Background(), user))
+
+	code, env := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{"email": user.Email, "password": "emailed-by-import"})
+	require.Equal(t, http.StatusOK, code)
+	var auth handler.AuthResponse
+	require.NoError(t, json.Unmarshal(env.Data, &auth))
+	require.True(t, auth.User.MustResetPassword)
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/users/me/password", auth.AccessToken, map[string]string{
+		"old_password": "emailed-by-import",
+		"new_password": "password123",
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, "WEAK_PASSWORD", env.Error.Code)
+
+	stored, err := userRepo.FindByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.MustResetPassword, "a refused password doesn't end the reset")
+	assert.True(t, stored.CheckPassword("emailed-by-import"))
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me/password", auth.AccessToken, map[string]string{
+		"old_password": "emailed-by-import",
+		"new_password": "back-nine-2024",
+	})
+	require.Equal(t, http.StatusOK, code)
+	stored, err = userRepo.FindByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.MustResetPassword)
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+func setupAvatarAPI(t *testing.T, store storage.PublicStorage, maxSize int64) http.Handler {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	userRepo := repository.NewUserRepository(db)
+	authService := service.NewAuthService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+	userService := service.NewUserService(userRepo, store, nil, password.Policy{}, config.AvatarConfig{
+		MaxSize:      maxSize,
+		UploadURLTTL: 10 * time.Minute,
+	})
+
+	return router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithUsers(handler.NewUserHandler(userService)),
+	).SetupRoutes()
+}
+
+func postAvatar(t *testing.T, h http.Handler, token, contentType string, content []byte) (int, apiEnvelope) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	partHeader := make(textproto.MIMEHeader)
+	partHeader.Set("Content-Disposition", `form-data; name="avatar"; filename="avatar"`)
+	partHeader.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(partHeader)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/api/v1/users/me/avatar", &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var env apiEnvelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env), "body: %s", rec.Body.String())
+	return rec.Code, env
+}
+
+func TestAvatarAPI_MultipartUpload(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	api := setupAvatarAPI(t, store, 1000)
+	token, _ := registerTestUser(t, api, "golfer@example.com", "Golfer")
+
+	code, env := postAvatar(t, api, token, "image/png", make([]byte, 1001))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "AVATAR_TOO_LARGE", env.Error.Code)
+	assert.JSONEq(t, `{"max_size":1000}`, string(env.Error.Details))
+
+	code, env = postAvatar(t, api, token, "image/png", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "Avatar file is empty", env.Error.Message)
+
+	code, _ = postAvatar(t, api, token, "image/gif", make([]byte, 10))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, env = postAvatar(t, api, token, "image/png", make([]byte, 1000))
+	require.Equal(t, http.StatusOK, code)
+	var first handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &first))
+	require.NotNil(t, first.AvatarURL)
+	firstKey := strings.TrimPrefix(*first.AvatarURL, "memory://")
+	assert.True(t, store.Has(firstKey))
+
+	code, env = postAvatar(t, api, token, "image/png", make([]byte, 10))
+	require.Equal(t, http.StatusOK, code)
+	var second handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &second))
+	require.NotNil(t, second.AvatarURL)
+	assert.NotEqual(t, *first.AvatarURL, *second.AvatarURL)
+	assert.False(t, store.Has(firstKey), "replaced avatar should be deleted")
+}
+
+func TestAvatarAPI_DirectUpload(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	api := setupAvatarAPI(t, store, 1000)
+	token, _ := registerTestUser(t, api, "golfer@example.com", "Golfer")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/users/me/avatar/upload-url", token, map[string]interface{}{
+		"content_type": "image/png",
+		"size":         1001,
+	})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+	require.NotNil(t, env.Error)
+	assert.JSONEq(t, `{"max_size":1000}`, string(env.Error.Details))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/users/me/avatar/upload-url", token, map[string]interface{}{
+		"content_type": "image/png",
+		"size":         0,
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	code, env = doJSON(t, api, "POST", "/api/v1/users/me/avatar/upload-url", token, map[string]interface{}{
+		"content_type": "image/png",
+		"size":         500,
+	})
+	require.Equal(t, http.StatusOK, code)
+	var upload handler.AvatarUploadURLResponse
+	require.NoError(t, json.Unmarshal(env.Data, &upload))
+	assert.NotEmpty(t, upload.UploadURL)
+	assert.True(t, strings.HasPrefix(upload.Key, "avatars/"))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/users/me/avatar/complete", token, map[string]string{"key": upload.Key})
+	assert.Equal(t, http.StatusBadRequest, code, "nothing uploaded yet")
+
+	// The client PUTs the file to the presigned URL.
+	require.NoError(t, store.PutObject(context.Background(), upload.Key, bytes.NewReader(make([]byte, 500)), "image/png"))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/users/me/avatar/complete", otherToken, map[string]string{"key": upload.Key})
+	assert.Equal(t, http.StatusBadRequest, code, "another user's upload")
+
+	code, env = doJSON(t, api, "POST", "/api/v1/users/me/avatar/complete", token, map[string]string{"key": upload.Key})
+	require.Equal(t, http.StatusOK, code)
+	var user handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &user))
+	require.NotNil(t, user.AvatarURL)
+	assert.Equal(t, "memory://"+upload.Key, *user.AvatarURL)
+}
+
+func TestAvatarAPI_DirectUploadRejectsEmptyObject(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	api := setupAvatarAPI(t, store, 1000)
+	token, _ := registerTestUser(t, api, "golfer@example.com", "Golfer")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/users/me/avatar/upload-url", token, map[string]interface{}{
+		"content_type": "image/jpeg",
+		"size":         100,
+	})
+	require.Equal(t, http.StatusOK, code)
+	var upload handler.AvatarUploadURLResponse
+	require.NoError(t, json.Unmarshal(env.Data, &upload))
+
+	require.NoError(t, store.PutObject(context.Background(), upload.Key, bytes.NewReader(nil), "image/jpeg"))
+
+	code, env = doJSON(t, api, "POST", "/api/v1/users/me/avatar/complete", token, map[string]string{"key": upload.Key})
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "Avatar file is empty", env.Error.Message)
+	assert.False(t, store.Has(upload.Key), "rejected upload should be deleted")
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/service"
+)
+
+func TestChangeFeedAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	strangerToken, _ := registerTestUser(t, api, "stranger@example.com", "Stranger")
+	ttrID := createTestTTR(t, api, captainToken)
+
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	for _, body := range []string{"Who's driving?", "I can"} {
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages", playerToken, map[string]string{"body": body})
+		require.Equal(t, http.StatusCreated, code)
+	}
+
+	getChanges := func(t *testing.T, query string) handler.ChangeFeedResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/changes"+query, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var feed handler.ChangeFeedResponse
+		require.NoError(t, json.Unmarshal(env.Data, &feed))
+		return feed
+	}
+
+	t.Run("pages with the cursor", func(t *testing.T) {
+		feed := getChanges(t, "?limit=2")
+		require.Len(t, feed.Events, 2)
+		assert.Equal(t, models.TTREventPlayerJoined, feed.Events[0].Type)
+		assert.Equal(t, playerID, feed.Events[0].Data["user_id"])
+		require.NotNil(t, feed.Events[0].ActorUserID)
+		assert.Equal(t, playerID, *feed.Events[0].ActorUserID)
+		assert.Equal(t, models.TTREventMessagePosted, feed.Events[1].Type)
+		assert.Equal(t, int64(2), feed.Cursor)
+		assert.True(t, feed.HasMore)
+
+		feed = getChanges(t, "?since=2&limit=2")
+		require.Len(t, feed.Events, 1)
+		assert.Equal(t, int64(3), feed.Events[0].Sequence)
+		assert.Equal(t, int64(3), feed.Cursor)
+		assert.False(t, feed.HasMore)
+
+		feed = getChanges(t, "?since=3")
+		assert.Empty(t, feed.Events)
+		assert.Equal(t, int64(3), feed.Cursor, "an empty page keeps the cursor")
+	})
+
+	t.Run("pages past the default limit", func(t *testing.T) {
+		eventRepo := repository.NewTTREventRepository(db)
+		for i := 0; i < service.MaxChangeFeedLimit; i++ {
+			require.NoError(t, eventRepo.Append(context.Background(), &models.TTREvent{TTRID: uuid.MustParse(ttrID), Type: models.TTREventDetailsUpdated}))
+		}
+		total := int64(service.MaxChangeFeedLimit + 3)
+
+		feed := getChanges(t, "")
+		assert.Len(t, feed.Events, service.MaxChangeFeedLimit)
+		assert.Equal(t, int64(service.MaxChangeFeedLimit), feed.Cursor)
+		assert.True(t, feed.HasMore, "a full default page says more are waiting")
+
+		feed = getChanges(t, "?since=3&limit=200")
+		assert.Len(t, feed.Events, service.MaxChangeFeedLimit, "the maximum limit is honored")
+		assert.Equal(t, total, feed.Cursor)
+		assert.False(t, feed.HasMore)
+
+		feed = getChanges(t, "?limit=500")
+		assert.Len(t, feed.Events, service.MaxChangeFeedLimit, "larger limits fall back to the maximum")
+		assert.True(t, feed.HasMore)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/changes?since=abc", captainToken, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_CHANGE_CURSOR", env.Error.Code)
+	})
+
+	t.Run("only participants", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/changes", strangerToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
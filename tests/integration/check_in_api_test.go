@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestCheckInAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	strangerToken, _ := registerTestUser(t, api, "stranger@example.com", "Stranger")
+
+	teeAt := time.Now().UTC().Add(time.Hour).Truncate(time.Minute)
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    teeAt.Format("2006-01-02"),
+		"tee_time":    teeAt.Format("15:04"),
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("checks in once", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/check-in", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var first handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &first))
+		assert.Equal(t, playerID, first.UserID)
+		require.NotNil(t, first.CheckedInAt)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/check-in", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var again handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &again))
+		assert.Equal(t, *first.CheckedInAt, *again.CheckedInAt)
+	})
+
+	t.Run("lists check-ins", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/check-ins", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var checkIns handler.CheckInsResponse
+		require.NoError(t, json.Unmarshal(env.Data, &checkIns))
+		assert.Equal(t, teeAt.Add(-2*time.Hour).Format(time.RFC3339), checkIns.OpensAt)
+		assert.Equal(t, teeAt.Add(time.Hour).Format(time.RFC3339), checkIns.ClosesAt)
+		assert.Equal(t, 1, checkIns.CheckedIn)
+		require.Len(t, checkIns.Players, 2)
+		for _, player := range checkIns.Players {
+			assert.Equal(t, player.UserID == playerID, player.CheckedInAt != nil)
+		}
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/check-ins", strangerToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("only players", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/check-in", strangerToken, nil)
+		require.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_PLAYER", env.Error.Code)
+	})
+
+	t.Run("closed outside the window", func(t *testing.T) {
+		laterID := createTestTTR(t, api, captainToken)
+
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+laterID+"/check-in", captainToken, nil)
+		require.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "CHECK_IN_CLOSED", env.Error.Code)
+		var details map[string]string
+		require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+		assert.Contains(t, details, "opens_at")
+		assert.Contains(t, details, "closes_at")
+	})
+}
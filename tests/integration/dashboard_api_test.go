@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestDashboardAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := createTestTTR(t, api, captainToken)
+
+	code, _ := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID})
+	require.Equal(t, http.StatusCreated, code)
+
+	getDashboard := func(t *testing.T, token string) handler.DashboardResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/dashboard", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var dashboard handler.DashboardResponse
+		require.NoError(t, json.Unmarshal(env.Data, &dashboard))
+		return dashboard
+	}
+
+	t.Run("captain", func(t *testing.T) {
+		dashboard := getDashboard(t, captainToken)
+
+		assert.Empty(t, dashboard.Warnings)
+		require.NotNil(t, dashboard.NextTTR)
+		assert.Equal(t, ttrID, dashboard.NextTTR.TTR.ID)
+		require.NotNil(t, dashboard.PendingInvitations)
+		assert.Equal(t, 1, dashboard.PendingInvitations.Total)
+		assert.Equal(t, []handler.PendingInvitationCountResponse{{TTRID: ttrID, Count: 1}}, dashboard.PendingInvitations.ByTTR)
+		require.NotNil(t, dashboard.UnreadNotifications)
+		assert.Zero(t, *dashboard.UnreadNotifications)
+	})
+
+	t.Run("invitee", func(t *testing.T) {
+		dashboard := getDashboard(t, inviteeToken)
+
+		assert.Empty(t, dashboard.Warnings)
+		assert.Nil(t, dashboard.NextTTR, "an invitation is not a round yet")
+		require.NotNil(t, dashboard.PendingInvitations)
+		assert.Zero(t, dashboard.PendingInvitations.Total)
+		require.NotNil(t, dashboard.UnreadNotifications)
+		assert.Equal(t, int64(1), *dashboard.UnreadNotifications)
+		require.Len(t, dashboard.ActionItems, 1)
+		assert.Equal(t, "pending_invitation", dashboard.ActionItems[0].Kind)
+	})
+
+	t.Run("needs a token", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/dashboard", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// postSendGridEvents posts events signed with key, the way SendGrid's event
+// webhook does.
+func postSendGridEvents(t *testing.T, h http.Handler, key *ecdsa.PrivateKey, events interface{}) int {
+	t.Helper()
+
+	body, err := json.Marshal(events)
+	require.NoError(t, err)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/integrations/email/events", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(mailevents.SendGridTimestampHeader, timestamp)
+	req.Header.Set(mailevents.SendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestEmailEventsAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	suppressionService := service.NewEmailSuppressionService(repository.NewEmailSuppressionRepository(db), repository.NewUserRepository(db), repository.NewTransactor(db), zap.NewNop())
+	api := newTestAPIWithStorage(t, db, storage.NewMemoryStorage(), config.MessagingConfig{},
+		router.WithEmailEvents(handler.NewEmailEventHandler(suppressionService, &key.PublicKey, nil)))
+
+	token, _ := registerTestUser(t, api, "ann@example.com", "Ann")
+	undeliverable := func() bool {
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var user handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &user))
+		return user.EmailUndeliverable
+	}
+	softBounce := func(id string) map[string]string {
+		return map[string]string{"sg_event_id": id, "email": "Ann@example.com", "event": "bounce", "type": "blocked"}
+	}
+
+	t.Run("unsigned events are refused", func(t *testing.T) {
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		code := postSendGridEvents(t, api, otherKey, []interface{}{map[string]string{"sg_event_id": "x", "email": "ann@example.com", "event": "spamreport"}})
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.False(t, undeliverable())
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/integrations/email/events", "", []interface{}{})
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("SES is refused when not configured", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/integrations/email/events", bytes.NewReader([]byte("{}")))
+		req.Header.Set(mailevents.SNSMessageTypeHeader, mailevents.SNSTypeNotification)
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("soft bounces suppress after the limit", func(t *testing.T) {
+		code := postSendGridEvents(t, api, key, []interface{}{softBounce("s1"), softBounce("s2")})
+		require.Equal(t, http.StatusNoContent, code)
+		// SendGrid retries a batch it got no answer for.
+		code = postSendGridEvents(t, api, key, []interface{}{softBounce("s2")})
+		require.Equal(t, http.StatusNoContent, code)
+		assert.False(t, undeliverable())
+
+		code = postSendGridEvents(t, api, key, []interface{}{softBounce("s3")})
+		require.Equal(t, http.StatusNoContent, code)
+		assert.True(t, undeliverable())
+	})
+}
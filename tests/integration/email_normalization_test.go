@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/emailnorm"
+)
+
+func TestEmailNormalization_API(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	_, userID := registerTestUser(t, api, "John.Smith@Example.com", "John")
+
+	t.Run("stored as typed", func(t *testing.T) {
+		var user models.User
+		require.NoError(t, db.First(&user, "id = ?", userID).Error)
+		assert.Equal(t, "John.Smith@Example.com", user.Email)
+		assert.Equal(t, "john.smith@example.com", user.NormalizedEmail)
+	})
+
+	t.Run("login with another spelling", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{
+			"email":    "john.smith@EXAMPLE.com",
+			"password": "password123",
+		})
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("register another spelling", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/auth/register", "", map[string]string{
+			"email":      "JOHN.SMITH@example.com",
+			"password":   "password123",
+			"first_name": "Other",
+			"last_name":  "Tester",
+		})
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "user with this email already exists", env.Error.Message)
+	})
+}
+
+// useEmailNormalizer swaps emailnorm.Default for the rest of the test.
+func useEmailNormalizer(t *testing.T, n emailnorm.Normalizer) {
+	previous := emailnorm.Default
+	emailnorm.Default = n
+	t.Cleanup(func() {
+		emailnorm.Default = previous
+	})
+}
+
+func TestNormalizeEmails(t *testing.T) {
+	ctx := context.Background()
+	lowercase := emailnorm.Normalizer{LowercaseLocalPart: true}
+
+	t.Run("reports collisions without writing", func(t *testing.T) {
+		useEmailNormalizer(t, emailnorm.Normalizer{LowercaseLocalPart: false})
+		db := setupTTRTestDB(t)
+		for _, email := range []string{"John@example.com", "john@example.com", "other@example.com"} {
+			require.NoError(t, db.Create(&models.User{Email: email, PasswordHash: "hash", FirstName: "Test", LastName: "User"}).Error)
+		}
+
+		collisions, err := database.NormalizeEmails(ctx, db, lowercase)
+		require.NoError(t, err)
+		assert.Equal(t, []emailnorm.Collision{
+			{Normalized: "john@example.com", Emails: []string{"John@example.com", "john@example.com"}},
+		}, collisions)
+
+		var normalized []string
+		require.NoError(t, db.Model(&models.User{}).Order("normalized_email").Pluck("normalized_email", &normalized).Error)
+		assert.Equal(t, []string{"John@example.com", "john@example.com", "other@example.com"}, normalized)
+	})
+
+	t.Run("renormalizes users and invitations", func(t *testing.T) {
+		useEmailNormalizer(t, emailnorm.Normalizer{LowercaseLocalPart: false})
+		db := setupTTRTestDB(t)
+		user := models.User{Email: "Jane@Example.com", PasswordHash: "hash", FirstName: "Jane", LastName: "User"}
+		require.NoError(t, db.Create(&user).Error)
+		require.NoError(t, db.Delete(&user).Error)
+		invitation := models.OrganizationInvitation{Email: "Jane@example.com", Role: models.OrganizationRoleMember}
+		require.NoError(t, db.Create(&invitation).Error)
+
+		collisions, err := database.NormalizeEmails(ctx, db, lowercase)
+		require.NoError(t, err)
+		assert.Empty(t, collisions)
+
+		require.NoError(t, db.Unscoped().First(&user, "id = ?", user.ID).Error)
+		assert.Equal(t, "Jane@Example.com", user.Email)
+		assert.Equal(t, "jane@example.com", user.NormalizedEmail, "soft-deleted users are normalized too")
+		require.NoError(t, db.First(&invitation, "id = ?", invitation.ID).Error)
+		assert.Equal(t, "jane@example.com", invitation.Email)
+	})
+}
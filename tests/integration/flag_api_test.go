@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+)
+
+func TestFlagAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	userToken, userID := registerTestUser(t, api, "golfer@example.com", "Golfer")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	myFlags := func(t *testing.T) map[string]bool {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/meta/flags", userToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var flags map[string]bool
+		require.NoError(t, json.Unmarshal(env.Data, &flags))
+		return flags
+	}
+
+	t.Run("config rollouts apply", func(t *testing.T) {
+		assert.Equal(t, map[string]bool{"messaging": true}, myFlags(t))
+	})
+
+	t.Run("flags need a token", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/meta/flags", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("only admins manage flags", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/admin/flags/waitlist", userToken, map[string]interface{}{"enabled": true, "percentage": 100})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("admins flip flags at runtime", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/admin/flags/waitlist", adminToken, map[string]interface{}{
+			"description": "Join full tee times",
+			"enabled":     true,
+			"percentage":  0,
+			"user_ids":    []string{userID},
+		})
+		require.Equal(t, http.StatusOK, code)
+		var flag handler.FeatureFlagResponse
+		require.NoError(t, json.Unmarshal(env.Data, &flag))
+		assert.Equal(t, []string{userID}, flag.UserIDs)
+		assert.True(t, myFlags(t)["waitlist"])
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/flags/waitlist", adminToken, map[string]interface{}{
+			"enabled":  false,
+			"user_ids": []string{userID},
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.False(t, myFlags(t)["waitlist"])
+
+		code, env = doJSON(t, api, "GET", "/api/v1/admin/flags", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var flags []handler.FeatureFlagResponse
+		require.NoError(t, json.Unmarshal(env.Data, &flags))
+		require.Len(t, flags, 1)
+		assert.Equal(t, "waitlist", flags[0].Key)
+		assert.Equal(t, "", flags[0].Description)
+	})
+
+	t.Run("invalid flags are rejected", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/admin/flags/Wait-List", adminToken, map[string]interface{}{"enabled": true})
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_FEATURE_FLAG", env.Error.Code)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/flags/waitlist", adminToken, map[string]interface{}{"percentage": 150})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("deleting falls back to config", func(t *testing.T) {
+		code, _ := doJSON(t, api, "DELETE", "/api/v1/admin/flags/waitlist", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		_, ok := myFlags(t)["waitlist"]
+		assert.False(t, ok)
+
+		code, env := doJSON(t, api, "DELETE", "/api/v1/admin/flags/waitlist", adminToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "FEATURE_FLAG_NOT_FOUND", env.Error.Code)
+	})
+}
@@ -0,0 +1,276 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"gorm.io/gorm"
+)
+
+type graphqlEnvelope struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string        `json:"message"`
+		Path       []interface{} `json:"path"`
+		Extensions struct {
+			Code string `json:"code"`
+		} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func doGraphQL(t *testing.T, h http.Handler, token, query string, variables map[string]interface{}) (int, graphqlEnvelope) {
+	t.Helper()
+
+	var body bytes.Buffer
+	require.NoError(t, json.NewEncoder(&body).Encode(map[string]interface{}{"query": query, "variables": variables}))
+	req := httptest.NewRequest("POST", "/api/v1/graphql", &body)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var env graphqlEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env), "body: %s", w.Body.String())
+	return w.Code, env
+}
+
+func inviteTestUser(t *testing.T, h http.Handler, token, ttrID, inviteeID string) string {
+	t.Helper()
+
+	code, env := doJSON(t, h, "POST", "/api/v1/invitations", token, map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	return invitation.ID
+}
+
+func TestGraphQLAPI_Queries(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	invitationID := inviteTestUser(t, api, captainToken, ttrID, inviteeID)
+
+	t.Run("the captain gets the TTR and its invitations in one request", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `query Round($id: ID!) {
+			me { id firstName }
+			round: ttr(id: $id) {
+				id
+				participant
+				courseName
+				captain { id }
+				invitations { id status invitee { id firstName } }
+			}
+		}`, map[string]interface{}{"id": ttrID})
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, env.Errors)
+
+		var data struct {
+			Me struct {
+				ID        string `json:"id"`
+				FirstName string `json:"firstName"`
+			} `json:"me"`
+			Round struct {
+				ID          string `json:"id"`
+				Participant bool   `json:"participant"`
+				CourseName  string `json:"courseName"`
+				Captain     struct {
+					ID string `json:"id"`
+				} `json:"captain"`
+				Invitations []struct {
+					ID      string `json:"id"`
+					Status  string `json:"status"`
+					Invitee struct {
+						ID string `json:"id"`
+					} `json:"invitee"`
+				} `json:"invitations"`
+			} `json:"round"`
+		}
+		require.NoError(t, json.Unmarshal(env.Data, &data))
+		assert.Equal(t, captainID, data.Me.ID)
+		assert.Equal(t, "Captain", data.Me.FirstName)
+		assert.Equal(t, ttrID, data.Round.ID)
+		assert.True(t, data.Round.Participant)
+		assert.Equal(t, "Pebble Beach", data.Round.CourseName)
+		assert.Equal(t, captainID, data.Round.Captain.ID)
+		require.Len(t, data.Round.Invitations, 1)
+		assert.Equal(t, invitationID, data.Round.Invitations[0].ID)
+		assert.Equal(t, "PENDING", data.Round.Invitations[0].Status)
+		assert.Equal(t, inviteeID, data.Round.Invitations[0].Invitee.ID)
+	})
+
+	t.Run("fields come back in the order they were asked for", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `{ me { lastName id } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.JSONEq(t, fmt.Sprintf(`{"me": {"lastName": "Tester", "id": %q}}`, captainID), string(env.Data))
+		assert.True(t, strings.Index(string(env.Data), "lastName") < strings.Index(string(env.Data), `"id"`))
+	})
+
+	t.Run("an invitee sees the TTR they're invited to but not its invitations", func(t *testing.T) {
+		code, env := doGraphQL(t, api, inviteeToken, `{
+			myInvitations { id ttr { id participant minPlayers invitations { id } } }
+		}`, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, env.Errors)
+		assert.JSONEq(t, fmt.Sprintf(`{"myInvitations": [{"id": %q, "ttr": {"id": %q, "participant": true, "minPlayers": 2, "invitations": null}}]}`, invitationID, ttrID), string(env.Data))
+	})
+
+	t.Run("an outsider only sees a public TTR's public fields", func(t *testing.T) {
+		code, env := doGraphQL(t, api, outsiderToken, `query($id: ID!) {
+			ttr(id: $id) { id participant courseName openSlots notes captain { id } }
+		}`, map[string]interface{}{"id": ttrID})
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, env.Errors)
+		assert.JSONEq(t, fmt.Sprintf(`{"ttr": {"id": %q, "participant": false, "courseName": "Pebble Beach", "openSlots": 3, "notes": null, "captain": null}}`, ttrID), string(env.Data))
+	})
+
+	t.Run("an invalid ID fails only its field", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `{ me { id } ttr(id: "not-a-uuid") { id } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "BAD_REQUEST", env.Errors[0].Extensions.Code)
+		assert.Equal(t, []interface{}{"ttr"}, env.Errors[0].Path)
+		assert.JSONEq(t, fmt.Sprintf(`{"me": {"id": %q}, "ttr": null}`, captainID), string(env.Data))
+	})
+
+	t.Run("an unknown field is refused", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `{ me { id password } }`, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		assert.Empty(t, env.Data)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", env.Errors[0].Extensions.Code)
+	})
+
+	t.Run("a query that doesn't parse is refused", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `{ me { id `, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "GRAPHQL_PARSE_FAILED", env.Errors[0].Extensions.Code)
+	})
+
+	t.Run("mutations are refused", func(t *testing.T) {
+		code, env := doGraphQL(t, api, captainToken, `mutation { me { id } }`, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", env.Errors[0].Extensions.Code)
+	})
+
+	t.Run("a token without the profile scope can't read me", func(t *testing.T) {
+		code, tokenEnv := doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", captainToken, map[string]interface{}{
+			"name":   "mobile",
+			"scopes": []string{"read:ttrs"},
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var created handler.APITokenResponse
+		require.NoError(t, json.Unmarshal(tokenEnv.Data, &created))
+
+		code, env := doGraphQL(t, api, created.Token, `{ me { id } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "INSUFFICIENT_SCOPE", env.Errors[0].Extensions.Code)
+		assert.JSONEq(t, `{"me": null}`, string(env.Data))
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/graphql", "", map[string]string{"query": "{ me { id } }"})
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func TestGraphQLAPI_Limits(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	token, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+
+	t.Run("too deep", func(t *testing.T) {
+		// Nine levels, one more than the test API allows.
+		query := `{ myInvitations { ttr { invitations { ttr { invitations { ttr { invitations { ttr { id } } } } } } } } }`
+		code, env := doGraphQL(t, api, token, query, nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", env.Errors[0].Extensions.Code)
+	})
+
+	t.Run("too complex", func(t *testing.T) {
+		var query strings.Builder
+		query.WriteString("{")
+		for i := 0; i < 101; i++ {
+			fmt.Fprintf(&query, " me%d: me { id }", i)
+		}
+		query.WriteString(" }")
+
+		code, env := doGraphQL(t, api, token, query.String(), nil)
+		require.Equal(t, http.StatusBadRequest, code)
+		require.Len(t, env.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_COMPLEX", env.Errors[0].Extensions.Code)
+	})
+
+	t.Run("within the limits", func(t *testing.T) {
+		code, env := doGraphQL(t, api, token, `{ myInvitations { ttr { invitations { ttr { id } } } } }`, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, env.Errors)
+	})
+}
+
+// countQueries counts the SQL queries db runs from now on.
+func countQueries(t *testing.T, db *gorm.DB) *int64 {
+	var count int64
+	increment := func(*gorm.DB) { atomic.AddInt64(&count, 1) }
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:count_queries", increment))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count_rows", increment))
+	return &count
+}
+
+func TestGraphQLAPI_Batching(t *testing.T) {
+	// queries runs the query for a captain with n TTRs, each with an
+	// invitation for the same invitee, and returns how many SQL queries it
+	// took.
+	queries := func(t *testing.T, n int, asInvitee bool, query string) int64 {
+		db := setupTTRTestDB(t)
+		api := newTestAPI(t, db)
+
+		captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+		inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+		for i := 0; i < n; i++ {
+			inviteTestUser(t, api, captainToken, createTestTTR(t, api, captainToken), inviteeID)
+		}
+
+		token := captainToken
+		if asInvitee {
+			token = inviteeToken
+		}
+		count := countQueries(t, db)
+		code, env := doGraphQL(t, api, token, query, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.Empty(t, env.Errors)
+		assert.Equal(t, n, strings.Count(string(env.Data), `"courseName"`), "data: %s", env.Data)
+		return atomic.LoadInt64(count)
+	}
+
+	t.Run("a captain's TTRs and their invitations", func(t *testing.T) {
+		query := `{ myTTRs(as: CAPTAIN) { id courseName invitations { id invitee { id } } } }`
+		assert.Equal(t, queries(t, 1, false, query), queries(t, 5, false, query))
+	})
+
+	t.Run("an invitee's invitations and their TTRs", func(t *testing.T) {
+		query := `{ myInvitations { id ttr { id courseName openSlots invitations { id } } } }`
+		assert.Equal(t, queries(t, 1, true, query), queries(t, 5, true, query))
+	})
+}
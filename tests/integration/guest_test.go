@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestGuestAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	linkToken, _ := registerTestUser(t, api, "link@example.com", "Link")
+	joinerToken, joinerID := registerTestUser(t, api, "joiner@example.com", "Joiner")
+	_, lateID := registerTestUser(t, api, "late@example.com", "Late")
+	ttrID := createTestTTR(t, api, captainToken)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	link := createTestInviteLink(t, api, captainToken, ttrID, map[string]interface{}{"max_uses": 1})
+
+	addGuest := func(body map[string]interface{}) (int, apiEnvelope) {
+		t.Helper()
+		return doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/guests", captainToken, body)
+	}
+	roster := func() []handler.TTRPlayerResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		return players
+	}
+
+	var guest handler.TTRPlayerResponse
+	t.Run("roster shape", func(t *testing.T) {
+		code, env := addGuest(map[string]interface{}{"display_name": "Uncle Joe", "handicap": 12.5})
+		require.Equal(t, http.StatusCreated, code)
+		require.NoError(t, json.Unmarshal(env.Data, &guest))
+		assert.True(t, guest.IsGuest)
+		assert.NotEmpty(t, guest.GuestID)
+		assert.Equal(t, "Uncle Joe", guest.DisplayName)
+
+		players := roster()
+		require.Len(t, players, 2)
+		assert.False(t, players[0].IsGuest)
+		assert.NotEmpty(t, players[0].UserID)
+		assert.Equal(t, guest.GuestID, players[1].GuestID)
+		assert.True(t, players[1].IsGuest)
+		assert.Equal(t, models.TTRPlayerStatusConfirmed, players[1].Status)
+		require.NotNil(t, players[1].Handicap)
+		assert.Equal(t, 12.5, *players[1].Handicap)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var raw []map[string]interface{}
+		require.NoError(t, json.Unmarshal(env.Data, &raw))
+		assert.NotContains(t, raw[1], "user_id")
+		assert.NotContains(t, raw[1], "user")
+		assert.Equal(t, false, raw[0]["is_guest"])
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		require.Len(t, ttr.Players, 2)
+		assert.Equal(t, "Uncle Joe", ttr.Players[1].DisplayName)
+	})
+
+	t.Run("only managers add guests", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/guests", joinerToken, map[string]interface{}{"display_name": "Crasher"})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+
+		code, _ = addGuest(map[string]interface{}{"display_name": ""})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		code, _ = addGuest(map[string]interface{}{"display_name": "Sandbagger", "handicap": 60})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+	})
+
+	t.Run("guests fill the TTR", func(t *testing.T) {
+		for _, name := range []string{"Cousin Ann", "Cousin Bea"} {
+			code, _ := addGuest(map[string]interface{}{"display_name": name})
+			require.Equal(t, http.StatusCreated, code)
+		}
+
+		code, env := addGuest(map[string]interface{}{"display_name": "One Too Many"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", joinerToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", inviteeToken, map[string]string{"status": string(models.InvitationStatusYes)})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/invite-links/"+link.Token+"/accept", linkToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttrID, "invitee_user_id": lateID})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/public/invite-links/"+link.Token, "", nil)
+		require.Equal(t, http.StatusOK, code)
+		var preview handler.InviteLinkPreviewResponse
+		require.NoError(t, json.Unmarshal(env.Data, &preview))
+		assert.Equal(t, 0, preview.OpenSlots)
+
+		assert.Len(t, roster(), 4)
+	})
+
+	t.Run("removing a guest frees the slot", func(t *testing.T) {
+		code, _ := doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID+"/guests/"+guest.GuestID, joinerToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID+"/guests/"+guest.GuestID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID+"/guests/"+guest.GuestID, captainToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "GUEST_NOT_FOUND", env.Error.Code)
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", joinerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		players := roster()
+		require.Len(t, players, 4)
+		playerUser(t, players, joinerID)
+		for _, player := range players {
+			assert.NotEqual(t, "Uncle Joe", player.DisplayName)
+		}
+	})
+}
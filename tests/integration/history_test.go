@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// seedRound stores a TTR captained by captainID teeing off on date with the
+// given roster, bypassing the API, which only takes TTRs in the future.
+func seedRound(t *testing.T, db *gorm.DB, captainID string, date string, course string, status models.TTRStatus, roster map[string]models.TTRPlayerStatus) string {
+	t.Helper()
+	teeDate, err := time.Parse("2006-01-02", date)
+	require.NoError(t, err)
+
+	ttr := &models.TTR{
+		CourseName:      course,
+		TeeDate:         teeDate,
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		MaxPlayers:      8,
+		CreatedByUserID: uuid.MustParse(captainID),
+		CaptainUserID:   uuid.MustParse(captainID),
+		Status:          status,
+		Visibility:      models.TTRVisibilityPublic,
+	}
+	require.NoError(t, db.Create(ttr).Error)
+	for id, playerStatus := range roster {
+		require.NoError(t, db.Create(&models.TTRPlayer{
+			TTRID:    ttr.ID,
+			UserID:   uuid.MustParse(id),
+			Status:   playerStatus,
+			JoinedAt: teeDate,
+		}).Error)
+	}
+	return ttr.ID.String()
+}
+
+func TestHistoryAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	token, me := registerTestUser(t, api, "me@example.com", "Me")
+	ids := map[string]string{}
+	for _, name := range []string{"Ada", "Bea", "Cal", "Dee", "Eve", "Fay", "Gus", "Zed"} {
+		_, id := registerTestUser(t, api, name+"@example.com", name)
+		ids[name] = id
+	}
+	confirmed := models.TTRPlayerStatusConfirmed
+	roster := func(names ...string) map[string]models.TTRPlayerStatus {
+		players := map[string]models.TTRPlayerStatus{me: confirmed}
+		for _, name := range names {
+			players[ids[name]] = confirmed
+		}
+		return players
+	}
+
+	// 2023 ends and 2024 starts with a round on either side of midnight.
+	seedRound(t, db, me, "2023-06-10", "Old Course", models.TTRStatusCompleted, roster("Gus"))
+	lastOf2023 := seedRound(t, db, me, "2023-12-31", "Pebble Beach", models.TTRStatusCompleted, roster("Ada", "Zed"))
+	firstOf2024 := seedRound(t, db, me, "2024-01-01", "Pebble Beach", models.TTRStatusCompleted, roster("Ada", "Bea", "Cal"))
+	seedRound(t, db, me, "2024-01-20", "pebble beach", models.TTRStatusCompleted, roster("Ada", "Bea", "Zed"))
+	seedRound(t, db, me, "2024-03-05", "Augusta", models.TTRStatusCompleted, roster("Ada", "Cal", "Dee", "Eve", "Fay"))
+	lastOf2024 := seedRound(t, db, me, "2024-12-31", "St Andrews", models.TTRStatusCompleted, roster("Zed", "Dee"))
+	// None of these count: a cancelled round, a deleted one and one the
+	// caller was only a maybe for.
+	seedRound(t, db, me, "2024-02-01", "Augusta", models.TTRStatusCancelled, roster("Gus"))
+	deleted := seedRound(t, db, me, "2024-02-02", "Augusta", models.TTRStatusCompleted, roster("Gus"))
+	require.NoError(t, db.Delete(&models.TTR{}, "id = ?", deleted).Error)
+	seedRound(t, db, me, "2024-02-03", "Augusta", models.TTRStatusCompleted, map[string]models.TTRPlayerStatus{ids["Gus"]: confirmed, me: models.TTRPlayerStatusMaybe})
+	open := seedRound(t, db, me, "2024-05-01", "Torrey Pines", models.TTRStatusOpen, roster("Gus"))
+
+	getHistory := func(query string) handler.HistoryResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me/history"+query, token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var history handler.HistoryResponse
+		require.NoError(t, json.Unmarshal(env.Data, &history))
+		return history
+	}
+	monthRounds := func(history handler.HistoryResponse) map[int][]string {
+		months := map[int][]string{}
+		for _, month := range history.Months {
+			assert.Len(t, month.Rounds, month.RoundCount)
+			for _, round := range month.Rounds {
+				months[month.Month] = append(months[month.Month], round.TTRID)
+			}
+		}
+		return months
+	}
+
+	t.Run("grouping stops at the year's edges", func(t *testing.T) {
+		history := getHistory("?year=2023")
+		assert.Equal(t, 2023, history.Year)
+		months := monthRounds(history)
+		assert.Equal(t, []string{lastOf2023}, months[12])
+		assert.Len(t, months, 2)
+		assert.Equal(t, 2, history.Summary.RoundsPlayed)
+
+		history = getHistory("?year=2024")
+		require.Len(t, history.Months, 3, "months without rounds are left out")
+		assert.Equal(t, []int{1, 3, 12}, []int{history.Months[0].Month, history.Months[1].Month, history.Months[2].Month})
+		assert.Equal(t, 2, history.Months[0].RoundCount)
+		assert.Equal(t, firstOf2024, history.Months[0].Rounds[0].TTRID)
+		assert.Equal(t, "2024-01-01", history.Months[0].Rounds[0].TeeDate)
+		assert.Equal(t, 4, history.Months[0].Rounds[0].Players)
+		assert.Equal(t, []string{lastOf2024}, monthRounds(history)[12])
+
+		assert.Equal(t, 4, history.Summary.RoundsPlayed)
+		assert.Equal(t, 3, history.Summary.DistinctCourses, "course names are matched regardless of case")
+	})
+
+	t.Run("partners are ranked by shared rounds", func(t *testing.T) {
+		partners := getHistory("?year=2024").Summary.TopPartners
+		names := make([]string, 0, len(partners))
+		rounds := make([]int, 0, len(partners))
+		for _, partner := range partners {
+			names = append(names, partner.User.FirstName)
+			rounds = append(rounds, partner.Rounds)
+		}
+		// Ties go by name; Eve and Fay, with a round each, miss the cut.
+		assert.Equal(t, []string{"Ada", "Bea", "Cal", "Dee", "Zed"}, names)
+		assert.Equal(t, []int{3, 2, 2, 2, 2}, rounds)
+
+		require.NoError(t, db.Delete(&models.User{}, "id = ?", ids["Bea"]).Error)
+	})
+
+	t.Run("completing a TTR refreshes the cached history", func(t *testing.T) {
+		// Bea's deletion above isn't seen until the cache entry is dropped.
+		before := getHistory("?year=2024")
+		assert.Equal(t, "Bea", before.Summary.TopPartners[1].User.FirstName)
+
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+open, token, map[string]string{"status": string(models.TTRStatusCompleted)})
+		require.Equal(t, http.StatusOK, code)
+
+		after := getHistory("?year=2024")
+		assert.Equal(t, 5, after.Summary.RoundsPlayed)
+		assert.Equal(t, []string{open}, monthRounds(after)[5])
+		assert.Equal(t, "Cal", after.Summary.TopPartners[1].User.FirstName, "deleted users aren't ranked")
+	})
+
+	t.Run("year", func(t *testing.T) {
+		history := getHistory("")
+		assert.Equal(t, time.Now().UTC().Year(), history.Year)
+		assert.Empty(t, history.Months)
+		assert.Empty(t, history.Summary.TopPartners)
+
+		for _, year := range []string{"abc", "1899", "99999"} {
+			code, _ := doJSON(t, api, "GET", "/api/v1/users/me/history?year="+year, token, nil)
+			assert.Equal(t, http.StatusBadRequest, code, year)
+		}
+	})
+}
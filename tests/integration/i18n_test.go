@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+	"go.uber.org/zap"
+)
+
+func setupI18nAPI(t *testing.T) http.Handler {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	userRepo := repository.NewUserRepository(db)
+	authService := service.NewAuthService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+
+	return router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithUsers(handler.NewUserHandler(service.NewUserService(userRepo, nil, nil, password.Policy{}, config.AvatarConfig{}))),
+	).SetupRoutes()
+}
+
+func doLocalized(t *testing.T, h http.Handler, method, path, token, acceptLanguage string, body interface{}) (*httptest.ResponseRecorder, apiEnvelope) {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var env apiEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env), "body: %s", w.Body.String())
+	return w, env
+}
+
+func TestI18n_AcceptLanguageSelectsResponseLanguage(t *testing.T) {
+	api := setupI18nAPI(t)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		wantLanguage   string
+		wantMessage    string
+	}{
+		{"no header", "", "en", "Authorization header required"},
+		{"spanish", "es-ES,es;q=0.9,en;q=0.8", "es", "Se requiere la cabecera de autorización"},
+		{"unsupported falls back to english", "fr-FR", "en", "Authorization header required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, env := doLocalized(t, api, "GET", "/api/v1/users/me", "", tt.acceptLanguage, nil)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, tt.wantLanguage, w.Header().Get("Content-Language"))
+			require.NotNil(t, env.Error)
+			assert.Equal(t, tt.wantMessage, env.Error.Message)
+			assert.Equal(t, "error.authorization_header_required", env.Error.MessageKey)
+		})
+	}
+}
+
+func TestI18n_ValidationDetailsAreLocalized(t *testing.T) {
+	api := setupI18nAPI(t)
+
+	w, env := doLocalized(t, api, "POST", "/api/v1/auth/register", "", "es", map[string]string{
+		"email":      "not-an-email",
+		"password":   "password123",
+		"first_name": "Ana",
+		"last_name":  "Tester",
+	})
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "La validación ha fallado", env.Error.Message)
+	assert.Equal(t, "error.validation_failed", env.Error.MessageKey)
+
+	var details []validator.FieldError
+	require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+	assert.Equal(t, []validator.FieldError{
+		{Field: "email", Rule: "email", Message: "debe ser un correo electrónico válido"},
+	}, details)
+}
+
+func TestI18n_HandlerMessagesAreLocalized(t *testing.T) {
+	api := setupI18nAPI(t)
+	token, _ := registerTestUser(t, api, "i18n@example.com", "Ana")
+
+	w, env := doLocalized(t, api, "GET", "/api/v1/users/not-a-uuid", token, "es", nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "ID de usuario no válido", env.Error.Message)
+	assert.Equal(t, "error.invalid_user_id", env.Error.MessageKey)
+}
+
+func TestI18n_PreferredLanguageOnProfile(t *testing.T) {
+	api := setupI18nAPI(t)
+	token, _ := registerTestUser(t, api, "profile-lang@example.com", "Ana")
+
+	w, env := doLocalized(t, api, "PUT", "/api/v1/users/me", token, "", map[string]string{
+		"preferred_language": "es-MX",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var user handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &user))
+	require.NotNil(t, user.PreferredLanguage)
+	assert.Equal(t, "es", *user.PreferredLanguage)
+
+	w, env = doLocalized(t, api, "PUT", "/api/v1/users/me", token, "", map[string]string{
+		"preferred_language": "xx",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "error.unsupported_language", env.Error.MessageKey)
+}
@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+)
+
+func TestImpersonationAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	golferToken, golferID := registerTestUser(t, api, "golfer@example.com", "Golfer")
+	_, adminID := registerTestUser(t, api, "support@example.com", "Support")
+	require.NoError(t, db.Model(&models.User{}).Where("id = ?", adminID).Update("role", models.UserRoleAdmin).Error)
+	adminToken, err := jwt.GenerateAccessToken(uuid.MustParse(adminID), "support@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	newTTR := map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+	}
+
+	impersonate := func(t *testing.T, allowWrites bool) handler.ImpersonationResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/impersonate/"+golferID, adminToken, map[string]interface{}{
+			"reason":       "support ticket 42",
+			"allow_writes": allowWrites,
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var started handler.ImpersonationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &started))
+		return started
+	}
+
+	t.Run("only admins can impersonate", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/admin/impersonate/"+adminID, golferToken, map[string]interface{}{"reason": "curious"})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("admins cannot impersonate themselves", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/admin/impersonate/"+adminID, adminToken, map[string]interface{}{"reason": "testing"})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "CANNOT_IMPERSONATE", env.Error.Code)
+	})
+
+	readOnly := impersonate(t, false)
+
+	t.Run("the token acts as the user", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", readOnly.AccessToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var me struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(env.Data, &me))
+		assert.Equal(t, golferID, me.ID)
+	})
+
+	t.Run("a read-only session cannot change anything", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", readOnly.AccessToken, newTTR)
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "IMPERSONATION_READ_ONLY", env.Error.Code)
+	})
+
+	t.Run("admin routes stay out of reach", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/admin/audit-log", readOnly.AccessToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("stopping invalidates the token", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/impersonation/stop", readOnly.AccessToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/users/me", readOnly.AccessToken, nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("stopping needs an impersonation token", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/impersonation/stop", golferToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "NOT_IMPERSONATING", env.Error.Code)
+	})
+
+	t.Run("allow_writes permits changes", func(t *testing.T) {
+		writable := impersonate(t, true)
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs", writable.AccessToken, newTTR)
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	t.Run("account security stays out of reach even with allow_writes", func(t *testing.T) {
+		writable := impersonate(t, true)
+
+		code, env := doJSON(t, api, "PUT", "/api/v1/users/me/password", writable.AccessToken, map[string]string{
+			"old_password": "password123",
+			"new_password": "taken-over-by-support",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "IMPERSONATION_DENIED", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/users/me/api-tokens", writable.AccessToken, map[string]interface{}{
+			"name":   "support",
+			"scopes": []string{"read:profile"},
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "IMPERSONATION_DENIED", env.Error.Code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/users/me/api-tokens", writable.AccessToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/users/me/api-tokens", golferToken, nil)
+		assert.Equal(t, http.StatusOK, code, "the user still manages their own tokens")
+	})
+
+	t.Run("the audit log records the admin", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/admin/audit-log?user_id="+golferID+"&limit=50", adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var entries []handler.AuditLogEntryResponse
+		require.NoError(t, json.Unmarshal(env.Data, &entries))
+
+		var actions []string
+		details := map[string]bool{}
+		for _, entry := range entries {
+			assert.Equal(t, adminID, entry.ActorUserID)
+			actions = append(actions, entry.Action)
+			details[entry.Details] = true
+		}
+		assert.Contains(t, actions, models.AuditActionImpersonationStart)
+		assert.Contains(t, actions, models.AuditActionImpersonationStop)
+		assert.Contains(t, actions, models.AuditActionImpersonatedRequest)
+		assert.True(t, details["GET /api/v1/users/me 200"])
+		assert.True(t, details["POST /api/v1/ttrs 403"], "blocked requests are audited too")
+		assert.True(t, details["POST /api/v1/ttrs 201"])
+	})
+}
@@ -0,0 +1,451 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+// racingInvitationRepository holds every FindByTTRAndInvitee until all
+// invites have made theirs, so each one gets past InvitationService's
+// pending-invitation check.
+type racingInvitationRepository struct {
+	repository.InvitationRepository
+	checked sync.WaitGroup
+}
+
+func (r *racingInvitationRepository) FindByTTRAndInvitee(ctx context.Context, ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
+	invitation, err := r.InvitationRepository.FindByTTRAndInvitee(ctx, ttrID, inviteeUserID)
+	r.checked.Done()
+	r.checked.Wait()
+	return invitation, err
+}
+
+func TestInvitationService_ConcurrentInvites(t *testing.T) {
+	db := setupTTRTestDB(t)
+	// Every connection to ":memory:" opens a separate, empty database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	api := newTestAPI(t, db)
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	_, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := uuid.MustParse(createTestTTR(t, api, captainToken))
+
+	const invites = 5
+	invitationRepo := &racingInvitationRepository{InvitationRepository: repository.NewInvitationRepository(db)}
+	invitationRepo.checked.Add(invites)
+	ttrRepo := repository.NewTTRRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	authorizer := service.NewAuthorizer(ttrRepo, repository.NewOrganizationRepository(db), repository.NewInvitationRepository(db), userRepo)
+	transactor := repository.NewTransactor(db)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, zap.NewNop())
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, transactor, authorizer, notificationService, nil, nil, nil, nil, time.Hour, 0, zap.NewNop())
+	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, repository.NewInvitationTemplateRepository(db), ttrService, transactor, authorizer, notificationService, nil, nil, zap.NewNop())
+
+	errs := make(chan error, invites)
+	var wg sync.WaitGroup
+	for i := 0; i < invites; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := invitationService.CreateInvitation(context.Background(), ttrID, uuid.MustParse(captainID), uuid.MustParse(inviteeID), nil, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var created, duplicates int
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.EqualError(t, err, "pending invitation already exists for this user")
+		duplicates++
+	}
+	assert.Equal(t, 1, created)
+	assert.Equal(t, invites-1, duplicates)
+
+	var pending int64
+	require.NoError(t, db.Model(&models.Invitation{}).
+		Where("ttr_id = ? AND invitee_user_id = ? AND status = ?", ttrID, inviteeID, models.InvitationStatusPending).
+		Count(&pending).Error)
+	assert.Equal(t, int64(1), pending)
+}
+
+// racingTTRRepository lets someone else take a slot right after the roster
+// is read, once armed, the way a concurrent accept would.
+type racingTTRRepository struct {
+	repository.TTRRepository
+	latecomer *uuid.UUID
+}
+
+func (r *racingTTRRepository) GetPlayers(ctx context.Context, ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
+	players, err := r.TTRRepository.GetPlayers(ctx, ttrID)
+	if err == nil && r.latecomer != nil {
+		err = r.TTRRepository.AddPlayer(ctx, ttrID, *r.latecomer, models.TTRPlayerStatusConfirmed)
+		r.latecomer = nil
+	}
+	return players, err
+}
+
+func TestInvitationService_AcceptRacesForLastSlot(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	ctx := context.Background()
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	_, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := uuid.MustParse(createTestTTR(t, api, captainToken))
+
+	ttrRepo := &racingTTRRepository{TTRRepository: repository.NewTTRRepository(db)}
+	for _, name := range []string{"Second", "Third"} {
+		_, userID := registerTestUser(t, api, strings.ToLower(name)+"@example.com", name)
+		require.NoError(t, ttrRepo.AddPlayer(ctx, ttrID, uuid.MustParse(userID), models.TTRPlayerStatusConfirmed))
+	}
+	_, latecomerID := registerTestUser(t, api, "latecomer@example.com", "Latecomer")
+
+	invitationRepo := repository.NewInvitationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	authorizer := service.NewAuthorizer(ttrRepo, repository.NewOrganizationRepository(db), invitationRepo, userRepo)
+	transactor := repository.NewTransactor(db)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, zap.NewNop())
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, transactor, authorizer, notificationService, nil, nil, nil, nil, time.Hour, 0, zap.NewNop())
+	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, repository.NewInvitationTemplateRepository(db), ttrService, transactor, authorizer, notificationService, nil, nil, zap.NewNop())
+
+	invitation, err := invitationService.CreateInvitation(ctx, ttrID, uuid.MustParse(captainID), uuid.MustParse(inviteeID), nil, nil)
+	require.NoError(t, err)
+
+	// The roster read by the accept still has the last slot open, but the
+	// latecomer takes it before the invitee is added.
+	latecomer := uuid.MustParse(latecomerID)
+	ttrRepo.latecomer = &latecomer
+	_, err = invitationService.RespondToInvitation(ctx, invitation.ID, uuid.MustParse(inviteeID), models.InvitationStatusYes, nil)
+	assert.ErrorIs(t, err, service.ErrTTRFullForInvitation)
+
+	isPlayer, err := ttrRepo.IsPlayer(ctx, ttrID, uuid.MustParse(inviteeID))
+	require.NoError(t, err)
+	assert.False(t, isPlayer, "the invitee doesn't take a fifth slot")
+}
+
+func TestInvitationAPI_ReinviteAfterDecline(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := createTestTTR(t, api, captainToken)
+	invite := map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID}
+
+	code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, invite)
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, invite)
+	assert.Equal(t, http.StatusConflict, code, "a second pending invitation is refused")
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", inviteeToken, map[string]string{"status": string(models.InvitationStatusNo)})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, invite)
+	assert.Equal(t, http.StatusCreated, code, "a declined user can be invited again")
+}
+
+func TestInvitationAPI_CancelResolvesNotification(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	ctx := context.Background()
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	_, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := createTestTTR(t, api, captainToken)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+		"ttr_id":          ttrID,
+		"invitee_user_id": inviteeID,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+
+	unread, err := notificationRepo.FindUnreadByUserID(ctx, uuid.MustParse(inviteeID))
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, models.NotificationTypeInvitation, unread[0].Type)
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/invitations/"+invitation.ID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	notifications, err := notificationRepo.FindByTarget(ctx, "invitation", uuid.MustParse(invitation.ID))
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	byType := make(map[string]*models.Notification)
+	for _, notification := range notifications {
+		byType[notification.Type] = notification
+	}
+	require.Contains(t, byType, models.NotificationTypeInvitation)
+	assert.True(t, byType[models.NotificationTypeInvitation].IsRead, "the original invitation notification is resolved")
+	require.Contains(t, byType, models.NotificationTypeInvitationWithdrawn)
+	assert.False(t, byType[models.NotificationTypeInvitationWithdrawn].IsRead)
+	assert.Equal(t, uuid.MustParse(inviteeID), byType[models.NotificationTypeInvitationWithdrawn].UserID)
+}
+
+func TestInvitationAPI_DeclineReasonVisibility(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	respondPath := "/api/v1/invitations/" + invitation.ID + "/respond"
+
+	code, _ = doJSON(t, api, "PUT", respondPath, inviteeToken, map[string]string{"status": string(models.InvitationStatusYes), "decline_reason": "Can't wait"})
+	assert.Equal(t, http.StatusBadRequest, code, "a reason only goes with NO or MAYBE")
+
+	code, env = doJSON(t, api, "PUT", respondPath, inviteeToken, map[string]string{"status": string(models.InvitationStatusMaybe), "decline_reason": "Waiting on work"})
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	require.NotNil(t, invitation.DeclineReason)
+	assert.Equal(t, "Waiting on work", *invitation.DeclineReason)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/invitations", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var listed []handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &listed))
+	require.Len(t, listed, 1)
+	require.NotNil(t, listed[0].DeclineReason)
+	assert.Equal(t, "Waiting on work", *listed[0].DeclineReason)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/invitations", playerToken, nil)
+	assert.Equal(t, http.StatusForbidden, code, "other players can't see the TTR's invitations")
+	code, _ = doJSON(t, api, "GET", "/api/v1/invitations/"+invitation.ID, playerToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	_, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID, playerToken, nil)
+	assert.NotContains(t, string(env.Data), "Waiting on work")
+
+	code, env = doJSON(t, api, "PUT", respondPath, inviteeToken, map[string]string{"status": string(models.InvitationStatusYes)})
+	require.Equal(t, http.StatusOK, code, "a MAYBE can still become YES")
+	var accepted handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &accepted))
+	assert.Nil(t, accepted.DeclineReason)
+
+	code, _ = doJSON(t, api, "PUT", respondPath, inviteeToken, map[string]string{"status": string(models.InvitationStatusNo)})
+	assert.Equal(t, http.StatusBadRequest, code, "YES is final")
+}
+
+func TestInvitationAPI_AcceptableFlipsWhenFull(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	firstToken, firstID := registerTestUser(t, api, "first@example.com", "First")
+	secondToken, secondID := registerTestUser(t, api, "second@example.com", "Second")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 2,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	invite := func(inviteeID string) string {
+		code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttr.ID, "invitee_user_id": inviteeID})
+		require.Equal(t, http.StatusCreated, code)
+		var invitation handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &invitation))
+		return invitation.ID
+	}
+	firstInvitation := invite(firstID)
+	secondInvitation := invite(secondID)
+
+	received := func(token string) handler.InvitationResponse {
+		code, env := doJSON(t, api, "GET", "/api/v1/invitations/me", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var invitations []handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &invitations))
+		require.Len(t, invitations, 1)
+		require.NotNil(t, invitations[0].Acceptable)
+		require.NotNil(t, invitations[0].TTR)
+		require.NotNil(t, invitations[0].TTR.OpenSlots)
+		require.NotNil(t, invitations[0].TTR.ConfirmedCount)
+		return invitations[0]
+	}
+
+	listed := received(secondToken)
+	assert.True(t, *listed.Acceptable)
+	assert.Equal(t, 1, *listed.TTR.OpenSlots)
+	assert.Equal(t, 1, *listed.TTR.ConfirmedCount)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+firstInvitation+"/respond", firstToken, map[string]string{"status": string(models.InvitationStatusYes)})
+	require.Equal(t, http.StatusOK, code)
+
+	listed = received(secondToken)
+	assert.False(t, *listed.Acceptable, "the round filled up after the first listing")
+	assert.Equal(t, 0, *listed.TTR.OpenSlots)
+	assert.Equal(t, 2, *listed.TTR.ConfirmedCount)
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/invitations/"+secondInvitation+"/respond", secondToken, map[string]string{"status": string(models.InvitationStatusYes)})
+	assert.Equal(t, http.StatusBadRequest, code, "the capacity check still refuses the answer")
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "TTR is full, cannot accept invitation", env.Error.Message)
+}
+
+func TestInvitationAPI_MessageTemplates(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	ttrID := createTestTTR(t, api, captainToken)
+	teeDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/invitation-templates", captainToken, map[string]string{
+		"name": "Weekend",
+		"body": "{{captain}} tees off at {{course}} on {{date}} at {{time}}",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var template handler.InvitationTemplateResponse
+	require.NoError(t, json.Unmarshal(env.Data, &template))
+	templatePath := "/api/v1/invitation-templates/" + template.ID
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitation-templates", captainToken, map[string]string{"name": "Broken", "body": "Hi\n{{.Course}}"})
+	require.Equal(t, http.StatusUnprocessableEntity, code, "only the listed variables are allowed")
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "INVALID_MESSAGE_TEMPLATE", env.Error.Code)
+	var position struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	}
+	require.NoError(t, json.Unmarshal(env.Error.Details, &position))
+	assert.Equal(t, 2, position.Line)
+	assert.Equal(t, 2, position.Column)
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitation-templates", captainToken, map[string]string{"name": "Broken", "body": "{{course"})
+	require.Equal(t, http.StatusUnprocessableEntity, code)
+	require.NoError(t, json.Unmarshal(env.Error.Details, &position))
+	assert.Equal(t, 1, position.Line)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/invitation-templates", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var listed []handler.InvitationTemplateResponse
+	require.NoError(t, json.Unmarshal(env.Data, &listed))
+	require.Len(t, listed, 1, "malformed templates aren't saved")
+
+	code, _ = doJSON(t, api, "GET", templatePath, otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, code, "templates are private to their owner")
+	code, _ = doJSON(t, api, "PUT", templatePath, otherToken, map[string]string{"name": "Mine"})
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doJSON(t, api, "DELETE", templatePath, otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, env = doJSON(t, api, "GET", "/api/v1/invitation-templates", otherToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(env.Data, &listed))
+	assert.Empty(t, listed)
+
+	invite := map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID, "template_id": template.ID, "message": "Hi"}
+	code, _ = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, invite)
+	assert.Equal(t, http.StatusBadRequest, code, "a message and a template can't both be sent")
+	delete(invite, "message")
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, invite)
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	require.NotNil(t, invitation.Message)
+	assert.Equal(t, "Captain Tester tees off at Pebble Beach on "+teeDate+" at 08:30", *invitation.Message)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", inviteeToken, map[string]string{"status": string(models.InvitationStatusNo)})
+	require.Equal(t, http.StatusOK, code)
+	code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{"ttr_id": ttrID, "invitee_user_id": inviteeID, "message": "See you at {{course}}"})
+	require.Equal(t, http.StatusCreated, code, "messages sent directly are expanded too")
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+	assert.Equal(t, "See you at Pebble Beach", *invitation.Message)
+
+	code, _ = doJSON(t, api, "DELETE", templatePath, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "GET", templatePath, captainToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestInvitationAPI_Forwarding(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	brotherToken, brotherID := registerTestUser(t, api, "brother@example.com", "Brother")
+	_, rivalID := registerTestUser(t, api, "rival@example.com", "Rival")
+	ttrID := createTestTTR(t, api, captainToken)
+	invitationID := inviteTestUser(t, api, captainToken, ttrID, inviteeID)
+
+	code, _ := doJSON(t, api, "PUT", "/api/v1/users/me/blocks/"+rivalID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, env := doJSON(t, api, "GET", "/api/v1/users/me/blocks", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var blocked []handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &blocked))
+	require.Len(t, blocked, 1)
+	assert.Equal(t, rivalID, blocked[0].ID)
+
+	forwardPath := "/api/v1/invitations/" + invitationID + "/forward"
+	code, env = doJSON(t, api, "POST", forwardPath, inviteeToken, map[string]string{"invitee_user_id": rivalID})
+	assert.Equal(t, http.StatusBadRequest, code, "the captain blocked the target")
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "CANNOT_FORWARD_TO_USER", env.Error.Code)
+
+	code, env = doJSON(t, api, "POST", forwardPath, inviteeToken, map[string]string{"invitee_user_id": brotherID, "message": "I can't make it, my brother can"})
+	require.Equal(t, http.StatusCreated, code)
+	var forwarded handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &forwarded))
+	assert.Equal(t, models.InvitationStatusForwardRequested, forwarded.Status)
+	require.NotNil(t, forwarded.ForwardedByUserID)
+	assert.Equal(t, inviteeID, *forwarded.ForwardedByUserID)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/invitations/"+invitationID, inviteeToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var original handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &original))
+	assert.Equal(t, models.InvitationStatusNo, original.Status)
+
+	approvePath := "/api/v1/invitations/" + forwarded.ID + "/approve"
+	code, _ = doJSON(t, api, "PUT", approvePath, inviteeToken, nil)
+	assert.Equal(t, http.StatusForbidden, code, "only managers approve")
+	code, env = doJSON(t, api, "PUT", approvePath, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var approved handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &approved))
+	assert.Equal(t, models.InvitationStatusPending, approved.Status)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+forwarded.ID+"/respond", brotherToken, map[string]string{"status": string(models.InvitationStatusYes)})
+	assert.Equal(t, http.StatusOK, code)
+}
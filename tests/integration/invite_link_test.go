@@ -0,0 +1,233 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+// racingInviteLinkRepository holds every FindByToken until all accepts have
+// made theirs, so each one gets past InviteLinkService's checks before any
+// of them redeems the link.
+type racingInviteLinkRepository struct {
+	repository.InviteLinkRepository
+	found sync.WaitGroup
+}
+
+func (r *racingInviteLinkRepository) FindByToken(ctx context.Context, token string) (*models.InviteLink, error) {
+	link, err := r.InviteLinkRepository.FindByToken(ctx, token)
+	r.found.Done()
+	r.found.Wait()
+	return link, err
+}
+
+func createTestInviteLink(t *testing.T, h http.Handler, token, ttrID string, body map[string]interface{}) handler.InviteLinkResponse {
+	t.Helper()
+
+	code, env := doJSON(t, h, "POST", "/api/v1/ttrs/"+ttrID+"/invite-links", token, body)
+	require.Equal(t, http.StatusCreated, code)
+
+	var link handler.InviteLinkResponse
+	require.NoError(t, json.Unmarshal(env.Data, &link))
+	return link
+}
+
+func TestInviteLinkAPI_Flow(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+	lateToken, _ := registerTestUser(t, api, "late@example.com", "Late")
+	ttrID := createTestTTR(t, api, captainToken)
+
+	link := createTestInviteLink(t, api, captainToken, ttrID, map[string]interface{}{"max_uses": 2})
+	assert.NotEmpty(t, link.Token)
+	assert.Equal(t, 2, link.MaxUses)
+	assert.Equal(t, 0, link.Uses)
+
+	t.Run("only managers create links", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/invite-links", otherToken, map[string]interface{}{"max_uses": 1})
+		assert.Equal(t, http.StatusForbidden, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+	})
+
+	t.Run("expiry must be in the future", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/invite-links", captainToken, map[string]interface{}{
+			"max_uses":   1,
+			"expires_at": time.Now().Add(-time.Hour).Format(time.RFC3339),
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_INVITE_LINK_EXPIRY", env.Error.Code)
+	})
+
+	t.Run("preview is public", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/public/invite-links/"+link.Token, "", nil)
+		require.Equal(t, http.StatusOK, code)
+		var preview handler.InviteLinkPreviewResponse
+		require.NoError(t, json.Unmarshal(env.Data, &preview))
+		assert.Equal(t, "Pebble Beach", preview.CourseName)
+		assert.Equal(t, "Captain Tester", preview.CaptainName)
+		assert.Equal(t, 3, preview.OpenSlots)
+		assert.Equal(t, 2, preview.RemainingUses)
+		assert.True(t, preview.Usable)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/public/invite-links/not-a-token", "", nil)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("accepting joins as confirmed player", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/invite-links/"+link.Token+"/accept", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		var joined bool
+		for _, player := range ttr.Players {
+			if player.UserID == playerID {
+				joined = true
+				assert.Equal(t, models.TTRPlayerStatusConfirmed, player.Status)
+			}
+		}
+		assert.True(t, joined)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/invite-links/"+link.Token+"/accept", playerToken, nil)
+		assert.Equal(t, http.StatusConflict, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "ALREADY_PLAYER", env.Error.Code)
+	})
+
+	t.Run("a used up link is refused", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/invite-links/"+link.Token+"/accept", otherToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "POST", "/api/v1/invite-links/"+link.Token+"/accept", lateToken, nil)
+		assert.Equal(t, http.StatusConflict, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVITE_LINK_USED_UP", env.Error.Code)
+	})
+
+	t.Run("a revoked link is refused", func(t *testing.T) {
+		revocable := createTestInviteLink(t, api, captainToken, ttrID, map[string]interface{}{"max_uses": 1})
+
+		code, env := doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID+"/invite-links/"+revocable.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var revoked handler.InviteLinkResponse
+		require.NoError(t, json.Unmarshal(env.Data, &revoked))
+		assert.NotNil(t, revoked.RevokedAt)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/invite-links/"+revocable.Token+"/accept", lateToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVITE_LINK_REVOKED", env.Error.Code)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/invite-links", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var links []handler.InviteLinkResponse
+		require.NoError(t, json.Unmarshal(env.Data, &links))
+		require.Len(t, links, 2)
+		assert.Equal(t, revocable.ID, links[0].ID)
+		assert.Equal(t, 2, links[1].Uses)
+	})
+}
+
+func TestInviteLinkService_ConcurrentAccepts(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxUses    int
+		maxPlayers int
+		accepts    int
+		joined     int
+		refusedErr error
+	}{
+		{name: "uses run out first", maxUses: 3, maxPlayers: 8, accepts: 6, joined: 3, refusedErr: service.ErrInviteLinkUsedUp},
+		{name: "slots run out first", maxUses: 8, maxPlayers: 3, accepts: 6, joined: 2, refusedErr: service.ErrTTRFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTTRTestDB(t)
+			// Every connection to ":memory:" opens a separate, empty database.
+			sqlDB, err := db.DB()
+			require.NoError(t, err)
+			sqlDB.SetMaxOpenConns(1)
+
+			api := newTestAPI(t, db)
+			captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+			code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+				"course_name": "Pebble Beach",
+				"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+				"tee_time":    "08:30",
+				"max_players": tt.maxPlayers,
+			})
+			require.Equal(t, http.StatusCreated, code)
+			var ttr handler.TTRResponse
+			require.NoError(t, json.Unmarshal(env.Data, &ttr))
+			link := createTestInviteLink(t, api, captainToken, ttr.ID, map[string]interface{}{"max_uses": tt.maxUses})
+
+			userIDs := make([]uuid.UUID, tt.accepts)
+			for i := range userIDs {
+				_, userID := registerTestUser(t, api, fmt.Sprintf("player%d@example.com", i), "Player")
+				userIDs[i] = uuid.MustParse(userID)
+			}
+
+			inviteLinkRepo := &racingInviteLinkRepository{InviteLinkRepository: repository.NewInviteLinkRepository(db)}
+			inviteLinkRepo.found.Add(tt.accepts)
+			ttrRepo := repository.NewTTRRepository(db)
+			authorizer := service.NewAuthorizer(ttrRepo, repository.NewOrganizationRepository(db), repository.NewInvitationRepository(db), repository.NewUserRepository(db))
+			notificationService := service.NewNotificationService(repository.NewNotificationRepository(db), nil, nil, 0, zap.NewNop())
+			ttrService := service.NewTTRService(ttrRepo, repository.NewUserRepository(db), repository.NewInvitationRepository(db), repository.NewTransactor(db), authorizer, notificationService, nil, nil, nil, nil, time.Hour, 0, zap.NewNop())
+			inviteLinkService := service.NewInviteLinkService(inviteLinkRepo, ttrRepo, ttrService, authorizer, notificationService, zap.NewNop())
+
+			errs := make(chan error, tt.accepts)
+			var wg sync.WaitGroup
+			for _, userID := range userIDs {
+				wg.Add(1)
+				go func(userID uuid.UUID) {
+					defer wg.Done()
+					_, err := inviteLinkService.AcceptInviteLink(context.Background(), link.Token, userID)
+					errs <- err
+				}(userID)
+			}
+			wg.Wait()
+			close(errs)
+
+			var joined, refused int
+			for err := range errs {
+				if err == nil {
+					joined++
+					continue
+				}
+				assert.True(t, errors.Is(err, tt.refusedErr), "unexpected error: %v", err)
+				refused++
+			}
+			assert.Equal(t, tt.joined, joined)
+			assert.Equal(t, tt.accepts-tt.joined, refused)
+
+			var players int64
+			require.NoError(t, db.Model(&models.TTRPlayer{}).Where("ttr_id = ?", ttr.ID).Count(&players).Error)
+			assert.LessOrEqual(t, players, int64(tt.maxPlayers))
+			assert.Equal(t, int64(tt.joined+1), players)
+
+			var stored models.InviteLink
+			require.NoError(t, db.First(&stored, "id = ?", link.ID).Error)
+			assert.LessOrEqual(t, stored.Uses, tt.maxUses)
+			assert.Equal(t, tt.joined, stored.Uses)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+// createCompletedRound creates a TTR captained by captainToken, has the other
+// players join it and marks it completed.
+func createCompletedRound(t *testing.T, h http.Handler, captainToken string, playerTokens ...string) string {
+	t.Helper()
+
+	ttrID := createTestTTR(t, h, captainToken)
+	for _, token := range playerTokens {
+		code, _ := doJSON(t, h, "POST", "/api/v1/ttrs/"+ttrID+"/join", token, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	code, _ := doJSON(t, h, "PUT", "/api/v1/ttrs/"+ttrID, captainToken, map[string]string{"status": string(models.TTRStatusCompleted)})
+	require.Equal(t, http.StatusOK, code)
+	return ttrID
+}
+
+func createTestLeague(t *testing.T, h http.Handler, token, scoringScheme string) string {
+	t.Helper()
+
+	code, env := doJSON(t, h, "POST", "/api/v1/leagues", token, map[string]string{
+		"name":           "Summer Season",
+		"start_date":     time.Now().Format("2006-01-02"),
+		"end_date":       time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		"scoring_scheme": scoringScheme,
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	var league handler.LeagueResponse
+	require.NoError(t, json.Unmarshal(env.Data, &league))
+	return league.ID
+}
+
+func recordScore(t *testing.T, h http.Handler, token, ttrID, userID string, score int) {
+	t.Helper()
+
+	code, _ := doJSON(t, h, "PUT", "/api/v1/ttrs/"+ttrID+"/scores/"+userID, token, map[string]int{"score": score})
+	require.Equal(t, http.StatusOK, code)
+}
+
+func getStandings(t *testing.T, h http.Handler, token, leagueID string) map[string]handler.LeagueStandingResponse {
+	t.Helper()
+
+	code, env := doJSON(t, h, "GET", "/api/v1/leagues/"+leagueID+"/standings", token, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var standings []handler.LeagueStandingResponse
+	require.NoError(t, json.Unmarshal(env.Data, &standings))
+
+	byUser := make(map[string]handler.LeagueStandingResponse, len(standings))
+	for _, standing := range standings {
+		byUser[standing.UserID] = standing
+	}
+	return byUser
+}
+
+func TestLeagueAPI_PointsPerFinishWithTies(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	aliceToken, aliceID := registerTestUser(t, api, "alice@example.com", "Alice")
+	bobToken, bobID := registerTestUser(t, api, "bob@example.com", "Bob")
+	carolToken, carolID := registerTestUser(t, api, "carol@example.com", "Carol")
+	daveToken, daveID := registerTestUser(t, api, "dave@example.com", "Dave")
+
+	leagueID := createTestLeague(t, api, aliceToken, models.LeagueScoringPointsPerFinish)
+
+	// Round one: Alice and Bob tie for first and both take the winner's 4
+	// points; Carol is third (2) and Dave last (1).
+	round1 := createCompletedRound(t, api, aliceToken, bobToken, carolToken, daveToken)
+	recordScore(t, api, aliceToken, round1, aliceID, 72)
+	recordScore(t, api, aliceToken, round1, bobID, 72)
+	recordScore(t, api, aliceToken, round1, carolID, 75)
+	recordScore(t, api, aliceToken, round1, daveID, 80)
+
+	// Round two has three players: Bob and Carol tie for first (3 each) and
+	// Alice is last (1).
+	round2 := createCompletedRound(t, api, aliceToken, bobToken, carolToken)
+	recordScore(t, api, aliceToken, round2, aliceID, 80)
+	recordScore(t, api, aliceToken, round2, bobID, 70)
+	recordScore(t, api, aliceToken, round2, carolID, 70)
+
+	for _, ttrID := range []string{round1, round2} {
+		code, _ := doJSON(t, api, "POST", "/api/v1/leagues/"+leagueID+"/ttrs", bobToken, map[string]string{"ttr_id": ttrID})
+		assert.Equal(t, http.StatusForbidden, code, "only the league owner can attach TTRs")
+		code, _ = doJSON(t, api, "POST", "/api/v1/leagues/"+leagueID+"/ttrs", aliceToken, map[string]string{"ttr_id": ttrID})
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	standings := getStandings(t, api, carolToken, leagueID)
+	require.Len(t, standings, 4)
+	assert.Equal(t, handler.LeagueStandingResponse{Position: 1, UserID: bobID, Rounds: 2, TotalStrokes: 142, Points: 7}, standings[bobID])
+	assert.Equal(t, handler.LeagueStandingResponse{Position: 2, UserID: aliceID, Rounds: 2, TotalStrokes: 152, Points: 5}, standings[aliceID])
+	assert.Equal(t, handler.LeagueStandingResponse{Position: 2, UserID: carolID, Rounds: 2, TotalStrokes: 145, Points: 5}, standings[carolID])
+	assert.Equal(t, handler.LeagueStandingResponse{Position: 4, UserID: daveID, Rounds: 1, TotalStrokes: 80, Points: 1}, standings[daveID])
+
+	// A score correction drops the cached standings: Alice now wins round two
+	// outright (3 points, Bob and Carol now 2 each) and moves to the top.
+	recordScore(t, api, aliceToken, round2, aliceID, 68)
+	standings = getStandings(t, api, carolToken, leagueID)
+	assert.Equal(t, 1, standings[aliceID].Position)
+	assert.Equal(t, 7, standings[aliceID].Points)
+	assert.Equal(t, 2, standings[bobID].Position)
+	assert.Equal(t, 6, standings[bobID].Points)
+	assert.Equal(t, 3, standings[carolID].Position)
+	assert.Equal(t, 4, standings[carolID].Points)
+}
+
+func TestLeagueAPI_StrokePlayAndAttachRules(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	aliceToken, aliceID := registerTestUser(t, api, "alice@example.com", "Alice")
+	bobToken, bobID := registerTestUser(t, api, "bob@example.com", "Bob")
+	carolToken, carolID := registerTestUser(t, api, "carol@example.com", "Carol")
+
+	leagueID := createTestLeague(t, api, aliceToken, models.LeagueScoringStrokePlay)
+	otherLeagueID := createTestLeague(t, api, aliceToken, models.LeagueScoringStrokePlay)
+
+	openTTR := createTestTTR(t, api, aliceToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/leagues/"+leagueID+"/ttrs", aliceToken, map[string]string{"ttr_id": openTTR})
+	assert.Equal(t, http.StatusBadRequest, code, "open TTRs cannot be attached")
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+openTTR+"/scores/"+aliceID, aliceToken, map[string]int{"score": 72})
+	assert.Equal(t, http.StatusBadRequest, code, "scores need a completed TTR")
+
+	round := createCompletedRound(t, api, aliceToken, bobToken, carolToken)
+	recordScore(t, api, aliceToken, round, aliceID, 74)
+	recordScore(t, api, aliceToken, round, bobID, 71)
+	recordScore(t, api, aliceToken, round, carolID, 74)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+round+"/scores/"+aliceID, bobToken, map[string]int{"score": 60})
+	assert.Equal(t, http.StatusForbidden, code, "players cannot record scores")
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/leagues/"+leagueID+"/ttrs", aliceToken, map[string]string{"ttr_id": round})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/leagues/"+otherLeagueID+"/ttrs", aliceToken, map[string]string{"ttr_id": round})
+	assert.Equal(t, http.StatusConflict, code, "a TTR belongs to one league at most")
+
+	standings := getStandings(t, api, bobToken, leagueID)
+	require.Len(t, standings, 3)
+	assert.Equal(t, 1, standings[bobID].Position)
+	assert.Equal(t, 71, standings[bobID].TotalStrokes)
+	assert.Equal(t, 2, standings[aliceID].Position)
+	assert.Equal(t, 2, standings[carolID].Position)
+
+	assert.Empty(t, getStandings(t, api, bobToken, otherLeagueID))
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+)
+
+func TestMaintenanceAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	userToken, _ := registerTestUser(t, api, "golfer@example.com", "Golfer")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	endsAt := time.Now().Add(30 * time.Minute).UTC().Truncate(time.Second)
+	code, _ := doJSON(t, api, "PUT", "/api/v1/admin/maintenance", adminToken, map[string]interface{}{
+		"enabled": true,
+		"ends_at": endsAt.Format(time.RFC3339),
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("routes answer 503 with the estimated end", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/ttrs", nil)
+		req.Header.Set("Authorization", "Bearer "+userToken)
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		var env apiEnvelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env))
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "MAINTENANCE", env.Error.Code)
+		assert.JSONEq(t, `{"ends_at":"`+endsAt.Format(time.RFC3339)+`"}`, string(env.Error.Details))
+	})
+
+	t.Run("before authentication", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{"email": "golfer@example.com", "password": "password123"})
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "MAINTENANCE", env.Error.Code)
+	})
+
+	t.Run("health checks and the switch are exempt", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/healthz", "", nil)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "GET", "/readyz", "", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, code, "not ready, so load balancers drain")
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "MAINTENANCE", env.Error.Code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v2/admin/maintenance", adminToken, nil)
+		assert.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/maintenance", userToken, map[string]interface{}{"enabled": false})
+		assert.Equal(t, http.StatusForbidden, code, "the switch still needs an admin")
+	})
+
+	t.Run("turning it off restores traffic", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/admin/maintenance", adminToken, map[string]interface{}{"enabled": false})
+		require.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs", userToken, nil)
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = doJSON(t, api, "GET", "/readyz", "", nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
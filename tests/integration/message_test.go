@@ -0,0 +1,359 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+func createTestTTR(t *testing.T, h http.Handler, token string) string {
+	t.Helper()
+
+	// Tests create several of these for one captain, which would otherwise
+	// be refused as duplicates.
+	code, env := doJSON(t, h, "POST", "/api/v1/ttrs", token, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+		"force":       true,
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	return ttr.ID
+}
+
+func TestMessageRepository_CountUnreadInterleaved(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	repo := repository.NewMessageRepository(db)
+	ctx := context.Background()
+
+	aliceToken, aliceIDStr := registerTestUser(t, api, "alice@example.com", "Alice")
+	bobToken, bobIDStr := registerTestUser(t, api, "bob@example.com", "Bob")
+	_, carolIDStr := registerTestUser(t, api, "carol@example.com", "Carol")
+	aliceID, bobID, carolID := uuid.MustParse(aliceIDStr), uuid.MustParse(bobIDStr), uuid.MustParse(carolIDStr)
+
+	first := uuid.MustParse(createTestTTR(t, api, aliceToken))
+	second := uuid.MustParse(createTestTTR(t, api, aliceToken))
+	for _, id := range []uuid.UUID{first, second} {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+id.String()+"/join", bobToken, nil)
+		require.Equal(t, http.StatusOK, code)
+	}
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	post := func(ttrID, userID uuid.UUID, seconds int) *models.Message {
+		message := &models.Message{TTRID: ttrID, UserID: userID, Body: "hi", CreatedAt: base.Add(time.Duration(seconds) * time.Second)}
+		require.NoError(t, repo.Create(ctx, message))
+		return message
+	}
+	counts := func(userID uuid.UUID) map[uuid.UUID]int64 {
+		result, err := repo.CountUnreadByUserID(ctx, userID)
+		require.NoError(t, err)
+		return result
+	}
+
+	m1 := post(first, aliceID, 1)
+	post(first, aliceID, 2)
+	assert.Equal(t, map[uuid.UUID]int64{first: 2}, counts(bobID))
+	assert.Empty(t, counts(aliceID), "own messages are never unread")
+
+	require.NoError(t, repo.MarkRead(ctx, first, bobID, m1.CreatedAt))
+	assert.Equal(t, map[uuid.UUID]int64{first: 1}, counts(bobID))
+
+	post(first, bobID, 3)
+	m4 := post(first, aliceID, 4)
+	post(second, aliceID, 5)
+	assert.Equal(t, map[uuid.UUID]int64{first: 2, second: 1}, counts(bobID))
+	assert.Equal(t, map[uuid.UUID]int64{first: 1}, counts(aliceID))
+
+	require.NoError(t, repo.MarkRead(ctx, first, bobID, m4.CreatedAt))
+	assert.Equal(t, map[uuid.UUID]int64{second: 1}, counts(bobID))
+
+	require.NoError(t, repo.MarkRead(ctx, first, bobID, m1.CreatedAt))
+	assert.Equal(t, map[uuid.UUID]int64{second: 1}, counts(bobID), "an older read marker does not move back")
+
+	post(first, aliceID, 6)
+	assert.Equal(t, map[uuid.UUID]int64{first: 1, second: 1}, counts(bobID))
+
+	assert.Empty(t, counts(carolID), "non-players have no unread counts")
+}
+
+func TestMessageAPI_ReadReceipts(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var posted []handler.MessageResponse
+	for _, body := range []string{"Who's driving?", "I can"} {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages", captainToken, map[string]string{"body": body})
+		require.Equal(t, http.StatusCreated, code)
+		var message handler.MessageResponse
+		require.NoError(t, json.Unmarshal(env.Data, &message))
+		posted = append(posted, message)
+	}
+
+	unreadCounts := func(token string) map[string]int64 {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/me/unread-counts", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var counts map[string]int64
+		require.NoError(t, json.Unmarshal(env.Data, &counts))
+		return counts
+	}
+
+	assert.Equal(t, map[string]int64{ttrID: 2}, unreadCounts(playerToken))
+
+	code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var messages []handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &messages))
+	assert.Len(t, messages, 2)
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages/read", playerToken, map[string]string{"message_id": posted[1].ID})
+	require.Equal(t, http.StatusOK, code)
+	assert.Empty(t, unreadCounts(playerToken))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages/read", playerToken, map[string]string{"message_id": uuid.NewString()})
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", outsiderToken, nil)
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages", outsiderToken, map[string]string{"body": "hello"})
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
+func TestMessageAPI_EditAndDelete(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages", playerToken, map[string]string{"body": "See you at 8"})
+	require.Equal(t, http.StatusCreated, code)
+	var message handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &message))
+	path := "/api/v1/ttrs/" + ttrID + "/messages/" + message.ID
+
+	code, _ = doJSON(t, api, "PUT", path, captainToken, map[string]string{"body": "hijacked"})
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, env = doJSON(t, api, "PUT", path, playerToken, map[string]string{"body": "See you at 8:15"})
+	require.Equal(t, http.StatusOK, code)
+	var edited handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &edited))
+	assert.Equal(t, "See you at 8:15", edited.Body)
+	assert.NotNil(t, edited.EditedAt)
+
+	code, _ = doJSON(t, api, "DELETE", path, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var messages []handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &messages))
+	require.Len(t, messages, 1, "deleted messages keep their place in the thread")
+	assert.True(t, messages[0].Deleted)
+	assert.Empty(t, messages[0].Body)
+}
+
+func TestMessageAPI_Reactions(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var posted []handler.MessageResponse
+	for _, body := range []string{"Tee time confirmed", "Bring extra balls"} {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/messages", captainToken, map[string]string{"body": body})
+		require.Equal(t, http.StatusCreated, code)
+		var message handler.MessageResponse
+		require.NoError(t, json.Unmarshal(env.Data, &message))
+		posted = append(posted, message)
+	}
+	reactionsPath := "/api/v1/ttrs/" + ttrID + "/messages/" + posted[0].ID + "/reactions"
+
+	for i := 0; i < 2; i++ {
+		code, _ = doJSON(t, api, "POST", reactionsPath, playerToken, map[string]string{"emoji": "thumbsup"})
+		require.Equal(t, http.StatusOK, code, "re-adding a reaction is idempotent")
+	}
+	code, _ = doJSON(t, api, "POST", reactionsPath, captainToken, map[string]string{"emoji": "thumbsup"})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", reactionsPath, captainToken, map[string]string{"emoji": "golf"})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = doJSON(t, api, "POST", reactionsPath, playerToken, map[string]string{"emoji": "<script>"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	listAs := func(token string) map[string][]handler.MessageReactionResponse {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var messages []handler.MessageResponse
+		require.NoError(t, json.Unmarshal(env.Data, &messages))
+		byID := make(map[string][]handler.MessageReactionResponse, len(messages))
+		for _, message := range messages {
+			byID[message.ID] = message.Reactions
+		}
+		return byID
+	}
+
+	reactions := listAs(playerToken)
+	assert.Equal(t, []handler.MessageReactionResponse{
+		{Emoji: "thumbsup", Count: 2, ReactedByMe: true},
+		{Emoji: "golf", Count: 1, ReactedByMe: false},
+	}, reactions[posted[0].ID])
+	assert.Empty(t, reactions[posted[1].ID])
+
+	code, _ = doJSON(t, api, "DELETE", reactionsPath+"?emoji=thumbsup", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	reactions = listAs(captainToken)
+	assert.Equal(t, []handler.MessageReactionResponse{
+		{Emoji: "thumbsup", Count: 1, ReactedByMe: true},
+		{Emoji: "golf", Count: 1, ReactedByMe: true},
+	}, reactions[posted[0].ID])
+}
+
+func postAttachment(t *testing.T, h http.Handler, path, token, filename, contentType string, content []byte) (int, apiEnvelope) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	partHeader := make(textproto.MIMEHeader)
+	partHeader.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, filename))
+	partHeader.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(partHeader)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteField("body", "Scorecard from Sunday"))
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", path, &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var env apiEnvelope
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &env))
+	return rec.Code, env
+}
+
+func TestMessageAPI_Attachments(t *testing.T) {
+	db := setupTTRTestDB(t)
+	store := storage.NewMemoryStorage()
+	api := newTestAPIWithStorage(t, db, store, config.MessagingConfig{
+		EditWindow:          15 * time.Minute,
+		AttachmentMaxSize:   1000,
+		TTRAttachmentQuota:  1500,
+		UserAttachmentQuota: 1000,
+		AttachmentURLTTL:    10 * time.Minute,
+	})
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	path := "/api/v1/ttrs/" + ttrID + "/messages/attachments"
+
+	code, _ = postAttachment(t, api, path, outsiderToken, "card.png", "image/png", make([]byte, 10))
+	assert.Equal(t, http.StatusForbidden, code)
+	code, _ = postAttachment(t, api, path, playerToken, "notes.html", "text/html", make([]byte, 10))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = postAttachment(t, api, path, playerToken, "card.png", "image/png", make([]byte, 1001))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code)
+
+	code, env := postAttachment(t, api, path, playerToken, "card.png", "image/png", make([]byte, 800))
+	require.Equal(t, http.StatusCreated, code)
+	var posted handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &posted))
+	assert.Equal(t, "Scorecard from Sunday", posted.Body)
+	require.NotNil(t, posted.Attachment)
+	assert.Equal(t, "card.png", posted.Attachment.Name)
+	assert.Equal(t, int64(800), posted.Attachment.Size)
+
+	code, _ = postAttachment(t, api, path, playerToken, "second.pdf", "application/pdf", make([]byte, 300))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code, "user quota")
+	code, _ = postAttachment(t, api, path, captainToken, "course.pdf", "application/pdf", make([]byte, 701))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, code, "TTR quota")
+	code, _ = postAttachment(t, api, path, captainToken, "course.pdf", "application/pdf", make([]byte, 700))
+	require.Equal(t, http.StatusCreated, code)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var messages []handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &messages))
+	require.Len(t, messages, 2)
+	require.NotNil(t, messages[1].Attachment)
+	assert.Contains(t, messages[1].Attachment.URL, url.PathEscape("ttr-attachments/"+ttrID+"/"))
+	assert.Contains(t, messages[1].Attachment.URL, "expires=")
+
+	stored, err := repository.NewMessageRepository(db).FindByID(context.Background(), uuid.MustParse(posted.ID))
+	require.NoError(t, err)
+	key := *stored.AttachmentKey
+	assert.True(t, store.Has(key))
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID+"/messages/"+posted.ID, playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/messages", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var afterDelete []handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &afterDelete))
+	assert.True(t, afterDelete[1].Deleted)
+	assert.Nil(t, afterDelete[1].Attachment, "deleted messages hide their attachment")
+
+	code, _ = postAttachment(t, api, path, playerToken, "second.pdf", "application/pdf", make([]byte, 300))
+	assert.Equal(t, http.StatusCreated, code, "deleting frees quota")
+
+	logger, _ := zap.NewDevelopment()
+	messageService := service.NewMessageService(repository.NewMessageRepository(db), service.NewAuthorizer(repository.NewTTRRepository(db), repository.NewOrganizationRepository(db), repository.NewInvitationRepository(db), repository.NewUserRepository(db)), store, config.MessagingConfig{}, nil, logger)
+	require.NoError(t, messageService.PurgeDeletedAttachments(context.Background()))
+	assert.False(t, store.Has(key), "purge removes the stored object")
+
+	stored, err = repository.NewMessageRepository(db).FindByID(context.Background(), uuid.MustParse(posted.ID))
+	require.NoError(t, err)
+	assert.Nil(t, stored.AttachmentKey)
+}
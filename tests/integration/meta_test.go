@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestMetaAPI_ErrorCatalogue(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	code, env := doJSON(t, api, "GET", "/api/v1/meta/errors", "", nil)
+	require.Equal(t, http.StatusOK, code, "the catalogue is public")
+	var codes []handler.ErrorCodeResponse
+	require.NoError(t, json.Unmarshal(env.Data, &codes))
+
+	byCode := make(map[string]handler.ErrorCodeResponse, len(codes))
+	for _, c := range codes {
+		byCode[c.Code] = c
+	}
+	require.Contains(t, byCode, "TTR_FULL")
+	assert.Equal(t, http.StatusBadRequest, byCode["TTR_FULL"].Status)
+	assert.NotEmpty(t, byCode["TTR_FULL"].Description)
+	require.Contains(t, byCode, "INVITATION_ALREADY_PENDING")
+	assert.Equal(t, http.StatusConflict, byCode["INVITATION_ALREADY_PENDING"].Status)
+
+	t.Run("service errors carry their code", func(t *testing.T) {
+		captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+		playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    "08:30",
+			"max_players": 1,
+			"visibility":  "PUBLIC",
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+		code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+		assert.Equal(t, byCode["TTR_FULL"].Status, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "TTR_FULL", env.Error.Code)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/leave", captainToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "CAPTAIN_CANNOT_LEAVE", env.Error.Code)
+	})
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestModelHooks_SQLite(t *testing.T) {
+	db := setupTTRTestDB(t)
+
+	user := &models.User{Email: "hooks@example.com", PasswordHash: "hash", FirstName: "Hook", LastName: "Golfer"}
+	require.NoError(t, db.Create(user).Error)
+	assert.NotEqual(t, uuid.Nil, user.ID, "sqlite has no uuid_generate_v4, so the hook sets the id")
+	assert.False(t, user.CreatedAt.IsZero())
+	assert.Equal(t, user.CreatedAt, user.UpdatedAt)
+
+	ttr := &models.TTR{
+		CourseName:      "Pebble Beach",
+		TeeDate:         time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		CreatedByUserID: user.ID,
+		CaptainUserID:   user.ID,
+	}
+	require.NoError(t, db.Create(ttr).Error)
+	assert.NotEqual(t, uuid.Nil, ttr.ID)
+	assert.Equal(t, models.DefaultMaxPlayers, ttr.MaxPlayers)
+
+	invitation := &models.Invitation{TTRID: ttr.ID, InviterUserID: user.ID, InviteeUserID: uuid.New(), Status: models.InvitationStatusPending}
+	require.NoError(t, db.Create(invitation).Error)
+	assert.NotEqual(t, uuid.Nil, invitation.ID)
+	assert.False(t, invitation.CreatedAt.IsZero())
+
+	notification := &models.Notification{UserID: user.ID, Type: models.NotificationTypeTTRUpdate, Title: "Title", Message: "Message"}
+	require.NoError(t, db.Create(notification).Error)
+	assert.NotEqual(t, uuid.Nil, notification.ID)
+	assert.False(t, notification.CreatedAt.IsZero())
+
+	// Saves within the same clock second must still move UpdatedAt on.
+	created := ttr.UpdatedAt
+	time.Sleep(2 * time.Millisecond)
+	ttr.MinPlayers = 3
+	require.NoError(t, db.Save(ttr).Error)
+	assert.True(t, ttr.UpdatedAt.After(created))
+	var stored models.TTR
+	require.NoError(t, db.First(&stored, "id = ?", ttr.ID).Error)
+	assert.True(t, stored.UpdatedAt.After(created), "the bumped UpdatedAt is written")
+
+	userCreated := user.UpdatedAt
+	time.Sleep(2 * time.Millisecond)
+	user.FirstName = "Renamed"
+	require.NoError(t, db.Save(user).Error)
+	assert.True(t, user.UpdatedAt.After(userCreated))
+
+	ttr.MaxPlayers = 0
+	assert.ErrorIs(t, db.Save(ttr).Error, models.ErrTTRMaxPlayers)
+	ttr.MaxPlayers = 4
+	ttr.CourseName = "  "
+	assert.ErrorIs(t, db.Save(ttr).Error, models.ErrTTRCourseName)
+	assert.ErrorIs(t, db.Create(&models.TTR{MaxPlayers: -1, CourseName: "Augusta"}).Error, models.ErrTTRMaxPlayers)
+
+	require.NoError(t, db.Model(&models.TTR{}).Where("id = ?", ttr.ID).Update("status", models.TTRStatusConfirmed).Error,
+		"column updates through a bare model skip the row invariants")
+}
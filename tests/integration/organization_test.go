@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestOrganizationAPI_TTRVisibility(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	ownerToken, _ := registerTestUser(t, api, "owner@example.com", "Owner")
+	memberToken, memberID := registerTestUser(t, api, "member@example.com", "Member")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/orgs", ownerToken, map[string]string{"name": "Pine Valley GC"})
+	require.Equal(t, http.StatusCreated, code)
+	var org handler.OrganizationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &org))
+	orgPath := "/api/v1/orgs/" + org.ID
+
+	code, _ = doJSON(t, api, "POST", orgPath+"/invitations", ownerToken, map[string]string{"email": "Member@Example.com"})
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = doJSON(t, api, "POST", orgPath+"/invitations", ownerToken, map[string]string{"email": "member@example.com"})
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/orgs/invitations/me", memberToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var invitations []handler.OrganizationInvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitations))
+	require.Len(t, invitations, 1)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/orgs/invitations/"+invitations[0].ID+"/respond", outsiderToken, map[string]bool{"accept": true})
+	assert.Equal(t, http.StatusNotFound, code, "only the invited address can respond")
+	code, _ = doJSON(t, api, "PUT", "/api/v1/orgs/invitations/"+invitations[0].ID+"/respond", memberToken, map[string]bool{"accept": true})
+	require.Equal(t, http.StatusOK, code)
+
+	code, env = doJSON(t, api, "GET", orgPath+"/members", memberToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var members []handler.OrganizationMemberResponse
+	require.NoError(t, json.Unmarshal(env.Data, &members))
+	assert.Len(t, members, 2)
+	code, _ = doJSON(t, api, "GET", orgPath, outsiderToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	teeDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	createTTR := func(token string, organizationID string) (int, handler.TTRResponse) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", token, map[string]interface{}{
+			"course_name":     "Pine Valley",
+			"tee_date":        teeDate,
+			"tee_time":        "08:00",
+			"max_players":     4,
+			"visibility":      "PUBLIC",
+			"organization_id": organizationID,
+			"force":           true,
+		})
+		var ttr handler.TTRResponse
+		if code == http.StatusCreated {
+			require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		}
+		return code, ttr
+	}
+
+	code, _ = createTTR(outsiderToken, org.ID)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, clubTTR := createTTR(ownerToken, org.ID)
+	require.Equal(t, http.StatusCreated, code)
+	require.NotNil(t, clubTTR.OrganizationID)
+	assert.Equal(t, org.ID, *clubTTR.OrganizationID)
+	code, publicTTR := createTTR(ownerToken, "")
+	require.Equal(t, http.StatusCreated, code)
+
+	search := func(token string) []string {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		ids := make([]string, 0, len(ttrs))
+		for _, ttr := range ttrs {
+			ids = append(ids, ttr.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{clubTTR.ID, publicTTR.ID}, search(memberToken))
+	assert.ElementsMatch(t, []string{publicTTR.ID}, search(outsiderToken))
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+clubTTR.ID, memberToken, nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+clubTTR.ID, outsiderToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+clubTTR.ID+"/join", outsiderToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+clubTTR.ID, memberToken, map[string]string{"course_name": "Merion"})
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _ = doJSON(t, api, "PUT", orgPath+"/members/"+memberID, ownerToken, map[string]string{"role": "admin"})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+clubTTR.ID, memberToken, map[string]string{"course_name": "Merion"})
+	assert.Equal(t, http.StatusOK, code, "organization admins can manage any TTR in the organization")
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+publicTTR.ID, memberToken, map[string]string{"course_name": "Merion"})
+	assert.Equal(t, http.StatusForbidden, code, "admin rights don't reach TTRs outside the organization")
+
+	code, _ = doJSON(t, api, "DELETE", orgPath+"/members/"+memberID, memberToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.ElementsMatch(t, []string{publicTTR.ID}, search(memberToken), "leaving hides the club's TTRs")
+}
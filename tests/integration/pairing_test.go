@@ -0,0 +1,71 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestPairingAPI_SuggestPairings(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 8,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	setHandicap := func(token string, handicap float64) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{"handicap": handicap})
+		require.Equal(t, http.StatusOK, code)
+	}
+	setHandicap(captainToken, 2)
+
+	var playerToken string
+	for email, handicap := range map[string]float64{"ten@example.com": 10, "twelve@example.com": 12, "twenty@example.com": 20, "unknown@example.com": 0} {
+		token, _ := registerTestUser(t, api, email, "Player")
+		if handicap > 0 {
+			setHandicap(token, handicap)
+		}
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		playerToken = token
+	}
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/pairings/suggest", playerToken, nil)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/pairings/suggest", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var suggested handler.SuggestedPairingsResponse
+	require.NoError(t, json.Unmarshal(env.Data, &suggested))
+
+	require.Len(t, suggested.Groups, 2)
+	assert.Len(t, suggested.Groups[0].UserIDs, 3)
+	assert.Len(t, suggested.Groups[1].UserIDs, 2)
+	assert.Equal(t, 62.0, suggested.Groups[0].TotalHandicap+suggested.Groups[1].TotalHandicap, "the player without a handicap counts as 18")
+	assert.Len(t, suggested.Pairings, 5)
+	assert.Contains(t, suggested.Pairings, captainID)
+
+	// The suggestion is a valid body for the pairings endpoint.
+	code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/pairings", captainToken, suggested)
+	require.Equal(t, http.StatusOK, code)
+	var players []handler.TTRPlayerResponse
+	require.NoError(t, json.Unmarshal(env.Data, &players))
+	require.Len(t, players, 5)
+	for _, player := range players {
+		assert.Equal(t, suggested.Pairings[player.UserID], player.GroupNumber)
+	}
+}
@@ -0,0 +1,169 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+// playerUser is the user of userID's place on a roster.
+func playerUser(t *testing.T, players []handler.TTRPlayerResponse, userID string) *handler.UserResponse {
+	t.Helper()
+	for _, player := range players {
+		if player.UserID == userID {
+			require.NotNil(t, player.User)
+			return player.User
+		}
+	}
+	require.Failf(t, "player not on the roster", "user %s", userID)
+	return nil
+}
+
+func TestPrivacyAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	bobToken, bobID := registerTestUser(t, api, "bob@example.com", "Bobby")
+	strangerToken, _ := registerTestUser(t, api, "stranger@example.com", "Stranger")
+
+	code, _ := doJSON(t, api, "PUT", "/api/v1/users/me", bobToken, map[string]interface{}{"handicap": 8.2, "phone": "555-0100"})
+	require.Equal(t, http.StatusOK, code)
+
+	getUser := func(token string) handler.UserResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/users/"+bobID, token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var user handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &user))
+		return user
+	}
+	setPrivacy := func(settings map[string]bool) handler.PrivacySettingsResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "PUT", "/api/v1/users/me/privacy", bobToken, settings)
+		require.Equal(t, http.StatusOK, code)
+		var privacy handler.PrivacySettingsResponse
+		require.NoError(t, json.Unmarshal(env.Data, &privacy))
+		return privacy
+	}
+	search := func(query string) []handler.UserResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/users?q="+query, strangerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var users []handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &users))
+		return users
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		user := getUser(strangerToken)
+		assert.Empty(t, user.Email)
+		assert.Nil(t, user.Phone)
+		require.NotNil(t, user.Handicap)
+		assert.Equal(t, 8.2, *user.Handicap)
+		assert.Nil(t, user.Privacy, "privacy settings are only shown to their user")
+
+		own := getUser(bobToken)
+		assert.Equal(t, "bob@example.com", own.Email)
+		require.NotNil(t, own.Phone)
+
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", bobToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var me handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &me))
+		require.NotNil(t, me.Privacy)
+		assert.Equal(t, handler.PrivacySettingsResponse{ShowHandicap: true, DiscoverableInSearch: true}, *me.Privacy)
+	})
+
+	t.Run("show email and phone", func(t *testing.T) {
+		privacy := setPrivacy(map[string]bool{"show_email": true})
+		assert.Equal(t, handler.PrivacySettingsResponse{ShowHandicap: true, ShowEmail: true, DiscoverableInSearch: true}, privacy)
+		user := getUser(strangerToken)
+		assert.Equal(t, "bob@example.com", user.Email)
+		assert.Nil(t, user.Phone)
+
+		setPrivacy(map[string]bool{"show_phone": true})
+		user = getUser(strangerToken)
+		assert.Equal(t, "bob@example.com", user.Email, "settings not sent are kept")
+		require.NotNil(t, user.Phone)
+		assert.Equal(t, "555-0100", *user.Phone)
+	})
+
+	t.Run("hide handicap", func(t *testing.T) {
+		setPrivacy(map[string]bool{"show_handicap": false})
+		assert.Nil(t, getUser(strangerToken).Handicap)
+		assert.NotNil(t, getUser(bobToken).Handicap, "users always see their own handicap")
+		assert.Nil(t, getUser(captainToken).Handicap)
+	})
+
+	t.Run("captains see their players' handicaps", func(t *testing.T) {
+		ttrID := createTestTTR(t, api, captainToken)
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", bobToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		user := getUser(captainToken)
+		require.NotNil(t, user.Handicap)
+		assert.Equal(t, "Bobby", user.FirstName)
+		assert.Nil(t, getUser(strangerToken).Handicap)
+
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		require.NotNil(t, playerUser(t, players, bobID).Handicap)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		assert.NotNil(t, playerUser(t, ttr.Players, bobID).Handicap)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/players", strangerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		players = nil
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		assert.Nil(t, playerUser(t, players, bobID).Handicap, "only the TTR's captains see hidden handicaps")
+	})
+
+	t.Run("not discoverable in search", func(t *testing.T) {
+		require.Len(t, search("Bobby"), 1)
+
+		setPrivacy(map[string]bool{"discoverable_in_search": false})
+		assert.Empty(t, search("Bobby"))
+		users := search("bob@example.com")
+		require.Len(t, users, 1, "undiscoverable users are still found by exact email")
+		assert.Equal(t, bobID, users[0].ID)
+	})
+}
+
+func TestNotificationPreferencesAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	token, _ := registerTestUser(t, api, "bob@example.com", "Bobby")
+
+	me := func() handler.UserResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/users/me", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var me handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &me))
+		return me
+	}
+	require.NotNil(t, me().NotificationPreferences)
+	assert.True(t, me().NotificationPreferences.WeeklyDigest, "the weekly digest is on by default")
+
+	code, env := doJSON(t, api, "PUT", "/api/v1/users/me/notification-preferences", token, map[string]bool{"weekly_digest": false})
+	require.Equal(t, http.StatusOK, code)
+	var preferences handler.NotificationPreferencesResponse
+	require.NoError(t, json.Unmarshal(env.Data, &preferences))
+	assert.False(t, preferences.WeeklyDigest)
+	assert.False(t, me().NotificationPreferences.WeeklyDigest)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me/notification-preferences", token, map[string]bool{})
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, me().NotificationPreferences.WeeklyDigest, "preferences not sent are kept")
+}
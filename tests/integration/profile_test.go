@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestUserAPI_ProfileDetails(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	token, userID := registerTestUser(t, api, "golfer@example.com", "Golfer")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+
+	code, env := doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{
+		"home_course":              "Pebble Beach",
+		"bio":                      "Weekend hacker chasing single digits",
+		"playing_days":             []string{"sunday", "saturday", "wednesday"},
+		"preferred_tee_time_range": map[string]string{"start": "07:00", "end": "10:30"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	var me handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &me))
+	assert.Equal(t, "Pebble Beach", *me.HomeCourse)
+	assert.Equal(t, []string{"wednesday", "saturday", "sunday"}, me.PlayingDays)
+	assert.Equal(t, &handler.TeeTimeRange{Start: "07:00", End: "10:30"}, me.PreferredTeeTimeRange)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/users/"+userID, otherToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var public handler.UserResponse
+	require.NoError(t, json.Unmarshal(env.Data, &public))
+	assert.Equal(t, "Weekend hacker chasing single digits", *public.Bio)
+	assert.Equal(t, []string{"wednesday", "saturday", "sunday"}, public.PlayingDays)
+	assert.Equal(t, &handler.TeeTimeRange{Start: "07:00", End: "10:30"}, public.PreferredTeeTimeRange)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{
+		"playing_days": []string{"someday"},
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{
+		"preferred_tee_time_range": map[string]string{"start": "11:00", "end": "08:00"},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{
+		"preferred_tee_time_range": map[string]string{"start": "11:00"},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Clearing: empty strings, an empty day list and an empty range.
+	code, env = doJSON(t, api, "PUT", "/api/v1/users/me", token, map[string]interface{}{
+		"home_course":              "",
+		"playing_days":             []string{},
+		"preferred_tee_time_range": map[string]string{"start": "", "end": ""},
+	})
+	require.Equal(t, http.StatusOK, code)
+	me = handler.UserResponse{}
+	require.NoError(t, json.Unmarshal(env.Data, &me))
+	assert.Nil(t, me.HomeCourse)
+	assert.Empty(t, me.PlayingDays)
+	assert.Nil(t, me.PreferredTeeTimeRange)
+	require.NotNil(t, me.Bio, "fields not sent are unchanged")
+}
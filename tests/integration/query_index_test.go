@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"gorm.io/gorm"
+)
+
+// queryIndexes are the composite indexes behind the hot TTR, invitation and
+// notification lookups, keyed by the model that declares them.
+var queryIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	{&models.TTRPlayer{}, "idx_ttr_players_user_ttr"},
+	{&models.TTRCoCaptain{}, "idx_ttr_co_captains_user_ttr"},
+	{&models.Invitation{}, "idx_invitations_ttr_invitee_status"},
+	{&models.TTR{}, "idx_ttrs_status_tee_date"},
+	{&models.TTR{}, "idx_ttrs_captain_tee_date"},
+	{&models.Notification{}, "idx_notifications_user_read_created"},
+	{&models.Notification{}, "idx_notifications_digest"},
+}
+
+func setupQueryIndexDB(tb testing.TB) *gorm.DB {
+	db := setupTTRTestDB(tb)
+	require.NoError(tb, db.AutoMigrate(&models.Notification{}))
+	return db
+}
+
+// seedQueryRows fills the database with a few thousand rows spread over 200
+// users and returns one of them.
+func seedQueryRows(tb testing.TB, db *gorm.DB) uuid.UUID {
+	tb.Helper()
+
+	users := make([]models.User, 200)
+	for i := range users {
+		users[i] = models.User{
+			ID:           uuid.New(),
+			Email:        fmt.Sprintf("player%d@example.com", i),
+			PasswordHash: "hash",
+			FirstName:    fmt.Sprintf("Player%d", i),
+			LastName:     "Seeded",
+		}
+	}
+	require.NoError(tb, db.CreateInBatches(users, 100).Error)
+
+	var (
+		ttrs          []models.TTR
+		players       []models.TTRPlayer
+		coCaptains    []models.TTRCoCaptain
+		invitations   []models.Invitation
+		notifications []models.Notification
+	)
+	today := time.Now().Truncate(24 * time.Hour)
+	for i := 0; i < 2000; i++ {
+		captain := users[i%len(users)]
+		ttr := models.TTR{
+			ID:              uuid.New(),
+			CourseName:      "Torrey Pines",
+			TeeDate:         today.AddDate(0, 0, i%60-30),
+			TeeTime:         time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: captain.ID,
+			CaptainUserID:   captain.ID,
+			Status:          []models.TTRStatus{models.TTRStatusOpen, models.TTRStatusConfirmed, models.TTRStatusCompleted}[i%3],
+			Visibility:      models.TTRVisibilityPrivate,
+		}
+		ttrs = append(ttrs, ttr)
+		for j := 0; j < 4; j++ {
+			players = append(players, models.TTRPlayer{TTRID: ttr.ID, UserID: users[(i+j*7)%len(users)].ID, Status: models.TTRPlayerStatusConfirmed})
+		}
+		coCaptains = append(coCaptains, models.TTRCoCaptain{TTRID: ttr.ID, UserID: users[(i+3)%len(users)].ID})
+		for j := 0; j < 3; j++ {
+			invitations = append(invitations, models.Invitation{
+				TTRID:         ttr.ID,
+				InviterUserID: captain.ID,
+				InviteeUserID: users[(i+j*11+1)%len(users)].ID,
+				Status:        models.InvitationStatusPending,
+			})
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:  users[(i+5)%len(users)].ID,
+			Type:    models.NotificationTypeInvitation,
+			Title:   "Invitation",
+			Message: "You were invited",
+			IsRead:  i%2 == 0,
+		})
+	}
+	require.NoError(tb, db.CreateInBatches(ttrs, 500).Error)
+	require.NoError(tb, db.CreateInBatches(players, 500).Error)
+	require.NoError(tb, db.CreateInBatches(coCaptains, 500).Error)
+	require.NoError(tb, db.CreateInBatches(invitations, 500).Error)
+	require.NoError(tb, db.CreateInBatches(notifications, 500).Error)
+
+	return users[0].ID
+}
+
+func dropQueryIndexes(tb testing.TB, db *gorm.DB) {
+	tb.Helper()
+	for _, idx := range queryIndexes {
+		require.NoError(tb, db.Migrator().DropIndex(idx.model, idx.name))
+	}
+}
+
+// queryPlan returns SQLite's plan for the query as one string.
+func queryPlan(t *testing.T, db *gorm.DB, query string, args ...interface{}) string {
+	t.Helper()
+	rows, err := db.Raw("EXPLAIN QUERY PLAN "+query, args...).Rows()
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var steps []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		require.NoError(t, rows.Scan(&id, &parent, &notUsed, &detail))
+		steps = append(steps, detail)
+	}
+	require.NoError(t, rows.Err())
+	return strings.Join(steps, "\n")
+}
+
+func TestQueryIndexes_CreatedByAutoMigrate(t *testing.T) {
+	db := setupQueryIndexDB(t)
+	for _, idx := range queryIndexes {
+		assert.True(t, db.Migrator().HasIndex(idx.model, idx.name), idx.name)
+	}
+}
+
+func TestQueryIndexes_UsedByHotQueries(t *testing.T) {
+	db := setupQueryIndexDB(t)
+	userID := seedQueryRows(t, db)
+	ttrID := uuid.New()
+
+	plans := []struct {
+		index string
+		plan  string
+	}{
+		{"idx_ttr_players_user_ttr", queryPlan(t, db, "SELECT ttr_id FROM ttr_players WHERE user_id = ?", userID)},
+		{"idx_ttr_co_captains_user_ttr", queryPlan(t, db, "SELECT ttr_id FROM ttr_co_captains WHERE user_id = ?", userID)},
+		{"idx_invitations_ttr_invitee_status", queryPlan(t, db, "SELECT * FROM invitations WHERE ttr_id = ? AND invitee_user_id = ?", ttrID, userID)},
+		{"idx_ttrs_status_tee_date", queryPlan(t, db, "SELECT * FROM ttrs WHERE status = ? AND tee_date >= ?", models.TTRStatusOpen, time.Now())},
+		{"idx_ttrs_captain_tee_date", queryPlan(t, db, "SELECT * FROM ttrs WHERE captain_user_id = ? AND LOWER(course_name) = LOWER(?) AND status <> ? AND tee_date BETWEEN ? AND ?", userID, "Torrey Pines", models.TTRStatusCancelled, time.Now(), time.Now().AddDate(0, 0, 1))},
+		{"idx_notifications_user_read_created", queryPlan(t, db, "SELECT * FROM notifications WHERE user_id = ? AND is_read = ? ORDER BY created_at DESC", userID, false)},
+		{"idx_notifications_digest", queryPlan(t, db, "SELECT * FROM notifications WHERE user_id = ? AND type = ? AND target_type = ? AND target_id = ? AND created_at >= ? ORDER BY created_at DESC", userID, models.NotificationTypePlayerJoined, "ttr", ttrID, time.Now().Add(-time.Hour))},
+	}
+	for _, p := range plans {
+		assert.Contains(t, p.plan, p.index)
+	}
+}
+
+// benchmarkWithAndWithoutIndexes runs query against the seeded rows, once with
+// the composite indexes and once without them, for comparing the two.
+func benchmarkWithAndWithoutIndexes(b *testing.B, query func(db *gorm.DB, userID uuid.UUID) error) {
+	for _, indexed := range []bool{true, false} {
+		name := "indexed"
+		if !indexed {
+			name = "unindexed"
+		}
+		b.Run(name, func(b *testing.B) {
+			db := setupQueryIndexDB(b)
+			userID := seedQueryRows(b, db)
+			if !indexed {
+				dropQueryIndexes(b, db)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := query(db, userID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTTRRepository_FindUpcomingByUserID(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(db *gorm.DB, userID uuid.UUID) error {
+		_, err := repository.NewTTRRepository(db).FindUpcomingByUserID(context.Background(), userID, time.Now(), 0)
+		return err
+	})
+}
+
+func BenchmarkInvitationRepository_FindByTTRAndInvitee(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(db *gorm.DB, userID uuid.UUID) error {
+		_, err := repository.NewInvitationRepository(db).FindByTTRAndInvitee(context.Background(), uuid.New(), userID)
+		return err
+	})
+}
+
+func BenchmarkNotificationRepository_FindUnreadByUserID(b *testing.B) {
+	benchmarkWithAndWithoutIndexes(b, func(db *gorm.DB, userID uuid.UUID) error {
+		_, err := repository.NewNotificationRepository(db).FindUnreadByUserID(context.Background(), userID)
+		return err
+	})
+}
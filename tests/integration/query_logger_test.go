@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupQueryLoggerDB(t *testing.T, slowThreshold time.Duration) (*gorm.DB, *zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: database.NewQueryLogger(logger, slowThreshold),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}))
+
+	return db, logger, logs
+}
+
+func TestQueryLogger_SlowQuery(t *testing.T) {
+	db, _, logs := setupQueryLoggerDB(t, time.Nanosecond)
+
+	user := &models.User{Email: "golfer@example.com", PasswordHash: "hash", FirstName: "Slow", LastName: "Query"}
+	require.NoError(t, db.Create(user).Error)
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-123")
+	found, err := repository.NewUserRepository(db).FindByEmail(ctx, "golfer@example.com")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	var entry *observer.LoggedEntry
+	for _, e := range logs.FilterMessage("slow query").All() {
+		if e.ContextMap()["request_id"] == "req-123" {
+			e := e
+			entry = &e
+		}
+	}
+	require.NotNil(t, entry, "expected a slow query entry for the request")
+
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	fields := entry.ContextMap()
+	sql, ok := fields["sql"].(string)
+	require.True(t, ok)
+	assert.Contains(t, sql, "users")
+	assert.NotContains(t, sql, "golfer@example.com", "parameters are redacted")
+	assert.Equal(t, int64(1), fields["rows_affected"])
+	assert.Contains(t, fields, "duration")
+}
+
+func TestQueryLogger_FastQueryNotLogged(t *testing.T) {
+	db, _, logs := setupQueryLoggerDB(t, time.Hour)
+
+	_, err := repository.NewUserRepository(db).FindByEmail(context.Background(), "nobody@example.com")
+	require.NoError(t, err)
+
+	assert.Zero(t, logs.FilterMessage("slow query").Len())
+	assert.Zero(t, logs.FilterMessage("query").Len(), "queries are only logged in Info mode")
+}
+
+func TestQueryLogger_RequestQueryCount(t *testing.T) {
+	db, logger, logs := setupQueryLoggerDB(t, time.Hour)
+	userRepo := repository.NewUserRepository(db)
+
+	handler := middleware.Logging(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = userRepo.FindByEmail(r.Context(), "first@example.com")
+		_, _ = userRepo.FindByEmail(r.Context(), "second@example.com")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	completed := logs.FilterMessage("request completed").All()
+	require.Len(t, completed, 1)
+	assert.Equal(t, int64(2), completed[0].ContextMap()["db_queries"])
+}
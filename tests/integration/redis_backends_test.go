@@ -0,0 +1,133 @@
+//go:build redis
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/redis"
+)
+
+// Run with: go test -tags redis ./tests/integration/
+
+const backendWindow = 100 * time.Millisecond
+
+type backend struct {
+	name    string
+	cache   cache.Cache
+	limiter ratelimit.RateLimiter
+	// advance moves the backend's clock past d so TTLs expire.
+	advance func(d time.Duration)
+}
+
+func setupBackends(t *testing.T, limit int) []backend {
+	mr := miniredis.RunT(t)
+
+	client, err := redis.NewClient(&config.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return []backend{
+		{
+			name:    "memory",
+			cache:   cache.NewMemoryCache(),
+			limiter: ratelimit.NewMemoryLimiter(limit, backendWindow),
+			advance: func(d time.Duration) { time.Sleep(d) },
+		},
+		{
+			name:    "redis",
+			cache:   cache.NewRedisCache(client, "test:cache:"),
+			limiter: ratelimit.NewRedisLimiter(client, "test:ratelimit:", limit, backendWindow),
+			advance: mr.FastForward,
+		},
+	}
+}
+
+func TestRedisClient_HealthCheck(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	client, err := redis.NewClient(&config.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.NoError(t, client.HealthCheck(context.Background()))
+
+	mr.Close()
+	assert.Error(t, client.HealthCheck(context.Background()))
+}
+
+func TestBackends_RateLimiterCounters(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupBackends(t, 3) {
+		t.Run(b.name, func(t *testing.T) {
+			for i := 1; i <= 3; i++ {
+				result, err := b.limiter.Allow(ctx, "client-a")
+				require.NoError(t, err)
+				assert.True(t, result.Allowed, "request %d", i)
+				assert.Equal(t, 3, result.Limit)
+				assert.Equal(t, 3-i, result.Remaining)
+				assert.True(t, result.ResetAt.After(time.Now()))
+			}
+
+			result, err := b.limiter.Allow(ctx, "client-a")
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+
+			result, err = b.limiter.Allow(ctx, "client-b")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed, "keys are counted independently")
+
+			b.advance(backendWindow + 20*time.Millisecond)
+
+			result, err = b.limiter.Allow(ctx, "client-a")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed, "window resets after it expires")
+			assert.Equal(t, 2, result.Remaining)
+		})
+	}
+}
+
+func TestBackends_CacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupBackends(t, 1) {
+		t.Run(b.name, func(t *testing.T) {
+			_, err := b.cache.Get(ctx, "ttr:1")
+			assert.ErrorIs(t, err, cache.ErrMiss)
+
+			require.NoError(t, b.cache.Set(ctx, "ttr:1", []byte("v1"), time.Minute))
+			require.NoError(t, b.cache.Set(ctx, "ttr:2", []byte("v2"), time.Minute))
+
+			value, err := b.cache.Get(ctx, "ttr:1")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("v1"), value)
+
+			require.NoError(t, b.cache.Set(ctx, "ttr:1", []byte("v1-updated"), time.Minute))
+			value, err = b.cache.Get(ctx, "ttr:1")
+			require.NoError(t, err)
+			assert.Equal(t, []byte("v1-updated"), value)
+
+			require.NoError(t, b.cache.Delete(ctx, "ttr:1", "ttr:2"))
+			for _, key := range []string{"ttr:1", "ttr:2"} {
+				_, err = b.cache.Get(ctx, key)
+				assert.ErrorIs(t, err, cache.ErrMiss, fmt.Sprintf("%s after delete", key))
+			}
+
+			require.NoError(t, b.cache.Set(ctx, "ttr:3", []byte("v3"), backendWindow))
+			b.advance(backendWindow + 20*time.Millisecond)
+			_, err = b.cache.Get(ctx, "ttr:3")
+			assert.ErrorIs(t, err, cache.ErrMiss, "entries expire after their TTL")
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+// repairRestoreWindow is how long the repair tests let a deleted TTR be
+// restored.
+const repairRestoreWindow = 7 * 24 * time.Hour
+
+// repairFixture stores rows directly, so the tests can write the
+// inconsistencies the services never would.
+type repairFixture struct {
+	t  *testing.T
+	db *gorm.DB
+}
+
+func newRepairFixture(t *testing.T) *repairFixture {
+	return &repairFixture{t: t, db: setupTTRTestDB(t)}
+}
+
+func (f *repairFixture) user(name string) uuid.UUID {
+	user := &models.User{Email: name + "@example.com", PasswordHash: "hash", FirstName: name, LastName: "Golfer"}
+	require.NoError(f.t, f.db.Create(user).Error)
+	return user.ID
+}
+
+// ttr stores an OPEN TTR for four teeing off days from today.
+func (f *repairFixture) ttr(captainID uuid.UUID, days int) *models.TTR {
+	ttr := &models.TTR{
+		CourseName:      "Pebble Beach",
+		TeeDate:         time.Now().AddDate(0, 0, days).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captainID,
+		CaptainUserID:   captainID,
+		Status:          models.TTRStatusOpen,
+	}
+	require.NoError(f.t, f.db.Create(ttr).Error)
+	return ttr
+}
+
+// deleteTTR soft-deletes the TTR as if that happened at deletedAt.
+func (f *repairFixture) deleteTTR(ttrID uuid.UUID, deletedAt time.Time) {
+	require.NoError(f.t, f.db.Unscoped().Model(&models.TTR{}).Where("id = ?", ttrID).UpdateColumn("deleted_at", deletedAt).Error)
+}
+
+func (f *repairFixture) player(ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) {
+	require.NoError(f.t, f.db.Create(&models.TTRPlayer{TTRID: ttrID, UserID: userID, Status: status}).Error)
+}
+
+func (f *repairFixture) coCaptain(ttrID uuid.UUID, userID uuid.UUID) {
+	require.NoError(f.t, f.db.Create(&models.TTRCoCaptain{TTRID: ttrID, UserID: userID, Permissions: models.CoCaptainPermissionInvite}).Error)
+}
+
+func (f *repairFixture) invitation(ttrID uuid.UUID, inviterID uuid.UUID, inviteeID uuid.UUID, status models.InvitationStatus) uuid.UUID {
+	invitation := &models.Invitation{TTRID: ttrID, InviterUserID: inviterID, InviteeUserID: inviteeID, Status: status}
+	require.NoError(f.t, f.db.Create(invitation).Error)
+	return invitation.ID
+}
+
+func (f *repairFixture) notification(userID uuid.UUID, targetType string, targetID *uuid.UUID) uuid.UUID {
+	notification := &models.Notification{UserID: userID, Type: models.NotificationTypeTTRUpdate, Title: "Update", Message: "Something changed"}
+	if targetType != "" {
+		notification.TargetType = &targetType
+	}
+	notification.TargetID = targetID
+	require.NoError(f.t, f.db.Create(notification).Error)
+	return notification.ID
+}
+
+// run runs check, first without and then with Apply, and returns both
+// results.
+func (f *repairFixture) run(check func(context.Context, *gorm.DB, database.RepairOptions) (database.RepairCheck, error)) (database.RepairCheck, database.RepairCheck) {
+	opts := database.RepairOptions{Now: time.Now(), RestoreWindow: repairRestoreWindow}
+	dryRun, err := check(context.Background(), f.db, opts)
+	require.NoError(f.t, err)
+	assert.Zero(f.t, dryRun.Fixed, "nothing is fixed without apply")
+
+	opts.Apply = true
+	applied, err := check(context.Background(), f.db, opts)
+	require.NoError(f.t, err)
+	return dryRun, applied
+}
+
+func TestCheckCoCaptainsNotPlayers(t *testing.T) {
+	f := newRepairFixture(t)
+	captain, player, orphan := f.user("captain"), f.user("player"), f.user("orphan")
+
+	ttr := f.ttr(captain, 7)
+	f.player(ttr.ID, captain, models.TTRPlayerStatusConfirmed)
+	f.player(ttr.ID, player, models.TTRPlayerStatusMaybe)
+	f.coCaptain(ttr.ID, player)
+	f.coCaptain(ttr.ID, orphan)
+
+	deleted := f.ttr(captain, 7)
+	f.coCaptain(deleted.ID, orphan)
+	f.deleteTTR(deleted.ID, time.Now().Add(-time.Hour))
+
+	var coCaptains int64
+	require.NoError(t, f.db.Model(&models.TTRCoCaptain{}).Count(&coCaptains).Error)
+	require.Equal(t, int64(3), coCaptains)
+
+	dryRun, applied := f.run(database.CheckCoCaptainsNotPlayers)
+	assert.Equal(t, []database.RepairIssue{{TTRID: &ttr.ID, UserID: &orphan, Problem: "co-captain isn't on the roster"}}, dryRun.Issues,
+		"co-captains of deleted TTRs are kept for their restore")
+	assert.Equal(t, dryRun.Issues, applied.Issues)
+	assert.Equal(t, 1, applied.Fixed)
+
+	var left []uuid.UUID
+	require.NoError(t, f.db.Model(&models.TTRCoCaptain{}).Where("ttr_id = ?", ttr.ID).Pluck("user_id", &left).Error)
+	assert.Equal(t, []uuid.UUID{player}, left)
+
+	again, _ := f.run(database.CheckCoCaptainsNotPlayers)
+	assert.Empty(t, again.Issues)
+}
+
+func TestCheckPlayersOnDeletedTTRs(t *testing.T) {
+	f := newRepairFixture(t)
+	captain, player := f.user("captain"), f.user("player")
+
+	live := f.ttr(captain, 7)
+	f.player(live.ID, player, models.TTRPlayerStatusConfirmed)
+
+	restorable := f.ttr(captain, 7)
+	f.player(restorable.ID, player, models.TTRPlayerStatusConfirmed)
+	f.deleteTTR(restorable.ID, time.Now().Add(-repairRestoreWindow+time.Hour))
+
+	expired := f.ttr(captain, 7)
+	f.player(expired.ID, player, models.TTRPlayerStatusConfirmed)
+	f.deleteTTR(expired.ID, time.Now().Add(-repairRestoreWindow-time.Hour))
+
+	missing := uuid.New()
+	f.player(missing, player, models.TTRPlayerStatusWaitlisted)
+
+	dryRun, applied := f.run(database.CheckPlayersOnDeletedTTRs)
+	want := []database.RepairIssue{
+		{TTRID: &expired.ID, UserID: &player, Problem: "TTR was deleted and can no longer be restored"},
+		{TTRID: &missing, UserID: &player, Problem: "TTR doesn't exist"},
+	}
+	assert.ElementsMatch(t, want, dryRun.Issues)
+	assert.Equal(t, 2, applied.Fixed)
+
+	var ttrIDs []uuid.UUID
+	require.NoError(t, f.db.Model(&models.TTRPlayer{}).Pluck("ttr_id", &ttrIDs).Error)
+	assert.ElementsMatch(t, []uuid.UUID{live.ID, restorable.ID}, ttrIDs)
+}
+
+func TestCheckOverfullRosters(t *testing.T) {
+	f := newRepairFixture(t)
+	captain, first, second, waiting := f.user("captain"), f.user("first"), f.user("second"), f.user("waiting")
+
+	overfull := f.ttr(captain, 7)
+	require.NoError(t, f.db.Model(overfull).UpdateColumn("max_players", 2).Error)
+	f.player(overfull.ID, captain, models.TTRPlayerStatusConfirmed)
+	f.player(overfull.ID, first, models.TTRPlayerStatusMaybe)
+	f.player(overfull.ID, waiting, models.TTRPlayerStatusWaitlisted)
+	require.NoError(t, f.db.Create(&models.TTRGuest{TTRID: overfull.ID, DisplayName: "Walk-on", AddedByUserID: captain}).Error)
+
+	full := f.ttr(captain, 7)
+	require.NoError(t, f.db.Model(full).UpdateColumn("max_players", 2).Error)
+	f.player(full.ID, captain, models.TTRPlayerStatusConfirmed)
+	f.player(full.ID, first, models.TTRPlayerStatusConfirmed)
+	f.player(full.ID, waiting, models.TTRPlayerStatusWaitlisted)
+
+	deleted := f.ttr(captain, 7)
+	require.NoError(t, f.db.Model(deleted).UpdateColumn("max_players", 1).Error)
+	f.player(deleted.ID, captain, models.TTRPlayerStatusConfirmed)
+	f.player(deleted.ID, second, models.TTRPlayerStatusConfirmed)
+	f.deleteTTR(deleted.ID, time.Now())
+
+	dryRun, applied := f.run(database.CheckOverfullRosters)
+	assert.True(t, dryRun.ReportOnly)
+	assert.Equal(t, []database.RepairIssue{{TTRID: &overfull.ID, Problem: "roster takes 3 slots of 2"}}, dryRun.Issues,
+		"waitlisted players don't take a slot and guests do")
+	assert.Equal(t, dryRun.Issues, applied.Issues)
+	assert.Zero(t, applied.Fixed, "overfull rosters are only reported")
+
+	var players int64
+	require.NoError(t, f.db.Model(&models.TTRPlayer{}).Where("ttr_id = ?", overfull.ID).Count(&players).Error)
+	assert.Equal(t, int64(3), players)
+}
+
+func TestCheckStalePendingInvitations(t *testing.T) {
+	f := newRepairFixture(t)
+	captain, invitee := f.user("captain"), f.user("invitee")
+
+	upcoming := f.ttr(captain, 7)
+	cancelled := f.ttr(captain, 7)
+	require.NoError(t, f.db.Model(cancelled).UpdateColumn("status", models.TTRStatusCancelled).Error)
+	deleted := f.ttr(captain, 7)
+	f.deleteTTR(deleted.ID, time.Now())
+	yesterday := f.ttr(captain, -1)
+	past := f.ttr(captain, -3)
+	missing := uuid.New()
+
+	open := f.invitation(upcoming.ID, captain, invitee, models.InvitationStatusPending)
+	onYesterday := f.invitation(yesterday.ID, captain, invitee, models.InvitationStatusPending)
+	answered := f.invitation(cancelled.ID, captain, captain, models.InvitationStatusYes)
+	onCancelled := f.invitation(cancelled.ID, captain, invitee, models.InvitationStatusPending)
+	onDeleted := f.invitation(deleted.ID, captain, invitee, models.InvitationStatusPending)
+	onPast := f.invitation(past.ID, captain, invitee, models.InvitationStatusPending)
+	onMissing := f.invitation(missing, captain, invitee, models.InvitationStatusPending)
+
+	dryRun, applied := f.run(database.CheckStalePendingInvitations)
+	assert.ElementsMatch(t, []database.RepairIssue{
+		{TTRID: &cancelled.ID, InvitationID: &onCancelled, Problem: "TTR was cancelled"},
+		{TTRID: &deleted.ID, InvitationID: &onDeleted, Problem: "TTR was deleted"},
+		{TTRID: &past.ID, InvitationID: &onPast, Problem: "TTR has already been played"},
+		{TTRID: &missing, InvitationID: &onMissing, Problem: "TTR doesn't exist"},
+	}, dryRun.Issues)
+	assert.Equal(t, 4, applied.Fixed)
+
+	statuses := map[uuid.UUID]models.InvitationStatus{}
+	var invitations []models.Invitation
+	require.NoError(t, f.db.Find(&invitations).Error)
+	for _, invitation := range invitations {
+		statuses[invitation.ID] = invitation.Status
+	}
+	assert.Equal(t, map[uuid.UUID]models.InvitationStatus{
+		open:        models.InvitationStatusPending,
+		onYesterday: models.InvitationStatusPending,
+		answered:    models.InvitationStatusYes,
+		onCancelled: models.InvitationStatusCanceled,
+		onDeleted:   models.InvitationStatusCanceled,
+		onPast:      models.InvitationStatusExpired,
+		onMissing:   models.InvitationStatusCanceled,
+	}, statuses)
+}
+
+func TestCheckOrphanNotifications(t *testing.T) {
+	f := newRepairFixture(t)
+	captain := f.user("captain")
+
+	ttr := f.ttr(captain, 7)
+	deleted := f.ttr(captain, 7)
+	f.deleteTTR(deleted.ID, time.Now().AddDate(0, -1, 0))
+	invitationID := f.invitation(ttr.ID, captain, captain, models.InvitationStatusYes)
+	gone, goneInvitation, unknown := uuid.New(), uuid.New(), uuid.New()
+
+	kept := []uuid.UUID{
+		f.notification(captain, "ttr", &ttr.ID),
+		f.notification(captain, "ttr", &deleted.ID),
+		f.notification(captain, "invitation", &invitationID),
+		f.notification(captain, "user", &captain),
+		f.notification(captain, "", nil),
+		f.notification(captain, "scorecard", &unknown),
+	}
+	aboutGoneTTR := f.notification(captain, "ttr", &gone)
+	aboutGoneInvitation := f.notification(captain, "invitation", &goneInvitation)
+
+	dryRun, applied := f.run(database.CheckOrphanNotifications)
+	assert.Equal(t, []database.RepairIssue{
+		{NotificationID: &aboutGoneInvitation, Problem: "invitation doesn't exist"},
+		{NotificationID: &aboutGoneTTR, Problem: "ttr doesn't exist"},
+	}, dryRun.Issues, "soft-deleted targets and unknown target types are left alone")
+	assert.Equal(t, 2, applied.Fixed)
+
+	var left []uuid.UUID
+	require.NoError(t, f.db.Model(&models.Notification{}).Pluck("id", &left).Error)
+	assert.ElementsMatch(t, kept, left)
+}
+
+func TestRepairRosters(t *testing.T) {
+	f := newRepairFixture(t)
+	captain, orphan := f.user("captain"), f.user("orphan")
+	ttr := f.ttr(captain, 7)
+	f.coCaptain(ttr.ID, orphan)
+	f.invitation(uuid.New(), captain, orphan, models.InvitationStatusPending)
+
+	report, err := database.RepairRosters(context.Background(), f.db, database.RepairOptions{Now: time.Now(), RestoreWindow: repairRestoreWindow})
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+
+	names := make([]string, 0, len(report.Checks))
+	found := 0
+	for _, check := range report.Checks {
+		names = append(names, check.Name)
+		found += len(check.Issues)
+	}
+	assert.Equal(t, []string{"co_captains_not_players", "players_on_deleted_ttrs", "overfull_rosters", "stale_pending_invitations", "orphan_notifications"}, names)
+	assert.Equal(t, 2, found)
+
+	report, err = database.RepairRosters(context.Background(), f.db, database.RepairOptions{Apply: true, Now: time.Now(), RestoreWindow: repairRestoreWindow})
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+
+	report, err = database.RepairRosters(context.Background(), f.db, database.RepairOptions{Now: time.Now(), RestoreWindow: repairRestoreWindow})
+	require.NoError(t, err)
+	for _, check := range report.Checks {
+		assert.Empty(t, check.Issues, check.Name)
+	}
+}
@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+)
+
+func TestReportAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	reporterToken, reporterID := registerTestUser(t, api, "reporter@example.com", "Reporter")
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	_, otherID := registerTestUser(t, api, "other@example.com", "Other")
+	adminToken, err := jwt.GenerateAccessToken(uuid.New(), "admin@example.com", models.UserRoleAdmin, "test-secret", time.Minute)
+	require.NoError(t, err)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"force":       true,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/messages", captainToken, map[string]string{"body": "get lost"})
+	require.Equal(t, http.StatusCreated, code)
+	var message handler.MessageResponse
+	require.NoError(t, json.Unmarshal(env.Data, &message))
+
+	report := func(token string, targetType string, targetID string, category string) (int, apiEnvelope) {
+		t.Helper()
+		return doJSON(t, api, "POST", "/api/v1/reports", token, map[string]string{
+			"target_type": targetType,
+			"target_id":   targetID,
+			"category":    category,
+			"details":     "<b>Rude</b> in   chat",
+		})
+	}
+	listReports := func(query string) []handler.ReportResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/admin/reports"+query, adminToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var page handler.PageResponse[handler.ReportResponse]
+		require.NoError(t, json.Unmarshal(env.Data, &page))
+		return page.Items
+	}
+
+	var userReport handler.ReportResponse
+	t.Run("duplicate open reports are refused", func(t *testing.T) {
+		code, env := report(reporterToken, models.ReportTargetUser, captainID, models.ReportCategoryHarassment)
+		require.Equal(t, http.StatusCreated, code)
+		require.NoError(t, json.Unmarshal(env.Data, &userReport))
+		assert.Equal(t, models.ReportStatusOpen, userReport.Status)
+		assert.Equal(t, reporterID, userReport.ReporterUserID)
+		assert.Equal(t, "Rude in chat", userReport.Details)
+
+		code, env = report(reporterToken, models.ReportTargetUser, captainID, models.ReportCategorySpam)
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "REPORT_ALREADY_OPEN", env.Error.Code)
+
+		// The same target can be reported by someone else, and other
+		// targets by the same reporter.
+		code, _ = report(captainToken, models.ReportTargetUser, otherID, models.ReportCategorySpam)
+		assert.Equal(t, http.StatusCreated, code)
+		code, _ = report(reporterToken, models.ReportTargetMessage, message.ID, models.ReportCategoryHarassment)
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	t.Run("targets must exist and not be the reporter", func(t *testing.T) {
+		code, env := report(reporterToken, models.ReportTargetUser, reporterID, models.ReportCategoryOther)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "CANNOT_REPORT_SELF", env.Error.Code)
+		code, env = report(captainToken, models.ReportTargetMessage, message.ID, models.ReportCategoryOther)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "CANNOT_REPORT_SELF", env.Error.Code)
+		code, env = report(reporterToken, models.ReportTargetTTR, uuid.NewString(), models.ReportCategoryOther)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "REPORT_TARGET_NOT_FOUND", env.Error.Code)
+		code, env = report(reporterToken, "COURSE", ttr.ID, "RUDE")
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.Equal(t, "VALIDATION_ERROR", env.Error.Code)
+	})
+
+	t.Run("reports are limited per day", func(t *testing.T) {
+		// Two reports so far; the third fills the day.
+		code, _ := report(reporterToken, models.ReportTargetTTR, ttr.ID, models.ReportCategorySpam)
+		require.Equal(t, http.StatusCreated, code)
+		code, env := report(reporterToken, models.ReportTargetUser, otherID, models.ReportCategorySpam)
+		assert.Equal(t, http.StatusTooManyRequests, code)
+		assert.Equal(t, "REPORT_LIMIT_REACHED", env.Error.Code)
+	})
+
+	t.Run("only admins review reports", func(t *testing.T) {
+		code, _ := doJSON(t, api, "GET", "/api/v1/admin/reports", reporterToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/reports/"+userReport.ID, reporterToken, map[string]string{"status": models.ReportStatusDismissed})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("admins resolve and dismiss open reports", func(t *testing.T) {
+		open := listReports("?status=OPEN")
+		require.Len(t, open, 4)
+		assert.Equal(t, userReport.ID, open[0].ID, "oldest first")
+		assert.Equal(t, "Reporter Tester", open[0].ReporterName)
+
+		code, env := doJSON(t, api, "PUT", "/api/v1/admin/reports/"+userReport.ID, adminToken, map[string]string{"status": models.ReportStatusResolved, "notes": "Warned the captain"})
+		require.Equal(t, http.StatusOK, code)
+		var resolved handler.ReportResponse
+		require.NoError(t, json.Unmarshal(env.Data, &resolved))
+		assert.Equal(t, models.ReportStatusResolved, resolved.Status)
+		assert.Equal(t, "Warned the captain", resolved.Resolution)
+		assert.NotNil(t, resolved.ResolvedByUserID)
+		assert.NotNil(t, resolved.ResolvedAt)
+
+		var notifications []models.Notification
+		require.NoError(t, db.Where("user_id = ? AND type = ?", reporterID, models.NotificationTypeReportResolved).Find(&notifications).Error)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "Report Resolved", notifications[0].Title)
+
+		// A closed report stays closed.
+		code, env = doJSON(t, api, "PUT", "/api/v1/admin/reports/"+userReport.ID, adminToken, map[string]string{"status": models.ReportStatusDismissed})
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "REPORT_CLOSED", env.Error.Code)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/reports/"+open[1].ID, adminToken, map[string]string{"status": models.ReportStatusDismissed})
+		require.Equal(t, http.StatusOK, code)
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/reports/"+open[2].ID, adminToken, map[string]string{"status": models.ReportStatusOpen})
+		assert.Equal(t, http.StatusUnprocessableEntity, code, "reports can't be reopened")
+		code, _ = doJSON(t, api, "PUT", "/api/v1/admin/reports/"+uuid.NewString(), adminToken, map[string]string{"status": models.ReportStatusResolved})
+		assert.Equal(t, http.StatusNotFound, code)
+
+		assert.Len(t, listReports("?status=OPEN"), 2)
+		resolvedReports := listReports("?status=RESOLVED")
+		require.Len(t, resolvedReports, 1)
+		assert.Equal(t, userReport.ID, resolvedReports[0].ID)
+		assert.Len(t, listReports("?status=DISMISSED"), 1)
+		assert.Len(t, listReports(""), 4)
+		code, _ = doJSON(t, api, "GET", "/api/v1/admin/reports?status=CLOSED", adminToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
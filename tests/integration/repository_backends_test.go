@@ -0,0 +1,2842 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"gorm.io/gorm"
+)
+
+// repositoryBackend bundles one implementation of every repository. The same
+// tests run against the GORM repositories on SQLite and the in-memory fakes,
+// so the fakes can't drift from the behavior services rely on.
+type repositoryBackend struct {
+	name          string
+	users         repository.UserRepository
+	ttrs          repository.TTRRepository
+	invitations   repository.InvitationRepository
+	organizations repository.OrganizationRepository
+	messages      repository.MessageRepository
+	notifications repository.NotificationRepository
+	refreshTokens repository.RefreshTokenRepository
+	leagues       repository.LeagueRepository
+	tournaments   repository.TournamentRepository
+	suggestions   repository.SuggestionRepository
+	inviteLinks   repository.InviteLinkRepository
+	actionItems   repository.ActionItemRepository
+	webhooks      repository.WebhookRepository
+	slack         repository.SlackRepository
+	apiTokens     repository.APITokenRepository
+	impersonation repository.ImpersonationRepository
+	auditLog      repository.AuditLogRepository
+	featureFlags  repository.FeatureFlagRepository
+	ttrEvents     repository.TTREventRepository
+	outbox        repository.OutboxRepository
+	stats         repository.StatsRepository
+	suppressions  repository.EmailSuppressionRepository
+	security      repository.SecurityEventRepository
+	history       repository.HistoryRepository
+	reports       repository.ReportRepository
+	schedule      repository.ScheduleRepository
+	templates     repository.InvitationTemplateRepository
+	transactor    repository.Transactor
+}
+
+func setupRepositoryBackends(t *testing.T) []repositoryBackend {
+	db := setupTTRTestDB(t)
+	if err := db.AutoMigrate(&models.Notification{}); err != nil {
+		t.Fatalf("Failed to migrate notifications: %v", err)
+	}
+	store := memory.NewStore()
+
+	return []repositoryBackend{
+		{
+			name:          "gorm",
+			users:         repository.NewUserRepository(db),
+			ttrs:          repository.NewTTRRepository(db),
+			invitations:   repository.NewInvitationRepository(db),
+			organizations: repository.NewOrganizationRepository(db),
+			messages:      repository.NewMessageRepository(db),
+			notifications: repository.NewNotificationRepository(db),
+			refreshTokens: repository.NewRefreshTokenRepository(db),
+			leagues:       repository.NewLeagueRepository(db),
+			tournaments:   repository.NewTournamentRepository(db),
+			suggestions:   repository.NewSuggestionRepository(db),
+			inviteLinks:   repository.NewInviteLinkRepository(db),
+			actionItems:   repository.NewActionItemRepository(db),
+			webhooks:      repository.NewWebhookRepository(db),
+			slack:         repository.NewSlackRepository(db),
+			apiTokens:     repository.NewAPITokenRepository(db),
+			impersonation: repository.NewImpersonationRepository(db),
+			auditLog:      repository.NewAuditLogRepository(db),
+			featureFlags:  repository.NewFeatureFlagRepository(db),
+			ttrEvents:     repository.NewTTREventRepository(db),
+			outbox:        repository.NewOutboxRepository(db),
+			stats:         repository.NewStatsRepository(db),
+			suppressions:  repository.NewEmailSuppressionRepository(db),
+			security:      repository.NewSecurityEventRepository(db),
+			history:       repository.NewHistoryRepository(db),
+			reports:       repository.NewReportRepository(db),
+			schedule:      repository.NewScheduleRepository(db),
+			templates:     repository.NewInvitationTemplateRepository(db),
+			transactor:    repository.NewTransactor(db),
+		},
+		{
+			name:          "memory",
+			users:         memory.NewUserRepository(store),
+			ttrs:          memory.NewTTRRepository(store),
+			invitations:   memory.NewInvitationRepository(store),
+			organizations: memory.NewOrganizationRepository(store),
+			messages:      memory.NewMessageRepository(store),
+			notifications: memory.NewNotificationRepository(store),
+			refreshTokens: memory.NewRefreshTokenRepository(store),
+			leagues:       memory.NewLeagueRepository(store),
+			tournaments:   memory.NewTournamentRepository(store),
+			suggestions:   memory.NewSuggestionRepository(store),
+			inviteLinks:   memory.NewInviteLinkRepository(store),
+			actionItems:   memory.NewActionItemRepository(store),
+			webhooks:      memory.NewWebhookRepository(store),
+			slack:         memory.NewSlackRepository(store),
+			apiTokens:     memory.NewAPITokenRepository(store),
+			impersonation: memory.NewImpersonationRepository(store),
+			auditLog:      memory.NewAuditLogRepository(store),
+			featureFlags:  memory.NewFeatureFlagRepository(store),
+			ttrEvents:     memory.NewTTREventRepository(store),
+			outbox:        memory.NewOutboxRepository(store),
+			stats:         memory.NewStatsRepository(store),
+			suppressions:  memory.NewEmailSuppressionRepository(store),
+			security:      memory.NewSecurityEventRepository(store),
+			history:       memory.NewHistoryRepository(store),
+			reports:       memory.NewReportRepository(store),
+			schedule:      memory.NewScheduleRepository(store),
+			templates:     memory.NewInvitationTemplateRepository(store),
+			transactor:    memory.NewTransactor(store),
+		},
+	}
+}
+
+func (b repositoryBackend) createUser(t *testing.T, firstName string) *models.User {
+	user := &models.User{
+		Email:        firstName + "-" + uuid.NewString() + "@example.com",
+		PasswordHash: "hash",
+		FirstName:    firstName,
+		LastName:     "Golfer",
+	}
+	require.NoError(t, b.users.Create(context.Background(), user))
+	return user
+}
+
+func (b repositoryBackend) createTTR(t *testing.T, captainID uuid.UUID, orgID *uuid.UUID) *models.TTR {
+	ttr := &models.TTR{
+		CourseName:      "Pebble Beach",
+		TeeDate:         time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captainID,
+		CaptainUserID:   captainID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPublic,
+		OrganizationID:  orgID,
+	}
+	require.NoError(t, b.ttrs.Create(context.Background(), ttr))
+	return ttr
+}
+
+func TestRepositoryBackends_Users(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			user := b.createUser(t, "Arnold")
+
+			duplicate := &models.User{Email: user.Email, PasswordHash: "hash", FirstName: "Other", LastName: "Golfer"}
+			assert.ErrorIs(t, b.users.Create(ctx, duplicate), repository.ErrDuplicate)
+
+			found, err := b.users.FindByEmail(ctx, user.Email)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, user.ID, found.ID)
+
+			found.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			require.NoError(t, b.users.Update(ctx, found))
+
+			found, err = b.users.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+
+			found, err = b.users.FindByIDUnscoped(ctx, user.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.True(t, found.IsDeleted())
+		})
+	}
+}
+
+func TestRepositoryBackends_PlayingDays(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			user := b.createUser(t, "Seve")
+
+			require.NoError(t, b.users.SetPlayingDays(ctx, user.ID, []string{"tuesday", "saturday"}))
+			found, err := b.users.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"tuesday", "saturday"}, found.Days())
+
+			// Saving the user leaves its playing days alone.
+			found.PlayingDays = nil
+			found.FirstName = "Severiano"
+			require.NoError(t, b.users.Update(ctx, found))
+			found, err = b.users.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Severiano", found.FirstName)
+			assert.Equal(t, []string{"tuesday", "saturday"}, found.Days())
+
+			require.NoError(t, b.users.SetPlayingDays(ctx, user.ID, nil))
+			found, err = b.users.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Empty(t, found.Days())
+		})
+	}
+}
+
+func TestRepositoryBackends_UserSearch(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Zed")
+			player := b.createUser(t, "Zelda")
+			other := b.createUser(t, "Zeke")
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+
+			users, err := b.users.Search(ctx, repository.UserSearchFilter{Name: "ze", ExcludeUserID: captain.ID, ExcludeTTRID: ttr.ID, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			assert.Equal(t, other.ID, users[0].ID)
+
+			users, err = b.users.Search(ctx, repository.UserSearchFilter{Email: player.Email, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, users, 1)
+			assert.Equal(t, player.ID, users[0].ID)
+		})
+	}
+}
+
+func TestRepositoryBackends_Guests(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			ttr := b.createTTR(t, captain.ID, nil)
+			other := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusMaybe))
+
+			handicap := 14.2
+			guest := &models.TTRGuest{TTRID: ttr.ID, DisplayName: "Uncle Joe", Handicap: &handicap, AddedByUserID: captain.ID}
+			require.NoError(t, b.ttrs.AddGuest(ctx, guest))
+			assert.NotEqual(t, uuid.Nil, guest.ID)
+			require.NoError(t, b.ttrs.AddGuest(ctx, &models.TTRGuest{TTRID: other.ID, DisplayName: "Elsewhere", AddedByUserID: captain.ID}))
+
+			found, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, found.Guests, 1)
+			assert.Equal(t, "Uncle Joe", found.Guests[0].DisplayName)
+			require.NotNil(t, found.Guests[0].Handicap)
+			assert.InDelta(t, 14.2, *found.Guests[0].Handicap, 0.001)
+			assert.Equal(t, 2, found.Headcount())
+
+			counts, err := b.ttrs.CountPlayers(ctx, []uuid.UUID{ttr.ID})
+			require.NoError(t, err)
+			assert.Equal(t, models.PlayerCounts{Total: 2, Confirmed: 1}, counts[ttr.ID], "guests count as confirmed")
+
+			removed, err := b.ttrs.RemoveGuest(ctx, other.ID, guest.ID)
+			require.NoError(t, err)
+			assert.False(t, removed, "guests are only removed from their own TTR")
+			removed, err = b.ttrs.RemoveGuest(ctx, ttr.ID, guest.ID)
+			require.NoError(t, err)
+			assert.True(t, removed)
+
+			found, err = b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Empty(t, found.Guests)
+		})
+	}
+}
+
+func TestRepositoryBackends_TeeSlots(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			ttr := b.createTTR(t, captain.ID, nil)
+			other := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.ReplaceSlots(ctx, other.ID, []models.TeeSlot{{TTRID: other.ID, Number: 1, Capacity: 4}}))
+
+			require.NoError(t, b.ttrs.ReplaceSlots(ctx, ttr.ID, []models.TeeSlot{
+				{TTRID: ttr.ID, Number: 2, OffsetMinutes: 10, Capacity: 4},
+				{TTRID: ttr.ID, Number: 1, OffsetMinutes: 0, Capacity: 2},
+			}))
+			found, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, found.Slots, 2)
+			assert.Equal(t, 1, found.Slots[0].Number, "slots load in tee order")
+			assert.Equal(t, 2, found.Slots[0].Capacity)
+			assert.Equal(t, "08:40", found.SlotTeeTime(found.Slots[1]).Format("15:04"))
+
+			require.NoError(t, b.ttrs.ReplaceSlots(ctx, ttr.ID, []models.TeeSlot{{TTRID: ttr.ID, Number: 1, OffsetMinutes: 5, Capacity: 3}}))
+			found, err = b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, found.Slots, 1)
+			assert.Equal(t, 5, found.Slots[0].OffsetMinutes)
+
+			require.NoError(t, b.ttrs.ReplaceSlots(ctx, ttr.ID, nil))
+			found, err = b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Empty(t, found.Slots)
+
+			found, err = b.ttrs.FindByID(ctx, other.ID)
+			require.NoError(t, err)
+			assert.Len(t, found.Slots, 1, "other TTRs keep their slots")
+		})
+	}
+}
+
+func TestRepositoryBackends_History(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			me := b.createUser(t, "Me")
+			ada := b.createUser(t, "Ada")
+			bea := b.createUser(t, "Bea")
+			cal := b.createUser(t, "Cal")
+			round := func(teeDate time.Time, course string, status models.TTRStatus, players ...*models.User) *models.TTR {
+				ttr := b.createTTR(t, me.ID, nil)
+				ttr.TeeDate = teeDate
+				ttr.CourseName = course
+				ttr.Status = status
+				require.NoError(t, b.ttrs.Update(ctx, ttr))
+				for _, player := range append([]*models.User{me}, players...) {
+					require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+				}
+				return ttr
+			}
+
+			round(from.AddDate(0, 0, -1), "Pebble Beach", models.TTRStatusCompleted, ada, bea)
+			first := round(from, "Pebble Beach", models.TTRStatusCompleted, ada, bea)
+			last := round(to.AddDate(0, 0, -1), "PEBBLE BEACH", models.TTRStatusCompleted, cal, bea)
+			round(to, "Augusta", models.TTRStatusCompleted, ada)
+			round(from.AddDate(0, 5, 0), "Augusta", models.TTRStatusOpen, ada)
+			maybe := round(from.AddDate(0, 6, 0), "Augusta", models.TTRStatusCompleted, ada)
+			require.NoError(t, b.ttrs.UpdatePlayer(ctx, &models.TTRPlayer{TTRID: maybe.ID, UserID: me.ID, Status: models.TTRPlayerStatusMaybe}))
+
+			rounds, err := b.history.Rounds(ctx, me.ID, from, to)
+			require.NoError(t, err)
+			require.Len(t, rounds, 2)
+			assert.Equal(t, first.ID, rounds[0].ID)
+			assert.Equal(t, last.ID, rounds[1].ID)
+			assert.Len(t, rounds[1].Players, 3)
+
+			summary, err := b.history.Summary(ctx, me.ID, from, to)
+			require.NoError(t, err)
+			assert.Equal(t, repository.HistorySummary{Rounds: 2, Courses: 1}, summary)
+
+			partners, err := b.history.TopPartners(ctx, me.ID, from, to, 2)
+			require.NoError(t, err)
+			assert.Equal(t, []repository.PartnerCount{{UserID: bea.ID, Rounds: 2}, {UserID: ada.ID, Rounds: 1}}, partners)
+
+			partners, err = b.history.TopPartners(ctx, ada.ID, time.Time{}, to.AddDate(1, 0, 0), 5)
+			require.NoError(t, err)
+			assert.Equal(t, []repository.PartnerCount{{UserID: me.ID, Rounds: 3}, {UserID: bea.ID, Rounds: 2}}, partners)
+		})
+	}
+}
+
+func TestRepositoryBackends_UserPrivacy(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			hidden := b.createUser(t, "Quorra")
+			hidden.Privacy.HideFromSearch = true
+			require.NoError(t, b.users.Update(ctx, hidden))
+
+			users, err := b.users.Search(ctx, repository.UserSearchFilter{Name: "quorra", Limit: 10})
+			require.NoError(t, err)
+			assert.Empty(t, users, "undiscoverable users aren't found by name")
+
+			users, err = b.users.Search(ctx, repository.UserSearchFilter{Email: hidden.Email, Limit: 10})
+			require.NoError(t, err)
+			require.Len(t, users, 1, "undiscoverable users are still found by email")
+			assert.True(t, users[0].Privacy.HideFromSearch)
+
+			captain := b.createUser(t, "Captain")
+			coCaptain := b.createUser(t, "CoCaptain")
+			player := b.createUser(t, "Player")
+			formerPlayer := b.createUser(t, "Former")
+			stranger := b.createUser(t, "Stranger")
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, ttr.ID, coCaptain.ID, models.CoCaptainPermissions))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+			deleted := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, deleted.ID, formerPlayer.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.Delete(ctx, deleted.ID))
+
+			candidates := []uuid.UUID{player.ID, formerPlayer.ID, stranger.ID}
+			captained, err := b.users.FindCaptainedBy(ctx, captain.ID, candidates)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{player.ID}, captained)
+
+			captained, err = b.users.FindCaptainedBy(ctx, coCaptain.ID, candidates)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{player.ID}, captained, "co-captains captain the roster too")
+
+			captained, err = b.users.FindCaptainedBy(ctx, stranger.ID, candidates)
+			require.NoError(t, err)
+			assert.Empty(t, captained)
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRRoster(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			coCaptain := b.createUser(t, "CoCaptain")
+			player := b.createUser(t, "Player")
+			ttr := b.createTTR(t, captain.ID, nil)
+
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, ttr.ID, coCaptain.ID, models.CoCaptainPermissions))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+			assert.ErrorIs(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed), repository.ErrDuplicate)
+			assert.ErrorIs(t, b.ttrs.AddCoCaptain(ctx, ttr.ID, coCaptain.ID, models.CoCaptainPermissions), repository.ErrDuplicate)
+
+			player.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			require.NoError(t, b.users.Update(ctx, player))
+
+			found, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			require.NotNil(t, found.CaptainUser)
+			assert.Equal(t, captain.ID, found.CaptainUser.ID)
+			assert.True(t, found.HasCoCaptain(coCaptain.ID))
+			require.Len(t, found.Players, 1)
+			require.NotNil(t, found.Players[0].User, "deleted players are still preloaded")
+			assert.True(t, found.Players[0].User.IsDeleted())
+
+			score := 72
+			require.NoError(t, b.ttrs.UpdatePlayer(ctx, &models.TTRPlayer{TTRID: ttr.ID, UserID: player.ID, Status: models.TTRPlayerStatusMaybe, GroupNumber: 1, Score: &score}))
+			players, err := b.ttrs.GetPlayers(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			assert.Equal(t, models.TTRPlayerStatusMaybe, players[0].Status)
+			assert.Equal(t, 1, players[0].GroupNumber)
+			require.NotNil(t, players[0].Score)
+			assert.Equal(t, 72, *players[0].Score)
+
+			found.CourseName = "Changed without saving"
+			found.Players = nil
+			again, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Pebble Beach", again.CourseName)
+			assert.Len(t, again.Players, 1)
+
+			require.Len(t, again.CoCaptains, 1)
+			assert.Equal(t, models.CoCaptainPermissions, again.CoCaptains[0].PermissionList())
+			updated, err := b.ttrs.UpdateCoCaptainPermissions(ctx, ttr.ID, coCaptain.ID, []string{models.CoCaptainPermissionInvite})
+			require.NoError(t, err)
+			assert.True(t, updated)
+			updated, err = b.ttrs.UpdateCoCaptainPermissions(ctx, ttr.ID, player.ID, []string{models.CoCaptainPermissionInvite})
+			require.NoError(t, err)
+			assert.False(t, updated, "players who are not co-captains are left alone")
+			again, err = b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.True(t, again.CoCaptainCan(coCaptain.ID, models.CoCaptainPermissionInvite))
+			assert.False(t, again.CoCaptainCan(coCaptain.ID, models.CoCaptainPermissionEditDetails))
+
+			removed, err := b.ttrs.RemoveCoCaptain(ctx, ttr.ID, coCaptain.ID)
+			require.NoError(t, err)
+			assert.True(t, removed)
+			removed, err = b.ttrs.RemoveCoCaptain(ctx, ttr.ID, coCaptain.ID)
+			require.NoError(t, err)
+			assert.False(t, removed, "removing a co-captain twice is no error")
+
+			require.NoError(t, b.ttrs.Delete(ctx, ttr.ID))
+			found, err = b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+		})
+	}
+}
+
+func TestRepositoryBackends_PlayerStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			ttr := b.createTTR(t, captain.ID, nil)
+
+			var want models.PlayerCounts
+			for _, status := range models.AllPlayerStatuses() {
+				player := b.createUser(t, "Player")
+				require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, status), status)
+				if status.IsActive() {
+					want.Total++
+				}
+				if status == models.TTRPlayerStatusConfirmed {
+					want.Confirmed++
+				}
+			}
+
+			found, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, found.Players, len(models.AllPlayerStatuses()), "every status can be stored")
+			assert.Equal(t, want.Total, found.Headcount())
+
+			counts, err := b.ttrs.CountPlayers(ctx, []uuid.UUID{ttr.ID})
+			require.NoError(t, err)
+			assert.Equal(t, want, counts[ttr.ID], "only active players take a slot")
+		})
+	}
+}
+
+func TestRepositoryBackends_UpcomingAndPast(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			createTTR := func(day int, hour int) uuid.UUID {
+				ttr := b.createTTR(t, captain.ID, nil)
+				ttr.TeeDate = time.Date(2031, 6, day, 0, 0, 0, 0, time.UTC)
+				ttr.TeeTime = time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+				require.NoError(t, b.ttrs.Update(ctx, ttr))
+				return ttr.ID
+			}
+			yesterday := createTTR(9, 15)
+			morning := createTTR(10, 8)
+			afternoon := createTTR(10, 15)
+			late := createTTR(10, 22)
+			tomorrow := createTTR(11, 9)
+
+			for _, tt := range []struct {
+				name     string
+				now      time.Time
+				grace    time.Duration
+				upcoming []uuid.UUID
+				past     []uuid.UUID
+			}{
+				{
+					name:     "tee time earlier today",
+					now:      time.Date(2031, 6, 10, 12, 0, 0, 0, time.UTC),
+					upcoming: []uuid.UUID{afternoon, late, tomorrow},
+					past:     []uuid.UUID{morning, yesterday},
+				},
+				{
+					name:     "within the grace",
+					now:      time.Date(2031, 6, 10, 16, 0, 0, 0, time.UTC),
+					grace:    3 * time.Hour,
+					upcoming: []uuid.UUID{afternoon, late, tomorrow},
+					past:     []uuid.UUID{morning, yesterday},
+				},
+				{
+					name:     "after midnight",
+					now:      time.Date(2031, 6, 11, 0, 30, 0, 0, time.UTC),
+					grace:    3 * time.Hour,
+					upcoming: []uuid.UUID{late, tomorrow},
+					past:     []uuid.UUID{afternoon, morning, yesterday},
+				},
+				{
+					name:     "after midnight without a grace",
+					now:      time.Date(2031, 6, 11, 0, 30, 0, 0, time.UTC),
+					upcoming: []uuid.UUID{tomorrow},
+					past:     []uuid.UUID{late, afternoon, morning, yesterday},
+				},
+			} {
+				upcoming, err := b.ttrs.FindUpcomingByUserID(ctx, captain.ID, tt.now, tt.grace)
+				require.NoError(t, err, tt.name)
+				assert.Equal(t, tt.upcoming, ttrIDs(upcoming), tt.name)
+				past, err := b.ttrs.FindPastByUserID(ctx, captain.ID, tt.now, tt.grace)
+				require.NoError(t, err, tt.name)
+				assert.Equal(t, tt.past, ttrIDs(past), tt.name)
+			}
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRVisibility(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			member := b.createUser(t, "Member")
+			outsider := b.createUser(t, "Outsider")
+
+			org := &models.Organization{Name: "Cypress Point Club", CreatedByUserID: captain.ID}
+			require.NoError(t, b.organizations.Create(ctx, org))
+			require.NoError(t, b.organizations.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: member.ID, Role: models.OrganizationRoleMember}))
+
+			public := b.createTTR(t, captain.ID, nil)
+			private := b.createTTR(t, captain.ID, &org.ID)
+
+			ttrs, err := b.ttrs.FindAll(ctx, member.ID, repository.TTRSearchFilter{Limit: 10})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{public.ID, private.ID}, ttrIDs(ttrs))
+
+			ttrs, err = b.ttrs.FindAll(ctx, outsider.ID, repository.TTRSearchFilter{Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{public.ID}, ttrIDs(ttrs))
+
+			ttrs, err = b.ttrs.FindUpcomingByUserID(ctx, captain.ID, time.Now(), 0)
+			require.NoError(t, err)
+			assert.Len(t, ttrs, 2)
+
+			orgs, err := b.organizations.FindByUserID(ctx, member.ID)
+			require.NoError(t, err)
+			require.Len(t, orgs, 1)
+
+			require.NoError(t, b.organizations.Delete(ctx, org.ID))
+			orgs, err = b.organizations.FindByUserID(ctx, member.ID)
+			require.NoError(t, err)
+			assert.Empty(t, orgs)
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRVisibilitySettings(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			friend := b.createUser(t, "Friend")
+			requester := b.createUser(t, "Requester")
+			clubmate := b.createUser(t, "Clubmate")
+			player := b.createUser(t, "Player")
+			invitee := b.createUser(t, "Invitee")
+			stranger := b.createUser(t, "Stranger")
+
+			_, err := b.users.RequestFriendship(ctx, captain.ID, friend.ID, time.Now())
+			require.NoError(t, err)
+			_, err = b.users.RequestFriendship(ctx, friend.ID, captain.ID, time.Now())
+			require.NoError(t, err)
+			_, err = b.users.RequestFriendship(ctx, requester.ID, captain.ID, time.Now())
+			require.NoError(t, err)
+
+			org := &models.Organization{Name: "Cypress Point Club", CreatedByUserID: captain.ID}
+			require.NoError(t, b.organizations.Create(ctx, org))
+			for _, member := range []*models.User{captain, clubmate} {
+				require.NoError(t, b.organizations.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: member.ID, Role: models.OrganizationRoleMember}))
+			}
+
+			withVisibility := func(visibility string) *models.TTR {
+				ttr := b.createTTR(t, captain.ID, nil)
+				ttr.Visibility = visibility
+				require.NoError(t, b.ttrs.Update(ctx, ttr))
+				require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+				require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}))
+				return ttr
+			}
+			private := withVisibility(models.TTRVisibilityPrivate)
+			friends := withVisibility(models.TTRVisibilityFriends)
+			public := withVisibility(models.TTRVisibilityPublic)
+
+			for _, tc := range []struct {
+				viewer *models.User
+				want   []uuid.UUID
+			}{
+				{captain, []uuid.UUID{private.ID, friends.ID, public.ID}},
+				{player, []uuid.UUID{private.ID, friends.ID, public.ID}},
+				{invitee, []uuid.UUID{private.ID, friends.ID, public.ID}},
+				{friend, []uuid.UUID{friends.ID, public.ID}},
+				{requester, []uuid.UUID{public.ID}},
+				{clubmate, []uuid.UUID{public.ID}},
+				{stranger, []uuid.UUID{public.ID}},
+			} {
+				ttrs, err := b.ttrs.FindAll(ctx, tc.viewer.ID, repository.TTRSearchFilter{Limit: 10})
+				require.NoError(t, err)
+				assert.ElementsMatch(t, tc.want, ttrIDs(ttrs), tc.viewer.FirstName)
+			}
+
+			_, err = b.users.RemoveFriendship(ctx, friend.ID, captain.ID)
+			require.NoError(t, err)
+			ttrs, err := b.ttrs.FindAll(ctx, friend.ID, repository.TTRSearchFilter{Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{public.ID}, ttrIDs(ttrs), "an ended friendship no longer shows FRIENDS TTRs")
+		})
+	}
+}
+
+func TestRepositoryBackends_DeletedTTRs(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			invitee := b.createUser(t, "Invitee")
+			other := b.createUser(t, "Other")
+
+			recent := b.createTTR(t, captain.ID, nil)
+			confirmed := models.TTRStatusConfirmed
+			recent.Status = models.TTRStatusCancelled
+			recent.PreDeleteStatus = &confirmed
+			require.NoError(t, b.ttrs.Update(ctx, recent))
+			require.NoError(t, b.ttrs.Delete(ctx, recent.ID))
+
+			old := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, old.ID, player.ID, models.TTRPlayerStatusConfirmed))
+			invitation := &models.Invitation{TTRID: old.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusCanceled}
+			require.NoError(t, b.invitations.Create(ctx, invitation))
+			message := &models.Message{TTRID: old.ID, UserID: player.ID, Body: "See you there"}
+			require.NoError(t, b.messages.Create(ctx, message))
+			targetType := "ttr"
+			notification := &models.Notification{UserID: player.ID, Type: models.NotificationTypeTTRCancelled, Title: "Cancelled", Message: "Cancelled", TargetType: &targetType, TargetID: &old.ID}
+			require.NoError(t, b.notifications.Create(ctx, notification))
+			old.DeletedAt = gorm.DeletedAt{Time: time.Now().AddDate(0, 0, -10), Valid: true}
+			require.NoError(t, b.ttrs.Update(ctx, old))
+
+			othersTTR := b.createTTR(t, other.ID, nil)
+			require.NoError(t, b.ttrs.Delete(ctx, othersTTR.ID))
+			live := b.createTTR(t, captain.ID, nil)
+
+			cutoff := time.Now().AddDate(0, 0, -7)
+			deleted, err := b.ttrs.FindDeletedByCaptain(ctx, captain.ID, cutoff)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{recent.ID}, ttrIDs(deleted), "only the captain's TTRs deleted within the window")
+
+			found, err := b.ttrs.FindDeletedByID(ctx, recent.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			require.NotNil(t, found.PreDeleteStatus)
+			assert.Equal(t, models.TTRStatusConfirmed, *found.PreDeleteStatus)
+
+			found, err = b.ttrs.FindDeletedByID(ctx, live.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found, "live TTRs aren't in the trash")
+
+			require.NoError(t, b.ttrs.Restore(ctx, recent.ID, models.TTRStatusConfirmed))
+			found, err = b.ttrs.FindByID(ctx, recent.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, models.TTRStatusConfirmed, found.Status)
+			assert.Nil(t, found.PreDeleteStatus)
+
+			ttrs, err := b.ttrs.FindAll(ctx, captain.ID, repository.TTRSearchFilter{Limit: 10})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{recent.ID, live.ID}, ttrIDs(ttrs))
+
+			purged, err := b.ttrs.PurgeDeletedBefore(ctx, cutoff, 10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), purged)
+
+			found, err = b.ttrs.FindDeletedByID(ctx, old.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+			players, err := b.ttrs.GetPlayers(ctx, old.ID)
+			require.NoError(t, err)
+			assert.Empty(t, players)
+			foundInvitation, err := b.invitations.FindByID(ctx, invitation.ID)
+			require.NoError(t, err)
+			assert.Nil(t, foundInvitation)
+			foundMessage, err := b.messages.FindByID(ctx, message.ID)
+			require.NoError(t, err)
+			assert.Nil(t, foundMessage)
+			foundNotification, err := b.notifications.FindByID(ctx, notification.ID)
+			require.NoError(t, err)
+			assert.Nil(t, foundNotification)
+
+			found, err = b.ttrs.FindDeletedByID(ctx, othersTTR.ID)
+			require.NoError(t, err)
+			assert.NotNil(t, found, "TTRs deleted within the window are kept")
+		})
+	}
+}
+
+func TestRepositoryBackends_PurgeDeletedUsers(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			gone := b.createUser(t, "Gone")
+			recent := b.createUser(t, "Recent")
+			captainGone := b.createUser(t, "Departed")
+
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, gone.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, recent.ID, models.TTRPlayerStatusConfirmed))
+			invitation := &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: gone.ID, Status: models.InvitationStatusYes}
+			require.NoError(t, b.invitations.Create(ctx, invitation))
+			require.NoError(t, b.users.SetPlayingDays(ctx, gone.ID, []string{"saturday"}))
+			require.NoError(t, b.refreshTokens.Create(ctx, &models.RefreshToken{UserID: gone.ID, TokenHash: "gone-token", ExpiresAt: time.Now().Add(time.Hour)}))
+			notification := &models.Notification{UserID: gone.ID, Type: models.NotificationTypeTTRUpdate, Title: "Update", Message: "Update"}
+			require.NoError(t, b.notifications.Create(ctx, notification))
+			org := &models.Organization{Name: "Augusta National", CreatedByUserID: captain.ID}
+			require.NoError(t, b.organizations.Create(ctx, org))
+			require.NoError(t, b.organizations.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: gone.ID, Role: models.OrganizationRoleMember}))
+			b.createTTR(t, captainGone.ID, nil)
+
+			softDelete := func(user *models.User, at time.Time) {
+				user.DeletedAt = gorm.DeletedAt{Time: at, Valid: true}
+				require.NoError(t, b.users.Update(ctx, user))
+			}
+			longAgo := time.Now().AddDate(0, 0, -40)
+			softDelete(gone, longAgo)
+			softDelete(captainGone, longAgo)
+			softDelete(recent, time.Now().AddDate(0, 0, -1))
+
+			purged, err := b.users.PurgeDeletedBefore(ctx, time.Now().AddDate(0, 0, -30), 10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), purged)
+
+			found, err := b.users.FindByIDUnscoped(ctx, gone.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+			for _, kept := range []*models.User{recent, captainGone} {
+				found, err := b.users.FindByIDUnscoped(ctx, kept.ID)
+				require.NoError(t, err)
+				assert.NotNil(t, found, kept.FirstName)
+			}
+
+			players, err := b.ttrs.GetPlayers(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			assert.Equal(t, recent.ID, players[0].UserID)
+			foundInvitation, err := b.invitations.FindByID(ctx, invitation.ID)
+			require.NoError(t, err)
+			assert.Nil(t, foundInvitation)
+			token, err := b.refreshTokens.FindByTokenHash(ctx, "gone-token")
+			require.NoError(t, err)
+			assert.Nil(t, token)
+			foundNotification, err := b.notifications.FindByID(ctx, notification.ID)
+			require.NoError(t, err)
+			assert.Nil(t, foundNotification)
+			member, err := b.organizations.FindMember(ctx, org.ID, gone.ID)
+			require.NoError(t, err)
+			assert.Nil(t, member)
+		})
+	}
+}
+
+func TestRepositoryBackends_MergeUsers(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			source := b.createUser(t, "Source")
+			target := b.createUser(t, "Target")
+			friend := b.createUser(t, "Friend")
+
+			captained := b.createTTR(t, source.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, captained.ID, source.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, captained.ID, target.ID, models.TTRPlayerStatusMaybe))
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, captained.ID, target.ID, models.CoCaptainPermissions))
+			shared := b.createTTR(t, friend.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, shared.ID, source.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, shared.ID, source.ID, models.CoCaptainPermissions))
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, shared.ID, target.ID, []string{models.CoCaptainPermissionInvite}))
+
+			pending := &models.Invitation{TTRID: shared.ID, InviterUserID: friend.ID, InviteeUserID: source.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, pending))
+			require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: shared.ID, InviterUserID: friend.ID, InviteeUserID: target.ID, Status: models.InvitationStatusPending}))
+			between := &models.Invitation{TTRID: captained.ID, InviterUserID: source.ID, InviteeUserID: target.ID, Status: models.InvitationStatusYes}
+			require.NoError(t, b.invitations.Create(ctx, between))
+			sent := &models.Invitation{TTRID: captained.ID, InviterUserID: source.ID, InviteeUserID: friend.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, sent))
+			message := &models.Message{TTRID: shared.ID, UserID: source.ID, Body: "See you there"}
+			require.NoError(t, b.messages.Create(ctx, message))
+			notification := &models.Notification{UserID: source.ID, Type: models.NotificationTypeTTRUpdate, Title: "Update", Message: "Update"}
+			require.NoError(t, b.notifications.Create(ctx, notification))
+			require.NoError(t, b.refreshTokens.Create(ctx, &models.RefreshToken{UserID: source.ID, TokenHash: "source-token", ExpiresAt: time.Now().Add(time.Hour)}))
+
+			merge, err := b.users.Merge(ctx, source.ID, target.ID, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, repository.UserMerge{
+				CaptainedTTRs:        1,
+				DroppedCoCaptaincies: 2,
+				RosterSpots:          1,
+				DroppedRosterSpots:   1,
+				Invitations:          1,
+				DroppedInvitations:   2,
+				Notifications:        1,
+				Messages:             1,
+				RevokedRefreshTokens: 1,
+			}, *merge)
+
+			found, err := b.ttrs.FindByID(ctx, captained.ID)
+			require.NoError(t, err)
+			assert.Equal(t, target.ID, found.CaptainUserID)
+			assert.Equal(t, target.ID, found.CreatedByUserID)
+			assert.Empty(t, found.CoCaptains)
+			players, err := b.ttrs.GetPlayers(ctx, captained.ID)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			assert.Equal(t, target.ID, players[0].UserID)
+			assert.Equal(t, models.TTRPlayerStatusMaybe, players[0].Status)
+
+			found, err = b.ttrs.FindByID(ctx, shared.ID)
+			require.NoError(t, err)
+			require.Len(t, found.CoCaptains, 1)
+			assert.Equal(t, target.ID, found.CoCaptains[0].UserID)
+			assert.Equal(t, models.CoCaptainPermissionInvite, found.CoCaptains[0].Permissions)
+			players, err = b.ttrs.GetPlayers(ctx, shared.ID)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			assert.Equal(t, target.ID, players[0].UserID)
+
+			for _, dropped := range []*models.Invitation{pending, between} {
+				foundInvitation, err := b.invitations.FindByID(ctx, dropped.ID)
+				require.NoError(t, err)
+				assert.Nil(t, foundInvitation)
+			}
+			foundInvitation, err := b.invitations.FindByID(ctx, sent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, target.ID, foundInvitation.InviterUserID)
+			foundMessage, err := b.messages.FindByID(ctx, message.ID)
+			require.NoError(t, err)
+			assert.Equal(t, target.ID, foundMessage.UserID)
+			foundNotification, err := b.notifications.FindByID(ctx, notification.ID)
+			require.NoError(t, err)
+			assert.Equal(t, target.ID, foundNotification.UserID)
+			token, err := b.refreshTokens.FindByTokenHash(ctx, "source-token")
+			require.NoError(t, err)
+			assert.True(t, token.Revoked)
+
+			merged, err := b.users.FindByIDUnscoped(ctx, source.ID)
+			require.NoError(t, err)
+			assert.True(t, merged.DeletedAt.Valid)
+			require.NotNil(t, merged.MergedIntoUserID)
+			assert.Equal(t, target.ID, *merged.MergedIntoUserID)
+
+			again, err := b.users.Merge(ctx, source.ID, target.ID, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, repository.UserMerge{}, *again)
+		})
+	}
+}
+
+func TestRepositoryBackends_UserBlocks(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			rival := b.createUser(t, "Rival")
+			sandbagger := b.createUser(t, "Sandbagger")
+
+			require.NoError(t, b.users.Block(ctx, captain.ID, rival.ID))
+			require.NoError(t, b.users.Block(ctx, captain.ID, rival.ID), "blocking twice is a no-op")
+			require.NoError(t, b.users.Block(ctx, captain.ID, sandbagger.ID))
+
+			blocked, err := b.users.IsBlocked(ctx, captain.ID, rival.ID)
+			require.NoError(t, err)
+			assert.True(t, blocked)
+			blocked, err = b.users.IsBlocked(ctx, rival.ID, captain.ID)
+			require.NoError(t, err)
+			assert.False(t, blocked, "blocks only go one way")
+			users, err := b.users.FindBlocked(ctx, captain.ID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{rival.ID, sandbagger.ID}, modelUserIDs(users))
+
+			removed, err := b.users.Unblock(ctx, captain.ID, sandbagger.ID)
+			require.NoError(t, err)
+			assert.True(t, removed)
+			removed, err = b.users.Unblock(ctx, captain.ID, sandbagger.ID)
+			require.NoError(t, err)
+			assert.False(t, removed)
+
+			t.Run("merging moves blocks to the target", func(t *testing.T) {
+				duplicate := b.createUser(t, "Duplicate")
+				require.NoError(t, b.users.Block(ctx, captain.ID, duplicate.ID))
+				require.NoError(t, b.users.Block(ctx, duplicate.ID, sandbagger.ID))
+				require.NoError(t, b.users.Block(ctx, duplicate.ID, rival.ID))
+
+				_, err := b.users.Merge(ctx, duplicate.ID, rival.ID, time.Now())
+				require.NoError(t, err)
+
+				users, err := b.users.FindBlocked(ctx, captain.ID)
+				require.NoError(t, err)
+				assert.Equal(t, []uuid.UUID{rival.ID}, modelUserIDs(users))
+				users, err = b.users.FindBlocked(ctx, rival.ID)
+				require.NoError(t, err)
+				assert.Equal(t, []uuid.UUID{sandbagger.ID}, modelUserIDs(users), "a block of the target itself is dropped")
+			})
+
+			t.Run("purging drops the user's blocks", func(t *testing.T) {
+				rival.DeletedAt = gorm.DeletedAt{Time: time.Now().AddDate(0, 0, -40), Valid: true}
+				require.NoError(t, b.users.Update(ctx, rival))
+				_, err := b.users.PurgeDeletedBefore(ctx, time.Now().AddDate(0, 0, -30), 10)
+				require.NoError(t, err)
+
+				users, err := b.users.FindBlocked(ctx, captain.ID)
+				require.NoError(t, err)
+				assert.Empty(t, users)
+			})
+		})
+	}
+}
+
+func TestRepositoryBackends_Friendships(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			partner := b.createUser(t, "Partner")
+			caddie := b.createUser(t, "Caddie")
+
+			friendship, err := b.users.RequestFriendship(ctx, captain.ID, partner.ID, time.Now())
+			require.NoError(t, err)
+			assert.False(t, friendship.Accepted())
+			friendship, err = b.users.RequestFriendship(ctx, captain.ID, partner.ID, time.Now())
+			require.NoError(t, err)
+			assert.False(t, friendship.Accepted(), "asking twice doesn't accept")
+			_, err = b.users.RequestFriendship(ctx, caddie.ID, captain.ID, time.Now())
+			require.NoError(t, err)
+
+			friends, err := b.users.AreFriends(ctx, captain.ID, partner.ID)
+			require.NoError(t, err)
+			assert.False(t, friends, "a pending request isn't a friendship")
+			users, err := b.users.FindFriendRequests(ctx, partner.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{captain.ID}, modelUserIDs(users))
+			users, err = b.users.FindFriendRequests(ctx, captain.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{caddie.ID}, modelUserIDs(users))
+
+			friendship, err = b.users.RequestFriendship(ctx, partner.ID, captain.ID, time.Now())
+			require.NoError(t, err)
+			assert.True(t, friendship.Accepted(), "asking back accepts the request")
+			for _, pair := range [][2]uuid.UUID{{captain.ID, partner.ID}, {partner.ID, captain.ID}} {
+				friends, err = b.users.AreFriends(ctx, pair[0], pair[1])
+				require.NoError(t, err)
+				assert.True(t, friends)
+			}
+			users, err = b.users.FindFriends(ctx, partner.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{captain.ID}, modelUserIDs(users))
+			users, err = b.users.FindFriendRequests(ctx, partner.ID)
+			require.NoError(t, err)
+			assert.Empty(t, users)
+
+			removed, err := b.users.RemoveFriendship(ctx, captain.ID, caddie.ID)
+			require.NoError(t, err)
+			assert.True(t, removed, "declining removes the request")
+			removed, err = b.users.RemoveFriendship(ctx, captain.ID, caddie.ID)
+			require.NoError(t, err)
+			assert.False(t, removed)
+
+			t.Run("merging moves friendships to the target", func(t *testing.T) {
+				duplicate := b.createUser(t, "Duplicate")
+				_, err := b.users.RequestFriendship(ctx, duplicate.ID, caddie.ID, time.Now())
+				require.NoError(t, err)
+				_, err = b.users.RequestFriendship(ctx, caddie.ID, duplicate.ID, time.Now())
+				require.NoError(t, err)
+				_, err = b.users.RequestFriendship(ctx, duplicate.ID, partner.ID, time.Now())
+				require.NoError(t, err)
+
+				_, err = b.users.Merge(ctx, duplicate.ID, partner.ID, time.Now())
+				require.NoError(t, err)
+
+				users, err := b.users.FindFriends(ctx, partner.ID)
+				require.NoError(t, err)
+				assert.ElementsMatch(t, []uuid.UUID{captain.ID, caddie.ID}, modelUserIDs(users), "a friendship with the target itself is dropped")
+				users, err = b.users.FindFriends(ctx, caddie.ID)
+				require.NoError(t, err)
+				assert.Equal(t, []uuid.UUID{partner.ID}, modelUserIDs(users))
+			})
+
+			t.Run("purging drops the user's friendships", func(t *testing.T) {
+				partner.DeletedAt = gorm.DeletedAt{Time: time.Now().AddDate(0, 0, -40), Valid: true}
+				require.NoError(t, b.users.Update(ctx, partner))
+				_, err := b.users.PurgeDeletedBefore(ctx, time.Now().AddDate(0, 0, -30), 10)
+				require.NoError(t, err)
+
+				users, err := b.users.FindFriends(ctx, captain.ID)
+				require.NoError(t, err)
+				assert.Empty(t, users)
+			})
+		})
+	}
+}
+
+func modelUserIDs(users []*models.User) []uuid.UUID {
+	ids := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+func ttrIDs(ttrs []*models.TTR) []uuid.UUID {
+	ids := make([]uuid.UUID, len(ttrs))
+	for i, ttr := range ttrs {
+		ids[i] = ttr.ID
+	}
+	return ids
+}
+
+func TestRepositoryBackends_Invitations(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			coCaptain := b.createUser(t, "CoCaptain")
+			invitee := b.createUser(t, "Invitee")
+			declined := b.createUser(t, "Declined")
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, ttr.ID, coCaptain.ID, models.CoCaptainPermissions))
+
+			pending := &models.Invitation{TTRID: ttr.ID, InviterUserID: coCaptain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, pending))
+			answered := &models.Invitation{TTRID: ttr.ID, InviterUserID: coCaptain.ID, InviteeUserID: declined.ID, Status: models.InvitationStatusNo}
+			require.NoError(t, b.invitations.Create(ctx, answered))
+
+			found, err := b.invitations.FindByID(ctx, pending.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found.TTR)
+			assert.Equal(t, ttr.ID, found.TTR.ID)
+			require.NotNil(t, found.InviteeUser)
+			assert.Equal(t, invitee.ID, found.InviteeUser.ID)
+
+			removed, err := b.ttrs.RemoveMember(ctx, ttr.ID, coCaptain.ID, captain.ID)
+			require.NoError(t, err)
+			assert.True(t, removed)
+			isCoCaptain, err := b.ttrs.IsCoCaptain(ctx, ttr.ID, coCaptain.ID)
+			require.NoError(t, err)
+			assert.False(t, isCoCaptain)
+			removed, err = b.ttrs.RemoveMember(ctx, ttr.ID, coCaptain.ID, captain.ID)
+			require.NoError(t, err)
+			assert.False(t, removed, "removing a user who left is no error")
+
+			found, err = b.invitations.FindByTTRAndInvitee(ctx, ttr.ID, invitee.ID)
+			require.NoError(t, err)
+			assert.Equal(t, captain.ID, found.InviterUserID, "pending invitations move to the new inviter")
+			found, err = b.invitations.FindByTTRAndInvitee(ctx, ttr.ID, declined.ID)
+			require.NoError(t, err)
+			assert.Equal(t, coCaptain.ID, found.InviterUserID, "answered invitations keep their inviter")
+
+			canceled, err := b.invitations.CancelPendingByTTRID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, canceled, 1)
+			assert.Equal(t, pending.ID, canceled[0].ID)
+			assert.Equal(t, models.InvitationStatusCanceled, canceled[0].Status)
+
+			received, err := b.invitations.FindReceivedByUserID(ctx, invitee.ID)
+			require.NoError(t, err)
+			require.Len(t, received, 1)
+			assert.Equal(t, models.InvitationStatusCanceled, received[0].Status)
+
+			require.NoError(t, b.ttrs.Delete(ctx, ttr.ID))
+			found, err = b.invitations.FindByID(ctx, pending.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found.TTR, "deleted TTRs aren't preloaded")
+		})
+	}
+}
+
+func TestRepositoryBackends_BatchLookups(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			invitee := b.createUser(t, "Invitee")
+			other := b.createUser(t, "Other")
+			first := b.createTTR(t, captain.ID, nil)
+			second := b.createTTR(t, captain.ID, nil)
+			deleted := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, second.ID, invitee.ID, models.TTRPlayerStatusConfirmed))
+
+			toFirst := &models.Invitation{TTRID: first.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, toFirst))
+			toSecond := &models.Invitation{TTRID: second.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusYes}
+			require.NoError(t, b.invitations.Create(ctx, toSecond))
+			otherToFirst := &models.Invitation{TTRID: first.ID, InviterUserID: captain.ID, InviteeUserID: other.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, otherToFirst))
+			require.NoError(t, b.ttrs.Delete(ctx, deleted.ID))
+
+			ttrs, err := b.ttrs.FindByIDs(ctx, []uuid.UUID{first.ID, second.ID, deleted.ID, uuid.New()})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, ttrIDs(ttrs), "missing and deleted TTRs are skipped")
+			for _, ttr := range ttrs {
+				require.NotNil(t, ttr.CaptainUser)
+				if ttr.ID == second.ID {
+					require.Len(t, ttr.Players, 1)
+					assert.Equal(t, invitee.ID, ttr.Players[0].UserID)
+				}
+			}
+			ttrs, err = b.ttrs.FindByIDs(ctx, nil)
+			require.NoError(t, err)
+			assert.Empty(t, ttrs)
+
+			invitations, err := b.invitations.FindByTTRIDs(ctx, []uuid.UUID{first.ID, second.ID})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{toFirst.ID, toSecond.ID, otherToFirst.ID}, invitationIDs(invitations))
+			for _, invitation := range invitations {
+				require.NotNil(t, invitation.InviterUser)
+				require.NotNil(t, invitation.InviteeUser)
+			}
+
+			invitations, err = b.invitations.FindByTTRsAndInvitee(ctx, []uuid.UUID{first.ID, second.ID}, invitee.ID)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []uuid.UUID{toFirst.ID, toSecond.ID}, invitationIDs(invitations))
+			invitations, err = b.invitations.FindByTTRsAndInvitee(ctx, []uuid.UUID{second.ID}, other.ID)
+			require.NoError(t, err)
+			assert.Empty(t, invitations)
+		})
+	}
+}
+
+func invitationIDs(invitations []*models.Invitation) []uuid.UUID {
+	ids := make([]uuid.UUID, len(invitations))
+	for i, invitation := range invitations {
+		ids[i] = invitation.ID
+	}
+	return ids
+}
+
+func TestRepositoryBackends_InviteLinks(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, captain.ID, models.TTRPlayerStatusConfirmed))
+
+			link := &models.InviteLink{TTRID: ttr.ID, Token: uuid.NewString(), CreatedByUserID: captain.ID, MaxUses: 2}
+			require.NoError(t, b.inviteLinks.Create(ctx, link))
+
+			found, err := b.inviteLinks.FindByToken(ctx, link.Token)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, link.ID, found.ID)
+			found, err = b.inviteLinks.FindByToken(ctx, "unknown")
+			require.NoError(t, err)
+			assert.Nil(t, found)
+
+			first := b.createUser(t, "First")
+			require.NoError(t, b.inviteLinks.Redeem(ctx, link, first.ID))
+			err = b.inviteLinks.Redeem(ctx, link, first.ID)
+			assert.True(t, errors.Is(err, repository.ErrDuplicate), "got %v", err)
+
+			second := b.createUser(t, "Second")
+			require.NoError(t, b.inviteLinks.Redeem(ctx, link, second.ID))
+			err = b.inviteLinks.Redeem(ctx, link, b.createUser(t, "Third").ID)
+			assert.True(t, errors.Is(err, repository.ErrNoUsesLeft), "got %v", err)
+
+			found, err = b.inviteLinks.FindByID(ctx, link.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 2, found.Uses)
+			isPlayer, err := b.ttrs.IsPlayer(ctx, ttr.ID, second.ID)
+			require.NoError(t, err)
+			assert.True(t, isPlayer)
+
+			roomy := &models.InviteLink{TTRID: ttr.ID, Token: uuid.NewString(), CreatedByUserID: captain.ID, MaxUses: 8}
+			require.NoError(t, b.inviteLinks.Create(ctx, roomy))
+			require.NoError(t, b.inviteLinks.Redeem(ctx, roomy, b.createUser(t, "Fourth").ID))
+			err = b.inviteLinks.Redeem(ctx, roomy, b.createUser(t, "Fifth").ID)
+			assert.True(t, errors.Is(err, repository.ErrTTRFull), "got %v", err)
+			found, err = b.inviteLinks.FindByID(ctx, roomy.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 1, found.Uses, "a refused redeem takes no use")
+
+			require.NoError(t, b.inviteLinks.Revoke(ctx, link.ID, time.Now()))
+			links, err := b.inviteLinks.FindByTTRID(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, links, 2)
+			assert.Equal(t, link.ID, links[1].ID)
+			assert.NotNil(t, links[1].RevokedAt)
+		})
+	}
+}
+
+func TestRepositoryBackends_PendingInvitationUnique(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			invitee := b.createUser(t, "Invitee")
+			ttr := b.createTTR(t, captain.ID, nil)
+
+			first := &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, first))
+			second := &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+			assert.ErrorIs(t, b.invitations.Create(ctx, second), repository.ErrDuplicate)
+
+			first.Status = models.InvitationStatusNo
+			require.NoError(t, b.invitations.Update(ctx, first))
+			again := &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, again), "only pending invitations are unique")
+		})
+	}
+}
+
+func TestRepositoryBackends_Messages(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+
+			start := time.Now().Add(-time.Hour)
+			var messages []*models.Message
+			for i := 0; i < 3; i++ {
+				message := &models.Message{TTRID: ttr.ID, UserID: captain.ID, Body: "Tee times", CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+				require.NoError(t, b.messages.Create(ctx, message))
+				messages = append(messages, message)
+			}
+
+			page, err := b.messages.FindByTTRID(ctx, ttr.ID, 2, 0)
+			require.NoError(t, err)
+			require.Len(t, page, 2)
+			assert.Equal(t, messages[2].ID, page[0].ID)
+			require.NotNil(t, page[0].User)
+
+			unread, err := b.messages.CountUnreadByUserID(ctx, player.ID)
+			require.NoError(t, err)
+			assert.Equal(t, map[uuid.UUID]int64{ttr.ID: 3}, unread)
+
+			require.NoError(t, b.messages.MarkRead(ctx, ttr.ID, player.ID, messages[1].CreatedAt))
+			require.NoError(t, b.messages.MarkRead(ctx, ttr.ID, player.ID, start))
+			unread, err = b.messages.CountUnreadByUserID(ctx, player.ID)
+			require.NoError(t, err)
+			assert.Equal(t, map[uuid.UUID]int64{ttr.ID: 1}, unread)
+
+			require.NoError(t, b.messages.AddReaction(ctx, &models.MessageReaction{MessageID: messages[0].ID, UserID: player.ID, Emoji: "golf", CreatedAt: start}))
+			require.NoError(t, b.messages.AddReaction(ctx, &models.MessageReaction{MessageID: messages[0].ID, UserID: player.ID, Emoji: "golf", CreatedAt: start}))
+			require.NoError(t, b.messages.AddReaction(ctx, &models.MessageReaction{MessageID: messages[0].ID, UserID: captain.ID, Emoji: "golf", CreatedAt: start.Add(time.Second)}))
+			require.NoError(t, b.messages.AddReaction(ctx, &models.MessageReaction{MessageID: messages[0].ID, UserID: captain.ID, Emoji: "tada", CreatedAt: start.Add(time.Second)}))
+
+			summaries, err := b.messages.SummarizeReactions(ctx, []uuid.UUID{messages[0].ID, messages[1].ID}, player.ID)
+			require.NoError(t, err)
+			assert.Equal(t, map[uuid.UUID][]models.ReactionSummary{
+				messages[0].ID: {
+					{Emoji: "golf", Count: 2, ReactedByMe: true},
+					{Emoji: "tada", Count: 1, ReactedByMe: false},
+				},
+			}, summaries)
+
+			key := "attachments/scorecard.pdf"
+			messages[2].AttachmentKey = &key
+			messages[2].AttachmentSize = 1024
+			require.NoError(t, b.messages.Update(ctx, messages[2]))
+			total, err := b.messages.SumAttachmentSizeByTTRID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1024), total)
+
+			messages[2].Deleted = true
+			require.NoError(t, b.messages.Update(ctx, messages[2]))
+			deleted, err := b.messages.FindDeletedWithAttachments(ctx, 10)
+			require.NoError(t, err)
+			require.Len(t, deleted, 1)
+			require.NoError(t, b.messages.ClearAttachment(ctx, deleted[0].ID))
+			deleted, err = b.messages.FindDeletedWithAttachments(ctx, 10)
+			require.NoError(t, err)
+			assert.Empty(t, deleted)
+		})
+	}
+}
+
+func TestRepositoryBackends_LeagueStandings(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			owner := b.createUser(t, "Owner")
+			players := []*models.User{b.createUser(t, "A"), b.createUser(t, "B"), b.createUser(t, "C")}
+
+			league := &models.League{Name: "Summer", OwnerUserID: owner.ID, StartDate: time.Now(), EndDate: time.Now().AddDate(0, 3, 0), ScoringScheme: models.LeagueScoringPointsPerFinish}
+			require.NoError(t, b.leagues.Create(ctx, league))
+
+			rounds := [][]int{{70, 72, 72}, {75, 71, 80}}
+			for _, scores := range rounds {
+				ttr := b.createTTR(t, owner.ID, nil)
+				attached, err := b.leagues.AttachTTR(ctx, league.ID, ttr.ID)
+				require.NoError(t, err)
+				assert.True(t, attached)
+				attached, err = b.leagues.AttachTTR(ctx, league.ID, ttr.ID)
+				require.NoError(t, err)
+				assert.False(t, attached)
+
+				for i, score := range scores {
+					score := score
+					require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, players[i].ID, models.TTRPlayerStatusConfirmed))
+					require.NoError(t, b.ttrs.UpdatePlayer(ctx, &models.TTRPlayer{TTRID: ttr.ID, UserID: players[i].ID, Status: models.TTRPlayerStatusConfirmed, Score: &score}))
+				}
+
+				found, err := b.ttrs.FindByID(ctx, ttr.ID)
+				require.NoError(t, err)
+				found.Status = models.TTRStatusCompleted
+				require.NoError(t, b.ttrs.Update(ctx, found))
+			}
+
+			standings, err := b.leagues.ComputeStandings(ctx, league)
+			require.NoError(t, err)
+			require.Len(t, standings, 3)
+
+			byUser := make(map[uuid.UUID]*models.LeagueStanding)
+			for _, standing := range standings {
+				byUser[standing.UserID] = standing
+			}
+			// Round 1: A 3 points, B and C tie for second with 2. Round 2: B 3,
+			// A 2, C 1.
+			assert.Equal(t, models.LeagueStanding{Position: 1, UserID: players[0].ID, Rounds: 2, TotalStrokes: 145, Points: 5}, *byUser[players[0].ID])
+			assert.Equal(t, models.LeagueStanding{Position: 1, UserID: players[1].ID, Rounds: 2, TotalStrokes: 143, Points: 5}, *byUser[players[1].ID])
+			assert.Equal(t, models.LeagueStanding{Position: 3, UserID: players[2].ID, Rounds: 2, TotalStrokes: 152, Points: 3}, *byUser[players[2].ID])
+
+			league.ScoringScheme = models.LeagueScoringStrokePlay
+			standings, err = b.leagues.ComputeStandings(ctx, league)
+			require.NoError(t, err)
+			require.Len(t, standings, 3)
+			assert.Equal(t, players[1].ID, standings[0].UserID)
+			assert.Equal(t, 1, standings[0].Position)
+		})
+	}
+}
+
+func TestRepositoryBackends_Suggestions(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			often := b.createUser(t, "Often")
+			once := b.createUser(t, "Once")
+			rostered := b.createUser(t, "Rostered")
+			invited := b.createUser(t, "Invited")
+			cancelled := b.createUser(t, "Cancelled")
+
+			pastRound := func(status models.TTRStatus, players ...*models.User) {
+				ttr := b.createTTR(t, captain.ID, nil)
+				for _, player := range players {
+					require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+				}
+				found, err := b.ttrs.FindByID(ctx, ttr.ID)
+				require.NoError(t, err)
+				found.TeeDate = time.Now().AddDate(0, 0, -7).Truncate(24 * time.Hour)
+				found.Status = status
+				require.NoError(t, b.ttrs.Update(ctx, found))
+			}
+			pastRound(models.TTRStatusCompleted, captain, often, once, rostered, invited)
+			pastRound(models.TTRStatusCompleted, captain, often)
+			pastRound(models.TTRStatusCancelled, captain, cancelled)
+
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, rostered.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invited.ID, Status: models.InvitationStatusPending}))
+
+			counts, err := b.suggestions.SharedRounds(ctx, ttr.ID, captain.ID, time.Now().Truncate(24*time.Hour), 20)
+			require.NoError(t, err)
+			assert.Equal(t, []repository.SharedRoundCount{
+				{UserID: often.ID, Rounds: 2},
+				{UserID: once.ID, Rounds: 1},
+			}, counts)
+
+			org := &models.Organization{Name: "Club", CreatedByUserID: captain.ID}
+			require.NoError(t, b.organizations.Create(ctx, org))
+			for _, member := range []*models.User{captain, once, invited} {
+				require.NoError(t, b.organizations.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: member.ID, Role: models.OrganizationRoleMember}))
+			}
+			mates, err := b.suggestions.OrganizationMates(ctx, ttr.ID, captain.ID, 20)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{once.ID}, mates)
+
+			require.NoError(t, b.users.SetPlayingDays(ctx, cancelled.ID, []string{"saturday"}))
+			require.NoError(t, b.users.SetPlayingDays(ctx, rostered.ID, []string{"saturday"}))
+			playing, err := b.suggestions.PlayingOn(ctx, ttr.ID, captain.ID, "saturday", 20)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{cancelled.ID}, playing)
+		})
+	}
+}
+
+func TestRepositoryBackends_ActionItems(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			from := time.Now().Truncate(24 * time.Hour)
+
+			invited := b.createTTR(t, captain.ID, nil)
+			invitation := &models.Invitation{TTRID: invited.ID, InviterUserID: captain.ID, InviteeUserID: player.ID, Status: models.InvitationStatusPending}
+			require.NoError(t, b.invitations.Create(ctx, invitation))
+
+			cancelled := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: cancelled.ID, InviterUserID: captain.ID, InviteeUserID: player.ID, Status: models.InvitationStatusPending}))
+			cancelled.Status = models.TTRStatusCancelled
+			require.NoError(t, b.ttrs.Update(ctx, cancelled))
+
+			maybe := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, maybe.ID, player.ID, models.TTRPlayerStatusMaybe))
+			confirmed := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, confirmed.ID, player.ID, models.TTRPlayerStatusConfirmed))
+
+			invitations, err := b.actionItems.PendingInvitations(ctx, player.ID, from)
+			require.NoError(t, err)
+			require.Len(t, invitations, 1, "invitations to cancelled TTRs are left out")
+			assert.Equal(t, invitation.ID, invitations[0].ID)
+			require.NotNil(t, invitations[0].TTR)
+			assert.Equal(t, invited.ID, invitations[0].TTR.ID)
+
+			awaiting, err := b.actionItems.AwaitingAnswers(ctx, captain.ID, from, from.AddDate(0, 0, 8))
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{invited.ID}, ttrIDs(awaiting))
+			awaiting, err = b.actionItems.AwaitingAnswers(ctx, captain.ID, from, from.AddDate(0, 0, 3))
+			require.NoError(t, err)
+			assert.Empty(t, awaiting, "tee dates after until are left out")
+			awaiting, err = b.actionItems.AwaitingAnswers(ctx, player.ID, from, from.AddDate(0, 0, 8))
+			require.NoError(t, err)
+			assert.Empty(t, awaiting, "only the captain's TTRs")
+
+			maybes, err := b.actionItems.MaybeRounds(ctx, player.ID, from)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{maybe.ID}, ttrIDs(maybes))
+			maybes, err = b.actionItems.MaybeRounds(ctx, player.ID, from.AddDate(0, 0, 8))
+			require.NoError(t, err)
+			assert.Empty(t, maybes, "tee dates before from are left out")
+		})
+	}
+}
+
+func TestRepositoryBackends_DashboardCounts(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			other := b.createUser(t, "Other")
+			from := time.Now().Truncate(24 * time.Hour)
+
+			ttr := b.createTTR(t, captain.ID, nil)
+			for _, invitee := range []*models.User{player, other} {
+				require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}))
+			}
+			answered := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: answered.ID, InviterUserID: captain.ID, InviteeUserID: player.ID, Status: models.InvitationStatusYes}))
+			cancelled := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: cancelled.ID, InviterUserID: captain.ID, InviteeUserID: player.ID, Status: models.InvitationStatusPending}))
+			cancelled.Status = models.TTRStatusCancelled
+			require.NoError(t, b.ttrs.Update(ctx, cancelled))
+
+			counts, err := b.invitations.CountPendingByCaptainID(ctx, captain.ID, from)
+			require.NoError(t, err)
+			assert.Equal(t, map[uuid.UUID]int{ttr.ID: 2}, counts)
+			counts, err = b.invitations.CountPendingByCaptainID(ctx, player.ID, from)
+			require.NoError(t, err)
+			assert.Empty(t, counts, "only the captain's TTRs")
+			counts, err = b.invitations.CountPendingByCaptainID(ctx, captain.ID, from.AddDate(0, 0, 8))
+			require.NoError(t, err)
+			assert.Empty(t, counts, "tee dates before from are left out")
+
+			read := &models.Notification{UserID: player.ID, Type: models.NotificationTypeInvitation, Title: "Invited", Message: "You're invited"}
+			require.NoError(t, b.notifications.Create(ctx, read))
+			require.NoError(t, b.notifications.MarkAsRead(ctx, read.ID))
+			require.NoError(t, b.notifications.Create(ctx, &models.Notification{UserID: player.ID, Type: models.NotificationTypeInvitation, Title: "Invited", Message: "You're invited"}))
+
+			unread, err := b.notifications.CountUnreadByUserID(ctx, player.ID)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), unread)
+		})
+	}
+}
+
+func TestRepositoryBackends_Tournament(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			owner := uuid.New()
+			p1, p2 := uuid.New(), uuid.New()
+			final := &models.Match{Round: 2, Position: 0, Status: models.MatchStatusPending}
+			semi := &models.Match{Round: 1, Position: 0, Player1UserID: &p1, Player2UserID: &p2, Status: models.MatchStatusScheduled}
+			tournament := &models.Tournament{Name: "Club Championship", OwnerUserID: owner, Status: models.TournamentStatusInProgress, Matches: []*models.Match{final, semi}}
+			require.NoError(t, b.tournaments.Create(ctx, tournament))
+
+			found, err := b.tournaments.FindByID(ctx, tournament.ID)
+			require.NoError(t, err)
+			require.Len(t, found.Matches, 2)
+			assert.Equal(t, semi.ID, found.Matches[0].ID)
+			assert.Equal(t, final.ID, found.Matches[1].ID)
+
+			now := time.Now()
+			result := *found.Matches[0]
+			result.WinnerUserID = &p1
+			result.Status = models.MatchStatusCompleted
+			result.CompletedAt = &now
+			completed, err := b.tournaments.CompleteMatch(ctx, &result)
+			require.NoError(t, err)
+			assert.True(t, completed)
+			completed, err = b.tournaments.CompleteMatch(ctx, &result)
+			require.NoError(t, err)
+			assert.False(t, completed)
+
+			found, err = b.tournaments.FindByID(ctx, tournament.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found.Matches[0].WinnerUserID)
+			assert.Equal(t, p1, *found.Matches[0].WinnerUserID)
+		})
+	}
+}
+
+func TestRepositoryBackends_NotificationsAndTokens(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			user := b.createUser(t, "Reader")
+			for i := 0; i < 3; i++ {
+				require.NoError(t, b.notifications.Create(ctx, &models.Notification{UserID: user.ID, Type: models.NotificationTypeTTRUpdate, Title: "Update", Message: "Tee time moved"}))
+			}
+			notifications, err := b.notifications.FindByUserID(ctx, user.ID, 2, 0)
+			require.NoError(t, err)
+			assert.Len(t, notifications, 2)
+
+			require.NoError(t, b.notifications.MarkAsRead(ctx, notifications[0].ID))
+			unread, err := b.notifications.FindUnreadByUserID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Len(t, unread, 2)
+			require.NoError(t, b.notifications.MarkAllAsRead(ctx, user.ID))
+			unread, err = b.notifications.FindUnreadByUserID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Empty(t, unread)
+
+			targetType := "invitation"
+			targetID := uuid.New()
+			require.NoError(t, b.notifications.Create(ctx, &models.Notification{UserID: user.ID, Type: models.NotificationTypeInvitation, Title: "Invite", Message: "You're invited", TargetType: &targetType, TargetID: &targetID}))
+			targeted, err := b.notifications.FindByTarget(ctx, targetType, targetID)
+			require.NoError(t, err)
+			require.Len(t, targeted, 1)
+			assert.Equal(t, models.NotificationTypeInvitation, targeted[0].Type)
+			targeted, err = b.notifications.FindByTarget(ctx, "ttr", targetID)
+			require.NoError(t, err)
+			assert.Empty(t, targeted)
+
+			recent, err := b.notifications.FindRecentByTypeAndTarget(ctx, user.ID, models.NotificationTypeInvitation, targetType, targetID, time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			require.NotNil(t, recent)
+			assert.Equal(t, 1, recent.EventCount)
+			stale, err := b.notifications.FindRecentByTypeAndTarget(ctx, user.ID, models.NotificationTypeInvitation, targetType, targetID, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			assert.Nil(t, stale, "nothing was created after since")
+			require.NoError(t, b.notifications.MarkAsRead(ctx, recent.ID))
+			require.NoError(t, b.notifications.UpdateDigest(ctx, recent.ID, "Invites", "2 invites", 2))
+			digest, err := b.notifications.FindByID(ctx, recent.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "2 invites", digest.Message)
+			assert.Equal(t, 2, digest.EventCount)
+			assert.False(t, digest.IsRead)
+			assert.Nil(t, digest.ReadAt)
+
+			require.NoError(t, b.refreshTokens.Create(ctx, &models.RefreshToken{UserID: user.ID, TokenHash: "live", ExpiresAt: time.Now().Add(time.Hour)}))
+			require.NoError(t, b.refreshTokens.Create(ctx, &models.RefreshToken{UserID: user.ID, TokenHash: "expired", ExpiresAt: time.Now().Add(-time.Hour)}))
+			require.NoError(t, b.refreshTokens.DeleteExpired(ctx))
+			require.NoError(t, b.refreshTokens.RevokeByUserID(ctx, user.ID))
+
+			token, err := b.refreshTokens.FindByTokenHash(ctx, "live")
+			require.NoError(t, err)
+			require.NotNil(t, token)
+			assert.True(t, token.Revoked)
+			require.NotNil(t, token.User)
+			assert.Equal(t, user.ID, token.User.ID)
+
+			token, err = b.refreshTokens.FindByTokenHash(ctx, "expired")
+			require.NoError(t, err)
+			assert.Nil(t, token)
+		})
+	}
+}
+
+func TestRepositoryBackends_TransactionRollback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			ttr := b.createTTR(t, captain.ID, nil)
+
+			err := b.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+				return errors.New("abort")
+			})
+			assert.EqualError(t, err, "abort")
+
+			isPlayer, err := b.ttrs.IsPlayer(ctx, ttr.ID, player.ID)
+			require.NoError(t, err)
+			assert.False(t, isPlayer)
+
+			err = b.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed)
+			})
+			require.NoError(t, err)
+			isPlayer, err = b.ttrs.IsPlayer(ctx, ttr.ID, player.ID)
+			require.NoError(t, err)
+			assert.True(t, isPlayer)
+		})
+	}
+}
+
+func TestMemoryRepositories_ConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	ttrs := memory.NewTTRRepository(store)
+	messages := memory.NewMessageRepository(store)
+
+	ttr := &models.TTR{CourseName: "Pebble Beach", CaptainUserID: uuid.New(), MaxPlayers: 20}
+	require.NoError(t, ttrs.Create(ctx, ttr))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := uuid.New()
+			assert.NoError(t, ttrs.AddPlayer(ctx, ttr.ID, userID, models.TTRPlayerStatusConfirmed))
+			assert.NoError(t, messages.Create(ctx, &models.Message{TTRID: ttr.ID, UserID: userID, Body: "On my way"}))
+			_, err := ttrs.FindByID(ctx, ttr.ID)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	found, err := ttrs.FindByID(ctx, ttr.ID)
+	require.NoError(t, err)
+	assert.Len(t, found.Players, 20)
+	all, err := messages.FindByTTRID(ctx, ttr.ID, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, all, 20)
+}
+
+func TestRepositoryBackends_Webhooks(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			other := b.createUser(t, "Other")
+			orgID := uuid.New()
+
+			personal := &models.Webhook{OwnerUserID: captain.ID, URL: "https://example.com/personal", Secret: "s", Events: models.WebhookEventTTRCreated, Status: models.WebhookStatusActive}
+			require.NoError(t, b.webhooks.Create(ctx, personal))
+			orgHook := &models.Webhook{OwnerUserID: other.ID, OrganizationID: &orgID, URL: "https://example.com/org", Secret: "s", Events: models.WebhookEventTTRCreated, Status: models.WebhookStatusActive}
+			require.NoError(t, b.webhooks.Create(ctx, orgHook))
+			require.NoError(t, b.webhooks.Create(ctx, &models.Webhook{OwnerUserID: other.ID, URL: "https://example.com/other", Secret: "s", Events: models.WebhookEventTTRCreated, Status: models.WebhookStatusActive}))
+
+			owned, err := b.webhooks.FindByOwner(ctx, other.ID)
+			require.NoError(t, err)
+			require.Len(t, owned, 1, "organization webhooks aren't the owner's own")
+			assert.Equal(t, "https://example.com/other", owned[0].URL)
+
+			byOrg, err := b.webhooks.FindByOrganization(ctx, orgID)
+			require.NoError(t, err)
+			require.Len(t, byOrg, 1)
+			assert.Equal(t, orgHook.ID, byOrg[0].ID)
+
+			active, err := b.webhooks.FindActiveForTTR(ctx, captain.ID, nil)
+			require.NoError(t, err)
+			require.Len(t, active, 1)
+			assert.Equal(t, personal.ID, active[0].ID)
+
+			active, err = b.webhooks.FindActiveForTTR(ctx, captain.ID, &orgID)
+			require.NoError(t, err)
+			assert.Len(t, active, 2)
+
+			require.NoError(t, b.webhooks.RecordResult(ctx, personal.ID, false, 2))
+			stored, err := b.webhooks.FindByID(ctx, personal.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 1, stored.ConsecutiveFailures)
+			assert.Equal(t, models.WebhookStatusActive, stored.Status)
+
+			require.NoError(t, b.webhooks.RecordResult(ctx, personal.ID, false, 2))
+			stored, err = b.webhooks.FindByID(ctx, personal.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 2, stored.ConsecutiveFailures)
+			assert.Equal(t, models.WebhookStatusFailing, stored.Status)
+
+			active, err = b.webhooks.FindActiveForTTR(ctx, captain.ID, nil)
+			require.NoError(t, err)
+			assert.Empty(t, active, "failing webhooks get no deliveries")
+
+			require.NoError(t, b.webhooks.RecordResult(ctx, orgHook.ID, false, 0))
+			stored, err = b.webhooks.FindByID(ctx, orgHook.ID)
+			require.NoError(t, err)
+			assert.Equal(t, models.WebhookStatusActive, stored.Status, "zero never marks a webhook failing")
+
+			eventID := uuid.New()
+			for attempt := 1; attempt <= 3; attempt++ {
+				require.NoError(t, b.webhooks.CreateDelivery(ctx, &models.WebhookDelivery{
+					WebhookID: personal.ID,
+					EventID:   eventID,
+					Event:     models.WebhookEventTTRCreated,
+					Attempt:   attempt,
+					CreatedAt: time.Now().Add(time.Duration(attempt) * time.Second),
+				}))
+			}
+			deliveries, err := b.webhooks.FindDeliveries(ctx, personal.ID, 2, 0)
+			require.NoError(t, err)
+			require.Len(t, deliveries, 2)
+			assert.Equal(t, 3, deliveries[0].Attempt, "newest first")
+			assert.Equal(t, 2, deliveries[1].Attempt)
+
+			require.NoError(t, b.webhooks.Delete(ctx, personal.ID))
+			gone, err := b.webhooks.FindByID(ctx, personal.ID)
+			require.NoError(t, err)
+			assert.Nil(t, gone)
+			deliveries, err = b.webhooks.FindDeliveries(ctx, personal.ID, 10, 0)
+			require.NoError(t, err)
+			assert.Empty(t, deliveries)
+		})
+	}
+}
+
+func TestRepositoryBackends_Slack(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			first := b.createUser(t, "First")
+			second := b.createUser(t, "Second")
+			now := time.Now()
+
+			account, err := b.slack.FindAccount(ctx, "T0001", "U0001")
+			require.NoError(t, err)
+			assert.Nil(t, account)
+
+			require.NoError(t, b.slack.LinkAccount(ctx, &models.SlackAccount{TeamID: "T0001", SlackUserID: "U0001", UserID: first.ID}))
+			require.NoError(t, b.slack.LinkAccount(ctx, &models.SlackAccount{TeamID: "T0001", SlackUserID: "U0001", UserID: second.ID}))
+			account, err = b.slack.FindAccount(ctx, "T0001", "U0001")
+			require.NoError(t, err)
+			require.NotNil(t, account)
+			assert.Equal(t, second.ID, account.UserID, "linking again moves the Slack user")
+
+			other, err := b.slack.FindAccount(ctx, "T0002", "U0001")
+			require.NoError(t, err)
+			assert.Nil(t, other, "accounts are per workspace")
+
+			require.NoError(t, b.slack.CreateLinkCode(ctx, &models.SlackLinkCode{Code: "ABCD2345", TeamID: "T0001", SlackUserID: "U0002", ExpiresAt: now.Add(time.Minute)}))
+			err = b.slack.CreateLinkCode(ctx, &models.SlackLinkCode{Code: "ABCD2345", TeamID: "T0001", SlackUserID: "U0003", ExpiresAt: now.Add(time.Minute)})
+			assert.True(t, errors.Is(err, repository.ErrDuplicate))
+
+			code, err := b.slack.ConsumeLinkCode(ctx, "ABCD2345", now)
+			require.NoError(t, err)
+			require.NotNil(t, code)
+			assert.Equal(t, "U0002", code.SlackUserID)
+			code, err = b.slack.ConsumeLinkCode(ctx, "ABCD2345", now)
+			require.NoError(t, err)
+			assert.Nil(t, code, "codes are consumed once")
+
+			require.NoError(t, b.slack.CreateLinkCode(ctx, &models.SlackLinkCode{Code: "EXPIRED2", TeamID: "T0001", SlackUserID: "U0002", ExpiresAt: now.Add(-time.Second)}))
+			code, err = b.slack.ConsumeLinkCode(ctx, "EXPIRED2", now)
+			require.NoError(t, err)
+			assert.Nil(t, code)
+		})
+	}
+}
+
+func TestRepositoryBackends_APITokens(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			user := b.createUser(t, "Scripter")
+			older := &models.APIToken{UserID: user.ID, Name: "older", Prefix: "gm_pat_aaaaa", TokenHash: "hash-older-" + b.name, CreatedAt: time.Now().Add(-time.Hour)}
+			newer := &models.APIToken{UserID: user.ID, Name: "newer", Prefix: "gm_pat_bbbbb", TokenHash: "hash-newer-" + b.name}
+			require.NoError(t, b.apiTokens.Create(ctx, older))
+			require.NoError(t, b.apiTokens.Create(ctx, newer))
+			err := b.apiTokens.Create(ctx, &models.APIToken{UserID: user.ID, Name: "copy", Prefix: "gm_pat_aaaaa", TokenHash: older.TokenHash})
+			assert.True(t, errors.Is(err, repository.ErrDuplicate))
+
+			found, err := b.apiTokens.FindByTokenHash(ctx, newer.TokenHash)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, newer.ID, found.ID)
+			require.NotNil(t, found.User, "the user is preloaded")
+			assert.Equal(t, user.ID, found.User.ID)
+
+			tokens, err := b.apiTokens.FindByUserID(ctx, user.ID)
+			require.NoError(t, err)
+			require.Len(t, tokens, 2)
+			assert.Equal(t, newer.ID, tokens[0].ID, "newest first")
+
+			usedAt := time.Now().Truncate(time.Second)
+			require.NoError(t, b.apiTokens.UpdateLastUsed(ctx, older.ID, usedAt))
+			found, err = b.apiTokens.FindByID(ctx, older.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found.LastUsedAt)
+			assert.True(t, found.LastUsedAt.Equal(usedAt))
+
+			require.NoError(t, b.apiTokens.Delete(ctx, older.ID))
+			found, err = b.apiTokens.FindByTokenHash(ctx, older.TokenHash)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+		})
+	}
+}
+
+func TestRepositoryBackends_Impersonation(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			admin := b.createUser(t, "Admin")
+			user := b.createUser(t, "Golfer")
+			other := b.createUser(t, "Other")
+
+			session := &models.ImpersonationSession{ActorUserID: admin.ID, TargetUserID: user.ID, Reason: "ticket", ExpiresAt: time.Now().Add(time.Hour)}
+			require.NoError(t, b.impersonation.CreateSession(ctx, session))
+			found, err := b.impersonation.FindSession(ctx, session.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.True(t, found.IsActive(time.Now()))
+
+			ended, err := b.impersonation.EndSession(ctx, session.ID, time.Now())
+			require.NoError(t, err)
+			assert.True(t, ended)
+			ended, err = b.impersonation.EndSession(ctx, session.ID, time.Now())
+			require.NoError(t, err)
+			assert.False(t, ended, "a session ends once")
+			found, err = b.impersonation.FindSession(ctx, session.ID)
+			require.NoError(t, err)
+			assert.False(t, found.IsActive(time.Now()))
+
+			missing, err := b.impersonation.FindSession(ctx, uuid.New())
+			require.NoError(t, err)
+			assert.Nil(t, missing)
+
+			older := &models.AuditLogEntry{Action: models.AuditActionImpersonationStart, ActorUserID: admin.ID, SubjectUserID: &user.ID, CreatedAt: time.Now().Add(-time.Minute)}
+			newer := &models.AuditLogEntry{Action: models.AuditActionImpersonationStop, ActorUserID: admin.ID, SubjectUserID: &user.ID}
+			unrelated := &models.AuditLogEntry{Action: models.AuditActionImpersonationStart, ActorUserID: other.ID}
+			require.NoError(t, b.auditLog.Create(ctx, older))
+			require.NoError(t, b.auditLog.Create(ctx, newer))
+			require.NoError(t, b.auditLog.Create(ctx, unrelated))
+
+			entries, err := b.auditLog.Find(ctx, &user.ID, 10, 0)
+			require.NoError(t, err)
+			require.Len(t, entries, 2, "entries where the user is the subject")
+			assert.Equal(t, newer.ID, entries[0].ID, "newest first")
+
+			entries, err = b.auditLog.Find(ctx, &admin.ID, 1, 1)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, older.ID, entries[0].ID)
+		})
+	}
+}
+
+func TestRepositoryBackends_FeatureFlags(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			require.NoError(t, b.featureFlags.Upsert(ctx, &models.FeatureFlag{Key: "waitlist", Enabled: true, Percentage: 10}))
+			require.NoError(t, b.featureFlags.Upsert(ctx, &models.FeatureFlag{Key: "messaging", Percentage: 50}))
+			first, err := b.featureFlags.FindByKey(ctx, "waitlist")
+			require.NoError(t, err)
+			require.NotNil(t, first)
+
+			require.NoError(t, b.featureFlags.Upsert(ctx, &models.FeatureFlag{Key: "waitlist", Enabled: false, Percentage: 40}))
+			found, err := b.featureFlags.FindByKey(ctx, "waitlist")
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.False(t, found.Enabled)
+			assert.Equal(t, 40, found.Percentage)
+			assert.True(t, found.CreatedAt.Equal(first.CreatedAt), "replacing keeps the creation time")
+
+			flags, err := b.featureFlags.FindAll(ctx)
+			require.NoError(t, err)
+			require.Len(t, flags, 2)
+			assert.Equal(t, "messaging", flags[0].Key, "ordered by key")
+
+			deleted, err := b.featureFlags.Delete(ctx, "messaging")
+			require.NoError(t, err)
+			assert.True(t, deleted)
+			deleted, err = b.featureFlags.Delete(ctx, "messaging")
+			require.NoError(t, err)
+			assert.False(t, deleted)
+			missing, err := b.featureFlags.FindByKey(ctx, "messaging")
+			require.NoError(t, err)
+			assert.Nil(t, missing)
+		})
+	}
+}
+
+func TestRepositoryBackends_TTREvents(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			ttr := b.createTTR(t, captain.ID, nil)
+			other := b.createTTR(t, captain.ID, nil)
+
+			old := &models.TTREvent{TTRID: ttr.ID, Type: models.TTREventPlayerJoined, CreatedAt: time.Now().AddDate(0, 0, -40)}
+			require.NoError(t, b.ttrEvents.Append(ctx, old))
+			assert.Equal(t, int64(1), old.Sequence)
+			for _, eventType := range []string{models.TTREventStatusChanged, models.TTREventMessagePosted} {
+				require.NoError(t, b.ttrEvents.Append(ctx, &models.TTREvent{TTRID: ttr.ID, Type: eventType, ActorUserID: &captain.ID, Data: `{"status":"CONFIRMED"}`}))
+			}
+			first := &models.TTREvent{TTRID: other.ID, Type: models.TTREventDetailsUpdated, CreatedAt: time.Now().AddDate(0, 0, -40)}
+			require.NoError(t, b.ttrEvents.Append(ctx, first))
+			assert.Equal(t, int64(1), first.Sequence, "sequences are per TTR")
+
+			events, err := b.ttrEvents.FindSince(ctx, ttr.ID, 1, 10)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			assert.Equal(t, int64(2), events[0].Sequence)
+			assert.Equal(t, models.TTREventStatusChanged, events[0].Type)
+			assert.Equal(t, `{"status":"CONFIRMED"}`, events[0].Data)
+			require.NotNil(t, events[0].ActorUserID)
+			assert.Equal(t, captain.ID, *events[0].ActorUserID)
+			assert.Equal(t, int64(3), events[1].Sequence)
+
+			events, err = b.ttrEvents.FindSince(ctx, ttr.ID, 0, 1)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, old.ID, events[0].ID)
+
+			deleted, err := b.ttrEvents.DeleteBefore(ctx, time.Now().AddDate(0, 0, -30), 10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+			events, err = b.ttrEvents.FindSince(ctx, ttr.ID, 0, 10)
+			require.NoError(t, err)
+			assert.Len(t, events, 2)
+			events, err = b.ttrEvents.FindSince(ctx, other.ID, 0, 10)
+			require.NoError(t, err)
+			assert.Len(t, events, 1, "a TTR's latest event is kept")
+
+			next := &models.TTREvent{TTRID: ttr.ID, Type: models.TTREventPlayerLeft}
+			require.NoError(t, b.ttrEvents.Append(ctx, next))
+			assert.Equal(t, int64(4), next.Sequence, "purging old events doesn't reuse their numbers")
+		})
+	}
+}
+
+func TestRepositoryBackends_Outbox(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			now := time.Now()
+			first := &models.OutboxMessage{Kind: models.OutboxKindNotification, Payload: `{"n":1}`}
+			require.NoError(t, b.outbox.Create(ctx, first))
+			assert.Equal(t, models.OutboxStatusPending, first.Status)
+			second := &models.OutboxMessage{Kind: models.OutboxKindWebhook, Payload: `{"n":2}`}
+			require.NoError(t, b.outbox.Create(ctx, second))
+			later := &models.OutboxMessage{Kind: models.OutboxKindWebhook, Payload: `{"n":3}`, NextAttemptAt: now.Add(time.Hour)}
+			require.NoError(t, b.outbox.Create(ctx, later))
+
+			claimed, err := b.outbox.Claim(ctx, now.Add(time.Second), time.Minute, 10)
+			require.NoError(t, err)
+			require.Len(t, claimed, 2, "messages that aren't due are left")
+			assert.Equal(t, `{"n":1}`, claimed[0].Payload)
+			assert.Equal(t, models.OutboxKindNotification, claimed[0].Kind)
+
+			claimed, err = b.outbox.Claim(ctx, now.Add(time.Second), time.Minute, 10)
+			require.NoError(t, err)
+			assert.Empty(t, claimed, "claimed messages are leased")
+			claimed, err = b.outbox.Claim(ctx, now.Add(2*time.Minute), time.Minute, 1)
+			require.NoError(t, err)
+			require.Len(t, claimed, 1, "and due again once the lease is up")
+
+			require.NoError(t, b.outbox.MarkFailed(ctx, second.ID, 1, "timeout", now.Add(10*time.Minute), false))
+			require.NoError(t, b.outbox.MarkFailed(ctx, later.ID, 5, "gone", now, true))
+			require.NoError(t, b.outbox.MarkDone(ctx, first.ID))
+
+			pending, err := b.outbox.FindByStatus(ctx, models.OutboxStatusPending, 10)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			assert.Equal(t, second.ID, pending[0].ID)
+			assert.Equal(t, 1, pending[0].Attempts)
+			require.NotNil(t, pending[0].LastError)
+			assert.Equal(t, "timeout", *pending[0].LastError)
+			assert.WithinDuration(t, now.Add(10*time.Minute), pending[0].NextAttemptAt, time.Second)
+
+			dead, err := b.outbox.FindByStatus(ctx, models.OutboxStatusDead, 10)
+			require.NoError(t, err)
+			require.Len(t, dead, 1)
+			assert.Equal(t, later.ID, dead[0].ID)
+			claimed, err = b.outbox.Claim(ctx, now.Add(24*time.Hour), time.Minute, 10)
+			require.NoError(t, err)
+			require.Len(t, claimed, 1, "dead messages are never claimed")
+			assert.Equal(t, second.ID, claimed[0].ID)
+
+			done, err := b.outbox.FindByStatus(ctx, models.OutboxStatusDone, 10)
+			require.NoError(t, err)
+			require.Len(t, done, 1)
+			require.NotNil(t, done[0].ProcessedAt)
+
+			deleted, err := b.outbox.DeleteDoneBefore(ctx, now.Add(-time.Minute), 10)
+			require.NoError(t, err)
+			assert.Zero(t, deleted)
+			deleted, err = b.outbox.DeleteDoneBefore(ctx, time.Now().Add(time.Minute), 10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted, "only delivered messages are purged")
+			dead, err = b.outbox.FindByStatus(ctx, models.OutboxStatusDead, 10)
+			require.NoError(t, err)
+			assert.Len(t, dead, 1)
+		})
+	}
+}
+
+func TestRepositoryBackends_Stats(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			require.NoError(t, b.users.UpdateLastLogin(ctx, captain.ID, now))
+			for _, created := range []time.Time{now.AddDate(0, 0, -10), now.AddDate(0, 0, -40)} {
+				user := &models.User{Email: uuid.NewString() + "@example.com", PasswordHash: "hash", FirstName: "Old", LastName: "Golfer", CreatedAt: created}
+				require.NoError(t, b.users.Create(ctx, user))
+				require.NoError(t, b.users.UpdateLastLogin(ctx, user.ID, created))
+			}
+			deleted := b.createUser(t, "Deleted")
+			require.NoError(t, b.users.UpdateLastLogin(ctx, deleted.ID, now))
+			deleted.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+			require.NoError(t, b.users.Update(ctx, deleted))
+			player := b.createUser(t, "Player")
+
+			// Two completed TTRs, one with 3 of 4 slots confirmed and one
+			// with 1 of 4 plus a MAYBE, average a fill rate of 0.5.
+			full := b.createTTR(t, captain.ID, nil)
+			for _, userID := range []uuid.UUID{captain.ID, player.ID, deleted.ID} {
+				require.NoError(t, b.ttrs.AddPlayer(ctx, full.ID, userID, models.TTRPlayerStatusConfirmed))
+			}
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, full.ID, models.TTRStatusCompleted))
+			sparse := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, sparse.ID, captain.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, sparse.ID, player.ID, models.TTRPlayerStatusMaybe))
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, sparse.ID, models.TTRStatusCompleted))
+			open := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, open.ID, captain.ID, models.TTRPlayerStatusConfirmed))
+			gone := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.Delete(ctx, gone.ID))
+
+			for _, status := range []models.InvitationStatus{models.InvitationStatusPending, models.InvitationStatusYes, models.InvitationStatusYes} {
+				invitee := b.createUser(t, "Invitee")
+				require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: open.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: status}))
+			}
+
+			users, err := b.stats.CountUsers(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(7), users, "deleted users aren't counted")
+			created, err := b.stats.CountUsersCreatedSince(ctx, now.AddDate(0, 0, -7))
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), created)
+			created, err = b.stats.CountUsersCreatedSince(ctx, now.AddDate(0, 0, -30))
+			require.NoError(t, err)
+			assert.Equal(t, int64(6), created)
+			active, err := b.stats.CountUsersLoggedInSince(ctx, now.AddDate(0, 0, -30))
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), active)
+
+			ttrs, err := b.stats.CountTTRsByStatus(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[models.TTRStatus]int64{models.TTRStatusCompleted: 2, models.TTRStatusOpen: 1}, ttrs)
+			invitations, err := b.stats.CountInvitationsByStatus(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, map[models.InvitationStatus]int64{models.InvitationStatusPending: 1, models.InvitationStatusYes: 2}, invitations)
+
+			rate, err := b.stats.AverageFillRate(ctx, models.TTRStatusCompleted)
+			require.NoError(t, err)
+			assert.InDelta(t, 0.5, rate, 0.0001)
+			rate, err = b.stats.AverageFillRate(ctx, models.TTRStatusCancelled)
+			require.NoError(t, err)
+			assert.Zero(t, rate)
+		})
+	}
+}
+
+func TestRepositoryBackends_InactiveUsers(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -180)
+	from := now.AddDate(0, 0, -1).Truncate(24 * time.Hour)
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			createUser := func(name string, lastLogin time.Time) *models.User {
+				user := b.createUser(t, name)
+				require.NoError(t, b.users.UpdateLastLogin(ctx, user.ID, lastLogin))
+				return user
+			}
+			ids := func(users []*models.User) []uuid.UUID {
+				ids := make([]uuid.UUID, 0, len(users))
+				for _, user := range users {
+					ids = append(ids, user.ID)
+				}
+				return ids
+			}
+
+			active := createUser("Active", now)
+			inactive := createUser("Inactive", now.AddDate(0, 0, -200))
+			longGone := createUser("LongGone", now.AddDate(0, 0, -400))
+			captain := createUser("Captain", now.AddDate(0, 0, -400))
+			player := createUser("Player", now.AddDate(0, 0, -400))
+			ttr := b.createTTR(t, captain.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+
+			found, err := b.users.FindInactiveSince(ctx, cutoff, from, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{longGone.ID, inactive.ID}, ids(found), "oldest first, skipping upcoming TTRs")
+			found, err = b.users.FindInactiveSince(ctx, cutoff, from, 1)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{longGone.ID}, ids(found))
+
+			// Once the TTR has been played the captain and player count too.
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, ttr.ID, models.TTRStatusCompleted))
+			found, err = b.users.FindInactiveSince(ctx, cutoff, from, 10)
+			require.NoError(t, err)
+			assert.Len(t, found, 4)
+			assert.NotContains(t, ids(found), active.ID)
+
+			warnedAt := now.AddDate(0, 0, -31)
+			marked, err := b.users.MarkInactivityWarned(ctx, longGone.ID, warnedAt)
+			require.NoError(t, err)
+			assert.True(t, marked)
+			marked, err = b.users.MarkInactivityWarned(ctx, longGone.ID, now)
+			require.NoError(t, err)
+			assert.False(t, marked, "already warned")
+			marked, err = b.users.MarkInactivityWarned(ctx, inactive.ID, now)
+			require.NoError(t, err)
+			assert.True(t, marked)
+
+			found, err = b.users.FindInactiveSince(ctx, cutoff, from, 10)
+			require.NoError(t, err)
+			assert.NotContains(t, ids(found), longGone.ID, "warned users aren't warned again")
+			graceOver := now.AddDate(0, 0, -30)
+			found, err = b.users.FindWarnedBefore(ctx, graceOver, from, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []uuid.UUID{longGone.ID}, ids(found))
+
+			deactivated, err := b.users.Deactivate(ctx, inactive.ID, graceOver, now)
+			require.NoError(t, err)
+			assert.False(t, deactivated, "still within the grace period")
+			deactivated, err = b.users.Deactivate(ctx, longGone.ID, graceOver, now)
+			require.NoError(t, err)
+			assert.True(t, deactivated)
+			deactivated, err = b.users.Deactivate(ctx, longGone.ID, graceOver, now)
+			require.NoError(t, err)
+			assert.False(t, deactivated, "already deactivated")
+			user, err := b.users.FindByID(ctx, longGone.ID)
+			require.NoError(t, err)
+			assert.Nil(t, user)
+
+			// Logging in clears the warning.
+			require.NoError(t, b.users.UpdateLastLogin(ctx, inactive.ID, now))
+			found, err = b.users.FindWarnedBefore(ctx, now.Add(time.Hour), from, 10)
+			require.NoError(t, err)
+			assert.Empty(t, found)
+			deactivated, err = b.users.Deactivate(ctx, inactive.ID, now.Add(time.Hour), now)
+			require.NoError(t, err)
+			assert.False(t, deactivated)
+		})
+	}
+}
+
+func TestRepositoryBackends_DigestDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	runStart := now.Add(-time.Hour)
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			var due []uuid.UUID
+			for _, name := range []string{"First", "Second", "Third"} {
+				due = append(due, b.createUser(t, name).ID)
+			}
+			sort.Slice(due, func(i, j int) bool { return due[i].String() < due[j].String() })
+			optedOut := b.createUser(t, "OptedOut")
+			optedOut.Notifications.NoWeeklyDigest = true
+			require.NoError(t, b.users.Update(ctx, optedOut))
+			bounced := b.createUser(t, "Bounced")
+			bounced.EmailUndeliverable = true
+			require.NoError(t, b.users.Update(ctx, bounced))
+			ids := func(users []*models.User) []uuid.UUID {
+				ids := make([]uuid.UUID, 0, len(users))
+				for _, user := range users {
+					ids = append(ids, user.ID)
+				}
+				return ids
+			}
+
+			found, err := b.users.FindDigestDue(ctx, runStart, 10)
+			require.NoError(t, err)
+			assert.Equal(t, due, ids(found), "by ID, without users who opted out or bounced")
+			found, err = b.users.FindDigestDue(ctx, runStart, 2)
+			require.NoError(t, err)
+			assert.Equal(t, due[:2], ids(found))
+
+			// Handled by last week's run, then by this one.
+			marked, err := b.users.MarkDigestSent(ctx, due[0], runStart.AddDate(0, 0, -7), runStart.AddDate(0, 0, -7))
+			require.NoError(t, err)
+			assert.True(t, marked)
+			found, err = b.users.FindDigestDue(ctx, runStart, 10)
+			require.NoError(t, err)
+			assert.Equal(t, due, ids(found), "last week's digest doesn't count")
+			marked, err = b.users.MarkDigestSent(ctx, due[0], runStart, now)
+			require.NoError(t, err)
+			assert.True(t, marked)
+			marked, err = b.users.MarkDigestSent(ctx, due[0], runStart, now)
+			require.NoError(t, err)
+			assert.False(t, marked, "already handled this week")
+
+			found, err = b.users.FindDigestDue(ctx, runStart, 10)
+			require.NoError(t, err)
+			assert.Equal(t, due[1:], ids(found))
+		})
+	}
+}
+
+func TestRepositoryBackends_EmailSuppressions(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			found, err := b.suppressions.FindByEmailForUpdate(ctx, "ann@example.com")
+			require.NoError(t, err)
+			assert.Nil(t, found)
+
+			suppression := &models.EmailSuppression{Email: "ann@example.com", SoftBounces: 1, LastEventID: "e1"}
+			require.NoError(t, b.suppressions.Upsert(ctx, suppression))
+			suppressed, err := b.suppressions.IsSuppressed(ctx, "ann@example.com")
+			require.NoError(t, err)
+			assert.False(t, suppressed, "soft bounces alone don't suppress")
+
+			at := time.Now()
+			require.NoError(t, b.suppressions.Upsert(ctx, &models.EmailSuppression{Email: "ann@example.com", Reason: models.SuppressionReasonHardBounce, SoftBounces: 1, SuppressedAt: &at, LastEventID: "e2"}))
+			found, err = b.suppressions.FindByEmailForUpdate(ctx, "ann@example.com")
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, models.SuppressionReasonHardBounce, found.Reason)
+			assert.Equal(t, "e2", found.LastEventID)
+			assert.WithinDuration(t, suppression.CreatedAt, found.CreatedAt, time.Second, "created_at is kept")
+			suppressed, err = b.suppressions.IsSuppressed(ctx, "ann@example.com")
+			require.NoError(t, err)
+			assert.True(t, suppressed)
+
+			user := b.createUser(t, "Bounced")
+			require.NoError(t, b.users.MarkEmailUndeliverable(ctx, strings.ToUpper(user.Email)))
+			user, err = b.users.FindByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.True(t, user.EmailUndeliverable)
+		})
+	}
+}
+
+func TestRepositoryBackends_SecurityEvents(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			user := b.createUser(t, "Secure")
+			other := b.createUser(t, "Other")
+			now := time.Now()
+			var ids []uuid.UUID
+			for i, eventType := range []string{models.SecurityEventLogin, models.SecurityEventLoginFailed, models.SecurityEventPasswordChanged} {
+				event := &models.SecurityEvent{UserID: user.ID, Type: eventType, IPAddress: "203.0.113.7", CreatedAt: now.Add(time.Duration(i-100) * time.Hour)}
+				require.NoError(t, b.security.Create(ctx, event))
+				ids = append(ids, event.ID)
+			}
+			require.NoError(t, b.security.Create(ctx, &models.SecurityEvent{UserID: other.ID, Type: models.SecurityEventLogin}))
+
+			events, err := b.security.FindByUserID(ctx, user.ID, 2, 0)
+			require.NoError(t, err)
+			require.Len(t, events, 2)
+			assert.Equal(t, ids[2], events[0].ID, "newest first")
+			assert.Equal(t, ids[1], events[1].ID)
+			events, err = b.security.FindByUserID(ctx, user.ID, 2, 2)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, ids[0], events[0].ID)
+
+			deleted, err := b.security.DeleteBefore(ctx, now.Add(-98*time.Hour-time.Minute), 1)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted, "deletes up to the limit")
+			deleted, err = b.security.DeleteBefore(ctx, now.Add(-98*time.Hour-time.Minute), 10)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), deleted)
+			events, err = b.security.FindByUserID(ctx, user.ID, 10, 0)
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			assert.Equal(t, ids[2], events[0].ID)
+
+			known, err := b.security.HasDevices(ctx, user.ID)
+			require.NoError(t, err)
+			assert.False(t, known)
+			isNew, err := b.security.SeeDevice(ctx, user.ID, "phone", now)
+			require.NoError(t, err)
+			assert.True(t, isNew)
+			isNew, err = b.security.SeeDevice(ctx, user.ID, "phone", now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.False(t, isNew)
+			isNew, err = b.security.SeeDevice(ctx, other.ID, "phone", now)
+			require.NoError(t, err)
+			assert.True(t, isNew, "devices are per user")
+			known, err = b.security.HasDevices(ctx, user.ID)
+			require.NoError(t, err)
+			assert.True(t, known)
+		})
+	}
+}
+
+func TestRepositoryBackends_Reports(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			reporter := b.createUser(t, "Reporter")
+			other := b.createUser(t, "Other")
+			target := b.createUser(t, "Target")
+			now := time.Now()
+
+			first := &models.Report{ReporterUserID: reporter.ID, TargetType: models.ReportTargetUser, TargetID: target.ID, Category: models.ReportCategoryHarassment, Status: models.ReportStatusOpen, CreatedAt: now.Add(-25 * time.Hour)}
+			require.NoError(t, b.reports.Create(ctx, first))
+			err := b.reports.Create(ctx, &models.Report{ReporterUserID: reporter.ID, TargetType: models.ReportTargetUser, TargetID: target.ID, Category: models.ReportCategorySpam, Status: models.ReportStatusOpen})
+			assert.ErrorIs(t, err, repository.ErrDuplicate, "one open report per reporter and target")
+			second := &models.Report{ReporterUserID: other.ID, TargetType: models.ReportTargetUser, TargetID: target.ID, Category: models.ReportCategorySpam, Status: models.ReportStatusOpen, CreatedAt: now.Add(-time.Hour)}
+			require.NoError(t, b.reports.Create(ctx, second))
+
+			count, err := b.reports.CountByReporterSince(ctx, reporter.ID, now.Add(-24*time.Hour))
+			require.NoError(t, err)
+			assert.Zero(t, count)
+
+			resolvedAt := now
+			first.Status = models.ReportStatusResolved
+			first.Resolution = "Warned the user"
+			first.ResolvedByUserID = &other.ID
+			first.ResolvedAt = &resolvedAt
+			closed, err := b.reports.Close(ctx, first)
+			require.NoError(t, err)
+			assert.True(t, closed)
+			first.Status = models.ReportStatusDismissed
+			closed, err = b.reports.Close(ctx, first)
+			require.NoError(t, err)
+			assert.False(t, closed, "closed reports stay closed")
+
+			found, err := b.reports.FindByID(ctx, first.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, models.ReportStatusResolved, found.Status)
+			assert.Equal(t, "Warned the user", found.Resolution)
+			require.NotNil(t, found.ResolvedByUserID)
+			assert.Equal(t, other.ID, *found.ResolvedByUserID)
+			require.NotNil(t, found.Reporter)
+			assert.Equal(t, "Reporter", found.Reporter.FirstName)
+
+			// Closing the report makes room for a new one.
+			third := &models.Report{ReporterUserID: reporter.ID, TargetType: models.ReportTargetUser, TargetID: target.ID, Category: models.ReportCategoryOther, Status: models.ReportStatusOpen}
+			require.NoError(t, b.reports.Create(ctx, third))
+			count, err = b.reports.CountByReporterSince(ctx, reporter.ID, now.Add(-24*time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			open, err := b.reports.FindByStatus(ctx, models.ReportStatusOpen, 10, 0)
+			require.NoError(t, err)
+			require.Len(t, open, 2)
+			assert.Equal(t, second.ID, open[0].ID, "oldest first")
+			assert.Equal(t, third.ID, open[1].ID)
+			all, err := b.reports.FindByStatus(ctx, "", 2, 1)
+			require.NoError(t, err)
+			require.Len(t, all, 2)
+			assert.Equal(t, second.ID, all[0].ID)
+
+			missing, err := b.reports.FindByID(ctx, uuid.New())
+			require.NoError(t, err)
+			assert.Nil(t, missing)
+		})
+	}
+}
+
+func TestRepositoryBackends_FindByCaptainCourseNear(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			other := b.createUser(t, "Other")
+			teeDate := time.Now().UTC().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+			late := &models.TTR{
+				CourseName:      "Pebble Beach",
+				TeeDate:         teeDate,
+				TeeTime:         time.Date(0, 1, 1, 23, 50, 0, 0, time.UTC),
+				MaxPlayers:      4,
+				CreatedByUserID: captain.ID,
+				CaptainUserID:   captain.ID,
+				Status:          models.TTRStatusOpen,
+				Visibility:      models.TTRVisibilityPublic,
+			}
+			require.NoError(t, b.ttrs.Create(ctx, late))
+			teeAt := late.TeeDateTime()
+
+			found, err := b.ttrs.FindByCaptainCourseNear(ctx, captain.ID, "pebble beach", teeAt.Add(-30*time.Minute), teeAt.Add(30*time.Minute))
+			require.NoError(t, err)
+			require.NotNil(t, found, "the window reaches into the next day and course names ignore case")
+			assert.Equal(t, late.ID, found.ID)
+
+			found, err = b.ttrs.FindByCaptainCourseNear(ctx, captain.ID, "Pebble Beach", teeAt, teeAt.Add(time.Hour))
+			require.NoError(t, err)
+			assert.NotNil(t, found, "the window is inclusive")
+
+			found, err = b.ttrs.FindByCaptainCourseNear(ctx, captain.ID, "Pebble Beach", teeAt.Add(time.Minute), teeAt.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Nil(t, found)
+
+			found, err = b.ttrs.FindByCaptainCourseNear(ctx, other.ID, "Pebble Beach", teeAt.Add(-time.Hour), teeAt.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Nil(t, found, "another captain's TTR is no duplicate")
+
+			found, err = b.ttrs.FindByCaptainCourseNear(ctx, captain.ID, "Spyglass Hill", teeAt.Add(-time.Hour), teeAt.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Nil(t, found)
+
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, late.ID, models.TTRStatusCancelled))
+			found, err = b.ttrs.FindByCaptainCourseNear(ctx, captain.ID, "Pebble Beach", teeAt.Add(-time.Hour), teeAt.Add(time.Hour))
+			require.NoError(t, err)
+			assert.Nil(t, found, "cancelled TTRs are skipped")
+		})
+	}
+}
+
+func TestRepositoryBackends_CheckIns(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			player := b.createUser(t, "Player")
+			teeAt := time.Now().UTC().Add(10 * time.Minute).Truncate(time.Minute)
+			ttr := &models.TTR{
+				CourseName:      "Pebble Beach",
+				TeeDate:         time.Date(teeAt.Year(), teeAt.Month(), teeAt.Day(), 0, 0, 0, 0, time.UTC),
+				TeeTime:         time.Date(0, 1, 1, teeAt.Hour(), teeAt.Minute(), 0, 0, time.UTC),
+				MaxPlayers:      4,
+				CreatedByUserID: captain.ID,
+				CaptainUserID:   captain.ID,
+				Status:          models.TTRStatusOpen,
+				Visibility:      models.TTRVisibilityPublic,
+			}
+			require.NoError(t, b.ttrs.Create(ctx, ttr))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, ttr.ID, player.ID, models.TTRPlayerStatusConfirmed))
+
+			checkedInAt := time.Now().UTC().Truncate(time.Second)
+			checkedIn, err := b.ttrs.UpdatePlayerCheckIn(ctx, ttr.ID, player.ID, checkedInAt)
+			require.NoError(t, err)
+			assert.True(t, checkedIn)
+			checkedIn, err = b.ttrs.UpdatePlayerCheckIn(ctx, ttr.ID, player.ID, checkedInAt.Add(time.Minute))
+			require.NoError(t, err)
+			assert.False(t, checkedIn, "a second check-in changes nothing")
+			checkedIn, err = b.ttrs.UpdatePlayerCheckIn(ctx, ttr.ID, captain.ID, checkedInAt)
+			require.NoError(t, err)
+			assert.False(t, checkedIn, "only players on the roster are checked in")
+
+			players, err := b.ttrs.GetPlayers(ctx, ttr.ID)
+			require.NoError(t, err)
+			require.Len(t, players, 1)
+			require.NotNil(t, players[0].CheckedInAt)
+			assert.True(t, checkedInAt.Equal(*players[0].CheckedInAt))
+
+			due, err := b.ttrs.FindCheckInSummaryDue(ctx, teeAt.Add(-15*time.Minute), teeAt)
+			require.NoError(t, err)
+			require.Len(t, due, 1, "the window is inclusive")
+			assert.Equal(t, ttr.ID, due[0].ID)
+			require.Len(t, due[0].Players, 1)
+			assert.NotNil(t, due[0].Players[0].CheckedInAt)
+
+			due, err = b.ttrs.FindCheckInSummaryDue(ctx, teeAt.Add(time.Minute), teeAt.Add(15*time.Minute))
+			require.NoError(t, err)
+			assert.Empty(t, due)
+
+			marked, err := b.ttrs.MarkCheckInSummarySent(ctx, ttr.ID, checkedInAt)
+			require.NoError(t, err)
+			assert.True(t, marked)
+			marked, err = b.ttrs.MarkCheckInSummarySent(ctx, ttr.ID, checkedInAt)
+			require.NoError(t, err)
+			assert.False(t, marked, "the summary is marked once")
+
+			due, err = b.ttrs.FindCheckInSummaryDue(ctx, teeAt.Add(-15*time.Minute), teeAt)
+			require.NoError(t, err)
+			assert.Empty(t, due, "TTRs that had their summary are skipped")
+		})
+	}
+}
+
+func TestRepositoryBackends_Schedule(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			member := b.createUser(t, "Member")
+			outsider := b.createUser(t, "Outsider")
+			player := b.createUser(t, "Player")
+			waitlisted := b.createUser(t, "Waitlisted")
+
+			org := &models.Organization{Name: "Cypress Point Club", CreatedByUserID: captain.ID}
+			require.NoError(t, b.organizations.Create(ctx, org))
+			require.NoError(t, b.organizations.AddMember(ctx, &models.OrganizationMember{OrganizationID: org.ID, UserID: member.ID, Role: models.OrganizationRoleMember}))
+
+			day := time.Now().UTC().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+			at := func(ttr *models.TTR, date time.Time, hour int, course string) *models.TTR {
+				ttr.TeeDate = date
+				ttr.TeeTime = time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC)
+				ttr.CourseName = course
+				require.NoError(t, b.ttrs.Update(ctx, ttr))
+				return ttr
+			}
+			late := at(b.createTTR(t, captain.ID, nil), day, 14, "Pebble Beach")
+			early := at(b.createTTR(t, captain.ID, nil), day, 7, "PEBBLE BEACH")
+			private := at(b.createTTR(t, captain.ID, &org.ID), day, 9, "Pebble Beach")
+			nextDay := at(b.createTTR(t, captain.ID, nil), day.AddDate(0, 0, 1), 8, "Pebble Beach")
+			at(b.createTTR(t, captain.ID, nil), day, 10, "Spyglass Hill")
+			cancelled := at(b.createTTR(t, captain.ID, nil), day, 11, "Pebble Beach")
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, cancelled.ID, models.TTRStatusCancelled))
+			deleted := at(b.createTTR(t, captain.ID, nil), day, 12, "Pebble Beach")
+			require.NoError(t, b.ttrs.Delete(ctx, deleted.ID))
+
+			require.NoError(t, b.ttrs.AddPlayer(ctx, late.ID, player.ID, models.TTRPlayerStatusConfirmed))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, late.ID, member.ID, models.TTRPlayerStatusMaybe))
+			require.NoError(t, b.ttrs.AddPlayer(ctx, late.ID, waitlisted.ID, models.TTRPlayerStatusWaitlisted))
+			require.NoError(t, b.ttrs.AddGuest(ctx, &models.TTRGuest{TTRID: late.ID, DisplayName: "Guest", AddedByUserID: captain.ID}))
+
+			entries, err := b.schedule.FindSchedule(ctx, member.ID, "pebble beach", day, day.AddDate(0, 0, 1))
+			require.NoError(t, err)
+			ids := make([]uuid.UUID, len(entries))
+			for i, entry := range entries {
+				ids[i] = entry.TTRID
+			}
+			assert.Equal(t, []uuid.UUID{early.ID, private.ID, late.ID}, ids, "by tee time, courses matched regardless of case")
+
+			lateEntry := entries[2]
+			assert.Equal(t, 2, lateEntry.Confirmed, "confirmed players and guests")
+			assert.Equal(t, 3, lateEntry.Taken, "active players and guests")
+			assert.Equal(t, 4, lateEntry.MaxPlayers)
+			assert.Equal(t, models.TTRStatusOpen, lateEntry.Status)
+			assert.Equal(t, captain.ID, lateEntry.CaptainUserID)
+			assert.Equal(t, "Captain", lateEntry.CaptainFirstName)
+			assert.False(t, lateEntry.CaptainDeleted)
+			assert.Equal(t, 14, lateEntry.TeeTime.Hour())
+
+			entries, err = b.schedule.FindSchedule(ctx, outsider.ID, "Pebble Beach", day, day.AddDate(0, 0, 1))
+			require.NoError(t, err)
+			assert.Len(t, entries, 2, "outsiders don't find the organization's TTR")
+
+			entries, err = b.schedule.FindSchedule(ctx, outsider.ID, "Pebble Beach", day, day.AddDate(0, 0, 7))
+			require.NoError(t, err)
+			require.Len(t, entries, 3)
+			assert.Equal(t, nextDay.ID, entries[2].TTRID)
+
+			entries, err = b.schedule.FindSchedule(ctx, outsider.ID, "Pebble Beach", day.AddDate(0, 0, 1), day.AddDate(0, 0, 2))
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, nextDay.ID, entries[0].TTRID)
+
+			entries, err = b.schedule.FindSchedule(ctx, outsider.ID, "Augusta National", day, day.AddDate(0, 0, 7))
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRInvariants(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			ttr := b.createTTR(t, captain.ID, nil)
+
+			ttr.MaxPlayers = 0
+			assert.ErrorIs(t, b.ttrs.Update(ctx, ttr), models.ErrTTRMaxPlayers)
+			ttr.MaxPlayers = 4
+			ttr.CourseName = ""
+			assert.ErrorIs(t, b.ttrs.Update(ctx, ttr), models.ErrTTRCourseName)
+
+			err := b.ttrs.Create(ctx, &models.TTR{CourseName: "", CreatedByUserID: captain.ID, CaptainUserID: captain.ID, TeeDate: time.Now()})
+			assert.ErrorIs(t, err, models.ErrTTRCourseName)
+
+			stored, err := b.ttrs.FindByID(ctx, ttr.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Pebble Beach", stored.CourseName, "rejected updates aren't written")
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRConditionFilters(t *testing.T) {
+	ctx := context.Background()
+	nine, eighteen, twelve := 9, 18, 12
+	cheap, pricey, negative := 4500, 12000, -1
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			captain := b.createUser(t, "Captain")
+			withConditions := func(conditions models.CourseConditions) *models.TTR {
+				ttr := b.createTTR(t, captain.ID, nil)
+				ttr.CourseConditions = conditions
+				require.NoError(t, b.ttrs.Update(ctx, ttr))
+				return ttr
+			}
+			cheapNine := withConditions(models.CourseConditions{Holes: &nine, GreenFeeCents: &cheap})
+			priceyNine := withConditions(models.CourseConditions{Holes: &nine, GreenFeeCents: &pricey})
+			cheapEighteen := withConditions(models.CourseConditions{Holes: &eighteen, GreenFeeCents: &cheap})
+			b.createTTR(t, captain.ID, nil)
+
+			find := func(filter repository.TTRSearchFilter) []uuid.UUID {
+				t.Helper()
+				filter.Limit = 20
+				ttrs, err := b.ttrs.FindAll(ctx, captain.ID, filter)
+				require.NoError(t, err)
+				return ttrIDs(ttrs)
+			}
+
+			assert.Len(t, find(repository.TTRSearchFilter{}), 4)
+			assert.ElementsMatch(t, []uuid.UUID{cheapNine.ID, priceyNine.ID}, find(repository.TTRSearchFilter{Holes: &nine}))
+			assert.ElementsMatch(t, []uuid.UUID{cheapNine.ID, cheapEighteen.ID}, find(repository.TTRSearchFilter{MaxGreenFeeCents: &cheap}),
+				"the bound is inclusive and TTRs without a fee are left out")
+			assert.Equal(t, []uuid.UUID{cheapNine.ID}, find(repository.TTRSearchFilter{Holes: &nine, MaxGreenFeeCents: &cheap}))
+
+			stored, err := b.ttrs.FindByID(ctx, priceyNine.ID)
+			require.NoError(t, err)
+			require.NotNil(t, stored.Holes)
+			assert.Equal(t, 9, *stored.Holes)
+			require.NotNil(t, stored.GreenFeeCents)
+			assert.Equal(t, pricey, *stored.GreenFeeCents)
+
+			stored.Holes = &twelve
+			assert.ErrorIs(t, b.ttrs.Update(ctx, stored), models.ErrTTRHoles)
+			stored.Holes = &nine
+			stored.GreenFeeCents = &negative
+			assert.ErrorIs(t, b.ttrs.Update(ctx, stored), models.ErrTTRGreenFee)
+		})
+	}
+}
+
+func TestRepositoryBackends_TTRParticipationFilters(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			me := b.createUser(t, "Me")
+			other := b.createUser(t, "Other")
+			invite := func(ttr *models.TTR, status models.InvitationStatus) {
+				require.NoError(t, b.invitations.Create(ctx, &models.Invitation{TTRID: ttr.ID, InviterUserID: other.ID, InviteeUserID: me.ID, Status: status}))
+			}
+
+			captained := b.createTTR(t, me.ID, nil)
+			coCaptained := b.createTTR(t, other.ID, nil)
+			require.NoError(t, b.ttrs.AddCoCaptain(ctx, coCaptained.ID, me.ID, models.CoCaptainPermissions))
+			played := b.createTTR(t, other.ID, nil)
+			require.NoError(t, b.ttrs.AddPlayer(ctx, played.ID, me.ID, models.TTRPlayerStatusConfirmed))
+			invited := b.createTTR(t, other.ID, nil)
+			invited.Visibility = models.TTRVisibilityPrivate
+			require.NoError(t, b.ttrs.Update(ctx, invited))
+			invite(invited, models.InvitationStatusPending)
+			invitedLater := b.createTTR(t, other.ID, nil)
+			invitedLater.TeeDate = invitedLater.TeeDate.AddDate(0, 0, 7)
+			require.NoError(t, b.ttrs.Update(ctx, invitedLater))
+			invite(invitedLater, models.InvitationStatusPending)
+			answered := b.createTTR(t, other.ID, nil)
+			invite(answered, models.InvitationStatusYes)
+			invitedCancelled := b.createTTR(t, other.ID, nil)
+			invite(invitedCancelled, models.InvitationStatusPending)
+			require.NoError(t, b.ttrs.UpdateStatus(ctx, invitedCancelled.ID, models.TTRStatusCancelled))
+			b.createTTR(t, other.ID, nil)
+
+			find := func(filter repository.TTRSearchFilter) []uuid.UUID {
+				t.Helper()
+				filter.Limit = 20
+				ttrs, err := b.ttrs.FindAll(ctx, me.ID, filter)
+				require.NoError(t, err)
+				return ttrIDs(ttrs)
+			}
+
+			assert.Len(t, find(repository.TTRSearchFilter{}), 8)
+			assert.Equal(t, []uuid.UUID{captained.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationCaptain}))
+			assert.Equal(t, []uuid.UUID{coCaptained.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationCoCaptain}))
+			assert.Equal(t, []uuid.UUID{played.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationPlayer}))
+			assert.Equal(t, []uuid.UUID{invited.ID, invitedLater.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationInvited}),
+				"only pending invitations to TTRs still open, by tee date")
+
+			day := invited.TeeDate
+			assert.Equal(t, []uuid.UUID{invited.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationInvited, TeeFrom: &day, TeeTo: &day}),
+				"the date bounds are inclusive")
+			later := invitedLater.TeeDate
+			assert.Equal(t, []uuid.UUID{invitedLater.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationInvited, TeeFrom: &later}))
+			assert.Empty(t, find(repository.TTRSearchFilter{Mine: models.TTRParticipationInvited, Status: models.TTRStatusCancelled}))
+			assert.Equal(t, []uuid.UUID{played.ID}, find(repository.TTRSearchFilter{Mine: models.TTRParticipationPlayer, Status: models.TTRStatusOpen, TeeTo: &day}))
+			assert.Empty(t, find(repository.TTRSearchFilter{Mine: models.TTRParticipationPlayer, Status: models.TTRStatusConfirmed}))
+		})
+	}
+}
+
+func TestRepositoryBackends_InvitationTemplates(t *testing.T) {
+	ctx := context.Background()
+
+	for _, b := range setupRepositoryBackends(t) {
+		t.Run(b.name, func(t *testing.T) {
+			owner := b.createUser(t, "Owner")
+			other := b.createUser(t, "Other")
+
+			weekend := &models.InvitationTemplate{UserID: owner.ID, Name: "Weekend", Body: "See you at {{course}}"}
+			require.NoError(t, b.templates.Create(ctx, weekend))
+			assert.NotEqual(t, uuid.Nil, weekend.ID)
+			early := &models.InvitationTemplate{UserID: owner.ID, Name: "Early", Body: "{{time}} sharp"}
+			require.NoError(t, b.templates.Create(ctx, early))
+			require.NoError(t, b.templates.Create(ctx, &models.InvitationTemplate{UserID: other.ID, Name: "Other", Body: "Hi"}))
+
+			templates, err := b.templates.FindByUserID(ctx, owner.ID)
+			require.NoError(t, err)
+			require.Len(t, templates, 2)
+			assert.Equal(t, "Early", templates[0].Name, "templates come by name")
+			assert.Equal(t, "Weekend", templates[1].Name)
+
+			weekend.Body = "See you at {{course}} on {{date}}"
+			require.NoError(t, b.templates.Update(ctx, weekend))
+			found, err := b.templates.FindByID(ctx, weekend.ID)
+			require.NoError(t, err)
+			require.NotNil(t, found)
+			assert.Equal(t, "See you at {{course}} on {{date}}", found.Body)
+			assert.Equal(t, owner.ID, found.UserID)
+
+			require.NoError(t, b.templates.Delete(ctx, weekend.ID))
+			found, err = b.templates.FindByID(ctx, weekend.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found)
+		})
+	}
+}
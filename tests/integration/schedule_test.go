@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestScheduleAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captain := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, player := registerTestUser(t, api, "player@example.com", "Player")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+	confirmed := models.TTRPlayerStatusConfirmed
+
+	first := seedRound(t, db, captain, "2030-05-01", "Pebble Beach", models.TTRStatusOpen, map[string]models.TTRPlayerStatus{captain: confirmed, player: confirmed})
+	private := seedRound(t, db, captain, "2030-05-01", "pebble beach", models.TTRStatusOpen, map[string]models.TTRPlayerStatus{player: models.TTRPlayerStatusMaybe})
+	require.NoError(t, db.Model(&models.TTR{}).Where("id = ?", private).Updates(map[string]interface{}{
+		"visibility": models.TTRVisibilityPrivate,
+		"tee_time":   "07:00:00",
+	}).Error)
+	later := seedRound(t, db, captain, "2030-05-04", "Pebble Beach", models.TTRStatusOpen, nil)
+	seedRound(t, db, captain, "2030-05-01", "Pebble Beach", models.TTRStatusCancelled, nil)
+	seedRound(t, db, captain, "2030-05-01", "Spyglass Hill", models.TTRStatusOpen, nil)
+	seedRound(t, db, captain, "2030-05-08", "Pebble Beach", models.TTRStatusOpen, nil)
+
+	getSheet := func(token string, query string) handler.TeeSheetResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/schedule"+query, token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var sheet handler.TeeSheetResponse
+		require.NoError(t, json.Unmarshal(env.Data, &sheet))
+		return sheet
+	}
+	dayIDs := func(day handler.TeeSheetDayResponse) []string {
+		ids := make([]string, len(day.TTRs))
+		for i, entry := range day.TTRs {
+			ids[i] = entry.TTRID
+		}
+		return ids
+	}
+
+	sheet := getSheet(playerToken, "?date=2030-05-01&course=PEBBLE%20BEACH")
+	assert.Equal(t, "PEBBLE BEACH", sheet.CourseName)
+	require.Len(t, sheet.Days, 1)
+	assert.Equal(t, "2030-05-01", sheet.Days[0].Date)
+	assert.Equal(t, []string{private, first}, dayIDs(sheet.Days[0]), "by tee time")
+
+	entry := sheet.Days[0].TTRs[1]
+	assert.Equal(t, "08:30", entry.TeeTime)
+	assert.Equal(t, string(models.TTRStatusOpen), entry.Status)
+	assert.Equal(t, "Captain Tester", entry.CaptainName)
+	assert.Equal(t, 2, entry.ConfirmedCount)
+	assert.Equal(t, 6, entry.OpenSlots)
+	assert.Equal(t, 0, sheet.Days[0].TTRs[0].ConfirmedCount, "maybes aren't confirmed")
+	assert.Equal(t, 7, sheet.Days[0].TTRs[0].OpenSlots)
+
+	sheet = getSheet(outsiderToken, "?date=2030-05-01&course=Pebble%20Beach")
+	require.Len(t, sheet.Days, 1)
+	assert.Equal(t, []string{first}, dayIDs(sheet.Days[0]), "outsiders don't see the private TTR")
+
+	sheet = getSheet(captainToken, "?date=2030-05-01&course=Pebble%20Beach&week=true")
+	require.Len(t, sheet.Days, 7, "every day of the week is listed")
+	assert.Equal(t, "2030-05-01", sheet.Days[0].Date)
+	assert.Equal(t, "2030-05-07", sheet.Days[6].Date)
+	assert.Equal(t, []string{private, first}, dayIDs(sheet.Days[0]))
+	assert.Equal(t, []string{later}, dayIDs(sheet.Days[3]))
+	for _, i := range []int{1, 2, 4, 5, 6} {
+		assert.NotNil(t, sheet.Days[i].TTRs)
+		assert.Empty(t, sheet.Days[i].TTRs, sheet.Days[i].Date)
+	}
+
+	for _, query := range []string{
+		"?date=2030-05-01",
+		"?date=2030-05-01&course=%20",
+		"?date=05/01/2030&course=Pebble%20Beach",
+		"?course=Pebble%20Beach",
+		"?date=2030-05-01&course=Pebble%20Beach&week=often",
+	} {
+		code, _ := doJSON(t, api, "GET", "/api/v1/ttrs/schedule"+query, captainToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	code, _ := doJSON(t, api, "GET", "/api/v1/ttrs/schedule?date=2030-05-01&course=Pebble%20Beach", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestSecurityEventsAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	token, _ := registerTestUser(t, api, "ann@example.com", "Ann")
+	code, _ := doJSON(t, api, "POST", "/api/v1/auth/login", "", map[string]string{"email": "ann@example.com", "password": "wrong-password"})
+	require.Equal(t, http.StatusUnauthorized, code)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me/password", token, map[string]string{"old_password": "password123", "new_password": "password456"})
+	require.Equal(t, http.StatusOK, code)
+
+	code, env := doJSON(t, api, "GET", "/api/v1/users/me/security-events?limit=2", token, nil)
+	require.Equal(t, http.StatusOK, code)
+	var page handler.PageResponse[handler.SecurityEventResponse]
+	require.NoError(t, json.Unmarshal(env.Data, &page))
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, models.SecurityEventPasswordChanged, page.Items[0].Type)
+	assert.Equal(t, models.SecurityEventLoginFailed, page.Items[1].Type)
+	assert.Equal(t, "192.0.2.1", page.Items[0].IPAddress, "events record the client's IP")
+	require.NotNil(t, page.NextOffset)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/users/me/security-events?offset=2", token, nil)
+	require.Equal(t, http.StatusOK, code)
+	page = handler.PageResponse[handler.SecurityEventResponse]{}
+	require.NoError(t, json.Unmarshal(env.Data, &page))
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, models.SecurityEventLogin, page.Items[0].Type)
+	assert.Nil(t, page.NextOffset)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/users/me/security-events", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/pkg/slack"
+)
+
+// doSlackCommand posts a /golf command signed with secret, the way Slack
+// sends it.
+func doSlackCommand(t *testing.T, h http.Handler, secret string, slackUserID string, text string) (int, handler.SlackCommandResponse) {
+	t.Helper()
+
+	body := url.Values{
+		"team_id": {"T0001"},
+		"user_id": {slackUserID},
+		"command": {"/golf"},
+		"text":    {text},
+	}.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req := httptest.NewRequest("POST", "/api/v1/integrations/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set(slack.TimestampHeader, timestamp)
+	req.Header.Set(slack.SignatureHeader, slack.Sign(secret, timestamp, []byte(body)))
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var reply handler.SlackCommandResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reply), "body: %s", w.Body.String())
+	}
+	return w.Code, reply
+}
+
+func TestSlackAPI_Commands(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	token, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	teeDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	t.Run("unsigned requests are refused", func(t *testing.T) {
+		code, _ := doSlackCommand(t, api, "not-the-secret", "U0001", "help")
+		assert.Equal(t, http.StatusUnauthorized, code)
+	})
+
+	t.Run("unlinked users are told to link", func(t *testing.T) {
+		code, reply := doSlackCommand(t, api, testSlackSigningSecret, "U0001", "Bethpage Black "+teeDate+" 07:30 4")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ephemeral", reply.ResponseType)
+		assert.Contains(t, reply.Text, "/golf link")
+	})
+
+	t.Run("a linked user creates a TTR with a share link", func(t *testing.T) {
+		code, reply := doSlackCommand(t, api, testSlackSigningSecret, "U0001", "link")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ephemeral", reply.ResponseType)
+		match := regexp.MustCompile(`\*([A-Z0-9]+)\*`).FindStringSubmatch(reply.Text)
+		require.Len(t, match, 2, reply.Text)
+		linkCode := match[1]
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/integrations/slack/link", token, map[string]string{"code": strings.ToLower(linkCode)})
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "POST", "/api/v1/integrations/slack/link", token, map[string]string{"code": linkCode})
+		assert.Equal(t, http.StatusBadRequest, code, "codes work once")
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_SLACK_LINK_CODE", env.Error.Code)
+
+		code, reply = doSlackCommand(t, api, testSlackSigningSecret, "U0001", "Bethpage Black "+teeDate+" 07:30 4")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "in_channel", reply.ResponseType)
+		assert.Contains(t, reply.Text, "*Bethpage Black*")
+		assert.Contains(t, reply.Text, "<@U0001>")
+
+		shareLink := regexp.MustCompile(`<(https://[^|>]+)\|`).FindStringSubmatch(reply.Text)
+		require.Len(t, shareLink, 2, reply.Text)
+		require.True(t, strings.HasPrefix(shareLink[1], testSlackPublicURL+"/api/v1/public/invite-links/"))
+		code, env = doJSON(t, api, "GET", strings.TrimPrefix(shareLink[1], testSlackPublicURL), "", nil)
+		require.Equal(t, http.StatusOK, code)
+		var preview handler.InviteLinkPreviewResponse
+		require.NoError(t, json.Unmarshal(env.Data, &preview))
+		assert.Equal(t, "Bethpage Black", preview.CourseName)
+		assert.Equal(t, teeDate, preview.TeeDate)
+		assert.Equal(t, "07:30", preview.TeeTime)
+		assert.Equal(t, 3, preview.OpenSlots)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		require.Len(t, ttrs, 1)
+		assert.Equal(t, "Bethpage Black", ttrs[0].CourseName)
+		assert.Equal(t, 4, ttrs[0].MaxPlayers)
+	})
+
+	t.Run("mistakes get the usage", func(t *testing.T) {
+		code, reply := doSlackCommand(t, api, testSlackSigningSecret, "U0001", "Bethpage Black tomorrow")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "ephemeral", reply.ResponseType)
+		assert.Contains(t, reply.Text, slack.Usage)
+	})
+}
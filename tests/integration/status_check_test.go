@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestStatusChecks_RejectUnknownValues(t *testing.T) {
+	db := setupTTRTestDB(t)
+
+	captain := models.User{ID: uuid.New(), Email: "captain@example.com", PasswordHash: "hash", FirstName: "Cap", LastName: "Tain"}
+	invitee := models.User{ID: uuid.New(), Email: "invitee@example.com", PasswordHash: "hash", FirstName: "In", LastName: "Vitee"}
+	require.NoError(t, db.Create(&captain).Error)
+	require.NoError(t, db.Create(&invitee).Error)
+	ttr := models.TTR{
+		ID:              uuid.New(),
+		CourseName:      "Torrey Pines",
+		TeeDate:         time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 0, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captain.ID,
+		CaptainUserID:   captain.ID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPrivate,
+	}
+	require.NoError(t, db.Create(&ttr).Error)
+	require.NoError(t, db.Create(&models.TTRPlayer{TTRID: ttr.ID, UserID: captain.ID, Status: models.TTRPlayerStatusConfirmed}).Error)
+	invitation := models.Invitation{ID: uuid.New(), TTRID: ttr.ID, InviterUserID: captain.ID, InviteeUserID: invitee.ID, Status: models.InvitationStatusPending}
+	require.NoError(t, db.Create(&invitation).Error)
+
+	// Raw SQL skips the Go types, as a manual edit would.
+	tests := []struct {
+		name string
+		sql  string
+		args []interface{}
+	}{
+		{"ttr status", "UPDATE ttrs SET status = ? WHERE id = ?", []interface{}{"ARCHIVED", ttr.ID}},
+		{"ttr pre-delete status", "UPDATE ttrs SET pre_delete_status = ? WHERE id = ?", []interface{}{"open", ttr.ID}},
+		{"ttr player status", "UPDATE ttr_players SET status = ? WHERE ttr_id = ?", []interface{}{"ACCEPTED", ttr.ID}},
+		{"invitation status", "UPDATE invitations SET status = ? WHERE id = ?", []interface{}{"DECLINED", invitation.ID}},
+		{"new invitation", "INSERT INTO invitations (id, ttr_id, inviter_user_id, invitee_user_id, status) VALUES (?, ?, ?, ?, ?)", []interface{}{uuid.New(), ttr.ID, captain.ID, invitee.ID, "SENT"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.Exec(tt.sql, tt.args...).Error
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "CHECK constraint failed")
+		})
+	}
+
+	require.NoError(t, db.Exec("UPDATE ttrs SET status = ? WHERE id = ?", "CONFIRMED", ttr.ID).Error, "known values still go through")
+	var stored models.TTR
+	require.NoError(t, db.First(&stored, "id = ?", ttr.ID).Error)
+	assert.Equal(t, models.TTRStatusConfirmed, stored.Status)
+}
@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+)
+
+func TestSuggestionAPI_SuggestedPlayers(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	ttrRepo := repository.NewTTRRepository(db)
+	ctx := context.Background()
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	_, oftenID := registerTestUser(t, api, "often@example.com", "Often")
+	_, onceID := registerTestUser(t, api, "once@example.com", "Once")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	_, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+
+	// Past rounds are created directly, since the API only schedules future
+	// ones.
+	pastRound := func(userIDs ...string) {
+		ttr := &models.TTR{
+			CourseName:      "Torrey Pines",
+			TeeDate:         time.Now().AddDate(0, 0, -14).Truncate(24 * time.Hour),
+			TeeTime:         time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC),
+			MaxPlayers:      4,
+			CreatedByUserID: uuid.MustParse(captainID),
+			CaptainUserID:   uuid.MustParse(captainID),
+			Status:          models.TTRStatusCompleted,
+		}
+		require.NoError(t, ttrRepo.Create(ctx, ttr))
+		for _, id := range userIDs {
+			require.NoError(t, ttrRepo.AddPlayer(ctx, ttr.ID, uuid.MustParse(id), models.TTRPlayerStatusConfirmed))
+		}
+	}
+	pastRound(captainID, oftenID, onceID, playerID, inviteeID)
+	pastRound(captainID, oftenID)
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+		"ttr_id":          ttrID,
+		"invitee_user_id": inviteeID,
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/suggested-players", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var suggestions []handler.SuggestedPlayerResponse
+	require.NoError(t, json.Unmarshal(env.Data, &suggestions))
+	require.Len(t, suggestions, 2, "players and pending invitees are left out")
+	assert.Equal(t, oftenID, suggestions[0].User.ID)
+	assert.Equal(t, "played_together", suggestions[0].Reason)
+	assert.Equal(t, 2, suggestions[0].SharedRounds)
+	assert.Equal(t, onceID, suggestions[1].User.ID)
+	assert.Equal(t, 1, suggestions[1].SharedRounds)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID+"/suggested-players", playerToken, nil)
+	assert.Equal(t, http.StatusForbidden, code)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+uuid.NewString()+"/suggested-players", captainToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+// slotPlayers maps each slot number to the sorted first names of its players.
+func slotPlayers(ttr handler.TTRResponse) map[int][]string {
+	slots := make(map[int][]string, len(ttr.Slots))
+	for _, slot := range ttr.Slots {
+		names := []string{}
+		for _, player := range slot.Players {
+			names = append(names, player.User.FirstName)
+		}
+		sort.Strings(names)
+		slots[slot.Number] = names
+	}
+	return slots
+}
+
+func TestTeeSlotAPI(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 6,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &created))
+	ttrID := created.ID
+
+	// Join order is set by hand, as joins within a second tie in SQLite.
+	ids := map[string]string{"Captain": captainID}
+	joinedAt := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Model(&models.TTRPlayer{}).Where("ttr_id = ? AND user_id = ?", ttrID, captainID).Update("joined_at", joinedAt).Error)
+	var playerToken string
+	for i, name := range []string{"Ada", "Bea", "Cal", "Dee", "Eve"} {
+		token, id := registerTestUser(t, api, name+"@example.com", name)
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, db.Model(&models.TTRPlayer{}).Where("ttr_id = ? AND user_id = ?", ttrID, id).Update("joined_at", joinedAt.Add(time.Duration(i+1)*time.Minute)).Error)
+		ids[name] = id
+		playerToken = token
+	}
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/players/"+ids["Eve"], captainToken, map[string]string{"status": string(models.TTRPlayerStatusMaybe)})
+	require.Equal(t, http.StatusOK, code)
+
+	setSlots := func(token string, body map[string]interface{}) (int, apiEnvelope) {
+		t.Helper()
+		return doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/slots", token, body)
+	}
+	getTTR := func() handler.TTRResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttrID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		return ttr
+	}
+
+	t.Run("slots must hold the confirmed players", func(t *testing.T) {
+		code, env := setSlots(captainToken, map[string]interface{}{"slots": []map[string]int{
+			{"offset_minutes": 0, "capacity": 2},
+			{"offset_minutes": 10, "capacity": 2},
+		}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "SLOT_CAPACITY_TOO_SMALL", env.Error.Code)
+		assert.Empty(t, getTTR().Slots)
+	})
+
+	t.Run("invalid slots", func(t *testing.T) {
+		code, env := setSlots(captainToken, map[string]interface{}{"slots": []map[string]int{
+			{"offset_minutes": 10},
+			{"offset_minutes": 0},
+		}})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_TEE_SLOTS", env.Error.Code)
+
+		code, _ = setSlots(captainToken, map[string]interface{}{"slots": []map[string]int{{"offset_minutes": 0, "capacity": 5}}})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+		code, env = setSlots(playerToken, map[string]interface{}{"slots": []map[string]int{{"offset_minutes": 0}}})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+	})
+
+	t.Run("auto-fill deals players out in join order", func(t *testing.T) {
+		code, env := setSlots(captainToken, map[string]interface{}{
+			"slots": []map[string]int{
+				{"offset_minutes": 0, "capacity": 2},
+				{"offset_minutes": 10},
+				{"offset_minutes": 20, "capacity": 1},
+			},
+			"auto_fill": true,
+		})
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+		require.Len(t, ttr.Slots, 3)
+		assert.Equal(t, []string{"08:30", "08:40", "08:50"}, []string{ttr.Slots[0].TeeTime, ttr.Slots[1].TeeTime, ttr.Slots[2].TeeTime})
+		assert.Equal(t, 4, ttr.Slots[1].Capacity, "capacity defaults to a foursome")
+		want := map[int][]string{
+			1: {"Cal", "Captain"},
+			2: {"Ada", "Dee"},
+			3: {"Bea"},
+		}
+		assert.Equal(t, want, slotPlayers(ttr))
+		require.Len(t, ttr.Players, 1, "players who aren't confirmed stay out of the slots")
+		assert.Equal(t, ids["Eve"], ttr.Players[0].UserID)
+
+		assert.Equal(t, want, slotPlayers(getTTR()))
+	})
+
+	t.Run("pairings are limited to the slots", func(t *testing.T) {
+		pairings := map[string]int{
+			ids["Captain"]: 1, ids["Cal"]: 1,
+			ids["Ada"]: 2, ids["Dee"]: 2,
+			ids["Bea"]: 3, ids["Eve"]: 3,
+		}
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/pairings", captainToken, map[string]interface{}{"pairings": pairings})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "PAIRING_GROUP_FULL", env.Error.Code)
+
+		pairings[ids["Eve"]] = 4
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/pairings", captainToken, map[string]interface{}{"pairings": pairings})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TEE_SLOT_NOT_FOUND", env.Error.Code)
+
+		pairings[ids["Eve"]] = 2
+		code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/pairings", captainToken, map[string]interface{}{"pairings": pairings})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"Ada", "Dee", "Eve"}, slotPlayers(getTTR())[2])
+	})
+
+	t.Run("replacing slots keeps assignments that still fit", func(t *testing.T) {
+		code, env := setSlots(captainToken, map[string]interface{}{"slots": []map[string]int{
+			{"offset_minutes": 0},
+			{"offset_minutes": 10},
+		}})
+		require.Equal(t, http.StatusOK, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		assert.Equal(t, map[int][]string{1: {"Cal", "Captain"}, 2: {"Ada", "Dee", "Eve"}}, slotPlayers(ttr))
+		require.Len(t, ttr.Players, 1)
+		assert.Equal(t, ids["Bea"], ttr.Players[0].UserID)
+	})
+
+	t.Run("removing slots falls back to the flat roster", func(t *testing.T) {
+		code, _ := setSlots(captainToken, map[string]interface{}{"slots": []map[string]int{}})
+		require.Equal(t, http.StatusOK, code)
+
+		ttr := getTTR()
+		assert.Empty(t, ttr.Slots)
+		require.Len(t, ttr.Players, 6)
+		assert.Equal(t, 1, playerGroup(t, ttr.Players, ids["Cal"]), "pairings are kept")
+	})
+}
+
+func playerGroup(t *testing.T, players []handler.TTRPlayerResponse, userID string) int {
+	t.Helper()
+	for _, player := range players {
+		if player.UserID == userID {
+			return player.GroupNumber
+		}
+	}
+	require.Failf(t, "player not on the roster", "user %s", userID)
+	return 0
+}
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestTournamentAPI_RoundTTRsAndResults(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	ownerToken, _ := registerTestUser(t, api, "owner@example.com", "Owner")
+	tokens := make([]string, 3)
+	ids := make([]string, 3)
+	for i, name := range []string{"Seed", "Second", "Third"} {
+		tokens[i], ids[i] = registerTestUser(t, api, name+"@example.com", name)
+	}
+	sourceTTR := createTestTTR(t, api, ownerToken)
+
+	code, _ := doJSON(t, api, "POST", "/api/v1/tournaments", tokens[0], map[string]interface{}{
+		"name": "Club Matchplay", "player_ids": ids, "ttr_id": sourceTTR,
+	})
+	assert.Equal(t, http.StatusForbidden, code, "only the TTR's managers can draw from it")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/tournaments", ownerToken, map[string]interface{}{
+		"name": "Club Matchplay", "player_ids": ids, "ttr_id": sourceTTR,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var tournament handler.TournamentResponse
+	require.NoError(t, json.Unmarshal(env.Data, &tournament))
+	require.Len(t, tournament.Matches, 3)
+	tournamentPath := "/api/v1/tournaments/" + tournament.ID
+
+	bye, semi, final := tournament.Matches[0], tournament.Matches[1], tournament.Matches[2]
+	assert.True(t, bye.Walkover)
+	assert.Equal(t, models.MatchStatusScheduled, semi.Status)
+	assert.Equal(t, models.MatchStatusPending, final.Status)
+
+	teeDate := time.Now().AddDate(0, 0, 14).Format("2006-01-02")
+	code, _ = doJSON(t, api, "POST", tournamentPath+"/rounds/2/ttr", ownerToken, map[string]string{"tee_date": teeDate})
+	assert.Equal(t, http.StatusBadRequest, code, "the final's players aren't known yet")
+
+	code, env = doJSON(t, api, "POST", tournamentPath+"/rounds/1/ttr", ownerToken, map[string]string{"tee_date": teeDate, "tee_time": "07:30"})
+	require.Equal(t, http.StatusCreated, code)
+	var roundTTR handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &roundTTR))
+	assert.Equal(t, "Pebble Beach", roundTTR.CourseName)
+	assert.Equal(t, 2, roundTTR.MaxPlayers, "the walkover winner isn't playing")
+	code, _ = doJSON(t, api, "POST", tournamentPath+"/rounds/1/ttr", ownerToken, map[string]string{"tee_date": teeDate})
+	assert.Equal(t, http.StatusConflict, code)
+
+	resultPath := tournamentPath + "/matches/" + semi.ID + "/result"
+	code, _ = doJSON(t, api, "POST", resultPath, tokens[0], map[string]string{"winner_user_id": ids[2]})
+	assert.Equal(t, http.StatusForbidden, code, "the bye winner isn't in this match")
+
+	code, _ = doJSON(t, api, "POST", resultPath, tokens[2], map[string]string{"winner_user_id": ids[2]})
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", resultPath, tokens[1], map[string]string{"winner_user_id": ids[1]})
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, env = doJSON(t, api, "POST", tournamentPath+"/matches/"+final.ID+"/result", ownerToken, map[string]string{"winner_user_id": ids[0]})
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(env.Data, &tournament))
+	assert.Equal(t, models.TournamentStatusCompleted, tournament.Status)
+	require.NotNil(t, tournament.WinnerUserID)
+	assert.Equal(t, ids[0], *tournament.WinnerUserID)
+	require.NotNil(t, tournament.Matches[1].TTRID)
+	assert.Equal(t, roundTTR.ID, *tournament.Matches[1].TTRID)
+
+	code, env = doJSON(t, api, "GET", tournamentPath, tokens[1], nil)
+	require.Equal(t, http.StatusOK, code)
+	var fetched handler.TournamentResponse
+	require.NoError(t, json.Unmarshal(env.Data, &fetched))
+	assert.Equal(t, models.MatchStatusCompleted, fetched.Matches[2].Status)
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	return recorder
+}
+
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracing_TTRCreateSpanHierarchy(t *testing.T) {
+	recorder := setupSpanRecorder(t)
+
+	db := setupTTRTestDB(t)
+	require.NoError(t, db.Use(otelgorm.NewPlugin()))
+	api := newTestAPI(t, db)
+
+	token, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+
+	// Only look at spans produced by the create request.
+	before := len(recorder.Ended())
+
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs", token, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	spans := recorder.Ended()[before:]
+
+	var server sdktrace.ReadOnlySpan
+	for _, span := range spans {
+		if span.SpanKind() == trace.SpanKindServer {
+			require.Nil(t, server, "expected a single server span")
+			server = span
+		}
+	}
+	require.NotNil(t, server)
+
+	assert.Equal(t, "POST /api/v1/ttrs", server.Name())
+	assert.False(t, server.Parent().IsValid(), "server span is the root")
+
+	route, ok := spanAttribute(server, "http.route")
+	require.True(t, ok)
+	assert.Equal(t, "/api/v1/ttrs", route.AsString())
+
+	status, ok := spanAttribute(server, "http.response.status_code")
+	require.True(t, ok)
+	assert.Equal(t, int64(http.StatusCreated), status.AsInt64())
+
+	requestID, ok := spanAttribute(server, "request_id")
+	require.True(t, ok)
+	assert.NotEmpty(t, requestID.AsString())
+
+	bySpanID := make(map[trace.SpanID]sdktrace.ReadOnlySpan, len(spans))
+	for _, span := range spans {
+		bySpanID[span.SpanContext().SpanID()] = span
+	}
+
+	// Every span must hang off the server span, directly or through a parent
+	// gorm span (preloads run inside the outer query's span).
+	var dbSpans, directChildren int
+	for _, span := range spans {
+		if span == server {
+			continue
+		}
+		assert.Equal(t, server.SpanContext().TraceID(), span.SpanContext().TraceID(), "span %q", span.Name())
+
+		if span.Parent().SpanID() == server.SpanContext().SpanID() {
+			directChildren++
+		}
+		ancestor := span
+		for ancestor != server {
+			parent, ok := bySpanID[ancestor.Parent().SpanID()]
+			if !assert.True(t, ok, "span %q is detached from the request", span.Name()) {
+				break
+			}
+			ancestor = parent
+		}
+
+		if _, ok := spanAttribute(span, "db.system"); ok {
+			dbSpans++
+		}
+	}
+	assert.GreaterOrEqual(t, directChildren, 3, "insert TTR, insert captain, reload TTR")
+	assert.Equal(t, len(spans)-1, dbSpans)
+}
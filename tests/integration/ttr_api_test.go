@@ -0,0 +1,1430 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/database"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type apiEnvelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code       string          `json:"code"`
+		Message    string          `json:"message"`
+		MessageKey string          `json:"message_key"`
+		Details    json.RawMessage `json:"details"`
+	} `json:"error"`
+}
+
+func setupTTRTestDB(t testing.TB) *gorm.DB {
+	db := setupTestDB(t)
+
+	err := db.AutoMigrate(
+		&models.TTR{},
+		&models.TTRCoCaptain{},
+		&models.TTRPlayer{},
+		&models.TTRGuest{},
+		&models.TeeSlot{},
+		&models.Invitation{},
+		&models.InvitationTemplate{},
+		&models.Message{},
+		&models.MessageRead{},
+		&models.MessageReaction{},
+		&models.Organization{},
+		&models.OrganizationMember{},
+		&models.OrganizationInvitation{},
+		&models.League{},
+		&models.Tournament{},
+		&models.Match{},
+		&models.Notification{},
+		&models.InviteLink{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.SlackAccount{},
+		&models.SlackLinkCode{},
+		&models.APIToken{},
+		&models.AuditLogEntry{},
+		&models.ImpersonationSession{},
+		&models.FeatureFlag{},
+		&models.TTREvent{},
+		&models.OutboxMessage{},
+		&models.EmailSuppression{},
+		&models.SecurityEvent{},
+		&models.KnownDevice{},
+		&models.Report{},
+		&models.UserBlock{},
+		&models.Friendship{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate TTR tables: %v", err)
+	}
+
+	return db
+}
+
+// The Slack app the test API accepts commands from.
+const (
+	testSlackSigningSecret = "slack-test-signing-secret"
+	testSlackPublicURL     = "https://golf.example.com"
+)
+
+// testReportsPerDay is how many abuse reports the test API takes from a user
+// in 24 hours.
+const testReportsPerDay = 3
+
+// The GraphQL limits of the test API.
+const (
+	testGraphQLMaxDepth      = 8
+	testGraphQLMaxComplexity = 200
+)
+
+func newTestAPI(t *testing.T, db *gorm.DB) http.Handler {
+	return newTestAPIWithStorage(t, db, storage.NewMemoryStorage(), config.MessagingConfig{
+		EditWindow:          15 * time.Minute,
+		AttachmentMaxSize:   10 << 20,
+		TTRAttachmentQuota:  200 << 20,
+		UserAttachmentQuota: 100 << 20,
+		AttachmentURLTTL:    15 * time.Minute,
+	})
+}
+
+// newTestAPIWithStorage builds the test API with the given attachment storage
+// and messaging limits. opts add router options such as WithV1Sunset.
+func newTestAPIWithStorage(t *testing.T, db *gorm.DB, store storage.Storage, messagingCfg config.MessagingConfig, opts ...router.Option) http.Handler {
+	return newTestRouter(t, db, store, messagingCfg, opts...).SetupRoutes()
+}
+
+// newTestRouter builds the Router behind the test API, before its routes are
+// set up.
+func newTestRouter(t *testing.T, db *gorm.DB, store storage.Storage, messagingCfg config.MessagingConfig, opts ...router.Option) *router.Router {
+	logger, _ := zap.NewDevelopment()
+
+	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	ttrRepo := repository.NewTTRRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo, nil, nil, 0, logger)
+	securityEventService := service.NewSecurityEventService(repository.NewSecurityEventRepository(db), notificationService)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, securityEventService, password.Policy{}, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	userService := service.NewUserService(userRepo, nil, securityEventService, password.Policy{}, config.AvatarConfig{})
+	authorizer := service.NewAuthorizer(ttrRepo, orgRepo, invitationRepo, userRepo)
+	changeFeedService := service.NewChangeFeedService(repository.NewTTREventRepository(db), authorizer, 30*24*time.Hour, logger)
+	historyService := service.NewHistoryService(repository.NewHistoryRepository(db), userRepo, cache.NewMemoryCache(), logger)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, transactor, authorizer, notificationService, nil, changeFeedService, nil, historyService, 7*24*time.Hour, 2*time.Hour, logger)
+	reportService := service.NewReportService(repository.NewReportRepository(db), userRepo, repository.NewMessageRepository(db), ttrRepo, notificationService, testReportsPerDay, logger)
+	invitationTemplateRepo := repository.NewInvitationTemplateRepository(db)
+	invitationService := service.NewInvitationService(invitationRepo, ttrRepo, userRepo, invitationTemplateRepo, ttrService, transactor, authorizer, notificationService, nil, nil, logger)
+	orgService := service.NewOrganizationService(orgRepo, userRepo, authorizer, transactor, notificationService, logger)
+	messageService := service.NewMessageService(repository.NewMessageRepository(db), authorizer, store, messagingCfg, changeFeedService, logger)
+	tournamentService := service.NewTournamentService(repository.NewTournamentRepository(db), ttrRepo, userRepo, authorizer, transactor, notificationService, logger)
+	suggestionService := service.NewSuggestionService(repository.NewSuggestionRepository(db), userRepo, authorizer)
+	leagueService := service.NewLeagueService(repository.NewLeagueRepository(db), ttrRepo, authorizer, cache.NewMemoryCache(), time.Hour, logger)
+	inviteLinkService := service.NewInviteLinkService(repository.NewInviteLinkRepository(db), ttrRepo, ttrService, authorizer, notificationService, logger)
+	apiTokenService := service.NewAPITokenService(repository.NewAPITokenRepository(db), logger)
+	maintenanceService := service.NewMaintenanceService(cache.NewMemoryCache(), logger)
+	flagService := service.NewFlagService(repository.NewFeatureFlagRepository(db), orgRepo, map[string]int{"messaging": 100}, logger)
+	impersonationService := service.NewImpersonationService(userRepo, repository.NewImpersonationRepository(db), repository.NewAuditLogRepository(db), "test-secret", 15*time.Minute, logger)
+	actionItemService := service.NewActionItemService(repository.NewActionItemRepository(db))
+	dashboardService := service.NewDashboardService(ttrRepo, invitationRepo, notificationRepo, actionItemService, logger)
+	slackService := service.NewSlackService(repository.NewSlackRepository(db), ttrService, inviteLinkService, config.SlackConfig{LinkCodeTTL: 15 * time.Minute, PublicURL: testSlackPublicURL}, logger)
+
+	opts = append([]router.Option{
+		router.WithAuth(handler.NewAuthHandler(authService)),
+		router.WithUsers(handler.NewUserHandler(userService)),
+		router.WithAPITokens(handler.NewAPITokenHandler(apiTokenService), apiTokenService),
+		router.WithSecurityEvents(handler.NewSecurityEventHandler(securityEventService)),
+		router.WithHistory(handler.NewHistoryHandler(historyService)),
+		router.WithReports(handler.NewReportHandler(reportService)),
+		router.WithSchedule(handler.NewScheduleHandler(service.NewScheduleService(repository.NewScheduleRepository(db)))),
+		router.WithImpersonation(handler.NewImpersonationHandler(impersonationService), impersonationService),
+		router.WithTTR(handler.NewTTRHandler(ttrService)),
+		router.WithInvitations(handler.NewInvitationHandler(invitationService)),
+		router.WithInvitationTemplates(handler.NewInvitationTemplateHandler(service.NewInvitationTemplateService(invitationTemplateRepo))),
+		router.WithMessages(handler.NewMessageHandler(messageService)),
+		router.WithSuggestions(handler.NewSuggestionHandler(suggestionService)),
+		router.WithPairings(handler.NewPairingHandler(service.NewPairingService(authorizer, 18))),
+		router.WithInviteLinks(handler.NewInviteLinkHandler(inviteLinkService)),
+		router.WithActionItems(handler.NewActionItemHandler(actionItemService)),
+		router.WithDashboard(handler.NewDashboardHandler(dashboardService)),
+		router.WithGraphQL(handler.NewGraphQLHandler(userService, ttrService, invitationService, testGraphQLMaxDepth, testGraphQLMaxComplexity, logger)),
+		router.WithChangeFeed(handler.NewChangeFeedHandler(changeFeedService)),
+		router.WithCheckIns(handler.NewCheckInHandler(service.NewCheckInService(ttrRepo, authorizer, notificationService, 2*time.Hour, time.Hour, logger))),
+		router.WithSlack(handler.NewSlackHandler(slackService, testSlackSigningSecret)),
+		router.WithWebhooks(handler.NewWebhookHandler(service.NewWebhookService(repository.NewWebhookRepository(db), authorizer, nil, config.WebhooksConfig{}, logger))),
+		router.WithOrganizations(handler.NewOrganizationHandler(orgService)),
+		router.WithLeagues(handler.NewLeagueHandler(leagueService)),
+		router.WithTournaments(handler.NewTournamentHandler(tournamentService)),
+		router.WithMeta(handler.NewMetaHandler()),
+		router.WithFlags(handler.NewFlagHandler(flagService)),
+		router.WithHealth(handler.NewHealthHandler(&database.Database{DB: db}, maintenanceService)),
+		router.WithMaintenance(handler.NewMaintenanceHandler(maintenanceService), maintenanceService),
+	}, opts...)
+
+	return router.New(logger, "test-secret", []string{"*"}, opts...)
+}
+
+func doJSON(t *testing.T, h http.Handler, method, path, token string, body interface{}) (int, apiEnvelope) {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &reqBody)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, req)
+
+	var env apiEnvelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &env), "body: %s", w.Body.String())
+	return w.Code, env
+}
+
+func registerTestUser(t *testing.T, h http.Handler, email, firstName string) (string, string) {
+	t.Helper()
+
+	code, env := doJSON(t, h, "POST", "/api/v1/auth/register", "", map[string]string{
+		"email":      email,
+		"password":   "password123",
+		"first_name": firstName,
+		"last_name":  "Tester",
+	})
+	require.Equal(t, http.StatusCreated, code)
+
+	var auth handler.AuthResponse
+	require.NoError(t, json.Unmarshal(env.Data, &auth))
+	return auth.AccessToken, auth.User.ID
+}
+
+func TestTTRAPI_Lifecycle(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+
+	teeDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	var ttr handler.TTRResponse
+	t.Run("create", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+			"course_name":     "Pebble Beach",
+			"course_location": "California",
+			"tee_date":        teeDate,
+			"tee_time":        "08:30",
+			"max_players":     4,
+			"visibility":      "PUBLIC",
+			"notes":           "Bring extra balls",
+		})
+		require.Equal(t, http.StatusCreated, code)
+		assert.True(t, env.Success)
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+		assert.Equal(t, "Pebble Beach", ttr.CourseName)
+		assert.Equal(t, teeDate, ttr.TeeDate)
+		assert.Equal(t, "08:30", ttr.TeeTime)
+		assert.Equal(t, captainID, ttr.CaptainUserID)
+		assert.Equal(t, models.TTRStatusOpen, ttr.Status)
+		require.Len(t, ttr.Players, 1)
+		assert.Equal(t, captainID, ttr.Players[0].UserID)
+	})
+
+	t.Run("create rejects invalid body", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+			"course_name": "Pebble Beach",
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.False(t, env.Success)
+		assert.Equal(t, "VALIDATION_ERROR", env.Error.Code)
+
+		var details []validator.FieldError
+		require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+		assert.Equal(t, []validator.FieldError{
+			{Field: "tee_date", Rule: "required", Message: "is required"},
+			{Field: "tee_time", Rule: "required", Message: "is required"},
+			{Field: "max_players", Rule: "required", Message: "is required"},
+		}, details)
+	})
+
+	t.Run("requires authentication", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs", "", nil)
+		assert.Equal(t, http.StatusUnauthorized, code)
+		assert.Equal(t, "UNAUTHORIZED", env.Error.Code)
+	})
+
+	t.Run("search with filters", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs?status=OPEN&limit=10", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var open []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &open))
+		require.Len(t, open, 1)
+		assert.Equal(t, ttr.ID, open[0].ID)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs?status=CANCELLED", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var cancelled []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &cancelled))
+		assert.Empty(t, cancelled)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs?mine=captain&from_date="+teeDate+"&to_date="+teeDate, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var mine []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &mine))
+		require.Len(t, mine, 1)
+		assert.Equal(t, ttr.ID, mine[0].ID)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs?mine=captain", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(env.Data, &mine))
+		assert.Empty(t, mine)
+
+		for _, query := range []string{"mine=owner", "from_date=tomorrow", "to_date=2024-13-01"} {
+			code, _ = doJSON(t, api, "GET", "/api/v1/ttrs?"+query, playerToken, nil)
+			assert.Equal(t, http.StatusBadRequest, code, query)
+			code, _ = doJSON(t, api, "GET", "/api/v2/ttrs?"+query, playerToken, nil)
+			assert.Equal(t, http.StatusBadRequest, code, query)
+		}
+	})
+
+	t.Run("non-manager cannot update", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, playerToken, map[string]interface{}{
+			"course_name": "Augusta National",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+	})
+
+	t.Run("non-manager cannot invite", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/invitations", playerToken, map[string]string{
+			"ttr_id":          ttr.ID,
+			"invitee_user_id": captainID,
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+	})
+
+	var invitation handler.InvitationResponse
+	t.Run("invite and respond", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+			"ttr_id":          ttr.ID,
+			"invitee_user_id": playerID,
+			"message":         "Join us!",
+		})
+		require.Equal(t, http.StatusCreated, code)
+		require.NoError(t, json.Unmarshal(env.Data, &invitation))
+		assert.Equal(t, models.InvitationStatusPending, invitation.Status)
+		assert.Equal(t, playerID, invitation.InviteeUserID)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/invitations/me?type=received", playerToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var received []handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &received))
+		require.Len(t, received, 1)
+		assert.Equal(t, invitation.ID, received[0].ID)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", captainToken, map[string]string{
+			"status": string(models.InvitationStatusYes),
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", playerToken, map[string]string{
+			"status": string(models.InvitationStatusPending),
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		var details []validator.FieldError
+		require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+		require.Len(t, details, 1)
+		assert.Equal(t, "invitation_response", details[0].Rule)
+		assert.Equal(t, []string{string(models.InvitationStatusYes), string(models.InvitationStatusNo), string(models.InvitationStatusMaybe)}, details[0].Allowed)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", playerToken, map[string]string{
+			"status": string(models.InvitationStatusYes),
+		})
+		require.Equal(t, http.StatusOK, code)
+		var responded handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &responded))
+		assert.Equal(t, models.InvitationStatusYes, responded.Status)
+		assert.NotNil(t, responded.RespondedAt)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		assert.Len(t, players, 2)
+	})
+
+	t.Run("update player status", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+captainID, playerToken, map[string]string{
+			"status": string(models.TTRPlayerStatusMaybe),
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+playerID, captainToken, map[string]string{
+			"status": string(models.TTRPlayerStatusMaybe),
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		for _, p := range players {
+			if p.UserID == playerID {
+				assert.Equal(t, models.TTRPlayerStatusMaybe, p.Status)
+			}
+		}
+	})
+
+	t.Run("co-captain management", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/co-captains", playerToken, map[string]string{
+			"user_id": playerID,
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/co-captains", captainToken, map[string]string{
+			"user_id": playerID,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/co-captains", captainToken, map[string]string{
+			"user_id": playerID,
+		})
+		assert.Equal(t, http.StatusOK, code)
+		var membership handler.MembershipResponse
+		require.NoError(t, json.Unmarshal(env.Data, &membership))
+		assert.True(t, membership.AlreadyDone)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, playerToken, map[string]interface{}{
+			"notes": "Updated by co-captain",
+		})
+		require.Equal(t, http.StatusOK, code)
+		var updated handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &updated))
+		require.NotNil(t, updated.Notes)
+		assert.Equal(t, "Updated by co-captain", *updated.Notes)
+		require.Len(t, updated.CoCaptains, 1)
+		assert.Equal(t, playerID, updated.CoCaptains[0].UserID)
+		assert.Equal(t, models.CoCaptainPermissions, updated.CoCaptains[0].Permissions)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+playerID, playerToken, map[string]interface{}{
+			"permissions": []string{models.CoCaptainPermissionInvite},
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+playerID, captainToken, map[string]interface{}{
+			"permissions": []string{"delete"},
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_CO_CAPTAIN_PERMISSION", env.Error.Code)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+captainID, captainToken, map[string]interface{}{
+			"permissions": []string{models.CoCaptainPermissionInvite},
+		})
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "CO_CAPTAIN_NOT_FOUND", env.Error.Code)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+playerID, captainToken, map[string]interface{}{
+			"permissions": []string{models.CoCaptainPermissionInvite},
+		})
+		require.Equal(t, http.StatusOK, code)
+		var coCaptain handler.TTRCoCaptainResponse
+		require.NoError(t, json.Unmarshal(env.Data, &coCaptain))
+		assert.Equal(t, []string{models.CoCaptainPermissionInvite}, coCaptain.Permissions)
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, playerToken, map[string]interface{}{
+			"notes": "Needs edit_details",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_MANAGER", env.Error.Code)
+
+		code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+playerID, playerToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID+"/co-captains/"+playerID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, playerToken, map[string]interface{}{
+			"notes": "Should not apply",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		code, env := doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID, playerToken, nil)
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "NOT_TTR_CAPTAIN", env.Error.Code)
+
+		code, env = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.True(t, env.Success)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "TTR_NOT_FOUND", env.Error.Code)
+	})
+}
+
+func TestTTRAPI_CoCaptainLeaves(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	coCaptainToken, coCaptainID := registerTestUser(t, api, "cocaptain@example.com", "CoCaptain")
+	_, guestID := registerTestUser(t, api, "guest@example.com", "Guest")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", coCaptainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/co-captains", captainToken, map[string]string{
+		"user_id": coCaptainID,
+	})
+	require.Equal(t, http.StatusOK, code)
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitations", coCaptainToken, map[string]string{
+		"ttr_id":          ttr.ID,
+		"invitee_user_id": guestID,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/leave", coCaptainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("co-captain can no longer manage the TTR", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, coCaptainToken, map[string]interface{}{
+			"notes": "Should not apply",
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var current handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &current))
+		assert.Empty(t, current.CoCaptains)
+		require.Len(t, current.Players, 1)
+		assert.Equal(t, captainID, current.Players[0].UserID)
+	})
+
+	t.Run("pending invitations are reassigned to the captain", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/invitations/"+invitation.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var reassigned handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &reassigned))
+		assert.Equal(t, models.InvitationStatusPending, reassigned.Status)
+		assert.Equal(t, captainID, reassigned.InviterUserID)
+
+		code, _ = doJSON(t, api, "DELETE", "/api/v1/invitations/"+invitation.ID, captainToken, nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestTTRAPI_CourseConditions(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	teeDate := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+	create := func(body map[string]interface{}) (int, handler.TTRResponse) {
+		t.Helper()
+		body["tee_date"] = teeDate
+		body["tee_time"] = "08:30"
+		body["max_players"] = 4
+		body["force"] = true
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, body)
+		var ttr handler.TTRResponse
+		if code == http.StatusCreated {
+			require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		}
+		return code, ttr
+	}
+	search := func(query string) []string {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs?"+query, captainToken, nil)
+		require.Equal(t, http.StatusOK, code, query)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		ids := make([]string, 0, len(ttrs))
+		for _, ttr := range ttrs {
+			ids = append(ids, ttr.ID)
+		}
+		return ids
+	}
+
+	code, twilight := create(map[string]interface{}{
+		"course_name":     "Pebble Beach",
+		"walking_allowed": false,
+		"carts_available": true,
+		"holes":           9,
+		"dress_code":      "Collared shirts",
+		"green_fee_cents": 4500,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	require.NotNil(t, twilight.WalkingAllowed)
+	assert.False(t, *twilight.WalkingAllowed)
+	require.NotNil(t, twilight.CartsAvailable)
+	assert.True(t, *twilight.CartsAvailable)
+	require.NotNil(t, twilight.Holes)
+	assert.Equal(t, 9, *twilight.Holes)
+	require.NotNil(t, twilight.DressCode)
+	assert.Equal(t, "Collared shirts", *twilight.DressCode)
+	require.NotNil(t, twilight.GreenFeeCents)
+	assert.Equal(t, 4500, *twilight.GreenFeeCents)
+
+	code, championship := create(map[string]interface{}{"course_name": "Spyglass Hill", "holes": 18, "green_fee_cents": 19500})
+	require.Equal(t, http.StatusCreated, code)
+	code, plain := create(map[string]interface{}{"course_name": "Links Course"})
+	require.Equal(t, http.StatusCreated, code)
+	assert.Nil(t, plain.Holes, "conditions are optional")
+	assert.Nil(t, plain.WalkingAllowed)
+
+	assert.Equal(t, []string{twilight.ID}, search("holes=9"))
+	assert.Equal(t, []string{championship.ID}, search("holes=18&max_green_fee=19500"))
+	assert.Equal(t, []string{twilight.ID}, search("max_green_fee=10000"), "TTRs without a fee are left out")
+
+	code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+twilight.ID, captainToken, map[string]interface{}{
+		"walking_allowed": true,
+		"green_fee_cents": 0,
+	})
+	require.Equal(t, http.StatusOK, code)
+	var updated handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &updated))
+	require.NotNil(t, updated.WalkingAllowed)
+	assert.True(t, *updated.WalkingAllowed)
+	require.NotNil(t, updated.GreenFeeCents)
+	assert.Equal(t, 0, *updated.GreenFeeCents)
+	require.NotNil(t, updated.Holes, "conditions left out of an update are kept")
+	assert.Equal(t, 9, *updated.Holes)
+	assert.Equal(t, "Collared shirts", *updated.DressCode)
+
+	code, _ = create(map[string]interface{}{"course_name": "Pebble Beach", "holes": 12})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, _ = create(map[string]interface{}{"course_name": "Pebble Beach", "green_fee_cents": -100})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+twilight.ID, captainToken, map[string]interface{}{"holes": 27})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	for _, query := range []string{"holes=12", "holes=nine", "max_green_fee=-1", "max_green_fee=cheap"} {
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs?"+query, captainToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+		code, _ = doJSON(t, api, "GET", "/api/v2/ttrs?"+query, captainToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+}
+
+func TestTTRAPI_IdempotentMembership(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 2,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	membership := func(method string, path string, token string, body interface{}) handler.MembershipResponse {
+		t.Helper()
+		code, env := doJSON(t, api, method, "/api/v1/ttrs/"+ttr.ID+path, token, body)
+		require.Equal(t, http.StatusOK, code, "%s %s", method, path)
+		var resp handler.MembershipResponse
+		require.NoError(t, json.Unmarshal(env.Data, &resp))
+		return resp
+	}
+	roster := func() handler.TTRResponse {
+		t.Helper()
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var current handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &current))
+		return current
+	}
+
+	assert.False(t, membership("POST", "/join", playerToken, nil).AlreadyDone)
+	assert.True(t, membership("POST", "/join", playerToken, nil).AlreadyDone,
+		"a second join succeeds, even though the TTR is now full")
+	assert.Len(t, roster().Players, 2)
+
+	coCaptain := map[string]string{"user_id": playerID}
+	assert.False(t, membership("POST", "/co-captains", captainToken, coCaptain).AlreadyDone)
+	assert.True(t, membership("POST", "/co-captains", captainToken, coCaptain).AlreadyDone)
+	assert.Len(t, roster().CoCaptains, 1)
+	assert.False(t, membership("DELETE", "/co-captains/"+playerID, captainToken, nil).AlreadyDone)
+	assert.True(t, membership("DELETE", "/co-captains/"+playerID, captainToken, nil).AlreadyDone)
+	assert.Empty(t, roster().CoCaptains)
+
+	assert.False(t, membership("POST", "/leave", playerToken, nil).AlreadyDone)
+	assert.True(t, membership("POST", "/leave", playerToken, nil).AlreadyDone)
+	assert.Len(t, roster().Players, 1)
+
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/leave", captainToken, nil)
+	assert.Equal(t, http.StatusBadRequest, code, "the captain still can't leave")
+	assert.Equal(t, "CAPTAIN_CANNOT_LEAVE", env.Error.Code)
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, map[string]interface{}{"status": "CANCELLED"})
+	require.Equal(t, http.StatusOK, code)
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "TTR_CLOSED", env.Error.Code, "joining a cancelled TTR is still an error")
+}
+
+func TestTTRAPI_DeleteCancelsPendingInvitations(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+		"ttr_id":          ttr.ID,
+		"invitee_user_id": inviteeID,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	var stored models.TTR
+	require.NoError(t, db.Unscoped().First(&stored, "id = ?", ttr.ID).Error)
+	assert.Equal(t, models.TTRStatusCancelled, stored.Status)
+	assert.True(t, stored.DeletedAt.Valid)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/invitations/"+invitation.ID, inviteeToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var cancelled handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &cancelled))
+	assert.Equal(t, models.InvitationStatusCanceled, cancelled.Status)
+	assert.Nil(t, cancelled.TTR, "the deleted TTR is not preloaded")
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitation.ID+"/respond", inviteeToken, map[string]string{
+		"status": string(models.InvitationStatusYes),
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestTTRAPI_ReadAccess(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	outsiderToken, _ := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+		"notes":       "Gate code 1234",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+		"ttr_id":          ttr.ID,
+		"invitee_user_id": inviteeID,
+		"message":         "Private note",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var invitation handler.InvitationResponse
+	require.NoError(t, json.Unmarshal(env.Data, &invitation))
+
+	t.Run("participant sees the full TTR", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var full handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &full))
+		require.NotNil(t, full.Notes)
+		assert.Len(t, full.Players, 1)
+	})
+
+	t.Run("pending invitee sees the full TTR and the invitation", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, inviteeToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var full handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &full))
+		assert.Len(t, full.Players, 1)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/invitations/"+invitation.ID, inviteeToken, nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("outsider sees the public view of an open TTR", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, outsiderToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(env.Data, &fields))
+		assert.Equal(t, "Pebble Beach", fields["course_name"])
+		assert.Equal(t, float64(3), fields["open_slots"])
+		assert.NotContains(t, fields, "players")
+		assert.NotContains(t, fields, "notes")
+		assert.NotContains(t, fields, "captain_user")
+	})
+
+	t.Run("outsider cannot read the invitation", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/invitations/"+invitation.ID, outsiderToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "INVITATION_NOT_FOUND", env.Error.Code)
+	})
+
+	t.Run("outsider cannot see a TTR that is not open", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, map[string]interface{}{
+			"status": models.TTRStatusConfirmed,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, outsiderToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+
+		code, _ = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, inviteeToken, nil)
+		assert.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("outsider cannot read the roster of a TTR they cannot see", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", outsiderToken, nil)
+		assert.Equal(t, http.StatusNotFound, code)
+		assert.Equal(t, "TTR_NOT_FOUND", env.Error.Code)
+		assert.Nil(t, env.Data)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", inviteeToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		assert.Len(t, players, 1)
+	})
+}
+
+func TestTTRAPI_DeletedUserOnRoster(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	require.NoError(t, db.Delete(&models.User{}, "id = ?", playerID).Error)
+
+	t.Run("roster renders the deleted player without PII", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		var roster struct {
+			Players []struct {
+				UserID string                 `json:"user_id"`
+				User   map[string]interface{} `json:"user"`
+			} `json:"players"`
+		}
+		require.NoError(t, json.Unmarshal(env.Data, &roster))
+		require.Len(t, roster.Players, 2, "the deleted player still holds a slot")
+
+		for _, p := range roster.Players {
+			require.NotNil(t, p.User)
+			if p.UserID != playerID {
+				continue
+			}
+			assert.Equal(t, "Deleted user", p.User["first_name"])
+			assert.Equal(t, true, p.User["deleted"])
+			assert.NotContains(t, p.User, "email")
+			assert.NotContains(t, p.User, "phone")
+		}
+	})
+
+	t.Run("deleted player still counts towards capacity", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		assert.Len(t, players, 2)
+	})
+
+	t.Run("cannot invite a deleted user", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+			"ttr_id":          ttr.ID,
+			"invitee_user_id": playerID,
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "cannot invite a deleted user", env.Error.Message)
+	})
+}
+
+func TestTTRAPI_PlayerNotesAndPairings(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	_, outsiderID := registerTestUser(t, api, "outsider@example.com", "Outsider")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 8,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("notes and group via player update", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+playerID, captainToken, map[string]interface{}{
+			"notes":        "needs rental clubs",
+			"group_number": 2,
+		})
+		require.Equal(t, http.StatusOK, code)
+
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs/"+ttr.ID+"/players", captainToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		for _, p := range players {
+			if p.UserID != playerID {
+				continue
+			}
+			require.NotNil(t, p.Notes)
+			assert.Equal(t, "needs rental clubs", *p.Notes)
+			assert.Equal(t, 2, p.GroupNumber)
+			assert.Equal(t, models.TTRPlayerStatusConfirmed, p.Status, "status is left unchanged")
+		}
+	})
+
+	t.Run("set pairings", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/pairings", captainToken, map[string]interface{}{
+			"pairings": map[string]int{captainID: 1, playerID: 1},
+		})
+		require.Equal(t, http.StatusOK, code)
+		var players []handler.TTRPlayerResponse
+		require.NoError(t, json.Unmarshal(env.Data, &players))
+		require.Len(t, players, 2)
+		for _, p := range players {
+			assert.Equal(t, 1, p.GroupNumber)
+		}
+	})
+
+	t.Run("rejects users off the roster", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/pairings", captainToken, map[string]interface{}{
+			"pairings": map[string]int{captainID: 1, outsiderID: 1},
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "pairings can only include players on the roster", env.Error.Message)
+	})
+
+	t.Run("only managers can set pairings", func(t *testing.T) {
+		code, _ := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/pairings", playerToken, map[string]interface{}{
+			"pairings": map[string]int{playerID: 2},
+		})
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+}
+
+func TestTTRAPI_PlayerStatuses(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, playerID := registerTestUser(t, api, "player@example.com", "Player")
+	latecomerToken, _ := registerTestUser(t, api, "latecomer@example.com", "Latecomer")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 2,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	setStatus := func(status models.TTRPlayerStatus) (int, apiEnvelope) {
+		t.Helper()
+		return doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID+"/players/"+playerID, captainToken, map[string]interface{}{"status": status})
+	}
+
+	// Waitlisting the player frees their slot for someone else.
+	code, _ = setStatus(models.TTRPlayerStatusWaitlisted)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", latecomerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	code, env = setStatus(models.TTRPlayerStatusConfirmed)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "TTR_FULL", env.Error.Code, "a waitlisted player needs a free slot")
+
+	code, env = setStatus(models.TTRPlayerStatusNoShow)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "INVALID_STATUS_CHANGE", env.Error.Code, "only confirmed players can be no-shows")
+}
+
+func TestTTRAPI_RSVPDeadline(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	inviteeToken, inviteeID := registerTestUser(t, api, "invitee@example.com", "Invitee")
+	_, lateID := registerTestUser(t, api, "late@example.com", "Late")
+
+	teeDate := time.Now().AddDate(0, 0, 7)
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name":   "Pebble Beach",
+		"tee_date":      teeDate.Format("2006-01-02"),
+		"tee_time":      "08:30",
+		"max_players":   4,
+		"visibility":    "PUBLIC",
+		"rsvp_deadline": teeDate.AddDate(0, 0, 1).Format(time.RFC3339),
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "rsvp_deadline must be before the tee time", env.Error.Message)
+
+	deadline := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name":   "Pebble Beach",
+		"tee_date":      teeDate.Format("2006-01-02"),
+		"tee_time":      "08:30",
+		"max_players":   4,
+		"visibility":    "PUBLIC",
+		"rsvp_deadline": deadline.Format(time.RFC3339),
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	require.NotNil(t, ttr.RSVPDeadline)
+	assert.Equal(t, deadline.Format(time.RFC3339), *ttr.RSVPDeadline)
+
+	var invitations []handler.InvitationResponse
+	for _, id := range []string{inviteeID, lateID} {
+		code, env = doJSON(t, api, "POST", "/api/v1/invitations", captainToken, map[string]string{
+			"ttr_id":          ttr.ID,
+			"invitee_user_id": id,
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var invitation handler.InvitationResponse
+		require.NoError(t, json.Unmarshal(env.Data, &invitation))
+		invitations = append(invitations, invitation)
+	}
+
+	require.NoError(t, db.Model(&models.TTR{}).Where("id = ?", ttr.ID).
+		Update("rsvp_deadline", time.Now().Add(-time.Minute)).Error)
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/invitations/"+invitations[0].ID+"/respond", inviteeToken, map[string]string{
+		"status": string(models.InvitationStatusYes),
+	})
+	require.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "RSVP deadline has passed", env.Error.Message)
+
+	logger, _ := zap.NewDevelopment()
+	ttrService := service.NewTTRService(
+		repository.NewTTRRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewInvitationRepository(db),
+		repository.NewTransactor(db),
+		service.NewAuthorizer(repository.NewTTRRepository(db), repository.NewOrganizationRepository(db), repository.NewInvitationRepository(db), repository.NewUserRepository(db)),
+		service.NewNotificationService(repository.NewNotificationRepository(db), nil, nil, 0, logger),
+		nil,
+		nil,
+		nil,
+		nil,
+		7*24*time.Hour,
+		0,
+		logger,
+	)
+	require.NoError(t, ttrService.ProcessRSVPDeadlines(context.Background()))
+
+	notificationRepo := repository.NewNotificationRepository(db)
+	for _, invitation := range invitations {
+		var stored models.Invitation
+		require.NoError(t, db.First(&stored, "id = ?", invitation.ID).Error)
+		assert.Equal(t, models.InvitationStatusExpired, stored.Status)
+
+		notifications, err := notificationRepo.FindByTarget(context.Background(), "invitation", stored.ID)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.True(t, notifications[0].IsRead, "the expired invitation's notification is resolved")
+	}
+
+	var stored models.TTR
+	require.NoError(t, db.First(&stored, "id = ?", ttr.ID).Error)
+	assert.NotNil(t, stored.RSVPClosedAt)
+}
+
+func TestTTRAPI_NonexistentTTR(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	token, userID := registerTestUser(t, api, "captain@example.com", "Captain")
+	missing := "/api/v1/ttrs/" + uuid.New().String()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"get", "GET", missing, nil},
+		{"update", "PUT", missing, map[string]interface{}{"course_name": "Augusta"}},
+		{"delete", "DELETE", missing, nil},
+		{"add co-captain", "POST", missing + "/co-captains", map[string]string{"user_id": userID}},
+		{"remove co-captain", "DELETE", missing + "/co-captains/" + userID, nil},
+		{"join", "POST", missing + "/join", nil},
+		{"leave", "POST", missing + "/leave", nil},
+		{"get players", "GET", missing + "/players", nil},
+		{"update player", "PUT", missing + "/players/" + userID, map[string]string{"status": string(models.TTRPlayerStatusMaybe)}},
+		{"set pairings", "PUT", missing + "/pairings", map[string]interface{}{"pairings": map[string]int{userID: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, env := doJSON(t, api, tt.method, tt.path, token, tt.body)
+			assert.Equal(t, http.StatusNotFound, code)
+			require.NotNil(t, env.Error)
+			assert.Equal(t, "TTR_NOT_FOUND", env.Error.Code)
+		})
+	}
+}
+
+func TestTTRAPI_Visibility(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, captainID := registerTestUser(t, api, "captain@example.com", "Captain")
+	friendToken, friendID := registerTestUser(t, api, "friend@example.com", "Friend")
+	strangerToken, _ := registerTestUser(t, api, "stranger@example.com", "Stranger")
+
+	befriend := func(token, otherID, want string) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/users/me/friends/"+otherID, token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(env.Data, &body))
+		assert.Equal(t, want, body["status"])
+	}
+	befriend(captainToken, friendID, "PENDING")
+	code, env := doJSON(t, api, "GET", "/api/v1/users/me/friends/requests", friendToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(env.Data), captainID)
+	befriend(friendToken, captainID, "ACCEPTED")
+	code, env = doJSON(t, api, "GET", "/api/v1/users/me/friends", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(env.Data), friendID)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/users/me/friends/"+captainID, captainToken, nil)
+	assert.Equal(t, http.StatusBadRequest, code, "nobody befriends themselves")
+
+	create := func(visibility string) handler.TTRResponse {
+		body := map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    "08:30",
+			"max_players": 4,
+			"force":       true,
+		}
+		if visibility != "" {
+			body["visibility"] = visibility
+		}
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, body)
+		require.Equal(t, http.StatusCreated, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+		return ttr
+	}
+	private := create("")
+	assert.Equal(t, models.TTRVisibilityPrivate, private.Visibility, "TTRs are private by default")
+	friends := create(models.TTRVisibilityFriends)
+	public := create(models.TTRVisibilityPublic)
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 4,
+		"visibility":  "SECRET",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	search := func(token string) []string {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		ids := make([]string, 0, len(ttrs))
+		for _, ttr := range ttrs {
+			ids = append(ids, ttr.ID)
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []string{private.ID, friends.ID, public.ID}, search(captainToken))
+	assert.ElementsMatch(t, []string{friends.ID, public.ID}, search(friendToken))
+	assert.ElementsMatch(t, []string{public.ID}, search(strangerToken))
+
+	for _, tc := range []struct {
+		token string
+		ttrID string
+		want  int
+	}{
+		{strangerToken, private.ID, http.StatusNotFound},
+		{strangerToken, friends.ID, http.StatusNotFound},
+		{friendToken, private.ID, http.StatusNotFound},
+		{friendToken, friends.ID, http.StatusOK},
+		{strangerToken, public.ID, http.StatusOK},
+	} {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+tc.ttrID+"/join", tc.token, nil)
+		assert.Equal(t, tc.want, code)
+	}
+
+	// Joining made the stranger a participant, so they keep seeing the TTR
+	// after the captain hides it.
+	code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+public.ID, captainToken, map[string]string{"visibility": models.TTRVisibilityPrivate})
+	require.Equal(t, http.StatusOK, code)
+	var updated handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &updated))
+	assert.Equal(t, models.TTRVisibilityPrivate, updated.Visibility)
+	assert.ElementsMatch(t, []string{public.ID}, search(strangerToken))
+	assert.ElementsMatch(t, []string{friends.ID}, search(friendToken))
+
+	// Ending the friendship doesn't take the friend off the roster.
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/users/me/friends/"+friendID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/users/me/friends/"+friendID, captainToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.ElementsMatch(t, []string{friends.ID}, search(friendToken))
+}
+
+func TestTTRAPI_DeleteAndRestore(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	playerToken, _ := registerTestUser(t, api, "player@example.com", "Player")
+
+	ttrID := createTestTTR(t, api, captainToken)
+	code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID, captainToken, map[string]string{"status": string(models.TTRStatusConfirmed)})
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	searchIDs := func(token string) []string {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs", token, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		ids := make([]string, 0, len(ttrs))
+		for _, ttr := range ttrs {
+			ids = append(ids, ttr.ID)
+		}
+		return ids
+	}
+	assert.NotContains(t, searchIDs(playerToken), ttrID)
+
+	code, env := doJSON(t, api, "GET", "/api/v1/ttrs/trash", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var trash []handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &trash))
+	require.Len(t, trash, 1)
+	assert.Equal(t, ttrID, trash[0].ID)
+	assert.NotNil(t, trash[0].DeletedAt)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/trash", playerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, "[]", string(env.Data), "the trash only lists the caller's own TTRs")
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/restore", playerToken, nil)
+	assert.Equal(t, http.StatusNotFound, code, "only the captain can restore")
+
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/restore", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var restored handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &restored))
+	assert.Equal(t, models.TTRStatusConfirmed, restored.Status, "the status from before the delete comes back")
+	assert.Nil(t, restored.DeletedAt)
+	require.Len(t, restored.Players, 2, "the captain and the player")
+
+	assert.Contains(t, searchIDs(playerToken), ttrID)
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/restore", captainToken, nil)
+	assert.Equal(t, http.StatusNotFound, code, "a live TTR can't be restored")
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttrID, captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, db.Unscoped().Model(&models.TTR{}).Where("id = ?", ttrID).
+		Update("deleted_at", time.Now().AddDate(0, 0, -8)).Error)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/ttrs/trash", captainToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, "[]", string(env.Data), "TTRs past the restore window drop out of the trash")
+
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/restore", captainToken, nil)
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "TTR can no longer be restored", env.Error.Message)
+}
+
+func TestTTRAPI_DuplicateDetection(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+
+	newTTR := func(teeTime string, force bool) map[string]interface{} {
+		return map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    teeTime,
+			"max_players": 4,
+			"force":       force,
+		}
+	}
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, newTTR("08:30", false))
+	require.Equal(t, http.StatusCreated, code)
+	var existing handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &existing))
+
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, newTTR("09:00", false))
+	require.Equal(t, http.StatusConflict, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "DUPLICATE_TTR", env.Error.Code)
+	assert.JSONEq(t, `{"existing_ttr_id":"`+existing.ID+`"}`, string(env.Error.Details))
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, newTTR("09:01", false))
+	assert.Equal(t, http.StatusCreated, code, "tee times more than 30 minutes apart are different rounds")
+
+	code, _ = doJSON(t, api, "POST", "/api/v1/ttrs", otherToken, newTTR("08:30", false))
+	assert.Equal(t, http.StatusCreated, code, "other captains aren't affected")
+
+	code, env = doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, newTTR("08:30", true))
+	require.Equal(t, http.StatusCreated, code)
+	var forced handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &forced))
+	assert.NotEqual(t, existing.ID, forced.ID)
+}
+
+func TestTTRAPI_EditLock(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+
+	teeAt := time.Now().UTC().Add(time.Hour).Truncate(time.Minute)
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    teeAt.Format("2006-01-02"),
+		"tee_time":    teeAt.Format("15:04"),
+		"max_players": 4,
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+	moved := map[string]interface{}{"tee_time": teeAt.Add(time.Hour).Format("15:04")}
+	code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, moved)
+	require.Equal(t, http.StatusConflict, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "TTR_LOCKED", env.Error.Code)
+	assert.JSONEq(t, `{"locked_at":"`+teeAt.Add(-2*time.Hour).Format(time.RFC3339)+`"}`, string(env.Error.Details))
+
+	code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, map[string]interface{}{"notes": "Carts only"})
+	assert.Equal(t, http.StatusOK, code, "notes stay editable")
+
+	moved["override"] = true
+	code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttr.ID, captainToken, moved)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	assert.Equal(t, teeAt.Add(time.Hour).Format("15:04"), ttr.TeeTime)
+
+	code, env = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID, captainToken, nil)
+	require.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, "TTR_LOCKED", env.Error.Code)
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID+"?override=maybe", captainToken, nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/ttrs/"+ttr.ID+"?override=true", captainToken, nil)
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,112 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"gorm.io/gorm"
+)
+
+func getTestTTR(t *testing.T, h http.Handler, token, ttrID string) handler.TTRResponse {
+	t.Helper()
+
+	code, env := doJSON(t, h, "GET", "/api/v1/ttrs/"+ttrID, token, nil)
+	require.Equal(t, http.StatusOK, code)
+
+	var ttr handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &ttr))
+	return ttr
+}
+
+func countStatusNotifications(t *testing.T, db *gorm.DB, ttrID, title string) int64 {
+	t.Helper()
+
+	var count int64
+	require.NoError(t, db.Model(&models.Notification{}).
+		Where("type = ? AND target_id = ? AND title = ?", models.NotificationTypeTTRUpdate, ttrID, title).
+		Count(&count).Error)
+	return count
+}
+
+func TestTTRAPI_StatusFollowsRoster(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+	captainToken, _ := registerTestUser(t, api, "captain@example.com", "Captain")
+	firstToken, firstID := registerTestUser(t, api, "first@example.com", "First")
+	secondToken, _ := registerTestUser(t, api, "second@example.com", "Second")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/ttrs", captainToken, map[string]interface{}{
+		"course_name": "Pebble Beach",
+		"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+		"tee_time":    "08:30",
+		"max_players": 3,
+		"visibility":  "PUBLIC",
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created handler.TTRResponse
+	require.NoError(t, json.Unmarshal(env.Data, &created))
+	ttrID := created.ID
+	assert.Equal(t, models.DefaultMinPlayers, created.MinPlayers)
+
+	t.Run("filling up confirms", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", firstToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.TTRStatusOpen, getTestTTR(t, api, captainToken, ttrID).Status)
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/join", secondToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.TTRStatusConfirmed, getTestTTR(t, api, captainToken, ttrID).Status)
+		assert.Equal(t, int64(3), countStatusNotifications(t, db, ttrID, "Tee Time Confirmed"), "every player hears about it")
+	})
+
+	t.Run("dropping below min_players reopens", func(t *testing.T) {
+		code, _ := doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/leave", secondToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.TTRStatusConfirmed, getTestTTR(t, api, captainToken, ttrID).Status, "two confirmed players are enough")
+
+		code, _ = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID+"/players/"+firstID, captainToken, map[string]string{
+			"status": string(models.TTRPlayerStatusMaybe),
+		})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.TTRStatusOpen, getTestTTR(t, api, captainToken, ttrID).Status)
+		assert.Equal(t, int64(2), countStatusNotifications(t, db, ttrID, "Tee Time Reopened"))
+	})
+
+	t.Run("a status set by hand is locked", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID, captainToken, map[string]interface{}{
+			"status": models.TTRStatusConfirmed,
+		})
+		require.Equal(t, http.StatusOK, code)
+		var updated handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &updated))
+		assert.Equal(t, models.TTRStatusConfirmed, updated.Status)
+		assert.True(t, updated.StatusLocked)
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttrID+"/leave", firstToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.TTRStatusConfirmed, getTestTTR(t, api, captainToken, ttrID).Status, "the captain's status stands")
+
+		code, env = doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID, captainToken, map[string]interface{}{
+			"status_locked": false,
+		})
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(env.Data, &updated))
+		assert.False(t, updated.StatusLocked)
+		assert.Equal(t, models.TTRStatusOpen, updated.Status, "unlocking syncs with the roster right away")
+	})
+
+	t.Run("min_players can't exceed max_players", func(t *testing.T) {
+		code, env := doJSON(t, api, "PUT", "/api/v1/ttrs/"+ttrID, captainToken, map[string]interface{}{
+			"min_players": 4,
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+		require.NotNil(t, env.Error)
+		assert.Equal(t, "INVALID_MIN_PLAYERS", env.Error.Code)
+	})
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func searchUsers(t *testing.T, h http.Handler, token, params string) (int, []handler.UserResponse) {
+	t.Helper()
+
+	code, env := doJSON(t, h, "GET", "/api/v1/users?"+params, token, nil)
+	var users []handler.UserResponse
+	if code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(env.Data, &users))
+	}
+	return code, users
+}
+
+func userIDs(users []handler.UserResponse) []string {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func TestUserSearch(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	aliceToken, aliceID := registerTestUser(t, api, "alice.smith@gmail.com", "Alice")
+	bobToken, bobID := registerTestUser(t, api, "bob.smith@gmail.com", "Bob")
+	_, carolID := registerTestUser(t, api, "carol@example.com", "Carol")
+
+	t.Run("full email matches exactly", func(t *testing.T) {
+		code, users := searchUsers(t, api, aliceToken, "q=bob.smith@gmail.com")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{bobID}, userIDs(users))
+	})
+
+	t.Run("email fragments do not match", func(t *testing.T) {
+		code, users := searchUsers(t, api, aliceToken, "q=gmail.com")
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, users)
+	})
+
+	t.Run("names match case-insensitively and exclude self", func(t *testing.T) {
+		code, users := searchUsers(t, api, aliceToken, "q=TESTER")
+		require.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{bobID, carolID}, userIDs(users))
+
+		code, users = searchUsers(t, api, aliceToken, "q=tester&exclude_self=false")
+		require.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{aliceID, bobID, carolID}, userIDs(users))
+	})
+
+	t.Run("short queries are rejected", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/users?q=bo", aliceToken, nil)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "search query must be at least 3 characters", env.Error.Message)
+	})
+
+	t.Run("exclude_ttr_id leaves out the roster", func(t *testing.T) {
+		code, env := doJSON(t, api, "POST", "/api/v1/ttrs", aliceToken, map[string]interface{}{
+			"course_name": "Pebble Beach",
+			"tee_date":    time.Now().AddDate(0, 0, 7).Format("2006-01-02"),
+			"tee_time":    "08:30",
+			"max_players": 4,
+			"visibility":  "PUBLIC",
+		})
+		require.Equal(t, http.StatusCreated, code)
+		var ttr handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttr))
+
+		code, _ = doJSON(t, api, "POST", "/api/v1/ttrs/"+ttr.ID+"/join", bobToken, nil)
+		require.Equal(t, http.StatusOK, code)
+
+		code, users := searchUsers(t, api, aliceToken, "q=tester&exclude_self=false&exclude_ttr_id="+ttr.ID)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{carolID}, userIDs(users))
+
+		code, _ = searchUsers(t, api, aliceToken, "q=tester&exclude_ttr_id=not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/router"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+)
+
+func TestAPIVersions_SideBySide(t *testing.T) {
+	db := setupTTRTestDB(t)
+	sunset := time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)
+	api := newTestAPIWithStorage(t, db, storage.NewMemoryStorage(), config.MessagingConfig{}, router.WithV1Sunset(sunset))
+
+	aliceToken, _ := registerTestUser(t, api, "alice@example.com", "Alice")
+	bobToken, bobID := registerTestUser(t, api, "bob@example.com", "Bobby")
+	registerTestUser(t, api, "bobbie@example.com", "Bobbie")
+	code, _ := doJSON(t, api, "PUT", "/api/v1/users/me/privacy", bobToken, map[string]bool{"show_email": true})
+	require.Equal(t, http.StatusOK, code)
+	for i := 0; i < 3; i++ {
+		createTestTTR(t, api, aliceToken)
+	}
+
+	t.Run("v1 lists are plain arrays", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v1/ttrs?limit=2", aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var ttrs []handler.TTRResponse
+		require.NoError(t, json.Unmarshal(env.Data, &ttrs))
+		assert.Len(t, ttrs, 2)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/users?q=bob", aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var users []handler.UserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &users))
+		require.Len(t, users, 2)
+		assert.Empty(t, users[0].Email, "emails are hidden by default")
+		assert.Equal(t, "bob@example.com", users[1].Email)
+	})
+
+	t.Run("v2 lists are pages", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v2/ttrs?limit=2", aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		var page handler.PageResponse[handler.TTRResponse]
+		require.NoError(t, json.Unmarshal(env.Data, &page))
+		assert.Len(t, page.Items, 2)
+		assert.Equal(t, 2, page.Limit)
+		assert.Equal(t, 0, page.Offset)
+		require.NotNil(t, page.NextOffset)
+		assert.Equal(t, 2, *page.NextOffset)
+
+		code, env = doJSON(t, api, "GET", "/api/v2/ttrs?limit=2&offset=2", aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		page = handler.PageResponse[handler.TTRResponse]{}
+		require.NoError(t, json.Unmarshal(env.Data, &page))
+		assert.Len(t, page.Items, 1)
+		assert.Nil(t, page.NextOffset, "last page")
+	})
+
+	t.Run("v2 users are trimmed", func(t *testing.T) {
+		code, env := doJSON(t, api, "GET", "/api/v2/users?q=bob", aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.NotContains(t, string(env.Data), "email")
+		var page handler.PageResponse[handler.PublicUserResponse]
+		require.NoError(t, json.Unmarshal(env.Data, &page))
+		assert.Len(t, page.Items, 2)
+		assert.Nil(t, page.NextOffset)
+
+		code, env = doJSON(t, api, "GET", "/api/v2/users/"+bobID, aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.NotContains(t, string(env.Data), "email")
+		assert.NotContains(t, string(env.Data), "created_at")
+		var user handler.PublicUserResponse
+		require.NoError(t, json.Unmarshal(env.Data, &user))
+		assert.Equal(t, bobID, user.ID)
+		assert.Equal(t, "Bobby", user.FirstName)
+
+		code, env = doJSON(t, api, "GET", "/api/v1/users/"+bobID, aliceToken, nil)
+		require.Equal(t, http.StatusOK, code)
+		assert.Contains(t, string(env.Data), "bob@example.com")
+	})
+
+	t.Run("only v1 is deprecated", func(t *testing.T) {
+		for path, deprecated := range map[string]bool{"/api/v1/ttrs": true, "/api/v2/ttrs": false} {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+aliceToken)
+			w := httptest.NewRecorder()
+			api.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code, path)
+			if deprecated {
+				assert.Equal(t, "true", w.Header().Get("Deprecation"), path)
+				assert.Equal(t, "Wed, 31 Mar 2027 00:00:00 GMT", w.Header().Get("Sunset"), path)
+				assert.Equal(t, `</api/v2>; rel="successor-version"`, w.Header().Get("Link"), path)
+			} else {
+				assert.Empty(t, w.Header().Get("Deprecation"), path)
+				assert.Empty(t, w.Header().Get("Sunset"), path)
+			}
+		}
+	})
+
+	t.Run("unknown version lists supported versions", func(t *testing.T) {
+		for _, path := range []string{"/api/v3/ttrs", "/api/ttrs", "/api"} {
+			code, env := doJSON(t, api, "GET", path, aliceToken, nil)
+			require.Equal(t, http.StatusNotFound, code, path)
+			require.NotNil(t, env.Error, path)
+			assert.Equal(t, "Unsupported API version", env.Error.Message)
+			var details struct {
+				SupportedVersions []string `json:"supported_versions"`
+			}
+			require.NoError(t, json.Unmarshal(env.Error.Details, &details))
+			assert.Equal(t, []string{"v1", "v2"}, details.SupportedVersions)
+		}
+	})
+}
+
+func TestAPIVersions_V1NotDeprecatedWithoutSunset(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", nil)
+	w := httptest.NewRecorder()
+	api.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Deprecation"))
+	assert.Empty(t, w.Header().Get("Sunset"))
+}
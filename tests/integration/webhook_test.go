@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+)
+
+func TestWebhookAPI_CRUD(t *testing.T) {
+	db := setupTTRTestDB(t)
+	api := newTestAPI(t, db)
+
+	ownerToken, ownerID := registerTestUser(t, api, "owner@example.com", "Owner")
+	otherToken, _ := registerTestUser(t, api, "other@example.com", "Other")
+
+	code, env := doJSON(t, api, "POST", "/api/v1/webhooks", ownerToken, map[string]interface{}{
+		"url":    "https://teesheet.example.com/hooks",
+		"events": []string{"ttr.created", "invitation.responded"},
+	})
+	require.Equal(t, http.StatusCreated, code)
+	var created handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(env.Data, &created))
+	assert.Equal(t, ownerID, created.OwnerUserID)
+	assert.Equal(t, []string{"ttr.created", "invitation.responded"}, created.Events)
+	assert.Equal(t, "ACTIVE", created.Status)
+	assert.Len(t, created.Secret, 64, "the secret is shown on creation")
+
+	code, env = doJSON(t, api, "GET", "/api/v1/webhooks", ownerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var listed []handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(env.Data, &listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, created.ID, listed[0].ID)
+	assert.Empty(t, listed[0].Secret, "and never again")
+
+	code, env = doJSON(t, api, "GET", "/api/v1/webhooks/"+created.ID, otherToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "WEBHOOK_NOT_FOUND", env.Error.Code)
+
+	code, env = doJSON(t, api, "POST", "/api/v1/webhooks", ownerToken, map[string]interface{}{
+		"url":    "https://teesheet.example.com/hooks",
+		"events": []string{"ttr.deleted"},
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "INVALID_WEBHOOK_EVENT", env.Error.Code)
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/webhooks/"+created.ID, ownerToken, map[string]interface{}{
+		"url": "ftp://teesheet.example.com/hooks",
+	})
+	assert.Equal(t, http.StatusBadRequest, code)
+	require.NotNil(t, env.Error)
+	assert.Equal(t, "INVALID_WEBHOOK_URL", env.Error.Code)
+
+	code, env = doJSON(t, api, "PUT", "/api/v1/webhooks/"+created.ID, ownerToken, map[string]interface{}{
+		"events": []string{"ttr.cancelled"},
+	})
+	require.Equal(t, http.StatusOK, code)
+	var updated handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(env.Data, &updated))
+	assert.Equal(t, []string{"ttr.cancelled"}, updated.Events)
+	assert.Equal(t, created.URL, updated.URL)
+
+	code, env = doJSON(t, api, "GET", "/api/v1/webhooks/"+created.ID+"/deliveries", ownerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	var deliveries []handler.WebhookDeliveryResponse
+	require.NoError(t, json.Unmarshal(env.Data, &deliveries))
+	assert.Empty(t, deliveries)
+
+	code, _ = doJSON(t, api, "DELETE", "/api/v1/webhooks/"+created.ID, ownerToken, nil)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = doJSON(t, api, "GET", "/api/v1/webhooks/"+created.ID, ownerToken, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
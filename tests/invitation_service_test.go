@@ -1,27 +1,33 @@
 package tests
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
 	"github.com/yourusername/golf_messenger/internal/service"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type MockInvitationRepository struct {
 	mock.Mock
 }
 
-func (m *MockInvitationRepository) Create(invitation *models.Invitation) error {
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
 	args := m.Called(invitation)
 	return args.Error(0)
 }
 
-func (m *MockInvitationRepository) FindByID(id uuid.UUID) (*models.Invitation, error) {
+func (m *MockInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Invitation, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -29,7 +35,7 @@ func (m *MockInvitationRepository) FindByID(id uuid.UUID) (*models.Invitation, e
 	return args.Get(0).(*models.Invitation), args.Error(1)
 }
 
-func (m *MockInvitationRepository) FindReceivedByUserID(userID uuid.UUID) ([]*models.Invitation, error) {
+func (m *MockInvitationRepository) FindReceivedByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -37,7 +43,7 @@ func (m *MockInvitationRepository) FindReceivedByUserID(userID uuid.UUID) ([]*mo
 	return args.Get(0).([]*models.Invitation), args.Error(1)
 }
 
-func (m *MockInvitationRepository) FindSentByUserID(userID uuid.UUID) ([]*models.Invitation, error) {
+func (m *MockInvitationRepository) FindSentByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Invitation, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -45,17 +51,33 @@ func (m *MockInvitationRepository) FindSentByUserID(userID uuid.UUID) ([]*models
 	return args.Get(0).([]*models.Invitation), args.Error(1)
 }
 
-func (m *MockInvitationRepository) Update(invitation *models.Invitation) error {
+func (m *MockInvitationRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	args := m.Called(ttrID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) FindByTTRIDs(ctx context.Context, ttrIDs []uuid.UUID) ([]*models.Invitation, error) {
+	args := m.Called(ttrIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) Update(ctx context.Context, invitation *models.Invitation) error {
 	args := m.Called(invitation)
 	return args.Error(0)
 }
 
-func (m *MockInvitationRepository) Delete(id uuid.UUID) error {
+func (m *MockInvitationRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockInvitationRepository) FindByTTRAndInvitee(ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
+func (m *MockInvitationRepository) FindByTTRAndInvitee(ctx context.Context, ttrID uuid.UUID, inviteeUserID uuid.UUID) (*models.Invitation, error) {
 	args := m.Called(ttrID, inviteeUserID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -63,13 +85,53 @@ func (m *MockInvitationRepository) FindByTTRAndInvitee(ttrID uuid.UUID, inviteeU
 	return args.Get(0).(*models.Invitation), args.Error(1)
 }
 
+func (m *MockInvitationRepository) FindByTTRsAndInvitee(ctx context.Context, ttrIDs []uuid.UUID, inviteeUserID uuid.UUID) ([]*models.Invitation, error) {
+	args := m.Called(ttrIDs, inviteeUserID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) CancelPendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	args := m.Called(ttrID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) ExpirePendingByTTRID(ctx context.Context, ttrID uuid.UUID) ([]*models.Invitation, error) {
+	args := m.Called(ttrID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepository) CountPendingByCaptainID(ctx context.Context, captainID uuid.UUID, from time.Time) (map[uuid.UUID]int, error) {
+	args := m.Called(captainID, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
+// newTestInvitationService builds an InvitationService, and the TTRService it
+// syncs TTR statuses through, on the same mocks.
+func newTestInvitationService(invitationRepo *MockInvitationRepository, ttrRepo *MockTTRRepository, userRepo *MockUserRepository, notificationService *service.NotificationService, logger *zap.Logger) *service.InvitationService {
+	authorizer := service.NewAuthorizer(ttrRepo, new(MockOrganizationRepository), invitationRepo, new(MockUserRepository))
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, passthroughTransactor{}, authorizer, notificationService, nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+	return service.NewInvitationService(invitationRepo, ttrRepo, userRepo, memory.NewInvitationTemplateRepository(memory.NewStore()), ttrService, passthroughTransactor{}, authorizer, notificationService, nil, nil, logger)
+}
+
 func TestCreateInvitation_Authorization(t *testing.T) {
 	mockInvitationRepo := new(MockInvitationRepository)
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	notificationService := service.NewNotificationService(logger)
-	invitationService := service.NewInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
 
 	captainID := uuid.New()
 	inviterID := uuid.New()
@@ -83,9 +145,8 @@ func TestCreateInvitation_Authorization(t *testing.T) {
 	}
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
-	mockTTRRepo.On("IsCoCaptain", ttrID, inviterID).Return(false, nil)
 
-	_, err := invitationService.CreateInvitation(ttrID, inviterID, inviteeID, nil)
+	_, err := invitationService.CreateInvitation(context.Background(), ttrID, inviterID, inviteeID, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "unauthorized: only captain or co-captain can send invitations", err.Error())
@@ -97,8 +158,8 @@ func TestCreateInvitation_DuplicatePrevention(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	notificationService := service.NewNotificationService(logger)
-	invitationService := service.NewInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
 
 	captainID := uuid.New()
 	inviteeID := uuid.New()
@@ -108,6 +169,8 @@ func TestCreateInvitation_DuplicatePrevention(t *testing.T) {
 		ID:            ttrID,
 		CaptainUserID: captainID,
 		MaxPlayers:    4,
+		Status:        models.TTRStatusOpen,
+		TeeDate:       time.Now().AddDate(0, 0, 7),
 	}
 
 	invitee := &models.User{
@@ -126,13 +189,12 @@ func TestCreateInvitation_DuplicatePrevention(t *testing.T) {
 	}
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
-	mockTTRRepo.On("IsCoCaptain", ttrID, captainID).Return(false, nil)
-	mockUserRepo.On("FindByID", inviteeID).Return(invitee, nil)
+	mockUserRepo.On("FindByIDUnscoped", inviteeID).Return(invitee, nil)
 	mockTTRRepo.On("GetPlayers", ttrID).Return([]*models.TTRPlayer{}, nil)
 	mockTTRRepo.On("IsPlayer", ttrID, inviteeID).Return(false, nil)
 	mockInvitationRepo.On("FindByTTRAndInvitee", ttrID, inviteeID).Return(existingInvitation, nil)
 
-	_, err := invitationService.CreateInvitation(ttrID, captainID, inviteeID, nil)
+	_, err := invitationService.CreateInvitation(context.Background(), ttrID, captainID, inviteeID, nil, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "pending invitation already exists for this user", err.Error())
@@ -141,13 +203,142 @@ func TestCreateInvitation_DuplicatePrevention(t *testing.T) {
 	mockInvitationRepo.AssertExpectations(t)
 }
 
+func TestCreateInvitation_Rejections(t *testing.T) {
+	captainID := uuid.New()
+	coCaptainID := uuid.New()
+	inviteeID := uuid.New()
+
+	tests := []struct {
+		name      string
+		inviterID uuid.UUID
+		inviteeID uuid.UUID
+		status    models.TTRStatus
+		teeDate   time.Time
+		wantErr   string
+	}{
+		{
+			name:      "captain invites themselves",
+			inviterID: captainID,
+			inviteeID: captainID,
+			status:    models.TTRStatusOpen,
+			teeDate:   time.Now().AddDate(0, 0, 7),
+			wantErr:   "cannot invite yourself",
+		},
+		{
+			name:      "co-captain invites themselves",
+			inviterID: coCaptainID,
+			inviteeID: coCaptainID,
+			status:    models.TTRStatusOpen,
+			teeDate:   time.Now().AddDate(0, 0, 7),
+			wantErr:   "cannot invite yourself",
+		},
+		{
+			name:      "co-captain invites the captain",
+			inviterID: coCaptainID,
+			inviteeID: captainID,
+			status:    models.TTRStatusOpen,
+			teeDate:   time.Now().AddDate(0, 0, 7),
+			wantErr:   "cannot invite the TTR captain",
+		},
+		{
+			name:      "cancelled TTR",
+			inviterID: captainID,
+			inviteeID: inviteeID,
+			status:    models.TTRStatusCancelled,
+			teeDate:   time.Now().AddDate(0, 0, 7),
+			wantErr:   "cannot invite to a cancelled or completed TTR",
+		},
+		{
+			name:      "completed TTR",
+			inviterID: captainID,
+			inviteeID: inviteeID,
+			status:    models.TTRStatusCompleted,
+			teeDate:   time.Now().AddDate(0, 0, 7),
+			wantErr:   "cannot invite to a cancelled or completed TTR",
+		},
+		{
+			name:      "tee time has passed",
+			inviterID: captainID,
+			inviteeID: inviteeID,
+			status:    models.TTRStatusOpen,
+			teeDate:   time.Now().AddDate(0, 0, -1),
+			wantErr:   "cannot invite to a TTR whose tee time has passed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInvitationRepo := new(MockInvitationRepository)
+			mockTTRRepo := new(MockTTRRepository)
+			mockUserRepo := new(MockUserRepository)
+			logger := zap.NewNop()
+			notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+			invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+
+			ttrID := uuid.New()
+			ttr := &models.TTR{
+				ID:            ttrID,
+				CaptainUserID: captainID,
+				MaxPlayers:    4,
+				Status:        tt.status,
+				TeeDate:       tt.teeDate,
+				CoCaptains:    []models.TTRCoCaptain{{TTRID: ttrID, UserID: coCaptainID, Permissions: models.CoCaptainPermissionInvite}},
+			}
+
+			mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+
+			_, err := invitationService.CreateInvitation(context.Background(), ttrID, tt.inviterID, tt.inviteeID, nil, nil)
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockTTRRepo.AssertExpectations(t)
+			mockInvitationRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestCreateInvitation_DeletedInvitee(t *testing.T) {
+	mockInvitationRepo := new(MockInvitationRepository)
+	mockTTRRepo := new(MockTTRRepository)
+	mockUserRepo := new(MockUserRepository)
+	logger := zap.NewNop()
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+
+	captainID := uuid.New()
+	inviteeID := uuid.New()
+	ttrID := uuid.New()
+
+	ttr := &models.TTR{
+		ID:            ttrID,
+		CaptainUserID: captainID,
+		MaxPlayers:    4,
+		Status:        models.TTRStatusOpen,
+		TeeDate:       time.Now().AddDate(0, 0, 7),
+	}
+
+	invitee := &models.User{
+		ID:        inviteeID,
+		DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true},
+	}
+
+	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+	mockUserRepo.On("FindByIDUnscoped", inviteeID).Return(invitee, nil)
+
+	_, err := invitationService.CreateInvitation(context.Background(), ttrID, captainID, inviteeID, nil, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, "cannot invite a deleted user", err.Error())
+	mockInvitationRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
 func TestRespondToInvitation_AcceptJoinsTTR(t *testing.T) {
 	mockInvitationRepo := new(MockInvitationRepository)
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	notificationService := service.NewNotificationService(logger)
-	invitationService := service.NewInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
 
 	inviteeID := uuid.New()
 	ttrID := uuid.New()
@@ -170,7 +361,8 @@ func TestRespondToInvitation_AcceptJoinsTTR(t *testing.T) {
 	mockInvitationRepo.On("FindByID", invitationID).Return(invitation, nil)
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
 	mockTTRRepo.On("GetPlayers", ttrID).Return([]*models.TTRPlayer{{UserID: uuid.New()}}, nil)
-	mockTTRRepo.On("AddPlayer", ttrID, inviteeID, models.TTRPlayerStatusConfirmed).Return(nil)
+	mockTTRRepo.On("AddPlayerIfOpen", ttrID, inviteeID, models.TTRPlayerStatusConfirmed).Return(nil)
+	mockTTRRepo.On("CountPlayers", []uuid.UUID{ttrID}).Return(map[uuid.UUID]models.PlayerCounts{ttrID: {Total: 2, Confirmed: 2}}, nil)
 	mockInvitationRepo.On("Update", mock.AnythingOfType("*models.Invitation")).Return(nil)
 	mockInvitationRepo.On("FindByID", invitationID).Return(&models.Invitation{
 		ID:            invitationID,
@@ -182,13 +374,13 @@ func TestRespondToInvitation_AcceptJoinsTTR(t *testing.T) {
 		RespondedAt:   &time.Time{},
 	}, nil)
 
-	result, err := invitationService.RespondToInvitation(invitationID, inviteeID, models.InvitationStatusYes)
+	result, err := invitationService.RespondToInvitation(context.Background(), invitationID, inviteeID, models.InvitationStatusYes, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Equal(t, models.InvitationStatusYes, result.Status)
 	mockInvitationRepo.AssertCalled(t, "Update", mock.AnythingOfType("*models.Invitation"))
-	mockTTRRepo.AssertCalled(t, "AddPlayer", ttrID, inviteeID, models.TTRPlayerStatusConfirmed)
+	mockTTRRepo.AssertCalled(t, "AddPlayerIfOpen", ttrID, inviteeID, models.TTRPlayerStatusConfirmed)
 	mockInvitationRepo.AssertExpectations(t)
 	mockTTRRepo.AssertExpectations(t)
 }
@@ -198,8 +390,8 @@ func TestRespondToInvitation_WhenTTRFull(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	notificationService := service.NewNotificationService(logger)
-	invitationService := service.NewInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+	notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+	invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
 
 	inviteeID := uuid.New()
 	ttrID := uuid.New()
@@ -220,20 +412,528 @@ func TestRespondToInvitation_WhenTTRFull(t *testing.T) {
 	}
 
 	players := []*models.TTRPlayer{
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
+		{UserID: uuid.New(), Status: models.TTRPlayerStatusConfirmed},
+		{UserID: uuid.New(), Status: models.TTRPlayerStatusConfirmed},
+		{UserID: uuid.New(), Status: models.TTRPlayerStatusMaybe},
+		{UserID: uuid.New(), Status: models.TTRPlayerStatusDeclined},
 	}
 
 	mockInvitationRepo.On("FindByID", invitationID).Return(invitation, nil)
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
 	mockTTRRepo.On("GetPlayers", ttrID).Return(players, nil)
+	mockTTRRepo.On("AddPlayerIfOpen", ttrID, inviteeID, models.TTRPlayerStatusConfirmed).Return(repository.ErrTTRFull)
 
-	_, err := invitationService.RespondToInvitation(invitationID, inviteeID, models.InvitationStatusYes)
+	_, err := invitationService.RespondToInvitation(context.Background(), invitationID, inviteeID, models.InvitationStatusYes, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "TTR is full, cannot accept invitation", err.Error())
 	mockInvitationRepo.AssertExpectations(t)
 	mockTTRRepo.AssertExpectations(t)
 }
+
+func TestRespondToInvitation_RSVPDeadline(t *testing.T) {
+	tests := []struct {
+		name     string
+		deadline *time.Time
+		wantErr  string
+	}{
+		{name: "no deadline is not enforced", deadline: nil},
+		{name: "deadline one second away", deadline: timePtr(time.Now().Add(time.Second))},
+		{name: "deadline passed one second ago", deadline: timePtr(time.Now().Add(-time.Second)), wantErr: "RSVP deadline has passed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInvitationRepo := new(MockInvitationRepository)
+			mockTTRRepo := new(MockTTRRepository)
+			mockUserRepo := new(MockUserRepository)
+			logger, _ := zap.NewDevelopment()
+			notificationService := service.NewNotificationService(nil, nil, nil, 0, logger)
+			invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, mockUserRepo, notificationService, logger)
+
+			inviteeID := uuid.New()
+			ttrID := uuid.New()
+			invitationID := uuid.New()
+
+			invitation := &models.Invitation{
+				ID:            invitationID,
+				TTRID:         ttrID,
+				InviterUserID: uuid.New(),
+				InviteeUserID: inviteeID,
+				Status:        models.InvitationStatusPending,
+			}
+
+			ttr := &models.TTR{
+				ID:           ttrID,
+				MaxPlayers:   4,
+				TeeDate:      time.Now().AddDate(0, 0, 7),
+				RSVPDeadline: tt.deadline,
+			}
+
+			mockInvitationRepo.On("FindByID", invitationID).Return(invitation, nil)
+			mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+			mockInvitationRepo.On("Update", mock.AnythingOfType("*models.Invitation")).Return(nil)
+
+			_, err := invitationService.RespondToInvitation(context.Background(), invitationID, inviteeID, models.InvitationStatusNo, nil)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockInvitationRepo.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockInvitationRepo.AssertCalled(t, "Update", mock.AnythingOfType("*models.Invitation"))
+		})
+	}
+}
+
+func TestRespondToInvitation_Transitions(t *testing.T) {
+	answers := []models.InvitationStatus{models.InvitationStatusYes, models.InvitationStatusNo, models.InvitationStatusMaybe}
+	allowed := map[models.InvitationStatus][]models.InvitationStatus{
+		models.InvitationStatusPending:  answers,
+		models.InvitationStatusMaybe:    {models.InvitationStatusYes, models.InvitationStatusNo},
+		models.InvitationStatusYes:      nil,
+		models.InvitationStatusNo:       nil,
+		models.InvitationStatusCanceled: nil,
+		models.InvitationStatusExpired:  nil,
+	}
+
+	for from, to := range allowed {
+		for _, answer := range answers {
+			t.Run(string(from)+" to "+string(answer), func(t *testing.T) {
+				mockInvitationRepo := new(MockInvitationRepository)
+				mockTTRRepo := new(MockTTRRepository)
+				logger := zap.NewNop()
+				invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, new(MockUserRepository), service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+				inviteeID := uuid.New()
+				ttrID := uuid.New()
+				invitation := &models.Invitation{ID: uuid.New(), TTRID: ttrID, InviterUserID: uuid.New(), InviteeUserID: inviteeID, Status: from}
+
+				mockInvitationRepo.On("FindByID", invitation.ID).Return(invitation, nil)
+				mockTTRRepo.On("FindByID", ttrID).Return(&models.TTR{ID: ttrID, MaxPlayers: 4}, nil)
+				mockTTRRepo.On("GetPlayers", ttrID).Return([]*models.TTRPlayer{}, nil).Maybe()
+				mockTTRRepo.On("AddPlayerIfOpen", ttrID, inviteeID, models.TTRPlayerStatusConfirmed).Return(nil).Maybe()
+				mockTTRRepo.On("CountPlayers", []uuid.UUID{ttrID}).Return(map[uuid.UUID]models.PlayerCounts{}, nil).Maybe()
+				mockInvitationRepo.On("Update", mock.AnythingOfType("*models.Invitation")).Return(nil).Maybe()
+
+				_, err := invitationService.RespondToInvitation(context.Background(), invitation.ID, inviteeID, answer, nil)
+
+				for _, ok := range to {
+					if ok == answer {
+						assert.NoError(t, err)
+						return
+					}
+				}
+				assert.EqualError(t, err, "invitation has already been responded to")
+				mockInvitationRepo.AssertNotCalled(t, "Update", mock.Anything)
+			})
+		}
+	}
+}
+
+func TestRespondToInvitation_DeclineReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     models.InvitationStatus
+		reason     string
+		wantErr    string
+		wantReason *string
+	}{
+		{name: "with NO", status: models.InvitationStatusNo, reason: "Out of town", wantReason: stringPtr("Out of town")},
+		{name: "with MAYBE", status: models.InvitationStatusMaybe, reason: "  Depends on work  ", wantReason: stringPtr("Depends on work")},
+		{name: "blank is dropped", status: models.InvitationStatusNo, reason: "   "},
+		{name: "not with YES", status: models.InvitationStatusYes, reason: "Can't wait", wantErr: "decline reason is only allowed with NO or MAYBE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInvitationRepo := new(MockInvitationRepository)
+			mockTTRRepo := new(MockTTRRepository)
+			logger := zap.NewNop()
+			invitationService := newTestInvitationService(mockInvitationRepo, mockTTRRepo, new(MockUserRepository), service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+			inviteeID := uuid.New()
+			ttrID := uuid.New()
+			invitation := &models.Invitation{ID: uuid.New(), TTRID: ttrID, InviterUserID: uuid.New(), InviteeUserID: inviteeID, Status: models.InvitationStatusPending}
+
+			mockInvitationRepo.On("FindByID", invitation.ID).Return(invitation, nil)
+			mockTTRRepo.On("FindByID", ttrID).Return(&models.TTR{ID: ttrID, MaxPlayers: 4}, nil)
+			mockInvitationRepo.On("Update", mock.AnythingOfType("*models.Invitation")).Return(nil)
+
+			_, err := invitationService.RespondToInvitation(context.Background(), invitation.ID, inviteeID, tt.status, &tt.reason)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReason, invitation.DeclineReason)
+		})
+	}
+}
+
+// failingUpdateInvitationRepository fails every invitation update, as a
+// database going away between adding the player and answering would.
+type failingUpdateInvitationRepository struct {
+	repository.InvitationRepository
+}
+
+func (r failingUpdateInvitationRepository) Update(ctx context.Context, invitation *models.Invitation) error {
+	return errors.New("connection reset")
+}
+
+// invitationFixture runs the services on the in-memory repositories, so a
+// failed response rolls back like it would on the database.
+type invitationFixture struct {
+	service          *service.InvitationService
+	ttrService       *service.TTRService
+	ttrRepo          repository.TTRRepository
+	invitationRepo   repository.InvitationRepository
+	notificationRepo repository.NotificationRepository
+	store            *memory.Store
+	ttr              *service.TTRDetail
+	captainID        uuid.UUID
+	inviteeID        uuid.UUID
+	invitation       *service.InvitationDetail
+}
+
+func newInvitationFixture(t *testing.T, wrap func(repository.InvitationRepository) repository.InvitationRepository) *invitationFixture {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	store := memory.NewStore()
+	f := &invitationFixture{
+		ttrRepo:          memory.NewTTRRepository(store),
+		invitationRepo:   memory.NewInvitationRepository(store),
+		notificationRepo: memory.NewNotificationRepository(store),
+		store:            store,
+	}
+	userRepo := memory.NewUserRepository(store)
+	transactor := memory.NewTransactor(store)
+	notificationService := service.NewNotificationService(f.notificationRepo, userRepo, nil, 0, logger)
+	authorizer := service.NewAuthorizer(f.ttrRepo, memory.NewOrganizationRepository(store), f.invitationRepo, userRepo)
+	f.ttrService = service.NewTTRService(f.ttrRepo, userRepo, f.invitationRepo, transactor, authorizer, notificationService, nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+	f.service = service.NewInvitationService(wrap(f.invitationRepo), f.ttrRepo, userRepo, memory.NewInvitationTemplateRepository(store), f.ttrService, transactor, authorizer, notificationService, nil, nil, logger)
+
+	for _, id := range []*uuid.UUID{&f.captainID, &f.inviteeID} {
+		user := &models.User{Email: uuid.NewString() + "@example.com", FirstName: "Test", LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		*id = user.ID
+	}
+
+	var err error
+	f.ttr, err = f.ttrService.CreateTTR(ctx, f.captainID, "Pebble Beach", nil, time.Now().UTC().AddDate(0, 0, 7).Truncate(24*time.Hour), time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), 4, 2, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, false)
+	require.NoError(t, err)
+	f.invitation, err = f.service.CreateInvitation(ctx, f.ttr.ID, f.captainID, f.inviteeID, nil, nil)
+	require.NoError(t, err)
+	return f
+}
+
+func (f *invitationFixture) captainNotifications(t *testing.T) []*models.Notification {
+	notifications, err := f.notificationRepo.FindByUserID(context.Background(), f.captainID, 100, 0)
+	require.NoError(t, err)
+	return notifications
+}
+
+func TestCreateInvitation_MessageVariables(t *testing.T) {
+	ctx := context.Background()
+	f := newInvitationFixture(t, func(repo repository.InvitationRepository) repository.InvitationRepository { return repo })
+	invite := func(message string) (*service.InvitationDetail, error) {
+		invitee := &models.User{Email: uuid.NewString() + "@example.com", FirstName: "Guest", LastName: "Golfer"}
+		require.NoError(t, memory.NewUserRepository(f.store).Create(ctx, invitee))
+		return f.service.CreateInvitation(ctx, f.ttr.ID, f.captainID, invitee.ID, &message, nil)
+	}
+
+	invitation, err := invite("{{captain}} booked {{course}} on {{date}} at {{time}}")
+	require.NoError(t, err)
+	require.NotNil(t, invitation.Message)
+	assert.Equal(t, "Test Golfer booked Pebble Beach on "+f.ttr.TeeDate.Format("2006-01-02")+" at 09:00", *invitation.Message)
+
+	for _, message := range []string{
+		"Bring {{handicap}}",
+		"{{.CourseName}}",
+		"{{course | printf \"%q\"}}",
+		"{{if course}}yes{{end}}",
+		"{{$x := course}}",
+		"Unclosed {{course",
+	} {
+		_, err := invite(message)
+		var malformed *service.MessageTemplateError
+		require.ErrorAs(t, err, &malformed, message)
+		assert.ErrorIs(t, err, service.ErrInvalidMessageTemplate)
+		assert.Equal(t, 1, malformed.Line, message)
+		assert.NotEmpty(t, malformed.Reason, message)
+	}
+}
+
+func TestRespondToInvitation_Atomic(t *testing.T) {
+	ctx := context.Background()
+	f := newInvitationFixture(t, func(repo repository.InvitationRepository) repository.InvitationRepository {
+		return failingUpdateInvitationRepository{repo}
+	})
+
+	_, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusYes, nil)
+	require.Error(t, err)
+
+	players, err := f.ttrRepo.GetPlayers(ctx, f.ttr.ID)
+	require.NoError(t, err)
+	require.Len(t, players, 1, "the invitee isn't left on the roster")
+	assert.Equal(t, f.captainID, players[0].UserID)
+	invitation, err := f.invitationRepo.FindByID(ctx, f.invitation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationStatusPending, invitation.Status)
+	assert.Empty(t, f.captainNotifications(t))
+}
+
+func TestRespondToInvitation_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	keep := func(repo repository.InvitationRepository) repository.InvitationRepository { return repo }
+
+	t.Run("a retried YES succeeds without joining twice", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+
+		first, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusYes, nil)
+		require.NoError(t, err)
+		retried, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusYes, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.InvitationStatusYes, retried.Status)
+		assert.Equal(t, first.RespondedAt, retried.RespondedAt)
+		players, err := f.ttrRepo.GetPlayers(ctx, f.ttr.ID)
+		require.NoError(t, err)
+		assert.Len(t, players, 2)
+		assert.Len(t, f.captainNotifications(t), 1, "the captain is told once")
+	})
+
+	t.Run("YES from someone already on the roster answers the invitation", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+		require.NoError(t, f.ttrRepo.AddPlayer(ctx, f.ttr.ID, f.inviteeID, models.TTRPlayerStatusConfirmed))
+
+		result, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusYes, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, models.InvitationStatusYes, result.Status)
+		assert.NotNil(t, result.RespondedAt)
+		players, err := f.ttrRepo.GetPlayers(ctx, f.ttr.ID)
+		require.NoError(t, err)
+		assert.Len(t, players, 2)
+		notifications := f.captainNotifications(t)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationTypeInvitationResponse, notifications[0].Type)
+	})
+
+	t.Run("a different answer after YES is still refused", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+
+		_, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusYes, nil)
+		require.NoError(t, err)
+		_, err = f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusNo, nil)
+		assert.ErrorIs(t, err, service.ErrInvitationAnswered)
+	})
+}
+
+// newUser creates another user in the fixture's store.
+func (f *invitationFixture) newUser(t *testing.T) uuid.UUID {
+	user := &models.User{Email: uuid.NewString() + "@example.com", FirstName: "Forward", LastName: "Golfer"}
+	require.NoError(t, memory.NewUserRepository(f.store).Create(context.Background(), user))
+	return user.ID
+}
+
+func (f *invitationFixture) notifications(t *testing.T, userID uuid.UUID) []*models.Notification {
+	notifications, err := f.notificationRepo.FindByUserID(context.Background(), userID, 100, 0)
+	require.NoError(t, err)
+	return notifications
+}
+
+func TestForwardInvitation_ApprovedBecomesPending(t *testing.T) {
+	ctx := context.Background()
+	f := newInvitationFixture(t, func(repo repository.InvitationRepository) repository.InvitationRepository { return repo })
+	targetID := f.newUser(t)
+
+	forwarded, err := f.service.ForwardInvitation(ctx, f.invitation.ID, f.inviteeID, targetID, stringPtr("My brother can make it"))
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationStatusForwardRequested, forwarded.Status)
+	assert.Equal(t, targetID, forwarded.InviteeUserID)
+	require.NotNil(t, forwarded.ForwardedByUserID)
+	assert.Equal(t, f.inviteeID, *forwarded.ForwardedByUserID)
+
+	original, err := f.invitationRepo.FindByID(ctx, f.invitation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationStatusNo, original.Status)
+	assert.NotNil(t, original.RespondedAt)
+
+	captain := f.captainNotifications(t)
+	require.Len(t, captain, 1)
+	assert.Equal(t, models.NotificationTypeInvitationForwarded, captain[0].Type)
+	assert.Empty(t, f.notifications(t, targetID), "the target hears nothing before approval")
+	received, err := f.service.GetUserInvitations(ctx, targetID, true)
+	require.NoError(t, err)
+	assert.Empty(t, received)
+
+	_, err = f.service.RespondToInvitation(ctx, forwarded.ID, targetID, models.InvitationStatusYes, nil)
+	assert.ErrorIs(t, err, service.ErrInvitationAnswered, "a forward can't be answered before approval")
+
+	approved, err := f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.captainID)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationStatusPending, approved.Status)
+	assert.Equal(t, f.captainID, approved.InviterUserID)
+	target := f.notifications(t, targetID)
+	require.Len(t, target, 1)
+	assert.Equal(t, models.NotificationTypeInvitation, target[0].Type)
+
+	accepted, err := f.service.RespondToInvitation(ctx, forwarded.ID, targetID, models.InvitationStatusYes, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.InvitationStatusYes, accepted.Status)
+}
+
+func TestForwardInvitation_Rejections(t *testing.T) {
+	ctx := context.Background()
+	keep := func(repo repository.InvitationRepository) repository.InvitationRepository { return repo }
+
+	tests := []struct {
+		name    string
+		setup   func(f *invitationFixture, targetID uuid.UUID) (userID, target uuid.UUID)
+		wantErr error
+	}{
+		{
+			name: "not the invitee",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				return f.captainID, targetID
+			},
+			wantErr: service.ErrNotInviteeForward,
+		},
+		{
+			name: "already answered",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				_, err := f.service.RespondToInvitation(ctx, f.invitation.ID, f.inviteeID, models.InvitationStatusNo, nil)
+				require.NoError(t, err)
+				return f.inviteeID, targetID
+			},
+			wantErr: service.ErrInvitationAnswered,
+		},
+		{
+			name: "to themselves",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				return f.inviteeID, f.inviteeID
+			},
+			wantErr: service.ErrCannotInviteSelf,
+		},
+		{
+			name: "to the captain",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				return f.inviteeID, f.captainID
+			},
+			wantErr: service.ErrCannotInviteCaptain,
+		},
+		{
+			name: "to a player on the roster",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				require.NoError(t, f.ttrRepo.AddPlayer(ctx, f.ttr.ID, targetID, models.TTRPlayerStatusConfirmed))
+				return f.inviteeID, targetID
+			},
+			wantErr: service.ErrForwardToRoster,
+		},
+		{
+			name: "to someone the captain blocked",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				require.NoError(t, memory.NewUserRepository(f.store).Block(ctx, f.captainID, targetID))
+				return f.inviteeID, targetID
+			},
+			wantErr: service.ErrForwardToBlocked,
+		},
+		{
+			name: "to someone already invited",
+			setup: func(f *invitationFixture, targetID uuid.UUID) (uuid.UUID, uuid.UUID) {
+				_, err := f.service.CreateInvitation(ctx, f.ttr.ID, f.captainID, targetID, nil, nil)
+				require.NoError(t, err)
+				return f.inviteeID, targetID
+			},
+			wantErr: service.ErrInvitationPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newInvitationFixture(t, keep)
+			userID, targetID := tt.setup(f, f.newUser(t))
+
+			_, err := f.service.ForwardInvitation(ctx, f.invitation.ID, userID, targetID, nil)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestApproveForwardedInvitation_Rejections(t *testing.T) {
+	ctx := context.Background()
+	keep := func(repo repository.InvitationRepository) repository.InvitationRepository { return repo }
+	forward := func(t *testing.T, f *invitationFixture) (*service.InvitationDetail, uuid.UUID) {
+		targetID := f.newUser(t)
+		forwarded, err := f.service.ForwardInvitation(ctx, f.invitation.ID, f.inviteeID, targetID, nil)
+		require.NoError(t, err)
+		return forwarded, targetID
+	}
+
+	t.Run("only managers can approve", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+		forwarded, _ := forward(t, f)
+
+		_, err := f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.inviteeID)
+		assert.ErrorIs(t, err, service.ErrNotManagerApproveForward)
+	})
+
+	t.Run("only forwarded invitations can be approved", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+
+		_, err := f.service.ApproveForwardedInvitation(ctx, f.invitation.ID, f.captainID)
+		assert.ErrorIs(t, err, service.ErrInvitationNotForwarded)
+
+		forwarded, _ := forward(t, f)
+		_, err = f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.captainID)
+		require.NoError(t, err)
+		_, err = f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.captainID)
+		assert.ErrorIs(t, err, service.ErrInvitationNotForwarded, "approving twice")
+	})
+
+	t.Run("the target is checked again", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+		forwarded, targetID := forward(t, f)
+		require.NoError(t, memory.NewUserRepository(f.store).Block(ctx, f.captainID, targetID))
+
+		_, err := f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.captainID)
+		assert.ErrorIs(t, err, service.ErrForwardToBlocked)
+	})
+
+	t.Run("the captain turns a forward down by cancelling it", func(t *testing.T) {
+		f := newInvitationFixture(t, keep)
+		forwarded, targetID := forward(t, f)
+
+		require.NoError(t, f.service.CancelInvitation(ctx, forwarded.ID, f.captainID))
+		invitation, err := f.invitationRepo.FindByID(ctx, forwarded.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.InvitationStatusCanceled, invitation.Status)
+		assert.Empty(t, f.notifications(t, targetID))
+
+		_, err = f.service.ApproveForwardedInvitation(ctx, forwarded.ID, f.captainID)
+		assert.ErrorIs(t, err, service.ErrInvitationNotForwarded)
+	})
+}
+
+func TestTTR_RSVPDeadlinePassed_BoundarySecond(t *testing.T) {
+	deadline := time.Date(2030, 6, 1, 7, 0, 0, 0, time.UTC)
+	ttr := &models.TTR{RSVPDeadline: &deadline}
+
+	assert.False(t, ttr.RSVPDeadlinePassed(deadline.Add(-time.Second)))
+	assert.False(t, ttr.RSVPDeadlinePassed(deadline), "responses are still accepted at the deadline itself")
+	assert.True(t, ttr.RSVPDeadlinePassed(deadline.Add(time.Second)))
+
+	assert.False(t, (&models.TTR{}).RSVPDeadlinePassed(deadline.AddDate(10, 0, 0)), "nil deadline is never enforced")
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/jwt"
+	"github.com/yourusername/golf_messenger/pkg/response"
+)
+
+const testJWTSecret = "test-secret"
+
+func TestJWT_TokenTypes(t *testing.T) {
+	userID := uuid.New()
+	access, err := jwt.GenerateAccessToken(userID, "golfer@example.com", models.UserRoleUser, testJWTSecret, time.Minute)
+	require.NoError(t, err)
+	impersonation, err := jwt.GenerateImpersonationToken(userID, "golfer@example.com", models.UserRoleUser, jwt.Actor{Subject: uuid.New()}, uuid.NewString(), testJWTSecret, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	challenge, err := jwt.GenerateToken(jwt.TokenTypeChallenge, userID, map[string]string{"method": "totp"}, testJWTSecret, time.Minute)
+	require.NoError(t, err)
+	link, err := jwt.GenerateToken(jwt.TokenTypeLink, userID, nil, testJWTSecret, time.Minute)
+	require.NoError(t, err)
+
+	tokens := map[string]string{
+		jwt.TokenTypeAccess:    access,
+		jwt.TokenTypeChallenge: challenge,
+		jwt.TokenTypeLink:      link,
+	}
+	for signedAs, token := range tokens {
+		for validatedAs := range tokens {
+			claims, err := jwt.ValidateToken(token, validatedAs, testJWTSecret)
+			if signedAs != validatedAs {
+				assert.ErrorIs(t, err, jwt.ErrWrongTokenType, "%s token validated as %s", signedAs, validatedAs)
+				continue
+			}
+			require.NoError(t, err, signedAs)
+			assert.Equal(t, signedAs, claims.TokenType)
+			assert.Equal(t, userID, claims.UserID)
+		}
+	}
+
+	claims, err := jwt.ValidateAccessToken(impersonation, testJWTSecret)
+	require.NoError(t, err, "impersonation tokens are access tokens")
+	assert.NotNil(t, claims.Act)
+
+	claims, err = jwt.ValidateToken(challenge, jwt.TokenTypeChallenge, testJWTSecret)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"method": "totp"}, claims.Extra)
+
+	_, err = jwt.ValidateAccessToken(challenge, testJWTSecret)
+	assert.ErrorIs(t, err, jwt.ErrWrongTokenType)
+	_, err = jwt.ValidateToken(challenge, jwt.TokenTypeChallenge, "another-secret")
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken, "the signature is checked before the type")
+}
+
+func TestAuth_RejectsNonAccessTokens(t *testing.T) {
+	userID := uuid.New()
+	handler := middleware.Auth(testJWTSecret, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(token string) (int, response.Response) {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/v1/users/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		var body response.Response
+		if w.Code != http.StatusNoContent {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		}
+		return w.Code, body
+	}
+
+	access, err := jwt.GenerateAccessToken(userID, "golfer@example.com", models.UserRoleUser, testJWTSecret, time.Minute)
+	require.NoError(t, err)
+	code, _ := serve(access)
+	assert.Equal(t, http.StatusNoContent, code)
+
+	for _, tokenType := range []string{jwt.TokenTypeChallenge, jwt.TokenTypeLink} {
+		token, err := jwt.GenerateToken(tokenType, userID, nil, testJWTSecret, time.Minute)
+		require.NoError(t, err)
+		code, body := serve(token)
+		assert.Equal(t, http.StatusUnauthorized, code, tokenType)
+		require.NotNil(t, body.Error)
+		assert.Equal(t, "WRONG_TOKEN_TYPE", body.Error.Code, tokenType)
+	}
+
+	code, body := serve("not-a-token")
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, "UNAUTHORIZED", body.Error.Code)
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/lifecycle"
+	"go.uber.org/zap"
+)
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *eventRecorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func TestLifecycleManager_ShutdownOrder(t *testing.T) {
+	m := lifecycle.NewManager(zap.NewNop())
+	rec := &eventRecorder{}
+
+	started := make(chan struct{})
+	m.Go("worker", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		rec.add("worker stopped")
+		return nil
+	})
+	<-started
+
+	m.OnShutdown("first", func(ctx context.Context) error {
+		rec.add("first")
+		return nil
+	})
+	m.OnShutdown("second", func(ctx context.Context) error {
+		assert.NoError(t, m.Context().Err(), "workers must still be running while hooks drain")
+		rec.add("second")
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, m.Shutdown(ctx))
+	assert.Equal(t, []string{"second", "first", "worker stopped"}, rec.list())
+	assert.Error(t, m.Context().Err())
+}
+
+func TestLifecycleManager_WorkerFinishesCurrentItem(t *testing.T) {
+	m := lifecycle.NewManager(zap.NewNop())
+	rec := &eventRecorder{}
+
+	started := make(chan struct{})
+	m.Go("worker", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		rec.add("item finished")
+		return ctx.Err()
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, m.Shutdown(ctx))
+	assert.Equal(t, []string{"item finished"}, rec.list())
+}
+
+func TestLifecycleManager_ShutdownTimeout(t *testing.T) {
+	m := lifecycle.NewManager(zap.NewNop())
+
+	release := make(chan struct{})
+	defer close(release)
+	m.Go("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := m.Shutdown(ctx)
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestLifecycleManager_HookErrorsAreReturned(t *testing.T) {
+	m := lifecycle.NewManager(zap.NewNop())
+	rec := &eventRecorder{}
+
+	m.OnShutdown("ok", func(ctx context.Context) error {
+		rec.add("ok")
+		return nil
+	})
+	m.OnShutdown("broken", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+
+	err := m.Shutdown(context.Background())
+
+	assert.ErrorContains(t, err, "broken: boom")
+	assert.Equal(t, []string{"ok"}, rec.list(), "a failing hook must not skip the rest")
+}
+
+func TestLifecycleManager_Every(t *testing.T) {
+	m := lifecycle.NewManager(zap.NewNop())
+
+	var mu sync.Mutex
+	runs := 0
+	m.Every("tick", 5*time.Millisecond, func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs++
+		return errors.New("errors do not stop the task")
+	})
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return runs >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, m.Shutdown(context.Background()))
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/mailevents"
+)
+
+// signSendGrid signs body the way SendGrid's event webhook does.
+func signSendGrid(t *testing.T, key *ecdsa.PrivateKey, body []byte, at time.Time) http.Header {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	digest := sha256.Sum256(append([]byte(timestamp), body...))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	header := http.Header{}
+	header.Set(mailevents.SendGridTimestampHeader, timestamp)
+	header.Set(mailevents.SendGridSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	return header
+}
+
+func TestMailEvents_VerifySendGrid(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	publicKey, err := mailevents.ParseSendGridPublicKey(base64.StdEncoding.EncodeToString(der))
+	require.NoError(t, err)
+
+	body := []byte(`[{"email":"ann@example.com","event":"bounce"}]`)
+	signedAt := time.Now()
+	header := signSendGrid(t, key, body, signedAt)
+
+	assert.NoError(t, mailevents.VerifySendGrid(publicKey, header, body, signedAt.Add(time.Minute)))
+	assert.ErrorIs(t, mailevents.VerifySendGrid(publicKey, header, append(body, ' '), signedAt), mailevents.ErrInvalidSignature)
+	assert.ErrorIs(t, mailevents.VerifySendGrid(publicKey, header, body, signedAt.Add(mailevents.MaxRequestAge+time.Second)), mailevents.ErrStaleRequest, "replays are refused")
+	assert.ErrorIs(t, mailevents.VerifySendGrid(publicKey, http.Header{}, body, signedAt), mailevents.ErrMissingSignature)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	assert.ErrorIs(t, mailevents.VerifySendGrid(publicKey, signSendGrid(t, otherKey, body, signedAt), body, signedAt), mailevents.ErrInvalidSignature)
+
+	_, err = mailevents.ParseSendGridPublicKey("not base64!")
+	assert.Error(t, err)
+}
+
+func TestMailEvents_ParseSendGrid(t *testing.T) {
+	events, err := mailevents.ParseSendGrid([]byte(`[
+		{"sg_event_id":"e1","email":"ann@example.com","event":"bounce","type":"bounce","reason":"550 no such user"},
+		{"sg_event_id":"e2","email":"bob@example.com","event":"bounce","type":"blocked","reason":"mailbox full"},
+		{"sg_event_id":"e3","email":"cid@example.com","event":"spamreport"},
+		{"sg_event_id":"e4","email":"dee@example.com","event":"delivered"},
+		{"sg_event_id":"e5","email":"eve@example.com","event":"open"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []mailevents.Event{
+		{ID: "e1", Email: "ann@example.com", Kind: mailevents.KindHardBounce, Reason: "550 no such user"},
+		{ID: "e2", Email: "bob@example.com", Kind: mailevents.KindSoftBounce, Reason: "mailbox full"},
+		{ID: "e3", Email: "cid@example.com", Kind: mailevents.KindComplaint},
+		{ID: "e4", Email: "dee@example.com", Kind: mailevents.KindDelivered},
+	}, events)
+
+	_, err = mailevents.ParseSendGrid([]byte(`{"event":"bounce"}`))
+	assert.Error(t, err)
+}
+
+const (
+	testSNSTopic   = "arn:aws:sns:us-east-1:123456789012:ses-events"
+	testSNSCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-test.pem"
+)
+
+// newSNSSigner returns an RSA key with a self-signed certificate for it, and
+// a verifier that is served that certificate from testSNSCertURL.
+func newSNSSigner(t *testing.T) (*rsa.PrivateKey, *mailevents.SNSVerifier) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	verifier := mailevents.NewSNSVerifier(testSNSTopic)
+	verifier.Fetch = func(ctx context.Context, certURL string) ([]byte, error) {
+		require.Equal(t, testSNSCertURL, certURL)
+		return certPEM, nil
+	}
+	return key, verifier
+}
+
+// signSNS signs msg with key the way SNS does for signature version.
+func signSNS(t *testing.T, key *rsa.PrivateKey, msg *mailevents.SNSMessage, version string) {
+	msg.SignatureVersion = version
+	msg.SigningCertURL = testSNSCertURL
+	var signature []byte
+	var err error
+	if version == "1" {
+		digest := sha1.Sum([]byte(msg.StringToSign()))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	} else {
+		digest := sha256.Sum256([]byte(msg.StringToSign()))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+	require.NoError(t, err)
+	msg.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func sesNotification(message string) *mailevents.SNSMessage {
+	return &mailevents.SNSMessage{
+		Type:      mailevents.SNSTypeNotification,
+		MessageID: "msg-1",
+		TopicArn:  testSNSTopic,
+		Message:   message,
+		Timestamp: "2024-06-01T12:00:00.000Z",
+	}
+}
+
+func TestMailEvents_VerifySNS(t *testing.T) {
+	ctx := context.Background()
+	key, verifier := newSNSSigner(t)
+
+	for _, version := range []string{"1", "2"} {
+		t.Run("signature version "+version, func(t *testing.T) {
+			msg := sesNotification(`{"notificationType":"Bounce"}`)
+			signSNS(t, key, msg, version)
+			assert.NoError(t, verifier.Verify(ctx, msg))
+
+			msg.Message = `{"notificationType":"Delivery"}`
+			assert.ErrorIs(t, verifier.Verify(ctx, msg), mailevents.ErrInvalidSignature)
+		})
+	}
+
+	t.Run("subscription confirmation", func(t *testing.T) {
+		msg := &mailevents.SNSMessage{
+			Type:         mailevents.SNSTypeSubscriptionConfirmation,
+			MessageID:    "msg-2",
+			Token:        "token",
+			TopicArn:     testSNSTopic,
+			Message:      "You have chosen to subscribe",
+			SubscribeURL: "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription",
+			Timestamp:    "2024-06-01T12:00:00.000Z",
+		}
+		signSNS(t, key, msg, "2")
+		assert.NoError(t, verifier.Verify(ctx, msg))
+		msg.Token = "another"
+		assert.ErrorIs(t, verifier.Verify(ctx, msg), mailevents.ErrInvalidSignature)
+	})
+
+	t.Run("other topics are refused", func(t *testing.T) {
+		msg := sesNotification("{}")
+		msg.TopicArn = "arn:aws:sns:us-east-1:123456789012:other"
+		signSNS(t, key, msg, "2")
+		assert.ErrorIs(t, verifier.Verify(ctx, msg), mailevents.ErrUnknownTopic)
+	})
+
+	t.Run("certificates only come from SNS", func(t *testing.T) {
+		msg := sesNotification("{}")
+		signSNS(t, key, msg, "2")
+		for _, certURL := range []string{
+			"http://sns.us-east-1.amazonaws.com/cert.pem",
+			"https://sns.us-east-1.amazonaws.com.evil.example/cert.pem",
+			"https://example.com/cert.pem",
+		} {
+			msg.SigningCertURL = certURL
+			assert.ErrorIs(t, verifier.Verify(ctx, msg), mailevents.ErrInvalidSignature, certURL)
+		}
+	})
+
+	t.Run("unsigned messages are refused", func(t *testing.T) {
+		assert.ErrorIs(t, verifier.Verify(ctx, sesNotification("{}")), mailevents.ErrMissingSignature)
+	})
+}
+
+func TestMailEvents_ParseSES(t *testing.T) {
+	events, err := mailevents.ParseSES(sesNotification(`{"notificationType":"Bounce","bounce":{"bounceType":"Permanent","bounceSubType":"General",
+		"bouncedRecipients":[{"emailAddress":"ann@example.com","diagnosticCode":"550 no such user"},{"emailAddress":"bob@example.com"}]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []mailevents.Event{
+		{ID: "msg-1:ann@example.com", Email: "ann@example.com", Kind: mailevents.KindHardBounce, Reason: "550 no such user"},
+		{ID: "msg-1:bob@example.com", Email: "bob@example.com", Kind: mailevents.KindHardBounce, Reason: "General"},
+	}, events)
+
+	events, err = mailevents.ParseSES(sesNotification(`{"eventType":"Bounce","bounce":{"bounceType":"Transient","bounceSubType":"MailboxFull",
+		"bouncedRecipients":[{"emailAddress":"cid@example.com"}]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []mailevents.Event{{ID: "msg-1:cid@example.com", Email: "cid@example.com", Kind: mailevents.KindSoftBounce, Reason: "MailboxFull"}}, events)
+
+	events, err = mailevents.ParseSES(sesNotification(`{"notificationType":"Complaint","complaint":{"complainedRecipients":[{"emailAddress":"dee@example.com"}]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []mailevents.Event{{ID: "msg-1:dee@example.com", Email: "dee@example.com", Kind: mailevents.KindComplaint}}, events)
+
+	events, err = mailevents.ParseSES(sesNotification(`{"notificationType":"Delivery","delivery":{"recipients":["eve@example.com"]}}`))
+	require.NoError(t, err)
+	assert.Equal(t, []mailevents.Event{{ID: "msg-1:eve@example.com", Email: "eve@example.com", Kind: mailevents.KindDelivered}}, events)
+
+	_, err = mailevents.ParseSES(sesNotification("not json"))
+	assert.Error(t, err)
+}
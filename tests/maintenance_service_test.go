@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/cache"
+	"go.uber.org/zap"
+)
+
+func TestMaintenanceService_SharedThroughCache(t *testing.T) {
+	ctx := context.Background()
+	shared := cache.NewMemoryCache()
+	instance := service.NewMaintenanceService(shared, zap.NewNop())
+
+	active, _ := instance.Maintenance(ctx)
+	assert.False(t, active)
+
+	endsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	_, err := instance.SetState(ctx, service.MaintenanceState{Enabled: true, EndsAt: &endsAt})
+	require.NoError(t, err)
+
+	other := service.NewMaintenanceService(shared, zap.NewNop())
+	active, seenEndsAt := other.Maintenance(ctx)
+	assert.True(t, active, "another instance sees the toggle")
+	require.NotNil(t, seenEndsAt)
+	assert.True(t, seenEndsAt.Equal(endsAt))
+
+	state, err := instance.SetState(ctx, service.MaintenanceState{Enabled: false, EndsAt: &endsAt})
+	require.NoError(t, err)
+	assert.Nil(t, state.EndsAt, "turning it off clears the estimate")
+	active, _ = service.NewMaintenanceService(shared, zap.NewNop()).Maintenance(ctx)
+	assert.False(t, active)
+}
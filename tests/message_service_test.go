@@ -0,0 +1,305 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/storage"
+	"go.uber.org/zap"
+)
+
+type MockMessageRepository struct {
+	mock.Mock
+}
+
+func (m *MockMessageRepository) Create(ctx context.Context, message *models.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindByTTRID(ctx context.Context, ttrID uuid.UUID, limit int, offset int) ([]*models.Message, error) {
+	args := m.Called(ttrID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) Update(ctx context.Context, message *models.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) MarkRead(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, readAt time.Time) error {
+	args := m.Called(ttrID, userID, readAt)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) CountUnreadByUserID(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]int64), args.Error(1)
+}
+
+func (m *MockMessageRepository) AddReaction(ctx context.Context, reaction *models.MessageReaction) error {
+	args := m.Called(reaction)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) RemoveReaction(ctx context.Context, messageID uuid.UUID, userID uuid.UUID, emoji string) error {
+	args := m.Called(messageID, userID, emoji)
+	return args.Error(0)
+}
+
+func (m *MockMessageRepository) SummarizeReactions(ctx context.Context, messageIDs []uuid.UUID, userID uuid.UUID) (map[uuid.UUID][]models.ReactionSummary, error) {
+	args := m.Called(messageIDs, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID][]models.ReactionSummary), args.Error(1)
+}
+
+func (m *MockMessageRepository) SumAttachmentSizeByTTRID(ctx context.Context, ttrID uuid.UUID) (int64, error) {
+	args := m.Called(ttrID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMessageRepository) SumAttachmentSizeByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockMessageRepository) FindDeletedWithAttachments(ctx context.Context, limit int) ([]*models.Message, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) ClearAttachment(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+const testEditWindow = 15 * time.Minute
+
+func newTestMessageService(messageRepo *MockMessageRepository, ttrRepo *MockTTRRepository) *service.MessageService {
+	logger, _ := zap.NewDevelopment()
+	authorizer := service.NewAuthorizer(ttrRepo, new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository))
+	return service.NewMessageService(messageRepo, authorizer, storage.NewMemoryStorage(), config.MessagingConfig{
+		EditWindow:          testEditWindow,
+		AttachmentMaxSize:   1024,
+		TTRAttachmentQuota:  4096,
+		UserAttachmentQuota: 2048,
+		AttachmentURLTTL:    15 * time.Minute,
+	}, nil, logger)
+}
+
+func TestEditMessage_Permissions(t *testing.T) {
+	ttrID := uuid.New()
+	authorID := uuid.New()
+	captainID := uuid.New()
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		postedAt time.Time
+		deleted  bool
+		wantErr  string
+	}{
+		{name: "author within window", userID: authorID, postedAt: time.Now().Add(-time.Minute)},
+		{name: "author just inside window", userID: authorID, postedAt: time.Now().Add(-testEditWindow + time.Second)},
+		{name: "author after window", userID: authorID, postedAt: time.Now().Add(-testEditWindow - time.Second), wantErr: "unauthorized: edit window has expired"},
+		{name: "captain is not the author", userID: captainID, postedAt: time.Now(), wantErr: "unauthorized: only the author can edit a message"},
+		{name: "other player", userID: uuid.New(), postedAt: time.Now(), wantErr: "unauthorized: only the author can edit a message"},
+		{name: "deleted message", userID: authorID, postedAt: time.Now(), deleted: true, wantErr: "message has been deleted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMessageRepo := new(MockMessageRepository)
+			messageService := newTestMessageService(mockMessageRepo, new(MockTTRRepository))
+
+			message := &models.Message{
+				ID:        uuid.New(),
+				TTRID:     ttrID,
+				UserID:    authorID,
+				Body:      "Tee time moved",
+				Deleted:   tt.deleted,
+				CreatedAt: tt.postedAt,
+			}
+
+			mockMessageRepo.On("FindByID", message.ID).Return(message, nil)
+			mockMessageRepo.On("Update", mock.AnythingOfType("*models.Message")).Return(nil)
+
+			result, err := messageService.EditMessage(context.Background(), ttrID, message.ID, tt.userID, "Tee time moved to 9:00")
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockMessageRepo.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "Tee time moved to 9:00", result.Body)
+			assert.NotNil(t, result.EditedAt)
+		})
+	}
+}
+
+func TestEditMessage_WrongTTR(t *testing.T) {
+	mockMessageRepo := new(MockMessageRepository)
+	messageService := newTestMessageService(mockMessageRepo, new(MockTTRRepository))
+
+	authorID := uuid.New()
+	message := &models.Message{ID: uuid.New(), TTRID: uuid.New(), UserID: authorID, CreatedAt: time.Now()}
+
+	mockMessageRepo.On("FindByID", message.ID).Return(message, nil)
+
+	_, err := messageService.EditMessage(context.Background(), uuid.New(), message.ID, authorID, "hello")
+
+	assert.Error(t, err)
+	assert.Equal(t, "message not found", err.Error())
+}
+
+func TestDeleteMessage_Permissions(t *testing.T) {
+	ttrID := uuid.New()
+	authorID := uuid.New()
+	captainID := uuid.New()
+	coCaptainID := uuid.New()
+	playerID := uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		wantErr string
+	}{
+		{name: "author", userID: authorID},
+		{name: "captain", userID: captainID},
+		{name: "co-captain", userID: coCaptainID},
+		{name: "other player", userID: playerID, wantErr: "unauthorized: only the author, captain or co-captain can delete a message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMessageRepo := new(MockMessageRepository)
+			mockTTRRepo := new(MockTTRRepository)
+			messageService := newTestMessageService(mockMessageRepo, mockTTRRepo)
+
+			message := &models.Message{
+				ID:        uuid.New(),
+				TTRID:     ttrID,
+				UserID:    authorID,
+				Body:      "Running late",
+				CreatedAt: time.Now().Add(-24 * time.Hour),
+			}
+
+			mockMessageRepo.On("FindByID", message.ID).Return(message, nil)
+			mockMessageRepo.On("Update", mock.AnythingOfType("*models.Message")).Return(nil)
+			mockTTRRepo.On("FindByID", ttrID).Return(&models.TTR{
+				ID:            ttrID,
+				CaptainUserID: captainID,
+				CoCaptains:    []models.TTRCoCaptain{{TTRID: ttrID, UserID: coCaptainID}},
+				Players:       []models.TTRPlayer{{TTRID: ttrID, UserID: playerID}},
+			}, nil)
+
+			err := messageService.DeleteMessage(context.Background(), ttrID, message.ID, tt.userID)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockMessageRepo.AssertNotCalled(t, "Update", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, message.Deleted)
+			assert.Empty(t, message.Body)
+			mockMessageRepo.AssertCalled(t, "Update", message)
+		})
+	}
+}
+
+func TestAddReaction_RejectsUnknownEmoji(t *testing.T) {
+	mockMessageRepo := new(MockMessageRepository)
+	messageService := newTestMessageService(mockMessageRepo, new(MockTTRRepository))
+
+	for _, emoji := range []string{"", "THUMBSUP", "thumbsup ", "<img src=x>"} {
+		err := messageService.AddReaction(context.Background(), uuid.New(), uuid.New(), uuid.New(), emoji)
+
+		assert.Error(t, err)
+		assert.Equal(t, "invalid emoji", err.Error())
+	}
+	mockMessageRepo.AssertNotCalled(t, "AddReaction", mock.Anything)
+}
+
+func TestPostAttachment_Limits(t *testing.T) {
+	ttrID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name        string
+		contentType string
+		size        int64
+		ttrUsage    int64
+		userUsage   int64
+		wantErr     string
+	}{
+		{name: "within limits", contentType: "image/png", size: 1024},
+		{name: "unsupported type", contentType: "text/html", size: 10, wantErr: "unsupported attachment type"},
+		{name: "over size limit", contentType: "application/pdf", size: 1025, wantErr: "attachment is too large"},
+		{name: "fills TTR quota exactly", contentType: "image/jpeg", size: 96, ttrUsage: 4000},
+		{name: "over TTR quota", contentType: "image/jpeg", size: 97, ttrUsage: 4000, wantErr: "TTR attachment storage quota exceeded"},
+		{name: "over user quota", contentType: "image/jpeg", size: 100, userUsage: 2000, wantErr: "user attachment storage quota exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockMessageRepo := new(MockMessageRepository)
+			mockTTRRepo := new(MockTTRRepository)
+			messageService := newTestMessageService(mockMessageRepo, mockTTRRepo)
+
+			mockTTRRepo.On("FindByID", ttrID).Return(&models.TTR{ID: ttrID, CaptainUserID: userID}, nil)
+			mockMessageRepo.On("SumAttachmentSizeByTTRID", ttrID).Return(tt.ttrUsage, nil)
+			mockMessageRepo.On("SumAttachmentSizeByUserID", userID).Return(tt.userUsage, nil)
+			var created *models.Message
+			mockMessageRepo.On("Create", mock.AnythingOfType("*models.Message")).Return(nil).Run(func(args mock.Arguments) {
+				created = args.Get(0).(*models.Message)
+			})
+			mockMessageRepo.On("FindByID", mock.Anything).Return(&models.Message{TTRID: ttrID, UserID: userID}, nil)
+
+			file := strings.NewReader(strings.Repeat("x", int(tt.size)))
+			message, err := messageService.PostAttachment(context.Background(), ttrID, userID, "", file, "scorecard.PNG", tt.contentType, tt.size)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockMessageRepo.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, message.AttachmentURL)
+			assert.True(t, strings.HasPrefix(*created.AttachmentKey, "ttr-attachments/"+ttrID.String()+"/"))
+			assert.True(t, strings.HasSuffix(*created.AttachmentKey, ".png"))
+			assert.Equal(t, tt.size, created.AttachmentSize)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+func TestNotificationService_Digest(t *testing.T) {
+	ctx := context.Background()
+	captainID := uuid.New()
+	ttrID := uuid.New()
+	targetType := "ttr"
+	params := map[string]string{"course": "Pebble Beach"}
+
+	setup := func() (*service.NotificationService, func() []*models.Notification) {
+		repo := memory.NewNotificationRepository(memory.NewStore())
+		notificationService := service.NewNotificationService(repo, nil, nil, time.Hour, zap.NewNop())
+		list := func() []*models.Notification {
+			notifications, err := repo.FindByUserID(ctx, captainID, 10, 0)
+			require.NoError(t, err)
+			return notifications
+		}
+		return notificationService, list
+	}
+	join := func(notificationService *service.NotificationService) {
+		require.NoError(t, notificationService.Notify(ctx, captainID, models.NotificationTypePlayerJoined, "player_joined", params, &targetType, &ttrID))
+	}
+
+	t.Run("events within the window collapse", func(t *testing.T) {
+		notificationService, list := setup()
+		for i := 0; i < 6; i++ {
+			join(notificationService)
+		}
+
+		notifications := list()
+		require.Len(t, notifications, 1)
+		assert.Equal(t, 6, notifications[0].EventCount)
+		assert.Equal(t, "Players Joined", notifications[0].Title)
+		assert.Equal(t, "6 players joined your tee time at Pebble Beach", notifications[0].Message)
+	})
+
+	t.Run("a single event is a plain notification", func(t *testing.T) {
+		notificationService, list := setup()
+		join(notificationService)
+
+		notifications := list()
+		require.Len(t, notifications, 1)
+		assert.Equal(t, 1, notifications[0].EventCount)
+		assert.Equal(t, "A player joined your tee time at Pebble Beach", notifications[0].Message)
+	})
+
+	t.Run("digesting marks the notification unread again", func(t *testing.T) {
+		repo := memory.NewNotificationRepository(memory.NewStore())
+		notificationService := service.NewNotificationService(repo, nil, nil, time.Hour, zap.NewNop())
+		join(notificationService)
+		notifications, err := repo.FindByUserID(ctx, captainID, 10, 0)
+		require.NoError(t, err)
+		require.NoError(t, repo.MarkAsRead(ctx, notifications[0].ID))
+
+		join(notificationService)
+
+		digest, err := repo.FindByID(ctx, notifications[0].ID)
+		require.NoError(t, err)
+		assert.False(t, digest.IsRead)
+		assert.Nil(t, digest.ReadAt)
+		assert.Equal(t, 2, digest.EventCount)
+	})
+
+	t.Run("an expired window starts a new notification", func(t *testing.T) {
+		repo := memory.NewNotificationRepository(memory.NewStore())
+		notificationService := service.NewNotificationService(repo, nil, nil, time.Hour, zap.NewNop())
+		old := &models.Notification{
+			UserID:     captainID,
+			Type:       models.NotificationTypePlayerJoined,
+			Title:      "Player Joined",
+			Message:    "A player joined your tee time at Pebble Beach",
+			TargetType: &targetType,
+			TargetID:   &ttrID,
+			CreatedAt:  time.Now().Add(-2 * time.Hour),
+		}
+		require.NoError(t, repo.Create(ctx, old))
+
+		join(notificationService)
+
+		notifications, err := repo.FindByUserID(ctx, captainID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.NotEqual(t, old.ID, notifications[0].ID)
+		assert.Equal(t, 1, notifications[0].EventCount)
+		assert.Equal(t, 1, notifications[1].EventCount)
+	})
+
+	t.Run("other targets and templates are not digested", func(t *testing.T) {
+		notificationService, list := setup()
+		otherTTRID := uuid.New()
+		join(notificationService)
+		require.NoError(t, notificationService.Notify(ctx, captainID, models.NotificationTypePlayerJoined, "player_joined", params, &targetType, &otherTTRID))
+		require.NoError(t, notificationService.Notify(ctx, captainID, "TTR_CANCELLED", "ttr_cancelled", params, &targetType, &ttrID))
+		require.NoError(t, notificationService.Notify(ctx, captainID, "TTR_CANCELLED", "ttr_cancelled", params, &targetType, &ttrID))
+
+		assert.Len(t, list(), 4)
+	})
+
+	t.Run("a zero window turns digests off", func(t *testing.T) {
+		repo := memory.NewNotificationRepository(memory.NewStore())
+		notificationService := service.NewNotificationService(repo, nil, nil, 0, zap.NewNop())
+		join(notificationService)
+		join(notificationService)
+
+		notifications, err := repo.FindByUserID(ctx, captainID, 10, 0)
+		require.NoError(t, err)
+		assert.Len(t, notifications, 2)
+	})
+}
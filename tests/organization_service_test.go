@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	args := m.Called(org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*models.Organization, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) Update(ctx context.Context, org *models.Organization) error {
+	args := m.Called(org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) (*models.OrganizationMember, error) {
+	args := m.Called(orgID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	args := m.Called(member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID uuid.UUID, userID uuid.UUID) error {
+	args := m.Called(orgID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetMembers(ctx context.Context, orgID uuid.UUID) ([]*models.OrganizationMember, error) {
+	args := m.Called(orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OrganizationMember), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) CreateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	args := m.Called(invitation)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) FindInvitationByID(ctx context.Context, id uuid.UUID) (*models.OrganizationInvitation, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindPendingInvitation(ctx context.Context, orgID uuid.UUID, email string) (*models.OrganizationInvitation, error) {
+	args := m.Called(orgID, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) FindPendingInvitationsByEmail(ctx context.Context, email string) ([]*models.OrganizationInvitation, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) UpdateInvitation(ctx context.Context, invitation *models.OrganizationInvitation) error {
+	args := m.Called(invitation)
+	return args.Error(0)
+}
+
+func TestInviteMember_RoleRules(t *testing.T) {
+	orgID := uuid.New()
+	ownerID := uuid.New()
+	adminID := uuid.New()
+	memberID := uuid.New()
+
+	tests := []struct {
+		name    string
+		inviter uuid.UUID
+		role    string
+		wantErr string
+	}{
+		{name: "owner invites admin", inviter: ownerID, role: models.OrganizationRoleAdmin},
+		{name: "admin invites member", inviter: adminID, role: models.OrganizationRoleMember},
+		{name: "admin invites admin", inviter: adminID, role: models.OrganizationRoleAdmin, wantErr: "unauthorized: only the organization owner can grant the admin role"},
+		{name: "member invites member", inviter: memberID, role: models.OrganizationRoleMember, wantErr: "unauthorized: only organization owners and admins can invite members"},
+		{name: "non-member", inviter: uuid.New(), role: models.OrganizationRoleMember, wantErr: "organization not found"},
+		{name: "owner role", inviter: ownerID, role: models.OrganizationRoleOwner, wantErr: "invalid role"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrgRepo := new(MockOrganizationRepository)
+			mockUserRepo := new(MockUserRepository)
+			logger := zap.NewNop()
+			orgService := service.NewOrganizationService(mockOrgRepo, mockUserRepo, service.NewAuthorizer(new(MockTTRRepository), mockOrgRepo, new(MockInvitationRepository), mockUserRepo), passthroughTransactor{}, service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+			mockOrgRepo.On("FindByID", orgID).Return(&models.Organization{ID: orgID, Name: "Pine Valley GC"}, nil)
+			mockOrgRepo.On("FindMember", orgID, ownerID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: ownerID, Role: models.OrganizationRoleOwner}, nil)
+			mockOrgRepo.On("FindMember", orgID, adminID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: adminID, Role: models.OrganizationRoleAdmin}, nil)
+			mockOrgRepo.On("FindMember", orgID, memberID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: memberID, Role: models.OrganizationRoleMember}, nil)
+			mockOrgRepo.On("FindMember", orgID, mock.Anything).Return(nil, nil)
+			mockUserRepo.On("FindByEmail", "new.member@example.com").Return(nil, nil)
+			mockOrgRepo.On("FindPendingInvitation", orgID, "new.member@example.com").Return(nil, nil)
+			mockOrgRepo.On("CreateInvitation", mock.AnythingOfType("*models.OrganizationInvitation")).Return(nil)
+
+			invitation, err := orgService.InviteMember(context.Background(), orgID, tt.inviter, " New.Member@example.com ", tt.role)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockOrgRepo.AssertNotCalled(t, "CreateInvitation", mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "new.member@example.com", invitation.Email)
+			assert.Equal(t, tt.role, invitation.Role)
+		})
+	}
+}
+
+func TestRemoveMember_Permissions(t *testing.T) {
+	orgID := uuid.New()
+	ownerID := uuid.New()
+	adminID := uuid.New()
+	otherAdminID := uuid.New()
+	memberID := uuid.New()
+	roles := map[uuid.UUID]string{
+		ownerID:      models.OrganizationRoleOwner,
+		adminID:      models.OrganizationRoleAdmin,
+		otherAdminID: models.OrganizationRoleAdmin,
+		memberID:     models.OrganizationRoleMember,
+	}
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		target  uuid.UUID
+		wantErr string
+	}{
+		{name: "member leaves", actor: memberID, target: memberID},
+		{name: "admin removes member", actor: adminID, target: memberID},
+		{name: "owner removes admin", actor: ownerID, target: adminID},
+		{name: "admin removes admin", actor: adminID, target: otherAdminID, wantErr: "unauthorized: only the organization owner can remove admins"},
+		{name: "member removes member", actor: memberID, target: adminID, wantErr: "unauthorized: only organization owners and admins can remove members"},
+		{name: "owner leaves", actor: ownerID, target: ownerID, wantErr: "the organization owner cannot be removed"},
+		{name: "admin removes owner", actor: adminID, target: ownerID, wantErr: "the organization owner cannot be removed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrgRepo := new(MockOrganizationRepository)
+			logger := zap.NewNop()
+			orgService := service.NewOrganizationService(mockOrgRepo, new(MockUserRepository), service.NewAuthorizer(new(MockTTRRepository), mockOrgRepo, new(MockInvitationRepository), new(MockUserRepository)), passthroughTransactor{}, service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+			mockOrgRepo.On("FindByID", orgID).Return(&models.Organization{ID: orgID}, nil)
+			for userID, role := range roles {
+				mockOrgRepo.On("FindMember", orgID, userID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: role}, nil)
+			}
+			mockOrgRepo.On("RemoveMember", orgID, tt.target).Return(nil)
+
+			err := orgService.RemoveMember(context.Background(), orgID, tt.actor, tt.target)
+
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantErr, err.Error())
+				mockOrgRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			mockOrgRepo.AssertCalled(t, "RemoveMember", orgID, tt.target)
+		})
+	}
+}
+
+func TestUpdateTTR_OrganizationAdminCanManage(t *testing.T) {
+	orgID := uuid.New()
+	adminID := uuid.New()
+	memberID := uuid.New()
+	ttrID := uuid.New()
+
+	mockTTRRepo := new(MockTTRRepository)
+	mockOrgRepo := new(MockOrganizationRepository)
+	logger := zap.NewNop()
+	ttrService := service.NewTTRService(mockTTRRepo, new(MockUserRepository), new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, mockOrgRepo, new(MockInvitationRepository), new(MockUserRepository)), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	ttr := &models.TTR{ID: ttrID, CaptainUserID: uuid.New(), MaxPlayers: 4, OrganizationID: &orgID}
+	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+	mockTTRRepo.On("Update", mock.AnythingOfType("*models.TTR")).Return(nil)
+	mockTTRRepo.On("CountPlayers", []uuid.UUID{ttrID}).Return(map[uuid.UUID]models.PlayerCounts{}, nil)
+	mockOrgRepo.On("FindMember", orgID, adminID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: adminID, Role: models.OrganizationRoleAdmin}, nil)
+	mockOrgRepo.On("FindMember", orgID, memberID).Return(&models.OrganizationMember{OrganizationID: orgID, UserID: memberID, Role: models.OrganizationRoleMember}, nil)
+
+	newCourseName := "Cypress Point"
+	_, err := ttrService.UpdateTTR(context.Background(), ttrID, memberID, &newCourseName, nil, nil, nil, nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
+	assert.Error(t, err)
+	assert.Equal(t, "unauthorized: only captain or co-captain can update TTR", err.Error())
+
+	_, err = ttrService.UpdateTTR(context.Background(), ttrID, adminID, &newCourseName, nil, nil, nil, nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
+	assert.NoError(t, err)
+	mockTTRRepo.AssertCalled(t, "Update", mock.AnythingOfType("*models.TTR"))
+}
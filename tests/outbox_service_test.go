@@ -0,0 +1,329 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+// flakySender fails its first failures calls and records the payloads of all
+// of them.
+type flakySender struct {
+	mu       sync.Mutex
+	failures int
+	payloads []string
+}
+
+func (f *flakySender) send(ctx context.Context, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.payloads = append(f.payloads, string(payload))
+	if len(f.payloads) <= f.failures {
+		return fmt.Errorf("attempt %d failed", len(f.payloads))
+	}
+	return nil
+}
+
+func (f *flakySender) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.payloads)
+}
+
+// failingOutboxRepository refuses new messages, as a database rejecting the
+// insert would.
+type failingOutboxRepository struct {
+	repository.OutboxRepository
+}
+
+func (r failingOutboxRepository) Create(ctx context.Context, message *models.OutboxMessage) error {
+	return errors.New("outbox is down")
+}
+
+func outboxTestConfig() config.OutboxConfig {
+	return config.OutboxConfig{PollInterval: time.Millisecond, BatchSize: 10, Lease: time.Minute, MaxAttempts: 3, Retention: time.Hour}
+}
+
+func TestOutboxService_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	messages := func(t *testing.T, repo repository.OutboxRepository, status string) []*models.OutboxMessage {
+		found, err := repo.FindByStatus(ctx, status, 100)
+		require.NoError(t, err)
+		return found
+	}
+
+	t.Run("retries until the sender succeeds", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		outbox := service.NewOutboxService(repo, outboxTestConfig(), logger)
+		sender := &flakySender{failures: 2}
+		outbox.Handle("test", sender.send)
+		require.NoError(t, outbox.Enqueue(ctx, "test", map[string]string{"hello": "world"}))
+
+		for i := 0; i < 5; i++ {
+			_, err := outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, sender.calls(), "delivered once it went through, and not again")
+		for _, payload := range sender.payloads {
+			assert.JSONEq(t, `{"hello":"world"}`, payload)
+		}
+		done := messages(t, repo, models.OutboxStatusDone)
+		require.Len(t, done, 1)
+		assert.Equal(t, 2, done[0].Attempts)
+		require.NotNil(t, done[0].LastError)
+		assert.Equal(t, "attempt 2 failed", *done[0].LastError)
+		assert.NotNil(t, done[0].ProcessedAt)
+	})
+
+	t.Run("dead-letters after the last attempt", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		outbox := service.NewOutboxService(repo, outboxTestConfig(), logger)
+		sender := &flakySender{failures: 100}
+		outbox.Handle("test", sender.send)
+		require.NoError(t, outbox.Enqueue(ctx, "test", "payload"))
+
+		for i := 0; i < 5; i++ {
+			_, err := outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 3, sender.calls())
+		assert.Empty(t, messages(t, repo, models.OutboxStatusPending))
+		dead := messages(t, repo, models.OutboxStatusDead)
+		require.Len(t, dead, 1)
+		assert.Equal(t, 3, dead[0].Attempts)
+		assert.Equal(t, "attempt 3 failed", *dead[0].LastError)
+	})
+
+	t.Run("messages without a sender end up dead", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		outbox := service.NewOutboxService(repo, outboxTestConfig(), logger)
+		require.NoError(t, outbox.Enqueue(ctx, "unknown", "payload"))
+
+		for i := 0; i < 3; i++ {
+			_, err := outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+
+		dead := messages(t, repo, models.OutboxStatusDead)
+		require.Len(t, dead, 1)
+		assert.Equal(t, "no sender for unknown messages", *dead[0].LastError)
+	})
+
+	t.Run("backs off between attempts", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		cfg := outboxTestConfig()
+		cfg.InitialBackoff = time.Hour
+		outbox := service.NewOutboxService(repo, cfg, logger)
+		sender := &flakySender{failures: 1}
+		outbox.Handle("test", sender.send)
+		require.NoError(t, outbox.Enqueue(ctx, "test", "payload"))
+
+		before := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := outbox.Dispatch(ctx)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 1, sender.calls(), "the retry isn't due for an hour")
+		pending := messages(t, repo, models.OutboxStatusPending)
+		require.Len(t, pending, 1)
+		assert.WithinDuration(t, before.Add(time.Hour), pending[0].NextAttemptAt, time.Minute)
+	})
+
+	t.Run("a message claimed by a dispatcher that died is delivered after the lease", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		cfg := outboxTestConfig()
+		cfg.Lease = 50 * time.Millisecond
+		outbox := service.NewOutboxService(repo, cfg, logger)
+		sender := &flakySender{}
+		outbox.Handle("test", sender.send)
+		require.NoError(t, outbox.Enqueue(ctx, "test", "payload"))
+
+		claimed, err := repo.Claim(ctx, time.Now(), cfg.Lease, 10)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		dispatched, err := outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, dispatched, "the other dispatcher still holds it")
+
+		time.Sleep(cfg.Lease)
+		dispatched, err = outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		assert.Equal(t, 1, sender.calls())
+		assert.Len(t, messages(t, repo, models.OutboxStatusDone), 1)
+	})
+
+	t.Run("messages are only written if the transaction commits", func(t *testing.T) {
+		store := memory.NewStore()
+		repo := memory.NewOutboxRepository(store)
+		transactor := memory.NewTransactor(store)
+		outbox := service.NewOutboxService(repo, outboxTestConfig(), logger)
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.NoError(t, outbox.Enqueue(ctx, "test", "rolled back"))
+			return errors.New("change failed")
+		})
+		require.Error(t, err)
+		assert.Empty(t, messages(t, repo, models.OutboxStatusPending))
+
+		require.NoError(t, transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			return outbox.Enqueue(ctx, "test", "committed")
+		}))
+		assert.Len(t, messages(t, repo, models.OutboxStatusPending), 1)
+	})
+
+	t.Run("run delivers until stopped", func(t *testing.T) {
+		repo := memory.NewOutboxRepository(memory.NewStore())
+		outbox := service.NewOutboxService(repo, outboxTestConfig(), logger)
+		sender := &flakySender{failures: 1}
+		outbox.Handle("test", sender.send)
+		for i := 0; i < 25; i++ {
+			require.NoError(t, outbox.Enqueue(ctx, "test", i))
+		}
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- outbox.Run(runCtx) }()
+		require.Eventually(t, func() bool {
+			return len(messages(t, repo, models.OutboxStatusDone)) == 25
+		}, 5*time.Second, 5*time.Millisecond)
+		cancel()
+		require.NoError(t, <-done)
+		assert.Equal(t, 26, sender.calls())
+	})
+}
+
+func TestOutboxService_Notifications(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+
+	type fixture struct {
+		outbox            *service.OutboxService
+		invitationRepo    repository.InvitationRepository
+		notificationRepo  repository.NotificationRepository
+		invitationService *service.InvitationService
+		ttr               *service.TTRDetail
+		captainID         uuid.UUID
+		inviteeID         uuid.UUID
+	}
+	setup := func(t *testing.T, outboxRepo func(store *memory.Store) repository.OutboxRepository) *fixture {
+		store := memory.NewStore()
+		f := &fixture{
+			invitationRepo:   memory.NewInvitationRepository(store),
+			notificationRepo: memory.NewNotificationRepository(store),
+		}
+		ttrRepo := memory.NewTTRRepository(store)
+		userRepo := memory.NewUserRepository(store)
+		transactor := memory.NewTransactor(store)
+		f.outbox = service.NewOutboxService(outboxRepo(store), outboxTestConfig(), logger)
+		notificationService := service.NewNotificationService(f.notificationRepo, userRepo, f.outbox, 0, logger)
+		authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), f.invitationRepo, userRepo)
+		ttrService := service.NewTTRService(ttrRepo, userRepo, f.invitationRepo, transactor, authorizer, notificationService, nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+		f.invitationService = service.NewInvitationService(f.invitationRepo, ttrRepo, userRepo, memory.NewInvitationTemplateRepository(store), ttrService, transactor, authorizer, notificationService, nil, nil, logger)
+
+		newUser := func(name string) uuid.UUID {
+			user := &models.User{Email: name + "@example.com", FirstName: name, LastName: "Golfer"}
+			require.NoError(t, userRepo.Create(ctx, user))
+			return user.ID
+		}
+		f.captainID = newUser("captain")
+		f.inviteeID = newUser("invitee")
+		var err error
+		f.ttr, err = ttrService.CreateTTR(ctx, f.captainID, "Pebble Beach", nil, time.Now().UTC().AddDate(0, 0, 7).Truncate(24*time.Hour), time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC), 4, 2, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, false)
+		require.NoError(t, err)
+		return f
+	}
+
+	t.Run("delivered by the dispatcher", func(t *testing.T) {
+		f := setup(t, func(store *memory.Store) repository.OutboxRepository { return memory.NewOutboxRepository(store) })
+
+		invitation, err := f.invitationService.CreateInvitation(ctx, f.ttr.ID, f.captainID, f.inviteeID, nil, nil)
+		require.NoError(t, err)
+		notifications, err := f.notificationRepo.FindByUserID(ctx, f.inviteeID, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, notifications, "nothing is created before the dispatcher runs")
+
+		dispatched, err := f.outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, dispatched)
+		notifications, err = f.notificationRepo.FindByUserID(ctx, f.inviteeID, 10, 0)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, models.NotificationTypeInvitation, notifications[0].Type)
+		assert.Equal(t, invitation.ID, *notifications[0].TargetID)
+		assert.Contains(t, notifications[0].Message, "Pebble Beach")
+	})
+
+	t.Run("the invitation isn't kept when its notification can't be written", func(t *testing.T) {
+		f := setup(t, func(store *memory.Store) repository.OutboxRepository {
+			return failingOutboxRepository{memory.NewOutboxRepository(store)}
+		})
+
+		_, err := f.invitationService.CreateInvitation(ctx, f.ttr.ID, f.captainID, f.inviteeID, nil, nil)
+		require.Error(t, err)
+		invitation, err := f.invitationRepo.FindByTTRAndInvitee(ctx, f.ttr.ID, f.inviteeID)
+		require.NoError(t, err)
+		assert.Nil(t, invitation)
+	})
+}
+
+func TestOutboxService_Webhooks(t *testing.T) {
+	ctx := context.Background()
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := memory.NewStore()
+	outboxRepo := memory.NewOutboxRepository(store)
+	outbox := service.NewOutboxService(outboxRepo, outboxTestConfig(), zap.NewNop())
+	f := &webhookFixture{repo: memory.NewWebhookRepository(store), orgRepo: memory.NewOrganizationRepository(store)}
+	authorizer := service.NewAuthorizer(memory.NewTTRRepository(store), f.orgRepo, memory.NewInvitationRepository(store), memory.NewUserRepository(store))
+	f.service = service.NewWebhookService(f.repo, authorizer, outbox, config.WebhooksConfig{MaxAttempts: 1, Timeout: time.Second}, zap.NewNop())
+
+	captainID := uuid.New()
+	webhook, err := f.service.CreateWebhook(ctx, captainID, nil, server.URL, []string{models.WebhookEventTTRCreated})
+	require.NoError(t, err)
+	require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRCreated, webhookTestTTR(captainID)))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = f.service.Run(runCtx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	assert.Zero(t, receiver.received(), "published events wait in the outbox")
+
+	dispatched, err := outbox.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dispatched)
+	require.Eventually(t, func() bool { return len(f.deliveries(t, webhook.ID)) == 1 }, 5*time.Second, 5*time.Millisecond)
+
+	receiver.mu.Lock()
+	defer receiver.mu.Unlock()
+	assert.Equal(t, service.WebhookSignature(webhook.Secret, receiver.bodies[0]), receiver.signatures[0])
+	assert.Equal(t, models.WebhookEventTTRCreated, receiver.events[0])
+}
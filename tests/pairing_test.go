@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/pairing"
+)
+
+func pairingPlayers(handicaps ...float64) []pairing.Player {
+	players := make([]pairing.Player, len(handicaps))
+	for i := range handicaps {
+		players[i] = pairing.Player{ID: uuid.New(), Handicap: &handicaps[i]}
+	}
+	return players
+}
+
+func groupSizes(groups []pairing.Group) []int {
+	sizes := make([]int, len(groups))
+	for i, group := range groups {
+		sizes[i] = len(group.Players)
+	}
+	return sizes
+}
+
+func groupOf(groups []pairing.Group, id uuid.UUID) int {
+	for i, group := range groups {
+		for _, playerID := range group.Players {
+			if playerID == id {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func totalSpread(groups []pairing.Group) float64 {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, group := range groups {
+		low = math.Min(low, group.TotalHandicap)
+		high = math.Max(high, group.TotalHandicap)
+	}
+	return high - low
+}
+
+func assertEveryPlayerOnce(t *testing.T, players []pairing.Player, groups []pairing.Group) {
+	t.Helper()
+	seen := make(map[uuid.UUID]int)
+	for _, group := range groups {
+		for _, id := range group.Players {
+			seen[id]++
+		}
+	}
+	require.Len(t, seen, len(players))
+	for _, p := range players {
+		assert.Equal(t, 1, seen[p.ID])
+	}
+}
+
+func TestPairing_Balance(t *testing.T) {
+	t.Run("four players make one foursome", func(t *testing.T) {
+		players := pairingPlayers(3, 12.5, 20, 7)
+		groups := pairing.Balance(players, 4, 18)
+
+		require.Len(t, groups, 1)
+		assertEveryPlayerOnce(t, players, groups)
+		assert.Equal(t, 42.5, groups[0].TotalHandicap)
+	})
+
+	t.Run("eight players make two even foursomes", func(t *testing.T) {
+		players := pairingPlayers(0, 2, 4, 6, 8, 10, 12, 14)
+		groups := pairing.Balance(players, 4, 18)
+
+		assert.Equal(t, []int{4, 4}, groupSizes(groups))
+		assertEveryPlayerOnce(t, players, groups)
+		assert.Equal(t, 28.0, groups[0].TotalHandicap)
+		assert.Equal(t, 28.0, groups[1].TotalHandicap)
+	})
+
+	t.Run("eight players split the two best players", func(t *testing.T) {
+		players := pairingPlayers(1, 2, 20, 21, 22, 23, 24, 25)
+		groups := pairing.Balance(players, 4, 18)
+
+		require.Len(t, groups, 2)
+		assertEveryPlayerOnce(t, players, groups)
+		assert.NotEqual(t, groupOf(groups, players[0].ID), groupOf(groups, players[1].ID), "the best two are in different groups")
+		assert.LessOrEqual(t, totalSpread(groups), 2.0)
+	})
+
+	t.Run("nine players make three threesomes", func(t *testing.T) {
+		players := pairingPlayers(1, 2, 3, 4, 5, 6, 7, 8, 9)
+		groups := pairing.Balance(players, 4, 18)
+
+		assert.Equal(t, []int{3, 3, 3}, groupSizes(groups), "sizes differ by at most one")
+		assertEveryPlayerOnce(t, players, groups)
+		assert.Equal(t, 45.0, groups[0].TotalHandicap+groups[1].TotalHandicap+groups[2].TotalHandicap)
+		assert.LessOrEqual(t, totalSpread(groups), 2.0)
+	})
+
+	t.Run("missing handicaps count as the default", func(t *testing.T) {
+		players := pairingPlayers(2, 4, 30, 32)
+		players = append(players,
+			pairing.Player{ID: uuid.New()},
+			pairing.Player{ID: uuid.New()},
+			pairing.Player{ID: uuid.New()},
+			pairing.Player{ID: uuid.New()},
+		)
+		groups := pairing.Balance(players, 4, 18)
+
+		assert.Equal(t, []int{4, 4}, groupSizes(groups))
+		assertEveryPlayerOnce(t, players, groups)
+		assert.Equal(t, 70.0, groups[0].TotalHandicap)
+		assert.Equal(t, 70.0, groups[1].TotalHandicap)
+
+		groups = pairing.Balance(players, 4, 0)
+		assert.Equal(t, 34.0, groups[0].TotalHandicap)
+		assert.Equal(t, 34.0, groups[1].TotalHandicap)
+	})
+
+	t.Run("input order does not matter", func(t *testing.T) {
+		players := pairingPlayers(5.4, 11.2, 0.8, 17.9, 24, 9.1, 13.3, 2.2, 30)
+		reversed := make([]pairing.Player, len(players))
+		for i, p := range players {
+			reversed[len(players)-1-i] = p
+		}
+
+		assert.Equal(t, pairing.Balance(players, 4, 18), pairing.Balance(reversed, 4, 18))
+	})
+
+	t.Run("no players", func(t *testing.T) {
+		assert.Empty(t, pairing.Balance(nil, 4, 18))
+	})
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/password"
+)
+
+func TestPasswordPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   password.Policy
+		password string
+		email    string
+		want     []password.Rule
+	}{
+		{
+			name:     "zero policy accepts anything",
+			password: "a",
+			email:    "a@example.com",
+		},
+		{
+			name:     "too short",
+			policy:   password.Policy{MinLength: 10},
+			password: "Fairway7!",
+			want:     []password.Rule{password.RuleMinLength},
+		},
+		{
+			name:     "length counts characters, not bytes",
+			policy:   password.Policy{MinLength: 8},
+			password: "ñandúñandú",
+		},
+		{
+			name:     "missing lowercase",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleLowercase}},
+			password: "FAIRWAY7",
+			want:     []password.Rule{password.RuleLowercase},
+		},
+		{
+			name:     "missing uppercase",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleUppercase}},
+			password: "fairway7",
+			want:     []password.Rule{password.RuleUppercase},
+		},
+		{
+			name:     "missing digit",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleDigit}},
+			password: "Fairway!",
+			want:     []password.Rule{password.RuleDigit},
+		},
+		{
+			name:     "missing symbol",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleSymbol}},
+			password: "Fairway7",
+			want:     []password.Rule{password.RuleSymbol},
+		},
+		{
+			name:     "a space is a symbol",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleSymbol}},
+			password: "long fairway",
+		},
+		{
+			name:     "every class present",
+			policy:   password.Policy{RequireClasses: []password.Rule{password.RuleLowercase, password.RuleUppercase, password.RuleDigit, password.RuleSymbol}},
+			password: "Fairway7!",
+		},
+		{
+			name:     "common password ignoring case",
+			policy:   password.Policy{BanCommon: true},
+			password: "PassWord123",
+			want:     []password.Rule{password.RuleCommon},
+		},
+		{
+			name:     "common passwords allowed when not banned",
+			policy:   password.Policy{},
+			password: "password123",
+		},
+		{
+			name:     "contains the email local part",
+			policy:   password.Policy{RejectEmail: true},
+			password: "xXJohn.Doe99",
+			email:    "john.doe@example.com",
+			want:     []password.Rule{password.RuleEmail},
+		},
+		{
+			name:     "the email domain is fine",
+			policy:   password.Policy{RejectEmail: true},
+			password: "example-links",
+			email:    "john.doe@example.com",
+		},
+		{
+			name:     "short local parts are ignored",
+			policy:   password.Policy{RejectEmail: true},
+			password: "jo-on-the-green",
+			email:    "jo@example.com",
+		},
+		{
+			name:     "every broken rule in order",
+			policy:   password.Policy{MinLength: 12, RequireClasses: []password.Rule{password.RuleUppercase, password.RuleSymbol}, BanCommon: true, RejectEmail: true},
+			password: "birdie",
+			email:    "birdie@example.com",
+			want:     []password.Rule{password.RuleMinLength, password.RuleUppercase, password.RuleSymbol, password.RuleCommon, password.RuleEmail},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Check(tt.password, tt.email))
+		})
+	}
+}
+
+func TestPasswordPolicy_CommonList(t *testing.T) {
+	file, err := os.Open("../pkg/password/common.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	policy := password.Policy{BanCommon: true}
+	entries := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entries++
+		assert.Equal(t, []password.Rule{password.RuleCommon}, policy.Check(line, ""), "%q is on the list", line)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Greater(t, entries, 100)
+
+	assert.Nil(t, policy.Check("quiet-fairway-lantern", ""), "passwords off the list are allowed")
+}
+
+func TestPasswordIsClass(t *testing.T) {
+	for _, rule := range []password.Rule{password.RuleLowercase, password.RuleUppercase, password.RuleDigit, password.RuleSymbol} {
+		assert.True(t, password.IsClass(rule), rule)
+	}
+	for _, rule := range []password.Rule{password.RuleMinLength, password.RuleCommon, password.RuleEmail, "emoji"} {
+		assert.False(t, password.IsClass(rule), rule)
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/golf_messenger/pkg/sanitize"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain text", "Meet at the range at 8", "Meet at the range at 8"},
+		{"script tag", `Hi<script>alert("x")</script> all`, "Hi all"},
+		{"event handler", `<img src=x onerror="alert(1)">Tee at 9`, "Tee at 9"},
+		{"javascript link", `<a href="javascript:alert(1)">click</a>`, "click"},
+		{"escaped script stays escaped", "&lt;script&gt;alert(1)&lt;/script&gt;", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"unclosed tag", "bring <b>balls", "bring balls"},
+		{"angle brackets in text", "handicap < 10 & > 5", "handicap &lt; 10 &amp; &gt; 5"},
+		{"quotes stay readable", `O'Brien said "fore"`, `O'Brien said "fore"`},
+		{"control characters", "tee\x00 time\x07\x1b[31m", "tee time[31m"},
+		{"invalid utf-8", "caf\xc3\x28e", "caf(e"},
+		{"bidi override", "pay \u202eusd 001\u202c now", "pay usd 001 now"},
+		{"runs of spaces", "  too   many \t\t spaces  here  ", "too many spaces here"},
+		{"blank lines", "line one\r\n\r\n\r\n\r\nline two\n   \nline three", "line one\n\nline two\n\nline three"},
+		{"emoji sequences", "family 👨\u200d👩\u200d👧 ok 👍🏽", "family 👨\u200d👩\u200d👧 ok 👍🏽"},
+		{"combining marks", "Jose\u0301 and 日本語", "Jose\u0301 and 日本語"},
+		{"only markup", "<script>alert(1)</script>", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitize.Text(tt.in)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, got, sanitize.Text(got), "sanitizing again changes nothing")
+		})
+	}
+}
+
+func TestSanitizeTextPtr(t *testing.T) {
+	assert.Nil(t, sanitize.TextPtr(nil))
+
+	notes := " <i>bring</i> cash "
+	assert.Equal(t, "bring cash", *sanitize.TextPtr(&notes))
+}
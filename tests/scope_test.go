@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+)
+
+func TestScope_ParseList(t *testing.T) {
+	scopes, err := scope.ParseList([]string{"write:ttrs", "read:profile", "write:ttrs"})
+	require.NoError(t, err)
+	assert.Equal(t, []scope.Scope{scope.ReadProfile, scope.WriteTTRs}, scopes, "taxonomy order, no duplicates")
+
+	_, err = scope.ParseList([]string{"read:ttrs", "read:everything"})
+	assert.Error(t, err)
+
+	scopes, err = scope.ParseList(nil)
+	require.NoError(t, err)
+	assert.Empty(t, scopes)
+
+	all := scope.All()
+	assert.True(t, scope.Has(all, scope.Admin))
+	assert.False(t, scope.Has(scopes, scope.ReadTTRs))
+}
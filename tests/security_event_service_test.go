@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/password"
+	"go.uber.org/zap"
+)
+
+type securityEventFixture struct {
+	notificationRepo repository.NotificationRepository
+	securityEvents   *service.SecurityEventService
+	authService      *service.AuthService
+	userService      *service.UserService
+}
+
+func newSecurityEventFixture() *securityEventFixture {
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepository(store)
+	f := &securityEventFixture{notificationRepo: memory.NewNotificationRepository(store)}
+	notificationService := service.NewNotificationService(f.notificationRepo, nil, nil, 0, zap.NewNop())
+	f.securityEvents = service.NewSecurityEventService(memory.NewSecurityEventRepository(store), notificationService)
+	f.authService = service.NewAuthService(userRepo, memory.NewRefreshTokenRepository(store), f.securityEvents, password.Policy{}, "test-secret", 15*time.Minute, 7*24*time.Hour)
+	f.userService = service.NewUserService(userRepo, nil, f.securityEvents, password.Policy{}, config.AvatarConfig{})
+	return f
+}
+
+// eventTypes returns the user's event types, oldest first.
+func (f *securityEventFixture) eventTypes(t *testing.T, userID uuid.UUID) []string {
+	events, err := f.securityEvents.List(context.Background(), userID, 50, 0)
+	require.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for i := len(events) - 1; i >= 0; i-- {
+		types = append(types, events[i].Type)
+	}
+	return types
+}
+
+func (f *securityEventFixture) newLoginNotifications(t *testing.T, userID uuid.UUID) int {
+	notifications, err := f.notificationRepo.FindByUserID(context.Background(), userID, 50, 0)
+	require.NoError(t, err)
+	count := 0
+	for _, notification := range notifications {
+		if notification.Type == models.NotificationTypeNewLogin {
+			count++
+		}
+	}
+	return count
+}
+
+func TestSecurityEventService_RecordsAccountActivity(t *testing.T) {
+	f := newSecurityEventFixture()
+	ctx := service.WithClient(context.Background(), "203.0.113.7", "GolfApp/1.0")
+
+	user, _, err := f.authService.Register(ctx, "ann@example.com", "password123", "Ann", "Archer")
+	require.NoError(t, err)
+
+	_, _, err = f.authService.Login(ctx, "ann@example.com", "wrong-password")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+	_, _, err = f.authService.Login(ctx, "nobody@example.com", "password123")
+	assert.ErrorIs(t, err, service.ErrInvalidCredentials)
+
+	_, tokens, err := f.authService.Login(ctx, "ann@example.com", "password123")
+	require.NoError(t, err)
+	require.NoError(t, f.userService.ChangePassword(ctx, user.ID, "password123", "new-password456"))
+	require.NoError(t, f.authService.Logout(ctx, tokens.RefreshToken))
+
+	assert.Equal(t, []string{
+		models.SecurityEventLogin,
+		models.SecurityEventLoginFailed,
+		models.SecurityEventLogin,
+		models.SecurityEventPasswordChanged,
+		models.SecurityEventTokensRevoked,
+	}, f.eventTypes(t, user.ID))
+
+	events, err := f.securityEvents.List(ctx, user.ID, 1, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "203.0.113.7", events[0].IPAddress)
+	assert.Equal(t, "GolfApp/1.0", events[0].UserAgent)
+}
+
+func TestSecurityEventService_NewDeviceAlert(t *testing.T) {
+	f := newSecurityEventFixture()
+	phone := service.WithClient(context.Background(), "203.0.113.7", "GolfApp/1.0 (iPhone)")
+
+	user, _, err := f.authService.Register(phone, "ann@example.com", "password123", "Ann", "Archer")
+	require.NoError(t, err)
+	assert.Zero(t, f.newLoginNotifications(t, user.ID), "the device signed up on isn't new")
+
+	_, _, err = f.authService.Login(phone, "ann@example.com", "password123")
+	require.NoError(t, err)
+	assert.Zero(t, f.newLoginNotifications(t, user.ID), "a known device isn't new")
+
+	laptop := service.WithClient(context.Background(), "203.0.113.7", "Mozilla/5.0 (Macintosh)")
+	_, _, err = f.authService.Login(laptop, "ann@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.newLoginNotifications(t, user.ID), "a new user agent is a new device")
+
+	elsewhere := service.WithClient(context.Background(), "198.51.100.20", "GolfApp/1.0 (iPhone)")
+	_, _, err = f.authService.Login(elsewhere, "ann@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.newLoginNotifications(t, user.ID), "a new IP address is a new device")
+
+	_, _, err = f.authService.Login(laptop, "ann@example.com", "password123")
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.newLoginNotifications(t, user.ID))
+}
+
+func TestSecurityEventService_NilRecordsNothing(t *testing.T) {
+	var securityEvents *service.SecurityEventService
+	assert.NoError(t, securityEvents.Record(context.Background(), uuid.New(), models.SecurityEventLogin))
+	assert.NoError(t, securityEvents.RecordLogin(context.Background(), uuid.New()))
+}
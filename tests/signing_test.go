@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/middleware"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/scope"
+	"github.com/yourusername/golf_messenger/pkg/signing"
+	"go.uber.org/zap"
+)
+
+const testSigningSecret = "scheduler-secret-at-least-32-bytes!"
+
+func signedRequest(t *testing.T, method, target string, body []byte, now time.Time) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	require.NoError(t, signing.SignRequest(req, "scheduler", testSigningSecret, body, now))
+	return req
+}
+
+func verifySigned(verifier *signing.Verifier, req *http.Request, body []byte, now time.Time) (*signing.Client, error) {
+	return verifier.Verify(context.Background(), req.Method, req.URL.RequestURI(), req.Header, body, now)
+}
+
+func TestSigning_Verify(t *testing.T) {
+	now := time.Now()
+	body := []byte(`{"course_name":"Bethpage Black"}`)
+	newVerifier := func() *signing.Verifier {
+		return signing.NewVerifier([]signing.Client{{KeyID: "scheduler", Secret: testSigningSecret}}, signing.NewMemoryNonceStore())
+	}
+
+	t.Run("valid request", func(t *testing.T) {
+		req := signedRequest(t, "POST", "/api/v1/ttrs?notify=true", body, now)
+
+		client, err := verifySigned(newVerifier(), req, body, now.Add(time.Minute))
+
+		require.NoError(t, err)
+		assert.Equal(t, "scheduler", client.KeyID)
+		assert.Equal(t, scope.All(), client.GrantedScopes(), "clients without scopes get every scope")
+	})
+
+	t.Run("skewed timestamps", func(t *testing.T) {
+		verifier := newVerifier()
+
+		for _, skew := range []time.Duration{signing.MaxRequestAge + time.Second, -signing.MaxRequestAge - time.Second} {
+			req := signedRequest(t, "POST", "/api/v1/ttrs", body, now.Add(-skew))
+			_, err := verifySigned(verifier, req, body, now)
+			assert.ErrorIs(t, err, signing.ErrStaleRequest, "skew %s", skew)
+		}
+
+		req := signedRequest(t, "POST", "/api/v1/ttrs", body, now.Add(-signing.MaxRequestAge+time.Second))
+		_, err := verifySigned(verifier, req, body, now)
+		assert.NoError(t, err, "requests inside the window are accepted")
+	})
+
+	t.Run("tampered requests", func(t *testing.T) {
+		verifier := newVerifier()
+
+		req := signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		_, err := verifySigned(verifier, req, []byte(`{"course_name":"Pebble Beach"}`), now)
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature, "tampered body")
+
+		req = signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		req.URL.Path = "/api/v1/users/me"
+		_, err = verifySigned(verifier, req, body, now)
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature, "tampered path")
+
+		req = signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		req.Method = "DELETE"
+		_, err = verifySigned(verifier, req, body, now)
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature, "tampered method")
+
+		req = signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		req.Header.Set(signing.TimestampHeader, strconv.FormatInt(now.Add(time.Minute).Unix(), 10))
+		_, err = verifySigned(verifier, req, body, now)
+		assert.ErrorIs(t, err, signing.ErrInvalidSignature, "tampered timestamp")
+
+		req = signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		req.Header.Set(signing.KeyIDHeader, "reports")
+		_, err = verifySigned(verifier, req, body, now)
+		assert.ErrorIs(t, err, signing.ErrUnknownKey)
+
+		req = signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+		req.Header.Del(signing.NonceHeader)
+		_, err = verifySigned(verifier, req, body, now)
+		assert.ErrorIs(t, err, signing.ErrMissingSignature)
+	})
+
+	t.Run("replayed nonces", func(t *testing.T) {
+		verifier := newVerifier()
+		req := signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+
+		_, err := verifySigned(verifier, req, body, now)
+		require.NoError(t, err)
+		_, err = verifySigned(verifier, req, body, now.Add(time.Second))
+		assert.ErrorIs(t, err, signing.ErrReplayedRequest)
+	})
+
+	t.Run("forged requests do not spend nonces", func(t *testing.T) {
+		verifier := newVerifier()
+		req := signedRequest(t, "POST", "/api/v1/ttrs", body, now)
+
+		_, err := verifySigned(verifier, req, append(body, ' '), now)
+		require.ErrorIs(t, err, signing.ErrInvalidSignature)
+		_, err = verifySigned(verifier, req, body, now)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSigning_MemoryNonceStoreExpires(t *testing.T) {
+	store := signing.NewMemoryNonceStore()
+	ctx := context.Background()
+
+	fresh, err := store.Claim(ctx, "scheduler:abc", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = store.Claim(ctx, "scheduler:abc", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, fresh)
+
+	time.Sleep(60 * time.Millisecond)
+	fresh, err = store.Claim(ctx, "scheduler:abc", 50*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, fresh, "a nonce can be reused once it has expired")
+}
+
+func TestSignedAuth_InjectsServicePrincipal(t *testing.T) {
+	client := signing.Client{KeyID: "scheduler", Secret: testSigningSecret, Scopes: []scope.Scope{scope.ReadTTRs}}
+	verifier := signing.NewVerifier([]signing.Client{client}, signing.NewMemoryNonceStore())
+
+	var gotBody string
+	var gotUserID uuid.UUID
+	var gotRole, gotClient string
+	handler := middleware.SignedAuth(verifier, zap.NewNop())(middleware.RequireScope(scope.ReadTTRs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		gotUserID, _ = r.Context().Value(middleware.UserIDKey).(uuid.UUID)
+		gotRole, _ = r.Context().Value(middleware.RoleKey).(string)
+		gotClient, _ = middleware.ServiceClient(r)
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	body := []byte(`{"limit":10}`)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(t, "GET", "/api/v1/ttrs", body, time.Now()))
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, string(body), gotBody, "the body is handed on after verifying")
+	assert.Equal(t, client.PrincipalID(), gotUserID)
+	assert.Equal(t, models.UserRoleService, gotRole)
+	assert.Equal(t, "scheduler", gotClient)
+
+	w = httptest.NewRecorder()
+	writeTTRs := middleware.SignedAuth(verifier, zap.NewNop())(middleware.RequireScope(scope.WriteTTRs)(http.NotFoundHandler()))
+	writeTTRs.ServeHTTP(w, signedRequest(t, "POST", "/api/v1/ttrs", body, time.Now()))
+	assert.Equal(t, http.StatusForbidden, w.Code, "scoped clients only get their scopes")
+
+	w = httptest.NewRecorder()
+	req := signedRequest(t, "GET", "/api/v1/ttrs", body, time.Now())
+	req.Body = io.NopCloser(strings.NewReader(`{"limit":1000}`))
+	handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
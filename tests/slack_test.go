@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/pkg/slack"
+)
+
+func TestSlack_Parse(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, 5, 29, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		text    string
+		course  string
+		date    string
+		time    string
+		players int
+	}{
+		{"iso date and 24h time", "Bethpage Black 2024-06-01 07:30 4", "Bethpage Black", "2024-06-01", "07:30", 4},
+		{"players default to 4", "Bethpage Black 2024-06-01 07:30", "Bethpage Black", "2024-06-01", "07:30", 4},
+		{"month/day with am", "Bethpage Black 6/1 7:30am", "Bethpage Black", "2024-06-01", "07:30", 4},
+		{"month/day/year", "Pebble Beach 6/1/2024 13:10 2", "Pebble Beach", "2024-06-01", "13:10", 2},
+		{"two-digit year", "Pebble Beach 6/1/24 7:05 2players", "Pebble Beach", "2024-06-01", "07:05", 2},
+		{"month/day already past rolls to next year", "Pebble Beach 5/1 7am", "Pebble Beach", "2025-05-01", "07:00", 4},
+		{"today", "Pebble Beach today 3pm", "Pebble Beach", "2024-05-29", "15:00", 4},
+		{"tomorrow with at and separate pm", `"Bethpage Black" tomorrow at 7 pm x3`, "Bethpage Black", "2024-05-30", "19:00", 3},
+		{"weekday with curly quotes and on", "“Pebble Beach” on sat 13:10", "Pebble Beach", "2024-06-01", "13:10", 4},
+		{"today's weekday is today", "Pebble Beach Wednesday 7am 1", "Pebble Beach", "2024-05-29", "07:00", 1},
+		{"numbers in the course name", "Hole 19 Links 2024-06-01 07:30 4p", "Hole 19 Links", "2024-06-01", "07:30", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := slack.Parse(tt.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, slack.CommandCreate, cmd.Kind)
+			assert.Equal(t, tt.course, cmd.CourseName)
+			assert.Equal(t, tt.date, cmd.TeeDate.Format("2006-01-02"))
+			assert.Equal(t, tt.time, cmd.TeeTime.Format("15:04"))
+			assert.Equal(t, tt.players, cmd.MaxPlayers)
+		})
+	}
+
+	t.Run("subcommands", func(t *testing.T) {
+		for text, kind := range map[string]string{"": slack.CommandHelp, "  help ": slack.CommandHelp, "LINK": slack.CommandLink} {
+			cmd, err := slack.Parse(text, now)
+			require.NoError(t, err)
+			assert.Equal(t, kind, cmd.Kind, text)
+		}
+	})
+
+	t.Run("mistakes", func(t *testing.T) {
+		for text, want := range map[string]error{
+			"Pebble Beach":                    slack.ErrMissingTime,
+			"Pebble Beach tomorrow 4":         slack.ErrMissingTime,
+			"Pebble Beach 7:30":               slack.ErrMissingDate,
+			"Pebble Beach 2/30 7:30":          slack.ErrMissingDate,
+			"2024-06-01 07:30":                slack.ErrMissingCourse,
+			"Pebble Beach 2024-06-01 07:30 9": slack.ErrInvalidPlayers,
+			"Pebble Beach 2024-06-01 07:30 0": slack.ErrInvalidPlayers,
+			`"Pebble Beach 2024-06-01 07:30`:  slack.ErrUnterminatedQuote,
+			`"Pebble" Beach 2024-06-01 07:30`: slack.ErrMissingDate,
+		} {
+			_, err := slack.Parse(text, now)
+			assert.ErrorIs(t, err, want, text)
+		}
+	})
+}
+
+func TestSlack_Verify(t *testing.T) {
+	// The example from Slack's "Verifying requests from Slack" guide.
+	secret := "8f742231b10e8888abcd99yyyzzz85a5"
+	timestamp := "1531420618"
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c")
+	signature := "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+	assert.Equal(t, signature, slack.Sign(secret, timestamp, body))
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	signedAt := time.Unix(seconds, 0)
+	header := http.Header{}
+	header.Set(slack.TimestampHeader, timestamp)
+	header.Set(slack.SignatureHeader, signature)
+
+	assert.NoError(t, slack.Verify(secret, header, body, signedAt.Add(time.Minute)))
+	assert.ErrorIs(t, slack.Verify("another-secret", header, body, signedAt), slack.ErrInvalidSignature)
+	assert.ErrorIs(t, slack.Verify(secret, header, append(body, '&'), signedAt), slack.ErrInvalidSignature)
+	assert.ErrorIs(t, slack.Verify(secret, header, body, signedAt.Add(slack.MaxRequestAge+time.Second)), slack.ErrStaleRequest, "replays are refused")
+	assert.ErrorIs(t, slack.Verify(secret, http.Header{}, body, signedAt), slack.ErrMissingSignature)
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+)
+
+func TestStatus_ScanAndValue(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for _, want := range []models.TTRStatus{models.TTRStatusOpen, models.TTRStatusConfirmed, models.TTRStatusCancelled, models.TTRStatusCompleted} {
+			value, err := want.Value()
+			require.NoError(t, err)
+			assert.Equal(t, string(want), value)
+
+			var got models.TTRStatus
+			require.NoError(t, got.Scan(value))
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("scans bytes", func(t *testing.T) {
+		var player models.TTRPlayerStatus
+		require.NoError(t, player.Scan([]byte("MAYBE")))
+		assert.Equal(t, models.TTRPlayerStatusMaybe, player)
+
+		var invitation models.InvitationStatus
+		require.NoError(t, invitation.Scan([]byte("EXPIRED")))
+		assert.Equal(t, models.InvitationStatusExpired, invitation)
+	})
+
+	t.Run("refuses unknown values", func(t *testing.T) {
+		ttr := models.TTRStatus("ARCHIVED")
+		assert.False(t, ttr.IsValid())
+		_, err := ttr.Value()
+		assert.EqualError(t, err, `invalid TTR status "ARCHIVED"`)
+		assert.EqualError(t, ttr.Scan("open"), `invalid TTR status "open"`)
+		assert.Equal(t, models.TTRStatus("ARCHIVED"), ttr, "a failed scan leaves the value alone")
+
+		var player models.TTRPlayerStatus
+		assert.Error(t, player.Scan(""))
+		assert.Error(t, player.Scan(nil))
+		_, err = models.TTRPlayerStatus("").Value()
+		assert.Error(t, err)
+
+		var invitation models.InvitationStatus
+		assert.EqualError(t, invitation.Scan([]byte("ACCEPTED")), `invalid invitation status "ACCEPTED"`)
+		assert.Empty(t, invitation)
+	})
+}
+
+func TestTTRPlayerStatus(t *testing.T) {
+	statuses := models.AllPlayerStatuses()
+
+	t.Run("every status round trips", func(t *testing.T) {
+		for _, want := range statuses {
+			assert.True(t, want.IsValid(), want)
+			value, err := want.Value()
+			require.NoError(t, err)
+
+			var got models.TTRPlayerStatus
+			require.NoError(t, got.Scan(value))
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("active statuses", func(t *testing.T) {
+		var active []models.TTRPlayerStatus
+		for _, status := range statuses {
+			if status.IsActive() {
+				active = append(active, status)
+			}
+		}
+		assert.Equal(t, active, models.ActivePlayerStatuses())
+		assert.Contains(t, active, models.DefaultPlayerStatus, "new players take a slot")
+		assert.True(t, models.TTRPlayerStatusConfirmed.IsActive())
+		assert.False(t, models.TTRPlayerStatusWaitlisted.IsActive())
+		assert.False(t, models.TTRPlayerStatusNoShow.IsActive())
+		assert.False(t, models.TTRPlayerStatus("LATE").IsActive())
+	})
+
+	t.Run("transitions", func(t *testing.T) {
+		for _, from := range statuses {
+			assert.True(t, from.CanTransitionTo(from), "%s can stay %s", from, from)
+			assert.True(t, from.CanTransitionTo(models.TTRPlayerStatusWaitlisted), "%s can be waitlisted", from)
+			assert.False(t, from.CanTransitionTo("LATE"))
+
+			wantNoShow := from == models.TTRPlayerStatusConfirmed || from == models.TTRPlayerStatusNoShow
+			assert.Equal(t, wantNoShow, from.CanTransitionTo(models.TTRPlayerStatusNoShow), "%s to NO_SHOW", from)
+			assert.True(t, models.TTRPlayerStatusNoShow.CanTransitionTo(from), "NO_SHOW can be corrected to %s", from)
+		}
+	})
+}
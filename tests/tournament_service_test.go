@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+type MockTournamentRepository struct {
+	mock.Mock
+}
+
+func (m *MockTournamentRepository) Create(ctx context.Context, tournament *models.Tournament) error {
+	args := m.Called(tournament)
+	return args.Error(0)
+}
+
+func (m *MockTournamentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Tournament, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tournament), args.Error(1)
+}
+
+func (m *MockTournamentRepository) Update(ctx context.Context, tournament *models.Tournament) error {
+	args := m.Called(tournament)
+	return args.Error(0)
+}
+
+func (m *MockTournamentRepository) CompleteMatch(ctx context.Context, match *models.Match) (bool, error) {
+	args := m.Called(match)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTournamentRepository) UpdateMatch(ctx context.Context, match *models.Match) error {
+	args := m.Called(match)
+	return args.Error(0)
+}
+
+func seededPlayers(n int) []uuid.UUID {
+	players := make([]uuid.UUID, n)
+	for i := range players {
+		players[i] = uuid.New()
+	}
+	return players
+}
+
+func bracketMatch(t *testing.T, matches []*models.Match, round, position int) *models.Match {
+	t.Helper()
+	for _, m := range matches {
+		if m.Round == round && m.Position == position {
+			return m
+		}
+	}
+	t.Fatalf("no match at round %d position %d", round, position)
+	return nil
+}
+
+func assertPairing(t *testing.T, match *models.Match, player1, player2 *uuid.UUID) {
+	t.Helper()
+	assert.Equal(t, player1, match.Player1UserID, "round %d position %d player 1", match.Round, match.Position)
+	assert.Equal(t, player2, match.Player2UserID, "round %d position %d player 2", match.Round, match.Position)
+}
+
+func TestNewBracket_SeedsFullFields(t *testing.T) {
+	tests := []struct {
+		players int
+		rounds  int
+		// pairings lists the first-round seeds (1-based) in position order
+		pairings [][2]int
+	}{
+		{players: 4, rounds: 2, pairings: [][2]int{{1, 4}, {2, 3}}},
+		{players: 8, rounds: 3, pairings: [][2]int{{1, 8}, {4, 5}, {2, 7}, {3, 6}}},
+		{players: 16, rounds: 4, pairings: [][2]int{
+			{1, 16}, {8, 9}, {4, 13}, {5, 12}, {2, 15}, {7, 10}, {3, 14}, {6, 11},
+		}},
+	}
+
+	for _, tt := range tests {
+		players := seededPlayers(tt.players)
+		matches, err := service.NewBracket(uuid.New(), players)
+		require.NoError(t, err)
+		assert.Len(t, matches, tt.players-1, "%d players", tt.players)
+		assert.Equal(t, tt.rounds, service.FinalMatch(matches).Round)
+
+		for position, seeds := range tt.pairings {
+			match := bracketMatch(t, matches, 1, position)
+			assertPairing(t, match, &players[seeds[0]-1], &players[seeds[1]-1])
+			assert.Equal(t, models.MatchStatusScheduled, match.Status)
+			assert.False(t, match.Walkover)
+		}
+		for _, m := range matches {
+			if m.Round > 1 {
+				assert.Equal(t, models.MatchStatusPending, m.Status)
+				assertPairing(t, m, nil, nil)
+			}
+		}
+	}
+}
+
+func TestNewBracket_SixPlayersGivesTopSeedsByes(t *testing.T) {
+	players := seededPlayers(6)
+	matches, err := service.NewBracket(uuid.New(), players)
+	require.NoError(t, err)
+	require.Len(t, matches, 7, "an eight-slot bracket has 4+2+1 matches")
+
+	// Seeds 7 and 8 are missing, so seeds 1 and 2 walk over into round two.
+	bye1 := bracketMatch(t, matches, 1, 0)
+	assertPairing(t, bye1, &players[0], nil)
+	assert.Equal(t, models.MatchStatusCompleted, bye1.Status)
+	assert.True(t, bye1.Walkover)
+	assert.Equal(t, &players[0], bye1.WinnerUserID)
+	assert.NotNil(t, bye1.CompletedAt)
+
+	played := bracketMatch(t, matches, 1, 1)
+	assertPairing(t, played, &players[3], &players[4])
+	assert.Equal(t, models.MatchStatusScheduled, played.Status)
+
+	bye2 := bracketMatch(t, matches, 1, 2)
+	assertPairing(t, bye2, &players[1], nil)
+	assert.Equal(t, models.MatchStatusCompleted, bye2.Status)
+	assert.True(t, bye2.Walkover)
+
+	assertPairing(t, bracketMatch(t, matches, 1, 3), &players[2], &players[5])
+
+	semi1 := bracketMatch(t, matches, 2, 0)
+	assertPairing(t, semi1, &players[0], nil)
+	assert.Equal(t, models.MatchStatusPending, semi1.Status)
+	semi2 := bracketMatch(t, matches, 2, 1)
+	assertPairing(t, semi2, &players[1], nil)
+	assert.Equal(t, models.MatchStatusPending, semi2.Status)
+
+	// Seed 5 upsets seed 4 and is scheduled against the bye winner.
+	changed, err := service.ReportMatchResult(matches, played.ID, players[4])
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+	assert.Equal(t, played, changed[0])
+	assert.Equal(t, semi1, changed[1])
+	assert.Equal(t, models.MatchStatusCompleted, played.Status)
+	assert.False(t, played.Walkover)
+	assertPairing(t, semi1, &players[0], &players[4])
+	assert.Equal(t, models.MatchStatusScheduled, semi1.Status)
+	assert.Equal(t, models.MatchStatusPending, semi2.Status)
+}
+
+func TestNewBracket_FiveAndThreePlayers(t *testing.T) {
+	players := seededPlayers(5)
+	matches, err := service.NewBracket(uuid.New(), players)
+	require.NoError(t, err)
+
+	walkovers := 0
+	for _, m := range matches {
+		if m.Walkover {
+			walkovers++
+		}
+	}
+	assert.Equal(t, 3, walkovers)
+	// Seeds 2 and 3 both had byes, so their semi-final is ready straight away.
+	semi2 := bracketMatch(t, matches, 2, 1)
+	assertPairing(t, semi2, &players[1], &players[2])
+	assert.Equal(t, models.MatchStatusScheduled, semi2.Status)
+
+	players = seededPlayers(3)
+	matches, err = service.NewBracket(uuid.New(), players)
+	require.NoError(t, err)
+	assert.True(t, bracketMatch(t, matches, 1, 0).Walkover)
+	assertPairing(t, bracketMatch(t, matches, 1, 1), &players[1], &players[2])
+	assertPairing(t, bracketMatch(t, matches, 2, 0), &players[0], nil)
+}
+
+func TestNewBracket_RejectsInvalidFields(t *testing.T) {
+	_, err := service.NewBracket(uuid.New(), seededPlayers(1))
+	assert.EqualError(t, err, "a tournament needs between 2 and 16 players")
+
+	_, err = service.NewBracket(uuid.New(), seededPlayers(17))
+	assert.EqualError(t, err, "a tournament needs between 2 and 16 players")
+
+	players := seededPlayers(4)
+	players[3] = players[0]
+	_, err = service.NewBracket(uuid.New(), players)
+	assert.EqualError(t, err, "a player can only be entered once")
+}
+
+func TestReportMatchResult_PlaysThroughToChampion(t *testing.T) {
+	players := seededPlayers(4)
+	matches, err := service.NewBracket(uuid.New(), players)
+	require.NoError(t, err)
+	semi1 := bracketMatch(t, matches, 1, 0)
+	semi2 := bracketMatch(t, matches, 1, 1)
+	final := service.FinalMatch(matches)
+
+	_, err = service.ReportMatchResult(matches, final.ID, players[0])
+	assert.EqualError(t, err, "match is not ready to be played")
+
+	_, err = service.ReportMatchResult(matches, semi1.ID, players[1])
+	assert.EqualError(t, err, "winner must be one of the match players")
+
+	_, err = service.ReportMatchResult(matches, uuid.New(), players[0])
+	assert.EqualError(t, err, "match not found")
+
+	_, err = service.ReportMatchResult(matches, semi1.ID, players[3])
+	require.NoError(t, err)
+	assert.Equal(t, models.MatchStatusPending, final.Status, "final waits for the other semi-final")
+
+	_, err = service.ReportMatchResult(matches, semi1.ID, players[0])
+	assert.EqualError(t, err, "match already completed")
+
+	_, err = service.ReportMatchResult(matches, semi2.ID, players[1])
+	require.NoError(t, err)
+	assertPairing(t, final, &players[3], &players[1])
+	assert.Equal(t, models.MatchStatusScheduled, final.Status)
+
+	changed, err := service.ReportMatchResult(matches, final.ID, players[1])
+	require.NoError(t, err)
+	assert.Len(t, changed, 1, "the final has no next match")
+	assert.Equal(t, models.MatchStatusCompleted, final.Status)
+	assert.Equal(t, &players[1], final.WinnerUserID)
+}
+
+func TestReportResult_Permissions(t *testing.T) {
+	ownerID := uuid.New()
+	players := seededPlayers(4)
+	outsiderID := uuid.New()
+
+	newTournament := func() *models.Tournament {
+		tournament := &models.Tournament{ID: uuid.New(), Name: "Club Championship", OwnerUserID: ownerID, Status: models.TournamentStatusInProgress}
+		matches, err := service.NewBracket(tournament.ID, players)
+		require.NoError(t, err)
+		tournament.Matches = matches
+		return tournament
+	}
+
+	tests := []struct {
+		name     string
+		reporter uuid.UUID
+		wantErr  string
+	}{
+		{name: "outsider", reporter: outsiderID, wantErr: "unauthorized: only the match players or the tournament owner can report results"},
+		{name: "player in another match", reporter: players[1], wantErr: "unauthorized: only the match players or the tournament owner can report results"},
+		{name: "match player", reporter: players[3]},
+		{name: "owner", reporter: ownerID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTournamentRepo := new(MockTournamentRepository)
+			logger, _ := zap.NewDevelopment()
+			tournamentService := service.NewTournamentService(mockTournamentRepo, new(MockTTRRepository), new(MockUserRepository), service.NewAuthorizer(new(MockTTRRepository), new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository)), passthroughTransactor{}, service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+			tournament := newTournament()
+			match := tournament.Matches[0]
+			mockTournamentRepo.On("FindByID", tournament.ID).Return(tournament, nil)
+			if tt.wantErr == "" {
+				mockTournamentRepo.On("CompleteMatch", match).Return(true, nil).Once()
+				mockTournamentRepo.On("UpdateMatch", service.FinalMatch(tournament.Matches)).Return(nil).Once()
+			}
+
+			_, err := tournamentService.ReportResult(context.Background(), tournament.ID, match.ID, tt.reporter, players[0])
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				mockTournamentRepo.AssertNotCalled(t, "CompleteMatch", mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, &players[0], match.WinnerUserID)
+			}
+			mockTournamentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReportResult_FinalCompletesTournament(t *testing.T) {
+	mockTournamentRepo := new(MockTournamentRepository)
+	logger, _ := zap.NewDevelopment()
+	tournamentService := service.NewTournamentService(mockTournamentRepo, new(MockTTRRepository), new(MockUserRepository), service.NewAuthorizer(new(MockTTRRepository), new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository)), passthroughTransactor{}, service.NewNotificationService(nil, nil, nil, 0, logger), logger)
+
+	players := seededPlayers(2)
+	tournament := &models.Tournament{ID: uuid.New(), Name: "Matchplay", OwnerUserID: players[0], Status: models.TournamentStatusInProgress}
+	matches, err := service.NewBracket(tournament.ID, players)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	tournament.Matches = matches
+
+	mockTournamentRepo.On("FindByID", tournament.ID).Return(tournament, nil)
+	mockTournamentRepo.On("CompleteMatch", matches[0]).Return(true, nil).Once()
+	mockTournamentRepo.On("Update", tournament).Return(nil).Once()
+
+	got, err := tournamentService.ReportResult(context.Background(), tournament.ID, matches[0].ID, players[1], players[1])
+
+	require.NoError(t, err)
+	assert.Equal(t, models.TournamentStatusCompleted, got.Status)
+	assert.Equal(t, &players[1], got.WinnerUserID)
+	mockTournamentRepo.AssertExpectations(t)
+}
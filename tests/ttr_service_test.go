@@ -1,28 +1,32 @@
 package tests
 
 import (
-	"errors"
+	"context"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
 	"github.com/yourusername/golf_messenger/internal/service"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type MockTTRRepository struct {
 	mock.Mock
 }
 
-func (m *MockTTRRepository) Create(ttr *models.TTR) error {
+func (m *MockTTRRepository) Create(ctx context.Context, ttr *models.TTR) error {
 	args := m.Called(ttr)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) FindByID(id uuid.UUID) (*models.TTR, error) {
+func (m *MockTTRRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,25 +34,64 @@ func (m *MockTTRRepository) FindByID(id uuid.UUID) (*models.TTR, error) {
 	return args.Get(0).(*models.TTR), args.Error(1)
 }
 
-func (m *MockTTRRepository) FindAll(limit int, offset int, status string) ([]*models.TTR, error) {
-	args := m.Called(limit, offset, status)
+func (m *MockTTRRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.TTR, error) {
+	args := m.Called(ids)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*models.TTR), args.Error(1)
 }
 
-func (m *MockTTRRepository) Update(ttr *models.TTR) error {
+func (m *MockTTRRepository) FindAll(ctx context.Context, viewerID uuid.UUID, filter repository.TTRSearchFilter) ([]*models.TTR, error) {
+	args := m.Called(viewerID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TTR), args.Error(1)
+}
+
+func (m *MockTTRRepository) Update(ctx context.Context, ttr *models.TTR) error {
 	args := m.Called(ttr)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) Delete(id uuid.UUID) error {
+func (m *MockTTRRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	args := m.Called(id, status)
+	return args.Error(0)
+}
+
+func (m *MockTTRRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) FindUpcomingByUserID(userID uuid.UUID) ([]*models.TTR, error) {
+func (m *MockTTRRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*models.TTR, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TTR), args.Error(1)
+}
+
+func (m *MockTTRRepository) FindDeletedByCaptain(ctx context.Context, captainID uuid.UUID, since time.Time) ([]*models.TTR, error) {
+	args := m.Called(captainID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TTR), args.Error(1)
+}
+
+func (m *MockTTRRepository) Restore(ctx context.Context, id uuid.UUID, status models.TTRStatus) error {
+	args := m.Called(id, status)
+	return args.Error(0)
+}
+
+func (m *MockTTRRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	args := m.Called(cutoff, limit)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTTRRepository) FindUpcomingByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -56,7 +99,7 @@ func (m *MockTTRRepository) FindUpcomingByUserID(userID uuid.UUID) ([]*models.TT
 	return args.Get(0).([]*models.TTR), args.Error(1)
 }
 
-func (m *MockTTRRepository) FindPastByUserID(userID uuid.UUID) ([]*models.TTR, error) {
+func (m *MockTTRRepository) FindPastByUserID(ctx context.Context, userID uuid.UUID, now time.Time, grace time.Duration) ([]*models.TTR, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -64,32 +107,57 @@ func (m *MockTTRRepository) FindPastByUserID(userID uuid.UUID) ([]*models.TTR, e
 	return args.Get(0).([]*models.TTR), args.Error(1)
 }
 
-func (m *MockTTRRepository) AddCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error {
-	args := m.Called(ttrID, userID)
+func (m *MockTTRRepository) AddCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) error {
+	args := m.Called(ttrID, userID, permissions)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) RemoveCoCaptain(ttrID uuid.UUID, userID uuid.UUID) error {
+func (m *MockTTRRepository) UpdateCoCaptainPermissions(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, permissions []string) (bool, error) {
+	args := m.Called(ttrID, userID, permissions)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTTRRepository) RemoveCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
 	args := m.Called(ttrID, userID)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockTTRRepository) IsCoCaptain(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+func (m *MockTTRRepository) IsCoCaptain(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
 	args := m.Called(ttrID, userID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockTTRRepository) AddPlayer(ttrID uuid.UUID, userID uuid.UUID, status string) error {
+func (m *MockTTRRepository) AddPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
 	args := m.Called(ttrID, userID, status)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) RemovePlayer(ttrID uuid.UUID, userID uuid.UUID) error {
+func (m *MockTTRRepository) AddPlayerIfOpen(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, status models.TTRPlayerStatus) error {
+	args := m.Called(ttrID, userID, status)
+	return args.Error(0)
+}
+
+func (m *MockTTRRepository) RemovePlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) error {
 	args := m.Called(ttrID, userID)
 	return args.Error(0)
 }
 
-func (m *MockTTRRepository) GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
+func (m *MockTTRRepository) UpdatePlayer(ctx context.Context, player *models.TTRPlayer) error {
+	args := m.Called(player)
+	return args.Error(0)
+}
+
+func (m *MockTTRRepository) UpdatePlayerCheckIn(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, checkedInAt time.Time) (bool, error) {
+	args := m.Called(ttrID, userID, checkedInAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTTRRepository) RemoveMember(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID, newInviterID uuid.UUID) (bool, error) {
+	args := m.Called(ttrID, userID, newInviterID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTTRRepository) GetPlayers(ctx context.Context, ttrID uuid.UUID) ([]*models.TTRPlayer, error) {
 	args := m.Called(ttrID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -97,54 +165,74 @@ func (m *MockTTRRepository) GetPlayers(ttrID uuid.UUID) ([]*models.TTRPlayer, er
 	return args.Get(0).([]*models.TTRPlayer), args.Error(1)
 }
 
-func (m *MockTTRRepository) IsPlayer(ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
-	args := m.Called(ttrID, userID)
-	return args.Bool(0), args.Error(1)
+func (m *MockTTRRepository) CountPlayers(ctx context.Context, ttrIDs []uuid.UUID) (map[uuid.UUID]models.PlayerCounts, error) {
+	args := m.Called(ttrIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]models.PlayerCounts), args.Error(1)
 }
 
-type MockUserRepository struct {
-	mock.Mock
+func (m *MockTTRRepository) AddGuest(ctx context.Context, guest *models.TTRGuest) error {
+	args := m.Called(guest)
+	return args.Error(0)
 }
 
-func (m *MockUserRepository) Create(user *models.User) error {
-	args := m.Called(user)
+func (m *MockTTRRepository) RemoveGuest(ctx context.Context, ttrID uuid.UUID, guestID uuid.UUID) (bool, error) {
+	args := m.Called(ttrID, guestID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTTRRepository) ReplaceSlots(ctx context.Context, ttrID uuid.UUID, slots []models.TeeSlot) error {
+	args := m.Called(ttrID, slots)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
-	args := m.Called(id)
+func (m *MockTTRRepository) IsPlayer(ctx context.Context, ttrID uuid.UUID, userID uuid.UUID) (bool, error) {
+	args := m.Called(ttrID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTTRRepository) FindRSVPDeadlinePassed(ctx context.Context, now time.Time) ([]*models.TTR, error) {
+	args := m.Called(now)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
+	return args.Get(0).([]*models.TTR), args.Error(1)
 }
 
-func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
-	args := m.Called(email)
+func (m *MockTTRRepository) FindByCaptainCourseNear(ctx context.Context, captainID uuid.UUID, courseName string, from time.Time, to time.Time) (*models.TTR, error) {
+	args := m.Called(captainID, courseName, from, to)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.User), args.Error(1)
-}
-
-func (m *MockUserRepository) Update(user *models.User) error {
-	args := m.Called(user)
-	return args.Error(0)
+	return args.Get(0).(*models.TTR), args.Error(1)
 }
 
-func (m *MockUserRepository) Search(query string, limit int, offset int) ([]*models.User, error) {
-	args := m.Called(query, limit, offset)
+func (m *MockTTRRepository) FindCheckInSummaryDue(ctx context.Context, from time.Time, to time.Time) ([]*models.TTR, error) {
+	args := m.Called(from, to)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.User), args.Error(1)
+	return args.Get(0).([]*models.TTR), args.Error(1)
+}
+
+func (m *MockTTRRepository) MarkCheckInSummarySent(ctx context.Context, id uuid.UUID, sentAt time.Time) (bool, error) {
+	args := m.Called(id, sentAt)
+	return args.Bool(0), args.Error(1)
+}
+
+type passthroughTransactor struct{}
+
+func (passthroughTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
 
 func TestCreateTTR(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, logger)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
 
 	userID := uuid.New()
 	courseName := "Pebble Beach"
@@ -162,6 +250,7 @@ func TestCreateTTR(t *testing.T) {
 	}
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
+	mockTTRRepo.On("FindByCaptainCourseNear", userID, courseName, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil, nil)
 	mockTTRRepo.On("Create", mock.AnythingOfType("*models.TTR")).Return(nil)
 	mockTTRRepo.On("AddPlayer", mock.AnythingOfType("uuid.UUID"), userID, models.TTRPlayerStatusConfirmed).Return(nil)
 	mockTTRRepo.On("FindByID", mock.AnythingOfType("uuid.UUID")).Return(&models.TTR{
@@ -177,7 +266,7 @@ func TestCreateTTR(t *testing.T) {
 		Notes:           &notes,
 	}, nil)
 
-	ttr, err := ttrService.CreateTTR(userID, courseName, &courseLocation, teeDate, teeTime, maxPlayers, &notes)
+	ttr, err := ttrService.CreateTTR(context.Background(), userID, courseName, &courseLocation, teeDate, teeTime, maxPlayers, 0, &notes, models.CourseConditions{}, nil, nil, "", false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, ttr)
@@ -188,11 +277,167 @@ func TestCreateTTR(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestCreateTTR_DuplicateWindow(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	teeDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return time.Date(0, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		course    string
+		teeDate   time.Time
+		teeTime   time.Time
+		force     bool
+		cancelled bool
+		duplicate bool
+	}{
+		{name: "same tee time", course: "Pebble Beach", teeDate: teeDate, teeTime: at(9, 0), duplicate: true},
+		{name: "30 minutes later", course: "Pebble Beach", teeDate: teeDate, teeTime: at(9, 30), duplicate: true},
+		{name: "30 minutes earlier", course: "Pebble Beach", teeDate: teeDate, teeTime: at(8, 30), duplicate: true},
+		{name: "31 minutes later", course: "Pebble Beach", teeDate: teeDate, teeTime: at(9, 31)},
+		{name: "31 minutes earlier", course: "Pebble Beach", teeDate: teeDate, teeTime: at(8, 29)},
+		{name: "course name in another case", course: "PEBBLE BEACH", teeDate: teeDate, teeTime: at(9, 10), duplicate: true},
+		{name: "another course", course: "Spyglass Hill", teeDate: teeDate, teeTime: at(9, 0)},
+		{name: "another day", course: "Pebble Beach", teeDate: teeDate.AddDate(0, 0, 1), teeTime: at(9, 0)},
+		{name: "existing TTR cancelled", course: "Pebble Beach", teeDate: teeDate, teeTime: at(9, 0), cancelled: true},
+		{name: "forced", course: "Pebble Beach", teeDate: teeDate, teeTime: at(9, 0), force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memory.NewStore()
+			ttrRepo := memory.NewTTRRepository(store)
+			userRepo := memory.NewUserRepository(store)
+			invitationRepo := memory.NewInvitationRepository(store)
+			authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), invitationRepo, userRepo)
+			ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, memory.NewTransactor(store), authorizer, service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+			captain := &models.User{Email: "captain@example.com", FirstName: "Cap", LastName: "Tain"}
+			require.NoError(t, userRepo.Create(ctx, captain))
+			existing, err := ttrService.CreateTTR(ctx, captain.ID, "Pebble Beach", nil, teeDate, at(9, 0), 4, 0, nil, models.CourseConditions{}, nil, nil, "", false)
+			require.NoError(t, err)
+			if tt.cancelled {
+				require.NoError(t, ttrRepo.UpdateStatus(ctx, existing.ID, models.TTRStatusCancelled))
+			}
+
+			created, err := ttrService.CreateTTR(ctx, captain.ID, tt.course, nil, tt.teeDate, tt.teeTime, 4, 0, nil, models.CourseConditions{}, nil, nil, "", tt.force)
+
+			if !tt.duplicate {
+				require.NoError(t, err)
+				assert.NotEqual(t, existing.ID, created.ID)
+				return
+			}
+			assert.ErrorIs(t, err, service.ErrDuplicateTTR)
+			var duplicateErr *service.DuplicateTTRError
+			require.ErrorAs(t, err, &duplicateErr)
+			assert.Equal(t, existing.ID, duplicateErr.ExistingTTRID)
+		})
+	}
+}
+
+func TestTTRService_EditLockWindow(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop()
+	store := memory.NewStore()
+	ttrRepo := memory.NewTTRRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	invitationRepo := memory.NewInvitationRepository(store)
+	notificationRepo := memory.NewNotificationRepository(store)
+	authorizer := service.NewAuthorizer(ttrRepo, memory.NewOrganizationRepository(store), invitationRepo, userRepo)
+	ttrService := service.NewTTRService(ttrRepo, userRepo, invitationRepo, memory.NewTransactor(store), authorizer, service.NewNotificationService(notificationRepo, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 2*time.Hour, logger)
+
+	newUser := func(name string) uuid.UUID {
+		user := &models.User{Email: name + "@example.com", FirstName: name, LastName: "Golfer"}
+		require.NoError(t, userRepo.Create(ctx, user))
+		return user.ID
+	}
+	captainID := newUser("captain")
+	confirmedID := newUser("confirmed")
+	maybeID := newUser("maybe")
+
+	// newTTR creates a TTR teeing off in, to the minute, the given time.
+	newTTR := func(in time.Duration) (*service.TTRDetail, time.Time) {
+		teeAt := time.Now().UTC().Add(in).Truncate(time.Minute)
+		teeDate := time.Date(teeAt.Year(), teeAt.Month(), teeAt.Day(), 0, 0, 0, 0, time.UTC)
+		teeTime := time.Date(0, 1, 1, teeAt.Hour(), teeAt.Minute(), 0, 0, time.UTC)
+		ttr, err := ttrService.CreateTTR(ctx, captainID, "Pebble Beach", nil, teeDate, teeTime, 4, 0, nil, models.CourseConditions{}, nil, nil, models.TTRVisibilityPublic, true)
+		require.NoError(t, err)
+		return ttr, teeAt
+	}
+	later := func(ttr *service.TTRDetail) *time.Time {
+		teeTime := ttr.TeeTime.Add(time.Hour)
+		return &teeTime
+	}
+
+	t.Run("outside the window", func(t *testing.T) {
+		ttr, _ := newTTR(2*time.Hour + time.Minute)
+
+		_, err := ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, later(ttr), nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
+		require.NoError(t, err)
+		require.NoError(t, ttrService.DeleteTTR(ctx, ttr.ID, captainID, false))
+	})
+
+	t.Run("inside the window", func(t *testing.T) {
+		ttr, teeAt := newTTR(2*time.Hour - time.Minute)
+
+		courseName := "Spyglass Hill"
+		_, err := ttrService.UpdateTTR(ctx, ttr.ID, captainID, &courseName, nil, nil, nil, nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
+		var locked *service.TTRLockedError
+		require.ErrorAs(t, err, &locked)
+		assert.ErrorIs(t, err, service.ErrTTRLocked)
+		assert.Equal(t, teeAt.Add(-2*time.Hour), locked.LockedAt)
+
+		_, err = ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, later(ttr), nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
+		assert.ErrorIs(t, err, service.ErrTTRLocked)
+		assert.ErrorIs(t, ttrService.DeleteTTR(ctx, ttr.ID, captainID, false), service.ErrTTRLocked)
+
+		notes := "Meet at the range"
+		confirmed := models.TTRStatusConfirmed
+		sameTime := ttr.TeeTime
+		_, err = ttrService.UpdateTTR(ctx, ttr.ID, captainID, &ttr.CourseName, nil, nil, &sameTime, nil, nil, &confirmed, nil, &notes, models.CourseConditions{}, nil, nil, false)
+		assert.NoError(t, err, "notes, status and unchanged fields stay editable")
+	})
+
+	t.Run("override notifies confirmed players", func(t *testing.T) {
+		ttr, _ := newTTR(30 * time.Minute)
+		_, err := ttrService.JoinTTR(ctx, ttr.ID, confirmedID)
+		require.NoError(t, err)
+		_, err = ttrService.JoinTTR(ctx, ttr.ID, maybeID)
+		require.NoError(t, err)
+		require.NoError(t, ttrService.UpdatePlayerStatus(ctx, ttr.ID, captainID, maybeID, models.TTRPlayerStatusMaybe))
+
+		updated, err := ttrService.UpdateTTR(ctx, ttr.ID, captainID, nil, nil, nil, later(ttr), nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, true)
+		require.NoError(t, err)
+		assert.Equal(t, later(ttr).Format("15:04"), updated.TeeTime.Format("15:04"))
+
+		urgent := func(userID uuid.UUID) []*models.Notification {
+			notifications, err := notificationRepo.FindByUserID(ctx, userID, 50, 0)
+			require.NoError(t, err)
+			var found []*models.Notification
+			for _, notification := range notifications {
+				if notification.Type == models.NotificationTypeTTRUrgentChange {
+					found = append(found, notification)
+				}
+			}
+			return found
+		}
+		notified := urgent(confirmedID)
+		require.Len(t, notified, 1)
+		assert.Contains(t, notified[0].Message, later(ttr).Format("15:04"))
+		assert.Empty(t, urgent(maybeID), "only confirmed players are notified")
+		assert.Empty(t, urgent(captainID), "the captain made the change")
+
+		require.NoError(t, ttrService.DeleteTTR(ctx, ttr.ID, captainID, true))
+	})
+}
+
 func TestUpdateTTR_Authorization(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, logger)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
 
 	captainID := uuid.New()
 	nonCaptainID := uuid.New()
@@ -204,10 +449,9 @@ func TestUpdateTTR_Authorization(t *testing.T) {
 	}
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
-	mockTTRRepo.On("IsCoCaptain", ttrID, nonCaptainID).Return(false, nil)
 
 	newCourseName := "Augusta National"
-	_, err := ttrService.UpdateTTR(ttrID, nonCaptainID, &newCourseName, nil, nil, nil, nil, nil, nil)
+	_, err := ttrService.UpdateTTR(context.Background(), ttrID, nonCaptainID, &newCourseName, nil, nil, nil, nil, nil, nil, nil, nil, models.CourseConditions{}, nil, nil, false)
 
 	assert.Error(t, err)
 	assert.Equal(t, "unauthorized: only captain or co-captain can update TTR", err.Error())
@@ -218,7 +462,7 @@ func TestAddCoCaptain_Authorization(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, logger)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
 
 	captainID := uuid.New()
 	nonCaptainID := uuid.New()
@@ -232,7 +476,7 @@ func TestAddCoCaptain_Authorization(t *testing.T) {
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
 
-	err := ttrService.AddCoCaptain(ttrID, nonCaptainID, coCaptainID)
+	_, err := ttrService.AddCoCaptain(context.Background(), ttrID, nonCaptainID, coCaptainID, nil)
 
 	assert.Error(t, err)
 	assert.Equal(t, "unauthorized: only captain can add co-captains", err.Error())
@@ -243,7 +487,8 @@ func TestJoinTTR_WhenFull(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, logger)
+	mockInvitationRepo := new(MockInvitationRepository)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, mockInvitationRepo, passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), mockInvitationRepo, mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
 
 	userID := uuid.New()
 	ttrID := uuid.New()
@@ -251,19 +496,19 @@ func TestJoinTTR_WhenFull(t *testing.T) {
 	ttr := &models.TTR{
 		ID:         ttrID,
 		MaxPlayers: 4,
-	}
-
-	players := []*models.TTRPlayer{
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
-		{UserID: uuid.New()},
+		Visibility: models.TTRVisibilityPublic,
+		Players: []models.TTRPlayer{
+			{UserID: uuid.New(), Status: models.TTRPlayerStatusConfirmed},
+			{UserID: uuid.New(), Status: models.TTRPlayerStatusConfirmed},
+			{UserID: uuid.New(), Status: models.TTRPlayerStatusMaybe},
+			{UserID: uuid.New(), Status: models.TTRPlayerStatusDeclined},
+		},
 	}
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
-	mockTTRRepo.On("GetPlayers", ttrID).Return(players, nil)
+	mockInvitationRepo.On("FindByTTRAndInvitee", ttrID, userID).Return(nil, nil)
 
-	err := ttrService.JoinTTR(ttrID, userID)
+	_, err := ttrService.JoinTTR(context.Background(), ttrID, userID)
 
 	assert.Error(t, err)
 	assert.Equal(t, "TTR is full", err.Error())
@@ -274,7 +519,7 @@ func TestUpdatePlayerStatus_Authorization(t *testing.T) {
 	mockTTRRepo := new(MockTTRRepository)
 	mockUserRepo := new(MockUserRepository)
 	logger, _ := zap.NewDevelopment()
-	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, logger)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
 
 	captainID := uuid.New()
 	nonManagerID := uuid.New()
@@ -287,11 +532,269 @@ func TestUpdatePlayerStatus_Authorization(t *testing.T) {
 	}
 
 	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
-	mockTTRRepo.On("IsCoCaptain", ttrID, nonManagerID).Return(false, nil)
 
-	err := ttrService.UpdatePlayerStatus(ttrID, nonManagerID, playerID, models.TTRPlayerStatusMaybe)
+	err := ttrService.UpdatePlayerStatus(context.Background(), ttrID, nonManagerID, playerID, models.TTRPlayerStatusMaybe)
 
 	assert.Error(t, err)
 	assert.Equal(t, "unauthorized: only captain or co-captain can update player status", err.Error())
 	mockTTRRepo.AssertExpectations(t)
 }
+
+func TestDeleteTTR_CancelsInvitationsAndNotifies(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockInvitationRepo := new(MockInvitationRepository)
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, mockInvitationRepo, passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), mockInvitationRepo, mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	captainID := uuid.New()
+	ttrID := uuid.New()
+
+	ttr := &models.TTR{
+		ID:            ttrID,
+		CaptainUserID: captainID,
+		CourseName:    "Pebble Beach",
+		Status:        models.TTRStatusOpen,
+		Players: []models.TTRPlayer{
+			{TTRID: ttrID, UserID: captainID},
+			{TTRID: ttrID, UserID: uuid.New()},
+			{TTRID: ttrID, UserID: uuid.New()},
+		},
+	}
+
+	cancelled := []*models.Invitation{
+		{ID: uuid.New(), TTRID: ttrID, InviteeUserID: uuid.New(), Status: models.InvitationStatusCanceled},
+	}
+
+	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+	mockInvitationRepo.On("CancelPendingByTTRID", ttrID).Return(cancelled, nil)
+	mockTTRRepo.On("Update", mock.MatchedBy(func(updated *models.TTR) bool {
+		return updated.Status == models.TTRStatusCancelled
+	})).Return(nil)
+	mockTTRRepo.On("Delete", ttrID).Return(nil)
+
+	err := ttrService.DeleteTTR(context.Background(), ttrID, captainID, false)
+
+	assert.NoError(t, err)
+	mockTTRRepo.AssertExpectations(t)
+	mockInvitationRepo.AssertExpectations(t)
+
+	notified := logs.FilterMessage("Notification created").FilterField(zap.String("type", "TTR_CANCELLED"))
+	assert.Equal(t, 3, notified.Len(), "two other players and one pending invitee")
+	for _, entry := range notified.All() {
+		assert.NotEqual(t, captainID.String(), entry.ContextMap()["user_id"], "the captain deleted it and is not notified")
+	}
+}
+
+func TestDeleteTTR_OnlyCaptain(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockInvitationRepo := new(MockInvitationRepository)
+	logger := zap.NewNop()
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, mockInvitationRepo, passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), mockInvitationRepo, mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	ttrID := uuid.New()
+	ttr := &models.TTR{
+		ID:            ttrID,
+		CaptainUserID: uuid.New(),
+	}
+
+	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+
+	err := ttrService.DeleteTTR(context.Background(), ttrID, uuid.New(), false)
+
+	assert.Error(t, err)
+	assert.Equal(t, "unauthorized: only captain can delete TTR", err.Error())
+	mockInvitationRepo.AssertNotCalled(t, "CancelPendingByTTRID", mock.Anything)
+	mockTTRRepo.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestSetPairings_Validation(t *testing.T) {
+	captainID := uuid.New()
+	ttrID := uuid.New()
+
+	players := make([]models.TTRPlayer, 0, 6)
+	for i := 0; i < 6; i++ {
+		players = append(players, models.TTRPlayer{TTRID: ttrID, UserID: uuid.New()})
+	}
+
+	fiveInGroupOne := make(map[uuid.UUID]int)
+	for _, p := range players[:5] {
+		fiveInGroupOne[p.UserID] = 1
+	}
+
+	tests := []struct {
+		name     string
+		pairings map[uuid.UUID]int
+		wantErr  string
+	}{
+		{
+			name:     "group larger than four",
+			pairings: fiveInGroupOne,
+			wantErr:  "pairing group cannot have more than 4 players",
+		},
+		{
+			name:     "user not on the roster",
+			pairings: map[uuid.UUID]int{players[0].UserID: 1, uuid.New(): 1},
+			wantErr:  "pairings can only include players on the roster",
+		},
+		{
+			name:     "negative group",
+			pairings: map[uuid.UUID]int{players[0].UserID: -1},
+			wantErr:  "invalid group number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTTRRepo := new(MockTTRRepository)
+			logger := zap.NewNop()
+			ttrService := service.NewTTRService(mockTTRRepo, new(MockUserRepository), new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository)), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+			ttr := &models.TTR{
+				ID:            ttrID,
+				CaptainUserID: captainID,
+				Players:       append([]models.TTRPlayer(nil), players...),
+			}
+			mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+
+			err := ttrService.SetPairings(context.Background(), ttrID, captainID, tt.pairings)
+
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockTTRRepo.AssertNotCalled(t, "UpdatePlayer", mock.Anything)
+		})
+	}
+}
+
+func TestSetPairings_UpdatesChangedPlayersAndNotifies(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	ttrService := service.NewTTRService(mockTTRRepo, new(MockUserRepository), new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository)), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	captainID := uuid.New()
+	ttrID := uuid.New()
+	unchangedID := uuid.New()
+	movedID := uuid.New()
+	droppedID := uuid.New()
+
+	ttr := &models.TTR{
+		ID:            ttrID,
+		CaptainUserID: captainID,
+		Players: []models.TTRPlayer{
+			{TTRID: ttrID, UserID: unchangedID, GroupNumber: 1},
+			{TTRID: ttrID, UserID: movedID, GroupNumber: 1},
+			{TTRID: ttrID, UserID: droppedID, GroupNumber: 2},
+		},
+	}
+
+	mockTTRRepo.On("FindByID", ttrID).Return(ttr, nil)
+	mockTTRRepo.On("UpdatePlayer", mock.MatchedBy(func(p *models.TTRPlayer) bool {
+		return p.UserID == movedID && p.GroupNumber == 2
+	})).Return(nil).Once()
+	mockTTRRepo.On("UpdatePlayer", mock.MatchedBy(func(p *models.TTRPlayer) bool {
+		return p.UserID == droppedID && p.GroupNumber == 0
+	})).Return(nil).Once()
+
+	err := ttrService.SetPairings(context.Background(), ttrID, captainID, map[uuid.UUID]int{
+		unchangedID: 1,
+		movedID:     2,
+	})
+
+	assert.NoError(t, err)
+	mockTTRRepo.AssertExpectations(t)
+	assert.Equal(t, 2, logs.FilterField(zap.String("type", "pairings_updated")).Len())
+}
+
+func TestCreateTTR_RSVPDeadlineMustPrecedeTeeTime(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	mockUserRepo := new(MockUserRepository)
+	logger, _ := zap.NewDevelopment()
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	userID := uuid.New()
+	teeDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	teeTime := time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC)
+
+	mockUserRepo.On("FindByID", userID).Return(&models.User{ID: userID}, nil)
+
+	for _, deadline := range []time.Time{
+		time.Date(2030, 6, 1, 8, 30, 0, 0, time.UTC),
+		time.Date(2030, 6, 2, 8, 0, 0, 0, time.UTC),
+	} {
+		_, err := ttrService.CreateTTR(context.Background(), userID, "Pebble Beach", nil, teeDate, teeTime, 4, 0, nil, models.CourseConditions{}, &deadline, nil, "", false)
+
+		assert.Error(t, err)
+		assert.Equal(t, "rsvp_deadline must be before the tee time", err.Error())
+	}
+	mockTTRRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateTTR_CourseConditionsValidation(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	logger, _ := zap.NewDevelopment()
+	ttrService := service.NewTTRService(mockTTRRepo, new(MockUserRepository), new(MockInvitationRepository), passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), new(MockInvitationRepository), new(MockUserRepository)), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	teeDate := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	teeTime := time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC)
+	twelve, negative := 12, -500
+
+	tests := []struct {
+		conditions models.CourseConditions
+		want       error
+	}{
+		{models.CourseConditions{Holes: &twelve}, service.ErrInvalidHoles},
+		{models.CourseConditions{GreenFeeCents: &negative}, service.ErrInvalidGreenFee},
+	}
+	for _, tt := range tests {
+		_, err := ttrService.CreateTTR(context.Background(), uuid.New(), "Pebble Beach", nil, teeDate, teeTime, 4, 0, nil, tt.conditions, nil, nil, "", false)
+		assert.ErrorIs(t, err, tt.want)
+	}
+	mockTTRRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestProcessRSVPDeadlines_ExpiresInvitationsAndNotifiesMaybePlayers(t *testing.T) {
+	mockTTRRepo := new(MockTTRRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockInvitationRepo := new(MockInvitationRepository)
+	core, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(core)
+	ttrService := service.NewTTRService(mockTTRRepo, mockUserRepo, mockInvitationRepo, passthroughTransactor{}, service.NewAuthorizer(mockTTRRepo, new(MockOrganizationRepository), mockInvitationRepo, mockUserRepo), service.NewNotificationService(nil, nil, nil, 0, logger), nil, nil, nil, nil, 7*24*time.Hour, 0, logger)
+
+	ttrID := uuid.New()
+	maybeID := uuid.New()
+	deadline := time.Now().Add(-time.Minute)
+
+	ttr := &models.TTR{
+		ID:           ttrID,
+		CourseName:   "Pebble Beach",
+		RSVPDeadline: &deadline,
+		Players: []models.TTRPlayer{
+			{TTRID: ttrID, UserID: uuid.New(), Status: models.TTRPlayerStatusConfirmed},
+			{TTRID: ttrID, UserID: maybeID, Status: models.TTRPlayerStatusMaybe},
+		},
+	}
+
+	expired := []*models.Invitation{
+		{ID: uuid.New(), TTRID: ttrID, Status: models.InvitationStatusExpired},
+	}
+
+	mockTTRRepo.On("FindRSVPDeadlinePassed", mock.AnythingOfType("time.Time")).Return([]*models.TTR{ttr}, nil)
+	mockInvitationRepo.On("ExpirePendingByTTRID", ttrID).Return(expired, nil)
+	mockTTRRepo.On("Update", mock.MatchedBy(func(updated *models.TTR) bool {
+		return updated.RSVPClosedAt != nil
+	})).Return(nil)
+
+	err := ttrService.ProcessRSVPDeadlines(context.Background())
+
+	assert.NoError(t, err)
+	mockTTRRepo.AssertExpectations(t)
+	mockInvitationRepo.AssertExpectations(t)
+
+	notified := logs.FilterMessage("Notification created").FilterField(zap.String("type", "rsvp_confirm"))
+	if assert.Equal(t, 1, notified.Len()) {
+		assert.Equal(t, maybeID.String(), notified.All()[0].ContextMap()["user_id"])
+	}
+}
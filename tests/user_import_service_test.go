@@ -0,0 +1,274 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"github.com/yourusername/golf_messenger/pkg/errcode"
+	"github.com/yourusername/golf_messenger/pkg/mailer"
+	"go.uber.org/zap"
+)
+
+func openMembersCSV(t *testing.T) io.Reader {
+	file, err := os.Open(filepath.Join("testdata", "import", "members.csv"))
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestUserImportReader(t *testing.T) {
+	t.Run("fixture", func(t *testing.T) {
+		reader, err := service.NewUserImportReader(openMembersCSV(t))
+		require.NoError(t, err)
+
+		type result struct {
+			line   int
+			email  string
+			reason string
+		}
+		var results []result
+		var rows []*service.UserImportRow
+		for {
+			row, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			var rowErr *service.UserImportRowError
+			if errors.As(err, &rowErr) {
+				results = append(results, result{rowErr.Line, rowErr.Email, rowErr.Reason})
+				continue
+			}
+			require.NoError(t, err)
+			results = append(results, result{row.Line, row.Email, ""})
+			rows = append(rows, row)
+		}
+
+		assert.Equal(t, []result{
+			{2, "ann@example.com", ""},
+			{3, "bob@example.com", ""},
+			{4, "ANN@example.com", ""},
+			{5, "not-an-email", "email is not a valid address"},
+			{6, "dee@example.com", "first_name must be 2 to 100 characters"},
+			{7, "eve@example.com", "handicap must be a number from 0 to 54"},
+			{8, "taken@example.com", ""},
+			{10, "fay@example.com", ""},
+			{11, "", `malformed CSV: bare " in non-quoted-field`},
+			{12, "hal@example.com", ""},
+		}, results)
+
+		require.Len(t, rows, 6)
+		ann := rows[0]
+		assert.Equal(t, "Ann", ann.FirstName)
+		assert.Equal(t, "Archer", ann.LastName)
+		require.NotNil(t, ann.Handicap)
+		assert.Equal(t, 12.4, *ann.Handicap)
+		require.NotNil(t, ann.Phone)
+		assert.Equal(t, "555-0100", *ann.Phone)
+		bob := rows[1]
+		assert.Nil(t, bob.Handicap)
+		assert.Nil(t, bob.Phone)
+		assert.Equal(t, "Fay, Jr", rows[4].FirstName)
+	})
+
+	t.Run("columns in any order", func(t *testing.T) {
+		reader, err := service.NewUserImportReader(strings.NewReader("\uFEFFlast_name,e-mail,firstname,notes\nArcher,ann@example.com,Ann\n"))
+		require.NoError(t, err)
+		row, err := reader.Next()
+		require.NoError(t, err)
+		assert.Equal(t, service.UserImportRow{Line: 2, Email: "ann@example.com", FirstName: "Ann", LastName: "Archer"}, *row)
+		_, err = reader.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	for name, header := range map[string]string{
+		"empty file":       "",
+		"missing column":   "email,first_name,handicap\n",
+		"not a member csv": "id,name\n1,Ann\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.NewUserImportReader(strings.NewReader(header))
+			assert.ErrorIs(t, err, service.ErrInvalidImportFile)
+			var coded *errcode.Error
+			require.ErrorAs(t, err, &coded)
+			assert.Equal(t, errcode.InvalidImportFile, coded.Code)
+		})
+	}
+}
+
+// failingCreateUserRepository fails to create the user with email, to show a
+// row that can't be saved doesn't stop the import.
+type failingCreateUserRepository struct {
+	repository.UserRepository
+	email string
+}
+
+func (r failingCreateUserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.Email == r.email {
+		return errors.New("connection reset")
+	}
+	return r.UserRepository.Create(ctx, user)
+}
+
+type userImportFixture struct {
+	store        *memory.Store
+	userRepo     repository.UserRepository
+	auditLogRepo repository.AuditLogRepository
+	outbox       *service.OutboxService
+	mailer       *mailer.MemoryMailer
+	adminID      uuid.UUID
+}
+
+func newUserImportFixture(t *testing.T) *userImportFixture {
+	store := memory.NewStore()
+	f := &userImportFixture{
+		store:        store,
+		userRepo:     memory.NewUserRepository(store),
+		auditLogRepo: memory.NewAuditLogRepository(store),
+		outbox:       service.NewOutboxService(memory.NewOutboxRepository(store), outboxTestConfig(), zap.NewNop()),
+		mailer:       mailer.NewMemoryMailer(),
+		adminID:      uuid.New(),
+	}
+	require.NoError(t, f.userRepo.Create(context.Background(), &models.User{Email: "taken@example.com", PasswordHash: "hash", FirstName: "Tom", LastName: "Taken"}))
+	return f
+}
+
+func (f *userImportFixture) service(userRepo repository.UserRepository, mail mailer.Mailer, maxRows int) *service.UserImportService {
+	return service.NewUserImportService(userRepo, f.auditLogRepo, memory.NewTransactor(f.store), f.outbox, mail, maxRows, zap.NewNop())
+}
+
+func TestUserImportService_Import(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fixture", func(t *testing.T) {
+		f := newUserImportFixture(t)
+		report, err := f.service(f.userRepo, f.mailer, 100).Import(ctx, f.adminID, openMembersCSV(t))
+		require.NoError(t, err)
+
+		statuses := make(map[int]string)
+		for _, row := range report.Rows {
+			statuses[row.Line] = row.Status
+		}
+		assert.Equal(t, map[int]string{
+			2:  service.ImportStatusCreated,
+			3:  service.ImportStatusCreated,
+			4:  service.ImportStatusDuplicate,
+			5:  service.ImportStatusError,
+			6:  service.ImportStatusError,
+			7:  service.ImportStatusError,
+			8:  service.ImportStatusDuplicate,
+			10: service.ImportStatusCreated,
+			11: service.ImportStatusError,
+			12: service.ImportStatusCreated,
+		}, statuses)
+		assert.Equal(t, 4, report.Created)
+		assert.Equal(t, 2, report.Skipped)
+		assert.Equal(t, 4, report.Failed)
+		assert.False(t, report.Truncated)
+
+		ann, err := f.userRepo.FindByEmail(ctx, "ann@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, ann)
+		assert.Equal(t, "Archer", ann.LastName)
+		assert.True(t, ann.MustResetPassword)
+		assert.Equal(t, &ann.ID, report.Rows[0].UserID)
+		assert.True(t, report.Rows[0].Invited)
+
+		entries, err := f.auditLogRepo.Find(ctx, &f.adminID, 100, 0)
+		require.NoError(t, err)
+		assert.Len(t, entries, 4)
+		for _, entry := range entries {
+			assert.Equal(t, models.AuditActionImported, entry.Action)
+		}
+
+		// Invitations go out once the outbox is dispatched, each with a
+		// temporary password that logs the member in.
+		assert.Empty(t, f.mailer.Sent())
+		_, err = f.outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		sent := f.mailer.Sent()
+		require.Len(t, sent, 4)
+		var annMail *mailer.Message
+		for i := range sent {
+			if sent[i].To == "ann@example.com" {
+				annMail = &sent[i]
+			}
+		}
+		require.NotNil(t, annMail)
+		ann, err = f.userRepo.FindByEmail(ctx, "ann@example.com")
+		require.NoError(t, err)
+		lines := strings.Split(annMail.Body, "\n")
+		assert.Contains(t, lines[0], "Ann")
+		assert.True(t, ann.CheckPassword(lines[4]), "the emailed password logs in")
+	})
+
+	t.Run("without a mailer", func(t *testing.T) {
+		f := newUserImportFixture(t)
+		report, err := f.service(f.userRepo, nil, 100).Import(ctx, f.adminID, strings.NewReader("email,first_name,last_name\nann@example.com,Ann,Archer\n"))
+		require.NoError(t, err)
+		require.Len(t, report.Rows, 1)
+		assert.Equal(t, service.ImportStatusCreated, report.Rows[0].Status)
+		assert.False(t, report.Rows[0].Invited)
+		dispatched, err := f.outbox.Dispatch(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, dispatched)
+	})
+
+	t.Run("a row that fails to save doesn't stop the import", func(t *testing.T) {
+		f := newUserImportFixture(t)
+		userRepo := failingCreateUserRepository{UserRepository: f.userRepo, email: "bob@example.com"}
+		report, err := f.service(userRepo, f.mailer, 100).Import(ctx, f.adminID, strings.NewReader("email,first_name,last_name\nann@example.com,Ann,Archer\nbob@example.com,Bob,Baker\ncid@example.com,Cid,Cole\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Created)
+		assert.Equal(t, 1, report.Failed)
+		assert.Equal(t, service.UserImportResult{Line: 3, Email: "bob@example.com", Status: service.ImportStatusError, Reason: "account could not be created"}, report.Rows[1])
+	})
+
+	t.Run("capped at the row limit", func(t *testing.T) {
+		f := newUserImportFixture(t)
+		report, err := f.service(f.userRepo, f.mailer, 3).Import(ctx, f.adminID, openMembersCSV(t))
+		require.NoError(t, err)
+		assert.True(t, report.Truncated)
+		assert.Len(t, report.Rows, 3)
+		hal, err := f.userRepo.FindByEmail(ctx, "hal@example.com")
+		require.NoError(t, err)
+		assert.Nil(t, hal, "rows past the limit aren't read")
+
+		report, err = f.service(f.userRepo, f.mailer, 1).Import(ctx, f.adminID, strings.NewReader("email,first_name,last_name\ncid@example.com,Cid,Cole\n"))
+		require.NoError(t, err)
+		assert.False(t, report.Truncated, "a file exactly at the limit is complete")
+	})
+}
+
+func TestUserImportService_InviteSkipsChosenPassword(t *testing.T) {
+	ctx := context.Background()
+	f := newUserImportFixture(t)
+	report, err := f.service(f.userRepo, f.mailer, 100).Import(ctx, f.adminID, strings.NewReader("email,first_name,last_name\nann@example.com,Ann,Archer\n"))
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Created)
+
+	// Ann chose a password before the invitation went out.
+	ann, err := f.userRepo.FindByEmail(ctx, "ann@example.com")
+	require.NoError(t, err)
+	require.NoError(t, ann.SetPassword("my own password"))
+	ann.MustResetPassword = false
+	require.NoError(t, f.userRepo.Update(ctx, ann))
+
+	_, err = f.outbox.Dispatch(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, f.mailer.Sent())
+	ann, err = f.userRepo.FindByEmail(ctx, "ann@example.com")
+	require.NoError(t, err)
+	assert.True(t, ann.CheckPassword("my own password"))
+}
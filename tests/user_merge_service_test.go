@@ -0,0 +1,360 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type mergeFixture struct {
+	userRepo         repository.UserRepository
+	ttrRepo          repository.TTRRepository
+	invitationRepo   repository.InvitationRepository
+	notificationRepo repository.NotificationRepository
+	messageRepo      repository.MessageRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	service          *service.UserMergeService
+	admin            uuid.UUID
+	source           *models.User
+	target           *models.User
+}
+
+// newMergeFixture sets up a merge service with a source account, made with
+// Google, and a target account, made with email, for the same golfer.
+func newMergeFixture(t *testing.T) *mergeFixture {
+	store := memory.NewStore()
+	f := &mergeFixture{
+		userRepo:         memory.NewUserRepository(store),
+		ttrRepo:          memory.NewTTRRepository(store),
+		invitationRepo:   memory.NewInvitationRepository(store),
+		notificationRepo: memory.NewNotificationRepository(store),
+		messageRepo:      memory.NewMessageRepository(store),
+		refreshTokenRepo: memory.NewRefreshTokenRepository(store),
+		admin:            uuid.New(),
+	}
+	f.service = service.NewUserMergeService(f.userRepo, memory.NewTransactor(store), zap.NewNop())
+	f.source = f.createUser(t, "ana.google")
+	f.target = f.createUser(t, "ana")
+	return f
+}
+
+func (f *mergeFixture) createUser(t *testing.T, name string) *models.User {
+	user := &models.User{Email: name + "@example.com", PasswordHash: "hash", FirstName: "Ana", LastName: "Golfer"}
+	require.NoError(t, f.userRepo.Create(context.Background(), user))
+	return user
+}
+
+func (f *mergeFixture) createTTR(t *testing.T, captainID uuid.UUID) *models.TTR {
+	ttr := &models.TTR{
+		CourseName:      "Pebble Beach",
+		TeeDate:         time.Now().AddDate(0, 0, 3).Truncate(24 * time.Hour),
+		TeeTime:         time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		MaxPlayers:      4,
+		CreatedByUserID: captainID,
+		CaptainUserID:   captainID,
+		Status:          models.TTRStatusOpen,
+		Visibility:      models.TTRVisibilityPublic,
+	}
+	require.NoError(t, f.ttrRepo.Create(context.Background(), ttr))
+	require.NoError(t, f.ttrRepo.AddPlayer(context.Background(), ttr.ID, captainID, models.TTRPlayerStatusConfirmed))
+	return ttr
+}
+
+func (f *mergeFixture) invite(t *testing.T, ttrID uuid.UUID, inviterID uuid.UUID, inviteeID uuid.UUID, status models.InvitationStatus) *models.Invitation {
+	invitation := &models.Invitation{TTRID: ttrID, InviterUserID: inviterID, InviteeUserID: inviteeID, Status: status}
+	require.NoError(t, f.invitationRepo.Create(context.Background(), invitation))
+	return invitation
+}
+
+func (f *mergeFixture) merge(t *testing.T) *service.UserMergeResult {
+	result, err := f.service.Merge(context.Background(), f.admin, f.source.ID, f.target.ID)
+	require.NoError(t, err)
+	return result
+}
+
+func (f *mergeFixture) roster(t *testing.T, ttrID uuid.UUID) map[uuid.UUID]models.TTRPlayerStatus {
+	players, err := f.ttrRepo.GetPlayers(context.Background(), ttrID)
+	require.NoError(t, err)
+	roster := make(map[uuid.UUID]models.TTRPlayerStatus, len(players))
+	for _, player := range players {
+		roster[player.UserID] = player.Status
+	}
+	return roster
+}
+
+func (f *mergeFixture) coCaptains(t *testing.T, ttrID uuid.UUID) map[uuid.UUID]string {
+	ttr, err := f.ttrRepo.FindByID(context.Background(), ttrID)
+	require.NoError(t, err)
+	require.NotNil(t, ttr)
+	coCaptains := make(map[uuid.UUID]string, len(ttr.CoCaptains))
+	for _, coCaptain := range ttr.CoCaptains {
+		coCaptains[coCaptain.UserID] = coCaptain.Permissions
+	}
+	return coCaptains
+}
+
+func TestUserMergeService_MovesSourceRows(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t)
+	friend := f.createUser(t, "friend")
+
+	captained := f.createTTR(t, f.source.ID)
+	joined := f.createTTR(t, friend.ID)
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, joined.ID, f.source.ID, models.TTRPlayerStatusMaybe))
+	coCaptained := f.createTTR(t, friend.ID)
+	require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, coCaptained.ID, f.source.ID, []string{models.CoCaptainPermissionInvite}))
+	received := f.invite(t, joined.ID, friend.ID, f.source.ID, models.InvitationStatusYes)
+	sent := f.invite(t, captained.ID, f.source.ID, friend.ID, models.InvitationStatusPending)
+	notification := &models.Notification{UserID: f.source.ID, Type: models.NotificationTypeTTRUpdate, Title: "Update", Message: "Update"}
+	require.NoError(t, f.notificationRepo.Create(ctx, notification))
+	message := &models.Message{TTRID: joined.ID, UserID: f.source.ID, Body: "See you on the first tee"}
+	require.NoError(t, f.messageRepo.Create(ctx, message))
+	require.NoError(t, f.refreshTokenRepo.Create(ctx, &models.RefreshToken{UserID: f.source.ID, TokenHash: "source-token", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	result := f.merge(t)
+	assert.Equal(t, f.source.ID, result.SourceUserID)
+	assert.Equal(t, f.target.ID, result.TargetUserID)
+	assert.Equal(t, repository.UserMerge{
+		CaptainedTTRs:        1,
+		CoCaptaincies:        1,
+		RosterSpots:          2,
+		Invitations:          2,
+		Notifications:        1,
+		Messages:             1,
+		RevokedRefreshTokens: 1,
+	}, result.UserMerge)
+
+	ttr, err := f.ttrRepo.FindByID(ctx, captained.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, ttr.CaptainUserID)
+	assert.Equal(t, f.target.ID, ttr.CreatedByUserID)
+	assert.Equal(t, map[uuid.UUID]models.TTRPlayerStatus{f.target.ID: models.TTRPlayerStatusConfirmed}, f.roster(t, captained.ID))
+	assert.Equal(t, models.TTRPlayerStatusMaybe, f.roster(t, joined.ID)[f.target.ID], "the moved spot keeps its status")
+	assert.Equal(t, map[uuid.UUID]string{f.target.ID: models.CoCaptainPermissionInvite}, f.coCaptains(t, coCaptained.ID))
+
+	foundReceived, err := f.invitationRepo.FindByID(ctx, received.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, foundReceived.InviteeUserID)
+	foundSent, err := f.invitationRepo.FindByID(ctx, sent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, foundSent.InviterUserID)
+	foundNotification, err := f.notificationRepo.FindByID(ctx, notification.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, foundNotification.UserID)
+	foundMessage, err := f.messageRepo.FindByID(ctx, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.target.ID, foundMessage.UserID)
+	token, err := f.refreshTokenRepo.FindByTokenHash(ctx, "source-token")
+	require.NoError(t, err)
+	assert.Equal(t, f.source.ID, token.UserID, "refresh tokens are revoked, not moved")
+	assert.True(t, token.Revoked)
+
+	source, err := f.userRepo.FindByID(ctx, f.source.ID)
+	require.NoError(t, err)
+	assert.Nil(t, source, "the source is deleted")
+	source, err = f.userRepo.FindByIDUnscoped(ctx, f.source.ID)
+	require.NoError(t, err)
+	require.NotNil(t, source.MergedIntoUserID)
+	assert.Equal(t, f.target.ID, *source.MergedIntoUserID)
+}
+
+func TestUserMergeService_SameRosterKeepsTargetSpot(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t)
+	friend := f.createUser(t, "friend")
+	ttr := f.createTTR(t, friend.ID)
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, ttr.ID, f.source.ID, models.TTRPlayerStatusConfirmed))
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, ttr.ID, f.target.ID, models.TTRPlayerStatusMaybe))
+
+	result := f.merge(t)
+	assert.Equal(t, int64(0), result.RosterSpots)
+	assert.Equal(t, int64(1), result.DroppedRosterSpots)
+	assert.Equal(t, map[uuid.UUID]models.TTRPlayerStatus{
+		friend.ID:   models.TTRPlayerStatusConfirmed,
+		f.target.ID: models.TTRPlayerStatusMaybe,
+	}, f.roster(t, ttr.ID), "the target's spot is kept as it was")
+}
+
+func TestUserMergeService_CaptainOnTargetRoster(t *testing.T) {
+	f := newMergeFixture(t)
+	ttr := f.createTTR(t, f.source.ID)
+	require.NoError(t, f.ttrRepo.AddPlayer(context.Background(), ttr.ID, f.target.ID, models.TTRPlayerStatusWaitlisted))
+
+	result := f.merge(t)
+	assert.Equal(t, int64(1), result.CaptainedTTRs)
+	assert.Equal(t, int64(1), result.DroppedRosterSpots)
+	assert.Equal(t, map[uuid.UUID]models.TTRPlayerStatus{f.target.ID: models.TTRPlayerStatusWaitlisted}, f.roster(t, ttr.ID))
+}
+
+func TestUserMergeService_CoCaptainConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("both co-captain keeps the target's permissions", func(t *testing.T) {
+		f := newMergeFixture(t)
+		friend := f.createUser(t, "friend")
+		ttr := f.createTTR(t, friend.ID)
+		require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, ttr.ID, f.source.ID, models.CoCaptainPermissions))
+		require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, ttr.ID, f.target.ID, []string{models.CoCaptainPermissionInvite}))
+
+		result := f.merge(t)
+		assert.Equal(t, int64(0), result.CoCaptaincies)
+		assert.Equal(t, int64(1), result.DroppedCoCaptaincies)
+		assert.Equal(t, map[uuid.UUID]string{f.target.ID: models.CoCaptainPermissionInvite}, f.coCaptains(t, ttr.ID))
+	})
+
+	t.Run("source co-captains the target's TTR", func(t *testing.T) {
+		f := newMergeFixture(t)
+		ttr := f.createTTR(t, f.target.ID)
+		require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, ttr.ID, f.source.ID, models.CoCaptainPermissions))
+
+		result := f.merge(t)
+		assert.Equal(t, int64(1), result.DroppedCoCaptaincies)
+		assert.Empty(t, f.coCaptains(t, ttr.ID), "the captain isn't also a co-captain")
+	})
+
+	t.Run("target co-captains the source's TTR", func(t *testing.T) {
+		f := newMergeFixture(t)
+		ttr := f.createTTR(t, f.source.ID)
+		require.NoError(t, f.ttrRepo.AddCoCaptain(ctx, ttr.ID, f.target.ID, models.CoCaptainPermissions))
+
+		result := f.merge(t)
+		assert.Equal(t, int64(1), result.CaptainedTTRs)
+		assert.Equal(t, int64(1), result.DroppedCoCaptaincies)
+		found, err := f.ttrRepo.FindByID(ctx, ttr.ID)
+		require.NoError(t, err)
+		assert.Equal(t, f.target.ID, found.CaptainUserID)
+		assert.Empty(t, found.CoCaptains, "the target is captain now")
+	})
+}
+
+func TestUserMergeService_InvitationConflicts(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("both pending keeps the target's", func(t *testing.T) {
+		f := newMergeFixture(t)
+		friend := f.createUser(t, "friend")
+		ttr := f.createTTR(t, friend.ID)
+		sourceInvitation := f.invite(t, ttr.ID, friend.ID, f.source.ID, models.InvitationStatusPending)
+		targetInvitation := f.invite(t, ttr.ID, friend.ID, f.target.ID, models.InvitationStatusPending)
+
+		result := f.merge(t)
+		assert.Equal(t, int64(0), result.Invitations)
+		assert.Equal(t, int64(1), result.DroppedInvitations)
+		found, err := f.invitationRepo.FindByID(ctx, sourceInvitation.ID)
+		require.NoError(t, err)
+		assert.Nil(t, found)
+		found, err = f.invitationRepo.FindByID(ctx, targetInvitation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.InvitationStatusPending, found.Status)
+	})
+
+	t.Run("answered invitations to the same TTR are all kept", func(t *testing.T) {
+		f := newMergeFixture(t)
+		friend := f.createUser(t, "friend")
+		ttr := f.createTTR(t, friend.ID)
+		answered := f.invite(t, ttr.ID, friend.ID, f.source.ID, models.InvitationStatusNo)
+		f.invite(t, ttr.ID, friend.ID, f.target.ID, models.InvitationStatusPending)
+
+		result := f.merge(t)
+		assert.Equal(t, int64(1), result.Invitations)
+		assert.Equal(t, int64(0), result.DroppedInvitations)
+		found, err := f.invitationRepo.FindByID(ctx, answered.ID)
+		require.NoError(t, err)
+		assert.Equal(t, f.target.ID, found.InviteeUserID)
+	})
+
+	t.Run("invitations between the accounts are dropped", func(t *testing.T) {
+		f := newMergeFixture(t)
+		sourceTTR := f.createTTR(t, f.source.ID)
+		targetTTR := f.createTTR(t, f.target.ID)
+		toTarget := f.invite(t, sourceTTR.ID, f.source.ID, f.target.ID, models.InvitationStatusPending)
+		toSource := f.invite(t, targetTTR.ID, f.target.ID, f.source.ID, models.InvitationStatusYes)
+
+		result := f.merge(t)
+		assert.Equal(t, int64(2), result.DroppedInvitations)
+		for _, invitation := range []*models.Invitation{toTarget, toSource} {
+			found, err := f.invitationRepo.FindByID(ctx, invitation.ID)
+			require.NoError(t, err)
+			assert.Nil(t, found, "nobody is invited by themselves")
+		}
+	})
+}
+
+func TestUserMergeService_RerunChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture(t)
+	friend := f.createUser(t, "friend")
+	ttr := f.createTTR(t, f.source.ID)
+	require.NoError(t, f.ttrRepo.AddPlayer(ctx, ttr.ID, friend.ID, models.TTRPlayerStatusConfirmed))
+	f.merge(t)
+	merged, err := f.userRepo.FindByIDUnscoped(ctx, f.source.ID)
+	require.NoError(t, err)
+
+	result := f.merge(t)
+	assert.Equal(t, repository.UserMerge{}, result.UserMerge)
+	again, err := f.userRepo.FindByIDUnscoped(ctx, f.source.ID)
+	require.NoError(t, err)
+	assert.True(t, merged.DeletedAt.Time.Equal(again.DeletedAt.Time), "the source keeps its deletion time")
+	assert.Equal(t, map[uuid.UUID]models.TTRPlayerStatus{
+		f.target.ID: models.TTRPlayerStatusConfirmed,
+		friend.ID:   models.TTRPlayerStatusConfirmed,
+	}, f.roster(t, ttr.ID))
+}
+
+func TestUserMergeService_Refusals(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("into itself", func(t *testing.T) {
+		f := newMergeFixture(t)
+		_, err := f.service.Merge(ctx, f.admin, f.source.ID, f.source.ID)
+		assert.ErrorIs(t, err, service.ErrCannotMergeSelf)
+	})
+
+	t.Run("unknown source", func(t *testing.T) {
+		f := newMergeFixture(t)
+		_, err := f.service.Merge(ctx, f.admin, uuid.New(), f.target.ID)
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+
+	t.Run("deleted target", func(t *testing.T) {
+		f := newMergeFixture(t)
+		f.target.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		require.NoError(t, f.userRepo.Update(ctx, f.target))
+		_, err := f.service.Merge(ctx, f.admin, f.source.ID, f.target.ID)
+		assert.ErrorIs(t, err, service.ErrMergeTargetNotFound)
+	})
+
+	t.Run("source merged into someone else", func(t *testing.T) {
+		f := newMergeFixture(t)
+		other := f.createUser(t, "other")
+		_, err := f.service.Merge(ctx, f.admin, f.source.ID, other.ID)
+		require.NoError(t, err)
+		_, err = f.service.Merge(ctx, f.admin, f.source.ID, f.target.ID)
+		assert.ErrorIs(t, err, service.ErrUserAlreadyMerged)
+	})
+
+	t.Run("deactivated source is merged", func(t *testing.T) {
+		f := newMergeFixture(t)
+		deactivatedAt := time.Now().AddDate(0, 0, -10)
+		f.source.DeletedAt = gorm.DeletedAt{Time: deactivatedAt, Valid: true}
+		require.NoError(t, f.userRepo.Update(ctx, f.source))
+		f.createTTR(t, f.source.ID)
+
+		result := f.merge(t)
+		assert.Equal(t, int64(1), result.CaptainedTTRs)
+		source, err := f.userRepo.FindByIDUnscoped(ctx, f.source.ID)
+		require.NoError(t, err)
+		assert.True(t, deactivatedAt.Equal(source.DeletedAt.Time))
+		assert.NotNil(t, source.MergedIntoUserID)
+	})
+}
@@ -1,14 +1,19 @@
 package tests
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
 	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
 	"github.com/yourusername/golf_messenger/internal/service"
-	"github.com/yourusername/golf_messenger/pkg/storage"
+	"github.com/yourusername/golf_messenger/pkg/password"
 )
 
 func TestUserService_GetProfile_Success(t *testing.T) {
@@ -24,9 +29,9 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	result, err := userService.GetProfile(userID)
+	result, err := userService.GetProfile(context.Background(), userID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -44,9 +49,9 @@ func TestUserService_GetProfile_NotFound(t *testing.T) {
 
 	mockUserRepo.On("FindByID", userID).Return(nil, nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	result, err := userService.GetProfile(userID)
+	result, err := userService.GetProfile(context.Background(), userID)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -69,10 +74,10 @@ func TestUserService_UpdateProfile_Success(t *testing.T) {
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
 	mockUserRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
 	handicap := 15.5
-	result, err := userService.UpdateProfile(userID, "Jane", "Smith", &handicap, nil)
+	result, err := userService.UpdateProfile(context.Background(), userID, "Jane", "Smith", &handicap, nil, nil, nil, nil, nil, nil)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -90,9 +95,9 @@ func TestUserService_UpdateProfile_UserNotFound(t *testing.T) {
 
 	mockUserRepo.On("FindByID", userID).Return(nil, nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	result, err := userService.UpdateProfile(userID, "Jane", "Smith", nil, nil)
+	result, err := userService.UpdateProfile(context.Background(), userID, "Jane", "Smith", nil, nil, nil, nil, nil, nil, nil)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -101,26 +106,89 @@ func TestUserService_UpdateProfile_UserNotFound(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUserService_UpdateProfile_Details(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	userID := uuid.New()
+	user := &models.User{ID: userID, Email: "test@example.com", FirstName: "John", LastName: "Doe"}
+
+	mockUserRepo.On("FindByID", userID).Return(user, nil)
+	mockUserRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
+	mockUserRepo.On("SetPlayingDays", userID, []string{"wednesday", "saturday"}).Return(nil)
+
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
+
+	homeCourse := "  Pebble Beach "
+	bio := "Weekend hacker"
+	teeTimes := &service.TeeTimeRange{
+		Start: time.Date(0, 1, 1, 7, 0, 0, 0, time.UTC),
+		End:   time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC),
+	}
+	result, err := userService.UpdateProfile(context.Background(), userID, "", "", nil, nil, nil, &homeCourse, &bio, []string{"Saturday", "wednesday", "saturday"}, teeTimes)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Pebble Beach", *result.HomeCourse)
+	assert.Equal(t, "Weekend hacker", *result.Bio)
+	assert.Equal(t, []string{"wednesday", "saturday"}, result.PlayingDays)
+	require.NotNil(t, result.PreferredTeeTimes)
+	assert.Equal(t, "07:00", result.PreferredTeeTimes.Start.Format("15:04"))
+	assert.Equal(t, "10:30", result.PreferredTeeTimes.End.Format("15:04"))
+
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_UpdateProfile_InvalidDetails(t *testing.T) {
+	userID := uuid.New()
+	at := func(hour int) time.Time { return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name        string
+		playingDays []string
+		teeTimes    *service.TeeTimeRange
+		wantErr     string
+	}{
+		{"unknown weekday", []string{"someday"}, nil, "invalid playing day"},
+		{"range ends before it starts", nil, &service.TeeTimeRange{Start: at(10), End: at(7)}, "preferred tee time range must end after it starts"},
+		{"empty range", nil, &service.TeeTimeRange{Start: at(8), End: at(8)}, "preferred tee time range must end after it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(MockUserRepository)
+			mockUserRepo.On("FindByID", userID).Return(&models.User{ID: userID}, nil)
+			userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
+
+			_, err := userService.UpdateProfile(context.Background(), userID, "", "", nil, nil, nil, nil, nil, tt.playingDays, tt.teeTimes)
+
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+			mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+		})
+	}
+}
+
 func TestUserService_ChangePassword_Success(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 
 	userID := uuid.New()
 	user := &models.User{
-		ID:        userID,
-		Email:     "test@example.com",
-		FirstName: "John",
-		LastName:  "Doe",
+		ID:                userID,
+		Email:             "test@example.com",
+		FirstName:         "John",
+		LastName:          "Doe",
+		MustResetPassword: true,
 	}
 	user.SetPassword("oldpassword123")
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
 	mockUserRepo.On("Update", mock.AnythingOfType("*models.User")).Return(nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	err := userService.ChangePassword(userID, "oldpassword123", "newpassword123")
+	err := userService.ChangePassword(context.Background(), userID, "oldpassword123", "newpassword123")
 
 	assert.NoError(t, err)
+	assert.False(t, user.MustResetPassword, "choosing a password ends the reset")
 
 	mockUserRepo.AssertExpectations(t)
 }
@@ -139,9 +207,9 @@ func TestUserService_ChangePassword_InvalidOldPassword(t *testing.T) {
 
 	mockUserRepo.On("FindByID", userID).Return(user, nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	err := userService.ChangePassword(userID, "wrongpassword", "newpassword123")
+	err := userService.ChangePassword(context.Background(), userID, "wrongpassword", "newpassword123")
 
 	assert.Error(t, err)
 	assert.Equal(t, "invalid old password", err.Error())
@@ -149,6 +217,34 @@ func TestUserService_ChangePassword_InvalidOldPassword(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestUserService_ChangePassword_WeakPassword(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	userID := uuid.New()
+	user := &models.User{
+		ID:                userID,
+		Email:             "test@example.com",
+		MustResetPassword: true,
+	}
+	user.SetPassword("oldpassword123")
+
+	mockUserRepo.On("FindByID", userID).Return(user, nil)
+
+	policy := password.Policy{MinLength: 12, RequireClasses: []password.Rule{password.RuleDigit}, RejectEmail: true}
+	userService := service.NewUserService(mockUserRepo, nil, nil, policy, config.AvatarConfig{})
+
+	err := userService.ChangePassword(context.Background(), userID, "oldpassword123", "my-test-pass")
+
+	var weak *service.PasswordPolicyError
+	require.ErrorAs(t, err, &weak)
+	assert.Equal(t, []password.Rule{password.RuleDigit, password.RuleEmail}, weak.Rules)
+	assert.Equal(t, 12, weak.MinLength)
+	assert.True(t, user.CheckPassword("oldpassword123"), "the old password is kept")
+	assert.True(t, user.MustResetPassword, "a refused password doesn't end the reset")
+
+	mockUserRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
 func TestUserService_SearchUsers_Success(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 
@@ -167,11 +263,16 @@ func TestUserService_SearchUsers_Success(t *testing.T) {
 		},
 	}
 
-	mockUserRepo.On("Search", "doe", 20, 0).Return(users, nil)
+	callerID := uuid.New()
+	mockUserRepo.On("Search", repository.UserSearchFilter{
+		Name:          "doe",
+		ExcludeUserID: callerID,
+		Limit:         20,
+	}).Return(users, nil)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	result, err := userService.SearchUsers("doe", 20, 0)
+	result, err := userService.SearchUsers(context.Background(), "doe", callerID, uuid.Nil, 20, 0)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -183,11 +284,69 @@ func TestUserService_SearchUsers_Success(t *testing.T) {
 func TestUserService_SearchUsers_EmptyQuery(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 
-	userService := service.NewUserService(mockUserRepo, nil)
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
 
-	result, err := userService.SearchUsers("  ", 20, 0)
+	result, err := userService.SearchUsers(context.Background(), "  ", uuid.Nil, uuid.Nil, 20, 0)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
 	assert.Len(t, result, 0)
 }
+
+func TestUserService_SearchUsers_ExactEmail(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	ttrID := uuid.New()
+	user := &models.User{
+		ID:        uuid.New(),
+		Email:     "john@example.com",
+		FirstName: "John",
+		LastName:  "Doe",
+	}
+
+	mockUserRepo.On("Search", repository.UserSearchFilter{
+		Email:        "john@example.com",
+		ExcludeTTRID: ttrID,
+		Limit:        20,
+	}).Return([]*models.User{user}, nil)
+
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
+
+	result, err := userService.SearchUsers(context.Background(), "john@example.com", uuid.Nil, ttrID, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_SearchUsers_EmailFragmentMatchesNamesOnly(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	mockUserRepo.On("Search", repository.UserSearchFilter{
+		Name:  "@gmail.com",
+		Limit: 20,
+	}).Return([]*models.User{}, nil)
+
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
+
+	result, err := userService.SearchUsers(context.Background(), "@gmail.com", uuid.Nil, uuid.Nil, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_SearchUsers_QueryTooShort(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+
+	userService := service.NewUserService(mockUserRepo, nil, nil, password.Policy{}, config.AvatarConfig{})
+
+	result, err := userService.SearchUsers(context.Background(), "jo", uuid.Nil, uuid.Nil, 20, 0)
+
+	assert.Error(t, err)
+	assert.Equal(t, "search query must be at least 3 characters", err.Error())
+	assert.Nil(t, result)
+	mockUserRepo.AssertNotCalled(t, "Search", mock.Anything)
+}
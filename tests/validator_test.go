@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/handler"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/pkg/validator"
+)
+
+func TestValidator_FormatValidationErrors(t *testing.T) {
+	handicap := 60.0
+	tooLong := "a-phone-number-far-too-long"
+	maxPlayers := 9
+
+	tests := []struct {
+		name    string
+		request interface{}
+		want    []validator.FieldError
+	}{
+		{
+			name:    "register with missing fields",
+			request: &handler.RegisterRequest{Email: "not-an-email", Password: "short"},
+			want: []validator.FieldError{
+				{Field: "email", Rule: "email", Message: "must be a valid email address"},
+				{Field: "password", Rule: "min", Param: "8", Message: "must be at least 8 characters"},
+				{Field: "first_name", Rule: "required", Message: "is required"},
+				{Field: "last_name", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:    "profile limits",
+			request: &handler.UpdateProfileRequest{FirstName: "A", Handicap: &handicap, Phone: &tooLong},
+			want: []validator.FieldError{
+				{Field: "first_name", Rule: "min", Param: "2", Message: "must be at least 2 characters"},
+				{Field: "handicap", Rule: "lte", Param: "54", Message: "must be less than or equal to 54"},
+				{Field: "phone", Rule: "max", Param: "20", Message: "must be at most 20 characters"},
+			},
+		},
+		{
+			name: "ttr numeric limit",
+			request: &handler.CreateTTRRequest{
+				CourseName: "Pebble Beach",
+				TeeDate:    "2030-01-01",
+				TeeTime:    "08:00",
+				MaxPlayers: 9,
+			},
+			want: []validator.FieldError{
+				{Field: "max_players", Rule: "max", Param: "8", Message: "must be at most 8"},
+			},
+		},
+		{
+			name:    "ttr update pointer limit",
+			request: &handler.UpdateTTRRequest{MaxPlayers: &maxPlayers},
+			want: []validator.FieldError{
+				{Field: "max_players", Rule: "max", Param: "8", Message: "must be at most 8"},
+			},
+		},
+		{
+			name:    "organization member role",
+			request: &handler.UpdateOrganizationMemberRequest{Role: "owner"},
+			want: []validator.FieldError{
+				{Field: "role", Rule: "oneof", Param: "admin member", Message: "must be one of: admin, member"},
+			},
+		},
+		{
+			name:    "tournament player list",
+			request: &handler.CreateTournamentRequest{Name: "Club Cup", PlayerIDs: []string{"not-a-uuid"}},
+			want: []validator.FieldError{
+				{Field: "player_ids", Rule: "min", Param: "2", Message: "must contain at least 2 items"},
+			},
+		},
+		{
+			name: "tournament player ids",
+			request: &handler.CreateTournamentRequest{
+				Name:      "Club Cup",
+				PlayerIDs: []string{"6f1c1a52-5b0e-4a53-9d49-0d2b5b6f6a10", "not-a-uuid"},
+			},
+			want: []validator.FieldError{
+				{Field: "player_ids[1]", Rule: "uuid", Message: "must be a valid UUID"},
+			},
+		},
+		{
+			name:    "invitation ids",
+			request: &handler.CreateInvitationRequest{TTRID: "nope"},
+			want: []validator.FieldError{
+				{Field: "ttr_id", Rule: "uuid", Message: "must be a valid UUID"},
+				{Field: "invitee_user_id", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name:    "valid request",
+			request: &handler.LoginRequest{Email: "john@example.com", Password: "secret"},
+			want:    []validator.FieldError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.request)
+			if len(tt.want) == 0 {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, validator.FormatValidationErrors("en", err))
+		})
+	}
+}
+
+func TestValidator_FormatValidationErrorsLocalized(t *testing.T) {
+	err := validator.Validate(&handler.RegisterRequest{Email: "not-an-email", Password: "password123", FirstName: "Ana", LastName: "B"})
+
+	assert.Equal(t, []validator.FieldError{
+		{Field: "email", Rule: "email", Message: "debe ser un correo electrónico válido"},
+		{Field: "last_name", Rule: "min", Param: "2", Message: "debe tener al menos 2 caracteres"},
+	}, validator.FormatValidationErrors("es", err))
+}
+
+func TestValidator_EnumTags(t *testing.T) {
+	ttrStatus := func(s models.TTRStatus) *models.TTRStatus { return &s }
+	playerStatus := func(s models.TTRPlayerStatus) *models.TTRPlayerStatus { return &s }
+	ttrStatuses := []string{"OPEN", "CONFIRMED", "CANCELLED", "COMPLETED"}
+	var playerStatuses []string
+	for _, status := range models.AllPlayerStatuses() {
+		playerStatuses = append(playerStatuses, string(status))
+	}
+	playerStatusMessage := "must be one of: " + strings.Join(playerStatuses, ", ")
+	invitationResponses := []string{"YES", "NO", "MAYBE"}
+
+	tests := []struct {
+		name    string
+		request interface{}
+		want    []validator.FieldError
+	}{
+		{"ttr status valid", &handler.UpdateTTRRequest{Status: ttrStatus("CANCELLED")}, nil},
+		{"ttr status omitted", &handler.UpdateTTRRequest{}, nil},
+		{"ttr status invalid", &handler.UpdateTTRRequest{Status: ttrStatus("ARCHIVED")}, []validator.FieldError{
+			{Field: "status", Rule: "ttr_status", Allowed: ttrStatuses, Message: "must be one of: OPEN, CONFIRMED, CANCELLED, COMPLETED"},
+		}},
+		{"ttr status empty", &handler.UpdateTTRRequest{Status: ttrStatus("")}, []validator.FieldError{
+			{Field: "status", Rule: "ttr_status", Allowed: ttrStatuses, Message: "must be one of: OPEN, CONFIRMED, CANCELLED, COMPLETED"},
+		}},
+		{"player status valid", &handler.UpdatePlayerStatusRequest{Status: playerStatus("MAYBE")}, nil},
+		{"player status omitted", &handler.UpdatePlayerStatusRequest{}, nil},
+		{"player status invalid", &handler.UpdatePlayerStatusRequest{Status: playerStatus("maybe")}, []validator.FieldError{
+			{Field: "status", Rule: "player_status", Allowed: playerStatuses, Message: playerStatusMessage},
+		}},
+		{"player status empty", &handler.UpdatePlayerStatusRequest{Status: playerStatus("")}, []validator.FieldError{
+			{Field: "status", Rule: "player_status", Allowed: playerStatuses, Message: playerStatusMessage},
+		}},
+		{"invitation response valid", &handler.RespondToInvitationRequest{Status: "YES"}, nil},
+		{"invitation response invalid", &handler.RespondToInvitationRequest{Status: "PENDING"}, []validator.FieldError{
+			{Field: "status", Rule: "invitation_response", Allowed: invitationResponses, Message: "must be one of: YES, NO, MAYBE"},
+		}},
+		{"invitation response empty", &handler.RespondToInvitationRequest{Status: ""}, []validator.FieldError{
+			{Field: "status", Rule: "required", Message: "is required"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.request)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.want, validator.FormatValidationErrors("en", err))
+		})
+	}
+
+	for _, status := range models.AllPlayerStatuses() {
+		assert.NoError(t, validator.Validate(&handler.UpdatePlayerStatusRequest{Status: playerStatus(status)}), status)
+	}
+}
+
+func TestValidator_ProfileDetails(t *testing.T) {
+	str := func(s string) *string { return &s }
+	weekdays := []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+	tests := []struct {
+		name    string
+		request *handler.UpdateProfileRequest
+		want    []validator.FieldError
+	}{
+		{"bio at limit", &handler.UpdateProfileRequest{Bio: str(strings.Repeat("é", 500))}, nil},
+		{"bio too long", &handler.UpdateProfileRequest{Bio: str(strings.Repeat("a", 501))}, []validator.FieldError{
+			{Field: "bio", Rule: "max", Param: "500", Message: "must be at most 500 characters"},
+		}},
+		{"playing days valid", &handler.UpdateProfileRequest{PlayingDays: []string{"saturday", "sunday"}}, nil},
+		{"playing days cleared", &handler.UpdateProfileRequest{PlayingDays: []string{}}, nil},
+		{"playing day invalid", &handler.UpdateProfileRequest{PlayingDays: []string{"saturday", "Funday"}}, []validator.FieldError{
+			{Field: "playing_days[1]", Rule: "weekday", Allowed: weekdays, Message: "must be one of: monday, tuesday, wednesday, thursday, friday, saturday, sunday"},
+		}},
+		{"playing days repeated", &handler.UpdateProfileRequest{PlayingDays: []string{"monday", "monday"}}, []validator.FieldError{
+			{Field: "playing_days", Rule: "unique", Message: "must not contain duplicates"},
+		}},
+		{"tee time range valid", &handler.UpdateProfileRequest{PreferredTeeTimeRange: &handler.TeeTimeRange{Start: "07:00", End: "10:30"}}, nil},
+		{"tee time range invalid", &handler.UpdateProfileRequest{PreferredTeeTimeRange: &handler.TeeTimeRange{Start: "7am", End: "10:30"}}, []validator.FieldError{
+			{Field: "preferred_tee_time_range.start", Rule: "time_of_day", Message: "must be a time in HH:MM format"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(tt.request)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.want, validator.FormatValidationErrors("en", err))
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/golf_messenger/internal/config"
+	"github.com/yourusername/golf_messenger/internal/models"
+	"github.com/yourusername/golf_messenger/internal/repository"
+	"github.com/yourusername/golf_messenger/internal/repository/memory"
+	"github.com/yourusername/golf_messenger/internal/service"
+	"go.uber.org/zap"
+)
+
+// webhookReceiver records what it receives and answers the first failures
+// requests with a 500.
+type webhookReceiver struct {
+	mu         sync.Mutex
+	failures   int
+	bodies     [][]byte
+	signatures []string
+	events     []string
+	deliveries []string
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.bodies = append(rcv.bodies, body)
+	rcv.signatures = append(rcv.signatures, r.Header.Get(service.WebhookSignatureHeader))
+	rcv.events = append(rcv.events, r.Header.Get(service.WebhookEventHeader))
+	rcv.deliveries = append(rcv.deliveries, r.Header.Get(service.WebhookDeliveryHeader))
+	if len(rcv.bodies) <= rcv.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rcv *webhookReceiver) received() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return len(rcv.bodies)
+}
+
+type webhookFixture struct {
+	service *service.WebhookService
+	repo    repository.WebhookRepository
+	orgRepo repository.OrganizationRepository
+}
+
+// newWebhookFixture starts the dispatcher with millisecond backoff and stops
+// it when the test ends.
+func newWebhookFixture(t *testing.T, cfg config.WebhooksConfig) *webhookFixture {
+	store := memory.NewStore()
+	f := &webhookFixture{
+		repo:    memory.NewWebhookRepository(store),
+		orgRepo: memory.NewOrganizationRepository(store),
+	}
+	authorizer := service.NewAuthorizer(memory.NewTTRRepository(store), f.orgRepo, memory.NewInvitationRepository(store), memory.NewUserRepository(store))
+	f.service = service.NewWebhookService(f.repo, authorizer, nil, cfg, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = f.service.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return f
+}
+
+func (f *webhookFixture) deliveries(t *testing.T, webhookID uuid.UUID) []*models.WebhookDelivery {
+	deliveries, err := f.repo.FindDeliveries(context.Background(), webhookID, 100, 0)
+	require.NoError(t, err)
+	return deliveries
+}
+
+func webhookTestTTR(captainID uuid.UUID) *models.TTR {
+	return &models.TTR{
+		ID:            uuid.New(),
+		CourseName:    "Pebble Beach",
+		TeeDate:       time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC),
+		TeeTime:       time.Date(0, 1, 1, 8, 30, 0, 0, time.UTC),
+		Status:        models.TTRStatusOpen,
+		MaxPlayers:    4,
+		CaptainUserID: captainID,
+	}
+}
+
+func TestWebhookService_Delivery(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.WebhooksConfig{MaxAttempts: 5, InitialBackoff: time.Millisecond, Timeout: time.Second, FailingAfter: 3}
+
+	t.Run("retries until the endpoint accepts and signs every attempt", func(t *testing.T) {
+		receiver := &webhookReceiver{failures: 2}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		f := newWebhookFixture(t, cfg)
+		captainID := uuid.New()
+		webhook, err := f.service.CreateWebhook(ctx, captainID, nil, server.URL, []string{models.WebhookEventTTRCreated})
+		require.NoError(t, err)
+		require.NotEmpty(t, webhook.Secret)
+
+		ttr := webhookTestTTR(captainID)
+		require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRCreated, ttr))
+
+		require.Eventually(t, func() bool { return len(f.deliveries(t, webhook.ID)) == 3 }, 5*time.Second, 5*time.Millisecond)
+
+		receiver.mu.Lock()
+		for i, body := range receiver.bodies {
+			assert.Equal(t, service.WebhookSignature(webhook.Secret, body), receiver.signatures[i])
+			assert.Equal(t, models.WebhookEventTTRCreated, receiver.events[i])
+			assert.Equal(t, receiver.deliveries[0], receiver.deliveries[i], "retries keep the event ID")
+		}
+		var payload struct {
+			Event string                 `json:"event"`
+			Data  service.WebhookTTRData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(receiver.bodies[0], &payload))
+		receiver.mu.Unlock()
+		assert.Equal(t, models.WebhookEventTTRCreated, payload.Event)
+		assert.Equal(t, ttr.ID, payload.Data.ID)
+		assert.Equal(t, "2030-06-01", payload.Data.TeeDate)
+		assert.Equal(t, "08:30", payload.Data.TeeTime)
+
+		deliveries := f.deliveries(t, webhook.ID)
+		assert.Equal(t, 3, deliveries[0].Attempt)
+		assert.True(t, deliveries[0].Succeeded)
+		for _, failed := range deliveries[1:] {
+			assert.False(t, failed.Succeeded)
+			require.NotNil(t, failed.StatusCode)
+			assert.Equal(t, http.StatusInternalServerError, *failed.StatusCode)
+			assert.NotNil(t, failed.Error)
+		}
+
+		stored, err := f.repo.FindByID(ctx, webhook.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookStatusActive, stored.Status)
+		assert.Zero(t, stored.ConsecutiveFailures)
+	})
+
+	t.Run("only subscribed events are delivered", func(t *testing.T) {
+		receiver := &webhookReceiver{}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		f := newWebhookFixture(t, cfg)
+		captainID := uuid.New()
+		webhook, err := f.service.CreateWebhook(ctx, captainID, nil, server.URL, []string{models.WebhookEventTTRCancelled})
+		require.NoError(t, err)
+		otherCaptains, err := f.service.CreateWebhook(ctx, uuid.New(), nil, server.URL, []string{models.WebhookEventTTRCancelled})
+		require.NoError(t, err)
+
+		ttr := webhookTestTTR(captainID)
+		require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRUpdated, ttr))
+		require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRCancelled, ttr))
+
+		require.Eventually(t, func() bool { return len(f.deliveries(t, webhook.ID)) == 1 }, 5*time.Second, 5*time.Millisecond)
+		assert.Equal(t, models.WebhookEventTTRCancelled, f.deliveries(t, webhook.ID)[0].Event)
+		assert.Empty(t, f.deliveries(t, otherCaptains.ID))
+		assert.Equal(t, 1, receiver.received())
+	})
+
+	t.Run("repeated failures mark the webhook failing", func(t *testing.T) {
+		receiver := &webhookReceiver{failures: 100}
+		server := httptest.NewServer(receiver)
+		defer server.Close()
+
+		f := newWebhookFixture(t, config.WebhooksConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond, Timeout: time.Second, FailingAfter: 2})
+		captainID := uuid.New()
+		webhook, err := f.service.CreateWebhook(ctx, captainID, nil, server.URL, []string{models.WebhookEventTTRCreated})
+		require.NoError(t, err)
+
+		require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRCreated, webhookTestTTR(captainID)))
+		require.Eventually(t, func() bool { return len(f.deliveries(t, webhook.ID)) == 2 }, 5*time.Second, 5*time.Millisecond)
+		require.NoError(t, f.service.PublishTTR(ctx, models.WebhookEventTTRCreated, webhookTestTTR(captainID)))
+
+		require.Eventually(t, func() bool {
+			stored, err := f.repo.FindByID(ctx, webhook.ID)
+			require.NoError(t, err)
+			return stored.Status == models.WebhookStatusFailing
+		}, 5*time.Second, 5*time.Millisecond)
+		assert.Len(t, f.deliveries(t, webhook.ID), 4)
+
+		updated, err := f.service.UpdateWebhook(ctx, webhook.ID, captainID, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, models.WebhookStatusActive, updated.Status, "updating revives a failing webhook")
+		assert.Zero(t, updated.ConsecutiveFailures)
+	})
+}
+
+func TestWebhookService_Management(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture(t, config.WebhooksConfig{})
+	ownerID := uuid.New()
+
+	t.Run("rejects bad URLs and events", func(t *testing.T) {
+		for _, url := range []string{"", "ftp://example.com/hook", "https://", "not a url"} {
+			_, err := f.service.CreateWebhook(ctx, ownerID, nil, url, []string{models.WebhookEventTTRCreated})
+			assert.ErrorIs(t, err, service.ErrInvalidWebhookURL, url)
+		}
+
+		_, err := f.service.CreateWebhook(ctx, ownerID, nil, "https://example.com/hook", []string{"ttr.deleted"})
+		assert.ErrorIs(t, err, service.ErrInvalidWebhookEvent)
+		_, err = f.service.CreateWebhook(ctx, ownerID, nil, "https://example.com/hook", nil)
+		assert.ErrorIs(t, err, service.ErrInvalidWebhookEvent)
+	})
+
+	t.Run("duplicate events are kept once", func(t *testing.T) {
+		webhook, err := f.service.CreateWebhook(ctx, ownerID, nil, "https://example.com/hook", []string{models.WebhookEventTTRCreated, models.WebhookEventTTRCreated, models.WebhookEventTTRUpdated})
+		require.NoError(t, err)
+		assert.Equal(t, []string{models.WebhookEventTTRCreated, models.WebhookEventTTRUpdated}, webhook.EventList())
+	})
+
+	t.Run("other users' webhooks look missing", func(t *testing.T) {
+		webhook, err := f.service.CreateWebhook(ctx, ownerID, nil, "https://example.com/hook", []string{models.WebhookEventTTRCreated})
+		require.NoError(t, err)
+
+		_, err = f.service.GetWebhook(ctx, webhook.ID, uuid.New())
+		assert.ErrorIs(t, err, service.ErrWebhookNotFound)
+		assert.ErrorIs(t, f.service.DeleteWebhook(ctx, webhook.ID, uuid.New()), service.ErrWebhookNotFound)
+	})
+
+	t.Run("organization webhooks need an admin", func(t *testing.T) {
+		orgID := uuid.New()
+		adminID := uuid.New()
+		memberID := uuid.New()
+		require.NoError(t, f.orgRepo.Create(ctx, &models.Organization{ID: orgID, Name: "Cypress Point Club"}))
+		require.NoError(t, f.orgRepo.AddMember(ctx, &models.OrganizationMember{OrganizationID: orgID, UserID: adminID, Role: models.OrganizationRoleAdmin}))
+		require.NoError(t, f.orgRepo.AddMember(ctx, &models.OrganizationMember{OrganizationID: orgID, UserID: memberID, Role: models.OrganizationRoleMember}))
+
+		_, err := f.service.CreateWebhook(ctx, memberID, &orgID, "https://example.com/hook", []string{models.WebhookEventTTRCreated})
+		assert.ErrorIs(t, err, service.ErrNotAdminWebhooks)
+		_, err = f.service.CreateWebhook(ctx, uuid.New(), &orgID, "https://example.com/hook", []string{models.WebhookEventTTRCreated})
+		assert.ErrorIs(t, err, service.ErrOrganizationNotFound)
+
+		webhook, err := f.service.CreateWebhook(ctx, adminID, &orgID, "https://example.com/hook", []string{models.WebhookEventTTRCreated})
+		require.NoError(t, err)
+		webhooks, err := f.service.ListWebhooks(ctx, adminID, &orgID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		assert.Equal(t, webhook.ID, webhooks[0].ID)
+
+		own, err := f.service.ListWebhooks(ctx, adminID, nil)
+		require.NoError(t, err)
+		assert.Empty(t, own)
+	})
+}